@@ -3,6 +3,8 @@ package main
 import (
 	"io"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 func cp(src, dst string) error {
@@ -32,7 +34,24 @@ func cp(src, dst string) error {
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "prewarm":
+			// Run in each pipeline image by pachd's image prewarmer, which
+			// only needs the image to be pulled, so exit immediately
+			return
+		case "pause":
+			// Keep the image prewarmer's pods running until they're deleted
+			sig := make(chan os.Signal, 1)
+			signal.Notify(sig, syscall.SIGTERM, syscall.SIGINT)
+			<-sig
+			return
+		}
+	}
 	if err := cp("/app/worker", "/pach-bin/worker"); err != nil {
 		panic(err)
 	}
+	if err := cp("/app/init", "/pach-bin/init"); err != nil {
+		panic(err)
+	}
 }
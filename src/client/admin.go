@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"io"
 
 	"github.com/gogo/protobuf/types"
@@ -166,3 +167,163 @@ func (c APIClient) RestoreURL(url string) (retErr error) {
 	}()
 	return grpcutil.ScrubGRPC(restoreClient.Send(&admin.RestoreRequest{URL: url}))
 }
+
+// ExtractWithProgress extracts the cluster state selected by 'request',
+// calling opF with each op (unless request.URL is set, in which case the ops
+// are written there instead and opF may be nil), and progressF with the extract's progress every
+// few seconds and when it's done.
+func (c APIClient) ExtractWithProgress(request *admin.ExtractRequest, opF func(*admin.Op) error, progressF func(*admin.Progress) error) error {
+	extractClient, err := c.AdminAPIClient.ExtractWithProgress(c.Ctx(), request)
+	if err != nil {
+		return grpcutil.ScrubGRPC(err)
+	}
+	for {
+		resp, err := extractClient.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return grpcutil.ScrubGRPC(err)
+		}
+		if resp.Op != nil {
+			if err := opF(resp.Op); err != nil {
+				return err
+			}
+		}
+		if resp.Progress != nil {
+			if err := progressF(resp.Progress); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// RestoreWithProgress restores cluster state from the marshalled ops in 'r'
+// (such as those written by ExtractWriter), or from request.URL if 'r' is nil,
+// and calls progressF with the restore's progress every few seconds and when
+// it's done. 'request' is sent as the restore's first request, so it can also
+// set its checkpoint, whether to resume from it, and its size.
+func (c APIClient) RestoreWithProgress(request *admin.RestoreRequest, r io.Reader, progressF func(*admin.Progress) error) (retErr error) {
+	ctx, cancel := context.WithCancel(c.Ctx())
+	defer cancel()
+	restoreClient, err := c.AdminAPIClient.RestoreWithProgress(ctx)
+	if err != nil {
+		return grpcutil.ScrubGRPC(err)
+	}
+	// Receive progress while the ops are sent. The restore's result is the
+	// error that ends the stream of progress.
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			p, err := restoreClient.Recv()
+			if errors.Is(err, io.EOF) {
+				recvErr <- nil
+				return
+			}
+			if err != nil {
+				recvErr <- grpcutil.ScrubGRPC(err)
+				return
+			}
+			if err := progressF(p); err != nil {
+				cancel()
+				recvErr <- err
+				return
+			}
+		}
+	}()
+	if err := sendRestoreRequests(restoreClient, request, r); err != nil {
+		// io.EOF means that the server ended the restore, and its error is
+		// received by the goroutine
+		if !errors.Is(err, io.EOF) {
+			cancel()
+			<-recvErr
+			return err
+		}
+	}
+	return <-recvErr
+}
+
+func sendRestoreRequests(restoreClient admin.API_RestoreWithProgressClient, request *admin.RestoreRequest, r io.Reader) error {
+	if r == nil {
+		if err := restoreClient.Send(request); err != nil {
+			return err
+		}
+	} else {
+		reader := pbutil.NewReader(r)
+		for {
+			op := &admin.Op{}
+			if err := reader.Read(op); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return err
+			}
+			request.Op = op
+			if err := restoreClient.Send(request); err != nil {
+				return err
+			}
+			request = &admin.RestoreRequest{}
+		}
+	}
+	return restoreClient.CloseSend()
+}
+
+// SetFaults replaces the faults that are injected into the cluster, which
+// must have been started with FAULT_INJECTION=true. Only cluster admins may
+// set faults.
+func (c APIClient) SetFaults(faults *admin.Faults) error {
+	_, err := c.AdminAPIClient.SetFaults(c.Ctx(), faults)
+	return grpcutil.ScrubGRPC(err)
+}
+
+// GetFaults returns the faults that are injected into the cluster.
+func (c APIClient) GetFaults() (*admin.Faults, error) {
+	faults, err := c.AdminAPIClient.GetFaults(c.Ctx(), &types.Empty{})
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return faults, nil
+}
+
+// InspectRateLimits returns the rate limits that pachd enforces and the
+// current usage of them, by 'principal' if it's set or else by every principal
+// that has made calls recently. Only cluster admins may inspect rate limits.
+func (c APIClient) InspectRateLimits(principal string) (*admin.InspectRateLimitsResponse, error) {
+	resp, err := c.AdminAPIClient.InspectRateLimits(c.Ctx(), &admin.InspectRateLimitsRequest{Principal: principal})
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return resp, nil
+}
+
+// Snapshot writes a snapshot of the cluster's metadata, and a manifest of the
+// objects that it refers to, to object storage under 'url'. Only cluster
+// admins may take snapshots.
+func (c APIClient) Snapshot(url string) (*admin.SnapshotInfo, error) {
+	info, err := c.AdminAPIClient.Snapshot(c.Ctx(), &admin.SnapshotRequest{URL: url})
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return info, nil
+}
+
+// InspectSnapshot returns info about the snapshot under 'url'. If 'verify' is
+// true, it also checks that the snapshot is intact and that the cluster's
+// object storage has everything that it refers to.
+func (c APIClient) InspectSnapshot(url string, verify bool) (*admin.InspectSnapshotResponse, error) {
+	resp, err := c.AdminAPIClient.InspectSnapshot(c.Ctx(), &admin.InspectSnapshotRequest{URL: url, Verify: verify})
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return resp, nil
+}
+
+// RestoreSnapshot verifies the snapshot under 'url' and restores it into the
+// cluster, which must not have any repos or pipelines.
+func (c APIClient) RestoreSnapshot(url string) (*admin.SnapshotInfo, error) {
+	info, err := c.AdminAPIClient.RestoreSnapshot(c.Ctx(), &admin.RestoreSnapshotRequest{URL: url})
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return info, nil
+}
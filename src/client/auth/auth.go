@@ -14,6 +14,11 @@ const (
 	// authenticated context
 	ContextTokenKey = "authn-token"
 
+	// ContextImpersonateKey is the key of the subject that a cluster admin is
+	// impersonating in the metadata of a request. The request is authorized as
+	// though it had been made by that subject, and the impersonation is logged.
+	ContextImpersonateKey = "authn-impersonate"
+
 	// The following constants are Subject prefixes. These are prepended to
 	// Subjects in the 'tokens' collection, and Principals in 'admins' and on ACLs
 	// to indicate what type of Subject or Principal they are (every Pachyderm
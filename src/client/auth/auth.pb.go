@@ -1437,11 +1437,24 @@ type TokenInfo struct {
 	// Subject (i.e. Pachyderm account) that a given token authorizes. Prefixed
 	// with "github:" or "robot:" to distinguish the two classes of
 	// Subject in Pachyderm
-	Subject              string                `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
-	Source               TokenInfo_TokenSource `protobuf:"varint,2,opt,name=source,proto3,enum=auth.TokenInfo_TokenSource" json:"source,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+	Subject string                `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Source  TokenInfo_TokenSource `protobuf:"varint,2,opt,name=source,proto3,enum=auth.TokenInfo_TokenSource" json:"source,omitempty"`
+	// impersonator is the cluster admin who made the request on behalf of
+	// 'subject' (see ContextImpersonateKey), if any. It's only set on the
+	// TokenInfo of an impersonated request, and is never stored.
+	Impersonator string `protobuf:"bytes,3,opt,name=impersonator,proto3" json:"impersonator,omitempty"`
+	// read_only_repos, if set, restricts the token: it only grants READER
+	// access, only to these repos, and none of its subject's cluster roles
+	// (see GetAuthTokenRequest.read_only_repos).
+	ReadOnlyRepos []string `protobuf:"bytes,4,rep,name=read_only_repos,json=readOnlyRepos,proto3" json:"read_only_repos,omitempty"`
+	// tenant, if set, confines the token to one tenant: it can only access the
+	// repos and pipelines owned by the tenant (whose names start with
+	// "<tenant>-"), and has none of its subject's cluster roles (see
+	// GetAuthTokenRequest.tenant).
+	Tenant               string   `protobuf:"bytes,5,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *TokenInfo) Reset()         { *m = TokenInfo{} }
@@ -1491,6 +1504,27 @@ func (m *TokenInfo) GetSource() TokenInfo_TokenSource {
 	return TokenInfo_INVALID
 }
 
+func (m *TokenInfo) GetImpersonator() string {
+	if m != nil {
+		return m.Impersonator
+	}
+	return ""
+}
+
+func (m *TokenInfo) GetReadOnlyRepos() []string {
+	if m != nil {
+		return m.ReadOnlyRepos
+	}
+	return nil
+}
+
+func (m *TokenInfo) GetTenant() string {
+	if m != nil {
+		return m.Tenant
+	}
+	return ""
+}
+
 type AuthenticateRequest struct {
 	// This is the token returned by GitHub and used to authenticate the caller.
 	// When Pachyderm is deployed locally, setting this value to a given string
@@ -1624,6 +1658,9 @@ func (m *AuthenticateResponse) GetPachToken() string {
 }
 
 type WhoAmIRequest struct {
+	// include_scopes, if set, causes the response to include the caller's scope
+	// on every repo where it has one
+	IncludeScopes        bool     `protobuf:"varint,1,opt,name=include_scopes,json=includeScopes,proto3" json:"include_scopes,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1662,21 +1699,100 @@ func (m *WhoAmIRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_WhoAmIRequest proto.InternalMessageInfo
 
+func (m *WhoAmIRequest) GetIncludeScopes() bool {
+	if m != nil {
+		return m.IncludeScopes
+	}
+	return false
+}
+
+// RepoScope is the scope that a subject has on a repo
+type RepoScope struct {
+	Repo                 string   `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
+	Scope                Scope    `protobuf:"varint,2,opt,name=scope,proto3,enum=auth.Scope" json:"scope,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RepoScope) Reset()         { *m = RepoScope{} }
+func (m *RepoScope) String() string { return proto.CompactTextString(m) }
+func (*RepoScope) ProtoMessage()    {}
+func (*RepoScope) Descriptor() ([]byte, []int) {
+	return fileDescriptor_15ace9a5d0179ff3, []int{24}
+}
+func (m *RepoScope) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RepoScope) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RepoScope.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RepoScope) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RepoScope.Merge(m, src)
+}
+func (m *RepoScope) XXX_Size() int {
+	return m.Size()
+}
+func (m *RepoScope) XXX_DiscardUnknown() {
+	xxx_messageInfo_RepoScope.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RepoScope proto.InternalMessageInfo
+
+func (m *RepoScope) GetRepo() string {
+	if m != nil {
+		return m.Repo
+	}
+	return ""
+}
+
+func (m *RepoScope) GetScope() Scope {
+	if m != nil {
+		return m.Scope
+	}
+	return Scope_NONE
+}
+
 type WhoAmIResponse struct {
-	Username             string        `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
-	IsAdmin              bool          `protobuf:"varint,2,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
-	TTL                  int64         `protobuf:"varint,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
-	ClusterRoles         *ClusterRoles `protobuf:"bytes,4,opt,name=cluster_roles,json=clusterRoles,proto3" json:"cluster_roles,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+	Username     string        `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	IsAdmin      bool          `protobuf:"varint,2,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
+	TTL          int64         `protobuf:"varint,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	ClusterRoles *ClusterRoles `protobuf:"bytes,4,opt,name=cluster_roles,json=clusterRoles,proto3" json:"cluster_roles,omitempty"`
+	// impersonator is the cluster admin who is impersonating 'username', if
+	// the request was impersonated
+	Impersonator string `protobuf:"bytes,5,opt,name=impersonator,proto3" json:"impersonator,omitempty"`
+	// scopes are the caller's scopes on repos (directly, via its groups, or via
+	// allClusterUsers), if include_scopes was set. Repos where the caller has no
+	// scope are omitted. Cluster admins can additionally access every repo,
+	// regardless of these scopes.
+	Scopes []*RepoScope `protobuf:"bytes,6,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// read_only_repos is set if the caller's token is restricted to reading
+	// these repos (see TokenInfo.read_only_repos). is_admin and cluster_roles
+	// are never set for restricted tokens.
+	ReadOnlyRepos []string `protobuf:"bytes,7,rep,name=read_only_repos,json=readOnlyRepos,proto3" json:"read_only_repos,omitempty"`
+	// tenant is set if the caller's token is confined to a tenant (see
+	// TokenInfo.tenant). is_admin and cluster_roles are never set for tenant
+	// tokens.
+	Tenant               string   `protobuf:"bytes,8,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *WhoAmIResponse) Reset()         { *m = WhoAmIResponse{} }
 func (m *WhoAmIResponse) String() string { return proto.CompactTextString(m) }
 func (*WhoAmIResponse) ProtoMessage()    {}
 func (*WhoAmIResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{24}
+	return fileDescriptor_15ace9a5d0179ff3, []int{25}
 }
 func (m *WhoAmIResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1733,6 +1849,34 @@ func (m *WhoAmIResponse) GetClusterRoles() *ClusterRoles {
 	return nil
 }
 
+func (m *WhoAmIResponse) GetImpersonator() string {
+	if m != nil {
+		return m.Impersonator
+	}
+	return ""
+}
+
+func (m *WhoAmIResponse) GetScopes() []*RepoScope {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *WhoAmIResponse) GetReadOnlyRepos() []string {
+	if m != nil {
+		return m.ReadOnlyRepos
+	}
+	return nil
+}
+
+func (m *WhoAmIResponse) GetTenant() string {
+	if m != nil {
+		return m.Tenant
+	}
+	return ""
+}
+
 type ACL struct {
 	// principal -> scope. All principals are the default principal of a Pachyderm
 	// subject (i.e. all keys in this map are strings prefixed with either
@@ -1748,7 +1892,7 @@ func (m *ACL) Reset()         { *m = ACL{} }
 func (m *ACL) String() string { return proto.CompactTextString(m) }
 func (*ACL) ProtoMessage()    {}
 func (*ACL) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{25}
+	return fileDescriptor_15ace9a5d0179ff3, []int{26}
 }
 func (m *ACL) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1795,7 +1939,7 @@ func (m *Users) Reset()         { *m = Users{} }
 func (m *Users) String() string { return proto.CompactTextString(m) }
 func (*Users) ProtoMessage()    {}
 func (*Users) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{26}
+	return fileDescriptor_15ace9a5d0179ff3, []int{27}
 }
 func (m *Users) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1842,7 +1986,7 @@ func (m *Groups) Reset()         { *m = Groups{} }
 func (m *Groups) String() string { return proto.CompactTextString(m) }
 func (*Groups) ProtoMessage()    {}
 func (*Groups) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{27}
+	return fileDescriptor_15ace9a5d0179ff3, []int{28}
 }
 func (m *Groups) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1892,7 +2036,7 @@ func (m *AuthorizeRequest) Reset()         { *m = AuthorizeRequest{} }
 func (m *AuthorizeRequest) String() string { return proto.CompactTextString(m) }
 func (*AuthorizeRequest) ProtoMessage()    {}
 func (*AuthorizeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{28}
+	return fileDescriptor_15ace9a5d0179ff3, []int{29}
 }
 func (m *AuthorizeRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1949,7 +2093,7 @@ func (m *AuthorizeResponse) Reset()         { *m = AuthorizeResponse{} }
 func (m *AuthorizeResponse) String() string { return proto.CompactTextString(m) }
 func (*AuthorizeResponse) ProtoMessage()    {}
 func (*AuthorizeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{29}
+	return fileDescriptor_15ace9a5d0179ff3, []int{30}
 }
 func (m *AuthorizeResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2004,7 +2148,7 @@ func (m *GetScopeRequest) Reset()         { *m = GetScopeRequest{} }
 func (m *GetScopeRequest) String() string { return proto.CompactTextString(m) }
 func (*GetScopeRequest) ProtoMessage()    {}
 func (*GetScopeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{30}
+	return fileDescriptor_15ace9a5d0179ff3, []int{31}
 }
 func (m *GetScopeRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2061,7 +2205,7 @@ func (m *GetScopeResponse) Reset()         { *m = GetScopeResponse{} }
 func (m *GetScopeResponse) String() string { return proto.CompactTextString(m) }
 func (*GetScopeResponse) ProtoMessage()    {}
 func (*GetScopeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{31}
+	return fileDescriptor_15ace9a5d0179ff3, []int{32}
 }
 func (m *GetScopeResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2119,7 +2263,7 @@ func (m *SetScopeRequest) Reset()         { *m = SetScopeRequest{} }
 func (m *SetScopeRequest) String() string { return proto.CompactTextString(m) }
 func (*SetScopeRequest) ProtoMessage()    {}
 func (*SetScopeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{32}
+	return fileDescriptor_15ace9a5d0179ff3, []int{33}
 }
 func (m *SetScopeRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2179,7 +2323,7 @@ func (m *SetScopeResponse) Reset()         { *m = SetScopeResponse{} }
 func (m *SetScopeResponse) String() string { return proto.CompactTextString(m) }
 func (*SetScopeResponse) ProtoMessage()    {}
 func (*SetScopeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{33}
+	return fileDescriptor_15ace9a5d0179ff3, []int{34}
 }
 func (m *SetScopeResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2219,7 +2363,7 @@ func (m *GetACLRequest) Reset()         { *m = GetACLRequest{} }
 func (m *GetACLRequest) String() string { return proto.CompactTextString(m) }
 func (*GetACLRequest) ProtoMessage()    {}
 func (*GetACLRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{34}
+	return fileDescriptor_15ace9a5d0179ff3, []int{35}
 }
 func (m *GetACLRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2272,7 +2416,7 @@ func (m *ACLEntry) Reset()         { *m = ACLEntry{} }
 func (m *ACLEntry) String() string { return proto.CompactTextString(m) }
 func (*ACLEntry) ProtoMessage()    {}
 func (*ACLEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{35}
+	return fileDescriptor_15ace9a5d0179ff3, []int{36}
 }
 func (m *ACLEntry) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2339,7 +2483,7 @@ func (m *GetACLResponse) Reset()         { *m = GetACLResponse{} }
 func (m *GetACLResponse) String() string { return proto.CompactTextString(m) }
 func (*GetACLResponse) ProtoMessage()    {}
 func (*GetACLResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{36}
+	return fileDescriptor_15ace9a5d0179ff3, []int{37}
 }
 func (m *GetACLResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2394,7 +2538,7 @@ func (m *SetACLRequest) Reset()         { *m = SetACLRequest{} }
 func (m *SetACLRequest) String() string { return proto.CompactTextString(m) }
 func (*SetACLRequest) ProtoMessage()    {}
 func (*SetACLRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{37}
+	return fileDescriptor_15ace9a5d0179ff3, []int{38}
 }
 func (m *SetACLRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2447,7 +2591,7 @@ func (m *SetACLResponse) Reset()         { *m = SetACLResponse{} }
 func (m *SetACLResponse) String() string { return proto.CompactTextString(m) }
 func (*SetACLResponse) ProtoMessage()    {}
 func (*SetACLResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{38}
+	return fileDescriptor_15ace9a5d0179ff3, []int{39}
 }
 func (m *SetACLResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2515,7 +2659,7 @@ func (m *SessionInfo) Reset()         { *m = SessionInfo{} }
 func (m *SessionInfo) String() string { return proto.CompactTextString(m) }
 func (*SessionInfo) ProtoMessage()    {}
 func (*SessionInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{39}
+	return fileDescriptor_15ace9a5d0179ff3, []int{40}
 }
 func (m *SessionInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2575,7 +2719,7 @@ func (m *GetOIDCLoginRequest) Reset()         { *m = GetOIDCLoginRequest{} }
 func (m *GetOIDCLoginRequest) String() string { return proto.CompactTextString(m) }
 func (*GetOIDCLoginRequest) ProtoMessage()    {}
 func (*GetOIDCLoginRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{40}
+	return fileDescriptor_15ace9a5d0179ff3, []int{41}
 }
 func (m *GetOIDCLoginRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2617,7 +2761,7 @@ func (m *GetOIDCLoginResponse) Reset()         { *m = GetOIDCLoginResponse{} }
 func (m *GetOIDCLoginResponse) String() string { return proto.CompactTextString(m) }
 func (*GetOIDCLoginResponse) ProtoMessage()    {}
 func (*GetOIDCLoginResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{41}
+	return fileDescriptor_15ace9a5d0179ff3, []int{42}
 }
 func (m *GetOIDCLoginResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2666,7 +2810,16 @@ type GetAuthTokenRequest struct {
 	Subject string `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
 	// ttl indicates the requested (approximate) remaining lifetime of this token,
 	// in seconds
-	TTL                  int64    `protobuf:"varint,2,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	TTL int64 `protobuf:"varint,2,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// read_only_repos, if set, restricts the returned token to reading these
+	// repos, e.g. so that it can be handed to a notebook to mount them. A
+	// restricted caller can only get tokens restricted to a subset of its own
+	// repos.
+	ReadOnlyRepos []string `protobuf:"bytes,3,rep,name=read_only_repos,json=readOnlyRepos,proto3" json:"read_only_repos,omitempty"`
+	// tenant, if set, confines the returned token to this tenant's repos and
+	// pipelines. Only cluster admins can set it; tokens obtained by a tenant
+	// token are always confined to the same tenant.
+	Tenant               string   `protobuf:"bytes,4,opt,name=tenant,proto3" json:"tenant,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -2676,7 +2829,7 @@ func (m *GetAuthTokenRequest) Reset()         { *m = GetAuthTokenRequest{} }
 func (m *GetAuthTokenRequest) String() string { return proto.CompactTextString(m) }
 func (*GetAuthTokenRequest) ProtoMessage()    {}
 func (*GetAuthTokenRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{42}
+	return fileDescriptor_15ace9a5d0179ff3, []int{43}
 }
 func (m *GetAuthTokenRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2719,6 +2872,20 @@ func (m *GetAuthTokenRequest) GetTTL() int64 {
 	return 0
 }
 
+func (m *GetAuthTokenRequest) GetReadOnlyRepos() []string {
+	if m != nil {
+		return m.ReadOnlyRepos
+	}
+	return nil
+}
+
+func (m *GetAuthTokenRequest) GetTenant() string {
+	if m != nil {
+		return m.Tenant
+	}
+	return ""
+}
+
 type GetAuthTokenResponse struct {
 	// A canonicalized version of the subject in the request
 	Subject string `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
@@ -2733,7 +2900,7 @@ func (m *GetAuthTokenResponse) Reset()         { *m = GetAuthTokenResponse{} }
 func (m *GetAuthTokenResponse) String() string { return proto.CompactTextString(m) }
 func (*GetAuthTokenResponse) ProtoMessage()    {}
 func (*GetAuthTokenResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{43}
+	return fileDescriptor_15ace9a5d0179ff3, []int{44}
 }
 func (m *GetAuthTokenResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2790,7 +2957,7 @@ func (m *ExtendAuthTokenRequest) Reset()         { *m = ExtendAuthTokenRequest{}
 func (m *ExtendAuthTokenRequest) String() string { return proto.CompactTextString(m) }
 func (*ExtendAuthTokenRequest) ProtoMessage()    {}
 func (*ExtendAuthTokenRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{44}
+	return fileDescriptor_15ace9a5d0179ff3, []int{45}
 }
 func (m *ExtendAuthTokenRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2843,7 +3010,7 @@ func (m *ExtendAuthTokenResponse) Reset()         { *m = ExtendAuthTokenResponse
 func (m *ExtendAuthTokenResponse) String() string { return proto.CompactTextString(m) }
 func (*ExtendAuthTokenResponse) ProtoMessage()    {}
 func (*ExtendAuthTokenResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{45}
+	return fileDescriptor_15ace9a5d0179ff3, []int{46}
 }
 func (m *ExtendAuthTokenResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2883,7 +3050,7 @@ func (m *RevokeAuthTokenRequest) Reset()         { *m = RevokeAuthTokenRequest{}
 func (m *RevokeAuthTokenRequest) String() string { return proto.CompactTextString(m) }
 func (*RevokeAuthTokenRequest) ProtoMessage()    {}
 func (*RevokeAuthTokenRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{46}
+	return fileDescriptor_15ace9a5d0179ff3, []int{47}
 }
 func (m *RevokeAuthTokenRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2929,7 +3096,7 @@ func (m *RevokeAuthTokenResponse) Reset()         { *m = RevokeAuthTokenResponse
 func (m *RevokeAuthTokenResponse) String() string { return proto.CompactTextString(m) }
 func (*RevokeAuthTokenResponse) ProtoMessage()    {}
 func (*RevokeAuthTokenResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{47}
+	return fileDescriptor_15ace9a5d0179ff3, []int{48}
 }
 func (m *RevokeAuthTokenResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2970,7 +3137,7 @@ func (m *SetGroupsForUserRequest) Reset()         { *m = SetGroupsForUserRequest
 func (m *SetGroupsForUserRequest) String() string { return proto.CompactTextString(m) }
 func (*SetGroupsForUserRequest) ProtoMessage()    {}
 func (*SetGroupsForUserRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{48}
+	return fileDescriptor_15ace9a5d0179ff3, []int{49}
 }
 func (m *SetGroupsForUserRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3023,7 +3190,7 @@ func (m *SetGroupsForUserResponse) Reset()         { *m = SetGroupsForUserRespon
 func (m *SetGroupsForUserResponse) String() string { return proto.CompactTextString(m) }
 func (*SetGroupsForUserResponse) ProtoMessage()    {}
 func (*SetGroupsForUserResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{49}
+	return fileDescriptor_15ace9a5d0179ff3, []int{50}
 }
 func (m *SetGroupsForUserResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3065,7 +3232,7 @@ func (m *ModifyMembersRequest) Reset()         { *m = ModifyMembersRequest{} }
 func (m *ModifyMembersRequest) String() string { return proto.CompactTextString(m) }
 func (*ModifyMembersRequest) ProtoMessage()    {}
 func (*ModifyMembersRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{50}
+	return fileDescriptor_15ace9a5d0179ff3, []int{51}
 }
 func (m *ModifyMembersRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3125,7 +3292,7 @@ func (m *ModifyMembersResponse) Reset()         { *m = ModifyMembersResponse{} }
 func (m *ModifyMembersResponse) String() string { return proto.CompactTextString(m) }
 func (*ModifyMembersResponse) ProtoMessage()    {}
 func (*ModifyMembersResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{51}
+	return fileDescriptor_15ace9a5d0179ff3, []int{52}
 }
 func (m *ModifyMembersResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3165,7 +3332,7 @@ func (m *GetGroupsRequest) Reset()         { *m = GetGroupsRequest{} }
 func (m *GetGroupsRequest) String() string { return proto.CompactTextString(m) }
 func (*GetGroupsRequest) ProtoMessage()    {}
 func (*GetGroupsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{52}
+	return fileDescriptor_15ace9a5d0179ff3, []int{53}
 }
 func (m *GetGroupsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3212,7 +3379,7 @@ func (m *GetGroupsResponse) Reset()         { *m = GetGroupsResponse{} }
 func (m *GetGroupsResponse) String() string { return proto.CompactTextString(m) }
 func (*GetGroupsResponse) ProtoMessage()    {}
 func (*GetGroupsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{53}
+	return fileDescriptor_15ace9a5d0179ff3, []int{54}
 }
 func (m *GetGroupsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3259,7 +3426,7 @@ func (m *GetUsersRequest) Reset()         { *m = GetUsersRequest{} }
 func (m *GetUsersRequest) String() string { return proto.CompactTextString(m) }
 func (*GetUsersRequest) ProtoMessage()    {}
 func (*GetUsersRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{54}
+	return fileDescriptor_15ace9a5d0179ff3, []int{55}
 }
 func (m *GetUsersRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3306,7 +3473,7 @@ func (m *GetUsersResponse) Reset()         { *m = GetUsersResponse{} }
 func (m *GetUsersResponse) String() string { return proto.CompactTextString(m) }
 func (*GetUsersResponse) ProtoMessage()    {}
 func (*GetUsersResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{55}
+	return fileDescriptor_15ace9a5d0179ff3, []int{56}
 }
 func (m *GetUsersResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3365,7 +3532,7 @@ func (m *GetOneTimePasswordRequest) Reset()         { *m = GetOneTimePasswordReq
 func (m *GetOneTimePasswordRequest) String() string { return proto.CompactTextString(m) }
 func (*GetOneTimePasswordRequest) ProtoMessage()    {}
 func (*GetOneTimePasswordRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{56}
+	return fileDescriptor_15ace9a5d0179ff3, []int{57}
 }
 func (m *GetOneTimePasswordRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3424,7 +3591,7 @@ func (m *GetOneTimePasswordResponse) Reset()         { *m = GetOneTimePasswordRe
 func (m *GetOneTimePasswordResponse) String() string { return proto.CompactTextString(m) }
 func (*GetOneTimePasswordResponse) ProtoMessage()    {}
 func (*GetOneTimePasswordResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_15ace9a5d0179ff3, []int{57}
+	return fileDescriptor_15ace9a5d0179ff3, []int{58}
 }
 func (m *GetOneTimePasswordResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3500,6 +3667,7 @@ func init() {
 	proto.RegisterType((*AuthenticateRequest)(nil), "auth.AuthenticateRequest")
 	proto.RegisterType((*AuthenticateResponse)(nil), "auth.AuthenticateResponse")
 	proto.RegisterType((*WhoAmIRequest)(nil), "auth.WhoAmIRequest")
+	proto.RegisterType((*RepoScope)(nil), "auth.RepoScope")
 	proto.RegisterType((*WhoAmIResponse)(nil), "auth.WhoAmIResponse")
 	proto.RegisterType((*ACL)(nil), "auth.ACL")
 	proto.RegisterMapType((map[string]Scope)(nil), "auth.ACL.EntriesEntry")
@@ -3542,161 +3710,168 @@ func init() {
 func init() { proto.RegisterFile("client/auth/auth.proto", fileDescriptor_15ace9a5d0179ff3) }
 
 var fileDescriptor_15ace9a5d0179ff3 = []byte{
-	// 2453 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x59, 0x5f, 0x73, 0xdb, 0x58,
-	0x15, 0x8f, 0xed, 0xd8, 0xb1, 0x8f, 0xff, 0xc4, 0xb9, 0x49, 0x1d, 0x47, 0xbb, 0x8d, 0x83, 0xc2,
-	0x6e, 0xd3, 0x2e, 0xe3, 0x94, 0x94, 0xd2, 0x65, 0xbb, 0x03, 0xe3, 0x24, 0x6e, 0xd6, 0x4b, 0xfe,
-	0x21, 0x39, 0xed, 0xc2, 0x8b, 0x50, 0xa4, 0x5b, 0x47, 0xd4, 0x96, 0x8c, 0x24, 0x87, 0x96, 0x17,
-	0x78, 0xe2, 0x81, 0x2f, 0xc0, 0x03, 0x33, 0x7c, 0x13, 0x98, 0xe1, 0x8d, 0x47, 0xf8, 0x02, 0x19,
-	0xc6, 0x0c, 0xdf, 0x83, 0xb9, 0xff, 0xe4, 0x2b, 0x5b, 0x4e, 0xd3, 0xe5, 0xc5, 0xd1, 0x3d, 0xff,
-	0xee, 0xd1, 0xb9, 0xe7, 0x9e, 0xf3, 0x3b, 0x0a, 0xd4, 0xac, 0xbe, 0x83, 0xdd, 0x70, 0xd7, 0x1c,
-	0x85, 0x57, 0xf4, 0xa7, 0x39, 0xf4, 0xbd, 0xd0, 0x43, 0x8b, 0xe4, 0x59, 0x59, 0xeb, 0x79, 0x3d,
-	0x8f, 0x12, 0x76, 0xc9, 0x13, 0xe3, 0x29, 0x8d, 0x9e, 0xe7, 0xf5, 0xfa, 0x78, 0x97, 0xae, 0x2e,
-	0x47, 0xaf, 0x77, 0x43, 0x67, 0x80, 0x83, 0xd0, 0x1c, 0x0c, 0x99, 0x80, 0x6a, 0xc0, 0x72, 0xcb,
-	0x0a, 0x9d, 0x6b, 0x33, 0xc4, 0x1a, 0xfe, 0xf5, 0x08, 0x07, 0x21, 0xaa, 0xc3, 0x52, 0x30, 0xba,
-	0xfc, 0x15, 0xb6, 0xc2, 0x7a, 0x7a, 0x2b, 0xb5, 0x53, 0xd0, 0xc4, 0x12, 0xed, 0x41, 0xa9, 0xe7,
-	0x84, 0x57, 0xa3, 0x4b, 0x23, 0xf4, 0xde, 0x60, 0xb7, 0x9e, 0x22, 0xec, 0xfd, 0xe5, 0xf1, 0x4d,
-	0xa3, 0x78, 0xe4, 0x84, 0x5f, 0x8d, 0x2e, 0xbb, 0x84, 0xac, 0x15, 0x99, 0x10, 0x5d, 0xa8, 0xdf,
-	0x87, 0xea, 0x64, 0x83, 0x60, 0xe8, 0xb9, 0x01, 0x46, 0xf7, 0x01, 0x86, 0xa6, 0x75, 0x25, 0x5b,
-	0xd1, 0x0a, 0x84, 0xc2, 0x54, 0x56, 0x61, 0xe5, 0x10, 0x9b, 0x71, 0xaf, 0xd4, 0x35, 0x40, 0x32,
-	0x91, 0x59, 0x52, 0xff, 0x9a, 0x05, 0xe8, 0x1c, 0x9e, 0xfb, 0xde, 0xb5, 0x63, 0x63, 0x1f, 0x21,
-	0x58, 0x74, 0xcd, 0x01, 0xe6, 0x26, 0xe9, 0x33, 0xda, 0x82, 0xa2, 0x8d, 0x03, 0xcb, 0x77, 0x86,
-	0xa1, 0xe3, 0xb9, 0xfc, 0x95, 0x64, 0x12, 0xfa, 0x02, 0x16, 0x03, 0x73, 0xd0, 0xaf, 0x67, 0xb6,
-	0x52, 0x3b, 0xc5, 0xbd, 0x8f, 0x9b, 0x34, 0xb6, 0x13, 0xab, 0x4d, 0xbd, 0x75, 0x72, 0x7c, 0x46,
-	0x45, 0x83, 0xfd, 0xfc, 0xf8, 0xa6, 0xb1, 0x48, 0x08, 0x1a, 0xd5, 0x21, 0xba, 0x9e, 0x63, 0x5b,
-	0xf5, 0xec, 0x1c, 0xdd, 0xb3, 0xce, 0xe1, 0x41, 0x4c, 0x97, 0x10, 0x34, 0xaa, 0x83, 0xf6, 0x21,
-	0xc7, 0x22, 0x55, 0x5f, 0xa4, 0xda, 0x9b, 0x33, 0xda, 0x2c, 0xaa, 0x42, 0x1f, 0xc6, 0x37, 0x8d,
-	0x1c, 0x23, 0x69, 0x5c, 0x53, 0xf9, 0x4b, 0x0a, 0x8a, 0x92, 0x7f, 0xe4, 0x88, 0x06, 0x38, 0x34,
-	0x6d, 0x33, 0x34, 0x8d, 0x91, 0xdf, 0x97, 0x8f, 0xe8, 0x84, 0xd3, 0x2f, 0xb4, 0x63, 0xad, 0x28,
-	0x84, 0x2e, 0xfc, 0x7e, 0x4c, 0xe7, 0xed, 0xa0, 0x4f, 0x43, 0x54, 0x8a, 0xeb, 0x7c, 0x73, 0x22,
-	0xe9, 0x7c, 0x33, 0xe8, 0xa3, 0x07, 0xb0, 0xdc, 0xf3, 0xbd, 0xd1, 0xd0, 0x30, 0xc3, 0xd0, 0x77,
-	0x2e, 0x47, 0x21, 0xa6, 0xe1, 0x2b, 0x68, 0x15, 0x4a, 0x6e, 0x09, 0xaa, 0xf2, 0xc7, 0x34, 0x14,
-	0xa5, 0x20, 0xa0, 0x1a, 0xe4, 0x9c, 0x20, 0x18, 0x61, 0x9f, 0x1f, 0x12, 0x5f, 0xa1, 0x87, 0x50,
-	0x60, 0xf9, 0x6d, 0x38, 0x36, 0x3b, 0xa4, 0xfd, 0xd2, 0xf8, 0xa6, 0x91, 0x3f, 0xa0, 0xc4, 0xce,
-	0xa1, 0x96, 0x67, 0xec, 0x8e, 0x8d, 0xb6, 0xa1, 0xcc, 0x45, 0x03, 0x6c, 0xf9, 0x38, 0xe4, 0x3b,
-	0x97, 0x18, 0x51, 0xa7, 0x34, 0xf2, 0x52, 0x3e, 0xb6, 0x1d, 0x1f, 0x5b, 0xa1, 0x31, 0xf2, 0x1d,
-	0x1a, 0x62, 0x1e, 0x08, 0x8d, 0xd3, 0x2f, 0xb4, 0x8e, 0x56, 0x14, 0x42, 0x17, 0xbe, 0x83, 0x3e,
-	0x83, 0x15, 0xd3, 0xb6, 0x1d, 0xe2, 0xa8, 0xd9, 0x37, 0x02, 0xcb, 0x1b, 0xe2, 0xa0, 0x9e, 0xdd,
-	0xca, 0xec, 0x14, 0xb4, 0xea, 0x84, 0xa1, 0x53, 0x3a, 0xda, 0x83, 0x7b, 0x4e, 0xcf, 0xf5, 0x7c,
-	0x6c, 0xe0, 0x81, 0xe9, 0xf4, 0x8d, 0x6b, 0xec, 0x3b, 0xaf, 0x1d, 0x6c, 0xd7, 0x73, 0x5b, 0xa9,
-	0x9d, 0xbc, 0xb6, 0xca, 0x98, 0x6d, 0xc2, 0x7b, 0xc9, 0x59, 0xca, 0x32, 0x94, 0x63, 0x47, 0xaa,
-	0xfe, 0x2b, 0x03, 0xd0, 0x1a, 0x85, 0x57, 0x07, 0x9e, 0xfb, 0xda, 0xe9, 0xa1, 0x26, 0xac, 0xf6,
-	0x9d, 0x6b, 0x6c, 0x58, 0x74, 0x49, 0x4c, 0x06, 0x24, 0x67, 0x49, 0xa4, 0x32, 0xda, 0x0a, 0x61,
-	0x31, 0xc1, 0x97, 0x8c, 0x81, 0x0e, 0xa1, 0xe4, 0xd8, 0xc6, 0x90, 0xa7, 0x4b, 0x50, 0x4f, 0x6f,
-	0x65, 0x76, 0x8a, 0x7b, 0xd5, 0xe9, 0x3c, 0x62, 0xaf, 0x3d, 0x59, 0x07, 0x5a, 0xd1, 0xb1, 0xa3,
-	0x05, 0xc2, 0x50, 0x25, 0xb9, 0x6c, 0x04, 0xd7, 0x96, 0xe1, 0x31, 0xc7, 0xf8, 0x5d, 0xd8, 0x66,
-	0x96, 0x26, 0x1e, 0xd2, 0xbb, 0xa0, 0x63, 0xff, 0xda, 0xb1, 0xb0, 0x48, 0xcb, 0xda, 0xf8, 0xa6,
-	0x81, 0x66, 0xe9, 0x5a, 0x85, 0x18, 0xd5, 0xaf, 0x2d, 0xbe, 0x56, 0xfe, 0x9b, 0x82, 0x04, 0x31,
-	0xb4, 0x0d, 0x4b, 0xa6, 0x15, 0x48, 0xc9, 0x4a, 0xd3, 0xbc, 0x75, 0xa0, 0x93, 0x3c, 0xcd, 0x99,
-	0x56, 0x30, 0x9d, 0xa2, 0x44, 0x32, 0x7d, 0x87, 0xb4, 0xfe, 0x14, 0xf2, 0xb6, 0x19, 0x5c, 0x51,
-	0x79, 0x9a, 0x21, 0xfb, 0xc5, 0xf1, 0x4d, 0x63, 0xe9, 0xd0, 0x0c, 0xae, 0x88, 0xec, 0x12, 0x61,
-	0x12, 0xb9, 0x87, 0x50, 0x0d, 0x70, 0x40, 0xe2, 0x69, 0xd8, 0x23, 0xdf, 0xa4, 0x55, 0x82, 0x66,
-	0x8b, 0xb6, 0xcc, 0xe9, 0x87, 0x9c, 0x4c, 0x32, 0xcf, 0xc6, 0x97, 0xa3, 0x9e, 0xd1, 0xf7, 0x7a,
-	0x3d, 0xc7, 0xed, 0xd1, 0x6b, 0x9f, 0xd7, 0x4a, 0x94, 0x78, 0xcc, 0x68, 0xea, 0x06, 0xac, 0x1f,
-	0xe1, 0x90, 0xc5, 0x8b, 0x2b, 0x8a, 0x22, 0xa6, 0x41, 0x7d, 0x96, 0xc5, 0x8b, 0xe2, 0x0f, 0xa1,
-	0x6c, 0xc9, 0x0c, 0x1a, 0x8d, 0xe8, 0x30, 0x27, 0x47, 0xa0, 0xc5, 0xc5, 0xd4, 0x9f, 0xc1, 0xba,
-	0x9e, 0xbc, 0xdd, 0xb7, 0x36, 0xa9, 0x40, 0x5d, 0x9f, 0xe3, 0xa6, 0xfa, 0x0c, 0x4a, 0x07, 0xfd,
-	0x51, 0x10, 0x62, 0x5f, 0xf3, 0xfa, 0x38, 0x40, 0x0f, 0x20, 0xeb, 0x93, 0x87, 0x7a, 0x6a, 0x2b,
-	0xb3, 0x53, 0xd9, 0x5b, 0x61, 0xb6, 0x25, 0x11, 0x8d, 0xf1, 0xd5, 0x06, 0xdc, 0x27, 0xef, 0x3e,
-	0x61, 0xec, 0x3b, 0xae, 0xed, 0xb8, 0xbd, 0x40, 0x04, 0xe7, 0xef, 0x29, 0xd8, 0x9c, 0x27, 0xc1,
-	0x63, 0x74, 0x0a, 0xf9, 0x4b, 0x4e, 0xa3, 0xfb, 0x15, 0xf7, 0xf6, 0xd8, 0x7e, 0xb7, 0xeb, 0x35,
-	0x05, 0xa1, 0xed, 0x86, 0xfe, 0x3b, 0x2d, 0xb2, 0xa1, 0x9c, 0x41, 0x39, 0xc6, 0x42, 0x55, 0xc8,
-	0xbc, 0xc1, 0xef, 0x78, 0x69, 0x22, 0x8f, 0x68, 0x07, 0xb2, 0xd7, 0x66, 0x7f, 0x84, 0x69, 0xca,
-	0x15, 0xf7, 0xd0, 0xcc, 0xfb, 0x05, 0x1a, 0x13, 0xf8, 0x22, 0xfd, 0x79, 0x4a, 0x75, 0xa0, 0x71,
-	0xe2, 0xd9, 0xce, 0xeb, 0x77, 0xb3, 0xde, 0x88, 0x43, 0xf9, 0x18, 0x0a, 0x43, 0xdf, 0x71, 0x2d,
-	0x67, 0x68, 0xf6, 0xa3, 0xde, 0x27, 0x08, 0x64, 0x3b, 0x16, 0xce, 0x5b, 0xb6, 0x63, 0xf1, 0x54,
-	0x61, 0x6b, 0xfe, 0x56, 0xfc, 0xb0, 0x10, 0x54, 0x8f, 0x70, 0xd8, 0xb2, 0x07, 0x8e, 0x1b, 0x85,
-	0xf9, 0x33, 0x58, 0x91, 0x68, 0x3c, 0xb0, 0x35, 0xc8, 0x99, 0x94, 0x42, 0xc3, 0x5a, 0xd0, 0xf8,
-	0x4a, 0xfd, 0x09, 0xac, 0xb2, 0x4d, 0x62, 0x36, 0x48, 0x98, 0x4c, 0xdb, 0xe6, 0xb2, 0xe4, 0x91,
-	0x18, 0xf0, 0xf1, 0xc0, 0xbb, 0xc6, 0xb4, 0x06, 0x15, 0x34, 0xbe, 0x52, 0x6b, 0xb0, 0x16, 0x37,
-	0xc0, 0x3d, 0x73, 0x61, 0xe9, 0xac, 0x7b, 0xde, 0x71, 0x5f, 0x7b, 0x32, 0xde, 0x48, 0xc5, 0xf1,
-	0x46, 0x07, 0x90, 0xb8, 0x99, 0xf8, 0xed, 0xd0, 0xe1, 0x49, 0xcc, 0x22, 0xa3, 0x34, 0x19, 0xb4,
-	0x69, 0x0a, 0x68, 0xd3, 0xec, 0x0a, 0x68, 0xa3, 0xad, 0x70, 0xad, 0x76, 0xa4, 0xa4, 0xfe, 0x29,
-	0x05, 0x05, 0x8a, 0x2e, 0xde, 0xb3, 0xe5, 0x13, 0xc8, 0x05, 0xde, 0xc8, 0xb7, 0xd8, 0x79, 0x57,
-	0xf6, 0x3e, 0x62, 0x07, 0x10, 0xa9, 0xb2, 0x27, 0x9d, 0x8a, 0x68, 0x5c, 0x54, 0x7d, 0x0e, 0x45,
-	0x89, 0x8c, 0x8a, 0xb0, 0xd4, 0x39, 0x7d, 0xd9, 0x3a, 0xee, 0x1c, 0x56, 0x17, 0x50, 0x15, 0x4a,
-	0xad, 0x8b, 0xee, 0x57, 0xed, 0xd3, 0x6e, 0xe7, 0xa0, 0xd5, 0x6d, 0x57, 0x53, 0xa8, 0x0c, 0x85,
-	0xa3, 0x76, 0xd7, 0xe8, 0x9e, 0xfd, 0xb4, 0x7d, 0x5a, 0x4d, 0xab, 0x7f, 0x4b, 0xc1, 0x2a, 0xb9,
-	0x8a, 0xd8, 0x0d, 0x1d, 0x4b, 0x82, 0x61, 0xdf, 0x02, 0x6c, 0xa1, 0xef, 0x01, 0x10, 0x64, 0x61,
-	0x04, 0xa1, 0x29, 0x1a, 0xf2, 0x7e, 0x79, 0x7c, 0xd3, 0x28, 0x90, 0x0e, 0xac, 0x13, 0xa2, 0x56,
-	0x20, 0x02, 0xf4, 0x11, 0x3d, 0x82, 0x15, 0xcf, 0xc5, 0x06, 0x81, 0x84, 0xc6, 0xd0, 0x0c, 0x82,
-	0xdf, 0x78, 0x3e, 0x6f, 0xbd, 0xda, 0xb2, 0xe7, 0x62, 0x12, 0xcf, 0x73, 0x4e, 0x46, 0x1b, 0x90,
-	0x77, 0x6c, 0xee, 0x09, 0x2b, 0x8e, 0x4b, 0x8e, 0xcd, 0xe0, 0xda, 0x53, 0x58, 0x8b, 0xfb, 0x7f,
-	0x37, 0x94, 0xb7, 0x0c, 0xe5, 0x57, 0x57, 0x5e, 0x6b, 0xd0, 0x11, 0x89, 0xf9, 0xe7, 0x14, 0x54,
-	0x04, 0x85, 0x9b, 0x50, 0x20, 0x3f, 0x0a, 0xb0, 0x2f, 0x61, 0xba, 0x68, 0x4d, 0x3d, 0x0a, 0x0c,
-	0x9a, 0xa7, 0xd4, 0xe9, 0xbc, 0xb6, 0xe4, 0x04, 0x34, 0xcb, 0xd0, 0x06, 0x64, 0xc2, 0x90, 0x15,
-	0xfd, 0xcc, 0xfe, 0xd2, 0xf8, 0xa6, 0x91, 0xe9, 0x76, 0x8f, 0x35, 0x42, 0x43, 0xcf, 0x08, 0x76,
-	0xa0, 0xf7, 0xc5, 0x60, 0xf7, 0x6c, 0x71, 0xee, 0x3d, 0x2b, 0x59, 0xd2, 0x4a, 0xfd, 0x7d, 0x0a,
-	0x32, 0xad, 0x83, 0x63, 0xf4, 0x18, 0x96, 0xb0, 0x1b, 0xfa, 0x0e, 0x16, 0x15, 0xa8, 0xc6, 0xab,
-	0xe9, 0xc1, 0x71, 0xb3, 0xcd, 0x18, 0xac, 0xca, 0x08, 0x31, 0xe5, 0x08, 0x4a, 0x32, 0x23, 0xa1,
-	0xc6, 0x7c, 0x47, 0xae, 0x31, 0x95, 0xbd, 0x22, 0xb3, 0x48, 0x71, 0x86, 0x5c, 0x5c, 0x7e, 0x07,
-	0xd9, 0x8b, 0x80, 0x34, 0xec, 0xcf, 0xa1, 0x20, 0xc2, 0x20, 0xbc, 0x50, 0x98, 0x0e, 0xe5, 0xd3,
-	0x5f, 0xca, 0x64, 0x9e, 0x4c, 0x84, 0x95, 0x2f, 0xa1, 0x12, 0x67, 0x26, 0x78, 0xb3, 0x26, 0x7b,
-	0x93, 0x97, 0x1d, 0x18, 0x41, 0xee, 0x88, 0xa0, 0xbb, 0x00, 0x3d, 0x86, 0x1c, 0xc5, 0x79, 0x62,
-	0xfb, 0x3a, 0x2f, 0xc3, 0x94, 0xc6, 0xff, 0xb0, 0xcd, 0xb9, 0x9c, 0xf2, 0x23, 0x28, 0x4a, 0xe4,
-	0x0f, 0xda, 0xb6, 0x03, 0x55, 0x92, 0x60, 0x9e, 0xef, 0xfc, 0x36, 0xba, 0x1d, 0x08, 0x16, 0x7d,
-	0x3c, 0xf4, 0x04, 0xd2, 0x27, 0xcf, 0x24, 0x8c, 0x14, 0xb3, 0x25, 0x86, 0x91, 0x72, 0xd4, 0x27,
-	0xb0, 0x22, 0x99, 0xe2, 0x59, 0xb6, 0x09, 0x60, 0x0a, 0xa2, 0x4d, 0x2d, 0xe6, 0x35, 0x89, 0xa2,
-	0x1e, 0xc0, 0xf2, 0x11, 0x0e, 0x99, 0x1d, 0xbe, 0xfd, 0x6d, 0x89, 0xb9, 0x06, 0x59, 0xe2, 0x4e,
-	0xc0, 0x2b, 0x21, 0x5b, 0xa8, 0xcf, 0x68, 0x29, 0xe6, 0x46, 0xf8, 0xc6, 0xdb, 0x90, 0xe3, 0x20,
-	0x93, 0x35, 0xcf, 0x98, 0xc7, 0x9c, 0xa5, 0xda, 0xb0, 0xac, 0x7f, 0xc0, 0xee, 0x22, 0x30, 0xe9,
-	0xa4, 0xc0, 0x64, 0xe6, 0x06, 0x06, 0x41, 0x55, 0x9f, 0x72, 0x4f, 0xdd, 0x86, 0x32, 0xe9, 0x14,
-	0x07, 0xc7, 0xb7, 0x04, 0x5d, 0xed, 0x40, 0xbe, 0x75, 0x70, 0xcc, 0x0e, 0xf5, 0x36, 0xbf, 0xee,
-	0x70, 0x38, 0x1e, 0x54, 0xc4, 0x7e, 0x3c, 0x40, 0x3b, 0xd3, 0x97, 0xad, 0x12, 0x5d, 0xb6, 0xf8,
-	0x25, 0x43, 0x4f, 0xa0, 0xec, 0x7b, 0x97, 0x5e, 0x68, 0x08, 0xf9, 0x74, 0xa2, 0x7c, 0x89, 0x0a,
-	0xf1, 0xeb, 0xa8, 0x9e, 0x40, 0x59, 0x7f, 0xdf, 0x0b, 0xca, 0x3e, 0xa4, 0x6f, 0xf5, 0x41, 0xad,
-	0x42, 0x45, 0x8f, 0xf9, 0xaf, 0xfe, 0x12, 0x8a, 0x3a, 0x6b, 0x45, 0xb4, 0xed, 0xac, 0x41, 0xd6,
-	0xf5, 0x5c, 0x4b, 0x04, 0x87, 0x2d, 0x08, 0x95, 0x4e, 0x10, 0xfc, 0xc8, 0xd8, 0x02, 0x7d, 0x02,
-	0x15, 0xcb, 0x73, 0xf9, 0x04, 0x60, 0x60, 0xdf, 0xa7, 0x87, 0x97, 0xa7, 0x50, 0x8d, 0x53, 0xdb,
-	0xbe, 0xaf, 0xde, 0x83, 0xd5, 0x23, 0x1c, 0x92, 0xf2, 0x7e, 0xec, 0xf5, 0x9c, 0x08, 0x68, 0xbe,
-	0x82, 0xb5, 0x38, 0x99, 0x07, 0xf4, 0x21, 0x14, 0xfa, 0x84, 0x20, 0xc1, 0x6d, 0x3a, 0x65, 0x51,
-	0x29, 0x82, 0x8a, 0xf3, 0x94, 0x4d, 0x60, 0xf1, 0x1a, 0x64, 0x59, 0x1b, 0xe1, 0x6e, 0xd1, 0x85,
-	0xfa, 0x35, 0xdd, 0x8f, 0xdc, 0x21, 0xd6, 0x7e, 0x66, 0xbf, 0x19, 0x4c, 0x35, 0x54, 0x5e, 0x8b,
-	0xd3, 0xb3, 0xb5, 0x58, 0x7d, 0x41, 0x9d, 0x94, 0x6c, 0x71, 0x27, 0xe7, 0x7f, 0x80, 0x58, 0x83,
-	0xac, 0xdc, 0x4d, 0xd8, 0x42, 0xed, 0x40, 0xad, 0xfd, 0x36, 0xc4, 0xae, 0x3d, 0xe3, 0x56, 0xa2,
-	0xfc, 0x6d, 0x2e, 0x6d, 0xc0, 0xfa, 0x8c, 0x29, 0x7e, 0x96, 0x4d, 0xa8, 0x69, 0xf8, 0xda, 0x7b,
-	0x83, 0xef, 0xb6, 0x0b, 0x31, 0x35, 0x23, 0xcf, 0x4d, 0x9d, 0x50, 0xc8, 0xce, 0xca, 0xe1, 0x0b,
-	0xcf, 0x27, 0x15, 0xf9, 0x2e, 0x57, 0xbb, 0x16, 0x15, 0x5d, 0x8e, 0xb1, 0xd8, 0x8a, 0xc3, 0xf5,
-	0x29, 0x73, 0x7c, 0xab, 0x97, 0x02, 0x7f, 0x9d, 0xe0, 0xc1, 0x25, 0x99, 0xfc, 0x26, 0x3e, 0x53,
-	0x6d, 0xe1, 0x33, 0x5d, 0x08, 0x5c, 0x97, 0x4e, 0xc2, 0x75, 0x99, 0x18, 0xae, 0x5b, 0x87, 0x7b,
-	0x53, 0x76, 0xa3, 0x30, 0x91, 0x3a, 0xc7, 0x9c, 0xb9, 0xc3, 0x4b, 0x71, 0x38, 0x2a, 0xe4, 0x27,
-	0x70, 0x54, 0x6a, 0x2f, 0x93, 0x37, 0x7d, 0x40, 0x2b, 0x31, 0x6d, 0x72, 0xb7, 0xbe, 0x88, 0xfa,
-	0x98, 0x7a, 0xc1, 0x05, 0xb9, 0xd1, 0x8f, 0xa7, 0xbb, 0x66, 0x41, 0xea, 0x8c, 0xea, 0x39, 0x6c,
-	0x90, 0x1b, 0x13, 0x87, 0x3d, 0xff, 0x57, 0x7a, 0xff, 0x21, 0x05, 0x4a, 0x92, 0x49, 0xee, 0x0e,
-	0x82, 0x45, 0xcb, 0xb3, 0xa3, 0x6f, 0x55, 0xe4, 0x19, 0x75, 0xa1, 0xe2, 0x85, 0xc3, 0x0f, 0x02,
-	0xbb, 0xfb, 0x2b, 0xe3, 0x9b, 0x46, 0xf9, 0xac, 0x7b, 0x3e, 0x01, 0xbb, 0x5a, 0xd9, 0x0b, 0x87,
-	0x93, 0xe5, 0xa3, 0x5d, 0x28, 0x4a, 0xc0, 0x86, 0xe0, 0xcf, 0x8b, 0xd3, 0xc3, 0xf6, 0x8b, 0xce,
-	0x69, 0x9b, 0x00, 0xd4, 0x02, 0x64, 0xf5, 0x8b, 0xf3, 0xb6, 0x56, 0x4d, 0xa1, 0x1c, 0xa4, 0x5f,
-	0xe8, 0xd5, 0xf4, 0xa3, 0x1f, 0x40, 0x96, 0x16, 0x66, 0x94, 0x87, 0xc5, 0xd3, 0xb3, 0xd3, 0x76,
-	0x75, 0x01, 0x01, 0xe4, 0xb4, 0x76, 0xeb, 0x90, 0x8a, 0x01, 0xe4, 0x5e, 0x69, 0x9d, 0x6e, 0x5b,
-	0xab, 0xa6, 0x89, 0xf6, 0xd9, 0xab, 0xd3, 0xb6, 0x56, 0xcd, 0xec, 0xfd, 0xa7, 0x0c, 0x99, 0xd6,
-	0x79, 0x07, 0x3d, 0x87, 0xbc, 0xf8, 0xe2, 0x87, 0xee, 0xf1, 0x5a, 0x19, 0xff, 0x98, 0xa7, 0xd4,
-	0xa6, 0xc9, 0x3c, 0x79, 0x16, 0x50, 0x0b, 0x60, 0xf2, 0x99, 0x0f, 0xad, 0x33, 0xb9, 0x99, 0xaf,
-	0x81, 0x4a, 0x7d, 0x96, 0x11, 0x99, 0xd0, 0xe9, 0xd9, 0xc7, 0xa6, 0x57, 0x74, 0x7f, 0x32, 0x26,
-	0x26, 0x0c, 0xca, 0xca, 0xe6, 0x3c, 0xb6, 0x6c, 0x54, 0x9f, 0x63, 0x54, 0xbf, 0xdd, 0xa8, 0x3e,
-	0xdf, 0xe8, 0x8f, 0xa1, 0x10, 0x8d, 0x62, 0xa8, 0x16, 0xf9, 0x10, 0x9b, 0xb5, 0x94, 0xf5, 0x19,
-	0x7a, 0xa4, 0x7f, 0x04, 0x25, 0x79, 0xb8, 0x42, 0x1b, 0x4c, 0x34, 0x61, 0x62, 0x53, 0x94, 0x24,
-	0x56, 0x64, 0x08, 0x43, 0x2d, 0x79, 0x82, 0x46, 0xdb, 0xb7, 0xcf, 0xd7, 0xcc, 0xf8, 0x77, 0xef,
-	0x32, 0x84, 0xab, 0x0b, 0xe8, 0x0d, 0xd4, 0xe7, 0x8d, 0xac, 0xe8, 0x13, 0xd9, 0xc1, 0xb9, 0xd3,
-	0xb3, 0xf2, 0xe9, 0xfb, 0xc4, 0xe4, 0xe0, 0xc8, 0x63, 0x89, 0x08, 0x4e, 0xc2, 0xa8, 0x25, 0x82,
-	0x93, 0x34, 0xc5, 0xb0, 0x53, 0x8a, 0x30, 0xa3, 0x38, 0xa5, 0x69, 0x3c, 0x2a, 0x4e, 0x69, 0x06,
-	0x5c, 0xaa, 0x0b, 0xe8, 0x29, 0xe4, 0xd8, 0x58, 0x83, 0x56, 0x99, 0x50, 0x6c, 0xec, 0x51, 0xd6,
-	0xe2, 0xc4, 0x48, 0xed, 0x39, 0xe4, 0x05, 0x60, 0x14, 0xd7, 0x68, 0x0a, 0x85, 0x2a, 0xb5, 0x69,
-	0xb2, 0xac, 0xac, 0x4f, 0x29, 0xeb, 0xc9, 0xca, 0xfa, 0xac, 0xf2, 0x53, 0xc8, 0x31, 0x1c, 0x26,
-	0x1c, 0x8e, 0xa1, 0x40, 0xe1, 0x70, 0x1c, 0xaa, 0x31, 0x35, 0x3d, 0xa6, 0xa6, 0x27, 0xa9, 0xe9,
-	0xd3, 0x6a, 0x47, 0x50, 0x92, 0xa1, 0x8a, 0x38, 0xa7, 0x04, 0x54, 0x23, 0xce, 0x29, 0x09, 0xd9,
-	0x44, 0x86, 0xa2, 0x6e, 0x2b, 0x19, 0x9a, 0xee, 0xd8, 0x92, 0xa1, 0xd9, 0xe6, 0xbc, 0x80, 0xce,
-	0x61, 0x79, 0x0a, 0x04, 0x20, 0xfe, 0x61, 0x3f, 0x19, 0x66, 0x28, 0xf7, 0xe7, 0x70, 0x65, 0x8b,
-	0x53, 0x58, 0x40, 0x58, 0x4c, 0x86, 0x14, 0xc2, 0xe2, 0x3c, 0x00, 0x21, 0xea, 0x51, 0xac, 0xe7,
-	0x4b, 0xf5, 0x28, 0x09, 0x5a, 0x48, 0xf5, 0x28, 0x19, 0x2a, 0x2c, 0xa0, 0xaf, 0xa1, 0x1c, 0x6b,
-	0xea, 0x28, 0x56, 0x35, 0xe2, 0x08, 0x42, 0xf9, 0x28, 0x91, 0x37, 0x55, 0xdb, 0xf8, 0xb8, 0x38,
-	0x49, 0xd4, 0x18, 0x30, 0x90, 0x6a, 0x5b, 0x1c, 0x00, 0x44, 0xe9, 0xcf, 0xe6, 0xdd, 0x49, 0xfa,
-	0xcb, 0xad, 0x5f, 0x4a, 0xff, 0x58, 0xa3, 0x57, 0x17, 0xd0, 0xcf, 0x01, 0xcd, 0x76, 0x5e, 0xd4,
-	0x98, 0xa4, 0x4f, 0x62, 0x9b, 0x57, 0xb6, 0xe6, 0x0b, 0x08, 0xd3, 0xfb, 0x5f, 0xfe, 0x63, 0xbc,
-	0x99, 0xfa, 0xe7, 0x78, 0x33, 0xf5, 0xef, 0xf1, 0x66, 0xea, 0x17, 0x4d, 0xf6, 0xf5, 0xa5, 0x69,
-	0x79, 0x83, 0xdd, 0xa1, 0x69, 0x5d, 0xbd, 0xb3, 0xb1, 0x2f, 0x3f, 0x05, 0xbe, 0xb5, 0x2b, 0xfd,
-	0xdb, 0xee, 0x32, 0x47, 0x1b, 0xf8, 0x93, 0xff, 0x05, 0x00, 0x00, 0xff, 0xff, 0xb8, 0x75, 0xb4,
-	0x07, 0xcc, 0x1b, 0x00, 0x00,
+	// 2563 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x59, 0x4f, 0x73, 0xe3, 0x48,
+	0x15, 0x1f, 0xdb, 0xb1, 0x63, 0x3f, 0xdb, 0x89, 0xd3, 0xf1, 0x38, 0x1e, 0xed, 0x4e, 0x9c, 0x55,
+	0xd8, 0x9d, 0xec, 0x2e, 0xe5, 0x2c, 0x19, 0x76, 0x67, 0xd9, 0xd9, 0x82, 0x72, 0x12, 0x4f, 0xd6,
+	0x90, 0x7f, 0xb4, 0x9c, 0x99, 0x85, 0x8b, 0x50, 0xa4, 0x1e, 0x47, 0x8c, 0x2d, 0x19, 0x49, 0x0e,
+	0x13, 0x2e, 0x70, 0xe2, 0xb0, 0x1f, 0x82, 0x33, 0x5f, 0x02, 0xaa, 0xb8, 0x71, 0x84, 0x2f, 0x90,
+	0xa2, 0x4c, 0xf1, 0x29, 0xb8, 0x50, 0xfd, 0x47, 0x72, 0xcb, 0x96, 0x3d, 0x99, 0xe1, 0x92, 0xa8,
+	0x7f, 0xef, 0x4f, 0xbf, 0x7e, 0xfd, 0xfa, 0xbd, 0xd7, 0x6d, 0xa8, 0x99, 0x7d, 0x9b, 0x38, 0xc1,
+	0xae, 0x31, 0x0a, 0xae, 0xd8, 0x9f, 0xe6, 0xd0, 0x73, 0x03, 0x17, 0x2d, 0xd1, 0x6f, 0xa5, 0xda,
+	0x73, 0x7b, 0x2e, 0x03, 0x76, 0xe9, 0x17, 0xa7, 0x29, 0x8d, 0x9e, 0xeb, 0xf6, 0xfa, 0x64, 0x97,
+	0x8d, 0x2e, 0x47, 0x2f, 0x77, 0x03, 0x7b, 0x40, 0xfc, 0xc0, 0x18, 0x0c, 0x39, 0x83, 0xaa, 0xc3,
+	0x6a, 0xcb, 0x0c, 0xec, 0x6b, 0x23, 0x20, 0x98, 0xfc, 0x66, 0x44, 0xfc, 0x00, 0xd5, 0x61, 0xd9,
+	0x1f, 0x5d, 0xfe, 0x9a, 0x98, 0x41, 0x3d, 0xbd, 0x95, 0xda, 0x29, 0xe0, 0x70, 0x88, 0xf6, 0xa0,
+	0xd4, 0xb3, 0x83, 0xab, 0xd1, 0xa5, 0x1e, 0xb8, 0xaf, 0x88, 0x53, 0x4f, 0x51, 0xf2, 0xfe, 0xea,
+	0xf8, 0xb6, 0x51, 0x3c, 0xb2, 0x83, 0x6f, 0x46, 0x97, 0x5d, 0x0a, 0xe3, 0x22, 0x67, 0x62, 0x03,
+	0xf5, 0x07, 0x50, 0x99, 0x4c, 0xe0, 0x0f, 0x5d, 0xc7, 0x27, 0xe8, 0x21, 0xc0, 0xd0, 0x30, 0xaf,
+	0x64, 0x2d, 0xb8, 0x40, 0x11, 0x2e, 0xb2, 0x0e, 0x6b, 0x87, 0xc4, 0x88, 0x5b, 0xa5, 0x56, 0x01,
+	0xc9, 0x20, 0xd7, 0xa4, 0xfe, 0x25, 0x0b, 0xd0, 0x39, 0x3c, 0xf7, 0xdc, 0x6b, 0xdb, 0x22, 0x1e,
+	0x42, 0xb0, 0xe4, 0x18, 0x03, 0x22, 0x54, 0xb2, 0x6f, 0xb4, 0x05, 0x45, 0x8b, 0xf8, 0xa6, 0x67,
+	0x0f, 0x03, 0xdb, 0x75, 0xc4, 0x92, 0x64, 0x08, 0x7d, 0x05, 0x4b, 0xbe, 0x31, 0xe8, 0xd7, 0x33,
+	0x5b, 0xa9, 0x9d, 0xe2, 0xde, 0xfb, 0x4d, 0xe6, 0xdb, 0x89, 0xd6, 0xa6, 0xd6, 0x3a, 0x39, 0x3e,
+	0x63, 0xac, 0xfe, 0x7e, 0x7e, 0x7c, 0xdb, 0x58, 0xa2, 0x00, 0x66, 0x32, 0x54, 0xd6, 0xb5, 0x2d,
+	0xb3, 0x9e, 0x9d, 0x23, 0x7b, 0xd6, 0x39, 0x3c, 0x88, 0xc9, 0x52, 0x00, 0x33, 0x19, 0xb4, 0x0f,
+	0x39, 0xee, 0xa9, 0xfa, 0x12, 0x93, 0xde, 0x9c, 0x91, 0xe6, 0x5e, 0x0d, 0xe5, 0x61, 0x7c, 0xdb,
+	0xc8, 0x71, 0x08, 0x0b, 0x49, 0xe5, 0x4f, 0x29, 0x28, 0x4a, 0xf6, 0xd1, 0x2d, 0x1a, 0x90, 0xc0,
+	0xb0, 0x8c, 0xc0, 0xd0, 0x47, 0x5e, 0x5f, 0xde, 0xa2, 0x13, 0x81, 0x5f, 0xe0, 0x63, 0x5c, 0x0c,
+	0x99, 0x2e, 0xbc, 0x7e, 0x4c, 0xe6, 0xf5, 0xa0, 0xcf, 0x5c, 0x54, 0x8a, 0xcb, 0x7c, 0x7b, 0x22,
+	0xc9, 0x7c, 0x3b, 0xe8, 0xa3, 0x47, 0xb0, 0xda, 0xf3, 0xdc, 0xd1, 0x50, 0x37, 0x82, 0xc0, 0xb3,
+	0x2f, 0x47, 0x01, 0x61, 0xee, 0x2b, 0xe0, 0x15, 0x06, 0xb7, 0x42, 0x54, 0xf9, 0x2e, 0x0d, 0x45,
+	0xc9, 0x09, 0xa8, 0x06, 0x39, 0xdb, 0xf7, 0x47, 0xc4, 0x13, 0x9b, 0x24, 0x46, 0xe8, 0x63, 0x28,
+	0xf0, 0xf8, 0xd6, 0x6d, 0x8b, 0x6f, 0xd2, 0x7e, 0x69, 0x7c, 0xdb, 0xc8, 0x1f, 0x30, 0xb0, 0x73,
+	0x88, 0xf3, 0x9c, 0xdc, 0xb1, 0xd0, 0x36, 0x94, 0x05, 0xab, 0x4f, 0x4c, 0x8f, 0x04, 0x62, 0xe6,
+	0x12, 0x07, 0x35, 0x86, 0xd1, 0x45, 0x79, 0xc4, 0xb2, 0x3d, 0x62, 0x06, 0xfa, 0xc8, 0xb3, 0xeb,
+	0x4b, 0x13, 0x47, 0x60, 0x81, 0x5f, 0xe0, 0x0e, 0x2e, 0x86, 0x4c, 0x17, 0x9e, 0x8d, 0x3e, 0x85,
+	0x35, 0xc3, 0xb2, 0x6c, 0x6a, 0xa8, 0xd1, 0xd7, 0x7d, 0xd3, 0x1d, 0x12, 0xbf, 0x9e, 0xdd, 0xca,
+	0xec, 0x14, 0x70, 0x65, 0x42, 0xd0, 0x18, 0x8e, 0xf6, 0xe0, 0xbe, 0xdd, 0x73, 0x5c, 0x8f, 0xe8,
+	0x64, 0x60, 0xd8, 0x7d, 0xfd, 0x9a, 0x78, 0xf6, 0x4b, 0x9b, 0x58, 0xf5, 0xdc, 0x56, 0x6a, 0x27,
+	0x8f, 0xd7, 0x39, 0xb1, 0x4d, 0x69, 0xcf, 0x05, 0x49, 0x59, 0x85, 0x72, 0x6c, 0x4b, 0xd5, 0x7f,
+	0x66, 0x00, 0x5a, 0xa3, 0xe0, 0xea, 0xc0, 0x75, 0x5e, 0xda, 0x3d, 0xd4, 0x84, 0xf5, 0xbe, 0x7d,
+	0x4d, 0x74, 0x93, 0x0d, 0xa9, 0x4a, 0x9f, 0xc6, 0x2c, 0xf5, 0x54, 0x06, 0xaf, 0x51, 0x12, 0x67,
+	0x7c, 0xce, 0x09, 0xe8, 0x10, 0x4a, 0xb6, 0xa5, 0x0f, 0x45, 0xb8, 0xf8, 0xf5, 0xf4, 0x56, 0x66,
+	0xa7, 0xb8, 0x57, 0x99, 0x8e, 0x23, 0xbe, 0xec, 0xc9, 0xd8, 0xc7, 0x45, 0xdb, 0x8a, 0x06, 0x88,
+	0x40, 0x85, 0xc6, 0xb2, 0xee, 0x5f, 0x9b, 0xba, 0xcb, 0x0d, 0x13, 0x67, 0x61, 0x9b, 0x6b, 0x9a,
+	0x58, 0xc8, 0xce, 0x82, 0x46, 0xbc, 0x6b, 0xdb, 0x24, 0x61, 0x58, 0xd6, 0xc6, 0xb7, 0x0d, 0x34,
+	0x8b, 0xe3, 0x15, 0xaa, 0x54, 0xbb, 0x36, 0xc5, 0x58, 0xf9, 0x4f, 0x0a, 0x12, 0xd8, 0xd0, 0x36,
+	0x2c, 0x1b, 0xa6, 0x2f, 0x05, 0x2b, 0x0b, 0xf3, 0xd6, 0x81, 0x46, 0xe3, 0x34, 0x67, 0x98, 0xfe,
+	0x74, 0x88, 0x52, 0xce, 0xf4, 0x1d, 0xc2, 0xfa, 0x23, 0xc8, 0x5b, 0x86, 0x7f, 0xc5, 0xf8, 0x59,
+	0x84, 0xec, 0x17, 0xc7, 0xb7, 0x8d, 0xe5, 0x43, 0xc3, 0xbf, 0xa2, 0xbc, 0xcb, 0x94, 0x48, 0xf9,
+	0x3e, 0x86, 0x8a, 0x4f, 0x7c, 0xea, 0x4f, 0xdd, 0x1a, 0x79, 0x06, 0xcb, 0x12, 0x2c, 0x5a, 0xf0,
+	0xaa, 0xc0, 0x0f, 0x05, 0x4c, 0x23, 0xcf, 0x22, 0x97, 0xa3, 0x9e, 0xde, 0x77, 0x7b, 0x3d, 0xdb,
+	0xe9, 0xb1, 0x63, 0x9f, 0xc7, 0x25, 0x06, 0x1e, 0x73, 0x4c, 0x7d, 0x00, 0x1b, 0x47, 0x24, 0xe0,
+	0xfe, 0x12, 0x82, 0x61, 0x12, 0xc3, 0x50, 0x9f, 0x25, 0x89, 0xa4, 0xf8, 0x05, 0x94, 0x4d, 0x99,
+	0xc0, 0xbc, 0x11, 0x6d, 0xe6, 0x64, 0x0b, 0x70, 0x9c, 0x4d, 0xfd, 0x39, 0x6c, 0x68, 0xc9, 0xd3,
+	0xbd, 0xb3, 0x4a, 0x05, 0xea, 0xda, 0x1c, 0x33, 0xd5, 0x27, 0x50, 0x3a, 0xe8, 0x8f, 0xfc, 0x80,
+	0x78, 0xd8, 0xed, 0x13, 0x1f, 0x3d, 0x82, 0xac, 0x47, 0x3f, 0xea, 0xa9, 0xad, 0xcc, 0xce, 0xca,
+	0xde, 0x1a, 0xd7, 0x2d, 0xb1, 0x60, 0x4e, 0x57, 0x1b, 0xf0, 0x90, 0xae, 0x7d, 0x42, 0xd8, 0xb7,
+	0x1d, 0xcb, 0x76, 0x7a, 0x7e, 0xe8, 0x9c, 0xbf, 0xa5, 0x60, 0x73, 0x1e, 0x87, 0xf0, 0xd1, 0x29,
+	0xe4, 0x2f, 0x05, 0xc6, 0xe6, 0x2b, 0xee, 0xed, 0xf1, 0xf9, 0x16, 0xcb, 0x35, 0x43, 0xa0, 0xed,
+	0x04, 0xde, 0x0d, 0x8e, 0x74, 0x28, 0x67, 0x50, 0x8e, 0x91, 0x50, 0x05, 0x32, 0xaf, 0xc8, 0x8d,
+	0x48, 0x4d, 0xf4, 0x13, 0xed, 0x40, 0xf6, 0xda, 0xe8, 0x8f, 0x08, 0x0b, 0xb9, 0xe2, 0x1e, 0x9a,
+	0x59, 0x9f, 0x8f, 0x39, 0xc3, 0x57, 0xe9, 0x2f, 0x53, 0xaa, 0x0d, 0x8d, 0x13, 0xd7, 0xb2, 0x5f,
+	0xde, 0xcc, 0x5a, 0x13, 0x6e, 0xca, 0xfb, 0x50, 0x18, 0x7a, 0xb6, 0x63, 0xda, 0x43, 0xa3, 0x1f,
+	0xd5, 0xbe, 0x10, 0xa0, 0xd3, 0x71, 0x77, 0x2e, 0x98, 0x8e, 0xfb, 0x53, 0x85, 0xad, 0xf9, 0x53,
+	0x89, 0xcd, 0x42, 0x50, 0x39, 0x22, 0x41, 0xcb, 0x1a, 0xd8, 0x4e, 0xe4, 0xe6, 0x4f, 0x61, 0x4d,
+	0xc2, 0x84, 0x63, 0x6b, 0x90, 0x33, 0x18, 0xc2, 0xdc, 0x5a, 0xc0, 0x62, 0xa4, 0xfe, 0x04, 0xd6,
+	0xf9, 0x24, 0x31, 0x1d, 0xd4, 0x4d, 0x86, 0x65, 0x09, 0x5e, 0xfa, 0x49, 0x15, 0x78, 0x64, 0xe0,
+	0x5e, 0x13, 0x96, 0x83, 0x0a, 0x58, 0x8c, 0xd4, 0x1a, 0x54, 0xe3, 0x0a, 0x84, 0x65, 0x0e, 0x2c,
+	0x9f, 0x75, 0xcf, 0x3b, 0xce, 0x4b, 0x57, 0xee, 0x37, 0x52, 0xf1, 0x7e, 0xa3, 0x03, 0x28, 0x3c,
+	0x99, 0xe4, 0xf5, 0xd0, 0x16, 0x41, 0xcc, 0x3d, 0xa3, 0x34, 0x79, 0x6b, 0xd3, 0x0c, 0x5b, 0x9b,
+	0x66, 0x37, 0x6c, 0x6d, 0xf0, 0x9a, 0x90, 0x6a, 0x47, 0x42, 0xea, 0x7f, 0x53, 0x50, 0x60, 0xdd,
+	0xc5, 0x1b, 0xa6, 0x7c, 0x0c, 0x39, 0xdf, 0x1d, 0x79, 0x26, 0xdf, 0xef, 0x95, 0xbd, 0xf7, 0xf8,
+	0x06, 0x44, 0xa2, 0xfc, 0x4b, 0x63, 0x2c, 0x58, 0xb0, 0x22, 0x15, 0x4a, 0xf6, 0x60, 0x48, 0x3c,
+	0xdf, 0x75, 0x8c, 0xc0, 0xf5, 0xc2, 0x7a, 0x24, 0x63, 0xe8, 0x23, 0x58, 0xf5, 0x88, 0x61, 0xe9,
+	0xae, 0xd3, 0xbf, 0xd1, 0x3d, 0x32, 0x74, 0xfd, 0xfa, 0x12, 0xf3, 0x54, 0x99, 0xc2, 0x67, 0x4e,
+	0xff, 0x06, 0x53, 0x90, 0x3a, 0x32, 0x20, 0x8e, 0xe1, 0x04, 0x2c, 0xb7, 0x14, 0xb0, 0x18, 0xa9,
+	0x4f, 0xa1, 0x28, 0x4d, 0x8d, 0x8a, 0xb0, 0xdc, 0x39, 0x7d, 0xde, 0x3a, 0xee, 0x1c, 0x56, 0xee,
+	0xa1, 0x0a, 0x94, 0x5a, 0x17, 0xdd, 0x6f, 0xda, 0xa7, 0xdd, 0xce, 0x41, 0xab, 0xdb, 0xae, 0xa4,
+	0x50, 0x19, 0x0a, 0x47, 0xed, 0xae, 0xde, 0x3d, 0xfb, 0x59, 0xfb, 0xb4, 0x92, 0x56, 0xff, 0x9a,
+	0x82, 0x75, 0x7a, 0xdc, 0x89, 0x13, 0xd8, 0xa6, 0xd4, 0xea, 0xbd, 0x43, 0x43, 0x87, 0xbe, 0x0f,
+	0x40, 0xbb, 0x17, 0xdd, 0x0f, 0x8c, 0xb0, 0xe8, 0xef, 0x97, 0xc7, 0xb7, 0x8d, 0x02, 0xad, 0xf2,
+	0x1a, 0x05, 0x71, 0x81, 0x32, 0xb0, 0x4f, 0xf4, 0x09, 0xac, 0xb9, 0x0e, 0xd1, 0x69, 0xdb, 0xa9,
+	0x0f, 0x0d, 0xdf, 0xff, 0xad, 0xeb, 0x89, 0xf2, 0x8e, 0x57, 0x5d, 0x87, 0xd0, 0x3d, 0x3b, 0x17,
+	0x30, 0x7a, 0x00, 0x79, 0xdb, 0x12, 0x96, 0xf0, 0x04, 0xbc, 0x6c, 0x5b, 0xbc, 0x25, 0xfc, 0x1c,
+	0xaa, 0x71, 0xfb, 0xef, 0xd6, 0x49, 0x7e, 0x01, 0xe5, 0x17, 0x57, 0x6e, 0x6b, 0xd0, 0x09, 0x17,
+	0xfc, 0x21, 0xac, 0xd8, 0x8e, 0xd9, 0x1f, 0x59, 0x24, 0x2c, 0xef, 0x29, 0x96, 0xc1, 0xcb, 0x02,
+	0xe5, 0xb5, 0x5d, 0xdd, 0x87, 0x02, 0xdd, 0x0d, 0x36, 0xa2, 0x4d, 0x25, 0xdd, 0xaf, 0xb0, 0xa9,
+	0xa4, 0xdf, 0xe8, 0x03, 0xc8, 0x32, 0x79, 0x11, 0x25, 0x45, 0x1e, 0x25, 0x8c, 0x1f, 0x73, 0x8a,
+	0xfa, 0xe7, 0x34, 0xac, 0x84, 0x93, 0x0b, 0x6b, 0x15, 0xc8, 0x8f, 0x7c, 0xe2, 0x49, 0x2d, 0x6a,
+	0x34, 0x66, 0x8b, 0xf7, 0x75, 0x76, 0xec, 0x98, 0xd2, 0x3c, 0x5e, 0xb6, 0x7d, 0x76, 0x68, 0xd0,
+	0x03, 0xc8, 0x04, 0x01, 0xaf, 0x61, 0x99, 0xfd, 0xe5, 0xf1, 0x6d, 0x23, 0xd3, 0xed, 0x1e, 0x63,
+	0x8a, 0xa1, 0x27, 0xb4, 0x15, 0x62, 0xc7, 0x5f, 0xe7, 0x69, 0x63, 0x69, 0x6e, 0xda, 0x28, 0x99,
+	0xd2, 0x68, 0x26, 0x64, 0xb3, 0x09, 0x21, 0xfb, 0x08, 0x72, 0xc2, 0x49, 0x39, 0x96, 0x6b, 0x57,
+	0xb9, 0xd6, 0xc8, 0x33, 0x58, 0x90, 0x93, 0x62, 0x7b, 0x79, 0x71, 0x6c, 0xe7, 0x63, 0xb1, 0xfd,
+	0x87, 0x14, 0x64, 0x5a, 0x07, 0xc7, 0xe8, 0x33, 0x58, 0x26, 0x4e, 0xe0, 0xd9, 0x24, 0xcc, 0xee,
+	0x35, 0x51, 0xa9, 0x0e, 0x8e, 0x9b, 0x6d, 0x4e, 0xe0, 0x19, 0x3c, 0x64, 0x53, 0x8e, 0xa0, 0x24,
+	0x13, 0x12, 0xf2, 0xf7, 0x07, 0x72, 0xfe, 0x9e, 0xde, 0xa9, 0x49, 0xe2, 0xfe, 0x3d, 0x64, 0x2f,
+	0x7c, 0xda, 0x0c, 0x7d, 0x09, 0x85, 0x70, 0x4f, 0x42, 0x2b, 0x14, 0x2e, 0xc3, 0xe8, 0xcd, 0x8b,
+	0x90, 0xc8, 0x2d, 0x99, 0x30, 0x2b, 0x5f, 0xc3, 0x4a, 0x9c, 0x98, 0x60, 0x4d, 0x55, 0xb6, 0x26,
+	0x2f, 0x1b, 0x30, 0x82, 0xdc, 0x11, 0xed, 0x9c, 0x7d, 0xf4, 0x19, 0xe4, 0x58, 0x0f, 0x1d, 0x4e,
+	0x5f, 0x17, 0x25, 0x8e, 0x61, 0xe2, 0x1f, 0x9f, 0x5c, 0xf0, 0x29, 0x3f, 0x82, 0xa2, 0x04, 0xbf,
+	0xd5, 0xb4, 0x1d, 0xa8, 0xd0, 0x83, 0xe5, 0x7a, 0xf6, 0xef, 0xa2, 0xac, 0xf0, 0x8e, 0x01, 0xff,
+	0x18, 0xd6, 0x24, 0x55, 0x22, 0xe4, 0x37, 0x01, 0x8c, 0x10, 0xb4, 0xc4, 0x61, 0x93, 0x10, 0xf5,
+	0x00, 0x56, 0x8f, 0x48, 0xc0, 0xf5, 0x88, 0xe9, 0x17, 0x9d, 0x92, 0x2a, 0x64, 0x79, 0x7c, 0xf1,
+	0x2a, 0xc3, 0x07, 0xea, 0x13, 0xa8, 0x4c, 0x94, 0x88, 0x89, 0xb7, 0xa3, 0xe0, 0xe5, 0x8d, 0x49,
+	0xcc, 0x62, 0x41, 0x52, 0x2d, 0x58, 0xd5, 0xde, 0x62, 0xf6, 0xd0, 0x31, 0xe9, 0x24, 0xc7, 0x64,
+	0xe6, 0x3a, 0x06, 0x41, 0x45, 0x9b, 0x32, 0x4f, 0xdd, 0x86, 0x32, 0xad, 0xc2, 0x07, 0xc7, 0x0b,
+	0x9c, 0xae, 0x76, 0x20, 0xdf, 0x3a, 0x38, 0xe6, 0x9b, 0xba, 0xc8, 0xae, 0x3b, 0x6c, 0x8e, 0x0b,
+	0x2b, 0xe1, 0x7c, 0xc2, 0x41, 0x3b, 0xd3, 0x87, 0x6d, 0x25, 0x3a, 0x6c, 0xf1, 0x43, 0x86, 0x1e,
+	0x43, 0xd9, 0x73, 0x2f, 0xdd, 0x40, 0x0f, 0xf9, 0xd3, 0x89, 0xfc, 0x25, 0xc6, 0x24, 0x8e, 0xa3,
+	0x7a, 0x02, 0x65, 0xed, 0x4d, 0x0b, 0x94, 0x6d, 0x48, 0x2f, 0xb4, 0x41, 0xad, 0xc0, 0x8a, 0x16,
+	0xb3, 0x5f, 0xfd, 0x15, 0x14, 0x35, 0x5e, 0xe6, 0x59, 0x49, 0xaf, 0x42, 0xd6, 0x71, 0x1d, 0x33,
+	0x74, 0x0e, 0x1f, 0x50, 0x94, 0xdd, 0xce, 0xc4, 0x96, 0xf1, 0x01, 0xad, 0x02, 0xa6, 0xeb, 0x88,
+	0xdb, 0x95, 0x4e, 0x3c, 0x5e, 0xb1, 0xf3, 0xb8, 0x3c, 0x41, 0xdb, 0x9e, 0xa7, 0xde, 0x87, 0xf5,
+	0x23, 0x12, 0xd0, 0xb2, 0x76, 0xec, 0xf6, 0xec, 0xa8, 0x89, 0x7f, 0x01, 0xd5, 0x38, 0x2c, 0x1c,
+	0xfa, 0x31, 0x14, 0xfa, 0x14, 0x90, 0xae, 0x32, 0xec, 0x06, 0xcb, 0xb8, 0xe8, 0x8d, 0x23, 0xcf,
+	0xc8, 0xf4, 0xca, 0x51, 0x85, 0x2c, 0x2f, 0x9f, 0xc2, 0x2c, 0x36, 0x50, 0xbf, 0x4b, 0xb1, 0x09,
+	0xe9, 0x21, 0xe2, 0x75, 0x77, 0xf6, 0x41, 0x66, 0xaa, 0x5b, 0x11, 0x95, 0x21, 0x9d, 0x50, 0x19,
+	0x12, 0x72, 0x72, 0x66, 0x71, 0x4e, 0x5e, 0x8a, 0xe5, 0xe4, 0x67, 0x50, 0x8d, 0xdb, 0x22, 0x56,
+	0x39, 0xff, 0x75, 0xa8, 0x0a, 0x59, 0xb9, 0x0c, 0xf3, 0x81, 0xda, 0x81, 0x5a, 0xfb, 0x75, 0x40,
+	0x1c, 0x6b, 0x66, 0x59, 0x89, 0xfc, 0x0b, 0x96, 0x44, 0x2f, 0x56, 0x33, 0xaa, 0x44, 0x30, 0x34,
+	0xa1, 0x86, 0xc9, 0xb5, 0xfb, 0x8a, 0xdc, 0x6d, 0x16, 0xaa, 0x6a, 0x86, 0x5f, 0xa8, 0x3a, 0x61,
+	0xf7, 0x29, 0x9e, 0x4f, 0x9f, 0xb9, 0x1e, 0x4d, 0xe9, 0x77, 0xc9, 0x0d, 0xb5, 0x28, 0x6b, 0x8b,
+	0x06, 0x98, 0x8f, 0xc4, 0x5d, 0x6a, 0x4a, 0x9d, 0x98, 0xea, 0x79, 0xd8, 0x1c, 0x9f, 0x90, 0xc1,
+	0x25, 0xbd, 0x96, 0x4f, 0x6c, 0x66, 0xd2, 0xa1, 0xcd, 0x6c, 0x10, 0x36, 0xdd, 0xe9, 0xa4, 0xa6,
+	0x3b, 0x13, 0x6b, 0xba, 0x37, 0xe0, 0xfe, 0x94, 0xde, 0xc8, 0x4d, 0x95, 0xa3, 0xd0, 0x98, 0x3b,
+	0x2c, 0x4a, 0xdc, 0x15, 0x42, 0xfe, 0xc9, 0x5d, 0x41, 0xaa, 0x4f, 0x93, 0x95, 0x3e, 0x62, 0xa9,
+	0x9c, 0x55, 0xc9, 0x85, 0x0b, 0x51, 0x3f, 0x83, 0xca, 0x84, 0x51, 0x28, 0x7d, 0x7f, 0xba, 0xec,
+	0x16, 0xa4, 0xd2, 0xaa, 0x9e, 0xc3, 0x03, 0x7a, 0xe4, 0xe2, 0xfd, 0xe2, 0xff, 0x73, 0x3c, 0xd4,
+	0x3f, 0xa6, 0x40, 0x49, 0x52, 0x29, 0xcc, 0x41, 0xb0, 0x64, 0xba, 0x56, 0xf4, 0x90, 0x48, 0xbf,
+	0x51, 0x17, 0x56, 0xdc, 0x60, 0xf8, 0x56, 0x37, 0x91, 0xfd, 0xb5, 0xf1, 0x6d, 0xa3, 0x7c, 0xd6,
+	0x3d, 0x9f, 0xdc, 0x44, 0x70, 0xd9, 0x0d, 0x86, 0x93, 0xe1, 0x27, 0xbb, 0x50, 0x94, 0xda, 0x34,
+	0xda, 0xb8, 0x5f, 0x9c, 0x1e, 0xb6, 0x9f, 0x75, 0x4e, 0xdb, 0xb4, 0xb3, 0x2f, 0x40, 0x56, 0xbb,
+	0x38, 0x6f, 0xe3, 0x4a, 0x0a, 0xe5, 0x20, 0xfd, 0x4c, 0xab, 0xa4, 0x3f, 0xf9, 0x21, 0x64, 0x79,
+	0x5f, 0x9a, 0x87, 0xa5, 0xd3, 0xb3, 0xd3, 0x76, 0xe5, 0x1e, 0x02, 0xc8, 0xe1, 0x76, 0xeb, 0x90,
+	0xb1, 0x01, 0xe4, 0x5e, 0xe0, 0x4e, 0xb7, 0x8d, 0x2b, 0x69, 0x2a, 0x7d, 0xf6, 0xe2, 0xb4, 0x8d,
+	0x2b, 0x99, 0xbd, 0x7f, 0x97, 0x21, 0xd3, 0x3a, 0xef, 0xa0, 0xa7, 0x90, 0x0f, 0x9f, 0x63, 0xd1,
+	0x7d, 0x91, 0x6c, 0xe3, 0x2f, 0xad, 0x4a, 0x6d, 0x1a, 0x16, 0xc1, 0x73, 0x0f, 0xb5, 0x00, 0x26,
+	0x6f, 0xb0, 0x68, 0x83, 0xf3, 0xcd, 0x3c, 0xd5, 0x2a, 0xf5, 0x59, 0x42, 0xa4, 0x42, 0x63, 0x7b,
+	0x1f, 0x7b, 0x5a, 0x40, 0x0f, 0x27, 0x77, 0xf8, 0x84, 0x57, 0x0c, 0x65, 0x73, 0x1e, 0x59, 0x56,
+	0xaa, 0xcd, 0x51, 0xaa, 0x2d, 0x56, 0xaa, 0xcd, 0x57, 0xfa, 0x63, 0x28, 0x44, 0xf7, 0x64, 0x54,
+	0x8b, 0x6c, 0x88, 0x5d, 0x84, 0x95, 0x8d, 0x19, 0x3c, 0x92, 0x3f, 0x82, 0x92, 0x7c, 0xf3, 0x45,
+	0x0f, 0x38, 0x6b, 0xc2, 0x75, 0x5a, 0x51, 0x92, 0x48, 0x91, 0x22, 0x02, 0xb5, 0xe4, 0xe7, 0x0d,
+	0xb4, 0xbd, 0xf8, 0xf1, 0x83, 0x2b, 0xff, 0xde, 0x5d, 0x5e, 0x48, 0xd4, 0x7b, 0xe8, 0x15, 0xd4,
+	0xe7, 0xbd, 0x27, 0xa0, 0x0f, 0x65, 0x03, 0xe7, 0x3e, 0x6d, 0x28, 0x1f, 0xbd, 0x89, 0x4d, 0x76,
+	0x8e, 0x7c, 0x9f, 0x0b, 0x9d, 0x93, 0x70, 0x47, 0x55, 0x94, 0x24, 0x92, 0xbc, 0x4b, 0x51, 0xd3,
+	0x19, 0xee, 0xd2, 0x74, 0x43, 0xab, 0x6c, 0xcc, 0xe0, 0x91, 0xfc, 0xe7, 0x90, 0xe3, 0x97, 0x34,
+	0xb4, 0xce, 0x99, 0x62, 0xf7, 0x45, 0xa5, 0x1a, 0x07, 0x23, 0xb1, 0xa7, 0x90, 0x0f, 0x3b, 0xce,
+	0xf0, 0x18, 0x4d, 0xb5, 0xb1, 0x4a, 0x6d, 0x1a, 0x96, 0x85, 0xb5, 0x29, 0x61, 0x2d, 0x59, 0x58,
+	0x9b, 0x15, 0xfe, 0x1c, 0x72, 0xbc, 0x91, 0x0b, 0x0d, 0x8e, 0xb5, 0x91, 0x4a, 0x35, 0x0e, 0xca,
+	0x62, 0x5a, 0x4c, 0x4c, 0x4b, 0x12, 0xd3, 0xa6, 0xc5, 0x8e, 0xa0, 0x24, 0xf7, 0x3a, 0xe1, 0x3e,
+	0x25, 0xb4, 0x45, 0x8a, 0x92, 0x44, 0x9a, 0x52, 0x14, 0x55, 0x5b, 0x49, 0xd1, 0x74, 0xc5, 0x56,
+	0x94, 0x24, 0x52, 0xa4, 0xe8, 0x1c, 0x56, 0xa7, 0x9a, 0x00, 0x24, 0x7e, 0x75, 0x49, 0x6e, 0x33,
+	0x94, 0x87, 0x73, 0xa8, 0xb2, 0xc6, 0xa9, 0x5e, 0x20, 0xd4, 0x98, 0xdc, 0x52, 0x28, 0x0f, 0xe7,
+	0x50, 0xa7, 0xf2, 0x51, 0xac, 0xe6, 0x4b, 0xf9, 0x28, 0xa9, 0xb5, 0x50, 0x36, 0xe7, 0x91, 0x23,
+	0xa5, 0x3f, 0x85, 0x72, 0xac, 0xa8, 0xa3, 0x58, 0xd6, 0x88, 0x77, 0x10, 0xca, 0x7b, 0x89, 0xb4,
+	0xa9, 0xdc, 0xc6, 0x67, 0x92, 0x72, 0x5b, 0xac, 0x31, 0x50, 0x36, 0x66, 0xf0, 0xa9, 0xf0, 0xe7,
+	0x17, 0xe6, 0x49, 0xf8, 0xcb, 0xa5, 0x5f, 0xa9, 0x4d, 0xc3, 0x91, 0xf0, 0x2f, 0x00, 0xcd, 0x56,
+	0x5e, 0xd4, 0x98, 0x84, 0x4f, 0x62, 0x99, 0x57, 0xb6, 0xe6, 0x33, 0x84, 0xaa, 0xf7, 0xbf, 0xfe,
+	0xfb, 0x78, 0x33, 0xf5, 0x8f, 0xf1, 0x66, 0xea, 0x5f, 0xe3, 0xcd, 0xd4, 0x2f, 0x9b, 0xfc, 0xd9,
+	0xaa, 0x69, 0xba, 0x83, 0x5d, 0xfa, 0x26, 0x74, 0x63, 0x11, 0x4f, 0xfe, 0xf2, 0x3d, 0x73, 0x57,
+	0xfa, 0x4d, 0xf5, 0x32, 0xc7, 0x0a, 0xf8, 0xe3, 0xff, 0x0d, 0x00, 0xf2, 0x91, 0x34, 0xfa, 0x69,
+	0x1d, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -5634,6 +5809,29 @@ func (m *TokenInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Tenant) > 0 {
+		i -= len(m.Tenant)
+		copy(dAtA[i:], m.Tenant)
+		i = encodeVarintAuth(dAtA, i, uint64(len(m.Tenant)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.ReadOnlyRepos) > 0 {
+		for iNdEx := len(m.ReadOnlyRepos) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ReadOnlyRepos[iNdEx])
+			copy(dAtA[i:], m.ReadOnlyRepos[iNdEx])
+			i = encodeVarintAuth(dAtA, i, uint64(len(m.ReadOnlyRepos[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Impersonator) > 0 {
+		i -= len(m.Impersonator)
+		copy(dAtA[i:], m.Impersonator)
+		i = encodeVarintAuth(dAtA, i, uint64(len(m.Impersonator)))
+		i--
+		dAtA[i] = 0x1a
+	}
 	if m.Source != 0 {
 		i = encodeVarintAuth(dAtA, i, uint64(m.Source))
 		i--
@@ -5762,6 +5960,55 @@ func (m *WhoAmIRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.IncludeScopes {
+		i--
+		if m.IncludeScopes {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RepoScope) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RepoScope) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RepoScope) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Scope != 0 {
+		i = encodeVarintAuth(dAtA, i, uint64(m.Scope))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Repo) > 0 {
+		i -= len(m.Repo)
+		copy(dAtA[i:], m.Repo)
+		i = encodeVarintAuth(dAtA, i, uint64(len(m.Repo)))
+		i--
+		dAtA[i] = 0xa
+	}
 	return len(dAtA) - i, nil
 }
 
@@ -5789,6 +6036,43 @@ func (m *WhoAmIResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Tenant) > 0 {
+		i -= len(m.Tenant)
+		copy(dAtA[i:], m.Tenant)
+		i = encodeVarintAuth(dAtA, i, uint64(len(m.Tenant)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.ReadOnlyRepos) > 0 {
+		for iNdEx := len(m.ReadOnlyRepos) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ReadOnlyRepos[iNdEx])
+			copy(dAtA[i:], m.ReadOnlyRepos[iNdEx])
+			i = encodeVarintAuth(dAtA, i, uint64(len(m.ReadOnlyRepos[iNdEx])))
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if len(m.Scopes) > 0 {
+		for iNdEx := len(m.Scopes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Scopes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintAuth(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if len(m.Impersonator) > 0 {
+		i -= len(m.Impersonator)
+		copy(dAtA[i:], m.Impersonator)
+		i = encodeVarintAuth(dAtA, i, uint64(len(m.Impersonator)))
+		i--
+		dAtA[i] = 0x2a
+	}
 	if m.ClusterRoles != nil {
 		{
 			size, err := m.ClusterRoles.MarshalToSizedBuffer(dAtA[:i])
@@ -6551,6 +6835,22 @@ func (m *GetAuthTokenRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Tenant) > 0 {
+		i -= len(m.Tenant)
+		copy(dAtA[i:], m.Tenant)
+		i = encodeVarintAuth(dAtA, i, uint64(len(m.Tenant)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.ReadOnlyRepos) > 0 {
+		for iNdEx := len(m.ReadOnlyRepos) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ReadOnlyRepos[iNdEx])
+			copy(dAtA[i:], m.ReadOnlyRepos[iNdEx])
+			i = encodeVarintAuth(dAtA, i, uint64(len(m.ReadOnlyRepos[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if m.TTL != 0 {
 		i = encodeVarintAuth(dAtA, i, uint64(m.TTL))
 		i--
@@ -7583,6 +7883,20 @@ func (m *TokenInfo) Size() (n int) {
 	if m.Source != 0 {
 		n += 1 + sovAuth(uint64(m.Source))
 	}
+	l = len(m.Impersonator)
+	if l > 0 {
+		n += 1 + l + sovAuth(uint64(l))
+	}
+	if len(m.ReadOnlyRepos) > 0 {
+		for _, s := range m.ReadOnlyRepos {
+			l = len(s)
+			n += 1 + l + sovAuth(uint64(l))
+		}
+	}
+	l = len(m.Tenant)
+	if l > 0 {
+		n += 1 + l + sovAuth(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -7639,6 +7953,28 @@ func (m *WhoAmIRequest) Size() (n int) {
 	}
 	var l int
 	_ = l
+	if m.IncludeScopes {
+		n += 2
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *RepoScope) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Repo)
+	if l > 0 {
+		n += 1 + l + sovAuth(uint64(l))
+	}
+	if m.Scope != 0 {
+		n += 1 + sovAuth(uint64(m.Scope))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -7665,6 +8001,26 @@ func (m *WhoAmIResponse) Size() (n int) {
 		l = m.ClusterRoles.Size()
 		n += 1 + l + sovAuth(uint64(l))
 	}
+	l = len(m.Impersonator)
+	if l > 0 {
+		n += 1 + l + sovAuth(uint64(l))
+	}
+	if len(m.Scopes) > 0 {
+		for _, e := range m.Scopes {
+			l = e.Size()
+			n += 1 + l + sovAuth(uint64(l))
+		}
+	}
+	if len(m.ReadOnlyRepos) > 0 {
+		for _, s := range m.ReadOnlyRepos {
+			l = len(s)
+			n += 1 + l + sovAuth(uint64(l))
+		}
+	}
+	l = len(m.Tenant)
+	if l > 0 {
+		n += 1 + l + sovAuth(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -8002,6 +8358,16 @@ func (m *GetAuthTokenRequest) Size() (n int) {
 	if m.TTL != 0 {
 		n += 1 + sovAuth(uint64(m.TTL))
 	}
+	if len(m.ReadOnlyRepos) > 0 {
+		for _, s := range m.ReadOnlyRepos {
+			l = len(s)
+			n += 1 + l + sovAuth(uint64(l))
+		}
+	}
+	l = len(m.Tenant)
+	if l > 0 {
+		n += 1 + l + sovAuth(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -10935,6 +11301,102 @@ func (m *TokenInfo) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Impersonator", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuth
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuth
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Impersonator = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadOnlyRepos", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuth
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuth
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ReadOnlyRepos = append(m.ReadOnlyRepos, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tenant", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuth
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuth
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tenant = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAuth(dAtA[iNdEx:])
@@ -11257,6 +11719,131 @@ func (m *WhoAmIRequest) Unmarshal(dAtA []byte) error {
 			return fmt.Errorf("proto: WhoAmIRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IncludeScopes", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IncludeScopes = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAuth(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthAuth
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthAuth
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RepoScope) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAuth
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RepoScope: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RepoScope: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Repo", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuth
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuth
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Repo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
+			}
+			m.Scope = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Scope |= Scope(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAuth(dAtA[iNdEx:])
@@ -11418,6 +12005,136 @@ func (m *WhoAmIResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Impersonator", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuth
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuth
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Impersonator = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scopes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAuth
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuth
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Scopes = append(m.Scopes, &RepoScope{})
+			if err := m.Scopes[len(m.Scopes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadOnlyRepos", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuth
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuth
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ReadOnlyRepos = append(m.ReadOnlyRepos, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tenant", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuth
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuth
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tenant = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAuth(dAtA[iNdEx:])
@@ -13436,6 +14153,70 @@ func (m *GetAuthTokenRequest) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadOnlyRepos", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuth
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuth
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ReadOnlyRepos = append(m.ReadOnlyRepos, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tenant", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuth
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuth
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tenant = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAuth(dAtA[iNdEx:])
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxBlockingRetryInterval is the longest that a blocking call waits between
+// attempts to reach pachd, so that it resumes soon after pachd is back
+const maxBlockingRetryInterval = 10 * time.Second
+
+// isTransientErr returns true if 'err' means that pachd couldn't be reached
+// or dropped the call (e.g. because it's restarting), in which case the call
+// can be made again.
+func isTransientErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if s, ok := status.FromError(err); ok {
+		return s.Code() == codes.Unavailable
+	}
+	return false
+}
+
+// retryBlocking calls 'f', which makes a blocking call to pachd, until it
+// returns nil or an error that isn't transient. 'f' is called with a function
+// that renews the call's lease, which it should call whenever it hears from
+// pachd; f is retried until its lease has gone unrenewed for the client's
+// blocking retry budget. Since 'f' may be called more than once, it must
+// resume the blocking call rather than start it over, e.g. by skipping the
+// results it already received.
+func (c APIClient) retryBlocking(f func(renew func()) error) error {
+	if c.blockingRetryBudget <= 0 {
+		return f(func() {})
+	}
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = c.blockingRetryBudget
+	b.MaxInterval = maxBlockingRetryInterval
+	b.Reset()
+	for {
+		err := f(b.Reset)
+		if !isTransientErr(err) {
+			return err
+		}
+		next := b.NextBackOff()
+		if next == backoff.Stop {
+			return errors.Wrapf(err, "pachd was unreachable for more than %v", c.blockingRetryBudget)
+		}
+		select {
+		case <-time.After(next):
+		case <-c.Ctx().Done():
+			return err
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/testpachd"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFlushJobResumes(t *testing.T) {
+	mock, err := testpachd.NewMockPachd(context.Background())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, mock.Close()) }()
+	// The first stream breaks after sending job 'a', as if pachd restarted
+	calls := 0
+	mock.PPS.FlushJob.Use(func(req *pps.FlushJobRequest, server pps.API_FlushJobServer) error {
+		calls++
+		if calls == 1 {
+			require.Equal(t, 0, len(req.SkipJobs))
+			require.NoError(t, server.Send(&pps.JobInfo{Job: client.NewJob("a")}))
+			return status.Error(codes.Unavailable, "transport is closing")
+		}
+		require.Equal(t, 1, len(req.SkipJobs))
+		require.Equal(t, "a", req.SkipJobs[0].ID)
+		return server.Send(&pps.JobInfo{Job: client.NewJob("b")})
+	})
+	c, err := client.NewFromAddress(mock.Addr.String())
+	require.NoError(t, err)
+	defer c.Close()
+
+	jobInfos, err := c.FlushJobAll(nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.Equal(t, 2, len(jobInfos))
+	require.Equal(t, "a", jobInfos[0].Job.ID)
+	require.Equal(t, "b", jobInfos[1].Job.ID)
+}
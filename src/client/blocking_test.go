@@ -0,0 +1,53 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryBlocking(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "transport is closing")
+	c := APIClient{blockingRetryBudget: time.Minute}
+
+	// Transient errors are retried
+	calls := 0
+	require.NoError(t, c.retryBlocking(func(renew func()) error {
+		calls++
+		if calls < 3 {
+			renew()
+			return unavailable
+		}
+		return nil
+	}))
+	require.Equal(t, 3, calls)
+
+	// Other errors aren't
+	calls = 0
+	err := c.retryBlocking(func(func()) error {
+		calls++
+		return errors.New("job not found")
+	})
+	require.YesError(t, err)
+	require.Equal(t, 1, calls)
+
+	// The call fails once its lease has gone unrenewed for the budget
+	c.blockingRetryBudget = 100 * time.Millisecond
+	err = c.retryBlocking(func(func()) error { return unavailable })
+	require.YesError(t, err)
+	require.Matches(t, "unreachable for more than 100ms", err.Error())
+
+	// A budget of 0 disables retries
+	c.blockingRetryBudget = 0
+	calls = 0
+	require.YesError(t, c.retryBlocking(func(func()) error {
+		calls++
+		return unavailable
+	}))
+	require.Equal(t, 1, calls)
+}
@@ -0,0 +1,64 @@
+// Package builtin is the SDK for builtin transforms: Go plugins, stored in
+// PFS, that a pipeline's workers load once and call in-process for each
+// datum, instead of running a command in the user container. This avoids the
+// per-datum cost of starting a process, so it suits pipelines with many small
+// datums.
+//
+// A builtin transform is a main package that exports a function named
+// Transform, of type Func:
+//
+//	package main
+//
+//	func Transform(ctx context.Context, datum builtin.Datum) error {
+//		r, err := datum.Open("images", "/")
+//		...
+//	}
+//
+// The plugin must be built with the Go toolchain and source tree in the
+// pachyderm/worker-builtin image whose version matches the cluster's, as
+// plugins can only be loaded by a binary built with the same versions of Go
+// and of every package that they share:
+//
+//	docker run -v $PWD:/plugin -w /plugin pachyderm/worker-builtin:<version> \
+//		go build -buildmode=plugin -o transform.so .
+//
+// The plugin is then put in a repo, and referenced by the pipeline's
+// 'transform.builtin' field. Transforms should only use Datum to read their
+// inputs and write their output, and must not exit or change the process's
+// global state. Nothing enforces this: a transform runs in the worker's
+// process, with all of the worker's privileges, and is only asked to stop
+// (by cancelling its context) when its datum times out.
+package builtin
+
+import (
+	"context"
+	"io"
+	"os"
+)
+
+// SymbolName is the name of the function that a builtin transform's plugin
+// must export.
+const SymbolName = "Transform"
+
+// Func is the type of the function that a builtin transform's plugin must
+// export, which is called once for each datum. If it returns an error, or
+// panics, the datum fails.
+type Func = func(ctx context.Context, datum Datum) error
+
+// Datum is the API that a builtin transform uses to access a datum. Paths are
+// relative to the root of an input, or of the output, and can't refer to
+// files outside of it.
+type Datum interface {
+	// Inputs returns the names of the datum's inputs.
+	Inputs() []string
+	// Open opens the file at 'path' in the input 'name'.
+	Open(name, path string) (io.ReadCloser, error)
+	// Walk walks the files under 'path' in the input 'name', in lexical
+	// order, calling 'f' with each file's path, relative to the input.
+	Walk(name, path string, f func(path string, info os.FileInfo) error) error
+	// Create creates (or truncates) the output file at 'path', creating its
+	// parent directories if necessary.
+	Create(path string) (io.WriteCloser, error)
+	// Logf writes a message to the datum's logs.
+	Logf(format string, args ...interface{})
+}
@@ -0,0 +1,237 @@
+package client
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+)
+
+// cacheEvent is a change to the items in a watchCache
+type cacheEvent struct {
+	name string
+	// nil if the item was deleted
+	item proto.Message
+	// If set, all of the items that existed when the watch started have been
+	// sent
+	synced bool
+}
+
+// watchCache is a local, eventually-consistent copy of a set of named items,
+// which is kept up to date by a watch RPC (WatchRepos or WatchPipelines). If
+// the watch fails, it's restarted.
+type watchCache struct {
+	mu    sync.Mutex
+	items map[string]proto.Message
+	// closed once the cache has been populated for the first time
+	synced     chan struct{}
+	syncedOnce sync.Once
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// newWatchCache returns a watchCache that's populated by 'watch', which must
+// call 'f' with the events of a single watch, until 'ctx' is done.
+func newWatchCache(ctx context.Context, watch func(ctx context.Context, f func(cacheEvent) error) error) *watchCache {
+	ctx, cancel := context.WithCancel(ctx)
+	wc := &watchCache{
+		items:  make(map[string]proto.Message),
+		synced: make(chan struct{}),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(wc.done)
+		backoff.RetryUntilCancel(ctx, func() error {
+			// Every watch starts by resending all of the items, so collect them
+			// separately and swap them in once they've all arrived. That way,
+			// items that were deleted while the previous watch was down are
+			// dropped from the cache.
+			pending := make(map[string]proto.Message)
+			return watch(ctx, func(e cacheEvent) error {
+				wc.mu.Lock()
+				defer wc.mu.Unlock()
+				items := wc.items
+				if pending != nil {
+					items = pending
+				}
+				switch {
+				case e.synced:
+					if pending != nil {
+						wc.items = pending
+						pending = nil
+						wc.syncedOnce.Do(func() { close(wc.synced) })
+					}
+				case e.item == nil:
+					delete(items, e.name)
+				default:
+					items[e.name] = e.item
+				}
+				return nil
+			})
+		}, backoff.NewInfiniteBackOff(), func(err error, d time.Duration) error {
+			log.Errorf("error watching cluster state for cache: %v; retrying in %v", err, d)
+			return nil
+		})
+	}()
+	return wc
+}
+
+// waitSynced blocks until the cache has been populated, 'ctx' is done, or
+// the cache is closed
+func (wc *watchCache) waitSynced(ctx context.Context) error {
+	select {
+	case <-wc.synced:
+		return nil
+	case <-wc.done:
+		return errors.New("cache was closed before it was populated")
+	case <-ctx.Done():
+		return errors.EnsureStack(ctx.Err())
+	}
+}
+
+// get returns a copy of the item called 'name', or nil if there's no such item
+func (wc *watchCache) get(name string) proto.Message {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	item, ok := wc.items[name]
+	if !ok {
+		return nil
+	}
+	return proto.Clone(item)
+}
+
+// list calls 'f' with a copy of each item, in order of name
+func (wc *watchCache) list(f func(proto.Message)) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	names := make([]string, 0, len(wc.items))
+	for name := range wc.items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f(proto.Clone(wc.items[name]))
+	}
+}
+
+func (wc *watchCache) close() {
+	wc.cancel()
+	<-wc.done
+}
+
+// RepoCache is a local, eventually-consistent view of the cluster's repos.
+// It's kept up to date by WatchRepos, so reading it doesn't make any calls to
+// pachd, which makes it a cheaper alternative to calling ListRepo repeatedly.
+type RepoCache struct {
+	wc *watchCache
+}
+
+// NewRepoCache returns a RepoCache that watches the cluster's repos until it's
+// closed, or until the client's context is done. The cache is empty until it
+// has been populated; use WaitSynced to wait for that.
+func (c APIClient) NewRepoCache() *RepoCache {
+	return &RepoCache{newWatchCache(c.Ctx(), func(ctx context.Context, f func(cacheEvent) error) error {
+		return c.WithCtx(ctx).WatchRepos(func(e *pfs.RepoEvent) error {
+			switch e.Type {
+			case pfs.RepoEvent_SYNCED:
+				return f(cacheEvent{synced: true})
+			case pfs.RepoEvent_DELETE:
+				return f(cacheEvent{name: e.RepoInfo.Repo.Name})
+			default:
+				return f(cacheEvent{name: e.RepoInfo.Repo.Name, item: e.RepoInfo})
+			}
+		})
+	})}
+}
+
+// WaitSynced blocks until the cache has been populated, or 'ctx' is done.
+func (rc *RepoCache) WaitSynced(ctx context.Context) error {
+	return rc.wc.waitSynced(ctx)
+}
+
+// ListRepo returns the cached repos, in order of name.
+func (rc *RepoCache) ListRepo() []*pfs.RepoInfo {
+	var result []*pfs.RepoInfo
+	rc.wc.list(func(item proto.Message) {
+		result = append(result, item.(*pfs.RepoInfo))
+	})
+	return result
+}
+
+// InspectRepo returns the cached repo called 'name', or nil if there's no such
+// repo in the cache.
+func (rc *RepoCache) InspectRepo(name string) *pfs.RepoInfo {
+	if item := rc.wc.get(name); item != nil {
+		return item.(*pfs.RepoInfo)
+	}
+	return nil
+}
+
+// Close stops updating the cache.
+func (rc *RepoCache) Close() {
+	rc.wc.close()
+}
+
+// PipelineCache is a local, eventually-consistent view of the cluster's
+// pipelines, including their states. It's kept up to date by WatchPipelines,
+// so reading it doesn't make any calls to pachd, which makes it a cheaper
+// alternative to calling ListPipeline repeatedly.
+type PipelineCache struct {
+	wc *watchCache
+}
+
+// NewPipelineCache returns a PipelineCache that watches the cluster's
+// pipelines until it's closed, or until the client's context is done. The
+// cache is empty until it has been populated; use WaitSynced to wait for that.
+func (c APIClient) NewPipelineCache() *PipelineCache {
+	return &PipelineCache{newWatchCache(c.Ctx(), func(ctx context.Context, f func(cacheEvent) error) error {
+		return c.WithCtx(ctx).WatchPipelines(func(e *pps.PipelineEvent) error {
+			switch e.Type {
+			case pps.PipelineEvent_SYNCED:
+				return f(cacheEvent{synced: true})
+			case pps.PipelineEvent_DELETE:
+				return f(cacheEvent{name: e.PipelineInfo.Pipeline.Name})
+			default:
+				return f(cacheEvent{name: e.PipelineInfo.Pipeline.Name, item: e.PipelineInfo})
+			}
+		})
+	})}
+}
+
+// WaitSynced blocks until the cache has been populated, or 'ctx' is done.
+func (pc *PipelineCache) WaitSynced(ctx context.Context) error {
+	return pc.wc.waitSynced(ctx)
+}
+
+// ListPipeline returns the cached pipelines, in order of name.
+func (pc *PipelineCache) ListPipeline() []*pps.PipelineInfo {
+	var result []*pps.PipelineInfo
+	pc.wc.list(func(item proto.Message) {
+		result = append(result, item.(*pps.PipelineInfo))
+	})
+	return result
+}
+
+// InspectPipeline returns the cached pipeline called 'name', or nil if there's
+// no such pipeline in the cache.
+func (pc *PipelineCache) InspectPipeline(name string) *pps.PipelineInfo {
+	if item := pc.wc.get(name); item != nil {
+		return item.(*pps.PipelineInfo)
+	}
+	return nil
+}
+
+// Close stops updating the cache.
+func (pc *PipelineCache) Close() {
+	pc.wc.close()
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestWatchCacheResync(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	repo := func(name string) cacheEvent {
+		return cacheEvent{name: name, item: &pfs.RepoInfo{Repo: NewRepo(name)}}
+	}
+	// The first watch fails after sending 'a' and 'b' and deleting 'a'. The
+	// second watch, which replaces it, doesn't see 'b', which was deleted
+	// while no watch was running.
+	watches := [][]cacheEvent{
+		{repo("a"), repo("b"), {synced: true}, {name: "a"}},
+		{repo("c"), {synced: true}},
+	}
+	done := make(chan struct{})
+	wc := newWatchCache(ctx, func(ctx context.Context, f func(cacheEvent) error) error {
+		if len(watches) == 0 {
+			close(done)
+			<-ctx.Done()
+			return nil
+		}
+		events := watches[0]
+		watches = watches[1:]
+		for _, e := range events {
+			require.NoError(t, f(e))
+		}
+		return errors.New("watch failed")
+	})
+	defer wc.close()
+	require.NoError(t, wc.waitSynced(ctx))
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the cache to resync")
+	}
+	var names []string
+	wc.list(func(item proto.Message) {
+		names = append(names, item.(*pfs.RepoInfo).Repo.Name)
+	})
+	require.Equal(t, []string{"c"}, names)
+	require.Nil(t, wc.get("b"))
+}
@@ -92,9 +92,17 @@ type APIClient struct {
 	// The trusted CAs, for authenticating a pachd server over TLS
 	caCerts *x509.CertPool
 
+	// tlsServerName, if set, is the name that pachd's TLS certificate is
+	// verified against, instead of the hostname in 'addr'
+	tlsServerName string
+
 	// gzipCompress configures whether to enable compression by default for all calls
 	gzipCompress bool
 
+	// blockingRetryBudget is how long blocking calls (e.g. FlushJob) keep
+	// trying to reach pachd before they fail (see WithBlockingRetryBudget)
+	blockingRetryBudget time.Duration
+
 	// clientConn is a cached grpc connection to 'addr'
 	clientConn *grpc.ClientConn
 
@@ -118,6 +126,15 @@ type APIClient struct {
 	// they want to access privileged data
 	authenticationToken string
 
+	// impersonatedSubject, if set, is the subject that the client's requests
+	// are made on behalf of. Only cluster admins may impersonate subjects.
+	impersonatedSubject string
+
+	// dropImpersonation, if set, stops the client's requests from carrying
+	// over an impersonated subject from the context that they're made in
+	// (see WithoutImpersonation)
+	dropImpersonation bool
+
 	// The context used in requests, can be set with WithCtx
 	ctx context.Context
 
@@ -140,11 +157,17 @@ const DefaultMaxConcurrentStreams = 100
 // for a connection to be established unless overridden by WithDialTimeout()
 const DefaultDialTimeout = 30 * time.Second
 
+// DefaultBlockingRetryBudget is how long blocking calls keep trying to reach
+// pachd, unless overridden by WithBlockingRetryBudget()
+const DefaultBlockingRetryBudget = 5 * time.Minute
+
 type clientSettings struct {
 	maxConcurrentStreams int
 	gzipCompress         bool
 	dialTimeout          time.Duration
+	blockingRetryBudget  time.Duration
 	caCerts              *x509.CertPool
+	tlsServerName        string
 	storageV2            bool
 	unaryInterceptors    []grpc.UnaryClientInterceptor
 	streamInterceptors   []grpc.StreamClientInterceptor
@@ -160,6 +183,7 @@ func NewFromAddress(addr string, options ...Option) (*APIClient, error) {
 	settings := clientSettings{
 		maxConcurrentStreams: DefaultMaxConcurrentStreams,
 		dialTimeout:          DefaultDialTimeout,
+		blockingRetryBudget:  DefaultBlockingRetryBudget,
 	}
 	storageV2Env, ok := os.LookupEnv("STORAGE_V2")
 	if ok {
@@ -181,11 +205,13 @@ func NewFromAddress(addr string, options ...Option) (*APIClient, error) {
 		settings.streamInterceptors = append(settings.streamInterceptors, tracing.StreamClientInterceptor())
 	}
 	c := &APIClient{
-		addr:         addr,
-		caCerts:      settings.caCerts,
-		limiter:      limit.New(settings.maxConcurrentStreams),
-		gzipCompress: settings.gzipCompress,
-		storageV2:    settings.storageV2,
+		addr:                addr,
+		caCerts:             settings.caCerts,
+		tlsServerName:       settings.tlsServerName,
+		limiter:             limit.New(settings.maxConcurrentStreams),
+		gzipCompress:        settings.gzipCompress,
+		blockingRetryBudget: settings.blockingRetryBudget,
+		storageV2:           settings.storageV2,
 	}
 	if err := c.connect(settings.dialTimeout, settings.unaryInterceptors, settings.streamInterceptors); err != nil {
 		return nil, err
@@ -252,6 +278,16 @@ func WithSystemCAs(settings *clientSettings) error {
 	return nil
 }
 
+// WithTLSServerName instructs the New* functions to verify pachd's TLS
+// certificate against 'name', rather than the hostname that the client
+// connects to (e.g. when pachd is reached through a proxy or tunnel).
+func WithTLSServerName(name string) Option {
+	return func(settings *clientSettings) error {
+		settings.tlsServerName = name
+		return nil
+	}
+}
+
 // WithDialTimeout instructs the New* functions to use 't' as the deadline to
 // connect to pachd
 func WithDialTimeout(t time.Duration) Option {
@@ -261,6 +297,20 @@ func WithDialTimeout(t time.Duration) Option {
 	}
 }
 
+// WithBlockingRetryBudget instructs the New* functions to create a client
+// whose blocking calls (FlushJob, FlushJobAll, and InspectJob and
+// InspectJobOutputCommit with blockState) resume, with the same semantics,
+// when their connection to pachd is lost, e.g. because pachd restarted. Each
+// call holds a lease that's renewed whenever it hears from pachd, and it fails
+// once pachd has been unreachable for 'budget'. A budget of 0 disables
+// resumption.
+func WithBlockingRetryBudget(budget time.Duration) Option {
+	return func(settings *clientSettings) error {
+		settings.blockingRetryBudget = budget
+		return nil
+	}
+}
+
 // WithGZIPCompression enabled GZIP compression for data on the wire
 func WithGZIPCompression() Option {
 	return func(settings *clientSettings) error {
@@ -382,39 +432,52 @@ func getUserMachineAddrAndOpts(context *config.Context) (*grpcutil.PachdAddress,
 	}
 
 	// 2) Get target address from global config if possible
-	if context != nil && (context.ServerCAs != "" || context.PachdAddress != "") {
-		pachdAddress, err := grpcutil.ParsePachdAddress(context.PachdAddress)
-		if err != nil {
-			return nil, nil, errors.Wrap(err, "could not parse the active context's pachd address")
-		}
-
-		// Proactively return an error in this case, instead of falling back to the default address below
-		if context.ServerCAs != "" && !pachdAddress.Secured {
-			return nil, nil, errors.New("must set pachd_address to grpcs://... if server_cas is set")
-		}
-
-		if pachdAddress.Secured {
-			options = append(options, WithSystemCAs)
-		}
-		// Also get cert info from config (if set)
-		if context.ServerCAs != "" {
-			pemBytes, err := base64.StdEncoding.DecodeString(context.ServerCAs)
-			if err != nil {
-				return nil, nil, errors.Wrap(err, "could not decode server CA certs in config")
-			}
-			return pachdAddress, []Option{WithAdditionalRootCAs(pemBytes)}, nil
-		}
-		return pachdAddress, options, nil
+	pachdAddress, options, err := getContextAddrAndOpts(context)
+	if err != nil || pachdAddress != nil {
+		return pachdAddress, options, err
 	}
 
 	// 3) Use default address (broadcast) if nothing else works
-	options, err := getCertOptionsFromEnv() // error if PACH_CA_CERTS is set
+	options, err = getCertOptionsFromEnv() // error if PACH_CA_CERTS is set
 	if err != nil {
 		return nil, nil, err
 	}
 	return nil, options, nil
 }
 
+// getContextAddrAndOpts returns the address and TLS options that 'context'
+// configures, or a nil address if it doesn't configure one.
+func getContextAddrAndOpts(context *config.Context) (*grpcutil.PachdAddress, []Option, error) {
+	if context == nil || (context.ServerCAs == "" && context.PachdAddress == "") {
+		return nil, nil, nil
+	}
+	pachdAddress, err := grpcutil.ParsePachdAddress(context.PachdAddress)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not parse the active context's pachd address")
+	}
+
+	// Proactively return an error in this case, instead of falling back to the default address below
+	if context.ServerCAs != "" && !pachdAddress.Secured {
+		return nil, nil, errors.New("must set pachd_address to grpcs://... if server_cas is set")
+	}
+
+	var options []Option
+	if context.ServerCAs != "" {
+		// Get cert info from config (if set)
+		pemBytes, err := base64.StdEncoding.DecodeString(context.ServerCAs)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not decode server CA certs in config")
+		}
+		options = append(options, WithAdditionalRootCAs(pemBytes))
+	} else if pachdAddress.Secured {
+		options = append(options, WithSystemCAs)
+	}
+	if context.TLSServerName != "" {
+		options = append(options, WithTLSServerName(context.TLSServerName))
+	}
+	return pachdAddress, options, nil
+}
+
 func portForwarder(context *config.Context) (*PortForwarder, uint16, error) {
 	fw, err := NewPortForwarder(context, "")
 	if err != nil {
@@ -481,7 +544,33 @@ func NewOnUserMachine(prefix string, options ...Option) (*APIClient, error) {
 	if err != nil {
 		return nil, err
 	}
+	return newOnUserMachine(cfg, context, pachdAddress, prefix, append(options, cfgOptions...)...)
+}
 
+// NewOnUserMachineWithContext constructs a new APIClient for the cluster that
+// the context named 'contextName' in $HOME/.pachyderm/config points at,
+// regardless of which context is active (or selected by env vars). This lets
+// tools target several clusters at once.
+func NewOnUserMachineWithContext(contextName string, prefix string, options ...Option) (*APIClient, error) {
+	cfg, err := config.Read(false)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read config")
+	}
+	context, ok := cfg.V2.Contexts[contextName]
+	if !ok {
+		return nil, errors.Errorf("context does not exist: %s", contextName)
+	}
+	pachdAddress, cfgOptions, err := getContextAddrAndOpts(context)
+	if err != nil {
+		return nil, err
+	}
+	return newOnUserMachine(cfg, context, pachdAddress, prefix, append(options, cfgOptions...)...)
+}
+
+// newOnUserMachine connects to the cluster that 'context' points at, at
+// 'pachdAddress' (or through a port forwarder, if it's nil).
+func newOnUserMachine(cfg *config.Config, context *config.Context, pachdAddress *grpcutil.PachdAddress, prefix string, options ...Option) (*APIClient, error) {
+	var err error
 	var fw *PortForwarder
 	if pachdAddress == nil && context.PortForwarders != nil {
 		pachdLocalPort, ok := context.PortForwarders["pachd"]
@@ -507,7 +596,7 @@ func NewOnUserMachine(prefix string, options ...Option) (*APIClient, error) {
 		}
 	}
 
-	client, err := NewFromAddress(pachdAddress.Hostname(), append(options, cfgOptions...)...)
+	client, err := NewFromAddress(pachdAddress.Hostname(), options...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not connect to pachd at %q", pachdAddress.Qualified())
 	}
@@ -520,6 +609,9 @@ func NewOnUserMachine(prefix string, options ...Option) (*APIClient, error) {
 	if context.SessionToken != "" {
 		client.authenticationToken = context.SessionToken
 	}
+	// PACH_IMPERSONATE lets cluster admins run pachctl commands as another
+	// subject, e.g. to reproduce a user's permission errors
+	client.impersonatedSubject = os.Getenv("PACH_IMPERSONATE")
 
 	// Verify cluster deployment ID
 	clusterInfo, err := client.InspectCluster()
@@ -581,37 +673,38 @@ func (c *APIClient) Close() error {
 	return nil
 }
 
-// DeleteAll deletes everything in the cluster.
+// DeleteAll deletes everything in the cluster. If the client's token is
+// confined to a tenant, only the tenant's pipelines and repos are deleted, and
+// auth and transactions (which aren't owned by tenants) are left alone.
+// Clusters whose DeleteAllPolicy is CONFIRM refuse it; use DeleteAllConfirmed
+// instead.
 // Use with caution, there is no undo.
-// TODO: rewrite this to use transactions
 func (c APIClient) DeleteAll() error {
-	if _, err := c.AuthAPIClient.Deactivate(
-		c.Ctx(),
-		&auth.DeactivateRequest{},
-	); err != nil && !auth.IsErrNotActivated(err) {
-		return grpcutil.ScrubGRPC(err)
-	}
-	if _, err := c.PpsAPIClient.DeleteAll(
-		c.Ctx(),
-		&types.Empty{},
-	); err != nil {
-		return grpcutil.ScrubGRPC(err)
-	}
-	if _, err := c.PfsAPIClient.DeleteAll(
-		c.Ctx(),
-		&types.Empty{},
-	); err != nil {
-		return grpcutil.ScrubGRPC(err)
-	}
-	if _, err := c.TransactionAPIClient.DeleteAll(
+	return c.DeleteAllConfirmed("")
+}
+
+// DeleteAllConfirmed is like DeleteAll, but confirms the delete with 'token',
+// which comes from PrepareDeleteAll.
+func (c APIClient) DeleteAllConfirmed(token string) error {
+	if _, err := c.AdminAPIClient.DeleteAll(
 		c.Ctx(),
-		&transaction.DeleteAllRequest{},
+		&admin.DeleteAllRequest{ConfirmationToken: token},
 	); err != nil {
 		return grpcutil.ScrubGRPC(err)
 	}
 	return nil
 }
 
+// PrepareDeleteAll returns a token that confirms a DeleteAllConfirmed call
+// within a few minutes, along with the cluster's DeleteAllPolicy.
+func (c APIClient) PrepareDeleteAll() (*admin.DeleteAllConfirmation, error) {
+	confirmation, err := c.AdminAPIClient.PrepareDeleteAll(c.Ctx(), &types.Empty{})
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return confirmation, nil
+}
+
 // SetMaxConcurrentStreams Sets the maximum number of concurrent streams the
 // client can have. It is not safe to call this operations while operations are
 // outstanding.
@@ -644,7 +737,7 @@ func (c *APIClient) connect(timeout time.Duration, unaryInterceptors []grpc.Unar
 	if c.caCerts == nil {
 		dialOptions = append(dialOptions, grpc.WithInsecure())
 	} else {
-		tlsCreds := credentials.NewClientTLSFromCert(c.caCerts, "")
+		tlsCreds := credentials.NewClientTLSFromCert(c.caCerts, c.tlsServerName)
 		dialOptions = append(dialOptions, grpc.WithTransportCredentials(tlsCreds))
 	}
 	if c.gzipCompress {
@@ -698,6 +791,9 @@ func (c *APIClient) AddMetadata(ctx context.Context) context.Context {
 	if c.authenticationToken != "" {
 		clientData[auth.ContextTokenKey] = c.authenticationToken
 	}
+	if c.impersonatedSubject != "" {
+		clientData[auth.ContextImpersonateKey] = c.impersonatedSubject
+	}
 	// metadata API downcases all the key names
 	if c.metricsUserID != "" {
 		clientData["userid"] = c.metricsUserID
@@ -717,6 +813,9 @@ func (c *APIClient) AddMetadata(ctx context.Context) context.Context {
 			finalMD[k] = v
 		}
 	}
+	if c.dropImpersonation {
+		delete(finalMD, auth.ContextImpersonateKey)
+	}
 	return metadata.NewOutgoingContext(ctx, finalMD)
 }
 
@@ -744,3 +843,23 @@ func (c *APIClient) WithCtx(ctx context.Context) *APIClient {
 func (c *APIClient) SetAuthToken(token string) {
 	c.authenticationToken = token
 }
+
+// Impersonate makes all API calls for this client on behalf of 'subject' (e.g.
+// "github:alice"), so that they're authorized as though 'subject' had made
+// them. The client's own token must belong to a cluster admin, and every
+// impersonated call is logged by pachd. An empty subject stops impersonating.
+func (c *APIClient) Impersonate(subject string) {
+	c.impersonatedSubject = subject
+}
+
+// WithoutImpersonation returns a copy of the client whose requests aren't
+// made on behalf of another subject, even if they're made in the context of
+// an incoming request that is. Servers use it for requests that they make
+// with their own credentials, rather than those of the incoming request, as
+// impersonation only applies to the latter.
+func (c *APIClient) WithoutImpersonation() *APIClient {
+	result := *c // copy c
+	result.impersonatedSubject = ""
+	result.dropImpersonation = true
+	return &result
+}
@@ -5,9 +5,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pachyderm/pachyderm/src/client/auth"
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 type countingInterceptor struct {
@@ -74,3 +76,30 @@ func TestInterceptors(t *testing.T) {
 		t.Errorf("stream call count:\n  got: %v\n want: %v", got, want)
 	}
 }
+
+func TestWithoutImpersonation(t *testing.T) {
+	// An incoming request from an admin who is impersonating alice
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		auth.ContextTokenKey, "admin-token",
+		auth.ContextImpersonateKey, "github:alice",
+	))
+	impersonated := func(c *APIClient) []string {
+		md, _ := metadata.FromOutgoingContext(c.Ctx())
+		return md[auth.ContextImpersonateKey]
+	}
+
+	// Requests made with the incoming request's credentials impersonate alice
+	c := (&APIClient{}).WithCtx(ctx)
+	if got := impersonated(c); len(got) != 1 || got[0] != "github:alice" {
+		t.Errorf("impersonated subject:\n  got: %v\n want: github:alice", got)
+	}
+
+	// Requests made with other credentials don't, even after WithCtx
+	c = c.WithoutImpersonation()
+	c.SetAuthToken("pps-token")
+	for _, c := range []*APIClient{c, c.WithCtx(c.Ctx())} {
+		if got := impersonated(c); len(got) != 0 {
+			t.Errorf("impersonated subject:\n  got: %v\n want: none", got)
+		}
+	}
+}
@@ -45,3 +45,16 @@ func (c APIClient) Dump(filter *debug.Filter, w io.Writer) (retErr error) {
 	}
 	return grpcutil.WriteFromStreamingBytesClient(dumpC, w)
 }
+
+// CollectDebugBundle collects a bundle of debugging information about the
+// cluster, with secrets redacted, for support.
+func (c APIClient) CollectDebugBundle(request *debug.CollectDebugBundleRequest, w io.Writer) (retErr error) {
+	defer func() {
+		retErr = grpcutil.ScrubGRPC(retErr)
+	}()
+	bundleC, err := c.DebugClient.CollectDebugBundle(c.Ctx(), request)
+	if err != nil {
+		return err
+	}
+	return grpcutil.WriteFromStreamingBytesClient(bundleC, w)
+}
@@ -388,6 +388,69 @@ func (m *DumpRequest) GetFilter() *Filter {
 	return nil
 }
 
+// CollectDebugBundleRequest asks for a bundle of the information that support
+// needs to diagnose a cluster: pachd's logs and goroutines, etcd key
+// statistics, recently failed jobs, pipeline specs and the cluster's version.
+// Secrets are redacted from the bundle.
+type CollectDebugBundleRequest struct {
+	// since limits the bundle's logs and failed jobs to the ones from this long
+	// ago. Defaults to 24 hours.
+	Since *types.Duration `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`
+	// max_failed_jobs limits the number of failed jobs in the bundle (the most
+	// recent ones are kept). Defaults to 100.
+	MaxFailedJobs        int64    `protobuf:"varint,2,opt,name=max_failed_jobs,json=maxFailedJobs,proto3" json:"max_failed_jobs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CollectDebugBundleRequest) Reset()         { *m = CollectDebugBundleRequest{} }
+func (m *CollectDebugBundleRequest) String() string { return proto.CompactTextString(m) }
+func (*CollectDebugBundleRequest) ProtoMessage()    {}
+func (*CollectDebugBundleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6d15a320d0127c22, []int{6}
+}
+func (m *CollectDebugBundleRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CollectDebugBundleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CollectDebugBundleRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CollectDebugBundleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CollectDebugBundleRequest.Merge(m, src)
+}
+func (m *CollectDebugBundleRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *CollectDebugBundleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CollectDebugBundleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CollectDebugBundleRequest proto.InternalMessageInfo
+
+func (m *CollectDebugBundleRequest) GetSince() *types.Duration {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+func (m *CollectDebugBundleRequest) GetMaxFailedJobs() int64 {
+	if m != nil {
+		return m.MaxFailedJobs
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*ProfileRequest)(nil), "debug.ProfileRequest")
 	proto.RegisterType((*Profile)(nil), "debug.Profile")
@@ -395,39 +458,45 @@ func init() {
 	proto.RegisterType((*Worker)(nil), "debug.Worker")
 	proto.RegisterType((*BinaryRequest)(nil), "debug.BinaryRequest")
 	proto.RegisterType((*DumpRequest)(nil), "debug.DumpRequest")
+	proto.RegisterType((*CollectDebugBundleRequest)(nil), "debug.CollectDebugBundleRequest")
 }
 
 func init() { proto.RegisterFile("client/debug/debug.proto", fileDescriptor_6d15a320d0127c22) }
 
 var fileDescriptor_6d15a320d0127c22 = []byte{
-	// 432 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0x5d, 0x6b, 0xd4, 0x40,
-	0x14, 0xdd, 0xb1, 0xdd, 0x34, 0xde, 0xd2, 0x22, 0x97, 0x2a, 0x6b, 0x85, 0x20, 0x01, 0xb1, 0x20,
-	0x24, 0x52, 0x3f, 0x1e, 0x14, 0x11, 0x97, 0x45, 0xf6, 0xb1, 0x0c, 0xa2, 0xe0, 0x5b, 0x36, 0xb9,
-	0x9b, 0x0e, 0x66, 0x33, 0xe3, 0x64, 0x42, 0xd9, 0x37, 0x7f, 0x5e, 0x1f, 0xfd, 0x09, 0xb2, 0xbf,
-	0x44, 0x32, 0x33, 0xd9, 0xa6, 0x16, 0x2c, 0x7d, 0xd8, 0x65, 0xe6, 0x9c, 0x73, 0xcf, 0xdc, 0x73,
-	0x2f, 0x81, 0x49, 0x5e, 0x09, 0xaa, 0x4d, 0x5a, 0xd0, 0xa2, 0x2d, 0xdd, 0x7f, 0xa2, 0xb4, 0x34,
-	0x12, 0xc7, 0xf6, 0x72, 0x1c, 0x95, 0x52, 0x96, 0x15, 0xa5, 0x16, 0x5c, 0xb4, 0xcb, 0xf4, 0x42,
-	0x67, 0x4a, 0x91, 0x6e, 0x9c, 0xec, 0x26, 0x5f, 0xb4, 0x3a, 0x33, 0x42, 0xd6, 0x9e, 0x3f, 0xf2,
-	0x0f, 0x28, 0xd5, 0x74, 0x3f, 0x87, 0xc6, 0x19, 0x1c, 0x9e, 0x69, 0xb9, 0x14, 0x15, 0x71, 0xfa,
-	0xd9, 0x52, 0x63, 0xf0, 0x04, 0xf6, 0x94, 0x43, 0x26, 0xec, 0x29, 0x3b, 0xd9, 0x3f, 0x3d, 0x4c,
-	0x5c, 0x37, 0xbd, 0xae, 0xa7, 0xf1, 0x19, 0x04, 0x4b, 0x51, 0x19, 0xd2, 0x93, 0x7b, 0x56, 0x78,
-	0xe0, 0x85, 0x9f, 0x2d, 0xc8, 0x3d, 0x19, 0x7f, 0x81, 0x3d, 0x5f, 0x8a, 0x08, 0xbb, 0x75, 0xb6,
-	0x72, 0xc6, 0xf7, 0xb9, 0x3d, 0xe3, 0x1b, 0x08, 0xfb, 0x4e, 0xbd, 0xcf, 0xe3, 0xc4, 0x45, 0x49,
-	0xfa, 0x28, 0xc9, 0xcc, 0x0b, 0xf8, 0x56, 0x1a, 0xff, 0x62, 0x10, 0xb8, 0x87, 0xf0, 0x11, 0x8c,
-	0x55, 0x96, 0x9f, 0x17, 0xd6, 0x36, 0x9c, 0x8f, 0xb8, 0xbb, 0xe2, 0x0b, 0x08, 0x95, 0x50, 0x54,
-	0x89, 0x9a, 0xb6, 0x1d, 0x76, 0xc9, 0xcf, 0x3c, 0x38, 0x1f, 0xf1, 0xad, 0x00, 0x9f, 0x43, 0x70,
-	0x21, 0xf5, 0x0f, 0xd2, 0x93, 0x9d, 0x6b, 0x61, 0xbe, 0x59, 0x70, 0x3e, 0xe2, 0x9e, 0x9e, 0x86,
-	0x7d, 0xea, 0xf8, 0x1d, 0x04, 0x8e, 0xc5, 0x07, 0xb0, 0xa3, 0x64, 0xe1, 0x63, 0x75, 0x47, 0x8c,
-	0x00, 0x34, 0x15, 0x42, 0x53, 0x6e, 0xa8, 0xb0, 0xaf, 0x87, 0x7c, 0x80, 0xc4, 0x6f, 0xe1, 0x60,
-	0x2a, 0xea, 0x4c, 0xaf, 0xfb, 0xb1, 0x5f, 0x0d, 0x93, 0xfd, 0x6f, 0x98, 0xaf, 0x61, 0x7f, 0xd6,
-	0xae, 0xd4, 0xdd, 0xaa, 0x4e, 0x2f, 0x19, 0x8c, 0x67, 0x1d, 0x81, 0x9f, 0xae, 0x96, 0xf1, 0xf0,
-	0x9f, 0xbd, 0x3a, 0xcb, 0xe3, 0x27, 0x37, 0xa6, 0x3f, 0x5d, 0x1b, 0x6a, 0xbe, 0x66, 0x55, 0x4b,
-	0xf1, 0xe8, 0x25, 0xc3, 0x8f, 0x10, 0xb8, 0xd6, 0xf1, 0xc8, 0x3b, 0x5c, 0x4b, 0x72, 0xbb, 0xc1,
-	0x7b, 0xd8, 0xed, 0x32, 0x20, 0xfa, 0xf2, 0x41, 0xa0, 0x5b, 0x8b, 0xa7, 0x1f, 0x2e, 0x37, 0x11,
-	0xfb, 0xbd, 0x89, 0xd8, 0x9f, 0x4d, 0xc4, 0xbe, 0xa7, 0xa5, 0x30, 0xe7, 0xed, 0x22, 0xc9, 0xe5,
-	0x2a, 0xed, 0x96, 0xbe, 0x2e, 0x48, 0x0f, 0x4f, 0x8d, 0xce, 0xd3, 0xe1, 0x87, 0xb5, 0x08, 0xac,
-	0xef, 0xab, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xac, 0x50, 0xf4, 0x22, 0x6f, 0x03, 0x00, 0x00,
+	// 504 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x5f, 0x6b, 0xd4, 0x4e,
+	0x14, 0xdd, 0x74, 0xbb, 0xe9, 0xfe, 0x6e, 0xd9, 0xfe, 0x64, 0xa8, 0xb2, 0xad, 0x10, 0x4a, 0x40,
+	0x2d, 0x08, 0x89, 0xd4, 0x3f, 0x0f, 0x8a, 0x88, 0x71, 0x29, 0x8b, 0x4f, 0x25, 0x88, 0x05, 0x5f,
+	0xca, 0x24, 0xb9, 0xbb, 0x1d, 0x4d, 0x32, 0xe3, 0x64, 0x42, 0xbb, 0x6f, 0x7e, 0x15, 0xbf, 0x8d,
+	0x8f, 0x7e, 0x04, 0xd9, 0x4f, 0x22, 0x99, 0x99, 0xec, 0x6e, 0x5d, 0xec, 0xe2, 0xc3, 0x2e, 0x93,
+	0x73, 0xcf, 0x3d, 0xf7, 0x9e, 0x93, 0x21, 0x30, 0x4c, 0x73, 0x86, 0xa5, 0x0a, 0x33, 0x4c, 0xea,
+	0xa9, 0xf9, 0x0f, 0x84, 0xe4, 0x8a, 0x93, 0x9e, 0x7e, 0x38, 0xf4, 0xa6, 0x9c, 0x4f, 0x73, 0x0c,
+	0x35, 0x98, 0xd4, 0x93, 0xf0, 0x4a, 0x52, 0x21, 0x50, 0x56, 0x86, 0xb6, 0x5e, 0xcf, 0x6a, 0x49,
+	0x15, 0xe3, 0xa5, 0xad, 0xef, 0xdb, 0x01, 0x42, 0x54, 0xcd, 0xcf, 0xa0, 0x3e, 0x85, 0xbd, 0x33,
+	0xc9, 0x27, 0x2c, 0xc7, 0x18, 0xbf, 0xd6, 0x58, 0x29, 0x72, 0x0c, 0x3b, 0xc2, 0x20, 0x43, 0xe7,
+	0xc8, 0x39, 0xde, 0x3d, 0xd9, 0x0b, 0xcc, 0x36, 0x2d, 0xaf, 0x2d, 0x93, 0x07, 0xe0, 0x4e, 0x58,
+	0xae, 0x50, 0x0e, 0xb7, 0x34, 0x71, 0x60, 0x89, 0xa7, 0x1a, 0x8c, 0x6d, 0xd1, 0xff, 0x00, 0x3b,
+	0xb6, 0x95, 0x10, 0xd8, 0x2e, 0x69, 0x61, 0x84, 0xff, 0x8b, 0xf5, 0x99, 0x3c, 0x87, 0x7e, 0xbb,
+	0xa9, 0xd5, 0x39, 0x08, 0x8c, 0x95, 0xa0, 0xb5, 0x12, 0x8c, 0x2c, 0x21, 0x5e, 0x50, 0xfd, 0x6f,
+	0x0e, 0xb8, 0x66, 0x10, 0xb9, 0x07, 0x3d, 0x41, 0xd3, 0xcb, 0x4c, 0xcb, 0xf6, 0xc7, 0x9d, 0xd8,
+	0x3c, 0x92, 0xc7, 0xd0, 0x17, 0x4c, 0x60, 0xce, 0x4a, 0x5c, 0x6c, 0xd8, 0x38, 0x3f, 0xb3, 0xe0,
+	0xb8, 0x13, 0x2f, 0x08, 0xe4, 0x11, 0xb8, 0x57, 0x5c, 0x7e, 0x41, 0x39, 0xec, 0xde, 0x30, 0x73,
+	0xae, 0xc1, 0x71, 0x27, 0xb6, 0xe5, 0xa8, 0xdf, 0xba, 0xf6, 0x5f, 0x82, 0x6b, 0xaa, 0xe4, 0x0e,
+	0x74, 0x05, 0xcf, 0xac, 0xad, 0xe6, 0x48, 0x3c, 0x00, 0x89, 0x19, 0x93, 0x98, 0x2a, 0xcc, 0xf4,
+	0xf4, 0x7e, 0xbc, 0x82, 0xf8, 0x2f, 0x60, 0x10, 0xb1, 0x92, 0xca, 0x59, 0x1b, 0xfb, 0x32, 0x4c,
+	0xe7, 0xb6, 0x30, 0x9f, 0xc1, 0xee, 0xa8, 0x2e, 0xc4, 0x3f, 0x76, 0x29, 0x38, 0x78, 0xc7, 0xf3,
+	0x1c, 0x53, 0x35, 0x6a, 0xca, 0x51, 0x5d, 0x66, 0xcb, 0x17, 0x1e, 0x42, 0xaf, 0x62, 0x65, 0xda,
+	0xbe, 0xee, 0x5b, 0xd2, 0x37, 0x3c, 0xf2, 0x10, 0xfe, 0x2f, 0xe8, 0xf5, 0xc5, 0x84, 0xb2, 0x1c,
+	0xb3, 0x8b, 0xcf, 0x3c, 0xa9, 0xb4, 0xc1, 0x6e, 0x3c, 0x28, 0xe8, 0xf5, 0xa9, 0x46, 0xdf, 0xf3,
+	0xa4, 0x3a, 0xf9, 0xbe, 0x05, 0x3d, 0x3d, 0x8f, 0xbc, 0x5d, 0x5e, 0x81, 0xbb, 0x7f, 0xdc, 0x26,
+	0xb3, 0xc4, 0xe1, 0xfd, 0xb5, 0xa9, 0xd1, 0x4c, 0x61, 0xf5, 0x91, 0xe6, 0x35, 0xfa, 0x9d, 0x27,
+	0x0e, 0x79, 0x03, 0xae, 0x09, 0x8c, 0xec, 0x5b, 0x85, 0x1b, 0xf9, 0x6d, 0x16, 0x78, 0x05, 0xdb,
+	0x4d, 0x72, 0x84, 0xd8, 0xf6, 0x95, 0x18, 0x37, 0x37, 0x9f, 0x03, 0x59, 0x0f, 0x90, 0x1c, 0x59,
+	0xa9, 0xbf, 0x66, 0xbb, 0x51, 0x38, 0x7a, 0xfd, 0x63, 0xee, 0x39, 0x3f, 0xe7, 0x9e, 0xf3, 0x6b,
+	0xee, 0x39, 0x9f, 0xc2, 0x29, 0x53, 0x97, 0x75, 0x12, 0xa4, 0xbc, 0x08, 0x9b, 0x3b, 0x3c, 0xcb,
+	0x50, 0xae, 0x9e, 0x2a, 0x99, 0x86, 0xab, 0xdf, 0x89, 0xc4, 0xd5, 0xba, 0x4f, 0x7f, 0x0f, 0x00,
+	0xcb, 0xfa, 0xd4, 0x89, 0x3e, 0x04, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -445,6 +514,9 @@ type DebugClient interface {
 	Profile(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (Debug_ProfileClient, error)
 	Binary(ctx context.Context, in *BinaryRequest, opts ...grpc.CallOption) (Debug_BinaryClient, error)
 	Dump(ctx context.Context, in *DumpRequest, opts ...grpc.CallOption) (Debug_DumpClient, error)
+	// CollectDebugBundle returns a gzipped tar archive of debugging information
+	// about the cluster. It requires cluster admin if auth is active.
+	CollectDebugBundle(ctx context.Context, in *CollectDebugBundleRequest, opts ...grpc.CallOption) (Debug_CollectDebugBundleClient, error)
 }
 
 type debugClient struct {
@@ -551,11 +623,46 @@ func (x *debugDumpClient) Recv() (*types.BytesValue, error) {
 	return m, nil
 }
 
+func (c *debugClient) CollectDebugBundle(ctx context.Context, in *CollectDebugBundleRequest, opts ...grpc.CallOption) (Debug_CollectDebugBundleClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Debug_serviceDesc.Streams[3], "/debug.Debug/CollectDebugBundle", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &debugCollectDebugBundleClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Debug_CollectDebugBundleClient interface {
+	Recv() (*types.BytesValue, error)
+	grpc.ClientStream
+}
+
+type debugCollectDebugBundleClient struct {
+	grpc.ClientStream
+}
+
+func (x *debugCollectDebugBundleClient) Recv() (*types.BytesValue, error) {
+	m := new(types.BytesValue)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // DebugServer is the server API for Debug service.
 type DebugServer interface {
 	Profile(*ProfileRequest, Debug_ProfileServer) error
 	Binary(*BinaryRequest, Debug_BinaryServer) error
 	Dump(*DumpRequest, Debug_DumpServer) error
+	// CollectDebugBundle returns a gzipped tar archive of debugging information
+	// about the cluster. It requires cluster admin if auth is active.
+	CollectDebugBundle(*CollectDebugBundleRequest, Debug_CollectDebugBundleServer) error
 }
 
 // UnimplementedDebugServer can be embedded to have forward compatible implementations.
@@ -571,6 +678,9 @@ func (*UnimplementedDebugServer) Binary(req *BinaryRequest, srv Debug_BinaryServ
 func (*UnimplementedDebugServer) Dump(req *DumpRequest, srv Debug_DumpServer) error {
 	return status.Errorf(codes.Unimplemented, "method Dump not implemented")
 }
+func (*UnimplementedDebugServer) CollectDebugBundle(req *CollectDebugBundleRequest, srv Debug_CollectDebugBundleServer) error {
+	return status.Errorf(codes.Unimplemented, "method CollectDebugBundle not implemented")
+}
 
 func RegisterDebugServer(s *grpc.Server, srv DebugServer) {
 	s.RegisterService(&_Debug_serviceDesc, srv)
@@ -639,6 +749,27 @@ func (x *debugDumpServer) Send(m *types.BytesValue) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Debug_CollectDebugBundle_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CollectDebugBundleRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DebugServer).CollectDebugBundle(m, &debugCollectDebugBundleServer{stream})
+}
+
+type Debug_CollectDebugBundleServer interface {
+	Send(*types.BytesValue) error
+	grpc.ServerStream
+}
+
+type debugCollectDebugBundleServer struct {
+	grpc.ServerStream
+}
+
+func (x *debugCollectDebugBundleServer) Send(m *types.BytesValue) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Debug_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "debug.Debug",
 	HandlerType: (*DebugServer)(nil),
@@ -659,6 +790,11 @@ var _Debug_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Debug_Dump_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "CollectDebugBundle",
+			Handler:       _Debug_CollectDebugBundle_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "client/debug/debug.proto",
 }
@@ -977,6 +1113,50 @@ func (m *DumpRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *CollectDebugBundleRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CollectDebugBundleRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CollectDebugBundleRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.MaxFailedJobs != 0 {
+		i = encodeVarintDebug(dAtA, i, uint64(m.MaxFailedJobs))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Since != nil {
+		{
+			size, err := m.Since.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintDebug(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintDebug(dAtA []byte, offset int, v uint64) int {
 	offset -= sovDebug(v)
 	base := offset
@@ -1127,6 +1307,25 @@ func (m *DumpRequest) Size() (n int) {
 	return n
 }
 
+func (m *CollectDebugBundleRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Since != nil {
+		l = m.Since.Size()
+		n += 1 + l + sovDebug(uint64(l))
+	}
+	if m.MaxFailedJobs != 0 {
+		n += 1 + sovDebug(uint64(m.MaxFailedJobs))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func sovDebug(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -1812,6 +2011,115 @@ func (m *DumpRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *CollectDebugBundleRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDebug
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CollectDebugBundleRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CollectDebugBundleRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Since", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDebug
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDebug
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthDebug
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Since == nil {
+				m.Since = &types.Duration{}
+			}
+			if err := m.Since.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxFailedJobs", wireType)
+			}
+			m.MaxFailedJobs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDebug
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxFailedJobs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDebug(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDebug
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthDebug
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipDebug(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
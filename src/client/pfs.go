@@ -5,6 +5,7 @@ import (
 	"context"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/gogo/protobuf/types"
 	"github.com/pachyderm/pachyderm/src/client/pfs"
@@ -26,6 +27,14 @@ func NewBranch(repoName string, branchName string) *pfs.Branch {
 	}
 }
 
+// NewCommitTag creates a pfs.CommitTag
+func NewCommitTag(repoName string, tagName string) *pfs.CommitTag {
+	return &pfs.CommitTag{
+		Repo: NewRepo(repoName),
+		Name: tagName,
+	}
+}
+
 // NewCommit creates a pfs.Commit.
 func NewCommit(repoName string, commitID string) *pfs.Commit {
 	return &pfs.Commit{
@@ -153,6 +162,102 @@ func (c APIClient) DeleteRepo(repoName string, force bool) error {
 	return grpcutil.ScrubGRPC(err)
 }
 
+// DeleteAllReposMatching deletes every repo whose name starts with 'prefix'.
+// Unlike DeleteAll, it isn't constrained by the cluster's DeleteAllPolicy, as
+// the caller must be authorized to delete each repo.
+func (c APIClient) DeleteAllReposMatching(prefix string) error {
+	if prefix == "" {
+		return errors.New("prefix must not be empty; use DeleteAll to delete every repo")
+	}
+	_, err := c.PfsAPIClient.DeleteRepo(
+		c.Ctx(),
+		&pfs.DeleteRepoRequest{
+			All:    true,
+			Prefix: prefix,
+			Force:  true,
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
+// SetReadPolicy sets the read policy of a repo, which makes GetFile redact
+// its files for callers without full access to it. If "policy" is nil, the
+// repo's read policy is removed.
+func (c APIClient) SetReadPolicy(repoName string, policy *pfs.ReadPolicy) error {
+	_, err := c.PfsAPIClient.SetReadPolicy(
+		c.Ctx(),
+		&pfs.SetReadPolicyRequest{
+			Repo:   NewRepo(repoName),
+			Policy: policy,
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
+// SetOpenCommitPolicy sets the open-commit policy of a repo, which abandons
+// the repo's commits that are left open for longer than the policy allows. If
+// "policy" is nil, the repo's open-commit policy is removed.
+func (c APIClient) SetOpenCommitPolicy(repoName string, policy *pfs.OpenCommitPolicy) error {
+	_, err := c.PfsAPIClient.SetOpenCommitPolicy(
+		c.Ctx(),
+		&pfs.SetOpenCommitPolicyRequest{
+			Repo:   NewRepo(repoName),
+			Policy: policy,
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
+// UpdateRepoMetadata replaces the catalog metadata of a repo, which users can
+// search with DiscoverRepos. If "metadata" is nil, the repo's metadata is
+// removed. If "description" is set, it also replaces the repo's description.
+func (c APIClient) UpdateRepoMetadata(repoName string, metadata *pfs.RepoMetadata, description string) error {
+	_, err := c.PfsAPIClient.UpdateRepoMetadata(
+		c.Ctx(),
+		&pfs.UpdateRepoMetadataRequest{
+			Repo:        NewRepo(repoName),
+			Metadata:    metadata,
+			Description: description,
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
+// DiscoverRepos returns the repos whose names, descriptions and catalog
+// metadata match "query", most relevant first. If "owner" or "tags" are set,
+// only the repos with that owner and all of those tags are returned.
+func (c APIClient) DiscoverRepos(query string, owner string, tags []string) (*pfs.DiscoverReposResponse, error) {
+	response, err := c.PfsAPIClient.DiscoverRepos(
+		c.Ctx(),
+		&pfs.DiscoverReposRequest{
+			Query: query,
+			Owner: owner,
+			Tags:  tags,
+		},
+	)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return response, nil
+}
+
+// ListOpenCommits returns the commits that are still open in a repo, oldest
+// first. If "repoName" is empty, the open commits of all repos are returned.
+func (c APIClient) ListOpenCommits(repoName string) ([]*pfs.CommitInfo, error) {
+	var repo *pfs.Repo
+	if repoName != "" {
+		repo = NewRepo(repoName)
+	}
+	commitInfos, err := c.PfsAPIClient.ListOpenCommits(
+		c.Ctx(),
+		&pfs.ListOpenCommitsRequest{Repo: repo},
+	)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return commitInfos.CommitInfo, nil
+}
+
 // StartCommit begins the process of committing data to a Repo. Once started
 // you can write to the Commit with PutFile and when all the data has been
 // written you must finish the Commit with FinishCommit. NOTE, data is not
@@ -245,6 +350,54 @@ func (c APIClient) FinishCommit(repoName string, commitID string) error {
 	return grpcutil.ScrubGRPC(err)
 }
 
+// StartCommitOnHead is like StartCommit, but only starts the commit if the
+// head of 'branch' is currently 'expectedHead' (or, if it's empty, if 'branch'
+// has no head); otherwise the error satisfies
+// pfsserver.IsBranchHeadMismatchErr.
+func (c APIClient) StartCommitOnHead(repoName string, branch string, expectedHead string) (*pfs.Commit, error) {
+	commit, err := c.PfsAPIClient.StartCommit(
+		c.Ctx(),
+		&pfs.StartCommitRequest{
+			Parent:       &pfs.Commit{Repo: &pfs.Repo{Name: repoName}},
+			Branch:       branch,
+			ExpectedHead: NewCommit(repoName, expectedHead),
+		},
+	)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return commit, nil
+}
+
+// FinishCommitOnHead is like FinishCommit, but only finishes the commit if
+// it's still the head of its branch, i.e. if no other commit has been started
+// on the branch since it was. Otherwise the commit is left open, and the error
+// satisfies pfsserver.IsCommitConflictErr.
+func (c APIClient) FinishCommitOnHead(repoName string, commitID string) error {
+	_, err := c.PfsAPIClient.FinishCommit(
+		c.Ctx(),
+		&pfs.FinishCommitRequest{
+			Commit:      NewCommit(repoName, commitID),
+			RequireHead: true,
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
+// FinishCommitStatus returns the progress of FinishCommit on a commit.
+func (c APIClient) FinishCommitStatus(repoName string, commitID string) (*pfs.FinishCommitProgress, error) {
+	progress, err := c.PfsAPIClient.FinishCommitStatus(
+		c.Ctx(),
+		&pfs.FinishCommitStatusRequest{
+			Commit: NewCommit(repoName, commitID),
+		},
+	)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return progress, nil
+}
+
 // InspectCommit returns info about a specific Commit.
 func (c APIClient) InspectCommit(repoName string, commitID string) (*pfs.CommitInfo, error) {
 	return c.inspectCommit(repoName, commitID, pfs.CommitState_STARTED)
@@ -375,6 +528,43 @@ func (c APIClient) CreateBranchTrigger(repoName string, branch string, commit st
 	return grpcutil.ScrubGRPC(err)
 }
 
+// MoveBranch moves an existing branch to 'commit', which may use ancestry
+// syntax (e.g. "master^3"), keeping its provenance and trigger. If
+// 'expectedHead' isn't empty, the branch is only moved if its head is
+// currently that commit; otherwise the error satisfies
+// pfsserver.IsBranchHeadMismatchErr.
+func (c APIClient) MoveBranch(repoName string, branch string, commit string, expectedHead string) error {
+	var expected *pfs.Commit
+	if expectedHead != "" {
+		expected = NewCommit(repoName, expectedHead)
+	}
+	_, err := c.PfsAPIClient.MoveBranch(
+		c.Ctx(),
+		&pfs.MoveBranchRequest{
+			Branch:       NewBranch(repoName, branch),
+			Head:         NewCommit(repoName, commit),
+			ExpectedHead: expected,
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
+// SetBranchDeferred makes an existing branch deferred, so that its new heads
+// don't trigger downstream pipelines, or not. Commits can be staged on a
+// deferred branch, and then processed together by moving a branch that
+// pipelines read to its head with MoveBranch, which is atomic, or by making
+// the branch stop being deferred.
+func (c APIClient) SetBranchDeferred(repoName string, branch string, deferred bool) error {
+	_, err := c.PfsAPIClient.SetBranchDeferred(
+		c.Ctx(),
+		&pfs.SetBranchDeferredRequest{
+			Branch:   NewBranch(repoName, branch),
+			Deferred: deferred,
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
 // InspectBranch returns information on a specific PFS branch
 func (c APIClient) InspectBranch(repoName string, branch string) (*pfs.BranchInfo, error) {
 	branchInfo, err := c.PfsAPIClient.InspectBranch(
@@ -420,6 +610,71 @@ func (c APIClient) DeleteBranch(repoName string, branch string, force bool) erro
 	return grpcutil.ScrubGRPC(err)
 }
 
+// TagCommit creates a tag called 'tagName', which refers to the commit
+// 'commitID' (which may be a branch, in which case its HEAD is tagged) until
+// the tag is deleted. The commit must be finished. The tag's name can be used
+// anywhere that a commit ID can.
+func (c APIClient) TagCommit(repoName string, commitID string, tagName string, description string) error {
+	_, err := c.PfsAPIClient.TagCommit(
+		c.Ctx(),
+		&pfs.TagCommitRequest{
+			Tag:         NewCommitTag(repoName, tagName),
+			Commit:      NewCommit(repoName, commitID),
+			Description: description,
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
+// InspectCommitTag returns information about a commit tag.
+func (c APIClient) InspectCommitTag(repoName string, tagName string) (*pfs.CommitTagInfo, error) {
+	tagInfo, err := c.PfsAPIClient.InspectCommitTag(
+		c.Ctx(),
+		&pfs.InspectCommitTagRequest{
+			Tag: NewCommitTag(repoName, tagName),
+		},
+	)
+	return tagInfo, grpcutil.ScrubGRPC(err)
+}
+
+// ListCommitTag lists the commit tags in a repo.
+func (c APIClient) ListCommitTag(repoName string) ([]*pfs.CommitTagInfo, error) {
+	tagInfos, err := c.PfsAPIClient.ListCommitTag(
+		c.Ctx(),
+		&pfs.ListCommitTagRequest{
+			Repo: NewRepo(repoName),
+		},
+	)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return tagInfos.CommitTagInfo, nil
+}
+
+// DeleteCommitTag deletes a commit tag, which must not be used by any
+// pipeline. The commit that it refers to isn't affected.
+func (c APIClient) DeleteCommitTag(repoName string, tagName string) error {
+	_, err := c.PfsAPIClient.DeleteCommitTag(
+		c.Ctx(),
+		&pfs.DeleteCommitTagRequest{
+			Tag: NewCommitTag(repoName, tagName),
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
+// GetAttestation returns the signed attestation of an output commit of a
+// pipeline that has 'attest' set.
+func (c APIClient) GetAttestation(repoName string, commitID string) (*pfs.Attestation, error) {
+	attestation, err := c.PfsAPIClient.GetAttestation(
+		c.Ctx(),
+		&pfs.GetAttestationRequest{
+			Commit: NewCommit(repoName, commitID),
+		},
+	)
+	return attestation, grpcutil.ScrubGRPC(err)
+}
+
 // DeleteCommit deletes a commit.
 func (c APIClient) DeleteCommit(repoName string, commitID string) error {
 	_, err := c.PfsAPIClient.DeleteCommit(
@@ -431,6 +686,19 @@ func (c APIClient) DeleteCommit(repoName string, commitID string) error {
 	return grpcutil.ScrubGRPC(err)
 }
 
+// PruneCommit drops the contents of a finished commit, leaving it empty, so
+// that they can be garbage collected. The commit keeps its place in its
+// branch and its provenance.
+func (c APIClient) PruneCommit(repoName string, commitID string) error {
+	_, err := c.PfsAPIClient.PruneCommit(
+		c.Ctx(),
+		&pfs.PruneCommitRequest{
+			Commit: NewCommit(repoName, commitID),
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
 // FlushCommit returns an iterator that returns commits that have the
 // specified `commits` as provenance.  Note that the iterator can block if
 // jobs have not successfully completed. This in effect waits for all of the
@@ -470,13 +738,39 @@ func (c APIClient) FlushCommit(commits []*pfs.Commit, toRepos []*pfs.Repo) (Comm
 // no matter what, FlushCommitF just allows you to wait for them to complete and
 // see their output once they do.
 func (c APIClient) FlushCommitF(commits []*pfs.Commit, toRepos []*pfs.Repo, f func(*pfs.CommitInfo) error) error {
-	stream, err := c.PfsAPIClient.FlushCommit(
-		c.Ctx(),
-		&pfs.FlushCommitRequest{
-			Commits: commits,
-			ToRepos: toRepos,
-		},
-	)
+	return c.flushCommitF(&pfs.FlushCommitRequest{
+		Commits: commits,
+		ToRepos: toRepos,
+	}, f)
+}
+
+// FlushCommitStatusF is like FlushCommitF, but it also waits for the job
+// that produced each output commit to finish, and sets the commit's
+// FlushStatus to the job's pipeline, ID and final state. Commits that aren't
+// the output of a job (e.g. stats commits) have no FlushStatus.
+func (c APIClient) FlushCommitStatusF(commits []*pfs.Commit, toRepos []*pfs.Repo, f func(*pfs.CommitInfo) error) error {
+	return c.flushCommitF(&pfs.FlushCommitRequest{
+		Commits:   commits,
+		ToRepos:   toRepos,
+		JobStatus: true,
+	}, f)
+}
+
+// FlushCommitStatusAll is like FlushCommitStatusF, but it returns the
+// commits in a slice.
+func (c APIClient) FlushCommitStatusAll(commits []*pfs.Commit, toRepos []*pfs.Repo) ([]*pfs.CommitInfo, error) {
+	var result []*pfs.CommitInfo
+	if err := c.FlushCommitStatusF(commits, toRepos, func(ci *pfs.CommitInfo) error {
+		result = append(result, ci)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c APIClient) flushCommitF(request *pfs.FlushCommitRequest, f func(*pfs.CommitInfo) error) error {
+	stream, err := c.PfsAPIClient.FlushCommit(c.Ctx(), request)
 	if err != nil {
 		return grpcutil.ScrubGRPC(err)
 	}
@@ -591,6 +885,81 @@ func (c APIClient) SubscribeCommitF(repo, branch string, prov *pfs.CommitProvena
 	}
 }
 
+// ChangeFeed calls 'f' with each file-level change (put, overwrite or delete)
+// made by the commits to 'repo'@'branch', in order, followed by a
+// COMMIT_FINISHED event for each commit. It keeps waiting for new commits to
+// be finished until 'f' returns an error. If 'f' returns errutil.ErrBreak,
+// ChangeFeed returns nil. If 'cursor' is set (to the Cursor of an event
+// returned by a previous call), the feed resumes after that event.
+func (c APIClient) ChangeFeed(repo, branch, cursor string, f func(*pfs.ChangeFeedEvent) error) error {
+	stream, err := c.PfsAPIClient.ChangeFeed(c.Ctx(), &pfs.ChangeFeedRequest{
+		Branch: NewBranch(repo, branch),
+		Cursor: cursor,
+	})
+	if err != nil {
+		return grpcutil.ScrubGRPC(err)
+	}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return grpcutil.ScrubGRPC(err)
+		}
+		if err := f(event); err != nil {
+			if errors.Is(err, errutil.ErrBreak) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// WatchRepos calls 'f' with a PUT event for each existing repo, then a SYNCED
+// event, and then an event for each change to the set of repos. It keeps
+// watching until 'f' returns an error. If 'f' returns errutil.ErrBreak,
+// WatchRepos returns nil. Most callers should use a RepoCache instead.
+func (c APIClient) WatchRepos(f func(*pfs.RepoEvent) error) error {
+	stream, err := c.PfsAPIClient.WatchRepos(c.Ctx(), &pfs.WatchReposRequest{})
+	if err != nil {
+		return grpcutil.ScrubGRPC(err)
+	}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return grpcutil.ScrubGRPC(err)
+		}
+		if err := f(event); err != nil {
+			if errors.Is(err, errutil.ErrBreak) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// ListDeleted returns the deleted repos and commits that can be restored with
+// RestoreDeleted.
+func (c APIClient) ListDeleted() ([]*pfs.DeletedInfo, error) {
+	response, err := c.PfsAPIClient.ListDeleted(c.Ctx(), &pfs.ListDeletedRequest{})
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return response.DeletedInfo, nil
+}
+
+// RestoreDeleted restores the deleted repo or commit with the given ID.
+func (c APIClient) RestoreDeleted(id string) error {
+	_, err := c.PfsAPIClient.RestoreDeleted(c.Ctx(), &pfs.RestoreDeletedRequest{ID: id})
+	return grpcutil.ScrubGRPC(err)
+}
+
+// PurgeDeleted permanently deletes the deleted repo or commit with the given
+// ID. If 'id' is "", all of the deleted items whose retention has expired are
+// purged.
+func (c APIClient) PurgeDeleted(id string) error {
+	_, err := c.PfsAPIClient.PurgeDeleted(c.Ctx(), &pfs.PurgeDeletedRequest{ID: id})
+	return grpcutil.ScrubGRPC(err)
+}
+
 // PutObjectAsync puts a value into the object store asynchronously.
 func (c APIClient) PutObjectAsync(tags []*pfs.Tag) (*PutObjectWriteCloserAsync, error) {
 	w, err := c.newPutObjectWriteCloserAsync(tags)
@@ -1225,6 +1594,93 @@ func (c APIClient) GetFile(repoName string, commitID string, path string, offset
 	return nil
 }
 
+// GetFileRecords writes records of a file at a specific Commit to writer, as
+// delimited by delimiter. offset specifies a number of records that should be
+// skipped, and size limits the number of records returned (0 means all
+// remaining records). If path is a file created by PutFileSplit, the records
+// of all of its split files are read, and its header and footer (if any) are
+// written before and after the records.
+func (c APIClient) GetFileRecords(repoName string, commitID string, path string, delimiter pfs.Delimiter, offset int64, size int64, writer io.Writer) error {
+	if c.limiter != nil {
+		c.limiter.Acquire()
+		defer c.limiter.Release()
+	}
+	apiGetFileClient, err := c.PfsAPIClient.GetFile(
+		c.Ctx(),
+		&pfs.GetFileRequest{
+			File:          NewFile(repoName, commitID, path),
+			Delimiter:     delimiter,
+			OffsetRecords: offset,
+			SizeRecords:   size,
+		},
+	)
+	if err != nil {
+		return grpcutil.ScrubGRPC(err)
+	}
+	if err := grpcutil.WriteFromStreamingBytesClient(apiGetFileClient, writer); err != nil {
+		return grpcutil.ScrubGRPC(err)
+	}
+	return nil
+}
+
+// GetFileRanges writes the bytes in each of 'ranges' of a file at a specific
+// Commit to writer, one after the other. Each range's lower bound is inclusive
+// and its upper bound exclusive, and every range must be non-empty and within
+// the file, so what's written can be split by the ranges' sizes.
+func (c APIClient) GetFileRanges(repoName string, commitID string, path string, ranges []*pfs.ByteRange, writer io.Writer) error {
+	if c.limiter != nil {
+		c.limiter.Acquire()
+		defer c.limiter.Release()
+	}
+	apiGetFileClient, err := c.PfsAPIClient.GetFile(
+		c.Ctx(),
+		&pfs.GetFileRequest{
+			File:   NewFile(repoName, commitID, path),
+			Ranges: ranges,
+		},
+	)
+	if err != nil {
+		return grpcutil.ScrubGRPC(err)
+	}
+	if err := grpcutil.WriteFromStreamingBytesClient(apiGetFileClient, writer); err != nil {
+		return grpcutil.ScrubGRPC(err)
+	}
+	return nil
+}
+
+// GetFileAncestry writes the contents of a file to writer like GetFile, except
+// that if the file isn't in the commit, it's read from the nearest of the
+// commit's first 'depth' ancestors that has it. It returns the commit that the
+// file was read from.
+func (c APIClient) GetFileAncestry(repoName string, commitID string, path string, depth int64, writer io.Writer) (*pfs.Commit, error) {
+	if c.limiter != nil {
+		c.limiter.Acquire()
+		defer c.limiter.Release()
+	}
+	apiGetFileClient, err := c.PfsAPIClient.GetFile(
+		c.Ctx(),
+		&pfs.GetFileRequest{
+			File:          NewFile(repoName, commitID, path),
+			AncestryDepth: depth,
+		},
+	)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	if err := grpcutil.WriteFromStreamingBytesClient(apiGetFileClient, writer); err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	header, err := apiGetFileClient.Header()
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	resolved := header.Get(pfs.ResolvedCommitKey)
+	if len(resolved) == 0 {
+		return nil, errors.Errorf("GetFile response is missing the %q header", pfs.ResolvedCommitKey)
+	}
+	return NewCommit(repoName, resolved[0]), nil
+}
+
 // GetFileReader returns a reader for the contents of a file at a specific Commit.
 // offset specifies a number of bytes that should be skipped in the beginning of the file.
 // size limits the total amount of data returned, note you will get fewer bytes
@@ -1275,6 +1731,40 @@ func (c APIClient) InspectFile(repoName string, commitID string, path string) (*
 	return c.inspectFile(repoName, commitID, path)
 }
 
+// InspectFileAncestry returns info about a specific file like InspectFile,
+// except that if the file isn't in the commit, the nearest of the commit's
+// first 'depth' ancestors that has it is inspected. The commit that was
+// inspected is in the result's File.
+func (c APIClient) InspectFileAncestry(repoName string, commitID string, path string, depth int64) (*pfs.FileInfo, error) {
+	fileInfo, err := c.PfsAPIClient.InspectFile(
+		c.Ctx(),
+		&pfs.InspectFileRequest{
+			File:          NewFile(repoName, commitID, path),
+			AncestryDepth: depth,
+		},
+	)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return fileInfo, nil
+}
+
+// GetFileURL returns a signed URL that anyone can download a file from,
+// through pachd's HTTP server, for "ttl" (or an hour, if "ttl" is 0). The
+// response's Path is the URL's path and query, relative to the HTTP server's
+// address.
+func (c APIClient) GetFileURL(repoName string, commitID string, path string, ttl time.Duration) (*pfs.GetFileURLResponse, error) {
+	request := &pfs.GetFileURLRequest{File: NewFile(repoName, commitID, path)}
+	if ttl != 0 {
+		request.Ttl = types.DurationProto(ttl)
+	}
+	response, err := c.PfsAPIClient.GetFileURL(c.Ctx(), request)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return response, nil
+}
+
 func (c APIClient) inspectFile(repoName string, commitID string, path string) (*pfs.FileInfo, error) {
 	fileInfo, err := c.PfsAPIClient.InspectFile(
 		c.Ctx(),
@@ -1481,6 +1971,36 @@ func (c APIClient) DeleteFile(repoName string, commitID string, path string) err
 	return pfc.DeleteFile(repoName, commitID, path)
 }
 
+// DeleteFiles deletes every file in a commit that matches one of 'paths',
+// which may be glob patterns (e.g. "/logs/*.tmp"). Unlike calling DeleteFile
+// for each path, the deletes are applied atomically: if the commit is open,
+// readers see all of them or none of them, and if 'commitID' is a branch
+// whose HEAD is finished, a single new commit deletes all of them.
+func (c APIClient) DeleteFiles(repoName string, commitID string, paths ...string) error {
+	_, err := c.PfsAPIClient.DeleteFiles(
+		c.Ctx(),
+		&pfs.DeleteFilesRequest{
+			Commit: NewCommit(repoName, commitID),
+			Paths:  paths,
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
+// ReplaceDirectory replaces the directory at 'path' in the open commit
+// 'commitID' with the files that are subsequently put in it. The new files
+// are staged: reads of the commit see the directory's old contents until the
+// commit is finished, at which point the directory is atomically replaced.
+func (c APIClient) ReplaceDirectory(repoName string, commitID string, path string) error {
+	_, err := c.PfsAPIClient.ReplaceDirectory(
+		c.Ctx(),
+		&pfs.ReplaceDirectoryRequest{
+			File: NewFile(repoName, commitID, path),
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
 type putFileWriteCloser struct {
 	request *pfs.PutFileRequest
 	sent    bool
@@ -1514,6 +2034,18 @@ func (c APIClient) Fsck(fix bool, cb func(*pfs.FsckResponse) error) error {
 	return nil
 }
 
+// ArchiveProvenance moves the provenance of commits that refers to deleted
+// commits (e.g. the output commits of deleted pipelines) into the commits'
+// archived provenance. If dryRun is true, nothing is changed, and the response
+// reports what would be archived.
+func (c APIClient) ArchiveProvenance(dryRun bool) (*pfs.ArchiveProvenanceResponse, error) {
+	response, err := c.PfsAPIClient.ArchiveProvenance(c.Ctx(), &pfs.ArchiveProvenanceRequest{DryRun: dryRun})
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return response, nil
+}
+
 // FsckFastExit performs checks on pfs, similar to Fsck, except that it returns the
 // first fsck error it encounters and exits.
 func (c APIClient) FsckFastExit() error {
@@ -15,6 +15,22 @@ var (
 	EmptyStr = "(empty)"
 )
 
+const (
+	// ValidationMetadataKey is the key of the commit metadata that records
+	// whether a validation pipeline's assertions passed on the input of its
+	// output commit. Its value is ValidationPassed or ValidationFailed.
+	ValidationMetadataKey = "validation"
+	// ValidationPassed means that all of a validation pipeline's assertions
+	// passed.
+	ValidationPassed = "passed"
+	// ValidationFailed means that at least one of a validation pipeline's
+	// assertions failed.
+	ValidationFailed = "failed"
+	// ResolvedCommitKey is the key of the gRPC header that GetFile sets to the
+	// ID of the commit that was read when GetFileRequest.ancestry_depth is set.
+	ResolvedCommitKey = "pfs-resolved-commit"
+)
+
 // FullID prints repoName/CommitID
 func (c *Commit) FullID() string {
 	return fmt.Sprintf("%s/%s", c.Repo.Name, c.ID)
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 
 	"github.com/gogo/protobuf/proto"
@@ -16,6 +17,7 @@ import (
 
 const configEnvVar = "PACH_CONFIG"
 const contextEnvVar = "PACH_CONTEXT"
+const pachdAddressEnvVar = "PACHD_ADDRESS"
 
 var defaultConfigDir = filepath.Join(os.Getenv("HOME"), ".pachyderm")
 var defaultConfigPath = filepath.Join(defaultConfigDir, "config.json")
@@ -42,6 +44,9 @@ func (c *Config) ActiveContext(errorOnNoActive bool) (string, *Context, error) {
 		}
 		return envContext, context, nil
 	}
+	if name, context := c.contextForEnvPachdAddress(); context != nil {
+		return name, context, nil
+	}
 	context := c.V2.Contexts[c.V2.ActiveContext]
 	if context == nil {
 		if c.V2.ActiveContext == "" {
@@ -63,6 +68,48 @@ func (c *Config) ActiveContext(errorOnNoActive bool) (string, *Context, error) {
 	return c.V2.ActiveContext, context, nil
 }
 
+// contextForEnvPachdAddress returns the context that points at the pachd
+// address in PACHD_ADDRESS, if it's set, so that the address's session token
+// and certs are used with it. If several contexts point at the address, the
+// active context is preferred, then the first by name.
+func (c *Config) contextForEnvPachdAddress() (string, *Context) {
+	envAddrStr, ok := os.LookupEnv(pachdAddressEnvVar)
+	if !ok {
+		return "", nil
+	}
+	envAddr, err := grpcutil.ParsePachdAddress(envAddrStr)
+	if err != nil {
+		return "", nil // reported when the client connects
+	}
+	var names []string
+	for name, context := range c.V2.Contexts {
+		if context.PachdAddress == envAddr.Qualified() {
+			if name == c.V2.ActiveContext {
+				return name, context
+			}
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	sort.Strings(names)
+	return names[0], c.V2.Contexts[names[0]]
+}
+
+// SetActiveContext makes the context named 'name' the active context. It
+// doesn't write the config.
+func (c *Config) SetActiveContext(name string) error {
+	if c.V2 == nil {
+		return errors.Errorf("cannot set active context in non-v2 config")
+	}
+	if _, ok := c.V2.Contexts[name]; !ok {
+		return errors.Errorf("context does not exist: %s", name)
+	}
+	c.V2.ActiveContext = name
+	return nil
+}
+
 // Read loads the Pachyderm config on this machine.
 // If an existing configuration cannot be found, it sets up the defaults. Read
 // returns a nil Config if and only if it returns a non-nil error.
@@ -312,7 +312,11 @@ type Context struct {
 	// A unique ID for the cluster deployment. At client initialization time,
 	// we ensure this is the same as what the cluster reports back, to prevent
 	// us from connecting to the wrong cluster.
-	ClusterDeploymentID  string   `protobuf:"bytes,11,opt,name=cluster_deployment_id,json=clusterDeploymentId,proto3" json:"cluster_deployment_id,omitempty"`
+	ClusterDeploymentID string `protobuf:"bytes,11,opt,name=cluster_deployment_id,json=clusterDeploymentId,proto3" json:"cluster_deployment_id,omitempty"`
+	// The name that pachd's TLS certificate is verified against, if it isn't
+	// the hostname in pachd_address (e.g. when pachd is reached through a
+	// proxy or tunnel).
+	TLSServerName        string   `protobuf:"bytes,13,opt,name=tls_server_name,json=tlsServerName,proto3" json:"tls_server_name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -421,6 +425,13 @@ func (m *Context) GetClusterDeploymentID() string {
 	return ""
 }
 
+func (m *Context) GetTLSServerName() string {
+	if m != nil {
+		return m.TLSServerName
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterEnum("config.ContextSource", ContextSource_name, ContextSource_value)
 	proto.RegisterType((*Config)(nil), "config.Config")
@@ -434,50 +445,52 @@ func init() {
 func init() { proto.RegisterFile("client/pkg/config/config.proto", fileDescriptor_60f651abce1dcdf3) }
 
 var fileDescriptor_60f651abce1dcdf3 = []byte{
-	// 679 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x54, 0xcd, 0x6e, 0xda, 0x4a,
-	0x18, 0xbd, 0x06, 0x02, 0xf6, 0x07, 0x24, 0xdc, 0x21, 0xd1, 0xb5, 0x72, 0x2b, 0x92, 0x12, 0x45,
-	0x8a, 0xaa, 0x06, 0x84, 0xdb, 0x45, 0x95, 0x4d, 0x15, 0x20, 0x69, 0x69, 0x53, 0x12, 0x39, 0x3f,
-	0x8b, 0x6e, 0x2c, 0xc7, 0x1e, 0xc0, 0x0a, 0xf6, 0xb8, 0x33, 0x03, 0x0d, 0x8f, 0xd0, 0xc7, 0xe8,
-	0x23, 0xf4, 0x2d, 0xba, 0xec, 0x13, 0x44, 0x15, 0x4f, 0x52, 0xcd, 0x8c, 0x21, 0xe4, 0xa7, 0x52,
-	0x57, 0x5d, 0x31, 0x3e, 0xe7, 0x7c, 0x7f, 0xf3, 0x1d, 0x06, 0x2a, 0xde, 0x30, 0xc0, 0x11, 0xaf,
-	0xc7, 0x57, 0xfd, 0xba, 0x47, 0xa2, 0x5e, 0x30, 0xfb, 0xa9, 0xc5, 0x94, 0x70, 0x82, 0xb2, 0xea,
-	0x6b, 0x7d, 0xb5, 0x4f, 0xfa, 0x44, 0x42, 0x75, 0x71, 0x52, 0x6c, 0xf5, 0x13, 0x64, 0x5b, 0x92,
-	0x47, 0x5b, 0x90, 0x1b, 0x31, 0x4c, 0x9d, 0xc0, 0x37, 0xb5, 0x4d, 0x6d, 0xc7, 0x68, 0xc2, 0xf4,
-	0x66, 0x23, 0x7b, 0xce, 0x30, 0xed, 0xb4, 0xed, 0xac, 0xa0, 0x3a, 0x3e, 0xda, 0x84, 0xd4, 0xb8,
-	0x61, 0xa6, 0x36, 0xb5, 0x9d, 0xbc, 0x55, 0xaa, 0x25, 0x75, 0x54, 0x82, 0x8b, 0x86, 0x9d, 0x1a,
-	0x37, 0xa4, 0xc2, 0x32, 0xd3, 0x8f, 0x2a, 0x2c, 0x3b, 0x35, 0xb6, 0xaa, 0xdf, 0x34, 0xd0, 0x67,
-	0x21, 0x68, 0x0b, 0x8a, 0xb1, 0xeb, 0x0d, 0x7c, 0xc7, 0xf5, 0x7d, 0x8a, 0x19, 0x93, 0xb9, 0x0d,
-	0xbb, 0x20, 0xc1, 0x7d, 0x85, 0xa1, 0xe7, 0x00, 0x0c, 0xd3, 0x31, 0xa6, 0x8e, 0xe7, 0x32, 0x99,
-	0xdb, 0x68, 0x16, 0xa7, 0x37, 0x1b, 0xc6, 0xa9, 0x44, 0x5b, 0xfb, 0xcc, 0x36, 0x94, 0xa0, 0xe5,
-	0x32, 0x91, 0x92, 0x61, 0xc6, 0x02, 0x12, 0x39, 0x9c, 0x5c, 0xe1, 0x48, 0x8d, 0x63, 0x17, 0x12,
-	0xf0, 0x4c, 0x60, 0x68, 0x17, 0x90, 0xeb, 0xf1, 0x60, 0x8c, 0x1d, 0x4e, 0xdd, 0x88, 0x89, 0x33,
-	0x89, 0xcc, 0x8c, 0x54, 0xfe, 0xab, 0x98, 0xb3, 0x5b, 0xa2, 0xfa, 0x25, 0x35, 0xef, 0xd9, 0x42,
-	0xdb, 0xb0, 0x9c, 0xc4, 0x7a, 0x24, 0xe2, 0xf8, 0x9a, 0x27, 0x15, 0x8a, 0x0a, 0x6d, 0x29, 0x10,
-	0xed, 0x81, 0x9e, 0xf0, 0x62, 0xaa, 0xf4, 0x4e, 0xde, 0xaa, 0xdc, 0xbf, 0x8f, 0x5a, 0xa2, 0x65,
-	0x07, 0x11, 0xa7, 0x13, 0x7b, 0xae, 0x47, 0x26, 0xe4, 0x42, 0xcc, 0x69, 0xe0, 0xa9, 0x71, 0x75,
-	0x7b, 0xf6, 0x89, 0x2c, 0x58, 0x0b, 0xdd, 0x6b, 0x87, 0x0d, 0xf0, 0x70, 0xe8, 0x78, 0x24, 0x8c,
-	0x87, 0x58, 0x74, 0xc8, 0x64, 0xef, 0x69, 0xbb, 0x1c, 0xba, 0xd7, 0xa7, 0x82, 0x6b, 0xdd, 0x52,
-	0xeb, 0x47, 0x50, 0xbc, 0x53, 0x08, 0x95, 0x20, 0x7d, 0x85, 0x27, 0x49, 0xdb, 0xe2, 0x88, 0xb6,
-	0x61, 0x69, 0xec, 0x0e, 0x47, 0x38, 0xd9, 0xed, 0xca, 0x42, 0xa7, 0x22, 0xce, 0x56, 0xec, 0x5e,
-	0xea, 0x95, 0x56, 0xfd, 0x9a, 0x81, 0xdc, 0x6c, 0xc6, 0x5d, 0xc8, 0x32, 0x32, 0xa2, 0x1e, 0x96,
-	0xb9, 0x96, 0xad, 0xb5, 0x7b, 0x71, 0xa7, 0x92, 0xb4, 0x13, 0xd1, 0x5f, 0xd9, 0x76, 0xe6, 0x8f,
-	0xb7, 0xbd, 0xf4, 0x9b, 0x6d, 0xa3, 0xa7, 0x50, 0xf0, 0x86, 0x23, 0xc6, 0x31, 0x75, 0x22, 0x37,
-	0xc4, 0x66, 0x56, 0x0a, 0xf3, 0x09, 0xd6, 0x75, 0x43, 0x8c, 0xfe, 0x07, 0xc3, 0x1d, 0xf1, 0x81,
-	0x13, 0x44, 0x3d, 0x62, 0xe6, 0x24, 0xaf, 0x0b, 0xa0, 0x13, 0xf5, 0x08, 0x7a, 0x02, 0x86, 0x88,
-	0x63, 0xb1, 0xeb, 0x61, 0x53, 0x97, 0xe4, 0x2d, 0x80, 0x8e, 0x60, 0x25, 0x26, 0x94, 0x3b, 0x3d,
-	0x42, 0x3f, 0xbb, 0xd4, 0xc7, 0x94, 0x99, 0x20, 0xed, 0xb1, 0x75, 0xef, 0xf2, 0x6a, 0x27, 0x84,
-	0xf2, 0xc3, 0xb9, 0x4a, 0x79, 0x64, 0x39, 0xbe, 0x03, 0xa2, 0xf7, 0xb0, 0x36, 0xeb, 0xd5, 0xc7,
-	0xf1, 0x90, 0x4c, 0x42, 0x1c, 0x71, 0xf1, 0x27, 0xce, 0xcb, 0x8b, 0xfb, 0x6f, 0x7a, 0xb3, 0x51,
-	0x6e, 0x29, 0x41, 0x7b, 0xce, 0x77, 0xda, 0x76, 0xd9, 0x7b, 0x00, 0xfa, 0xeb, 0xfb, 0x50, 0x7e,
-	0xa4, 0xe6, 0x23, 0x76, 0x59, 0x5d, 0xb4, 0x4b, 0x71, 0xc1, 0x1d, 0xef, 0x32, 0xba, 0x51, 0x82,
-	0x67, 0xaf, 0xe7, 0x8e, 0x53, 0x0e, 0x40, 0x3a, 0x64, 0xba, 0xc7, 0xdd, 0x83, 0xd2, 0x3f, 0xa8,
-	0x08, 0x46, 0xeb, 0xb8, 0x7b, 0xd8, 0x79, 0xe3, 0x5c, 0x34, 0x4a, 0x1a, 0xca, 0x41, 0xfa, 0xed,
-	0x79, 0xb3, 0x94, 0x42, 0x05, 0xd0, 0x3b, 0x1f, 0x4e, 0x8e, 0xed, 0xb3, 0x83, 0x76, 0x29, 0xdd,
-	0x6c, 0x7e, 0x9f, 0x56, 0xb4, 0x1f, 0xd3, 0x8a, 0xf6, 0x73, 0x5a, 0xd1, 0x3e, 0xbe, 0xec, 0x07,
-	0x7c, 0x30, 0xba, 0xac, 0x79, 0x24, 0xac, 0x0b, 0xaf, 0x4c, 0x7c, 0x4c, 0x17, 0x4f, 0x8c, 0x7a,
-	0xf5, 0x07, 0xcf, 0xe0, 0x65, 0x56, 0x3e, 0x71, 0x2f, 0x7e, 0x05, 0x00, 0x00, 0xff, 0xff, 0x16,
-	0x1c, 0xdd, 0x64, 0x22, 0x05, 0x00, 0x00,
+	// 707 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x54, 0xcb, 0x6e, 0xc2, 0x46,
+	0x14, 0xad, 0x81, 0x80, 0x7d, 0xc1, 0x89, 0x33, 0x24, 0xaa, 0x95, 0x56, 0x90, 0x12, 0x45, 0x8a,
+	0xaa, 0x06, 0x84, 0xdb, 0x45, 0x9b, 0x4d, 0x15, 0x20, 0x69, 0x49, 0x53, 0x12, 0x19, 0x92, 0x45,
+	0x37, 0x96, 0x63, 0x0f, 0x60, 0xc5, 0xf6, 0xb8, 0x33, 0x03, 0x0d, 0x9f, 0xd0, 0xcf, 0xe9, 0x5f,
+	0x74, 0xd9, 0x2f, 0x40, 0x95, 0xbf, 0xa4, 0xf2, 0xd8, 0x3c, 0xf2, 0xa8, 0xd4, 0x55, 0x57, 0xbe,
+	0x73, 0xce, 0xb9, 0x77, 0xee, 0xcb, 0x03, 0x35, 0xc7, 0xf7, 0x70, 0xc8, 0x5b, 0xd1, 0xf3, 0xa4,
+	0xe5, 0x90, 0x70, 0xec, 0xad, 0x3e, 0xcd, 0x88, 0x12, 0x4e, 0x50, 0x31, 0x3d, 0x1d, 0x1d, 0x4c,
+	0xc8, 0x84, 0x08, 0xa8, 0x95, 0x58, 0x29, 0xdb, 0xf8, 0x15, 0x8a, 0x5d, 0xc1, 0xa3, 0x13, 0x28,
+	0xcd, 0x18, 0xa6, 0x96, 0xe7, 0xea, 0xd2, 0xb1, 0x74, 0xa6, 0x74, 0x20, 0x5e, 0xd6, 0x8b, 0x0f,
+	0x0c, 0xd3, 0x7e, 0xcf, 0x2c, 0x26, 0x54, 0xdf, 0x45, 0xc7, 0x90, 0x9b, 0xb7, 0xf5, 0xdc, 0xb1,
+	0x74, 0x56, 0x36, 0xb4, 0x66, 0x76, 0x4f, 0x1a, 0xe0, 0xb1, 0x6d, 0xe6, 0xe6, 0x6d, 0xa1, 0x30,
+	0xf4, 0xfc, 0x87, 0x0a, 0xc3, 0xcc, 0xcd, 0x8d, 0xc6, 0x1f, 0x12, 0xc8, 0x2b, 0x17, 0x74, 0x02,
+	0x6a, 0x64, 0x3b, 0x53, 0xd7, 0xb2, 0x5d, 0x97, 0x62, 0xc6, 0x44, 0x6c, 0xc5, 0xac, 0x08, 0xf0,
+	0x32, 0xc5, 0xd0, 0x57, 0x00, 0x0c, 0xd3, 0x39, 0xa6, 0x96, 0x63, 0x33, 0x11, 0x5b, 0xe9, 0xa8,
+	0xf1, 0xb2, 0xae, 0x0c, 0x05, 0xda, 0xbd, 0x64, 0xa6, 0x92, 0x0a, 0xba, 0x36, 0x4b, 0x42, 0x32,
+	0xcc, 0x98, 0x47, 0x42, 0x8b, 0x93, 0x67, 0x1c, 0xa6, 0xe5, 0x98, 0x95, 0x0c, 0x1c, 0x25, 0x18,
+	0x3a, 0x07, 0x64, 0x3b, 0xdc, 0x9b, 0x63, 0x8b, 0x53, 0x3b, 0x64, 0x89, 0x4d, 0x42, 0xbd, 0x20,
+	0x94, 0xfb, 0x29, 0x33, 0xda, 0x10, 0x8d, 0xdf, 0x73, 0xeb, 0x9c, 0x0d, 0x74, 0x0a, 0xbb, 0x99,
+	0xaf, 0x43, 0x42, 0x8e, 0x5f, 0x78, 0x76, 0x83, 0x9a, 0xa2, 0xdd, 0x14, 0x44, 0x17, 0x20, 0x67,
+	0x7c, 0x52, 0x55, 0xfe, 0xac, 0x6c, 0xd4, 0xde, 0xf6, 0xa3, 0x99, 0x69, 0xd9, 0x55, 0xc8, 0xe9,
+	0xc2, 0x5c, 0xeb, 0x91, 0x0e, 0xa5, 0x00, 0x73, 0xea, 0x39, 0x69, 0xb9, 0xb2, 0xb9, 0x3a, 0x22,
+	0x03, 0x0e, 0x03, 0xfb, 0xc5, 0x62, 0x53, 0xec, 0xfb, 0x96, 0x43, 0x82, 0xc8, 0xc7, 0x49, 0x86,
+	0x4c, 0xe4, 0x9e, 0x37, 0xab, 0x81, 0xfd, 0x32, 0x4c, 0xb8, 0xee, 0x86, 0x3a, 0xba, 0x05, 0xf5,
+	0xd5, 0x45, 0x48, 0x83, 0xfc, 0x33, 0x5e, 0x64, 0x69, 0x27, 0x26, 0x3a, 0x85, 0x9d, 0xb9, 0xed,
+	0xcf, 0x70, 0x36, 0xdb, 0xbd, 0xad, 0x4c, 0x13, 0x3f, 0x33, 0x65, 0x2f, 0x72, 0xdf, 0x4a, 0x8d,
+	0xb8, 0x00, 0xa5, 0x55, 0x8d, 0xe7, 0x50, 0x64, 0x64, 0x46, 0x1d, 0x2c, 0x62, 0xed, 0x1a, 0x87,
+	0x6f, 0xfc, 0x86, 0x82, 0x34, 0x33, 0xd1, 0xff, 0x32, 0xed, 0xc2, 0x7f, 0x9e, 0xf6, 0xce, 0xbf,
+	0x4c, 0x1b, 0x7d, 0x01, 0x15, 0xc7, 0x9f, 0x31, 0x8e, 0xa9, 0x15, 0xda, 0x01, 0xd6, 0x8b, 0x42,
+	0x58, 0xce, 0xb0, 0x81, 0x1d, 0x60, 0xf4, 0x19, 0x28, 0xf6, 0x8c, 0x4f, 0x2d, 0x2f, 0x1c, 0x13,
+	0xbd, 0x24, 0x78, 0x39, 0x01, 0xfa, 0xe1, 0x98, 0xa0, 0xcf, 0x41, 0x49, 0xfc, 0x58, 0x64, 0x3b,
+	0x58, 0x97, 0x05, 0xb9, 0x01, 0xd0, 0x2d, 0xec, 0x45, 0x84, 0x72, 0x6b, 0x4c, 0xe8, 0x6f, 0x36,
+	0x75, 0x31, 0x65, 0x3a, 0x88, 0xf5, 0x38, 0x79, 0xd3, 0xbc, 0xe6, 0x3d, 0xa1, 0xfc, 0x7a, 0xad,
+	0x4a, 0x77, 0x64, 0x37, 0x7a, 0x05, 0xa2, 0x9f, 0xe0, 0x70, 0x95, 0xab, 0x8b, 0x23, 0x9f, 0x2c,
+	0x02, 0x1c, 0xf2, 0xe4, 0x27, 0x2e, 0x8b, 0xc6, 0x7d, 0x1a, 0x2f, 0xeb, 0xd5, 0x6e, 0x2a, 0xe8,
+	0xad, 0xf9, 0x7e, 0xcf, 0xac, 0x3a, 0xef, 0x40, 0x17, 0x7d, 0x07, 0x7b, 0xdc, 0x67, 0x56, 0xd6,
+	0x7e, 0x51, 0xbb, 0x2a, 0xc2, 0xec, 0xc7, 0xcb, 0xba, 0x3a, 0xba, 0x1d, 0xa6, 0x23, 0x48, 0x3a,
+	0x60, 0xaa, 0xdc, 0x67, 0x9b, 0xe3, 0xd1, 0x25, 0x54, 0x3f, 0x48, 0xf7, 0x83, 0x4d, 0x3b, 0xd8,
+	0xde, 0x34, 0x75, 0x6b, 0xb1, 0x6e, 0x0a, 0xb2, 0xa2, 0xc1, 0x4d, 0x41, 0xae, 0x68, 0xea, 0x97,
+	0xdf, 0x83, 0xfa, 0x6a, 0x85, 0x90, 0x0c, 0x85, 0xc1, 0xdd, 0xe0, 0x4a, 0xfb, 0x04, 0xa9, 0xa0,
+	0x74, 0xef, 0x06, 0xd7, 0xfd, 0x1f, 0xac, 0xc7, 0xb6, 0x26, 0xa1, 0x12, 0xe4, 0x7f, 0x7c, 0xe8,
+	0x68, 0x39, 0x54, 0x01, 0xb9, 0xff, 0xf3, 0xfd, 0x9d, 0x39, 0xba, 0xea, 0x69, 0xf9, 0x4e, 0xe7,
+	0xcf, 0xb8, 0x26, 0xfd, 0x15, 0xd7, 0xa4, 0xbf, 0xe3, 0x9a, 0xf4, 0xcb, 0x37, 0x13, 0x8f, 0x4f,
+	0x67, 0x4f, 0x4d, 0x87, 0x04, 0xad, 0x64, 0xd9, 0x16, 0x2e, 0xa6, 0xdb, 0x16, 0xa3, 0x4e, 0xeb,
+	0xdd, 0x3b, 0xfa, 0x54, 0x14, 0x6f, 0xe4, 0xd7, 0xff, 0x0c, 0x00, 0xc1, 0xe1, 0x75, 0x93, 0x63,
+	0x05, 0x00, 0x00,
 }
 
 func (m *Config) Marshal() (dAtA []byte, err error) {
@@ -692,6 +705,13 @@ func (m *Context) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.TLSServerName) > 0 {
+		i -= len(m.TLSServerName)
+		copy(dAtA[i:], m.TLSServerName)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.TLSServerName)))
+		i--
+		dAtA[i] = 0x6a
+	}
 	if len(m.ClusterDeploymentID) > 0 {
 		i -= len(m.ClusterDeploymentID)
 		copy(dAtA[i:], m.ClusterDeploymentID)
@@ -920,6 +940,10 @@ func (m *Context) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovConfig(uint64(l))
 	}
+	l = len(m.TLSServerName)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1943,6 +1967,38 @@ func (m *Context) Unmarshal(dAtA []byte) error {
 			}
 			m.ClusterDeploymentID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TLSServerName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TLSServerName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
@@ -28,6 +28,14 @@ type Server struct {
 	eg     *errgroup.Group
 }
 
+// Interceptor intercepts the unary and streaming RPCs served by a Server
+// (e.g. to rate limit them). Interceptors run after the Server's tracing
+// interceptors, in the order in which they're passed to NewServer.
+type Interceptor interface {
+	UnaryServerInterceptor() grpc.UnaryServerInterceptor
+	StreamServerInterceptor() grpc.StreamServerInterceptor
+}
+
 // NewServer creates a new gRPC server, but does not start serving yet.
 //
 // If 'publicPortTLSAllowed' is set, grpcutil may enable TLS. This should be
@@ -36,7 +44,13 @@ type Server struct {
 // corresponding private key in 'TLSVolumePath', this will serve GRPC traffic
 // over TLS. If either are missing this will serve GRPC traffic over
 // unencrypted HTTP,
-func NewServer(ctx context.Context, publicPortTLSAllowed bool) (*Server, error) {
+func NewServer(ctx context.Context, publicPortTLSAllowed bool, interceptors ...Interceptor) (*Server, error) {
+	unary := []grpc.UnaryServerInterceptor{tracing.UnaryServerInterceptor()}
+	stream := []grpc.StreamServerInterceptor{tracing.StreamServerInterceptor()}
+	for _, i := range interceptors {
+		unary = append(unary, i.UnaryServerInterceptor())
+		stream = append(stream, i.StreamServerInterceptor())
+	}
 	opts := []grpc.ServerOption{
 		grpc.MaxConcurrentStreams(math.MaxUint32),
 		grpc.MaxRecvMsgSize(MaxMsgSize),
@@ -45,8 +59,8 @@ func NewServer(ctx context.Context, publicPortTLSAllowed bool) (*Server, error)
 			MinTime:             5 * time.Second,
 			PermitWithoutStream: true,
 		}),
-		grpc.UnaryInterceptor(tracing.UnaryServerInterceptor()),
-		grpc.StreamInterceptor(tracing.StreamServerInterceptor()),
+		grpc.UnaryInterceptor(chainUnaryInterceptors(unary)),
+		grpc.StreamInterceptor(chainStreamInterceptors(stream)),
 	}
 
 	var cLoader *tls.CertLoader
@@ -85,6 +99,31 @@ func NewServer(ctx context.Context, publicPortTLSAllowed bool) (*Server, error)
 	}, nil
 }
 
+// chainUnaryInterceptors returns an interceptor that runs 'interceptors' in
+// order (the version of grpc that we use can't chain server interceptors)
+func chainUnaryInterceptors(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	if len(interceptors) == 1 {
+		return interceptors[0]
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return interceptors[0](ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return chainUnaryInterceptors(interceptors[1:])(ctx, req, info, handler)
+		})
+	}
+}
+
+// chainStreamInterceptors is chainUnaryInterceptors for streaming RPCs
+func chainStreamInterceptors(interceptors []grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	if len(interceptors) == 1 {
+		return interceptors[0]
+	}
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return interceptors[0](srv, ss, info, func(srv interface{}, ss grpc.ServerStream) error {
+			return chainStreamInterceptors(interceptors[1:])(srv, ss, info, handler)
+		})
+	}
+}
+
 // ListenTCP causes the gRPC server to listen on a given TCP host and port
 func (s *Server) ListenTCP(host string, port uint16) (net.Listener, error) {
 	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", host, port))
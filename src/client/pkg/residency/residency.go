@@ -0,0 +1,182 @@
+// Package residency implements data residency constraints. A repo may be
+// created with a residency (e.g. "eu"), in which case the contents of its
+// files are stored in the object storage bucket configured for that residency
+// (with STORAGE_RESIDENCY_BUCKETS) instead of the cluster's default bucket,
+// and data may not be copied or egressed from it to repos or destinations
+// outside of that residency. Repos without a residency are unrestricted.
+package residency
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ancestry"
+	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// ContextResidencyKey is the key of the residency in the metadata of an Object
+// API request. Objects put by a request with a residency are stored in that
+// residency's bucket.
+const ContextResidencyKey = "pach-residency"
+
+const (
+	// blockSeparator separates a block's residency from the rest of its hash
+	blockSeparator = "/"
+
+	errViolationMsg = "data residency violation"
+)
+
+// WithContext returns a copy of 'ctx' whose outgoing metadata has the
+// residency 'residency', replacing any residency that it already had. If
+// 'residency' is "", 'ctx' is returned unchanged.
+func WithContext(ctx context.Context, residency string) context.Context {
+	if residency == "" {
+		return ctx
+	}
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	md.Set(ContextResidencyKey, residency)
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// FromContext returns the residency of the Object API request in 'ctx', or ""
+// if it doesn't have one.
+func FromContext(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md[ContextResidencyKey]) > 0 {
+		return md[ContextResidencyKey][len(md[ContextResidencyKey])-1]
+	}
+	return ""
+}
+
+// NewBlock returns a new block in the residency 'residency'. The residency is
+// part of the block's hash, so that readers of the block know which bucket it
+// is stored in.
+func NewBlock(residency string) *pfs.Block {
+	hash := uuid.NewWithoutDashes()
+	if residency != "" {
+		hash = residency + blockSeparator + hash
+	}
+	return &pfs.Block{Hash: hash}
+}
+
+// OfBlock returns the residency of 'block', or "" if it's stored in the
+// default bucket.
+func OfBlock(block *pfs.Block) string {
+	if i := strings.Index(block.Hash, blockSeparator); i >= 0 {
+		return block.Hash[:i]
+	}
+	return ""
+}
+
+// ValidateName returns an error if 'name' can't be used as a residency name.
+// Residency names follow the same rules as repo names.
+func ValidateName(name string) error {
+	if err := ancestry.ValidateName(name); err != nil {
+		return errors.Wrapf(err, "invalid residency name")
+	}
+	return nil
+}
+
+// ParseURLs parses 'spec', a comma-separated list of <residency>=<url> pairs
+// (e.g. "eu=s3://pach-eu,eu=s3://eu-exports"), into the URLs of each
+// residency.
+func ParseURLs(spec string) (map[string][]string, error) {
+	result := make(map[string][]string)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, errors.Errorf("malformed residency URL %q (expected <residency>=<url>)", pair)
+		}
+		if err := ValidateName(parts[0]); err != nil {
+			return nil, err
+		}
+		result[parts[0]] = append(result[parts[0]], parts[1])
+	}
+	return result, nil
+}
+
+// Names returns the sorted names of the residencies in 'urls'.
+func Names(urls map[string][]string) []string {
+	var result []string
+	for name := range urls {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Check returns an error if data from 'src', whose residency is
+// 'srcResidency', may not be written to 'dst', whose residency is
+// 'dstResidency'. Data from repos without a residency may be written anywhere.
+func Check(src, srcResidency, dst, dstResidency string) error {
+	if srcResidency == "" || srcResidency == dstResidency {
+		return nil
+	}
+	return ErrViolation{
+		Source:               src,
+		SourceResidency:      srcResidency,
+		Destination:          dst,
+		DestinationResidency: dstResidency,
+	}
+}
+
+// CheckEgress returns an error if data from 'src', whose residency is
+// 'srcResidency', may not be egressed to 'url'. Data in a residency may only
+// be egressed to URLs under one of 'allowed', the egress URLs configured for
+// that residency.
+func CheckEgress(src, srcResidency, url string, allowed []string) error {
+	if srcResidency == "" {
+		return nil
+	}
+	for _, prefix := range allowed {
+		if strings.HasPrefix(url, strings.TrimSuffix(prefix, "/")+"/") || url == prefix {
+			return nil
+		}
+	}
+	return ErrViolation{
+		Source:          src,
+		SourceResidency: srcResidency,
+		Destination:     url,
+	}
+}
+
+// ErrViolation is returned when a request would copy or egress data from a
+// repo with a residency to a repo or URL outside of that residency.
+type ErrViolation struct {
+	Source               string
+	SourceResidency      string
+	Destination          string
+	DestinationResidency string
+}
+
+func (e ErrViolation) Error() string {
+	dst := "outside of that residency"
+	if e.DestinationResidency != "" {
+		dst = fmt.Sprintf("in residency %q", e.DestinationResidency)
+	}
+	return fmt.Sprintf("%s: data from %q (residency %q) can't be written to %q, which is %s",
+		errViolationMsg, e.Source, e.SourceResidency, e.Destination, dst)
+}
+
+// IsErrViolation returns true if 'err' is an ErrViolation, including one that
+// was returned by pachd (and so lost its type crossing the GRPC boundary).
+func IsErrViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	return strings.Contains(err.Error(), errViolationMsg)
+}
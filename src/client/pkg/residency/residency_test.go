@@ -0,0 +1,55 @@
+package residency
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestContext(t *testing.T) {
+	require.Equal(t, "", FromContext(context.Background()))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), ContextResidencyKey, "us")
+	ctx = WithContext(ctx, "eu")
+	md, _ := metadata.FromOutgoingContext(ctx)
+	require.Equal(t, "eu", FromContext(metadata.NewIncomingContext(context.Background(), md)))
+}
+
+func TestBlock(t *testing.T) {
+	require.Equal(t, "", OfBlock(NewBlock("")))
+	require.Equal(t, "eu", OfBlock(NewBlock("eu")))
+	require.Equal(t, "", OfBlock(&pfs.Block{Hash: "abc"}))
+}
+
+func TestParseURLs(t *testing.T) {
+	urls, err := ParseURLs("eu=s3://pach-eu, eu=s3://eu-exports,us=gs://pach-us")
+	require.NoError(t, err)
+	require.Equal(t, []string{"s3://pach-eu", "s3://eu-exports"}, urls["eu"])
+	require.Equal(t, []string{"eu", "us"}, Names(urls))
+
+	urls, err = ParseURLs("")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(urls))
+
+	_, err = ParseURLs("s3://pach-eu")
+	require.YesError(t, err)
+	_, err = ParseURLs("e/u=s3://pach-eu")
+	require.YesError(t, err)
+}
+
+func TestCheck(t *testing.T) {
+	require.NoError(t, Check("in", "", "out", "eu"))
+	require.NoError(t, Check("in", "eu", "out", "eu"))
+	require.True(t, IsErrViolation(Check("in", "eu", "out", "")))
+	require.True(t, IsErrViolation(Check("in", "eu", "out", "us")))
+
+	allowed := []string{"s3://eu-exports/"}
+	require.NoError(t, CheckEgress("in", "", "s3://anywhere/x", nil))
+	require.NoError(t, CheckEgress("in", "eu", "s3://eu-exports/x", allowed))
+	require.True(t, IsErrViolation(CheckEgress("in", "eu", "s3://eu-exports-copy/x", allowed)))
+	require.True(t, IsErrViolation(CheckEgress("in", "eu", "s3://anywhere/x", nil)))
+}
@@ -0,0 +1,160 @@
+// Package tenant implements tenancy, which lets several teams share one
+// cluster. A tenant is a namespace of repos and pipelines: the repos and
+// pipelines owned by tenant "t" are named "t-<name>", so that their etcd keys
+// (and the object storage keys of their data) all start with the tenant's
+// prefix. Tenancy is tied to auth: an admin gets tokens confined to a tenant
+// (see GetAuthTokenRequest.Tenant), and callers with such a token can only
+// see and modify the tenant's repos and pipelines.
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/residency"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// ContextObjectTenantKey is the key of the tenant in the metadata of an Object
+// API request. Blocks put by a request with a tenant are stored under the
+// tenant's prefix. It only determines where a request's data is stored, not
+// what the request may access (which is always decided by the caller's auth
+// token), so pachd sets it from the tenant of the repo or pipeline that the
+// data belongs to.
+const ContextObjectTenantKey = "pach-object-tenant"
+
+// separator separates a tenant from the rest of a name or object key. Tenant
+// names can't contain it, so a name has at most one tenant.
+const separator = "-"
+
+var validName = regexp.MustCompile("^[a-zA-Z0-9]+$")
+
+// ValidateName returns an error if 'name' can't be used as a tenant name.
+// Tenant names may only contain letters and digits, as they're separated from
+// the names of the tenant's repos and pipelines with a dash.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return errors.Errorf("invalid tenant name %q (tenant names may only contain letters and digits)", name)
+	}
+	return nil
+}
+
+// Prefix returns the prefix of the names of the repos and pipelines owned by
+// the tenant 't'.
+func Prefix(t string) string {
+	return t + separator
+}
+
+// Owns returns true if a caller confined to the tenant 't' may access the repo
+// or pipeline 'name'. Callers that aren't confined to a tenant ('t' is "") may
+// access everything.
+func Owns(t, name string) bool {
+	return t == "" || strings.HasPrefix(name, Prefix(t))
+}
+
+// CheckName returns an error if a caller confined to the tenant 't' may not
+// access (or create) the repo or pipeline 'name'.
+func CheckName(t, name string) error {
+	if !Owns(t, name) {
+		return ErrOtherTenant{Tenant: t, Name: name}
+	}
+	return nil
+}
+
+// FromAuth returns the tenant that the caller in 'ctx' is confined to, or ""
+// if it isn't confined to one. Callers without a token (and all callers when
+// auth isn't activated) aren't confined to a tenant; what they may do is up
+// to the usual auth checks.
+func FromAuth(ctx context.Context, authClient auth.APIClient) (string, error) {
+	me, err := authClient.WhoAmI(ctx, &auth.WhoAmIRequest{})
+	if err != nil {
+		if auth.IsErrNotActivated(err) || auth.IsErrPartiallyActivated(err) ||
+			auth.IsErrNotSignedIn(err) || auth.IsErrNoMetadata(err) {
+			return "", nil
+		}
+		return "", grpcutil.ScrubGRPC(err)
+	}
+	return me.Tenant, nil
+}
+
+// WithObjectContext returns a copy of 'ctx' whose outgoing metadata has the
+// tenant 't', so that the blocks put with it are stored under the tenant's
+// prefix. If 't' is "", 'ctx' is returned unchanged.
+func WithObjectContext(ctx context.Context, t string) context.Context {
+	if t == "" {
+		return ctx
+	}
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	md.Set(ContextObjectTenantKey, t)
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// FromObjectContext returns the tenant of the Object API request in 'ctx', or
+// "" if it doesn't have one.
+func FromObjectContext(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md[ContextObjectTenantKey]) > 0 {
+		t := md[ContextObjectTenantKey][len(md[ContextObjectTenantKey])-1]
+		if ValidateName(t) == nil {
+			return t
+		}
+	}
+	return ""
+}
+
+// NewBlock returns a new block in the residency 'r' whose object key starts
+// with the prefix of the tenant 't' (if 't' isn't ""). The residency stays at
+// the start of the block's hash, so residency.OfBlock works on it as usual.
+func NewBlock(r, t string) *pfs.Block {
+	block := residency.NewBlock(r)
+	if t != "" {
+		dir, hash := path.Split(block.Hash)
+		block.Hash = dir + Prefix(t) + hash
+	}
+	return block
+}
+
+// OfBlock returns the tenant whose prefix the object key of 'block' starts
+// with, or "" if it has none.
+func OfBlock(block *pfs.Block) string {
+	_, hash := path.Split(block.Hash)
+	if i := strings.Index(hash, separator); i >= 0 && ValidateName(hash[:i]) == nil {
+		return hash[:i]
+	}
+	return ""
+}
+
+// ErrQuotaExceeded is returned when a tenant tries to create more repos or
+// pipelines than the cluster allows each tenant to have.
+type ErrQuotaExceeded struct {
+	Tenant   string
+	Resource string
+	Limit    int
+}
+
+func (e ErrQuotaExceeded) Error() string {
+	return fmt.Sprintf("tenant %q already has the maximum number of %s (%d)", e.Tenant, e.Resource, e.Limit)
+}
+
+// ErrOtherTenant is returned when a caller confined to one tenant tries to
+// access a repo or pipeline that the tenant doesn't own.
+type ErrOtherTenant struct {
+	Tenant string
+	Name   string
+}
+
+func (e ErrOtherTenant) Error() string {
+	return fmt.Sprintf("%q is not owned by tenant %q (its repos and pipelines must be named %q)", e.Name, e.Tenant, Prefix(e.Tenant)+"...")
+}
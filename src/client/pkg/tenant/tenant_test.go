@@ -0,0 +1,57 @@
+package tenant
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pkg/residency"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestValidateName(t *testing.T) {
+	require.NoError(t, ValidateName("teamA1"))
+	require.YesError(t, ValidateName("team-a"))
+	require.YesError(t, ValidateName("team/a"))
+	require.YesError(t, ValidateName(""))
+}
+
+func TestOwns(t *testing.T) {
+	require.True(t, Owns("", "images"))
+	require.True(t, Owns("acme", "acme-images"))
+	require.False(t, Owns("acme", "images"))
+	require.False(t, Owns("acme", "acmeimages"))
+	require.False(t, Owns("acme", "acme2-images"))
+	require.NoError(t, CheckName("acme", "acme-images"))
+	require.YesError(t, CheckName("acme", "other-images"))
+}
+
+func TestFromObjectContext(t *testing.T) {
+	require.Equal(t, "", FromObjectContext(context.Background()))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ContextObjectTenantKey, "acme"))
+	require.Equal(t, "acme", FromObjectContext(ctx))
+
+	// Malformed tenants are ignored rather than put in object keys
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(ContextObjectTenantKey, "../acme"))
+	require.Equal(t, "", FromObjectContext(ctx))
+}
+
+func TestNewBlock(t *testing.T) {
+	block := NewBlock("", "acme")
+	require.True(t, strings.HasPrefix(block.Hash, "acme-"))
+	require.Equal(t, "", residency.OfBlock(block))
+	require.Equal(t, "acme", OfBlock(block))
+
+	block = NewBlock("eu", "acme")
+	require.True(t, strings.HasPrefix(block.Hash, "eu/acme-"))
+	require.Equal(t, "eu", residency.OfBlock(block))
+	require.Equal(t, "acme", OfBlock(block))
+
+	block = NewBlock("eu", "")
+	require.Equal(t, "eu", residency.OfBlock(block))
+	require.Equal(t, "", OfBlock(block))
+	require.False(t, strings.Contains(block.Hash, "-"))
+}
@@ -36,9 +36,20 @@ const (
 	PPSJobIDEnv = "PPS_JOB_ID"
 	// PPSSpecCommitEnv is the namespace in which pachyderm is deployed
 	PPSSpecCommitEnv = "PPS_SPEC_COMMIT"
+	// PPSEscalatedMemoryEnv is the env var that sets the memory limit of
+	// workers whose memory was escalated to retry OOM-killed datums (see
+	// pps.OOMRetry).
+	PPSEscalatedMemoryEnv = "PPS_ESCALATED_MEMORY"
+	// PPSWorkerClassEnv is the env var that sets the worker class (see
+	// pps.DatumSizing) of workers that aren't the pipeline's own workers.
+	PPSWorkerClassEnv = "PPS_WORKER_CLASS"
 	// PPSInputPrefix is the prefix of the path where datums are downloaded
 	// to.  A datum of an input named `XXX` is downloaded to `/pfs/XXX/`.
 	PPSInputPrefix = "/pfs"
+	// PPSEgressSecretPath is where the kubernetes secret named in a
+	// pipeline's egress credentials (see pps.EgressCredentials) is mounted in
+	// its workers.
+	PPSEgressSecretPath = "/pachyderm-egress-secret"
 	// PPSScratchSpace is where pps workers store data while it's waiting to be
 	// processed.
 	PPSScratchSpace = ".scratch"
@@ -63,6 +74,27 @@ const (
 	// OutputCommitIDEnv is an env var that is added to the environment of user
 	// pipelined code and indicates the id of the output commit.
 	OutputCommitIDEnv = "PACH_OUTPUT_COMMIT_ID"
+	// RuntimeConfigEnv is an env var that is added to the environment of user
+	// pipeline code and contains the pipeline's runtime config, as JSON.
+	RuntimeConfigEnv = "PACH_RUNTIME_CONFIG"
+	// RuntimeConfigFileEnv is an env var that is added to the environment of
+	// user pipeline code and indicates the path of a file that contains the
+	// pipeline's runtime config, as JSON. Unlike RuntimeConfigEnv, the file is
+	// rewritten whenever the config is updated, so long-running user code
+	// (e.g. services and spouts) can re-read it.
+	RuntimeConfigFileEnv = "PACH_RUNTIME_CONFIG_FILE"
+	// DatumSeedEnv is an env var that is added to the environment of user
+	// pipeline code and contains a seed for random number generators. It's
+	// derived from the datum's ID and the job's seed salt, so it's the same
+	// whenever the datum is reprocessed by a job with the same seed salt.
+	DatumSeedEnv = "PACH_DATUM_SEED"
+	// SeedSaltMetadataKey is the key of the metadata that RunPipeline sets on
+	// the output commit it starts, if the new job's seed salt was overridden.
+	SeedSaltMetadataKey = "pach.seed_salt"
+	// RoutedCommitMetadataKey is the key of the metadata that's set on the
+	// commits of a pipeline's output route branches, and contains the ID of the
+	// job output commit whose routed output the commit contains.
+	RoutedCommitMetadataKey = "pach.routed_commit"
 	// PeerPortEnv is the env var that sets a custom peer port
 	PeerPortEnv = "PEER_PORT"
 )
@@ -214,6 +246,7 @@ func (c APIClient) CreateJob(pipeline string, outputCommit, statsCommit *pfs.Com
 
 // InspectJob returns info about a specific job.
 // blockState will cause the call to block until the job reaches a terminal state (failure or success).
+// A blocking call resumes if pachd restarts (see WithBlockingRetryBudget).
 // full indicates that the full job info should be returned.
 func (c APIClient) InspectJob(jobID string, blockState bool, full ...bool) (*pps.JobInfo, error) {
 	req := &pps.InspectJobRequest{
@@ -223,19 +256,32 @@ func (c APIClient) InspectJob(jobID string, blockState bool, full ...bool) (*pps
 	if len(full) > 0 {
 		req.Full = full[0]
 	}
-	jobInfo, err := c.PpsAPIClient.InspectJob(c.Ctx(), req)
-	return jobInfo, grpcutil.ScrubGRPC(err)
+	return c.inspectJob(req)
 }
 
 // InspectJobOutputCommit returns info about a job that created a commit.
 // blockState will cause the call to block until the job reaches a terminal state (failure or success).
+// A blocking call resumes if pachd restarts (see WithBlockingRetryBudget).
 func (c APIClient) InspectJobOutputCommit(repoName, commitID string, blockState bool) (*pps.JobInfo, error) {
-	jobInfo, err := c.PpsAPIClient.InspectJob(
-		c.Ctx(),
-		&pps.InspectJobRequest{
-			OutputCommit: NewCommit(repoName, commitID),
-			BlockState:   blockState,
-		})
+	return c.inspectJob(&pps.InspectJobRequest{
+		OutputCommit: NewCommit(repoName, commitID),
+		BlockState:   blockState,
+	})
+}
+
+func (c APIClient) inspectJob(req *pps.InspectJobRequest) (*pps.JobInfo, error) {
+	if !req.BlockState {
+		jobInfo, err := c.PpsAPIClient.InspectJob(c.Ctx(), req)
+		return jobInfo, grpcutil.ScrubGRPC(err)
+	}
+	// Waiting for the job is stateless on pachd's side, so it's resumed by
+	// making the same request again
+	var jobInfo *pps.JobInfo
+	err := c.retryBlocking(func(func()) error {
+		var err error
+		jobInfo, err = c.PpsAPIClient.InspectJob(c.Ctx(), req)
+		return err
+	})
 	return jobInfo, grpcutil.ScrubGRPC(err)
 }
 
@@ -291,20 +337,35 @@ func (c APIClient) ListJobF(pipelineName string, inputCommit []*pfs.Commit,
 func (c APIClient) ListJobFilterF(pipelineName string, inputCommit []*pfs.Commit,
 	outputCommit *pfs.Commit, history int64, includePipelineInfo bool, jqFilter string,
 	f func(*pps.JobInfo) error) error {
+	return c.ListJobFailureCauseF(pipelineName, inputCommit, outputCommit, history, includePipelineInfo, jqFilter, nil, f)
+}
+
+// ListJobFailureCauseF is like ListJobFilterF, but if 'failureCauses' is
+// non-empty, only jobs that failed with one of 'failureCauses' are returned.
+func (c APIClient) ListJobFailureCauseF(pipelineName string, inputCommit []*pfs.Commit,
+	outputCommit *pfs.Commit, history int64, includePipelineInfo bool, jqFilter string,
+	failureCauses []pps.FailureCause, f func(*pps.JobInfo) error) error {
 	var pipeline *pps.Pipeline
 	if pipelineName != "" {
 		pipeline = NewPipeline(pipelineName)
 	}
-	client, err := c.PpsAPIClient.ListJobStream(
-		c.Ctx(),
-		&pps.ListJobRequest{
-			Pipeline:     pipeline,
-			InputCommit:  inputCommit,
-			OutputCommit: outputCommit,
-			History:      history,
-			Full:         includePipelineInfo,
-			JqFilter:     jqFilter,
-		})
+	return c.ListJobRequestF(&pps.ListJobRequest{
+		Pipeline:     pipeline,
+		InputCommit:  inputCommit,
+		OutputCommit: outputCommit,
+		History:      history,
+		Full:         includePipelineInfo,
+		JqFilter:     jqFilter,
+		FailureCause: failureCauses,
+	}, f)
+}
+
+// ListJobRequestF returns info about the jobs that match 'request', newest
+// first, calling f with each JobInfo. It accepts all of ListJobRequest's
+// filters, e.g. job states and creation time ranges. If f returns
+// errutil.ErrBreak, iteration stops and ListJobRequestF returns nil.
+func (c APIClient) ListJobRequestF(request *pps.ListJobRequest, f func(*pps.JobInfo) error) error {
+	client, err := c.PpsAPIClient.ListJobStream(c.Ctx(), request)
 	if err != nil {
 		return grpcutil.ScrubGRPC(err)
 	}
@@ -324,9 +385,23 @@ func (c APIClient) ListJobFilterF(pipelineName string, inputCommit []*pfs.Commit
 	}
 }
 
+// ListJobPage returns a page of at most request.PageSize jobs that match
+// 'request', newest first, and the token of the next page, which is empty if
+// this is the last page. Pass the token as request.PageToken to get the next
+// page.
+func (c APIClient) ListJobPage(request *pps.ListJobRequest) ([]*pps.JobInfo, string, error) {
+	jobInfos, err := c.PpsAPIClient.ListJob(c.Ctx(), request)
+	if err != nil {
+		return nil, "", grpcutil.ScrubGRPC(err)
+	}
+	return jobInfos.JobInfo, jobInfos.NextPageToken, nil
+}
+
 // FlushJob calls f with all the jobs which were triggered by commits.
 // If toPipelines is non-nil then only the jobs between commits and those
 // pipelines in the DAG will be returned.
+// FlushJob resumes if pachd restarts (see WithBlockingRetryBudget), and f is
+// called only once for each job.
 func (c APIClient) FlushJob(commits []*pfs.Commit, toPipelines []string, f func(*pps.JobInfo) error) error {
 	req := &pps.FlushJobRequest{
 		Commits: commits,
@@ -334,22 +409,32 @@ func (c APIClient) FlushJob(commits []*pfs.Commit, toPipelines []string, f func(
 	for _, pipeline := range toPipelines {
 		req.ToPipelines = append(req.ToPipelines, NewPipeline(pipeline))
 	}
-	client, err := c.PpsAPIClient.FlushJob(c.Ctx(), req)
-	if err != nil {
-		return grpcutil.ScrubGRPC(err)
-	}
-	for {
-		jobInfo, err := client.Recv()
+	var cbErr error
+	if err := c.retryBlocking(func(renew func()) error {
+		client, err := c.PpsAPIClient.FlushJob(c.Ctx(), req)
 		if err != nil {
-			if errors.Is(err, io.EOF) {
+			return err
+		}
+		for {
+			jobInfo, err := client.Recv()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			renew()
+			// If the stream breaks, the jobs that were already received are
+			// skipped when it's resumed
+			req.SkipJobs = append(req.SkipJobs, jobInfo.Job)
+			if cbErr = f(jobInfo); cbErr != nil {
 				return nil
 			}
-			return grpcutil.ScrubGRPC(err)
-		}
-		if err := f(jobInfo); err != nil {
-			return err
 		}
+	}); err != nil {
+		return grpcutil.ScrubGRPC(err)
 	}
+	return cbErr
 }
 
 // FlushJobAll returns all the jobs which were triggered by commits.
@@ -388,6 +473,19 @@ func (c APIClient) StopJob(jobID string) error {
 	return grpcutil.ScrubGRPC(err)
 }
 
+// ScaleJob changes the number of workers that are running a job, without
+// restarting it.
+func (c APIClient) ScaleJob(jobID string, workers uint64) error {
+	_, err := c.PpsAPIClient.ScaleJob(
+		c.Ctx(),
+		&pps.ScaleJobRequest{
+			Job:     NewJob(jobID),
+			Workers: workers,
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
 // RestartDatum restarts a datum that's being processed as part of a job.
 // datumFilter is a slice of strings which are matched against either the Path
 // or Hash of the datum, the order of the strings in datumFilter is irrelevant.
@@ -480,6 +578,42 @@ func (c APIClient) InspectDatum(jobID string, datumID string) (*pps.DatumInfo, e
 	return datumInfo, nil
 }
 
+// ResolveDatum returns the datums in the job 'jobID' that contain each of the
+// input files in 'paths'. The result contains one ResolvedPath per path, in the
+// same order as 'paths'.
+func (c APIClient) ResolveDatum(jobID string, paths ...string) ([]*pps.ResolvedPath, error) {
+	resp, err := c.PpsAPIClient.ResolveDatum(
+		c.Ctx(),
+		&pps.ResolveDatumRequest{
+			Job:   NewJob(jobID),
+			Paths: paths,
+		},
+	)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return resp.Resolved, nil
+}
+
+// ExplainDatum returns why the datum 'datumID' in the job 'jobID' was or
+// wasn't skipped: the values its hash is computed from, the matching datum in
+// the pipeline's previous job (if any) and human-readable reasons.
+func (c APIClient) ExplainDatum(jobID string, datumID string) (*pps.ExplainDatumResponse, error) {
+	resp, err := c.PpsAPIClient.ExplainDatum(
+		c.Ctx(),
+		&pps.ExplainDatumRequest{
+			Datum: &pps.Datum{
+				ID:  datumID,
+				Job: NewJob(jobID),
+			},
+		},
+	)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return resp, nil
+}
+
 // LogsIter iterates through log messages returned from pps.GetLogs. Logs can
 // be fetched with 'Next()'. The log message received can be examined with
 // 'Message()', and any errors can be examined with 'Err()'.
@@ -525,11 +659,32 @@ func (c APIClient) GetLogs(
 	master bool,
 	follow bool,
 	tail int64,
+) *LogsIter {
+	return c.GetLogsInRange(pipelineName, jobID, data, datumID, master, follow, tail, time.Time{}, time.Time{}, false)
+}
+
+// GetLogsInRange is identical to GetLogs, except that it only returns logs
+// written at or after 'since' and before 'until' (either of which may be the
+// zero time, to leave that end of the range open). If 'fromStart' is true, logs
+// are returned from the start of the job 'jobID' instead of from 'since', which
+// combined with 'follow' follows a running job's logs from its beginning.
+func (c APIClient) GetLogsInRange(
+	pipelineName string,
+	jobID string,
+	data []string,
+	datumID string,
+	master bool,
+	follow bool,
+	tail int64,
+	since time.Time,
+	until time.Time,
+	fromStart bool,
 ) *LogsIter {
 	request := pps.GetLogsRequest{
-		Master: master,
-		Follow: follow,
-		Tail:   tail,
+		Master:    master,
+		Follow:    follow,
+		Tail:      tail,
+		FromStart: fromStart,
 	}
 	resp := &LogsIter{}
 	if pipelineName != "" {
@@ -545,6 +700,16 @@ func (c APIClient) GetLogs(
 			ID:  datumID,
 		}
 	}
+	if !since.IsZero() {
+		if request.Since, resp.err = types.TimestampProto(since); resp.err != nil {
+			return resp
+		}
+	}
+	if !until.IsZero() {
+		if request.Until, resp.err = types.TimestampProto(until); resp.err != nil {
+			return resp
+		}
+	}
 	resp.logsClient, resp.err = c.PpsAPIClient.GetLogs(c.Ctx(), &request)
 	resp.err = grpcutil.ScrubGRPC(resp.err)
 	return resp
@@ -658,6 +823,30 @@ func (c APIClient) ListPipeline() ([]*pps.PipelineInfo, error) {
 	return pipelineInfos.PipelineInfo, nil
 }
 
+// WatchPipelines calls 'f' with a PUT event for each existing pipeline, then a
+// SYNCED event, and then an event for each change to the set of pipelines
+// (including changes to their state). It keeps watching until 'f' returns an
+// error. If 'f' returns errutil.ErrBreak, WatchPipelines returns nil. Most
+// callers should use a PipelineCache instead.
+func (c APIClient) WatchPipelines(f func(*pps.PipelineEvent) error) error {
+	stream, err := c.PpsAPIClient.WatchPipelines(c.Ctx(), &pps.WatchPipelinesRequest{})
+	if err != nil {
+		return grpcutil.ScrubGRPC(err)
+	}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return grpcutil.ScrubGRPC(err)
+		}
+		if err := f(event); err != nil {
+			if errors.Is(err, errutil.ErrBreak) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
 // ListPipelineHistory returns historical information about pipelines.
 // `pipeline` specifies which pipeline to return history about, if it's equal
 // to "" then ListPipelineHistory returns historical information about all
@@ -697,6 +886,21 @@ func (c APIClient) DeletePipeline(name string, force bool) error {
 	return grpcutil.ScrubGRPC(err)
 }
 
+// DeleteAllPipelines deletes every pipeline, along with its output repo and
+// jobs, but leaves the cluster's other repos alone. Unlike DeleteAll, it isn't
+// constrained by the cluster's DeleteAllPolicy, as the caller must be
+// authorized to delete each pipeline.
+func (c APIClient) DeleteAllPipelines() error {
+	_, err := c.PpsAPIClient.DeletePipeline(
+		c.Ctx(),
+		&pps.DeletePipelineRequest{
+			All:   true,
+			Force: true,
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
 // StartPipeline restarts a stopped pipeline.
 func (c APIClient) StartPipeline(name string) error {
 	_, err := c.PpsAPIClient.StartPipeline(
@@ -723,12 +927,21 @@ func (c APIClient) StopPipeline(name string) error {
 // RunPipeline runs a pipeline. It can be passed a list of commit provenance.
 // This will trigger a new job provenant on those commits, effectively running the pipeline on the data in those commits.
 func (c APIClient) RunPipeline(name string, provenance []*pfs.CommitProvenance, jobID string) error {
+	return c.RunPipelineWithSeedSalt(name, provenance, jobID, "")
+}
+
+// RunPipelineWithSeedSalt is like RunPipeline, but the seeds of the new job's
+// datums (see DatumSeedEnv) are derived from 'seedSalt' instead of the
+// pipeline's salt. If 'seedSalt' is empty and 'jobID' is set, the new job's
+// datums get the same seeds as the datums of 'jobID'.
+func (c APIClient) RunPipelineWithSeedSalt(name string, provenance []*pfs.CommitProvenance, jobID string, seedSalt string) error {
 	_, err := c.PpsAPIClient.RunPipeline(
 		c.Ctx(),
 		&pps.RunPipelineRequest{
 			Pipeline:   NewPipeline(name),
 			Provenance: provenance,
 			JobID:      jobID,
+			SeedSalt:   seedSalt,
 		},
 	)
 	return grpcutil.ScrubGRPC(err)
@@ -746,6 +959,181 @@ func (c APIClient) RunCron(name string) error {
 	return grpcutil.ScrubGRPC(err)
 }
 
+// ApplyDAG makes the cluster's repos and pipelines match 'request', and
+// returns the changes that it made (or, if request.DryRun is set, the changes
+// that it would make).
+func (c APIClient) ApplyDAG(request *pps.ApplyDAGRequest) ([]*pps.DAGChange, error) {
+	response, err := c.PpsAPIClient.ApplyDAG(c.Ctx(), request)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return response.Changes, nil
+}
+
+// CreatePipelines creates (or, if 'update' is set, updates) the pipelines in
+// 'requests', each after the pipelines in 'requests' that it reads from. If
+// any of them can't be created, the pipelines that were already created are
+// rolled back. It returns the pipelines in the order they were created.
+func (c APIClient) CreatePipelines(requests []*pps.CreatePipelineRequest, update bool, reprocess bool) ([]*pps.Pipeline, error) {
+	response, err := c.PpsAPIClient.CreatePipelines(c.Ctx(), &pps.CreatePipelinesRequest{
+		Pipelines: requests,
+		Update:    update,
+		Reprocess: reprocess,
+	})
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return response.Pipelines, nil
+}
+
+// PruneStats drops the detailed stats of the jobs of 'pipeline' (or, if it's
+// empty, of all pipelines) that exceed their pipeline's stats retention
+// policy, and returns them. If 'dryRun' is true, the stats that would be
+// pruned are returned, but nothing is pruned.
+func (c APIClient) PruneStats(pipeline string, dryRun bool) ([]*pps.PrunedStats, error) {
+	request := &pps.PruneStatsRequest{DryRun: dryRun}
+	if pipeline != "" {
+		request.Pipeline = NewPipeline(pipeline)
+	}
+	response, err := c.PpsAPIClient.PruneStats(c.Ctx(), request)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return response.Pruned, nil
+}
+
+// ListIdlePipelines returns the pipelines that the idle reaper has flagged,
+// or would stop or flag within 'within' (which may be 0), soonest first.
+func (c APIClient) ListIdlePipelines(within time.Duration) ([]*pps.IdlePipeline, error) {
+	response, err := c.PpsAPIClient.ListIdlePipelines(
+		c.Ctx(),
+		&pps.ListIdlePipelinesRequest{Within: types.DurationProto(within)},
+	)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return response.Pipelines, nil
+}
+
+// CheckPipelineUpdate returns the ways in which applying 'request' (an update
+// to an existing pipeline) may break the pipelines downstream of it, without
+// applying it. It returns nothing if the pipeline doesn't exist yet.
+func (c APIClient) CheckPipelineUpdate(request *pps.CreatePipelineRequest) ([]*pps.UpdateIncompatibility, error) {
+	response, err := c.PpsAPIClient.CheckPipelineUpdate(
+		c.Ctx(),
+		&pps.CheckPipelineUpdateRequest{Update: request},
+	)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return response.Incompatibilities, nil
+}
+
+// DiffPipeline compares versions 'versionA' and 'versionB' of a pipeline's
+// spec. If 'versionB' is 0, it's the current version, and if 'versionA' is 0,
+// it's the version before 'versionB'.
+func (c APIClient) DiffPipeline(pipelineName string, versionA, versionB uint64) (*pps.DiffPipelineResponse, error) {
+	response, err := c.PpsAPIClient.DiffPipeline(
+		c.Ctx(),
+		&pps.DiffPipelineRequest{
+			Pipeline: NewPipeline(pipelineName),
+			VersionA: versionA,
+			VersionB: versionB,
+		},
+	)
+	return response, grpcutil.ScrubGRPC(err)
+}
+
+// GetMountCredentials returns short-lived credentials for mounting the commits
+// in 'targets' and the output commit of 'jobID' (which may be empty) read-only
+// in an in-cluster service. The credentials can read every commit of those
+// commits' repos. 'ttl' may be 0, in
+// which case the credentials are valid for an hour.
+func (c APIClient) GetMountCredentials(targets []*pps.MountTarget, jobID string, ttl time.Duration) (*pps.MountCredentials, error) {
+	request := &pps.GetMountCredentialsRequest{Targets: targets}
+	if jobID != "" {
+		request.Job = NewJob(jobID)
+	}
+	if ttl != 0 {
+		request.TTL = types.DurationProto(ttl)
+	}
+	credentials, err := c.PpsAPIClient.GetMountCredentials(c.Ctx(), request)
+	return credentials, grpcutil.ScrubGRPC(err)
+}
+
+// GetScheduler returns the PPS master's view of the unfinished jobs and the
+// workers of 'pipeline', or, if 'pipeline' is empty, of every pipeline that
+// has unfinished jobs or whose workers have problems.
+func (c APIClient) GetScheduler(pipeline string) ([]*pps.PipelineSchedule, error) {
+	request := &pps.GetSchedulerRequest{}
+	if pipeline != "" {
+		request.Pipeline = NewPipeline(pipeline)
+	}
+	response, err := c.PpsAPIClient.GetScheduler(c.Ctx(), request)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return response.Pipelines, nil
+}
+
+// ApproveCommit moves the output branch of the gated pipeline whose output
+// repo is 'repoName' to the pending output commit 'commitID', so that it's
+// processed by downstream pipelines.
+func (c APIClient) ApproveCommit(repoName string, commitID string) error {
+	_, err := c.PpsAPIClient.ApproveCommit(
+		c.Ctx(),
+		&pps.ApproveCommitRequest{
+			Commit: NewCommit(repoName, commitID),
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
+// UpdatePipelineConfig replaces the runtime config of the pipeline
+// 'pipelineName' with 'config'. The pipeline's workers pick up the new config
+// without being restarted.
+func (c APIClient) UpdatePipelineConfig(pipelineName string, config map[string]string) error {
+	_, err := c.PpsAPIClient.UpdatePipelineConfig(
+		c.Ctx(),
+		&pps.UpdatePipelineConfigRequest{
+			Pipeline:      NewPipeline(pipelineName),
+			RuntimeConfig: config,
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
+// ListGitHooks returns the pipelines' git inputs, each with its most recent
+// webhook delivery, and the recent deliveries that didn't match any git
+// input.
+func (c APIClient) ListGitHooks() (*pps.GitHookInfos, error) {
+	response, err := c.PpsAPIClient.ListGitHooks(c.Ctx(), &pps.ListGitHooksRequest{})
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return response, nil
+}
+
+// InspectGitHook returns the git input named 'name' and its recent webhook
+// deliveries.
+func (c APIClient) InspectGitHook(name string) (*pps.GitHookInfo, error) {
+	hookInfo, err := c.PpsAPIClient.InspectGitHook(
+		c.Ctx(),
+		&pps.InspectGitHookRequest{Name: name},
+	)
+	return hookInfo, grpcutil.ScrubGRPC(err)
+}
+
+// ReplayGitHookDelivery commits the push of the recorded webhook delivery
+// 'id' to its git inputs again, and returns the new delivery.
+func (c APIClient) ReplayGitHookDelivery(id string) (*pps.GitHookDelivery, error) {
+	delivery, err := c.PpsAPIClient.ReplayGitHookDelivery(
+		c.Ctx(),
+		&pps.ReplayGitHookDeliveryRequest{ID: id},
+	)
+	return delivery, grpcutil.ScrubGRPC(err)
+}
+
 // CreateSecret creates a secret on the cluster.
 func (c APIClient) CreateSecret(file []byte) error {
 	_, err := c.PpsAPIClient.CreateSecret(
@@ -15,6 +15,7 @@ var (
 	// format strings for state name parsing errors
 	errInvalidJobStateName      string
 	errInvalidPipelineStateName string
+	errInvalidFailureCauseName  string
 )
 
 func init() {
@@ -29,6 +30,11 @@ func init() {
 		states = append(states, strings.ToLower(strings.TrimPrefix(PipelineState_name[i], "PIPELINE_")))
 	}
 	errInvalidPipelineStateName = fmt.Sprintf("state %%s must be one of %s, or %s, etc", strings.Join(states, ", "), PipelineState_name[0])
+	var causes []string
+	for i := int32(0); FailureCause_name[i] != ""; i++ {
+		causes = append(causes, strings.ToLower(strings.TrimPrefix(FailureCause_name[i], "FAILURE_")))
+	}
+	errInvalidFailureCauseName = fmt.Sprintf("failure cause %%s must be one of %s, or %s, etc", strings.Join(causes, ", "), FailureCause_name[0])
 }
 
 // VisitInput visits each input recursively in ascending order (root last)
@@ -124,6 +130,30 @@ func InputBranches(input *Input) []*pfs.Branch {
 				Name: input.Git.Branch,
 			})
 		}
+		if input.HTTPMirror != nil {
+			result = append(result, &pfs.Branch{
+				Repo: &pfs.Repo{Name: input.HTTPMirror.Repo},
+				Name: "master",
+			})
+		}
+		if input.Parameter != nil {
+			result = append(result, &pfs.Branch{
+				Repo: &pfs.Repo{Name: input.Parameter.Repo},
+				Name: "master",
+			})
+		}
+		if input.RemoteRepo != nil {
+			result = append(result, &pfs.Branch{
+				Repo: &pfs.Repo{Name: input.RemoteRepo.Repo},
+				Name: "master",
+			})
+		}
+		if input.DBImport != nil {
+			result = append(result, &pfs.Branch{
+				Repo: &pfs.Repo{Name: input.DBImport.Repo},
+				Name: "master",
+			})
+		}
 	})
 	return result
 }
@@ -180,3 +210,14 @@ func PipelineStateFromName(name string) (PipelineState, error) {
 	}
 	return 0, fmt.Errorf(errInvalidPipelineStateName, name)
 }
+
+// FailureCauseFromName attempts to interpret a string as a FailureCause,
+// accepting either the enum names or the names without the FAILURE_ prefix
+// (e.g. "oom_killed")
+func FailureCauseFromName(name string) (FailureCause, error) {
+	canonical := "FAILURE_" + strings.TrimPrefix(strings.ToUpper(name), "FAILURE_")
+	if value, ok := FailureCause_value[canonical]; ok {
+		return FailureCause(value), nil
+	}
+	return 0, fmt.Errorf(errInvalidFailureCauseName, name)
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
+)
+
+// maxRebaseAttempts is the number of times that CommitWithRebase tries to
+// commit before giving up
+const maxRebaseAttempts = 10
+
+// CommitWithRebase makes a commit on 'branch' whose contents are written by
+// 'write', so that concurrent writers to the branch build a linear chain of
+// commits rather than starting commits on each other's open ones. The commit
+// is started on the branch's current head once it has finished (see
+// StartCommitOnHead), and is only finished if it's still the branch's head
+// (see FinishCommitOnHead). If another commit has taken its place, it's
+// deleted and 'write' is called again with a new commit on the new head, so
+// 'write' must be safe to call more than once. If 'write' returns an error,
+// the commit is deleted and the error is returned.
+func (c APIClient) CommitWithRebase(repoName string, branch string, write func(commit *pfs.Commit) error) (*pfs.Commit, error) {
+	var conflictErr error
+	for i := 0; i < maxRebaseAttempts; i++ {
+		var head string
+		branchInfo, err := c.InspectBranch(repoName, branch)
+		if err != nil && !errutil.IsNotFoundError(err) {
+			return nil, err
+		}
+		if branchInfo != nil && branchInfo.Head != nil {
+			head = branchInfo.Head.ID
+			// Writers that use CommitWithRebase take turns, rather than
+			// starting commits on each other's open commits
+			if _, err := c.BlockCommit(repoName, head); err != nil {
+				return nil, err
+			}
+		}
+		commit, err := c.StartCommitOnHead(repoName, branch, head)
+		if err != nil {
+			if errutil.IsBranchHeadMismatchError(err) {
+				conflictErr = err
+				continue
+			}
+			return nil, err
+		}
+		if err := write(commit); err != nil {
+			if deleteErr := c.DeleteCommit(repoName, commit.ID); deleteErr != nil {
+				return nil, errors.Wrapf(err, "could not delete commit %s after the error (%v)", commit.ID, deleteErr)
+			}
+			return nil, err
+		}
+		if err := c.FinishCommitOnHead(repoName, commit.ID); err != nil {
+			if !errutil.IsCommitConflictError(err) {
+				return nil, err
+			}
+			conflictErr = err
+			if err := c.DeleteCommit(repoName, commit.ID); err != nil {
+				return nil, errors.Wrapf(err, "could not delete conflicting commit %s", commit.ID)
+			}
+			continue
+		}
+		return commit, nil
+	}
+	return nil, errors.Wrapf(conflictErr, "could not commit to %s@%s after %d attempts", repoName, branch, maxRebaseAttempts)
+}
@@ -0,0 +1,86 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
+	"github.com/pachyderm/pachyderm/src/server/pkg/testpachd"
+)
+
+func TestCommitWithRebase(t *testing.T) {
+	mock, err := testpachd.NewMockPachd(context.Background())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, mock.Close()) }()
+	repo, branch := "repo", client.NewBranch("repo", "master")
+
+	// The branch moves from 'a' to 'b' before the first commit is started,
+	// and to 'x' before the second commit is finished
+	heads := []string{"a", "b", "x"}
+	var headIdx int
+	mock.PFS.InspectBranch.Use(func(_ context.Context, req *pfs.InspectBranchRequest) (*pfs.BranchInfo, error) {
+		return &pfs.BranchInfo{Branch: branch, Head: client.NewCommit(repo, heads[headIdx])}, nil
+	})
+	var blocked []string
+	mock.PFS.InspectCommit.Use(func(_ context.Context, req *pfs.InspectCommitRequest) (*pfs.CommitInfo, error) {
+		require.Equal(t, pfs.CommitState_FINISHED, req.BlockState)
+		blocked = append(blocked, req.Commit.ID)
+		return &pfs.CommitInfo{Commit: req.Commit}, nil
+	})
+	var started []string
+	mock.PFS.StartCommit.Use(func(_ context.Context, req *pfs.StartCommitRequest) (*pfs.Commit, error) {
+		if len(started) == 0 {
+			require.Equal(t, "a", req.ExpectedHead.ID)
+			headIdx++
+			started = append(started, "")
+			return nil, pfsserver.ErrBranchHeadMismatch{Branch: branch, Expected: req.ExpectedHead, Actual: client.NewCommit(repo, "b")}
+		}
+		commit := client.NewCommit(repo, heads[headIdx]+"-child")
+		started = append(started, commit.ID)
+		return commit, nil
+	})
+	mock.PFS.FinishCommit.Use(func(_ context.Context, req *pfs.FinishCommitRequest) (*types.Empty, error) {
+		require.True(t, req.RequireHead)
+		if req.Commit.ID == "b-child" {
+			headIdx++
+			return nil, pfsserver.ErrCommitConflict{Commit: req.Commit, Branch: branch, Head: client.NewCommit(repo, "x")}
+		}
+		return &types.Empty{}, nil
+	})
+	var deleted []string
+	mock.PFS.DeleteCommit.Use(func(_ context.Context, req *pfs.DeleteCommitRequest) (*types.Empty, error) {
+		deleted = append(deleted, req.Commit.ID)
+		return &types.Empty{}, nil
+	})
+	c, err := client.NewFromAddress(mock.Addr.String())
+	require.NoError(t, err)
+	defer c.Close()
+
+	var written []string
+	commit, err := c.CommitWithRebase(repo, "master", func(commit *pfs.Commit) error {
+		written = append(written, commit.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "x-child", commit.ID)
+	require.Equal(t, []string{"a", "b", "x"}, blocked)
+	require.Equal(t, []string{"b-child", "x-child"}, written)
+	require.Equal(t, []string{"b-child"}, deleted)
+
+	// Errors from 'write' abandon the commit
+	written, deleted = nil, nil
+	_, err = c.CommitWithRebase(repo, "master", func(commit *pfs.Commit) error {
+		written = append(written, commit.ID)
+		return errors.New("write failed")
+	})
+	require.YesError(t, err)
+	require.Matches(t, "write failed", err.Error())
+	require.Equal(t, []string{"x-child"}, written)
+	require.Equal(t, []string{"x-child"}, deleted)
+}
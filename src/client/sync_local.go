@@ -0,0 +1,307 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+)
+
+// SyncStateFile is the name of the file, in the root of a directory that's
+// synced with SyncCommitToLocal or SyncLocalToCommit, that records the state
+// of each synced file. It's never uploaded.
+const SyncStateFile = ".pachyderm-sync"
+
+// SyncStats describes what a SyncCommitToLocal or SyncLocalToCommit call did
+type SyncStats struct {
+	// FilesTransferred is the number of files downloaded or uploaded
+	FilesTransferred int
+	// BytesTransferred is the size of the files downloaded or uploaded
+	BytesTransferred int64
+	// FilesDeleted is the number of files deleted from the destination
+	// because they were deleted from the source
+	FilesDeleted int
+	// FilesUnchanged is the number of files that weren't transferred because
+	// they hadn't changed since the last sync
+	FilesUnchanged int
+}
+
+// syncedFile is the state of a synced file in the SyncStateFile
+type syncedFile struct {
+	// PFSHash is the hash of the file in PFS when it was last downloaded. It's
+	// empty if the file was last uploaded, as its PFS hash isn't known until
+	// the commit is finished.
+	PFSHash string `json:"pfs_hash,omitempty"`
+	// SHA256 is the hash of the local file's contents
+	SHA256  string    `json:"sha256"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// syncState is the contents of the SyncStateFile. The paths of files are
+// relative to the synced path in PFS, which is recorded so that a directory
+// isn't mistakenly treated as a mirror of some other path.
+type syncState struct {
+	Repo  string                 `json:"repo"`
+	Path  string                 `json:"path"`
+	Files map[string]*syncedFile `json:"files"`
+}
+
+func readSyncState(dir, repoName, p string) (*syncState, error) {
+	state := &syncState{Repo: repoName, Path: p, Files: make(map[string]*syncedFile)}
+	data, err := ioutil.ReadFile(filepath.Join(dir, SyncStateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, errors.EnsureStack(err)
+	}
+	stored := &syncState{}
+	if err := json.Unmarshal(data, stored); err != nil {
+		return nil, errors.Wrapf(err, "could not parse %s", filepath.Join(dir, SyncStateFile))
+	}
+	if stored.Repo != repoName || stored.Path != p {
+		// 'dir' mirrors something else, so nothing in it is known to be in sync
+		return state, nil
+	}
+	if stored.Files != nil {
+		state.Files = stored.Files
+	}
+	return state, nil
+}
+
+func (s *syncState) write(dir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.EnsureStack(err)
+	}
+	tmp := filepath.Join(dir, SyncStateFile+".tmp")
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return errors.EnsureStack(err)
+	}
+	return errors.EnsureStack(os.Rename(tmp, filepath.Join(dir, SyncStateFile)))
+}
+
+// unmodified returns true if the local file at 'p' has the size and
+// modification time that it had when it was last synced
+func (f *syncedFile) unmodified(p string) bool {
+	info, err := os.Stat(p)
+	return err == nil && info.Mode().IsRegular() && info.Size() == f.Size && info.ModTime().Equal(f.ModTime)
+}
+
+// setLocal records the size and modification time of the local file at 'p'
+func (f *syncedFile) setLocal(p string) error {
+	info, err := os.Stat(p)
+	if err != nil {
+		return errors.EnsureStack(err)
+	}
+	f.Size = info.Size()
+	f.ModTime = info.ModTime()
+	return nil
+}
+
+func hashLocalFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", errors.EnsureStack(err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", errors.EnsureStack(err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cleanSyncPath returns 'p' as an absolute PFS path without a trailing slash
+func cleanSyncPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+// SyncCommitToLocal incrementally mirrors the files under 'p' (which may be
+// "" or "/" for the whole commit) in a commit into the local directory 'dir',
+// like rsync. Only files whose PFS hashes differ from when they were last
+// downloaded, or that were modified locally, are downloaded. Files that were
+// synced before but have since been deleted from the commit are deleted from
+// 'dir'; other local files are left alone. The state of each file is stored in
+// SyncStateFile in 'dir'.
+func (c APIClient) SyncCommitToLocal(repoName string, commitID string, p string, dir string) (*SyncStats, error) {
+	p = cleanSyncPath(p)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	state, err := readSyncState(dir, repoName, p)
+	if err != nil {
+		return nil, err
+	}
+	remote := make(map[string]*pfs.FileInfo)
+	if err := c.Walk(repoName, commitID, p, func(fi *pfs.FileInfo) error {
+		if fi.FileType == pfs.FileType_FILE {
+			rel := strings.TrimPrefix(strings.TrimPrefix(fi.File.Path, p), "/")
+			if rel == "" {
+				// 'p' is a file
+				rel = path.Base(p)
+			}
+			remote[rel] = fi
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	stats := &SyncStats{}
+	names := make([]string, 0, len(remote))
+	for name := range remote {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fi := remote[name]
+		local := filepath.Join(dir, filepath.FromSlash(name))
+		pfsHash := hex.EncodeToString(fi.Hash)
+		if f, ok := state.Files[name]; ok && f.PFSHash == pfsHash && f.unmodified(local) {
+			stats.FilesUnchanged++
+			continue
+		}
+		f, err := c.downloadSyncedFile(repoName, commitID, fi.File.Path, local)
+		if err != nil {
+			return nil, err
+		}
+		f.PFSHash = pfsHash
+		state.Files[name] = f
+		stats.FilesTransferred++
+		stats.BytesTransferred += f.Size
+	}
+	for name := range state.Files {
+		if _, ok := remote[name]; ok {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(name))); err != nil && !os.IsNotExist(err) {
+			return nil, errors.EnsureStack(err)
+		}
+		delete(state.Files, name)
+		stats.FilesDeleted++
+	}
+	if err := state.write(dir); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// downloadSyncedFile downloads the file at 'p' to 'local', through a temporary
+// file so that an interrupted download doesn't leave a partial file behind
+func (c APIClient) downloadSyncedFile(repoName, commitID, p, local string) (_ *syncedFile, retErr error) {
+	if err := os.MkdirAll(filepath.Dir(local), 0755); err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(local), "."+filepath.Base(local)+".")
+	if err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	defer func() {
+		if retErr != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+	h := sha256.New()
+	if err := c.GetFile(repoName, commitID, p, 0, 0, io.MultiWriter(tmp, h)); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	if err := os.Rename(tmp.Name(), local); err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	f := &syncedFile{SHA256: hex.EncodeToString(h.Sum(nil))}
+	if err := f.setLocal(local); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// SyncLocalToCommit is the inverse of SyncCommitToLocal: it incrementally
+// uploads the files in the local directory 'dir' to 'p' in an open commit.
+// Only files whose contents differ from when they were last synced are
+// uploaded (overwriting the files in the commit), and files that were synced
+// before but have since been deleted from 'dir' are deleted from the commit.
+func (c APIClient) SyncLocalToCommit(dir string, repoName string, commitID string, p string) (*SyncStats, error) {
+	p = cleanSyncPath(p)
+	state, err := readSyncState(dir, repoName, p)
+	if err != nil {
+		return nil, err
+	}
+	stats := &SyncStats{}
+	local := make(map[string]bool)
+	if err := filepath.Walk(dir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.EnsureStack(err)
+		}
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return errors.EnsureStack(err)
+		}
+		name := filepath.ToSlash(rel)
+		if !info.Mode().IsRegular() || strings.HasPrefix(name, SyncStateFile) {
+			return nil
+		}
+		local[name] = true
+		f, ok := state.Files[name]
+		if ok && f.unmodified(filePath) {
+			stats.FilesUnchanged++
+			return nil
+		}
+		sum, err := hashLocalFile(filePath)
+		if err != nil {
+			return err
+		}
+		if ok && f.SHA256 == sum {
+			// Only the modification time changed
+			stats.FilesUnchanged++
+			return f.setLocal(filePath)
+		}
+		r, err := os.Open(filePath)
+		if err != nil {
+			return errors.EnsureStack(err)
+		}
+		defer r.Close()
+		if _, err := c.PutFileOverwrite(repoName, commitID, path.Join(p, name), r, 0); err != nil {
+			return err
+		}
+		f = &syncedFile{SHA256: sum}
+		if err := f.setLocal(filePath); err != nil {
+			return err
+		}
+		state.Files[name] = f
+		stats.FilesTransferred++
+		stats.BytesTransferred += f.Size
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	for name := range state.Files {
+		if local[name] {
+			continue
+		}
+		if err := c.DeleteFile(repoName, commitID, path.Join(p, name)); err != nil {
+			return nil, err
+		}
+		delete(state.Files, name)
+		stats.FilesDeleted++
+	}
+	if err := state.write(dir); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
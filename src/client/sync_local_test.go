@@ -0,0 +1,46 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestSyncState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sync")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	p := filepath.Join(dir, "a")
+	require.NoError(t, ioutil.WriteFile(p, []byte("foo"), 0644))
+
+	state, err := readSyncState(dir, "repo", "/")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(state.Files))
+	f := &syncedFile{PFSHash: "abc"}
+	require.NoError(t, f.setLocal(p))
+	state.Files["a"] = f
+	require.NoError(t, state.write(dir))
+
+	// The state survives a round trip, and detects local modifications
+	state, err = readSyncState(dir, "repo", "/")
+	require.NoError(t, err)
+	require.Equal(t, "abc", state.Files["a"].PFSHash)
+	require.True(t, state.Files["a"].unmodified(p))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(p, later, later))
+	require.False(t, state.Files["a"].unmodified(p))
+	require.NoError(t, os.Remove(p))
+	require.False(t, state.Files["a"].unmodified(p))
+
+	// The state of a different repo or path is ignored
+	state, err = readSyncState(dir, "repo", "/sub")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(state.Files))
+	state, err = readSyncState(dir, "other", "/")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(state.Files))
+}
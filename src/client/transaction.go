@@ -323,6 +323,9 @@ func (c *pfsBuilderClient) InspectRepo(ctx context.Context, req *pfs.InspectRepo
 func (c *pfsBuilderClient) ListRepo(ctx context.Context, req *pfs.ListRepoRequest, opts ...grpc.CallOption) (*pfs.ListRepoResponse, error) {
 	return nil, unsupportedError("ListRepo")
 }
+func (c *pfsBuilderClient) FinishCommitStatus(ctx context.Context, req *pfs.FinishCommitStatusRequest, opts ...grpc.CallOption) (*pfs.FinishCommitProgress, error) {
+	return nil, unsupportedError("FinishCommitStatus")
+}
 func (c *pfsBuilderClient) InspectCommit(ctx context.Context, req *pfs.InspectCommitRequest, opts ...grpc.CallOption) (*pfs.CommitInfo, error) {
 	return nil, unsupportedError("InspectCommit")
 }
@@ -359,6 +362,9 @@ func (c *pfsBuilderClient) GetFile(ctx context.Context, req *pfs.GetFileRequest,
 func (c *pfsBuilderClient) InspectFile(ctx context.Context, req *pfs.InspectFileRequest, opts ...grpc.CallOption) (*pfs.FileInfo, error) {
 	return nil, unsupportedError("InspectFile")
 }
+func (c *pfsBuilderClient) GetFileURL(ctx context.Context, req *pfs.GetFileURLRequest, opts ...grpc.CallOption) (*pfs.GetFileURLResponse, error) {
+	return nil, unsupportedError("GetFileURL")
+}
 func (c *pfsBuilderClient) ListFile(ctx context.Context, req *pfs.ListFileRequest, opts ...grpc.CallOption) (*pfs.FileInfos, error) {
 	return nil, unsupportedError("ListFile")
 }
@@ -380,12 +386,75 @@ func (c *pfsBuilderClient) DiffFile(ctx context.Context, req *pfs.DiffFileReques
 func (c *pfsBuilderClient) DeleteFile(ctx context.Context, req *pfs.DeleteFileRequest, opts ...grpc.CallOption) (*types.Empty, error) {
 	return nil, unsupportedError("DeleteFile")
 }
+func (c *pfsBuilderClient) DeleteFiles(ctx context.Context, req *pfs.DeleteFilesRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("DeleteFiles")
+}
+func (c *pfsBuilderClient) ReplaceDirectory(ctx context.Context, req *pfs.ReplaceDirectoryRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("ReplaceDirectory")
+}
 func (c *pfsBuilderClient) DeleteAll(ctx context.Context, req *types.Empty, opts ...grpc.CallOption) (*types.Empty, error) {
 	return nil, unsupportedError("DeleteAll")
 }
 func (c *pfsBuilderClient) Fsck(ctx context.Context, req *pfs.FsckRequest, opts ...grpc.CallOption) (pfs.API_FsckClient, error) {
 	return nil, unsupportedError("Fsck")
 }
+func (c *pfsBuilderClient) ChangeFeed(ctx context.Context, req *pfs.ChangeFeedRequest, opts ...grpc.CallOption) (pfs.API_ChangeFeedClient, error) {
+	return nil, unsupportedError("ChangeFeed")
+}
+func (c *pfsBuilderClient) MoveBranch(ctx context.Context, req *pfs.MoveBranchRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("MoveBranch")
+}
+func (c *pfsBuilderClient) SetBranchDeferred(ctx context.Context, req *pfs.SetBranchDeferredRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("SetBranchDeferred")
+}
+func (c *pfsBuilderClient) GetAttestation(ctx context.Context, req *pfs.GetAttestationRequest, opts ...grpc.CallOption) (*pfs.Attestation, error) {
+	return nil, unsupportedError("GetAttestation")
+}
+func (c *pfsBuilderClient) SetReadPolicy(ctx context.Context, req *pfs.SetReadPolicyRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("SetReadPolicy")
+}
+func (c *pfsBuilderClient) SetOpenCommitPolicy(ctx context.Context, req *pfs.SetOpenCommitPolicyRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("SetOpenCommitPolicy")
+}
+func (c *pfsBuilderClient) UpdateRepoMetadata(ctx context.Context, req *pfs.UpdateRepoMetadataRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("UpdateRepoMetadata")
+}
+func (c *pfsBuilderClient) DiscoverRepos(ctx context.Context, req *pfs.DiscoverReposRequest, opts ...grpc.CallOption) (*pfs.DiscoverReposResponse, error) {
+	return nil, unsupportedError("DiscoverRepos")
+}
+func (c *pfsBuilderClient) ListOpenCommits(ctx context.Context, req *pfs.ListOpenCommitsRequest, opts ...grpc.CallOption) (*pfs.CommitInfos, error) {
+	return nil, unsupportedError("ListOpenCommits")
+}
+func (c *pfsBuilderClient) PruneCommit(ctx context.Context, req *pfs.PruneCommitRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("PruneCommit")
+}
+func (c *pfsBuilderClient) DeleteCommitTag(ctx context.Context, req *pfs.DeleteCommitTagRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("DeleteCommitTag")
+}
+func (c *pfsBuilderClient) ListCommitTag(ctx context.Context, req *pfs.ListCommitTagRequest, opts ...grpc.CallOption) (*pfs.CommitTagInfos, error) {
+	return nil, unsupportedError("ListCommitTag")
+}
+func (c *pfsBuilderClient) InspectCommitTag(ctx context.Context, req *pfs.InspectCommitTagRequest, opts ...grpc.CallOption) (*pfs.CommitTagInfo, error) {
+	return nil, unsupportedError("InspectCommitTag")
+}
+func (c *pfsBuilderClient) TagCommit(ctx context.Context, req *pfs.TagCommitRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("TagCommit")
+}
+func (c *pfsBuilderClient) WatchRepos(ctx context.Context, req *pfs.WatchReposRequest, opts ...grpc.CallOption) (pfs.API_WatchReposClient, error) {
+	return nil, unsupportedError("WatchRepos")
+}
+func (c *pfsBuilderClient) ArchiveProvenance(ctx context.Context, req *pfs.ArchiveProvenanceRequest, opts ...grpc.CallOption) (*pfs.ArchiveProvenanceResponse, error) {
+	return nil, unsupportedError("ArchiveProvenance")
+}
+func (c *pfsBuilderClient) ListDeleted(ctx context.Context, req *pfs.ListDeletedRequest, opts ...grpc.CallOption) (*pfs.ListDeletedResponse, error) {
+	return nil, unsupportedError("ListDeleted")
+}
+func (c *pfsBuilderClient) RestoreDeleted(ctx context.Context, req *pfs.RestoreDeletedRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("RestoreDeleted")
+}
+func (c *pfsBuilderClient) PurgeDeleted(ctx context.Context, req *pfs.PurgeDeletedRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("PurgeDeleted")
+}
 func (c *pfsBuilderClient) FileOperationV2(ctx context.Context, opts ...grpc.CallOption) (pfs.API_FileOperationV2Client, error) {
 	return nil, unsupportedError("FileOperationV2")
 }
@@ -508,6 +577,12 @@ func (c *ppsBuilderClient) ListDatumStream(ctx context.Context, req *pps.ListDat
 func (c *ppsBuilderClient) RestartDatum(ctx context.Context, req *pps.RestartDatumRequest, opts ...grpc.CallOption) (*types.Empty, error) {
 	return nil, unsupportedError("RestartDatum")
 }
+func (c *ppsBuilderClient) ResolveDatum(ctx context.Context, req *pps.ResolveDatumRequest, opts ...grpc.CallOption) (*pps.ResolveDatumResponse, error) {
+	return nil, unsupportedError("ResolveDatum")
+}
+func (c *ppsBuilderClient) ExplainDatum(ctx context.Context, req *pps.ExplainDatumRequest, opts ...grpc.CallOption) (*pps.ExplainDatumResponse, error) {
+	return nil, unsupportedError("ExplainDatum")
+}
 func (c *ppsBuilderClient) CreatePipeline(ctx context.Context, req *pps.CreatePipelineRequest, opts ...grpc.CallOption) (*types.Empty, error) {
 	return nil, unsupportedError("CreatePipeline")
 }
@@ -517,6 +592,27 @@ func (c *ppsBuilderClient) InspectPipeline(ctx context.Context, req *pps.Inspect
 func (c *ppsBuilderClient) ListPipeline(ctx context.Context, req *pps.ListPipelineRequest, opts ...grpc.CallOption) (*pps.PipelineInfos, error) {
 	return nil, unsupportedError("ListPipeline")
 }
+func (c *ppsBuilderClient) ReplayGitHookDelivery(ctx context.Context, req *pps.ReplayGitHookDeliveryRequest, opts ...grpc.CallOption) (*pps.GitHookDelivery, error) {
+	return nil, unsupportedError("ReplayGitHookDelivery")
+}
+func (c *ppsBuilderClient) InspectGitHook(ctx context.Context, req *pps.InspectGitHookRequest, opts ...grpc.CallOption) (*pps.GitHookInfo, error) {
+	return nil, unsupportedError("InspectGitHook")
+}
+func (c *ppsBuilderClient) ListGitHooks(ctx context.Context, req *pps.ListGitHooksRequest, opts ...grpc.CallOption) (*pps.GitHookInfos, error) {
+	return nil, unsupportedError("ListGitHooks")
+}
+func (c *ppsBuilderClient) CreatePipelines(ctx context.Context, req *pps.CreatePipelinesRequest, opts ...grpc.CallOption) (*pps.CreatePipelinesResponse, error) {
+	return nil, unsupportedError("CreatePipelines")
+}
+func (c *ppsBuilderClient) DiffPipeline(ctx context.Context, req *pps.DiffPipelineRequest, opts ...grpc.CallOption) (*pps.DiffPipelineResponse, error) {
+	return nil, unsupportedError("DiffPipeline")
+}
+func (c *ppsBuilderClient) ScaleJob(ctx context.Context, req *pps.ScaleJobRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("ScaleJob")
+}
+func (c *ppsBuilderClient) WatchPipelines(ctx context.Context, req *pps.WatchPipelinesRequest, opts ...grpc.CallOption) (pps.API_WatchPipelinesClient, error) {
+	return nil, unsupportedError("WatchPipelines")
+}
 func (c *ppsBuilderClient) DeletePipeline(ctx context.Context, req *pps.DeletePipelineRequest, opts ...grpc.CallOption) (*types.Empty, error) {
 	return nil, unsupportedError("DeletePipeline")
 }
@@ -544,6 +640,30 @@ func (c *ppsBuilderClient) ActivateAuth(ctx context.Context, req *pps.ActivateAu
 func (c *ppsBuilderClient) RunCron(ctx context.Context, req *pps.RunCronRequest, opts ...grpc.CallOption) (*types.Empty, error) {
 	return nil, unsupportedError("RunCron")
 }
+func (c *ppsBuilderClient) ApplyDAG(ctx context.Context, req *pps.ApplyDAGRequest, opts ...grpc.CallOption) (*pps.ApplyDAGResponse, error) {
+	return nil, unsupportedError("ApplyDAG")
+}
+func (c *ppsBuilderClient) PruneStats(ctx context.Context, req *pps.PruneStatsRequest, opts ...grpc.CallOption) (*pps.PruneStatsResponse, error) {
+	return nil, unsupportedError("PruneStats")
+}
+func (c *ppsBuilderClient) ListIdlePipelines(ctx context.Context, req *pps.ListIdlePipelinesRequest, opts ...grpc.CallOption) (*pps.ListIdlePipelinesResponse, error) {
+	return nil, unsupportedError("ListIdlePipelines")
+}
+func (c *ppsBuilderClient) CheckPipelineUpdate(ctx context.Context, req *pps.CheckPipelineUpdateRequest, opts ...grpc.CallOption) (*pps.CheckPipelineUpdateResponse, error) {
+	return nil, unsupportedError("CheckPipelineUpdate")
+}
+func (c *ppsBuilderClient) GetMountCredentials(ctx context.Context, req *pps.GetMountCredentialsRequest, opts ...grpc.CallOption) (*pps.MountCredentials, error) {
+	return nil, unsupportedError("GetMountCredentials")
+}
+func (c *ppsBuilderClient) GetScheduler(ctx context.Context, req *pps.GetSchedulerRequest, opts ...grpc.CallOption) (*pps.GetSchedulerResponse, error) {
+	return nil, unsupportedError("GetScheduler")
+}
+func (c *ppsBuilderClient) ApproveCommit(ctx context.Context, req *pps.ApproveCommitRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("ApproveCommit")
+}
+func (c *ppsBuilderClient) UpdatePipelineConfig(ctx context.Context, req *pps.UpdatePipelineConfigRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("UpdatePipelineConfig")
+}
 func (c *ppsBuilderClient) CreateSecret(ctx context.Context, req *pps.CreateSecretRequest, opts ...grpc.CallOption) (*types.Empty, error) {
 	return nil, unsupportedError("CreateSecret")
 }
@@ -653,9 +773,39 @@ func (c *adminBuilderClient) ExtractPipeline(ctx context.Context, req *admin.Ext
 func (c *adminBuilderClient) Restore(ctx context.Context, opts ...grpc.CallOption) (admin.API_RestoreClient, error) {
 	return nil, unsupportedError("Restore")
 }
+func (c *adminBuilderClient) ExtractWithProgress(ctx context.Context, req *admin.ExtractRequest, opts ...grpc.CallOption) (admin.API_ExtractWithProgressClient, error) {
+	return nil, unsupportedError("ExtractWithProgress")
+}
+func (c *adminBuilderClient) RestoreWithProgress(ctx context.Context, opts ...grpc.CallOption) (admin.API_RestoreWithProgressClient, error) {
+	return nil, unsupportedError("RestoreWithProgress")
+}
 func (c *adminBuilderClient) InspectCluster(ctx context.Context, req *types.Empty, opts ...grpc.CallOption) (*admin.ClusterInfo, error) {
 	return nil, unsupportedError("InspectCluster")
 }
+func (c *adminBuilderClient) SetFaults(ctx context.Context, req *admin.Faults, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("SetFaults")
+}
+func (c *adminBuilderClient) GetFaults(ctx context.Context, req *types.Empty, opts ...grpc.CallOption) (*admin.Faults, error) {
+	return nil, unsupportedError("GetFaults")
+}
+func (c *adminBuilderClient) InspectRateLimits(ctx context.Context, req *admin.InspectRateLimitsRequest, opts ...grpc.CallOption) (*admin.InspectRateLimitsResponse, error) {
+	return nil, unsupportedError("InspectRateLimits")
+}
+func (c *adminBuilderClient) PrepareDeleteAll(ctx context.Context, req *types.Empty, opts ...grpc.CallOption) (*admin.DeleteAllConfirmation, error) {
+	return nil, unsupportedError("PrepareDeleteAll")
+}
+func (c *adminBuilderClient) DeleteAll(ctx context.Context, req *admin.DeleteAllRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return nil, unsupportedError("DeleteAll")
+}
+func (c *adminBuilderClient) Snapshot(ctx context.Context, req *admin.SnapshotRequest, opts ...grpc.CallOption) (*admin.SnapshotInfo, error) {
+	return nil, unsupportedError("Snapshot")
+}
+func (c *adminBuilderClient) InspectSnapshot(ctx context.Context, req *admin.InspectSnapshotRequest, opts ...grpc.CallOption) (*admin.InspectSnapshotResponse, error) {
+	return nil, unsupportedError("InspectSnapshot")
+}
+func (c *adminBuilderClient) RestoreSnapshot(ctx context.Context, req *admin.RestoreSnapshotRequest, opts ...grpc.CallOption) (*admin.SnapshotInfo, error) {
+	return nil, unsupportedError("RestoreSnapshot")
+}
 
 func (c *transactionBuilderClient) BatchTransaction(ctx context.Context, req *transaction.BatchTransactionRequest, opts ...grpc.CallOption) (*transaction.TransactionInfo, error) {
 	return nil, unsupportedError("BatchTransaction")
@@ -688,3 +838,6 @@ func (c *debugBuilderClient) Binary(ctx context.Context, req *debug.BinaryReques
 func (c *debugBuilderClient) Dump(ctx context.Context, req *debug.DumpRequest, opts ...grpc.CallOption) (debug.Debug_DumpClient, error) {
 	return nil, unsupportedError("Dump")
 }
+func (c *debugBuilderClient) CollectDebugBundle(ctx context.Context, req *debug.CollectDebugBundleRequest, opts ...grpc.CallOption) (debug.Debug_CollectDebugBundleClient, error) {
+	return nil, unsupportedError("CollectDebugBundle")
+}
@@ -0,0 +1,299 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+)
+
+const (
+	// DefaultUploadChunkSize is the default size of the chunks that
+	// PutFileWithProgress splits files into
+	DefaultUploadChunkSize = 64 * 1024 * 1024
+	// DefaultUploadConcurrency is the default maximum number of chunks that
+	// PutFileWithProgress uploads in parallel
+	DefaultUploadConcurrency = 8
+	// DefaultUploadRetries is the default number of times that
+	// PutFileWithProgress retries uploading a chunk
+	DefaultUploadRetries = 3
+)
+
+// UploadProgress describes the progress of a PutFileWithProgress call
+type UploadProgress struct {
+	// BytesUploaded is the number of bytes uploaded so far
+	BytesUploaded int64
+	// ChunksUploaded is the number of chunks uploaded so far
+	ChunksUploaded int
+	// Concurrency is the number of chunks currently allowed to upload in
+	// parallel
+	Concurrency int
+	// BytesPerSecond is the most recently observed upload throughput
+	BytesPerSecond float64
+	// Done is true in the last callback, once the file has been put
+	Done bool
+}
+
+// UploadOptions configures PutFileWithProgress. The zero value uses the
+// defaults.
+type UploadOptions struct {
+	// ChunkSize is the size of the chunks that the file is split into.
+	// PutFileWithProgress buffers up to MaxConcurrency chunks in memory.
+	ChunkSize int
+	// MaxConcurrency is the maximum number of chunks uploaded in parallel.
+	// Parallelism starts at 1, and is increased while doing so increases the
+	// throughput.
+	MaxConcurrency int
+	// Retries is the number of times a chunk is retried before the upload
+	// fails. A negative value disables retries.
+	Retries int
+	// Overwrite, if set, replaces the file's contents rather than appending
+	// to them
+	Overwrite bool
+	// Progress, if set, is called (serially) each time a chunk is uploaded,
+	// and once the file has been put
+	Progress func(UploadProgress)
+}
+
+func (o *UploadOptions) withDefaults() UploadOptions {
+	var result UploadOptions
+	if o != nil {
+		result = *o
+	}
+	if result.ChunkSize <= 0 {
+		result.ChunkSize = DefaultUploadChunkSize
+	}
+	if result.MaxConcurrency <= 0 {
+		result.MaxConcurrency = DefaultUploadConcurrency
+	}
+	if result.Retries < 0 {
+		result.Retries = 0
+	} else if o == nil || o.Retries == 0 {
+		result.Retries = DefaultUploadRetries
+	}
+	return result
+}
+
+// PutFileWithProgress puts a file into PFS like PutFile, but splits 'r' into
+// chunks that are uploaded in parallel, retries chunks that fail, and reports
+// its progress to 'opts.Progress'. The number of chunks uploaded in parallel
+// adapts to the observed throughput. The file is put atomically once every
+// chunk has been uploaded. 'opts' may be nil.
+func (c APIClient) PutFileWithProgress(repoName string, commitID string, path string, r io.Reader, opts *UploadOptions) (int64, error) {
+	o := opts.withDefaults()
+	limiter := newAdaptiveLimiter(o.MaxConcurrency)
+	var (
+		mu       sync.Mutex
+		objects  []*pfs.Object
+		progress UploadProgress
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	report := func(p UploadProgress) {
+		if o.Progress != nil {
+			o.Progress(p)
+		}
+	}
+	for i := 0; ; i++ {
+		mu.Lock()
+		err := firstErr
+		mu.Unlock()
+		if err != nil {
+			break
+		}
+		buf := make([]byte, o.ChunkSize)
+		n, err := io.ReadFull(r, buf)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			mu.Lock()
+			firstErr = errors.EnsureStack(err)
+			mu.Unlock()
+			break
+		}
+		chunk := buf[:n]
+		mu.Lock()
+		objects = append(objects, nil)
+		mu.Unlock()
+		limiter.acquire()
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			start := time.Now()
+			object, err := c.putChunk(chunk, o.Retries, limiter)
+			limiter.release(int64(len(chunk)), time.Since(start), err)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			objects[i] = object
+			progress.BytesUploaded += int64(len(chunk))
+			progress.ChunksUploaded++
+			progress.Concurrency = limiter.limit()
+			progress.BytesPerSecond = limiter.throughput()
+			report(progress)
+		}(i)
+		if err != nil {
+			break // io.ErrUnexpectedEOF: this was the last chunk
+		}
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return 0, firstErr
+	}
+
+	var overwriteIndex *pfs.OverwriteIndex
+	if o.Overwrite {
+		overwriteIndex = &pfs.OverwriteIndex{}
+	}
+	req := &pfs.PutFileRequest{
+		File:           NewFile(repoName, commitID, path),
+		OverwriteIndex: overwriteIndex,
+		Objects:        objects,
+	}
+	if len(objects) == 0 {
+		// An empty file has no chunks, so it's put the usual way
+		req.Objects = nil
+	}
+	pfc, err := c.PfsAPIClient.PutFile(c.Ctx())
+	if err != nil {
+		return 0, grpcutil.ScrubGRPC(err)
+	}
+	if err := pfc.Send(req); err != nil {
+		return 0, grpcutil.ScrubGRPC(err)
+	}
+	if _, err := pfc.CloseAndRecv(); err != nil {
+		return 0, grpcutil.ScrubGRPC(err)
+	}
+	progress.Done = true
+	report(progress)
+	return progress.BytesUploaded, nil
+}
+
+// putChunk uploads 'chunk' as an object, retrying up to 'retries' times
+func (c APIClient) putChunk(chunk []byte, retries int, limiter *adaptiveLimiter) (*pfs.Object, error) {
+	backoff := 100 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		object, _, err := c.PutObject(bytes.NewReader(chunk))
+		if err == nil {
+			return object, nil
+		}
+		if attempt >= retries {
+			return nil, errors.Wrapf(err, "could not upload chunk after %d attempts", attempt+1)
+		}
+		limiter.backOff()
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// adaptiveLimiter limits the number of chunks uploaded in parallel. The limit
+// starts at 1 and is adjusted after every 'limit' uploads: it's increased
+// while increasing it increases the throughput, decreased when the throughput
+// drops, and halved when an upload fails.
+type adaptiveLimiter struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	max  int
+	cur  int // the current limit
+	// inFlight is the number of uploads in progress
+	inFlight int
+
+	// the uploads completed since the limit was last adjusted
+	windowBytes   int64
+	windowTime    time.Duration
+	windowUploads int
+	// lastThroughput is the throughput (in bytes/second) of the previous
+	// window, and 'grew' is true if the limit was increased after it
+	lastThroughput float64
+	grew           bool
+}
+
+func newAdaptiveLimiter(max int) *adaptiveLimiter {
+	l := &adaptiveLimiter{max: max, cur: 1}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+func (l *adaptiveLimiter) acquire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.inFlight >= l.cur {
+		l.cond.Wait()
+	}
+	l.inFlight++
+}
+
+// release records that an upload of 'size' bytes finished after 'elapsed'
+func (l *adaptiveLimiter) release(size int64, elapsed time.Duration, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+	defer l.cond.Broadcast()
+	if err != nil {
+		return
+	}
+	l.windowBytes += size
+	l.windowTime += elapsed
+	l.windowUploads++
+	if l.windowUploads < l.cur || l.windowTime <= 0 {
+		return
+	}
+	// Uploads run in parallel, so the overall throughput is the throughput of
+	// each upload times the limit
+	l.adjust(float64(l.windowBytes) / l.windowTime.Seconds() * float64(l.cur))
+	l.windowBytes, l.windowTime, l.windowUploads = 0, 0, 0
+}
+
+// adjust updates the limit based on the throughput of the last window
+func (l *adaptiveLimiter) adjust(throughput float64) {
+	switch {
+	case l.lastThroughput == 0 || throughput > l.lastThroughput*1.1:
+		// The first window, or the last change helped: keep growing
+		if l.cur < l.max {
+			l.cur++
+			l.grew = true
+		} else {
+			l.grew = false
+		}
+	case throughput < l.lastThroughput*0.9 && l.grew && l.cur > 1:
+		// Growing made things worse: back off
+		l.cur--
+		l.grew = false
+	default:
+		l.grew = false
+	}
+	l.lastThroughput = throughput
+}
+
+// backOff halves the limit, because an upload failed
+func (l *adaptiveLimiter) backOff() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.cur /= 2; l.cur < 1 {
+		l.cur = 1
+	}
+	l.grew = false
+	l.windowBytes, l.windowTime, l.windowUploads = 0, 0, 0
+}
+
+func (l *adaptiveLimiter) limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.cur
+}
+
+func (l *adaptiveLimiter) throughput() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lastThroughput
+}
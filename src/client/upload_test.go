@@ -0,0 +1,61 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+// completeWindow releases 'l.limit()' uploads that each took 'elapsed'
+func completeWindow(l *adaptiveLimiter, elapsed time.Duration) {
+	n := l.limit()
+	for i := 0; i < n; i++ {
+		l.acquire()
+	}
+	for i := 0; i < n; i++ {
+		l.release(1024*1024, elapsed, nil)
+	}
+}
+
+func TestAdaptiveLimiterGrows(t *testing.T) {
+	l := newAdaptiveLimiter(4)
+	require.Equal(t, 1, l.limit())
+	// Uploads take as long regardless of parallelism, so parallelism helps
+	for i := 0; i < 10; i++ {
+		completeWindow(l, time.Second)
+	}
+	require.Equal(t, 4, l.limit())
+}
+
+func TestAdaptiveLimiterBacksOff(t *testing.T) {
+	l := newAdaptiveLimiter(8)
+	completeWindow(l, time.Second) // 1 MB/s -> 2
+	completeWindow(l, time.Second) // 2 MB/s -> 3
+	require.Equal(t, 3, l.limit())
+	// Uploads slow down more than parallelism helps
+	completeWindow(l, 6*time.Second) // 0.5 MB/s
+	require.Equal(t, 2, l.limit())
+	// Throughput is flat, so the limit stays put
+	completeWindow(l, 4*time.Second)
+	require.Equal(t, 2, l.limit())
+
+	// Failures halve the limit, and aren't counted towards the window
+	l.backOff()
+	require.Equal(t, 1, l.limit())
+	l.acquire()
+	l.release(1024*1024, time.Millisecond, errors.New("failed"))
+	require.Equal(t, 1, l.limit())
+}
+
+func TestUploadOptionsDefaults(t *testing.T) {
+	var nilOpts *UploadOptions
+	o := nilOpts.withDefaults()
+	require.Equal(t, DefaultUploadChunkSize, o.ChunkSize)
+	require.Equal(t, DefaultUploadConcurrency, o.MaxConcurrency)
+	require.Equal(t, DefaultUploadRetries, o.Retries)
+	o = (&UploadOptions{ChunkSize: 10, Retries: -1}).withDefaults()
+	require.Equal(t, 10, o.ChunkSize)
+	require.Equal(t, 0, o.Retries)
+}
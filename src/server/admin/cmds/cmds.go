@@ -2,12 +2,20 @@ package cmds
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/admin"
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/pbutil"
 	"github.com/pachyderm/pachyderm/src/server/pkg/cmdutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/pretty"
+	"github.com/pachyderm/pachyderm/src/server/pkg/tabwriter"
 
+	"github.com/gogo/protobuf/types"
 	"github.com/golang/snappy"
 	"github.com/spf13/cobra"
 )
@@ -18,6 +26,7 @@ func Cmds() []*cobra.Command {
 
 	var noObjects bool
 	var url string
+	var noProgress bool
 	extract := &cobra.Command{
 		Short: "Extract Pachyderm state to stdout or an object store bucket.",
 		Long:  "Extract Pachyderm state to stdout or an object store bucket.",
@@ -33,43 +42,105 @@ $ {{alias}} -u s3://bucket/backup`,
 				return err
 			}
 			defer c.Close()
-			if url != "" {
+			if noProgress && url != "" {
 				return c.ExtractURL(url)
 			}
+			request := &admin.ExtractRequest{URL: url, NoObjects: noObjects}
+			if url != "" {
+				return c.ExtractWithProgress(request, nil, printProgress)
+			}
 			w := snappy.NewBufferedWriter(os.Stdout)
 			defer func() {
 				if err := w.Close(); err != nil && retErr == nil {
 					retErr = err
 				}
 			}()
-			return c.ExtractWriter(!noObjects, w)
+			if noProgress {
+				return c.ExtractWriter(!noObjects, w)
+			}
+			writer := pbutil.NewWriter(w)
+			return c.ExtractWithProgress(request, func(op *admin.Op) error {
+				_, err := writer.Write(op)
+				return err
+			}, printProgress)
 		}),
 	}
 	extract.Flags().BoolVar(&noObjects, "no-objects", false, "don't extract from object storage, only extract data from etcd")
 	extract.Flags().StringVarP(&url, "url", "u", "", "An object storage url (i.e. s3://...) to extract to.")
+	extract.Flags().BoolVar(&noProgress, "no-progress", false, "Don't print the extract's progress to stderr.")
 	commands = append(commands, cmdutil.CreateAlias(extract, "extract"))
 
+	var checkpoint string
+	var resume bool
+	var snapshot string
 	restore := &cobra.Command{
 		Short: "Restore Pachyderm state from stdin or an object store.",
-		Long:  "Restore Pachyderm state from stdin or an object store.",
+		Long: "Restore Pachyderm state from stdin or an object store. Restores " +
+			"from an object store, and restores given a --checkpoint, record " +
+			"their progress in a checkpoint, so that if they're interrupted they " +
+			"can be resumed with --resume instead of starting over.",
 		Example: `
 # Restore from a local file:
 $ {{alias}} < backup
 
 # Restore from s3:
-$ {{alias}} -u s3://bucket/backup`,
+$ {{alias}} -u s3://bucket/backup
+
+# Resume an interrupted restore from s3:
+$ {{alias}} -u s3://bucket/backup --resume
+
+# Restore from a local file, and resume if it's interrupted:
+$ {{alias}} --checkpoint backup < backup
+$ {{alias}} --checkpoint backup --resume < backup
+
+# Restore a snapshot (see 'pachctl create snapshot') into an empty cluster:
+$ {{alias}} --snapshot s3://bucket/snapshots/2020-06-01`,
 		Run: cmdutil.RunFixedArgs(0, func(args []string) error {
 			c, err := client.NewOnUserMachine("user")
 			if err != nil {
 				return err
 			}
 			defer c.Close()
-			if url != "" {
-				err = c.RestoreURL(url)
+			if snapshot != "" {
+				if url != "" || checkpoint != "" || resume {
+					return errors.Errorf("--snapshot can't be used with --url, --checkpoint or --resume")
+				}
+				info, err := c.RestoreSnapshot(snapshot)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "restored snapshot of cluster %s taken %s; restart pachd so that it picks up the restored state\n",
+					info.ClusterID, pretty.Ago(info.Created))
+				return nil
+			}
+			if resume && url == "" && checkpoint == "" {
+				return errors.Errorf("--resume needs --url or --checkpoint")
+			}
+			if noProgress && checkpoint == "" && !resume {
+				if url != "" {
+					err = c.RestoreURL(url)
+				} else {
+					err = c.RestoreReader(snappy.NewReader(os.Stdin))
+				}
 			} else {
-				err = c.RestoreReader(snappy.NewReader(os.Stdin))
+				request := &admin.RestoreRequest{URL: url, Checkpoint: checkpoint, Resume: resume}
+				progressF := printProgress
+				if noProgress {
+					progressF = func(*admin.Progress) error { return nil }
+				}
+				if url != "" {
+					err = c.RestoreWithProgress(request, nil, progressF)
+				} else {
+					request.SizeBytes = opsSize(os.Stdin)
+					err = c.RestoreWithProgress(request, snappy.NewReader(os.Stdin), progressF)
+				}
 			}
 			if err != nil {
+				if url != "" || checkpoint != "" {
+					return errors.Wrapf(err, "WARNING: Your cluster might be in an invalid "+
+						"state--consider resuming the restore with --resume, or deleting "+
+						"partially-restored data before continuing")
+				}
 				return errors.Wrapf(err, "WARNING: Your cluster might be in an invalid "+
 					"state--consider deleting partially-restored data before continuing")
 			}
@@ -77,8 +148,79 @@ $ {{alias}} -u s3://bucket/backup`,
 		}),
 	}
 	restore.Flags().StringVarP(&url, "url", "u", "", "An object storage url (i.e. s3://...) to restore from.")
+	restore.Flags().StringVar(&checkpoint, "checkpoint", "", "Record the restore's progress in a checkpoint with this name, so that it can be resumed if it's interrupted (restores from a --url are checkpointed under the url by default).")
+	restore.Flags().BoolVar(&resume, "resume", false, "Resume an interrupted restore from its checkpoint, skipping the ops that were already restored. The same data must be restored as before.")
+	restore.Flags().BoolVar(&noProgress, "no-progress", false, "Don't print the restore's progress to stderr.")
+	restore.Flags().StringVar(&snapshot, "snapshot", "", "Verify the snapshot at this object storage url, and restore it into this cluster, which must not have any repos or pipelines.")
 	commands = append(commands, cmdutil.CreateAlias(restore, "restore"))
 
+	createSnapshot := &cobra.Command{
+		Use:   "{{alias}} <url>",
+		Short: "Take a point-in-time snapshot of the cluster.",
+		Long: "Take a point-in-time snapshot of the cluster, and store it in object " +
+			"storage under <url>. A snapshot is a consistent copy of all of the " +
+			"cluster's metadata, and a manifest of the objects in the cluster's " +
+			"object storage that it refers to. The objects themselves aren't " +
+			"copied, so a snapshot can only be restored by a cluster whose object " +
+			"storage holds them, such as a cluster using a replica of this " +
+			"cluster's bucket. Only cluster admins can take snapshots.",
+		Example: `
+# Take a snapshot:
+$ {{alias}} s3://bucket/snapshots/2020-06-01`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			info, err := c.Snapshot(args[0])
+			if err != nil {
+				return err
+			}
+			printSnapshotInfo(info)
+			return nil
+		}),
+	}
+	commands = append(commands, cmdutil.CreateAlias(createSnapshot, "create snapshot"))
+
+	var verify bool
+	inspectSnapshot := &cobra.Command{
+		Use:   "{{alias}} <url>",
+		Short: "Return info about a snapshot.",
+		Long: "Return info about the snapshot stored under <url>. With --verify, " +
+			"also check that the snapshot is intact, and that this cluster's object " +
+			"storage has every object that it refers to, so that it can be " +
+			"restored into this cluster.",
+		Example: `
+# Check that a snapshot can be restored into this cluster:
+$ {{alias}} s3://bucket/snapshots/2020-06-01 --verify`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			resp, err := c.InspectSnapshot(args[0], verify)
+			if err != nil {
+				return err
+			}
+			printSnapshotInfo(resp.Info)
+			if !verify {
+				return nil
+			}
+			if !resp.Verified {
+				for _, problem := range resp.Problems {
+					fmt.Printf("Problem: %s\n", problem)
+				}
+				return errors.Errorf("the snapshot failed verification")
+			}
+			fmt.Println("Verified: the snapshot can be restored into this cluster")
+			return nil
+		}),
+	}
+	inspectSnapshot.Flags().BoolVar(&verify, "verify", false, "Check that the snapshot is intact, and that this cluster's object storage has everything that it refers to.")
+	commands = append(commands, cmdutil.CreateAlias(inspectSnapshot, "inspect snapshot"))
+
 	inspectCluster := &cobra.Command{
 		Short: "Returns info about the pachyderm cluster",
 		Long:  "Returns info about the pachyderm cluster",
@@ -98,5 +240,188 @@ $ {{alias}} -u s3://bucket/backup`,
 	}
 	commands = append(commands, cmdutil.CreateAlias(inspectCluster, "inspect cluster"))
 
+	var blockReadLatency, blockWriteLatency time.Duration
+	var blockReadErrorRate, blockWriteErrorRate, heartbeatDropRate float64
+	updateFaults := &cobra.Command{
+		Short: "Set the faults that are injected into a test cluster.",
+		Long: "Set the faults that are injected into a test cluster. The new faults " +
+			"replace the current ones, so running this command without any flags " +
+			"stops injecting faults. Only clusters started with FAULT_INJECTION=true " +
+			"inject faults, and only cluster admins can set them.",
+		Example: `
+# Delay every object storage read by 500ms, and fail 10% of them:
+$ {{alias}} --block-read-latency 500ms --block-read-error-rate 0.1
+
+# Drop half of the workers' heartbeats:
+$ {{alias}} --heartbeat-drop-rate 0.5
+
+# Stop injecting faults:
+$ {{alias}}`,
+		Run: cmdutil.RunFixedArgs(0, func(args []string) error {
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			opFaults := func(latency time.Duration, errorRate float64) *admin.OpFaults {
+				if latency == 0 && errorRate == 0 {
+					return nil
+				}
+				return &admin.OpFaults{
+					Latency:   types.DurationProto(latency),
+					ErrorRate: errorRate,
+				}
+			}
+			return c.SetFaults(&admin.Faults{
+				BlockReads:        opFaults(blockReadLatency, blockReadErrorRate),
+				BlockWrites:       opFaults(blockWriteLatency, blockWriteErrorRate),
+				HeartbeatDropRate: heartbeatDropRate,
+			})
+		}),
+	}
+	updateFaults.Flags().DurationVar(&blockReadLatency, "block-read-latency", 0, "How long to delay each object storage read.")
+	updateFaults.Flags().Float64Var(&blockReadErrorRate, "block-read-error-rate", 0, "The fraction (between 0 and 1) of object storage reads that fail.")
+	updateFaults.Flags().DurationVar(&blockWriteLatency, "block-write-latency", 0, "How long to delay each object storage write.")
+	updateFaults.Flags().Float64Var(&blockWriteErrorRate, "block-write-error-rate", 0, "The fraction (between 0 and 1) of object storage writes that fail.")
+	updateFaults.Flags().Float64Var(&heartbeatDropRate, "heartbeat-drop-rate", 0, "The fraction (between 0 and 1) of worker heartbeats that are dropped.")
+	commands = append(commands, cmdutil.CreateAlias(updateFaults, "update faults"))
+
+	inspectFaults := &cobra.Command{
+		Short: "Returns the faults that are injected into a test cluster.",
+		Long:  "Returns the faults that are injected into a test cluster.",
+		Run: cmdutil.RunFixedArgs(0, func(args []string) error {
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			faults, err := c.GetFaults()
+			if err != nil {
+				return err
+			}
+			printOpFaults := func(name string, f *admin.OpFaults) {
+				var latency time.Duration
+				var errorRate float64
+				if f != nil {
+					latency, _ = types.DurationFromProto(f.Latency)
+					errorRate = f.ErrorRate
+				}
+				fmt.Printf("%s: latency %v, error rate %v\n", name, latency, errorRate)
+			}
+			printOpFaults("Block reads", faults.BlockReads)
+			printOpFaults("Block writes", faults.BlockWrites)
+			fmt.Printf("Heartbeats: drop rate %v\n", faults.HeartbeatDropRate)
+			return nil
+		}),
+	}
+	commands = append(commands, cmdutil.CreateAlias(inspectFaults, "inspect faults"))
+
+	var principal string
+	inspectRateLimits := &cobra.Command{
+		Short: "Returns the usage of the rate limits on pachd's API.",
+		Long: "Returns the rate limits that pachd enforces (configured with its " +
+			"RATE_LIMITS environment variable), and how much of them each " +
+			"principal that has made calls recently has used. Only cluster " +
+			"admins can inspect rate limits.",
+		Run: cmdutil.RunFixedArgs(0, func(args []string) error {
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			resp, err := c.InspectRateLimits(principal)
+			if err != nil {
+				return err
+			}
+			if len(resp.Rules) == 0 {
+				fmt.Println("No rate limits are configured")
+				return nil
+			}
+			fmt.Printf("Rules: %s\n\n", strings.Join(resp.Rules, ", "))
+			writer := tabwriter.NewWriter(os.Stdout, "PRINCIPAL\tMETHOD\tLIMIT\tAVAILABLE\tALLOWED\tREJECTED\t\n")
+			for _, u := range resp.Usage {
+				period, _ := types.DurationFromProto(u.Period)
+				fmt.Fprintf(writer, "%s\t%s\t%d/%v\t%.1f\t%d\t%d\t\n", u.Principal, u.Method, u.Requests, period, u.Available, u.Allowed, u.Rejected)
+			}
+			return writer.Flush()
+		}),
+	}
+	inspectRateLimits.Flags().StringVar(&principal, "principal", "", "Only return the usage of this principal (e.g. \"robot:ci\", or \"ip:10.0.0.1\" for unauthenticated callers).")
+	commands = append(commands, cmdutil.CreateAlias(inspectRateLimits, "inspect rate-limits"))
+
 	return commands
 }
+
+func printSnapshotInfo(info *admin.SnapshotInfo) {
+	fmt.Printf("URL: %s\n", info.URL)
+	fmt.Printf("Cluster: %s\n", info.ClusterID)
+	fmt.Printf("Created: %s\n", pretty.Ago(info.Created))
+	fmt.Printf("Revision: %d\n", info.Revision)
+	fmt.Printf("Keys: %d\n", info.Keys)
+	fmt.Printf("Objects: %d\n", info.Objects)
+	fmt.Printf("Tags: %d\n", info.Tags)
+}
+
+// printProgress prints the progress of an extract or restore to stderr.
+func printProgress(p *admin.Progress) error {
+	_, err := fmt.Fprintln(os.Stderr, progressLine(p))
+	return err
+}
+
+func progressLine(p *admin.Progress) string {
+	var b strings.Builder
+	if p.Done {
+		fmt.Fprintf(&b, "done: %d ops (%s) in %s", p.Ops, pretty.Size(uint64(p.Bytes)), pretty.Duration(p.Elapsed))
+		if p.ResumedOps > 0 {
+			fmt.Fprintf(&b, ", of which %d were restored before", p.ResumedOps)
+		}
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%s: %d", p.Stage, p.StageDone)
+	if p.StageTotal > 0 {
+		fmt.Fprintf(&b, "/%d", p.StageTotal)
+	}
+	if p.Item != "" {
+		fmt.Fprintf(&b, " (%s)", p.Item)
+	}
+	fmt.Fprintf(&b, "; %d ops, %s", p.Ops, pretty.Size(uint64(p.Bytes)))
+	if p.BytesTotal > 0 {
+		fmt.Fprintf(&b, " of %s", pretty.Size(uint64(p.BytesTotal)))
+	}
+	fmt.Fprintf(&b, "; elapsed %s", pretty.Duration(p.Elapsed))
+	if p.Eta != nil {
+		fmt.Fprintf(&b, "; ETA %s", pretty.Duration(p.Eta))
+	}
+	if p.ResumedOps > 0 {
+		fmt.Fprintf(&b, "; skipped %d ops restored before", p.ResumedOps)
+	}
+	return b.String()
+}
+
+// opsSize returns the total size of the marshalled ops in 'f' (a snappy
+// compressed extract), which lets a restore from it report an ETA. It reads
+// them all and then seeks back to the start of 'f', so it returns 0 if 'f'
+// isn't a regular file.
+func opsSize(f *os.File) int64 {
+	info, err := f.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return 0
+	}
+	var size int64
+	r := pbutil.NewReader(snappy.NewReader(f))
+	op := &admin.Op{}
+	for {
+		op.Reset()
+		if err := r.Read(op); err != nil {
+			if !errors.Is(err, io.EOF) {
+				size = 0
+			}
+			break
+		}
+		size += int64(op.Size())
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0
+	}
+	return size
+}
@@ -20,8 +20,10 @@ import (
 
 	"github.com/gogo/protobuf/types"
 	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/admin"
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/pbutil"
 	"github.com/pachyderm/pachyderm/src/client/pkg/require"
 	"github.com/pachyderm/pachyderm/src/client/pps"
 	versionlib "github.com/pachyderm/pachyderm/src/client/version"
@@ -586,6 +588,71 @@ func TestExtractRestoreHeadlessBranches(t *testing.T) {
 	require.Equal(t, "headless", bis[0].Branch.Name)
 }
 
+// failingReader returns an error once it has read 'n' bytes
+type failingReader struct {
+	r io.Reader
+	n int
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.n <= 0 {
+		return 0, errors.Errorf("failingReader: interrupted")
+	}
+	if len(p) > r.n {
+		p = p[:r.n]
+	}
+	n, err := r.r.Read(p)
+	r.n -= n
+	return n, err
+}
+
+func TestExtractRestoreWithProgressResume(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	c := tu.GetPachClient(t)
+	require.NoError(t, c.DeleteAll())
+
+	dataRepo := tu.UniqueString("TestExtractRestoreWithProgressResume_data")
+	require.NoError(t, c.CreateRepo(dataRepo))
+	for i := 0; i < 5; i++ {
+		_, err := c.PutFile(dataRepo, "master", fmt.Sprintf("file%d", i), strings.NewReader("foo"))
+		require.NoError(t, err)
+	}
+	commitInfos := listAllCommits(t, c)
+
+	var buf bytes.Buffer
+	w := pbutil.NewWriter(&buf)
+	var last *admin.Progress
+	require.NoError(t, c.ExtractWithProgress(&admin.ExtractRequest{}, func(op *admin.Op) error {
+		_, err := w.Write(op)
+		return err
+	}, func(p *admin.Progress) error {
+		last = p
+		return nil
+	}))
+	require.True(t, last.Done)
+	require.True(t, last.Bytes > 0)
+	size, data := last.Bytes, buf.Bytes()
+
+	// Interrupt a restore halfway through, and then resume it
+	require.NoError(t, c.DeleteAll())
+	checkpoint := tu.UniqueString("TestExtractRestoreWithProgressResume")
+	noProgress := func(*admin.Progress) error { return nil }
+	require.YesError(t, c.RestoreWithProgress(&admin.RestoreRequest{Checkpoint: checkpoint},
+		&failingReader{r: bytes.NewReader(data), n: len(data) / 2}, noProgress))
+	require.NoError(t, c.RestoreWithProgress(&admin.RestoreRequest{Checkpoint: checkpoint, Resume: true, SizeBytes: size},
+		bytes.NewReader(data), func(p *admin.Progress) error {
+			last = p
+			return nil
+		}))
+	require.True(t, last.Done)
+	require.Equal(t, size, last.Bytes)
+	require.NoError(t, c.FsckFastExit())
+	require.ImagesEqual(t, commitInfos, listAllCommits(t, c), commitInfoSummary)
+}
+
 func TestExtractVersion(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration tests in short mode")
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/protobuf/types"
 	"github.com/pachyderm/pachyderm/src/client"
 	"github.com/pachyderm/pachyderm/src/client/admin"
@@ -22,6 +23,8 @@ import (
 	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ratelimit"
+	"github.com/pachyderm/pachyderm/src/server/pkg/serviceenv"
 
 	"github.com/golang/snappy"
 	"github.com/sirupsen/logrus"
@@ -32,11 +35,16 @@ var objHashRE = regexp.MustCompile("[0-9a-f]{128}")
 
 type apiServer struct {
 	log.Logger
+	env *serviceenv.ServiceEnv
+	// The PPS etcd prefix, under which injected faults are stored
+	ppsEtcdPrefix  string
 	address        string
 	storageRoot    string // for downloading/converting hashtrees
 	pachClient     *client.APIClient
 	pachClientOnce sync.Once
 	clusterInfo    *admin.ClusterInfo
+	// The rate limiter of the gRPC server that this is registered with, if any
+	limiter *ratelimit.Limiter
 }
 
 func (a *apiServer) InspectCluster(ctx context.Context, request *types.Empty) (*admin.ClusterInfo, error) {
@@ -95,9 +103,28 @@ func version(op *admin.Op) opVersion {
 func (a *apiServer) Extract(request *admin.ExtractRequest, extractServer admin.API_ExtractServer) (retErr error) {
 	func() { a.Log(request, nil, nil, 0) }()
 	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
-	ctx := extractServer.Context()
+	return a.extract(extractServer.Context(), request, extractServer.Send, newProgressTracker(nil))
+}
+
+func (a *apiServer) ExtractWithProgress(request *admin.ExtractRequest, extractServer admin.API_ExtractWithProgressServer) (retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
+	progress := newProgressTracker(func(p *admin.Progress) error {
+		return extractServer.Send(&admin.ExtractResponse{Progress: p})
+	})
+	if err := a.extract(extractServer.Context(), request, func(op *admin.Op) error {
+		return extractServer.Send(&admin.ExtractResponse{Op: op})
+	}, progress); err != nil {
+		return err
+	}
+	return progress.done()
+}
+
+// extract writes the ops that recreate the cluster's state with 'writeOp', or
+// to request.URL if it's set, counting them with 'progress'. It stops when
+// 'ctx' is cancelled.
+func (a *apiServer) extract(ctx context.Context, request *admin.ExtractRequest, writeOp func(*admin.Op) error, progress *progressTracker) (retErr error) {
 	pachClient := a.getPachClient().WithCtx(ctx)
-	writeOp := extractServer.Send
 	if request.URL != "" {
 		url, err := obj.ParseURL(request.URL)
 		if err != nil {
@@ -110,10 +137,20 @@ func (a *apiServer) Extract(request *admin.ExtractRequest, extractServer admin.A
 		if err != nil {
 			return err
 		}
-		objW, err := objClient.Writer(extractServer.Context(), url.Object)
+		objW, err := objClient.Writer(ctx, url.Object)
 		if err != nil {
 			return err
 		}
+		// Don't leave a truncated extract behind if it fails or is cancelled,
+		// since it could be mistaken for a complete one. This runs after the
+		// writers below are closed.
+		defer func() {
+			if retErr != nil {
+				if err := objClient.Delete(context.Background(), url.Object); err != nil && !objClient.IsNotExist(err) {
+					logrus.Warnf("could not delete incomplete extract at %s: %v", request.URL, err)
+				}
+			}
+		}()
 		defer func() {
 			if err := objW.Close(); err != nil && retErr == nil {
 				retErr = err
@@ -131,31 +168,66 @@ func (a *apiServer) Extract(request *admin.ExtractRequest, extractServer admin.A
 			return err
 		}
 	}
+	// Count every op, and stop between ops if the extract is cancelled
+	writeOp = func(writeOp func(*admin.Op) error) func(*admin.Op) error {
+		return func(op *admin.Op) error {
+			if err := ctx.Err(); err != nil {
+				return errors.EnsureStack(err)
+			}
+			if err := writeOp(op); err != nil {
+				return err
+			}
+			return progress.wrote(int64(op.Size()))
+		}
+	}(writeOp)
 	if !request.NoObjects {
+		var numBlocks int64
+		if progress.send != nil {
+			// Listing blocks is cheap compared to extracting them, and knowing
+			// how many there are gives the blocks stage an ETA
+			if err := pachClient.ListBlock(func(*pfs.Block) error {
+				numBlocks++
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		progress.setStage("blocks", numBlocks)
 		if err := pachClient.ListBlock(func(block *pfs.Block) error {
 			w := &extractBlockWriter{f: writeOp, block: block}
 			if err := pachClient.GetBlock(block.Hash, w); err != nil {
 				return err
 			}
-			return w.Close()
+			if err := w.Close(); err != nil {
+				return err
+			}
+			return progress.finished("")
 		}); err != nil {
 			return err
 		}
+		progress.setStage("objects", 0)
 		if err := pachClient.ListObject(func(oi *pfs.ObjectInfo) error {
-			return writeOp(&admin.Op{Op1_12: &admin.Op1_12{CreateObject: &pfs.CreateObjectRequest{
+			if err := writeOp(&admin.Op{Op1_12: &admin.Op1_12{CreateObject: &pfs.CreateObjectRequest{
 				Object:   oi.Object,
 				BlockRef: oi.BlockRef,
-			}}})
+			}}}); err != nil {
+				return err
+			}
+			return progress.finished("")
 		}); err != nil {
 			return err
 		}
+		progress.setStage("tags", 0)
 		if err := pachClient.ListTag(func(resp *pfs.ListTagsResponse) error {
-			return writeOp(&admin.Op{Op1_12: &admin.Op1_12{
+			if err := writeOp(&admin.Op{Op1_12: &admin.Op1_12{
 				Tag: &pfs.TagObjectRequest{
 					Object: resp.Object,
 					Tags:   []*pfs.Tag{resp.Tag},
 				},
-			}})
+			}}); err != nil {
+				return err
+			}
+			return progress.finished("")
 		}); err != nil {
 			return err
 		}
@@ -166,17 +238,23 @@ func (a *apiServer) Extract(request *admin.ExtractRequest, extractServer admin.A
 			return err
 		}
 		ris = append(ris, &pfs.RepoInfo{Repo: &pfs.Repo{Name: ppsconsts.SpecRepo}})
+		progress.setStage("repos", int64(len(ris)))
 		for i := range ris {
 			ri := ris[len(ris)-1-i]
 			if err := writeOp(&admin.Op{Op1_12: &admin.Op1_12{
 				Repo: &pfs.CreateRepoRequest{
 					Repo:        ri.Repo,
 					Description: ri.Description,
+					Residency:   ri.Residency,
 				}},
 			}); err != nil {
 				return err
 			}
+			if err := progress.finished(ri.Repo.Name); err != nil {
+				return err
+			}
 		}
+		progress.setStage("commits", 0)
 		if err := pachClient.ListCommitF("", "", "", 0, true, func(ci *pfs.CommitInfo) error {
 			if ci.ParentCommit == nil {
 				ci.ParentCommit = client.NewCommit(ci.Commit.Repo.Name, "")
@@ -187,7 +265,7 @@ func (a *apiServer) Extract(request *admin.ExtractRequest, extractServer admin.A
 				logrus.Warnf("Commit %q is not finished, so its data cannot be extracted, and any data it contains will not be restored", ci.Commit.ID)
 				ci.Finished = types.TimestampNow()
 			}
-			return writeOp(&admin.Op{Op1_12: &admin.Op1_12{Commit: &pfs.BuildCommitRequest{
+			if err := writeOp(&admin.Op{Op1_12: &admin.Op1_12{Commit: &pfs.BuildCommitRequest{
 				Origin:     ci.Origin,
 				Parent:     ci.ParentCommit,
 				Tree:       ci.Tree,
@@ -198,7 +276,10 @@ func (a *apiServer) Extract(request *admin.ExtractRequest, extractServer admin.A
 				Provenance: ci.Provenance,
 				Started:    ci.Started,
 				Finished:   ci.Finished,
-			}}})
+			}}}); err != nil {
+				return err
+			}
+			return progress.finished(ci.Commit.Repo.Name)
 		}); err != nil {
 			return err
 		}
@@ -211,6 +292,7 @@ func (a *apiServer) Extract(request *admin.ExtractRequest, extractServer admin.A
 		if err != nil {
 			return err
 		}
+		progress.setStage("branches", int64(len(bis.BranchInfo)))
 		for _, bi := range bis.BranchInfo {
 			if err := writeOp(&admin.Op{Op1_12: &admin.Op1_12{
 				Branch: &pfs.CreateBranchRequest{
@@ -221,6 +303,9 @@ func (a *apiServer) Extract(request *admin.ExtractRequest, extractServer admin.A
 			}}); err != nil {
 				return err
 			}
+			if err := progress.finished(bi.Branch.Repo.Name); err != nil {
+				return err
+			}
 		}
 	}
 	if !request.NoPipelines {
@@ -229,6 +314,9 @@ func (a *apiServer) Extract(request *admin.ExtractRequest, extractServer admin.A
 			return err
 		}
 		pis = sortPipelineInfos(pis)
+		// Each pipeline's jobs are extracted along with it, so they're part of
+		// the pipelines stage
+		progress.setStage("pipelines", int64(len(pis)))
 		for _, pi := range pis {
 			cPR := ppsutil.PipelineReqFromInfo(pi)
 			cPR.SpecCommit = pi.SpecCommit
@@ -237,24 +325,29 @@ func (a *apiServer) Extract(request *admin.ExtractRequest, extractServer admin.A
 			}
 			if err := pachClient.ListJobF(pi.Pipeline.Name, nil, nil, -1, false, func(ji *pps.JobInfo) error {
 				return writeOp(&admin.Op{Op1_12: &admin.Op1_12{Job: &pps.CreateJobRequest{
-					Pipeline:      pi.Pipeline,
-					OutputCommit:  ji.OutputCommit,
-					Restart:       ji.Restart,
-					DataProcessed: ji.DataProcessed,
-					DataSkipped:   ji.DataSkipped,
-					DataTotal:     ji.DataTotal,
-					DataFailed:    ji.DataFailed,
-					DataRecovered: ji.DataRecovered,
-					Stats:         ji.Stats,
-					StatsCommit:   ji.StatsCommit,
-					State:         ji.State,
-					Reason:        ji.Reason,
-					Started:       ji.Started,
-					Finished:      ji.Finished,
+					Pipeline:           pi.Pipeline,
+					OutputCommit:       ji.OutputCommit,
+					Restart:            ji.Restart,
+					DataProcessed:      ji.DataProcessed,
+					DataSkipped:        ji.DataSkipped,
+					DataTotal:          ji.DataTotal,
+					DataFailed:         ji.DataFailed,
+					DataRecovered:      ji.DataRecovered,
+					Stats:              ji.Stats,
+					StatsCommit:        ji.StatsCommit,
+					State:              ji.State,
+					Reason:             ji.Reason,
+					Started:            ji.Started,
+					Finished:           ji.Finished,
+					SeedSalt:           ji.SeedSalt,
+					VolumeFingerprints: ji.VolumeFingerprints,
 				}}})
 			}); err != nil {
 				return err
 			}
+			if err := progress.finished(pi.Pipeline.Name); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -309,25 +402,85 @@ func (a *apiServer) Restore(restoreServer admin.API_RestoreServer) (retErr error
 			retErr = err
 		}
 	}()
+	return a.restore(restoreServer, newProgressTracker(nil))
+}
 
+func (a *apiServer) RestoreWithProgress(restoreServer admin.API_RestoreWithProgressServer) (retErr error) {
+	func() { a.Log(nil, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(nil, nil, retErr, time.Since(start)) }(time.Now())
+	progress := newProgressTracker(restoreServer.Send)
+	if err := a.restore(restoreServer, progress); err != nil {
+		return err
+	}
+	return progress.done()
+}
+
+// restore restores the ops in the requests from 'restoreServer', or at the URL
+// in its first request, counting them with 'progress'. If the restore has a
+// checkpoint, it's updated as ops are restored and deleted once they all are.
+func (a *apiServer) restore(restoreServer adminAPIRestoreServer, progress *progressTracker) (retErr error) {
+	ctx := restoreServer.Context()
 	// Determine if we're restoring from a URL or not
 	r := &restoreCtx{
 		a:             a,
-		restoreServer: restoreServer,
+		restoreServer: &countingRestoreServer{restoreServer, progress},
 		// TODO(msteffen): refactor admin apiServer to use serviceenv
-		pachClient: a.getPachClient().WithCtx(restoreServer.Context()),
+		pachClient: a.getPachClient().WithCtx(ctx),
+		progress:   progress,
 	}
-	req, err := restoreServer.Recv()
+	req, err := r.restoreServer.Recv()
 	if err != nil {
 		if errors.Is(err, io.EOF) {
 			return nil
 		}
 		return err
 	}
+	progress.p.BytesTotal = req.SizeBytes
+	progress.p.Checkpoint = req.Checkpoint
+	if progress.p.Checkpoint == "" {
+		progress.p.Checkpoint = req.URL
+	}
+	if name := progress.p.Checkpoint; name != "" {
+		if req.Resume {
+			checkpoint, err := a.getCheckpoint(ctx, name)
+			if err != nil {
+				return err
+			}
+			if checkpoint != nil {
+				r.skip = checkpoint.Ops
+			} else {
+				logrus.Infof("restore checkpoint %q not found; restoring from the start", name)
+			}
+		}
+		r.lastCheckpoint = time.Now()
+		defer func() {
+			// The restore's context may be cancelled, so use a fresh one
+			ctx, cancel := context.WithTimeout(context.Background(), checkpointTimeout)
+			defer cancel()
+			if retErr == nil {
+				if err := a.deleteCheckpoint(ctx, name); err != nil {
+					retErr = err
+				}
+			} else if r.restoredOps > 0 {
+				if err := r.checkpoint(ctx); err != nil {
+					logrus.Errorf("could not update restore checkpoint %q: %v", name, err)
+				}
+			}
+		}()
+	}
 	if req.URL != "" {
-		return r.startFromURL(req.URL)
+		err = r.startFromURL(req.URL)
+	} else {
+		err = r.start(req.Op)
 	}
-	return r.start(req.Op)
+	if err != nil {
+		return err
+	}
+	if progress.p.Ops < r.skip {
+		return errors.Errorf("the restore's checkpoint says that %d ops were restored, but there "+
+			"are only %d; were different ops restored before?", r.skip, progress.p.Ops)
+	}
+	return nil
 }
 
 // restoreCtx holds the partial results needed to restore a stream of ops to
@@ -341,6 +494,8 @@ func (a *apiServer) Restore(restoreServer admin.API_RestoreServer) (retErr error
 // |         ↓                                                                        |
 // | start/startFromURL // (reads ops from stream in a loop)                          |
 // |         ↓                                                                        |
+// | restoreOp // (skips ops restored before a checkpoint, and counts progress)       |
+// |         ↓                                                                        |
 // | validateAndApplyOp ──┬───────────-┬─────────────┬─────────────╮                  |
 // |         ↓            ↓            ↓             ↓             ↓                  |
 // |     applyOp1_7 → applyOp1_8 → applyOp1_9 → applyOp1_10 → applyOp1_11 → applyOp   |
@@ -348,7 +503,7 @@ type restoreCtx struct {
 	a *apiServer
 
 	// invariant: pachClient.Ctx() == restoreServer.Context()
-	restoreServer admin.API_RestoreServer
+	restoreServer adminAPIRestoreServer
 	pachClient    *client.APIClient
 
 	r pbutil.Reader // set iff restoring from URL
@@ -357,6 +512,19 @@ type restoreCtx struct {
 	// be the same). streamVersion is set in validateAndApplyOp from first op's
 	// version
 	streamVersion opVersion
+
+	// progress counts the ops that are read (including the chunks of blocks
+	// and objects, which are read while restoring them)
+	progress *progressTracker
+	// The number of ops to skip, because the restore's checkpoint says that
+	// they were already restored
+	skip int64
+	// The number of ops (and their bytes) that were read when the last op was
+	// restored, which is where a resumed restore would continue from
+	restoredOps, restoredBytes int64
+	lastCheckpoint             time.Time
+	// The repo or pipeline of the op being restored, set by applyOp
+	item string
 }
 
 func (r *restoreCtx) start(initial *admin.Op) error {
@@ -374,7 +542,7 @@ func (r *restoreCtx) start(initial *admin.Op) error {
 			}
 			op = req.Op
 		}
-		if err := r.validateAndApplyOp(op); err != nil {
+		if err := r.restoreOp(op); err != nil {
 			return err
 		}
 	}
@@ -398,7 +566,7 @@ func (r *restoreCtx) startFromURL(reqURL string) error {
 		return err
 	}
 	snappyR := snappy.NewReader(objR)
-	r.r = pbutil.NewReader(snappyR)
+	r.r = &countingReader{pbutil.NewReader(snappyR), r.progress}
 	var op admin.Op
 	for {
 		op.Reset()
@@ -408,10 +576,51 @@ func (r *restoreCtx) startFromURL(reqURL string) error {
 			}
 			return err
 		}
-		if err := r.validateAndApplyOp(&op); err != nil {
+		if err := r.restoreOp(&op); err != nil {
+			return err
+		}
+	}
+}
+
+// restoreOp restores 'op', which has just been read, unless it's one of the
+// ops that the restore's checkpoint says were already restored. It counts the
+// op, and updates the checkpoint every checkpointInterval.
+func (r *restoreCtx) restoreOp(op *admin.Op) error {
+	if err := r.pachClient.Ctx().Err(); err != nil {
+		return errors.EnsureStack(err)
+	}
+	if r.progress.p.Ops <= r.skip {
+		// Skipped ops aren't restored, but all ops must still have the same
+		// version
+		if r.streamVersion == undefined {
+			r.streamVersion = version(op)
+		}
+		r.progress.skipped(int64(op.Size()))
+		r.restoredOps, r.restoredBytes = r.progress.p.Ops, r.progress.p.Bytes
+		return nil
+	}
+	r.progress.setStage(opStage(op), 0)
+	r.item = ""
+	if err := r.validateAndApplyOp(op); err != nil {
+		return err
+	}
+	r.restoredOps, r.restoredBytes = r.progress.p.Ops, r.progress.p.Bytes
+	if r.progress.p.Checkpoint != "" && time.Since(r.lastCheckpoint) > checkpointInterval {
+		if err := r.checkpoint(r.pachClient.Ctx()); err != nil {
 			return err
 		}
 	}
+	return r.progress.finished(r.item)
+}
+
+// checkpoint records how many ops have been restored.
+func (r *restoreCtx) checkpoint(ctx context.Context) error {
+	r.lastCheckpoint = time.Now()
+	return r.a.putCheckpoint(ctx, &admin.RestoreCheckpoint{
+		Name:  r.progress.p.Checkpoint,
+		Ops:   r.restoredOps,
+		Bytes: r.restoredBytes,
+	})
 }
 
 // validateAndApplyOp is a helper called by start() and startFromURL(), which
@@ -659,6 +868,7 @@ func (r *restoreCtx) applyOp(op *admin.Op1_12) error {
 		}
 	case op.Repo != nil:
 		op.Repo.Repo.Name = ancestry.SanitizeName(op.Repo.Repo.Name)
+		r.item = op.Repo.Repo.Name
 		if _, err := c.PfsAPIClient.CreateRepo(ctx, op.Repo); err != nil && !errutil.IsAlreadyExistError(err) {
 			return errors.Wrapf(grpcutil.ScrubGRPC(err), "error creating repo")
 		}
@@ -670,6 +880,9 @@ func (r *restoreCtx) applyOp(op *admin.Op1_12) error {
 			// commits and corrupt the entire cluster.
 			op.Commit.Finished = types.TimestampNow()
 		}
+		if op.Commit.Parent != nil && op.Commit.Parent.Repo != nil {
+			r.item = op.Commit.Parent.Repo.Name
+		}
 		if _, err := c.PfsAPIClient.BuildCommit(ctx, op.Commit); err != nil && !errutil.IsAlreadyExistError(err) {
 			return errors.Wrapf(grpcutil.ScrubGRPC(err), "error creating commit")
 		}
@@ -677,15 +890,20 @@ func (r *restoreCtx) applyOp(op *admin.Op1_12) error {
 		if op.Branch.Branch == nil {
 			op.Branch.Branch = client.NewBranch(op.Branch.Head.Repo.Name, ancestry.SanitizeName(op.Branch.SBranch))
 		}
+		r.item = op.Branch.Branch.Repo.Name
 		if _, err := c.PfsAPIClient.CreateBranch(ctx, op.Branch); err != nil && !errutil.IsAlreadyExistError(err) {
 			return errors.Wrapf(grpcutil.ScrubGRPC(err), "error creating branch")
 		}
 	case op.Pipeline != nil:
 		sanitizePipeline(op.Pipeline)
+		r.item = op.Pipeline.Pipeline.Name
 		if _, err := c.PpsAPIClient.CreatePipeline(ctx, op.Pipeline); err != nil && !errutil.IsAlreadyExistError(err) {
 			return errors.Wrapf(grpcutil.ScrubGRPC(err), "error creating pipeline")
 		}
 	case op.Job != nil:
+		if op.Job.Pipeline != nil {
+			r.item = op.Job.Pipeline.Name
+		}
 		if _, err := c.PpsAPIClient.CreateJob(ctx, op.Job); err != nil && !errutil.IsAlreadyExistError(err) {
 			return errors.Wrapf(grpcutil.ScrubGRPC(err), "error creating job")
 		}
@@ -712,11 +930,46 @@ func (a *apiServer) getPachClient() *client.APIClient {
 		if err != nil {
 			panic(fmt.Sprintf("pps failed to initialize pach client: %v", err))
 		}
+		// Impersonation is only honored on the admin API's incoming requests,
+		// not on the requests it makes to the other APIs
+		a.pachClient = a.pachClient.WithoutImpersonation()
 	})
 	return a.pachClient
 }
 
-type adminAPIRestoreServer admin.API_RestoreServer
+// adminAPIRestoreServer is the part of admin.API_RestoreServer (and
+// admin.API_RestoreWithProgressServer) that restores read requests from.
+type adminAPIRestoreServer interface {
+	Recv() (*admin.RestoreRequest, error)
+	Context() context.Context
+}
+
+// countingRestoreServer counts the ops in the requests that it receives.
+type countingRestoreServer struct {
+	adminAPIRestoreServer
+	progress *progressTracker
+}
+
+func (s *countingRestoreServer) Recv() (*admin.RestoreRequest, error) {
+	req, err := s.adminAPIRestoreServer.Recv()
+	if err != nil || req.Op == nil {
+		return req, err
+	}
+	return req, s.progress.wrote(int64(req.Op.Size()))
+}
+
+// countingReader counts the ops that it reads.
+type countingReader struct {
+	pbutil.Reader
+	progress *progressTracker
+}
+
+func (r *countingReader) Read(val proto.Message) error {
+	if err := r.Reader.Read(val); err != nil {
+		return err
+	}
+	return r.progress.wrote(int64(proto.Size(val)))
+}
 
 type extractObjectReader struct {
 	// One of these two must be set (whether user is restoring over the wire or
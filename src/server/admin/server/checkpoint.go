@@ -0,0 +1,66 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client/admin"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+
+	"golang.org/x/net/context"
+)
+
+const (
+	// checkpointsPrefix is the etcd prefix, under pachd's etcd prefix, of
+	// the checkpoints of interrupted restores
+	checkpointsPrefix = "restore-checkpoints"
+	// checkpointInterval is how often a restore's checkpoint is updated while
+	// it runs. On resume, ops restored since the last update are restored
+	// again, which is harmless since restoring an op is idempotent.
+	checkpointInterval = 10 * time.Second
+	// checkpointTimeout bounds writes of a checkpoint when a restore fails,
+	// which use a fresh context, since the restore's may be cancelled
+	checkpointTimeout = 10 * time.Second
+)
+
+// checkpointKey returns the etcd key of the checkpoint called 'name'. Names
+// can be URLs, so they're hashed.
+func (a *apiServer) checkpointKey(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return path.Join(a.env.EtcdPrefix, checkpointsPrefix, hex.EncodeToString(sum[:]))
+}
+
+// getCheckpoint returns the checkpoint called 'name', or nil if there isn't
+// one.
+func (a *apiServer) getCheckpoint(ctx context.Context, name string) (*admin.RestoreCheckpoint, error) {
+	resp, err := a.env.GetEtcdClient().Get(ctx, a.checkpointKey(name))
+	if err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	checkpoint := &admin.RestoreCheckpoint{}
+	if err := checkpoint.Unmarshal(resp.Kvs[0].Value); err != nil {
+		return nil, errors.Wrapf(err, "could not unmarshal restore checkpoint %q", name)
+	}
+	return checkpoint, nil
+}
+
+func (a *apiServer) putCheckpoint(ctx context.Context, checkpoint *admin.RestoreCheckpoint) error {
+	checkpoint.Updated = types.TimestampNow()
+	value, err := checkpoint.Marshal()
+	if err != nil {
+		return errors.EnsureStack(err)
+	}
+	_, err = a.env.GetEtcdClient().Put(ctx, a.checkpointKey(checkpoint.Name), string(value))
+	return errors.EnsureStack(err)
+}
+
+func (a *apiServer) deleteCheckpoint(ctx context.Context, name string) error {
+	_, err := a.env.GetEtcdClient().Delete(ctx, a.checkpointKey(name))
+	return errors.EnsureStack(err)
+}
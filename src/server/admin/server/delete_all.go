@@ -0,0 +1,121 @@
+package server
+
+import (
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/admin"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/transaction"
+	"github.com/pachyderm/pachyderm/src/server/pkg/deleteall"
+
+	"golang.org/x/net/context"
+)
+
+// checkDeleteAllCaller returns the name of the caller, or "" if auth isn't
+// active, and the tenant that the caller is confined to, if any. Callers that
+// aren't confined to a tenant must be cluster admins. Callers that are can
+// only delete the tenant's repos and pipelines, which PPS and PFS check that
+// they're authorized to do.
+func (a *apiServer) checkDeleteAllCaller(pachClient *client.APIClient, op string) (username string, t string, retErr error) {
+	me, err := pachClient.WhoAmI(pachClient.Ctx(), &auth.WhoAmIRequest{})
+	if err != nil {
+		if auth.IsErrNotActivated(err) {
+			return "", "", nil
+		}
+		return "", "", grpcutil.ScrubGRPC(err)
+	}
+	if !me.IsAdmin && me.Tenant == "" {
+		return "", "", &auth.ErrNotAuthorized{Subject: me.Username, AdminOp: op}
+	}
+	return me.Username, me.Tenant, nil
+}
+
+// PrepareDeleteAll implements the protobuf admin.PrepareDeleteAll RPC
+func (a *apiServer) PrepareDeleteAll(ctx context.Context, request *types.Empty) (response *admin.DeleteAllConfirmation, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	// Don't log the confirmation token
+	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
+	policy, err := deleteall.ParsePolicy(a.env.DeleteAllPolicy)
+	if err != nil {
+		return nil, err
+	}
+	pachClient := a.getPachClient().WithCtx(ctx)
+	if policy == admin.DeleteAllPolicy_DISABLED {
+		return nil, errors.New("DeleteAll is disabled in this cluster (DELETE_ALL_POLICY=disabled)")
+	}
+	username, _, err := a.checkDeleteAllCaller(pachClient, "PrepareDeleteAll")
+	if err != nil {
+		return nil, err
+	}
+	token, expires, err := deleteall.NewToken(ctx, a.env.GetEtcdClient(), a.env.EtcdPrefix, username)
+	if err != nil {
+		return nil, err
+	}
+	expiresProto, err := types.TimestampProto(expires)
+	if err != nil {
+		return nil, err
+	}
+	return &admin.DeleteAllConfirmation{
+		Policy:  policy,
+		Token:   token,
+		Expires: expiresProto,
+	}, nil
+}
+
+// DeleteAll implements the protobuf admin.DeleteAll RPC
+func (a *apiServer) DeleteAll(ctx context.Context, request *admin.DeleteAllRequest) (response *types.Empty, retErr error) {
+	// Don't log the confirmation token
+	func() { a.Log(nil, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(nil, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.getPachClient().WithCtx(ctx)
+	username, t, err := a.checkDeleteAllCaller(pachClient, "DeleteAll")
+	if err != nil {
+		return nil, err
+	}
+	etcdClient := a.env.GetEtcdClient()
+	// Check the policy before anything is deleted, and pass the token on to
+	// the other APIs, which check it again
+	ctx = deleteall.WithToken(ctx, request.ConfirmationToken)
+	if err := deleteall.Check(ctx, etcdClient, a.env.EtcdPrefix, a.env.DeleteAllPolicy, "DeleteAll", username); err != nil {
+		deleteall.Audit("admin.DeleteAll", username, err)
+		return nil, err
+	}
+	pachClient = pachClient.WithCtx(ctx)
+	defer func() {
+		deleteall.Audit("admin.DeleteAll", username, retErr)
+	}()
+	if t != "" {
+		// Only the tenant's pipelines and repos are deleted. The token isn't
+		// revoked until both are, as PPS and PFS each check it.
+		if _, err := pachClient.PpsAPIClient.DeleteAll(pachClient.Ctx(), &types.Empty{}); err != nil {
+			return nil, grpcutil.ScrubGRPC(err)
+		}
+		if _, err := pachClient.PfsAPIClient.DeleteAll(pachClient.Ctx(), &types.Empty{}); err != nil {
+			return nil, grpcutil.ScrubGRPC(err)
+		}
+		if err := deleteall.RevokeToken(ctx, etcdClient, a.env.EtcdPrefix, request.ConfirmationToken); err != nil {
+			return nil, err
+		}
+		return &types.Empty{}, nil
+	}
+	if _, err := pachClient.AuthAPIClient.Deactivate(pachClient.Ctx(), &auth.DeactivateRequest{}); err != nil && !auth.IsErrNotActivated(err) {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	if _, err := pachClient.PpsAPIClient.DeleteAll(pachClient.Ctx(), &types.Empty{}); err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	if _, err := pachClient.PfsAPIClient.DeleteAll(pachClient.Ctx(), &types.Empty{}); err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	if _, err := pachClient.TransactionAPIClient.DeleteAll(pachClient.Ctx(), &transaction.DeleteAllRequest{}); err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	if err := deleteall.RevokeToken(ctx, etcdClient, a.env.EtcdPrefix, request.ConfirmationToken); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
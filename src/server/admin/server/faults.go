@@ -0,0 +1,53 @@
+package server
+
+import (
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client/admin"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/fault"
+
+	"golang.org/x/net/context"
+)
+
+// SetFaults implements the protobuf admin.SetFaults RPC
+func (a *apiServer) SetFaults(ctx context.Context, request *admin.Faults) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	if err := a.checkFaultInjection(ctx, "SetFaults"); err != nil {
+		return nil, err
+	}
+	if err := fault.Set(ctx, a.env.GetEtcdClient(), a.ppsEtcdPrefix, request); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
+// GetFaults implements the protobuf admin.GetFaults RPC
+func (a *apiServer) GetFaults(ctx context.Context, request *types.Empty) (response *admin.Faults, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	if err := a.checkFaultInjection(ctx, "GetFaults"); err != nil {
+		return nil, err
+	}
+	return fault.Get(ctx, a.env.GetEtcdClient(), a.ppsEtcdPrefix)
+}
+
+// checkFaultInjection returns an error if the cluster wasn't started with
+// fault injection enabled, or if the caller isn't a cluster admin
+func (a *apiServer) checkFaultInjection(ctx context.Context, op string) error {
+	if !a.env.FaultInjection {
+		return errors.New("fault injection is disabled; it's only available in test clusters started with FAULT_INJECTION=true")
+	}
+	pachClient := a.getPachClient().WithCtx(ctx)
+	me, err := pachClient.WhoAmI(pachClient.Ctx(), &auth.WhoAmIRequest{})
+	if err != nil && !auth.IsErrNotActivated(err) {
+		return grpcutil.ScrubGRPC(err)
+	} else if err == nil && !me.IsAdmin {
+		return &auth.ErrNotAuthorized{Subject: me.Username, AdminOp: op}
+	}
+	return nil
+}
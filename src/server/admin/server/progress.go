@@ -0,0 +1,145 @@
+package server
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client/admin"
+)
+
+// progressInterval is how often extracts and restores report their progress
+const progressInterval = 5 * time.Second
+
+// opStages maps the fields of the versioned ops (e.g. admin.Op1_12) to the
+// stages that they're reported in
+var opStages = map[string]string{
+	"Object":       "objects",
+	"CreateObject": "objects",
+	"Tag":          "tags",
+	"Block":        "blocks",
+	"Repo":         "repos",
+	"Commit":       "commits",
+	"Branch":       "branches",
+	"Pipeline":     "pipelines",
+	"Job":          "jobs",
+}
+
+// opStage returns the stage that 'op' belongs to, whatever its version.
+func opStage(op *admin.Op) string {
+	v := reflect.ValueOf(op).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		versioned := v.Field(i)
+		if versioned.Kind() != reflect.Ptr || versioned.IsNil() {
+			continue
+		}
+		versioned = versioned.Elem()
+		for j := 0; j < versioned.NumField(); j++ {
+			if f := versioned.Field(j); f.Kind() == reflect.Ptr && !f.IsNil() {
+				return opStages[versioned.Type().Field(j).Name]
+			}
+		}
+	}
+	return ""
+}
+
+// estimateRemaining estimates how long it will take to process 'remaining'
+// units, given that 'done' units took 'elapsed'.
+func estimateRemaining(elapsed time.Duration, done, remaining int64) (time.Duration, bool) {
+	if done <= 0 || remaining < 0 || elapsed <= 0 {
+		return 0, false
+	}
+	return time.Duration(float64(elapsed) * float64(remaining) / float64(done)), true
+}
+
+// progressTracker counts the ops of an extract or restore, and reports its
+// progress with 'send' (if it's set) every progressInterval.
+type progressTracker struct {
+	send func(*admin.Progress) error
+	now  func() time.Time // time.Now, except in tests
+
+	start, stageStart, lastSent time.Time
+	// The bytes that were skipped when resuming from a checkpoint, which
+	// don't count towards the restore's rate
+	resumedBytes int64
+	p            admin.Progress
+}
+
+func newProgressTracker(send func(*admin.Progress) error) *progressTracker {
+	t := &progressTracker{send: send, now: time.Now}
+	t.start = t.now()
+	t.stageStart, t.lastSent = t.start, t.start
+	return t
+}
+
+// setStage starts a new stage, with 'total' ops (0 if unknown), unless the
+// tracker is already in it.
+func (t *progressTracker) setStage(stage string, total int64) {
+	if t.p.Stage == stage {
+		return
+	}
+	t.p.Stage, t.p.StageDone, t.p.StageTotal, t.p.Item = stage, 0, total, ""
+	t.stageStart = t.now()
+}
+
+// wrote counts an op of 'size' bytes that was read or written.
+func (t *progressTracker) wrote(size int64) error {
+	t.p.Ops++
+	t.p.Bytes += size
+	return t.maybeSend()
+}
+
+// skipped counts an op of 'size' bytes that was skipped when resuming.
+func (t *progressTracker) skipped(size int64) {
+	t.p.ResumedOps++
+	t.resumedBytes += size
+}
+
+// finished counts an op of the current stage that's done. 'item' is the repo
+// or pipeline that it belongs to, if any.
+func (t *progressTracker) finished(item string) error {
+	t.p.StageDone++
+	if item != "" {
+		t.p.Item = item
+	}
+	return t.maybeSend()
+}
+
+func (t *progressTracker) maybeSend() error {
+	if t.send == nil || t.now().Sub(t.lastSent) < progressInterval {
+		return nil
+	}
+	return t.sendNow()
+}
+
+func (t *progressTracker) sendNow() error {
+	if t.send == nil {
+		return nil
+	}
+	t.lastSent = t.now()
+	return t.send(t.report())
+}
+
+// done reports the final progress of a successful operation.
+func (t *progressTracker) done() error {
+	t.p.Done = true
+	return t.sendNow()
+}
+
+// report returns the current progress, with its elapsed time and ETA.
+func (t *progressTracker) report() *admin.Progress {
+	now := t.now()
+	p := t.p
+	p.Elapsed = types.DurationProto(now.Sub(t.start))
+	var eta time.Duration
+	var ok bool
+	if p.BytesTotal > 0 {
+		eta, ok = estimateRemaining(now.Sub(t.start), p.Bytes-t.resumedBytes, p.BytesTotal-p.Bytes)
+	} else if p.StageTotal > 0 {
+		eta, ok = estimateRemaining(now.Sub(t.stageStart), p.StageDone, p.StageTotal-p.StageDone)
+	}
+	if ok {
+		p.Eta = types.DurationProto(eta)
+	}
+	return &p
+}
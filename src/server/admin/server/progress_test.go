@@ -0,0 +1,72 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client/admin"
+	"github.com/pachyderm/pachyderm/src/client/admin/v1_7/pfs"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestOpStage(t *testing.T) {
+	require.Equal(t, "", opStage(&admin.Op{}))
+	require.Equal(t, "blocks", opStage(&admin.Op{Op1_12: &admin.Op1_12{Block: &pfsclient.PutBlockRequest{}}}))
+	require.Equal(t, "pipelines", opStage(&admin.Op{Op1_12: &admin.Op1_12{Pipeline: &pps.CreatePipelineRequest{}}}))
+	require.Equal(t, "repos", opStage(&admin.Op{Op1_7: &admin.Op1_7{Repo: &pfs.CreateRepoRequest{}}}))
+}
+
+func TestEstimateRemaining(t *testing.T) {
+	eta, ok := estimateRemaining(time.Minute, 10, 30)
+	require.True(t, ok)
+	require.Equal(t, 3*time.Minute, eta)
+	_, ok = estimateRemaining(time.Minute, 0, 30)
+	require.False(t, ok)
+	_, ok = estimateRemaining(time.Minute, 10, -1)
+	require.False(t, ok)
+}
+
+func TestProgressTracker(t *testing.T) {
+	now := time.Unix(0, 0)
+	var sent []*admin.Progress
+	tracker := newProgressTracker(func(p *admin.Progress) error {
+		sent = append(sent, p)
+		return nil
+	})
+	tracker.now = func() time.Time { return now }
+	tracker.start, tracker.stageStart, tracker.lastSent = now, now, now
+
+	tracker.setStage("repos", 4)
+	require.NoError(t, tracker.wrote(100))
+	require.NoError(t, tracker.finished("a"))
+	require.Equal(t, 0, len(sent)) // not yet due
+
+	now = now.Add(progressInterval)
+	require.NoError(t, tracker.finished("b"))
+	require.NoError(t, tracker.wrote(100))
+	require.Equal(t, 1, len(sent))
+	p := sent[0]
+	require.Equal(t, "repos", p.Stage)
+	require.Equal(t, "b", p.Item)
+	require.Equal(t, int64(2), p.StageDone)
+	require.Equal(t, int64(1), p.Ops)
+	require.Equal(t, int64(100), p.Bytes)
+	// Only the stage's size is known, so the ETA is the stage's
+	eta, err := types.DurationFromProto(p.Eta)
+	require.NoError(t, err)
+	require.Equal(t, progressInterval, eta)
+
+	// Once the total size is known, it gives the ETA
+	tracker.p.BytesTotal = 1000
+	tracker.setStage("commits", 0)
+	require.Equal(t, "", tracker.p.Item)
+	require.NoError(t, tracker.done())
+	p = sent[len(sent)-1]
+	require.True(t, p.Done)
+	eta, err = types.DurationFromProto(p.Eta)
+	require.NoError(t, err)
+	require.Equal(t, 4*progressInterval, eta)
+}
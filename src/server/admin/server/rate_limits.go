@@ -0,0 +1,34 @@
+package server
+
+import (
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/admin"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+
+	"golang.org/x/net/context"
+)
+
+// InspectRateLimits implements the protobuf admin.InspectRateLimits RPC
+func (a *apiServer) InspectRateLimits(ctx context.Context, request *admin.InspectRateLimitsRequest) (response *admin.InspectRateLimitsResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.getPachClient().WithCtx(ctx)
+	me, err := pachClient.WhoAmI(pachClient.Ctx(), &auth.WhoAmIRequest{})
+	if err != nil && !auth.IsErrNotActivated(err) {
+		return nil, grpcutil.ScrubGRPC(err)
+	} else if err == nil && !me.IsAdmin {
+		return nil, &auth.ErrNotAuthorized{Subject: me.Username, AdminOp: "InspectRateLimits"}
+	}
+	response = &admin.InspectRateLimitsResponse{}
+	if a.limiter == nil {
+		// pachd wasn't configured with any rate limits
+		return response, nil
+	}
+	for _, rule := range a.limiter.Rules() {
+		response.Rules = append(response.Rules, rule.String())
+	}
+	response.Usage = a.limiter.Usage(request.Principal)
+	return response, nil
+}
@@ -1,8 +1,12 @@
 package server
 
 import (
+	"path"
+
 	"github.com/pachyderm/pachyderm/src/client/admin"
 	"github.com/pachyderm/pachyderm/src/server/pkg/log"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ratelimit"
+	"github.com/pachyderm/pachyderm/src/server/pkg/serviceenv"
 )
 
 // APIServer represents and APIServer
@@ -10,12 +14,16 @@ type APIServer interface {
 	admin.APIServer
 }
 
-// NewAPIServer returns a new admin.APIServer
-func NewAPIServer(address string, storageRoot string, clusterInfo *admin.ClusterInfo) APIServer {
+// NewAPIServer returns a new admin.APIServer. 'limiter' is the rate limiter of
+// the gRPC server that it's registered with, if any.
+func NewAPIServer(env *serviceenv.ServiceEnv, address string, storageRoot string, clusterInfo *admin.ClusterInfo, limiter *ratelimit.Limiter) APIServer {
 	return &apiServer{
-		Logger:      log.NewLogger("admin.API"),
-		address:     address,
-		storageRoot: storageRoot,
-		clusterInfo: clusterInfo,
+		Logger:        log.NewLogger("admin.API"),
+		env:           env,
+		ppsEtcdPrefix: path.Join(env.EtcdPrefix, env.PPSEtcdPrefix),
+		address:       address,
+		storageRoot:   storageRoot,
+		clusterInfo:   clusterInfo,
+		limiter:       limiter,
 	}
 }
@@ -0,0 +1,563 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	etcd "github.com/coreos/etcd/clientv3"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/golang/snappy"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/admin"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/pbutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
+)
+
+const (
+	// The objects that a snapshot consists of, under its URL. The info is
+	// written last, so a snapshot is complete iff it has one.
+	snapshotInfoFile     = "info"
+	snapshotMetadataFile = "metadata"
+	snapshotObjectsFile  = "objects"
+
+	// snapshotPageSize is the number of metadata keys that a snapshot reads
+	// from etcd at once
+	snapshotPageSize = 1000
+	// A snapshot's keys are restored in etcd transactions of at most
+	// restoreBatchKeys keys and (roughly) restoreBatchBytes bytes, which are
+	// below etcd's default limits
+	restoreBatchKeys  = 100
+	restoreBatchBytes = 1024 * 1024
+	// maxSnapshotProblems is the number of problems that verifying a snapshot
+	// reports
+	maxSnapshotProblems = 100
+
+	// clusterIDKey is the etcd key of the cluster's ID (see pachd's
+	// getClusterID). A cluster keeps its own ID when a snapshot is restored
+	// into it.
+	clusterIDKey = "cluster-id"
+)
+
+// checkSnapshotCaller returns an error if the caller isn't a cluster admin.
+func (a *apiServer) checkSnapshotCaller(pachClient *client.APIClient, op string) error {
+	me, err := pachClient.WhoAmI(pachClient.Ctx(), &auth.WhoAmIRequest{})
+	if err != nil && !auth.IsErrNotActivated(err) {
+		return grpcutil.ScrubGRPC(err)
+	} else if err == nil && !me.IsAdmin {
+		return &auth.ErrNotAuthorized{Subject: me.Username, AdminOp: op}
+	}
+	return nil
+}
+
+// snapshotStore is where a snapshot is stored in object storage.
+type snapshotStore struct {
+	url       string
+	objClient obj.Client
+	// The prefix of the snapshot's objects
+	prefix string
+}
+
+func newSnapshotStore(reqURL string) (*snapshotStore, error) {
+	url, err := obj.ParseURL(reqURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing url %v", reqURL)
+	}
+	if url.Object == "" {
+		return nil, errors.Errorf("URL must be <svc>://<bucket>/<prefix> (no prefix in %s)", reqURL)
+	}
+	objClient, err := obj.NewClientFromURLAndSecret(url, false)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotStore{url: reqURL, objClient: objClient, prefix: url.Object}, nil
+}
+
+func (s *snapshotStore) path(file string) string {
+	return path.Join(s.prefix, file)
+}
+
+// readInfo returns the info of the snapshot, which is missing if the snapshot
+// doesn't exist or is incomplete.
+func (s *snapshotStore) readInfo(ctx context.Context) (*admin.SnapshotInfo, error) {
+	r, err := s.objClient.Reader(ctx, s.path(snapshotInfoFile), 0, 0)
+	if err != nil {
+		if s.objClient.IsNotExist(err) {
+			return nil, errors.Errorf("there's no snapshot at %s (or it's incomplete)", s.url)
+		}
+		return nil, err
+	}
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	info := &admin.SnapshotInfo{}
+	if err := info.Unmarshal(data); err != nil {
+		return nil, errors.Wrapf(err, "could not unmarshal the info of the snapshot at %s", s.url)
+	}
+	return info, nil
+}
+
+func (s *snapshotStore) writeInfo(ctx context.Context, info *admin.SnapshotInfo) (retErr error) {
+	data, err := info.Marshal()
+	if err != nil {
+		return errors.EnsureStack(err)
+	}
+	w, err := s.objClient.Writer(ctx, s.path(snapshotInfoFile))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := w.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	_, err = w.Write(data)
+	return errors.EnsureStack(err)
+}
+
+// writeFile writes the messages that 'f' writes to the snapshot's 'file', and
+// returns the hex-encoded SHA-256 of what's stored.
+func (s *snapshotStore) writeFile(ctx context.Context, file string, f func(pbutil.Writer) error) (_ string, retErr error) {
+	objW, err := s.objClient.Writer(ctx, s.path(file))
+	if err != nil {
+		return "", err
+	}
+	hasher := sha256.New()
+	snappyW := snappy.NewBufferedWriter(io.MultiWriter(objW, hasher))
+	err = f(pbutil.NewWriter(snappyW))
+	if closeErr := snappyW.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if closeErr := objW.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// readFile calls 'f' with a reader of the messages in the snapshot's 'file',
+// and returns the hex-encoded SHA-256 of what's stored.
+func (s *snapshotStore) readFile(ctx context.Context, file string, f func(pbutil.Reader) error) (string, error) {
+	objR, err := s.objClient.Reader(ctx, s.path(file), 0, 0)
+	if err != nil {
+		return "", err
+	}
+	defer objR.Close()
+	hasher := sha256.New()
+	r := io.TeeReader(objR, hasher)
+	if err := f(pbutil.NewReader(snappy.NewReader(r))); err != nil {
+		return "", err
+	}
+	// Hash all of the file, even if 'f' stopped reading early
+	if _, err := io.Copy(ioutil.Discard, r); err != nil {
+		return "", errors.EnsureStack(err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// deleteFiles deletes the files of a snapshot that couldn't be completed.
+func (s *snapshotStore) deleteFiles() {
+	for _, file := range []string{snapshotMetadataFile, snapshotObjectsFile} {
+		if err := s.objClient.Delete(context.Background(), s.path(file)); err != nil && !s.objClient.IsNotExist(err) {
+			logrus.Warnf("could not delete %s of incomplete snapshot at %s: %v", file, s.url, err)
+		}
+	}
+}
+
+// snapshotKey returns the key, relative to pachd's etcd prefix 'prefix', that
+// etcd key 'key' is stored under in a snapshot, or false if 'key' isn't under
+// 'prefix'.
+func snapshotKey(prefix, key string) (string, bool) {
+	if prefix == "" {
+		return key, true
+	}
+	if !strings.HasPrefix(key, prefix+"/") {
+		return "", false
+	}
+	return strings.TrimPrefix(key, prefix+"/"), true
+}
+
+// etcdKey is the inverse of snapshotKey.
+func etcdKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "/" + key
+}
+
+// readMetadata calls 'f' with each of the cluster's metadata keys that are
+// part of a snapshot, and returns the etcd revision that they were read at.
+// They're all read at the same revision, so they're a consistent cut of the
+// cluster's state, even if it's changing.
+func (a *apiServer) readMetadata(ctx context.Context, f func(*admin.SnapshotKV) error) (int64, error) {
+	prefix := a.env.EtcdPrefix
+	key, end := "\x00", "\x00" // every key
+	if prefix != "" {
+		key = prefix + "/"
+		end = etcd.GetPrefixRangeEnd(key)
+	}
+	checkpoints := path.Join(prefix, checkpointsPrefix) + "/"
+	var rev int64
+	for {
+		opts := []etcd.OpOption{etcd.WithRange(end), etcd.WithLimit(snapshotPageSize)}
+		if rev != 0 {
+			opts = append(opts, etcd.WithRev(rev))
+		}
+		resp, err := a.env.GetEtcdClient().Get(ctx, key, opts...)
+		if err != nil {
+			return 0, errors.EnsureStack(err)
+		}
+		if rev == 0 {
+			rev = resp.Header.Revision
+		}
+		for _, kv := range resp.Kvs {
+			// Keys with leases (e.g. locks, and workers' claims on tasks)
+			// belong to this cluster's processes, and the other skipped keys
+			// to this cluster
+			if kv.Lease != 0 || string(kv.Key) == clusterIDKey || strings.HasPrefix(string(kv.Key), checkpoints) {
+				continue
+			}
+			k, ok := snapshotKey(prefix, string(kv.Key))
+			if !ok {
+				continue
+			}
+			if err := f(&admin.SnapshotKV{Key: k, Value: kv.Value}); err != nil {
+				return 0, err
+			}
+		}
+		if !resp.More || len(resp.Kvs) == 0 {
+			return rev, nil
+		}
+		key = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+}
+
+// gcGeneration returns the cluster's garbage collection generation, which
+// changes whenever garbage collection deletes objects.
+func (a *apiServer) gcGeneration(ctx context.Context) (string, error) {
+	resp, err := a.env.GetEtcdClient().Get(ctx, client.GCGenerationKey)
+	if err != nil {
+		return "", errors.EnsureStack(err)
+	}
+	if len(resp.Kvs) == 0 {
+		return "", nil
+	}
+	return string(resp.Kvs[0].Value), nil
+}
+
+// Snapshot implements the protobuf admin.Snapshot RPC
+func (a *apiServer) Snapshot(ctx context.Context, request *admin.SnapshotRequest) (response *admin.SnapshotInfo, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.getPachClient().WithCtx(ctx)
+	if err := a.checkSnapshotCaller(pachClient, "Snapshot"); err != nil {
+		return nil, err
+	}
+	store, err := newSnapshotStore(request.URL)
+	if err != nil {
+		return nil, err
+	}
+	if store.objClient.Exists(ctx, store.path(snapshotInfoFile)) {
+		return nil, errors.Errorf("there's already a snapshot at %s", request.URL)
+	}
+	defer func() {
+		if retErr != nil {
+			store.deleteFiles()
+		}
+	}()
+	// Objects are only deleted by garbage collection, so if it doesn't run
+	// while the snapshot is taken, every object that the metadata refers to
+	// is still there when the objects are listed
+	gcGeneration, err := a.gcGeneration(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info := &admin.SnapshotInfo{
+		URL:       request.URL,
+		ClusterID: a.clusterInfo.ID,
+		Created:   types.TimestampNow(),
+	}
+	if info.MetadataSha256, err = store.writeFile(ctx, snapshotMetadataFile, func(w pbutil.Writer) error {
+		var err error
+		info.Revision, err = a.readMetadata(ctx, func(kv *admin.SnapshotKV) error {
+			info.Keys++
+			_, err := w.Write(kv)
+			return err
+		})
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if info.ObjectsSha256, err = store.writeFile(ctx, snapshotObjectsFile, func(w pbutil.Writer) error {
+		if err := pachClient.ListObject(func(oi *pfs.ObjectInfo) error {
+			info.Objects++
+			_, err := w.Write(&admin.SnapshotObject{Object: oi})
+			return err
+		}); err != nil {
+			return err
+		}
+		return pachClient.ListTag(func(resp *pfs.ListTagsResponse) error {
+			info.Tags++
+			_, err := w.Write(&admin.SnapshotObject{Tag: resp})
+			return err
+		})
+	}); err != nil {
+		return nil, err
+	}
+	if newGCGeneration, err := a.gcGeneration(ctx); err != nil {
+		return nil, err
+	} else if newGCGeneration != gcGeneration {
+		return nil, errors.New("garbage collection ran while the snapshot was taken, so it may be missing objects; take it again")
+	}
+	if err := store.writeInfo(ctx, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// InspectSnapshot implements the protobuf admin.InspectSnapshot RPC
+func (a *apiServer) InspectSnapshot(ctx context.Context, request *admin.InspectSnapshotRequest) (response *admin.InspectSnapshotResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.getPachClient().WithCtx(ctx)
+	if err := a.checkSnapshotCaller(pachClient, "InspectSnapshot"); err != nil {
+		return nil, err
+	}
+	store, err := newSnapshotStore(request.URL)
+	if err != nil {
+		return nil, err
+	}
+	info, err := store.readInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	response = &admin.InspectSnapshotResponse{Info: info}
+	if request.Verify {
+		if response.Problems, err = a.verifySnapshot(pachClient, store, info); err != nil {
+			return nil, err
+		}
+		response.Verified = len(response.Problems) == 0
+	}
+	return response, nil
+}
+
+// snapshotProblems collects the problems that verifying a snapshot finds.
+type snapshotProblems struct {
+	problems []string
+	count    int
+}
+
+func (p *snapshotProblems) add(format string, args ...interface{}) {
+	if p.count++; p.count <= maxSnapshotProblems {
+		p.problems = append(p.problems, fmt.Sprintf(format, args...))
+	}
+}
+
+func (p *snapshotProblems) list() []string {
+	if p.count > maxSnapshotProblems {
+		return append(p.problems, fmt.Sprintf("... and %d more problems", p.count-maxSnapshotProblems))
+	}
+	return p.problems
+}
+
+// verifySnapshot returns the problems that would stop the snapshot in 'store'
+// from being restored into this cluster intact: its files must match their
+// hashes, and the cluster's object storage must have every object and tag
+// that the snapshot refers to, and the blocks that hold the objects.
+func (a *apiServer) verifySnapshot(pachClient *client.APIClient, store *snapshotStore, info *admin.SnapshotInfo) ([]string, error) {
+	ctx := pachClient.Ctx()
+	problems := &snapshotProblems{}
+	// The end of the furthest range of each block that an object is in
+	blocks := make(map[string]uint64)
+	var objects, tags int64
+	objectsSha256, err := store.readFile(ctx, snapshotObjectsFile, func(r pbutil.Reader) error {
+		for {
+			so := &admin.SnapshotObject{}
+			if err := r.Read(so); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			switch {
+			case so.Object != nil:
+				objects++
+				hash := so.Object.Object.Hash
+				resp, err := pachClient.ObjectAPIClient.CheckObject(ctx, &pfs.CheckObjectRequest{Object: so.Object.Object})
+				if err != nil {
+					return grpcutil.ScrubGRPC(err)
+				}
+				if !resp.Exists {
+					problems.add("object %s is missing", hash)
+					continue
+				}
+				oi, err := pachClient.InspectObject(hash)
+				if err != nil {
+					return err
+				}
+				if !proto.Equal(oi.BlockRef, so.Object.BlockRef) {
+					problems.add("object %s is in a different block or range than in the snapshot", hash)
+					continue
+				}
+				if ref := so.Object.BlockRef; ref.Range.Upper > blocks[ref.Block.Hash] {
+					blocks[ref.Block.Hash] = ref.Range.Upper
+				}
+			case so.Tag != nil:
+				tags++
+				oi, err := pachClient.ObjectAPIClient.InspectTag(ctx, so.Tag.Tag)
+				if err != nil {
+					problems.add("tag %s is missing or unreadable: %v", so.Tag.Tag.Name, grpcutil.ScrubGRPC(err))
+				} else if oi.Object.Hash != so.Tag.Object.Hash {
+					problems.add("tag %s refers to object %s rather than %s", so.Tag.Tag.Name, oi.Object.Hash, so.Tag.Object.Hash)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if objectsSha256 != info.ObjectsSha256 {
+		problems.add("the snapshot's object manifest is corrupt (its SHA-256 is %s rather than %s)", objectsSha256, info.ObjectsSha256)
+	} else if objects != info.Objects || tags != info.Tags {
+		problems.add("the snapshot's object manifest has %d objects and %d tags rather than %d and %d", objects, tags, info.Objects, info.Tags)
+	}
+	// Reading the last byte of each block's furthest range checks that the
+	// block exists and isn't truncated
+	hashes := make([]string, 0, len(blocks))
+	for hash := range blocks {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	for _, hash := range hashes {
+		upper := blocks[hash]
+		if upper == 0 {
+			continue
+		}
+		getBlocksClient, err := pachClient.ObjectAPIClient.GetBlocks(ctx, &pfs.GetBlocksRequest{
+			BlockRefs: []*pfs.BlockRef{{Block: client.NewBlock(hash), Range: &pfs.ByteRange{Lower: upper - 1, Upper: upper}}},
+			SizeBytes: 1,
+			TotalSize: 1,
+		})
+		if err != nil {
+			return nil, grpcutil.ScrubGRPC(err)
+		}
+		var buf bytes.Buffer
+		if err := grpcutil.WriteFromStreamingBytesClient(getBlocksClient, &buf); err != nil || buf.Len() != 1 {
+			problems.add("block %s is missing or truncated", hash)
+		}
+	}
+	var keys int64
+	metadataSha256, err := store.readFile(ctx, snapshotMetadataFile, func(r pbutil.Reader) error {
+		for {
+			if err := r.Read(&admin.SnapshotKV{}); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			keys++
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if metadataSha256 != info.MetadataSha256 {
+		problems.add("the snapshot's metadata is corrupt (its SHA-256 is %s rather than %s)", metadataSha256, info.MetadataSha256)
+	} else if keys != info.Keys {
+		problems.add("the snapshot's metadata has %d keys rather than %d", keys, info.Keys)
+	}
+	return problems.list(), nil
+}
+
+// RestoreSnapshot implements the protobuf admin.RestoreSnapshot RPC
+func (a *apiServer) RestoreSnapshot(ctx context.Context, request *admin.RestoreSnapshotRequest) (response *admin.SnapshotInfo, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.getPachClient().WithCtx(ctx)
+	if err := a.checkSnapshotCaller(pachClient, "RestoreSnapshot"); err != nil {
+		return nil, err
+	}
+	store, err := newSnapshotStore(request.URL)
+	if err != nil {
+		return nil, err
+	}
+	info, err := store.readInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The snapshot replaces the cluster's state rather than being merged
+	// with it, so the cluster must be empty
+	repoInfos, err := pachClient.ListRepo()
+	if err != nil {
+		return nil, err
+	}
+	pipelineInfos, err := pachClient.ListPipeline()
+	if err != nil {
+		return nil, err
+	}
+	if len(repoInfos) > 0 || len(pipelineInfos) > 0 {
+		return nil, errors.Errorf("snapshots can only be restored into an empty cluster, but this one has %d repos and %d pipelines", len(repoInfos), len(pipelineInfos))
+	}
+	problems, err := a.verifySnapshot(pachClient, store, info)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, errors.Errorf("the snapshot at %s failed verification, so it wasn't restored: %s", request.URL, strings.Join(problems, "; "))
+	}
+	etcdClient := a.env.GetEtcdClient()
+	var ops []etcd.Op
+	var size int
+	flush := func() error {
+		if len(ops) == 0 {
+			return nil
+		}
+		if _, err := etcdClient.Txn(ctx).Then(ops...).Commit(); err != nil {
+			return errors.EnsureStack(err)
+		}
+		ops, size = nil, 0
+		return nil
+	}
+	if _, err := store.readFile(ctx, snapshotMetadataFile, func(r pbutil.Reader) error {
+		for {
+			kv := &admin.SnapshotKV{}
+			if err := r.Read(kv); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			ops = append(ops, etcd.OpPut(etcdKey(a.env.EtcdPrefix, kv.Key), string(kv.Value)))
+			if size += len(kv.Key) + len(kv.Value); len(ops) >= restoreBatchKeys || size >= restoreBatchBytes {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}); err != nil {
+		return nil, errors.Wrapf(err, "WARNING: the snapshot was partially restored")
+	}
+	if err := flush(); err != nil {
+		return nil, errors.Wrapf(err, "WARNING: the snapshot was partially restored")
+	}
+	return info, nil
+}
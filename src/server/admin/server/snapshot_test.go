@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/admin"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/pbutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
+)
+
+func TestSnapshotKey(t *testing.T) {
+	key, ok := snapshotKey("", "pachyderm_pfs/repos/foo")
+	require.True(t, ok)
+	require.Equal(t, "pachyderm_pfs/repos/foo", key)
+	require.Equal(t, "prefix/pachyderm_pfs/repos/foo", etcdKey("prefix", key))
+
+	key, ok = snapshotKey("prefix", "prefix/pachyderm_pfs/repos/foo")
+	require.True(t, ok)
+	require.Equal(t, "pachyderm_pfs/repos/foo", key)
+	require.Equal(t, "pachyderm_pfs/repos/foo", etcdKey("", key))
+
+	_, ok = snapshotKey("prefix", "prefix2/pachyderm_pfs/repos/foo")
+	require.False(t, ok)
+}
+
+func TestSnapshotStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	objClient, err := obj.NewLocalClient(dir)
+	require.NoError(t, err)
+	store := &snapshotStore{url: "local://" + dir, objClient: objClient, prefix: "snapshot"}
+	ctx := context.Background()
+
+	_, err = store.readInfo(ctx)
+	require.YesError(t, err)
+	require.Matches(t, "no snapshot", err.Error())
+
+	kvs := []*admin.SnapshotKV{
+		{Key: "a", Value: []byte("1")},
+		{Key: "b", Value: []byte("2")},
+	}
+	written, err := store.writeFile(ctx, snapshotMetadataFile, func(w pbutil.Writer) error {
+		for _, kv := range kvs {
+			if _, err := w.Write(kv); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	var read []*admin.SnapshotKV
+	readSha256, err := store.readFile(ctx, snapshotMetadataFile, func(r pbutil.Reader) error {
+		for {
+			kv := &admin.SnapshotKV{}
+			if err := r.Read(kv); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			read = append(read, kv)
+		}
+	})
+	require.NoError(t, err)
+	require.Equal(t, written, readSha256)
+	require.Equal(t, kvs, read)
+
+	// Files are hashed completely, even if they aren't read completely
+	readSha256, err = store.readFile(ctx, snapshotMetadataFile, func(r pbutil.Reader) error { return nil })
+	require.NoError(t, err)
+	require.Equal(t, written, readSha256)
+
+	// Changing the file changes its hash
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "snapshot", snapshotMetadataFile), []byte("corrupt"), 0644))
+	readSha256, err = store.readFile(ctx, snapshotMetadataFile, func(r pbutil.Reader) error { return nil })
+	require.NoError(t, err)
+	require.NotEqual(t, written, readSha256)
+
+	info := &admin.SnapshotInfo{URL: store.url, Revision: 7, Keys: 2, MetadataSha256: written}
+	require.NoError(t, store.writeInfo(ctx, info))
+	readInfo, err := store.readInfo(ctx)
+	require.NoError(t, err)
+	require.Equal(t, info, readInfo)
+}
+
+func TestSnapshotProblems(t *testing.T) {
+	problems := &snapshotProblems{}
+	require.Equal(t, 0, len(problems.list()))
+	for i := 0; i < maxSnapshotProblems+5; i++ {
+		problems.add("object %d is missing", i)
+	}
+	list := problems.list()
+	require.Equal(t, maxSnapshotProblems+1, len(list))
+	require.Equal(t, "object 0 is missing", list[0])
+	require.Equal(t, "... and 5 more problems", list[maxSnapshotProblems])
+}
@@ -267,29 +267,44 @@ func LogoutCmd() *cobra.Command {
 // credential, logging you out of your cluster. Note that this is not necessary
 // to do before logging in as another user, but is useful for testing.
 func WhoamiCmd() *cobra.Command {
+	var scopes bool
 	whoami := &cobra.Command{
 		Short: "Print your Pachyderm identity",
-		Long:  "Print your Pachyderm identity.",
+		Long: "Print your Pachyderm identity. Cluster admins can set " +
+			"PACH_IMPERSONATE to a subject (e.g. \"github:alice\") to run any " +
+			"pachctl command as that subject.",
 		Run: cmdutil.Run(func([]string) error {
 			c, err := client.NewOnUserMachine("user")
 			if err != nil {
 				return errors.Wrapf(err, "could not connect")
 			}
 			defer c.Close()
-			resp, err := c.WhoAmI(c.Ctx(), &auth.WhoAmIRequest{})
+			resp, err := c.WhoAmI(c.Ctx(), &auth.WhoAmIRequest{IncludeScopes: scopes})
 			if err != nil {
 				return errors.Wrapf(grpcutil.ScrubGRPC(err), "error")
 			}
 			fmt.Printf("You are \"%s\"\n", resp.Username)
+			if resp.Impersonator != "" {
+				fmt.Printf("impersonated by: %s\n", resp.Impersonator)
+			}
 			if resp.TTL > 0 {
 				fmt.Printf("session expires: %v\n", time.Now().Add(time.Duration(resp.TTL)*time.Second).Format(time.RFC822))
 			}
 			if resp.IsAdmin {
 				fmt.Println("You are an administrator of this Pachyderm cluster")
 			}
+			if scopes {
+				if len(resp.Scopes) == 0 {
+					fmt.Println("You have no scopes on any repo")
+				}
+				for _, s := range resp.Scopes {
+					fmt.Printf("%s: %s\n", s.Repo, s.Scope)
+				}
+			}
 			return nil
 		}),
 	}
+	whoami.Flags().BoolVar(&scopes, "scopes", false, "Print your scope on every repo where you have one.")
 	return cmdutil.CreateAlias(whoami, "auth whoami")
 }
 
@@ -477,6 +492,7 @@ func ModifyAdminsCmd() *cobra.Command {
 func GetAuthTokenCmd() *cobra.Command {
 	var quiet bool
 	var ttl string
+	var tenant string
 	getAuthToken := &cobra.Command{
 		Use: "{{alias}} [username]",
 		Short: "Get an auth token that authenticates the holder as \"username\", " +
@@ -502,6 +518,7 @@ func GetAuthTokenCmd() *cobra.Command {
 			if len(args) == 1 {
 				req.Subject = args[0]
 			}
+			req.Tenant = tenant
 			resp, err := c.GetAuthToken(c.Ctx(), req)
 			if err != nil {
 				return grpcutil.ScrubGRPC(err)
@@ -522,6 +539,10 @@ func GetAuthTokenCmd() *cobra.Command {
 		"of the caller's current session, whichever is shorter). This flag should "+
 		"be a golang duration (e.g. \"30s\" or \"1h2m3s\"). If unset, tokens will "+
 		"have a lifetime of 30 days.")
+	getAuthToken.PersistentFlags().StringVar(&tenant, "tenant", "", "if set, "+
+		"the resulting auth token is confined to this tenant: it can only access "+
+		"repos and pipelines named \"<tenant>-...\". Only cluster admins may "+
+		"set this flag.")
 	return cmdutil.CreateAlias(getAuthToken, "auth get-auth-token")
 }
 
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -16,6 +17,7 @@ import (
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
 	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/tenant"
 	"github.com/pachyderm/pachyderm/src/client/pps"
 	"github.com/pachyderm/pachyderm/src/client/version"
 	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
@@ -35,6 +37,7 @@ import (
 	"golang.org/x/net/context"
 	"golang.org/x/oauth2"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -588,7 +591,7 @@ func (a *apiServer) Activate(ctx context.Context, req *auth.ActivateRequest) (re
 	time.Sleep(time.Second) // give other pachd nodes time to update their cache
 
 	// Call PPS.ActivateAuth to set up all affected pipelines and repos
-	superUserClient := pachClient.WithCtx(pachClient.Ctx()) // clone pachClient
+	superUserClient := pachClient.WithCtx(pachClient.Ctx()).WithoutImpersonation() // clone pachClient
 	superUserClient.SetAuthToken(a.ppsToken)
 	if _, err := superUserClient.ActivateAuth(superUserClient.Ctx(), &pps.ActivateAuthRequest{}); err != nil {
 		return nil, err
@@ -673,7 +676,7 @@ func (a *apiServer) Deactivate(ctx context.Context, req *auth.DeactivateRequest)
 	if err != nil {
 		return nil, err
 	}
-	isAdmin, err := a.hasClusterRole(ctx, callerInfo.Subject, auth.ClusterRole_SUPER)
+	isAdmin, err := a.callerHasClusterRole(ctx, callerInfo, auth.ClusterRole_SUPER)
 	if err != nil {
 		return nil, err
 	}
@@ -842,7 +845,7 @@ func (a *apiServer) ModifyClusterRoleBinding(ctx context.Context, req *auth.Modi
 	if err != nil {
 		return nil, err
 	}
-	isAdmin, err := a.hasClusterRole(ctx, callerInfo.Subject, auth.ClusterRole_SUPER)
+	isAdmin, err := a.callerHasClusterRole(ctx, callerInfo, auth.ClusterRole_SUPER)
 	if err != nil {
 		return nil, err
 	}
@@ -1213,10 +1216,14 @@ func (a *apiServer) GetOneTimePassword(ctx context.Context, req *auth.GetOneTime
 	if err != nil {
 		return nil, err
 	}
-	isAdmin, err := a.hasClusterRole(ctx, callerInfo.Subject, auth.ClusterRole_SUPER)
+	isAdmin, err := a.callerHasClusterRole(ctx, callerInfo, auth.ClusterRole_SUPER)
 	if err != nil {
 		return nil, err
 	}
+	if len(callerInfo.ReadOnlyRepos) > 0 || callerInfo.Tenant != "" {
+		// The OTP could be exchanged for an unrestricted token
+		return nil, errors.New("restricted and tenant tokens can't get one-time passwords")
+	}
 
 	// check if this request is auhorized
 	req.Subject, err = a.authorizeNewToken(ctx, callerInfo, isAdmin, req.Subject)
@@ -1350,8 +1357,11 @@ func (a *apiServer) AuthorizeInTransaction(
 	if err != nil {
 		return nil, err
 	}
+	if !restrictionAllows(callerInfo, req.Repo, req.Scope) {
+		return &auth.AuthorizeResponse{Authorized: false}, nil
+	}
 	// Check for FS admin or SUPER admin role
-	isAdmin, err := a.hasClusterRole(txnCtx.ClientContext, callerInfo.Subject, auth.ClusterRole_FS)
+	isAdmin, err := a.callerHasClusterRole(txnCtx.ClientContext, callerInfo, auth.ClusterRole_FS)
 	if err != nil {
 		return nil, err
 	}
@@ -1442,12 +1452,20 @@ func (a *apiServer) WhoAmI(ctx context.Context, req *auth.WhoAmIRequest) (resp *
 		}
 	}
 
+	var scopes []*auth.RepoScope
+	if req.IncludeScopes {
+		scopes, err = a.getRepoScopes(ctx, callerInfo.Subject)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	a.adminMu.Lock()
 	defer a.adminMu.Unlock()
 	var adminRoles auth.ClusterRoles
 	var isAdmin bool
 
-	if _, ok := a.adminCache[callerInfo.Subject]; ok {
+	if _, ok := a.adminCache[callerInfo.Subject]; ok && len(callerInfo.ReadOnlyRepos) == 0 && callerInfo.Tenant == "" {
 		adminRoles.Roles = a.adminCache[callerInfo.Subject].Roles
 		for _, role := range adminRoles.Roles {
 			if role == auth.ClusterRole_SUPER {
@@ -1459,10 +1477,14 @@ func (a *apiServer) WhoAmI(ctx context.Context, req *auth.WhoAmIRequest) (resp *
 
 	// return final result
 	return &auth.WhoAmIResponse{
-		Username:     callerInfo.Subject,
-		TTL:          ttl,
-		IsAdmin:      isAdmin,
-		ClusterRoles: &adminRoles,
+		Username:      callerInfo.Subject,
+		TTL:           ttl,
+		IsAdmin:       isAdmin,
+		ClusterRoles:  &adminRoles,
+		Impersonator:  callerInfo.Impersonator,
+		Scopes:        scopes,
+		ReadOnlyRepos: callerInfo.ReadOnlyRepos,
+		Tenant:        callerInfo.Tenant,
 	}, nil
 }
 
@@ -1508,6 +1530,41 @@ func (a *apiServer) hasClusterRole(ctx context.Context, subject string, role aut
 	return false, nil
 }
 
+// callerHasClusterRole is like hasClusterRole, but for the caller described by
+// 'callerInfo'. Tokens restricted to reading some repos or confined to a
+// tenant never have any cluster roles, so that they can't be used to
+// administer the cluster.
+func (a *apiServer) callerHasClusterRole(ctx context.Context, callerInfo *auth.TokenInfo, role auth.ClusterRole) (bool, error) {
+	if len(callerInfo.ReadOnlyRepos) > 0 || callerInfo.Tenant != "" {
+		return false, nil
+	}
+	return a.hasClusterRole(ctx, callerInfo.Subject, role)
+}
+
+// restrictionAllows returns false if 'callerInfo' is restricted to reading
+// some repos (see TokenInfo.ReadOnlyRepos), and 'scope' on 'repo' isn't one
+// of them, or if 'callerInfo' is confined to a tenant (see TokenInfo.Tenant)
+// that doesn't own 'repo'. Tenant tokens may still read the spec repo, like
+// every other user.
+func restrictionAllows(callerInfo *auth.TokenInfo, repo string, scope auth.Scope) bool {
+	if !tenant.Owns(callerInfo.Tenant, repo) &&
+		!(repo == ppsconsts.SpecRepo && scope == auth.Scope_READER) {
+		return false
+	}
+	if len(callerInfo.ReadOnlyRepos) == 0 {
+		return true
+	}
+	if scope != auth.Scope_READER {
+		return false
+	}
+	for _, r := range callerInfo.ReadOnlyRepos {
+		if r == repo {
+			return true
+		}
+	}
+	return false
+}
+
 // SetScopeInTransaction is identical to SetScope except that it can run inside
 // an existing etcd STM transaction.  This is not an RPC.
 func (a *apiServer) SetScopeInTransaction(
@@ -1526,7 +1583,14 @@ func (a *apiServer) SetScopeInTransaction(
 	if err != nil {
 		return nil, err
 	}
-	isAdmin, err := a.hasClusterRole(txnCtx.ClientContext, callerInfo.Subject, auth.ClusterRole_FS)
+	if !restrictionAllows(callerInfo, req.Repo, auth.Scope_OWNER) {
+		return nil, &auth.ErrNotAuthorized{
+			Subject:  callerInfo.Subject,
+			Repo:     req.Repo,
+			Required: auth.Scope_OWNER,
+		}
+	}
+	isAdmin, err := a.callerHasClusterRole(txnCtx.ClientContext, callerInfo, auth.ClusterRole_FS)
 	if err != nil {
 		return nil, err
 	}
@@ -1629,6 +1693,16 @@ func (a *apiServer) SetScope(ctx context.Context, req *auth.SetScopeRequest) (re
 // Authorized() and other authorization checks (e.g. checking if a user is an
 // OWNER to determine if they can modify an ACL).
 func (a *apiServer) getScope(ctx context.Context, subject string, acl *auth.ACL) (auth.Scope, error) {
+	groups, err := a.getGroups(ctx, subject)
+	if err != nil {
+		return auth.Scope_NONE, errors.Wrapf(err, "could not retrieve caller's group memberships")
+	}
+	return scopeFromACL(acl, subject, groups), nil
+}
+
+// scopeFromACL returns the scope that 'subject', a member of 'groups', has on
+// the repo with the ACL 'acl'
+func scopeFromACL(acl *auth.ACL, subject string, groups []string) auth.Scope {
 	// Get the scope for the "allClusterUsers" ACL, if available
 	scope := acl.Entries[allClusterUsersSubject]
 
@@ -1638,17 +1712,34 @@ func (a *apiServer) getScope(ctx context.Context, subject string, acl *auth.ACL)
 	}
 
 	// Expand scope based on group access
-	groups, err := a.getGroups(ctx, subject)
-	if err != nil {
-		return auth.Scope_NONE, errors.Wrapf(err, "could not retrieve caller's group memberships")
-	}
 	for _, g := range groups {
 		groupScope := acl.Entries[g]
 		if scope < groupScope {
 			scope = groupScope
 		}
 	}
-	return scope, nil
+	return scope
+}
+
+// getRepoScopes returns the scope that 'subject' has on every repo where it
+// has one, sorted by repo
+func (a *apiServer) getRepoScopes(ctx context.Context, subject string) ([]*auth.RepoScope, error) {
+	groups, err := a.getGroups(ctx, subject)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not retrieve caller's group memberships")
+	}
+	var scopes []*auth.RepoScope
+	acl := &auth.ACL{}
+	if err := a.acls.ReadOnly(ctx).List(acl, col.DefaultOptions, func(repo string) error {
+		if scope := scopeFromACL(acl, subject, groups); scope != auth.Scope_NONE {
+			scopes = append(scopes, &auth.RepoScope{Repo: repo, Scope: scope})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(scopes, func(i, j int) bool { return scopes[i].Repo < scopes[j].Repo })
+	return scopes, nil
 }
 
 // GetScopeInTransaction is identical to GetScope except that it can run inside
@@ -1665,7 +1756,7 @@ func (a *apiServer) GetScopeInTransaction(
 	if err != nil {
 		return nil, err
 	}
-	callerIsAdmin, err := a.hasClusterRole(txnCtx.ClientContext, callerInfo.Subject, auth.ClusterRole_FS)
+	callerIsAdmin, err := a.callerHasClusterRole(txnCtx.ClientContext, callerInfo, auth.ClusterRole_FS)
 	if err != nil {
 		return nil, err
 	}
@@ -1777,6 +1868,13 @@ func (a *apiServer) GetACLInTransaction(
 	if err := a.expiredClusterAdminCheck(txnCtx.ClientContext, callerInfo.Subject); err != nil {
 		return nil, err
 	}
+	if !restrictionAllows(callerInfo, req.Repo, auth.Scope_READER) {
+		return nil, &auth.ErrNotAuthorized{
+			Subject:  callerInfo.Subject,
+			Repo:     req.Repo,
+			Required: auth.Scope_READER,
+		}
+	}
 
 	// Read repo ACL from etcd
 	acl := &auth.ACL{}
@@ -1833,7 +1931,14 @@ func (a *apiServer) SetACLInTransaction(
 	if err != nil {
 		return nil, err
 	}
-	isAdmin, err := a.hasClusterRole(txnCtx.ClientContext, callerInfo.Subject, auth.ClusterRole_FS)
+	if !restrictionAllows(callerInfo, req.Repo, auth.Scope_OWNER) {
+		return nil, &auth.ErrNotAuthorized{
+			Subject:  callerInfo.Subject,
+			Repo:     req.Repo,
+			Required: auth.Scope_OWNER,
+		}
+	}
+	isAdmin, err := a.callerHasClusterRole(txnCtx.ClientContext, callerInfo, auth.ClusterRole_FS)
 	if err != nil {
 		return nil, err
 	}
@@ -2019,7 +2124,7 @@ func (a *apiServer) GetAuthToken(ctx context.Context, req *auth.GetAuthTokenRequ
 	if err != nil {
 		return nil, err
 	}
-	isAdmin, err := a.hasClusterRole(ctx, callerInfo.Subject, auth.ClusterRole_SUPER)
+	isAdmin, err := a.callerHasClusterRole(ctx, callerInfo, auth.ClusterRole_SUPER)
 	if err != nil {
 		return nil, err
 	}
@@ -2027,6 +2132,35 @@ func (a *apiServer) GetAuthToken(ctx context.Context, req *auth.GetAuthTokenRequ
 	if req.TTL < 0 && !isAdmin {
 		return nil, errors.Errorf("GetAuthTokenRequest.TTL must be >= 0")
 	}
+	// A tenant caller can only get tokens confined to the same tenant, and only
+	// admins can confine new tokens to a tenant
+	if callerInfo.Tenant != "" {
+		if req.Tenant != "" && req.Tenant != callerInfo.Tenant {
+			return nil, errors.Errorf("a token confined to tenant %q can't get a token for tenant %q", callerInfo.Tenant, req.Tenant)
+		}
+		req.Tenant = callerInfo.Tenant
+	} else if req.Tenant != "" {
+		if !isAdmin {
+			return nil, &auth.ErrNotAuthorized{
+				Subject: callerInfo.Subject,
+				AdminOp: "GetAuthToken for a tenant",
+			}
+		}
+		if err := tenant.ValidateName(req.Tenant); err != nil {
+			return nil, err
+		}
+	}
+	// A restricted caller can only get tokens that are at least as restricted
+	if len(callerInfo.ReadOnlyRepos) > 0 {
+		if len(req.ReadOnlyRepos) == 0 {
+			req.ReadOnlyRepos = callerInfo.ReadOnlyRepos
+		}
+		for _, repo := range req.ReadOnlyRepos {
+			if !restrictionAllows(callerInfo, repo, auth.Scope_READER) {
+				return nil, errors.Errorf("a token restricted to %v can't get a token for repo %q", callerInfo.ReadOnlyRepos, repo)
+			}
+		}
+	}
 
 	// check if this request is auhorized
 	req.Subject, err = a.authorizeNewToken(ctx, callerInfo, isAdmin, req.Subject)
@@ -2072,8 +2206,10 @@ func (a *apiServer) GetAuthToken(ctx context.Context, req *auth.GetAuthTokenRequ
 		req.TTL = defaultSessionTTLSecs
 	}
 	tokenInfo := auth.TokenInfo{
-		Source:  auth.TokenInfo_GET_TOKEN,
-		Subject: req.Subject,
+		Source:        auth.TokenInfo_GET_TOKEN,
+		Subject:       req.Subject,
+		ReadOnlyRepos: req.ReadOnlyRepos,
+		Tenant:        req.Tenant,
 	}
 
 	// generate new token, and write to etcd
@@ -2130,7 +2266,7 @@ func (a *apiServer) ExtendAuthToken(ctx context.Context, req *auth.ExtendAuthTok
 	if err != nil {
 		return nil, err
 	}
-	isAdmin, err := a.hasClusterRole(ctx, callerInfo.Subject, auth.ClusterRole_SUPER)
+	isAdmin, err := a.callerHasClusterRole(ctx, callerInfo, auth.ClusterRole_SUPER)
 	if err != nil {
 		return nil, err
 	}
@@ -2196,7 +2332,7 @@ func (a *apiServer) RevokeAuthToken(ctx context.Context, req *auth.RevokeAuthTok
 	if err != nil {
 		return nil, err
 	}
-	isAdmin, err := a.hasClusterRole(ctx, callerInfo.Subject, auth.ClusterRole_SUPER)
+	isAdmin, err := a.callerHasClusterRole(ctx, callerInfo, auth.ClusterRole_SUPER)
 	if err != nil {
 		return nil, err
 	}
@@ -2289,7 +2425,7 @@ func (a *apiServer) SetGroupsForUser(ctx context.Context, req *auth.SetGroupsFor
 	if err != nil {
 		return nil, err
 	}
-	isAdmin, err := a.hasClusterRole(ctx, callerInfo.Subject, auth.ClusterRole_SUPER)
+	isAdmin, err := a.callerHasClusterRole(ctx, callerInfo, auth.ClusterRole_SUPER)
 	if err != nil {
 		return nil, err
 	}
@@ -2324,7 +2460,7 @@ func (a *apiServer) ModifyMembers(ctx context.Context, req *auth.ModifyMembersRe
 	if err != nil {
 		return nil, err
 	}
-	isAdmin, err := a.hasClusterRole(ctx, callerInfo.Subject, auth.ClusterRole_SUPER)
+	isAdmin, err := a.callerHasClusterRole(ctx, callerInfo, auth.ClusterRole_SUPER)
 	if err != nil {
 		return nil, err
 	}
@@ -2439,7 +2575,7 @@ func (a *apiServer) GetGroups(ctx context.Context, req *auth.GetGroupsRequest) (
 	// infinite recursion
 	var target string
 	if req.Username != "" && req.Username != callerInfo.Subject {
-		isAdmin, err := a.hasClusterRole(ctx, callerInfo.Subject, auth.ClusterRole_SUPER)
+		isAdmin, err := a.callerHasClusterRole(ctx, callerInfo, auth.ClusterRole_SUPER)
 		if err != nil {
 			return nil, err
 		}
@@ -2476,7 +2612,7 @@ func (a *apiServer) GetUsers(ctx context.Context, req *auth.GetUsersRequest) (re
 	if err != nil {
 		return nil, err
 	}
-	isAdmin, err := a.hasClusterRole(ctx, callerInfo.Subject, auth.ClusterRole_SUPER)
+	isAdmin, err := a.callerHasClusterRole(ctx, callerInfo, auth.ClusterRole_SUPER)
 	if err != nil {
 		return nil, err
 	}
@@ -2576,9 +2712,61 @@ func (a *apiServer) getAuthenticatedUser(ctx context.Context) (*auth.TokenInfo,
 		}
 		return nil, err
 	}
+	if subject := getImpersonatedSubject(ctx); subject != "" {
+		return a.impersonate(ctx, &tokenInfo, subject)
+	}
 	return &tokenInfo, nil
 }
 
+// getImpersonatedSubject returns the subject that the request in 'ctx' is
+// impersonating, if any
+func getImpersonatedSubject(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md[auth.ContextImpersonateKey]) == 0 {
+		return ""
+	}
+	return md[auth.ContextImpersonateKey][0]
+}
+
+// impersonate returns the TokenInfo that a request from 'caller' that
+// impersonates 'subject' is authorized as. Only cluster admins may impersonate
+// other subjects, and every impersonated request is logged.
+func (a *apiServer) impersonate(ctx context.Context, caller *auth.TokenInfo, subject string) (*auth.TokenInfo, error) {
+	method, _ := grpc.Method(ctx)
+	isAdmin, err := a.callerHasClusterRole(ctx, caller, auth.ClusterRole_SUPER)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		logrus.WithFields(logrus.Fields{
+			"impersonator": caller.Subject,
+			"subject":      subject,
+			"method":       method,
+		}).Warn("rejected impersonation by non-admin")
+		return nil, &auth.ErrNotAuthorized{
+			Subject: caller.Subject,
+			AdminOp: "impersonate " + subject,
+		}
+	}
+	// Subjects must be fully qualified, as canonicalizing GitHub usernames on
+	// every request would be too slow
+	if !strings.Contains(subject, ":") || strings.HasPrefix(subject, "group/") ||
+		subject == ppsUser || subject == allClusterUsersSubject {
+		return nil, errors.Errorf("cannot impersonate %q: only users, robots "+
+			"and pipelines (with their prefix, e.g. %q) can be impersonated", subject, auth.GitHubPrefix+"alice")
+	}
+	logrus.WithFields(logrus.Fields{
+		"impersonator": caller.Subject,
+		"subject":      subject,
+		"method":       method,
+	}).Info("impersonated request")
+	return &auth.TokenInfo{
+		Subject:      subject,
+		Source:       caller.Source,
+		Impersonator: caller.Subject,
+	}, nil
+}
+
 // canonicalizeSubjects applies canonicalizeSubject to a list
 func (a *apiServer) canonicalizeSubjects(ctx context.Context, subjects []string) ([]string, error) {
 	if subjects == nil {
@@ -2738,7 +2926,7 @@ func (a *apiServer) SetConfiguration(ctx context.Context, req *auth.SetConfigura
 	if err != nil {
 		return nil, err
 	}
-	isAdmin, err := a.hasClusterRole(ctx, callerInfo.Subject, auth.ClusterRole_SUPER)
+	isAdmin, err := a.callerHasClusterRole(ctx, callerInfo, auth.ClusterRole_SUPER)
 	if err != nil {
 		return nil, err
 	}
@@ -2201,3 +2201,128 @@ func TestInitialRobotUserGetOTP(t *testing.T) {
 	require.True(t, strings.HasPrefix(who.Username, auth.RobotPrefix))
 	require.True(t, who.TTL > 0)
 }
+
+// TestImpersonation tests that cluster admins (and only cluster admins) can
+// make requests on behalf of other users, with those users' permissions
+func TestImpersonation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+	deleteAll(t)
+	defer deleteAll(t)
+	alice, bob := tu.UniqueString("alice"), tu.UniqueString("bob")
+	aliceClient, bobClient := getPachClient(t, alice), getPachClient(t, bob)
+	adminClient := getPachClient(t, admin)
+
+	// alice creates a repo, which bob can't read
+	repo := tu.UniqueString(t.Name())
+	require.NoError(t, aliceClient.CreateRepo(repo))
+
+	// The admin impersonates bob, and sees bob's permission error
+	adminClient.Impersonate(gh(bob))
+	who, err := adminClient.WhoAmI(adminClient.Ctx(), &auth.WhoAmIRequest{})
+	require.NoError(t, err)
+	require.Equal(t, gh(bob), who.Username)
+	require.Equal(t, admin, who.Impersonator)
+	require.False(t, who.IsAdmin)
+	_, err = adminClient.ListFile(repo, "master", "")
+	require.YesError(t, err)
+	require.True(t, auth.IsErrNotAuthorized(err), err.Error())
+
+	// The admin impersonates alice, and can read the repo
+	adminClient.Impersonate(gh(alice))
+	_, err = adminClient.InspectRepo(repo)
+	require.NoError(t, err)
+
+	// Groups can't be impersonated
+	adminClient.Impersonate("group/github:admins")
+	_, err = adminClient.WhoAmI(adminClient.Ctx(), &auth.WhoAmIRequest{})
+	require.YesError(t, err)
+
+	// Non-admins can't impersonate anyone
+	bobClient.Impersonate(gh(alice))
+	_, err = bobClient.InspectRepo(repo)
+	require.YesError(t, err)
+	require.True(t, auth.IsErrNotAuthorized(err), err.Error())
+}
+
+// TestImpersonationCreatePipeline tests that a cluster admin can create a
+// pipeline on behalf of another user, and that PPS's own (superuser) requests
+// while it does so aren't made as that user
+func TestImpersonationCreatePipeline(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+	deleteAll(t)
+	defer deleteAll(t)
+	alice := tu.UniqueString("alice")
+	aliceClient, adminClient := getPachClient(t, alice), getPachClient(t, admin)
+
+	repo := tu.UniqueString(t.Name())
+	require.NoError(t, aliceClient.CreateRepo(repo))
+
+	// The admin creates and updates a pipeline as alice. Creating the output
+	// repo, writing the spec commit and setting the ACLs all succeed, and
+	// alice owns the output repo.
+	adminClient.Impersonate(gh(alice))
+	pipeline := tu.UniqueString("pipeline")
+	for _, update := range []bool{false, true} {
+		require.NoError(t, adminClient.CreatePipeline(
+			pipeline,
+			"", // default image: ubuntu:16.04
+			[]string{"bash"},
+			[]string{"cp /pfs/*/* /pfs/out/"},
+			&pps.ParallelismSpec{Constant: 1},
+			client.NewPFSInput(repo, "/*"),
+			"", // default output branch: master
+			update,
+		))
+	}
+	require.ElementsEqual(t,
+		entries(alice, "owner", pl(pipeline), "writer"), getACL(t, aliceClient, pipeline))
+	require.ElementsEqual(t,
+		entries(alice, "owner", pl(pipeline), "reader"), getACL(t, aliceClient, repo))
+
+	// The pipeline runs as itself, not as alice or the admin
+	_, err := aliceClient.PutFile(repo, "master", "/file", strings.NewReader("test"))
+	require.NoError(t, err)
+	iter, err := aliceClient.FlushCommit(
+		[]*pfs.Commit{client.NewCommit(repo, "master")},
+		[]*pfs.Repo{client.NewRepo(pipeline)},
+	)
+	require.NoError(t, err)
+	require.NoErrorWithinT(t, 60*time.Second, func() error {
+		_, err := iter.Next()
+		return err
+	})
+}
+
+// TestWhoAmIScopes tests that WhoAmI reports the caller's scope on each repo
+func TestWhoAmIScopes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+	deleteAll(t)
+	defer deleteAll(t)
+	alice, bob := tu.UniqueString("alice"), tu.UniqueString("bob")
+	aliceClient, bobClient := getPachClient(t, alice), getPachClient(t, bob)
+
+	repo1, repo2 := tu.UniqueString(t.Name()), tu.UniqueString(t.Name())
+	require.NoError(t, aliceClient.CreateRepo(repo1))
+	require.NoError(t, aliceClient.CreateRepo(repo2))
+	_, err := aliceClient.SetScope(aliceClient.Ctx(), &auth.SetScopeRequest{
+		Username: bob,
+		Scope:    auth.Scope_READER,
+		Repo:     repo2,
+	})
+	require.NoError(t, err)
+
+	who, err := bobClient.WhoAmI(bobClient.Ctx(), &auth.WhoAmIRequest{IncludeScopes: true})
+	require.NoError(t, err)
+	require.Equal(t, []*auth.RepoScope{{Repo: repo2, Scope: auth.Scope_READER}}, who.Scopes)
+
+	// Scopes are only included if requested
+	who, err = bobClient.WhoAmI(bobClient.Ctx(), &auth.WhoAmIRequest{})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(who.Scopes))
+}
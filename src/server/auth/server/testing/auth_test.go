@@ -2379,6 +2379,18 @@ func TestGetLogs(t *testing.T) {
 	require.YesError(t, iter.Err())
 	require.True(t, auth.IsErrNotAuthorized(iter.Err()), iter.Err().Error())
 
+	// bob can't get a job's logs from its start, or learn whether a job
+	// exists by trying to
+	jobs, err := aliceClient.ListJob(pipeline, nil /*inputs*/, nil /*output*/, -1 /*history*/, true)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobs))
+	for _, jobID := range []string{jobs[0].Job.ID, "nonexistent"} {
+		iter = bobClient.GetLogsInRange(pipeline, jobID, nil, "", false, false, 0, time.Time{}, time.Time{}, true)
+		require.False(t, iter.Next())
+		require.YesError(t, iter.Err())
+		require.True(t, auth.IsErrNotAuthorized(iter.Err()), iter.Err().Error())
+	}
+
 	// alice adds bob to the input repo, but bob still can't call GetLogs
 	aliceClient.SetScope(aliceClient.Ctx(), &auth.SetScopeRequest{
 		Username: bob,
@@ -2925,6 +2937,71 @@ func TestOneTimePasswordNoSubject(t *testing.T) {
 	require.Equal(t, gh(alice), who.Username)
 }
 
+// TestGetMountCredentials tests that mount credentials can only read the
+// repos that they're for
+func TestGetMountCredentials(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+	deleteAll(t)
+	defer deleteAll(t)
+
+	alice, bob := tu.UniqueString("alice"), tu.UniqueString("bob")
+	aliceClient, bobClient := getPachClient(t, alice), getPachClient(t, bob)
+	mountClient := getPachClient(t, "")
+	dataRepo, otherRepo := tu.UniqueString("TestGetMountCredentials"), tu.UniqueString("other")
+	require.NoError(t, aliceClient.CreateRepo(dataRepo))
+	require.NoError(t, aliceClient.CreateRepo(otherRepo))
+	_, err := aliceClient.PutFile(dataRepo, "master", "/file", strings.NewReader("1"))
+	require.NoError(t, err)
+	_, err = aliceClient.PutFile(otherRepo, "master", "/file", strings.NewReader("2"))
+	require.NoError(t, err)
+
+	// bob can't read dataRepo, so he can't mount it
+	target := &pps.MountTarget{Commit: client.NewCommit(dataRepo, "master")}
+	_, err = bobClient.GetMountCredentials([]*pps.MountTarget{target}, "", time.Minute)
+	require.YesError(t, err)
+
+	credentials, err := aliceClient.GetMountCredentials([]*pps.MountTarget{target}, "", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(credentials.Targets))
+	require.Equal(t, dataRepo, credentials.Targets[0].Name)
+	require.Equal(t, "/", credentials.Targets[0].Path)
+	require.NotEqual(t, "master", credentials.Targets[0].Commit.ID)
+	require.NotEqual(t, "", credentials.Token)
+	require.Equal(t, []string{dataRepo}, credentials.ReadableRepos)
+
+	// The credentials can read dataRepo (all of it, as reported by
+	// ReadableRepos), and nothing else
+	mountClient.SetAuthToken(credentials.Token)
+	who, err := mountClient.WhoAmI(mountClient.Ctx(), &auth.WhoAmIRequest{})
+	require.NoError(t, err)
+	require.Equal(t, gh(alice), who.Username)
+	require.ElementsEqual(t, []string{dataRepo}, who.ReadOnlyRepos)
+	buf := &bytes.Buffer{}
+	require.NoError(t, mountClient.GetFile(dataRepo, credentials.Targets[0].Commit.ID, "/file", 0, 0, buf))
+	require.Equal(t, "1", buf.String())
+	require.NoError(t, mountClient.GetFile(dataRepo, "master", "/file", 0, 0, &bytes.Buffer{}))
+	require.YesError(t, mountClient.GetFile(otherRepo, "master", "/file", 0, 0, &bytes.Buffer{}))
+	_, err = mountClient.PutFile(dataRepo, "master", "/file", strings.NewReader("3"))
+	require.YesError(t, err)
+	require.YesError(t, mountClient.CreateRepo(tu.UniqueString("new")))
+
+	// ...and can't be exchanged for credentials with more access
+	_, err = mountClient.GetOneTimePassword(mountClient.Ctx(), &auth.GetOneTimePasswordRequest{})
+	require.YesError(t, err)
+	_, err = mountClient.GetAuthToken(mountClient.Ctx(), &auth.GetAuthTokenRequest{
+		ReadOnlyRepos: []string{otherRepo},
+	})
+	require.YesError(t, err)
+	resp, err := mountClient.GetAuthToken(mountClient.Ctx(), &auth.GetAuthTokenRequest{})
+	require.NoError(t, err)
+	mountClient.SetAuthToken(resp.Token)
+	who, err = mountClient.WhoAmI(mountClient.Ctx(), &auth.WhoAmIRequest{})
+	require.NoError(t, err)
+	require.ElementsEqual(t, []string{dataRepo}, who.ReadOnlyRepos)
+}
+
 // TestOneTimePassword tests the GetOneTimePassword -> Authenticate auth flow
 func TestGetOneTimePassword(t *testing.T) {
 	if testing.Short() {
@@ -0,0 +1,120 @@
+// mount-helper is the entrypoint of the pachyderm/mount-helper sidecar image,
+// which lets in-cluster services such as JupyterHub notebooks read commits as
+// local files. It mounts the targets of a set of mount credentials (see
+// 'pachctl create mount-credentials') read-only, with FUSE, and links each
+// target's path to <dir>/<target name>. The directory must be shared with
+// the service's container with bidirectional mount propagation.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/hanwen/go-fuse/v2/fs"
+	gofuse "github.com/hanwen/go-fuse/v2/fuse"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pfs/fuse"
+)
+
+// credentialsEnvVar holds the JSON-encoded mount credentials, if
+// --credentials isn't set
+const credentialsEnvVar = "PACH_MOUNT_CREDENTIALS"
+
+// mountDir is the directory, under the shared directory, that the commits are
+// mounted at
+const mountDir = ".pfs"
+
+func main() {
+	credentialsPath := flag.String("credentials", "", "A file containing the JSON-encoded mount credentials (by default, they're read from $"+credentialsEnvVar+").")
+	dir := flag.String("dir", "/pfs", "The directory to mount the credentials' targets in.")
+	flag.Parse()
+	if err := run(*credentialsPath, *dir); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(credentialsPath, dir string) error {
+	credentials, err := readCredentials(credentialsPath)
+	if err != nil {
+		return err
+	}
+	c, err := client.NewFromAddress(credentials.PachdAddress)
+	if err != nil {
+		return errors.Wrapf(err, "error connecting to pachd at %s", credentials.PachdAddress)
+	}
+	defer c.Close()
+	if credentials.Token != "" {
+		c.SetAuthToken(credentials.Token)
+	}
+
+	repoOpts := make(map[string]*fuse.RepoOptions)
+	for _, target := range credentials.Targets {
+		repoOpts[target.Commit.Repo.Name] = &fuse.RepoOptions{Branch: target.Commit.ID}
+	}
+	if err := linkTargets(dir, credentials.Targets); err != nil {
+		return err
+	}
+	log.Infof("mounting %d commit(s) in %s", len(repoOpts), dir)
+	return fuse.Mount(c, filepath.Join(dir, mountDir), &fuse.Options{
+		Fuse: &fs.Options{
+			MountOptions: gofuse.MountOptions{
+				// The mount is read by the service's container, which may
+				// run as a different user
+				AllowOther: true,
+				FsName:     "pfs",
+				Name:       "pfs",
+			},
+		},
+		RepoOptions: repoOpts,
+	})
+}
+
+func readCredentials(credentialsPath string) (*pps.MountCredentials, error) {
+	var data string
+	if credentialsPath != "" {
+		bytes, err := ioutil.ReadFile(credentialsPath)
+		if err != nil {
+			return nil, errors.EnsureStack(err)
+		}
+		data = string(bytes)
+	} else if data = os.Getenv(credentialsEnvVar); data == "" {
+		return nil, errors.Errorf("no mount credentials (set --credentials or $%s)", credentialsEnvVar)
+	}
+	var credentials pps.MountCredentials
+	if err := jsonpb.UnmarshalString(data, &credentials); err != nil {
+		return nil, errors.Wrapf(err, "error parsing mount credentials")
+	}
+	if len(credentials.Targets) == 0 {
+		return nil, errors.New("the mount credentials have no targets")
+	}
+	return &credentials, nil
+}
+
+// linkTargets creates <dir>/<name> for each target, as a (relative) symlink
+// to the target's path in the mount
+func linkTargets(dir string, targets []*pps.MountTarget) error {
+	if err := os.MkdirAll(filepath.Join(dir, mountDir), 0755); err != nil {
+		return errors.EnsureStack(err)
+	}
+	for _, target := range targets {
+		link := filepath.Join(dir, target.Name)
+		dest := filepath.Join(mountDir, target.Commit.Repo.Name, strings.TrimPrefix(target.Path, "/"))
+		if err := os.Remove(link); err != nil && !os.IsNotExist(err) {
+			return errors.EnsureStack(err)
+		}
+		if err := os.Symlink(dest, link); err != nil {
+			return errors.EnsureStack(err)
+		}
+		fmt.Printf("%s -> %s@%s:%s\n", link, target.Commit.Repo.Name, target.Commit.ID, target.Path)
+	}
+	return nil
+}
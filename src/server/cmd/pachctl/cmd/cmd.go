@@ -476,13 +476,22 @@ Environment variables:
 	deleteAll := &cobra.Command{
 		Short: "Delete everything.",
 		Long: `Delete all repos, commits, files, pipelines and jobs.
-This resets the cluster to its initial state.`,
+This resets the cluster to its initial state.
+
+Clusters may disable this, or require it to be confirmed, with pachd's
+DELETE_ALL_POLICY; this command gets the confirmation before it prompts. Use
+'pachctl delete pipeline --all' or 'pachctl delete repo --all --prefix' to
+delete some of the cluster instead.`,
 		Run: cmdutil.RunFixedArgs(0, func(args []string) error {
 			client, err := client.NewOnUserMachine("user")
 			if err != nil {
 				return err
 			}
 			defer client.Close()
+			confirmation, err := client.PrepareDeleteAll()
+			if err != nil {
+				return err
+			}
 			red := color.New(color.FgRed).SprintFunc()
 			var repos, pipelines []string
 			repoInfos, err := client.ListRepo()
@@ -512,7 +521,7 @@ This resets the cluster to its initial state.`,
 				return nil
 			}
 
-			if err := client.DeleteAll(); err != nil {
+			if err := client.DeleteAllConfirmed(confirmation.Token); err != nil {
 				return err
 			}
 			return txncmds.ClearActiveTransaction()
@@ -715,6 +724,30 @@ This resets the cluster to its initial state.`,
 	}
 	subcommands = append(subcommands, cmdutil.CreateAlias(deleteDocs, "delete"))
 
+	archiveDocs := &cobra.Command{
+		Short: "Archive records that refer to deleted Pachyderm resources.",
+		Long:  "Archive records that refer to deleted Pachyderm resources.",
+	}
+	subcommands = append(subcommands, cmdutil.CreateAlias(archiveDocs, "archive"))
+
+	approveDocs := &cobra.Command{
+		Short: "Approve a pending Pachyderm resource.",
+		Long:  "Approve a pending Pachyderm resource.",
+	}
+	subcommands = append(subcommands, cmdutil.CreateAlias(approveDocs, "approve"))
+
+	searchDocs := &cobra.Command{
+		Short: "Find Pachyderm resources.",
+		Long:  "Find Pachyderm resources.",
+	}
+	subcommands = append(subcommands, cmdutil.CreateAlias(searchDocs, "search"))
+
+	scaleDocs := &cobra.Command{
+		Short: "Change the number of workers running a Pachyderm resource.",
+		Long:  "Change the number of workers running a Pachyderm resource.",
+	}
+	subcommands = append(subcommands, cmdutil.CreateAlias(scaleDocs, "scale"))
+
 	createDocs := &cobra.Command{
 		Short: "Create a new instance of a Pachyderm resource.",
 		Long:  "Create a new instance of a Pachyderm resource.",
@@ -805,18 +838,48 @@ This resets the cluster to its initial state.`,
 	}
 	subcommands = append(subcommands, cmdutil.CreateAlias(restartDocs, "restart"))
 
+	resolveDocs := &cobra.Command{
+		Short: "Find the Pachyderm resources that correspond to some data.",
+		Long:  "Find the Pachyderm resources that correspond to some data.",
+	}
+	subcommands = append(subcommands, cmdutil.CreateAlias(resolveDocs, "resolve"))
+
 	resumeDocs := &cobra.Command{
 		Short: "Resume a stopped task.",
 		Long:  "Resume a stopped task.",
 	}
 	subcommands = append(subcommands, cmdutil.CreateAlias(resumeDocs, "resume"))
 
+	pruneDocs := &cobra.Command{
+		Short: "Drop the data of old Pachyderm resources.",
+		Long:  "Drop the data of old Pachyderm resources.",
+	}
+	subcommands = append(subcommands, cmdutil.CreateAlias(pruneDocs, "prune"))
+
+	replayDocs := &cobra.Command{
+		Short: "Replay a recorded Pachyderm event.",
+		Long:  "Replay a recorded Pachyderm event.",
+	}
+	subcommands = append(subcommands, cmdutil.CreateAlias(replayDocs, "replay"))
+
 	runDocs := &cobra.Command{
 		Short: "Manually run a Pachyderm resource.",
 		Long:  "Manually run a Pachyderm resource.",
 	}
 	subcommands = append(subcommands, cmdutil.CreateAlias(runDocs, "run"))
 
+	applyDocs := &cobra.Command{
+		Short: "Make Pachyderm resources match a specification.",
+		Long:  "Make Pachyderm resources match a specification.",
+	}
+	subcommands = append(subcommands, cmdutil.CreateAlias(applyDocs, "apply"))
+
+	explainDocs := &cobra.Command{
+		Short: "Explain the behavior of a Pachyderm resource.",
+		Long:  "Explain the behavior of a Pachyderm resource.",
+	}
+	subcommands = append(subcommands, cmdutil.CreateAlias(explainDocs, "explain"))
+
 	editDocs := &cobra.Command{
 		Short: "Edit the value of an existing Pachyderm resource.",
 		Long:  "Edit the value of an existing Pachyderm resource.",
@@ -866,28 +929,38 @@ func applyRootUsageFunc(rootCmd *cobra.Command) {
 			"object",
 			"pipeline",
 			"repo",
-			"tag":
+			"tag",
+			"trash":
 			// These are ignored - they will show up in the help topics section
 		case
+			"apply",
+			"approve",
 			"copy",
 			"create",
 			"delete",
 			"diff",
 			"edit",
+			"explain",
 			"finish",
 			"flush",
 			"get",
 			"glob",
 			"inspect",
 			"list",
+			"prune",
 			"put",
+			"resolve",
 			"restart",
+			"scale",
+			"search",
 			"start",
 			"stop",
 			"subscribe",
+			"undelete",
 			"update":
 			actions = append(actions, subcmd)
 		case
+			"archive",
 			"deploy",
 			"undeploy",
 			"extract",
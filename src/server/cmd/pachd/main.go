@@ -6,10 +6,13 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"path"
 	"runtime/debug"
 	"runtime/pprof"
 	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/pachyderm/pachyderm/src/client"
 	adminclient "github.com/pachyderm/pachyderm/src/client/admin"
@@ -40,10 +43,13 @@ import (
 	"github.com/pachyderm/pachyderm/src/server/pkg/cmdutil"
 	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
 	"github.com/pachyderm/pachyderm/src/server/pkg/deploy/assets"
+	"github.com/pachyderm/pachyderm/src/server/pkg/eventbus"
+	"github.com/pachyderm/pachyderm/src/server/pkg/fault"
 	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
 	logutil "github.com/pachyderm/pachyderm/src/server/pkg/log"
 	"github.com/pachyderm/pachyderm/src/server/pkg/metrics"
 	"github.com/pachyderm/pachyderm/src/server/pkg/netutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ratelimit"
 	"github.com/pachyderm/pachyderm/src/server/pkg/serviceenv"
 	txnenv "github.com/pachyderm/pachyderm/src/server/pkg/transactionenv"
 	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
@@ -124,6 +130,12 @@ func doSidecarMode(config interface{}) (retErr error) {
 	if env.EtcdPrefix == "" {
 		env.EtcdPrefix = col.DefaultPrefix
 	}
+	if env.FaultInjection {
+		// must run before the Block API server is created, so that faults are
+		// injected into its object storage reads and writes
+		log.Warnf("fault injection is enabled; this cluster should only be used for testing")
+		fault.Enable(context.Background(), env.GetEtcdClient(), path.Join(env.EtcdPrefix, env.PPSEtcdPrefix))
+	}
 	clusterID, err := getClusterID(env.GetEtcdClient())
 	if err != nil {
 		return errors.Wrapf(err, "getClusterID")
@@ -154,7 +166,10 @@ func doSidecarMode(config interface{}) (retErr error) {
 			return errors.Wrapf(err, "units.RAMInBytes")
 		}
 		if err := logGRPCServerSetup("Block API", func() error {
-			blockAPIServer, err := pfs_server.NewBlockAPIServer(env.StorageRoot, blockCacheBytes, env.StorageBackend, net.JoinHostPort(env.EtcdHost, env.EtcdPort), false)
+			blockAPIServer, err := pfs_server.NewBlockAPIServer(env.StorageRoot, blockCacheBytes, env.StorageBackend, net.JoinHostPort(env.EtcdHost, env.EtcdPort), false, env.StorageResidencyBuckets, &pfs_server.ReplicaOptions{
+				Buckets: env.StorageReplicaBuckets,
+				Region:  env.StorageRegion,
+			})
 			if err != nil {
 				return err
 			}
@@ -267,6 +282,7 @@ func doSidecarMode(config interface{}) (retErr error) {
 		return err
 	}
 	txnEnv.Initialize(env, transactionAPIServer, authAPIServer, pfsAPIServer, ppsAPIServer)
+	go drainSidecar(env.PPSWorkerPort)
 	// The sidecar only needs to serve traffic on the peer port, as it only serves
 	// traffic from the user container (the worker binary and occasionally user
 	// pipelines)
@@ -276,6 +292,27 @@ func doSidecarMode(config interface{}) (retErr error) {
 	return server.Wait()
 }
 
+// drainSidecar keeps the sidecar serving after it receives SIGTERM, until the
+// worker in the same pod stops listening on 'workerPort'. A draining worker
+// still needs the sidecar to upload the output of its in-flight datums.
+// Kubernetes kills the sidecar anyway once the pod's termination grace period
+// is over.
+func drainSidecar(workerPort uint16) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGTERM)
+	<-sig
+	log.Infof("sidecar received SIGTERM, waiting for the worker to exit")
+	workerAddr := net.JoinHostPort("localhost", strconv.Itoa(int(workerPort)))
+	for {
+		conn, err := net.DialTimeout("tcp", workerAddr, time.Second)
+		if err != nil {
+			os.Exit(0)
+		}
+		conn.Close()
+		time.Sleep(time.Second)
+	}
+}
+
 func doFullMode(config interface{}) (retErr error) {
 	defer func() {
 		if retErr != nil {
@@ -310,6 +347,12 @@ func doFullMode(config interface{}) (retErr error) {
 	if env.EtcdPrefix == "" {
 		env.EtcdPrefix = col.DefaultPrefix
 	}
+	if env.FaultInjection {
+		// must run before the Block API server is created, so that faults are
+		// injected into its object storage reads and writes
+		log.Warnf("fault injection is enabled; this cluster should only be used for testing")
+		fault.Enable(context.Background(), env.GetEtcdClient(), path.Join(env.EtcdPrefix, env.PPSEtcdPrefix))
+	}
 	clusterID, err := getClusterID(env.GetEtcdClient())
 	if err != nil {
 		return errors.Wrapf(err, "getClusterID")
@@ -356,8 +399,31 @@ func doFullMode(config interface{}) (retErr error) {
 	}
 	kubeNamespace := env.Namespace
 	requireNoncriticalServers := !env.RequireCriticalServersOnly
+	// The External Pachd GRPC Server enforces the rate limits in RATE_LIMITS.
+	// The principals that make calls are looked up with its Auth API server,
+	// which is created below.
+	var externalAuthAPIServer authserver.APIServer
+	var limiter *ratelimit.Limiter
+	var interceptors []grpcutil.Interceptor
+	if env.RateLimits != "" {
+		rules, err := ratelimit.ParseRules(env.RateLimits)
+		if err != nil {
+			return errors.Wrapf(err, "invalid RATE_LIMITS")
+		}
+		limiter = ratelimit.NewLimiter(rules, func(ctx context.Context) (string, error) {
+			if externalAuthAPIServer == nil {
+				return "", errors.New("the Auth API server hasn't been created yet")
+			}
+			resp, err := externalAuthAPIServer.WhoAmI(ctx, &authclient.WhoAmIRequest{})
+			if err != nil {
+				return "", err
+			}
+			return resp.Username, nil
+		})
+		interceptors = append(interceptors, limiter)
+	}
 	// Setup External Pachd GRPC Server.
-	externalServer, err := grpcutil.NewServer(context.Background(), true)
+	externalServer, err := grpcutil.NewServer(context.Background(), true, interceptors...)
 	if err != nil {
 		return err
 	}
@@ -419,7 +485,9 @@ func doFullMode(config interface{}) (retErr error) {
 						env.StorageRoot,
 						0 /* = blockCacheBytes (disable cache) */, env.StorageBackend,
 						etcdAddress,
-						true /* duplicate */)
+						true, /* duplicate */
+						env.StorageResidencyBuckets,
+						nil /* = replicas (read from the default bucket) */)
 					if err != nil {
 						return err
 					}
@@ -437,6 +505,7 @@ func doFullMode(config interface{}) (retErr error) {
 			if err != nil {
 				return err
 			}
+			externalAuthAPIServer = authAPIServer
 			authclient.RegisterAPIServer(externalServer.Server, authAPIServer)
 			return nil
 		}); err != nil {
@@ -469,10 +538,10 @@ func doFullMode(config interface{}) (retErr error) {
 			return err
 		}
 		if err := logGRPCServerSetup("Admin API", func() error {
-			adminclient.RegisterAPIServer(externalServer.Server, adminserver.NewAPIServer(address, env.StorageRoot, &adminclient.ClusterInfo{
+			adminclient.RegisterAPIServer(externalServer.Server, adminserver.NewAPIServer(env, address, env.StorageRoot, &adminclient.ClusterInfo{
 				ID:           clusterID,
 				DeploymentID: env.DeploymentID,
-			}))
+			}, limiter))
 			return nil
 		}); err != nil {
 			return err
@@ -535,7 +604,12 @@ func doFullMode(config interface{}) (retErr error) {
 			}
 			if err := logGRPCServerSetup("Block API", func() error {
 				blockAPIServer, err := pfs_server.NewBlockAPIServer(
-					env.StorageRoot, blockCacheBytes, env.StorageBackend, etcdAddress, false)
+					env.StorageRoot, blockCacheBytes, env.StorageBackend, etcdAddress, false, env.StorageResidencyBuckets,
+					&pfs_server.ReplicaOptions{
+						Buckets:         env.StorageReplicaBuckets,
+						Region:          env.StorageRegion,
+						WriteHeartbeats: true,
+					})
 				if err != nil {
 					return err
 				}
@@ -656,10 +730,10 @@ func doFullMode(config interface{}) (retErr error) {
 			return err
 		}
 		if err := logGRPCServerSetup("Admin API", func() error {
-			adminclient.RegisterAPIServer(internalServer.Server, adminserver.NewAPIServer(address, env.StorageRoot, &adminclient.ClusterInfo{
+			adminclient.RegisterAPIServer(internalServer.Server, adminserver.NewAPIServer(env, address, env.StorageRoot, &adminclient.ClusterInfo{
 				ID:           clusterID,
 				DeploymentID: env.DeploymentID,
-			}))
+			}, nil))
 			return nil
 		}); err != nil {
 			return err
@@ -710,7 +784,11 @@ func doFullMode(config interface{}) (retErr error) {
 		return server.ListenAndServeTLS(certPath, keyPath)
 	})
 	go waitForError("Githook Server", errChan, requireNoncriticalServers, func() error {
-		return githook.RunGitHookServer(address, etcdAddress, path.Join(env.EtcdPrefix, env.PPSEtcdPrefix))
+		return githook.RunGitHookServer(address, etcdAddress, path.Join(env.EtcdPrefix, env.PPSEtcdPrefix), githook.Secrets{
+			GitHub:            env.GithookGitHubSecret,
+			GitLab:            env.GithookGitLabSecret,
+			BitbucketHookUUID: env.GithookBitbucketHookUUID,
+		})
 	})
 	go waitForError("S3 Server", errChan, requireNoncriticalServers, func() error {
 		server, err := s3.Server(env.S3GatewayPort, s3.NewMasterDriver(), func() (*client.APIClient, error) {
@@ -737,6 +815,22 @@ func doFullMode(config interface{}) (retErr error) {
 		http.Handle("/metrics", promhttp.Handler())
 		return http.ListenAndServe(fmt.Sprintf(":%v", assets.PrometheusPort), nil)
 	})
+	if env.EventBusURL != "" {
+		go waitForError("Event Bus Exporter", errChan, requireNoncriticalServers, func() error {
+			publisher, err := eventbus.NewPublisher(env.EventBusURL)
+			if err != nil {
+				return err
+			}
+			defer publisher.Close()
+			return eventbus.NewExporter(
+				env.GetEtcdClient(),
+				env.EtcdPrefix,
+				path.Join(env.EtcdPrefix, env.PFSEtcdPrefix),
+				path.Join(env.EtcdPrefix, env.PPSEtcdPrefix),
+				publisher,
+			).Run(context.Background())
+		})
+	}
 	return <-errChan
 }
 
@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"os"
+	"os/signal"
 	"path"
+	"syscall"
 	"time"
 
 	"github.com/pachyderm/pachyderm/src/client"
@@ -16,6 +19,7 @@ import (
 	"github.com/pachyderm/pachyderm/src/server/cmd/worker/assets"
 	debugserver "github.com/pachyderm/pachyderm/src/server/debug/server"
 	"github.com/pachyderm/pachyderm/src/server/pkg/cmdutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/fault"
 	logutil "github.com/pachyderm/pachyderm/src/server/pkg/log"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
 	"github.com/pachyderm/pachyderm/src/server/pkg/serviceenv"
@@ -73,6 +77,9 @@ func do(config interface{}) error {
 	// must run InstallJaegerTracer before InitWithKube/pach client initialization
 	tracing.InstallJaegerTracerFromEnv()
 	env := serviceenv.InitServiceEnv(serviceenv.NewConfiguration(config))
+	if env.FaultInjection {
+		fault.Enable(context.Background(), env.GetEtcdClient(), env.PPSEtcdPrefix)
+	}
 
 	// Construct a client that connects to the sidecar.
 	pachClient := env.GetPachClient(context.Background())
@@ -83,11 +90,25 @@ func do(config interface{}) error {
 
 	// Construct worker API server.
 	workerRcName := ppsutil.PipelineRcName(pipelineInfo.Pipeline.Name, pipelineInfo.Version)
-	workerInstance, err := worker.NewWorker(pachClient, env.GetEtcdClient(), env.PPSEtcdPrefix, pipelineInfo, env.PodName, env.Namespace, env.StorageRoot, "/")
+	workerInstance, err := worker.NewWorker(pachClient, env.GetEtcdClient(), env.PPSEtcdPrefix, pipelineInfo, env.PodName, env.Namespace, env.StorageRoot, "/", env.PPSWorkerClass)
 	if err != nil {
 		return err
 	}
 
+	// Kubernetes sends SIGTERM before it evicts a worker (e.g. when the
+	// worker's node is drained). Finish the datums that are already running
+	// and hand the rest to other workers, rather than being killed mid-datum.
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGTERM)
+		<-sig
+		log.Infof("worker received SIGTERM, draining")
+		if err := workerInstance.Drain(context.Background()); err != nil {
+			log.Errorf("error draining worker: %v", err)
+		}
+		os.Exit(0)
+	}()
+
 	// Start worker api server
 	server, err := grpcutil.NewServer(context.Background(), false)
 	if err != nil {
@@ -99,10 +99,9 @@ func Cmds() []*cobra.Command {
 			if err != nil {
 				return err
 			}
-			if _, ok := cfg.V2.Contexts[args[0]]; !ok {
-				return errors.Errorf("context does not exist: %s", args[0])
+			if err := cfg.SetActiveContext(args[0]); err != nil {
+				return err
 			}
-			cfg.V2.ActiveContext = args[0]
 			return cfg.Write()
 		}),
 	}
@@ -211,6 +210,7 @@ func Cmds() []*cobra.Command {
 	var authInfo string
 	var serverCAs string
 	var namespace string
+	var tlsServerName string
 	var removeClusterDeploymentID bool
 	var updateContext *cobra.Command // standalone declaration so Run() can refer
 	updateContext = &cobra.Command{
@@ -269,6 +269,9 @@ func Cmds() []*cobra.Command {
 			if updateContext.Flags().Changed("namespace") {
 				context.Namespace = namespace
 			}
+			if updateContext.Flags().Changed("tls-server-name") {
+				context.TLSServerName = tlsServerName
+			}
 			if removeClusterDeploymentID {
 				context.ClusterDeploymentID = ""
 			}
@@ -281,6 +284,7 @@ func Cmds() []*cobra.Command {
 	updateContext.Flags().StringVar(&authInfo, "auth-info", "", "Set a new k8s auth info.")
 	updateContext.Flags().StringVar(&serverCAs, "server-cas", "", "Set new trusted CA certs.")
 	updateContext.Flags().StringVar(&namespace, "namespace", "", "Set a new namespace.")
+	updateContext.Flags().StringVar(&tlsServerName, "tls-server-name", "", "Set the name that pachd's TLS certificate is verified against, if it isn't pachd's hostname.")
 	updateContext.Flags().BoolVar(&removeClusterDeploymentID, "remove-cluster-deployment-id", false, "Remove the cluster deployment ID field, which will be repopulated on the next `pachctl` call using this context.")
 	shell.RegisterCompletionFunc(updateContext, contextCompletion)
 	commands = append(commands, cmdutil.CreateAlias(updateContext, "config update context"))
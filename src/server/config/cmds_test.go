@@ -55,6 +55,24 @@ func TestEnvValue(t *testing.T) {
 	`))
 }
 
+func TestEnvPachdAddress(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	// the context that points at PACHD_ADDRESS is selected automatically,
+	// unless PACH_CONTEXT is set
+	require.NoError(t, run(t, `
+		echo '{"pachd_address": "foo:9000"}' | pachctl config set context foo
+		echo '{"pachd_address": "bar:9000"}' | pachctl config set context bar
+		pachctl config set active-context bar
+		export PACHD_ADDRESS=foo:9000
+		pachctl config get active-context | match foo
+		PACH_CONTEXT=bar pachctl config get active-context | match bar
+		PACHD_ADDRESS=baz:9000 pachctl config get active-context | match bar
+	`))
+}
+
 func TestMetrics(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration tests in short mode")
@@ -121,6 +139,8 @@ func TestUpdateContext(t *testing.T) {
 		pachctl config get context foo | match '"pachd_address": "grpc://foobar:9000"'
 		pachctl config update context foo --pachd-address=""
 		pachctl config get context foo | match -v pachd_address
+		pachctl config update context foo --tls-server-name=pachd.example.com
+		pachctl config get context foo | match '"tls_server_name": "pachd.example.com"'
 	`))
 }
 
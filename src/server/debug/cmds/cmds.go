@@ -102,6 +102,39 @@ func Cmds() []*cobra.Command {
 	dump.Flags().StringVarP(&worker, "worker", "w", "", "Only collect the dump from the given worker pod.")
 	commands = append(commands, cmdutil.CreateAlias(dump, "debug dump"))
 
+	var since time.Duration
+	var maxFailedJobs int64
+	bundle := &cobra.Command{
+		Use:   "{{alias}} <file>",
+		Short: "Collect a bundle of debugging information for support.",
+		Long: "Collect a bundle of debugging information for support: pachd's logs and goroutines, " +
+			"etcd key statistics, recently failed jobs, pipeline specs and the cluster's version. " +
+			"Secrets are redacted from the bundle, which is written to <file> as a gzipped tar archive.",
+		Example: `
+# collect a bundle with the last day's logs and failed jobs
+$ {{alias}} bundle.tar.gz
+
+# collect a bundle with the last hour's logs and failed jobs
+$ {{alias}} bundle.tar.gz --since 1h`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			client, err := client.NewOnUserMachine("debug-bundle")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			request := &debug.CollectDebugBundleRequest{
+				Since:         types.DurationProto(since),
+				MaxFailedJobs: maxFailedJobs,
+			}
+			return withFile(args[0], func(f *os.File) error {
+				return client.CollectDebugBundle(request, f)
+			})
+		}),
+	}
+	bundle.Flags().DurationVar(&since, "since", 24*time.Hour, "Only collect logs and failed jobs from this long ago.")
+	bundle.Flags().Int64Var(&maxFailedJobs, "max-failed-jobs", 100, "The maximum number of failed jobs to collect.")
+	commands = append(commands, cmdutil.CreateAlias(bundle, "debug bundle"))
+
 	debug := &cobra.Command{
 		Short: "Debug commands for analyzing a running cluster.",
 		Long:  "Debug commands for analyzing a running cluster.",
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	etcd "github.com/coreos/etcd/clientv3"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/debug"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultBundleSince   = 24 * time.Hour
+	defaultMaxFailedJobs = 100
+	// etcdStatsBatch is how many etcd keys are read at a time when computing
+	// key statistics
+	etcdStatsBatch = 1000
+)
+
+// CollectDebugBundle implements the protobuf debug.CollectDebugBundle RPC
+func (s *debugServer) CollectDebugBundle(request *debug.CollectDebugBundleRequest, server debug.Debug_CollectDebugBundleServer) error {
+	since := defaultBundleSince
+	if request.Since != nil {
+		var err error
+		if since, err = types.DurationFromProto(request.Since); err != nil {
+			return errors.EnsureStack(err)
+		}
+		if since <= 0 {
+			return errors.Errorf("since must be positive, not %v", since)
+		}
+	}
+	maxFailedJobs := request.MaxFailedJobs
+	if maxFailedJobs == 0 {
+		maxFailedJobs = defaultMaxFailedJobs
+	} else if maxFailedJobs < 0 {
+		return errors.Errorf("max_failed_jobs cannot be negative")
+	}
+	pachClient := s.env.GetPachClient(server.Context())
+	if err := checkBundleAdmin(pachClient); err != nil {
+		return err
+	}
+	start := time.Now()
+	return withDebugWriter(grpcutil.NewStreamingBytesWriter(server), func(tw *tar.Writer) error {
+		// Each part of the bundle is collected even if another fails, and
+		// failures are recorded in the bundle's "error" files
+		if err := collectDebugFile(tw, "bundle", func(w io.Writer) error {
+			_, err := fmt.Fprintf(w, "collected: %s\npachd: %s\nsince: %s\n", start.UTC().Format(time.RFC3339), s.name, since)
+			return err
+		}); err != nil {
+			return err
+		}
+		if err := s.collectPachdVersion(tw); err != nil {
+			return err
+		}
+		if err := s.collectPachdLogs(tw, since); err != nil {
+			return err
+		}
+		if err := collectProfile(tw, &debug.Profile{Name: "goroutine"}, join(pachdPrefix, s.name)); err != nil {
+			return err
+		}
+		if err := collectDebugFile(tw, "etcd/stats", func(w io.Writer) error {
+			return s.writeEtcdStats(server.Context(), w)
+		}); err != nil {
+			return err
+		}
+		if err := collectDebugFile(tw, "jobs/failed", func(w io.Writer) error {
+			return writeFailedJobs(pachClient, w, start.Add(-since), maxFailedJobs)
+		}); err != nil {
+			return err
+		}
+		return s.collectPipelineSpecs(tw, pachClient)
+	})
+}
+
+// checkBundleAdmin returns an error if auth is active and the caller isn't
+// a cluster admin, since a bundle describes the whole cluster
+func checkBundleAdmin(pachClient *client.APIClient) error {
+	me, err := pachClient.WhoAmI(pachClient.Ctx(), &auth.WhoAmIRequest{})
+	if err != nil && !auth.IsErrNotActivated(err) {
+		return grpcutil.ScrubGRPC(err)
+	} else if err == nil && !me.IsAdmin {
+		return &auth.ErrNotAuthorized{Subject: me.Username, AdminOp: "CollectDebugBundle"}
+	}
+	return nil
+}
+
+// collectPachdLogs collects the logs of every pachd pod from the last
+// 'since', with secrets redacted.
+func (s *debugServer) collectPachdLogs(tw *tar.Writer, since time.Duration) error {
+	pods, err := s.env.GetKubeClient().CoreV1().Pods(s.env.Namespace).List(metav1.ListOptions{
+		LabelSelector: metav1.FormatLabelSelector(metav1.SetAsLabelSelector(map[string]string{"app": "pachd"})),
+	})
+	if err != nil {
+		return writeErrorFile(tw, err, pachdPrefix)
+	}
+	sinceSeconds := int64(since / time.Second)
+	for _, pod := range pods.Items {
+		if err := collectDebugFile(tw, "logs", func(w io.Writer) (retErr error) {
+			stream, err := s.env.GetKubeClient().CoreV1().Pods(s.env.Namespace).GetLogs(pod.Name, &v1.PodLogOptions{
+				Container:    "pachd",
+				SinceSeconds: &sinceSeconds,
+			}).Stream()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := stream.Close(); retErr == nil {
+					retErr = err
+				}
+			}()
+			rw := newRedactingWriter(w)
+			if _, err := io.Copy(rw, stream); err != nil {
+				return err
+			}
+			return rw.Flush()
+		}, join(pachdPrefix, pod.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyStats counts the keys under an etcd prefix, and their size
+type keyStats struct {
+	keys, bytes int64
+}
+
+// etcdKeyGroup returns the group that 'key' is counted in: its first two
+// path elements (e.g. "pachyderm_pps/jobs").
+func etcdKeyGroup(key string) string {
+	parts := strings.SplitN(strings.TrimPrefix(key, "/"), "/", 3)
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return strings.Join(parts, "/")
+}
+
+// writeEtcdStats writes the number and size of the keys in each group of
+// pachyderm's etcd keys. Only the keys' names are summarized, never their
+// values.
+func (s *debugServer) writeEtcdStats(ctx context.Context, w io.Writer) error {
+	etcdClient := s.env.GetEtcdClient()
+	prefix := s.env.EtcdPrefix
+	stats := make(map[string]*keyStats)
+	key, end := prefix, etcd.GetPrefixRangeEnd(prefix)
+	for {
+		resp, err := etcdClient.Get(ctx, key, etcd.WithRange(end), etcd.WithLimit(etcdStatsBatch),
+			etcd.WithSort(etcd.SortByKey, etcd.SortAscend))
+		if err != nil {
+			return errors.EnsureStack(err)
+		}
+		for _, kv := range resp.Kvs {
+			group := etcdKeyGroup(strings.TrimPrefix(string(kv.Key), prefix))
+			if stats[group] == nil {
+				stats[group] = &keyStats{}
+			}
+			stats[group].keys++
+			stats[group].bytes += int64(len(kv.Key) + len(kv.Value))
+		}
+		if !resp.More || len(resp.Kvs) == 0 {
+			break
+		}
+		key = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+	groups := make([]string, 0, len(stats))
+	for group := range stats {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "PREFIX\tKEYS\tBYTES\n")
+	for _, group := range groups {
+		fmt.Fprintf(tw, "%s\t%d\t%d\n", path.Join(prefix, group), stats[group].keys, stats[group].bytes)
+	}
+	return tw.Flush()
+}
+
+// writeFailedJobs writes the most recent failed jobs (at most 'max') that
+// finished after 'cutoff', with their reasons.
+func writeFailedJobs(pachClient *client.APIClient, w io.Writer, cutoff time.Time, max int64) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "JOB\tPIPELINE\tFINISHED\tFAILURE CAUSE\tREASON\n")
+	var n int64
+	if err := pachClient.ListJobF("", nil, nil, -1, false, func(ji *pps.JobInfo) error {
+		if ji.State != pps.JobState_JOB_FAILURE || ji.Finished == nil {
+			return nil
+		}
+		finished, err := types.TimestampFromProto(ji.Finished)
+		if err != nil || finished.Before(cutoff) {
+			return nil
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", ji.Job.ID, ji.Pipeline.Name, finished.UTC().Format(time.RFC3339),
+			ji.FailureCause, redactString(strings.Replace(ji.Reason, "\n", " ", -1)))
+		if n++; n >= max {
+			return errutil.ErrBreak
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return tw.Flush()
+}
+
+// collectPipelineSpecs collects the spec of every pipeline, with secrets
+// redacted.
+func (s *debugServer) collectPipelineSpecs(tw *tar.Writer, pachClient *client.APIClient) error {
+	pipelineInfos, err := pachClient.ListPipeline()
+	if err != nil {
+		return writeErrorFile(tw, err, pipelinePrefix)
+	}
+	marshaller := &jsonpb.Marshaler{Indent: "  "}
+	for _, pipelineInfo := range pipelineInfos {
+		if err := collectDebugFile(tw, "spec.json", func(w io.Writer) error {
+			spec := ppsutil.PipelineReqFromInfo(pipelineInfo)
+			if spec.Transform != nil {
+				transform := *spec.Transform
+				transform.Env = redactEnv(transform.Env)
+				spec.Transform = &transform
+			}
+			js, err := marshaller.MarshalToString(spec)
+			if err != nil {
+				return errors.EnsureStack(err)
+			}
+			_, err = io.WriteString(w, redactString(js)+"\n")
+			return err
+		}, join(pipelinePrefix, pipelineInfo.Pipeline.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
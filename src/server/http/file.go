@@ -0,0 +1,155 @@
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+)
+
+// The query parameters of a signed file URL (see GetFileURL)
+const (
+	expiresParam   = "expires"
+	signatureParam = "signature"
+)
+
+// ErrInvalidURLSignature is returned when a file is read with a URL whose
+// signature is invalid or expired.
+var ErrInvalidURLSignature = errors.New("the URL's signature is invalid or expired")
+
+// IsErrInvalidURLSignature returns true if 'err' is (or was returned by pachd
+// for) an ErrInvalidURLSignature.
+func IsErrInvalidURLSignature(err error) bool {
+	return err != nil && strings.Contains(err.Error(), ErrInvalidURLSignature.Error())
+}
+
+// SignedFilePath returns the path and query of the URL on the HTTP server
+// that serves 'file' to whoever has 'signature'.
+func SignedFilePath(file *pfs.File, signature *pfs.URLSignature) string {
+	u := url.URL{Path: strings.Replace(getFilePath, ":repoName", file.Commit.Repo.Name, 1)}
+	u.Path = strings.Replace(u.Path, ":commitID", file.Commit.ID, 1)
+	u.Path = strings.Replace(u.Path, "/*filePath", "/"+strings.TrimPrefix(file.Path, "/"), 1)
+	u.RawQuery = url.Values{
+		expiresParam:   {strconv.FormatInt(signature.Expires, 10)},
+		signatureParam: {base64.RawURLEncoding.EncodeToString(signature.Signature)},
+	}.Encode()
+	return u.String()
+}
+
+// parseURLSignature returns the signature in the query of a signed file URL.
+func parseURLSignature(query url.Values) (*pfs.URLSignature, error) {
+	expires, err := strconv.ParseInt(query.Get(expiresParam), 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("invalid %s %q", expiresParam, query.Get(expiresParam))
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(query.Get(signatureParam))
+	if err != nil {
+		return nil, errors.Errorf("invalid %s %q", signatureParam, query.Get(signatureParam))
+	}
+	return &pfs.URLSignature{Expires: expires, Signature: signature}, nil
+}
+
+// pfsFile is a file that the HTTP server reads from PFS, either as the
+// caller or, if the request's URL is signed, with the URL's signature.
+type pfsFile struct {
+	c         *client.APIClient
+	file      *pfs.File
+	signature *pfs.URLSignature
+}
+
+func (f *pfsFile) inspect() (*pfs.FileInfo, error) {
+	fileInfo, err := f.c.PfsAPIClient.InspectFile(f.c.Ctx(), &pfs.InspectFileRequest{
+		File:         f.file,
+		UrlSignature: f.signature,
+	})
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return fileInfo, nil
+}
+
+// reader returns a reader of 'size' bytes of the file (or the rest of it, if
+// 'size' is 0) from 'offset'.
+func (f *pfsFile) reader(offset, size int64) (io.Reader, error) {
+	return f.readerWithCtx(f.c.Ctx(), offset, size)
+}
+
+// readerWithCtx is like reader, but the file is read until 'ctx' is done.
+func (f *pfsFile) readerWithCtx(ctx context.Context, offset, size int64) (io.Reader, error) {
+	getFileClient, err := f.c.PfsAPIClient.GetFile(ctx, &pfs.GetFileRequest{
+		File:         f.file,
+		OffsetBytes:  offset,
+		SizeBytes:    size,
+		UrlSignature: f.signature,
+	})
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return grpcutil.NewStreamingBytesReader(getFileClient, nil), nil
+}
+
+// readSeeker returns a ReadSeeker of the file, which is 'size' bytes. The
+// file is only read from once Read is called, so seeking is cheap. Seeking
+// stops the previous read, so that serving a request for several ranges of
+// the file (which http.ServeContent does by seeking to each one) doesn't leave
+// a read open for each range.
+func (f *pfsFile) readSeeker(size int64) io.ReadSeeker {
+	return &fileReadSeeker{f: f, size: size}
+}
+
+type fileReadSeeker struct {
+	f      *pfsFile
+	size   int64
+	offset int64
+	r      io.Reader
+	// cancel stops the read that 'r' is reading from
+	cancel context.CancelFunc
+}
+
+func (r *fileReadSeeker) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	if r.r == nil {
+		ctx, cancel := context.WithCancel(r.f.c.Ctx())
+		reader, err := r.f.readerWithCtx(ctx, r.offset, 0)
+		if err != nil {
+			cancel()
+			return 0, err
+		}
+		r.r, r.cancel = reader, cancel
+	}
+	n, err := r.r.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+func (r *fileReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return r.offset, errors.Errorf("invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return r.offset, errors.Errorf("can't seek to negative offset %d", offset)
+	}
+	if offset != r.offset {
+		r.offset = offset
+		if r.cancel != nil {
+			r.cancel()
+		}
+		r.r, r.cancel = nil, nil
+	}
+	return r.offset, nil
+}
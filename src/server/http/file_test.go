@@ -0,0 +1,47 @@
+package http
+
+import (
+	"io"
+	"net/url"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestSignedFilePath(t *testing.T) {
+	signature := &pfs.URLSignature{Expires: 1591747200, Signature: []byte{0xfb, 0xff, 0x01}}
+	u, err := url.Parse(SignedFilePath(client.NewFile("images", "abc", "/cats/a b.png"), signature))
+	require.NoError(t, err)
+	require.Equal(t, "/v1/pfs/repos/images/commits/abc/files/cats/a b.png", u.Path)
+	parsed, err := parseURLSignature(u.Query())
+	require.NoError(t, err)
+	require.Equal(t, signature, parsed)
+
+	_, err = parseURLSignature(url.Values{expiresParam: {"soon"}, signatureParam: {"-_8B"}})
+	require.YesError(t, err)
+	_, err = parseURLSignature(url.Values{expiresParam: {"1591747200"}, signatureParam: {"!"}})
+	require.YesError(t, err)
+}
+
+func TestFileReadSeeker(t *testing.T) {
+	r := (&pfsFile{}).readSeeker(100)
+	offset, err := r.Seek(0, io.SeekEnd)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), offset)
+	offset, err = r.Seek(-10, io.SeekCurrent)
+	require.NoError(t, err)
+	require.Equal(t, int64(90), offset)
+	offset, err = r.Seek(20, io.SeekStart)
+	require.NoError(t, err)
+	require.Equal(t, int64(20), offset)
+	_, err = r.Seek(-1, io.SeekStart)
+	require.YesError(t, err)
+
+	// Reading past the end doesn't read the file
+	_, err = r.Seek(0, io.SeekEnd)
+	require.NoError(t, err)
+	_, err = r.Read(make([]byte, 1))
+	require.Equal(t, io.EOF, err)
+}
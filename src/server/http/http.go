@@ -15,7 +15,6 @@ import (
 
 	"github.com/gogo/protobuf/types"
 	"github.com/julienschmidt/httprouter"
-	"golang.org/x/net/context"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -66,7 +65,8 @@ func NewHTTPServer(address string) (http.Handler, error) {
 func (s *server) getFileHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	filePaths := strings.Split(ps.ByName("filePath"), "/")
 	fileName := filePaths[len(filePaths)-1]
-	ctx := context.Background()
+	// Reads of the file stop when the request is done
+	ctx := r.Context()
 	for _, cookie := range r.Cookies() {
 		if cookie.Name == auth.ContextTokenKey {
 			ctx = metadata.NewIncomingContext(
@@ -79,23 +79,33 @@ func (s *server) getFileHandler(w http.ResponseWriter, r *http.Request, ps httpr
 	if len(downloadValues) == 1 && downloadValues[0] == "true" {
 		w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%v\"", fileName))
 	}
-	c := s.getPachClient().WithCtx(ctx)
-	commitInfo, err := c.InspectCommit(ps.ByName("repoName"), ps.ByName("commitID"))
-	if err != nil {
-		httpError(w, err)
+	f := &pfsFile{
+		c:    s.getPachClient().WithCtx(ctx),
+		file: client.NewFile(ps.ByName("repoName"), ps.ByName("commitID"), ps.ByName("filePath")),
+	}
+	if r.URL.Query().Get(signatureParam) != "" {
+		signature, err := parseURLSignature(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.signature = signature
+	}
+	if r.URL.Query().Get("preview") != "" {
+		s.previewFile(w, r, f)
 		return
 	}
-	content, err := c.GetFileReadSeeker(ps.ByName("repoName"), ps.ByName("commitID"), ps.ByName("filePath"))
+	fileInfo, err := f.inspect()
 	if err != nil {
 		httpError(w, err)
 		return
 	}
-	modtime, err := types.TimestampFromProto(commitInfo.Finished)
+	modtime, err := types.TimestampFromProto(fileInfo.Committed)
 	if err != nil {
 		httpError(w, err)
 		return
 	}
-	http.ServeContent(w, r, fileName, modtime, content)
+	http.ServeContent(w, r, fileName, modtime, f.readSeeker(int64(fileInfo.SizeBytes)))
 }
 
 func (s *server) serviceHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
@@ -145,6 +155,8 @@ func notFound(w http.ResponseWriter, r *http.Request) {
 func httpError(w http.ResponseWriter, err error) {
 	if errutil.IsNotFoundError(err) {
 		http.Error(w, err.Error(), http.StatusNotFound)
+	} else if IsErrInvalidURLSignature(err) || auth.IsErrNotAuthorized(err) {
+		http.Error(w, err.Error(), http.StatusForbidden)
 	} else {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
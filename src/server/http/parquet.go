@@ -0,0 +1,401 @@
+package http
+
+import (
+	"encoding/binary"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+)
+
+// parquetMagic begins and ends every parquet file.
+const parquetMagic = "PAR1"
+
+// The names of parquet's physical types, repetition types and converted
+// types, indexed by their values in the parquet format's Thrift definitions.
+var (
+	parquetTypes = []string{
+		"BOOLEAN", "INT32", "INT64", "INT96", "FLOAT", "DOUBLE", "BYTE_ARRAY", "FIXED_LEN_BYTE_ARRAY",
+	}
+	parquetRepetitions    = []string{"REQUIRED", "OPTIONAL", "REPEATED"}
+	parquetConvertedTypes = []string{
+		"UTF8", "MAP", "MAP_KEY_VALUE", "LIST", "ENUM", "DECIMAL", "DATE", "TIME_MILLIS", "TIME_MICROS",
+		"TIMESTAMP_MILLIS", "TIMESTAMP_MICROS", "UINT_8", "UINT_16", "UINT_32", "UINT_64", "INT_8",
+		"INT_16", "INT_32", "INT_64", "JSON", "BSON", "INTERVAL",
+	}
+)
+
+// parquetPreview is the preview of a parquet file.
+type parquetPreview struct {
+	NumRows   int64           `json:"num_rows"`
+	RowGroups int             `json:"row_groups"`
+	CreatedBy string          `json:"created_by,omitempty"`
+	Columns   []parquetColumn `json:"columns"`
+}
+
+// parquetColumn is a leaf column in a parquet file's schema.
+type parquetColumn struct {
+	// Name is the column's path in the schema, with its parts joined by '.'
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	Repetition    string `json:"repetition"`
+	ConvertedType string `json:"converted_type,omitempty"`
+}
+
+// enumName returns the name of 'value' in 'names'.
+func enumName(names []string, value int64) string {
+	if value >= 0 && value < int64(len(names)) {
+		return names[value]
+	}
+	return "UNKNOWN"
+}
+
+// previewParquet reads the schema and row count of the parquet file in 'r',
+// which is 'size' bytes, from its footer. Only the footer is read.
+func previewParquet(r io.ReaderAt, size int64) (*parquetPreview, error) {
+	notParquet := newPreviewError(http.StatusUnprocessableEntity, "file is not a parquet file")
+	// The footer's size and the trailing magic
+	tail := make([]byte, 8)
+	if size < int64(len(parquetMagic)+len(tail)) {
+		return nil, notParquet
+	}
+	if _, err := r.ReadAt(tail, size-int64(len(tail))); err != nil {
+		return nil, err
+	}
+	if string(tail[4:]) != parquetMagic {
+		return nil, notParquet
+	}
+	footerSize := int64(binary.LittleEndian.Uint32(tail[:4]))
+	if footerSize > maxParquetFooterBytes {
+		return nil, newPreviewError(http.StatusRequestEntityTooLarge, "parquet footer is %d bytes, which is more than the %d bytes that can be previewed",
+			footerSize, maxParquetFooterBytes)
+	}
+	if footerSize > size-int64(len(tail)+len(parquetMagic)) {
+		return nil, newPreviewError(http.StatusUnprocessableEntity, "invalid parquet footer size %d", footerSize)
+	}
+	footer := make([]byte, footerSize)
+	if _, err := r.ReadAt(footer, size-int64(len(tail))-footerSize); err != nil {
+		return nil, err
+	}
+	preview, err := parseParquetFooter(footer)
+	if err != nil {
+		return nil, newPreviewError(http.StatusUnprocessableEntity, "could not parse parquet footer: %v", err)
+	}
+	return preview, nil
+}
+
+// parquetSchemaElement is the part of an element of a parquet file's
+// flattened schema that's previewed.
+type parquetSchemaElement struct {
+	name          string
+	typ           int64
+	repetition    int64
+	convertedType int64
+	numChildren   int64
+}
+
+// parseParquetFooter parses the FileMetaData struct in a parquet file's
+// footer.
+func parseParquetFooter(footer []byte) (*parquetPreview, error) {
+	r := &compactReader{buf: footer}
+	preview := &parquetPreview{}
+	var schema []parquetSchemaElement
+	if err := r.readStruct(0, func(id int16, typ byte) error {
+		switch {
+		case id == 2 && typ == compactList:
+			elemType, n, err := r.readListHeader()
+			if err != nil {
+				return err
+			}
+			if elemType != compactStruct {
+				return errors.Errorf("unexpected schema element type %d", elemType)
+			}
+			for i := 0; i < n; i++ {
+				element, err := r.readSchemaElement()
+				if err != nil {
+					return err
+				}
+				schema = append(schema, element)
+			}
+			return nil
+		case id == 3 && typ == compactI64:
+			var err error
+			preview.NumRows, err = r.readZigzag()
+			return err
+		case id == 4 && typ == compactList:
+			elemType, n, err := r.readListHeader()
+			if err != nil {
+				return err
+			}
+			preview.RowGroups = n
+			for i := 0; i < n; i++ {
+				if err := r.skip(elemType, true, 1); err != nil {
+					return err
+				}
+			}
+			return nil
+		case id == 6 && typ == compactBinary:
+			createdBy, err := r.readBinary()
+			preview.CreatedBy = string(createdBy)
+			return err
+		default:
+			return r.skip(typ, false, 1)
+		}
+	}); err != nil {
+		return nil, err
+	}
+	columns, err := parquetColumns(schema)
+	if err != nil {
+		return nil, err
+	}
+	preview.Columns = columns
+	return preview, nil
+}
+
+func (r *compactReader) readSchemaElement() (parquetSchemaElement, error) {
+	element := parquetSchemaElement{typ: -1, repetition: -1, convertedType: -1}
+	err := r.readStruct(1, func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == compactI32:
+			element.typ, err = r.readZigzag()
+		case id == 3 && typ == compactI32:
+			element.repetition, err = r.readZigzag()
+		case id == 4 && typ == compactBinary:
+			var name []byte
+			name, err = r.readBinary()
+			element.name = string(name)
+		case id == 5 && typ == compactI32:
+			element.numChildren, err = r.readZigzag()
+		case id == 6 && typ == compactI32:
+			element.convertedType, err = r.readZigzag()
+		default:
+			err = r.skip(typ, false, 2)
+		}
+		return err
+	})
+	return element, err
+}
+
+// parquetColumns returns the leaf columns of the flattened, depth-first
+// 'schema', whose first element is its root.
+func parquetColumns(schema []parquetSchemaElement) ([]parquetColumn, error) {
+	if len(schema) == 0 {
+		return nil, errors.New("empty schema")
+	}
+	columns := []parquetColumn{}
+	next := 1
+	var walk func(path []string, numChildren int64) error
+	walk = func(path []string, numChildren int64) error {
+		for i := int64(0); i < numChildren; i++ {
+			if next >= len(schema) {
+				return errors.New("schema has fewer elements than its groups have children")
+			}
+			element := schema[next]
+			next++
+			elementPath := append(path[:len(path):len(path)], element.name)
+			if element.numChildren > 0 {
+				if len(elementPath) > maxCompactDepth {
+					return errors.New("schema is nested too deeply")
+				}
+				if err := walk(elementPath, element.numChildren); err != nil {
+					return err
+				}
+				continue
+			}
+			column := parquetColumn{
+				Name:       strings.Join(elementPath, "."),
+				Type:       enumName(parquetTypes, element.typ),
+				Repetition: enumName(parquetRepetitions, element.repetition),
+			}
+			if element.convertedType >= 0 {
+				column.ConvertedType = enumName(parquetConvertedTypes, element.convertedType)
+			}
+			columns = append(columns, column)
+		}
+		return nil
+	}
+	if err := walk(nil, schema[0].numChildren); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// The types of values in Thrift's compact protocol, which parquet's footer is
+// encoded with.
+const (
+	compactStop   = 0
+	compactTrue   = 1
+	compactFalse  = 2
+	compactByte   = 3
+	compactI16    = 4
+	compactI32    = 5
+	compactI64    = 6
+	compactDouble = 7
+	compactBinary = 8
+	compactList   = 9
+	compactSet    = 10
+	compactMap    = 11
+	compactStruct = 12
+	// compactShortSize in a list header means that the list's size follows
+	compactShortSize = 15
+)
+
+// maxCompactDepth is how deeply values can be nested.
+const maxCompactDepth = 64
+
+// compactReader decodes values encoded with Thrift's compact protocol. Only
+// what's needed to read parquet footers is supported.
+type compactReader struct {
+	buf []byte
+	pos int
+}
+
+var errCompactShort = errors.New("unexpected end of data")
+
+func (r *compactReader) readByte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, errCompactShort
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *compactReader) readVarint() (uint64, error) {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		return 0, errors.New("invalid varint")
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *compactReader) readZigzag() (int64, error) {
+	v, err := r.readVarint()
+	return int64(v>>1) ^ -int64(v&1), err
+}
+
+func (r *compactReader) readBinary() ([]byte, error) {
+	n, err := r.readVarint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.buf)-r.pos) {
+		return nil, errCompactShort
+	}
+	b := r.buf[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b, nil
+}
+
+// readListHeader reads the header of a list or set, and returns the type and
+// number of its elements.
+func (r *compactReader) readListHeader() (byte, int, error) {
+	header, err := r.readByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	n := uint64(header >> 4)
+	if n == compactShortSize {
+		if n, err = r.readVarint(); err != nil {
+			return 0, 0, err
+		}
+	}
+	// Every element is at least a byte
+	if n > uint64(len(r.buf)-r.pos) {
+		return 0, 0, errCompactShort
+	}
+	return header & 0x0f, int(n), nil
+}
+
+// readStruct reads the fields of a struct, calling 'f' with the ID and type of
+// each one, which must read or skip the field's value.
+func (r *compactReader) readStruct(depth int, f func(id int16, typ byte) error) error {
+	if depth > maxCompactDepth {
+		return errors.New("structs are nested too deeply")
+	}
+	var id int16
+	for {
+		header, err := r.readByte()
+		if err != nil {
+			return err
+		}
+		typ := header & 0x0f
+		if typ == compactStop {
+			return nil
+		}
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			v, err := r.readZigzag()
+			if err != nil {
+				return err
+			}
+			id = int16(v)
+		}
+		if err := f(id, typ); err != nil {
+			return err
+		}
+	}
+}
+
+// skip skips a value of type 'typ'. Booleans are encoded in a struct field's
+// type, but as a byte in a list, set or map.
+func (r *compactReader) skip(typ byte, inCollection bool, depth int) error {
+	if depth > maxCompactDepth {
+		return errors.New("values are nested too deeply")
+	}
+	var err error
+	switch typ {
+	case compactTrue, compactFalse:
+		if inCollection {
+			_, err = r.readByte()
+		}
+	case compactByte:
+		_, err = r.readByte()
+	case compactI16, compactI32, compactI64:
+		_, err = r.readVarint()
+	case compactDouble:
+		if len(r.buf)-r.pos < 8 {
+			return errCompactShort
+		}
+		r.pos += 8
+	case compactBinary:
+		_, err = r.readBinary()
+	case compactList, compactSet:
+		var elemType byte
+		var n int
+		if elemType, n, err = r.readListHeader(); err != nil {
+			return err
+		}
+		for i := 0; i < n && err == nil; i++ {
+			err = r.skip(elemType, true, depth+1)
+		}
+	case compactMap:
+		var n uint64
+		if n, err = r.readVarint(); err != nil || n == 0 {
+			return err
+		}
+		if n > uint64(len(r.buf)-r.pos) {
+			return errCompactShort
+		}
+		var types byte
+		if types, err = r.readByte(); err != nil {
+			return err
+		}
+		for i := uint64(0); i < n && err == nil; i++ {
+			if err = r.skip(types>>4, true, depth+1); err == nil {
+				err = r.skip(types&0x0f, true, depth+1)
+			}
+		}
+	case compactStruct:
+		err = r.readStruct(depth, func(_ int16, typ byte) error {
+			return r.skip(typ, false, depth+1)
+		})
+	default:
+		err = errors.Errorf("unknown type %d", typ)
+	}
+	return err
+}
@@ -0,0 +1,274 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"image"
+	// Register the formats that can be thumbnailed
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+)
+
+// Limits on file previews, which keep a preview of a large file from costing
+// as much as downloading it.
+const (
+	// defaultPreviewRows is how many rows of a CSV file are previewed if the
+	// request doesn't say.
+	defaultPreviewRows = 10
+	// maxPreviewRows is the most rows of a CSV file that can be previewed.
+	maxPreviewRows = 1000
+	// maxCSVPreviewBytes is how much of a CSV file is read to preview its
+	// rows. Rows past it aren't previewed.
+	maxCSVPreviewBytes = 1024 * 1024
+	// defaultThumbnailSize is the width and height that image thumbnails fit
+	// in if the request doesn't say.
+	defaultThumbnailSize = 256
+	// maxThumbnailSize is the largest width and height a thumbnail can have.
+	maxThumbnailSize = 1024
+	// maxImagePreviewBytes is the largest image that can be thumbnailed, as
+	// the whole image has to be read.
+	maxImagePreviewBytes = 32 * 1024 * 1024
+	// maxImagePreviewPixels is the most pixels an image that's thumbnailed can
+	// have, as a small, compressed image can still decode to a huge one.
+	maxImagePreviewPixels = 64 * 1024 * 1024
+	// maxParquetFooterBytes is the largest parquet footer (which holds the
+	// file's schema) that can be previewed.
+	maxParquetFooterBytes = 16 * 1024 * 1024
+)
+
+// previewError is an error with a file preview that's the client's fault,
+// and the HTTP status it's served with.
+type previewError struct {
+	code int
+	msg  string
+}
+
+func (e *previewError) Error() string {
+	return e.msg
+}
+
+func newPreviewError(code int, format string, args ...interface{}) error {
+	return &previewError{code: code, msg: fmt.Sprintf(format, args...)}
+}
+
+// previewFile serves a preview of 'f', in the mode given by the request's
+// "preview" parameter, instead of the file itself:
+// - "csv" serves the file's first rows (the "rows" parameter) as JSON
+// - "image" serves a PNG thumbnail that fits in the "size" parameter
+// - "parquet" serves the file's schema and row count as JSON
+func (s *server) previewFile(w http.ResponseWriter, r *http.Request, f *pfsFile) {
+	var err error
+	switch mode := r.URL.Query().Get("preview"); mode {
+	case "csv":
+		err = previewCSVFile(w, r, f)
+	case "image":
+		err = previewImageFile(w, r, f)
+	case "parquet":
+		err = previewParquetFile(w, f)
+	default:
+		err = newPreviewError(http.StatusBadRequest, "unknown preview mode %q, must be one of: csv, image, parquet", mode)
+	}
+	var pErr *previewError
+	if errors.As(err, &pErr) {
+		http.Error(w, pErr.msg, pErr.code)
+	} else if err != nil {
+		httpError(w, err)
+	}
+}
+
+// previewLimit returns the value of the request's 'param' parameter, or 'def'
+// if it isn't set. It must be between 1 and 'max'.
+func previewLimit(r *http.Request, param string, def, max int) (int, error) {
+	value := r.URL.Query().Get(param)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > max {
+		return 0, newPreviewError(http.StatusBadRequest, "invalid %s %q, must be between 1 and %d", param, value, max)
+	}
+	return n, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(v)
+}
+
+// csvPreview is the preview of a CSV file.
+type csvPreview struct {
+	// Columns is the file's first row, which names its columns.
+	Columns []string `json:"columns"`
+	// Rows are the rows after the first one.
+	Rows [][]string `json:"rows"`
+	// Truncated is true if the file may have more rows than were previewed.
+	Truncated bool `json:"truncated"`
+}
+
+// previewCSV reads the first 'rows' rows of CSV from 'r', after the header
+// row, reading at most 'limit' bytes. A row cut off by the limit isn't
+// previewed.
+func previewCSV(r io.Reader, limit int64, comma rune, rows int) (*csvPreview, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	preview := &csvPreview{Rows: [][]string{}}
+	if int64(len(data)) > limit {
+		data = data[:limit]
+		if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+			data = data[:i+1]
+		}
+		preview.Truncated = true
+	}
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	for len(preview.Rows) < rows {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return preview, nil
+		}
+		if err != nil {
+			return nil, newPreviewError(http.StatusUnprocessableEntity, "could not parse CSV: %v", err)
+		}
+		if preview.Columns == nil {
+			preview.Columns = record
+			continue
+		}
+		preview.Rows = append(preview.Rows, record)
+	}
+	if _, err := reader.Read(); !errors.Is(err, io.EOF) {
+		preview.Truncated = true
+	}
+	return preview, nil
+}
+
+func previewCSVFile(w http.ResponseWriter, r *http.Request, f *pfsFile) error {
+	rows, err := previewLimit(r, "rows", defaultPreviewRows, maxPreviewRows)
+	if err != nil {
+		return err
+	}
+	reader, err := f.reader(0, maxCSVPreviewBytes+1)
+	if err != nil {
+		return err
+	}
+	comma := ','
+	if strings.EqualFold(path.Ext(f.file.Path), ".tsv") {
+		comma = '\t'
+	}
+	preview, err := previewCSV(reader, maxCSVPreviewBytes, comma, rows)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, preview)
+}
+
+// thumbnail decodes the GIF, JPEG or PNG image in 'data' and scales it down
+// to fit in 'size' by 'size' pixels. Smaller images aren't scaled up.
+func thumbnail(data []byte, size int) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, newPreviewError(http.StatusUnprocessableEntity, "could not decode image: %v", err)
+	}
+	if int64(config.Width)*int64(config.Height) > maxImagePreviewPixels {
+		return nil, newPreviewError(http.StatusRequestEntityTooLarge, "image is %dx%d, which is more than the %d pixels that can be previewed",
+			config.Width, config.Height, maxImagePreviewPixels)
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, newPreviewError(http.StatusUnprocessableEntity, "could not decode image: %v", err)
+	}
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= size && height <= size {
+		return src, nil
+	}
+	if width >= height {
+		width, height = size, height*size/width
+	} else {
+		width, height = width*size/height, size
+	}
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+	// Nearest-neighbour scaling is rough, but it's enough for a preview
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		srcY := bounds.Min.Y + y*bounds.Dy()/height
+		for x := 0; x < width; x++ {
+			dst.Set(x, y, src.At(bounds.Min.X+x*bounds.Dx()/width, srcY))
+		}
+	}
+	return dst, nil
+}
+
+func previewImageFile(w http.ResponseWriter, r *http.Request, f *pfsFile) error {
+	size, err := previewLimit(r, "size", defaultThumbnailSize, maxThumbnailSize)
+	if err != nil {
+		return err
+	}
+	fileInfo, err := f.inspect()
+	if err != nil {
+		return err
+	}
+	if fileInfo.SizeBytes > maxImagePreviewBytes {
+		return newPreviewError(http.StatusRequestEntityTooLarge, "file is %d bytes, which is more than the %d bytes of image that can be previewed",
+			fileInfo.SizeBytes, maxImagePreviewBytes)
+	}
+	reader, err := f.reader(0, 0)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	img, err := thumbnail(data, size)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "image/png")
+	return png.Encode(w, img)
+}
+
+// fileReaderAt reads ranges of a file in PFS, so that e.g. a parquet file's
+// footer can be read without reading the rest of it.
+type fileReaderAt struct {
+	f *pfsFile
+}
+
+func (r fileReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	reader, err := r.f.reader(off, int64(len(p)))
+	if err != nil {
+		return 0, err
+	}
+	return io.ReadFull(reader, p)
+}
+
+func previewParquetFile(w http.ResponseWriter, f *pfsFile) error {
+	fileInfo, err := f.inspect()
+	if err != nil {
+		return err
+	}
+	preview, err := previewParquet(fileReaderAt{f: f}, int64(fileInfo.SizeBytes))
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, preview)
+}
@@ -0,0 +1,180 @@
+package http
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestPreviewCSV(t *testing.T) {
+	data := "name,age\nalice,30\nbob,25\n\"carol, jr\",7\n"
+	preview, err := previewCSV(strings.NewReader(data), maxCSVPreviewBytes, ',', 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"name", "age"}, preview.Columns)
+	require.Equal(t, [][]string{{"alice", "30"}, {"bob", "25"}}, preview.Rows)
+	require.True(t, preview.Truncated)
+
+	preview, err = previewCSV(strings.NewReader(data), maxCSVPreviewBytes, ',', 10)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"alice", "30"}, {"bob", "25"}, {"carol, jr", "7"}}, preview.Rows)
+	require.False(t, preview.Truncated)
+
+	// A row cut off by the byte limit isn't previewed
+	preview, err = previewCSV(strings.NewReader(data), int64(len("name,age\nalice,30\nbo")), ',', 10)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"alice", "30"}}, preview.Rows)
+	require.True(t, preview.Truncated)
+
+	preview, err = previewCSV(strings.NewReader("a\tb\n1\t2\n"), maxCSVPreviewBytes, '\t', 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, preview.Columns)
+	require.Equal(t, [][]string{{"1", "2"}}, preview.Rows)
+}
+
+func TestThumbnail(t *testing.T) {
+	encode := func(width, height int) []byte {
+		img := image.NewNRGBA(image.Rect(0, 0, width, height))
+		for x := 0; x < width; x++ {
+			img.Set(x, 0, color.NRGBA{R: 255, A: 255})
+		}
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, img))
+		return buf.Bytes()
+	}
+
+	thumb, err := thumbnail(encode(400, 100), 200)
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 200, 50), thumb.Bounds())
+	require.Equal(t, color.NRGBA{R: 255, A: 255}, thumb.At(0, 0))
+
+	thumb, err = thumbnail(encode(10, 1000), 100)
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 1, 100), thumb.Bounds())
+
+	// Smaller images aren't scaled up
+	thumb, err = thumbnail(encode(20, 10), 100)
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 20, 10), thumb.Bounds())
+
+	_, err = thumbnail([]byte("not an image"), 100)
+	require.YesError(t, err)
+}
+
+// compactWriter encodes values with Thrift's compact protocol, to build
+// parquet footers for tests.
+type compactWriter struct {
+	bytes.Buffer
+	lastIDs []int16
+}
+
+func (w *compactWriter) varint(v int64) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	w.Write(buf[:binary.PutUvarint(buf, uint64((v<<1)^(v>>63)))])
+}
+
+func (w *compactWriter) field(id int16, typ byte) {
+	last := w.lastIDs[len(w.lastIDs)-1]
+	w.WriteByte(byte(id-last)<<4 | typ)
+	w.lastIDs[len(w.lastIDs)-1] = id
+}
+
+func (w *compactWriter) beginStruct() { w.lastIDs = append(w.lastIDs, 0) }
+
+func (w *compactWriter) endStruct() {
+	w.WriteByte(compactStop)
+	w.lastIDs = w.lastIDs[:len(w.lastIDs)-1]
+}
+
+func (w *compactWriter) i32(id int16, v int64) {
+	w.field(id, compactI32)
+	w.varint(v)
+}
+
+func (w *compactWriter) str(id int16, s string) {
+	w.field(id, compactBinary)
+	buf := make([]byte, binary.MaxVarintLen64)
+	w.Write(buf[:binary.PutUvarint(buf, uint64(len(s)))])
+	w.WriteString(s)
+}
+
+func (w *compactWriter) list(id int16, elemType byte, n int) {
+	w.field(id, compactList)
+	w.WriteByte(byte(n)<<4 | elemType)
+}
+
+func TestPreviewParquet(t *testing.T) {
+	w := &compactWriter{}
+	w.beginStruct()
+	w.i32(1, 1)
+	w.list(2, compactStruct, 4)
+	w.beginStruct()
+	w.str(4, "schema")
+	w.i32(5, 2)
+	w.endStruct()
+	w.beginStruct()
+	w.i32(1, 2)
+	w.i32(3, 0)
+	w.str(4, "id")
+	w.endStruct()
+	w.beginStruct()
+	w.i32(3, 1)
+	w.str(4, "user")
+	w.i32(5, 1)
+	w.endStruct()
+	w.beginStruct()
+	w.i32(1, 6)
+	w.i32(3, 1)
+	w.str(4, "name")
+	w.i32(6, 0)
+	w.endStruct()
+	w.field(3, compactI64)
+	w.varint(42)
+	w.list(4, compactStruct, 2)
+	for i := 0; i < 2; i++ {
+		w.beginStruct()
+		w.list(1, compactI32, 2)
+		w.varint(1)
+		w.varint(2)
+		w.endStruct()
+	}
+	// Key-value metadata, which isn't previewed
+	w.list(5, compactStruct, 1)
+	w.beginStruct()
+	w.str(1, "key")
+	w.str(2, "value")
+	w.endStruct()
+	w.str(6, "parquet-test")
+	w.endStruct()
+
+	footer := w.Bytes()
+	file := append([]byte(parquetMagic+"column data"), footer...)
+	size := make([]byte, 4)
+	binary.LittleEndian.PutUint32(size, uint32(len(footer)))
+	file = append(append(file, size...), parquetMagic...)
+
+	preview, err := previewParquet(bytes.NewReader(file), int64(len(file)))
+	require.NoError(t, err)
+	require.Equal(t, &parquetPreview{
+		NumRows:   42,
+		RowGroups: 2,
+		CreatedBy: "parquet-test",
+		Columns: []parquetColumn{
+			{Name: "id", Type: "INT64", Repetition: "REQUIRED"},
+			{Name: "user.name", Type: "BYTE_ARRAY", Repetition: "OPTIONAL", ConvertedType: "UTF8"},
+		},
+	}, preview)
+
+	_, err = previewParquet(strings.NewReader("name,age\nalice,30\n"), 18)
+	require.YesError(t, err)
+	// A truncated footer
+	truncated := append(append([]byte(parquetMagic), footer[:len(footer)/2]...), file[len(file)-8:]...)
+	binary.LittleEndian.PutUint32(truncated[len(truncated)-8:], uint32(len(footer)/2))
+	_, err = previewParquet(bytes.NewReader(truncated), int64(len(truncated)))
+	require.YesError(t, err)
+}
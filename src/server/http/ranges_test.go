@@ -0,0 +1,81 @@
+package http_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	pachhttp "github.com/pachyderm/pachyderm/src/server/http"
+	"github.com/pachyderm/pachyderm/src/server/pkg/testpachd"
+)
+
+func TestGetFileRanges(t *testing.T) {
+	mock, err := testpachd.NewMockPachd(context.Background())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, mock.Close()) }()
+	content := "abcdefghijklmnopqrstuvwxyz"
+	mock.PFS.InspectFile.Use(func(_ context.Context, req *pfs.InspectFileRequest) (*pfs.FileInfo, error) {
+		return &pfs.FileInfo{
+			File:      req.File,
+			FileType:  pfs.FileType_FILE,
+			SizeBytes: uint64(len(content)),
+			Committed: types.TimestampNow(),
+		}, nil
+	})
+	mock.PFS.GetFile.Use(func(req *pfs.GetFileRequest, server pfs.API_GetFileServer) error {
+		data := content[req.OffsetBytes:]
+		if req.SizeBytes > 0 && req.SizeBytes < int64(len(data)) {
+			data = data[:req.SizeBytes]
+		}
+		return server.Send(&types.BytesValue{Value: []byte(data)})
+	})
+	handler, err := pachhttp.NewHTTPServer(mock.Addr.String())
+	require.NoError(t, err)
+	get := func(byteRange string) *http.Response {
+		req := httptest.NewRequest("GET", "/v1/pfs/repos/letters/commits/master/files/letters.txt", nil)
+		req.Header.Set("Range", byteRange)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Result()
+	}
+
+	resp := get("bytes=20-")
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "uvwxyz", string(body))
+	require.Equal(t, "bytes 20-25/26", resp.Header.Get("Content-Range"))
+
+	// Several ranges are returned as a multipart response
+	resp = get("bytes=0-2,10-12")
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/byteranges", mediaType)
+	parts := multipart.NewReader(resp.Body, params["boundary"])
+	for _, expected := range []struct{ contentRange, body string }{
+		{"bytes 0-2/26", "abc"},
+		{"bytes 10-12/26", "klm"},
+	} {
+		part, err := parts.NextPart()
+		require.NoError(t, err)
+		require.Equal(t, expected.contentRange, part.Header.Get("Content-Range"))
+		body, err := ioutil.ReadAll(part)
+		require.NoError(t, err)
+		require.Equal(t, expected.body, string(body))
+	}
+	_, err = parts.NextPart()
+	require.Equal(t, io.EOF, err)
+
+	resp = get("bytes=30-40")
+	require.Equal(t, http.StatusRequestedRangeNotSatisfiable, resp.StatusCode)
+}
@@ -2112,6 +2112,72 @@ func TestFlushCommitFailures(t *testing.T) {
 	}
 }
 
+func TestFlushCommitJobStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	c := tu.GetPachClient(t)
+	require.NoError(t, c.DeleteAll())
+	dataRepo := tu.UniqueString("TestFlushCommitJobStatus_data")
+	require.NoError(t, c.CreateRepo(dataRepo))
+	upstream := tu.UniqueString("TestFlushCommitJobStatus_upstream")
+	downstream := tu.UniqueString("TestFlushCommitJobStatus_downstream")
+	require.NoError(t, c.CreatePipeline(
+		upstream,
+		"",
+		[]string{"sh"},
+		[]string{fmt.Sprintf("cp /pfs/%s/* /pfs/out/", dataRepo)},
+		nil,
+		client.NewPFSInput(dataRepo, "/"),
+		"",
+		false,
+	))
+	require.NoError(t, c.CreatePipeline(
+		downstream,
+		"",
+		[]string{"sh"},
+		[]string{fmt.Sprintf("if [ -f /pfs/%s/bad ]; then exit 1; fi", upstream)},
+		nil,
+		client.NewPFSInput(upstream, "/"),
+		"",
+		false,
+	))
+
+	for _, file := range []string{"good", "bad"} {
+		commit, err := c.StartCommit(dataRepo, "master")
+		require.NoError(t, err)
+		_, err = c.PutFile(dataRepo, commit.ID, file, strings.NewReader("foo\n"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(dataRepo, commit.ID))
+
+		commitInfos, err := c.FlushCommitStatusAll([]*pfs.Commit{commit}, nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(commitInfos))
+		for _, ci := range commitInfos {
+			status := ci.FlushStatus
+			require.NotNil(t, status)
+			require.Equal(t, ci.Commit.Repo.Name, status.Pipeline)
+			jobInfo, err := c.InspectJobOutputCommit(ci.Commit.Repo.Name, ci.Commit.ID, false)
+			require.NoError(t, err)
+			require.Equal(t, jobInfo.Job.ID, status.JobID)
+			if file == "bad" && status.Pipeline == downstream {
+				require.Equal(t, pps.JobState_JOB_FAILURE.String(), status.State)
+				require.NotEqual(t, "", status.Reason)
+			} else {
+				require.Equal(t, pps.JobState_JOB_SUCCESS.String(), status.State)
+			}
+		}
+
+		// Without job_status, commits have no status
+		commitInfos, err = c.FlushCommitAll([]*pfs.Commit{commit}, nil)
+		require.NoError(t, err)
+		for _, ci := range commitInfos {
+			require.Nil(t, ci.FlushStatus)
+		}
+	}
+}
+
 func TestFlushCommitAfterCreatePipeline(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration tests in short mode")
@@ -12719,12 +12785,90 @@ func TestTrigger(t *testing.T) {
 	require.Equal(t, 1, len(cis))
 }
 
+func TestGatedPipeline(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	c := tu.GetPachClient(t)
+	require.NoError(t, c.DeleteAll())
+
+	dataRepo := tu.UniqueString("TestGatedPipeline_data")
+	require.NoError(t, c.CreateRepo(dataRepo))
+	_, err := c.PutFile(dataRepo, "master", "file", strings.NewReader("foo"))
+	require.NoError(t, err)
+	gated := tu.UniqueString("TestGatedPipeline_gated")
+	_, err = c.PpsAPIClient.CreatePipeline(context.Background(), &pps.CreatePipelineRequest{
+		Pipeline: client.NewPipeline(gated),
+		Transform: &pps.Transform{
+			Cmd: []string{"bash"},
+			Stdin: []string{
+				fmt.Sprintf("cp /pfs/%s/* /pfs/out/", dataRepo),
+			},
+		},
+		Input: client.NewPFSInput(dataRepo, "/*"),
+		Gated: true,
+	})
+	require.NoError(t, err)
+	downstream := tu.UniqueString("TestGatedPipeline_downstream")
+	require.NoError(t, c.CreatePipeline(
+		downstream,
+		"",
+		[]string{"bash"},
+		[]string{
+			fmt.Sprintf("cp /pfs/%s/* /pfs/out/", gated),
+		},
+		&pps.ParallelismSpec{
+			Constant: 1,
+		},
+		client.NewPFSInput(gated, "/*"),
+		"",
+		false,
+	))
+
+	// The gated pipeline's output lands on its pending branch, and its output
+	// branch doesn't move until the commit is approved
+	pending, err := c.BlockCommit(gated, "master-pending")
+	require.NoError(t, err)
+	branchInfo, err := c.InspectBranch(gated, "master")
+	require.NoError(t, err)
+	require.Nil(t, branchInfo.Head)
+
+	// Commits that aren't on the pending branch can't be approved
+	require.YesError(t, c.ApproveCommit(dataRepo, "master"))
+
+	require.NoError(t, c.ApproveCommit(gated, pending.Commit.ID))
+	branchInfo, err = c.InspectBranch(gated, "master")
+	require.NoError(t, err)
+	require.Equal(t, pending.Commit.ID, branchInfo.Head.ID)
+	cis, err := c.FlushCommitAll([]*pfs.Commit{pending.Commit}, []*pfs.Repo{client.NewRepo(downstream)})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(cis))
+	var buf bytes.Buffer
+	require.NoError(t, c.GetFile(downstream, "master", "file", 0, 0, &buf))
+	require.Equal(t, "foo", buf.String())
+
+	// Gating can't be turned off by updating the pipeline
+	_, err = c.PpsAPIClient.CreatePipeline(context.Background(), &pps.CreatePipelineRequest{
+		Pipeline: client.NewPipeline(gated),
+		Transform: &pps.Transform{
+			Cmd: []string{"bash"},
+			Stdin: []string{
+				fmt.Sprintf("cp /pfs/%s/* /pfs/out/", dataRepo),
+			},
+		},
+		Input:  client.NewPFSInput(dataRepo, "/*"),
+		Update: true,
+	})
+	require.YesError(t, err)
+}
+
 func getObjectCountForRepo(t testing.TB, c *client.APIClient, repo string) int {
 	pipelineInfos, err := c.ListPipeline()
 	require.NoError(t, err)
 	repoInfo, err := c.InspectRepo(repo)
 	require.NoError(t, err)
-	activeStat, err := pps_server.CollectActiveObjectsAndTags(context.Background(), c, []*pfs.RepoInfo{repoInfo}, pipelineInfos, 0, "")
+	activeStat, err := pps_server.CollectActiveObjectsAndTags(context.Background(), c, []*pfs.RepoInfo{repoInfo}, nil, pipelineInfos, 0, "")
 	require.NoError(t, err)
 	return activeStat.NObjects
 }
@@ -10,11 +10,14 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	gosync "sync"
+	"time"
 
 	prompt "github.com/c-bata/go-prompt"
 	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/types"
 	"github.com/pachyderm/pachyderm/src/client"
 	"github.com/pachyderm/pachyderm/src/client/limit"
 	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
@@ -69,6 +72,7 @@ or type (e.g. csv, binary, images, etc).`,
 	commands = append(commands, cmdutil.CreateDocsAlias(repoDocs, "repo", " repo$"))
 
 	var description string
+	var repoResidency string
 	createRepo := &cobra.Command{
 		Use:   "{{alias}} <repo>",
 		Short: "Create a new repo.",
@@ -86,6 +90,7 @@ or type (e.g. csv, binary, images, etc).`,
 					&pfsclient.CreateRepoRequest{
 						Repo:        client.NewRepo(args[0]),
 						Description: description,
+						Residency:   repoResidency,
 					},
 				)
 				return err
@@ -94,6 +99,7 @@ or type (e.g. csv, binary, images, etc).`,
 		}),
 	}
 	createRepo.Flags().StringVarP(&description, "description", "d", "", "A description of the repo.")
+	createRepo.Flags().StringVar(&repoResidency, "residency", "", "The data residency of the repo (e.g. 'eu'). The repo's data is stored in the residency's bucket, and can't be copied outside of the residency.")
 	commands = append(commands, cmdutil.CreateAlias(createRepo, "create repo"))
 
 	updateRepo := &cobra.Command{
@@ -125,6 +131,221 @@ or type (e.g. csv, binary, images, etc).`,
 	shell.RegisterCompletionFunc(updateRepo, shell.RepoCompletion)
 	commands = append(commands, cmdutil.CreateAlias(updateRepo, "update repo"))
 
+	var policyGlob string
+	var maskColumns []string
+	var maskPatterns []string
+	var mask string
+	updateReadPolicy := &cobra.Command{
+		Use:   "{{alias}} <repo>",
+		Short: "Set the read policy of a repo.",
+		Long: `Set the read policy of a repo, which redacts its files for users without full access to it.
+
+Users with WRITER access or above read the repo's files unchanged. Everyone
+else reads them with the values in the masked CSV columns, and the matches of
+the masked patterns, replaced with the mask. Only the repo's owners can set its
+read policy.`,
+		Example: `
+# mask the "ssn" and "email" columns of the CSV files in repo "patients"
+$ {{alias}} patients --column ssn --column email
+
+# mask phone numbers in the .txt files of repo "notes"
+$ {{alias}} notes --glob "/**.txt" --pattern '[0-9]{3}-[0-9]{3}-[0-9]{4}' --mask XXX`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			return c.SetReadPolicy(args[0], &pfsclient.ReadPolicy{
+				Glob:     policyGlob,
+				Columns:  maskColumns,
+				Patterns: maskPatterns,
+				Mask:     mask,
+			})
+		}),
+	}
+	updateReadPolicy.Flags().StringVar(&policyGlob, "glob", "", "Only redact the files that match this glob pattern.")
+	updateReadPolicy.Flags().StringSliceVar(&maskColumns, "column", nil, "A CSV column whose values are masked (may be repeated).")
+	updateReadPolicy.Flags().StringArrayVar(&maskPatterns, "pattern", nil, "A regular expression whose matches are masked (may be repeated).")
+	updateReadPolicy.Flags().StringVar(&mask, "mask", "", "The string that masked values are replaced with (defaults to 'REDACTED').")
+	shell.RegisterCompletionFunc(updateReadPolicy, shell.RepoCompletion)
+	commands = append(commands, cmdutil.CreateAlias(updateReadPolicy, "update read-policy"))
+
+	deleteReadPolicy := &cobra.Command{
+		Use:   "{{alias}} <repo>",
+		Short: "Remove the read policy of a repo.",
+		Long:  "Remove the read policy of a repo, so that all users with access to it read its files unchanged.",
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			return c.SetReadPolicy(args[0], nil)
+		}),
+	}
+	shell.RegisterCompletionFunc(deleteReadPolicy, shell.RepoCompletion)
+	commands = append(commands, cmdutil.CreateAlias(deleteReadPolicy, "delete read-policy"))
+
+	var maxOpen time.Duration
+	var abandonAction string
+	updateOpenCommitPolicy := &cobra.Command{
+		Use:   "{{alias}} <repo>",
+		Short: "Set the open commit policy of a repo.",
+		Long: `Set the open commit policy of a repo, which limits how long the repo's commits may stay open.
+
+Commits that are open for longer than --max-open are abandoned: they're either
+finished empty (--action finish, the default) or deleted (--action delete), and
+a commit_abandoned event is published. The output commits of pipelines are
+never abandoned. Only the repo's owners can set its open commit policy.`,
+		Example: `
+# finish the commits in repo "images" that are open for more than a day
+$ {{alias}} images --max-open 24h
+
+# delete the commits in repo "uploads" that are open for more than an hour
+$ {{alias}} uploads --max-open 1h --action delete`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			var action pfsclient.AbandonAction
+			switch abandonAction {
+			case "finish":
+				action = pfsclient.AbandonAction_ABANDON_FINISH
+			case "delete":
+				action = pfsclient.AbandonAction_ABANDON_DELETE
+			default:
+				return errors.Errorf("--action must be 'finish' or 'delete' (got %q)", abandonAction)
+			}
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			return c.SetOpenCommitPolicy(args[0], &pfsclient.OpenCommitPolicy{
+				MaxOpen: types.DurationProto(maxOpen),
+				Action:  action,
+			})
+		}),
+	}
+	updateOpenCommitPolicy.Flags().DurationVar(&maxOpen, "max-open", 0, "How long the repo's commits may stay open before they're abandoned.")
+	updateOpenCommitPolicy.Flags().StringVar(&abandonAction, "action", "finish", "What to do with abandoned commits: 'finish' them empty or 'delete' them.")
+	updateOpenCommitPolicy.MarkFlagRequired("max-open")
+	shell.RegisterCompletionFunc(updateOpenCommitPolicy, shell.RepoCompletion)
+	commands = append(commands, cmdutil.CreateAlias(updateOpenCommitPolicy, "update open-commit-policy"))
+
+	deleteOpenCommitPolicy := &cobra.Command{
+		Use:   "{{alias}} <repo>",
+		Short: "Remove the open commit policy of a repo.",
+		Long:  "Remove the open commit policy of a repo, so that its commits may stay open indefinitely.",
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			return c.SetOpenCommitPolicy(args[0], nil)
+		}),
+	}
+	shell.RegisterCompletionFunc(deleteOpenCommitPolicy, shell.RepoCompletion)
+	commands = append(commands, cmdutil.CreateAlias(deleteOpenCommitPolicy, "delete open-commit-policy"))
+
+	var metadataOwner string
+	var metadataTags []string
+	var metadataSchemaURL string
+	var metadataSLA string
+	var metadataDescription string
+	updateRepoMetadata := &cobra.Command{
+		Use:   "{{alias}} <repo>",
+		Short: "Set the catalog metadata of a repo.",
+		Long: `Set the catalog metadata of a repo, which describes the repo's dataset so that users can find it with 'pachctl search repo'.
+
+The metadata replaces the repo's existing metadata, so the flags that aren't
+given are cleared (except --description, which is kept unless it's given). Only
+the repo's owners can set its metadata.`,
+		Example: `
+# describe the dataset in repo "images"
+$ {{alias}} images --owner vision-team --tag imagery --tag raw --schema-url https://wiki.example.com/images --sla "updated hourly"
+
+# remove the catalog metadata of repo "images"
+$ {{alias}} images`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			var metadata *pfsclient.RepoMetadata
+			if metadataOwner != "" || len(metadataTags) > 0 || metadataSchemaURL != "" || metadataSLA != "" {
+				metadata = &pfsclient.RepoMetadata{
+					Owner:     metadataOwner,
+					Tags:      metadataTags,
+					SchemaUrl: metadataSchemaURL,
+					Sla:       metadataSLA,
+				}
+			}
+			return c.UpdateRepoMetadata(args[0], metadata, metadataDescription)
+		}),
+	}
+	updateRepoMetadata.Flags().StringVar(&metadataOwner, "owner", "", "The team or person responsible for the repo's dataset.")
+	updateRepoMetadata.Flags().StringSliceVar(&metadataTags, "tag", nil, "A tag of the repo's dataset (may be repeated).")
+	updateRepoMetadata.Flags().StringVar(&metadataSchemaURL, "schema-url", "", "A link to the schema of the repo's files.")
+	updateRepoMetadata.Flags().StringVar(&metadataSLA, "sla", "", "The service level of the repo's dataset, e.g. how often it's updated.")
+	updateRepoMetadata.Flags().StringVarP(&metadataDescription, "description", "d", "", "A new description of the repo.")
+	shell.RegisterCompletionFunc(updateRepoMetadata, shell.RepoCompletion)
+	commands = append(commands, cmdutil.CreateAlias(updateRepoMetadata, "update repo-metadata"))
+
+	var searchOwner string
+	var searchTags []string
+	searchRepo := &cobra.Command{
+		Use:   "{{alias}} [<query>]",
+		Short: "Find repos by their names, descriptions and catalog metadata.",
+		Long: `Find repos by their names, descriptions and catalog metadata (see 'pachctl update repo-metadata'), most relevant first.
+
+A repo matches the query if each of the query's words appears
+(case-insensitively) in its name, description, owner, tags, schema URL or SLA.
+The owners and tags of the matching repos are counted below them, which can be
+used to narrow the search with --owner and --tag.`,
+		Example: `
+# find the repos about images
+$ {{alias}} images
+
+# find the raw datasets owned by the vision team
+$ {{alias}} --owner vision-team --tag raw`,
+		Run: cmdutil.RunBoundedArgs(0, 1, func(args []string) error {
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			var query string
+			if len(args) > 0 {
+				query = args[0]
+			}
+			response, err := c.DiscoverRepos(query, searchOwner, searchTags)
+			if err != nil {
+				return err
+			}
+			if raw {
+				return marshaller.Marshal(os.Stdout, response)
+			}
+			writer := tabwriter.NewWriter(os.Stdout, pretty.CatalogRepoHeader)
+			for _, repoInfo := range response.RepoInfo {
+				pretty.PrintCatalogRepoInfo(writer, repoInfo)
+			}
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			if len(response.Owners) > 0 || len(response.Tags) > 0 {
+				fmt.Println()
+			}
+			pretty.PrintFacetCounts(os.Stdout, "Owners", response.Owners)
+			pretty.PrintFacetCounts(os.Stdout, "Tags", response.Tags)
+			return nil
+		}),
+	}
+	searchRepo.Flags().StringVar(&searchOwner, "owner", "", "Only return the repos with this owner.")
+	searchRepo.Flags().StringSliceVar(&searchTags, "tag", nil, "Only return the repos with this tag (may be repeated).")
+	searchRepo.Flags().AddFlagSet(rawFlags)
+	commands = append(commands, cmdutil.CreateAlias(searchRepo, "search repo"))
+
 	inspectRepo := &cobra.Command{
 		Use:   "{{alias}} <repo>",
 		Short: "Return info about a repo.",
@@ -196,10 +417,21 @@ or type (e.g. csv, binary, images, etc).`,
 
 	var force bool
 	var all bool
+	var repoPrefix string
 	deleteRepo := &cobra.Command{
 		Use:   "{{alias}} <repo>",
 		Short: "Delete a repo.",
-		Long:  "Delete a repo.",
+		Long: "Delete a repo.\n\n" +
+			"With --all, delete every repo, or with --all --prefix, every repo whose " +
+			"name starts with the prefix. Deleting every repo may need confirmation, " +
+			"or be disabled, in clusters with a DELETE_ALL_POLICY; use " +
+			"'pachctl delete all' in those clusters.",
+		Example: `
+# delete repo "foo"
+$ {{alias}} foo
+
+# delete every repo whose name starts with "scratch-"
+$ {{alias}} --all --prefix scratch-`,
 		Run: cmdutil.RunBoundedArgs(0, 1, func(args []string) error {
 			c, err := client.NewOnUserMachine("user")
 			if err != nil {
@@ -208,8 +440,12 @@ or type (e.g. csv, binary, images, etc).`,
 			defer c.Close()
 
 			request := &pfsclient.DeleteRepoRequest{
-				Force: force,
-				All:   all,
+				Force:  force,
+				All:    all,
+				Prefix: repoPrefix,
+			}
+			if repoPrefix != "" && !all {
+				return errors.Errorf("--prefix can only be used with --all")
 			}
 			if len(args) > 0 {
 				if all {
@@ -229,6 +465,7 @@ or type (e.g. csv, binary, images, etc).`,
 	}
 	deleteRepo.Flags().BoolVarP(&force, "force", "f", false, "remove the repo regardless of errors; use with care")
 	deleteRepo.Flags().BoolVar(&all, "all", false, "remove all repos")
+	deleteRepo.Flags().StringVar(&repoPrefix, "prefix", "", "with --all, only remove the repos whose names start with this prefix")
 	shell.RegisterCompletionFunc(deleteRepo, shell.RepoCompletion)
 	commands = append(commands, cmdutil.CreateAlias(deleteRepo, "delete repo"))
 
@@ -249,10 +486,13 @@ Commits can be created with another commit as a parent.`,
 	commands = append(commands, cmdutil.CreateDocsAlias(commitDocs, "commit", " commit$"))
 
 	var parent string
+	var startExpectedHead string
 	startCommit := &cobra.Command{
 		Use:   "{{alias}} <repo>@<branch-or-commit>",
 		Short: "Start a new commit.",
-		Long:  "Start a new commit with parent-commit as the parent, or start a commit on the given branch; if the branch does not exist, it will be created.",
+		Long: `Start a new commit with parent-commit as the parent, or start a commit on the given branch; if the branch does not exist, it will be created.
+
+If --expected-head is set, the commit is only started if the branch's head is currently that commit, so that concurrent writers to the branch don't start commits on each other's.`,
 		Example: `# Start a new commit in repo "test" that's not on any branch
 $ {{alias}} test
 
@@ -263,7 +503,10 @@ $ {{alias}} test@master
 $ {{alias}} test@patch -p master
 
 # Start a commit with XXX as the parent in repo "test", not on any branch
-$ {{alias}} test -p XXX`,
+$ {{alias}} test -p XXX
+
+# Start a commit in repo "test" on branch "master", only if master's head is XXX
+$ {{alias}} test@master --expected-head XXX`,
 		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
 			branch, err := cmdutil.ParseBranch(args[0])
 			if err != nil {
@@ -275,15 +518,20 @@ $ {{alias}} test -p XXX`,
 			}
 			defer c.Close()
 
+			var expectedHead *pfsclient.Commit
+			if startExpectedHead != "" {
+				expectedHead = client.NewCommit(branch.Repo.Name, startExpectedHead)
+			}
 			var commit *pfsclient.Commit
 			err = txncmds.WithActiveTransaction(c, func(c *client.APIClient) error {
 				var err error
 				commit, err = c.PfsAPIClient.StartCommit(
 					c.Ctx(),
 					&pfsclient.StartCommitRequest{
-						Branch:      branch.Name,
-						Parent:      client.NewCommit(branch.Repo.Name, parent),
-						Description: description,
+						Branch:       branch.Name,
+						Parent:       client.NewCommit(branch.Repo.Name, parent),
+						Description:  description,
+						ExpectedHead: expectedHead,
 					},
 				)
 				return err
@@ -298,13 +546,26 @@ $ {{alias}} test -p XXX`,
 	startCommit.MarkFlagCustom("parent", "__pachctl_get_commit $(__parse_repo ${nouns[0]})")
 	startCommit.Flags().StringVarP(&description, "message", "m", "", "A description of this commit's contents")
 	startCommit.Flags().StringVar(&description, "description", "", "A description of this commit's contents (synonym for --message)")
+	startCommit.Flags().StringVar(&startExpectedHead, "expected-head", "", "Only start the commit if the branch's head is currently this commit.")
 	shell.RegisterCompletionFunc(startCommit, shell.BranchCompletion)
 	commands = append(commands, cmdutil.CreateAlias(startCommit, "start commit"))
 
+	var status bool
+	var commitMetadata []string
+	var requireHead bool
 	finishCommit := &cobra.Command{
 		Use:   "{{alias}} <repo>@<branch-or-commit>",
 		Short: "Finish a started commit.",
-		Long:  "Finish a started commit. Commit-id must be a writeable commit.",
+		Long: `Finish a started commit. Commit-id must be a writeable commit.
+
+Finishing a commit with many files can take a while. If it's interrupted
+(e.g. because pachd restarted), finishing the commit again resumes from the
+last checkpoint. Use --status to see the progress of finishing a commit.
+
+If --require-head is set, the commit is only finished if it's still the head
+of its branch, i.e. if no other commit has been started on the branch since it
+was. Otherwise it's left open, so that it can be deleted and its writes
+reapplied on the branch's new head.`,
 		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
 			commit, err := cmdutil.ParseCommit(args[0])
 			if err != nil {
@@ -316,12 +577,32 @@ $ {{alias}} test -p XXX`,
 			}
 			defer c.Close()
 
+			if status {
+				progress, err := c.FinishCommitStatus(commit.Repo.Name, commit.ID)
+				if err != nil {
+					return err
+				}
+				return pretty.PrintDetailedFinishCommitProgress(progress)
+			}
+			var metadata map[string]string
+			for _, kv := range commitMetadata {
+				parts := strings.SplitN(kv, "=", 2)
+				if len(parts) != 2 {
+					return errors.Errorf("malformed metadata %q (expected <key>=<value>)", kv)
+				}
+				if metadata == nil {
+					metadata = make(map[string]string)
+				}
+				metadata[parts[0]] = parts[1]
+			}
 			err = txncmds.WithActiveTransaction(c, func(c *client.APIClient) error {
 				_, err = c.PfsAPIClient.FinishCommit(
 					c.Ctx(),
 					&pfsclient.FinishCommitRequest{
 						Commit:      commit,
 						Description: description,
+						Metadata:    metadata,
+						RequireHead: requireHead,
 					},
 				)
 				return err
@@ -331,6 +612,9 @@ $ {{alias}} test -p XXX`,
 	}
 	finishCommit.Flags().StringVarP(&description, "message", "m", "", "A description of this commit's contents (overwrites any existing commit description)")
 	finishCommit.Flags().StringVar(&description, "description", "", "A description of this commit's contents (synonym for --message)")
+	finishCommit.Flags().BoolVar(&status, "status", false, "Print the progress of finishing the commit instead of finishing it.")
+	finishCommit.Flags().StringSliceVar(&commitMetadata, "metadata", nil, "Metadata to store in the commit, as <key>=<value> (may be repeated).")
+	finishCommit.Flags().BoolVar(&requireHead, "require-head", false, "Only finish the commit if it's still the head of its branch.")
 	shell.RegisterCompletionFunc(finishCommit, shell.BranchCompletion)
 	commands = append(commands, cmdutil.CreateAlias(finishCommit, "finish commit"))
 
@@ -424,6 +708,50 @@ $ {{alias}} foo@master --from XXX`,
 	shell.RegisterCompletionFunc(listCommit, shell.RepoCompletion)
 	commands = append(commands, cmdutil.CreateAlias(listCommit, "list commit"))
 
+	listOpenCommit := &cobra.Command{
+		Use:   "{{alias}} [<repo>]",
+		Short: "Return the open commits of a repo, or of all repos.",
+		Long:  "Return the open commits of a repo, or of all repos if no repo is given, oldest first.",
+		Example: `
+# return the open commits of all repos
+$ {{alias}}
+
+# return the open commits of repo "foo"
+$ {{alias}} foo`,
+		Run: cmdutil.RunBoundedArgs(0, 1, func(args []string) error {
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			var repo string
+			if len(args) > 0 {
+				repo = args[0]
+			}
+			commitInfos, err := c.ListOpenCommits(repo)
+			if err != nil {
+				return err
+			}
+			if raw {
+				for _, ci := range commitInfos {
+					if err := marshaller.Marshal(os.Stdout, ci); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			writer := tabwriter.NewWriter(os.Stdout, pretty.OpenCommitHeader)
+			for _, ci := range commitInfos {
+				pretty.PrintOpenCommitInfo(writer, ci, fullTimestamps)
+			}
+			return writer.Flush()
+		}),
+	}
+	listOpenCommit.Flags().AddFlagSet(rawFlags)
+	listOpenCommit.Flags().AddFlagSet(fullTimestampsFlags)
+	shell.RegisterCompletionFunc(listOpenCommit, shell.RepoCompletion)
+	commands = append(commands, cmdutil.CreateAlias(listOpenCommit, "list open-commit"))
+
 	printCommitIter := func(commitIter client.CommitInfoIterator) error {
 		if raw {
 			for {
@@ -454,6 +782,7 @@ $ {{alias}} foo@master --from XXX`,
 	}
 
 	var repos cmdutil.RepeatedStringArg
+	var jobStatus bool
 	flushCommit := &cobra.Command{
 		Use:   "{{alias}} <repo>@<branch-or-commit> ...",
 		Short: "Wait for all commits caused by the specified commits to finish and return them.",
@@ -463,7 +792,11 @@ $ {{alias}} foo@master --from XXX`,
 $ {{alias}} foo@XXX bar@YYY
 
 # return commits caused by foo@XXX leading to repos bar and baz
-$ {{alias}} foo@XXX -r bar -r baz`,
+$ {{alias}} foo@XXX -r bar -r baz
+
+# return commits caused by foo@XXX, with the pipeline, ID and state of the
+# job that produced each one
+$ {{alias}} foo@XXX --job-status`,
 		Run: cmdutil.Run(func(args []string) error {
 			commits, err := cmdutil.ParseCommits(args)
 			if err != nil {
@@ -481,6 +814,22 @@ $ {{alias}} foo@XXX -r bar -r baz`,
 				toRepos = append(toRepos, client.NewRepo(repoName))
 			}
 
+			if jobStatus {
+				if raw {
+					return c.FlushCommitStatusF(commits, toRepos, func(commitInfo *pfsclient.CommitInfo) error {
+						return marshaller.Marshal(os.Stdout, commitInfo)
+					})
+				}
+				writer := tabwriter.NewWriter(os.Stdout, pretty.FlushStatusHeader)
+				if err := c.FlushCommitStatusF(commits, toRepos, func(commitInfo *pfsclient.CommitInfo) error {
+					pretty.PrintFlushStatus(writer, commitInfo, fullTimestamps)
+					return nil
+				}); err != nil {
+					return err
+				}
+				return writer.Flush()
+			}
+
 			commitIter, err := c.FlushCommit(commits, toRepos)
 			if err != nil {
 				return err
@@ -491,6 +840,7 @@ $ {{alias}} foo@XXX -r bar -r baz`,
 	}
 	flushCommit.Flags().VarP(&repos, "repos", "r", "Wait only for commits leading to a specific set of repos")
 	flushCommit.MarkFlagCustom("repos", "__pachctl_get_repo")
+	flushCommit.Flags().BoolVar(&jobStatus, "job-status", false, "Wait for the job that produced each commit to finish, and print its pipeline, ID and state.")
 	flushCommit.Flags().AddFlagSet(rawFlags)
 	flushCommit.Flags().AddFlagSet(fullTimestampsFlags)
 	shell.RegisterCompletionFunc(flushCommit, shell.BranchCompletion)
@@ -579,6 +929,28 @@ $ {{alias}} test@master --new`,
 	shell.RegisterCompletionFunc(deleteCommit, shell.BranchCompletion)
 	commands = append(commands, cmdutil.CreateAlias(deleteCommit, "delete commit"))
 
+	pruneCommit := &cobra.Command{
+		Use:   "{{alias}} <repo>@<commit>",
+		Short: "Drop the contents of a commit.",
+		Long: `Drop the contents of a finished commit, leaving an empty commit in its place, so that its data is removed by the next garbage collection.
+
+Unlike 'delete commit', the commit keeps its place in its branch and its provenance, so output commits (such as a pipeline's stats commits) can be pruned. The heads of branches and tagged commits can't be pruned.`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			commit, err := cmdutil.ParseCommit(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			return c.PruneCommit(commit.Repo.Name, commit.ID)
+		}),
+	}
+	shell.RegisterCompletionFunc(pruneCommit, shell.BranchCompletion)
+	commands = append(commands, cmdutil.CreateAlias(pruneCommit, "prune commit"))
+
 	branchDocs := &cobra.Command{
 		Short: "Docs for branches.",
 		Long: `A branch in Pachyderm is an alias for a Commit ID.
@@ -610,8 +982,8 @@ Any pachctl command that can take a Commit ID, can take a branch name instead.`,
 			if len(provenance) != 0 && trigger.Branch != "" {
 				return errors.Errorf("cannot use provenance and triggers on the same branch")
 			}
-			if (trigger.CronSpec != "" || trigger.Size_ != "" || trigger.Commits != 0) &&
-				trigger.Branch != "" {
+			if (trigger.CronSpec != "" || trigger.Size_ != "" || trigger.Commits != 0 || trigger.Validation != "") &&
+				trigger.Branch == "" {
 				return errors.Errorf("trigger condition specified without a branch to trigger on, specify a branch with --trigger")
 			}
 			c, err := client.NewOnUserMachine("user")
@@ -636,8 +1008,69 @@ Any pachctl command that can take a Commit ID, can take a branch name instead.`,
 	createBranch.Flags().StringVar(&trigger.CronSpec, "trigger-cron", "", "The cron spec to use in triggering.")
 	createBranch.Flags().StringVar(&trigger.Size_, "trigger-size", "", "The data size to use in triggering.")
 	createBranch.Flags().Int64Var(&trigger.Commits, "trigger-commits", 0, "The number of commits to use in triggering.")
+	createBranch.Flags().StringVar(&trigger.Validation, "trigger-validation", "", "The validation pipeline whose passing output commits trigger this branch.")
 	commands = append(commands, cmdutil.CreateAlias(createBranch, "create branch"))
 
+	var expectedHead string
+	var deferred bool
+	var updateBranch *cobra.Command // standalone declaration so Run() can refer
+	updateBranch = &cobra.Command{
+		Use:   "{{alias}} <repo>@<branch> [<branch-or-commit>]",
+		Short: "Move an existing branch to a new head.",
+		Long: `Move an existing branch to a new head, keeping its provenance and trigger. The new head may use ancestry syntax, e.g. master^3 or master.2.
+
+If --expected-head is set, the branch is only moved if its head is currently that commit, so that concurrent updates aren't overwritten.
+
+With --deferred, the branch's new commits stop triggering pipelines. Commits can be staged on a deferred branch, and then processed together by moving a branch that pipelines read to its head, or with --deferred=false, which processes the branch's head if it wasn't already. The new head may be omitted when --deferred is set.`,
+		Example: `
+# Reset master to its grandparent
+$ {{alias}} images@master master^2
+
+# Move staging to master's head, only if staging hasn't moved since it was read
+$ {{alias}} images@staging master --expected-head 7f8c0e26ca8e4ad6a1bdf4ad9ac3bfe1
+
+# Stage commits on images@staging, then process them by moving master to them
+$ {{alias}} images@staging --deferred
+$ pachctl put file images@staging:/scan-01.tiff -f scan-01.tiff
+$ pachctl put file images@staging:/scan-02.tiff -f scan-02.tiff
+$ {{alias}} images@master staging`,
+		Run: cmdutil.RunBoundedArgs(1, 2, func(args []string) error {
+			branch, err := cmdutil.ParseBranch(args[0])
+			if err != nil {
+				return err
+			}
+			setDeferred := updateBranch.Flags().Changed("deferred")
+			if len(args) < 2 && !setDeferred {
+				return errors.Errorf("a new head must be given, unless --deferred is set")
+			}
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			// A branch is deferred before it's moved, and undeferred after, so
+			// that its new head is only processed if it's not deferred
+			if setDeferred && deferred {
+				if err := c.SetBranchDeferred(branch.Repo.Name, branch.Name, true); err != nil {
+					return err
+				}
+			}
+			if len(args) == 2 {
+				if err := c.MoveBranch(branch.Repo.Name, branch.Name, args[1], expectedHead); err != nil {
+					return err
+				}
+			}
+			if setDeferred && !deferred {
+				return c.SetBranchDeferred(branch.Repo.Name, branch.Name, false)
+			}
+			return nil
+		}),
+	}
+	updateBranch.Flags().StringVar(&expectedHead, "expected-head", "", "Only move the branch if its head is currently this commit.")
+	updateBranch.Flags().BoolVar(&deferred, "deferred", false, "Stop (or, with --deferred=false, resume) the branch's new commits triggering pipelines.")
+	shell.RegisterCompletionFunc(updateBranch, shell.BranchCompletion)
+	commands = append(commands, cmdutil.CreateAlias(updateBranch, "update branch"))
+
 	inspectBranch := &cobra.Command{
 		Use:   "{{alias}}  <repo>@<branch>",
 		Short: "Return info about a branch.",
@@ -729,6 +1162,175 @@ Any pachctl command that can take a Commit ID, can take a branch name instead.`,
 	shell.RegisterCompletionFunc(deleteBranch, shell.BranchCompletion)
 	commands = append(commands, cmdutil.CreateAlias(deleteBranch, "delete branch"))
 
+	parseCommitTag := func(arg string) (*pfsclient.CommitTag, error) {
+		commit, err := cmdutil.ParseCommit(arg)
+		if err != nil {
+			return nil, err
+		}
+		if commit.ID == "" {
+			return nil, errors.Errorf("invalid format \"%s\": tag cannot be empty", arg)
+		}
+		return client.NewCommitTag(commit.Repo.Name, commit.ID), nil
+	}
+
+	createCommitTag := &cobra.Command{
+		Use:   "{{alias}} <repo>@<branch-or-commit> <tag>",
+		Short: "Create an immutable, named reference to a commit.",
+		Long: "Create an immutable, named reference to a commit. Unlike a branch, a " +
+			"commit tag always refers to the same commit, and its name can be used " +
+			"anywhere that a commit ID can, including in a pipeline's input. The " +
+			"commit must be finished. If it's given as a branch, the branch's " +
+			"current HEAD is tagged.",
+		Example: `
+# Tag the HEAD of the "master" branch of repo "data" as "v1.2-training-set":
+$ {{alias}} data@master v1.2-training-set
+
+# Read a file from the tagged commit:
+$ pachctl get file data@v1.2-training-set:/labels.csv`,
+		Run: cmdutil.RunFixedArgs(2, func(args []string) error {
+			commit, err := cmdutil.ParseCommit(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			return c.TagCommit(commit.Repo.Name, commit.ID, args[1], description)
+		}),
+	}
+	createCommitTag.Flags().StringVarP(&description, "description", "d", "", "A description of the tagged commit's contents.")
+	shell.RegisterCompletionFunc(createCommitTag, shell.BranchCompletion)
+	commands = append(commands, cmdutil.CreateAlias(createCommitTag, "create commit-tag"))
+
+	inspectCommitTag := &cobra.Command{
+		Use:   "{{alias}} <repo>@<tag>",
+		Short: "Return info about a commit tag.",
+		Long:  "Return info about a commit tag.",
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			tag, err := parseCommitTag(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			tagInfo, err := c.InspectCommitTag(tag.Repo.Name, tag.Name)
+			if err != nil {
+				return err
+			}
+			if raw {
+				return marshaller.Marshal(os.Stdout, tagInfo)
+			}
+			writer := tabwriter.NewWriter(os.Stdout, pretty.CommitTagHeader)
+			pretty.PrintCommitTag(writer, tagInfo, fullTimestamps)
+			return writer.Flush()
+		}),
+	}
+	inspectCommitTag.Flags().AddFlagSet(rawFlags)
+	inspectCommitTag.Flags().AddFlagSet(fullTimestampsFlags)
+	commands = append(commands, cmdutil.CreateAlias(inspectCommitTag, "inspect commit-tag"))
+
+	listCommitTag := &cobra.Command{
+		Use:   "{{alias}} <repo>",
+		Short: "Return all commit tags on a repo.",
+		Long:  "Return all commit tags on a repo.",
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			tagInfos, err := c.ListCommitTag(args[0])
+			if err != nil {
+				return err
+			}
+			if raw {
+				for _, tagInfo := range tagInfos {
+					if err := marshaller.Marshal(os.Stdout, tagInfo); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			writer := tabwriter.NewWriter(os.Stdout, pretty.CommitTagHeader)
+			for _, tagInfo := range tagInfos {
+				pretty.PrintCommitTag(writer, tagInfo, fullTimestamps)
+			}
+			return writer.Flush()
+		}),
+	}
+	listCommitTag.Flags().AddFlagSet(rawFlags)
+	listCommitTag.Flags().AddFlagSet(fullTimestampsFlags)
+	shell.RegisterCompletionFunc(listCommitTag, shell.RepoCompletion)
+	commands = append(commands, cmdutil.CreateAlias(listCommitTag, "list commit-tag"))
+
+	deleteCommitTag := &cobra.Command{
+		Use:   "{{alias}} <repo>@<tag>",
+		Short: "Delete a commit tag.",
+		Long:  "Delete a commit tag, while leaving the commit intact. Tags that are used by a pipeline's input can't be deleted.",
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			tag, err := parseCommitTag(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			return c.DeleteCommitTag(tag.Repo.Name, tag.Name)
+		}),
+	}
+	commands = append(commands, cmdutil.CreateAlias(deleteCommitTag, "delete commit-tag"))
+
+	var statement bool
+	inspectAttestation := &cobra.Command{
+		Use:   "{{alias}} <repo>@<commit>",
+		Short: "Return the signed attestation of an output commit.",
+		Long: `Return the signed attestation of an output commit of a pipeline that has 'attest' set.
+
+The attestation is printed as a DSSE envelope, whose payload is an in-toto
+statement with a SLSA provenance predicate, so it can be verified with standard
+supply-chain tools. The attestation's public key is included with --raw.`,
+		Example: `
+# save the attestation of the HEAD of the master branch of "model"
+$ {{alias}} model@master > model.intoto.json
+
+# print the provenance that the attestation signs
+$ {{alias}} model@master --statement`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			commit, err := cmdutil.ParseCommit(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			attestation, err := c.GetAttestation(commit.Repo.Name, commit.ID)
+			if err != nil {
+				return err
+			}
+			if raw {
+				return marshaller.Marshal(os.Stdout, attestation)
+			}
+			if statement {
+				_, err := os.Stdout.Write(append(attestation.Payload, '\n'))
+				return err
+			}
+			return pretty.PrintAttestationEnvelope(os.Stdout, attestation)
+		}),
+	}
+	inspectAttestation.Flags().AddFlagSet(rawFlags)
+	inspectAttestation.Flags().BoolVar(&statement, "statement", false, "Print the in-toto statement that the attestation signs, rather than the signed envelope.")
+	shell.RegisterCompletionFunc(inspectAttestation, shell.BranchCompletion)
+	commands = append(commands, cmdutil.CreateAlias(inspectAttestation, "inspect attestation"))
+
 	fileDocs := &cobra.Command{
 		Short: "Docs for files.",
 		Long: `Files are the lowest level data objects in Pachyderm.
@@ -749,6 +1351,7 @@ from commits with 'get file'.`,
 	var headerRecords uint
 	var putFileCommit bool
 	var overwrite bool
+	var replace bool
 	var compress bool
 	putFile := &cobra.Command{
 		Use:   "{{alias}} <repo>@<branch-or-commit>[:<path/to/file>]",
@@ -792,7 +1395,12 @@ $ {{alias}} repo@branch -i file
 # Put several files or URLs that are listed at URL.
 # NOTE this URL can reference local files, so it could cause you to put sensitive
 # files into your Pachyderm cluster.
-$ {{alias}} repo@branch -i http://host/path`,
+$ {{alias}} repo@branch -i http://host/path
+
+# Atomically replace the directory repo/branch/path with the contents of dir
+# (the branch's HEAD must be an open commit, and the old contents of
+# repo/branch/path are visible until the commit is finished):
+$ {{alias}} -r --replace repo@branch:/path -f dir`,
 		Run: cmdutil.RunFixedArgs(1, func(args []string) (retErr error) {
 			file, err := cmdutil.ParseFile(args[0])
 			if err != nil {
@@ -808,6 +1416,15 @@ $ {{alias}} repo@branch -i http://host/path`,
 			}
 			defer c.Close()
 
+			if replace {
+				if file.Path == "" {
+					return errors.Errorf("must specify the directory to replace")
+				}
+				if err := c.ReplaceDirectory(file.Commit.Repo.Name, file.Commit.ID, file.Path); err != nil {
+					return err
+				}
+			}
+
 			// load data into pachyderm
 			pfc, err := c.NewPutFileClient()
 			if err != nil {
@@ -905,6 +1522,7 @@ $ {{alias}} repo@branch -i http://host/path`,
 	putFile.Flags().UintVar(&headerRecords, "header-records", 0, "the number of records that will be converted to a PFS 'header', and prepended to future retrievals of any subset of data from PFS; needs to be used with --split=(json|line|csv)")
 	putFile.Flags().BoolVarP(&putFileCommit, "commit", "c", false, "DEPRECATED: Put file(s) in a new commit.")
 	putFile.Flags().BoolVarP(&overwrite, "overwrite", "o", false, "Overwrite the existing content of the file, either from previous commits or previous calls to 'put file' within this commit.")
+	putFile.Flags().BoolVar(&replace, "replace", false, "Replace the directory at the destination path with the files that are put, atomically when the (open) commit is finished.")
 	shell.RegisterCompletionFunc(putFile,
 		func(flag, text string, maxCompletions int64) ([]prompt.Suggest, shell.CacheFunc) {
 			if flag == "-f" || flag == "--file" || flag == "-i" || flag == "input-file" {
@@ -949,10 +1567,27 @@ $ {{alias}} repo@branch -i http://host/path`,
 	commands = append(commands, cmdutil.CreateAlias(copyFile, "copy file"))
 
 	var outputPath string
+	var getDelimiter string
+	var offsetRecords int64
+	var sizeRecords int64
+	var ancestryDepth int64
+	var byteRanges []string
 	getFile := &cobra.Command{
 		Use:   "{{alias}} <repo>@<branch-or-commit>:<path/in/pfs>",
 		Short: "Return the contents of a file.",
-		Long:  "Return the contents of a file.",
+		Long: "Return the contents of a file.\n\n" +
+			"With --delimiter, return a range of the file's records instead, " +
+			"selected by --offset-records and --size-records. If the file was " +
+			"created with 'put file --split', the records of all of its split " +
+			"files are read, and its header and footer (if any) are returned " +
+			"along with the records.\n\n" +
+			"With --ancestry-depth, a file that isn't in the commit is read from " +
+			"the nearest of the commit's ancestors (up to that many) that has it, " +
+			"which supports branches that only hold the files they override. " +
+			"The commit that was read is printed to stderr.\n\n" +
+			"With --range, return the bytes in each of the given ranges of the " +
+			"file, one after the other. Ranges are <first>-<last> (inclusive, as " +
+			"in HTTP range requests), and must be within the file.",
 		Example: `
 # get file "XXX" on branch "master" in repo "foo"
 $ {{alias}} foo@master:XXX
@@ -963,7 +1598,17 @@ $ {{alias}} foo@master^:XXX
 
 # get file "XXX" in the grandparent of the current head of branch "master"
 # in repo "foo"
-$ {{alias}} foo@master^2:XXX`,
+$ {{alias}} foo@master^2:XXX
+
+# get lines 1000-1099 of file "XXX", which was put with --split line
+$ {{alias}} foo@master:XXX --delimiter line --offset-records 1000 --size-records 100
+
+# get file "config.json" on branch "overlay" in repo "foo", or from the
+# nearest of its 10 most recent ancestors if "overlay" doesn't override it
+$ {{alias}} foo@overlay:config.json --ancestry-depth 10
+
+# get the first kilobyte of file "XXX", and the 100 bytes from byte 1000000
+$ {{alias}} foo@master:XXX --range 0-1023 --range 1000000-1000099`,
 		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
 			file, err := cmdutil.ParseFile(args[0])
 			if err != nil {
@@ -993,19 +1638,55 @@ $ {{alias}} foo@master^2:XXX`,
 				defer f.Close()
 				w = f
 			}
+			if getDelimiter != "" {
+				delimiter, err := parseDelimiter(getDelimiter)
+				if err != nil {
+					return err
+				}
+				return c.GetFileRecords(file.Commit.Repo.Name, file.Commit.ID, file.Path, delimiter, offsetRecords, sizeRecords, w)
+			}
+			if offsetRecords != 0 || sizeRecords != 0 {
+				return errors.Errorf("--offset-records and --size-records require --delimiter")
+			}
+			if len(byteRanges) > 0 {
+				var ranges []*pfsclient.ByteRange
+				for _, r := range byteRanges {
+					byteRange, err := parseByteRange(r)
+					if err != nil {
+						return err
+					}
+					ranges = append(ranges, byteRange)
+				}
+				return c.GetFileRanges(file.Commit.Repo.Name, file.Commit.ID, file.Path, ranges, w)
+			}
+			if ancestryDepth != 0 {
+				commit, err := c.GetFileAncestry(file.Commit.Repo.Name, file.Commit.ID, file.Path, ancestryDepth, w)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "read %s from commit %s\n", file.Path, commit.ID)
+				return nil
+			}
 			return c.GetFile(file.Commit.Repo.Name, file.Commit.ID, file.Path, 0, 0, w)
 		}),
 	}
 	getFile.Flags().BoolVarP(&recursive, "recursive", "r", false, "Recursively download a directory.")
 	getFile.Flags().StringVarP(&outputPath, "output", "o", "", "The path where data will be downloaded.")
 	getFile.Flags().IntVarP(&parallelism, "parallelism", "p", DefaultParallelism, "The maximum number of files that can be downloaded in parallel")
+	getFile.Flags().StringVar(&getDelimiter, "delimiter", "", "Return records delimited by 'line', 'json', 'sql' or 'csv' instead of raw bytes.")
+	getFile.Flags().Int64Var(&offsetRecords, "offset-records", 0, "The number of records to skip (requires --delimiter).")
+	getFile.Flags().Int64Var(&sizeRecords, "size-records", 0, "The maximum number of records to return, or 0 for all remaining records (requires --delimiter).")
+	getFile.Flags().Int64Var(&ancestryDepth, "ancestry-depth", 0, "If the file isn't in the commit, read it from the nearest of up to this many ancestors that has it.")
+	getFile.Flags().StringSliceVar(&byteRanges, "range", nil, "Return the bytes in this range of the file, as <first>-<last> (may be repeated).")
 	shell.RegisterCompletionFunc(getFile, shell.FileCompletion)
 	commands = append(commands, cmdutil.CreateAlias(getFile, "get file"))
 
 	inspectFile := &cobra.Command{
 		Use:   "{{alias}} <repo>@<branch-or-commit>:<path/in/pfs>",
 		Short: "Return info about a file.",
-		Long:  "Return info about a file.",
+		Long: "Return info about a file.\n\n" +
+			"With --ancestry-depth, a file that isn't in the commit is inspected in " +
+			"the nearest of the commit's ancestors (up to that many) that has it.",
 		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
 			file, err := cmdutil.ParseFile(args[0])
 			if err != nil {
@@ -1016,7 +1697,12 @@ $ {{alias}} foo@master^2:XXX`,
 				return err
 			}
 			defer c.Close()
-			fileInfo, err := c.InspectFile(file.Commit.Repo.Name, file.Commit.ID, file.Path)
+			var fileInfo *pfsclient.FileInfo
+			if ancestryDepth != 0 {
+				fileInfo, err = c.InspectFileAncestry(file.Commit.Repo.Name, file.Commit.ID, file.Path, ancestryDepth)
+			} else {
+				fileInfo, err = c.InspectFile(file.Commit.Repo.Name, file.Commit.ID, file.Path)
+			}
 			if err != nil {
 				return err
 			}
@@ -1030,9 +1716,52 @@ $ {{alias}} foo@master^2:XXX`,
 		}),
 	}
 	inspectFile.Flags().AddFlagSet(rawFlags)
+	inspectFile.Flags().Int64Var(&ancestryDepth, "ancestry-depth", 0, "If the file isn't in the commit, inspect it in the nearest of up to this many ancestors that has it.")
 	shell.RegisterCompletionFunc(inspectFile, shell.FileCompletion)
 	commands = append(commands, cmdutil.CreateAlias(inspectFile, "inspect file"))
 
+	var urlTTL time.Duration
+	var baseURL string
+	getFileURL := &cobra.Command{
+		Use:   "{{alias}} <repo>@<branch-or-commit>:<path/in/pfs>",
+		Short: "Return a URL that anyone can download a file from.",
+		Long: "Return a URL that anyone can download a file from, without credentials, until it expires.\n\n" +
+			"The URL is served by pachd's HTTP server, at --base-url, and is for the file in the " +
+			"commit that the branch points to now, which must be finished. Files in repos with a " +
+			"read policy can't be shared by URL.",
+		Example: `
+# return a URL that's valid for a day
+$ {{alias}} foo@master:/images/cat.png --ttl 24h
+
+# return a URL on pachd's external address
+$ {{alias}} foo@master:/images/cat.png --base-url https://pachyderm.example.com:30652`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			file, err := cmdutil.ParseFile(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			response, err := c.GetFileURL(file.Commit.Repo.Name, file.Commit.ID, file.Path, urlTTL)
+			if err != nil {
+				return err
+			}
+			if raw {
+				return marshaller.Marshal(os.Stdout, response)
+			}
+			fmt.Println(strings.TrimSuffix(baseURL, "/") + response.Path)
+			return nil
+		}),
+	}
+	getFileURL.Flags().AddFlagSet(rawFlags)
+	getFileURL.Flags().DurationVar(&urlTTL, "ttl", 0, "How long the URL is valid for (default 1h, at most 168h).")
+	getFileURL.Flags().StringVar(&baseURL, "base-url", "http://localhost:30652", "The address of pachd's HTTP server, which the URL is on.")
+	shell.RegisterCompletionFunc(getFileURL, shell.FileCompletion)
+	commands = append(commands, cmdutil.CreateAlias(getFileURL, "get file-url"))
+
 	var history string
 	listFile := &cobra.Command{
 		Use:   "{{alias}} <repo>@<branch-or-commit>[:<path/in/pfs>]",
@@ -1248,21 +1977,39 @@ $ {{alias}} foo@master:path1 bar@master:path2`,
 	commands = append(commands, cmdutil.CreateAlias(diffFile, "diff file"))
 
 	deleteFile := &cobra.Command{
-		Use:   "{{alias}} <repo>@<branch-or-commit>:<path/in/pfs>",
-		Short: "Delete a file.",
-		Long:  "Delete a file.",
-		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
-			file, err := cmdutil.ParseFile(args[0])
+		Use:   "{{alias}} <repo>@<branch-or-commit>:<path/in/pfs> [<repo>@<branch-or-commit>:<path/in/pfs>...]",
+		Short: "Delete one or more files.",
+		Long:  "Delete one or more files. Paths may be glob patterns. If several paths are given, they must be in the same commit, and are deleted atomically.",
+		Example: `
+# Delete a file:
+$ {{alias}} repo@branch:/path
+
+# Delete all of the .tmp files in a directory, and a log file, in one commit:
+$ {{alias}} repo@branch:/dir/*.tmp repo@branch:/log`,
+		Run: cmdutil.RunMinimumArgs(1, func(args []string) error {
+			files, err := cmdutil.ParseFiles(args)
 			if err != nil {
 				return err
 			}
+			commit := files[0].Commit
+			var paths []string
+			for _, file := range files {
+				if file.Commit.Repo.Name != commit.Repo.Name || file.Commit.ID != commit.ID {
+					return errors.Errorf("all files must be in the same commit, but %s@%s and %s@%s differ",
+						commit.Repo.Name, commit.ID, file.Commit.Repo.Name, file.Commit.ID)
+				}
+				paths = append(paths, file.Path)
+			}
 			c, err := client.NewOnUserMachine("user")
 			if err != nil {
 				return err
 			}
 			defer c.Close()
 
-			return c.DeleteFile(file.Commit.Repo.Name, file.Commit.ID, file.Path)
+			if len(paths) == 1 {
+				return c.DeleteFile(commit.Repo.Name, commit.ID, paths[0])
+			}
+			return c.DeleteFiles(commit.Repo.Name, commit.ID, paths...)
 		}),
 	}
 	shell.RegisterCompletionFunc(deleteFile, shell.FileCompletion)
@@ -1346,6 +2093,132 @@ Tags are a low-level resource and should not be accessed directly by most users.
 	fsck.Flags().BoolVarP(&fix, "fix", "f", false, "Attempt to fix as many issues as possible.")
 	commands = append(commands, cmdutil.CreateAlias(fsck, "fsck"))
 
+	var dryRun bool
+	archiveProvenance := &cobra.Command{
+		Short: "Archive commit provenance that refers to deleted commits.",
+		Long: `Archive commit provenance that refers to deleted commits.
+
+Deleting a pipeline (or force-deleting a repo) leaves the provenance of
+downstream commits pointing at commits that no longer exist, which 'fsck'
+reports as errors. This command removes those provenance entries from every
+finished commit, and records them in the commit's archived provenance (one
+tombstone per deleted branch, with the deleted commits' IDs) so that the
+commit's lineage can still be audited.
+
+Commits in the trash aren't archived, so that they can still be restored.
+Only admins may archive provenance.`,
+		Run: cmdutil.RunFixedArgs(0, func(args []string) error {
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			response, err := c.ArchiveProvenance(dryRun)
+			if err != nil {
+				return err
+			}
+			verb := "Archived"
+			if dryRun {
+				verb = "Would archive"
+			}
+			fmt.Printf("%s %d provenance edge(s) in %d commit(s).\n", verb, response.EdgesArchived, response.CommitsRewritten)
+			if len(response.ArchivedRepos) > 0 {
+				fmt.Printf("Deleted repos referenced: %s\n", strings.Join(response.ArchivedRepos, ", "))
+			}
+			return nil
+		}),
+	}
+	archiveProvenance.Flags().BoolVar(&dryRun, "dry-run", false, "Report what would be archived without changing any commits.")
+	commands = append(commands, cmdutil.CreateAlias(archiveProvenance, "archive provenance"))
+
+	trashDocs := &cobra.Command{
+		Short: "Docs for the trash.",
+		Long: `The trash holds deleted repos and input commits, if pachd is deployed with
+a trash retention (PFS_TRASH_RETENTION).
+
+A deleted repo or commit can be restored with 'undelete' until its retention
+expires, after which 'garbage-collect' permanently deletes it and its data.
+Only the user who deleted an item, or an admin, may restore it.`,
+	}
+	commands = append(commands, cmdutil.CreateDocsAlias(trashDocs, "trash", " trash$"))
+
+	listTrash := &cobra.Command{
+		Short: "Return the deleted repos and commits in the trash.",
+		Long:  "Return the deleted repos and commits in the trash.",
+		Run: cmdutil.RunFixedArgs(0, func(args []string) error {
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			deletedInfos, err := c.ListDeleted()
+			if err != nil {
+				return err
+			}
+			if raw {
+				for _, deletedInfo := range deletedInfos {
+					if err := marshaller.Marshal(os.Stdout, deletedInfo); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			writer := tabwriter.NewWriter(os.Stdout, pretty.DeletedHeader)
+			for _, deletedInfo := range deletedInfos {
+				pretty.PrintDeletedInfo(writer, deletedInfo, fullTimestamps)
+			}
+			return writer.Flush()
+		}),
+	}
+	listTrash.Flags().AddFlagSet(rawFlags)
+	listTrash.Flags().AddFlagSet(fullTimestampsFlags)
+	commands = append(commands, cmdutil.CreateAlias(listTrash, "list trash"))
+
+	undelete := &cobra.Command{
+		Use:   "{{alias}} <id>",
+		Short: "Restore a deleted repo or commit from the trash.",
+		Long: `Restore a deleted repo or commit from the trash, given the ID shown by 'list trash'.
+
+A restored repo has the branches and commits it had when it was deleted, but
+not its provenance on other repos. A restored commit is put back on the
+branches it was the head of, if they haven't moved since.`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			return c.RestoreDeleted(args[0])
+		}),
+	}
+	commands = append(commands, cmdutil.CreateAlias(undelete, "undelete"))
+
+	var expired bool
+	deleteTrash := &cobra.Command{
+		Use:   "{{alias}} <id>",
+		Short: "Permanently delete a repo or commit in the trash.",
+		Long:  "Permanently delete a repo or commit in the trash, so that it can no longer be restored.",
+		Run: cmdutil.RunBoundedArgs(0, 1, func(args []string) error {
+			if len(args) > 0 && expired {
+				return errors.Errorf("cannot use the --expired flag with an argument")
+			} else if len(args) == 0 && !expired {
+				return errors.Errorf("either an ID or the --expired flag needs to be provided")
+			}
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			var id string
+			if len(args) > 0 {
+				id = args[0]
+			}
+			return c.PurgeDeleted(id)
+		}),
+	}
+	deleteTrash.Flags().BoolVar(&expired, "expired", false, "delete all of the items whose retention has expired")
+	commands = append(commands, cmdutil.CreateAlias(deleteTrash, "delete trash"))
+
 	// Add the mount commands (which aren't available on Windows, so they're in
 	// their own file)
 	commands = append(commands, mountCmds()...)
@@ -1387,21 +2260,11 @@ func putFileHelper(c *client.APIClient, pfc client.PutFileClient,
 			return err
 		}
 
-		var delimiter pfsclient.Delimiter
-		switch split {
-		case "line":
-			delimiter = pfsclient.Delimiter_LINE
-		case "json":
-			delimiter = pfsclient.Delimiter_JSON
-		case "sql":
-			delimiter = pfsclient.Delimiter_SQL
-		case "csv":
-			delimiter = pfsclient.Delimiter_CSV
-		default:
-			return errors.Errorf("unrecognized delimiter '%s'; only accepts one of "+
-				"{json,line,sql,csv}", split)
+		delimiter, err := parseDelimiter(split)
+		if err != nil {
+			return err
 		}
-		_, err := pfc.PutFileSplit(repo, commit, path, delimiter, int64(targetFileDatums), int64(targetFileBytes), int64(headerRecords), overwrite, reader)
+		_, err = pfc.PutFileSplit(repo, commit, path, delimiter, int64(targetFileDatums), int64(targetFileBytes), int64(headerRecords), overwrite, reader)
 		return err
 	}
 
@@ -1460,6 +2323,45 @@ func putFileHelper(c *client.APIClient, pfc client.PutFileClient,
 	return putFile(f)
 }
 
+// parseDelimiter parses the value of 'put file --split' and
+// 'get file --delimiter'
+func parseDelimiter(delimiter string) (pfsclient.Delimiter, error) {
+	switch delimiter {
+	case "line":
+		return pfsclient.Delimiter_LINE, nil
+	case "json":
+		return pfsclient.Delimiter_JSON, nil
+	case "sql":
+		return pfsclient.Delimiter_SQL, nil
+	case "csv":
+		return pfsclient.Delimiter_CSV, nil
+	default:
+		return pfsclient.Delimiter_NONE, errors.Errorf("unrecognized delimiter '%s'; only accepts one of "+
+			"{json,line,sql,csv}", delimiter)
+	}
+}
+
+// parseByteRange parses a byte range of the form "<first>-<last>", where both
+// bounds are inclusive (as in HTTP range requests).
+func parseByteRange(byteRange string) (*pfsclient.ByteRange, error) {
+	parts := strings.SplitN(byteRange, "-", 2)
+	if len(parts) != 2 {
+		return nil, errors.Errorf("malformed range %q (expected <first>-<last>)", byteRange)
+	}
+	first, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("malformed range %q (expected <first>-<last>)", byteRange)
+	}
+	last, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("malformed range %q (expected <first>-<last>)", byteRange)
+	}
+	if last < first {
+		return nil, errors.Errorf("range %q ends before it starts", byteRange)
+	}
+	return &pfsclient.ByteRange{Lower: first, Upper: last + 1}, nil
+}
+
 func joinPaths(prefix, filePath string) string {
 	if url, err := url.Parse(filePath); err == nil && url.Scheme != "" {
 		if url.Scheme == "pfs" {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/require"
 	"github.com/pachyderm/pachyderm/src/server/pfs/fuse"
 	tu "github.com/pachyderm/pachyderm/src/server/pkg/testutil"
@@ -102,3 +103,17 @@ func TestMountParsing(t *testing.T) {
 		require.Equal(t, ro, opts[repo])
 	}
 }
+
+func TestParseByteRange(t *testing.T) {
+	byteRange, err := parseByteRange("0-99")
+	require.NoError(t, err)
+	require.Equal(t, &pfsclient.ByteRange{Lower: 0, Upper: 100}, byteRange)
+	byteRange, err = parseByteRange("5-5")
+	require.NoError(t, err)
+	require.Equal(t, &pfsclient.ByteRange{Lower: 5, Upper: 6}, byteRange)
+
+	for _, bad := range []string{"", "5", "5-", "-5", "a-b", "10-5", "1-2-3"} {
+		_, err := parseByteRange(bad)
+		require.YesError(t, err, bad)
+	}
+}
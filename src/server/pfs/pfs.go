@@ -67,6 +67,26 @@ type ErrCommitNotFinished struct {
 	Commit *pfs.Commit
 }
 
+// ErrBranchHeadMismatch represents an error where a branch isn't moved (or a
+// commit isn't started on it) because its head isn't the expected commit (see
+// MoveBranchRequest.ExpectedHead and StartCommitRequest.ExpectedHead)
+type ErrBranchHeadMismatch struct {
+	Branch *pfs.Branch
+	// Expected is nil if the branch was expected to have no head
+	Expected *pfs.Commit
+	// Actual is nil if the branch has no head
+	Actual *pfs.Commit
+}
+
+// ErrCommitConflict represents an error where a commit isn't finished because
+// it's no longer the head of its branch (see FinishCommitRequest.RequireHead)
+type ErrCommitConflict struct {
+	Commit *pfs.Commit
+	Branch *pfs.Branch
+	// Head is nil if the branch has no head
+	Head *pfs.Commit
+}
+
 func (e ErrFileNotFound) Error() string {
 	return fmt.Sprintf("file %v not found in repo %v at commit %v", e.File.Path, e.File.Commit.Repo.Name, e.File.Commit.ID)
 }
@@ -112,6 +132,25 @@ func (e ErrCommitNotFinished) Error() string {
 	return fmt.Sprintf("commit %v not finished", e.Commit.ID)
 }
 
+func (e ErrBranchHeadMismatch) Error() string {
+	actual, expected := "no head", "no head"
+	if e.Actual != nil {
+		actual = "head " + e.Actual.ID
+	}
+	if e.Expected != nil {
+		expected = "head " + e.Expected.ID
+	}
+	return fmt.Sprintf("branch %v@%v has %v, not the expected %v", e.Branch.Repo.Name, e.Branch.Name, actual, expected)
+}
+
+func (e ErrCommitConflict) Error() string {
+	head := "no head"
+	if e.Head != nil {
+		head = "head " + e.Head.ID
+	}
+	return fmt.Sprintf("commit %v in repo %v conflicts with branch %v, which has moved to %v", e.Commit.ID, e.Commit.Repo.Name, e.Branch.Name, head)
+}
+
 // ByteRangeSize returns byteRange.Upper - byteRange.Lower.
 func ByteRangeSize(byteRange *pfs.ByteRange) uint64 {
 	return byteRange.Upper - byteRange.Lower
@@ -128,6 +167,8 @@ var (
 	hasNoHeadRe               = regexp.MustCompile(`the branch .+ has no head \(create one with 'start commit'\)`)
 	outputCommitNotFinishedRe = regexp.MustCompile("output commit .+ not finished")
 	commitNotFinishedRe       = regexp.MustCompile("commit .+ not finished")
+	branchHeadMismatchRe      = regexp.MustCompile("branch [^ ]+ has .+, not the expected (head [^ ]+|no head)")
+	commitConflictRe          = regexp.MustCompile("commit [^ ]+ in repo [^ ]+ conflicts with branch [^ ]+, which has moved")
 )
 
 // IsCommitNotFoundErr returns true if 'err' has an error message that matches
@@ -219,3 +260,21 @@ func IsCommitNotFinishedErr(err error) bool {
 	}
 	return commitNotFinishedRe.MatchString(err.Error())
 }
+
+// IsBranchHeadMismatchErr returns true if 'err' is due to a branch not being
+// moved because its head isn't the expected commit
+func IsBranchHeadMismatchErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	return branchHeadMismatchRe.MatchString(grpcutil.ScrubGRPC(err).Error())
+}
+
+// IsCommitConflictErr returns true if 'err' is due to a commit not being
+// finished because it's no longer the head of its branch
+func IsCommitConflictErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	return commitConflictRe.MatchString(grpcutil.ScrubGRPC(err).Error())
+}
@@ -21,3 +21,18 @@ func TestErrorMatching(t *testing.T) {
 	require.False(t, IsCommitFinishedErr(ErrCommitDeleted{c}))
 	require.True(t, IsCommitFinishedErr(ErrCommitFinished{c}))
 }
+
+func TestBranchErrorMatching(t *testing.T) {
+	b := client.NewBranch("foo", "master")
+	c := client.NewCommit("foo", "bar")
+	require.True(t, IsBranchHeadMismatchErr(ErrBranchHeadMismatch{Branch: b, Expected: c, Actual: c}))
+	require.True(t, IsBranchHeadMismatchErr(ErrBranchHeadMismatch{Branch: b, Expected: c}))
+	require.True(t, IsBranchHeadMismatchErr(ErrBranchHeadMismatch{Branch: b, Actual: c}))
+	require.False(t, IsBranchHeadMismatchErr(ErrCommitConflict{Commit: c, Branch: b}))
+
+	require.True(t, IsCommitConflictErr(ErrCommitConflict{Commit: c, Branch: b}))
+	require.True(t, IsCommitConflictErr(ErrCommitConflict{Commit: c, Branch: b, Head: c}))
+	require.False(t, IsCommitConflictErr(ErrCommitFinished{c}))
+	require.False(t, IsCommitFinishedErr(ErrCommitConflict{Commit: c, Branch: b}))
+	require.False(t, IsCommitNotFoundErr(ErrCommitConflict{Commit: c, Branch: b}))
+}
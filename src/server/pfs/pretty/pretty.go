@@ -1,14 +1,17 @@
 package pretty
 
 import (
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	units "github.com/docker/go-units"
 	"github.com/fatih/color"
+	"github.com/gogo/protobuf/types"
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/server/pkg/pretty"
 )
@@ -18,8 +21,12 @@ const (
 	RepoHeader = "NAME\tCREATED\tSIZE (MASTER)\tDESCRIPTION\t\n"
 	// RepoAuthHeader is the header for repos with auth information attached.
 	RepoAuthHeader = "NAME\tCREATED\tSIZE (MASTER)\tACCESS LEVEL\t\n"
+	// CatalogRepoHeader is the header for repos found by DiscoverRepos.
+	CatalogRepoHeader = "NAME\tOWNER\tTAGS\tSIZE (MASTER)\tDESCRIPTION\t\n"
 	// CommitHeader is the header for commits.
 	CommitHeader = "REPO\tBRANCH\tCOMMIT\tFINISHED\tSIZE\tPROGRESS\tDESCRIPTION\n"
+	// OpenCommitHeader is the header for open commits.
+	OpenCommitHeader = "REPO\tBRANCH\tCOMMIT\tSTARTED\tDESCRIPTION\t\n"
 	// BranchHeader is the header for branches.
 	BranchHeader = "BRANCH\tHEAD\tTRIGGER\t\n"
 	// FileHeader is the header for files.
@@ -28,6 +35,13 @@ const (
 	FileHeaderWithCommit = "COMMIT\tNAME\tTYPE\tCOMMITTED\tSIZE\t\n"
 	// DiffFileHeader is the header for files produced by diff file.
 	DiffFileHeader = "OP\t" + FileHeader
+	// DeletedHeader is the header for deleted repos and commits.
+	DeletedHeader = "ID\tDELETED ITEM\tDELETED\tEXPIRES\tDELETED BY\t\n"
+	// FlushStatusHeader is the header for commits returned by flush commit
+	// with --job-status.
+	FlushStatusHeader = "REPO\tCOMMIT\tPIPELINE\tJOB\tSTATE\tFINISHED\tREASON\t\n"
+	// CommitTagHeader is the header for commit tags.
+	CommitTagHeader = "TAG\tCOMMIT\tCREATED\tDESCRIPTION\t\n"
 )
 
 // PrintRepoInfo pretty-prints repo info.
@@ -46,6 +60,29 @@ func PrintRepoInfo(w io.Writer, repoInfo *pfs.RepoInfo, fullTimestamps bool) {
 	fmt.Fprintln(w)
 }
 
+// PrintCatalogRepoInfo pretty-prints the catalog metadata of a repo.
+func PrintCatalogRepoInfo(w io.Writer, repoInfo *pfs.RepoInfo) {
+	fmt.Fprintf(w, "%s\t", repoInfo.Repo.Name)
+	fmt.Fprintf(w, "%s\t", repoInfo.Metadata.GetOwner())
+	fmt.Fprintf(w, "%s\t", strings.Join(repoInfo.Metadata.GetTags(), ","))
+	fmt.Fprintf(w, "%s\t", units.BytesSize(float64(repoInfo.SizeBytes)))
+	fmt.Fprintf(w, "%s\t", repoInfo.Description)
+	fmt.Fprintln(w)
+}
+
+// PrintFacetCounts pretty-prints the counts of a facet returned by
+// DiscoverRepos, e.g. "Owners: data-eng (3), ml (1)".
+func PrintFacetCounts(w io.Writer, name string, counts []*pfs.FacetCount) {
+	if len(counts) == 0 {
+		return
+	}
+	var parts []string
+	for _, c := range counts {
+		parts = append(parts, fmt.Sprintf("%s (%d)", c.Value, c.Count))
+	}
+	fmt.Fprintf(w, "%s: %s\n", name, strings.Join(parts, ", "))
+}
+
 // PrintableRepoInfo is a wrapper around RepoInfo containing any formatting options
 // used within the template to conditionally print information.
 type PrintableRepoInfo struct {
@@ -64,7 +101,15 @@ func NewPrintableRepoInfo(ri *pfs.RepoInfo) *PrintableRepoInfo {
 func PrintDetailedRepoInfo(repoInfo *PrintableRepoInfo) error {
 	template, err := template.New("RepoInfo").Funcs(funcMap).Parse(
 		`Name: {{.Repo.Name}}{{if .Description}}
-Description: {{.Description}}{{end}}{{if .FullTimestamps}}
+Description: {{.Description}}{{end}}{{if .Metadata}}{{if .Metadata.Owner}}
+Owner: {{.Metadata.Owner}}{{end}}{{if .Metadata.Tags}}
+Tags: {{join .Metadata.Tags ", "}}{{end}}{{if .Metadata.SchemaUrl}}
+Schema: {{.Metadata.SchemaUrl}}{{end}}{{if .Metadata.Sla}}
+SLA: {{.Metadata.Sla}}{{end}}{{end}}{{if .Tenant}}
+Tenant: {{.Tenant}}{{end}}{{if .Residency}}
+Residency: {{.Residency}}{{end}}{{if .ReadPolicy}}
+Read policy: {{printReadPolicy .ReadPolicy}}{{end}}{{if .OpenCommitPolicy}}
+Open commit policy: {{printOpenCommitPolicy .OpenCommitPolicy}}{{end}}{{if .FullTimestamps}}
 Created: {{.Created}}{{else}}
 Created: {{prettyAgo .Created}}{{end}}
 Size of HEAD on master: {{prettySize .SizeBytes}}{{if .AuthInfo}}
@@ -80,6 +125,29 @@ Access level: {{ .AuthInfo.AccessLevel.String }}{{end}}
 	return nil
 }
 
+func printReadPolicy(policy *pfs.ReadPolicy) string {
+	var parts []string
+	if len(policy.Columns) > 0 {
+		parts = append(parts, fmt.Sprintf("columns %s", strings.Join(policy.Columns, ", ")))
+	}
+	if len(policy.Patterns) > 0 {
+		parts = append(parts, fmt.Sprintf("patterns %s", strings.Join(policy.Patterns, ", ")))
+	}
+	result := "mask " + strings.Join(parts, " and ")
+	if policy.Glob != "" {
+		result += fmt.Sprintf(" in %s", policy.Glob)
+	}
+	return result
+}
+
+func printOpenCommitPolicy(policy *pfs.OpenCommitPolicy) string {
+	action := "finish"
+	if policy.Action == pfs.AbandonAction_ABANDON_DELETE {
+		action = "delete"
+	}
+	return fmt.Sprintf("%s commits open for more than %s", action, pretty.Duration(policy.MaxOpen))
+}
+
 func printTrigger(trigger *pfs.Trigger) string {
 	var conds []string
 	if trigger.CronSpec != "" {
@@ -102,7 +170,11 @@ func printTrigger(trigger *pfs.Trigger) string {
 
 // PrintBranch pretty-prints a Branch.
 func PrintBranch(w io.Writer, branchInfo *pfs.BranchInfo) {
-	fmt.Fprintf(w, "%s\t", branchInfo.Branch.Name)
+	if branchInfo.Deferred {
+		fmt.Fprintf(w, "%s (deferred)\t", branchInfo.Branch.Name)
+	} else {
+		fmt.Fprintf(w, "%s\t", branchInfo.Branch.Name)
+	}
 	if branchInfo.Head != nil {
 		fmt.Fprintf(w, "%s\t", branchInfo.Head.ID)
 	} else {
@@ -116,13 +188,83 @@ func PrintBranch(w io.Writer, branchInfo *pfs.BranchInfo) {
 	fmt.Fprintln(w)
 }
 
+// PrintDeletedInfo pretty-prints a deleted repo or commit.
+func PrintDeletedInfo(w io.Writer, deletedInfo *pfs.DeletedInfo, fullTimestamps bool) {
+	fmt.Fprintf(w, "%s\t", deletedInfo.ID)
+	if deletedInfo.RepoInfo != nil {
+		fmt.Fprintf(w, "repo %s\t", deletedInfo.RepoInfo.Repo.Name)
+	} else if len(deletedInfo.CommitInfos) > 0 {
+		commit := deletedInfo.CommitInfos[0].Commit
+		fmt.Fprintf(w, "commit %s@%s\t", commit.Repo.Name, commit.ID)
+	} else {
+		fmt.Fprintf(w, "-\t")
+	}
+	if fullTimestamps {
+		fmt.Fprintf(w, "%s\t%s\t", deletedInfo.Deleted.String(), deletedInfo.Expires.String())
+	} else {
+		fmt.Fprintf(w, "%s\t", pretty.Ago(deletedInfo.Deleted))
+		if expires, err := types.TimestampFromProto(deletedInfo.Expires); err == nil && time.Now().Before(expires) {
+			fmt.Fprintf(w, "in %s\t", units.HumanDuration(time.Until(expires)))
+		} else {
+			fmt.Fprintf(w, "expired\t")
+		}
+	}
+	if deletedInfo.DeletedBy != "" {
+		fmt.Fprintf(w, "%s\t", deletedInfo.DeletedBy)
+	} else {
+		fmt.Fprintf(w, "-\t")
+	}
+	fmt.Fprintln(w)
+}
+
+// PrintCommitTag pretty-prints a commit tag.
+func PrintCommitTag(w io.Writer, tagInfo *pfs.CommitTagInfo, fullTimestamps bool) {
+	fmt.Fprintf(w, "%s\t%s\t", tagInfo.Tag.Name, tagInfo.Commit.ID)
+	if fullTimestamps {
+		fmt.Fprintf(w, "%s\t", tagInfo.Created.String())
+	} else {
+		fmt.Fprintf(w, "%s\t", pretty.Ago(tagInfo.Created))
+	}
+	if tagInfo.Description != "" {
+		fmt.Fprintf(w, "%s\t", tagInfo.Description)
+	} else {
+		fmt.Fprintf(w, "-\t")
+	}
+	fmt.Fprintln(w)
+}
+
+// PrintAttestationEnvelope prints an attestation as a DSSE envelope, which is
+// the format that supply-chain tools verify.
+func PrintAttestationEnvelope(w io.Writer, attestation *pfs.Attestation) error {
+	type signature struct {
+		KeyID string `json:"keyid"`
+		Sig   []byte `json:"sig"`
+	}
+	envelope := struct {
+		PayloadType string      `json:"payloadType"`
+		Payload     []byte      `json:"payload"`
+		Signatures  []signature `json:"signatures"`
+	}{
+		PayloadType: attestation.PayloadType,
+		Payload:     attestation.Payload,
+		Signatures:  []signature{},
+	}
+	for _, s := range attestation.Signatures {
+		envelope.Signatures = append(envelope.Signatures, signature{KeyID: s.Keyid, Sig: s.Sig})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(envelope)
+}
+
 // PrintDetailedBranchInfo pretty-prints detailed branch info.
 func PrintDetailedBranchInfo(branchInfo *pfs.BranchInfo) error {
 	template, err := template.New("BranchInfo").Funcs(funcMap).Parse(
 		`Name: {{.Branch.Repo.Name}}@{{.Branch.Name}}{{if .Head}}
 Head Commit: {{ .Head.Repo.Name}}@{{.Head.ID}} {{end}}{{if .Provenance}}
 Provenance: {{range .Provenance}} {{.Repo.Name}}@{{.Name}} {{end}} {{end}}{{if .Trigger}}
-Trigger: {{printTrigger .Trigger}} {{end}}
+Trigger: {{printTrigger .Trigger}} {{end}}{{if .Deferred}}
+Deferred: true {{end}}
 `)
 	if err != nil {
 		return err
@@ -134,6 +276,23 @@ Trigger: {{printTrigger .Trigger}} {{end}}
 	return nil
 }
 
+// PrintDetailedFinishCommitProgress pretty-prints the progress of finishing a
+// commit.
+func PrintDetailedFinishCommitProgress(progress *pfs.FinishCommitProgress) error {
+	template, err := template.New("FinishCommitProgress").Funcs(funcMap).Parse(
+		`Commit: {{.Commit.Repo.Name}}@{{.Commit.ID}}
+Phase: {{.Phase}}{{if .FilesTotal}}
+Files: {{.FilesApplied}}/{{.FilesTotal}} {{end}}{{if .Started}}
+Started: {{prettyAgo .Started}} {{end}}{{if .Updated}}
+Updated: {{prettyAgo .Updated}} {{end}}{{if .CheckpointTree}}
+Checkpoint: {{.CheckpointFiles}} files {{end}}
+`)
+	if err != nil {
+		return err
+	}
+	return template.Execute(os.Stdout, progress)
+}
+
 // PrintCommitInfo pretty-prints commit info.
 func PrintCommitInfo(w io.Writer, commitInfo *pfs.CommitInfo, fullTimestamps bool) {
 	fmt.Fprintf(w, "%s\t", commitInfo.Commit.Repo.Name)
@@ -170,6 +329,55 @@ func PrintCommitInfo(w io.Writer, commitInfo *pfs.CommitInfo, fullTimestamps boo
 	fmt.Fprintln(w)
 }
 
+// PrintOpenCommitInfo pretty-prints an open commit.
+func PrintOpenCommitInfo(w io.Writer, commitInfo *pfs.CommitInfo, fullTimestamps bool) {
+	fmt.Fprintf(w, "%s\t", commitInfo.Commit.Repo.Name)
+	if commitInfo.Branch != nil {
+		fmt.Fprintf(w, "%s\t", commitInfo.Branch.Name)
+	} else {
+		fmt.Fprintf(w, "<none>\t")
+	}
+	fmt.Fprintf(w, "%s\t", commitInfo.Commit.ID)
+	if fullTimestamps {
+		fmt.Fprintf(w, "%s\t", commitInfo.Started.String())
+	} else {
+		fmt.Fprintf(w, "%s\t", pretty.Ago(commitInfo.Started))
+	}
+	fmt.Fprintf(w, "%s\t", commitInfo.Description)
+	fmt.Fprintln(w)
+}
+
+// PrintFlushStatus pretty-prints a commit returned by FlushCommit, with the
+// status of the job that produced it.
+func PrintFlushStatus(w io.Writer, commitInfo *pfs.CommitInfo, fullTimestamps bool) {
+	fmt.Fprintf(w, "%s\t%s\t", commitInfo.Commit.Repo.Name, commitInfo.Commit.ID)
+	if status := commitInfo.FlushStatus; status != nil {
+		fmt.Fprintf(w, "%s\t%s\t%s\t", status.Pipeline, status.JobID, flushJobState(status.State))
+	} else {
+		fmt.Fprintf(w, "-\t-\t-\t")
+	}
+	if fullTimestamps {
+		fmt.Fprintf(w, "%s\t", commitInfo.Finished.String())
+	} else {
+		fmt.Fprintf(w, "%s\t", pretty.Ago(commitInfo.Finished))
+	}
+	fmt.Fprintf(w, "%s\t", commitInfo.FlushStatus.GetReason())
+	fmt.Fprintln(w)
+}
+
+// flushJobState pretty-prints the name of a pps.JobState, in the same way as
+// pachctl list job.
+func flushJobState(state string) string {
+	s := strings.ToLower(strings.TrimPrefix(state, "JOB_"))
+	switch s {
+	case "success":
+		return color.New(color.FgGreen).SprintFunc()(s)
+	case "failure", "killed":
+		return color.New(color.FgRed).SprintFunc()(s)
+	}
+	return color.New(color.FgYellow).SprintFunc()(s)
+}
+
 // PrintableCommitInfo is a wrapper around CommitInfo containing any formatting options
 // used within the template to conditionally print information.
 type PrintableCommitInfo struct {
@@ -194,9 +402,17 @@ Parent: {{.ParentCommit.ID}}{{end}}{{if .FullTimestamps}}
 Started: {{.Started}}{{else}}
 Started: {{prettyAgo .Started}}{{end}}{{if .Finished}}{{if .FullTimestamps}}
 Finished: {{.Finished}}{{else}}
-Finished: {{prettyAgo .Finished}}{{end}}{{end}}
+Finished: {{prettyAgo .Finished}}{{end}}{{end}}{{if .Abandoned}}{{if .FullTimestamps}}
+Abandoned: {{.Abandoned}}{{else}}
+Abandoned: {{prettyAgo .Abandoned}}{{end}}{{end}}{{if .Pruned}}{{if .FullTimestamps}}
+Pruned: {{.Pruned}}{{else}}
+Pruned: {{prettyAgo .Pruned}}{{end}}{{end}}
 Size: {{prettySize .SizeBytes}}{{if .Provenance}}
-Provenance: {{range .Provenance}} {{.Commit.Repo.Name}}@{{.Commit.ID}} ({{.Branch.Name}}) {{end}} {{end}}
+Provenance: {{range .Provenance}} {{.Commit.Repo.Name}}@{{.Commit.ID}} ({{.Branch.Name}}) {{end}} {{end}}{{if .ArchivedProvenance}}
+Archived Provenance: {{range .ArchivedProvenance}} {{.Repo}}@{{.Branch}} ({{len .CommitIDs}} deleted) {{end}} {{end}}{{if .Tags}}
+Tags: {{range .Tags}} {{.}} {{end}} {{end}}{{if .Metadata}}
+Metadata: {{range $key, $value := .Metadata}} {{$key}}={{$value}} {{end}} {{end}}{{if .ChangedPaths}}
+Changed Paths{{if .ChangedPaths.Base}} (since {{.ChangedPaths.Base.ID}}){{end}}: {{range .ChangedPaths.Paths}} {{.}} {{else}} none {{end}} {{end}}
 `)
 	if err != nil {
 		return err
@@ -248,6 +464,7 @@ func PrintDiffFileInfo(w io.Writer, added bool, fileInfo *pfs.FileInfo, fullTime
 func PrintDetailedFileInfo(fileInfo *pfs.FileInfo) error {
 	template, err := template.New("FileInfo").Funcs(funcMap).Parse(
 		`Path: {{.File.Path}}
+Commit: {{.File.Commit.ID}}
 Type: {{fileType .FileType}}
 Size: {{prettySize .SizeBytes}}
 Children: {{range .Children}} {{.}} {{end}}
@@ -266,10 +483,13 @@ func fileType(fileType pfs.FileType) string {
 }
 
 var funcMap = template.FuncMap{
-	"prettyAgo":    pretty.Ago,
-	"prettySize":   pretty.Size,
-	"fileType":     fileType,
-	"printTrigger": printTrigger,
+	"prettyAgo":             pretty.Ago,
+	"prettySize":            pretty.Size,
+	"fileType":              fileType,
+	"printTrigger":          printTrigger,
+	"printReadPolicy":       printReadPolicy,
+	"printOpenCommitPolicy": printOpenCommitPolicy,
+	"join":                  strings.Join,
 }
 
 // CompactPrintBranch renders 'b' as a compact string, e.g.
@@ -8,6 +8,7 @@ import (
 
 	"github.com/pachyderm/pachyderm/src/client"
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
 
 	"github.com/gogo/protobuf/types"
 	"github.com/pachyderm/s2"
@@ -88,6 +89,14 @@ func (d *MasterDriver) bucket(pc *client.APIClient, r *http.Request, name string
 }
 
 func (d *MasterDriver) bucketCapabilities(pc *client.APIClient, r *http.Request, bucket *Bucket) (bucketCapabilities, error) {
+	if uuid.IsUUIDWithoutDashes(bucket.Commit) {
+		// Buckets named after a commit (such as those in mount credentials)
+		// are read-only
+		if _, err := pc.InspectCommit(bucket.Repo, bucket.Commit); err != nil {
+			return bucketCapabilities{}, maybeNotFoundError(r, err)
+		}
+		return bucketCapabilities{readable: true}, nil
+	}
 	branchInfo, err := pc.InspectBranch(bucket.Repo, bucket.Commit)
 	if err != nil {
 		return bucketCapabilities{}, maybeNotFoundError(r, err)
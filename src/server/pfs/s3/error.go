@@ -24,7 +24,7 @@ func writeToOutputBranchError(r *http.Request) *s2.Error {
 }
 
 func maybeNotFoundError(r *http.Request, err error) *s2.Error {
-	if pfs.IsRepoNotFoundErr(err) || pfs.IsBranchNotFoundErr(err) {
+	if pfs.IsRepoNotFoundErr(err) || pfs.IsBranchNotFoundErr(err) || pfs.IsCommitNotFoundErr(err) {
 		return s2.NoSuchBucketError(r)
 	} else if pfs.IsFileNotFoundErr(err) {
 		return s2.NoSuchKeyError(r)
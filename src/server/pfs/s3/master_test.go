@@ -88,6 +88,26 @@ func masterGetObjectInBranch(t *testing.T, pachClient *client.APIClient, minioCl
 	require.Equal(t, "content", fetchedContent)
 }
 
+func masterGetObjectInCommit(t *testing.T, pachClient *client.APIClient, minioClient *minio.Client) {
+	repo := tu.UniqueString("testgetobjectincommit")
+	require.NoError(t, pachClient.CreateRepo(repo))
+	_, err := pachClient.PutFile(repo, "master", "file", strings.NewReader("content"))
+	require.NoError(t, err)
+	commitInfo, err := pachClient.InspectCommit(repo, "master")
+	require.NoError(t, err)
+	_, err = pachClient.PutFile(repo, "master", "file", strings.NewReader("new content"))
+	require.NoError(t, err)
+
+	// Commit buckets serve the commit's files, and are read-only
+	bucket := fmt.Sprintf("%s.%s", commitInfo.Commit.ID, repo)
+	fetchedContent, err := getObject(t, minioClient, bucket, "file")
+	require.NoError(t, err)
+	require.Equal(t, "content", fetchedContent)
+	r := strings.NewReader("content")
+	_, err = minioClient.PutObject(bucket, "file", r, int64(r.Len()), minio.PutObjectOptions{ContentType: "text/plain"})
+	require.YesError(t, err)
+}
+
 func masterStatObject(t *testing.T, pachClient *client.APIClient, minioClient *minio.Client) {
 	repo := tu.UniqueString("teststatobject")
 	require.NoError(t, pachClient.CreateRepo(repo))
@@ -442,6 +462,9 @@ func TestMasterDriver(t *testing.T) {
 		t.Run("GetObjectInBranch", func(t *testing.T) {
 			masterGetObjectInBranch(t, pachClient, minioClient)
 		})
+		t.Run("GetObjectInCommit", func(t *testing.T) {
+			masterGetObjectInCommit(t, pachClient, minioClient)
+		})
 		t.Run("StatObject", func(t *testing.T) {
 			masterStatObject(t, pachClient, minioClient)
 		})
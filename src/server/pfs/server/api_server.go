@@ -19,6 +19,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
 )
 
 var _ APIServer = &apiServer{}
@@ -64,7 +65,7 @@ func (a *apiServer) CreateRepoInTransaction(
 	txnCtx *txnenv.TransactionContext,
 	request *pfs.CreateRepoRequest,
 ) error {
-	return a.driver.createRepo(txnCtx, request.Repo, request.Description, request.Update)
+	return a.driver.createRepo(txnCtx, request.Repo, request.Description, request.Update, request.Residency)
 }
 
 // CreateRepo implements the protobuf pfs.CreateRepo RPC
@@ -123,7 +124,10 @@ func (a *apiServer) DeleteRepoInTransaction(
 	request *pfs.DeleteRepoRequest,
 ) error {
 	if request.All {
-		return a.driver.deleteAll(txnCtx)
+		return a.driver.deleteAll(txnCtx, request.Prefix)
+	}
+	if request.Prefix != "" {
+		return errors.New("prefix can only be set along with all")
 	}
 	return a.driver.deleteRepo(txnCtx, request.Repo, request.Force)
 }
@@ -141,6 +145,61 @@ func (a *apiServer) DeleteRepo(ctx context.Context, request *pfs.DeleteRepoReque
 	return &types.Empty{}, nil
 }
 
+// SetReadPolicy implements the protobuf pfs.SetReadPolicy RPC
+func (a *apiServer) SetReadPolicy(ctx context.Context, request *pfs.SetReadPolicyRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	if err := a.driver.setReadPolicy(a.env.GetPachClient(ctx), request.Repo, request.Policy); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
+// SetOpenCommitPolicy implements the protobuf pfs.SetOpenCommitPolicy RPC
+func (a *apiServer) SetOpenCommitPolicy(ctx context.Context, request *pfs.SetOpenCommitPolicyRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	if err := a.driver.setOpenCommitPolicy(a.env.GetPachClient(ctx), request.Repo, request.Policy); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
+// ListOpenCommits implements the protobuf pfs.ListOpenCommits RPC
+func (a *apiServer) ListOpenCommits(ctx context.Context, request *pfs.ListOpenCommitsRequest) (response *pfs.CommitInfos, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	commitInfos, err := a.driver.listOpenCommits(a.env.GetPachClient(ctx), request.Repo)
+	if err != nil {
+		return nil, err
+	}
+	return &pfs.CommitInfos{
+		CommitInfo: commitInfos,
+	}, nil
+}
+
+// UpdateRepoMetadata implements the protobuf pfs.UpdateRepoMetadata RPC
+func (a *apiServer) UpdateRepoMetadata(ctx context.Context, request *pfs.UpdateRepoMetadataRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	if err := a.driver.updateRepoMetadata(a.env.GetPachClient(ctx), request.Repo, request.Metadata, request.Description); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
+// DiscoverRepos implements the protobuf pfs.DiscoverRepos RPC
+func (a *apiServer) DiscoverRepos(ctx context.Context, request *pfs.DiscoverReposRequest) (response *pfs.DiscoverReposResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	return a.driver.discoverRepos(a.env.GetPachClient(ctx), request)
+}
+
 // Fsckimplements the protobuf pfs.Fsck RPC
 func (a *apiServer) Fsck(request *pfs.FsckRequest, fsckServer pfs.API_FsckServer) (retErr error) {
 	func() { a.Log(request, nil, nil, 0) }()
@@ -157,6 +216,76 @@ func (a *apiServer) Fsck(request *pfs.FsckRequest, fsckServer pfs.API_FsckServer
 	return nil
 }
 
+// ArchiveProvenance implements the protobuf pfs.ArchiveProvenance RPC
+func (a *apiServer) ArchiveProvenance(ctx context.Context, request *pfs.ArchiveProvenanceRequest) (response *pfs.ArchiveProvenanceResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	return a.driver.archiveProvenance(a.env.GetPachClient(ctx), request.DryRun)
+}
+
+// ChangeFeed implements the protobuf pfs.ChangeFeed RPC
+func (a *apiServer) ChangeFeed(request *pfs.ChangeFeedRequest, changeFeedServer pfs.API_ChangeFeedServer) (retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	sent := 0
+	defer func(start time.Time) {
+		a.Log(request, fmt.Sprintf("stream containing %d messages", sent), retErr, time.Since(start))
+	}(time.Now())
+	return a.driver.changeFeed(a.env.GetPachClient(changeFeedServer.Context()), request.Branch, request.Cursor, func(event *pfs.ChangeFeedEvent) error {
+		sent++
+		return changeFeedServer.Send(event)
+	})
+}
+
+// WatchRepos implements the protobuf pfs.WatchRepos RPC
+func (a *apiServer) WatchRepos(request *pfs.WatchReposRequest, watchReposServer pfs.API_WatchReposServer) (retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	sent := 0
+	defer func(start time.Time) {
+		a.Log(request, fmt.Sprintf("stream containing %d messages", sent), retErr, time.Since(start))
+	}(time.Now())
+	return a.driver.watchRepos(a.env.GetPachClient(watchReposServer.Context()), func(event *pfs.RepoEvent) error {
+		sent++
+		return watchReposServer.Send(event)
+	})
+}
+
+// ListDeleted implements the protobuf pfs.ListDeleted RPC
+func (a *apiServer) ListDeleted(ctx context.Context, request *pfs.ListDeletedRequest) (response *pfs.ListDeletedResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) {
+		// Deleted repos include all of their commits, so only log how many
+		// items there are
+		a.Log(request, fmt.Sprintf("response containing %d deleted items", len(response.GetDeletedInfo())), retErr, time.Since(start))
+	}(time.Now())
+	return a.driver.listDeleted(a.env.GetPachClient(ctx))
+}
+
+// RestoreDeleted implements the protobuf pfs.RestoreDeleted RPC
+func (a *apiServer) RestoreDeleted(ctx context.Context, request *pfs.RestoreDeletedRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	if err := a.txnEnv.WithWriteContext(ctx, func(txnCtx *txnenv.TransactionContext) error {
+		return a.driver.restoreDeleted(txnCtx, request.ID)
+	}); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
+// PurgeDeleted implements the protobuf pfs.PurgeDeleted RPC
+func (a *apiServer) PurgeDeleted(ctx context.Context, request *pfs.PurgeDeletedRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	if err := a.txnEnv.WithWriteContext(ctx, func(txnCtx *txnenv.TransactionContext) error {
+		return a.driver.purgeDeleted(txnCtx, request.ID)
+	}); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
 // StartCommitInTransaction is identical to StartCommit except that it can run
 // inside an existing etcd STM transaction.  This is not an RPC.  The target
 // commit can be specified but is optional.  This is so that the transaction can
@@ -171,7 +300,12 @@ func (a *apiServer) StartCommitInTransaction(
 	if commit != nil {
 		id = commit.ID
 	}
-	return a.driver.startCommit(txnCtx, id, request.Parent, request.Branch, request.Provenance, request.Description)
+	if request.ExpectedHead != nil {
+		if err := a.driver.checkStartCommitHead(txnCtx, request.Parent, request.Branch, request.ExpectedHead); err != nil {
+			return nil, err
+		}
+	}
+	return a.driver.startCommit(txnCtx, id, request.Parent, request.Branch, request.Provenance, request.Description, request.Metadata)
 }
 
 // StartCommit implements the protobuf pfs.StartCommit RPC
@@ -225,10 +359,22 @@ func (a *apiServer) FinishCommitInTransaction(
 	txnCtx *txnenv.TransactionContext,
 	request *pfs.FinishCommitRequest,
 ) error {
+	if request.RequireHead {
+		if err := a.driver.checkCommitIsHead(txnCtx, request.Commit); err != nil {
+			return err
+		}
+	}
 	if request.Trees != nil {
-		return a.driver.finishOutputCommit(txnCtx, request.Commit, request.Trees, request.Datums, request.SizeBytes)
+		if err := a.driver.finishOutputCommit(txnCtx, request.Commit, request.Trees, request.Datums, request.SizeBytes, request.ChangedPaths, request.Description, request.Metadata); err != nil {
+			return err
+		}
+	} else if err := a.driver.finishCommit(txnCtx, request.Commit, request.Tree, request.Empty, request.Description, request.Metadata); err != nil {
+		return err
+	}
+	if request.Build != nil {
+		return a.driver.attestCommit(txnCtx, request.Commit, request.Build)
 	}
-	return a.driver.finishCommit(txnCtx, request.Commit, request.Tree, request.Empty, request.Description)
+	return nil
 }
 
 // FinishCommit implements the protobuf pfs.FinishCommit RPC
@@ -244,7 +390,14 @@ func (a *apiServer) FinishCommit(ctx context.Context, request *pfs.FinishCommitR
 	return &types.Empty{}, nil
 }
 
-// InspectCommit implements the protobuf pfs.InspectCommit RPC
+// FinishCommitStatus implements the protobuf pfs.FinishCommitStatus RPC
+func (a *apiServer) FinishCommitStatus(ctx context.Context, request *pfs.FinishCommitStatusRequest) (response *pfs.FinishCommitProgress, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	return a.driver.finishCommitStatus(a.env.GetPachClient(ctx), request.Commit)
+}
+
 func (a *apiServer) InspectCommit(ctx context.Context, request *pfs.InspectCommitRequest) (response *pfs.CommitInfo, retErr error) {
 	func() { a.Log(request, nil, nil, 0) }()
 	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
@@ -301,6 +454,32 @@ func (a *apiServer) CreateBranch(ctx context.Context, request *pfs.CreateBranchR
 	return &types.Empty{}, nil
 }
 
+// MoveBranch implements the protobuf pfs.MoveBranch RPC
+func (a *apiServer) MoveBranch(ctx context.Context, request *pfs.MoveBranchRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	if err := a.txnEnv.WithWriteContext(ctx, func(txnCtx *txnenv.TransactionContext) error {
+		return a.driver.moveBranch(txnCtx, request.Branch, request.Head, request.ExpectedHead)
+	}); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
+// SetBranchDeferred implements the protobuf pfs.SetBranchDeferred RPC
+func (a *apiServer) SetBranchDeferred(ctx context.Context, request *pfs.SetBranchDeferredRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	if err := a.txnEnv.WithWriteContext(ctx, func(txnCtx *txnenv.TransactionContext) error {
+		return a.driver.setBranchDeferred(txnCtx, request.Branch, request.Deferred)
+	}); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
 // InspectBranch implements the protobuf pfs.InspectBranch RPC
 func (a *apiServer) InspectBranch(ctx context.Context, request *pfs.InspectBranchRequest) (response *pfs.BranchInfo, retErr error) {
 	func() { a.Log(request, nil, nil, 0) }()
@@ -351,6 +530,67 @@ func (a *apiServer) DeleteBranch(ctx context.Context, request *pfs.DeleteBranchR
 	return &types.Empty{}, nil
 }
 
+// TagCommit implements the protobuf pfs.TagCommit RPC
+func (a *apiServer) TagCommit(ctx context.Context, request *pfs.TagCommitRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	if err := a.driver.tagCommit(a.env.GetPachClient(ctx), request.Tag, request.Commit, request.Description); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
+// InspectCommitTag implements the protobuf pfs.InspectCommitTag RPC
+func (a *apiServer) InspectCommitTag(ctx context.Context, request *pfs.InspectCommitTagRequest) (response *pfs.CommitTagInfo, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	return a.driver.inspectCommitTag(a.env.GetPachClient(ctx), request.Tag)
+}
+
+// ListCommitTag implements the protobuf pfs.ListCommitTag RPC
+func (a *apiServer) ListCommitTag(ctx context.Context, request *pfs.ListCommitTagRequest) (response *pfs.CommitTagInfos, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	tagInfos, err := a.driver.listCommitTag(a.env.GetPachClient(ctx), request.Repo)
+	if err != nil {
+		return nil, err
+	}
+	return &pfs.CommitTagInfos{CommitTagInfo: tagInfos}, nil
+}
+
+// DeleteCommitTag implements the protobuf pfs.DeleteCommitTag RPC
+func (a *apiServer) DeleteCommitTag(ctx context.Context, request *pfs.DeleteCommitTagRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	if err := a.driver.deleteCommitTag(a.env.GetPachClient(ctx), request.Tag); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
+// PruneCommit implements the protobuf pfs.PruneCommit RPC
+func (a *apiServer) PruneCommit(ctx context.Context, request *pfs.PruneCommitRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	if err := a.driver.pruneCommit(a.env.GetPachClient(ctx), request.Commit); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
+// GetAttestation implements the protobuf pfs.GetAttestation RPC
+func (a *apiServer) GetAttestation(ctx context.Context, request *pfs.GetAttestationRequest) (response *pfs.Attestation, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	return a.driver.getAttestation(a.env.GetPachClient(ctx), request.Commit)
+}
+
 // DeleteCommitInTransaction is identical to DeleteCommit except that it can run
 // inside an existing etcd STM transaction.  This is not an RPC.
 func (a *apiServer) DeleteCommitInTransaction(
@@ -378,7 +618,19 @@ func (a *apiServer) FlushCommit(request *pfs.FlushCommitRequest, stream pfs.API_
 	func() { a.Log(request, nil, nil, 0) }()
 	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
 
-	return a.driver.flushCommit(a.env.GetPachClient(stream.Context()), request.Commits, request.ToRepos, stream.Send)
+	pachClient := a.env.GetPachClient(stream.Context())
+	send := stream.Send
+	if request.JobStatus {
+		send = func(commitInfo *pfs.CommitInfo) error {
+			status, err := a.driver.flushStatus(pachClient, commitInfo.Commit)
+			if err != nil {
+				return err
+			}
+			commitInfo.FlushStatus = status
+			return stream.Send(commitInfo)
+		}
+	}
+	return a.driver.flushCommit(pachClient, request.Commits, request.ToRepos, send)
 }
 
 // SubscribeCommit implements the protobuf pfs.SubscribeCommit RPC
@@ -435,7 +687,69 @@ func (a *apiServer) GetFile(request *pfs.GetFileRequest, apiGetFileServer pfs.AP
 
 		a.Log(request, nil, retErr, time.Since(start))
 	}(time.Now())
-	file, err := a.driver.getFile(a.env.GetPachClient(apiGetFileServer.Context()), request.File, request.OffsetBytes, request.SizeBytes)
+	pachClient := a.env.GetPachClient(apiGetFileServer.Context())
+	if err := validateFile(request.File); err != nil {
+		return err
+	}
+	if request.OffsetBytes < 0 || request.SizeBytes < 0 {
+		return errors.Errorf("offset_bytes and size_bytes can't be negative")
+	}
+	if request.UrlSignature != nil {
+		if request.AncestryDepth != 0 {
+			return errors.New("ancestry_depth can't be set along with a URL signature")
+		}
+		var err error
+		if pachClient, err = a.driver.urlSignatureClient(pachClient, request.File, request.UrlSignature); err != nil {
+			return err
+		}
+	}
+	if request.AncestryDepth != 0 {
+		fileInfo, err := a.driver.inspectFileAncestry(pachClient, request.File, request.AncestryDepth)
+		if err != nil {
+			return err
+		}
+		if err := apiGetFileServer.SetHeader(metadata.Pairs(pfs.ResolvedCommitKey, fileInfo.File.Commit.ID)); err != nil {
+			return err
+		}
+		request = proto.Clone(request).(*pfs.GetFileRequest)
+		request.File = fileInfo.File
+	}
+	redactor, err := a.driver.getRedactor(pachClient, request.File.Commit.Repo)
+	if err != nil {
+		return err
+	}
+	if len(request.Ranges) > 0 {
+		if redactor != nil {
+			return errors.Errorf("repo %q has a read policy, so its files can't be read by range", request.File.Commit.Repo.Name)
+		}
+		return a.getFileRanges(pachClient, request, apiGetFileServer)
+	}
+	if redactor != nil {
+		if request.Delimiter != pfs.Delimiter_NONE || request.OffsetRecords != 0 || request.SizeRecords != 0 {
+			return errors.Errorf("repo %q has a read policy, so its files can't be read by record", request.File.Commit.Repo.Name)
+		}
+		file, err := a.driver.getRedactedFile(pachClient, redactor, request.File, request.OffsetBytes, request.SizeBytes)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return grpcutil.WriteToStreamingBytesServer(file, apiGetFileServer)
+	}
+	if request.Delimiter != pfs.Delimiter_NONE {
+		if request.OffsetBytes != 0 || request.SizeBytes != 0 {
+			return errors.Errorf("offset_bytes and size_bytes can't be set along with a delimiter; use offset_records and size_records")
+		}
+		records, err := a.driver.getFileRecords(pachClient, request.File, request.Delimiter, request.OffsetRecords, request.SizeRecords)
+		if err != nil {
+			return err
+		}
+		defer records.Close()
+		return grpcutil.WriteToStreamingBytesServer(records, apiGetFileServer)
+	}
+	if request.OffsetRecords != 0 || request.SizeRecords != 0 {
+		return errors.Errorf("offset_records and size_records require a delimiter")
+	}
+	file, err := a.driver.getFile(pachClient, request.File, request.OffsetBytes, request.SizeBytes)
 	if err != nil {
 		return err
 	}
@@ -455,7 +769,28 @@ func (a *apiServer) InspectFile(ctx context.Context, request *pfs.InspectFileReq
 		}
 	}(time.Now())
 
-	return a.driver.inspectFile(a.env.GetPachClient(ctx), request.File)
+	pachClient := a.env.GetPachClient(ctx)
+	if request.UrlSignature != nil {
+		if request.AncestryDepth != 0 {
+			return nil, errors.New("ancestry_depth can't be set along with a URL signature")
+		}
+		var err error
+		if pachClient, err = a.driver.urlSignatureClient(pachClient, request.File, request.UrlSignature); err != nil {
+			return nil, err
+		}
+	}
+	if request.AncestryDepth != 0 {
+		return a.driver.inspectFileAncestry(pachClient, request.File, request.AncestryDepth)
+	}
+	return a.driver.inspectFile(pachClient, request.File)
+}
+
+// GetFileURL implements the protobuf pfs.GetFileURL RPC
+func (a *apiServer) GetFileURL(ctx context.Context, request *pfs.GetFileURLRequest) (response *pfs.GetFileURLResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	return a.driver.getFileURL(a.env.GetPachClient(ctx), request.File, request.Ttl)
 }
 
 // ListFile implements the protobuf pfs.ListFile RPC
@@ -581,13 +916,35 @@ func (a *apiServer) DeleteFile(ctx context.Context, request *pfs.DeleteFileReque
 	return &types.Empty{}, nil
 }
 
+// DeleteFiles implements the protobuf pfs.DeleteFiles RPC
+func (a *apiServer) DeleteFiles(ctx context.Context, request *pfs.DeleteFilesRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	if err := a.driver.deleteFiles(a.env.GetPachClient(ctx), request.Commit, request.Paths); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
+// ReplaceDirectory implements the protobuf pfs.ReplaceDirectory RPC
+func (a *apiServer) ReplaceDirectory(ctx context.Context, request *pfs.ReplaceDirectoryRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	if err := a.driver.replaceDirectory(a.env.GetPachClient(ctx), request.File); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
 // DeleteAll implements the protobuf pfs.DeleteAll RPC
 func (a *apiServer) DeleteAll(ctx context.Context, request *types.Empty) (response *types.Empty, retErr error) {
 	func() { a.Log(request, nil, nil, 0) }()
 	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
 
 	err := a.txnEnv.WithWriteContext(ctx, func(txnCtx *txnenv.TransactionContext) error {
-		return a.driver.deleteAll(txnCtx)
+		return a.driver.deleteAll(txnCtx, "")
 	})
 	if err != nil {
 		return nil, err
@@ -47,7 +47,10 @@ func newAPIServerV2(env *serviceenv.ServiceEnv, txnEnv *txnenv.TransactionEnv, e
 // inside an existing etcd STM transaction.  This is not an RPC.
 func (a *apiServerV2) DeleteRepoInTransaction(txnCtx *txnenv.TransactionContext, request *pfs.DeleteRepoRequest) error {
 	if request.All {
-		return a.driver.deleteAll(txnCtx)
+		return a.driver.deleteAll(txnCtx, request.Prefix)
+	}
+	if request.Prefix != "" {
+		return errors.New("prefix can only be set along with all")
 	}
 	return a.driver.deleteRepo(txnCtx, request.Repo, request.Force)
 }
@@ -59,7 +62,7 @@ func (a *apiServerV2) FinishCommitInTransaction(txnCtx *txnenv.TransactionContex
 		if request.Empty {
 			request.Description += pfs.EmptyStr
 		}
-		return a.driver.finishCommitV2(txnCtx, request.Commit, request.Description)
+		return a.driver.finishCommitV2(txnCtx, request.Commit, request.Description, request.Metadata)
 	})
 }
 
@@ -69,6 +72,11 @@ func (a *apiServerV2) DeleteCommitInTransaction(txnCtx *txnenv.TransactionContex
 	return a.driver.deleteCommit(txnCtx, request.Commit)
 }
 
+// PruneCommit is not implemented in V2.
+func (a *apiServerV2) PruneCommit(_ context.Context, _ *pfs.PruneCommitRequest) (*types.Empty, error) {
+	return nil, errV1NotImplemented
+}
+
 // BuildCommit is not implemented in V2.
 func (a *apiServerV2) BuildCommit(_ context.Context, _ *pfs.BuildCommitRequest) (*pfs.Commit, error) {
 	return nil, errV1NotImplemented
@@ -98,9 +106,20 @@ func (a *apiServerV2) GetFile(_ *pfs.GetFileRequest, _ pfs.API_GetFileServer) er
 func (a *apiServerV2) InspectFile(ctx context.Context, request *pfs.InspectFileRequest) (response *pfs.FileInfo, retErr error) {
 	func() { a.Log(request, nil, nil, 0) }()
 	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	if request.AncestryDepth != 0 {
+		return nil, errors.New("ancestry_depth is not implemented in V2")
+	}
+	if request.UrlSignature != nil {
+		return nil, errors.New("url_signature is not implemented in V2")
+	}
 	return a.driver.inspectFile(a.env.GetPachClient(ctx), request.File)
 }
 
+// GetFileURL is not implemented in V2.
+func (a *apiServerV2) GetFileURL(_ context.Context, _ *pfs.GetFileURLRequest) (*pfs.GetFileURLResponse, error) {
+	return nil, errV1NotImplemented
+}
+
 // ListFile is not implemented in V2.
 func (a *apiServerV2) ListFile(_ context.Context, _ *pfs.ListFileRequest) (*pfs.FileInfos, error) {
 	return nil, errV1NotImplemented
@@ -145,10 +164,20 @@ func (a *apiServerV2) DeleteFile(ctx context.Context, request *pfs.DeleteFileReq
 	return nil, errV1NotImplemented
 }
 
+// DeleteFiles is not implemented in V2.
+func (a *apiServerV2) DeleteFiles(_ context.Context, _ *pfs.DeleteFilesRequest) (*types.Empty, error) {
+	return nil, errV1NotImplemented
+}
+
+// ReplaceDirectory is not implemented in V2.
+func (a *apiServerV2) ReplaceDirectory(_ context.Context, _ *pfs.ReplaceDirectoryRequest) (*types.Empty, error) {
+	return nil, errV1NotImplemented
+}
+
 // DeleteAll implements the protobuf pfs.DeleteAll RPC
 func (a *apiServerV2) DeleteAll(ctx context.Context, request *types.Empty) (response *types.Empty, retErr error) {
 	err := a.txnEnv.WithWriteContext(ctx, func(txnCtx *txnenv.TransactionContext) error {
-		return a.driver.deleteAll(txnCtx)
+		return a.driver.deleteAll(txnCtx, "")
 	})
 	if err != nil {
 		return nil, err
@@ -161,6 +190,36 @@ func (a *apiServerV2) Fsck(_ *pfs.FsckRequest, _ pfs.API_FsckServer) error {
 	return errV1NotImplemented
 }
 
+// ArchiveProvenance is not implemented in V2.
+func (a *apiServerV2) ArchiveProvenance(_ context.Context, _ *pfs.ArchiveProvenanceRequest) (*pfs.ArchiveProvenanceResponse, error) {
+	return nil, errV1NotImplemented
+}
+
+// ChangeFeed is not implemented in V2.
+func (a *apiServerV2) ChangeFeed(_ *pfs.ChangeFeedRequest, _ pfs.API_ChangeFeedServer) error {
+	return errV1NotImplemented
+}
+
+// FinishCommitStatus is not implemented in V2.
+func (a *apiServerV2) FinishCommitStatus(_ context.Context, _ *pfs.FinishCommitStatusRequest) (*pfs.FinishCommitProgress, error) {
+	return nil, errV1NotImplemented
+}
+
+// ListDeleted is not implemented in V2.
+func (a *apiServerV2) ListDeleted(_ context.Context, _ *pfs.ListDeletedRequest) (*pfs.ListDeletedResponse, error) {
+	return nil, errV1NotImplemented
+}
+
+// RestoreDeleted is not implemented in V2.
+func (a *apiServerV2) RestoreDeleted(_ context.Context, _ *pfs.RestoreDeletedRequest) (*types.Empty, error) {
+	return nil, errV1NotImplemented
+}
+
+// PurgeDeleted is not implemented in V2.
+func (a *apiServerV2) PurgeDeleted(_ context.Context, _ *pfs.PurgeDeletedRequest) (*types.Empty, error) {
+	return nil, errV1NotImplemented
+}
+
 func (a *apiServerV2) FileOperationV2(server pfs.API_FileOperationV2Server) (retErr error) {
 	request, err := server.Recv()
 	func() { a.Log(request, nil, nil, 0) }()
@@ -247,8 +306,15 @@ func (a *apiServerV2) GetTarV2(request *pfs.GetTarRequestV2, server pfs.API_GetT
 	return metrics.ReportRequestWithThroughput(func() (int64, error) {
 		commit := request.File.Commit
 		glob := request.File.Path
+		pachClient := a.env.GetPachClient(server.Context())
+		// Redaction is only implemented by GetFile
+		if redactor, err := a.driver.getRedactor(pachClient, commit.Repo); err != nil {
+			return 0, err
+		} else if redactor != nil {
+			return 0, errors.Errorf("repo %q has a read policy, so its files can only be read with GetFile", commit.Repo.Name)
+		}
 		gtw := newGetTarWriter(grpcutil.NewStreamingBytesWriter(server))
-		err := a.driver.getTar(a.env.GetPachClient(server.Context()), commit, glob, gtw)
+		err := a.driver.getTar(pachClient, commit, glob, gtw)
 		return gtw.bytesWritten, err
 	})
 }
@@ -317,5 +383,5 @@ func (a *apiServerV2) CreateRepoInTransaction(
 	if repo := request.GetRepo(); repo != nil && repo.Name == tmpRepo {
 		return errors.Errorf("%s is a reserved name", tmpRepo)
 	}
-	return a.driver.createRepo(txnCtx, request.Repo, request.Description, request.Update)
+	return a.driver.createRepo(txnCtx, request.Repo, request.Description, request.Update, request.Residency)
 }
@@ -0,0 +1,272 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
+	txnenv "github.com/pachyderm/pachyderm/src/server/pkg/transactionenv"
+
+	"github.com/gogo/protobuf/types"
+)
+
+const (
+	// attestationPayloadType is the DSSE payload type of in-toto statements
+	attestationPayloadType = "application/vnd.in-toto+json"
+	inTotoStatementType    = "https://in-toto.io/Statement/v0.1"
+	slsaProvenanceType     = "https://slsa.dev/provenance/v0.2"
+	pipelineBuildType      = "https://pachyderm.com/pipeline@v1"
+
+	// attestationKeyName is the key of pachd's signing key in the
+	// attestationKey collection
+	attestationKeyName = "signing"
+)
+
+// The in-toto statement and SLSA provenance predicate that attestations sign.
+// Only the fields that PFS fills in are included.
+type inTotoStatement struct {
+	Type          string          `json:"_type"`
+	Subject       []inTotoSubject `json:"subject"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     slsaProvenance  `json:"predicate"`
+}
+
+type inTotoSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type slsaProvenance struct {
+	Builder    slsaBuilder     `json:"builder"`
+	BuildType  string          `json:"buildType"`
+	Invocation slsaInvocation  `json:"invocation"`
+	Metadata   slsaMetadata    `json:"metadata"`
+	Materials  []inTotoSubject `json:"materials"`
+}
+
+type slsaBuilder struct {
+	ID string `json:"id"`
+}
+
+type slsaInvocation struct {
+	ConfigSource slsaConfigSource `json:"configSource"`
+}
+
+type slsaConfigSource struct {
+	URI        string            `json:"uri"`
+	Digest     map[string]string `json:"digest"`
+	EntryPoint string            `json:"entryPoint"`
+}
+
+type slsaMetadata struct {
+	BuildInvocationID string     `json:"buildInvocationId"`
+	BuildStartedOn    *time.Time `json:"buildStartedOn,omitempty"`
+	BuildFinishedOn   *time.Time `json:"buildFinishedOn,omitempty"`
+}
+
+// commitURI identifies a commit in attestations
+func commitURI(commit *pfs.Commit) string {
+	return fmt.Sprintf("pfs://%s@%s", commit.Repo.Name, commit.ID)
+}
+
+// commitDigest returns the digest of a finished commit's contents. Output
+// commits may have several hashtrees, so the digest is the sha256 of their
+// object hashes, which are themselves content hashes.
+func commitDigest(commitInfo *pfs.CommitInfo) map[string]string {
+	h := sha256.New()
+	if commitInfo.Tree != nil {
+		fmt.Fprintln(h, commitInfo.Tree.Hash)
+	}
+	for _, tree := range commitInfo.Trees {
+		if tree != nil {
+			fmt.Fprintln(h, tree.Hash)
+		}
+	}
+	return map[string]string{"sha256": hex.EncodeToString(h.Sum(nil))}
+}
+
+// preAuthEncoding returns the DSSE pre-authentication encoding of a payload,
+// which is what's actually signed
+func preAuthEncoding(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+func attestationKeyID(publicKey ed25519.PublicKey) string {
+	sum := sha256.Sum256(publicKey)
+	return hex.EncodeToString(sum[:8])
+}
+
+// signAttestation signs 'payload' with 'key', and returns the attestation of
+// 'commit' that contains it
+func signAttestation(key *pfs.AttestationKey, commit *pfs.Commit, payload []byte) *pfs.Attestation {
+	privateKey := ed25519.PrivateKey(key.PrivateKey)
+	return &pfs.Attestation{
+		Commit:      commit,
+		PayloadType: attestationPayloadType,
+		Payload:     payload,
+		Signatures: []*pfs.AttestationSignature{{
+			Keyid:     key.Keyid,
+			Sig:       ed25519.Sign(privateKey, preAuthEncoding(attestationPayloadType, payload)),
+			PublicKey: privateKey.Public().(ed25519.PublicKey),
+		}},
+	}
+}
+
+// getSigningKey returns the key named 'name' in the attestationKey
+// collection, generating it if it hasn't been used yet. Attestations are
+// signed with the key named attestationKeyName.
+func (d *driver) getSigningKey(stm col.STM, name string) (*pfs.AttestationKey, error) {
+	keys := d.attestationKey.ReadWrite(stm)
+	key := &pfs.AttestationKey{}
+	if err := keys.Get(name, key); err == nil {
+		return key, nil
+	} else if !col.IsErrNotFound(err) {
+		return nil, err
+	}
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not generate %q signing key", name)
+	}
+	key = &pfs.AttestationKey{
+		Keyid:      attestationKeyID(publicKey),
+		PrivateKey: privateKey,
+	}
+	if err := keys.Put(name, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// attestCommit signs and stores an attestation of 'commit', which must have
+// just been finished by the job that 'build' describes. The attestation
+// records the pipeline's spec (read from its spec commit, which is in the
+// output commit's provenance), its image, and the job's input commits, and
+// names the caller (the pipeline, if auth is active) as the builder.
+func (d *driver) attestCommit(txnCtx *txnenv.TransactionContext, commit *pfs.Commit, build *pfs.BuildInfo) error {
+	commitInfo, err := d.resolveCommit(txnCtx.Stm, commit)
+	if err != nil {
+		return err
+	}
+	builderID := "pachd"
+	if me, err := txnCtx.Client.WhoAmI(txnCtx.ClientContext, &auth.WhoAmIRequest{}); err == nil {
+		builderID = "pachd:" + me.Username
+	} else if !auth.IsErrNotActivated(err) {
+		return grpcutil.ScrubGRPC(err)
+	}
+
+	provenance := slsaProvenance{
+		Builder:   slsaBuilder{ID: builderID},
+		BuildType: pipelineBuildType,
+		Metadata:  slsaMetadata{BuildInvocationID: build.JobID},
+		Materials: []inTotoSubject{},
+	}
+	if build.Started != nil {
+		if started, err := types.TimestampFromProto(build.Started); err == nil {
+			provenance.Metadata.BuildStartedOn = &started
+		}
+	}
+	if finished, err := types.TimestampFromProto(commitInfo.Finished); err == nil {
+		provenance.Metadata.BuildFinishedOn = &finished
+	}
+
+	// The pipeline's spec is the build's config, and its image is a material
+	for _, prov := range commitInfo.Provenance {
+		if prov.Commit.Repo.Name != ppsconsts.SpecRepo {
+			continue
+		}
+		r, err := d.getFile(txnCtx.Client, client.NewFile(ppsconsts.SpecRepo, prov.Commit.ID, ppsconsts.SpecFile), 0, 0)
+		if err != nil {
+			return errors.Wrapf(err, "could not read the spec of pipeline %q", build.Pipeline)
+		}
+		spec, err := ioutil.ReadAll(r)
+		if err != nil {
+			return errors.Wrapf(err, "could not read the spec of pipeline %q", build.Pipeline)
+		}
+		specDigest := sha256.Sum256(spec)
+		provenance.Invocation.ConfigSource = slsaConfigSource{
+			URI:        commitURI(prov.Commit),
+			Digest:     map[string]string{"sha256": hex.EncodeToString(specDigest[:])},
+			EntryPoint: build.Pipeline,
+		}
+		pipelineInfo := &pps.PipelineInfo{}
+		if err := pipelineInfo.Unmarshal(spec); err != nil {
+			return errors.Wrapf(err, "could not unmarshal the spec of pipeline %q", build.Pipeline)
+		}
+		if pipelineInfo.Transform != nil && pipelineInfo.Transform.Image != "" {
+			image := inTotoSubject{
+				Name:   "docker://" + pipelineInfo.Transform.Image,
+				Digest: map[string]string{},
+			}
+			// The image's digest is only known if the pipeline pins it
+			if i := strings.LastIndex(pipelineInfo.ImageDigest, "@sha256:"); i >= 0 {
+				image.Digest["sha256"] = pipelineInfo.ImageDigest[i+len("@sha256:"):]
+			}
+			provenance.Materials = append(provenance.Materials, image)
+		}
+		break
+	}
+	for _, input := range build.Inputs {
+		inputInfo, err := d.resolveCommit(txnCtx.Stm, input)
+		if err != nil {
+			return err
+		}
+		provenance.Materials = append(provenance.Materials, inTotoSubject{
+			Name:   commitURI(inputInfo.Commit),
+			Digest: commitDigest(inputInfo),
+		})
+	}
+
+	payload, err := json.Marshal(&inTotoStatement{
+		Type: inTotoStatementType,
+		Subject: []inTotoSubject{{
+			Name:   commitURI(commitInfo.Commit),
+			Digest: commitDigest(commitInfo),
+		}},
+		PredicateType: slsaProvenanceType,
+		Predicate:     provenance,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "could not marshal attestation")
+	}
+	key, err := d.getSigningKey(txnCtx.Stm, attestationKeyName)
+	if err != nil {
+		return err
+	}
+	attestation := signAttestation(key, commitInfo.Commit, payload)
+	return d.attestations(commitInfo.Commit.Repo.Name).ReadWrite(txnCtx.Stm).Put(commitInfo.Commit.ID, attestation)
+}
+
+func (d *driver) getAttestation(pachClient *client.APIClient, commit *pfs.Commit) (*pfs.Attestation, error) {
+	if commit == nil || commit.Repo == nil {
+		return nil, errors.New("commit cannot be nil")
+	}
+	if err := d.checkIsAuthorized(pachClient, commit.Repo, auth.Scope_READER); err != nil {
+		return nil, err
+	}
+	commitInfo, err := d.inspectCommit(pachClient, commit, pfs.CommitState_STARTED)
+	if err != nil {
+		return nil, err
+	}
+	attestation := &pfs.Attestation{}
+	if err := d.attestations(commit.Repo.Name).ReadOnly(pachClient.Ctx()).Get(commitInfo.Commit.ID, attestation); err != nil {
+		if col.IsErrNotFound(err) {
+			return nil, errors.Errorf("commit %s has no attestation", commitInfo.Commit.FullID())
+		}
+		return nil, err
+	}
+	return attestation, nil
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestSignAttestation(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key := &pfs.AttestationKey{Keyid: attestationKeyID(publicKey), PrivateKey: privateKey}
+
+	payload := []byte(`{"_type":"https://in-toto.io/Statement/v0.1"}`)
+	attestation := signAttestation(key, client.NewCommit("model", "abc"), payload)
+	require.Equal(t, attestationPayloadType, attestation.PayloadType)
+	require.Equal(t, 1, len(attestation.Signatures))
+	sig := attestation.Signatures[0]
+	require.Equal(t, key.Keyid, sig.Keyid)
+	require.Equal(t, []byte(publicKey), sig.PublicKey)
+	require.True(t, ed25519.Verify(sig.PublicKey, preAuthEncoding(attestation.PayloadType, attestation.Payload), sig.Sig))
+	require.False(t, ed25519.Verify(sig.PublicKey, preAuthEncoding(attestation.PayloadType, []byte("{}")), sig.Sig))
+}
+
+func TestPreAuthEncoding(t *testing.T) {
+	// The example from the DSSE spec
+	require.Equal(t, "DSSEv1 29 http://example.com/HelloWorld 11 hello world",
+		string(preAuthEncoding("http://example.com/HelloWorld", []byte("hello world"))))
+}
+
+func TestCommitDigest(t *testing.T) {
+	a := commitDigest(&pfs.CommitInfo{Trees: []*pfs.Object{{Hash: "1"}, {Hash: "2"}}})
+	b := commitDigest(&pfs.CommitInfo{Trees: []*pfs.Object{{Hash: "2"}, {Hash: "1"}}})
+	require.NotEqual(t, a["sha256"], b["sha256"])
+	require.Equal(t, a, commitDigest(&pfs.CommitInfo{Trees: []*pfs.Object{{Hash: "1"}, {Hash: "2"}}}))
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"path"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
+	txnenv "github.com/pachyderm/pachyderm/src/server/pkg/transactionenv"
+	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
+
+	globlib "github.com/pachyderm/ohmyglob"
+)
+
+// deleteFiles deletes every file in 'commit' that matches one of 'paths',
+// which may be glob patterns. If 'commit' is open, the deletes are written
+// in a single etcd transaction, so readers of the commit see all of them or
+// none of them. If it's a branch whose HEAD is finished, one new commit is
+// made that deletes all of them.
+func (d *driver) deleteFiles(pachClient *client.APIClient, commit *pfs.Commit, paths []string) error {
+	if commit == nil {
+		return errors.New("commit cannot be nil")
+	}
+	if commit.Repo == nil {
+		return errors.New("commit repo cannot be nil")
+	}
+	if len(paths) == 0 {
+		return errors.New("must specify at least one path to delete")
+	}
+	if err := d.checkIsAuthorized(pachClient, commit.Repo, auth.Scope_WRITER); err != nil {
+		return err
+	}
+	for _, p := range paths {
+		if err := checkFilePath(p); err != nil {
+			return err
+		}
+		if hashtree.IsGlob(p) {
+			// Catch malformed patterns now, rather than when the commit is
+			// finished
+			if _, err := globlib.Compile(path.Clean("/"+p), '/'); err != nil {
+				return errors.Wrapf(err, "invalid glob pattern %q", p)
+			}
+		}
+	}
+	commitInfo, err := d.inspectCommit(pachClient, commit, pfs.CommitState_STARTED)
+	if err != nil {
+		return err
+	}
+	records := make([]*pfs.PutFileRecords, len(paths))
+	for i := range paths {
+		records[i] = &pfs.PutFileRecords{Tombstone: true}
+	}
+	if commitInfo.Finished != nil {
+		if uuid.IsUUIDWithoutDashes(commit.ID) {
+			return pfsserver.ErrCommitFinished{commit}
+		}
+		return d.txnEnv.WithWriteContext(pachClient.Ctx(), func(txnCtx *txnenv.TransactionContext) error {
+			_, err := d.makeCommit(txnCtx, "", client.NewCommit(commit.Repo.Name, ""), commit.ID, nil, nil, nil, nil, nil, paths, records, "", time.Time{}, time.Time{}, 0)
+			return err
+		})
+	}
+
+	commitPrefix := d.scratchCommitPrefix(commitInfo.Commit)
+	_, err = col.NewSTM(pachClient.Ctx(), d.etcdClient, func(stm col.STM) error {
+		if err := d.checkOpen(stm, commitInfo.Commit); err != nil {
+			return err
+		}
+		for i, p := range paths {
+			prefix, err := d.scratchFilePrefix(&pfs.File{Commit: commitInfo.Commit, Path: p})
+			if err != nil {
+				return err
+			}
+			recordsCol := d.putFileRecords.ReadWrite(stm)
+			staged, err := d.isStaged(stm, commitPrefix, prefix)
+			if err != nil {
+				return err
+			}
+			if staged {
+				recordsCol = d.stagedPutFileRecords.ReadWrite(stm)
+			}
+			// A delete replaces any earlier writes to the same path
+			if err := recordsCol.Put(prefix, records[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// replaceDirectory starts replacing the directory 'file' in an open commit.
+// The directory's contents don't change until the commit is finished: files
+// that are subsequently put in the directory are staged, and when the commit
+// is finished the directory is deleted and the staged files are applied, so
+// readers never see a partially-replaced directory. Replacing a directory
+// again discards the files that were staged by the earlier replacement.
+func (d *driver) replaceDirectory(pachClient *client.APIClient, file *pfs.File) error {
+	if file == nil {
+		return errors.New("file cannot be nil")
+	}
+	if file.Commit == nil {
+		return errors.New("file commit cannot be nil")
+	}
+	if file.Commit.Repo == nil {
+		return errors.New("file commit repo cannot be nil")
+	}
+	if err := d.checkIsAuthorized(pachClient, file.Commit.Repo, auth.Scope_WRITER); err != nil {
+		return err
+	}
+	if err := checkFilePath(file.Path); err != nil {
+		return err
+	}
+	if hashtree.IsGlob(file.Path) {
+		return errors.Errorf("cannot replace %q, as it's a glob pattern", file.Path)
+	}
+	commitInfo, err := d.inspectCommit(pachClient, file.Commit, pfs.CommitState_STARTED)
+	if err != nil {
+		return err
+	}
+	if commitInfo.Finished != nil {
+		return pfsserver.ErrCommitFinished{commitInfo.Commit}
+	}
+	prefix, err := d.scratchFilePrefix(&pfs.File{Commit: commitInfo.Commit, Path: file.Path})
+	if err != nil {
+		return err
+	}
+	_, err = col.NewSTM(pachClient.Ctx(), d.etcdClient, func(stm col.STM) error {
+		if err := d.checkOpen(stm, commitInfo.Commit); err != nil {
+			return err
+		}
+		return d.stagedPutFileRecords.ReadWrite(stm).Put(prefix, &pfs.PutFileRecords{Tombstone: true})
+	})
+	return err
+}
+
+// checkOpen returns an error if 'commit' isn't open
+func (d *driver) checkOpen(stm col.STM, commit *pfs.Commit) error {
+	var openCommit pfs.Commit
+	if err := d.openCommits.ReadWrite(stm).Get(commit.ID, &openCommit); err != nil {
+		return err
+	}
+	// Make sure the unmarshalled value exists (and matches the current ID)
+	// to denote that the commit is indeed open
+	if openCommit.ID != commit.ID {
+		return errors.Errorf("commit %v is not open", commit.ID)
+	}
+	return nil
+}
+
+// isStaged returns true if writes to 'prefix' (the scratch prefix of a path
+// in the open commit whose scratch prefix is 'commitPrefix') must be staged,
+// because the path is in a directory that's being replaced.
+func (d *driver) isStaged(stm col.STM, commitPrefix string, prefix string) (bool, error) {
+	stagedCol := d.stagedPutFileRecords.ReadWrite(stm)
+	for p := prefix; ; p = path.Dir(p) {
+		records := &pfs.PutFileRecords{}
+		if err := stagedCol.Get(p, records); err != nil && !col.IsErrNotFound(err) {
+			return false, err
+		} else if err == nil && records.Tombstone {
+			return true, nil
+		}
+		if len(p) <= len(commitPrefix) {
+			return false, nil
+		}
+	}
+}
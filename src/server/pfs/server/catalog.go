@@ -0,0 +1,193 @@
+package server
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+)
+
+// validateRepoMetadata returns an error if 'metadata' is invalid, and
+// otherwise sorts and dedupes its tags.
+func validateRepoMetadata(metadata *pfs.RepoMetadata) error {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, tag := range metadata.Tags {
+		if tag == "" || strings.IndexFunc(tag, unicode.IsSpace) >= 0 || strings.Contains(tag, ",") {
+			return errors.Errorf("invalid tag %q: tags must be non-empty, and can't contain whitespace or commas", tag)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	metadata.Tags = tags
+	if metadata.SchemaUrl != "" {
+		u, err := url.Parse(metadata.SchemaUrl)
+		if err != nil {
+			return errors.Wrapf(err, "invalid schema_url")
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return errors.Errorf("invalid schema_url %q: it must be an absolute URL", metadata.SchemaUrl)
+		}
+	}
+	return nil
+}
+
+// updateRepoMetadata replaces the catalog metadata of 'repo' with 'metadata'
+// (removing it if 'metadata' is nil), and its description with
+// 'description', if it's set. Only the repo's owners can change them.
+func (d *driver) updateRepoMetadata(pachClient *client.APIClient, repo *pfs.Repo, metadata *pfs.RepoMetadata, description string) error {
+	if repo == nil {
+		return errors.New("repo cannot be nil")
+	}
+	if metadata != nil {
+		if err := validateRepoMetadata(metadata); err != nil {
+			return err
+		}
+	}
+	if err := d.checkIsAuthorized(pachClient, repo, auth.Scope_OWNER); err != nil {
+		return err
+	}
+	_, err := col.NewSTM(pachClient.Ctx(), d.etcdClient, func(stm col.STM) error {
+		repoInfo := &pfs.RepoInfo{}
+		return d.repos.ReadWrite(stm).Update(repo.Name, repoInfo, func() error {
+			repoInfo.Metadata = metadata
+			if description != "" {
+				repoInfo.Description = description
+			}
+			return nil
+		})
+	})
+	if col.IsErrNotFound(err) {
+		return pfsserver.ErrRepoNotFound{Repo: repo}
+	}
+	return err
+}
+
+// repoRelevance returns how relevant 'repoInfo' is to the words of 'query',
+// or 0 if it doesn't match every word. Matches in a repo's name count the
+// most, and then matches in its tags.
+func repoRelevance(repoInfo *pfs.RepoInfo, query string) int {
+	metadata := repoInfo.Metadata
+	if metadata == nil {
+		metadata = &pfs.RepoMetadata{}
+	}
+	name := strings.ToLower(repoInfo.Repo.Name)
+	tags := strings.ToLower(strings.Join(metadata.Tags, " "))
+	text := strings.ToLower(strings.Join([]string{
+		repoInfo.Description, metadata.Owner, metadata.SchemaUrl, metadata.Sla,
+	}, " "))
+	relevance := 1
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		var wordRelevance int
+		if strings.Contains(name, word) {
+			wordRelevance += 4
+		}
+		if strings.Contains(tags, word) {
+			wordRelevance += 2
+		}
+		if strings.Contains(text, word) {
+			wordRelevance++
+		}
+		if wordRelevance == 0 {
+			return 0
+		}
+		relevance += wordRelevance
+	}
+	return relevance
+}
+
+// repoHasTags returns true if 'repoInfo' has all of 'tags'.
+func repoHasTags(repoInfo *pfs.RepoInfo, tags []string) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	if repoInfo.Metadata == nil {
+		return false
+	}
+	has := make(map[string]bool)
+	for _, tag := range repoInfo.Metadata.Tags {
+		has[tag] = true
+	}
+	for _, tag := range tags {
+		if !has[tag] {
+			return false
+		}
+	}
+	return true
+}
+
+// facetCounts returns the values in 'counts', most common first.
+func facetCounts(counts map[string]int64) []*pfs.FacetCount {
+	var result []*pfs.FacetCount
+	for value, count := range counts {
+		result = append(result, &pfs.FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result
+}
+
+// discoverRepos returns the repos in 'repoInfos' that match 'request', most
+// relevant first, and the counts of their owners and tags.
+func discoverRepos(repoInfos []*pfs.RepoInfo, request *pfs.DiscoverReposRequest) *pfs.DiscoverReposResponse {
+	relevance := make(map[string]int)
+	var matches []*pfs.RepoInfo
+	owners := make(map[string]int64)
+	tags := make(map[string]int64)
+	for _, repoInfo := range repoInfos {
+		if request.Owner != "" && repoInfo.Metadata.GetOwner() != request.Owner {
+			continue
+		}
+		if !repoHasTags(repoInfo, request.Tags) {
+			continue
+		}
+		r := repoRelevance(repoInfo, request.Query)
+		if r == 0 {
+			continue
+		}
+		relevance[repoInfo.Repo.Name] = r
+		matches = append(matches, repoInfo)
+		if owner := repoInfo.Metadata.GetOwner(); owner != "" {
+			owners[owner]++
+		}
+		for _, tag := range repoInfo.Metadata.GetTags() {
+			tags[tag]++
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		ri, rj := relevance[matches[i].Repo.Name], relevance[matches[j].Repo.Name]
+		if ri != rj {
+			return ri > rj
+		}
+		return matches[i].Repo.Name < matches[j].Repo.Name
+	})
+	return &pfs.DiscoverReposResponse{
+		RepoInfo: matches,
+		Owners:   facetCounts(owners),
+		Tags:     facetCounts(tags),
+	}
+}
+
+// discoverRepos searches the repos visible to the caller (as with ListRepo)
+// for the ones that match 'request'.
+func (d *driver) discoverRepos(pachClient *client.APIClient, request *pfs.DiscoverReposRequest) (*pfs.DiscoverReposResponse, error) {
+	repoInfos, err := d.listRepo(pachClient, true)
+	if err != nil {
+		return nil, err
+	}
+	return discoverRepos(repoInfos.RepoInfo, request), nil
+}
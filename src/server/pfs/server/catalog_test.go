@@ -0,0 +1,74 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestValidateRepoMetadata(t *testing.T) {
+	metadata := &pfs.RepoMetadata{
+		Owner:     "vision-team",
+		Tags:      []string{"raw", "imagery", "raw"},
+		SchemaUrl: "https://wiki.example.com/images",
+	}
+	require.NoError(t, validateRepoMetadata(metadata))
+	require.Equal(t, []string{"imagery", "raw"}, metadata.Tags)
+
+	require.YesError(t, validateRepoMetadata(&pfs.RepoMetadata{Tags: []string{""}}))
+	require.YesError(t, validateRepoMetadata(&pfs.RepoMetadata{Tags: []string{"two words"}}))
+	require.YesError(t, validateRepoMetadata(&pfs.RepoMetadata{Tags: []string{"a,b"}}))
+	require.YesError(t, validateRepoMetadata(&pfs.RepoMetadata{SchemaUrl: "wiki/images"}))
+}
+
+func TestDiscoverRepos(t *testing.T) {
+	repoInfos := []*pfs.RepoInfo{{
+		Repo:        client.NewRepo("images"),
+		Description: "Raw camera images.",
+		Metadata:    &pfs.RepoMetadata{Owner: "vision", Tags: []string{"imagery", "raw"}},
+	}, {
+		Repo:        client.NewRepo("thumbnails"),
+		Description: "Thumbnails of the camera images.",
+		Metadata:    &pfs.RepoMetadata{Owner: "vision", Tags: []string{"imagery"}},
+	}, {
+		Repo:        client.NewRepo("clicks"),
+		Description: "Raw click events.",
+		Metadata:    &pfs.RepoMetadata{Owner: "web", Tags: []string{"events", "raw"}, Sla: "updated hourly"},
+	}, {
+		Repo: client.NewRepo("scratch"),
+	}}
+	repoNames := func(response *pfs.DiscoverReposResponse) []string {
+		var result []string
+		for _, repoInfo := range response.RepoInfo {
+			result = append(result, repoInfo.Repo.Name)
+		}
+		return result
+	}
+
+	// Repos whose names match come first, and every word must match
+	response := discoverRepos(repoInfos, &pfs.DiscoverReposRequest{Query: "Camera IMAGES"})
+	require.Equal(t, []string{"images", "thumbnails"}, repoNames(response))
+	require.Equal(t, []*pfs.FacetCount{{Value: "vision", Count: 2}}, response.Owners)
+	require.Equal(t, []*pfs.FacetCount{{Value: "imagery", Count: 2}, {Value: "raw", Count: 1}}, response.Tags)
+
+	// Matches in tags count more than matches in descriptions
+	response = discoverRepos(repoInfos, &pfs.DiscoverReposRequest{Query: "imagery"})
+	require.Equal(t, []string{"images", "thumbnails"}, repoNames(response))
+	response = discoverRepos(repoInfos, &pfs.DiscoverReposRequest{Query: "hourly"})
+	require.Equal(t, []string{"clicks"}, repoNames(response))
+
+	// Without a query, every repo matches, sorted by name
+	response = discoverRepos(repoInfos, &pfs.DiscoverReposRequest{})
+	require.Equal(t, []string{"clicks", "images", "scratch", "thumbnails"}, repoNames(response))
+
+	// Filters
+	response = discoverRepos(repoInfos, &pfs.DiscoverReposRequest{Tags: []string{"raw"}})
+	require.Equal(t, []string{"clicks", "images"}, repoNames(response))
+	response = discoverRepos(repoInfos, &pfs.DiscoverReposRequest{Owner: "vision", Tags: []string{"raw"}})
+	require.Equal(t, []string{"images"}, repoNames(response))
+	response = discoverRepos(repoInfos, &pfs.DiscoverReposRequest{Query: "nothing"})
+	require.Equal(t, 0, len(response.RepoInfo))
+	require.Equal(t, 0, len(response.Owners))
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+)
+
+// changeFeedCursor returns the cursor of the i'th event (counting from 0) of
+// 'commit's changes. A cursor identifies the position of an event in the feed:
+// resuming from it skips the first i+1 events of 'commit' (and every commit
+// before it).
+func changeFeedCursor(commit *pfs.Commit, i int) string {
+	return fmt.Sprintf("%s:%d", commit.ID, i+1)
+}
+
+// parseChangeFeedCursor returns the commit ID and event count encoded in a
+// cursor created by changeFeedCursor.
+func parseChangeFeedCursor(cursor string) (string, int, error) {
+	i := strings.LastIndex(cursor, ":")
+	if i <= 0 {
+		return "", 0, errors.Errorf("invalid change feed cursor %q", cursor)
+	}
+	n, err := strconv.Atoi(cursor[i+1:])
+	if err != nil || n < 1 {
+		return "", 0, errors.Errorf("invalid change feed cursor %q", cursor)
+	}
+	return cursor[:i], n, nil
+}
+
+// changeFeedEvents classifies the results of diffing a commit against its
+// parent ('newFiles' and 'oldFiles') as file-level events. Events are sorted
+// by path, so that a commit's events (and therefore its cursors) are always the
+// same, and are followed by a COMMIT_FINISHED event.
+func changeFeedEvents(commit *pfs.Commit, newFiles, oldFiles []*pfs.FileInfo) []*pfs.ChangeFeedEvent {
+	inNew := make(map[string]bool)
+	for _, fi := range newFiles {
+		inNew[fi.File.Path] = true
+	}
+	inOld := make(map[string]bool)
+	var events []*pfs.ChangeFeedEvent
+	for _, fi := range oldFiles {
+		inOld[fi.File.Path] = true
+		if fi.FileType != pfs.FileType_FILE || inNew[fi.File.Path] {
+			continue
+		}
+		events = append(events, &pfs.ChangeFeedEvent{Type: pfs.ChangeType_DELETE, Commit: commit, File: fi})
+	}
+	for _, fi := range newFiles {
+		if fi.FileType != pfs.FileType_FILE {
+			continue
+		}
+		changeType := pfs.ChangeType_PUT
+		if inOld[fi.File.Path] {
+			changeType = pfs.ChangeType_OVERWRITE
+		}
+		events = append(events, &pfs.ChangeFeedEvent{Type: changeType, Commit: commit, File: fi})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].File.File.Path < events[j].File.File.Path
+	})
+	events = append(events, &pfs.ChangeFeedEvent{Type: pfs.ChangeType_COMMIT_FINISHED, Commit: commit})
+	for i, event := range events {
+		event.Cursor = changeFeedCursor(commit, i)
+	}
+	return events
+}
+
+// changeFeed calls 'f' with the file-level changes made by each commit to
+// 'branch', in the order that the commits were created. Commits are only
+// reported once they're finished, and changeFeed keeps waiting for new commits
+// until 'f' returns an error or the client's context is cancelled. If 'cursor'
+// is set, the feed resumes after the event that 'cursor' came from.
+func (d *driver) changeFeed(pachClient *client.APIClient, branch *pfs.Branch, cursor string, f func(*pfs.ChangeFeedEvent) error) error {
+	// Validate arguments
+	if branch == nil || branch.Repo == nil {
+		return errors.New("branch cannot be nil")
+	}
+	if branch.Name == "" {
+		return errors.New("branch name cannot be empty")
+	}
+	if err := d.checkIsAuthorized(pachClient, branch.Repo, auth.Scope_READER); err != nil {
+		return err
+	}
+	var cursorCommit string
+	var skip int
+	if cursor != "" {
+		var err error
+		cursorCommit, skip, err = parseChangeFeedCursor(cursor)
+		if err != nil {
+			return err
+		}
+		// If the cursor's commit is gone, the feed would never find it
+		commitInfo, err := d.inspectCommit(pachClient, client.NewCommit(branch.Repo.Name, cursorCommit), pfs.CommitState_STARTED)
+		if err != nil {
+			return errors.Wrapf(err, "could not resume change feed from cursor %q", cursor)
+		}
+		if commitInfo.Branch == nil || commitInfo.Branch.Name != branch.Name {
+			return errors.Errorf("cursor %q is not from branch %s", cursor, branch.Name)
+		}
+	}
+	return d.subscribeCommit(pachClient, branch.Repo, branch.Name, nil, nil, pfs.CommitState_FINISHED, func(commitInfo *pfs.CommitInfo) error {
+		start := 0
+		if cursorCommit != "" {
+			if commitInfo.Commit.ID != cursorCommit {
+				return nil // sent before the cursor
+			}
+			start = skip
+			cursorCommit = ""
+		}
+		newFiles, oldFiles, err := d.diffFile(pachClient, client.NewFile(branch.Repo.Name, commitInfo.Commit.ID, "/"), nil, false)
+		if err != nil {
+			return err
+		}
+		events := changeFeedEvents(commitInfo.Commit, newFiles, oldFiles)
+		for i := start; i < len(events); i++ {
+			if err := f(events[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
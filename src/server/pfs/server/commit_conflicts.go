@@ -0,0 +1,92 @@
+package server
+
+import (
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	txnenv "github.com/pachyderm/pachyderm/src/server/pkg/transactionenv"
+)
+
+// checkBranchHead returns an ErrBranchHeadMismatch if 'head', the head of
+// 'branch', isn't 'expectedHead', which may use ancestry syntax. An expected
+// head with no ID means that the branch is expected to have no head.
+func (d *driver) checkBranchHead(txnCtx *txnenv.TransactionContext, branch *pfs.Branch, head *pfs.Commit, expectedHead *pfs.Commit) error {
+	if expectedHead.ID == "" {
+		if head != nil {
+			return pfsserver.ErrBranchHeadMismatch{Branch: branch, Actual: head}
+		}
+		return nil
+	}
+	expected := proto.Clone(expectedHead).(*pfs.Commit)
+	if expected.Repo == nil {
+		expected.Repo = branch.Repo
+	}
+	if expected.Repo.Name != branch.Repo.Name {
+		return errors.Errorf("expected head of branch %s@%s must be in the same repo", branch.Repo.Name, branch.Name)
+	}
+	expectedInfo, err := d.resolveCommit(txnCtx.Stm, expected)
+	if err != nil {
+		return errors.Wrapf(err, "unable to inspect expected head %s@%s", expected.Repo.Name, expectedHead.ID)
+	}
+	if head == nil || head.ID != expectedInfo.Commit.ID {
+		return pfsserver.ErrBranchHeadMismatch{
+			Branch:   branch,
+			Expected: expectedInfo.Commit,
+			Actual:   head,
+		}
+	}
+	return nil
+}
+
+// checkStartCommitHead returns an ErrBranchHeadMismatch if a commit that's
+// started on 'branch' of the repo of 'parent' with 'expectedHead' (see
+// StartCommitRequest.ExpectedHead) can't be started. Branches that don't exist
+// yet have no head.
+func (d *driver) checkStartCommitHead(txnCtx *txnenv.TransactionContext, parent *pfs.Commit, branch string, expectedHead *pfs.Commit) error {
+	if parent == nil || parent.Repo == nil {
+		return errors.New("parent cannot be nil")
+	}
+	if branch == "" {
+		return errors.New("a commit can only be started with an expected head on a branch")
+	}
+	branchInfo := &pfs.BranchInfo{}
+	if err := d.branches(parent.Repo.Name).ReadWrite(txnCtx.Stm).Get(branch, branchInfo); err != nil {
+		if !col.IsErrNotFound(err) {
+			return err
+		}
+	}
+	return d.checkBranchHead(txnCtx, client.NewBranch(parent.Repo.Name, branch), branchInfo.Head, expectedHead)
+}
+
+// checkCommitIsHead returns an ErrCommitConflict if 'commit' is no longer the
+// head of the branch that it was started on (see
+// FinishCommitRequest.RequireHead), because another commit was started on the
+// branch after it or the branch was moved.
+func (d *driver) checkCommitIsHead(txnCtx *txnenv.TransactionContext, commit *pfs.Commit) error {
+	commitInfo, err := d.resolveCommit(txnCtx.Stm, commit)
+	if err != nil {
+		return err
+	}
+	if commitInfo.Branch == nil {
+		return errors.Errorf("commit %s@%s wasn't started on a branch, so it can't be required to be its head",
+			commitInfo.Commit.Repo.Name, commitInfo.Commit.ID)
+	}
+	branchInfo := &pfs.BranchInfo{}
+	if err := d.branches(commitInfo.Branch.Repo.Name).ReadWrite(txnCtx.Stm).Get(commitInfo.Branch.Name, branchInfo); err != nil {
+		if !col.IsErrNotFound(err) {
+			return err
+		}
+	}
+	if branchInfo.Head == nil || branchInfo.Head.ID != commitInfo.Commit.ID {
+		return pfsserver.ErrCommitConflict{
+			Commit: commitInfo.Commit,
+			Branch: commitInfo.Branch,
+			Head:   branchInfo.Head,
+		}
+	}
+	return nil
+}
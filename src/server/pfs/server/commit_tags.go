@@ -0,0 +1,215 @@
+package server
+
+import (
+	"path"
+	"regexp"
+	"sort"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsdb"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+)
+
+// Unlike branch names, tag names may contain '.', so that they can be
+// version numbers (e.g. "v1.2-training-set")
+var validCommitTagName = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]*$`)
+
+func validateCommitTag(tag *pfs.CommitTag) error {
+	if tag == nil {
+		return errors.New("tag cannot be nil")
+	}
+	if tag.Repo == nil {
+		return errors.New("tag repo cannot be nil")
+	}
+	if !validCommitTagName.MatchString(tag.Name) {
+		return errors.Errorf("tag name (%v) invalid: only alphanumeric characters, underscores, dashes and dots are allowed", tag.Name)
+	}
+	return nil
+}
+
+// resolveCommitTag sets 'commit.ID' to the ID of the tagged commit and
+// returns true, if 'commit.ID' is the name of one of its repo's tags.
+func (d *driver) resolveCommitTag(stm col.STM, commit *pfs.Commit) (bool, error) {
+	tagInfo := &pfs.CommitTagInfo{}
+	if err := d.commitTags(commit.Repo.Name).ReadWrite(stm).Get(commit.ID, tagInfo); err != nil {
+		if col.IsErrNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	commit.ID = tagInfo.Commit.ID
+	return true, nil
+}
+
+// tagCommit creates 'tag', which refers to 'commit'. Tags can't be moved, so
+// 'commit' must be finished, and there mustn't be a tag (or branch) with the
+// same name already.
+func (d *driver) tagCommit(pachClient *client.APIClient, tag *pfs.CommitTag, commit *pfs.Commit, description string) error {
+	if err := validateCommitTag(tag); err != nil {
+		return err
+	}
+	if commit == nil {
+		return errors.New("commit cannot be nil")
+	}
+	if commit.Repo != nil && commit.Repo.Name != tag.Repo.Name {
+		return errors.Errorf("cannot tag a commit in repo %q with a tag in repo %q", commit.Repo.Name, tag.Repo.Name)
+	}
+	if err := d.checkIsAuthorized(pachClient, tag.Repo, auth.Scope_WRITER); err != nil {
+		return err
+	}
+	_, err := col.NewSTM(pachClient.Ctx(), d.etcdClient, func(stm col.STM) error {
+		// Branches and tags share a namespace, so that a name always refers to
+		// the same thing
+		if err := d.branches(tag.Repo.Name).ReadWrite(stm).Get(tag.Name, &pfs.BranchInfo{}); err == nil {
+			return errors.Errorf("cannot create commit tag %q because a branch with that name exists in repo %q", tag.Name, tag.Repo.Name)
+		} else if !col.IsErrNotFound(err) {
+			return err
+		}
+		commitInfo, err := d.resolveCommit(stm, client.NewCommit(tag.Repo.Name, commit.ID))
+		if err != nil {
+			return err
+		}
+		if commitInfo.Finished == nil {
+			return errors.Errorf("cannot tag commit %s/%s because it isn't finished", tag.Repo.Name, commitInfo.Commit.ID)
+		}
+		tagInfo := &pfs.CommitTagInfo{
+			Tag:         tag,
+			Commit:      commitInfo.Commit,
+			Description: description,
+			Created:     types.TimestampNow(),
+		}
+		if err := d.commitTags(tag.Repo.Name).ReadWrite(stm).Create(tag.Name, tagInfo); err != nil {
+			if col.IsErrExists(err) {
+				return errors.Errorf("commit tag %q already exists in repo %q, and tags can't be moved", tag.Name, tag.Repo.Name)
+			}
+			return err
+		}
+		taggedInfo := &pfs.CommitInfo{}
+		return d.commits(tag.Repo.Name).ReadWrite(stm).Update(commitInfo.Commit.ID, taggedInfo, func() error {
+			taggedInfo.Tags = append(taggedInfo.Tags, tag.Name)
+			return nil
+		})
+	})
+	return err
+}
+
+func (d *driver) inspectCommitTag(pachClient *client.APIClient, tag *pfs.CommitTag) (*pfs.CommitTagInfo, error) {
+	if err := validateCommitTag(tag); err != nil {
+		return nil, err
+	}
+	if err := d.checkIsAuthorized(pachClient, tag.Repo, auth.Scope_READER); err != nil {
+		return nil, err
+	}
+	tagInfo := &pfs.CommitTagInfo{}
+	if err := d.commitTags(tag.Repo.Name).ReadOnly(pachClient.Ctx()).Get(tag.Name, tagInfo); err != nil {
+		if col.IsErrNotFound(err) {
+			return nil, errors.Errorf("commit tag %q not found in repo %q", tag.Name, tag.Repo.Name)
+		}
+		return nil, err
+	}
+	return tagInfo, nil
+}
+
+func (d *driver) listCommitTag(pachClient *client.APIClient, repo *pfs.Repo) ([]*pfs.CommitTagInfo, error) {
+	if repo == nil {
+		return nil, errors.New("repo cannot be nil")
+	}
+	if err := d.checkIsAuthorized(pachClient, repo, auth.Scope_READER); err != nil {
+		return nil, err
+	}
+	// Make sure that the repo exists, so that listing the tags of a
+	// nonexistent repo is an error
+	if err := d.repos.ReadOnly(pachClient.Ctx()).Get(repo.Name, &pfs.RepoInfo{}); err != nil {
+		return nil, err
+	}
+	var result []*pfs.CommitTagInfo
+	tagInfo := &pfs.CommitTagInfo{}
+	if err := d.commitTags(repo.Name).ReadOnly(pachClient.Ctx()).List(tagInfo, col.DefaultOptions, func(string) error {
+		result = append(result, proto.Clone(tagInfo).(*pfs.CommitTagInfo))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// deleteCommitTag deletes 'tag', unless a pipeline reads its commit.
+func (d *driver) deleteCommitTag(pachClient *client.APIClient, tag *pfs.CommitTag) error {
+	if err := validateCommitTag(tag); err != nil {
+		return err
+	}
+	if err := d.checkIsAuthorized(pachClient, tag.Repo, auth.Scope_WRITER); err != nil {
+		return err
+	}
+	pipelines, err := d.pipelinesUsingCommitTag(pachClient, tag)
+	if err != nil {
+		return err
+	}
+	if len(pipelines) > 0 {
+		return errors.Errorf("cannot delete commit tag %q because it's used by pipeline(s) %v", tag.Name, pipelines)
+	}
+	_, err = col.NewSTM(pachClient.Ctx(), d.etcdClient, func(stm col.STM) error {
+		tags := d.commitTags(tag.Repo.Name).ReadWrite(stm)
+		tagInfo := &pfs.CommitTagInfo{}
+		if err := tags.Get(tag.Name, tagInfo); err != nil {
+			if col.IsErrNotFound(err) {
+				return errors.Errorf("commit tag %q not found in repo %q", tag.Name, tag.Repo.Name)
+			}
+			return err
+		}
+		if err := tags.Delete(tag.Name); err != nil {
+			return err
+		}
+		commitInfo := &pfs.CommitInfo{}
+		if err := d.commits(tag.Repo.Name).ReadWrite(stm).Update(tagInfo.Commit.ID, commitInfo, func() error {
+			var remaining []string
+			for _, name := range commitInfo.Tags {
+				if name != tag.Name {
+					remaining = append(remaining, name)
+				}
+			}
+			commitInfo.Tags = remaining
+			return nil
+		}); err != nil && !col.IsErrNotFound(err) {
+			return err
+		}
+		return nil
+	})
+	return err
+}
+
+// pipelinesUsingCommitTag returns the names of the pipelines whose specs have
+// an input that reads the commit that 'tag' refers to
+func (d *driver) pipelinesUsingCommitTag(pachClient *client.APIClient, tag *pfs.CommitTag) ([]string, error) {
+	var result []string
+	pipelines := ppsdb.Pipelines(d.etcdClient, path.Join(d.env.EtcdPrefix, d.env.PPSEtcdPrefix))
+	ptr := &pps.EtcdPipelineInfo{}
+	if err := pipelines.ReadOnly(pachClient.Ctx()).List(ptr, col.DefaultOptions, func(name string) error {
+		pipelineInfo, err := ppsutil.GetPipelineInfo(pachClient, name, ptr)
+		if err != nil {
+			return err
+		}
+		var uses bool
+		pps.VisitInput(pipelineInfo.Input, func(input *pps.Input) {
+			if input.Pfs != nil && input.Pfs.Repo == tag.Repo.Name && input.Pfs.Commit == tag.Name {
+				uses = true
+			}
+		})
+		if uses {
+			result = append(result, name)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Strings(result)
+	return result, nil
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	txnenv "github.com/pachyderm/pachyderm/src/server/pkg/transactionenv"
+)
+
+// setBranchDeferred makes the existing branch 'branch' deferred, or not (see
+// BranchInfo.Deferred). When a branch stops being deferred, its head is
+// processed like a new head: it's propagated to the branch's subvenance, and
+// fires the triggers of branches that trigger on it.
+func (d *driver) setBranchDeferred(txnCtx *txnenv.TransactionContext, branch *pfs.Branch, deferred bool) error {
+	if branch == nil || branch.Repo == nil {
+		return errors.New("branch cannot be nil")
+	}
+	if err := d.checkIsAuthorizedInTransaction(txnCtx, branch.Repo, auth.Scope_WRITER); err != nil {
+		return err
+	}
+	branchInfo := &pfs.BranchInfo{}
+	var wasDeferred bool
+	if err := d.branches(branch.Repo.Name).ReadWrite(txnCtx.Stm).Update(branch.Name, branchInfo, func() error {
+		if deferred && len(branchInfo.DirectProvenance) > 0 {
+			return errors.Errorf("branch %s@%s has provenance, so its commits are made by pipelines and it can't be deferred", branch.Repo.Name, branch.Name)
+		}
+		wasDeferred = branchInfo.Deferred
+		branchInfo.Deferred = deferred
+		return nil
+	}); err != nil {
+		return err
+	}
+	if deferred || !wasDeferred {
+		return nil
+	}
+	var triggeredBranches []*pfs.Branch
+	if branchInfo.Head != nil {
+		headInfo, err := d.resolveCommit(txnCtx.Stm, branchInfo.Head)
+		if err != nil {
+			return err
+		}
+		if headInfo.Finished != nil {
+			if triggeredBranches, err = d.triggerCommit(txnCtx, headInfo.Commit); err != nil {
+				return err
+			}
+		}
+	}
+	for _, b := range append(triggeredBranches, branchInfo.Branch) {
+		if err := txnCtx.PropagateCommit(b, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
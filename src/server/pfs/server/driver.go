@@ -27,9 +27,12 @@ import (
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
 	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/residency"
+	"github.com/pachyderm/pachyderm/src/client/pkg/tenant"
 	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ancestry"
 	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/deleteall"
 	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
 	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
 	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
@@ -37,6 +40,7 @@ import (
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
 	"github.com/pachyderm/pachyderm/src/server/pkg/serviceenv"
 	"github.com/pachyderm/pachyderm/src/server/pkg/sql"
+	"github.com/pachyderm/pachyderm/src/server/pkg/tenantutil"
 	txnenv "github.com/pachyderm/pachyderm/src/server/pkg/transactionenv"
 	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
 	"github.com/pachyderm/pachyderm/src/server/pkg/watch"
@@ -95,7 +99,26 @@ type driver struct {
 	putFileRecords col.Collection
 	commits        collectionFactory
 	branches       collectionFactory
+	commitTags     collectionFactory
+	attestations   collectionFactory
+	attestationKey col.Collection
 	openCommits    col.Collection
+	trash          col.Collection
+	finishCommits  col.Collection
+	// the number of repos that each tenant owns
+	tenantRepos col.Collection
+
+	// the put-file records of directories that are being replaced in open
+	// commits, which are only applied when the commits are finished
+	stagedPutFileRecords col.Collection
+
+	// how long deleted repos and commits are kept in 'trash' (0 if they
+	// aren't kept)
+	trashRetention time.Duration
+
+	// the number of goroutines that add a commit's files to its hashtree in
+	// FinishCommit
+	finishCommitConcurrency int
 
 	// a cache for hashtrees
 	treeCache *hashtree.Cache
@@ -124,6 +147,18 @@ func newDriver(
 	if treeCache == nil {
 		return nil, errors.Errorf("cannot initialize driver with nil treeCache")
 	}
+	var trashRetention time.Duration
+	if env.PFSTrashRetention != "" {
+		var err error
+		trashRetention, err = time.ParseDuration(env.PFSTrashRetention)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid PFS_TRASH_RETENTION %q", env.PFSTrashRetention)
+		}
+	}
+	finishCommitConcurrency := env.PFSFinishCommitConcurrency
+	if finishCommitConcurrency <= 0 {
+		finishCommitConcurrency = hashtree.DefaultMergeConcurrency
+	}
 	// Initialize driver
 	etcdClient := env.GetEtcdClient()
 	d := &driver{
@@ -139,9 +174,24 @@ func newDriver(
 		branches: func(repo string) col.Collection {
 			return pfsdb.Branches(etcdClient, etcdPrefix, repo)
 		},
-		openCommits: pfsdb.OpenCommits(etcdClient, etcdPrefix),
-		treeCache:   treeCache,
-		storageRoot: storageRoot,
+		commitTags: func(repo string) col.Collection {
+			return pfsdb.CommitTags(etcdClient, etcdPrefix, repo)
+		},
+		attestations: func(repo string) col.Collection {
+			return pfsdb.Attestations(etcdClient, etcdPrefix, repo)
+		},
+		attestationKey: pfsdb.AttestationKey(etcdClient, etcdPrefix),
+		openCommits:    pfsdb.OpenCommits(etcdClient, etcdPrefix),
+		trash:          pfsdb.Trash(etcdClient, etcdPrefix),
+		finishCommits:  pfsdb.FinishCommits(etcdClient, etcdPrefix),
+		tenantRepos:    tenantutil.Counts(etcdClient, etcdPrefix, "repos"),
+		trashRetention: trashRetention,
+		treeCache:      treeCache,
+		storageRoot:    storageRoot,
+		// Records of the directories that are being replaced in open commits
+		stagedPutFileRecords: pfsdb.StagedPutFileRecords(etcdClient, etcdPrefix),
+		// Parallelism for applying writes when finishing commits
+		finishCommitConcurrency: finishCommitConcurrency,
 		// Allow up to a third of the requested memory to be used for memory intensive operations
 		memoryLimiter:    semaphore.NewWeighted(memoryRequest / 3),
 		putObjectLimiter: limit.New(env.StorageUploadConcurrencyLimit),
@@ -161,6 +211,11 @@ func newDriver(
 	}); err != nil && !col.IsErrExists(err) {
 		return nil, err
 	}
+	// Workers' sidecars (which have a spec commit) leave open commits to the
+	// main pachds
+	if env.PPSSpecCommitID == "" && !env.StorageV2 {
+		go d.abandonOpenCommits()
+	}
 	return d, nil
 }
 
@@ -203,7 +258,7 @@ func (d *driver) checkIsAuthorized(pachClient *client.APIClient, r *pfs.Repo, s
 	return nil
 }
 
-func (d *driver) createRepo(txnCtx *txnenv.TransactionContext, repo *pfs.Repo, description string, update bool) error {
+func (d *driver) createRepo(txnCtx *txnenv.TransactionContext, repo *pfs.Repo, description string, update bool, repoResidency string) error {
 	// Validate arguments
 	if repo == nil {
 		return errors.New("repo cannot be nil")
@@ -216,9 +271,24 @@ func (d *driver) createRepo(txnCtx *txnenv.TransactionContext, repo *pfs.Repo, d
 	if authIsActivated && err != nil {
 		return errors.Wrapf(grpcutil.ScrubGRPC(err), "error authenticating (must log in to create a repo)")
 	}
+	if authIsActivated && len(whoAmI.ReadOnlyRepos) > 0 {
+		return errors.Errorf("a token restricted to reading %v can't create repos", whoAmI.ReadOnlyRepos)
+	}
 	if err := ancestry.ValidateName(repo.Name); err != nil {
 		return err
 	}
+	var t string
+	if authIsActivated {
+		t = whoAmI.Tenant
+	}
+	if err := tenant.CheckName(t, repo.Name); err != nil {
+		return err
+	}
+	if repoResidency != "" {
+		if err := d.checkResidencyConfigured(repoResidency); err != nil {
+			return err
+		}
+	}
 
 	repos := d.repos.ReadWrite(txnCtx.Stm)
 
@@ -234,6 +304,9 @@ func (d *driver) createRepo(txnCtx *txnenv.TransactionContext, repo *pfs.Repo, d
 		if !update {
 			return pfsserver.ErrRepoExists{repo}
 		}
+		if repoResidency != "" && repoResidency != existingRepoInfo.Residency {
+			return errors.Errorf("the residency of repo %q can't be changed (it is %q)", repo.Name, existingRepoInfo.Residency)
+		}
 
 		if existingRepoInfo.Description == description {
 			// Don't overwrite the stored proto with an identical value. This
@@ -255,6 +328,9 @@ func (d *driver) createRepo(txnCtx *txnenv.TransactionContext, repo *pfs.Repo, d
 		return repos.Put(repo.Name, &existingRepoInfo)
 	} else {
 		// New repo case
+		if err := tenantutil.Reserve(d.tenantRepos.ReadWriteInt(txnCtx.Stm), t, "repos", d.env.TenantMaxRepos); err != nil {
+			return err
+		}
 		if authIsActivated {
 			// Create ACL for new repo. Make caller the sole owner. If the ACL already
 			// exists with a different owner, this will fail.
@@ -273,10 +349,65 @@ func (d *driver) createRepo(txnCtx *txnenv.TransactionContext, repo *pfs.Repo, d
 			Repo:        repo,
 			Created:     types.TimestampNow(),
 			Description: description,
+			Tenant:      t,
+			Residency:   repoResidency,
 		})
 	}
 }
 
+// checkResidencyConfigured returns an error if the residency 'r' has no
+// bucket configured, so that repos can't be created in it.
+func (d *driver) checkResidencyConfigured(r string) error {
+	buckets, err := residency.ParseURLs(d.env.StorageResidencyBuckets)
+	if err != nil {
+		return err
+	}
+	if _, ok := buckets[r]; !ok {
+		return errors.Errorf("residency %q has no bucket configured (configured residencies: %v)", r, residency.Names(buckets))
+	}
+	return nil
+}
+
+// repoResidency returns the residency of 'repo', or "" if it doesn't have one.
+func (d *driver) repoResidency(ctx context.Context, repo *pfs.Repo) (string, error) {
+	repoInfo := &pfs.RepoInfo{}
+	if err := d.repos.ReadOnly(ctx).Get(repo.Name, repoInfo); err != nil {
+		if col.IsErrNotFound(err) {
+			return "", pfsserver.ErrRepoNotFound{repo}
+		}
+		return "", err
+	}
+	return repoInfo.Residency, nil
+}
+
+// checkCopyResidency returns an error if data may not be copied from 'src' to
+// 'dst' because 'src' has a different residency.
+func (d *driver) checkCopyResidency(ctx context.Context, src, dst *pfs.Repo) error {
+	srcResidency, err := d.repoResidency(ctx, src)
+	if err != nil {
+		return err
+	}
+	dstResidency, err := d.repoResidency(ctx, dst)
+	if err != nil {
+		return err
+	}
+	return residency.Check(src.Name, srcResidency, dst.Name, dstResidency)
+}
+
+// checkTenant returns an error if the caller in 'ctx' is confined to a tenant
+// that doesn't own 'repo'. Like everyone else, tenant callers may read the
+// spec repo.
+func (d *driver) checkTenant(ctx context.Context, authClient auth.APIClient, repo *pfs.Repo) error {
+	if repo.Name == ppsconsts.SpecRepo {
+		return nil
+	}
+	t, err := tenant.FromAuth(ctx, authClient)
+	if err != nil {
+		return err
+	}
+	return tenant.CheckName(t, repo.Name)
+}
+
 func (d *driver) inspectRepo(
 	txnCtx *txnenv.TransactionContext,
 	repo *pfs.Repo,
@@ -287,6 +418,9 @@ func (d *driver) inspectRepo(
 		return nil, errors.New("repo cannot be nil")
 	}
 
+	if err := d.checkTenant(txnCtx.ClientContext, txnCtx.Client.AuthAPIClient, repo); err != nil {
+		return nil, err
+	}
 	result := &pfs.RepoInfo{}
 	if err := d.repos.ReadWrite(txnCtx.Stm).Get(repo.Name, result); err != nil {
 		return nil, err
@@ -543,6 +677,11 @@ func (e ErrProvenanceOfSubvenance) Error() string {
 // If fix is true it will attempt to fix as many of these issues as it can.
 func (d *driver) fsck(pachClient *client.APIClient, fix bool, cb func(*pfs.FsckResponse) error) error {
 	ctx := pachClient.Ctx()
+	// Fsck reads (and fixes) every repo, so callers confined to a tenant can't
+	// run it
+	if me, err := pachClient.WhoAmI(ctx, &auth.WhoAmIRequest{}); err == nil && me.Tenant != "" {
+		return &auth.ErrNotAuthorized{Subject: me.Username, AdminOp: "Fsck"}
+	}
 	repos := d.repos.ReadOnly(ctx)
 	key := path.Join
 
@@ -897,11 +1036,26 @@ func (d *driver) listRepo(pachClient *client.APIClient, includeAuth bool) (*pfs.
 	repos := d.repos.ReadOnly(ctx)
 	result := &pfs.ListRepoResponse{}
 	authSeemsActive := true
+	t, err := tenant.FromAuth(ctx, pachClient.AuthAPIClient)
+	if err != nil {
+		return nil, err
+	}
+	// A tenant's repos are all under its prefix in etcd, so callers confined
+	// to a tenant only read (and return) those
+	list := repos.List
+	if t != "" {
+		list = func(val proto.Message, opts *col.Options, f func(string) error) error {
+			return repos.ListPrefix(tenant.Prefix(t), val, opts, f)
+		}
+	}
 	repoInfo := &pfs.RepoInfo{}
-	if err := repos.List(repoInfo, col.DefaultOptions, func(repoName string) error {
+	if err := list(repoInfo, col.DefaultOptions, func(repoName string) error {
 		if repoName == ppsconsts.SpecRepo {
 			return nil
 		}
+		if t != "" && repoInfo.Tenant != t {
+			return nil
+		}
 		if includeAuth && authSeemsActive {
 			accessLevel, err := d.getAccessLevel(pachClient, repoInfo.Repo)
 			if err == nil {
@@ -1052,6 +1206,11 @@ func (d *driver) deleteRepo(txnCtx *txnenv.TransactionContext, repo *pfs.Repo, f
 		}
 		branchInfos = append(branchInfos, bi)
 	}
+	if d.trashRetention > 0 && repoInfo.Repo != nil && repo.Name != ppsconsts.SpecRepo {
+		if err := d.trashRepo(txnCtx, repoInfo, branchInfos, commitInfos); err != nil {
+			return err
+		}
+	}
 	// sort ascending provenance
 	sort.Slice(branchInfos, func(i, j int) bool { return len(branchInfos[i].Provenance) < len(branchInfos[j].Provenance) })
 	for i := range branchInfos {
@@ -1072,8 +1231,15 @@ func (d *driver) deleteRepo(txnCtx *txnenv.TransactionContext, repo *pfs.Repo, f
 	// Similarly with commits
 	commitsX := d.commits(repo.Name).ReadWrite(txnCtx.Stm)
 	commitsX.DeleteAll()
-	if err := repos.Delete(repo.Name); err != nil && !col.IsErrNotFound(err) {
-		return errors.Wrapf(err, "repos.Delete")
+	// Tags refer to the repo's commits, so they're deleted with it
+	d.commitTags(repo.Name).ReadWrite(txnCtx.Stm).DeleteAll()
+	d.attestations(repo.Name).ReadWrite(txnCtx.Stm).DeleteAll()
+	if err := repos.Delete(repo.Name); err != nil {
+		if !col.IsErrNotFound(err) {
+			return errors.Wrapf(err, "repos.Delete")
+		}
+	} else if err := tenantutil.Release(d.tenantRepos.ReadWriteInt(txnCtx.Stm), repoInfo.Tenant); err != nil {
+		return err
 	}
 
 	if _, err = txnCtx.Auth().SetACLInTransaction(txnCtx, &auth.SetACLRequest{
@@ -1086,8 +1252,19 @@ func (d *driver) deleteRepo(txnCtx *txnenv.TransactionContext, repo *pfs.Repo, f
 
 // ID can be passed in for transactions, which need to ensure the ID doesn't
 // change after the commit ID has been reported to a client.
-func (d *driver) startCommit(txnCtx *txnenv.TransactionContext, ID string, parent *pfs.Commit, branch string, provenance []*pfs.CommitProvenance, description string) (*pfs.Commit, error) {
-	return d.makeCommit(txnCtx, ID, parent, branch, nil, provenance, nil, nil, nil, nil, nil, description, time.Time{}, time.Time{}, 0)
+func (d *driver) startCommit(txnCtx *txnenv.TransactionContext, ID string, parent *pfs.Commit, branch string, provenance []*pfs.CommitProvenance, description string, metadata map[string]string) (*pfs.Commit, error) {
+	commit, err := d.makeCommit(txnCtx, ID, parent, branch, nil, provenance, nil, nil, nil, nil, nil, description, time.Time{}, time.Time{}, 0)
+	if err != nil || len(metadata) == 0 {
+		return commit, err
+	}
+	commitInfo := &pfs.CommitInfo{}
+	if err := d.commits(commit.Repo.Name).ReadWrite(txnCtx.Stm).Update(commit.ID, commitInfo, func() error {
+		commitInfo.Metadata = metadata
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return commit, nil
 }
 
 func (d *driver) buildCommit(ctx context.Context, ID string, parent *pfs.Commit,
@@ -1216,9 +1393,13 @@ func (d *driver) makeCommit(
 	// addition, 'parent.ID' was not set)
 	key := path.Join
 	branchProvMap := make(map[string]bool)
+	// Whether 'branch' is deferred, in which case the new commit doesn't
+	// trigger processing
+	var deferred bool
 	if branch != "" {
 		branchInfo := &pfs.BranchInfo{}
 		if err := branches.Upsert(branch, branchInfo, func() error {
+			deferred = branchInfo.Deferred
 			// validate branch
 			if parent.ID == "" && branchInfo.Head != nil {
 				parent.ID = branchInfo.Head.ID
@@ -1461,7 +1642,7 @@ func (d *driver) makeCommit(
 	}
 	// Defer propagation of the commit until the end of the transaction so we can
 	// batch downstream commits together if there are multiple changes.
-	if branch != "" {
+	if branch != "" && !deferred {
 		var triggeredBranches []*pfs.Branch
 		if newCommitInfo.Finished != nil {
 			triggeredBranches, err = d.triggerCommit(txnCtx, newCommit)
@@ -1478,7 +1659,7 @@ func (d *driver) makeCommit(
 	return newCommit, nil
 }
 
-func (d *driver) finishCommit(txnCtx *txnenv.TransactionContext, commit *pfs.Commit, tree *pfs.Object, empty bool, description string) (retErr error) {
+func (d *driver) finishCommit(txnCtx *txnenv.TransactionContext, commit *pfs.Commit, tree *pfs.Object, empty bool, description string, metadata map[string]string) (retErr error) {
 	// Validate arguments
 	if commit == nil {
 		return errors.New("commit cannot be nil")
@@ -1500,8 +1681,11 @@ func (d *driver) finishCommit(txnCtx *txnenv.TransactionContext, commit *pfs.Com
 	if description != "" {
 		commitInfo.Description = description
 	}
+	if metadata != nil {
+		commitInfo.Metadata = metadata
+	}
 
-	var parentTree, finishedTree hashtree.HashTree
+	var parentTree hashtree.HashTree
 	if !empty {
 		// Retrieve the parent commit's tree (to apply writes from etcd or just
 		// compute the size change). If parentCommit.Tree == nil, walk up the branch
@@ -1523,33 +1707,25 @@ func (d *driver) finishCommit(txnCtx *txnenv.TransactionContext, commit *pfs.Com
 			return err
 		}
 
-		defer func() {
-			if finishedTree != nil {
-				destroyHashtree(finishedTree)
-			}
-		}()
-
 		if tree == nil {
-			var err error
-			finishedTree, err = d.getTreeForOpenCommit(txnCtx.Client, &pfs.File{Commit: commit}, parentTree)
-			if err != nil {
-				return err
-			}
-			// Put the tree to object storage.
-			treeRef, err := hashtree.PutHashTree(txnCtx.Client, finishedTree)
+			// Build the tree and put it in object storage
+			commitInfo.Tree, commitInfo.SizeBytes, err = d.buildFinishedTree(txnCtx.Client, commitInfo.Commit, parentTree)
 			if err != nil {
 				return err
 			}
-			commitInfo.Tree = treeRef
 		} else {
-			var err error
-			finishedTree, err = hashtree.GetHashTreeObject(txnCtx.Client, d.storageRoot, tree)
+			finishedTree, err := hashtree.GetHashTreeObject(txnCtx.Client, d.storageRoot, tree)
 			if err != nil {
 				return err
 			}
+			defer destroyHashtree(finishedTree)
 			commitInfo.Tree = tree
+			commitInfo.SizeBytes = uint64(finishedTree.FSSize())
 		}
-		commitInfo.SizeBytes = uint64(finishedTree.FSSize())
+	}
+	// The commit's progress (and checkpoint) are no longer needed
+	if err := d.finishCommits.ReadWrite(txnCtx.Stm).Delete(commitInfo.Commit.ID); err != nil && !col.IsErrNotFound(err) {
+		return err
 	}
 	commitInfo.Finished = types.TimestampNow()
 	if err := d.updateProvenanceProgress(txnCtx, !empty, commitInfo); err != nil {
@@ -1570,7 +1746,7 @@ func (d *driver) finishCommit(txnCtx *txnenv.TransactionContext, commit *pfs.Com
 	return nil
 }
 
-func (d *driver) finishOutputCommit(txnCtx *txnenv.TransactionContext, commit *pfs.Commit, trees []*pfs.Object, datums *pfs.Object, size uint64) (retErr error) {
+func (d *driver) finishOutputCommit(txnCtx *txnenv.TransactionContext, commit *pfs.Commit, trees []*pfs.Object, datums *pfs.Object, size uint64, changedPaths *pfs.ChangedPaths, description string, metadata map[string]string) (retErr error) {
 	if err := d.checkIsAuthorizedInTransaction(txnCtx, commit.Repo, auth.Scope_WRITER); err != nil {
 		return err
 	}
@@ -1584,6 +1760,13 @@ func (d *driver) finishOutputCommit(txnCtx *txnenv.TransactionContext, commit *p
 	commitInfo.Trees = trees
 	commitInfo.Datums = datums
 	commitInfo.SizeBytes = size
+	commitInfo.ChangedPaths = changedPaths
+	if description != "" {
+		commitInfo.Description = description
+	}
+	if metadata != nil {
+		commitInfo.Metadata = metadata
+	}
 	commitInfo.Finished = types.TimestampNow()
 	if err := d.updateProvenanceProgress(txnCtx, true, commitInfo); err != nil {
 		return err
@@ -1980,12 +2163,22 @@ func (d *driver) resolveCommit(stm col.STM, userCommit *pfs.Commit) (*pfs.Commit
 		return nil, errors.Errorf("cannot resolve commit with no ID or branch")
 	}
 	commit := proto.Clone(userCommit).(*pfs.Commit) // back up user commit, for error reporting
-	// Extract any ancestor tokens from 'commit.ID' (i.e. ~, ^ and .)
+	// Extract any ancestor tokens from 'commit.ID' (i.e. ~, ^ and .). Tag
+	// names may contain '.', so a tag whose name looks like ancestry syntax
+	// takes precedence over it.
 	var ancestryLength int
+	var tagged bool
 	var err error
-	commit.ID, ancestryLength, err = ancestry.Parse(commit.ID)
-	if err != nil {
-		return nil, err
+	if strings.Contains(commit.ID, ".") {
+		if tagged, err = d.resolveCommitTag(stm, commit); err != nil {
+			return nil, err
+		}
+	}
+	if !tagged {
+		commit.ID, ancestryLength, err = ancestry.Parse(commit.ID)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Keep track of the commit branch, in case it isn't set in the commitInfo already
@@ -1994,15 +2187,23 @@ func (d *driver) resolveCommit(stm col.STM, userCommit *pfs.Commit) (*pfs.Commit
 	if !uuid.IsUUIDWithoutDashes(commit.ID) {
 		branches := d.branches(commit.Repo.Name).ReadWrite(stm)
 		branchInfo := &pfs.BranchInfo{}
-		// See if we are given a branch
+		// See if we are given a branch, or else a tag
 		if err := branches.Get(commit.ID, branchInfo); err != nil {
-			return nil, err
-		}
-		if branchInfo.Head == nil {
-			return nil, pfsserver.ErrNoHead{branchInfo.Branch}
+			if !col.IsErrNotFound(err) {
+				return nil, err
+			}
+			if tagged, tagErr := d.resolveCommitTag(stm, commit); tagErr != nil {
+				return nil, tagErr
+			} else if !tagged {
+				return nil, err
+			}
+		} else {
+			if branchInfo.Head == nil {
+				return nil, pfsserver.ErrNoHead{branchInfo.Branch}
+			}
+			commitBranch = branchInfo.Branch
+			commit.ID = branchInfo.Head.ID
 		}
-		commitBranch = branchInfo.Branch
-		commit.ID = branchInfo.Head.ID
 	}
 
 	// Traverse commits' parents until you've reached the right ancestor
@@ -2041,6 +2242,7 @@ func (d *driver) resolveCommit(stm col.STM, userCommit *pfs.Commit) (*pfs.Commit
 					}
 					return nil, pfsserver.ErrParentCommitNotFound{commit}
 				}
+				return nil, err
 			}
 			commit = cis[i%len(cis)].ParentCommit
 		}
@@ -2197,6 +2399,9 @@ func (d *driver) subscribeCommit(pachClient *client.APIClient, repo *pfs.Repo, b
 	if from != nil && from.Repo.Name != repo.Name {
 		return errors.Errorf("the `from` commit needs to be from repo %s", repo.Name)
 	}
+	if err := d.checkTenant(pachClient.Ctx(), pachClient.AuthAPIClient, repo); err != nil {
+		return err
+	}
 
 	commits := d.commits(repo.Name).ReadOnly(pachClient.Ctx())
 	newCommitWatcher, err := commits.Watch(watch.WithSort(etcd.SortByCreateRevision, etcd.SortAscend))
@@ -2425,6 +2630,11 @@ func (d *driver) deleteCommit(txnCtx *txnenv.TransactionContext, userCommit *pfs
 	if provenantOnInput(userCommitInfo.Provenance) {
 		return errors.Errorf("cannot delete the commit \"%s/%s\" because it has non-empty provenance", userCommit.Repo.Name, userCommit.ID)
 	}
+	if d.trashRetention > 0 {
+		if err := d.trashCommit(txnCtx, userCommitInfo); err != nil {
+			return err
+		}
+	}
 	deleteCommit(userCommitInfo.Commit, userCommitInfo.Commit)
 
 	// 4) Delete all of the downstream commits of 'commit'
@@ -2432,6 +2642,14 @@ func (d *driver) deleteCommit(txnCtx *txnenv.TransactionContext, userCommit *pfs
 		deleteCommit(subv.Lower, subv.Upper)
 	}
 
+	// Tags are immutable, so tagged commits can't be deleted
+	for _, deletedInfo := range deleted {
+		if len(deletedInfo.Tags) > 0 {
+			return errors.Errorf("cannot delete commit %s/%s because it's tagged as %q; delete its tags first",
+				deletedInfo.Commit.Repo.Name, deletedInfo.Commit.ID, deletedInfo.Tags)
+		}
+	}
+
 	// 5) Remove the commits in 'deleted' from all remaining upstream commits'
 	// subvenance.
 	// While 'commit' is required to be an input commit (no provenance),
@@ -2639,8 +2857,11 @@ func (d *driver) deleteCommit(txnCtx *txnenv.TransactionContext, userCommit *pfs
 
 	// 8) propagate the changes to 'branch' and its subvenance. This may start
 	// new HEAD commits downstream, if the new branch heads haven't been
-	// processed yet
+	// processed yet. The new heads of deferred branches aren't processed.
 	for _, afBranch := range affectedBranches {
+		if afBranch.Deferred {
+			continue
+		}
 		if err := txnCtx.PropagateCommit(afBranch.Branch, false); err != nil {
 			return err
 		}
@@ -2705,6 +2926,27 @@ func (d *driver) createBranch(txnCtx *txnenv.TransactionContext, branch *pfs.Bra
 	if err := ancestry.ValidateName(branch.Name); err != nil {
 		return err
 	}
+	// Branches and tags share a namespace, so that a name always refers to
+	// the same thing
+	if err := d.commitTags(branch.Repo.Name).ReadWrite(txnCtx.Stm).Get(branch.Name, &pfs.CommitTagInfo{}); err == nil {
+		return errors.Errorf("cannot create branch %q because a commit tag with that name exists in repo %q", branch.Name, branch.Repo.Name)
+	} else if !col.IsErrNotFound(err) {
+		return err
+	}
+	// 'commit' may be any commit or branch of 'branch's repo, with ancestry
+	// syntax (e.g. master^3 or master.2), which is resolved below
+	var ancestors int
+	if commit != nil {
+		if commit.Repo == nil {
+			commit.Repo = branch.Repo
+		} else if commit.Repo.Name != branch.Repo.Name {
+			return errors.Errorf("cannot set the head of branch %s@%s to a commit in repo %q", branch.Repo.Name, branch.Name, commit.Repo.Name)
+		}
+		if _, ancestors, err = ancestry.Parse(commit.ID); err != nil && !strings.Contains(commit.ID, ".") {
+			// Tag names may contain '.', so they're checked by resolveCommit
+			return errors.Wrapf(err, "invalid head for branch %s@%s", branch.Repo.Name, branch.Name)
+		}
+	}
 	// The request must do exactly one of:
 	// 1) updating 'branch's provenance (commit is nil OR commit == branch)
 	// 2) re-pointing 'branch' at a new commit
@@ -2740,8 +2982,9 @@ func (d *driver) createBranch(txnCtx *txnenv.TransactionContext, branch *pfs.Bra
 		_, err = d.resolveCommit(txnCtx.Stm, commit) // if 'commit' is a branch, resolve it
 		if err != nil {
 			// possible that branch exists but has no head commit. This is fine, but
-			// branchInfo.Head must also be nil
-			if !isNoHeadErr(err) {
+			// branchInfo.Head must also be nil. The ancestors of a headless branch
+			// don't exist, though.
+			if !isNoHeadErr(err) || ancestors != 0 {
 				return errors.Wrapf(err, "unable to inspect %s@%s", commit.Repo.Name, commit.ID)
 			}
 			commit = nil
@@ -2751,7 +2994,12 @@ func (d *driver) createBranch(txnCtx *txnenv.TransactionContext, branch *pfs.Bra
 	// Retrieve (and create, if necessary) the current version of this branch
 	branches := d.branches(branch.Repo.Name).ReadWrite(txnCtx.Stm)
 	branchInfo := &pfs.BranchInfo{}
+	var deferred bool
 	if err := branches.Upsert(branch.Name, branchInfo, func() error {
+		if branchInfo.Deferred && len(provenance) > 0 {
+			return errors.Errorf("branch %s@%s is deferred, so it can't have provenance", branch.Repo.Name, branch.Name)
+		}
+		deferred = branchInfo.Deferred
 		branchInfo.Name = branch.Name // set in case 'branch' is new
 		branchInfo.Branch = branch
 		branchInfo.Head = commit
@@ -2850,7 +3098,11 @@ func (d *driver) createBranch(txnCtx *txnenv.TransactionContext, branch *pfs.Bra
 
 	// propagate the head commit to 'branch'. This may also modify 'branch', by
 	// creating a new HEAD commit if 'branch's provenance was changed and its
-	// current HEAD commit has old provenance
+	// current HEAD commit has old provenance. Deferred branches' new heads
+	// aren't processed until the branch is promoted.
+	if deferred {
+		return nil
+	}
 	var triggeredBranches []*pfs.Branch
 	if commit != nil {
 		if ci == nil {
@@ -2874,6 +3126,34 @@ func (d *driver) createBranch(txnCtx *txnenv.TransactionContext, branch *pfs.Bra
 	return nil
 }
 
+// moveBranch moves the existing branch 'branch' to 'head', keeping its
+// provenance and trigger. If 'expectedHead' is set, the branch is only moved if
+// its head is currently that commit. As this happens in a single STM, the
+// check and the move are atomic.
+func (d *driver) moveBranch(txnCtx *txnenv.TransactionContext, branch *pfs.Branch, head *pfs.Commit, expectedHead *pfs.Commit) error {
+	if branch == nil || branch.Repo == nil {
+		return errors.New("branch cannot be nil")
+	}
+	if head == nil {
+		return errors.New("head cannot be nil")
+	}
+	if err := d.checkIsAuthorizedInTransaction(txnCtx, branch.Repo, auth.Scope_WRITER); err != nil {
+		return err
+	}
+	branchInfo := &pfs.BranchInfo{}
+	if err := d.branches(branch.Repo.Name).ReadWrite(txnCtx.Stm).Get(branch.Name, branchInfo); err != nil {
+		return err
+	}
+	if expectedHead != nil {
+		if err := d.checkBranchHead(txnCtx, branch, branchInfo.Head, expectedHead); err != nil {
+			return err
+		}
+	}
+	// 'createBranch' checks that the new head is consistent with the branch's
+	// provenance, and propagates it
+	return d.createBranch(txnCtx, branch, proto.Clone(head).(*pfs.Commit), branchInfo.DirectProvenance, nil)
+}
+
 func (d *driver) inspectBranch(txnCtx *txnenv.TransactionContext, branch *pfs.Branch) (*pfs.BranchInfo, error) {
 	// Validate arguments
 	if branch == nil {
@@ -2882,6 +3162,9 @@ func (d *driver) inspectBranch(txnCtx *txnenv.TransactionContext, branch *pfs.Br
 	if branch.Repo == nil {
 		return nil, errors.New("branch repo cannot be nil")
 	}
+	if err := d.checkTenant(txnCtx.ClientContext, txnCtx.Client.AuthAPIClient, branch.Repo); err != nil {
+		return nil, err
+	}
 
 	result := &pfs.BranchInfo{}
 	if err := d.branches(branch.Repo.Name).ReadWrite(txnCtx.Stm).Get(branch.Name, result); err != nil {
@@ -3018,8 +3301,14 @@ func (d *driver) putFiles(pachClient *client.APIClient, s *putFileServer) error
 	var putFileRecords []*pfs.PutFileRecords
 	var mu sync.Mutex
 	oneOff, repo, branch, err := d.forEachPutFile(pachClient, s, func(req *pfs.PutFileRequest, r io.Reader) error {
-		records, err := d.putFile(pachClient, req.File, req.Delimiter, req.TargetFileDatums,
-			req.TargetFileBytes, req.HeaderRecords, req.OverwriteIndex, req.Delete, r)
+		var records *pfs.PutFileRecords
+		var err error
+		if len(req.Objects) > 0 {
+			records, err = d.putFileObjects(pachClient, req.File, req.Delimiter, req.Objects, req.OverwriteIndex)
+		} else {
+			records, err = d.putFile(pachClient, req.File, req.Delimiter, req.TargetFileDatums,
+				req.TargetFileBytes, req.HeaderRecords, req.OverwriteIndex, req.Delete, r)
+		}
 		if err != nil {
 			return err
 		}
@@ -3052,6 +3341,44 @@ func (d *driver) putFiles(pachClient *client.APIClient, s *putFileServer) error
 	return nil
 }
 
+// putFileObjects is like putFile, but the file's contents are 'objects', which
+// the client has already uploaded (e.g. in parallel, with PutObject)
+func (d *driver) putFileObjects(pachClient *client.APIClient, file *pfs.File, delimiter pfs.Delimiter,
+	objects []*pfs.Object, overwriteIndex *pfs.OverwriteIndex) (*pfs.PutFileRecords, error) {
+	if err := d.checkIsAuthorized(pachClient, file.Commit.Repo, auth.Scope_WRITER); err != nil {
+		return nil, err
+	}
+	if delimiter != pfs.Delimiter_NONE {
+		return nil, errors.Errorf("cannot split a file that's put from objects")
+	}
+	if err := checkFilePath(file.Path); err != nil {
+		return nil, err
+	}
+	if err := hashtree.ValidatePath(file.Path); err != nil {
+		return nil, err
+	}
+	records := &pfs.PutFileRecords{}
+	if overwriteIndex != nil && overwriteIndex.Index == 0 {
+		records.Tombstone = true
+	}
+	for i, object := range objects {
+		objectInfo, err := pachClient.InspectObject(object.Hash)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not inspect object %q", object.Hash)
+		}
+		record := &pfs.PutFileRecord{
+			ObjectHash: object.Hash,
+			SizeBytes:  int64(objectInfo.BlockRef.Range.Upper - objectInfo.BlockRef.Range.Lower),
+		}
+		// The first record takes care of the overwriting
+		if i == 0 && overwriteIndex != nil && overwriteIndex.Index != 0 {
+			record.OverwriteIndex = overwriteIndex
+		}
+		records.Records = append(records.Records, record)
+	}
+	return records, nil
+}
+
 func (d *driver) putFile(pachClient *client.APIClient, file *pfs.File, delimiter pfs.Delimiter,
 	targetFileDatums, targetFileBytes, headerRecords int64, overwriteIndex *pfs.OverwriteIndex,
 	del bool, reader io.Reader) (*pfs.PutFileRecords, error) {
@@ -3077,6 +3404,16 @@ func (d *driver) putFile(pachClient *client.APIClient, file *pfs.File, delimiter
 	if err := hashtree.ValidatePath(file.Path); err != nil {
 		return nil, err
 	}
+	// The file's contents are stored in the bucket of the repo's residency,
+	// under the prefix of the repo's tenant
+	repoInfo := &pfs.RepoInfo{}
+	if err := d.repos.ReadOnly(pachClient.Ctx()).Get(file.Commit.Repo.Name, repoInfo); err != nil {
+		if col.IsErrNotFound(err) {
+			return nil, pfsserver.ErrRepoNotFound{file.Commit.Repo}
+		}
+		return nil, err
+	}
+	pachClient = pachClient.WithCtx(tenant.WithObjectContext(residency.WithContext(pachClient.Ctx(), repoInfo.Residency), repoInfo.Tenant))
 
 	if delimiter == pfs.Delimiter_NONE {
 		d.putObjectLimiter.Acquire()
@@ -3364,6 +3701,15 @@ func (d *driver) copyFile(pachClient *client.APIClient, src *pfs.File, dst *pfs.
 	if err := d.checkIsAuthorized(pachClient, dst.Commit.Repo, auth.Scope_WRITER); err != nil {
 		return err
 	}
+	if err := d.checkCopyResidency(pachClient.Ctx(), src.Commit.Repo, dst.Commit.Repo); err != nil {
+		return err
+	}
+	// Copying unredacted files out of a repo would bypass its read policy
+	if redactor, err := d.getRedactor(pachClient, src.Commit.Repo); err != nil {
+		return err
+	} else if redactor != nil {
+		return errors.Errorf("cannot copy files out of repo %q, which has a read policy, without full access to it", src.Commit.Repo.Name)
+	}
 	if err := checkFilePath(dst.Path); err != nil {
 		return err
 	}
@@ -3633,13 +3979,9 @@ func (d *driver) getTreeForOpenCommit(pachClient *client.APIClient, file *pfs.Fi
 		}
 	}()
 
-	recordsCol := d.putFileRecords.ReadOnly(pachClient.Ctx())
-	putFileRecords := &pfs.PutFileRecords{}
-	opts := &col.Options{etcd.SortByModRevision, etcd.SortAscend, true}
-	err = recordsCol.ListPrefix(prefix, putFileRecords, opts, func(key string) error {
-		return d.applyWrite(path.Join(file.Path, key), putFileRecords, tree)
-	})
-	if err != nil {
+	if err := d.listWrites(pachClient.Ctx(), file, prefix, nil, false, func(writes []*putFileWrite) error {
+		return d.applyWrites(writes, tree)
+	}); err != nil {
 		return nil, err
 	}
 	if err := tree.Hash(); err != nil {
@@ -4335,15 +4677,51 @@ func (d *driver) deleteFile(pachClient *client.APIClient, file *pfs.File) error
 	return d.upsertPutFileRecords(pachClient, file, &pfs.PutFileRecords{Tombstone: true})
 }
 
-func (d *driver) deleteAll(txnCtx *txnenv.TransactionContext) error {
+// checkDeleteAll returns an error if DELETE_ALL_POLICY doesn't allow the
+// caller of 'txnCtx' to delete all of the repos it can see: every repo in the
+// cluster or, if the caller is confined to a tenant, all of the tenant's
+// repos. It returns the caller's name, or "" if auth isn't active.
+func (d *driver) checkDeleteAll(txnCtx *txnenv.TransactionContext) (string, error) {
+	var username string
+	if me, err := txnCtx.Client.WhoAmI(txnCtx.ClientContext, &auth.WhoAmIRequest{}); err == nil {
+		username = me.Username
+	} else if !auth.IsErrNotActivated(err) {
+		return "", grpcutil.ScrubGRPC(err)
+	}
+	if err := deleteall.Check(txnCtx.ClientContext, d.etcdClient, d.env.EtcdPrefix, d.env.DeleteAllPolicy, "pfs.DeleteAll", username); err != nil {
+		return "", err
+	}
+	return username, nil
+}
+
+// deleteAll deletes every repo, or, if 'prefix' is set, every repo whose name
+// starts with it. Deleting every repo is subject to the cluster's
+// DeleteAllPolicy.
+func (d *driver) deleteAll(txnCtx *txnenv.TransactionContext, prefix string) (retErr error) {
+	if prefix == "" {
+		username, err := d.checkDeleteAll(txnCtx)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			deleteall.Audit("pfs.DeleteAll", username, retErr)
+		}()
+	}
 	// Note: d.listRepo() doesn't return the 'spec' repo, so it doesn't get
-	// deleted here. Instead, PPS is responsible for deleting and re-creating it
+	// deleted here. Instead, PPS is responsible for deleting and re-creating it.
+	// If the caller is confined to a tenant, d.listRepo() only returns the
+	// tenant's repos, so only those are deleted.
 	repoInfos, err := d.listRepo(txnCtx.Client, !includeAuth)
 	if err != nil {
 		return err
 	}
+	// The repos are all deleted in one transaction, so if the caller isn't
+	// authorized to delete one of them, none of them are deleted
 	for _, repoInfo := range repoInfos.RepoInfo {
-		if err := d.deleteRepo(txnCtx, repoInfo.Repo, true); err != nil && !auth.IsErrNotAuthorized(err) {
+		if !strings.HasPrefix(repoInfo.Repo.Name, prefix) {
+			continue
+		}
+		if err := d.deleteRepo(txnCtx, repoInfo.Repo, true); err != nil {
 			return err
 		}
 	}
@@ -4366,18 +4744,19 @@ func (d *driver) upsertPutFileRecords(
 
 	ctx := pachClient.Ctx()
 	_, err = col.NewSTM(ctx, d.etcdClient, func(stm col.STM) error {
-		commitsCol := d.openCommits.ReadWrite(stm)
-		var commit pfs.Commit
-		err := commitsCol.Get(file.Commit.ID, &commit)
+		if err := d.checkOpen(stm, file.Commit); err != nil {
+			return err
+		}
+		// Writes to a directory that's being replaced are staged until the
+		// commit is finished
+		recordsCol := d.putFileRecords.ReadWrite(stm)
+		staged, err := d.isStaged(stm, d.scratchCommitPrefix(file.Commit), prefix)
 		if err != nil {
 			return err
 		}
-		// Dumb check to make sure the unmarshalled value exists (and matches the current ID)
-		// to denote that the current commit is indeed open
-		if commit.ID != file.Commit.ID {
-			return errors.Errorf("commit %v is not open", file.Commit.ID)
+		if staged {
+			recordsCol = d.stagedPutFileRecords.ReadWrite(stm)
 		}
-		recordsCol := d.putFileRecords.ReadWrite(stm)
 		var existingRecords pfs.PutFileRecords
 		return recordsCol.Upsert(prefix, &existingRecords, func() error {
 			if newRecords.Tombstone {
@@ -4802,7 +5181,10 @@ func (d *driver) forEachPutFile(pachClient *client.APIClient, server pfs.API_Put
 			if pw != nil {
 				pw.Close() // can't error
 			}
-			if req.Delete {
+			if req.Delete || len(req.Objects) > 0 {
+				if len(req.Objects) > 0 && len(req.Value) > 0 {
+					return false, "", "", errors.New("cannot set both a value and objects in a put file request")
+				}
 				d.putFileLimiter.Acquire()
 				eg.Go(func() error {
 					defer d.putFileLimiter.Release()
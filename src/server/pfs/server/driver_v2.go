@@ -19,6 +19,7 @@ import (
 	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
 	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
 	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/deleteall"
 	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
 	"github.com/pachyderm/pachyderm/src/server/pkg/serviceenv"
 	"github.com/pachyderm/pachyderm/src/server/pkg/storage/chunk"
@@ -53,6 +54,9 @@ func newDriverV2(env *serviceenv.ServiceEnv, txnEnv *txnenv.TransactionEnv, etcd
 	if err != nil {
 		return nil, err
 	}
+	// V2 deletes file data as soon as a repo or commit is deleted, so there's
+	// nothing for the trash to restore
+	d1.trashRetention = 0
 	d2 := &driverV2{driver: d1}
 	objClient, err := NewObjClient(env.Configuration)
 	if err != nil {
@@ -95,7 +99,7 @@ func newDB() (*gorm.DB, error) {
 	return gc.NewDB(postgresHost, postgresPort)
 }
 
-func (d *driverV2) finishCommitV2(txnCtx *txnenv.TransactionContext, commit *pfs.Commit, description string) error {
+func (d *driverV2) finishCommitV2(txnCtx *txnenv.TransactionContext, commit *pfs.Commit, description string, metadata map[string]string) error {
 	commitInfo, err := d.resolveCommit(txnCtx.Stm, commit)
 	if err != nil {
 		return err
@@ -107,6 +111,9 @@ func (d *driverV2) finishCommitV2(txnCtx *txnenv.TransactionContext, commit *pfs
 	if description != "" {
 		commitInfo.Description = description
 	}
+	if metadata != nil {
+		commitInfo.Metadata = metadata
+	}
 	commitPath := commitKey(commit)
 	// Run compaction task.
 	return d.compactionQueue.RunTaskBlock(txnCtx.Client.Ctx(), func(m *work.Master) error {
@@ -174,13 +181,13 @@ func (d *driverV2) fileOperation(pachClient *client.APIClient, commit *pfs.Commi
 // TODO: Cleanup after failure?
 func (d *driverV2) oneOffFileOperation(ctx context.Context, repo, branch string, cb func(*fileset.UnorderedWriter) error) error {
 	return d.txnEnv.WithWriteContext(ctx, func(txnCtx *txnenv.TransactionContext) (retErr error) {
-		commit, err := d.startCommit(txnCtx, "", client.NewCommit(repo, ""), branch, nil, "")
+		commit, err := d.startCommit(txnCtx, "", client.NewCommit(repo, ""), branch, nil, "", nil)
 		if err != nil {
 			return err
 		}
 		defer func() {
 			if retErr == nil {
-				retErr = d.finishCommitV2(txnCtx, commit, "")
+				retErr = d.finishCommitV2(txnCtx, commit, "", nil)
 			}
 		}()
 		return d.withCommitWriter(txnCtx.ClientContext, commit, cb)
@@ -766,7 +773,16 @@ func (d *driverV2) deleteRepo(txnCtx *txnenv.TransactionContext, repo *pfs.Repo,
 	return d.driver.deleteRepo(txnCtx, repo, force)
 }
 
-func (d *driverV2) deleteAll(txnCtx *txnenv.TransactionContext) error {
+func (d *driverV2) deleteAll(txnCtx *txnenv.TransactionContext, prefix string) (retErr error) {
+	if prefix == "" {
+		username, err := d.checkDeleteAll(txnCtx)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			deleteall.Audit("pfs.DeleteAll", username, retErr)
+		}()
+	}
 	// Note: d.listRepo() doesn't return the 'spec' repo, so it doesn't get
 	// deleted here. Instead, PPS is responsible for deleting and re-creating it
 	repoInfos, err := d.listRepo(txnCtx.Client, !includeAuth)
@@ -774,7 +790,10 @@ func (d *driverV2) deleteAll(txnCtx *txnenv.TransactionContext) error {
 		return err
 	}
 	for _, repoInfo := range repoInfos.RepoInfo {
-		if err := d.deleteRepo(txnCtx, repoInfo.Repo, true); err != nil && !auth.IsErrNotAuthorized(err) {
+		if !strings.HasPrefix(repoInfo.Repo.Name, prefix) {
+			continue
+		}
+		if err := d.deleteRepo(txnCtx, repoInfo.Repo, true); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
+)
+
+// inspectFileAncestry inspects 'file' in the nearest of its commit and that
+// commit's first 'depth' ancestors that has it, which supports overlays in
+// which a branch only holds the files that it overrides. The commit that was
+// read is returned in the FileInfo's File. If none of them has 'file', the
+// error is the same as inspectFile's.
+func (d *driver) inspectFileAncestry(pachClient *client.APIClient, file *pfs.File, depth int64) (*pfs.FileInfo, error) {
+	if depth < 0 {
+		return nil, errors.Errorf("ancestry depth must not be negative, got %d", depth)
+	}
+	if err := validateFile(file); err != nil {
+		return nil, err
+	}
+	fileInfo, err := d.inspectFile(pachClient, file)
+	if err == nil || !pfsserver.IsFileNotFoundErr(err) {
+		return fileInfo, err
+	}
+	notFoundErr := err
+	commit := file.Commit
+	for i := int64(0); i < depth; i++ {
+		commitInfo, err := d.inspectCommit(pachClient, commit, pfs.CommitState_STARTED)
+		if err != nil {
+			return nil, err
+		}
+		if commitInfo.ParentCommit == nil {
+			break
+		}
+		commit = commitInfo.ParentCommit
+		fileInfo, err := d.inspectFile(pachClient, &pfs.File{Commit: commit, Path: file.Path})
+		if err == nil || !pfsserver.IsFileNotFoundErr(err) {
+			return fileInfo, err
+		}
+	}
+	return nil, notFoundErr
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	pachhttp "github.com/pachyderm/pachyderm/src/server/http"
+	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
+)
+
+const (
+	// fileURLKeyName is the key of the key that file URLs are signed with in
+	// the attestationKey collection
+	fileURLKeyName = "file-url"
+
+	// defaultFileURLTTL is how long file URLs are valid for if GetFileURL's
+	// caller doesn't say, and maxFileURLTTL is the longest they can be valid
+	// for
+	defaultFileURLTTL = time.Hour
+	maxFileURLTTL     = 7 * 24 * time.Hour
+)
+
+// fileURLMessage returns what's signed in the URL of 'file' (whose commit
+// must be a commit ID) that expires at 'expires'
+func fileURLMessage(file *pfs.File, expires int64) []byte {
+	return []byte(fmt.Sprintf("pachyderm file URL v1\n%s\n%s\n%s\n%d",
+		file.Commit.Repo.Name, file.Commit.ID, path.Clean("/"+file.Path), expires))
+}
+
+// verifyURLSignature returns an error unless 'signature' is a valid
+// signature, by 'key', of the URL of 'file' that hasn't expired at 'now'
+func verifyURLSignature(key *pfs.AttestationKey, file *pfs.File, signature *pfs.URLSignature, now time.Time) error {
+	if now.Unix() > signature.Expires {
+		return pachhttp.ErrInvalidURLSignature
+	}
+	publicKey := ed25519.PrivateKey(key.PrivateKey).Public().(ed25519.PublicKey)
+	if !ed25519.Verify(publicKey, fileURLMessage(file, signature.Expires), signature.Signature) {
+		return pachhttp.ErrInvalidURLSignature
+	}
+	return nil
+}
+
+// getFileURL returns a URL on pachd's HTTP server that anyone can read 'file'
+// from for 'ttl'. The caller must be able to read the file, and its commit
+// must be finished.
+func (d *driver) getFileURL(pachClient *client.APIClient, file *pfs.File, ttl *types.Duration) (*pfs.GetFileURLResponse, error) {
+	if err := validateFile(file); err != nil {
+		return nil, err
+	}
+	if hashtree.IsGlob(file.Path) {
+		return nil, errors.Errorf("can't get the URL of %q: URLs can't be for glob patterns", file.Path)
+	}
+	expiresIn := defaultFileURLTTL
+	if ttl != nil {
+		var err error
+		if expiresIn, err = types.DurationFromProto(ttl); err != nil {
+			return nil, errors.Wrapf(err, "invalid ttl")
+		}
+		if expiresIn <= 0 || expiresIn > maxFileURLTTL {
+			return nil, errors.Errorf("invalid ttl %v: it must be positive, and at most %v", expiresIn, maxFileURLTTL)
+		}
+	}
+	// The URL is for the commit's ID, so that what it serves can't change
+	commitInfo, err := d.inspectCommit(pachClient, file.Commit, pfs.CommitState_STARTED)
+	if err != nil {
+		return nil, err
+	}
+	if commitInfo.Finished == nil {
+		return nil, errors.Errorf("can't get the URL of a file in commit %s@%s, which isn't finished",
+			commitInfo.Commit.Repo.Name, commitInfo.Commit.ID)
+	}
+	file = client.NewFile(file.Commit.Repo.Name, commitInfo.Commit.ID, path.Clean("/"+file.Path))
+	// Whoever has the URL reads the file unredacted
+	redactor, err := d.getRedactor(pachClient, file.Commit.Repo)
+	if err != nil {
+		return nil, err
+	}
+	if redactor != nil {
+		return nil, errors.Errorf("repo %q has a read policy, and its files can only be shared by URL by its writers", file.Commit.Repo.Name)
+	}
+	fileInfo, err := d.inspectFile(pachClient, file)
+	if err != nil {
+		return nil, err
+	}
+	if fileInfo.FileType != pfs.FileType_FILE {
+		return nil, errors.Errorf("can't get the URL of %q, which isn't a file", file.Path)
+	}
+
+	var key *pfs.AttestationKey
+	if _, err := col.NewSTM(pachClient.Ctx(), d.etcdClient, func(stm col.STM) error {
+		var err error
+		key, err = d.getSigningKey(stm, fileURLKeyName)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	expires := time.Now().Add(expiresIn).Truncate(time.Second)
+	signature := &pfs.URLSignature{
+		Expires:   expires.Unix(),
+		Signature: ed25519.Sign(ed25519.PrivateKey(key.PrivateKey), fileURLMessage(file, expires.Unix())),
+	}
+	expiresProto, err := types.TimestampProto(expires)
+	if err != nil {
+		return nil, err
+	}
+	return &pfs.GetFileURLResponse{
+		Path:    pachhttp.SignedFilePath(file, signature),
+		Expires: expiresProto,
+	}, nil
+}
+
+// urlSignatureClient returns a client that can read 'file', for a request
+// that's authorized by its URL signature rather than by its caller, if
+// 'signature' is valid. The client acts as PPS, which can read every repo.
+func (d *driver) urlSignatureClient(pachClient *client.APIClient, file *pfs.File, signature *pfs.URLSignature) (*client.APIClient, error) {
+	if err := validateFile(file); err != nil {
+		return nil, err
+	}
+	ctx := pachClient.Ctx()
+	key := &pfs.AttestationKey{}
+	if err := d.attestationKey.ReadOnly(ctx).Get(fileURLKeyName, key); err != nil {
+		if col.IsErrNotFound(err) {
+			// No URL has been signed yet
+			return nil, pachhttp.ErrInvalidURLSignature
+		}
+		return nil, err
+	}
+	if err := verifyURLSignature(key, file, signature, time.Now()); err != nil {
+		return nil, err
+	}
+	// A read policy may have been set since the URL was signed
+	repoInfo := &pfs.RepoInfo{}
+	if err := d.repos.ReadOnly(ctx).Get(file.Commit.Repo.Name, repoInfo); err != nil {
+		if col.IsErrNotFound(err) {
+			return nil, pfsserver.ErrRepoNotFound{Repo: file.Commit.Repo}
+		}
+		return nil, err
+	}
+	if repoInfo.ReadPolicy != nil {
+		return nil, errors.Errorf("repo %q has a read policy, so its files can't be read by URL", file.Commit.Repo.Name)
+	}
+	var token types.StringValue
+	if err := col.NewCollection(d.etcdClient, ppsconsts.PPSTokenKey, nil, &types.StringValue{}, nil, nil).ReadOnly(ctx).Get("", &token); err != nil && !col.IsErrNotFound(err) {
+		return nil, err
+	}
+	// Keep the request's ctx (to propagate cancellation), but replace its
+	// credentials with PPS's (without impersonating anyone)
+	ppsClient := pachClient.WithCtx(ctx).WithoutImpersonation()
+	ppsClient.SetAuthToken(token.Value)
+	return ppsClient, nil
+}
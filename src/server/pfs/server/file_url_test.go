@@ -0,0 +1,41 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestVerifyURLSignature(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key := &pfs.AttestationKey{PrivateKey: privateKey}
+	now := time.Date(2020, 6, 10, 0, 0, 0, 0, time.UTC)
+	file := client.NewFile("images", "abc", "/cats/1.png")
+	expires := now.Add(time.Hour).Unix()
+	signature := &pfs.URLSignature{
+		Expires:   expires,
+		Signature: ed25519.Sign(privateKey, fileURLMessage(file, expires)),
+	}
+
+	require.NoError(t, verifyURLSignature(key, file, signature, now))
+	// Equivalent paths have the same signature
+	require.NoError(t, verifyURLSignature(key, client.NewFile("images", "abc", "cats//1.png"), signature, now))
+	// The signature is only valid for the file it was made for
+	require.YesError(t, verifyURLSignature(key, client.NewFile("images", "abc", "/cats/2.png"), signature, now))
+	require.YesError(t, verifyURLSignature(key, client.NewFile("images", "def", "/cats/1.png"), signature, now))
+	require.YesError(t, verifyURLSignature(key, client.NewFile("videos", "abc", "/cats/1.png"), signature, now))
+	// and until it expires
+	require.YesError(t, verifyURLSignature(key, file, signature, now.Add(2*time.Hour)))
+	require.YesError(t, verifyURLSignature(key, file, &pfs.URLSignature{Expires: expires + 3600, Signature: signature.Signature}, now))
+
+	// Signatures by other keys aren't valid
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	require.YesError(t, verifyURLSignature(&pfs.AttestationKey{PrivateKey: otherKey}, file, signature, now))
+}
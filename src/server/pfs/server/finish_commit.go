@@ -0,0 +1,450 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"hash"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/limit"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
+
+	etcd "github.com/coreos/etcd/clientv3"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// The number of put-file records that are read from etcd and applied to a
+	// commit's hashtree at a time
+	finishCommitChunkSize = 10000
+	// How often the progress of FinishCommit is written to etcd
+	finishCommitProgressInterval = 5 * time.Second
+	// How often a partially-built hashtree is checkpointed to object storage
+	// while a commit is finished
+	finishCommitCheckpointInterval = 2 * time.Minute
+)
+
+// errStaleCheckpoint is returned when a commit was modified after a
+// FinishCommit checkpoint was written, so the checkpoint can't be resumed
+var errStaleCheckpoint = errors.New("the commit was modified after the checkpoint")
+
+// putFileWrite is the put-file records of a path in an open commit, which are
+// applied to the commit's hashtree when the commit is read or finished.
+type putFileWrite struct {
+	path    string
+	records *pfs.PutFileRecords
+}
+
+// isBarrierWrite returns true if a write to 'p' may affect any path in the
+// hashtree (i.e. it's a write to the root, or a delete of a glob pattern), so
+// it can't be applied concurrently with other writes.
+func isBarrierWrite(p string) bool {
+	return path.Clean("/"+p) == "/" || hashtree.IsGlob(p)
+}
+
+// writeGroups splits 'writes' (none of which are barrier writes) into groups
+// that can be applied concurrently, preserving the order of the writes within
+// each group. Writes to a path and to its ancestors are in the same group, as
+// e.g. a delete of a directory must be applied after earlier writes to files
+// in it and before later ones. Writes to unrelated paths only share the
+// updates of their common ancestors' sizes, which commute.
+func writeGroups(writes []*putFileWrite) [][]*putFileWrite {
+	// union-find over the indexes of 'writes'
+	parent := make([]int, len(writes))
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	union := func(i, j int) {
+		if ri, rj := find(i), find(j); ri != rj {
+			parent[ri] = rj
+		}
+	}
+	byPath := make(map[string]int)
+	for i, w := range writes {
+		parent[i] = i
+		p := path.Clean("/" + w.path)
+		if j, ok := byPath[p]; ok {
+			union(i, j)
+		}
+		byPath[p] = i
+	}
+	for i, w := range writes {
+		for p := path.Dir(path.Clean("/" + w.path)); p != "/"; p = path.Dir(p) {
+			if j, ok := byPath[p]; ok {
+				union(i, j)
+			}
+		}
+	}
+	var groups [][]*putFileWrite
+	groupIdx := make(map[int]int)
+	for i, w := range writes {
+		root := find(i)
+		idx, ok := groupIdx[root]
+		if !ok {
+			idx = len(groups)
+			groupIdx[root] = idx
+			groups = append(groups, nil)
+		}
+		groups[idx] = append(groups[idx], w)
+	}
+	return groups
+}
+
+// applyWrites applies 'writes' to 'tree' as if they were applied in order.
+// Writes that don't affect each other are applied concurrently; the
+// hashtree's database batches concurrent updates into shared transactions.
+func (d *driver) applyWrites(writes []*putFileWrite, tree hashtree.HashTree) error {
+	for len(writes) > 0 {
+		if isBarrierWrite(writes[0].path) {
+			if err := d.applyWrite(writes[0].path, writes[0].records, tree); err != nil {
+				return err
+			}
+			writes = writes[1:]
+			continue
+		}
+		n := 1
+		for n < len(writes) && !isBarrierWrite(writes[n].path) {
+			n++
+		}
+		limiter := limit.New(d.finishCommitConcurrency)
+		var eg errgroup.Group
+		for _, group := range writeGroups(writes[:n]) {
+			group := group
+			limiter.Acquire()
+			eg.Go(func() error {
+				defer limiter.Release()
+				for _, w := range group {
+					if err := d.applyWrite(w.path, w.records, tree); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}
+		if err := eg.Wait(); err != nil {
+			return err
+		}
+		writes = writes[n:]
+	}
+	return nil
+}
+
+// listWrites calls 'f' with the put-file records under 'prefix' (which is
+// the scratch prefix of 'file') in the order in which they must be applied,
+// 'finishCommitChunkSize' writes at a time. 'h', if set, is updated with each
+// write before 'f' is called. If 'staged' is true, the writes to directories
+// that are being replaced (see ReplaceDirectory) are listed after the others,
+// as they're applied when the commit is finished.
+func (d *driver) listWrites(ctx context.Context, file *pfs.File, prefix string, h hash.Hash, staged bool, f func(writes []*putFileWrite) error) error {
+	var writes []*putFileWrite
+	records := &pfs.PutFileRecords{}
+	opts := &col.Options{etcd.SortByModRevision, etcd.SortAscend, true}
+	cols := []col.Collection{d.putFileRecords}
+	if staged {
+		cols = append(cols, d.stagedPutFileRecords)
+	}
+	for _, c := range cols {
+		if err := c.ReadOnly(ctx).ListPrefix(prefix, records, opts, func(key string) error {
+			if h != nil {
+				data, err := records.Marshal()
+				if err != nil {
+					return err
+				}
+				h.Write([]byte(key))
+				h.Write(data)
+			}
+			writes = append(writes, &putFileWrite{
+				path:    path.Join(file.Path, key),
+				records: proto.Clone(records).(*pfs.PutFileRecords),
+			})
+			if len(writes) < finishCommitChunkSize {
+				return nil
+			}
+			err := f(writes)
+			writes = nil
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+	if len(writes) > 0 {
+		return f(writes)
+	}
+	return nil
+}
+
+// finishTracker reports the progress of FinishCommit on a commit to etcd.
+type finishTracker struct {
+	d        *driver
+	ctx      context.Context
+	mu       sync.Mutex
+	progress *pfs.FinishCommitProgress
+}
+
+// update modifies the tracked progress
+func (t *finishTracker) update(f func(progress *pfs.FinishCommitProgress)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	f(t.progress)
+}
+
+// save writes the tracked progress to etcd. Progress is only informational
+// (and checkpoints are an optimization), so errors are logged rather than
+// returned.
+func (t *finishTracker) save() {
+	t.mu.Lock()
+	progress := proto.Clone(t.progress).(*pfs.FinishCommitProgress)
+	t.mu.Unlock()
+	progress.Updated = types.TimestampNow()
+	if _, err := col.NewSTM(t.ctx, t.d.etcdClient, func(stm col.STM) error {
+		return t.d.finishCommits.ReadWrite(stm).Put(progress.Commit.ID, progress)
+	}); err != nil {
+		logrus.Errorf("could not save the progress of finishing commit %s: %v", progress.Commit.ID, err)
+	}
+}
+
+// run saves the tracked progress every 'finishCommitProgressInterval' until
+// the returned function is called.
+func (t *finishTracker) run() (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(finishCommitProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				t.save()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// buildFinishedTree builds the hashtree of the open commit 'commit' by
+// applying the commit's writes to 'parentTree', and puts it in object
+// storage. If an earlier call was interrupted, it resumes from that call's
+// last checkpoint, unless the commit was modified since.
+func (d *driver) buildFinishedTree(pachClient *client.APIClient, commit *pfs.Commit, parentTree hashtree.HashTree) (*pfs.Object, uint64, error) {
+	ctx := pachClient.Ctx()
+	file := &pfs.File{Commit: commit}
+	prefix, err := d.scratchFilePrefix(file)
+	if err != nil {
+		return nil, 0, err
+	}
+	var filesTotal int64
+	for _, c := range []col.Collection{d.putFileRecords, d.stagedPutFileRecords} {
+		resp, err := d.etcdClient.Get(ctx, c.Path(prefix), etcd.WithPrefix(), etcd.WithCountOnly())
+		if err != nil {
+			return nil, 0, errors.EnsureStack(err)
+		}
+		filesTotal += resp.Count
+	}
+	checkpoint := &pfs.FinishCommitProgress{}
+	if err := d.finishCommits.ReadOnly(ctx).Get(commit.ID, checkpoint); err != nil {
+		if !col.IsErrNotFound(err) {
+			return nil, 0, err
+		}
+		checkpoint = nil
+	}
+	t := &finishTracker{
+		d:   d,
+		ctx: ctx,
+		progress: &pfs.FinishCommitProgress{
+			Commit:     commit,
+			Phase:      pfs.FinishCommitProgress_APPLYING,
+			FilesTotal: filesTotal,
+			Started:    types.TimestampNow(),
+		},
+	}
+	if checkpoint != nil {
+		// Keep the checkpoint until it's resumed or replaced
+		t.progress.FilesApplied = checkpoint.CheckpointFiles
+		t.progress.CheckpointTree = checkpoint.CheckpointTree
+		t.progress.CheckpointFiles = checkpoint.CheckpointFiles
+		t.progress.CheckpointHash = checkpoint.CheckpointHash
+		t.progress.Tree = checkpoint.Tree
+		t.progress.SizeBytes = checkpoint.SizeBytes
+	}
+	t.save()
+	stop := t.run()
+	defer stop()
+	if checkpoint != nil && (checkpoint.CheckpointTree != nil || checkpoint.Tree != nil) {
+		treeRef, size, err := d.resumeFinishedTree(pachClient, file, prefix, parentTree, t, checkpoint)
+		if err == nil {
+			return treeRef, size, nil
+		}
+		if !errors.Is(err, errStaleCheckpoint) {
+			return nil, 0, err
+		}
+		logrus.Infof("restarting FinishCommit on commit %s, as %v", commit.ID, err)
+		t.update(func(progress *pfs.FinishCommitProgress) {
+			progress.FilesApplied = 0
+			progress.CheckpointTree = nil
+			progress.CheckpointFiles = 0
+			progress.CheckpointHash = nil
+			progress.Tree = nil
+			progress.SizeBytes = 0
+		})
+	}
+	return d.resumeFinishedTree(pachClient, file, prefix, parentTree, t, nil)
+}
+
+// resumeFinishedTree builds the hashtree of 'file.Commit', starting from
+// 'checkpoint' if it's set, or from 'parentTree' otherwise. It returns
+// errStaleCheckpoint if the commit's writes have changed since 'checkpoint'.
+func (d *driver) resumeFinishedTree(pachClient *client.APIClient, file *pfs.File, prefix string, parentTree hashtree.HashTree, t *finishTracker, checkpoint *pfs.FinishCommitProgress) (_ *pfs.Object, _ uint64, retErr error) {
+	var tree hashtree.HashTree
+	var skip int64
+	var err error
+	switch {
+	case checkpoint == nil:
+		tree, err = parentTree.Copy()
+	case checkpoint.Tree != nil:
+		// The tree was complete, so it's only used if no writes were added
+		skip = checkpoint.CheckpointFiles
+	default:
+		tree, err = hashtree.GetHashTreeObject(pachClient, d.storageRoot, checkpoint.CheckpointTree)
+		skip = checkpoint.CheckpointFiles
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		if tree != nil {
+			destroyHashtree(tree)
+		}
+	}()
+
+	// The hash of the writes listed so far, which is saved with checkpoints
+	h := sha256.New()
+	var listed int64
+	lastCheckpoint := time.Now()
+	if err := d.listWrites(pachClient.Ctx(), file, prefix, h, true, func(writes []*putFileWrite) error {
+		listed += int64(len(writes))
+		if listed <= skip {
+			// These writes are in the checkpoint
+			if listed == skip && !bytes.Equal(h.Sum(nil), checkpoint.CheckpointHash) {
+				return errStaleCheckpoint
+			}
+			return nil
+		}
+		if tree == nil {
+			// writes were added after the complete tree was built
+			return errStaleCheckpoint
+		}
+		if skip > listed-int64(len(writes)) {
+			// The checkpoint ends in the middle of a chunk, which means that
+			// the chunks have changed since it was written
+			return errStaleCheckpoint
+		}
+		if err := d.applyWrites(writes, tree); err != nil {
+			return err
+		}
+		t.update(func(progress *pfs.FinishCommitProgress) {
+			progress.FilesApplied = listed
+		})
+		if time.Since(lastCheckpoint) < finishCommitCheckpointInterval {
+			return nil
+		}
+		// The tree is put in object storage without hashing it, as
+		// checkpointed trees are never read directly
+		treeRef, err := hashtree.PutHashTree(pachClient, tree)
+		if err != nil {
+			return err
+		}
+		t.update(func(progress *pfs.FinishCommitProgress) {
+			progress.CheckpointTree = treeRef
+			progress.CheckpointFiles = listed
+			progress.CheckpointHash = h.Sum(nil)
+		})
+		t.save()
+		lastCheckpoint = time.Now()
+		return nil
+	}); err != nil {
+		return nil, 0, err
+	}
+	if listed < skip {
+		return nil, 0, errStaleCheckpoint
+	}
+	if tree == nil {
+		t.update(func(progress *pfs.FinishCommitProgress) {
+			progress.FilesApplied = listed
+		})
+		return checkpoint.Tree, checkpoint.SizeBytes, nil
+	}
+
+	t.update(func(progress *pfs.FinishCommitProgress) {
+		progress.FilesApplied = listed
+		progress.Phase = pfs.FinishCommitProgress_HASHING
+	})
+	t.save()
+	if err := tree.Hash(); err != nil {
+		return nil, 0, err
+	}
+	t.update(func(progress *pfs.FinishCommitProgress) {
+		progress.Phase = pfs.FinishCommitProgress_UPLOADING
+	})
+	t.save()
+	treeRef, err := hashtree.PutHashTree(pachClient, tree)
+	if err != nil {
+		return nil, 0, err
+	}
+	size := uint64(tree.FSSize())
+	t.update(func(progress *pfs.FinishCommitProgress) {
+		progress.Tree = treeRef
+		progress.SizeBytes = size
+		progress.CheckpointTree = nil
+		progress.CheckpointFiles = listed
+		progress.CheckpointHash = h.Sum(nil)
+	})
+	t.save()
+	return treeRef, size, nil
+}
+
+// finishCommitStatus returns the progress of FinishCommit on 'commit'.
+func (d *driver) finishCommitStatus(pachClient *client.APIClient, commit *pfs.Commit) (*pfs.FinishCommitProgress, error) {
+	if commit == nil || commit.Repo == nil {
+		return nil, errors.New("commit and its repo cannot be nil")
+	}
+	commitInfo, err := d.inspectCommit(pachClient, commit, pfs.CommitState_STARTED)
+	if err != nil {
+		return nil, err
+	}
+	if commitInfo.Finished != nil {
+		return &pfs.FinishCommitProgress{
+			Commit:  commitInfo.Commit,
+			Phase:   pfs.FinishCommitProgress_FINISHED,
+			Updated: commitInfo.Finished,
+		}, nil
+	}
+	progress := &pfs.FinishCommitProgress{}
+	if err := d.finishCommits.ReadOnly(pachClient.Ctx()).Get(commitInfo.Commit.ID, progress); err != nil {
+		if !col.IsErrNotFound(err) {
+			return nil, err
+		}
+		return &pfs.FinishCommitProgress{Commit: commitInfo.Commit}, nil
+	}
+	return progress, nil
+}
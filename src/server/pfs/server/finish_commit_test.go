@@ -0,0 +1,40 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func writePaths(groups [][]*putFileWrite) [][]string {
+	var result [][]string
+	for _, group := range groups {
+		var paths []string
+		for _, w := range group {
+			paths = append(paths, w.path)
+		}
+		result = append(result, paths)
+	}
+	return result
+}
+
+func TestWriteGroups(t *testing.T) {
+	var writes []*putFileWrite
+	for _, p := range []string{"/a/1", "/b/1", "/a", "/c/d/1", "/a/2", "/c/e", "/c/d"} {
+		writes = append(writes, &putFileWrite{path: p, records: &pfs.PutFileRecords{}})
+	}
+	// Writes to a path and its ancestors keep their order, and other writes
+	// are in separate groups
+	require.Equal(t, [][]string{
+		{"/a/1", "/a", "/a/2"},
+		{"/b/1"},
+		{"/c/d/1", "/c/d"},
+		{"/c/e"},
+	}, writePaths(writeGroups(writes)))
+
+	require.True(t, isBarrierWrite("/"))
+	require.True(t, isBarrierWrite(""))
+	require.True(t, isBarrierWrite("/a/*"))
+	require.False(t, isBarrierWrite("/a/b"))
+}
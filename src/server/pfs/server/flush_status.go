@@ -0,0 +1,68 @@
+package server
+
+import (
+	"path"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsdb"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/watch"
+)
+
+// flushStatus returns the status of the job whose output commit is 'commit',
+// once the job has finished, or nil if 'commit' isn't the output commit of a
+// job (e.g. it's a stats commit).
+func (d *driver) flushStatus(pachClient *client.APIClient, commit *pfs.Commit) (*pfs.FlushStatus, error) {
+	jobs := ppsdb.Jobs(d.etcdClient, path.Join(d.env.EtcdPrefix, d.env.PPSEtcdPrefix)).ReadOnly(pachClient.Ctx())
+	var jobID string
+	jobPtr := &pps.EtcdJobInfo{}
+	if err := jobs.GetByIndex(ppsdb.JobsOutputIndex, commit, jobPtr, col.DefaultOptions, func(id string) error {
+		jobID = id
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if jobID == "" {
+		return nil, nil
+	}
+	if !ppsutil.IsTerminal(jobPtr.State) {
+		// An output commit can be finished before its job's state is updated
+		// (e.g. when the job is killed), so wait for the job too
+		watcher, err := jobs.WatchOne(jobID)
+		if err != nil {
+			return nil, err
+		}
+		defer watcher.Close()
+	loop:
+		for {
+			ev, ok := <-watcher.Watch()
+			if !ok {
+				return nil, errors.Errorf("the stream for job updates closed unexpectedly")
+			}
+			switch ev.Type {
+			case watch.EventError:
+				return nil, ev.Err
+			case watch.EventDelete:
+				return nil, nil // the job was deleted, so there's no status
+			case watch.EventPut:
+				var id string
+				if err := ev.Unmarshal(&id, jobPtr); err != nil {
+					return nil, err
+				}
+				if ppsutil.IsTerminal(jobPtr.State) {
+					break loop
+				}
+			}
+		}
+	}
+	return &pfs.FlushStatus{
+		Pipeline: jobPtr.Pipeline.Name,
+		JobID:    jobID,
+		State:    jobPtr.State.String(),
+		Reason:   jobPtr.Reason,
+	}, nil
+}
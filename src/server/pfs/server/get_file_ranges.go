@@ -0,0 +1,72 @@
+package server
+
+import (
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
+)
+
+// maxGetFileRanges is the most ranges that a single GetFile call can read (see
+// GetFileRequest.Ranges)
+const maxGetFileRanges = 1000
+
+// validateByteRanges returns an error if any of 'ranges' is empty or isn't
+// within a file of 'size' bytes.
+func validateByteRanges(ranges []*pfs.ByteRange, size uint64) error {
+	if len(ranges) > maxGetFileRanges {
+		return errors.Errorf("at most %d ranges can be read at once, but %d were requested", maxGetFileRanges, len(ranges))
+	}
+	for _, r := range ranges {
+		if r == nil {
+			return errors.New("ranges cannot be nil")
+		}
+		if r.Lower >= r.Upper {
+			return errors.Errorf("range [%d, %d) is empty", r.Lower, r.Upper)
+		}
+		if r.Upper > size {
+			return errors.Errorf("range [%d, %d) is beyond the end of the file (%d bytes)", r.Lower, r.Upper, size)
+		}
+	}
+	return nil
+}
+
+// getFileRanges streams the bytes of each of 'request.Ranges' of
+// 'request.File' to 'server' (see GetFileRequest.Ranges).
+func (a *apiServer) getFileRanges(pachClient *client.APIClient, request *pfs.GetFileRequest, server pfs.API_GetFileServer) error {
+	if request.OffsetBytes != 0 || request.SizeBytes != 0 {
+		return errors.New("offset_bytes and size_bytes can't be set along with ranges")
+	}
+	if request.Delimiter != pfs.Delimiter_NONE || request.OffsetRecords != 0 || request.SizeRecords != 0 {
+		return errors.New("files can't be read by record and by range at once")
+	}
+	if hashtree.IsGlob(request.File.Path) {
+		return errors.Errorf("ranges can only be read from a single file, but %q is a glob pattern", request.File.Path)
+	}
+	// Validate every range before sending any bytes, so that the result is
+	// either complete or an error
+	fileInfo, err := a.driver.inspectFile(pachClient, request.File)
+	if err != nil {
+		return err
+	}
+	if fileInfo.FileType != pfs.FileType_FILE {
+		return errors.Errorf("ranges can only be read from a file, but %q isn't one", request.File.Path)
+	}
+	if err := validateByteRanges(request.Ranges, fileInfo.SizeBytes); err != nil {
+		return err
+	}
+	// Read every range from the commit that was inspected, rather than from
+	// the head of a branch that may move between ranges
+	file := client.NewFile(request.File.Commit.Repo.Name, fileInfo.File.Commit.ID, request.File.Path)
+	for _, r := range request.Ranges {
+		content, err := a.driver.getFile(pachClient, file, int64(r.Lower), int64(r.Upper-r.Lower))
+		if err != nil {
+			return err
+		}
+		if err := grpcutil.WriteToStreamingBytesServer(content, server); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestValidateByteRanges(t *testing.T) {
+	require.NoError(t, validateByteRanges([]*pfs.ByteRange{{Lower: 0, Upper: 10}}, 10))
+	// Ranges may overlap, and be in any order
+	require.NoError(t, validateByteRanges([]*pfs.ByteRange{
+		{Lower: 5, Upper: 8},
+		{Lower: 0, Upper: 6},
+		{Lower: 9, Upper: 10},
+	}, 10))
+
+	require.YesError(t, validateByteRanges([]*pfs.ByteRange{{Lower: 3, Upper: 3}}, 10))
+	require.YesError(t, validateByteRanges([]*pfs.ByteRange{{Lower: 4, Upper: 3}}, 10))
+	require.YesError(t, validateByteRanges([]*pfs.ByteRange{{Lower: 5, Upper: 11}}, 10))
+	require.YesError(t, validateByteRanges([]*pfs.ByteRange{{Lower: 0, Upper: 1}}, 0))
+	require.YesError(t, validateByteRanges([]*pfs.ByteRange{nil}, 10))
+	tooMany := make([]*pfs.ByteRange, maxGetFileRanges+1)
+	for i := range tooMany {
+		tooMany[i] = &pfs.ByteRange{Lower: 0, Upper: 1}
+	}
+	require.YesError(t, validateByteRanges(tooMany, 10))
+}
@@ -0,0 +1,238 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
+)
+
+// trimReader reads the content of 'r' except for its last 'n' bytes, which
+// GetFile uses to drop the footer of a split file from its records.
+type trimReader struct {
+	r *bufio.Reader
+	n int
+}
+
+// trimReadSize is the maximum number of bytes that a trimReader returns per
+// Read call
+const trimReadSize = 64 * 1024
+
+func newTrimReader(r io.Reader, n int) io.Reader {
+	return &trimReader{
+		r: bufio.NewReaderSize(r, n+trimReadSize),
+		n: n,
+	}
+}
+
+func (t *trimReader) Read(p []byte) (int, error) {
+	if len(p) > trimReadSize {
+		p = p[:trimReadSize]
+	}
+	buf, err := t.r.Peek(t.n + len(p))
+	if len(buf) <= t.n {
+		// Peek only returns fewer bytes than requested along with an error,
+		// which is io.EOF once only the last 'n' bytes are left
+		return 0, err
+	}
+	n := copy(p, buf[:len(buf)-t.n])
+	if _, err := t.r.Discard(n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// readRecords calls 'f' on each record of 'r', as delimited by 'delimiter'.
+// Records are returned as they should be written out: lines keep their
+// newline, CSV rows are re-encoded and JSON values are followed by a newline.
+func readRecords(r io.Reader, delimiter pfs.Delimiter, f func([]byte) error) error {
+	bufioR := bufio.NewReader(r)
+	switch delimiter {
+	case pfs.Delimiter_LINE, pfs.Delimiter_SQL:
+		// The records of a split SQL file are the rows between its header and
+		// footer, one per line
+		for {
+			line, err := bufioR.ReadBytes('\n')
+			if len(line) > 0 {
+				if err := f(line); err != nil {
+					return err
+				}
+			}
+			if errors.Is(err, io.EOF) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+		}
+	case pfs.Delimiter_JSON:
+		decoder := json.NewDecoder(bufioR)
+		for {
+			var value json.RawMessage
+			if err := decoder.Decode(&value); errors.Is(err, io.EOF) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if err := f(append(value, '\n')); err != nil {
+				return err
+			}
+		}
+	case pfs.Delimiter_CSV:
+		csvReader := csv.NewReader(bufioR)
+		csvReader.FieldsPerRecord = -1 // as in PutFileSplit
+		var buf bytes.Buffer
+		csvWriter := csv.NewWriter(&buf)
+		for {
+			row, err := csvReader.Read()
+			if errors.Is(err, io.EOF) {
+				return nil
+			} else if err != nil {
+				return errors.Wrapf(err, "error parsing csv record")
+			}
+			buf.Reset()
+			if err := csvWriter.Write(row); err != nil {
+				return err
+			}
+			if csvWriter.Flush(); csvWriter.Error() != nil {
+				return csvWriter.Error()
+			}
+			if err := f(buf.Bytes()); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.Errorf("unrecognized delimiter %s", delimiter.String())
+	}
+}
+
+// errEnoughRecords stops readRecords once GetFile has read all of the records
+// that were requested
+var errEnoughRecords = errors.New("enough records")
+
+// writeRecords writes records [offset, offset+size) of 'r' to 'w' (all
+// records from 'offset' on if size is 0)
+func writeRecords(w io.Writer, r io.Reader, delimiter pfs.Delimiter, offset, size int64) error {
+	var i int64
+	err := readRecords(r, delimiter, func(record []byte) error {
+		defer func() { i++ }()
+		if i < offset {
+			return nil
+		}
+		if size > 0 && i >= offset+size {
+			return errEnoughRecords
+		}
+		_, err := w.Write(record)
+		return err
+	})
+	if errors.Is(err, errEnoughRecords) {
+		return nil
+	}
+	return err
+}
+
+// splitFileHeaderFooter returns the header and footer of the split file at
+// 'file' (nil if 'file' isn't a split file with a header or footer). Only
+// commits that use the old hashtree format store headers and footers.
+func (d *driver) splitFileHeaderFooter(pachClient *client.APIClient, commitInfo *pfs.CommitInfo, file *pfs.File) ([]byte, []byte, error) {
+	if provenantOnInput(commitInfo.Provenance) && commitInfo.Tree == nil {
+		return nil, nil, nil
+	}
+	tree, err := d.getTreeForFile(pachClient, client.NewFile(file.Commit.Repo.Name, file.Commit.ID, ""))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer destroyHashtree(tree)
+	node, err := tree.Get(file.Path)
+	if err != nil {
+		if hashtree.Code(err) == hashtree.PathNotFound {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	if node.DirNode == nil || node.DirNode.Shared == nil {
+		return nil, nil, nil
+	}
+	getObject := func(object *pfs.Object) ([]byte, error) {
+		if object == nil {
+			return nil, nil
+		}
+		var buf bytes.Buffer
+		if err := pachClient.GetObject(object.Hash, &buf); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	header, err := getObject(node.DirNode.Shared.Header)
+	if err != nil {
+		return nil, nil, err
+	}
+	footer, err := getObject(node.DirNode.Shared.Footer)
+	if err != nil {
+		return nil, nil, err
+	}
+	return header, footer, nil
+}
+
+// getFileRecords returns records [offset, offset+size) of 'file', as
+// delimited by 'delimiter'. If 'file' is a directory (e.g. a file created by
+// PutFileSplit), its children's records are read, and its header and footer
+// are returned around the records. The caller must close the returned reader.
+func (d *driver) getFileRecords(pachClient *client.APIClient, file *pfs.File, delimiter pfs.Delimiter, offset, size int64) (io.ReadCloser, error) {
+	if offset < 0 || size < 0 {
+		return nil, errors.Errorf("offset_records and size_records can't be negative")
+	}
+	if err := validateFile(file); err != nil {
+		return nil, err
+	}
+	bodyFile := file
+	var header, footer []byte
+	if !hashtree.IsGlob(file.Path) {
+		fileInfo, err := d.inspectFile(pachClient, file)
+		if err != nil {
+			return nil, err
+		}
+		if fileInfo.FileType == pfs.FileType_DIR {
+			bodyFile = client.NewFile(file.Commit.Repo.Name, file.Commit.ID, path.Join(file.Path, "*"))
+			commitInfo, err := d.inspectCommit(pachClient, file.Commit, pfs.CommitState_STARTED)
+			if err != nil {
+				return nil, err
+			}
+			if header, footer, err = d.splitFileHeaderFooter(pachClient, commitInfo, file); err != nil {
+				return nil, err
+			}
+		}
+	}
+	r, err := d.getFile(pachClient, bodyFile, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	// getFile returns the content of a split file's children between its
+	// header and footer, which aren't records
+	if _, err := io.CopyN(ioutil.Discard, r, int64(len(header))); err != nil {
+		return nil, err
+	}
+	r = newTrimReader(r, len(footer))
+	pr, pw := io.Pipe()
+	go func() {
+		err := func() error {
+			if _, err := pw.Write(header); err != nil {
+				return err
+			}
+			if err := writeRecords(pw, r, delimiter, offset, size); err != nil {
+				return err
+			}
+			_, err := pw.Write(footer)
+			return err
+		}()
+		pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestTrimReader(t *testing.T) {
+	data, err := ioutil.ReadAll(newTrimReader(strings.NewReader("records\nfooter\n"), len("footer\n")))
+	require.NoError(t, err)
+	require.Equal(t, "records\n", string(data))
+
+	data, err = ioutil.ReadAll(newTrimReader(strings.NewReader("records\n"), 0))
+	require.NoError(t, err)
+	require.Equal(t, "records\n", string(data))
+
+	// Larger than a single read
+	content := strings.Repeat("x", 3*trimReadSize)
+	data, err = ioutil.ReadAll(newTrimReader(strings.NewReader(content+"footer"), len("footer")))
+	require.NoError(t, err)
+	require.Equal(t, content, string(data))
+}
+
+func TestWriteRecords(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeRecords(&buf, strings.NewReader("a\nb\nc\nd"), pfs.Delimiter_LINE, 1, 2))
+	require.Equal(t, "b\nc\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeRecords(&buf, strings.NewReader("a\nb\nc\nd"), pfs.Delimiter_LINE, 2, 0))
+	require.Equal(t, "c\nd", buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeRecords(&buf, strings.NewReader(`{"a":1} {"b":2}`+"\n"+`{"c":3}`), pfs.Delimiter_JSON, 1, 1))
+	require.Equal(t, "{\"b\":2}\n", buf.String())
+
+	// CSV records may contain newlines
+	buf.Reset()
+	require.NoError(t, writeRecords(&buf, strings.NewReader("1,\"two\nlines\"\n3,4\n"), pfs.Delimiter_CSV, 0, 1))
+	require.Equal(t, "1,\"two\nlines\"\n", buf.String())
+
+	require.YesError(t, writeRecords(&buf, strings.NewReader("a"), pfs.Delimiter_NONE, 0, 0))
+}
@@ -8,6 +8,7 @@ import (
 	"math"
 	"path"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,6 +19,8 @@ import (
 	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
 	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/residency"
+	"github.com/pachyderm/pachyderm/src/client/pkg/tenant"
 	"github.com/pachyderm/pachyderm/src/client/pkg/tracing"
 	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
 	"github.com/pachyderm/pachyderm/src/server/pkg/log"
@@ -49,6 +52,13 @@ type objBlockAPIServer struct {
 	log.Logger
 	dir       string
 	objClient obj.Client
+	// The clients of the buckets of each data residency, in which the blocks
+	// of objects put with a residency are stored
+	residencyClients map[string]obj.Client
+	// The client that blocks in the default bucket are read with: objClient,
+	// wrapped to read from the default bucket's replica in this server's
+	// region if it has one
+	blockReadClient obj.Client
 
 	// cache
 	objectCache     *groupcache.Group
@@ -91,6 +101,7 @@ func newObjBlockAPIServer(dir string, cacheBytes int64, etcdAddress string, objC
 		Logger:           log.NewLogger("pfs.BlockAPI.Obj"),
 		dir:              dir,
 		objClient:        objClient,
+		blockReadClient:  objClient,
 		objectIndexes:    make(map[string]*pfsclient.ObjectIndex),
 		objectCacheBytes: oneCacheShare * objectCacheShares,
 	}
@@ -298,10 +309,14 @@ func (s *objBlockAPIServer) PutObjectSplit(server pfsclient.ObjectAPI_PutObjectS
 func (s *objBlockAPIServer) putObject(ctx context.Context, dataReader io.Reader, f func(io.Writer, io.Reader) (int64, error)) (_ *pfsclient.Object, retErr error) {
 	hash := pfsclient.NewHash()
 	r := io.TeeReader(dataReader, hash)
-	block := &pfsclient.Block{Hash: uuid.NewWithoutDashes()}
+	block := tenant.NewBlock(residency.FromContext(ctx), tenant.FromObjectContext(ctx))
+	blockClient, err := s.blockClient(block)
+	if err != nil {
+		return nil, err
+	}
 	var size int64
 	if err := func() (retErr error) {
-		w, err := s.objClient.Writer(ctx, s.blockPath(block))
+		w, err := blockClient.Writer(ctx, s.blockPath(block))
 		if err != nil {
 			return err
 		}
@@ -316,7 +331,7 @@ func (s *objBlockAPIServer) putObject(ctx context.Context, dataReader io.Reader,
 		// We throw away the delete error state here because the original error is what should be communicated
 		// back and we do not know the cause of the original error. This is just an attempt to clean up
 		// unused storage in the case that the block was actually written to object storage.
-		s.objClient.Delete(ctx, s.blockPath(block))
+		blockClient.Delete(ctx, s.blockPath(block))
 		return nil, err
 	}
 	object := &pfsclient.Object{Hash: pfsclient.EncodeHash(hash.Sum(nil))}
@@ -327,7 +342,7 @@ func (s *objBlockAPIServer) putObject(ctx context.Context, dataReader io.Reader,
 	}
 	if resp.Exists {
 		// the object already exists so we delete the block we put
-		if err := s.objClient.Delete(ctx, s.blockPath(block)); err != nil {
+		if err := blockClient.Delete(ctx, s.blockPath(block)); err != nil {
 			return nil, err
 		}
 	} else {
@@ -356,12 +371,22 @@ func (s *objBlockAPIServer) PutObjects(server pfsclient.ObjectAPI_PutObjectsServ
 	if request.Block == nil {
 		return errors.Errorf("first put objects request should include a block")
 	}
+	if r := residency.FromContext(server.Context()); residency.OfBlock(request.Block) != r {
+		return errors.Errorf("block %s must be in the request's residency (%q)", request.Block.Hash, r)
+	}
+	if t := tenant.FromObjectContext(server.Context()); t != "" && tenant.OfBlock(request.Block) != t {
+		return errors.Errorf("block %s must be under the request's tenant's prefix (%q)", request.Block.Hash, tenant.Prefix(t))
+	}
+	blockClient, err := s.blockClient(request.Block)
+	if err != nil {
+		return err
+	}
 
 	blockPath := s.blockPath(request.Block)
 	putObjectReader := &putObjectReader{
 		server: server,
 	}
-	w, err := s.objClient.Writer(server.Context(), blockPath)
+	w, err := blockClient.Writer(server.Context(), blockPath)
 	if err != nil {
 		return err
 	}
@@ -374,7 +399,7 @@ func (s *objBlockAPIServer) PutObjects(server pfsclient.ObjectAPI_PutObjectsServ
 	defer grpcutil.PutBuffer(buf)
 	_, err = io.CopyBuffer(w, putObjectReader, buf)
 	if err != nil {
-		s.objClient.Delete(server.Context(), blockPath)
+		blockClient.Delete(server.Context(), blockPath)
 		return err
 	}
 	return nil
@@ -414,8 +439,12 @@ func (s *objBlockAPIServer) GetObject(request *pfsclient.Object, getObjectServer
 	if (objectSize) >= uint64(s.objectCacheBytes/maxCachedObjectDenom) {
 		// The object is a substantial portion of the available cache space so
 		// we bypass the cache and stream it directly out of the underlying store.
+		blockClient, err := s.blockClient(objectInfo.BlockRef.Block)
+		if err != nil {
+			return err
+		}
 		blockPath := s.blockPath(objectInfo.BlockRef.Block)
-		r, err := s.objClient.Reader(getObjectServer.Context(), blockPath, objectInfo.BlockRef.Range.Lower, objectSize)
+		r, err := blockClient.Reader(getObjectServer.Context(), blockPath, objectInfo.BlockRef.Range.Lower, objectSize)
 		if err != nil {
 			return err
 		}
@@ -469,8 +498,12 @@ func (s *objBlockAPIServer) GetObjects(request *pfsclient.GetObjectsRequest, get
 			readSize = size
 		}
 		if request.TotalSize >= uint64(s.objectCacheBytes/maxCachedObjectDenom) {
+			blockClient, err := s.blockClient(objectInfo.BlockRef.Block)
+			if err != nil {
+				return err
+			}
 			blockPath := s.blockPath(objectInfo.BlockRef.Block)
-			r, err := s.objClient.Reader(getObjectsServer.Context(), blockPath, objectInfo.BlockRef.Range.Lower+offset, readSize)
+			r, err := blockClient.Reader(getObjectsServer.Context(), blockPath, objectInfo.BlockRef.Range.Lower+offset, readSize)
 			if err != nil {
 				return err
 			}
@@ -661,8 +694,12 @@ func (s *objBlockAPIServer) PutBlock(putBlockServer pfsclient.ObjectAPI_PutBlock
 	if request.Block == nil {
 		return errors.Errorf("block cannot be nil")
 	}
+	blockClient, err := s.blockClient(request.Block)
+	if err != nil {
+		return err
+	}
 	blockPath := s.blockPath(request.Block)
-	w, err := s.objClient.Writer(putBlockServer.Context(), blockPath)
+	w, err := blockClient.Writer(putBlockServer.Context(), blockPath)
 	if err != nil {
 		return err
 	}
@@ -689,8 +726,12 @@ func (s *objBlockAPIServer) PutBlock(putBlockServer pfsclient.ObjectAPI_PutBlock
 func (s *objBlockAPIServer) GetBlock(request *pfsclient.GetBlockRequest, getBlockServer pfsclient.ObjectAPI_GetBlockServer) (retErr error) {
 	func() { s.Log(request, nil, nil, 0) }()
 	defer func(start time.Time) { s.Log(request, nil, retErr, time.Since(start)) }(time.Now())
+	blockClient, err := s.blockClient(request.Block)
+	if err != nil {
+		return err
+	}
 	blockPath := s.blockPath(request.Block)
-	r, err := s.objClient.Reader(getBlockServer.Context(), blockPath, 0, 0)
+	r, err := blockClient.Reader(getBlockServer.Context(), blockPath, 0, 0)
 	if err != nil {
 		return err
 	}
@@ -721,8 +762,12 @@ func (s *objBlockAPIServer) GetBlocks(request *pfsclient.GetBlocksRequest, getBl
 			readSize = size
 		}
 		if request.TotalSize >= uint64(s.objectCacheBytes/maxCachedObjectDenom) {
+			blockClient, err := s.blockClient(blockRef.Block)
+			if err != nil {
+				return err
+			}
 			blockPath := s.blockPath(blockRef.Block)
-			r, err := s.objClient.Reader(getBlockServer.Context(), blockPath, blockRef.Range.Lower+offset, readSize)
+			r, err := blockClient.Reader(getBlockServer.Context(), blockPath, blockRef.Range.Lower+offset, readSize)
 			if err != nil {
 				return err
 			}
@@ -764,10 +809,21 @@ func (s *objBlockAPIServer) ListBlock(request *pfsclient.ListBlockRequest, listB
 	defer func(start time.Time) {
 		s.Log(request, fmt.Sprintf("stream containing %d Blocks", sent), retErr, time.Since(start))
 	}(time.Now())
-	return s.objClient.Walk(listBlockServer.Context(), s.blockDir(), func(key string) error {
+	if err := s.objClient.Walk(listBlockServer.Context(), s.blockDir(), func(key string) error {
 		sent++
 		return listBlockServer.Send(client.NewBlock(filepath.Base(key)))
-	})
+	}); err != nil {
+		return err
+	}
+	for _, r := range s.residencies() {
+		if err := s.residencyClients[r].Walk(listBlockServer.Context(), filepath.Join(s.blockDir(), r), func(key string) error {
+			sent++
+			return listBlockServer.Send(client.NewBlock(path.Join(r, filepath.Base(key))))
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *objBlockAPIServer) isNotFoundErr(err error) bool {
@@ -804,8 +860,12 @@ func (s *objBlockAPIServer) DeleteObjects(ctx context.Context, request *pfsclien
 			}
 
 			if objectInfo != nil && objectInfo.BlockRef != nil && objectInfo.BlockRef.Block != nil {
+				blockClient, err := s.blockClient(objectInfo.BlockRef.Block)
+				if err != nil {
+					return err
+				}
 				blockPath := s.blockPath(objectInfo.BlockRef.Block)
-				if err := s.objClient.Delete(ctx, blockPath); err != nil && !s.isNotFoundErr(err) {
+				if err := blockClient.Delete(ctx, blockPath); err != nil && !s.isNotFoundErr(err) {
 					return err
 				}
 			}
@@ -976,6 +1036,11 @@ func (s *objBlockAPIServer) compact(ctx context.Context) (retErr error) {
 				if err := s.readProto(ctx, name, blockRef); err != nil {
 					return err
 				}
+				if residency.OfBlock(blockRef.Block) != "" {
+					// The compacted block is in the default bucket, so objects
+					// with a residency are left where they are
+					return nil
+				}
 				blockPath := s.blockPath(blockRef.Block)
 				r, err := s.objClient.Reader(ctx, blockPath, blockRef.Range.Lower, blockRef.Range.Upper-blockRef.Range.Lower)
 				if err != nil {
@@ -1151,7 +1216,12 @@ func (s *objBlockAPIServer) blockGetter(ctx groupcache.Context, key string, dest
 	}
 	// use context.Background() for tracing, as groupcache may not necessarily do
 	// this inline with any RPC
-	return s.readObj(context.Background(), s.blockPath(client.NewBlock(fields[0])), lower, upper-lower, dest)
+	block := client.NewBlock(fields[0])
+	blockClient, err := s.blockClient(block)
+	if err != nil {
+		return err
+	}
+	return s.readObj(context.Background(), blockClient, s.blockPath(block), lower, upper-lower, dest)
 }
 
 func (s *objBlockAPIServer) objectGetter(ctx groupcache.Context, key string, dest groupcache.Sink) error {
@@ -1266,12 +1336,12 @@ func (s *objBlockAPIServer) objectInfoGetter(ctx groupcache.Context, key string,
 	return errors.Errorf("objectInfoGetter: object %s not found", object.Hash)
 }
 
-func (s *objBlockAPIServer) readObj(ctx context.Context, path string, offset uint64, size uint64, dest groupcache.Sink) (retErr error) {
+func (s *objBlockAPIServer) readObj(ctx context.Context, objClient obj.Client, path string, offset uint64, size uint64, dest groupcache.Sink) (retErr error) {
 	var reader io.ReadCloser
 	var err error
 	backoff.RetryNotify(func() error {
-		reader, err = s.objClient.Reader(ctx, path, offset, size)
-		if err != nil && obj.IsRetryable(s.objClient, err) {
+		reader, err = objClient.Reader(ctx, path, offset, size)
+		if err != nil && obj.IsRetryable(objClient, err) {
 			return err
 		}
 		return nil
@@ -1299,7 +1369,11 @@ func (s *objBlockAPIServer) readObj(ctx context.Context, path string, offset uin
 }
 
 func (s *objBlockAPIServer) readBlockRef(ctx context.Context, blockRef *pfsclient.BlockRef, dest groupcache.Sink) error {
-	return s.readObj(ctx, s.blockPath(blockRef.Block), blockRef.Range.Lower, blockRef.Range.Upper-blockRef.Range.Lower, dest)
+	blockClient, err := s.blockClient(blockRef.Block)
+	if err != nil {
+		return err
+	}
+	return s.readObj(ctx, blockClient, s.blockPath(blockRef.Block), blockRef.Range.Lower, blockRef.Range.Upper-blockRef.Range.Lower, dest)
 }
 
 func (s *objBlockAPIServer) getObjectIndex(prefix string) (*pfsclient.ObjectIndex, bool) {
@@ -1384,6 +1458,31 @@ func (s *objBlockAPIServer) blockPath(block *pfsclient.Block) string {
 	return filepath.Join(s.blockDir(), block.Hash)
 }
 
+// blockClient returns the client of the bucket that 'block' is stored in: the
+// bucket of its residency, if it has one, or the default bucket (which it's
+// read from through its local replica, if there is one)
+func (s *objBlockAPIServer) blockClient(block *pfsclient.Block) (obj.Client, error) {
+	r := residency.OfBlock(block)
+	if r == "" {
+		return s.blockReadClient, nil
+	}
+	objClient, ok := s.residencyClients[r]
+	if !ok {
+		return nil, errors.Errorf("no bucket is configured for residency %q (of block %s)", r, block.Hash)
+	}
+	return objClient, nil
+}
+
+// residencies returns the sorted names of the residencies that have buckets
+func (s *objBlockAPIServer) residencies() []string {
+	var result []string
+	for r := range s.residencyClients {
+		result = append(result, r)
+	}
+	sort.Strings(result)
+	return result
+}
+
 func (s *objBlockAPIServer) objectDir() string {
 	return filepath.Join(s.dir, "object")
 }
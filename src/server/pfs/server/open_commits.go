@@ -0,0 +1,246 @@
+package server
+
+import (
+	"path"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/dlock"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
+	txnenv "github.com/pachyderm/pachyderm/src/server/pkg/transactionenv"
+)
+
+const (
+	// openCommitsLockPath is the dlock held by the pachd that abandons open
+	// commits, so that each commit is only abandoned once
+	openCommitsLockPath = "pfs-open-commits-lock"
+	// openCommitsCheckInterval is how often open commits are checked against
+	// their repos' open commit policies
+	openCommitsCheckInterval = time.Minute
+)
+
+func validateOpenCommitPolicy(policy *pfs.OpenCommitPolicy) error {
+	if policy.MaxOpen == nil {
+		return errors.New("an open commit policy must set max_open")
+	}
+	maxOpen, err := types.DurationFromProto(policy.MaxOpen)
+	if err != nil {
+		return errors.Wrapf(err, "invalid max_open")
+	}
+	if maxOpen <= 0 {
+		return errors.Errorf("max_open must be positive (got %v)", maxOpen)
+	}
+	if _, ok := pfs.AbandonAction_name[int32(policy.Action)]; !ok {
+		return errors.Errorf("unknown abandon action %v", policy.Action)
+	}
+	return nil
+}
+
+// setOpenCommitPolicy sets the open commit policy of 'repo' to 'policy', or
+// removes it if 'policy' is nil. Only the repo's owners can change it.
+func (d *driver) setOpenCommitPolicy(pachClient *client.APIClient, repo *pfs.Repo, policy *pfs.OpenCommitPolicy) error {
+	if repo == nil {
+		return errors.New("repo cannot be nil")
+	}
+	if policy != nil {
+		if err := validateOpenCommitPolicy(policy); err != nil {
+			return err
+		}
+	}
+	if err := d.checkIsAuthorized(pachClient, repo, auth.Scope_OWNER); err != nil {
+		return err
+	}
+	_, err := col.NewSTM(pachClient.Ctx(), d.etcdClient, func(stm col.STM) error {
+		repoInfo := &pfs.RepoInfo{}
+		return d.repos.ReadWrite(stm).Update(repo.Name, repoInfo, func() error {
+			repoInfo.OpenCommitPolicy = policy
+			return nil
+		})
+	})
+	if col.IsErrNotFound(err) {
+		return pfsserver.ErrRepoNotFound{Repo: repo}
+	}
+	return err
+}
+
+// listOpenCommits returns the open commits of 'repo' (or of every repo, if
+// it's nil) that the caller can read, oldest first.
+func (d *driver) listOpenCommits(pachClient *client.APIClient, repo *pfs.Repo) ([]*pfs.CommitInfo, error) {
+	ctx := pachClient.Ctx()
+	if repo != nil {
+		if err := d.checkIsAuthorized(pachClient, repo, auth.Scope_READER); err != nil {
+			return nil, err
+		}
+	}
+	readable := make(map[string]bool)
+	var result []*pfs.CommitInfo
+	commit := &pfs.Commit{}
+	if err := d.openCommits.ReadOnly(ctx).List(commit, col.DefaultOptions, func(string) error {
+		if repo != nil && commit.Repo.Name != repo.Name {
+			return nil
+		}
+		name := commit.Repo.Name
+		if _, ok := readable[name]; !ok {
+			err := d.checkIsAuthorized(pachClient, commit.Repo, auth.Scope_READER)
+			if err != nil && !auth.IsErrNotAuthorized(err) {
+				return err
+			}
+			readable[name] = err == nil
+		}
+		if !readable[name] {
+			return nil
+		}
+		commitInfo := &pfs.CommitInfo{}
+		if err := d.commits(name).ReadOnly(ctx).Get(commit.ID, commitInfo); err != nil {
+			if col.IsErrNotFound(err) {
+				return nil // the commit was deleted
+			}
+			return err
+		}
+		if commitInfo.Finished == nil {
+			result = append(result, commitInfo)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Started.Compare(result[j].Started) < 0
+	})
+	return result, nil
+}
+
+// abandonOpenCommits abandons the commits that have been open for longer than
+// their repos' open commit policies allow, every openCommitsCheckInterval.
+// It holds a dlock, so that only one pachd abandons commits.
+func (d *driver) abandonOpenCommits() {
+	ctx := context.Background()
+	lock := dlock.NewDLock(d.etcdClient, path.Join(d.prefix, openCommitsLockPath))
+	backoff.RetryUntilCancel(ctx, func() error {
+		lockCtx, err := lock.Lock(ctx)
+		if err != nil {
+			return err
+		}
+		defer lock.Unlock(lockCtx)
+		// Act as PPS, which may write to every repo, if auth is active
+		var token types.StringValue
+		if err := col.NewCollection(d.etcdClient, ppsconsts.PPSTokenKey, nil, &types.StringValue{}, nil, nil).ReadOnly(lockCtx).Get("", &token); err != nil && !col.IsErrNotFound(err) {
+			return err
+		}
+		if token.Value != "" {
+			lockCtx = metadata.AppendToOutgoingContext(lockCtx, auth.ContextTokenKey, token.Value)
+		}
+		for {
+			if err := d.abandonExpiredCommits(lockCtx, time.Now()); err != nil {
+				return err
+			}
+			select {
+			case <-lockCtx.Done():
+				return lockCtx.Err()
+			case <-time.After(openCommitsCheckInterval):
+			}
+		}
+	}, backoff.NewInfiniteBackOff(), func(err error, wait time.Duration) error {
+		log.Errorf("error abandoning open commits: %v; retrying in %v", err, wait)
+		return nil
+	})
+}
+
+// abandonExpiredCommits abandons the commits that are past their repos' open
+// commit policies as of 'now'.
+func (d *driver) abandonExpiredCommits(ctx context.Context, now time.Time) error {
+	policies := make(map[string]*pfs.OpenCommitPolicy)
+	var expired []*pfs.Commit
+	commit := &pfs.Commit{}
+	if err := d.openCommits.ReadOnly(ctx).List(commit, col.DefaultOptions, func(string) error {
+		name := commit.Repo.Name
+		policy, ok := policies[name]
+		if !ok {
+			repoInfo := &pfs.RepoInfo{}
+			if err := d.repos.ReadOnly(ctx).Get(name, repoInfo); err != nil && !col.IsErrNotFound(err) {
+				return err
+			}
+			policy = repoInfo.OpenCommitPolicy
+			policies[name] = policy
+		}
+		if policy == nil {
+			return nil
+		}
+		commitInfo := &pfs.CommitInfo{}
+		if err := d.commits(name).ReadOnly(ctx).Get(commit.ID, commitInfo); err != nil {
+			if col.IsErrNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if commitExpired(commitInfo, policy, now) {
+			expired = append(expired, client.NewCommit(name, commit.ID))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, c := range expired {
+		if err := d.abandonCommit(ctx, c, policies[c.Repo.Name].Action); err != nil {
+			log.Errorf("could not abandon commit %s@%s: %v", c.Repo.Name, c.ID, err)
+		}
+	}
+	return nil
+}
+
+// commitExpired returns true if 'commitInfo' has been open for longer than
+// 'policy' allows as of 'now'. The output commits of pipelines (which are
+// finished by their jobs) never expire.
+func commitExpired(commitInfo *pfs.CommitInfo, policy *pfs.OpenCommitPolicy, now time.Time) bool {
+	if commitInfo.Finished != nil || commitInfo.Origin.GetKind() == pfs.OriginKind_AUTO {
+		return false
+	}
+	started, err := types.TimestampFromProto(commitInfo.Started)
+	if err != nil {
+		return false
+	}
+	maxOpen, err := types.DurationFromProto(policy.MaxOpen)
+	if err != nil {
+		return false
+	}
+	return now.Sub(started) > maxOpen
+}
+
+// abandonCommit marks 'commit' as abandoned, and then finishes it empty or
+// deletes it. Marking it first publishes the commit's abandonment (e.g. to
+// the event bus) even if it's deleted.
+func (d *driver) abandonCommit(ctx context.Context, commit *pfs.Commit, action pfs.AbandonAction) error {
+	if _, err := col.NewSTM(ctx, d.etcdClient, func(stm col.STM) error {
+		commitInfo := &pfs.CommitInfo{}
+		return d.commits(commit.Repo.Name).ReadWrite(stm).Update(commit.ID, commitInfo, func() error {
+			if commitInfo.Finished != nil {
+				return pfsserver.ErrCommitFinished{Commit: commit}
+			}
+			if commitInfo.Abandoned == nil {
+				commitInfo.Abandoned = types.TimestampNow()
+			}
+			return nil
+		})
+	}); err != nil {
+		return err
+	}
+	log.Infof("abandoning commit %s@%s (%v)", commit.Repo.Name, commit.ID, action)
+	return d.txnEnv.WithWriteContext(ctx, func(txnCtx *txnenv.TransactionContext) error {
+		if action == pfs.AbandonAction_ABANDON_DELETE {
+			return d.deleteCommit(txnCtx, commit)
+		}
+		return d.finishCommit(txnCtx, commit, nil, true, "", nil)
+	})
+}
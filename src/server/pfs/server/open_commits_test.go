@@ -0,0 +1,43 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestValidateOpenCommitPolicy(t *testing.T) {
+	require.NoError(t, validateOpenCommitPolicy(&pfs.OpenCommitPolicy{
+		MaxOpen: types.DurationProto(time.Hour),
+		Action:  pfs.AbandonAction_ABANDON_DELETE,
+	}))
+	require.YesError(t, validateOpenCommitPolicy(&pfs.OpenCommitPolicy{}))
+	require.YesError(t, validateOpenCommitPolicy(&pfs.OpenCommitPolicy{
+		MaxOpen: types.DurationProto(-time.Hour),
+	}))
+	require.YesError(t, validateOpenCommitPolicy(&pfs.OpenCommitPolicy{
+		MaxOpen: types.DurationProto(time.Hour),
+		Action:  pfs.AbandonAction(7),
+	}))
+}
+
+func TestCommitExpired(t *testing.T) {
+	now := time.Now()
+	started, err := types.TimestampProto(now.Add(-2 * time.Hour))
+	require.NoError(t, err)
+	policy := &pfs.OpenCommitPolicy{MaxOpen: types.DurationProto(time.Hour)}
+
+	commitInfo := &pfs.CommitInfo{Started: started}
+	require.True(t, commitExpired(commitInfo, policy, now))
+	require.False(t, commitExpired(commitInfo, policy, now.Add(-90*time.Minute)))
+
+	// Finished commits and the output commits of pipelines never expire
+	commitInfo.Finished = types.TimestampNow()
+	require.False(t, commitExpired(commitInfo, policy, now))
+	commitInfo = &pfs.CommitInfo{Started: started, Origin: &pfs.CommitOrigin{Kind: pfs.OriginKind_AUTO}}
+	require.False(t, commitExpired(commitInfo, policy, now))
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"path"
+	"sort"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+
+	"github.com/gogo/protobuf/types"
+)
+
+// Deleting a repo with --force (which is how a deleted pipeline's output repo
+// is deleted) leaves the provenance of downstream commits pointing at commits
+// that no longer exist. archiveProvenance removes those edges from the
+// commits' provenance, and records them in the commits' archived provenance
+// instead, as one ProvenanceTombstone per deleted branch.
+
+// archiveProvenance archives the provenance of every finished commit that
+// refers to commits that no longer exist. Commits in the trash still count as
+// existing, so that restoring them doesn't leave their downstream commits
+// without provenance. Only admins may archive provenance, as it rewrites
+// commits in every repo.
+func (d *driver) archiveProvenance(pachClient *client.APIClient, dryRun bool) (*pfs.ArchiveProvenanceResponse, error) {
+	ctx := pachClient.Ctx()
+	me, err := pachClient.WhoAmI(ctx, &auth.WhoAmIRequest{})
+	if err != nil && !auth.IsErrNotActivated(err) {
+		return nil, grpcutil.ScrubGRPC(err)
+	} else if err == nil && !me.IsAdmin {
+		return nil, &auth.ErrNotAuthorized{Subject: me.Username, AdminOp: "ArchiveProvenance"}
+	}
+
+	// collect the commits that exist, and the commits that have provenance
+	key := path.Join
+	exists := make(map[string]bool)
+	var candidates []*pfs.Commit
+	repoInfo := &pfs.RepoInfo{}
+	if err := d.repos.ReadOnly(ctx).List(repoInfo, col.DefaultOptions, func(repoName string) error {
+		commitInfo := &pfs.CommitInfo{}
+		return d.commits(repoName).ReadOnly(ctx).List(commitInfo, col.DefaultOptions, func(commitID string) error {
+			exists[key(repoName, commitID)] = true
+			if len(commitInfo.Provenance) > 0 {
+				candidates = append(candidates, client.NewCommit(repoName, commitID))
+			}
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	deletedInfo := &pfs.DeletedInfo{}
+	if err := d.trash.ReadOnly(ctx).List(deletedInfo, col.DefaultOptions, func(string) error {
+		for _, commitInfo := range deletedInfo.CommitInfos {
+			exists[key(commitInfo.Commit.Repo.Name, commitInfo.Commit.ID)] = true
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	response := &pfs.ArchiveProvenanceResponse{}
+	archivedRepos := make(map[string]bool)
+	for _, commit := range candidates {
+		var tombstones []*pfs.ProvenanceTombstone
+		var edges int64
+		if _, err := col.NewSTM(ctx, d.etcdClient, func(stm col.STM) error {
+			tombstones, edges = nil, 0
+			commits := d.commits(commit.Repo.Name).ReadWrite(stm)
+			commitInfo := &pfs.CommitInfo{}
+			if err := commits.Get(commit.ID, commitInfo); err != nil {
+				if col.IsErrNotFound(err) {
+					return nil // the commit was deleted in the meantime
+				}
+				return err
+			}
+			// Open commits may still be waiting for their provenance to
+			// finish, so they're left alone until the next run
+			if commitInfo.Finished == nil {
+				return nil
+			}
+			byBranch := make(map[string]*pfs.ProvenanceTombstone)
+			var kept []*pfs.CommitProvenance
+			for _, prov := range commitInfo.Provenance {
+				if exists[key(prov.Commit.Repo.Name, prov.Commit.ID)] {
+					kept = append(kept, prov)
+					continue
+				}
+				// The commit may have been created after it was listed
+				if err := d.commits(prov.Commit.Repo.Name).ReadWrite(stm).Get(prov.Commit.ID, &pfs.CommitInfo{}); err == nil {
+					kept = append(kept, prov)
+					continue
+				} else if !col.IsErrNotFound(err) {
+					return err
+				}
+				branch := prov.Branch.GetName()
+				tombstone, ok := byBranch[key(prov.Commit.Repo.Name, branch)]
+				if !ok {
+					tombstone = &pfs.ProvenanceTombstone{
+						Repo:   prov.Commit.Repo.Name,
+						Branch: branch,
+					}
+					byBranch[key(prov.Commit.Repo.Name, branch)] = tombstone
+					tombstones = append(tombstones, tombstone)
+				}
+				tombstone.CommitIDs = append(tombstone.CommitIDs, prov.Commit.ID)
+				edges++
+			}
+			if edges == 0 || dryRun {
+				return nil
+			}
+			archived, err := types.TimestampProto(time.Now())
+			if err != nil {
+				return err
+			}
+			for _, tombstone := range tombstones {
+				tombstone.Archived = archived
+			}
+			commitInfo.Provenance = kept
+			if commitInfo.ReadyProvenance > int64(len(kept)) {
+				commitInfo.ReadyProvenance = int64(len(kept))
+			}
+			commitInfo.ArchivedProvenance = append(commitInfo.ArchivedProvenance, tombstones...)
+			return commits.Put(commit.ID, commitInfo)
+		}); err != nil {
+			return nil, err
+		}
+		if edges == 0 {
+			continue
+		}
+		response.CommitsRewritten++
+		response.EdgesArchived += edges
+		for _, tombstone := range tombstones {
+			archivedRepos[tombstone.Repo] = true
+		}
+	}
+	for repo := range archivedRepos {
+		response.ArchivedRepos = append(response.ArchivedRepos, repo)
+	}
+	sort.Strings(response.ArchivedRepos)
+	return response, nil
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+
+	"github.com/gogo/protobuf/types"
+)
+
+// pruneCommit drops the contents of 'commit', leaving an empty commit in its
+// place, so that they can be garbage collected. Unlike deleteCommit, it
+// leaves the commit graph (and so provenance) as it is, which is what lets
+// it prune commits with provenance, like pipelines' stats commits. The heads
+// of branches are read by downstream pipelines, and tagged commits are
+// immutable, so neither can be pruned.
+func (d *driver) pruneCommit(pachClient *client.APIClient, commit *pfs.Commit) error {
+	if commit == nil {
+		return errors.New("commit cannot be nil")
+	}
+	if commit.Repo == nil {
+		return errors.New("commit repo cannot be nil")
+	}
+	if err := d.checkIsAuthorized(pachClient, commit.Repo, auth.Scope_WRITER); err != nil {
+		return err
+	}
+	_, err := col.NewSTM(pachClient.Ctx(), d.etcdClient, func(stm col.STM) error {
+		commitInfo, err := d.resolveCommit(stm, commit)
+		if err != nil {
+			return err
+		}
+		if commitInfo.Finished == nil {
+			return errors.Errorf("cannot prune commit %s/%s because it isn't finished", commit.Repo.Name, commitInfo.Commit.ID)
+		}
+		if commitInfo.Pruned != nil {
+			return nil
+		}
+		if len(commitInfo.Tags) > 0 {
+			return errors.Errorf("cannot prune commit %s/%s because it's tagged as %q",
+				commit.Repo.Name, commitInfo.Commit.ID, commitInfo.Tags)
+		}
+		repoInfo := &pfs.RepoInfo{}
+		if err := d.repos.ReadWrite(stm).Get(commit.Repo.Name, repoInfo); err != nil {
+			return err
+		}
+		for _, branch := range repoInfo.Branches {
+			branchInfo := &pfs.BranchInfo{}
+			if err := d.branches(commit.Repo.Name).ReadWrite(stm).Get(branch.Name, branchInfo); err != nil {
+				return err
+			}
+			if branchInfo.Head != nil && branchInfo.Head.ID == commitInfo.Commit.ID {
+				return errors.Errorf("cannot prune commit %s/%s because it's the head of branch %q",
+					commit.Repo.Name, commitInfo.Commit.ID, branch.Name)
+			}
+		}
+		prunedInfo := &pfs.CommitInfo{}
+		return d.commits(commit.Repo.Name).ReadWrite(stm).Update(commitInfo.Commit.ID, prunedInfo, func() error {
+			prunedInfo.Tree = nil
+			prunedInfo.Trees = nil
+			prunedInfo.SizeBytes = 0
+			prunedInfo.ChangedPaths = nil
+			prunedInfo.Pruned = types.TimestampNow()
+			return nil
+		})
+	})
+	return err
+}
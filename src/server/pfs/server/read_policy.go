@@ -0,0 +1,291 @@
+package server
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"io/ioutil"
+	"path"
+	"regexp"
+
+	globlib "github.com/pachyderm/ohmyglob"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
+)
+
+// defaultRedactionMask replaces masked values if a read policy doesn't set
+// its own mask
+const defaultRedactionMask = "REDACTED"
+
+func validateReadPolicy(policy *pfs.ReadPolicy) error {
+	if len(policy.Columns) == 0 && len(policy.Patterns) == 0 {
+		return errors.New("a read policy must mask at least one column or pattern")
+	}
+	if policy.Glob != "" {
+		if _, err := globlib.Compile(path.Clean("/"+policy.Glob), '/'); err != nil {
+			return errors.Wrapf(err, "invalid read policy glob %q", policy.Glob)
+		}
+	}
+	for _, c := range policy.Columns {
+		if c == "" {
+			return errors.New("read policy columns can't be empty")
+		}
+	}
+	for _, p := range policy.Patterns {
+		// An empty pattern matches between every pair of characters
+		if p == "" {
+			return errors.New("read policy patterns can't be empty")
+		}
+		if _, err := regexp.Compile(p); err != nil {
+			return errors.Wrapf(err, "invalid read policy pattern %q", p)
+		}
+	}
+	return nil
+}
+
+// setReadPolicy sets the read policy of 'repo' to 'policy', or removes it if
+// 'policy' is nil. Only the repo's owners can change its read policy.
+func (d *driver) setReadPolicy(pachClient *client.APIClient, repo *pfs.Repo, policy *pfs.ReadPolicy) error {
+	if repo == nil {
+		return errors.New("repo cannot be nil")
+	}
+	if policy != nil {
+		if err := validateReadPolicy(policy); err != nil {
+			return err
+		}
+	}
+	if err := d.checkIsAuthorized(pachClient, repo, auth.Scope_OWNER); err != nil {
+		return err
+	}
+	_, err := col.NewSTM(pachClient.Ctx(), d.etcdClient, func(stm col.STM) error {
+		repoInfo := &pfs.RepoInfo{}
+		return d.repos.ReadWrite(stm).Update(repo.Name, repoInfo, func() error {
+			repoInfo.ReadPolicy = policy
+			return nil
+		})
+	})
+	if col.IsErrNotFound(err) {
+		return pfsserver.ErrRepoNotFound{Repo: repo}
+	}
+	return err
+}
+
+// getRedactor returns the redactor that the caller's reads of 'repo' must go
+// through, or nil if the caller may read the repo's files unchanged (because
+// the repo has no read policy, or the caller has full access to it).
+func (d *driver) getRedactor(pachClient *client.APIClient, repo *pfs.Repo) (*redactor, error) {
+	ctx := pachClient.Ctx()
+	repoInfo := &pfs.RepoInfo{}
+	if err := d.repos.ReadOnly(ctx).Get(repo.Name, repoInfo); err != nil {
+		if col.IsErrNotFound(err) {
+			// Let the read itself report that the repo doesn't exist
+			return nil, nil
+		}
+		return nil, err
+	}
+	if repoInfo.ReadPolicy == nil {
+		return nil, nil
+	}
+	if _, err := pachClient.WhoAmI(ctx, &auth.WhoAmIRequest{}); auth.IsErrNotActivated(err) {
+		return nil, nil
+	}
+	resp, err := pachClient.AuthAPIClient.Authorize(ctx, &auth.AuthorizeRequest{
+		Repo:  repo.Name,
+		Scope: auth.Scope_WRITER,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(grpcutil.ScrubGRPC(err), "error during authorization check for operation on \"%s\"", repo.Name)
+	}
+	if resp.Authorized {
+		return nil, nil
+	}
+	return newRedactor(repoInfo.ReadPolicy)
+}
+
+// getRedactedFile is getFile for callers whose reads go through 'r'. Since
+// redaction changes the size of files, each file that matches file.Path is
+// redacted separately, and [offset, offset+size) is selected from the
+// redacted content. The caller must close the returned reader.
+func (d *driver) getRedactedFile(pachClient *client.APIClient, r *redactor, file *pfs.File, offset, size int64) (io.ReadCloser, error) {
+	if offset < 0 || size < 0 {
+		return nil, errors.Errorf("offset_bytes and size_bytes can't be negative")
+	}
+	files := []*pfs.File{file}
+	if hashtree.IsGlob(file.Path) {
+		files = nil
+		if err := d.globFile(pachClient, file.Commit, file.Path, func(fi *pfs.FileInfo) error {
+			if fi.FileType == pfs.FileType_FILE {
+				files = append(files, fi.File)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, pfsserver.ErrFileNotFound{file}
+		}
+	}
+	// Open the first file before returning, so that errors such as a missing
+	// file are returned by GetFile rather than in the middle of the stream
+	first, err := d.getFile(pachClient, files[0], 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(func() error {
+			for i, f := range files {
+				content := first
+				if i > 0 {
+					var err error
+					if content, err = d.getFile(pachClient, f, 0, 0); err != nil {
+						return err
+					}
+				}
+				if !r.matches(f.Path) {
+					if _, err := io.Copy(pw, content); err != nil {
+						return err
+					}
+					continue
+				}
+				if err := r.redact(pw, content); err != nil {
+					return errors.Wrapf(err, "error redacting %q", f.Path)
+				}
+			}
+			return nil
+		}())
+	}()
+	var result io.Reader = pr
+	if offset > 0 {
+		if _, err := io.CopyN(ioutil.Discard, pr, offset); err != nil && !errors.Is(err, io.EOF) {
+			pr.Close()
+			return nil, err
+		}
+	}
+	if size > 0 {
+		result = io.LimitReader(pr, size)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{result, pr}, nil
+}
+
+// redactor masks the parts of files that a repo's read policy hides
+type redactor struct {
+	glob     *globlib.Glob // nil if all files are redacted
+	columns  map[string]bool
+	patterns []*regexp.Regexp
+	mask     string
+}
+
+func newRedactor(policy *pfs.ReadPolicy) (*redactor, error) {
+	r := &redactor{
+		columns: make(map[string]bool),
+		mask:    policy.Mask,
+	}
+	if r.mask == "" {
+		r.mask = defaultRedactionMask
+	}
+	if policy.Glob != "" {
+		g, err := globlib.Compile(path.Clean("/"+policy.Glob), '/')
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid read policy glob %q", policy.Glob)
+		}
+		r.glob = g
+	}
+	for _, c := range policy.Columns {
+		r.columns[c] = true
+	}
+	for _, p := range policy.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid read policy pattern %q", p)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// matches returns true if the file at 'p' is redacted
+func (r *redactor) matches(p string) bool {
+	return r.glob == nil || r.glob.Match(path.Clean("/"+p))
+}
+
+// redact writes the contents of 'in' to 'out', with the values in the
+// policy's columns and the matches of its patterns replaced with its mask
+func (r *redactor) redact(out io.Writer, in io.Reader) error {
+	if len(r.columns) > 0 {
+		return r.redactCSV(out, in)
+	}
+	bufioR := bufio.NewReader(in)
+	for {
+		line, err := bufioR.ReadString('\n')
+		if len(line) > 0 {
+			// Don't let patterns match across lines, or swallow newlines
+			newline := ""
+			if line[len(line)-1] == '\n' {
+				line, newline = line[:len(line)-1], "\n"
+			}
+			if _, err := io.WriteString(out, r.maskPatterns(line)+newline); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// redactCSV is redact for CSV files, whose first row is a header that names
+// their columns
+func (r *redactor) redactCSV(out io.Writer, in io.Reader) error {
+	csvReader := csv.NewReader(in)
+	csvReader.FieldsPerRecord = -1 // as in PutFileSplit
+	csvWriter := csv.NewWriter(out)
+	var masked map[int]bool
+	for {
+		row, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return errors.Wrapf(err, "error parsing csv record")
+		}
+		if masked == nil {
+			masked = make(map[int]bool)
+			for i, name := range row {
+				if r.columns[name] {
+					masked[i] = true
+				}
+			}
+		} else {
+			for i := range row {
+				if masked[i] {
+					row[i] = r.mask
+				} else {
+					row[i] = r.maskPatterns(row[i])
+				}
+			}
+		}
+		if err := csvWriter.Write(row); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func (r *redactor) maskPatterns(s string) string {
+	for _, re := range r.patterns {
+		s = re.ReplaceAllLiteralString(s, r.mask)
+	}
+	return s
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestValidateReadPolicy(t *testing.T) {
+	require.NoError(t, validateReadPolicy(&pfs.ReadPolicy{Columns: []string{"ssn"}}))
+	require.NoError(t, validateReadPolicy(&pfs.ReadPolicy{Glob: "/**.csv", Patterns: []string{`\d+`}}))
+	require.YesError(t, validateReadPolicy(&pfs.ReadPolicy{}))
+	require.YesError(t, validateReadPolicy(&pfs.ReadPolicy{Patterns: []string{""}}))
+	require.YesError(t, validateReadPolicy(&pfs.ReadPolicy{Patterns: []string{"("}}))
+}
+
+func TestRedact(t *testing.T) {
+	r, err := newRedactor(&pfs.ReadPolicy{Patterns: []string{`\d{3}-\d{4}`}})
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, r.redact(&buf, strings.NewReader("call 555-1234\nor 555-9876")))
+	require.Equal(t, "call REDACTED\nor REDACTED", buf.String())
+
+	// Columns are masked by name, and patterns are masked in the other columns
+	r, err = newRedactor(&pfs.ReadPolicy{
+		Columns:  []string{"ssn"},
+		Patterns: []string{`@.*`},
+		Mask:     "XXX",
+	})
+	require.NoError(t, err)
+	buf.Reset()
+	require.NoError(t, r.redact(&buf, strings.NewReader("name,ssn,email\nalice,123-45-6789,alice@example.com\n")))
+	require.Equal(t, "name,ssn,email\nalice,XXX,aliceXXX\n", buf.String())
+}
+
+func TestRedactorMatches(t *testing.T) {
+	r, err := newRedactor(&pfs.ReadPolicy{Glob: "/pii/*", Columns: []string{"ssn"}})
+	require.NoError(t, err)
+	require.True(t, r.matches("/pii/patients.csv"))
+	require.True(t, r.matches("pii/patients.csv"))
+	require.False(t, r.matches("/public/counts.csv"))
+
+	r, err = newRedactor(&pfs.ReadPolicy{Columns: []string{"ssn"}})
+	require.NoError(t, err)
+	require.True(t, r.matches("/anything"))
+}
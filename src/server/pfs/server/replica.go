@@ -0,0 +1,195 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ancestry"
+	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// replicationHeartbeatInterval is how often the replication heartbeat is
+// written to the default bucket, and read from its replicas
+const replicationHeartbeatInterval = time.Minute
+
+var (
+	replicaMisses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pachyderm_pachd_storage_replica_misses_total",
+		Help: "Number of blocks that weren't in the region's replica of the default bucket yet, and were read from the default bucket",
+	}, []string{"region"})
+	replicaLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pachyderm_pachd_storage_replica_lag_seconds",
+		Help: "How far the region's replica of the default bucket is behind it, as of the last replication heartbeat it has",
+	}, []string{"region"})
+)
+
+// ReplicaOptions configures the read replicas of an Object API server's
+// default bucket, for clusters whose workers span regions.
+type ReplicaOptions struct {
+	// Buckets is the bucket in each region that the default bucket is
+	// replicated to, as a comma-separated list of <region>=<bucket URL> pairs
+	// (see STORAGE_REPLICA_BUCKETS). The buckets are accessed with the
+	// credentials of the default bucket.
+	Buckets string
+	// Region is the region that the server runs in. If the default bucket
+	// has a replica there, blocks are read from the replica, and from the
+	// default bucket if they haven't been replicated yet.
+	Region string
+	// WriteHeartbeats, if true, makes the server periodically write the time
+	// to the default bucket, from which the lag of each replica is measured.
+	WriteHeartbeats bool
+}
+
+// parseReplicaBuckets parses 'spec', a comma-separated list of
+// <region>=<bucket URL> pairs, into a map from region to bucket URL.
+func parseReplicaBuckets(spec string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, errors.Errorf("malformed replica bucket %q (expected <region>=<url>)", pair)
+		}
+		if err := ancestry.ValidateName(parts[0]); err != nil {
+			return nil, errors.Wrapf(err, "invalid region %q", parts[0])
+		}
+		if _, ok := result[parts[0]]; ok {
+			return nil, errors.Errorf("region %q has more than one replica bucket", parts[0])
+		}
+		if _, err := obj.ParseURL(parts[1]); err != nil {
+			return nil, errors.Wrapf(err, "invalid replica bucket of region %q", parts[0])
+		}
+		result[parts[0]] = parts[1]
+	}
+	return result, nil
+}
+
+// setUpReplicas reads blocks in the default bucket from its replica in the
+// server's region, if it has one, and starts writing or reading the
+// replication heartbeat.
+func (s *objBlockAPIServer) setUpReplicas(opts *ReplicaOptions) error {
+	if opts == nil || opts.Buckets == "" {
+		return nil
+	}
+	buckets, err := parseReplicaBuckets(opts.Buckets)
+	if err != nil {
+		return err
+	}
+	registerReplicaMetrics()
+	if opts.WriteHeartbeats {
+		go s.writeReplicationHeartbeats()
+	}
+	bucket, ok := buckets[opts.Region]
+	if !ok {
+		return nil
+	}
+	url, err := obj.ParseURL(bucket)
+	if err != nil {
+		return err
+	}
+	replica, err := obj.NewClientFromURLAndSecret(url)
+	if err != nil {
+		return errors.Wrapf(err, "could not create a client for the replica bucket of region %q", opts.Region)
+	}
+	if err := obj.TestStorage(context.Background(), replica); err != nil {
+		return errors.Wrapf(err, "could not access the replica bucket of region %q", opts.Region)
+	}
+	misses := replicaMisses.WithLabelValues(opts.Region)
+	s.blockReadClient = obj.NewReplicaClient(s.objClient, replica, misses.Inc)
+	go s.trackReplicationLag(opts.Region, replica)
+	return nil
+}
+
+func registerReplicaMetrics() {
+	for _, c := range []prometheus.Collector{replicaMisses, replicaLag} {
+		if err := prometheus.Register(c); err != nil {
+			// metrics may be redundantly registered; ignore these errors
+			if !errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+				logrus.Infof("error registering prometheus metric: %v", err)
+			}
+		}
+	}
+}
+
+func (s *objBlockAPIServer) replicationHeartbeatPath() string {
+	return filepath.Join(s.dir, "replication-heartbeat")
+}
+
+// writeReplicationHeartbeats writes the current time to the replication
+// heartbeat in the default bucket, every replicationHeartbeatInterval.
+func (s *objBlockAPIServer) writeReplicationHeartbeats() {
+	for {
+		if err := s.writeReplicationHeartbeat(context.Background(), time.Now()); err != nil {
+			logrus.Errorf("could not write the replication heartbeat: %v", err)
+		}
+		time.Sleep(replicationHeartbeatInterval)
+	}
+}
+
+func (s *objBlockAPIServer) writeReplicationHeartbeat(ctx context.Context, now time.Time) (retErr error) {
+	p := s.replicationHeartbeatPath()
+	// Object storage writers may not overwrite objects
+	if err := s.objClient.Delete(ctx, p); err != nil && !s.isNotFoundErr(err) {
+		return err
+	}
+	w, err := s.objClient.Writer(ctx, p)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := w.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	_, err = fmt.Fprint(w, now.UTC().Format(time.RFC3339Nano))
+	return errors.EnsureStack(err)
+}
+
+// trackReplicationLag sets the replication lag of 'region' from the
+// replication heartbeat in its bucket, 'replica', every
+// replicationHeartbeatInterval.
+func (s *objBlockAPIServer) trackReplicationLag(region string, replica obj.Client) {
+	for {
+		lag, err := s.replicationLag(context.Background(), replica, time.Now())
+		if err != nil {
+			logrus.Errorf("could not measure the replication lag of region %q: %v", region, err)
+		} else if lag >= 0 {
+			replicaLag.WithLabelValues(region).Set(lag.Seconds())
+		}
+		time.Sleep(replicationHeartbeatInterval)
+	}
+}
+
+// replicationLag returns how far 'replica' is behind the default bucket as
+// of 'now', or -1 if it has no replication heartbeat yet.
+func (s *objBlockAPIServer) replicationLag(ctx context.Context, replica obj.Client, now time.Time) (time.Duration, error) {
+	r, err := replica.Reader(ctx, s.replicationHeartbeatPath(), 0, 0)
+	if err != nil {
+		if replica.IsNotExist(err) {
+			return -1, nil
+		}
+		return 0, err
+	}
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return 0, errors.EnsureStack(err)
+	}
+	heartbeat, err := time.Parse(time.RFC3339Nano, string(bytes.TrimSpace(data)))
+	if err != nil {
+		return 0, errors.Wrapf(err, "malformed replication heartbeat")
+	}
+	return now.Sub(heartbeat), nil
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
+
+	"golang.org/x/net/context"
+)
+
+func TestParseReplicaBuckets(t *testing.T) {
+	buckets, err := parseReplicaBuckets("us-west-2=s3://pach-us-west-2, eu-west-1=gs://pach-eu")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"us-west-2": "s3://pach-us-west-2",
+		"eu-west-1": "gs://pach-eu",
+	}, buckets)
+	buckets, err = parseReplicaBuckets("")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(buckets))
+
+	_, err = parseReplicaBuckets("us-west-2")
+	require.YesError(t, err)
+	_, err = parseReplicaBuckets("us-west-2=s3://a,us-west-2=s3://b")
+	require.YesError(t, err)
+	_, err = parseReplicaBuckets("us-west-2=ftp://a")
+	require.YesError(t, err)
+}
+
+func putObj(t *testing.T, c obj.Client, p, content string) {
+	w, err := c.Writer(context.Background(), p)
+	require.NoError(t, err)
+	_, err = w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+}
+
+func readObj(t *testing.T, c obj.Client, p string) string {
+	r, err := c.Reader(context.Background(), p, 0, 0)
+	require.NoError(t, err)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// localBuckets returns the clients of two temporary local buckets, the
+// primary bucket and its replica, and a function that removes them
+func localBuckets(t *testing.T) (primary, replica obj.Client, cleanup func()) {
+	var clients []obj.Client
+	var dirs []string
+	for _, name := range []string{"primary", "replica"} {
+		dir, err := ioutil.TempDir("", name)
+		require.NoError(t, err)
+		dirs = append(dirs, dir)
+		c, err := obj.NewLocalClient(dir)
+		require.NoError(t, err)
+		clients = append(clients, c)
+	}
+	return clients[0], clients[1], func() {
+		for _, dir := range dirs {
+			os.RemoveAll(dir)
+		}
+	}
+}
+
+func TestReplicaReads(t *testing.T) {
+	primary, replica, cleanup := localBuckets(t)
+	defer cleanup()
+	misses := 0
+	c := obj.NewReplicaClient(primary, replica, func() { misses++ })
+
+	// Blocks are read from the replica once they've been replicated, and from
+	// the primary bucket until then
+	putObj(t, primary, "block/a", "a")
+	putObj(t, replica, "block/a", "a")
+	putObj(t, c, "block/b", "b")
+	require.Equal(t, "a", readObj(t, c, "block/a"))
+	require.Equal(t, 0, misses)
+	require.Equal(t, "b", readObj(t, c, "block/b"))
+	require.Equal(t, 1, misses)
+	require.False(t, replica.Exists(context.Background(), "block/b"))
+}
+
+func TestReplicationLag(t *testing.T) {
+	primary, replica, cleanup := localBuckets(t)
+	defer cleanup()
+	s := &objBlockAPIServer{dir: "pach", objClient: primary}
+	ctx := context.Background()
+
+	lag, err := s.replicationLag(ctx, replica, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(-1), lag)
+
+	// Heartbeats overwrite each other
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, s.writeReplicationHeartbeat(ctx, start))
+	require.NoError(t, s.writeReplicationHeartbeat(ctx, start.Add(time.Minute)))
+	// Replicate the heartbeat
+	putObj(t, replica, s.replicationHeartbeatPath(), readObj(t, primary, s.replicationHeartbeatPath()))
+	lag, err = s.replicationLag(ctx, replica, start.Add(3*time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Minute, lag)
+}
@@ -1,7 +1,12 @@
 package server
 
 import (
+	"context"
+
 	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/residency"
+	"github.com/pachyderm/pachyderm/src/server/pkg/fault"
 	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
 	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
 	"github.com/pachyderm/pachyderm/src/server/pkg/serviceenv"
@@ -48,53 +53,83 @@ func NewAPIServer(
 }
 
 // NewBlockAPIServer creates a BlockAPIServer using the credentials it finds in
-// the environment
+// the environment. 'residencyBuckets' configures the buckets that objects with
+// a residency are stored in (see STORAGE_RESIDENCY_BUCKETS), and 'replicas'
+// (if set) the read replicas of the default bucket.
 // TODO(msteffen) accept serviceenv.ServiceEnv instead of 'dir', 'backend', and
 // 'duplicate'?
-func NewBlockAPIServer(dir string, cacheBytes int64, backend string, etcdAddress string, duplicate bool) (BlockAPIServer, error) {
+func NewBlockAPIServer(dir string, cacheBytes int64, backend string, etcdAddress string, duplicate bool, residencyBuckets string, replicas *ReplicaOptions) (BlockAPIServer, error) {
+	var blockAPIServer *objBlockAPIServer
+	var err error
 	switch backend {
 	case MinioBackendEnvVar:
 		// S3 compatible doesn't like leading slashes
 		if len(dir) > 0 && dir[0] == '/' {
 			dir = dir[1:]
 		}
-		blockAPIServer, err := newMinioBlockAPIServer(dir, cacheBytes, etcdAddress, duplicate)
-		if err != nil {
-			return nil, err
-		}
-		return blockAPIServer, nil
+		blockAPIServer, err = newMinioBlockAPIServer(dir, cacheBytes, etcdAddress, duplicate)
 	case AmazonBackendEnvVar:
 		// amazon doesn't like leading slashes
 		if len(dir) > 0 && dir[0] == '/' {
 			dir = dir[1:]
 		}
-		blockAPIServer, err := newAmazonBlockAPIServer(dir, cacheBytes, etcdAddress, duplicate)
-		if err != nil {
-			return nil, err
-		}
-		return blockAPIServer, nil
+		blockAPIServer, err = newAmazonBlockAPIServer(dir, cacheBytes, etcdAddress, duplicate)
 	case GoogleBackendEnvVar:
 		// TODO figure out if google likes leading slashses
-		blockAPIServer, err := newGoogleBlockAPIServer(dir, cacheBytes, etcdAddress, duplicate)
-		if err != nil {
-			return nil, err
-		}
-		return blockAPIServer, nil
+		blockAPIServer, err = newGoogleBlockAPIServer(dir, cacheBytes, etcdAddress, duplicate)
 	case MicrosoftBackendEnvVar:
-		blockAPIServer, err := newMicrosoftBlockAPIServer(dir, cacheBytes, etcdAddress, duplicate)
-		if err != nil {
-			return nil, err
-		}
-		return blockAPIServer, nil
+		blockAPIServer, err = newMicrosoftBlockAPIServer(dir, cacheBytes, etcdAddress, duplicate)
 	case LocalBackendEnvVar:
 		fallthrough
 	default:
-		blockAPIServer, err := newLocalBlockAPIServer(dir, cacheBytes, etcdAddress, duplicate)
+		blockAPIServer, err = newLocalBlockAPIServer(dir, cacheBytes, etcdAddress, duplicate)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if blockAPIServer.residencyClients, err = newResidencyClients(residencyBuckets); err != nil {
+		return nil, err
+	}
+	if fault.Enabled() {
+		blockAPIServer.objClient = fault.NewObjClient(blockAPIServer.objClient)
+		for name, objClient := range blockAPIServer.residencyClients {
+			blockAPIServer.residencyClients[name] = fault.NewObjClient(objClient)
+		}
+	}
+	blockAPIServer.blockReadClient = blockAPIServer.objClient
+	if err := blockAPIServer.setUpReplicas(replicas); err != nil {
+		return nil, err
+	}
+	return blockAPIServer, nil
+}
+
+// newResidencyClients creates a client for the bucket of each residency in
+// 'residencyBuckets', a comma-separated list of <residency>=<bucket URL>
+// pairs. The buckets are accessed with the credentials of the default bucket.
+func newResidencyClients(residencyBuckets string) (map[string]obj.Client, error) {
+	urls, err := residency.ParseURLs(residencyBuckets)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]obj.Client)
+	for _, name := range residency.Names(urls) {
+		if len(urls[name]) != 1 {
+			return nil, errors.Errorf("residency %q must have exactly one bucket", name)
+		}
+		url, err := obj.ParseURL(urls[name][0])
 		if err != nil {
 			return nil, err
 		}
-		return blockAPIServer, nil
+		objClient, err := obj.NewClientFromURLAndSecret(url)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not create a client for the bucket of residency %q", name)
+		}
+		if err := obj.TestStorage(context.Background(), objClient); err != nil {
+			return nil, errors.Wrapf(err, "could not access the bucket of residency %q", name)
+		}
+		result[name] = objClient
 	}
+	return result, nil
 }
 
 // NewObjClient creates an obj.Client by selecting a construcot from the obj package.
@@ -23,8 +23,10 @@ import (
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
 	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ancestry"
 	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
 	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
 	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
 	"github.com/pachyderm/pachyderm/src/server/pkg/serviceenv"
@@ -1410,6 +1412,50 @@ func TestPutFileBig(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPutFileWithProgress(t *testing.T) {
+	t.Parallel()
+	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
+		repo := "test"
+		require.NoError(t, env.PachClient.CreateRepo(repo))
+
+		content := generateRandomString(10*1024*1024 + 5)
+		var progress []pclient.UploadProgress
+		n, err := env.PachClient.PutFileWithProgress(repo, "master", "foo", strings.NewReader(content), &pclient.UploadOptions{
+			ChunkSize: 1024 * 1024,
+			Progress:  func(p pclient.UploadProgress) { progress = append(progress, p) },
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(len(content)), n)
+		// One callback per chunk, and one once the file is put
+		require.Equal(t, 12, len(progress))
+		require.True(t, progress[len(progress)-1].Done)
+		require.Equal(t, int64(len(content)), progress[len(progress)-1].BytesUploaded)
+
+		var buf bytes.Buffer
+		require.NoError(t, env.PachClient.GetFile(repo, "master", "foo", 0, 0, &buf))
+		require.Equal(t, content, buf.String())
+		// Ranges that span chunks are read correctly
+		buf.Reset()
+		require.NoError(t, env.PachClient.GetFile(repo, "master", "foo", 1024*1024-5, 10, &buf))
+		require.Equal(t, content[1024*1024-5:1024*1024+5], buf.String())
+
+		// Appending and overwriting
+		_, err = env.PachClient.PutFileWithProgress(repo, "master", "foo", strings.NewReader("bar"), nil)
+		require.NoError(t, err)
+		buf.Reset()
+		require.NoError(t, env.PachClient.GetFile(repo, "master", "foo", 0, 0, &buf))
+		require.Equal(t, content+"bar", buf.String())
+		_, err = env.PachClient.PutFileWithProgress(repo, "master", "foo", strings.NewReader("baz"), &pclient.UploadOptions{Overwrite: true})
+		require.NoError(t, err)
+		buf.Reset()
+		require.NoError(t, env.PachClient.GetFile(repo, "master", "foo", 0, 0, &buf))
+		require.Equal(t, "baz", buf.String())
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestPutFile(t *testing.T) {
 	t.Parallel()
 	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
@@ -2119,6 +2165,89 @@ func TestDeleteFile(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestDeleteFiles(t *testing.T) {
+	t.Parallel()
+	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
+		repo := "test"
+		require.NoError(t, env.PachClient.CreateRepo(repo))
+
+		for _, path := range []string{"/dir/a.tmp", "/dir/b.tmp", "/dir/c", "/log"} {
+			_, err := env.PachClient.PutFile(repo, "master", path, strings.NewReader(path))
+			require.NoError(t, err)
+		}
+
+		// Deleting from a branch whose HEAD is finished makes a single commit
+		commitInfos, err := env.PachClient.ListCommit(repo, "master", "", 0)
+		require.NoError(t, err)
+		require.NoError(t, env.PachClient.DeleteFiles(repo, "master", "/dir/*.tmp", "/log"))
+		newCommitInfos, err := env.PachClient.ListCommit(repo, "master", "", 0)
+		require.NoError(t, err)
+		require.Equal(t, len(commitInfos)+1, len(newCommitInfos))
+		fileInfos, err := env.PachClient.GlobFile(repo, "master", "/**")
+		require.NoError(t, err)
+		require.Equal(t, 2, len(fileInfos))
+		require.Equal(t, "/dir", fileInfos[0].File.Path)
+		require.Equal(t, "/dir/c", fileInfos[1].File.Path)
+
+		// Deleting from an open commit
+		commit, err := env.PachClient.StartCommit(repo, "master")
+		require.NoError(t, err)
+		require.NoError(t, env.PachClient.DeleteFiles(repo, commit.ID, "/dir/c", "/nonexistent"))
+		require.NoError(t, env.PachClient.FinishCommit(repo, commit.ID))
+		fileInfos, err = env.PachClient.ListFile(repo, commit.ID, "/")
+		require.NoError(t, err)
+		require.Equal(t, 0, len(fileInfos))
+
+		// Malformed glob patterns are rejected up front
+		require.YesError(t, env.PachClient.DeleteFiles(repo, "master", "/dir/[a"))
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestReplaceDirectory(t *testing.T) {
+	t.Parallel()
+	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
+		repo := "test"
+		require.NoError(t, env.PachClient.CreateRepo(repo))
+
+		for _, path := range []string{"/dir/old1", "/dir/old2", "/other"} {
+			_, err := env.PachClient.PutFile(repo, "master", path, strings.NewReader(path))
+			require.NoError(t, err)
+		}
+
+		// A finished commit's directories can't be replaced
+		require.YesError(t, env.PachClient.ReplaceDirectory(repo, "master", "/dir"))
+
+		commit, err := env.PachClient.StartCommit(repo, "master")
+		require.NoError(t, err)
+		require.NoError(t, env.PachClient.ReplaceDirectory(repo, commit.ID, "/dir"))
+		_, err = env.PachClient.PutFile(repo, commit.ID, "/dir/new", strings.NewReader("new"))
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFile(repo, commit.ID, "/other2", strings.NewReader("other2"))
+		require.NoError(t, err)
+
+		// Until the commit is finished, readers see the old directory (but
+		// writes outside of it)
+		fileInfos, err := env.PachClient.ListFile(repo, commit.ID, "/dir")
+		require.NoError(t, err)
+		require.Equal(t, 2, len(fileInfos))
+		_, err = env.PachClient.InspectFile(repo, commit.ID, "/other2")
+		require.NoError(t, err)
+
+		require.NoError(t, env.PachClient.FinishCommit(repo, commit.ID))
+		fileInfos, err = env.PachClient.ListFile(repo, commit.ID, "/dir")
+		require.NoError(t, err)
+		require.Equal(t, 1, len(fileInfos))
+		require.Equal(t, "/dir/new", fileInfos[0].File.Path)
+		var buf bytes.Buffer
+		require.NoError(t, env.PachClient.GetFile(repo, commit.ID, "/other", 0, 0, &buf))
+		require.Equal(t, "/other", buf.String())
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestDeleteDir(t *testing.T) {
 	t.Parallel()
 	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
@@ -2468,6 +2597,499 @@ func TestSubscribeCommit(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestChangeFeed(t *testing.T) {
+	t.Parallel()
+	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
+		repo := "test"
+		require.NoError(t, env.PachClient.CreateRepo(repo))
+
+		commit1, err := env.PachClient.StartCommit(repo, "master")
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFile(repo, commit1.ID, "a", strings.NewReader("foo"))
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFile(repo, commit1.ID, "b", strings.NewReader("foo"))
+		require.NoError(t, err)
+		require.NoError(t, env.PachClient.FinishCommit(repo, commit1.ID))
+
+		commit2, err := env.PachClient.StartCommit(repo, "master")
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFileOverwrite(repo, commit2.ID, "a", strings.NewReader("bar"), 0)
+		require.NoError(t, err)
+		require.NoError(t, env.PachClient.DeleteFile(repo, commit2.ID, "b"))
+		_, err = env.PachClient.PutFile(repo, commit2.ID, "c", strings.NewReader("bar"))
+		require.NoError(t, err)
+		require.NoError(t, env.PachClient.FinishCommit(repo, commit2.ID))
+
+		readFeed := func(cursor string, n int) []*pfs.ChangeFeedEvent {
+			var events []*pfs.ChangeFeedEvent
+			require.NoError(t, env.PachClient.ChangeFeed(repo, "master", cursor, func(event *pfs.ChangeFeedEvent) error {
+				events = append(events, event)
+				if len(events) == n {
+					return errutil.ErrBreak
+				}
+				return nil
+			}))
+			return events
+		}
+		summary := func(events []*pfs.ChangeFeedEvent) []string {
+			var result []string
+			for _, event := range events {
+				s := event.Commit.ID + " " + event.Type.String()
+				if event.File != nil {
+					s += " " + event.File.File.Path
+				}
+				result = append(result, s)
+			}
+			return result
+		}
+
+		events := readFeed("", 7)
+		require.Equal(t, []string{
+			commit1.ID + " PUT /a",
+			commit1.ID + " PUT /b",
+			commit1.ID + " COMMIT_FINISHED",
+			commit2.ID + " OVERWRITE /a",
+			commit2.ID + " DELETE /b",
+			commit2.ID + " PUT /c",
+			commit2.ID + " COMMIT_FINISHED",
+		}, summary(events))
+
+		// Resuming from a cursor skips everything up to and including its event
+		require.Equal(t, summary(events[4:]), summary(readFeed(events[3].Cursor, 3)))
+		require.Equal(t, summary(events[3:]), summary(readFeed(events[2].Cursor, 4)))
+
+		require.YesError(t, env.PachClient.ChangeFeed(repo, "master", "bogus", func(*pfs.ChangeFeedEvent) error {
+			return nil
+		}))
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestRepoCache(t *testing.T) {
+	t.Parallel()
+	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
+		require.NoError(t, env.PachClient.CreateRepo("a"))
+		require.NoError(t, env.PachClient.CreateRepo("b"))
+
+		cache := env.PachClient.NewRepoCache()
+		defer cache.Close()
+		require.NoError(t, cache.WaitSynced(env.Context))
+		repoNames := func() []string {
+			var names []string
+			for _, ri := range cache.ListRepo() {
+				names = append(names, ri.Repo.Name)
+			}
+			return names
+		}
+		require.Equal(t, []string{"a", "b"}, repoNames())
+
+		// The cache is updated by the watch, rather than by re-listing
+		require.NoError(t, env.PachClient.CreateRepo("c"))
+		require.NoError(t, env.PachClient.DeleteRepo("a", false))
+		require.NoErrorWithinTRetry(t, 30*time.Second, func() error {
+			if names := repoNames(); strings.Join(names, ",") != "b,c" {
+				return errors.Errorf("expected [b c], but cache contains %v", names)
+			}
+			return nil
+		})
+		require.Equal(t, "c", cache.InspectRepo("c").Repo.Name)
+		require.Nil(t, cache.InspectRepo("a"))
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestCommitTag(t *testing.T) {
+	t.Parallel()
+	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
+		c := env.PachClient
+		repo := "test"
+		require.NoError(t, c.CreateRepo(repo))
+		commit1, err := c.StartCommit(repo, "master")
+		require.NoError(t, err)
+		_, err = c.PutFile(repo, commit1.ID, "a", strings.NewReader("foo"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repo, commit1.ID))
+
+		// Tagging a branch tags its HEAD, and the tag can be read like a commit
+		require.NoError(t, c.TagCommit(repo, "master", "v1.0", "first version"))
+		tagInfo, err := c.InspectCommitTag(repo, "v1.0")
+		require.NoError(t, err)
+		require.Equal(t, commit1.ID, tagInfo.Commit.ID)
+		require.Equal(t, "first version", tagInfo.Description)
+		commitInfo, err := c.InspectCommit(repo, "v1.0")
+		require.NoError(t, err)
+		require.Equal(t, commit1.ID, commitInfo.Commit.ID)
+		require.Equal(t, []string{"v1.0"}, commitInfo.Tags)
+
+		// The tag doesn't move when the branch does
+		commit2, err := c.StartCommit(repo, "master")
+		require.NoError(t, err)
+		_, err = c.PutFile(repo, commit2.ID, "a", strings.NewReader("bar"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repo, commit2.ID))
+		var buf bytes.Buffer
+		require.NoError(t, c.GetFile(repo, "v1.0", "a", 0, 0, &buf))
+		require.Equal(t, "foo", buf.String())
+
+		// Tags can't be moved, and their names can't be reused by branches
+		require.YesError(t, c.TagCommit(repo, commit2.ID, "v1.0", ""))
+		require.YesError(t, c.CreateBranch(repo, "v1.0", commit2.ID, nil))
+		require.YesError(t, c.TagCommit(repo, commit2.ID, "master", ""))
+
+		// Tagged commits can't be deleted
+		require.YesError(t, c.DeleteCommit(repo, commit1.ID))
+
+		require.NoError(t, c.TagCommit(repo, commit2.ID, "v2.0", ""))
+		tagInfos, err := c.ListCommitTag(repo)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(tagInfos))
+
+		require.NoError(t, c.DeleteCommitTag(repo, "v1.0"))
+		_, err = c.InspectCommitTag(repo, "v1.0")
+		require.YesError(t, err)
+		commitInfo, err = c.InspectCommit(repo, commit1.ID)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(commitInfo.Tags))
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestMoveBranch(t *testing.T) {
+	t.Parallel()
+	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
+		c := env.PachClient
+		repo := "test"
+		require.NoError(t, c.CreateRepo(repo))
+		var commits []*pfs.Commit
+		for i := 0; i < 4; i++ {
+			commit, err := c.StartCommit(repo, "master")
+			require.NoError(t, err)
+			require.NoError(t, c.FinishCommit(repo, commit.ID))
+			commits = append(commits, commit)
+		}
+
+		// Branches can be created from ancestry expressions
+		require.NoError(t, c.CreateBranch(repo, "old", "master^3", nil))
+		branchInfo, err := c.InspectBranch(repo, "old")
+		require.NoError(t, err)
+		require.Equal(t, commits[0].ID, branchInfo.Head.ID)
+		require.NoError(t, c.CreateBranch(repo, "second", "master.2", nil))
+		branchInfo, err = c.InspectBranch(repo, "second")
+		require.NoError(t, err)
+		require.Equal(t, commits[1].ID, branchInfo.Head.ID)
+		require.YesError(t, c.CreateBranch(repo, "bad", "master~x", nil))
+		require.YesError(t, c.CreateBranch(repo, "bad", "master^10", nil))
+		require.NoError(t, c.CreateBranch(repo, "empty", "", nil))
+		require.YesError(t, c.CreateBranch(repo, "bad", "empty^", nil))
+
+		// MoveBranch is a compare-and-swap if an expected head is given
+		require.YesError(t, c.MoveBranch(repo, "old", commits[0].ID+"~~~~", ""))
+		require.YesError(t, c.MoveBranch(repo, "old", "master", commits[2].ID))
+		err = c.MoveBranch(repo, "old", "master", commits[1].ID)
+		require.YesError(t, err)
+		require.True(t, pfsserver.IsBranchHeadMismatchErr(err))
+		require.NoError(t, c.MoveBranch(repo, "old", "master^", "second^"))
+		branchInfo, err = c.InspectBranch(repo, "old")
+		require.NoError(t, err)
+		require.Equal(t, commits[2].ID, branchInfo.Head.ID)
+
+		// Only existing branches can be moved
+		require.YesError(t, c.MoveBranch(repo, "nonexistent", "master", ""))
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestCommitConflicts(t *testing.T) {
+	t.Parallel()
+	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
+		c := env.PachClient
+		repo := "test"
+		require.NoError(t, c.CreateRepo(repo))
+
+		// Starting a commit with an expected head is a compare-and-swap
+		commit1, err := c.StartCommitOnHead(repo, "master", "")
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommitOnHead(repo, commit1.ID))
+		_, err = c.StartCommitOnHead(repo, "master", "")
+		require.YesError(t, err)
+		require.True(t, pfsserver.IsBranchHeadMismatchErr(err))
+		commit2, err := c.StartCommitOnHead(repo, "master", commit1.ID)
+		require.NoError(t, err)
+
+		// A commit that's no longer its branch's head isn't finished
+		commit3, err := c.StartCommit(repo, "master")
+		require.NoError(t, err)
+		err = c.FinishCommitOnHead(repo, commit2.ID)
+		require.YesError(t, err)
+		require.True(t, pfsserver.IsCommitConflictErr(err))
+		commitInfo, err := c.InspectCommit(repo, commit2.ID)
+		require.NoError(t, err)
+		require.Nil(t, commitInfo.Finished)
+		require.NoError(t, c.DeleteCommit(repo, commit2.ID))
+		require.NoError(t, c.FinishCommitOnHead(repo, commit3.ID))
+
+		// CommitWithRebase commits on top of the branch's head
+		commit4, err := c.CommitWithRebase(repo, "master", func(commit *pfs.Commit) error {
+			_, err := c.PutFile(repo, commit.ID, "file", strings.NewReader("foo"))
+			return err
+		})
+		require.NoError(t, err)
+		commitInfo, err = c.InspectCommit(repo, commit4.ID)
+		require.NoError(t, err)
+		require.NotNil(t, commitInfo.Finished)
+		require.Equal(t, commit3.ID, commitInfo.ParentCommit.ID)
+		var buf bytes.Buffer
+		require.NoError(t, c.GetFile(repo, "master", "file", 0, 0, &buf))
+		require.Equal(t, "foo", buf.String())
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestDeferredBranch(t *testing.T) {
+	t.Parallel()
+	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
+		c := env.PachClient
+		require.NoError(t, c.CreateRepo("in"))
+		require.NoError(t, c.CreateRepo("out"))
+		require.NoError(t, c.CreateRepo("preview"))
+		commit := func(branch string) *pfs.Commit {
+			commit, err := c.StartCommit("in", branch)
+			require.NoError(t, err)
+			require.NoError(t, c.FinishCommit("in", commit.ID))
+			return commit
+		}
+		numCommits := func(repo string) int {
+			commitInfos, err := c.ListCommitByRepo(repo)
+			require.NoError(t, err)
+			return len(commitInfos)
+		}
+		commit("master")
+		require.NoError(t, c.CreateBranch("in", "staging", "master", nil))
+		require.NoError(t, c.CreateBranch("out", "master", "", []*pfs.Branch{pclient.NewBranch("in", "master")}))
+		require.NoError(t, c.CreateBranch("preview", "master", "", []*pfs.Branch{pclient.NewBranch("in", "staging")}))
+		require.Equal(t, 1, numCommits("out"))
+		require.Equal(t, 1, numCommits("preview"))
+
+		// Commits on a deferred branch aren't propagated
+		require.NoError(t, c.SetBranchDeferred("in", "staging", true))
+		branchInfo, err := c.InspectBranch("in", "staging")
+		require.NoError(t, err)
+		require.True(t, branchInfo.Deferred)
+		commit("staging")
+		staged := commit("staging")
+		require.Equal(t, 1, numCommits("out"))
+		require.Equal(t, 1, numCommits("preview"))
+
+		// ...until a branch that isn't deferred is moved to them
+		require.NoError(t, c.MoveBranch("in", "master", "staging", ""))
+		commitInfos, err := c.ListCommit("out", "master", "", 0)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(commitInfos))
+		var found bool
+		for _, prov := range commitInfos[0].Provenance {
+			found = found || prov.Commit.ID == staged.ID
+		}
+		require.True(t, found)
+		require.Equal(t, 1, numCommits("preview"))
+
+		// ...or the branch stops being deferred
+		require.NoError(t, c.SetBranchDeferred("in", "staging", false))
+		require.Equal(t, 2, numCommits("preview"))
+		commit("staging")
+		require.Equal(t, 3, numCommits("preview"))
+
+		// Output branches can't be deferred
+		require.YesError(t, c.SetBranchDeferred("out", "master", true))
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestSyncCommitToLocal(t *testing.T) {
+	t.Parallel()
+	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
+		c := env.PachClient
+		repo := "test"
+		require.NoError(t, c.CreateRepo(repo))
+		commit1, err := c.StartCommit(repo, "master")
+		require.NoError(t, err)
+		_, err = c.PutFile(repo, commit1.ID, "dir/a", strings.NewReader("foo"))
+		require.NoError(t, err)
+		_, err = c.PutFile(repo, commit1.ID, "dir/b", strings.NewReader("bar"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repo, commit1.ID))
+
+		dir, err := ioutil.TempDir("", "sync")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		stats, err := c.SyncCommitToLocal(repo, "master", "/dir", dir)
+		require.NoError(t, err)
+		require.Equal(t, 2, stats.FilesTransferred)
+		data, err := ioutil.ReadFile(filepath.Join(dir, "a"))
+		require.NoError(t, err)
+		require.Equal(t, "foo", string(data))
+
+		// Only changed files are downloaded, and deleted files are removed
+		commit2, err := c.StartCommit(repo, "master")
+		require.NoError(t, err)
+		_, err = c.PutFileOverwrite(repo, commit2.ID, "dir/a", strings.NewReader("baz"), 0)
+		require.NoError(t, err)
+		require.NoError(t, c.DeleteFile(repo, commit2.ID, "dir/b"))
+		require.NoError(t, c.FinishCommit(repo, commit2.ID))
+		stats, err = c.SyncCommitToLocal(repo, "master", "/dir", dir)
+		require.NoError(t, err)
+		require.Equal(t, 1, stats.FilesTransferred)
+		require.Equal(t, 1, stats.FilesDeleted)
+		_, err = os.Stat(filepath.Join(dir, "b"))
+		require.True(t, os.IsNotExist(err))
+		stats, err = c.SyncCommitToLocal(repo, "master", "/dir", dir)
+		require.NoError(t, err)
+		require.Equal(t, 0, stats.FilesTransferred)
+		require.Equal(t, 1, stats.FilesUnchanged)
+
+		// Local changes are uploaded incrementally
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "c"), []byte("new"), 0644))
+		commit3, err := c.StartCommit(repo, "master")
+		require.NoError(t, err)
+		stats, err = c.SyncLocalToCommit(dir, repo, commit3.ID, "/dir")
+		require.NoError(t, err)
+		require.Equal(t, 1, stats.FilesTransferred)
+		require.Equal(t, 1, stats.FilesUnchanged)
+		require.NoError(t, c.FinishCommit(repo, commit3.ID))
+		var buf bytes.Buffer
+		require.NoError(t, c.GetFile(repo, commit3.ID, "dir/c", 0, 0, &buf))
+		require.Equal(t, "new", buf.String())
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestTrash(t *testing.T) {
+	t.Parallel()
+	config := &serviceenv.PachdFullConfiguration{}
+	config.PFSTrashRetention = "1h"
+	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
+		c := env.PachClient
+		repo := "test"
+		require.NoError(t, c.CreateRepo(repo))
+		commit1, err := c.StartCommit(repo, "master")
+		require.NoError(t, err)
+		_, err = c.PutFile(repo, commit1.ID, "a", strings.NewReader("foo"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repo, commit1.ID))
+		commit2, err := c.StartCommit(repo, "master")
+		require.NoError(t, err)
+		_, err = c.PutFile(repo, commit2.ID, "b", strings.NewReader("bar"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repo, commit2.ID))
+
+		// Deleting and restoring the head commit moves master back to it
+		require.NoError(t, c.DeleteCommit(repo, commit2.ID))
+		deletedInfos, err := c.ListDeleted()
+		require.NoError(t, err)
+		require.Equal(t, 1, len(deletedInfos))
+		require.Equal(t, commit2.ID, deletedInfos[0].CommitInfos[0].Commit.ID)
+		branchInfo, err := c.InspectBranch(repo, "master")
+		require.NoError(t, err)
+		require.Equal(t, commit1.ID, branchInfo.Head.ID)
+		require.NoError(t, c.RestoreDeleted(deletedInfos[0].ID))
+		branchInfo, err = c.InspectBranch(repo, "master")
+		require.NoError(t, err)
+		require.Equal(t, commit2.ID, branchInfo.Head.ID)
+		commitInfo, err := c.InspectCommit(repo, commit2.ID)
+		require.NoError(t, err)
+		require.Equal(t, commit1.ID, commitInfo.ParentCommit.ID)
+		var buf bytes.Buffer
+		require.NoError(t, c.GetFile(repo, "master", "b", 0, 0, &buf))
+		require.Equal(t, "bar", buf.String())
+
+		// Deleting and restoring the repo brings back its commits
+		require.NoError(t, c.DeleteRepo(repo, false))
+		deletedInfos, err = c.ListDeleted()
+		require.NoError(t, err)
+		require.Equal(t, 1, len(deletedInfos))
+		require.Equal(t, repo, deletedInfos[0].RepoInfo.Repo.Name)
+		require.Equal(t, 2, len(deletedInfos[0].CommitInfos))
+		id := deletedInfos[0].ID
+		require.NoError(t, c.CreateRepo(repo))
+		require.YesError(t, c.RestoreDeleted(id))
+		require.NoError(t, c.DeleteRepo(repo, false))
+		deletedInfos, err = c.ListDeleted()
+		require.NoError(t, err)
+		require.Equal(t, 2, len(deletedInfos))
+		require.NoError(t, c.RestoreDeleted(id))
+		commitInfos, err := c.ListCommit(repo, "master", "", 0)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(commitInfos))
+		buf.Reset()
+		require.NoError(t, c.GetFile(repo, "master", "a", 0, 0, &buf))
+		require.Equal(t, "foo", buf.String())
+
+		// Items can't be restored twice, and purged items can't be restored
+		require.YesError(t, c.RestoreDeleted(id))
+		deletedInfos, err = c.ListDeleted()
+		require.NoError(t, err)
+		require.Equal(t, 1, len(deletedInfos))
+		require.NoError(t, c.PurgeDeleted(deletedInfos[0].ID))
+		require.YesError(t, c.RestoreDeleted(deletedInfos[0].ID))
+		deletedInfos, err = c.ListDeleted()
+		require.NoError(t, err)
+		require.Equal(t, 0, len(deletedInfos))
+		return nil
+	}, config)
+	require.NoError(t, err)
+}
+
+func TestFinishCommitStatus(t *testing.T) {
+	t.Parallel()
+	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
+		c := env.PachClient
+		repo := "test"
+		require.NoError(t, c.CreateRepo(repo))
+		commit1, err := c.StartCommit(repo, "master")
+		require.NoError(t, err)
+		for i := 0; i < 20; i++ {
+			_, err = c.PutFile(repo, commit1.ID, fmt.Sprintf("dir%d/file%d", i%4, i), strings.NewReader(fmt.Sprint(i)))
+			require.NoError(t, err)
+		}
+		require.NoError(t, c.FinishCommit(repo, commit1.ID))
+
+		commit2, err := c.StartCommit(repo, "master")
+		require.NoError(t, err)
+		progress, err := c.FinishCommitStatus(repo, commit2.ID)
+		require.NoError(t, err)
+		require.Equal(t, pfs.FinishCommitProgress_NOT_STARTED, progress.Phase)
+		// Writes to different directories are applied concurrently, but a
+		// delete of a directory is still applied before later writes to it
+		require.NoError(t, c.DeleteFile(repo, commit2.ID, "dir0"))
+		_, err = c.PutFile(repo, commit2.ID, "dir0/new", strings.NewReader("new"))
+		require.NoError(t, err)
+		_, err = c.PutFile(repo, commit2.ID, "dir1/file1", strings.NewReader("changed"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repo, commit2.ID))
+
+		progress, err = c.FinishCommitStatus(repo, commit2.ID)
+		require.NoError(t, err)
+		require.Equal(t, pfs.FinishCommitProgress_FINISHED, progress.Phase)
+		fileInfos, err := c.ListFile(repo, commit2.ID, "dir0")
+		require.NoError(t, err)
+		require.Equal(t, 1, len(fileInfos))
+		fileInfos, err = c.GlobFile(repo, commit2.ID, "/*/*")
+		require.NoError(t, err)
+		require.Equal(t, 16, len(fileInfos))
+		var buf bytes.Buffer
+		require.NoError(t, c.GetFile(repo, commit2.ID, "dir1/file1", 0, 0, &buf))
+		require.Equal(t, "1changed", buf.String())
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestInspectRepoSimple(t *testing.T) {
 	t.Parallel()
 	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
@@ -3474,6 +4096,105 @@ func TestPutFileHeaderRecordsBasic(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestGetFileRecords(t *testing.T) {
+	t.Parallel()
+	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
+		repo := tu.UniqueString("TestGetFileRecords")
+		require.NoError(t, env.PachClient.CreateRepo(repo))
+
+		// Two records per split file, so pages don't line up with split files
+		_, err := env.PachClient.PutFileSplit(repo, "master", "lines", pfs.Delimiter_LINE, 2, 0, 0, false,
+			strings.NewReader("a\nb\nc\nd\ne\n"))
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFileSplit(repo, "master", "data", pfs.Delimiter_CSV, 0, 0, 1, false,
+			strings.NewReader("A,B\n"+
+				"1,2\n"+
+				"3,4\n"+
+				"5,6\n"))
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFile(repo, "master", "plain", strings.NewReader("x\ny\nz\n"))
+		require.NoError(t, err)
+
+		var contents bytes.Buffer
+		require.NoError(t, env.PachClient.GetFileRecords(repo, "master", "lines", pfs.Delimiter_LINE, 1, 3, &contents))
+		require.Equal(t, "b\nc\nd\n", contents.String())
+		contents.Reset()
+		require.NoError(t, env.PachClient.GetFileRecords(repo, "master", "lines", pfs.Delimiter_LINE, 3, 0, &contents))
+		require.Equal(t, "d\ne\n", contents.String())
+		contents.Reset()
+		require.NoError(t, env.PachClient.GetFileRecords(repo, "master", "lines", pfs.Delimiter_LINE, 10, 0, &contents))
+		require.Equal(t, "", contents.String())
+
+		// The header is returned with each page, but isn't a record
+		contents.Reset()
+		require.NoError(t, env.PachClient.GetFileRecords(repo, "master", "data", pfs.Delimiter_CSV, 1, 1, &contents))
+		require.Equal(t, "A,B\n3,4\n", contents.String())
+
+		// Records of a file that wasn't split
+		contents.Reset()
+		require.NoError(t, env.PachClient.GetFileRecords(repo, "master", "plain", pfs.Delimiter_LINE, 2, 1, &contents))
+		require.Equal(t, "z\n", contents.String())
+
+		require.YesError(t, env.PachClient.GetFileRecords(repo, "master", "lines", pfs.Delimiter_LINE, -1, 0, &contents))
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestGetFileAncestry(t *testing.T) {
+	t.Parallel()
+	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
+		repo := tu.UniqueString("TestGetFileAncestry")
+		require.NoError(t, env.PachClient.CreateRepo(repo))
+
+		base, err := env.PachClient.StartCommit(repo, "master")
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFile(repo, base.ID, "config", strings.NewReader("base\n"))
+		require.NoError(t, err)
+		require.NoError(t, env.PachClient.FinishCommit(repo, base.ID))
+
+		// The overlay drops "config" and adds "override", and its child
+		// changes nothing
+		overlay, err := env.PachClient.StartCommit(repo, "master")
+		require.NoError(t, err)
+		require.NoError(t, env.PachClient.DeleteFile(repo, overlay.ID, "config"))
+		_, err = env.PachClient.PutFile(repo, overlay.ID, "override", strings.NewReader("overlay\n"))
+		require.NoError(t, err)
+		require.NoError(t, env.PachClient.FinishCommit(repo, overlay.ID))
+		head, err := env.PachClient.StartCommit(repo, "master")
+		require.NoError(t, err)
+		require.NoError(t, env.PachClient.FinishCommit(repo, head.ID))
+
+		var contents bytes.Buffer
+		commit, err := env.PachClient.GetFileAncestry(repo, "master", "config", 2, &contents)
+		require.NoError(t, err)
+		require.Equal(t, base.ID, commit.ID)
+		require.Equal(t, "base\n", contents.String())
+
+		// Files in the commit itself are read from it
+		contents.Reset()
+		commit, err = env.PachClient.GetFileAncestry(repo, "master", "override", 2, &contents)
+		require.NoError(t, err)
+		require.Equal(t, head.ID, commit.ID)
+		require.Equal(t, "overlay\n", contents.String())
+
+		fileInfo, err := env.PachClient.InspectFileAncestry(repo, "master", "config", 2)
+		require.NoError(t, err)
+		require.Equal(t, base.ID, fileInfo.File.Commit.ID)
+
+		// The search is bounded by the depth
+		_, err = env.PachClient.InspectFileAncestry(repo, "master", "config", 1)
+		require.YesError(t, err)
+		require.True(t, pfsserver.IsFileNotFoundErr(err))
+		_, err = env.PachClient.GetFileAncestry(repo, "master", "missing", 10, &contents)
+		require.YesError(t, err)
+		_, err = env.PachClient.InspectFileAncestry(repo, "master", "config", -1)
+		require.YesError(t, err)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 // TestGetFileGlobMultipleHeaders tests the case where a commit contains two
 // header/footer directories, say a/* and b/*, and a user calls
 // GetFile("/*/*"). We expect the data to come back
@@ -6105,6 +6826,74 @@ func TestFsckFix(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestArchiveProvenance(t *testing.T) {
+	t.Parallel()
+	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
+		c := env.PachClient
+		input := tu.UniqueString("input")
+		output1 := tu.UniqueString("output1")
+		output2 := tu.UniqueString("output2")
+		require.NoError(t, c.CreateRepo(input))
+		require.NoError(t, c.CreateRepo(output1))
+		require.NoError(t, c.CreateRepo(output2))
+		require.NoError(t, c.CreateBranch(output1, "master", "", []*pfs.Branch{pclient.NewBranch(input, "master")}))
+		require.NoError(t, c.CreateBranch(output2, "master", "", []*pfs.Branch{pclient.NewBranch(output1, "master")}))
+		numCommits := 3
+		for i := 0; i < numCommits; i++ {
+			_, err := c.PutFile(input, "master", "file", strings.NewReader("1"))
+			require.NoError(t, err)
+		}
+		// Only finished commits are archived
+		for _, repo := range []string{output1, output2} {
+			commitInfos, err := c.ListCommit(repo, "master", "", 0)
+			require.NoError(t, err)
+			require.Equal(t, numCommits, len(commitInfos))
+			for _, commitInfo := range commitInfos {
+				require.NoError(t, c.FinishCommit(repo, commitInfo.Commit.ID))
+			}
+		}
+		require.NoError(t, c.DeleteRepo(input, true))
+		require.YesError(t, c.FsckFastExit())
+
+		// A dry run reports the dangling provenance, but doesn't change it
+		response, err := c.ArchiveProvenance(true)
+		require.NoError(t, err)
+		require.Equal(t, int64(2*numCommits), response.CommitsRewritten)
+		require.Equal(t, int64(2*numCommits), response.EdgesArchived)
+		require.Equal(t, []string{input}, response.ArchivedRepos)
+		require.YesError(t, c.FsckFastExit())
+
+		response, err = c.ArchiveProvenance(false)
+		require.NoError(t, err)
+		require.Equal(t, int64(2*numCommits), response.EdgesArchived)
+		require.NoError(t, c.FsckFastExit())
+
+		// output2's provenance on output1 is kept, and its provenance on the
+		// deleted repo is recorded in a tombstone
+		commitInfo, err := c.InspectCommit(output2, "master")
+		require.NoError(t, err)
+		require.Equal(t, 1, len(commitInfo.Provenance))
+		require.Equal(t, output1, commitInfo.Provenance[0].Commit.Repo.Name)
+		require.Equal(t, 1, len(commitInfo.ArchivedProvenance))
+		tombstone := commitInfo.ArchivedProvenance[0]
+		require.Equal(t, input, tombstone.Repo)
+		require.Equal(t, "master", tombstone.Branch)
+		require.Equal(t, 1, len(tombstone.CommitIDs))
+		require.NotNil(t, tombstone.Archived)
+
+		// Nothing is left to archive
+		response, err = c.ArchiveProvenance(false)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), response.EdgesArchived)
+
+		// The repos can now be deleted without --force
+		require.NoError(t, c.DeleteRepo(output2, false))
+		require.NoError(t, c.DeleteRepo(output1, false))
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestPutFileAtomic(t *testing.T) {
 	t.Parallel()
 	err := testpachd.WithRealEnv(func(env *testpachd.RealEnv) error {
@@ -0,0 +1,417 @@
+package server
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/tenant"
+	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/tenantutil"
+	txnenv "github.com/pachyderm/pachyderm/src/server/pkg/transactionenv"
+	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+)
+
+// The trash holds deleted repos and commits until 'd.trashRetention' after
+// they were deleted. Items in the trash can be restored with RestoreDeleted,
+// and the objects they reference aren't garbage collected until they expire
+// (and are purged).
+
+// newDeletedInfo returns a DeletedInfo for an item that the caller in
+// 'txnCtx' is deleting now.
+func (d *driver) newDeletedInfo(txnCtx *txnenv.TransactionContext, t string) (*pfs.DeletedInfo, error) {
+	now := time.Now()
+	deleted, err := types.TimestampProto(now)
+	if err != nil {
+		return nil, err
+	}
+	expires, err := types.TimestampProto(now.Add(d.trashRetention))
+	if err != nil {
+		return nil, err
+	}
+	deletedInfo := &pfs.DeletedInfo{
+		ID:      uuid.NewWithoutDashes(),
+		Deleted: deleted,
+		Expires: expires,
+		Tenant:  t,
+	}
+	me, err := txnCtx.Client.WhoAmI(txnCtx.ClientContext, &auth.WhoAmIRequest{})
+	if err != nil && !auth.IsErrNotActivated(err) {
+		return nil, grpcutil.ScrubGRPC(err)
+	} else if err == nil {
+		deletedInfo.DeletedBy = me.Username
+	}
+	return deletedInfo, nil
+}
+
+// trashRepo puts a repo that's being deleted, along with its branches and
+// commits, in the trash.
+func (d *driver) trashRepo(txnCtx *txnenv.TransactionContext, repoInfo *pfs.RepoInfo, branchInfos []*pfs.BranchInfo, commitInfos map[string]*pfs.CommitInfo) error {
+	deletedInfo, err := d.newDeletedInfo(txnCtx, repoInfo.Tenant)
+	if err != nil {
+		return err
+	}
+	deletedInfo.RepoInfo = proto.Clone(repoInfo).(*pfs.RepoInfo)
+	deletedInfo.RepoInfo.AuthInfo = nil
+	for _, branchInfo := range branchInfos {
+		deletedInfo.BranchInfos = append(deletedInfo.BranchInfos, proto.Clone(branchInfo).(*pfs.BranchInfo))
+	}
+	for _, commitInfo := range commitInfos {
+		// The repo's tags are deleted with it, rather than put in the trash
+		commitInfo = proto.Clone(commitInfo).(*pfs.CommitInfo)
+		commitInfo.Tags = nil
+		deletedInfo.CommitInfos = append(deletedInfo.CommitInfos, commitInfo)
+	}
+	sort.Slice(deletedInfo.CommitInfos, func(i, j int) bool {
+		return deletedInfo.CommitInfos[i].Commit.ID < deletedInfo.CommitInfos[j].Commit.ID
+	})
+	return d.trash.ReadWrite(txnCtx.Stm).Put(deletedInfo.ID, deletedInfo)
+}
+
+// trashCommit puts an input commit that's being deleted in the trash, along
+// with the branches whose head it is. The commit's downstream output commits
+// aren't kept, as they're recomputed if the commit is restored.
+func (d *driver) trashCommit(txnCtx *txnenv.TransactionContext, commitInfo *pfs.CommitInfo) error {
+	repoInfo := &pfs.RepoInfo{}
+	if err := d.repos.ReadWrite(txnCtx.Stm).Get(commitInfo.Commit.Repo.Name, repoInfo); err != nil {
+		return err
+	}
+	deletedInfo, err := d.newDeletedInfo(txnCtx, repoInfo.Tenant)
+	if err != nil {
+		return err
+	}
+	deletedInfo.CommitInfos = []*pfs.CommitInfo{proto.Clone(commitInfo).(*pfs.CommitInfo)}
+	branches := d.branches(commitInfo.Commit.Repo.Name).ReadWrite(txnCtx.Stm)
+	for _, branch := range repoInfo.Branches {
+		branchInfo := &pfs.BranchInfo{}
+		if err := branches.Get(branch.Name, branchInfo); err != nil {
+			if col.IsErrNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if branchInfo.Head != nil && branchInfo.Head.ID == commitInfo.Commit.ID {
+			deletedInfo.BranchInfos = append(deletedInfo.BranchInfos, branchInfo)
+		}
+	}
+	return d.trash.ReadWrite(txnCtx.Stm).Put(deletedInfo.ID, deletedInfo)
+}
+
+func (d *driver) listDeleted(pachClient *client.APIClient) (*pfs.ListDeletedResponse, error) {
+	ctx := pachClient.Ctx()
+	t, err := tenant.FromAuth(ctx, pachClient.AuthAPIClient)
+	if err != nil {
+		return nil, err
+	}
+	result := &pfs.ListDeletedResponse{}
+	deletedInfo := &pfs.DeletedInfo{}
+	if err := d.trash.ReadOnly(ctx).List(deletedInfo, col.DefaultOptions, func(string) error {
+		if t != "" && deletedInfo.Tenant != t {
+			return nil
+		}
+		result.DeletedInfo = append(result.DeletedInfo, proto.Clone(deletedInfo).(*pfs.DeletedInfo))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// getDeleted reads the item 'id' from the trash, and checks that the caller
+// may restore or purge it: only the user who deleted an item, or an admin,
+// may do so.
+func (d *driver) getDeleted(txnCtx *txnenv.TransactionContext, id string, op string) (*pfs.DeletedInfo, error) {
+	deletedInfo := &pfs.DeletedInfo{}
+	if err := d.trash.ReadWrite(txnCtx.Stm).Get(id, deletedInfo); err != nil {
+		if col.IsErrNotFound(err) {
+			return nil, errors.Errorf("deleted item %q not found", id)
+		}
+		return nil, err
+	}
+	me, err := txnCtx.Client.WhoAmI(txnCtx.ClientContext, &auth.WhoAmIRequest{})
+	if auth.IsErrNotActivated(err) {
+		return deletedInfo, nil
+	} else if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	if me.Tenant != "" && deletedInfo.Tenant != me.Tenant {
+		return nil, errors.Errorf("deleted item %q not found", id)
+	}
+	if !me.IsAdmin && me.Username != deletedInfo.DeletedBy {
+		return nil, &auth.ErrNotAuthorized{Subject: me.Username, AdminOp: op}
+	}
+	return deletedInfo, nil
+}
+
+func (d *driver) restoreDeleted(txnCtx *txnenv.TransactionContext, id string) error {
+	deletedInfo, err := d.getDeleted(txnCtx, id, "RestoreDeleted")
+	if err != nil {
+		return err
+	}
+	if deletedInfo.RepoInfo != nil {
+		err = d.restoreRepo(txnCtx, deletedInfo)
+	} else {
+		err = d.restoreCommit(txnCtx, deletedInfo)
+	}
+	if err != nil {
+		return err
+	}
+	return d.trash.ReadWrite(txnCtx.Stm).Delete(id)
+}
+
+// restoreRepo recreates a deleted repo with the branches and commits it had
+// when it was deleted. The repo is restored on its own: its provenance and
+// subvenance on other repos, which may have changed since, are dropped.
+func (d *driver) restoreRepo(txnCtx *txnenv.TransactionContext, deletedInfo *pfs.DeletedInfo) error {
+	repo := deletedInfo.RepoInfo.Repo
+	repos := d.repos.ReadWrite(txnCtx.Stm)
+	if err := repos.Get(repo.Name, &pfs.RepoInfo{}); err == nil {
+		return pfsserver.ErrRepoExists{repo}
+	} else if !col.IsErrNotFound(err) {
+		return errors.Wrapf(err, "error checking whether \"%s\" exists", repo.Name)
+	}
+	me, err := txnCtx.Client.WhoAmI(txnCtx.ClientContext, &auth.WhoAmIRequest{})
+	if err != nil && !auth.IsErrNotActivated(err) {
+		return grpcutil.ScrubGRPC(err)
+	} else if err == nil {
+		// As with CreateRepo, the caller becomes the repo's sole owner
+		if _, err := txnCtx.Auth().SetACLInTransaction(txnCtx, &auth.SetACLRequest{
+			Repo: repo.Name,
+			Entries: []*auth.ACLEntry{{
+				Username: me.Username,
+				Scope:    auth.Scope_OWNER,
+			}},
+		}); err != nil {
+			return errors.Wrapf(grpcutil.ScrubGRPC(err), "could not create ACL for restored repo \"%s\"", repo.Name)
+		}
+	}
+	restrictToRepo(repo.Name, deletedInfo.BranchInfos, deletedInfo.CommitInfos)
+	if err := tenantutil.Reserve(d.tenantRepos.ReadWriteInt(txnCtx.Stm), deletedInfo.RepoInfo.Tenant, "repos", d.env.TenantMaxRepos); err != nil {
+		return err
+	}
+	if err := repos.Create(repo.Name, deletedInfo.RepoInfo); err != nil {
+		return err
+	}
+	branches := d.branches(repo.Name).ReadWrite(txnCtx.Stm)
+	for _, branchInfo := range deletedInfo.BranchInfos {
+		if err := branches.Put(branchInfo.Branch.Name, branchInfo); err != nil {
+			return err
+		}
+	}
+	commits := d.commits(repo.Name).ReadWrite(txnCtx.Stm)
+	for _, commitInfo := range deletedInfo.CommitInfos {
+		if err := commits.Put(commitInfo.Commit.ID, commitInfo); err != nil {
+			return err
+		}
+		if commitInfo.Finished == nil {
+			if err := d.openCommits.ReadWrite(txnCtx.Stm).Put(commitInfo.Commit.ID, commitInfo.Commit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// restrictToRepo removes the references to repos other than 'repo' from the
+// provenance and subvenance of its branches and commits.
+func restrictToRepo(repo string, branchInfos []*pfs.BranchInfo, commitInfos []*pfs.CommitInfo) {
+	inRepo := func(branches []*pfs.Branch) []*pfs.Branch {
+		var result []*pfs.Branch
+		for _, branch := range branches {
+			if branch.Repo.Name == repo {
+				result = append(result, branch)
+			}
+		}
+		return result
+	}
+	for _, branchInfo := range branchInfos {
+		branchInfo.Provenance = inRepo(branchInfo.Provenance)
+		branchInfo.DirectProvenance = inRepo(branchInfo.DirectProvenance)
+		branchInfo.Subvenance = inRepo(branchInfo.Subvenance)
+	}
+	for _, commitInfo := range commitInfos {
+		var provenance []*pfs.CommitProvenance
+		for _, prov := range commitInfo.Provenance {
+			if prov.Commit.Repo.Name == repo {
+				provenance = append(provenance, prov)
+			}
+		}
+		commitInfo.Provenance = provenance
+		var subvenance []*pfs.CommitRange
+		for _, subv := range commitInfo.Subvenance {
+			if subv.Lower.Repo.Name == repo {
+				subvenance = append(subvenance, subv)
+			}
+		}
+		commitInfo.Subvenance = subvenance
+	}
+}
+
+// sameCommit returns true if 'a' and 'b' are both nil, or are the same commit
+func sameCommit(a, b *pfs.Commit) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.ID == b.ID
+}
+
+// restoreCommit reinserts a deleted input commit between its parent (if it
+// still exists) and the children that were moved to that parent when it was
+// deleted, and moves the branches it was the head of back to it if they
+// haven't moved since. The branches' downstream commits are then recomputed.
+func (d *driver) restoreCommit(txnCtx *txnenv.TransactionContext, deletedInfo *pfs.DeletedInfo) error {
+	if len(deletedInfo.CommitInfos) != 1 {
+		return errors.Errorf("malformed deleted commit %q", deletedInfo.ID)
+	}
+	commitInfo := deletedInfo.CommitInfos[0]
+	commit := commitInfo.Commit
+	repos := d.repos.ReadWrite(txnCtx.Stm)
+	repoInfo := &pfs.RepoInfo{}
+	if err := repos.Get(commit.Repo.Name, repoInfo); err != nil {
+		if col.IsErrNotFound(err) {
+			return errors.Errorf("cannot restore commit %s/%s because its repo no longer exists", commit.Repo.Name, commit.ID)
+		}
+		return err
+	}
+	if err := d.checkIsAuthorizedInTransaction(txnCtx, commit.Repo, auth.Scope_WRITER); err != nil {
+		return err
+	}
+	commits := d.commits(commit.Repo.Name).ReadWrite(txnCtx.Stm)
+	if err := commits.Get(commit.ID, &pfs.CommitInfo{}); err == nil {
+		return pfsserver.ErrCommitExists{commit}
+	} else if !col.IsErrNotFound(err) {
+		return err
+	}
+
+	// Find the commit's parent, if it still exists
+	parent := commitInfo.ParentCommit
+	if parent != nil {
+		if err := commits.Get(parent.ID, &pfs.CommitInfo{}); err != nil {
+			if !col.IsErrNotFound(err) {
+				return err
+			}
+			parent = nil
+		}
+	}
+	commitInfo.ParentCommit = parent
+
+	// Move the children that deleteCommit moved to 'parent' back
+	children := make(map[string]bool)
+	var childCommits []*pfs.Commit
+	for _, child := range commitInfo.ChildCommits {
+		childInfo := &pfs.CommitInfo{}
+		if err := commits.Update(child.ID, childInfo, func() error {
+			if sameCommit(childInfo.ParentCommit, parent) {
+				childInfo.ParentCommit = commit
+				children[child.ID] = true
+				childCommits = append(childCommits, child)
+			}
+			return nil
+		}); err != nil && !col.IsErrNotFound(err) {
+			return err
+		}
+	}
+	commitInfo.ChildCommits = childCommits
+	if parent != nil {
+		parentInfo := &pfs.CommitInfo{}
+		if err := commits.Update(parent.ID, parentInfo, func() error {
+			var parentChildren []*pfs.Commit
+			for _, child := range parentInfo.ChildCommits {
+				if !children[child.ID] {
+					parentChildren = append(parentChildren, child)
+				}
+			}
+			parentInfo.ChildCommits = append(parentChildren, commit)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	// The commit's downstream commits were deleted, and are recomputed below.
+	// Its provenance is dropped, as only input commits (whose only provenance
+	// may be the spec repo) are deleted.
+	commitInfo.Provenance = nil
+	commitInfo.Subvenance = nil
+	if err := commits.Create(commit.ID, commitInfo); err != nil {
+		return err
+	}
+	if commitInfo.Finished == nil {
+		if err := d.openCommits.ReadWrite(txnCtx.Stm).Put(commit.ID, commit); err != nil {
+			return err
+		}
+	}
+
+	// Move the branches back
+	var moved []*pfs.Branch
+	branches := d.branches(commit.Repo.Name).ReadWrite(txnCtx.Stm)
+	for _, deletedBranch := range deletedInfo.BranchInfos {
+		branchInfo := &pfs.BranchInfo{}
+		if err := branches.Update(deletedBranch.Branch.Name, branchInfo, func() error {
+			if sameCommit(branchInfo.Head, parent) {
+				branchInfo.Head = commit
+				moved = append(moved, branchInfo.Branch)
+			}
+			return nil
+		}); err != nil && !col.IsErrNotFound(err) {
+			return err
+		}
+	}
+	for _, branch := range moved {
+		if branch.Name == "master" {
+			repoInfo.SizeBytes = commitInfo.SizeBytes
+			if err := repos.Put(commit.Repo.Name, repoInfo); err != nil {
+				return err
+			}
+		}
+		if err := txnCtx.PropagateCommit(branch, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeDeleted permanently deletes the item 'id' from the trash, or, if 'id'
+// is unset, all of the items that have expired.
+func (d *driver) purgeDeleted(txnCtx *txnenv.TransactionContext, id string) error {
+	trash := d.trash.ReadWrite(txnCtx.Stm)
+	if id != "" {
+		if _, err := d.getDeleted(txnCtx, id, "PurgeDeleted"); err != nil {
+			return err
+		}
+		return trash.Delete(id)
+	}
+	var expired []string
+	deletedInfo := &pfs.DeletedInfo{}
+	if err := d.trash.ReadOnly(txnCtx.ClientContext).List(deletedInfo, col.DefaultOptions, func(id string) error {
+		if trashExpired(deletedInfo, time.Now()) {
+			expired = append(expired, id)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, id := range expired {
+		if err := trash.Delete(id); err != nil && !col.IsErrNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// trashExpired returns true if 'deletedInfo' may be purged at time 'now'
+func trashExpired(deletedInfo *pfs.DeletedInfo, now time.Time) bool {
+	expires, err := types.TimestampFromProto(deletedInfo.Expires)
+	if err != nil {
+		return true
+	}
+	return !now.Before(expires)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
 	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
 	txnenv "github.com/pachyderm/pachyderm/src/server/pkg/transactionenv"
 )
 
@@ -32,14 +33,15 @@ func (d *driver) triggerCommit(
 	if err := commits.Get(commit.ID, newHead); err != nil {
 		return nil, err
 	}
-	// find which branches this commit is the head of
+	// find which branches this commit is the head of. The new heads of
+	// deferred branches don't fire triggers.
 	headBranches := make(map[string]bool)
 	bi := &pfs.BranchInfo{}
 	for _, b := range repoInfo.Branches {
 		if err := branches.Get(b.Name, bi); err != nil {
 			return nil, err
 		}
-		if bi.Head != nil && bi.Head.ID == commit.ID {
+		if bi.Head != nil && bi.Head.ID == commit.ID && !bi.Deferred {
 			headBranches[b.Name] = true
 		}
 	}
@@ -77,8 +79,10 @@ func (d *driver) triggerCommit(
 					}); err != nil {
 						return err
 					}
-					result = append(result, client.NewBranch(commit.Repo.Name, branch))
-					headBranches[bi.Branch.Name] = true
+					if !bi.Deferred {
+						result = append(result, client.NewBranch(commit.Repo.Name, branch))
+						headBranches[bi.Branch.Name] = true
+					}
 				}
 			}
 		}
@@ -89,12 +93,66 @@ func (d *driver) triggerCommit(
 			return nil, err
 		}
 	}
+	validated, err := d.triggerValidatedBranches(txnCtx, newHead)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, validated...), nil
+}
+
+// triggerValidatedBranches is called when a commit is finished. If the commit
+// is the output of a validation pipeline whose assertions passed, it moves the
+// branches whose triggers wait for that pipeline to the commits that the
+// pipeline validated, and returns them.
+func (d *driver) triggerValidatedBranches(txnCtx *txnenv.TransactionContext, commitInfo *pfs.CommitInfo) ([]*pfs.Branch, error) {
+	if commitInfo.Metadata[pfs.ValidationMetadataKey] != pfs.ValidationPassed {
+		return nil, nil
+	}
+	// A validation pipeline's output repo has the pipeline's name
+	pipeline := commitInfo.Commit.Repo.Name
+	var result []*pfs.Branch
+	for _, prov := range commitInfo.Provenance {
+		if prov.Commit.Repo.Name == ppsconsts.SpecRepo || prov.Branch == nil {
+			continue
+		}
+		repoInfo := &pfs.RepoInfo{}
+		if err := d.repos.ReadWrite(txnCtx.Stm).Get(prov.Commit.Repo.Name, repoInfo); err != nil {
+			if col.IsErrNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		branches := d.branches(prov.Commit.Repo.Name).ReadWrite(txnCtx.Stm)
+		for _, b := range repoInfo.Branches {
+			bi := &pfs.BranchInfo{}
+			if err := branches.Get(b.Name, bi); err != nil {
+				return nil, err
+			}
+			if bi.Trigger == nil || bi.Trigger.Validation != pipeline || bi.Trigger.Branch != prov.Branch.Name {
+				continue
+			}
+			if bi.Head != nil && bi.Head.ID == prov.Commit.ID {
+				continue
+			}
+			if err := branches.Update(bi.Name, bi, func() error {
+				bi.Head = prov.Commit
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+			result = append(result, client.NewBranch(prov.Commit.Repo.Name, bi.Name))
+		}
+	}
 	return result, nil
 }
 
 // isTriggered checks to see if a branch should be updated from oldHead to
 // newHead based on a trigger.
 func (d *driver) isTriggered(txnCtx *txnenv.TransactionContext, t *pfs.Trigger, oldHead, newHead *pfs.CommitInfo) (bool, error) {
+	if t.Validation != "" {
+		// Only triggerValidatedBranches moves branches with validation triggers
+		return false, nil
+	}
 	result := t.All
 	merge := func(cond bool) {
 		if t.All {
@@ -174,6 +232,9 @@ func (d *driver) validateTrigger(txnCtx *txnenv.TransactionContext, branch *pfs.
 	if trigger.Commits < 0 {
 		return errors.Errorf("can't trigger on a negative number of commits")
 	}
+	if trigger.Validation != "" && (trigger.All || trigger.CronSpec != "" || trigger.Size_ != "" || trigger.Commits != 0) {
+		return errors.Errorf("triggers that wait for a validation pipeline can't have other conditions")
+	}
 	bis, err := d.listBranch(txnCtx.Client, branch.Repo, false)
 	if err != nil {
 		return err
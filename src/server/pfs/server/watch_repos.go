@@ -0,0 +1,52 @@
+package server
+
+import (
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/tenant"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
+	"github.com/pachyderm/pachyderm/src/server/pkg/watch"
+)
+
+// watchRepos calls 'f' with a PUT event for each existing repo, then a SYNCED
+// event, and then an event for each change to the set of repos, until 'f'
+// returns an error or the client goes away. Like listRepo, it skips the spec
+// repo and the repos of other tenants.
+func (d *driver) watchRepos(pachClient *client.APIClient, f func(*pfs.RepoEvent) error) error {
+	ctx := pachClient.Ctx()
+	t, err := tenant.FromAuth(ctx, pachClient.AuthAPIClient)
+	if err != nil {
+		return err
+	}
+	// The repos that have been sent, so that deletes of repos that weren't
+	// (which only carry the repo's name) aren't sent either
+	sent := make(map[string]bool)
+	return d.repos.ReadOnly(ctx).WatchF(func(e *watch.Event) error {
+		switch e.Type {
+		case watch.EventSynced:
+			return f(&pfs.RepoEvent{Type: pfs.RepoEvent_SYNCED})
+		case watch.EventDelete:
+			name := string(e.Key)
+			if !sent[name] {
+				return nil
+			}
+			delete(sent, name)
+			return f(&pfs.RepoEvent{
+				Type:     pfs.RepoEvent_DELETE,
+				RepoInfo: &pfs.RepoInfo{Repo: client.NewRepo(name)},
+			})
+		case watch.EventPut:
+			var name string
+			repoInfo := &pfs.RepoInfo{}
+			if err := e.Unmarshal(&name, repoInfo); err != nil {
+				return err
+			}
+			if name == ppsconsts.SpecRepo || (t != "" && repoInfo.Tenant != t) {
+				return nil
+			}
+			sent[name] = true
+			return f(&pfs.RepoEvent{Type: pfs.RepoEvent_PUT, RepoInfo: repoInfo})
+		}
+		return nil
+	}, watch.WithSynced())
+}
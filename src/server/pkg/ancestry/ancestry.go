@@ -57,7 +57,7 @@ func Parse(s string) (string, int, error) {
 	// (the same) separators and therefore uses the correct ancestry
 	// syntax.
 	if sep == '.' {
-		return s[:sepIndex], -1*len(s) - sepIndex, nil
+		return s[:sepIndex], -1 * (len(s) - sepIndex), nil
 	}
 	return s[:sepIndex], len(s) - sepIndex, nil
 }
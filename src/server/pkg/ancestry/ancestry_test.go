@@ -21,6 +21,9 @@ var ancestryTests = []struct {
 	{"foo^-1000000", "foo", -1000000},
 	{"foo.1000000", "foo", -1000000},
 	{"foo.-1000000", "foo", 1000000},
+	{"foo^^^", "foo", 3},
+	{"foo~~", "foo", 2},
+	{"foo...", "foo", -3},
 }
 
 func TestAncestry(t *testing.T) {
@@ -32,6 +35,13 @@ func TestAncestry(t *testing.T) {
 	}
 }
 
+func TestAncestryErrors(t *testing.T) {
+	for _, in := range []string{"foo~bar", "foo^~", "foo.^", "foo^3^"} {
+		_, _, err := Parse(in)
+		require.YesError(t, err, "Parse(%q)", in)
+	}
+}
+
 var validNames = []string{
 	"foo",
 	"foo2",
@@ -15,6 +15,7 @@ import (
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
 	"github.com/pachyderm/pachyderm/src/client/pkg/tracing"
 	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/fault"
 	"github.com/pachyderm/pachyderm/src/server/pkg/watch"
 
 	etcd "github.com/coreos/etcd/clientv3"
@@ -126,6 +127,9 @@ func (c *collection) Claim(ctx context.Context, key string, val proto.Message, f
 		for {
 			select {
 			case <-time.After((time.Second * time.Duration(ttl)) / 2):
+				if fault.DropHeartbeat() {
+					continue
+				}
 				// (bryce) potential race condition, goroutine does PutTTL after Put for completion which deletes work
 				// potential way around this is to have this only update the lease and not do a put (maybe through keepalive?)
 				if _, err := NewSTM(claimCtx, c.etcdClient, func(stm STM) error {
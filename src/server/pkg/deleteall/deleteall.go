@@ -0,0 +1,135 @@
+// Package deleteall constrains DeleteAll, which deletes everything in a
+// cluster, so that production clusters can disable it or require it to be
+// confirmed (see admin.DeleteAllPolicy). The admin API's DeleteAll checks the
+// policy and then calls the DeleteAll RPCs of the other APIs with the
+// confirmation token, which they check again, so that they can't be called
+// directly to get around the policy. Every full wipe is recorded with Audit.
+package deleteall
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	etcd "github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/pachyderm/pachyderm/src/client/admin"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
+)
+
+const (
+	// TokenKey is the key of the confirmation token in the metadata of
+	// DeleteAll requests
+	TokenKey = "pach-delete-all-token"
+	// TokenTTL is how long confirmation tokens are valid for
+	TokenTTL = 5 * time.Minute
+
+	// tokensPrefix is the etcd prefix, under pachd's etcd prefix, of the
+	// confirmation tokens
+	tokensPrefix = "delete-all-tokens"
+)
+
+// ParsePolicy parses DELETE_ALL_POLICY ("allow", "confirm" or "disabled").
+func ParsePolicy(policy string) (admin.DeleteAllPolicy, error) {
+	if policy == "" {
+		return admin.DeleteAllPolicy_ALLOW, nil
+	}
+	result, ok := admin.DeleteAllPolicy_value[strings.ToUpper(policy)]
+	if !ok {
+		return 0, errors.Errorf("invalid DELETE_ALL_POLICY %q; must be \"allow\", \"confirm\" or \"disabled\"", policy)
+	}
+	return admin.DeleteAllPolicy(result), nil
+}
+
+// NewToken stores a new confirmation token, requested by 'username', under
+// 'etcdPrefix', and returns it along with when it expires.
+func NewToken(ctx context.Context, etcdClient *etcd.Client, etcdPrefix string, username string) (string, time.Time, error) {
+	lease, err := etcdClient.Grant(ctx, int64(TokenTTL/time.Second))
+	if err != nil {
+		return "", time.Time{}, errors.EnsureStack(err)
+	}
+	token := uuid.NewWithoutDashes()
+	if _, err := etcdClient.Put(ctx, path.Join(etcdPrefix, tokensPrefix, token), username, etcd.WithLease(lease.ID)); err != nil {
+		return "", time.Time{}, errors.EnsureStack(err)
+	}
+	return token, time.Now().Add(TokenTTL), nil
+}
+
+// RevokeToken deletes 'token', so that it can't confirm another DeleteAll.
+func RevokeToken(ctx context.Context, etcdClient *etcd.Client, etcdPrefix string, token string) error {
+	if token == "" {
+		return nil
+	}
+	_, err := etcdClient.Delete(ctx, path.Join(etcdPrefix, tokensPrefix, token))
+	return errors.EnsureStack(err)
+}
+
+// TokenFromContext returns the confirmation token in the metadata of the
+// request in 'ctx', or "" if it has none.
+func TokenFromContext(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md[TokenKey]) > 0 {
+		return md[TokenKey][0]
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md[TokenKey]) > 0 {
+		return md[TokenKey][0]
+	}
+	return ""
+}
+
+// Check returns an error if 'policy' doesn't allow the full wipe 'op' (e.g.
+// "pps.DeleteAll") with the confirmation token in 'ctx'. 'username' is the
+// caller; if it's set, the token must have been requested by them.
+func Check(ctx context.Context, etcdClient *etcd.Client, etcdPrefix string, policy string, op string, username string) error {
+	p, err := ParsePolicy(policy)
+	if err != nil {
+		return err
+	}
+	switch p {
+	case admin.DeleteAllPolicy_ALLOW:
+		return nil
+	case admin.DeleteAllPolicy_DISABLED:
+		return errors.Errorf("%s is disabled in this cluster (DELETE_ALL_POLICY=disabled); delete repos and pipelines individually, or with a prefix, instead", op)
+	}
+	token := TokenFromContext(ctx)
+	if token == "" {
+		return errors.Errorf("%s requires a confirmation token in this cluster (DELETE_ALL_POLICY=confirm); get one with PrepareDeleteAll", op)
+	}
+	resp, err := etcdClient.Get(ctx, path.Join(etcdPrefix, tokensPrefix, token))
+	if err != nil {
+		return errors.EnsureStack(err)
+	}
+	if len(resp.Kvs) == 0 {
+		return errors.Errorf("the confirmation token of %s is invalid or expired", op)
+	}
+	if owner := string(resp.Kvs[0].Value); username != "" && owner != "" && owner != username {
+		return errors.Errorf("the confirmation token of %s was requested by %q, not %q", op, owner, username)
+	}
+	return nil
+}
+
+// Audit records that 'username' (empty if auth isn't active) called the full
+// wipe 'op', and its result.
+func Audit(op string, username string, err error) {
+	entry := log.WithFields(log.Fields{
+		"audit": "delete-all",
+		"op":    op,
+		"user":  username,
+	})
+	if err != nil {
+		entry.WithError(err).Warnf("%s failed", op)
+		return
+	}
+	entry.Warnf("%s succeeded", op)
+}
+
+// WithToken returns a copy of 'ctx' whose outgoing requests carry 'token'.
+func WithToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, TokenKey, token)
+}
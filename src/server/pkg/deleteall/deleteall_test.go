@@ -0,0 +1,44 @@
+package deleteall
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/pachyderm/pachyderm/src/client/admin"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	for s, expected := range map[string]admin.DeleteAllPolicy{
+		"":         admin.DeleteAllPolicy_ALLOW,
+		"allow":    admin.DeleteAllPolicy_ALLOW,
+		"confirm":  admin.DeleteAllPolicy_CONFIRM,
+		"DISABLED": admin.DeleteAllPolicy_DISABLED,
+	} {
+		policy, err := ParsePolicy(s)
+		require.NoError(t, err)
+		require.Equal(t, expected, policy)
+	}
+	_, err := ParsePolicy("sometimes")
+	require.YesError(t, err)
+}
+
+func TestTokenFromContext(t *testing.T) {
+	require.Equal(t, "", TokenFromContext(context.Background()))
+	require.Equal(t, "abc", TokenFromContext(WithToken(context.Background(), "abc")))
+	incoming := metadata.NewIncomingContext(context.Background(), metadata.Pairs(TokenKey, "def"))
+	require.Equal(t, "def", TokenFromContext(incoming))
+	require.Equal(t, context.Background(), WithToken(context.Background(), ""))
+}
+
+func TestCheck(t *testing.T) {
+	// The policies that don't need etcd
+	ctx := context.Background()
+	require.NoError(t, Check(ctx, nil, "", "allow", "DeleteAll", ""))
+	require.YesError(t, Check(ctx, nil, "", "disabled", "DeleteAll", ""))
+	require.YesError(t, Check(WithToken(ctx, "abc"), nil, "", "disabled", "DeleteAll", ""))
+	require.YesError(t, Check(ctx, nil, "", "confirm", "DeleteAll", ""))
+	require.YesError(t, Check(ctx, nil, "", "bogus", "DeleteAll", ""))
+}
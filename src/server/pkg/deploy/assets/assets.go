@@ -81,6 +81,16 @@ var (
 		APIGroups: []string{""},
 		Verbs:     []string{"get", "list", "watch"},
 		Resources: []string{"nodes", "pods", "pods/log", "endpoints"},
+	}, {
+		// Used to report the resource usage of workers (from the kubelet if
+		// metrics-server isn't installed)
+		APIGroups: []string{""},
+		Verbs:     []string{"get"},
+		Resources: []string{"nodes/proxy"},
+	}, {
+		APIGroups: []string{"metrics.k8s.io"},
+		Verbs:     []string{"get", "list"},
+		Resources: []string{"pods"},
 	}, {
 		APIGroups: []string{""},
 		Verbs:     []string{"get", "list", "watch", "create", "update", "delete"},
@@ -89,6 +99,38 @@ var (
 		APIGroups: []string{""},
 		Verbs:     []string{"get", "list", "watch", "create", "update", "delete", "deletecollection"},
 		Resources: []string{"secrets"},
+	}, {
+		APIGroups: []string{"policy"},
+		Verbs:     []string{"get", "list", "watch", "create", "update", "delete"},
+		Resources: []string{"poddisruptionbudgets"},
+	}, {
+		APIGroups: []string{"networking.k8s.io"},
+		Verbs:     []string{"get", "list", "watch", "create", "update", "delete"},
+		Resources: []string{"networkpolicies"},
+	}, {
+		APIGroups: []string{"apps"},
+		Verbs:     []string{"get", "list", "watch", "create", "update", "delete"},
+		Resources: []string{"daemonsets"},
+	}, {
+		// Used to check that the cluster supports sandboxed pipelines
+		APIGroups: []string{"node.k8s.io"},
+		Verbs:     []string{"get"},
+		Resources: []string{"runtimeclasses"},
+	}, {
+		// Used to explain job failures with the worker pods' warning events
+		APIGroups: []string{""},
+		Verbs:     []string{"list"},
+		Resources: []string{"events"},
+	}, {
+		// Used to gang-schedule pipeline workers with Volcano
+		APIGroups: []string{"scheduling.volcano.sh"},
+		Verbs:     []string{"get", "create", "delete"},
+		Resources: []string{"podgroups"},
+	}, {
+		// Used to report whether Kueue has admitted gang-scheduled workers
+		APIGroups: []string{"kueue.x-k8s.io"},
+		Verbs:     []string{"get"},
+		Resources: []string{"workloads"},
 	}}
 
 	// The name of the local volume (mounted kubernetes secret) where pachd
@@ -278,6 +320,14 @@ type AssetOpts struct {
 	// NoExposeDockerSocket if true prevents pipelines from accessing the docker socket.
 	NoExposeDockerSocket bool
 
+	// ImagePrewarming, if true, causes pachd to pull the images of active
+	// pipelines onto every node, so that workers start quickly on new nodes.
+	ImagePrewarming bool
+
+	// EventBusURL, if set, causes pachd to publish commit, job and pipeline
+	// events to this Kafka topic or NATS subject.
+	EventBusURL string
+
 	// ExposeObjectAPI, if set, causes pachd to serve Object/Block API requests on
 	// its public port. This should generally be false in production (it breaks
 	// auth) but is needed by tests
@@ -674,6 +724,8 @@ func PachdDeployment(opts *AssetOpts, objectStoreBackend backend, hostPath strin
 		{Name: "BLOCK_CACHE_BYTES", Value: opts.BlockCacheSize},
 		{Name: "IAM_ROLE", Value: opts.IAMRole},
 		{Name: "NO_EXPOSE_DOCKER_SOCKET", Value: strconv.FormatBool(opts.NoExposeDockerSocket)},
+		{Name: "IMAGE_PREWARMING", Value: strconv.FormatBool(opts.ImagePrewarming)},
+		{Name: "EVENT_BUS_URL", Value: opts.EventBusURL},
 		{Name: auth.DisableAuthenticationEnvVar, Value: strconv.FormatBool(opts.DisableAuthentication)},
 		{
 			Name: "PACH_NAMESPACE",
@@ -297,7 +297,9 @@ func standardDeployCmds() []*cobra.Command {
 	var etcdNodes int
 	var etcdStorageClassName string
 	var etcdVolume string
+	var eventBusURL string
 	var exposeObjectAPI bool
+	var imagePrewarming bool
 	var imagePullSecret string
 	var localRoles bool
 	var logLevel string
@@ -333,6 +335,8 @@ func standardDeployCmds() []*cobra.Command {
 		cmd.Flags().BoolVar(&noRBAC, "no-rbac", false, "Don't deploy RBAC roles for Pachyderm. (for k8s versions prior to 1.8)")
 		cmd.Flags().BoolVar(&localRoles, "local-roles", false, "Use namespace-local roles instead of cluster roles. Ignored if --no-rbac is set.")
 		cmd.Flags().StringVar(&namespace, "namespace", "", "Kubernetes namespace to deploy Pachyderm to.")
+		cmd.Flags().BoolVar(&imagePrewarming, "image-prewarming", false, "Pull the images of active pipelines onto every node in the cluster (including nodes added by autoscaling), so that workers on new nodes start without waiting for their image to be pulled.")
+		cmd.Flags().StringVar(&eventBusURL, "event-bus-url", "", "Publish commit, job and pipeline events to this Kafka topic (kafka://<broker>[,<broker>...]/<topic>) or NATS subject (nats://<host>:<port>/<subject>).")
 		cmd.Flags().BoolVar(&noExposeDockerSocket, "no-expose-docker-socket", false, "Don't expose the Docker socket to worker containers. This limits the privileges of workers which prevents them from automatically setting the container's working dir and user.")
 		cmd.Flags().BoolVar(&exposeObjectAPI, "expose-object-api", false, "If set, instruct pachd to serve its object/block API on its public port (not safe with auth enabled, do not set in production).")
 		cmd.Flags().StringVar(&tlsCertKey, "tls", "", "string of the form \"<cert path>,<key path>\" of the signed TLS certificate and private key that Pachd should use for TLS authentication (enables TLS-encrypted communication with Pachd)")
@@ -448,6 +452,8 @@ func standardDeployCmds() []*cobra.Command {
 			LocalRoles:                 localRoles,
 			Namespace:                  namespace,
 			NoExposeDockerSocket:       noExposeDockerSocket,
+			ImagePrewarming:            imagePrewarming,
+			EventBusURL:                eventBusURL,
 			ExposeObjectAPI:            exposeObjectAPI,
 			ClusterDeploymentID:        clusterDeploymentID,
 			RequireCriticalServersOnly: requireCriticalServersOnly,
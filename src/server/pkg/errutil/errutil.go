@@ -61,3 +61,23 @@ func IsInvalidPathError(err error) bool {
 	return strings.Contains(err.Error(), "only printable ASCII characters allowed") ||
 		strings.Contains(err.Error(), "not allowed in path")
 }
+
+// IsBranchHeadMismatchError returns true if the err is due to a branch not
+// being moved, or a commit not being started on it, because the branch's head
+// isn't the expected commit (see pfsserver.ErrBranchHeadMismatch)
+func IsBranchHeadMismatchError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return strings.Contains(err.Error(), ", not the expected ")
+}
+
+// IsCommitConflictError returns true if the err is due to a commit not being
+// finished because it's no longer the head of its branch (see
+// pfsserver.ErrCommitConflict)
+func IsCommitConflictError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return strings.Contains(err.Error(), ", which has moved to ")
+}
@@ -0,0 +1,262 @@
+// Package eventbus publishes Pachyderm events (finished and abandoned commits
+// and job and pipeline state changes) to a Kafka topic or NATS subject, so that
+// downstream systems can consume them as an ordered stream.
+package eventbus
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// SchemaVersion is the version of the Event schema. It's incremented when
+// fields are removed or change meaning (adding fields doesn't change it).
+const SchemaVersion = 1
+
+// EventType is the type of an Event
+type EventType string
+
+const (
+	// CommitFinished is published when a commit is finished
+	CommitFinished EventType = "commit_finished"
+	// CommitAbandoned is published when a commit is abandoned for having been
+	// open for longer than its repo's open commit policy allows. It's followed
+	// by a CommitFinished event if the commit is finished rather than deleted.
+	CommitAbandoned EventType = "commit_abandoned"
+	// JobStateChanged is published when a job is created or changes state
+	JobStateChanged EventType = "job_state_changed"
+	// PipelineStateChanged is published when a pipeline is created or
+	// changes state
+	PipelineStateChanged EventType = "pipeline_state_changed"
+)
+
+// Event is a single event, as it's published (encoded as JSON). Exactly one
+// of Commit, Job and Pipeline is set, depending on Type.
+type Event struct {
+	SchemaVersion int `json:"schema_version"`
+	// ID uniquely identifies the event. Events are published at least once, so
+	// consumers should use it to discard duplicates.
+	ID   string    `json:"id"`
+	Type EventType `json:"type"`
+	// Revision is the etcd revision at which the change happened. Events are
+	// published in revision order, and events with the same revision happened
+	// atomically.
+	Revision int64          `json:"revision"`
+	Commit   *CommitEvent   `json:"commit,omitempty"`
+	Job      *JobEvent      `json:"job,omitempty"`
+	Pipeline *PipelineEvent `json:"pipeline,omitempty"`
+}
+
+// CommitEvent describes a finished or abandoned commit
+type CommitEvent struct {
+	Repo     string `json:"repo"`
+	ID       string `json:"id"`
+	Branch   string `json:"branch,omitempty"`
+	ParentID string `json:"parent_id,omitempty"`
+	// Origin is how the commit was created: "USER", "AUTO" or "FSCK"
+	Origin    string     `json:"origin"`
+	Started   *time.Time `json:"started,omitempty"`
+	Finished  *time.Time `json:"finished,omitempty"`
+	Abandoned *time.Time `json:"abandoned,omitempty"`
+	SizeBytes uint64     `json:"size_bytes"`
+}
+
+// JobEvent describes a job's new state
+type JobEvent struct {
+	ID       string `json:"id"`
+	Pipeline string `json:"pipeline"`
+	// State and PreviousState are JobState names, e.g. "JOB_RUNNING".
+	// PreviousState is empty for new jobs.
+	State         string     `json:"state"`
+	PreviousState string     `json:"previous_state,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
+	OutputRepo    string     `json:"output_repo,omitempty"`
+	OutputCommit  string     `json:"output_commit,omitempty"`
+	Started       *time.Time `json:"started,omitempty"`
+	Finished      *time.Time `json:"finished,omitempty"`
+	DataProcessed int64      `json:"data_processed"`
+	DataSkipped   int64      `json:"data_skipped"`
+	DataFailed    int64      `json:"data_failed"`
+	DataTotal     int64      `json:"data_total"`
+}
+
+// PipelineEvent describes a pipeline's new state
+type PipelineEvent struct {
+	Name string `json:"name"`
+	// State and PreviousState are PipelineState names, e.g.
+	// "PIPELINE_RUNNING". PreviousState is empty for new pipelines.
+	State         string `json:"state"`
+	PreviousState string `json:"previous_state,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+	SpecCommit    string `json:"spec_commit,omitempty"`
+}
+
+// Key returns the key that 'e' is published with. Events with the same key
+// are published to the same Kafka partition, so they're consumed in order.
+func (e *Event) Key() string {
+	switch {
+	case e.Commit != nil:
+		return "repo/" + e.Commit.Repo
+	case e.Job != nil:
+		return "pipeline/" + e.Job.Pipeline
+	case e.Pipeline != nil:
+		return "pipeline/" + e.Pipeline.Name
+	}
+	return ""
+}
+
+// decoder turns changes to etcd keys into events. 'pfsPrefix' and 'ppsPrefix'
+// are the etcd prefixes of PFS and PPS.
+type decoder struct {
+	pfsPrefix string
+	ppsPrefix string
+}
+
+// indexIdentifier marks the keys of collection indexes (see
+// collection.indexRoot), which aren't decoded
+const indexIdentifier = "__index_"
+
+// decode returns the event (if any) caused by changing the value of 'key'
+// from 'prev' (nil if the key was created) to 'cur', at 'rev'. Deleting a key
+// doesn't cause an event.
+func (d *decoder) decode(key string, prev, cur []byte, rev int64) (*Event, error) {
+	if cur == nil || strings.Contains(key, indexIdentifier) {
+		return nil, nil
+	}
+	switch {
+	case strings.HasPrefix(key, d.pfsPrefix+"/commits/"):
+		return decodeCommit(prev, cur, rev)
+	case strings.HasPrefix(key, d.ppsPrefix+"/jobs/"):
+		return decodeJob(prev, cur, rev)
+	case strings.HasPrefix(key, d.ppsPrefix+"/pipelines/"):
+		return decodePipeline(strings.TrimPrefix(key, d.ppsPrefix+"/pipelines/"), prev, cur, rev)
+	}
+	return nil, nil
+}
+
+func unmarshal(data []byte, val proto.Message) (bool, error) {
+	if data == nil {
+		return false, nil
+	}
+	if err := proto.Unmarshal(data, val); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func timestamp(t *types.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+	result, err := types.TimestampFromProto(t)
+	if err != nil {
+		return nil
+	}
+	return &result
+}
+
+func decodeCommit(prev, cur []byte, rev int64) (*Event, error) {
+	prevInfo, curInfo := &pfs.CommitInfo{}, &pfs.CommitInfo{}
+	hadPrev, err := unmarshal(prev, prevInfo)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := unmarshal(cur, curInfo); err != nil {
+		return nil, err
+	}
+	eventType := CommitFinished
+	switch {
+	case curInfo.Finished != nil && (!hadPrev || prevInfo.Finished == nil):
+	case curInfo.Abandoned != nil && (!hadPrev || prevInfo.Abandoned == nil):
+		eventType = CommitAbandoned
+	default:
+		return nil, nil
+	}
+	commit := &CommitEvent{
+		Repo:      curInfo.Commit.Repo.Name,
+		ID:        curInfo.Commit.ID,
+		Origin:    curInfo.Origin.GetKind().String(),
+		Started:   timestamp(curInfo.Started),
+		Finished:  timestamp(curInfo.Finished),
+		Abandoned: timestamp(curInfo.Abandoned),
+		SizeBytes: curInfo.SizeBytes,
+	}
+	if curInfo.Branch != nil {
+		commit.Branch = curInfo.Branch.Name
+	}
+	if curInfo.ParentCommit != nil {
+		commit.ParentID = curInfo.ParentCommit.ID
+	}
+	return &Event{Type: eventType, Revision: rev, Commit: commit}, nil
+}
+
+func decodeJob(prev, cur []byte, rev int64) (*Event, error) {
+	prevInfo, curInfo := &pps.EtcdJobInfo{}, &pps.EtcdJobInfo{}
+	hadPrev, err := unmarshal(prev, prevInfo)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := unmarshal(cur, curInfo); err != nil {
+		return nil, err
+	}
+	if hadPrev && prevInfo.State == curInfo.State {
+		return nil, nil
+	}
+	job := &JobEvent{
+		ID:            curInfo.Job.ID,
+		Pipeline:      curInfo.Pipeline.Name,
+		State:         curInfo.State.String(),
+		Reason:        curInfo.Reason,
+		Started:       timestamp(curInfo.Started),
+		Finished:      timestamp(curInfo.Finished),
+		DataProcessed: curInfo.DataProcessed,
+		DataSkipped:   curInfo.DataSkipped,
+		DataFailed:    curInfo.DataFailed,
+		DataTotal:     curInfo.DataTotal,
+	}
+	if hadPrev {
+		job.PreviousState = prevInfo.State.String()
+	}
+	if curInfo.OutputCommit != nil {
+		job.OutputRepo = curInfo.OutputCommit.Repo.Name
+		job.OutputCommit = curInfo.OutputCommit.ID
+	}
+	return &Event{Type: JobStateChanged, Revision: rev, Job: job}, nil
+}
+
+func decodePipeline(name string, prev, cur []byte, rev int64) (*Event, error) {
+	prevInfo, curInfo := &pps.EtcdPipelineInfo{}, &pps.EtcdPipelineInfo{}
+	hadPrev, err := unmarshal(prev, prevInfo)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := unmarshal(cur, curInfo); err != nil {
+		return nil, err
+	}
+	if hadPrev && prevInfo.State == curInfo.State {
+		return nil, nil
+	}
+	pipeline := &PipelineEvent{
+		Name:   name,
+		State:  curInfo.State.String(),
+		Reason: curInfo.Reason,
+	}
+	if hadPrev {
+		pipeline.PreviousState = prevInfo.State.String()
+	}
+	if curInfo.SpecCommit != nil {
+		pipeline.SpecCommit = curInfo.SpecCommit.ID
+	}
+	return &Event{Type: PipelineStateChanged, Revision: rev, Pipeline: pipeline}, nil
+}
+
+// eventID returns the ID of the 'i'th event at revision 'rev'
+func eventID(rev int64, i int) string {
+	return fmt.Sprintf("%d-%d", rev, i)
+}
@@ -0,0 +1,142 @@
+package eventbus
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func marshal(t *testing.T, val proto.Message) []byte {
+	data, err := proto.Marshal(val)
+	require.NoError(t, err)
+	return data
+}
+
+func TestDecodeCommit(t *testing.T) {
+	d := &decoder{pfsPrefix: "pachyderm/1.7.0/pachyderm_pfs", ppsPrefix: "pachyderm/1.7.0/pachyderm_pps"}
+	key := d.pfsPrefix + "/commits/images/abc"
+	open := &pfs.CommitInfo{
+		Commit:  client.NewCommit("images", "abc"),
+		Branch:  client.NewBranch("images", "master"),
+		Started: types.TimestampNow(),
+	}
+	finished := proto.Clone(open).(*pfs.CommitInfo)
+	finished.Finished = types.TimestampNow()
+	finished.SizeBytes = 100
+
+	// Starting a commit doesn't cause an event
+	event, err := d.decode(key, nil, marshal(t, open), 1)
+	require.NoError(t, err)
+	require.Nil(t, event)
+
+	event, err = d.decode(key, marshal(t, open), marshal(t, finished), 2)
+	require.NoError(t, err)
+	require.Equal(t, CommitFinished, event.Type)
+	require.Equal(t, int64(2), event.Revision)
+	require.Equal(t, "images", event.Commit.Repo)
+	require.Equal(t, "abc", event.Commit.ID)
+	require.Equal(t, "master", event.Commit.Branch)
+	require.Equal(t, uint64(100), event.Commit.SizeBytes)
+	require.Equal(t, "repo/images", event.Key())
+
+	// Updating a finished commit (e.g. adding a subvenant) doesn't cause
+	// another event
+	event, err = d.decode(key, marshal(t, finished), marshal(t, finished), 3)
+	require.NoError(t, err)
+	require.Nil(t, event)
+
+	// Neither do deletes or indexes
+	event, err = d.decode(key, marshal(t, finished), nil, 4)
+	require.NoError(t, err)
+	require.Nil(t, event)
+	event, err = d.decode(d.pfsPrefix+"/commits/images__index_branch/master/abc", nil, marshal(t, finished), 5)
+	require.NoError(t, err)
+	require.Nil(t, event)
+}
+
+func TestDecodeAbandonedCommit(t *testing.T) {
+	d := &decoder{pfsPrefix: "pachyderm/1.7.0/pachyderm_pfs", ppsPrefix: "pachyderm/1.7.0/pachyderm_pps"}
+	key := d.pfsPrefix + "/commits/images/abc"
+	open := &pfs.CommitInfo{
+		Commit:  client.NewCommit("images", "abc"),
+		Started: types.TimestampNow(),
+	}
+	abandoned := proto.Clone(open).(*pfs.CommitInfo)
+	abandoned.Abandoned = types.TimestampNow()
+	finished := proto.Clone(abandoned).(*pfs.CommitInfo)
+	finished.Finished = types.TimestampNow()
+
+	event, err := d.decode(key, marshal(t, open), marshal(t, abandoned), 1)
+	require.NoError(t, err)
+	require.Equal(t, CommitAbandoned, event.Type)
+	require.Equal(t, "abc", event.Commit.ID)
+	require.NotNil(t, event.Commit.Abandoned)
+	require.Nil(t, event.Commit.Finished)
+
+	// Finishing the abandoned commit causes a CommitFinished event
+	event, err = d.decode(key, marshal(t, abandoned), marshal(t, finished), 2)
+	require.NoError(t, err)
+	require.Equal(t, CommitFinished, event.Type)
+	require.NotNil(t, event.Commit.Abandoned)
+
+	event, err = d.decode(key, marshal(t, finished), marshal(t, finished), 3)
+	require.NoError(t, err)
+	require.Nil(t, event)
+}
+
+func TestDecodeJobAndPipeline(t *testing.T) {
+	d := &decoder{pfsPrefix: "pachyderm/1.7.0/pachyderm_pfs", ppsPrefix: "pachyderm/1.7.0/pachyderm_pps"}
+	jobKey := d.ppsPrefix + "/jobs/job1"
+	starting := &pps.EtcdJobInfo{
+		Job:          client.NewJob("job1"),
+		Pipeline:     client.NewPipeline("edges"),
+		OutputCommit: client.NewCommit("edges", "out"),
+		State:        pps.JobState_JOB_STARTING,
+	}
+	running := proto.Clone(starting).(*pps.EtcdJobInfo)
+	running.State = pps.JobState_JOB_RUNNING
+	progress := proto.Clone(running).(*pps.EtcdJobInfo)
+	progress.DataProcessed = 5
+
+	event, err := d.decode(jobKey, nil, marshal(t, starting), 1)
+	require.NoError(t, err)
+	require.Equal(t, JobStateChanged, event.Type)
+	require.Equal(t, "JOB_STARTING", event.Job.State)
+	require.Equal(t, "", event.Job.PreviousState)
+	require.Equal(t, "edges", event.Job.OutputRepo)
+	require.Equal(t, "pipeline/edges", event.Key())
+
+	event, err = d.decode(jobKey, marshal(t, starting), marshal(t, running), 2)
+	require.NoError(t, err)
+	require.Equal(t, "JOB_RUNNING", event.Job.State)
+	require.Equal(t, "JOB_STARTING", event.Job.PreviousState)
+
+	// Progress updates don't change the job's state
+	event, err = d.decode(jobKey, marshal(t, running), marshal(t, progress), 3)
+	require.NoError(t, err)
+	require.Nil(t, event)
+
+	pipelineKey := d.ppsPrefix + "/pipelines/edges"
+	pipeline := &pps.EtcdPipelineInfo{
+		State:      pps.PipelineState_PIPELINE_STARTING,
+		SpecCommit: client.NewCommit("__spec__", "spec"),
+	}
+	event, err = d.decode(pipelineKey, nil, marshal(t, pipeline), 4)
+	require.NoError(t, err)
+	require.Equal(t, PipelineStateChanged, event.Type)
+	require.Equal(t, "edges", event.Pipeline.Name)
+	require.Equal(t, "PIPELINE_STARTING", event.Pipeline.State)
+	require.Equal(t, "spec", event.Pipeline.SpecCommit)
+	require.Equal(t, "pipeline/edges", event.Key())
+
+	// Keys outside of the watched collections are ignored
+	event, err = d.decode(d.ppsPrefix+"/other/edges", nil, marshal(t, pipeline), 5)
+	require.NoError(t, err)
+	require.Nil(t, event)
+}
@@ -0,0 +1,161 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strconv"
+	"time"
+
+	etcd "github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+	"github.com/pachyderm/pachyderm/src/server/pkg/dlock"
+)
+
+const (
+	// exporterLockPath is the dlock held by the pachd that exports events, so
+	// that each event is only published by one pachd
+	exporterLockPath = "_event_bus_lock"
+	// revisionKey stores the etcd revision up to which events have been
+	// published, so that a new exporter resumes where the last one stopped
+	revisionKey = "event_bus_revision"
+)
+
+// Exporter publishes the events caused by changes to etcd. It watches every
+// key under the cluster's etcd prefix, so that events are published in the
+// order they happened, and records its progress in etcd, so that events
+// aren't skipped when pachd restarts (but may be published twice).
+type Exporter struct {
+	etcdClient *etcd.Client
+	etcdPrefix string
+	decoder    *decoder
+	publisher  Publisher
+}
+
+// NewExporter returns an Exporter that publishes the events under
+// 'etcdPrefix' (the cluster's etcd prefix, which contains 'pfsPrefix' and
+// 'ppsPrefix') to 'publisher'.
+func NewExporter(etcdClient *etcd.Client, etcdPrefix, pfsPrefix, ppsPrefix string, publisher Publisher) *Exporter {
+	return &Exporter{
+		etcdClient: etcdClient,
+		etcdPrefix: etcdPrefix,
+		decoder:    &decoder{pfsPrefix: pfsPrefix, ppsPrefix: ppsPrefix},
+		publisher:  publisher,
+	}
+}
+
+// Run exports events until 'ctx' is cancelled, retrying on errors.
+func (e *Exporter) Run(ctx context.Context) error {
+	lock := dlock.NewDLock(e.etcdClient, path.Join(e.etcdPrefix, exporterLockPath))
+	return backoff.RetryUntilCancel(ctx, func() error {
+		lockCtx, err := lock.Lock(ctx)
+		if err != nil {
+			return err
+		}
+		defer lock.Unlock(lockCtx)
+		return e.export(lockCtx)
+	}, backoff.NewInfiniteBackOff(), func(err error, d time.Duration) error {
+		log.Errorf("event bus: error exporting events: %v; retrying in %v", err, d)
+		return nil
+	})
+}
+
+// revision returns the revision up to which events have been published, or,
+// if no events have been published yet, the current revision.
+func (e *Exporter) revision(ctx context.Context) (int64, error) {
+	resp, err := e.etcdClient.Get(ctx, path.Join(e.etcdPrefix, revisionKey))
+	if err != nil {
+		return 0, errors.EnsureStack(err)
+	}
+	if len(resp.Kvs) == 0 {
+		return resp.Header.Revision, nil
+	}
+	return strconv.ParseInt(string(resp.Kvs[0].Value), 10, 64)
+}
+
+func (e *Exporter) setRevision(ctx context.Context, rev int64) error {
+	_, err := e.etcdClient.Put(ctx, path.Join(e.etcdPrefix, revisionKey), strconv.FormatInt(rev, 10))
+	return errors.EnsureStack(err)
+}
+
+// export publishes events until 'ctx' is cancelled or an error occurs
+func (e *Exporter) export(ctx context.Context) error {
+	rev, err := e.revision(ctx)
+	if err != nil {
+		return err
+	}
+	log.Infof("event bus: exporting events after revision %d", rev)
+	// The trailing slash keeps the watch from matching other prefixes that
+	// start with the cluster's prefix
+	watchCh := e.etcdClient.Watch(ctx, e.etcdPrefix+"/",
+		etcd.WithPrefix(), etcd.WithRev(rev+1), etcd.WithPrevKV())
+	for resp := range watchCh {
+		if resp.CompactRevision != 0 {
+			// The events between 'rev' and the compaction are gone
+			log.Errorf("event bus: events after revision %d were compacted before "+
+				"they were published; resuming at revision %d", rev, resp.CompactRevision)
+			if err := e.setRevision(ctx, resp.CompactRevision-1); err != nil {
+				return err
+			}
+			return errors.Errorf("watch compacted at revision %d", resp.CompactRevision)
+		}
+		if err := resp.Err(); err != nil {
+			return errors.EnsureStack(err)
+		}
+		msgs, err := e.messages(resp.Events)
+		if err != nil {
+			return err
+		}
+		if len(msgs) > 0 {
+			if err := e.publisher.Publish(ctx, msgs); err != nil {
+				return errors.Wrapf(err, "could not publish events")
+			}
+			if err := e.setRevision(ctx, resp.Header.Revision); err != nil {
+				return err
+			}
+		}
+	}
+	return errors.EnsureStack(ctx.Err())
+}
+
+// messages returns the messages to publish for 'events'
+func (e *Exporter) messages(events []*etcd.Event) ([]Message, error) {
+	var msgs []Message
+	var lastRev int64
+	var i int
+	for _, etcdEvent := range events {
+		var prev []byte
+		if etcdEvent.PrevKv != nil {
+			prev = etcdEvent.PrevKv.Value
+		}
+		var cur []byte
+		if etcdEvent.Type == etcd.EventTypePut {
+			cur = etcdEvent.Kv.Value
+		}
+		rev := etcdEvent.Kv.ModRevision
+		event, err := e.decoder.decode(string(etcdEvent.Kv.Key), prev, cur, rev)
+		if err != nil {
+			// Skip values that can't be decoded rather than stalling the stream
+			log.Errorf("event bus: could not decode %s: %v", etcdEvent.Kv.Key, err)
+			continue
+		}
+		if event == nil {
+			continue
+		}
+		if rev != lastRev {
+			lastRev, i = rev, 0
+		}
+		event.SchemaVersion = SchemaVersion
+		event.ID = eventID(rev, i)
+		i++
+		value, err := json.Marshal(event)
+		if err != nil {
+			return nil, errors.EnsureStack(err)
+		}
+		msgs = append(msgs, Message{Key: event.Key(), Value: value})
+	}
+	return msgs, nil
+}
@@ -0,0 +1,219 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+)
+
+// Message is a single message published to the event bus
+type Message struct {
+	Key   string
+	Value []byte
+}
+
+// Publisher publishes messages to an event bus. Publish returns once the bus
+// has accepted all of 'msgs', in order.
+type Publisher interface {
+	Publish(ctx context.Context, msgs []Message) error
+	Close() error
+}
+
+// NewPublisher returns a Publisher for 'busURL', which is either
+// kafka://<broker>[,<broker>...]/<topic> or
+// nats://[<user>:<password>@]<host>:<port>/<subject>
+func NewPublisher(busURL string) (Publisher, error) {
+	parts := strings.SplitN(busURL, "://", 2)
+	if len(parts) != 2 {
+		return nil, errors.Errorf("invalid event bus URL %q (must be kafka://<brokers>/<topic> or nats://<host>/<subject>)", busURL)
+	}
+	scheme, rest := parts[0], parts[1]
+	slash := strings.Index(rest, "/")
+	if slash <= 0 || slash == len(rest)-1 {
+		return nil, errors.Errorf("invalid event bus URL %q: missing host or topic", busURL)
+	}
+	host, target := rest[:slash], rest[slash+1:]
+	switch scheme {
+	case "kafka":
+		return newKafkaPublisher(strings.Split(host, ","), target), nil
+	case "nats":
+		u, err := url.Parse("nats://" + host)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid event bus URL %q", busURL)
+		}
+		return &natsPublisher{address: u.Host, user: u.User, subject: target}, nil
+	default:
+		return nil, errors.Errorf("unsupported event bus %q (must be kafka or nats)", scheme)
+	}
+}
+
+type kafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+func newKafkaPublisher(brokers []string, topic string) *kafkaPublisher {
+	return &kafkaPublisher{
+		writer: kafka.NewWriter(kafka.WriterConfig{
+			Brokers: brokers,
+			Topic:   topic,
+			// Events with the same key go to the same partition, so that
+			// consumers see each repo's and pipeline's events in order
+			Balancer: &kafka.Hash{},
+			// Wait for all in-sync replicas, so that accepted events aren't lost
+			RequiredAcks: -1,
+			BatchTimeout: 10 * time.Millisecond,
+		}),
+	}
+}
+
+func (p *kafkaPublisher) Publish(ctx context.Context, msgs []Message) error {
+	kafkaMsgs := make([]kafka.Message, len(msgs))
+	for i, msg := range msgs {
+		kafkaMsgs[i] = kafka.Message{Key: []byte(msg.Key), Value: msg.Value}
+	}
+	return errors.EnsureStack(p.writer.WriteMessages(ctx, kafkaMsgs...))
+}
+
+func (p *kafkaPublisher) Close() error {
+	return p.writer.Close()
+}
+
+// natsPublisher publishes messages to a NATS subject using the NATS client
+// protocol (https://docs.nats.io/reference/reference-protocols/nats-protocol).
+// Only publishing is needed, so it doesn't use a full NATS client.
+type natsPublisher struct {
+	address string
+	user    *url.Userinfo
+	subject string
+
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// natsTimeout bounds each exchange with the NATS server
+const natsTimeout = 30 * time.Second
+
+func (p *natsPublisher) connect() error {
+	conn, err := net.DialTimeout("tcp", p.address, natsTimeout)
+	if err != nil {
+		return errors.Wrapf(err, "could not connect to NATS server %s", p.address)
+	}
+	p.conn, p.r = conn, bufio.NewReader(conn)
+	conn.SetDeadline(time.Now().Add(natsTimeout))
+	// The server starts by sending its INFO
+	line, err := p.readLine()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return errors.Errorf("unexpected greeting from NATS server: %q", line)
+	}
+	var info struct {
+		TLSRequired bool `json:"tls_required"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "INFO ")), &info); err != nil {
+		return errors.Wrapf(err, "could not parse NATS server info")
+	}
+	if info.TLSRequired {
+		return errors.Errorf("NATS server %s requires TLS, which isn't supported", p.address)
+	}
+	options := map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     "pachd",
+		"lang":     "go",
+	}
+	if p.user != nil {
+		if password, ok := p.user.Password(); ok {
+			options["user"], options["pass"] = p.user.Username(), password
+		} else {
+			options["auth_token"] = p.user.Username()
+		}
+	}
+	connect, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", connect); err != nil {
+		return errors.EnsureStack(err)
+	}
+	return p.waitForPong()
+}
+
+func (p *natsPublisher) readLine() (string, error) {
+	line, err := p.r.ReadString('\n')
+	if err != nil {
+		return "", errors.Wrapf(err, "could not read from NATS server")
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// waitForPong waits for the server's reply to a PING, which the server sends
+// after it has processed everything that was sent before the PING
+func (p *natsPublisher) waitForPong() error {
+	for {
+		line, err := p.readLine()
+		if err != nil {
+			return err
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := p.conn.Write([]byte("PONG\r\n")); err != nil {
+				return errors.EnsureStack(err)
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return errors.Errorf("NATS server error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+		// Ignore +OK and INFO updates
+	}
+}
+
+func (p *natsPublisher) Publish(ctx context.Context, msgs []Message) (retErr error) {
+	if p.conn == nil {
+		if err := p.connect(); err != nil {
+			p.Close()
+			return err
+		}
+	}
+	defer func() {
+		if retErr != nil {
+			p.Close() // reconnect on the next call
+		}
+	}()
+	deadline := time.Now().Add(natsTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	p.conn.SetDeadline(deadline)
+	w := bufio.NewWriter(p.conn)
+	for _, msg := range msgs {
+		fmt.Fprintf(w, "PUB %s %d\r\n", p.subject, len(msg.Value))
+		w.Write(msg.Value)
+		w.WriteString("\r\n")
+	}
+	w.WriteString("PING\r\n")
+	if err := w.Flush(); err != nil {
+		return errors.Wrapf(err, "could not publish to NATS server")
+	}
+	return p.waitForPong()
+}
+
+func (p *natsPublisher) Close() error {
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn, p.r = nil, nil
+	return err
+}
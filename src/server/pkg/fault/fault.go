@@ -0,0 +1,192 @@
+// Package fault injects faults into a cluster, so that its resilience can be
+// tested without forking the codebase: latency and errors in pachd's object
+// storage reads and writes, and dropped worker heartbeats. The faults to
+// inject are stored in etcd (see admin.SetFaults), and only processes that
+// call Enable, which pachd and workers do when they're started with
+// FAULT_INJECTION=true, inject them.
+package fault
+
+import (
+	"context"
+	"math/rand"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	etcd "github.com/coreos/etcd/clientv3"
+	"github.com/gogo/protobuf/types"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pachyderm/pachyderm/src/client/admin"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+)
+
+const (
+	// faultsKey is the etcd key, under the PPS etcd prefix, of the faults to
+	// inject
+	faultsKey = "faults"
+
+	errMsg = "injected fault"
+)
+
+var (
+	mu        sync.Mutex
+	enabled   bool
+	current   = &admin.Faults{}
+	localRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// Enable makes this process inject the faults stored under 'etcdPrefix' (the
+// PPS etcd prefix), which it watches until 'ctx' is done.
+func Enable(ctx context.Context, etcdClient *etcd.Client, etcdPrefix string) {
+	mu.Lock()
+	enabled = true
+	mu.Unlock()
+	go backoff.RetryUntilCancel(ctx, func() error {
+		return watch(ctx, etcdClient, etcdPrefix)
+	}, backoff.NewInfiniteBackOff(), func(err error, d time.Duration) error {
+		log.Errorf("error watching injected faults: %v; retrying in %v", err, d)
+		return nil
+	})
+}
+
+// Enabled returns true if this process injects faults.
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled
+}
+
+// Get returns the faults stored under 'etcdPrefix'.
+func Get(ctx context.Context, etcdClient *etcd.Client, etcdPrefix string) (*admin.Faults, error) {
+	resp, err := etcdClient.Get(ctx, path.Join(etcdPrefix, faultsKey))
+	if err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	faults := &admin.Faults{}
+	if len(resp.Kvs) > 0 {
+		if err := faults.Unmarshal(resp.Kvs[0].Value); err != nil {
+			return nil, errors.Wrapf(err, "could not unmarshal injected faults")
+		}
+	}
+	return faults, nil
+}
+
+// Set replaces the faults stored under 'etcdPrefix' with 'faults'.
+func Set(ctx context.Context, etcdClient *etcd.Client, etcdPrefix string, faults *admin.Faults) error {
+	if err := validate(faults); err != nil {
+		return err
+	}
+	data, err := faults.Marshal()
+	if err != nil {
+		return errors.EnsureStack(err)
+	}
+	_, err = etcdClient.Put(ctx, path.Join(etcdPrefix, faultsKey), string(data))
+	return errors.EnsureStack(err)
+}
+
+func validate(faults *admin.Faults) error {
+	checkRate := func(name string, rate float64) error {
+		if rate < 0 || rate > 1 {
+			return errors.Errorf("%s must be between 0 and 1, but is %v", name, rate)
+		}
+		return nil
+	}
+	for name, opFaults := range map[string]*admin.OpFaults{"block_reads": faults.BlockReads, "block_writes": faults.BlockWrites} {
+		if opFaults == nil {
+			continue
+		}
+		if err := checkRate(name+".error_rate", opFaults.ErrorRate); err != nil {
+			return err
+		}
+		if opFaults.Latency != nil {
+			latency, err := types.DurationFromProto(opFaults.Latency)
+			if err != nil {
+				return errors.Wrapf(err, "invalid %s.latency", name)
+			}
+			if latency < 0 {
+				return errors.Errorf("%s.latency cannot be negative", name)
+			}
+		}
+	}
+	return checkRate("heartbeat_drop_rate", faults.HeartbeatDropRate)
+}
+
+// watch keeps the faults that this process injects up to date with the
+// faults stored under 'etcdPrefix'
+func watch(ctx context.Context, etcdClient *etcd.Client, etcdPrefix string) error {
+	faults, err := Get(ctx, etcdClient, etcdPrefix)
+	if err != nil {
+		return err
+	}
+	setCurrent(faults)
+	// Read the faults again after every change, rather than decoding the
+	// events, so that deletes are handled too
+	for resp := range etcdClient.Watch(ctx, path.Join(etcdPrefix, faultsKey)) {
+		if err := resp.Err(); err != nil {
+			return errors.EnsureStack(err)
+		}
+		faults, err := Get(ctx, etcdClient, etcdPrefix)
+		if err != nil {
+			return err
+		}
+		setCurrent(faults)
+	}
+	return errors.EnsureStack(ctx.Err())
+}
+
+func setCurrent(faults *admin.Faults) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = faults
+}
+
+// inject delays the caller by the latency of 'opFaults' (or until 'ctx' is
+// done), and then returns an error with probability 'opFaults.ErrorRate'
+func inject(ctx context.Context, opFaults func(*admin.Faults) *admin.OpFaults) error {
+	mu.Lock()
+	f := opFaults(current)
+	if !enabled || f == nil {
+		mu.Unlock()
+		return nil
+	}
+	fail := localRand.Float64() < f.ErrorRate
+	mu.Unlock()
+	if f.Latency != nil {
+		if latency, err := types.DurationFromProto(f.Latency); err == nil && latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-ctx.Done():
+				return errors.EnsureStack(ctx.Err())
+			}
+		}
+	}
+	if fail {
+		return errors.New(errMsg)
+	}
+	return nil
+}
+
+// BlockRead injects the faults configured for object storage reads.
+func BlockRead(ctx context.Context) error {
+	return inject(ctx, func(f *admin.Faults) *admin.OpFaults { return f.BlockReads })
+}
+
+// BlockWrite injects the faults configured for object storage writes.
+func BlockWrite(ctx context.Context) error {
+	return inject(ctx, func(f *admin.Faults) *admin.OpFaults { return f.BlockWrites })
+}
+
+// DropHeartbeat returns true if the caller should drop a worker heartbeat.
+func DropHeartbeat() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled && localRand.Float64() < current.HeartbeatDropRate
+}
+
+// IsInjected returns true if 'err' is a fault injected by this package.
+func IsInjected(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errMsg)
+}
@@ -0,0 +1,44 @@
+package fault
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client/admin"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, validate(&admin.Faults{}))
+	require.NoError(t, validate(&admin.Faults{
+		BlockReads:        &admin.OpFaults{Latency: types.DurationProto(time.Second), ErrorRate: 0.5},
+		HeartbeatDropRate: 1,
+	}))
+	require.YesError(t, validate(&admin.Faults{BlockWrites: &admin.OpFaults{ErrorRate: 2}}))
+	require.YesError(t, validate(&admin.Faults{BlockReads: &admin.OpFaults{Latency: types.DurationProto(-time.Second)}}))
+	require.YesError(t, validate(&admin.Faults{HeartbeatDropRate: -0.1}))
+}
+
+func TestInject(t *testing.T) {
+	defer func() {
+		enabled = false
+		setCurrent(&admin.Faults{})
+	}()
+	setCurrent(&admin.Faults{
+		BlockReads:        &admin.OpFaults{ErrorRate: 1},
+		HeartbeatDropRate: 1,
+	})
+
+	// Nothing is injected unless fault injection is enabled
+	require.NoError(t, BlockRead(context.Background()))
+	require.False(t, DropHeartbeat())
+
+	enabled = true
+	err := BlockRead(context.Background())
+	require.YesError(t, err)
+	require.True(t, IsInjected(err))
+	require.NoError(t, BlockWrite(context.Background()))
+	require.True(t, DropHeartbeat())
+}
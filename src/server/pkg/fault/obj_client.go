@@ -0,0 +1,65 @@
+package fault
+
+import (
+	"context"
+	"io"
+
+	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
+)
+
+// NewObjClient wraps 'c' so that the faults configured for block reads and
+// writes are injected into its reads and writes.
+func NewObjClient(c obj.Client) obj.Client {
+	return &objClient{c}
+}
+
+type objClient struct {
+	c obj.Client
+}
+
+// Reader wraps the reader operation.
+func (c *objClient) Reader(ctx context.Context, name string, offset uint64, size uint64) (io.ReadCloser, error) {
+	if err := BlockRead(ctx); err != nil {
+		return nil, err
+	}
+	return c.c.Reader(ctx, name, offset, size)
+}
+
+// Writer wraps the writer operation.
+func (c *objClient) Writer(ctx context.Context, name string) (io.WriteCloser, error) {
+	if err := BlockWrite(ctx); err != nil {
+		return nil, err
+	}
+	return c.c.Writer(ctx, name)
+}
+
+// Delete wraps the delete operation.
+func (c *objClient) Delete(ctx context.Context, name string) error {
+	return c.c.Delete(ctx, name)
+}
+
+// Walk wraps the walk operation.
+func (c *objClient) Walk(ctx context.Context, prefix string, fn func(name string) error) error {
+	return c.c.Walk(ctx, prefix, fn)
+}
+
+// Exists wraps the existance check.
+func (c *objClient) Exists(ctx context.Context, name string) bool {
+	return c.c.Exists(ctx, name)
+}
+
+// IsRetryable wraps the is retryable check. Injected faults are retryable,
+// like the transient errors that they simulate.
+func (c *objClient) IsRetryable(err error) bool {
+	return IsInjected(err) || c.c.IsRetryable(err)
+}
+
+// IsNotExist wraps the does not exist check.
+func (c *objClient) IsNotExist(err error) bool {
+	return c.c.IsNotExist(err)
+}
+
+// IsIgnorable wraps the is ignorable check.
+func (c *objClient) IsIgnorable(err error) bool {
+	return c.c.IsIgnorable(err)
+}
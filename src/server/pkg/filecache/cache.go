@@ -0,0 +1,247 @@
+// Package filecache provides a read-through, in-memory cache for the contents
+// of small files, keyed by content hash, which can be backed by an external
+// Store (Redis or memcached) that several caches share.
+package filecache
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+
+	logrus "github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultMaxFileBytes is the largest file that's cached, if a Cache isn't
+	// given a limit.
+	DefaultMaxFileBytes = 1024 * 1024
+	// DefaultSizeBytes is the total size of the files in a Cache, if it isn't
+	// given a size.
+	DefaultSizeBytes = 64 * 1024 * 1024
+)
+
+type entry struct {
+	key     string
+	value   []byte
+	expires time.Time // zero if the entry doesn't expire
+}
+
+// Store is an external cache, shared by the Caches of several workers, that
+// files are read through when they aren't in a Cache's memory.
+type Store interface {
+	// Get returns the contents of the file with key 'key', and false if the
+	// file isn't in the store.
+	Get(key string) ([]byte, bool, error)
+	// Set stores 'value' as the contents of the file with key 'key', for
+	// 'ttl' (or until it's evicted, if 'ttl' is 0).
+	Set(key string, value []byte, ttl time.Duration) error
+}
+
+// Cache is an LRU cache of small files' contents, bounded by their total
+// size. Files are read through the cache with Get, which only fetches a file
+// once, even if it's requested concurrently. It is safe for concurrent use.
+type Cache struct {
+	maxFileBytes int64
+	sizeBytes    int64
+	ttl          time.Duration
+	store        Store
+	namespace    string
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // front is most recently used
+	size    int64
+	hits    int64
+	misses  int64
+
+	fetches singleflight.Group
+}
+
+// NewCache creates a new cache that holds up to 'sizeBytes' of files that
+// are at most 'maxFileBytes' in size, for 'ttl' after they're fetched (or
+// until they're evicted, if 'ttl' is 0). Zero sizes are replaced by
+// DefaultSizeBytes and DefaultMaxFileBytes.
+func NewCache(maxFileBytes, sizeBytes int64, ttl time.Duration) *Cache {
+	if maxFileBytes <= 0 {
+		maxFileBytes = DefaultMaxFileBytes
+	}
+	if sizeBytes <= 0 {
+		sizeBytes = DefaultSizeBytes
+	}
+	if maxFileBytes > sizeBytes {
+		maxFileBytes = sizeBytes
+	}
+	return &Cache{
+		maxFileBytes: maxFileBytes,
+		sizeBytes:    sizeBytes,
+		ttl:          ttl,
+		entries:      make(map[string]*list.Element),
+		lru:          list.New(),
+	}
+}
+
+// NewStoreCache creates a new cache like NewCache, which reads files that
+// aren't in its memory through 'store' before fetching them, and writes the
+// files it fetches to 'store'. The files' keys in 'store' are prefixed with
+// 'namespace', so that caches in different namespaces (e.g. tenants) don't
+// share files. Errors reading from or writing to 'store' are logged, and the
+// file is fetched as if it wasn't in 'store'.
+//
+// Anything that can reach 'store' can write to it, so the contents of the
+// files read from it are checked before they're used (see Get).
+func NewStoreCache(maxFileBytes, sizeBytes int64, ttl time.Duration, store Store, namespace string) *Cache {
+	c := NewCache(maxFileBytes, sizeBytes, ttl)
+	c.store = store
+	c.namespace = namespace
+	return c
+}
+
+// Caches returns true if a file of 'size' bytes is small enough to be cached.
+func (c *Cache) Caches(size int64) bool {
+	return c != nil && size <= c.maxFileBytes
+}
+
+// Get writes the contents of the file with key 'key' (its content hash) to
+// 'w'. If the file isn't cached, in memory or in the cache's store, it's
+// written to the cache by 'fetch' first. It returns true if the file was
+// cached.
+//
+// 'verify' checks that contents read from the cache's store are the file's,
+// and those that aren't are fetched instead. Files are only read from and
+// written to the store if 'verify' is set, as its contents can't be trusted
+// otherwise.
+func (c *Cache) Get(key string, w io.Writer, fetch func(io.Writer) error, verify func([]byte) bool) (bool, error) {
+	if value, ok := c.get(key, true); ok {
+		_, err := w.Write(value)
+		return true, errors.EnsureStack(err)
+	}
+	type result struct {
+		value  []byte
+		cached bool
+	}
+	r, err, _ := c.fetches.Do(key, func() (interface{}, error) {
+		// the file may have been fetched while this call waited for the lock
+		if value, ok := c.get(key, false); ok {
+			return result{value, true}, nil
+		}
+		if value, ok := c.storeGet(key, verify); ok {
+			c.put(key, value)
+			return result{value, true}, nil
+		}
+		buf := &bytes.Buffer{}
+		if err := fetch(buf); err != nil {
+			return nil, err
+		}
+		c.put(key, buf.Bytes())
+		c.storeSet(key, buf.Bytes(), verify)
+		return result{buf.Bytes(), false}, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	_, err = w.Write(r.(result).value)
+	return r.(result).cached, errors.EnsureStack(err)
+}
+
+// Stats returns the number of times that Get found a file in the cache, and
+// the number of times it didn't.
+func (c *Cache) Stats() (hits, misses int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// get returns the cached contents of the file with key 'key', if they're
+// cached and haven't expired. If 'count' is true, the lookup is counted in
+// the cache's stats.
+func (c *Cache) get(key string, count bool) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if ok {
+		e := elem.Value.(*entry)
+		if e.expires.IsZero() || time.Now().Before(e.expires) {
+			c.lru.MoveToFront(elem)
+			if count {
+				c.hits++
+			}
+			return e.value, true
+		}
+		c.remove(elem)
+	}
+	if count {
+		c.misses++
+	}
+	return nil, false
+}
+
+// storeKey returns the key of the file with key 'key' in c's store.
+func (c *Cache) storeKey(key string) string {
+	if c.namespace == "" {
+		return key
+	}
+	return c.namespace + "/" + key
+}
+
+// storeGet returns the contents of the file with key 'key' from c's store, if
+// it has one, the file is in it, and its contents pass 'verify'.
+func (c *Cache) storeGet(key string, verify func([]byte) bool) ([]byte, bool) {
+	if c.store == nil || verify == nil {
+		return nil, false
+	}
+	value, ok, err := c.store.Get(c.storeKey(key))
+	if err != nil {
+		logrus.Errorf("could not read file %s from the file cache's store: %v", key, err)
+		return nil, false
+	}
+	if !ok || int64(len(value)) > c.maxFileBytes {
+		return nil, false
+	}
+	if !verify(value) {
+		logrus.Warnf("file %s in the file cache's store doesn't match its hash, so it will be downloaded again", key)
+		return nil, false
+	}
+	return value, true
+}
+
+// storeSet writes the contents of the file with key 'key' to c's store, if it
+// has one and they pass 'verify' (so that other caches can verify them too).
+func (c *Cache) storeSet(key string, value []byte, verify func([]byte) bool) {
+	if c.store == nil || verify == nil || int64(len(value)) > c.maxFileBytes || !verify(value) {
+		return
+	}
+	if err := c.store.Set(c.storeKey(key), value, c.ttl); err != nil {
+		logrus.Errorf("could not write file %s to the file cache's store: %v", key, err)
+	}
+}
+
+func (c *Cache) put(key string, value []byte) {
+	if int64(len(value)) > c.maxFileBytes {
+		return // the file was bigger than its size said
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	e := &entry{key: key, value: value}
+	if c.ttl > 0 {
+		e.expires = time.Now().Add(c.ttl)
+	}
+	c.entries[key] = c.lru.PushFront(e)
+	c.size += int64(len(value))
+	for c.size > c.sizeBytes {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *Cache) remove(elem *list.Element) {
+	e := c.lru.Remove(elem).(*entry)
+	delete(c.entries, e.key)
+	c.size -= int64(len(e.value))
+}
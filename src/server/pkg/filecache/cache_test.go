@@ -0,0 +1,154 @@
+package filecache
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+// memStore is a Store that keeps files in memory, shared by the caches in a
+// test
+type memStore struct {
+	files map[string][]byte
+	ttls  map[string]time.Duration
+	err   error
+}
+
+func newMemStore() *memStore {
+	return &memStore{files: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (s *memStore) Get(key string) ([]byte, bool, error) {
+	if s.err != nil {
+		return nil, false, s.err
+	}
+	value, ok := s.files[key]
+	return value, ok, nil
+}
+
+func (s *memStore) Set(key string, value []byte, ttl time.Duration) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.files[key] = value
+	s.ttls[key] = ttl
+	return nil
+}
+
+type fetcher struct {
+	fetches int
+}
+
+func (f *fetcher) fetch(value string) func(io.Writer) error {
+	return func(w io.Writer) error {
+		f.fetches++
+		_, err := io.WriteString(w, value)
+		return err
+	}
+}
+
+func get(t *testing.T, c *Cache, f *fetcher, key, value string) bool {
+	buf := &bytes.Buffer{}
+	cached, err := c.Get(key, buf, f.fetch(value), nil)
+	require.NoError(t, err)
+	require.Equal(t, value, buf.String())
+	return cached
+}
+
+func TestCache(t *testing.T) {
+	c := NewCache(4, 10, 0)
+	require.True(t, c.Caches(4))
+	require.False(t, c.Caches(5))
+
+	f := &fetcher{}
+	require.False(t, get(t, c, f, "a", "aaaa"))
+	require.True(t, get(t, c, f, "a", "aaaa"))
+	require.Equal(t, 1, f.fetches)
+
+	// "a" is evicted once the cache holds more than 10 bytes, as it's the
+	// least recently used
+	require.False(t, get(t, c, f, "b", "bbbb"))
+	require.True(t, get(t, c, f, "a", "aaaa"))
+	require.False(t, get(t, c, f, "c", "cccc"))
+	require.False(t, get(t, c, f, "b", "bbbb"))
+	require.True(t, get(t, c, f, "c", "cccc"))
+	require.Equal(t, 4, f.fetches)
+
+	hits, misses := c.Stats()
+	require.Equal(t, int64(3), hits)
+	require.Equal(t, int64(4), misses)
+
+	// failed fetches aren't cached
+	_, err := c.Get("d", &bytes.Buffer{}, func(io.Writer) error { return errors.New("fetch failed") }, nil)
+	require.YesError(t, err)
+	require.False(t, get(t, c, f, "d", "dddd"))
+}
+
+func TestCacheTTL(t *testing.T) {
+	c := NewCache(0, 0, time.Millisecond)
+	f := &fetcher{}
+	require.False(t, get(t, c, f, "a", "aaaa"))
+	time.Sleep(5 * time.Millisecond)
+	require.False(t, get(t, c, f, "a", "aaaa"))
+	require.Equal(t, 2, f.fetches)
+}
+
+func TestCacheStore(t *testing.T) {
+	store := newMemStore()
+	c1 := NewStoreCache(4, 10, time.Minute, store, "tenant")
+	c2 := NewStoreCache(4, 10, time.Minute, store, "tenant")
+	f := &fetcher{}
+	// getVerified gets a file whose contents are checked against 'value'
+	getVerified := func(c *Cache, key, value string) bool {
+		buf := &bytes.Buffer{}
+		cached, err := c.Get(key, buf, f.fetch(value), func(v []byte) bool { return string(v) == value })
+		require.NoError(t, err)
+		require.Equal(t, value, buf.String())
+		return cached
+	}
+
+	// A file fetched by one cache is written to the store, in the cache's
+	// namespace, so the other cache reads it from there
+	require.False(t, getVerified(c1, "a", "aaaa"))
+	require.Equal(t, "aaaa", string(store.files["tenant/a"]))
+	require.Equal(t, time.Minute, store.ttls["tenant/a"])
+	require.True(t, getVerified(c2, "a", "aaaa"))
+	require.True(t, getVerified(c2, "a", "aaaa"))
+	require.Equal(t, 1, f.fetches)
+
+	// Caches in other namespaces don't share files
+	require.False(t, getVerified(NewStoreCache(4, 10, 0, store, "other"), "a", "aaaa"))
+	require.Equal(t, 2, f.fetches)
+
+	// Files that are too big, or that don't pass verification (e.g. because
+	// something else overwrote them in the store), aren't read from the store
+	store.files["tenant/b"] = []byte("bbbbb")
+	require.False(t, getVerified(c1, "b", "bbbb"))
+	store.files["tenant/c"] = []byte("evil")
+	require.False(t, getVerified(c1, "c", "cccc"))
+	require.Equal(t, "cccc", string(store.files["tenant/c"]))
+	require.Equal(t, 4, f.fetches)
+
+	// Files that can't be verified aren't read from or written to the store
+	store.files["tenant/d"] = []byte("evil")
+	require.False(t, get(t, c2, f, "d", "dddd"))
+	require.Equal(t, "evil", string(store.files["tenant/d"]))
+	require.Equal(t, 5, f.fetches)
+
+	// If the store fails, files are fetched
+	store.err = errors.New("store failed")
+	require.False(t, getVerified(c2, "e", "eeee"))
+	require.True(t, getVerified(c2, "e", "eeee"))
+	require.Equal(t, 6, f.fetches)
+}
+
+func TestCacheNil(t *testing.T) {
+	var c *Cache
+	require.False(t, c.Caches(0))
+	require.False(t, NewCache(0, 0, 0).Caches(int64(len(strings.Repeat("a", DefaultMaxFileBytes+1)))))
+}
@@ -0,0 +1,47 @@
+package filecache
+
+import (
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// maxMemcachedTTL is the longest expiration that memcached takes relative to
+// the current time. Longer expirations are taken as Unix times.
+const maxMemcachedTTL = 30 * 24 * time.Hour
+
+type memcachedStore struct {
+	client *memcache.Client
+}
+
+// NewMemcachedStore returns a Store that keeps files in the memcached servers
+// at 'addresses' (host:port), which files are spread across by key.
+func NewMemcachedStore(addresses ...string) Store {
+	client := memcache.New(addresses...)
+	client.Timeout = storeTimeout
+	return &memcachedStore{client: client}
+}
+
+func (s *memcachedStore) Get(key string) ([]byte, bool, error) {
+	item, err := s.client.Get(storeKeyPrefix + key)
+	if err != nil {
+		if errors.Is(err, memcache.ErrCacheMiss) {
+			return nil, false, nil
+		}
+		return nil, false, errors.EnsureStack(err)
+	}
+	return item.Value, true, nil
+}
+
+func (s *memcachedStore) Set(key string, value []byte, ttl time.Duration) error {
+	item := &memcache.Item{Key: storeKeyPrefix + key, Value: value}
+	if ttl > maxMemcachedTTL {
+		item.Expiration = int32(time.Now().Add(ttl).Unix())
+	} else if ttl > 0 {
+		// round up, as an expiration of 0 means the file doesn't expire
+		item.Expiration = int32((ttl + time.Second - 1) / time.Second)
+	}
+	return errors.EnsureStack(s.client.Set(item))
+}
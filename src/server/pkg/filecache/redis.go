@@ -0,0 +1,69 @@
+package filecache
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// storeTimeout is how long a Store waits to connect to its servers, and
+	// for each of their replies
+	storeTimeout = time.Second
+	// storeKeyPrefix is prepended to the keys of the files in a Store, which
+	// may be shared with other applications
+	storeKeyPrefix = "pachyderm-file-cache/"
+)
+
+type redisStore struct {
+	pool *redis.Pool
+}
+
+// NewRedisStore returns a Store that keeps files in the Redis server at
+// 'address', which is either host:port or a redis:// URL.
+func NewRedisStore(address string) Store {
+	return &redisStore{
+		pool: &redis.Pool{
+			MaxIdle:     8,
+			IdleTimeout: 5 * time.Minute,
+			Dial: func() (redis.Conn, error) {
+				options := []redis.DialOption{
+					redis.DialConnectTimeout(storeTimeout),
+					redis.DialReadTimeout(storeTimeout),
+					redis.DialWriteTimeout(storeTimeout),
+				}
+				if strings.Contains(address, "://") {
+					return redis.DialURL(address, options...)
+				}
+				return redis.Dial("tcp", address, options...)
+			},
+		},
+	}
+}
+
+func (s *redisStore) Get(key string) ([]byte, bool, error) {
+	conn := s.pool.Get()
+	defer conn.Close()
+	value, err := redis.Bytes(conn.Do("GET", storeKeyPrefix+key))
+	if err != nil {
+		if errors.Is(err, redis.ErrNil) {
+			return nil, false, nil
+		}
+		return nil, false, errors.EnsureStack(err)
+	}
+	return value, true, nil
+}
+
+func (s *redisStore) Set(key string, value []byte, ttl time.Duration) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+	args := []interface{}{storeKeyPrefix + key, value}
+	if ttl > 0 {
+		args = append(args, "PX", int64(ttl/time.Millisecond))
+	}
+	_, err := conn.Do("SET", args...)
+	return errors.EnsureStack(err)
+}
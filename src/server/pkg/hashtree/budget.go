@@ -0,0 +1,73 @@
+package hashtree
+
+import (
+	"sync/atomic"
+
+	units "github.com/docker/go-units"
+)
+
+// nodeOverhead is the approximate memory used by an in-memory node (not
+// counting its path and serialized size), including its directory hash
+const nodeOverhead = 256
+
+// MemoryBudget limits the memory used by the in-memory nodes of a set of
+// spilling Ordered hashtrees (see NewSpillingOrdered), such as the datum
+// hashtrees that a worker builds concurrently. It's safe for concurrent use.
+type MemoryBudget struct {
+	limit int64
+	used  int64 // accessed atomically
+	// onExceeded, if set, is called each time a reservation exceeds the budget
+	onExceeded func()
+	// onChange, if set, is called with the new usage each time it changes
+	onChange func(used int64)
+}
+
+// NewMemoryBudget returns a MemoryBudget of 'limit' bytes. If 'limit' is 0,
+// the budget is unlimited (but usage is still tracked).
+// 'onExceeded' and 'onChange' may be nil; they're used to export metrics.
+func NewMemoryBudget(limit int64, onExceeded func(), onChange func(used int64)) *MemoryBudget {
+	return &MemoryBudget{
+		limit:      limit,
+		onExceeded: onExceeded,
+		onChange:   onChange,
+	}
+}
+
+// Used returns the number of bytes currently reserved from the budget
+func (b *MemoryBudget) Used() int64 {
+	return atomic.LoadInt64(&b.used)
+}
+
+// reserve reserves 'n' bytes from the budget, or returns an error with code
+// MemoryLimitExceeded (and reserves nothing) if that would exceed it
+func (b *MemoryBudget) reserve(n int64) error {
+	used := atomic.AddInt64(&b.used, n)
+	if b.limit > 0 && used > b.limit {
+		atomic.AddInt64(&b.used, -n)
+		if b.onExceeded != nil {
+			b.onExceeded()
+		}
+		return errorf(MemoryLimitExceeded, "building hashtrees would use more than "+
+			"the worker's hashtree memory limit of %s; increase the pipeline's "+
+			"hashtree_memory_limit, or write fewer directories per datum",
+			units.BytesSize(float64(b.limit)))
+	}
+	if b.onChange != nil {
+		b.onChange(used)
+	}
+	return nil
+}
+
+// release returns 'n' bytes to the budget
+func (b *MemoryBudget) release(n int64) {
+	used := atomic.AddInt64(&b.used, -n)
+	if b.onChange != nil {
+		b.onChange(used)
+	}
+}
+
+// IsMemoryLimitExceeded returns true if 'err' was caused by exceeding a
+// MemoryBudget
+func IsMemoryLimitExceeded(err error) bool {
+	return Code(err) == MemoryLimitExceeded
+}
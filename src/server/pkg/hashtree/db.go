@@ -1,12 +1,14 @@
 package hashtree
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"crypto/sha256"
 	"fmt"
 	"hash"
 	"io"
+	"io/ioutil"
 	"os"
 	pathlib "path"
 	"regexp"
@@ -1247,12 +1249,7 @@ func merge(ns []*MergeNode) (*MergeNode, error) {
 		return ns[0], nil
 	}
 	base := ns[0]
-	base.nodeProto = &NodeProto{}
-	if err := base.nodeProto.Unmarshal(base.v); err != nil {
-		return nil, errors.EnsureStack(err)
-	}
-	for i := 1; i < len(ns); i++ {
-		n := ns[i]
+	for _, n := range ns {
 		n.nodeProto = &NodeProto{}
 		if err := n.nodeProto.Unmarshal(n.v); err != nil {
 			return nil, errors.EnsureStack(err)
@@ -1262,6 +1259,14 @@ func merge(ns []*MergeNode) (*MergeNode, error) {
 			return nil, errorf(PathConflict, "could not merge path \"%s\" "+
 				"which is a different type in different hashtrees", s(base.k))
 		}
+	}
+	// Files with a merge strategy are merged segment by segment, so that the
+	// result doesn't depend on the order of the hashtrees
+	if base.nodeProto.nodetype() == file && hasMergeStrategy(ns) {
+		return mergeSegments(ns)
+	}
+	for i := 1; i < len(ns); i++ {
+		n := ns[i]
 		// Merge file content
 		if base.nodeProto.nodetype() == file {
 			base.nodeProto.FileNode.BlockRefs = append(base.nodeProto.FileNode.BlockRefs, n.nodeProto.FileNode.BlockRefs...)
@@ -1339,6 +1344,88 @@ func Merge(w *Writer, rs []*Reader) error {
 	return nil
 }
 
+// Changed compares the serialized hashtrees 'oldTree' and 'newTree', reading
+// only the keys that pass 'filter', and calls 'f' with the path of each node
+// that is only in one of them, or that is a file whose hash differs between
+// them. Directories that are in both aren't compared, as their hashes depend
+// on how their children were merged; their changed children are reported
+// instead.
+func Changed(oldTree, newTree io.Reader, filter Filter, f func(path string) error) error {
+	next := func(r *Reader) (*MergeNode, error) {
+		n, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return n, err
+	}
+	oldR, newR := NewReader(oldTree, filter), NewReader(newTree, filter)
+	o, err := next(oldR)
+	if err != nil {
+		return err
+	}
+	n, err := next(newR)
+	if err != nil {
+		return err
+	}
+	for o != nil || n != nil {
+		cmp := 0
+		switch {
+		case o == nil:
+			cmp = 1
+		case n == nil:
+			cmp = -1
+		default:
+			cmp = bytes.Compare(o.k, n.k)
+		}
+		if cmp < 0 {
+			if err := f(s(o.k)); err != nil {
+				return err
+			}
+		} else if cmp > 0 {
+			if err := f(s(n.k)); err != nil {
+				return err
+			}
+		} else if !bytes.Equal(o.v, n.v) {
+			changed, err := nodeChanged(o.v, n.v)
+			if err != nil {
+				return err
+			}
+			if changed {
+				if err := f(s(n.k)); err != nil {
+					return err
+				}
+			}
+		}
+		if cmp <= 0 {
+			if o, err = next(oldR); err != nil {
+				return err
+			}
+		}
+		if cmp >= 0 {
+			if n, err = next(newR); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// nodeChanged returns true if the serialized nodes 'oldV' and 'newV' are
+// files with different hashes, or are different types of nodes.
+func nodeChanged(oldV, newV []byte) (bool, error) {
+	oldNode, newNode := &NodeProto{}, &NodeProto{}
+	if err := oldNode.Unmarshal(oldV); err != nil {
+		return false, errors.EnsureStack(err)
+	}
+	if err := newNode.Unmarshal(newV); err != nil {
+		return false, errors.EnsureStack(err)
+	}
+	if oldNode.nodetype() != newNode.nodetype() {
+		return true, nil
+	}
+	return oldNode.nodetype() == file && !bytes.Equal(oldNode.Hash, newNode.Hash), nil
+}
+
 func nodes(rs []io.ReadCloser, f func(path string, nodeProto *NodeProto) error) error {
 	mq := &mergePQ{q: make([]*nodeStream, len(rs)+1)}
 	// Setup first set of nodes
@@ -1640,6 +1727,18 @@ type Ordered struct {
 	fs       []*node
 	dirStack []*node
 	root     string
+
+	// spill, if set, holds the tree's nodes in order (see NewSpillingOrdered).
+	// Only directory nodes, whose hashes aren't known until all of their
+	// children are added, are kept in memory (in 'fs').
+	spill  *os.File
+	spillW *bufio.Writer
+	// budget, if set, limits the memory used by the nodes in 'fs'. 'reserved'
+	// is the memory reserved from it.
+	budget   *MemoryBudget
+	reserved int64
+	// err is the first error encountered while adding nodes
+	err error
 }
 
 type node struct {
@@ -1667,6 +1766,107 @@ func NewOrdered(root string) *Ordered {
 	return o
 }
 
+// NewSpillingOrdered creates a new ordered hashtree that writes its file nodes
+// to a temporary file in 'dir' (or the default temporary directory, if 'dir'
+// is empty) as they're added, rather than holding them in memory, so that
+// trees with millions of files can be built. The memory used by its directory
+// nodes is reserved from 'budget' (if non-nil). The tree must be closed with
+// Close.
+func NewSpillingOrdered(root string, dir string, budget *MemoryBudget) (*Ordered, error) {
+	spill, err := ioutil.TempFile(dir, "ordered-hashtree-")
+	if err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	o := &Ordered{
+		spill:  spill,
+		spillW: bufio.NewWriter(spill),
+		budget: budget,
+	}
+	n := &node{
+		path: "",
+		nodeProto: &NodeProto{
+			Name:    "",
+			DirNode: &DirectoryNodeProto{},
+		},
+		hash: sha256.New(),
+	}
+	o.add(n)
+	o.dirStack = append(o.dirStack, n)
+	root = clean(root)
+	o.MkdirAll(root)
+	o.root = root
+	if o.err != nil {
+		o.Close()
+		return nil, o.err
+	}
+	return o, nil
+}
+
+// add adds 'n' to the tree, spilling it to disk if possible. Errors are
+// recorded in o.err.
+func (o *Ordered) add(n *node) {
+	if o.err != nil {
+		return
+	}
+	if o.spill == nil {
+		o.fs = append(o.fs, n)
+		return
+	}
+	// Directory nodes are kept in memory until serialization, with an empty
+	// placeholder in the spill file (file nodes are never empty)
+	var v []byte
+	if n.nodeProto.DirNode != nil {
+		size := int64(len(n.path)+n.nodeProto.Size()) + nodeOverhead
+		if o.budget != nil {
+			if err := o.budget.reserve(size); err != nil {
+				o.err = errors.Wrapf(err, "could not add %q to hashtree (%d directories in memory)", n.path, len(o.fs))
+				return
+			}
+			o.reserved += size
+		}
+		o.fs = append(o.fs, n)
+	} else {
+		var err error
+		if v, err = n.nodeProto.Marshal(); err != nil {
+			o.err = errors.EnsureStack(err)
+			return
+		}
+	}
+	pbw := pbutil.NewWriter(o.spillW)
+	if _, err := pbw.WriteBytes(b(n.path)); err != nil {
+		o.err = errors.EnsureStack(err)
+		return
+	}
+	if _, err := pbw.WriteBytes(v); err != nil {
+		o.err = errors.EnsureStack(err)
+	}
+}
+
+// Err returns the first error (if any) encountered while adding nodes to the
+// tree. Errors can only occur in spilling trees, and are also returned by
+// Serialize.
+func (o *Ordered) Err() error {
+	return o.err
+}
+
+// Close releases the resources used by a spilling tree. It's a no-op for
+// in-memory trees.
+func (o *Ordered) Close() error {
+	if o.budget != nil {
+		o.budget.release(o.reserved)
+		o.reserved = 0
+	}
+	if o.spill == nil {
+		return nil
+	}
+	err := o.spill.Close()
+	if rmErr := os.Remove(o.spill.Name()); rmErr != nil && err == nil {
+		err = rmErr
+	}
+	o.spill, o.spillW = nil, nil
+	return errors.EnsureStack(err)
+}
+
 // MkdirAll puts all of the parent directories of a given
 // path into the hashtree.
 func (o *Ordered) MkdirAll(path string) {
@@ -1701,7 +1901,7 @@ func (o *Ordered) putDir(path string, nodeProto *NodeProto) {
 		nodeProto: nodeProto,
 		hash:      sha256.New(),
 	}
-	o.fs = append(o.fs, n)
+	o.add(n)
 	o.dirStack = append(o.dirStack, n)
 }
 
@@ -1724,7 +1924,7 @@ func (o *Ordered) putFile(path string, nodeProto *NodeProto) {
 		path:      path,
 		nodeProto: nodeProto,
 	}
-	o.fs = append(o.fs, n)
+	o.add(n)
 	o.dirStack[len(o.dirStack)-1].hash.Write([]byte(fmt.Sprintf("%s:%s:", n.nodeProto.Name, n.nodeProto.Hash)))
 	o.dirStack[len(o.dirStack)-1].nodeProto.SubtreeSize += nodeProto.SubtreeSize
 }
@@ -1754,6 +1954,9 @@ func (o *Ordered) Serialize(_w io.Writer) error {
 		parent.nodeProto.SubtreeSize += child.nodeProto.SubtreeSize
 	}
 	o.fs[0].nodeProto.Hash = o.fs[0].hash.Sum(nil)
+	if o.spill != nil {
+		return o.serializeSpill(w)
+	}
 	for _, n := range o.fs {
 		if err := w.Write(&MergeNode{
 			k:         b(n.path),
@@ -1765,6 +1968,42 @@ func (o *Ordered) Serialize(_w io.Writer) error {
 	return nil
 }
 
+// serializeSpill streams the nodes of a spilling tree from its spill file,
+// replacing the directory placeholders with the (now complete) directory
+// nodes in memory
+func (o *Ordered) serializeSpill(w *Writer) error {
+	if o.err != nil {
+		return o.err
+	}
+	if err := o.spillW.Flush(); err != nil {
+		return errors.EnsureStack(err)
+	}
+	if _, err := o.spill.Seek(0, io.SeekStart); err != nil {
+		return errors.EnsureStack(err)
+	}
+	r := NewReader(bufio.NewReader(o.spill), nil)
+	dirs := o.fs
+	for {
+		n, err := r.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return errors.EnsureStack(err)
+		}
+		if len(n.v) == 0 {
+			if len(dirs) == 0 {
+				return errorf(Internal, "spilled hashtree has more directories than are in memory")
+			}
+			n = &MergeNode{k: n.k, nodeProto: dirs[0].nodeProto}
+			dirs = dirs[1:]
+		}
+		if err := w.Write(n); err != nil {
+			return errors.EnsureStack(err)
+		}
+	}
+}
+
 // Unordered is an in memory version of the hashtree that supports random inserts. This will look more like the old version of hashtrees over time, with the key differences being that it supports arbitrary rooting and can easily be converted into a sorted tree.
 type Unordered struct {
 	fs   map[string]*NodeProto
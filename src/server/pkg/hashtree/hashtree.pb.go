@@ -23,6 +23,43 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+// MergeStrategy determines how a file that's written by more than one datum
+// is merged.
+type MergeStrategy int32
+
+const (
+	// The datums' writes are concatenated, in no particular order.
+	MergeStrategy_CONCAT MergeStrategy = 0
+	// The datums' writes are concatenated in order of their datum keys.
+	MergeStrategy_CONCAT_SORTED MergeStrategy = 1
+	// Only the write of the datum with the greatest datum key is kept.
+	MergeStrategy_LAST_WRITER_WINS MergeStrategy = 2
+	// Merging writes from more than one datum is an error.
+	MergeStrategy_ERROR_ON_CONFLICT MergeStrategy = 3
+)
+
+var MergeStrategy_name = map[int32]string{
+	0: "CONCAT",
+	1: "CONCAT_SORTED",
+	2: "LAST_WRITER_WINS",
+	3: "ERROR_ON_CONFLICT",
+}
+
+var MergeStrategy_value = map[string]int32{
+	"CONCAT":            0,
+	"CONCAT_SORTED":     1,
+	"LAST_WRITER_WINS":  2,
+	"ERROR_ON_CONFLICT": 3,
+}
+
+func (x MergeStrategy) String() string {
+	return proto.EnumName(MergeStrategy_name, int32(x))
+}
+
+func (MergeStrategy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_4bd44075bd9a7a70, []int{0}
+}
+
 // FileNodeProto is a node corresponding to a file (which is also a leaf node).
 type FileNodeProto struct {
 	// objects are references to the object store, whose targets contain this
@@ -40,10 +77,15 @@ type FileNodeProto struct {
 	// block_refs/objects. Without this signal, all calls to pfs.GetFile() would
 	// need to check the parent directory's metadata before beginning to return
 	// the file's contents, which would be slow.)
-	HasHeaderFooter      bool     `protobuf:"varint,6,opt,name=has_header_footer,json=hasHeaderFooter,proto3" json:"has_header_footer,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	HasHeaderFooter bool `protobuf:"varint,6,opt,name=has_header_footer,json=hasHeaderFooter,proto3" json:"has_header_footer,omitempty"`
+	// merge_strategy determines how this file is merged with the same file in
+	// other hashtrees (i.e. written by other datums). If it's set,
+	// datum_segments records which datum wrote which part of the file.
+	MergeStrategy        MergeStrategy   `protobuf:"varint,7,opt,name=merge_strategy,json=mergeStrategy,proto3,enum=hashtree.MergeStrategy" json:"merge_strategy,omitempty"`
+	DatumSegments        []*DatumSegment `protobuf:"bytes,8,rep,name=datum_segments,json=datumSegments,proto3" json:"datum_segments,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
 func (m *FileNodeProto) Reset()         { *m = FileNodeProto{} }
@@ -100,6 +142,97 @@ func (m *FileNodeProto) GetHasHeaderFooter() bool {
 	return false
 }
 
+func (m *FileNodeProto) GetMergeStrategy() MergeStrategy {
+	if m != nil {
+		return m.MergeStrategy
+	}
+	return MergeStrategy_CONCAT
+}
+
+func (m *FileNodeProto) GetDatumSegments() []*DatumSegment {
+	if m != nil {
+		return m.DatumSegments
+	}
+	return nil
+}
+
+// DatumSegment is the part of a file's content that was written by one datum.
+type DatumSegment struct {
+	// datum is the key of the datum that wrote the segment. It's empty for
+	// content whose datum isn't known (e.g. it was written before the file had
+	// a merge strategy).
+	Datum string `protobuf:"bytes,1,opt,name=datum,proto3" json:"datum,omitempty"`
+	// num_refs is the number of the file's objects or block_refs that hold
+	// the segment.
+	NumRefs              int64    `protobuf:"varint,2,opt,name=num_refs,json=numRefs,proto3" json:"num_refs,omitempty"`
+	SizeBytes            int64    `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	Hash                 []byte   `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DatumSegment) Reset()         { *m = DatumSegment{} }
+func (m *DatumSegment) String() string { return proto.CompactTextString(m) }
+func (*DatumSegment) ProtoMessage()    {}
+func (*DatumSegment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4bd44075bd9a7a70, []int{1}
+}
+func (m *DatumSegment) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DatumSegment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DatumSegment.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DatumSegment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DatumSegment.Merge(m, src)
+}
+func (m *DatumSegment) XXX_Size() int {
+	return m.Size()
+}
+func (m *DatumSegment) XXX_DiscardUnknown() {
+	xxx_messageInfo_DatumSegment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DatumSegment proto.InternalMessageInfo
+
+func (m *DatumSegment) GetDatum() string {
+	if m != nil {
+		return m.Datum
+	}
+	return ""
+}
+
+func (m *DatumSegment) GetNumRefs() int64 {
+	if m != nil {
+		return m.NumRefs
+	}
+	return 0
+}
+
+func (m *DatumSegment) GetSizeBytes() int64 {
+	if m != nil {
+		return m.SizeBytes
+	}
+	return 0
+}
+
+func (m *DatumSegment) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
 // Shared refers to data common to all direct children of a directory (i.e.
 // headers and footers)
 type Shared struct {
@@ -118,7 +251,7 @@ func (m *Shared) Reset()         { *m = Shared{} }
 func (m *Shared) String() string { return proto.CompactTextString(m) }
 func (*Shared) ProtoMessage()    {}
 func (*Shared) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4bd44075bd9a7a70, []int{1}
+	return fileDescriptor_4bd44075bd9a7a70, []int{2}
 }
 func (m *Shared) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -196,7 +329,7 @@ func (m *DirectoryNodeProto) Reset()         { *m = DirectoryNodeProto{} }
 func (m *DirectoryNodeProto) String() string { return proto.CompactTextString(m) }
 func (*DirectoryNodeProto) ProtoMessage()    {}
 func (*DirectoryNodeProto) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4bd44075bd9a7a70, []int{2}
+	return fileDescriptor_4bd44075bd9a7a70, []int{3}
 }
 func (m *DirectoryNodeProto) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -263,7 +396,7 @@ func (m *NodeProto) Reset()         { *m = NodeProto{} }
 func (m *NodeProto) String() string { return proto.CompactTextString(m) }
 func (*NodeProto) ProtoMessage()    {}
 func (*NodeProto) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4bd44075bd9a7a70, []int{3}
+	return fileDescriptor_4bd44075bd9a7a70, []int{4}
 }
 func (m *NodeProto) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -352,7 +485,7 @@ func (m *HashTreeProto) Reset()         { *m = HashTreeProto{} }
 func (m *HashTreeProto) String() string { return proto.CompactTextString(m) }
 func (*HashTreeProto) ProtoMessage()    {}
 func (*HashTreeProto) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4bd44075bd9a7a70, []int{4}
+	return fileDescriptor_4bd44075bd9a7a70, []int{5}
 }
 func (m *HashTreeProto) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -406,7 +539,7 @@ func (m *BucketHeader) Reset()         { *m = BucketHeader{} }
 func (m *BucketHeader) String() string { return proto.CompactTextString(m) }
 func (*BucketHeader) ProtoMessage()    {}
 func (*BucketHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4bd44075bd9a7a70, []int{5}
+	return fileDescriptor_4bd44075bd9a7a70, []int{6}
 }
 func (m *BucketHeader) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -454,7 +587,7 @@ func (m *Index) Reset()         { *m = Index{} }
 func (m *Index) String() string { return proto.CompactTextString(m) }
 func (*Index) ProtoMessage()    {}
 func (*Index) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4bd44075bd9a7a70, []int{6}
+	return fileDescriptor_4bd44075bd9a7a70, []int{7}
 }
 func (m *Index) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -498,7 +631,9 @@ func (m *Index) GetOffset() uint64 {
 }
 
 func init() {
+	proto.RegisterEnum("hashtree.MergeStrategy", MergeStrategy_name, MergeStrategy_value)
 	proto.RegisterType((*FileNodeProto)(nil), "hashtree.FileNodeProto")
+	proto.RegisterType((*DatumSegment)(nil), "hashtree.DatumSegment")
 	proto.RegisterType((*Shared)(nil), "hashtree.Shared")
 	proto.RegisterType((*DirectoryNodeProto)(nil), "hashtree.DirectoryNodeProto")
 	proto.RegisterType((*NodeProto)(nil), "hashtree.NodeProto")
@@ -513,43 +648,55 @@ func init() {
 }
 
 var fileDescriptor_4bd44075bd9a7a70 = []byte{
-	// 576 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x53, 0xcb, 0x6e, 0xd3, 0x40,
-	0x14, 0xd5, 0xd8, 0x4e, 0xe2, 0xdc, 0xa4, 0x22, 0x0c, 0x08, 0xac, 0x0a, 0xb5, 0xc1, 0x08, 0x14,
-	0x10, 0x24, 0x52, 0x41, 0x80, 0x58, 0x56, 0xa5, 0x2a, 0x59, 0x00, 0x9a, 0xb2, 0x62, 0x13, 0xf9,
-	0x71, 0x5d, 0x1b, 0xbb, 0x9e, 0x68, 0xc6, 0xa9, 0x48, 0xbf, 0x83, 0x2f, 0x60, 0xc1, 0x9f, 0x20,
-	0xb1, 0xe4, 0x13, 0x50, 0xbf, 0x04, 0xcd, 0xa3, 0x75, 0x0b, 0x5d, 0x44, 0xba, 0xe7, 0xdc, 0x73,
-	0x8f, 0xef, 0x99, 0xcc, 0x40, 0x28, 0x51, 0x9c, 0xa0, 0x98, 0x2d, 0xcb, 0xa3, 0x59, 0x1e, 0xc9,
-	0xbc, 0x11, 0x88, 0x17, 0xc5, 0x74, 0x29, 0x78, 0xc3, 0xa9, 0x7f, 0x8e, 0x37, 0x6f, 0x27, 0x55,
-	0x81, 0x75, 0x33, 0x5b, 0x66, 0x52, 0xfd, 0x4c, 0x3f, 0xfc, 0x4e, 0x60, 0x63, 0xbf, 0xa8, 0xf0,
-	0x3d, 0x4f, 0xf1, 0xa3, 0x9e, 0x78, 0x08, 0x3d, 0x1e, 0x7f, 0xc1, 0xa4, 0x91, 0x81, 0x37, 0x76,
-	0x27, 0x83, 0x9d, 0xc1, 0x54, 0xc9, 0x3f, 0x68, 0x8e, 0x9d, 0xf7, 0xe8, 0x53, 0x80, 0xb8, 0xe2,
-	0x49, 0xb9, 0x10, 0x98, 0xc9, 0xa0, 0xa3, 0x95, 0x1b, 0x5a, 0xb9, 0xab, 0x68, 0x86, 0x19, 0xeb,
-	0xc7, 0xb6, 0x92, 0xf4, 0x09, 0xdc, 0xcc, 0x23, 0xb9, 0xc8, 0x31, 0x4a, 0x51, 0x2c, 0x32, 0xce,
-	0x1b, 0x14, 0x41, 0x77, 0x4c, 0x26, 0x3e, 0xbb, 0x91, 0x47, 0xf2, 0x40, 0xf3, 0xfb, 0x9a, 0x9e,
-	0x7b, 0x3e, 0x19, 0x39, 0x73, 0xcf, 0x77, 0x46, 0xee, 0xdc, 0xf3, 0xdd, 0x91, 0x17, 0x7e, 0x23,
-	0xd0, 0x3d, 0xcc, 0x23, 0x81, 0x29, 0x7d, 0x00, 0x5d, 0x63, 0x12, 0x90, 0x31, 0xf9, 0x77, 0x39,
-	0xdb, 0x52, 0x22, 0xfb, 0x09, 0xe7, 0x1a, 0x91, 0x69, 0xd1, 0x6d, 0x18, 0xd8, 0x75, 0x64, 0x71,
-	0x8a, 0x81, 0x3b, 0x26, 0x13, 0x97, 0x81, 0xa1, 0x0e, 0x8b, 0x53, 0x54, 0x02, 0x23, 0x35, 0x02,
-	0xcf, 0x08, 0x0c, 0xa5, 0x04, 0x61, 0x06, 0x74, 0xaf, 0x10, 0x98, 0x34, 0x5c, 0xac, 0xdb, 0xf3,
-	0xdb, 0x04, 0x3f, 0xc9, 0x8b, 0x2a, 0x15, 0x58, 0x07, 0xee, 0xd8, 0x9d, 0xf4, 0xd9, 0x05, 0xa6,
-	0x13, 0xe8, 0x4a, 0x9d, 0x43, 0xbb, 0x0d, 0x76, 0x46, 0xd3, 0x8b, 0xbf, 0xcb, 0xe4, 0x63, 0xb6,
-	0x7f, 0xf9, 0x10, 0xc2, 0x9f, 0x04, 0xfa, 0xad, 0x3f, 0x05, 0xaf, 0x8e, 0x8e, 0x51, 0xe7, 0xef,
-	0x33, 0x5d, 0x2b, 0x4e, 0x19, 0xe9, 0xb8, 0x43, 0xa6, 0x6b, 0x7a, 0x1f, 0x86, 0x72, 0x15, 0x2b,
-	0xef, 0xcb, 0x01, 0x07, 0x96, 0xd3, 0x09, 0x5f, 0x40, 0x3f, 0x2b, 0x2a, 0x5c, 0xd4, 0x3c, 0x45,
-	0xbb, 0xd1, 0xdd, 0x76, 0xa3, 0x2b, 0xd7, 0x82, 0xf9, 0x99, 0x85, 0xf4, 0x15, 0xf8, 0x69, 0x21,
-	0xcc, 0x50, 0x47, 0x0f, 0xdd, 0x6b, 0x87, 0xfe, 0x3f, 0x10, 0xd6, 0x4b, 0x0b, 0xa1, 0x50, 0xf8,
-	0x83, 0xc0, 0xc6, 0x41, 0x24, 0xf3, 0x4f, 0x02, 0x6d, 0x96, 0x00, 0x7a, 0x27, 0x28, 0x64, 0xc1,
-	0x6b, 0x1d, 0xa7, 0xc3, 0xce, 0x21, 0x9d, 0x81, 0x93, 0xc9, 0xc0, 0xd1, 0xd7, 0x6a, 0xbb, 0xb5,
-	0xbf, 0x32, 0x3e, 0xdd, 0x97, 0x6f, 0xeb, 0x46, 0xac, 0x99, 0x93, 0xc9, 0xcd, 0x39, 0xf4, 0x2c,
-	0xa4, 0x23, 0x70, 0x4b, 0x5c, 0xdb, 0x03, 0x52, 0x25, 0x7d, 0x0c, 0x9d, 0x93, 0xa8, 0x5a, 0xa1,
-	0xbd, 0x0f, 0xb7, 0x5a, 0xc3, 0x76, 0x4d, 0xa3, 0x78, 0xe3, 0xbc, 0x26, 0xe1, 0x23, 0x18, 0xee,
-	0xae, 0x92, 0x12, 0x1b, 0x73, 0x2f, 0xe9, 0x1d, 0xe8, 0xc6, 0x1a, 0x5b, 0x4f, 0x8b, 0xc2, 0x67,
-	0xd0, 0x79, 0x57, 0xa7, 0xf8, 0x95, 0x0e, 0x81, 0x94, 0xba, 0x37, 0x64, 0xa4, 0x54, 0x72, 0x9e,
-	0x65, 0x12, 0x1b, 0xfd, 0x39, 0x8f, 0x59, 0xb4, 0xbb, 0xf7, 0xeb, 0x6c, 0x8b, 0xfc, 0x3e, 0xdb,
-	0x22, 0x7f, 0xce, 0xb6, 0xc8, 0xe7, 0x97, 0x47, 0x45, 0x93, 0xaf, 0xe2, 0x69, 0xc2, 0x8f, 0x67,
-	0xcb, 0x28, 0xc9, 0xd7, 0x29, 0x8a, 0xcb, 0x95, 0x14, 0xc9, 0xec, 0x9a, 0x07, 0x1e, 0x77, 0xf5,
-	0xc3, 0x7d, 0xfe, 0x37, 0x00, 0x00, 0xff, 0xff, 0x1c, 0x5c, 0xcd, 0xd9, 0xfe, 0x03, 0x00, 0x00,
+	// 766 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x54, 0xef, 0x6e, 0xe3, 0x44,
+	0x10, 0x67, 0x6d, 0x27, 0x71, 0x26, 0x49, 0x71, 0x97, 0x72, 0x98, 0x0a, 0x7a, 0xc1, 0x08, 0x14,
+	0x4e, 0x90, 0x48, 0x05, 0x01, 0x42, 0x02, 0xe9, 0xfa, 0x4f, 0xd7, 0xea, 0x48, 0xd0, 0x26, 0xd2,
+	0x49, 0xf0, 0xc1, 0x72, 0xec, 0x71, 0x6c, 0x92, 0xd8, 0xd5, 0xae, 0x53, 0x91, 0x7b, 0x0e, 0x5e,
+	0x80, 0x2f, 0xbc, 0x09, 0x12, 0x1f, 0x79, 0x04, 0xd4, 0x27, 0x41, 0xfb, 0xa7, 0x8d, 0xcb, 0xf5,
+	0x83, 0xa5, 0xf9, 0xcd, 0xef, 0x37, 0xb3, 0x33, 0xb3, 0xb3, 0x86, 0x40, 0x20, 0xbf, 0x41, 0x3e,
+	0xba, 0x5e, 0x2e, 0x46, 0x59, 0x24, 0xb2, 0x8a, 0x23, 0xde, 0x1b, 0xc3, 0x6b, 0x5e, 0x56, 0x25,
+	0x75, 0xef, 0xf0, 0xe1, 0x41, 0xbc, 0xca, 0xb1, 0xa8, 0x46, 0xd7, 0xa9, 0x90, 0x9f, 0xe6, 0x83,
+	0x3f, 0x2c, 0xe8, 0x5d, 0xe4, 0x2b, 0x1c, 0x97, 0x09, 0xfe, 0xa4, 0x22, 0x3e, 0x81, 0x56, 0x39,
+	0xff, 0x15, 0xe3, 0x4a, 0xf8, 0x4e, 0xdf, 0x1e, 0x74, 0x8e, 0x3b, 0x43, 0x29, 0x9f, 0x28, 0x1f,
+	0xbb, 0xe3, 0xe8, 0xe7, 0x00, 0xf3, 0x55, 0x19, 0x2f, 0x43, 0x8e, 0xa9, 0xf0, 0x1b, 0x4a, 0xd9,
+	0x53, 0xca, 0x13, 0xe9, 0x66, 0x98, 0xb2, 0xf6, 0xdc, 0x58, 0x82, 0x3e, 0x83, 0xfd, 0x2c, 0x12,
+	0x61, 0x86, 0x51, 0x82, 0x3c, 0x4c, 0xcb, 0xb2, 0x42, 0xee, 0x37, 0xfb, 0x64, 0xe0, 0xb2, 0xb7,
+	0xb3, 0x48, 0xbc, 0x50, 0xfe, 0x0b, 0xe5, 0xa6, 0x3f, 0xc0, 0xde, 0x1a, 0xf9, 0x02, 0x43, 0x51,
+	0xf1, 0xa8, 0xc2, 0xc5, 0xd6, 0x6f, 0xf5, 0xc9, 0x60, 0xef, 0xf8, 0xbd, 0xe1, 0x7d, 0x6f, 0x3f,
+	0x4a, 0x7e, 0x6a, 0x68, 0xd6, 0x5b, 0xd7, 0x21, 0xfd, 0x1e, 0xf6, 0x92, 0xa8, 0xda, 0xac, 0x43,
+	0x81, 0x8b, 0x35, 0x16, 0x95, 0xf0, 0x5d, 0x55, 0xdd, 0x93, 0x5d, 0xfc, 0x99, 0xe4, 0xa7, 0x9a,
+	0x66, 0xbd, 0xa4, 0x86, 0xc4, 0x95, 0xe3, 0x12, 0xcf, 0xba, 0x72, 0x5c, 0xcb, 0xb3, 0xaf, 0x1c,
+	0xd7, 0xf6, 0x9c, 0x80, 0x43, 0xb7, 0x1e, 0x40, 0x0f, 0xa0, 0xa1, 0x42, 0x7c, 0xd2, 0x27, 0x83,
+	0x36, 0xd3, 0x80, 0xbe, 0x0f, 0x6e, 0xb1, 0x59, 0xeb, 0x71, 0x58, 0x7d, 0x32, 0xb0, 0x59, 0xab,
+	0xd8, 0xac, 0x55, 0xf7, 0x1f, 0x02, 0x88, 0xfc, 0x35, 0x86, 0xf3, 0x6d, 0x85, 0xc2, 0xb7, 0x15,
+	0xd9, 0x96, 0x9e, 0x13, 0xe9, 0xa0, 0x14, 0x1c, 0x59, 0x99, 0xef, 0xf4, 0xc9, 0xa0, 0xcb, 0x94,
+	0x1d, 0xfc, 0x4e, 0xa0, 0x39, 0xcd, 0x22, 0x8e, 0x09, 0xfd, 0x18, 0x9a, 0x7a, 0x6e, 0xea, 0xbc,
+	0xff, 0xdd, 0x87, 0xa1, 0xa4, 0xc8, 0x4c, 0xd5, 0x7a, 0x44, 0xa4, 0x29, 0xfa, 0x14, 0x3a, 0xe6,
+	0x06, 0xe4, 0xe1, 0xa6, 0x10, 0xd0, 0xae, 0x69, 0xfe, 0x1a, 0xa5, 0x40, 0x4b, 0xb5, 0xc0, 0xd1,
+	0x02, 0xed, 0x92, 0x82, 0x20, 0x05, 0x7a, 0x96, 0x73, 0x8c, 0xab, 0x92, 0x6f, 0x77, 0x2b, 0x73,
+	0x08, 0x6e, 0x9c, 0xe5, 0xab, 0x84, 0x63, 0xe1, 0xdb, 0x7d, 0x7b, 0xd0, 0x66, 0xf7, 0x98, 0x0e,
+	0xa0, 0x29, 0x54, 0x1f, 0x2a, 0x5b, 0xe7, 0xd8, 0xdb, 0xdd, 0x82, 0xee, 0x8f, 0x19, 0xbe, 0x3e,
+	0xf8, 0xe0, 0x2f, 0x02, 0xed, 0x5d, 0x7e, 0x0a, 0x4e, 0x11, 0xad, 0xd1, 0xcc, 0x5b, 0xd9, 0xf7,
+	0x43, 0xb3, 0x76, 0x43, 0xa3, 0x1f, 0x41, 0x57, 0x6c, 0xe6, 0x32, 0x77, 0xbd, 0xc1, 0x8e, 0xf1,
+	0xa9, 0x0e, 0xbf, 0x82, 0x76, 0x9a, 0xaf, 0x30, 0x2c, 0xca, 0x04, 0x4d, 0x45, 0xb5, 0xbd, 0x7a,
+	0xf0, 0x12, 0x98, 0x9b, 0x1a, 0x48, 0xbf, 0x01, 0x37, 0xc9, 0xb9, 0x0e, 0x6a, 0xa8, 0xa0, 0x0f,
+	0x6a, 0xcb, 0xf4, 0xc6, 0x40, 0x58, 0x2b, 0xc9, 0xb9, 0x44, 0xc1, 0x9f, 0x04, 0x7a, 0x2f, 0x22,
+	0x91, 0xcd, 0x38, 0x9a, 0x5e, 0x7c, 0x68, 0xdd, 0x20, 0x17, 0x79, 0x59, 0xa8, 0x76, 0x1a, 0xec,
+	0x0e, 0xd2, 0x11, 0x58, 0x6a, 0x75, 0xe4, 0xae, 0x3e, 0xdd, 0xa5, 0x7f, 0x10, 0x3e, 0xbc, 0x10,
+	0xe7, 0x45, 0xc5, 0xb7, 0xcc, 0x4a, 0xc5, 0xe1, 0x15, 0xb4, 0x0c, 0xa4, 0x1e, 0xd8, 0x4b, 0xdc,
+	0x9a, 0x01, 0x49, 0x93, 0x7e, 0x06, 0x8d, 0x9b, 0x68, 0xb5, 0x41, 0xb3, 0x0f, 0xef, 0xec, 0x12,
+	0xee, 0xca, 0xd4, 0x8a, 0xef, 0xac, 0x6f, 0x49, 0xf0, 0x29, 0x74, 0x4f, 0x36, 0xf1, 0x12, 0x2b,
+	0xfd, 0x14, 0xe9, 0x13, 0x68, 0xce, 0x15, 0x36, 0x39, 0x0d, 0x0a, 0xbe, 0x80, 0xc6, 0x65, 0x91,
+	0xe0, 0x6f, 0xb4, 0x0b, 0x64, 0xa9, 0xb8, 0x2e, 0x23, 0x4b, 0x29, 0x2f, 0xd3, 0x54, 0x60, 0xa5,
+	0x8e, 0x73, 0x98, 0x41, 0xcf, 0x7e, 0x81, 0xde, 0x83, 0xb7, 0x4a, 0x01, 0x9a, 0xa7, 0x93, 0xf1,
+	0xe9, 0xf3, 0x99, 0xf7, 0x16, 0xdd, 0x87, 0x9e, 0xb6, 0xc3, 0xe9, 0x84, 0xcd, 0xce, 0xcf, 0x3c,
+	0x42, 0x0f, 0xc0, 0x7b, 0xf9, 0x7c, 0x3a, 0x0b, 0x5f, 0xb1, 0xcb, 0xd9, 0x39, 0x0b, 0x5f, 0x5d,
+	0x8e, 0xa7, 0x9e, 0x45, 0xdf, 0x85, 0xfd, 0x73, 0xc6, 0x26, 0x2c, 0x9c, 0x8c, 0xc3, 0xd3, 0xc9,
+	0xf8, 0xe2, 0xe5, 0xe5, 0xe9, 0xcc, 0xb3, 0x4f, 0xce, 0xfe, 0xbe, 0x3d, 0x22, 0xff, 0xdc, 0x1e,
+	0x91, 0x7f, 0x6f, 0x8f, 0xc8, 0xcf, 0x5f, 0x2f, 0xf2, 0x2a, 0xdb, 0xcc, 0x87, 0x71, 0xb9, 0x1e,
+	0x5d, 0x47, 0x71, 0xb6, 0x4d, 0x90, 0xd7, 0x2d, 0xc1, 0xe3, 0xd1, 0x23, 0x3f, 0xcc, 0x79, 0x53,
+	0xfd, 0x08, 0xbf, 0xfc, 0x6f, 0x00, 0x89, 0xfa, 0xe0, 0x51, 0x4e, 0x05, 0x00, 0x00,
 }
 
 func (m *FileNodeProto) Marshal() (dAtA []byte, err error) {
@@ -576,6 +723,25 @@ func (m *FileNodeProto) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.DatumSegments) > 0 {
+		for iNdEx := len(m.DatumSegments) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.DatumSegments[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintHashtree(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if m.MergeStrategy != 0 {
+		i = encodeVarintHashtree(dAtA, i, uint64(m.MergeStrategy))
+		i--
+		dAtA[i] = 0x38
+	}
 	if m.HasHeaderFooter {
 		i--
 		if m.HasHeaderFooter {
@@ -617,6 +783,57 @@ func (m *FileNodeProto) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *DatumSegment) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DatumSegment) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DatumSegment) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintHashtree(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.SizeBytes != 0 {
+		i = encodeVarintHashtree(dAtA, i, uint64(m.SizeBytes))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.NumRefs != 0 {
+		i = encodeVarintHashtree(dAtA, i, uint64(m.NumRefs))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Datum) > 0 {
+		i -= len(m.Datum)
+		copy(dAtA[i:], m.Datum)
+		i = encodeVarintHashtree(dAtA, i, uint64(len(m.Datum)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *Shared) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -959,6 +1176,41 @@ func (m *FileNodeProto) Size() (n int) {
 	if m.HasHeaderFooter {
 		n += 2
 	}
+	if m.MergeStrategy != 0 {
+		n += 1 + sovHashtree(uint64(m.MergeStrategy))
+	}
+	if len(m.DatumSegments) > 0 {
+		for _, e := range m.DatumSegments {
+			l = e.Size()
+			n += 1 + l + sovHashtree(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *DatumSegment) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Datum)
+	if l > 0 {
+		n += 1 + l + sovHashtree(uint64(l))
+	}
+	if m.NumRefs != 0 {
+		n += 1 + sovHashtree(uint64(m.NumRefs))
+	}
+	if m.SizeBytes != 0 {
+		n += 1 + sovHashtree(uint64(m.SizeBytes))
+	}
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovHashtree(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1230,6 +1482,217 @@ func (m *FileNodeProto) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.HasHeaderFooter = bool(v != 0)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MergeStrategy", wireType)
+			}
+			m.MergeStrategy = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHashtree
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MergeStrategy |= MergeStrategy(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DatumSegments", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHashtree
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHashtree
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthHashtree
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DatumSegments = append(m.DatumSegments, &DatumSegment{})
+			if err := m.DatumSegments[len(m.DatumSegments)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHashtree(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHashtree
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthHashtree
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DatumSegment) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHashtree
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DatumSegment: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DatumSegment: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Datum", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHashtree
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHashtree
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthHashtree
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Datum = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NumRefs", wireType)
+			}
+			m.NumRefs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHashtree
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.NumRefs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SizeBytes", wireType)
+			}
+			m.SizeBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHashtree
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SizeBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHashtree
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthHashtree
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthHashtree
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = append(m.Hash[:0], dAtA[iNdEx:postIndex]...)
+			if m.Hash == nil {
+				m.Hash = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipHashtree(dAtA[iNdEx:])
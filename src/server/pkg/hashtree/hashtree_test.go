@@ -769,3 +769,167 @@ func TestMergeFiles(t *testing.T) {
 
 	require.Equal(t, expectedBuf, resultBuf)
 }
+
+func TestChanged(t *testing.T) {
+	serialize := func(files map[string]string) *bytes.Buffer {
+		u := NewUnordered("")
+		for path, hash := range files {
+			u.PutFile(path, []byte(hash), 1, blocks(``)...)
+		}
+		buf := &bytes.Buffer{}
+		require.NoError(t, u.Ordered().Serialize(buf))
+		return buf
+	}
+	oldTree := serialize(map[string]string{
+		"/region=EU/a":   "eu-a",
+		"/region=EU/b":   "eu-b",
+		"/region=US/a":   "us-a",
+		"/region=APAC/a": "apac-a",
+	})
+	newTree := serialize(map[string]string{
+		"/region=EU/a": "eu-a2",
+		"/region=EU/b": "eu-b",
+		"/region=US/a": "us-a",
+		"/region=SA/a": "sa-a",
+	})
+	var changed []string
+	require.NoError(t, Changed(oldTree, newTree, nil, func(path string) error {
+		changed = append(changed, path)
+		return nil
+	}))
+	// Directories in both trees (including the root) aren't reported, even
+	// though their hashes changed
+	require.ElementsEqual(t, []string{
+		"/region=APAC", "/region=APAC/a", "/region=EU/a", "/region=SA", "/region=SA/a",
+	}, changed)
+}
+
+// datumTree returns a serialized hashtree in which 'datum' wrote /file with
+// 'strategy'
+func datumTree(t *testing.T, datum string, strategy MergeStrategy) *bytes.Buffer {
+	o := NewOrdered("/")
+	o.PutFile("/file", []byte(datum), 1, &FileNodeProto{
+		BlockRefs:     []*pfs.BlockRef{{Block: &pfs.Block{Hash: datum}}},
+		MergeStrategy: strategy,
+		DatumSegments: []*DatumSegment{{Datum: datum, NumRefs: 1, SizeBytes: 1, Hash: []byte(datum)}},
+	})
+	buf := &bytes.Buffer{}
+	require.NoError(t, o.Serialize(buf))
+	return buf
+}
+
+// mergedFile merges 'trees' and returns the resulting node for /file
+func mergedFile(t *testing.T, trees ...*bytes.Buffer) (*NodeProto, error) {
+	var rs []*Reader
+	for _, tree := range trees {
+		rs = append(rs, NewReader(bytes.NewReader(tree.Bytes()), nil))
+	}
+	buf := &bytes.Buffer{}
+	if err := Merge(NewWriter(buf), rs); err != nil {
+		return nil, err
+	}
+	r := NewReader(buf, nil)
+	for {
+		n, err := r.Read()
+		require.NoError(t, err)
+		if s(n.k) == "/file" {
+			nodeProto := &NodeProto{}
+			require.NoError(t, nodeProto.Unmarshal(n.v))
+			return nodeProto, nil
+		}
+	}
+}
+
+func blockHashes(nodeProto *NodeProto) []string {
+	var result []string
+	for _, blockRef := range nodeProto.FileNode.BlockRefs {
+		result = append(result, blockRef.Block.Hash)
+	}
+	return result
+}
+
+func TestMergeStrategies(t *testing.T) {
+	// The result of a sorted merge doesn't depend on the order of the trees,
+	// or on how the merge is split up
+	sorted := func(datum string) *bytes.Buffer { return datumTree(t, datum, MergeStrategy_CONCAT_SORTED) }
+	n1, err := mergedFile(t, sorted("c"), sorted("a"), sorted("b"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, blockHashes(n1))
+	require.Equal(t, int64(3), n1.SubtreeSize)
+	chunk := &bytes.Buffer{}
+	require.NoError(t, Merge(NewWriter(chunk), []*Reader{
+		NewReader(sorted("c"), nil),
+		NewReader(sorted("a"), nil),
+	}))
+	n2, err := mergedFile(t, sorted("b"), chunk)
+	require.NoError(t, err)
+	require.Equal(t, n1, n2)
+
+	// Only the greatest datum's write is kept
+	lww := func(datum string) *bytes.Buffer { return datumTree(t, datum, MergeStrategy_LAST_WRITER_WINS) }
+	n, err := mergedFile(t, lww("b"), lww("c"), lww("a"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"c"}, blockHashes(n))
+	require.Equal(t, int64(1), n.SubtreeSize)
+	require.Equal(t, []byte("c"), n.Hash)
+
+	// Writes from more than one datum are an error
+	conflict := func(datum string) *bytes.Buffer { return datumTree(t, datum, MergeStrategy_ERROR_ON_CONFLICT) }
+	_, err = mergedFile(t, conflict("a"), conflict("b"))
+	require.YesError(t, err)
+	require.Equal(t, PathConflict, Code(err))
+	_, err = mergedFile(t, conflict("a"))
+	require.NoError(t, err)
+
+	// Datums can't disagree about the strategy
+	_, err = mergedFile(t, sorted("a"), lww("b"))
+	require.YesError(t, err)
+}
+
+func buildOrdered(o *Ordered) {
+	o.PutDir("/dir")
+	o.PutFile("/dir/bar", []byte("h1"), 1, &FileNodeProto{BlockRefs: blocks(``)})
+	o.PutFile("/dir/buzz", []byte("h2"), 2, &FileNodeProto{BlockRefs: blocks(``)})
+	o.PutDir("/dir/sub")
+	o.PutFile("/dir/sub/file", []byte("h3"), 3, &FileNodeProto{BlockRefs: blocks(``)})
+	o.PutFile("/foo", []byte("h4"), 4, &FileNodeProto{BlockRefs: blocks(``)})
+}
+
+func TestSpillingOrdered(t *testing.T) {
+	expected := NewOrdered("/out")
+	buildOrdered(expected)
+	expectedBuf := &bytes.Buffer{}
+	require.NoError(t, expected.Serialize(expectedBuf))
+
+	budget := NewMemoryBudget(0, nil, nil)
+	spilling, err := NewSpillingOrdered("/out", "", budget)
+	require.NoError(t, err)
+	buildOrdered(spilling)
+	require.NoError(t, spilling.Err())
+	require.True(t, budget.Used() > 0)
+	buf := &bytes.Buffer{}
+	require.NoError(t, spilling.Serialize(buf))
+	require.Equal(t, expectedBuf.Bytes(), buf.Bytes())
+	require.NoError(t, spilling.Close())
+	require.Equal(t, int64(0), budget.Used())
+}
+
+func TestSpillingOrderedMemoryLimit(t *testing.T) {
+	var exceeded int
+	budget := NewMemoryBudget(4*nodeOverhead, func() { exceeded++ }, nil)
+	o, err := NewSpillingOrdered("", "", budget)
+	require.NoError(t, err)
+	defer o.Close()
+	// Files don't use the budget, but directories do
+	for i := 0; i < 100; i++ {
+		o.PutFile(fmt.Sprintf("/file-%03d", i), []byte("h"), 1, &FileNodeProto{})
+	}
+	require.NoError(t, o.Err())
+	for i := 0; i < 10; i++ {
+		o.PutDir(fmt.Sprintf("/hdir-%d", i))
+	}
+	require.YesError(t, o.Err())
+	require.True(t, IsMemoryLimitExceeded(o.Err()))
+	require.Equal(t, 1, exceeded)
+	require.YesError(t, o.Serialize(&bytes.Buffer{}))
+}
@@ -68,6 +68,10 @@ const (
 	// to write to an input file that was created by copying from an output
 	// file.
 	MixedObjectsAndBlockRefs
+
+	// MemoryLimitExceeded is returned when building a spilling Ordered
+	// hashtree would exceed its MemoryBudget.
+	MemoryLimitExceeded
 )
 
 // HashTree is the signature of a hash tree provided by this library. To get a
@@ -0,0 +1,132 @@
+package hashtree
+
+import (
+	"sort"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+)
+
+// segment is a DatumSegment along with the refs that hold its content.
+type segment struct {
+	*DatumSegment
+	objects   []*pfs.Object
+	blockRefs []*pfs.BlockRef
+}
+
+// hasMergeStrategy returns true if any of the (file) nodes 'ns' has a merge
+// strategy other than CONCAT.
+func hasMergeStrategy(ns []*MergeNode) bool {
+	for _, n := range ns {
+		if n.nodeProto.FileNode.MergeStrategy != MergeStrategy_CONCAT {
+			return true
+		}
+	}
+	return false
+}
+
+// fileSegments splits the content of the file node 'n' into the segments
+// written by each datum. Content that wasn't written with a merge strategy is
+// treated as a single segment, written by an unknown datum.
+func fileSegments(n *NodeProto) ([]*segment, error) {
+	fileNode := n.FileNode
+	if len(fileNode.DatumSegments) == 0 {
+		return []*segment{{
+			DatumSegment: &DatumSegment{
+				NumRefs:   int64(len(fileNode.Objects) + len(fileNode.BlockRefs)),
+				SizeBytes: n.SubtreeSize,
+				Hash:      n.Hash,
+			},
+			objects:   fileNode.Objects,
+			blockRefs: fileNode.BlockRefs,
+		}}, nil
+	}
+	var result []*segment
+	objects, blockRefs := fileNode.Objects, fileNode.BlockRefs
+	for _, datumSegment := range fileNode.DatumSegments {
+		seg := &segment{DatumSegment: datumSegment}
+		numRefs := int(datumSegment.NumRefs)
+		switch {
+		case numRefs <= len(objects):
+			seg.objects, objects = objects[:numRefs], objects[numRefs:]
+		case numRefs <= len(blockRefs):
+			seg.blockRefs, blockRefs = blockRefs[:numRefs], blockRefs[numRefs:]
+		default:
+			return nil, errorf(Internal, "file \"%s\" has a datum segment with "+
+				"%d refs, but only %d remain", n.Name, numRefs, len(objects)+len(blockRefs))
+		}
+		result = append(result, seg)
+	}
+	return result, nil
+}
+
+// mergeSegments merges the file nodes 'ns', which all have the same path,
+// according to their merge strategy. Unlike the default merge, the result
+// only depends on the datums that wrote each segment, not on the order of
+// 'ns'.
+//
+// Note that the nodes of the file's parent directories have already been
+// merged by the time the file is, so if LAST_WRITER_WINS drops any segments,
+// the directories' sizes still include them.
+func mergeSegments(ns []*MergeNode) (*MergeNode, error) {
+	base := ns[0]
+	path := s(base.k)
+	strategy := MergeStrategy_CONCAT
+	var segments []*segment
+	for _, n := range ns {
+		fileNode := n.nodeProto.FileNode
+		// Content written before the file had a merge strategy can be merged
+		// with any strategy, but datums can't disagree about the strategy
+		if len(fileNode.DatumSegments) > 0 {
+			if strategy != MergeStrategy_CONCAT && fileNode.MergeStrategy != strategy {
+				return nil, errorf(PathConflict, "could not merge path \"%s\" "+
+					"which has different merge strategies (%v and %v) in different "+
+					"hashtrees", path, strategy, fileNode.MergeStrategy)
+			}
+			strategy = fileNode.MergeStrategy
+		}
+		nodeSegments, err := fileSegments(n.nodeProto)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, nodeSegments...)
+	}
+	sort.SliceStable(segments, func(i, j int) bool {
+		return segments[i].Datum < segments[j].Datum
+	})
+
+	switch strategy {
+	case MergeStrategy_LAST_WRITER_WINS:
+		segments = segments[len(segments)-1:]
+	case MergeStrategy_ERROR_ON_CONFLICT:
+		if first, last := segments[0].Datum, segments[len(segments)-1].Datum; first != last {
+			return nil, errorf(PathConflict, "could not merge path \"%s\" "+
+				"which was written by more than one datum (%q and %q), and its "+
+				"merge strategy is %v", path, first, last, strategy)
+		}
+	}
+
+	fileNode := &FileNodeProto{
+		HasHeaderFooter: base.nodeProto.FileNode.HasHeaderFooter,
+		MergeStrategy:   strategy,
+	}
+	var size int64
+	hasher := pfs.NewHash()
+	for _, seg := range segments {
+		fileNode.Objects = append(fileNode.Objects, seg.objects...)
+		fileNode.BlockRefs = append(fileNode.BlockRefs, seg.blockRefs...)
+		fileNode.DatumSegments = append(fileNode.DatumSegments, seg.DatumSegment)
+		size += seg.SizeBytes
+		hasher.Write(seg.Hash)
+	}
+	hash := hasher.Sum(nil)
+	if len(segments) == 1 {
+		hash = segments[0].Hash
+	}
+	base.nodeProto = &NodeProto{
+		Name:        base.nodeProto.Name,
+		Hash:        hash,
+		SubtreeSize: size,
+		FileNode:    fileNode,
+	}
+	return base, nil
+}
@@ -0,0 +1,85 @@
+package obj
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+
+	"google.golang.org/api/option"
+)
+
+// ReadCredentialsDir reads the credentials in a mounted kubernetes secret,
+// returning a map from each of the secret's keys to its (trimmed) value.
+func ReadCredentialsDir(dir string) (map[string]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read credentials in %s", dir)
+	}
+	creds := make(map[string]string)
+	for _, entry := range entries {
+		// kubernetes mounts secrets' keys as symlinks into a hidden
+		// directory (..data), which is skipped along with any other dotfiles
+		if strings.HasPrefix(entry.Name(), ".") || entry.IsDir() {
+			continue
+		}
+		value, err := ioutil.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read credential %q", entry.Name())
+		}
+		creds[entry.Name()] = strings.TrimSpace(string(value))
+	}
+	return creds, nil
+}
+
+// NewClientFromURLAndCredentials creates an obj Client for the object store in
+// 'url' that authenticates with 'creds', rather than with pachyderm's own
+// storage secret. 'creds' uses the same keys as the storage secret (e.g.
+// amazon-region, amazon-id and amazon-secret for s3).
+func NewClientFromURLAndCredentials(url *ObjectStoreURL, creds map[string]string, reverse ...bool) (c Client, err error) {
+	switch url.Store {
+	case "s3":
+		region := creds["amazon-region"]
+		if region == "" {
+			return nil, errors.Errorf("amazon-region not found in credentials")
+		}
+		if creds["amazon-id"] == "" && creds["amazon-vault-addr"] == "" {
+			return nil, errors.Errorf("neither amazon-id nor amazon-vault-addr found in credentials")
+		}
+		c, err = NewAmazonClient(region, url.Bucket, &AmazonCreds{
+			ID:           creds["amazon-id"],
+			Secret:       creds["amazon-secret"],
+			Token:        creds["amazon-token"],
+			VaultAddress: creds["amazon-vault-addr"],
+			VaultRole:    creds["amazon-vault-role"],
+			VaultToken:   creds["amazon-vault-token"],
+		}, "", creds["custom-endpoint"], reverse...)
+	case "gcs":
+		fallthrough
+	case "gs":
+		cred := creds["google-cred"]
+		if cred == "" {
+			return nil, errors.Errorf("google-cred not found in credentials")
+		}
+		c, err = NewGoogleClient(url.Bucket, []option.ClientOption{option.WithCredentialsJSON([]byte(cred))})
+	case "as":
+		fallthrough
+	case "wasb":
+		id, secret := creds["microsoft-id"], creds["microsoft-secret"]
+		if id == "" || secret == "" {
+			return nil, errors.Errorf("microsoft-id or microsoft-secret not found in credentials")
+		}
+		c, err = NewMicrosoftClient(url.Bucket, id, secret)
+	case "local":
+		return nil, errors.Errorf("local object stores don't take credentials")
+	}
+	switch {
+	case err != nil:
+		return nil, err
+	case c != nil:
+		return TracingObjClient(url.Store, c), nil
+	default:
+		return nil, errors.Errorf("unrecognized object store: %s", url.Bucket)
+	}
+}
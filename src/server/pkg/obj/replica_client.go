@@ -0,0 +1,67 @@
+package obj
+
+import (
+	"context"
+	"io"
+)
+
+var _ Client = &replicaClient{}
+
+type replicaClient struct {
+	primary, replica Client
+	onMiss           func()
+}
+
+// NewReplicaClient returns primary wrapped so that objects are read from
+// replica, a bucket that primary is replicated to, falling back to primary
+// for objects that haven't been replicated yet (in which case onMiss, if
+// set, is called). Everything else, including writes, goes to primary.
+func NewReplicaClient(primary, replica Client, onMiss func()) Client {
+	return &replicaClient{
+		primary: primary,
+		replica: replica,
+		onMiss:  onMiss,
+	}
+}
+
+func (c *replicaClient) Reader(ctx context.Context, p string, offset, size uint64) (io.ReadCloser, error) {
+	r, err := c.replica.Reader(ctx, p, offset, size)
+	if err == nil {
+		return r, nil
+	}
+	if !c.replica.IsNotExist(err) {
+		return nil, err
+	}
+	if c.onMiss != nil {
+		c.onMiss()
+	}
+	return c.primary.Reader(ctx, p, offset, size)
+}
+
+func (c *replicaClient) Writer(ctx context.Context, p string) (io.WriteCloser, error) {
+	return c.primary.Writer(ctx, p)
+}
+
+func (c *replicaClient) Delete(ctx context.Context, p string) error {
+	return c.primary.Delete(ctx, p)
+}
+
+func (c *replicaClient) Exists(ctx context.Context, p string) bool {
+	return c.primary.Exists(ctx, p)
+}
+
+func (c *replicaClient) Walk(ctx context.Context, p string, cb func(p string) error) error {
+	return c.primary.Walk(ctx, p, cb)
+}
+
+func (c *replicaClient) IsIgnorable(err error) bool {
+	return c.primary.IsIgnorable(err) || c.replica.IsIgnorable(err)
+}
+
+func (c *replicaClient) IsNotExist(err error) bool {
+	return c.primary.IsNotExist(err) || c.replica.IsNotExist(err)
+}
+
+func (c *replicaClient) IsRetryable(err error) bool {
+	return c.primary.IsRetryable(err) || c.replica.IsRetryable(err)
+}
@@ -16,9 +16,15 @@ const (
 	putFileRecordsPrefix = "/putFileRecords"
 	commitsPrefix        = "/commits"
 	branchesPrefix       = "/branches"
+	commitTagsPrefix     = "/commitTags"
 	openCommitsPrefix    = "/openCommits"
 	mergesPrefix         = "/merges"
 	shardsPrefix         = "/shards"
+	trashPrefix          = "/trash"
+	finishCommitsPrefix  = "/finishCommits"
+	stagedRecordsPrefix  = "/stagedPutFileRecords"
+	attestationsPrefix   = "/attestations"
+	attestationKeyPrefix = "/attestationKey"
 )
 
 var (
@@ -50,6 +56,20 @@ func PutFileRecords(etcdClient *etcd.Client, etcdPrefix string) col.Collection {
 	)
 }
 
+// StagedPutFileRecords returns a collection of the putFileRecords of
+// directories that are being replaced in open commits, which aren't applied
+// until the commits are finished
+func StagedPutFileRecords(etcdClient *etcd.Client, etcdPrefix string) col.Collection {
+	return col.NewCollection(
+		etcdClient,
+		path.Join(etcdPrefix, stagedRecordsPrefix),
+		nil,
+		&pfs.PutFileRecords{},
+		nil,
+		nil,
+	)
+}
+
 // Commits returns a collection of commits
 func Commits(etcdClient *etcd.Client, etcdPrefix string, repo string) col.Collection {
 	return col.NewCollection(
@@ -79,6 +99,23 @@ func Branches(etcdClient *etcd.Client, etcdPrefix string, repo string) col.Colle
 	)
 }
 
+// CommitTags returns a collection of commit tags
+func CommitTags(etcdClient *etcd.Client, etcdPrefix string, repo string) col.Collection {
+	return col.NewCollection(
+		etcdClient,
+		path.Join(etcdPrefix, commitTagsPrefix, repo),
+		nil,
+		&pfs.CommitTagInfo{},
+		func(key string) error {
+			if uuid.IsUUIDWithoutDashes(key) {
+				return errors.Errorf("tag name cannot be a UUID V4")
+			}
+			return nil
+		},
+		nil,
+	)
+}
+
 // OpenCommits returns a collection of open commits
 func OpenCommits(etcdClient *etcd.Client, etcdPrefix string) col.Collection {
 	return col.NewCollection(
@@ -90,3 +127,54 @@ func OpenCommits(etcdClient *etcd.Client, etcdPrefix string) col.Collection {
 		nil,
 	)
 }
+
+// Trash returns a collection of deleted repos and commits
+func Trash(etcdClient *etcd.Client, etcdPrefix string) col.Collection {
+	return col.NewCollection(
+		etcdClient,
+		path.Join(etcdPrefix, trashPrefix),
+		nil,
+		&pfs.DeletedInfo{},
+		nil,
+		nil,
+	)
+}
+
+// FinishCommits returns a collection of the progress of FinishCommit on
+// commits that are being finished
+func FinishCommits(etcdClient *etcd.Client, etcdPrefix string) col.Collection {
+	return col.NewCollection(
+		etcdClient,
+		path.Join(etcdPrefix, finishCommitsPrefix),
+		nil,
+		&pfs.FinishCommitProgress{},
+		nil,
+		nil,
+	)
+}
+
+// Attestations returns a collection of the attestations of a repo's commits
+func Attestations(etcdClient *etcd.Client, etcdPrefix string, repo string) col.Collection {
+	return col.NewCollection(
+		etcdClient,
+		path.Join(etcdPrefix, attestationsPrefix, repo),
+		nil,
+		&pfs.Attestation{},
+		nil,
+		nil,
+	)
+}
+
+// AttestationKey returns a collection that holds the keys that pachd signs
+// with: the key that attestations are signed with, and the key that file URLs
+// are signed with
+func AttestationKey(etcdClient *etcd.Client, etcdPrefix string) col.Collection {
+	return col.NewCollection(
+		etcdClient,
+		path.Join(etcdPrefix, attestationKeyPrefix),
+		nil,
+		&pfs.AttestationKey{},
+		nil,
+		nil,
+	)
+}
@@ -11,8 +11,9 @@ import (
 )
 
 const (
-	pipelinesPrefix = "/pipelines"
-	jobsPrefix      = "/jobs"
+	pipelinesPrefix         = "/pipelines"
+	jobsPrefix              = "/jobs"
+	gitHookDeliveriesPrefix = "/githook_deliveries"
 )
 
 var (
@@ -50,3 +51,16 @@ func Jobs(etcdClient *etcd.Client, etcdPrefix string) col.Collection {
 		nil,
 	)
 }
+
+// GitHookDeliveries returns a Collection of the githook server's recent
+// webhook deliveries
+func GitHookDeliveries(etcdClient *etcd.Client, etcdPrefix string) col.Collection {
+	return col.NewCollection(
+		etcdClient,
+		path.Join(etcdPrefix, gitHookDeliveriesPrefix),
+		nil,
+		&pps.GitHookDelivery{},
+		nil,
+		nil,
+	)
+}
@@ -0,0 +1,32 @@
+package ppsutil
+
+import (
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// AlignedInputs returns the PFS inputs in 'input' that 'alignment' requires
+// to be aligned, and the names in alignment.Inputs that don't match any PFS
+// input.
+func AlignedInputs(alignment *pps.Alignment, input *pps.Input) ([]*pps.PFSInput, []string) {
+	names := make(map[string]bool)
+	for _, name := range alignment.Inputs {
+		names[name] = false
+	}
+	var result []*pps.PFSInput
+	pps.VisitInput(input, func(input *pps.Input) {
+		if input.Pfs == nil {
+			return
+		}
+		if _, ok := names[input.Pfs.Name]; ok || len(alignment.Inputs) == 0 {
+			names[input.Pfs.Name] = true
+			result = append(result, input.Pfs)
+		}
+	})
+	var missing []string
+	for _, name := range alignment.Inputs {
+		if !names[name] {
+			missing = append(missing, name)
+		}
+	}
+	return result, missing
+}
@@ -0,0 +1,33 @@
+package ppsutil
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestAlignedInputs(t *testing.T) {
+	input := client.NewCrossInput(
+		client.NewPFSInput("sales", "/*"),
+		client.NewPFSInput("prices", "/*"),
+		client.NewPFSInput("regions", "/"),
+	)
+	// The names of PFS inputs default to their repos
+	pps.VisitInput(input, func(input *pps.Input) {
+		if input.Pfs != nil {
+			input.Pfs.Name = input.Pfs.Repo
+		}
+	})
+
+	inputs, missing := AlignedInputs(&pps.Alignment{MetadataKey: "date"}, input)
+	require.Equal(t, 3, len(inputs))
+	require.Equal(t, 0, len(missing))
+
+	inputs, missing = AlignedInputs(&pps.Alignment{MetadataKey: "date", Inputs: []string{"sales", "prices", "costs"}}, input)
+	require.Equal(t, 2, len(inputs))
+	require.Equal(t, "sales", inputs[0].Name)
+	require.Equal(t, "prices", inputs[1].Name)
+	require.Equal(t, []string{"costs"}, missing)
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"unicode"
 
+	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
 	ppsclient "github.com/pachyderm/pachyderm/src/client/pps"
 	"github.com/pachyderm/pachyderm/src/server/pkg/serde"
@@ -112,3 +113,34 @@ func (r *PipelineManifestReader) NextCreatePipelineRequest() (*ppsclient.CreateP
 		return &result, nil
 	}
 }
+
+// NextDAGEntry gets the next entry from a DAG manifest (see 'pachctl apply
+// dag'). Entries with a top-level "repo" field are repo definitions, and
+// all other entries are pipeline specs. Exactly one of the returned requests
+// is non-nil.
+func (r *PipelineManifestReader) NextDAGEntry() (*ppsclient.CreatePipelineRequest, *pfs.CreateRepoRequest, error) {
+	holder := map[string]interface{}{}
+	if err := r.decoder.Decode(&holder); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, err
+		}
+		return nil, nil, errors.Wrapf(err, "malformed DAG manifest")
+	}
+	entry, err := serde.EncodeJSON(holder)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := holder["repo"]; ok {
+		var result pfs.CreateRepoRequest
+		if err := serde.NewJSONDecoder(bytes.NewReader(entry)).DecodeProto(&result); err != nil {
+			return nil, nil, errors.Wrapf(err, "malformed repo definition")
+		}
+		return nil, &result, nil
+	}
+	entryReader := &PipelineManifestReader{decoder: serde.NewJSONDecoder(bytes.NewReader(entry))}
+	result, err := entryReader.NextCreatePipelineRequest()
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, nil, nil
+}
@@ -0,0 +1,69 @@
+package ppsutil
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// JobGateInput describes an input commit of the output commit whose job gates
+// are being checked. It's what an input's name refers to in a gate's URL or
+// path template.
+type JobGateInput struct {
+	Repo     string
+	Branch   string
+	Commit   string
+	Metadata map[string]string
+}
+
+// JobGateData is the data that job gates' URL and path templates are
+// executed with.
+type JobGateData struct {
+	Inputs map[string]*JobGateInput
+}
+
+// JobGateTemplate parses the URL (for HTTP gates) or path (for marker file
+// gates) of 'gate' as a template.
+func JobGateTemplate(gate *pps.JobGate) (*template.Template, error) {
+	var text string
+	switch {
+	case gate.HTTP != nil:
+		text = gate.HTTP.URL
+	case gate.MarkerFile != nil:
+		text = gate.MarkerFile.Path
+	default:
+		return nil, errors.Errorf("job gate %q must set one of http or marker_file", gate.Name)
+	}
+	t, err := template.New(gate.Name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse job gate %q", gate.Name)
+	}
+	return t, nil
+}
+
+// ExpandJobGate returns the URL or path of 'gate', with its template executed
+// with 'data'.
+func ExpandJobGate(gate *pps.JobGate, data *JobGateData) (string, error) {
+	t, err := JobGateTemplate(gate)
+	if err != nil {
+		return "", err
+	}
+	var result strings.Builder
+	if err := t.Execute(&result, data); err != nil {
+		return "", errors.Wrapf(err, "could not expand job gate %q", gate.Name)
+	}
+	return result.String(), nil
+}
+
+// JobGatesUseInputs returns true if any of the templates of 'gates' refer to
+// inputs, in which case the input commits' metadata is needed to expand them.
+func JobGatesUseInputs(gates *pps.JobGates) bool {
+	for _, gate := range gates.GetGates() {
+		if strings.Contains(gate.GetHTTP().GetURL(), "{{") || strings.Contains(gate.GetMarkerFile().GetPath(), "{{") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package ppsutil
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestExpandJobGate(t *testing.T) {
+	data := &JobGateData{Inputs: map[string]*JobGateInput{
+		"sales": {Repo: "sales", Branch: "master", Commit: "abc", Metadata: map[string]string{"date": "2020-05-01"}},
+	}}
+	gate := &pps.JobGate{Name: "partner", MarkerFile: &pps.MarkerFileGate{
+		Repo: "uploads",
+		Path: `/done/{{(index .Inputs "sales").Metadata.date}}`,
+	}}
+	path, err := ExpandJobGate(gate, data)
+	require.NoError(t, err)
+	require.Equal(t, "/done/2020-05-01", path)
+	require.True(t, JobGatesUseInputs(&pps.JobGates{Gates: []*pps.JobGate{gate}}))
+
+	gate = &pps.JobGate{Name: "api", HTTP: &pps.HTTPGate{URL: `http://api/ready?commit={{(index .Inputs "sales").Commit}}`}}
+	url, err := ExpandJobGate(gate, data)
+	require.NoError(t, err)
+	require.Equal(t, "http://api/ready?commit=abc", url)
+
+	// Missing metadata is an error, rather than an empty string
+	gate.HTTP.URL = `http://api/{{(index .Inputs "sales").Metadata.region}}`
+	_, err = ExpandJobGate(gate, data)
+	require.YesError(t, err)
+
+	gate.HTTP.URL = "http://api/ready"
+	require.False(t, JobGatesUseInputs(&pps.JobGates{Gates: []*pps.JobGate{gate}}))
+	_, err = ExpandJobGate(&pps.JobGate{Name: "empty"}, data)
+	require.YesError(t, err)
+}
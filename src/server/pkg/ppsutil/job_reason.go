@@ -0,0 +1,83 @@
+package ppsutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// MaxJobReasonDatums is the maximum number of failed datums that a JobReason
+// samples
+const MaxJobReasonDatums = 5
+
+// maxJobReasonEvent is the maximum length of a JobReason's event excerpt
+const maxJobReasonEvent = 512
+
+// jobRemediations maps each category of job failure to the code of its
+// remediation, which are documented in the pipeline troubleshooting docs
+var jobRemediations = map[pps.FailureCause]string{
+	pps.FailureCause_FAILURE_USER_CODE:  "FIX_USER_CODE",
+	pps.FailureCause_FAILURE_OOM_KILLED: "RAISE_MEMORY",
+	pps.FailureCause_FAILURE_IMAGE_PULL: "FIX_IMAGE",
+	pps.FailureCause_FAILURE_EGRESS:     "FIX_EGRESS",
+	pps.FailureCause_FAILURE_TIMEOUT:    "RAISE_TIMEOUT",
+	pps.FailureCause_FAILURE_EVICTED:    "CHECK_NODES",
+	pps.FailureCause_FAILURE_INPUT:      "FIX_INPUT",
+	pps.FailureCause_FAILURE_OTHER:      "CHECK_CLUSTER",
+}
+
+// NewJobReason returns the structured reason for a job failure in 'category',
+// sampling up to MaxJobReasonDatums of 'datums'.
+func NewJobReason(category pps.FailureCause, message string, datums ...string) *pps.JobReason {
+	if len(datums) > MaxJobReasonDatums {
+		datums = datums[:MaxJobReasonDatums]
+	}
+	return &pps.JobReason{
+		Category:    category,
+		Message:     message,
+		DatumSample: datums,
+		Remediation: jobRemediations[category],
+	}
+}
+
+// IsKubeJobFailure returns true if a job failure in 'category' may have been
+// caused by kubernetes, in which case its reason should include the worker
+// pods' warning events.
+func IsKubeJobFailure(category pps.FailureCause) bool {
+	switch category {
+	case pps.FailureCause_FAILURE_OOM_KILLED, pps.FailureCause_FAILURE_IMAGE_PULL,
+		pps.FailureCause_FAILURE_EVICTED, pps.FailureCause_FAILURE_OTHER:
+		return true
+	}
+	return false
+}
+
+// JobReasonEvent returns an excerpt of a kubernetes event for a JobReason.
+func JobReasonEvent(reason, message string) string {
+	event := strings.TrimSpace(fmt.Sprintf("%s: %s", reason, message))
+	if len(event) > maxJobReasonEvent {
+		event = event[:maxJobReasonEvent-3] + "..."
+	}
+	return event
+}
+
+// RenderJobReason renders 'reason' as the free-text reason that's displayed
+// for a job. It starts with reason.Message, so the rendered reasons of older
+// jobs and newer jobs can be matched alike.
+func RenderJobReason(reason *pps.JobReason) string {
+	if reason == nil {
+		return ""
+	}
+	parts := []string{reason.Message}
+	if len(reason.DatumSample) > 0 {
+		parts = append(parts, fmt.Sprintf("failed datums include %s", strings.Join(reason.DatumSample, ", ")))
+	}
+	if reason.Event != "" {
+		parts = append(parts, fmt.Sprintf("kubernetes event %q", reason.Event))
+	}
+	if reason.Remediation != "" {
+		parts = append(parts, fmt.Sprintf("remediation %s", reason.Remediation))
+	}
+	return strings.Join(parts, "; ")
+}
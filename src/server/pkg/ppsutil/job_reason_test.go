@@ -0,0 +1,42 @@
+package ppsutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestNewJobReason(t *testing.T) {
+	reason := NewJobReason(pps.FailureCause_FAILURE_OOM_KILLED, "datum failed", "a", "b", "c", "d", "e", "f")
+	require.Equal(t, pps.FailureCause_FAILURE_OOM_KILLED, reason.Category)
+	require.Equal(t, "RAISE_MEMORY", reason.Remediation)
+	require.Equal(t, []string{"a", "b", "c", "d", "e"}, reason.DatumSample)
+
+	// Every failure category has a remediation
+	for cause := range pps.FailureCause_name {
+		if pps.FailureCause(cause) != pps.FailureCause_FAILURE_NONE {
+			require.NotEqual(t, "", NewJobReason(pps.FailureCause(cause), "").Remediation)
+		}
+	}
+	require.Equal(t, "", NewJobReason(pps.FailureCause_FAILURE_NONE, "output commit closed").Remediation)
+}
+
+func TestRenderJobReason(t *testing.T) {
+	require.Equal(t, "", RenderJobReason(nil))
+	require.Equal(t, "output commit closed", RenderJobReason(NewJobReason(pps.FailureCause_FAILURE_NONE, "output commit closed")))
+
+	reason := NewJobReason(pps.FailureCause_FAILURE_OOM_KILLED, "datum failed", "a", "b")
+	reason.Event = JobReasonEvent("OOMKilling", "Memory cgroup out of memory")
+	require.Equal(t,
+		`datum failed; failed datums include a, b; kubernetes event "OOMKilling: Memory cgroup out of memory"; remediation RAISE_MEMORY`,
+		RenderJobReason(reason))
+}
+
+func TestJobReasonEvent(t *testing.T) {
+	require.Equal(t, "Evicted: The node was low on resource", JobReasonEvent("Evicted", "The node was low on resource"))
+	event := JobReasonEvent("Failed", strings.Repeat("x", 2*maxJobReasonEvent))
+	require.Equal(t, maxJobReasonEvent, len(event))
+	require.True(t, strings.HasSuffix(event, "..."))
+}
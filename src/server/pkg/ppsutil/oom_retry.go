@@ -0,0 +1,75 @@
+package ppsutil
+
+import (
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// defaultMemoryMultiplier is how much a pipeline's workers' memory is
+// multiplied by when it's escalated, if its OOMRetry doesn't say.
+const defaultMemoryMultiplier = 2
+
+// EscalatedMemory returns the memory limit that the workers of 'pipelineInfo'
+// should be restarted with after one of their datums was OOM-killed, given
+// their current escalated memory limit 'current' (empty if their memory
+// hasn't been escalated). It returns "" if the pipeline doesn't retry
+// OOM-killed datums, or if its workers already have its OOMRetry's
+// max_memory.
+func EscalatedMemory(pipelineInfo *pps.PipelineInfo, current string) (string, error) {
+	spec := pipelineInfo.OomRetry
+	if spec == nil {
+		return "", nil
+	}
+	maxMemory, err := resource.ParseQuantity(spec.MaxMemory)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not parse max_memory")
+	}
+	base := current
+	if base == "" && pipelineInfo.ResourceLimits != nil {
+		base = pipelineInfo.ResourceLimits.Memory
+	}
+	if base == "" && pipelineInfo.ResourceRequests != nil {
+		base = pipelineInfo.ResourceRequests.Memory
+	}
+	if base == "" {
+		// The workers have no memory limit of their own, so they were killed by
+		// the node running out of memory: give them as much as they may have
+		return maxMemory.String(), nil
+	}
+	baseMemory, err := resource.ParseQuantity(base)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not parse memory %q", base)
+	}
+	if baseMemory.Cmp(maxMemory) >= 0 {
+		return "", nil
+	}
+	multiplier := spec.MemoryMultiplier
+	if multiplier == 0 {
+		multiplier = defaultMemoryMultiplier
+	}
+	escalated := int64(float64(baseMemory.Value()) * multiplier)
+	if escalated >= maxMemory.Value() {
+		return maxMemory.String(), nil
+	}
+	return resource.NewQuantity(escalated, resource.BinarySI).String(), nil
+}
+
+// SuggestedMemoryRequest returns a memory request that fits a pipeline's
+// peak memory usage, 'peakBytes', with some headroom, if the pipeline's
+// resource requests 'requests' don't request at least 'peakBytes' of memory.
+func SuggestedMemoryRequest(requests *pps.ResourceSpec, peakBytes uint64) string {
+	if peakBytes == 0 {
+		return ""
+	}
+	if requests != nil && requests.Memory != "" {
+		if requested, err := resource.ParseQuantity(requests.Memory); err == nil && requested.Value() >= int64(peakBytes) {
+			return ""
+		}
+	}
+	// 25% headroom, rounded up to a whole MiB
+	const mib = 1 << 20
+	suggested := (int64(peakBytes) + int64(peakBytes)/4 + mib - 1) / mib * mib
+	return resource.NewQuantity(suggested, resource.BinarySI).String()
+}
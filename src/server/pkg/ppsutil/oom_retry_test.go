@@ -0,0 +1,53 @@
+package ppsutil
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestEscalatedMemory(t *testing.T) {
+	pipelineInfo := &pps.PipelineInfo{
+		ResourceLimits: &pps.ResourceSpec{Memory: "1Gi"},
+	}
+	// OOM-killed datums aren't retried
+	memory, err := EscalatedMemory(pipelineInfo, "")
+	require.NoError(t, err)
+	require.Equal(t, "", memory)
+
+	pipelineInfo.OomRetry = &pps.OOMRetry{MaxMemory: "3Gi"}
+	memory, err = EscalatedMemory(pipelineInfo, "")
+	require.NoError(t, err)
+	require.Equal(t, "2Gi", memory)
+	// capped at max_memory
+	memory, err = EscalatedMemory(pipelineInfo, memory)
+	require.NoError(t, err)
+	require.Equal(t, "3Gi", memory)
+	// can't be escalated any further
+	memory, err = EscalatedMemory(pipelineInfo, memory)
+	require.NoError(t, err)
+	require.Equal(t, "", memory)
+
+	pipelineInfo.OomRetry.MemoryMultiplier = 1.5
+	memory, err = EscalatedMemory(pipelineInfo, "")
+	require.NoError(t, err)
+	require.Equal(t, "1536Mi", memory)
+
+	// no memory limit or request
+	pipelineInfo.ResourceLimits = nil
+	memory, err = EscalatedMemory(pipelineInfo, "")
+	require.NoError(t, err)
+	require.Equal(t, "3Gi", memory)
+
+	pipelineInfo.OomRetry.MaxMemory = "lots"
+	_, err = EscalatedMemory(pipelineInfo, "")
+	require.YesError(t, err)
+}
+
+func TestSuggestedMemoryRequest(t *testing.T) {
+	require.Equal(t, "", SuggestedMemoryRequest(nil, 0))
+	require.Equal(t, "1280Mi", SuggestedMemoryRequest(nil, 1<<30))
+	require.Equal(t, "1280Mi", SuggestedMemoryRequest(&pps.ResourceSpec{Memory: "512Mi"}, 1<<30))
+	require.Equal(t, "", SuggestedMemoryRequest(&pps.ResourceSpec{Memory: "2Gi"}, 1<<30))
+}
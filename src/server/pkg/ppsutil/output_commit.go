@@ -0,0 +1,106 @@
+package ppsutil
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// OutputCommitInfo describes the job that produced an output commit. It's
+// what a pipeline's output_commit_description template is executed with, and
+// it's stored in the output commit's metadata.
+type OutputCommitInfo struct {
+	Pipeline      string
+	JobID         string
+	State         string
+	InputCommits  []string
+	DataProcessed int64
+	DataSkipped   int64
+	DataFailed    int64
+	DataRecovered int64
+	DataTotal     int64
+	Duration      time.Duration
+}
+
+// NewOutputCommitInfo returns the OutputCommitInfo of the output commit of
+// 'jobInfo', which finished at 'finished'.
+func NewOutputCommitInfo(jobInfo *pps.JobInfo, finished time.Time) *OutputCommitInfo {
+	info := &OutputCommitInfo{
+		JobID:         jobInfo.Job.ID,
+		State:         jobInfo.State.String(),
+		DataProcessed: jobInfo.DataProcessed,
+		DataSkipped:   jobInfo.DataSkipped,
+		DataFailed:    jobInfo.DataFailed,
+		DataRecovered: jobInfo.DataRecovered,
+		DataTotal:     jobInfo.DataTotal,
+	}
+	if jobInfo.Pipeline != nil {
+		info.Pipeline = jobInfo.Pipeline.Name
+	}
+	if jobInfo.Started != nil {
+		if started, err := types.TimestampFromProto(jobInfo.Started); err == nil {
+			info.Duration = finished.Sub(started).Round(time.Second)
+		}
+	}
+	pps.VisitInput(jobInfo.Input, func(input *pps.Input) {
+		if input.Pfs != nil && input.Pfs.Commit != "" {
+			info.InputCommits = append(info.InputCommits, fmt.Sprintf("%s@%s", input.Pfs.Repo, input.Pfs.Commit))
+		}
+	})
+	return info
+}
+
+// Metadata returns 'info' as commit metadata
+func (info *OutputCommitInfo) Metadata() map[string]string {
+	return map[string]string{
+		"pipeline":       info.Pipeline,
+		"job_id":         info.JobID,
+		"state":          info.State,
+		"input_commits":  strings.Join(info.InputCommits, ","),
+		"data_processed": fmt.Sprint(info.DataProcessed),
+		"data_skipped":   fmt.Sprint(info.DataSkipped),
+		"data_failed":    fmt.Sprint(info.DataFailed),
+		"data_recovered": fmt.Sprint(info.DataRecovered),
+		"data_total":     fmt.Sprint(info.DataTotal),
+		"duration":       info.Duration.String(),
+	}
+}
+
+// ValidateOutputCommitDescription returns an error if 'tmpl' isn't a valid
+// output_commit_description template. Since text/template only reports
+// references to unknown fields when a template is executed, 'tmpl' is
+// executed with an empty OutputCommitInfo.
+func ValidateOutputCommitDescription(tmpl string) error {
+	t, err := template.New("output_commit_description").Parse(tmpl)
+	if err != nil {
+		return errors.Wrapf(err, "invalid output_commit_description")
+	}
+	if err := t.Execute(ioutil.Discard, &OutputCommitInfo{}); err != nil {
+		return errors.Wrapf(err, "invalid output_commit_description")
+	}
+	return nil
+}
+
+// OutputCommitDescription returns the description of an output commit, given
+// its pipeline's output_commit_description template.
+func OutputCommitDescription(tmpl string, info *OutputCommitInfo) (string, error) {
+	if tmpl == "" {
+		return "", nil
+	}
+	t, err := template.New("output_commit_description").Parse(tmpl)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid output_commit_description")
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, info); err != nil {
+		return "", errors.Wrapf(err, "could not generate output commit description")
+	}
+	return buf.String(), nil
+}
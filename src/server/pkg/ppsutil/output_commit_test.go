@@ -0,0 +1,40 @@
+package ppsutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestOutputCommitDescription(t *testing.T) {
+	require.NoError(t, ValidateOutputCommitDescription("job {{.JobID}} processed {{.DataProcessed}} datums"))
+	require.YesError(t, ValidateOutputCommitDescription("{{.JobID"))
+	require.YesError(t, ValidateOutputCommitDescription("{{.NoSuchField}}"))
+
+	started := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	startedProto, err := types.TimestampProto(started)
+	require.NoError(t, err)
+	info := NewOutputCommitInfo(&pps.JobInfo{
+		Job:           client.NewJob("abc"),
+		Pipeline:      client.NewPipeline("edges"),
+		State:         pps.JobState_JOB_SUCCESS,
+		Started:       startedProto,
+		DataProcessed: 3,
+		DataTotal:     4,
+		Input:         &pps.Input{Pfs: &pps.PFSInput{Repo: "images", Commit: "123"}},
+	}, started.Add(90*time.Second))
+	require.Equal(t, []string{"images@123"}, info.InputCommits)
+	description, err := OutputCommitDescription("{{.Pipeline}} job {{.JobID}}: {{.DataProcessed}}/{{.DataTotal}} in {{.Duration}}", info)
+	require.NoError(t, err)
+	require.Equal(t, "edges job abc: 3/4 in 1m30s", description)
+	require.Equal(t, "abc", info.Metadata()["job_id"])
+	require.Equal(t, "3", info.Metadata()["data_processed"])
+
+	description, err = OutputCommitDescription("", info)
+	require.NoError(t, err)
+	require.Equal(t, "", description)
+}
@@ -0,0 +1,31 @@
+package ppsutil
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// JobSeedSalt returns the salt from which the seeds of the datums in the job
+// 'jobInfo' are derived: the salt that overrode the pipeline's salt when the
+// job was created with RunPipeline, if any, or else the pipeline's salt.
+func JobSeedSalt(jobInfo *pps.JobInfo) string {
+	if jobInfo.SeedSalt != "" {
+		return jobInfo.SeedSalt
+	}
+	return jobInfo.Salt
+}
+
+// DatumSeed returns the seed that's given to user code processing the datum
+// 'datumID' in $PACH_DATUM_SEED. It's a non-negative int64, so that it can
+// seed most random number generators, and it only depends on the datum's ID
+// and 'seedSalt', so a datum gets the same seed whenever it's reprocessed by a
+// job with the same seed salt.
+func DatumSeed(seedSalt string, datumID string) int64 {
+	h := sha256.New()
+	h.Write([]byte(seedSalt))
+	h.Write([]byte(":"))
+	h.Write([]byte(datumID))
+	return int64(binary.BigEndian.Uint64(h.Sum(nil)[:8]) >> 1)
+}
@@ -0,0 +1,21 @@
+package ppsutil
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestDatumSeed(t *testing.T) {
+	seed := DatumSeed("salt", "datum")
+	require.True(t, seed >= 0)
+	require.Equal(t, seed, DatumSeed("salt", "datum"))
+	require.NotEqual(t, seed, DatumSeed("salt", "other"))
+	require.NotEqual(t, seed, DatumSeed("pepper", "datum"))
+
+	jobInfo := &pps.JobInfo{Salt: "salt"}
+	require.Equal(t, "salt", JobSeedSalt(jobInfo))
+	jobInfo.SeedSalt = "pepper"
+	require.Equal(t, "pepper", JobSeedSalt(jobInfo))
+}
@@ -0,0 +1,53 @@
+package ppsutil
+
+import (
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// AddJobSkippingStats adds the datum counts of 'jobPtr', which just finished,
+// to the skipping stats of its pipeline, 'pipelinePtr'.
+func AddJobSkippingStats(pipelinePtr *pps.EtcdPipelineInfo, jobPtr *pps.EtcdJobInfo) {
+	if pipelinePtr.SkippingStats == nil {
+		pipelinePtr.SkippingStats = &pps.SkippingStats{}
+	}
+	stats := pipelinePtr.SkippingStats
+	if stats.SinceCreation == nil {
+		stats.SinceCreation = &pps.DatumCounts{}
+	}
+	if stats.SinceUpdate == nil {
+		stats.SinceUpdate = &pps.DatumCounts{}
+	}
+	for _, counts := range []*pps.DatumCounts{stats.SinceCreation, stats.SinceUpdate} {
+		counts.Jobs++
+		counts.Total += jobPtr.DataTotal
+		counts.Processed += jobPtr.DataProcessed
+		counts.Skipped += jobPtr.DataSkipped
+		counts.Failed += jobPtr.DataFailed
+		counts.Recovered += jobPtr.DataRecovered
+		if jobPtr.Stats != nil {
+			counts.Reused += int64(jobPtr.Stats.DatumsReused)
+			counts.SkippedBytes += jobPtr.Stats.SkippedBytes
+		}
+	}
+}
+
+// ResetSkippingStats resets the counts of the jobs that finished since
+// 'pipelinePtr' was last updated, when it's updated again.
+func ResetSkippingStats(pipelinePtr *pps.EtcdPipelineInfo) {
+	if pipelinePtr.SkippingStats != nil {
+		pipelinePtr.SkippingStats.SinceUpdate = &pps.DatumCounts{}
+	}
+}
+
+// SkippingDisabled returns true if a pipeline skipped datums before it was
+// last updated, but hasn't skipped any since then (not counting the first job
+// after the update, which may reprocess everything). This usually means that
+// the update changed its input (e.g. its glob pattern) such that each job's
+// datums differ from its parent job's.
+func SkippingDisabled(stats *pps.SkippingStats) bool {
+	if stats == nil || stats.SinceCreation == nil || stats.SinceUpdate == nil {
+		return false
+	}
+	skippedBefore := stats.SinceCreation.Skipped - stats.SinceUpdate.Skipped
+	return skippedBefore > 0 && stats.SinceUpdate.Jobs > 1 && stats.SinceUpdate.Skipped == 0
+}
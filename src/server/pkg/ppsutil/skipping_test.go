@@ -0,0 +1,39 @@
+package ppsutil
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestSkippingStats(t *testing.T) {
+	pipelinePtr := &pps.EtcdPipelineInfo{}
+	AddJobSkippingStats(pipelinePtr, &pps.EtcdJobInfo{DataTotal: 10, DataProcessed: 10})
+	AddJobSkippingStats(pipelinePtr, &pps.EtcdJobInfo{
+		DataTotal:     10,
+		DataProcessed: 2,
+		DataSkipped:   8,
+		Stats:         &pps.ProcessStats{DatumsReused: 3, SkippedBytes: 300},
+	})
+	stats := pipelinePtr.SkippingStats
+	require.Equal(t, int64(2), stats.SinceCreation.Jobs)
+	require.Equal(t, int64(12), stats.SinceCreation.Processed)
+	require.Equal(t, int64(8), stats.SinceCreation.Skipped)
+	require.Equal(t, int64(3), stats.SinceCreation.Reused)
+	require.Equal(t, uint64(300), stats.SinceCreation.SkippedBytes)
+	require.Equal(t, stats.SinceCreation, stats.SinceUpdate)
+	require.False(t, SkippingDisabled(stats))
+
+	// After an update, the first job may reprocess everything, but the jobs
+	// after it should skip datums again
+	ResetSkippingStats(pipelinePtr)
+	AddJobSkippingStats(pipelinePtr, &pps.EtcdJobInfo{DataTotal: 10, DataProcessed: 10})
+	require.False(t, SkippingDisabled(stats))
+	AddJobSkippingStats(pipelinePtr, &pps.EtcdJobInfo{DataTotal: 10, DataProcessed: 10})
+	require.True(t, SkippingDisabled(stats))
+	require.Equal(t, int64(4), stats.SinceCreation.Jobs)
+	require.Equal(t, int64(2), stats.SinceUpdate.Jobs)
+	AddJobSkippingStats(pipelinePtr, &pps.EtcdJobInfo{DataTotal: 10, DataProcessed: 1, DataSkipped: 9})
+	require.False(t, SkippingDisabled(stats))
+}
@@ -0,0 +1,29 @@
+package ppsutil
+
+import (
+	"math"
+
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// SmokeTestDatums returns the number of datums in the smoke test sample of a
+// job with 'total' datums to process, or 0 if the job has no smoke test.
+func SmokeTestDatums(smokeTest *pps.SmokeTest, total int64) int64 {
+	if smokeTest == nil || total <= 0 {
+		return 0
+	}
+	n := smokeTest.Datums
+	if smokeTest.Fraction > 0 {
+		n = int64(math.Ceil(smokeTest.Fraction * float64(total)))
+	}
+	if n > total {
+		n = total
+	}
+	return n
+}
+
+// SmokeTestPaused returns true if a job whose smoke test is in the state
+// 'status' is paused after its smoke test, waiting to be approved.
+func SmokeTestPaused(smokeTest *pps.SmokeTest, status *pps.SmokeTestStatus) bool {
+	return smokeTest.GetPause() && status.GetPassed() != nil && status.GetApproved() == nil
+}
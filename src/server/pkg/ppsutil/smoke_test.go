@@ -0,0 +1,30 @@
+package ppsutil
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestSmokeTestDatums(t *testing.T) {
+	require.Equal(t, int64(0), SmokeTestDatums(nil, 100))
+	require.Equal(t, int64(5), SmokeTestDatums(&pps.SmokeTest{Datums: 5}, 100))
+	require.Equal(t, int64(3), SmokeTestDatums(&pps.SmokeTest{Datums: 5}, 3))
+	require.Equal(t, int64(0), SmokeTestDatums(&pps.SmokeTest{Datums: 5}, 0))
+	// Fractions are rounded up
+	require.Equal(t, int64(1), SmokeTestDatums(&pps.SmokeTest{Fraction: 0.01}, 10))
+	require.Equal(t, int64(10), SmokeTestDatums(&pps.SmokeTest{Fraction: 0.01}, 1000))
+}
+
+func TestSmokeTestPaused(t *testing.T) {
+	now := types.TimestampNow()
+	pause := &pps.SmokeTest{Datums: 1, Pause: true}
+	require.False(t, SmokeTestPaused(pause, nil))
+	require.False(t, SmokeTestPaused(pause, &pps.SmokeTestStatus{Datums: 1}))
+	require.True(t, SmokeTestPaused(pause, &pps.SmokeTestStatus{Datums: 1, Passed: now}))
+	require.False(t, SmokeTestPaused(pause, &pps.SmokeTestStatus{Datums: 1, Passed: now, Approved: now}))
+	require.False(t, SmokeTestPaused(&pps.SmokeTest{Datums: 1}, &pps.SmokeTestStatus{Datums: 1, Passed: now}))
+}
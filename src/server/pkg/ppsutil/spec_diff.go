@@ -0,0 +1,153 @@
+package ppsutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// specJSON returns 'spec' as generic JSON, with the field names that users
+// write in pipeline specs
+func specJSON(spec *pps.CreatePipelineRequest) (map[string]interface{}, error) {
+	var buf bytes.Buffer
+	if err := (&jsonpb.Marshaler{OrigName: true}).Marshal(&buf, spec); err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	result := make(map[string]interface{})
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	return result, nil
+}
+
+// DiffPipelineSpecs returns the fields that differ between two versions of a
+// pipeline's spec, sorted by path. Arrays of objects (e.g. the inputs of a
+// cross) are compared element by element, while other arrays (e.g. a
+// transform's cmd) are compared as a whole.
+func DiffPipelineSpecs(a, b *pps.CreatePipelineRequest) ([]*pps.PipelineFieldDiff, error) {
+	aJSON, err := specJSON(a)
+	if err != nil {
+		return nil, err
+	}
+	bJSON, err := specJSON(b)
+	if err != nil {
+		return nil, err
+	}
+	var result []*pps.PipelineFieldDiff
+	diffJSON("", aJSON, bJSON, &result)
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	for _, d := range result {
+		d.Section = d.Path
+		if i := strings.IndexAny(d.Path, ".["); i >= 0 {
+			d.Section = d.Path[:i]
+		}
+	}
+	return result, nil
+}
+
+func diffJSON(path string, a, b interface{}, result *[]*pps.PipelineFieldDiff) {
+	aMap, aIsMap := a.(map[string]interface{})
+	bMap, bIsMap := b.(map[string]interface{})
+	if aIsMap && bIsMap {
+		keys := make(map[string]bool)
+		for k := range aMap {
+			keys[k] = true
+		}
+		for k := range bMap {
+			keys[k] = true
+		}
+		for k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			diffJSON(p, aMap[k], bMap[k], result)
+		}
+		return
+	}
+	aList, aIsList := a.([]interface{})
+	bList, bIsList := b.([]interface{})
+	if aIsList && bIsList && hasObjects(aList) && hasObjects(bList) {
+		n := len(aList)
+		if len(bList) > n {
+			n = len(bList)
+		}
+		for i := 0; i < n; i++ {
+			var x, y interface{}
+			if i < len(aList) {
+				x = aList[i]
+			}
+			if i < len(bList) {
+				y = bList[i]
+			}
+			diffJSON(fmt.Sprintf("%s[%d]", path, i), x, y, result)
+		}
+		return
+	}
+	aValue, bValue := jsonValue(a), jsonValue(b)
+	if aValue != bValue {
+		*result = append(*result, &pps.PipelineFieldDiff{
+			Path:     path,
+			OldValue: aValue,
+			NewValue: bValue,
+		})
+	}
+}
+
+func hasObjects(list []interface{}) bool {
+	for _, x := range list {
+		if _, ok := x.(map[string]interface{}); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonValue returns 'v' as JSON, or "" if 'v' isn't set
+func jsonValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// PipelineDiffSummary returns a human-readable summary of the diffs between
+// versions 'a' and 'b' of 'pipeline', grouped by section.
+func PipelineDiffSummary(pipeline string, a, b uint64, diffs []*pps.PipelineFieldDiff) string {
+	var buf bytes.Buffer
+	if len(diffs) == 0 {
+		fmt.Fprintf(&buf, "pipeline %s is the same in versions %d and %d\n", pipeline, a, b)
+		return buf.String()
+	}
+	changes := "changes"
+	if len(diffs) == 1 {
+		changes = "change"
+	}
+	fmt.Fprintf(&buf, "pipeline %s, version %d -> %d: %d %s\n", pipeline, a, b, len(diffs), changes)
+	var section string
+	for _, d := range diffs {
+		if d.Section != section {
+			section = d.Section
+			fmt.Fprintf(&buf, "%s:\n", section)
+		}
+		switch {
+		case d.OldValue == "":
+			fmt.Fprintf(&buf, "  + %s: %s\n", d.Path, d.NewValue)
+		case d.NewValue == "":
+			fmt.Fprintf(&buf, "  - %s: %s\n", d.Path, d.OldValue)
+		default:
+			fmt.Fprintf(&buf, "  ~ %s: %s -> %s\n", d.Path, d.OldValue, d.NewValue)
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,58 @@
+package ppsutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestDiffPipelineSpecs(t *testing.T) {
+	a := &pps.CreatePipelineRequest{
+		Pipeline:  client.NewPipeline("edges"),
+		Transform: &pps.Transform{Image: "edges:1", Cmd: []string{"python3", "edges.py"}},
+		Input: client.NewCrossInput(
+			client.NewPFSInput("images", "/*"),
+			client.NewPFSInput("models", "/"),
+		),
+		ResourceRequests: &pps.ResourceSpec{Memory: "1G"},
+	}
+	b := &pps.CreatePipelineRequest{
+		Pipeline:  client.NewPipeline("edges"),
+		Transform: &pps.Transform{Image: "edges:2", Cmd: []string{"python3", "edges.py", "--fast"}},
+		Input: client.NewCrossInput(
+			client.NewPFSInput("images", "/*/*"),
+			client.NewPFSInput("models", "/"),
+		),
+		ResourceRequests: &pps.ResourceSpec{Memory: "1G", Cpu: 2},
+	}
+	diffs, err := DiffPipelineSpecs(a, b)
+	require.NoError(t, err)
+	var paths []string
+	for _, d := range diffs {
+		paths = append(paths, d.Path)
+	}
+	require.Equal(t, []string{
+		"input.cross[0].pfs.glob",
+		"resource_requests.cpu",
+		"transform.cmd",
+		"transform.image",
+	}, paths)
+	require.Equal(t, "input", diffs[0].Section)
+	require.Equal(t, `"/*"`, diffs[0].OldValue)
+	require.Equal(t, `"/*/*"`, diffs[0].NewValue)
+	require.Equal(t, "", diffs[1].OldValue)
+	require.Equal(t, `["python3","edges.py","--fast"]`, diffs[2].NewValue)
+
+	summary := PipelineDiffSummary("edges", 1, 2, diffs)
+	require.True(t, strings.HasPrefix(summary, "pipeline edges, version 1 -> 2: 4 changes\n"))
+	require.True(t, strings.Contains(summary, "  + resource_requests.cpu: 2\n"))
+	require.True(t, strings.Contains(summary, `  ~ transform.image: "edges:1" -> "edges:2"`))
+
+	diffs, err = DiffPipelineSpecs(a, a)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(diffs))
+	require.Equal(t, "pipeline edges is the same in versions 1 and 1\n", PipelineDiffSummary("edges", 1, 1, diffs))
+}
@@ -52,6 +52,46 @@ func PipelineRcName(name string, version uint64) string {
 	return fmt.Sprintf("pipeline-%s-v%d", strings.ToLower(name), version)
 }
 
+// JobOutputBranch returns the branch that the jobs of a pipeline commit their
+// output to. This is the pipeline's output branch, unless the pipeline is
+// gated, in which case it's the output branch's pending branch, or routes its
+// output, in which case it's the output branch's routed branch.
+func JobOutputBranch(pipelineInfo *pps.PipelineInfo) string {
+	if pipelineInfo.Gated {
+		return PendingBranch(pipelineInfo.OutputBranch)
+	}
+	if len(pipelineInfo.OutputRoutes) > 0 {
+		return RoutedBranch(pipelineInfo.OutputBranch)
+	}
+	return pipelineInfo.OutputBranch
+}
+
+// RoutedBranch returns the branch that a pipeline whose output branch is
+// 'outputBranch', and which routes its output, commits its jobs' output to.
+func RoutedBranch(outputBranch string) string {
+	return outputBranch + "-routed"
+}
+
+// OutputRouteBranches returns the branches that a pipeline routes its output
+// to (see pps.OutputRoute), starting with its output branch, or nil if it
+// doesn't route its output.
+func OutputRouteBranches(pipelineInfo *pps.PipelineInfo) []string {
+	if len(pipelineInfo.OutputRoutes) == 0 {
+		return nil
+	}
+	result := []string{pipelineInfo.OutputBranch}
+	for _, route := range pipelineInfo.OutputRoutes {
+		result = append(result, route.Branch)
+	}
+	return result
+}
+
+// PendingBranch returns the branch that a gated pipeline whose output branch
+// is 'outputBranch' commits its unapproved output to.
+func PendingBranch(outputBranch string) string {
+	return outputBranch + "-pending"
+}
+
 // GetRequestsResourceListFromPipeline returns a list of resources that the pipeline,
 // minimally requires.
 func GetRequestsResourceListFromPipeline(pipelineInfo *pps.PipelineInfo) (*v1.ResourceList, error) {
@@ -102,6 +142,12 @@ func getResourceListFromSpec(resources *pps.ResourceSpec) (*v1.ResourceList, err
 	return &result, nil
 }
 
+// GetRequestsResourceList returns a list of resources from a ResourceSpec
+// (e.g. a worker class's) that workers minimally require.
+func GetRequestsResourceList(requests *pps.ResourceSpec) (*v1.ResourceList, error) {
+	return getResourceListFromSpec(requests)
+}
+
 // GetLimitsResourceList returns a list of resources from a pipeline
 // ResourceSpec that it is maximally limited to.
 func GetLimitsResourceList(limits *pps.ResourceSpec) (*v1.ResourceList, error) {
@@ -144,6 +190,15 @@ func GetPipelineInfoAllowIncomplete(pachClient *client.APIClient, name string, p
 	result.JobCounts = ptr.JobCounts
 	result.LastJobState = ptr.LastJobState
 	result.SpecCommit = ptr.SpecCommit
+	result.Tenant = ptr.Tenant
+	result.AvailableImageDigest = ptr.AvailableImageDigest
+	result.IdleSince = ptr.IdleSince
+	result.RuntimeConfig = ptr.RuntimeConfig
+	result.SkippingStats = ptr.SkippingStats
+	result.EscalatedMemory = ptr.EscalatedMemory
+	result.PeakMemoryBytes = ptr.PeakMemoryBytes
+	result.GateStatus = ptr.GateStatus
+	result.SuggestedMemoryRequest = SuggestedMemoryRequest(result.ResourceRequests, ptr.PeakMemoryBytes)
 	return result, nil
 }
 
@@ -244,7 +299,8 @@ func JobInput(pipelineInfo *pps.PipelineInfo, outputCommitInfo *pfs.CommitInfo)
 	}
 	jobInput := proto.Clone(pipelineInfo.Input).(*pps.Input)
 	pps.VisitInput(jobInput, func(input *pps.Input) {
-		if input.Pfs != nil {
+		// An input that's pinned to a commit tag always reads the tagged commit
+		if input.Pfs != nil && input.Pfs.Commit == "" {
 			if commit, ok := branchToCommit[key(input.Pfs.Repo, input.Pfs.Branch)]; ok {
 				input.Pfs.Commit = commit.ID
 			}
@@ -259,40 +315,95 @@ func JobInput(pipelineInfo *pps.PipelineInfo, outputCommitInfo *pfs.CommitInfo)
 				input.Git.Commit = commit.ID
 			}
 		}
+		if input.HTTPMirror != nil {
+			if commit, ok := branchToCommit[key(input.HTTPMirror.Repo, "master")]; ok {
+				input.HTTPMirror.Commit = commit.ID
+			}
+		}
+		if input.Parameter != nil {
+			if commit, ok := branchToCommit[key(input.Parameter.Repo, "master")]; ok {
+				input.Parameter.Commit = commit.ID
+			}
+		}
+		if input.RemoteRepo != nil {
+			if commit, ok := branchToCommit[key(input.RemoteRepo.Repo, "master")]; ok {
+				input.RemoteRepo.Commit = commit.ID
+			}
+		}
+		if input.DBImport != nil {
+			if commit, ok := branchToCommit[key(input.DBImport.Repo, "master")]; ok {
+				input.DBImport.Commit = commit.ID
+			}
+		}
 	})
 	return jobInput
 }
 
+// SetVolumeFingerprints fills in the fingerprints of the volume inputs in a
+// job's input, from the fingerprints (by input name) that were recorded when
+// the job was created.
+func SetVolumeFingerprints(input *pps.Input, fingerprints map[string]string) {
+	pps.VisitInput(input, func(input *pps.Input) {
+		if input.Volume != nil {
+			input.Volume.Fingerprint = fingerprints[input.Volume.Name]
+		}
+	})
+}
+
 // PipelineReqFromInfo converts a PipelineInfo into a CreatePipelineRequest.
 func PipelineReqFromInfo(pipelineInfo *pps.PipelineInfo) *pps.CreatePipelineRequest {
 	return &pps.CreatePipelineRequest{
-		Pipeline:              pipelineInfo.Pipeline,
-		Transform:             pipelineInfo.Transform,
-		ParallelismSpec:       pipelineInfo.ParallelismSpec,
-		HashtreeSpec:          pipelineInfo.HashtreeSpec,
-		Egress:                pipelineInfo.Egress,
-		OutputBranch:          pipelineInfo.OutputBranch,
-		ResourceRequests:      pipelineInfo.ResourceRequests,
-		ResourceLimits:        pipelineInfo.ResourceLimits,
-		SidecarResourceLimits: pipelineInfo.SidecarResourceLimits,
-		Input:                 pipelineInfo.Input,
-		Description:           pipelineInfo.Description,
-		CacheSize:             pipelineInfo.CacheSize,
-		EnableStats:           pipelineInfo.EnableStats,
-		MaxQueueSize:          pipelineInfo.MaxQueueSize,
-		Service:               pipelineInfo.Service,
-		ChunkSpec:             pipelineInfo.ChunkSpec,
-		DatumTimeout:          pipelineInfo.DatumTimeout,
-		JobTimeout:            pipelineInfo.JobTimeout,
-		Salt:                  pipelineInfo.Salt,
-		PodSpec:               pipelineInfo.PodSpec,
-		PodPatch:              pipelineInfo.PodPatch,
-		Spout:                 pipelineInfo.Spout,
-		SchedulingSpec:        pipelineInfo.SchedulingSpec,
-		DatumTries:            pipelineInfo.DatumTries,
-		Standby:               pipelineInfo.Standby,
-		S3Out:                 pipelineInfo.S3Out,
-		Metadata:              pipelineInfo.Metadata,
+		Pipeline:                pipelineInfo.Pipeline,
+		Transform:               pipelineInfo.Transform,
+		ParallelismSpec:         pipelineInfo.ParallelismSpec,
+		HashtreeSpec:            pipelineInfo.HashtreeSpec,
+		Egress:                  pipelineInfo.Egress,
+		OutputBranch:            pipelineInfo.OutputBranch,
+		ResourceRequests:        pipelineInfo.ResourceRequests,
+		ResourceLimits:          pipelineInfo.ResourceLimits,
+		SidecarResourceLimits:   pipelineInfo.SidecarResourceLimits,
+		Input:                   pipelineInfo.Input,
+		Description:             pipelineInfo.Description,
+		CacheSize:               pipelineInfo.CacheSize,
+		EnableStats:             pipelineInfo.EnableStats,
+		MaxQueueSize:            pipelineInfo.MaxQueueSize,
+		Service:                 pipelineInfo.Service,
+		ChunkSpec:               pipelineInfo.ChunkSpec,
+		DatumTimeout:            pipelineInfo.DatumTimeout,
+		JobTimeout:              pipelineInfo.JobTimeout,
+		Salt:                    pipelineInfo.Salt,
+		PodSpec:                 pipelineInfo.PodSpec,
+		PodPatch:                pipelineInfo.PodPatch,
+		Spout:                   pipelineInfo.Spout,
+		SchedulingSpec:          pipelineInfo.SchedulingSpec,
+		DatumTries:              pipelineInfo.DatumTries,
+		Standby:                 pipelineInfo.Standby,
+		S3Out:                   pipelineInfo.S3Out,
+		Metadata:                pipelineInfo.Metadata,
+		LogQuota:                pipelineInfo.LogQuota,
+		ImagePinning:            pipelineInfo.ImagePinning,
+		Outputs:                 pipelineInfo.Outputs,
+		NetworkPolicy:           pipelineInfo.NetworkPolicy,
+		IdlePolicy:              pipelineInfo.IdlePolicy,
+		HashtreeMemoryLimit:     pipelineInfo.HashtreeMemoryLimit,
+		OutputMerges:            pipelineInfo.OutputMerges,
+		Gated:                   pipelineInfo.Gated,
+		RuntimeConfig:           pipelineInfo.RuntimeConfig,
+		OutputCommitDescription: pipelineInfo.OutputCommitDescription,
+		Alignment:               pipelineInfo.Alignment,
+		Attest:                  pipelineInfo.Attest,
+		Validation:              pipelineInfo.Validation,
+		OomRetry:                pipelineInfo.OomRetry,
+		FileCache:               pipelineInfo.FileCache,
+		StatsRetention:          pipelineInfo.StatsRetention,
+		Sandbox:                 pipelineInfo.Sandbox,
+		JobGates:                pipelineInfo.JobGates,
+		OutputRoutes:            pipelineInfo.OutputRoutes,
+		SmokeTest:               pipelineInfo.SmokeTest,
+		RetrySpec:               pipelineInfo.RetrySpec,
+		OutputRetention:         pipelineInfo.OutputRetention,
+		DatumSizing:             pipelineInfo.DatumSizing,
+		PhaseConcurrency:        pipelineInfo.PhaseConcurrency,
 	}
 }
 
@@ -329,6 +440,12 @@ func UpdateJobState(pipelines col.ReadWriteCollection, jobs col.ReadWriteCollect
 	}
 	pipelinePtr.JobCounts[int32(state)]++
 	pipelinePtr.LastJobState = state
+	if IsTerminal(state) && !IsTerminal(jobPtr.State) {
+		AddJobSkippingStats(pipelinePtr, jobPtr)
+		if peak := jobPtr.Stats.GetPeakMemoryBytes(); peak > pipelinePtr.PeakMemoryBytes {
+			pipelinePtr.PeakMemoryBytes = peak
+		}
+	}
 	if err := pipelines.Put(jobPtr.Pipeline.Name, pipelinePtr); err != nil {
 		return err
 	}
@@ -0,0 +1,407 @@
+// Package ratelimit limits the rate at which each principal may call pachd's
+// API methods, so that one misbehaving client (e.g. a script calling ListJob
+// in a hot loop) can't degrade the whole cluster. Limits are configured with
+// rules (see ParseRules), and calls that exceed them fail with
+// codes.ResourceExhausted and a RetryAfterKey header that says how long the
+// caller should wait before retrying.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/pachyderm/pachyderm/src/client/admin"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+)
+
+const (
+	// RetryAfterKey is the key of the gRPC header, set on calls that were
+	// rejected by a rate limit, whose value is the number of seconds after
+	// which the call may be retried.
+	RetryAfterKey = "retry-after"
+
+	// principalTTL is how long the principal that owns an auth token is cached
+	principalTTL = time.Minute
+	// maxEntries is the number of buckets (or cached principals) above which
+	// idle ones are discarded
+	maxEntries = 10000
+)
+
+// versionSuffixRE matches the version suffix of our proto packages (e.g. the
+// "_1_11" in "pps_1_11")
+var versionSuffixRE = regexp.MustCompile(`(_[0-9]+)+$`)
+
+// Rule limits the rate at which principals may call the methods it matches.
+type Rule struct {
+	// Principal is the principal that the rule applies to, or "" if it applies
+	// to every principal (each of which has its own limit)
+	Principal string
+	// Method is "*", "<service>.*" or "<service>.<method>" (e.g. "pps.ListJob")
+	Method string
+	// Each principal may make Requests calls per Period to the methods that
+	// the rule matches, in bursts of at most Requests calls.
+	Requests int64
+	Period   time.Duration
+}
+
+func (r *Rule) String() string {
+	var principal string
+	if r.Principal != "" {
+		principal = r.Principal + "@"
+	}
+	return fmt.Sprintf("%s%s=%d/%v", principal, r.Method, r.Requests, r.Period)
+}
+
+// ParseRules parses a comma-separated list of rules (the format of pachd's
+// RATE_LIMITS), each of the form "[<principal>@]<method>=<requests>/<period>",
+// e.g. "*=100/1s,pps.ListJob=5/1s,robot:ci@pps.ListJob=50/1s". A call is
+// limited by the most specific rule that matches it: rules for its principal
+// take precedence over rules for every principal, and then rules for its
+// method take precedence over rules for its service, which take precedence
+// over "*".
+func ParseRules(s string) ([]*Rule, error) {
+	var rules []*Rule
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		eq := strings.LastIndex(part, "=")
+		if eq < 0 {
+			return nil, errors.Errorf("invalid rate limit %q: expected \"[<principal>@]<method>=<requests>/<period>\"", part)
+		}
+		rule := &Rule{Method: part[:eq]}
+		if at := strings.LastIndex(rule.Method, "@"); at >= 0 {
+			rule.Principal, rule.Method = rule.Method[:at], rule.Method[at+1:]
+			if rule.Principal == "" {
+				return nil, errors.Errorf("invalid rate limit %q: empty principal", part)
+			}
+		}
+		if rule.Method != "*" && len(strings.Split(rule.Method, ".")) != 2 {
+			return nil, errors.Errorf("invalid rate limit %q: the method must be \"*\", \"<service>.*\" or \"<service>.<method>\"", part)
+		}
+		limit := strings.SplitN(part[eq+1:], "/", 2)
+		requests, err := strconv.ParseInt(limit[0], 10, 64)
+		if err != nil || requests <= 0 {
+			return nil, errors.Errorf("invalid rate limit %q: the number of requests must be a positive integer", part)
+		}
+		rule.Requests = requests
+		rule.Period = time.Second
+		if len(limit) == 2 {
+			period := limit[1]
+			if period != "" && !strings.ContainsAny(period[:1], "0123456789") {
+				period = "1" + period // e.g. "5/s"
+			}
+			if rule.Period, err = time.ParseDuration(period); err != nil || rule.Period <= 0 {
+				return nil, errors.Errorf("invalid rate limit %q: invalid period %q", part, limit[1])
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// specificity orders the rules that match a call; the most specific one
+// limits it
+func (r *Rule) specificity() int {
+	var result int
+	if r.Principal != "" {
+		result += 4
+	}
+	switch {
+	case strings.HasSuffix(r.Method, ".*"):
+		result++
+	case r.Method != "*":
+		result += 2
+	}
+	return result
+}
+
+func (r *Rule) matches(principal, method string) bool {
+	if r.Principal != "" && r.Principal != principal {
+		return false
+	}
+	switch {
+	case r.Method == "*":
+		return true
+	case strings.HasSuffix(r.Method, ".*"):
+		return strings.HasPrefix(method, strings.TrimSuffix(r.Method, "*"))
+	}
+	return r.Method == method
+}
+
+// methodName returns the name of a gRPC method that rules match, e.g.
+// "pps.ListJob" for "/pps_1_11.API/ListJob"
+func methodName(fullMethod string) string {
+	parts := strings.Split(strings.TrimPrefix(fullMethod, "/"), "/")
+	if len(parts) != 2 {
+		return fullMethod
+	}
+	service := parts[0]
+	if i := strings.LastIndex(service, "."); i >= 0 {
+		service = service[:i]
+	}
+	return versionSuffixRE.ReplaceAllString(service, "") + "." + parts[1]
+}
+
+// bucket is a token bucket, which holds the calls that a principal can make
+// right now under one rule
+type bucket struct {
+	rule     *Rule
+	tokens   float64
+	updated  time.Time
+	allowed  int64
+	rejected int64
+}
+
+func (b *bucket) refill(now time.Time) {
+	rate := float64(b.rule.Requests) / b.rule.Period.Seconds()
+	b.tokens = math.Min(float64(b.rule.Requests), b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+}
+
+type bucketKey struct {
+	principal string
+	rule      *Rule
+}
+
+type cachedPrincipal struct {
+	principal string
+	expires   time.Time
+}
+
+// PrincipalFunc returns the principal that made a call, given its context. It
+// should return an error if the call isn't authenticated, in which case it's
+// limited as a call from its peer's IP address (e.g. "ip:10.0.0.1").
+type PrincipalFunc func(ctx context.Context) (string, error)
+
+// Limiter limits the rate at which principals may call gRPC methods. It's a
+// grpcutil.Interceptor.
+type Limiter struct {
+	rules     []*Rule
+	principal PrincipalFunc
+	now       func() time.Time
+
+	mu         sync.Mutex
+	buckets    map[bucketKey]*bucket
+	principals map[string]*cachedPrincipal // by auth token
+}
+
+// NewLimiter returns a Limiter that enforces 'rules'. 'principal' identifies
+// the principals that make calls; the principal that owns each auth token is
+// cached for a minute.
+func NewLimiter(rules []*Rule, principal PrincipalFunc) *Limiter {
+	return &Limiter{
+		rules:      rules,
+		principal:  principal,
+		now:        time.Now,
+		buckets:    make(map[bucketKey]*bucket),
+		principals: make(map[string]*cachedPrincipal),
+	}
+}
+
+// Rules returns the rules that 'l' enforces.
+func (l *Limiter) Rules() []*Rule {
+	return l.rules
+}
+
+// rule returns the rule that limits 'principal's calls to 'method', or nil if
+// they aren't limited
+func (l *Limiter) rule(principal, method string) *Rule {
+	var result *Rule
+	for _, r := range l.rules {
+		if r.matches(principal, method) && (result == nil || r.specificity() >= result.specificity()) {
+			result = r
+		}
+	}
+	return result
+}
+
+// Allow records a call by 'principal' to 'method' (e.g. "pps.ListJob"), and
+// returns true if it's allowed. If it isn't, Allow also returns how long the
+// principal must wait before the call would be allowed.
+func (l *Limiter) Allow(principal, method string) (bool, time.Duration) {
+	rule := l.rule(principal, method)
+	if rule == nil {
+		return true, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	key := bucketKey{principal: principal, rule: rule}
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxEntries {
+			l.discardIdleBuckets(now)
+		}
+		b = &bucket{rule: rule, tokens: float64(rule.Requests), updated: now}
+		l.buckets[key] = b
+	}
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		b.allowed++
+		return true, 0
+	}
+	b.rejected++
+	rate := float64(rule.Requests) / rule.Period.Seconds()
+	return false, time.Duration((1 - b.tokens) / rate * float64(time.Second))
+}
+
+// discardIdleBuckets discards the buckets that are full, which behave the same
+// as new buckets. It must be called with l.mu held.
+func (l *Limiter) discardIdleBuckets(now time.Time) {
+	for key, b := range l.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.rule.Requests) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Usage returns the usage of the rate limits of the principals that have made
+// calls recently, sorted by principal and method. If 'principal' is set, only
+// its usage is returned.
+func (l *Limiter) Usage(principal string) []*admin.RateLimitUsage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	var result []*admin.RateLimitUsage
+	for key, b := range l.buckets {
+		if principal != "" && key.principal != principal {
+			continue
+		}
+		b.refill(now)
+		result = append(result, &admin.RateLimitUsage{
+			Principal: key.principal,
+			Method:    b.rule.Method,
+			Requests:  b.rule.Requests,
+			Period:    types.DurationProto(b.rule.Period),
+			Available: b.tokens,
+			Allowed:   b.allowed,
+			Rejected:  b.rejected,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Principal != result[j].Principal {
+			return result[i].Principal < result[j].Principal
+		}
+		return result[i].Method < result[j].Method
+	})
+	return result
+}
+
+// callPrincipal returns the principal that made the call with context 'ctx'
+func (l *Limiter) callPrincipal(ctx context.Context) string {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md[auth.ContextTokenKey]) == 1 {
+		token = md[auth.ContextTokenKey][0]
+	}
+	if token != "" {
+		l.mu.Lock()
+		cached, ok := l.principals[token]
+		l.mu.Unlock()
+		if ok && l.now().Before(cached.expires) {
+			return cached.principal
+		}
+		if principal, err := l.principal(ctx); err == nil && principal != "" {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			now := l.now()
+			if len(l.principals) >= maxEntries {
+				for t, c := range l.principals {
+					if !now.Before(c.expires) {
+						delete(l.principals, t)
+					}
+				}
+			}
+			l.principals[token] = &cachedPrincipal{principal: principal, expires: now.Add(principalTTL)}
+			return principal
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		return "ip:" + host
+	}
+	return "unknown"
+}
+
+// check returns a ResourceExhausted error if the call to 'fullMethod' with
+// context 'ctx' exceeds its rate limit, along with the header to send
+func (l *Limiter) check(ctx context.Context, fullMethod string) (metadata.MD, error) {
+	if strings.HasPrefix(fullMethod, "/grpc.health.") {
+		// never limit health checks
+		return nil, nil
+	}
+	method := methodName(fullMethod)
+	if l.rule("", method) == nil && !l.hasPrincipalRules() {
+		// avoid looking up the principal of calls that can't be limited
+		return nil, nil
+	}
+	principal := l.callPrincipal(ctx)
+	ok, wait := l.Allow(principal, method)
+	if ok {
+		return nil, nil
+	}
+	retryAfter := int64(math.Ceil(wait.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	rule := l.rule(principal, method)
+	return metadata.Pairs(RetryAfterKey, strconv.FormatInt(retryAfter, 10)), status.Errorf(codes.ResourceExhausted,
+		"rate limit exceeded: %s may make %d calls to %s per %v; retry after %ds",
+		principal, rule.Requests, rule.Method, rule.Period, retryAfter)
+}
+
+func (l *Limiter) hasPrincipalRules() bool {
+	for _, r := range l.rules {
+		if r.Principal != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// UnaryServerInterceptor implements grpcutil.Interceptor
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		header, err := l.check(ctx, info.FullMethod)
+		if err != nil {
+			grpc.SetHeader(ctx, header)
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor implements grpcutil.Interceptor. Streams are
+// limited when they're opened, not per message.
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		header, err := l.check(ss.Context(), info.FullMethod)
+		if err != nil {
+			ss.SetHeader(header)
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("*=100/1s, pps.ListJob=5/m,robot:ci@pps.*=50")
+	require.NoError(t, err)
+	require.Equal(t, 3, len(rules))
+	require.Equal(t, "*=100/1s", rules[0].String())
+	require.Equal(t, "pps.ListJob=5/1m0s", rules[1].String())
+	require.Equal(t, "robot:ci@pps.*=50/1s", rules[2].String())
+
+	rules, err = ParseRules("")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(rules))
+
+	for _, s := range []string{"pps.ListJob", "ListJob=5/1s", "pps.ListJob=0/1s", "pps.ListJob=5/soon", "@pps.ListJob=5"} {
+		_, err := ParseRules(s)
+		require.YesError(t, err, s)
+	}
+}
+
+func TestMethodName(t *testing.T) {
+	require.Equal(t, "pps.ListJob", methodName("/pps_1_11.API/ListJob"))
+	require.Equal(t, "auth.WhoAmI", methodName("/auth.API/WhoAmI"))
+}
+
+func TestAllow(t *testing.T) {
+	rules, err := ParseRules("*=10/1s,pps.ListJob=2/1s,robot:ci@pps.*=4/1s")
+	require.NoError(t, err)
+	l := NewLimiter(rules, func(context.Context) (string, error) { return "", nil })
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	// ListJob is limited separately from other methods
+	for i := 0; i < 2; i++ {
+		ok, _ := l.Allow("alice", "pps.ListJob")
+		require.True(t, ok)
+	}
+	ok, wait := l.Allow("alice", "pps.ListJob")
+	require.False(t, ok)
+	require.Equal(t, 500*time.Millisecond, wait)
+	ok, _ = l.Allow("alice", "pfs.ListRepo")
+	require.True(t, ok)
+
+	// Each principal has its own limits, and rules for a principal take
+	// precedence
+	ok, _ = l.Allow("bob", "pps.ListJob")
+	require.True(t, ok)
+	for i := 0; i < 4; i++ {
+		ok, _ := l.Allow("robot:ci", "pps.ListJob")
+		require.True(t, ok)
+	}
+	ok, _ = l.Allow("robot:ci", "pps.InspectJob")
+	require.False(t, ok)
+
+	// Calls are allowed again once the bucket refills
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = l.Allow("alice", "pps.ListJob")
+	require.True(t, ok)
+
+	usage := l.Usage("alice")
+	require.Equal(t, 2, len(usage))
+	require.Equal(t, "*", usage[0].Method)
+	require.Equal(t, int64(1), usage[0].Allowed)
+	require.Equal(t, "pps.ListJob", usage[1].Method)
+	require.Equal(t, int64(3), usage[1].Allowed)
+	require.Equal(t, int64(1), usage[1].Rejected)
+	require.Equal(t, 4, len(l.Usage("")))
+}
@@ -68,6 +68,66 @@ type PachdSpecificConfiguration struct {
 	DeploymentID               string `env:"CLUSTER_DEPLOYMENT_ID,default="`
 	RequireCriticalServersOnly bool   `env:"REQUIRE_CRITICAL_SERVERS_ONLY",default=false"`
 	MetricsEndpoint            string `env:"METRICS_ENDPOINT",default="`
+	// A comma-separated list of CIDRs that http_mirror inputs may fetch from,
+	// even though they're loopback, link-local or private addresses
+	HTTPMirrorAllowedNetworks string `env:"HTTP_MIRROR_ALLOWED_NETWORKS,default="`
+	// The maximum number of repos and pipelines that each tenant may create
+	// (0 means no limit)
+	TenantMaxRepos     int `env:"TENANT_MAX_REPOS,default=0"`
+	TenantMaxPipelines int `env:"TENANT_MAX_PIPELINES,default=0"`
+	// How long deleted repos and commits are kept in the trash, as a Go
+	// duration (e.g. "24h"). 0 disables the trash.
+	PFSTrashRetention string `env:"PFS_TRASH_RETENTION,default=0"`
+	// The number of goroutines that add a commit's files to its hashtree in
+	// FinishCommit
+	PFSFinishCommitConcurrency int `env:"PFS_FINISH_COMMIT_CONCURRENCY,default=10"`
+	// If true, the PPS master runs a DaemonSet that pulls the images of active
+	// pipelines onto every node
+	ImagePrewarming bool `env:"IMAGE_PREWARMING,default=false"`
+	// How long a pipeline may be idle before the PPS master acts on it, as a
+	// Go duration (e.g. "720h"), and what it does: "flag" or "stop". Pipelines
+	// can override both in their IdlePolicy. 0 disables the default policy.
+	PPSIdleTimeout string `env:"PPS_IDLE_TIMEOUT,default=0"`
+	PPSIdleAction  string `env:"PPS_IDLE_ACTION,default=flag"`
+	// How long the detailed stats of pipelines' jobs are kept, as a Go
+	// duration (e.g. "720h"), and how many of each pipeline's most recent
+	// jobs keep them. Pipelines can override both in their StatsRetention. 0
+	// disables either limit.
+	PPSStatsMaxAge   string `env:"PPS_STATS_MAX_AGE,default=0"`
+	PPSStatsKeepLast int64  `env:"PPS_STATS_KEEP_LAST,default=0"`
+	// If set, pachd publishes commit, job and pipeline events to this Kafka
+	// topic (kafka://<brokers>/<topic>) or NATS subject
+	// (nats://<host>/<subject>)
+	EventBusURL string `env:"EVENT_BUS_URL,default="`
+	// The object storage bucket of each data residency, and the URLs that
+	// pipelines with inputs in each residency may egress to, as
+	// comma-separated lists of <residency>=<url> pairs (e.g.
+	// "eu=s3://pach-eu"). Repos created with a residency store their files'
+	// contents in the residency's bucket.
+	StorageResidencyBuckets string `env:"STORAGE_RESIDENCY_BUCKETS,default="`
+	StorageResidencyEgress  string `env:"STORAGE_RESIDENCY_EGRESS,default="`
+	// The buckets that the default object storage bucket is replicated to, as
+	// a comma-separated list of <region>=<url> pairs (e.g.
+	// "us-west-2=s3://pach-us-west-2"), and the region that this process runs
+	// in. Blocks are read from the replica in this process's region, falling
+	// back to the default bucket if they haven't been replicated yet.
+	StorageReplicaBuckets string `env:"STORAGE_REPLICA_BUCKETS,default="`
+	StorageRegion         string `env:"STORAGE_REGION,default="`
+	// Rate limits on the calls that each principal may make to pachd's API,
+	// as a comma-separated list of [<principal>@]<method>=<requests>/<period>
+	// rules (e.g. "*=100/1s,pps.ListJob=5/1s"). See src/server/pkg/ratelimit.
+	RateLimits string `env:"RATE_LIMITS,default="`
+	// Whether DeleteAll may be called without confirmation ("allow"), needs
+	// a confirmation token ("confirm"), or is refused ("disabled"). See
+	// src/server/pkg/deleteall.
+	DeleteAllPolicy string `env:"DELETE_ALL_POLICY,default=allow"`
+	// The secrets that the githook server verifies webhook deliveries with:
+	// the webhook secrets of GitHub and GitLab, and the UUID of the Bitbucket
+	// webhook. Deliveries from a provider aren't verified if its secret isn't
+	// set.
+	GithookGitHubSecret      string `env:"GITHOOK_GITHUB_SECRET,default="`
+	GithookGitLabSecret      string `env:"GITHOOK_GITLAB_SECRET,default="`
+	GithookBitbucketHookUUID string `env:"GITHOOK_BITBUCKET_HOOK_UUID,default="`
 	// TODO: Merge this with the worker specific pod name (PPS_POD_NAME) into a global configuration pod name.
 	PachdPodName string `env:"PACHD_POD_NAME,required"`
 }
@@ -102,6 +162,9 @@ type WorkerSpecificConfiguration struct {
 	PPSPipelineName string `env:"PPS_PIPELINE_NAME,required"`
 	// The name of this pod
 	PodName string `env:"PPS_POD_NAME,required"`
+	// The worker class that this worker belongs to, if it isn't one of the
+	// pipeline's own workers (see pps.DatumSizing)
+	PPSWorkerClass string `env:"PPS_WORKER_CLASS"`
 }
 
 // FeatureFlags contains the configuration for feature flags.  XXX: if you're
@@ -112,6 +175,9 @@ type FeatureFlags struct {
 	StorageV2                    bool `env:"STORAGE_V2,default=false"`
 	DisableCommitProgressCounter bool `env:"DISABLE_COMMIT_PROGRESS_COUNTER,default=false"`
 	LokiLogging                  bool `env:"LOKI_LOGGING,default=false"`
+	// If true, the faults set with the admin API's SetFaults are injected
+	// (see src/server/pkg/fault). Only test clusters should set it.
+	FaultInjection bool `env:"FAULT_INJECTION,default=false"`
 }
 
 // NewConfiguration creates a generic configuration from a specific type of configuration.
@@ -0,0 +1,186 @@
+package sync
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	pachclient "github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// EgressManifestName is the name of the manifest that PushObjWithManifest
+	// writes to the root of the egress destination.
+	EgressManifestName = ".pachyderm_egress_manifest.json"
+	// partialEgressManifestName is the name of the manifest that lists the
+	// files delivered by an egress that failed, so that retries can skip them.
+	partialEgressManifestName = EgressManifestName + ".partial"
+)
+
+// EgressManifest lists the files of a commit that were delivered by egress.
+// It's written once every file has been delivered, so consumers that only
+// read the files listed in the latest manifest see each commit's files exactly
+// once, even if egress was retried.
+type EgressManifest struct {
+	Repo   string                `json:"repo"`
+	Commit string                `json:"commit"`
+	Files  []*EgressManifestFile `json:"files"`
+}
+
+// EgressManifestFile is a file listed in an EgressManifest.
+type EgressManifestFile struct {
+	Path      string `json:"path"`
+	Hash      string `json:"hash"`
+	SizeBytes uint64 `json:"size_bytes"`
+}
+
+// PushObjWithManifest pushes data from commit to an object store, like
+// PushObj, and then writes an EgressManifest of the commit's files to the
+// root. Files that the previous manifest (or a failed push's partial
+// manifest) lists with the same hash, and that are still present, aren't
+// pushed again.
+func PushObjWithManifest(pachClient *pachclient.APIClient, commit *pfs.Commit, objClient obj.Client, root string) (retErr error) {
+	ctx := pachClient.Ctx()
+	manifestPath := path.Join(root, EgressManifestName)
+	partialPath := path.Join(root, partialEgressManifestName)
+	// The files that are known to have been delivered, by path. The partial
+	// manifest is newer than the manifest, if it exists.
+	delivered := make(map[string]*EgressManifestFile)
+	for _, p := range []string{manifestPath, partialPath} {
+		manifest, err := readEgressManifest(ctx, objClient, p)
+		if err != nil {
+			return err
+		}
+		if manifest != nil {
+			for _, file := range manifest.Files {
+				delivered[file.Path] = file
+			}
+		}
+	}
+
+	var mu sync.Mutex
+	var files []*EgressManifestFile
+	defer func() {
+		if retErr != nil {
+			// Record what was delivered, so that the retry can skip it
+			partial := &EgressManifest{Repo: commit.Repo.Name, Commit: commit.ID}
+			for _, file := range delivered {
+				partial.Files = append(partial.Files, file)
+			}
+			if err := writeEgressManifest(ctx, objClient, partialPath, partial); err != nil {
+				retErr = errors.Errorf("%v (and could not write the partial egress manifest: %v)", retErr, err)
+			}
+		}
+	}()
+	var eg errgroup.Group
+	sem := make(chan struct{}, 200)
+	if err := pachClient.Walk(commit.Repo.Name, commit.ID, "", func(fileInfo *pfs.FileInfo) error {
+		if fileInfo.FileType != pfs.FileType_FILE {
+			return nil
+		}
+		file := &EgressManifestFile{
+			Path:      fileInfo.File.Path,
+			Hash:      hex.EncodeToString(fileInfo.Hash),
+			SizeBytes: fileInfo.SizeBytes,
+		}
+		files = append(files, file)
+		dst := filepath.Join(root, fileInfo.File.Path)
+		mu.Lock()
+		prev, ok := delivered[file.Path]
+		mu.Unlock()
+		if ok && prev.Hash == file.Hash && objClient.Exists(ctx, dst) {
+			return nil
+		}
+		eg.Go(func() (retErr error) {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			w, err := objClient.Writer(ctx, dst)
+			if err != nil {
+				return err
+			}
+			if err := pachClient.GetFile(commit.Repo.Name, commit.ID, fileInfo.File.Path, 0, 0, w); err != nil {
+				w.Close()
+				return err
+			}
+			if err := w.Close(); err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			delivered[file.Path] = file
+			return nil
+		})
+		return nil
+	}); err != nil {
+		eg.Wait()
+		return err
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	// Object stores only make an object visible once it's been completely
+	// written, so consumers never see a partial manifest
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	if err := writeEgressManifest(ctx, objClient, manifestPath, &EgressManifest{
+		Repo:   commit.Repo.Name,
+		Commit: commit.ID,
+		Files:  files,
+	}); err != nil {
+		return err
+	}
+	if err := objClient.Delete(ctx, partialPath); err != nil && !objClient.IsNotExist(err) {
+		return errors.EnsureStack(err)
+	}
+	return nil
+}
+
+// readEgressManifest reads the manifest at 'p', returning nil if it doesn't
+// exist.
+func readEgressManifest(ctx context.Context, objClient obj.Client, p string) (*EgressManifest, error) {
+	if !objClient.Exists(ctx, p) {
+		return nil, nil
+	}
+	r, err := objClient.Reader(ctx, p, 0, 0)
+	if err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	manifest := &EgressManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, errors.Wrapf(err, "could not parse egress manifest %s", p)
+	}
+	return manifest, nil
+}
+
+func writeEgressManifest(ctx context.Context, objClient obj.Client, p string, manifest *EgressManifest) (retErr error) {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.EnsureStack(err)
+	}
+	w, err := objClient.Writer(ctx, p)
+	if err != nil {
+		return errors.EnsureStack(err)
+	}
+	defer func() {
+		if err := w.Close(); err != nil && retErr == nil {
+			retErr = errors.EnsureStack(err)
+		}
+	}()
+	_, err = w.Write(data)
+	return errors.EnsureStack(err)
+}
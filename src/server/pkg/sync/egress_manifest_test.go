@@ -0,0 +1,34 @@
+package sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
+)
+
+func TestEgressManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "egress-manifest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	objClient, err := obj.NewLocalClient(dir)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	manifest, err := readEgressManifest(ctx, objClient, "out/"+EgressManifestName)
+	require.NoError(t, err)
+	require.Nil(t, manifest)
+
+	written := &EgressManifest{
+		Repo:   "pipeline",
+		Commit: "abc",
+		Files:  []*EgressManifestFile{{Path: "/a", Hash: "01", SizeBytes: 3}},
+	}
+	require.NoError(t, writeEgressManifest(ctx, objClient, "out/"+EgressManifestName, written))
+	manifest, err = readEgressManifest(ctx, objClient, "out/"+EgressManifestName)
+	require.NoError(t, err)
+	require.Equal(t, written, manifest)
+}
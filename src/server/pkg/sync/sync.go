@@ -2,6 +2,7 @@
 package sync
 
 import (
+	"encoding/hex"
 	"io"
 	"os"
 	"path"
@@ -15,6 +16,7 @@ import (
 	"github.com/pachyderm/pachyderm/src/client/limit"
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/server/pkg/filecache"
 	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
 	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
 
@@ -35,6 +37,16 @@ type Puller struct {
 	wg sync.WaitGroup
 	// size is the total amount this puller has pulled
 	size int64
+	// cache, if set, is read through when Pull downloads small files
+	cache *filecache.Cache
+	// cachedSize is the amount of 'size' that was read from 'cache'
+	cachedSize int64
+	// skipVerify, if set, stops Pull from checking the files that it pipes
+	// against their hashes (see VerifyPipes)
+	skipVerify bool
+	// correctedReads is the number of objects that were fetched again because
+	// they didn't match their hashes
+	correctedReads int64
 }
 
 // NewPuller creates a new Puller struct.
@@ -45,6 +57,66 @@ func NewPuller() *Puller {
 	}
 }
 
+// NewCachingPuller creates a new Puller that reads the files that Pull
+// downloads (but not those it pipes) through 'cache', if they're small
+// enough. 'cache' may be nil, in which case the Puller doesn't cache files.
+func NewCachingPuller(cache *filecache.Cache) *Puller {
+	p := NewPuller()
+	p.cache = cache
+	return p
+}
+
+// CachedSize returns the number of bytes that this puller has read from its
+// cache, since the last time CleanUp was called.
+func (p *Puller) CachedSize() int64 {
+	return atomic.LoadInt64(&p.cachedSize)
+}
+
+// getFile writes the file described by 'fileInfo' to 'w', reading it through
+// p's cache if it's small enough to be cached.
+func (p *Puller) getFile(client *pachclient.APIClient, repo, commit string, fileInfo *pfs.FileInfo, w io.Writer) error {
+	getFile := func(w io.Writer) error {
+		return client.GetFile(repo, commit, fileInfo.File.Path, 0, 0, w)
+	}
+	if len(fileInfo.Hash) == 0 || !p.cache.Caches(int64(fileInfo.SizeBytes)) {
+		return getFile(w)
+	}
+	verify := objectVerifier(client, repo, commit, fileInfo.File.Path)
+	cached, err := p.cache.Get(hex.EncodeToString(fileInfo.Hash), w, getFile, verify)
+	if err != nil {
+		return err
+	}
+	if cached {
+		atomic.AddInt64(&p.cachedSize, int64(fileInfo.SizeBytes))
+	}
+	return nil
+}
+
+// objectVerifier returns a function that checks that contents read from a
+// file cache's store are those of the file at 'path', by checking them
+// against the hash of the object that the file is stored as. Files that are
+// stored as several objects or as block refs can't be checked, so their
+// contents never pass. The file is only inspected if the function is called.
+func objectVerifier(client *pachclient.APIClient, repo, commit, path string) func([]byte) bool {
+	var once sync.Once
+	var hash string
+	return func(value []byte) bool {
+		once.Do(func() {
+			fileInfo, err := client.InspectFile(repo, commit, path)
+			if err != nil || len(fileInfo.Objects) != 1 || len(fileInfo.BlockRefs) > 0 {
+				return
+			}
+			hash = fileInfo.Objects[0].Hash
+		})
+		if hash == "" {
+			return false
+		}
+		h := pfs.NewHash()
+		h.Write(value)
+		return pfs.EncodeHash(h.Sum(nil)) == hash
+	}
+}
+
 type sizeWriter struct {
 	w    io.Writer
 	size int64
@@ -81,7 +153,8 @@ func (p *Puller) makeFile(path string, f func(io.Writer) error) (retErr error) {
 // root is the local path you want to clone to.
 // repo, commit, file specify the file/dir we are pulling.
 // pipes causes the function to create named pipes in place of files, thus
-// lazily downloading the data as it's needed.
+// lazily downloading the data as it's needed. Piped files are checked against
+// their hashes as they're read, unless VerifyPipes(false) was called.
 // emptyFiles causes the function to create empty files with no content, it's
 // mutually exclusive with pipes.
 // tree is a hashtree to mirror the pulled content into (it may be left nil)
@@ -90,6 +163,9 @@ func (p *Puller) Pull(client *pachclient.APIClient, root string, repo, commit, f
 	pipes bool, emptyFiles bool, concurrency int, statsTree *hashtree.Ordered, statsRoot string) error {
 	limiter := limit.New(concurrency)
 	var eg errgroup.Group
+	p.Lock()
+	verify := !p.skipVerify
+	p.Unlock()
 	if err := client.Walk(repo, commit, file, func(fileInfo *pfs.FileInfo) error {
 		basepath, err := filepath.Rel(file, fileInfo.File.Path)
 		if err != nil {
@@ -118,6 +194,9 @@ func (p *Puller) Pull(client *pachclient.APIClient, root string, repo, commit, f
 		}
 		if pipes {
 			return p.makePipe(path, func(w io.Writer) error {
+				if verify {
+					return p.getFileVerified(client, repo, commit, fileInfo.File.Path, w)
+				}
 				return client.GetFile(repo, commit, fileInfo.File.Path, 0, 0, w)
 			})
 		}
@@ -128,7 +207,7 @@ func (p *Puller) Pull(client *pachclient.APIClient, root string, repo, commit, f
 			limiter.Acquire()
 			defer limiter.Release()
 			return p.makeFile(path, func(w io.Writer) error {
-				return client.GetFile(repo, commit, fileInfo.File.Path, 0, 0, w)
+				return p.getFile(client, repo, commit, fileInfo, w)
 			})
 		})
 		return nil
@@ -293,6 +372,8 @@ func (p *Puller) CleanUp() (int64, error) {
 	}
 	size := p.size
 	p.size = 0
+	p.cachedSize = 0
+	p.correctedReads = 0
 	return size, result
 }
 
@@ -0,0 +1,147 @@
+package sync
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+
+	pachclient "github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+)
+
+const (
+	// maxVerifyAttempts is the number of times that an object is fetched
+	// before a lazy read gives up on getting a copy that matches its hash
+	maxVerifyAttempts = 3
+	// maxBufferedObject is the size above which objects are held back on disk,
+	// rather than in memory, while they're verified
+	maxBufferedObject = 32 * 1024 * 1024
+)
+
+// VerifyPipes sets whether the files that Pull pipes are checked against
+// their object hashes as they're read (it's true for new Pullers). It applies
+// to the pipes created by later calls to Pull.
+func (p *Puller) VerifyPipes(verify bool) {
+	p.Lock()
+	defer p.Unlock()
+	p.skipVerify = !verify
+}
+
+// CorrectedReads returns the number of objects that this puller fetched
+// again because they didn't match their hashes, since the last time CleanUp
+// was called.
+func (p *Puller) CorrectedReads() int64 {
+	return atomic.LoadInt64(&p.correctedReads)
+}
+
+// getFileVerified writes the file at 'path' to 'w' one object at a time,
+// writing each object only once it matches its hash, and fetching it again if
+// it doesn't. Files that are stored as block refs have no hashes to check, so
+// they're streamed unverified.
+func (p *Puller) getFileVerified(client *pachclient.APIClient, repo, commit, path string, w io.Writer) error {
+	fileInfo, err := client.InspectFile(repo, commit, path)
+	if err != nil {
+		return err
+	}
+	if len(fileInfo.Objects) == 0 || len(fileInfo.BlockRefs) > 0 {
+		return client.GetFile(repo, commit, path, 0, 0, w)
+	}
+	for _, object := range fileInfo.Objects {
+		if err := p.getObjectVerified(client, object.Hash, w); err != nil {
+			return errors.Wrapf(err, "could not read %s@%s:%s", repo, commit, path)
+		}
+	}
+	return nil
+}
+
+// getObjectVerified writes the object 'hash' to 'w' once a copy of it that
+// matches its hash has been fetched.
+func (p *Puller) getObjectVerified(client *pachclient.APIClient, hash string, w io.Writer) error {
+	for attempt := 1; ; attempt++ {
+		err := p.tryGetObjectVerified(client, hash, w)
+		if err == nil {
+			if attempt > 1 {
+				atomic.AddInt64(&p.correctedReads, 1)
+			}
+			return nil
+		}
+		if !errors.Is(err, errObjectMismatch) {
+			return err
+		}
+		if attempt == maxVerifyAttempts {
+			return errors.Errorf("object %s didn't match its hash after %d attempts", hash, attempt)
+		}
+	}
+}
+
+func (p *Puller) tryGetObjectVerified(client *pachclient.APIClient, hash string, w io.Writer) (retErr error) {
+	buf := &spillBuffer{}
+	defer func() {
+		if err := buf.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	h := pfs.NewHash()
+	if err := client.GetObject(hash, io.MultiWriter(buf, h)); err != nil {
+		return err
+	}
+	if pfs.EncodeHash(h.Sum(nil)) != hash {
+		return errObjectMismatch
+	}
+	return buf.writeTo(w)
+}
+
+var errObjectMismatch = errors.New("object doesn't match its hash")
+
+// spillBuffer holds the bytes written to it in memory, until there are more
+// than maxBufferedObject of them, at which point it moves them to a temporary
+// file.
+type spillBuffer struct {
+	buf  bytes.Buffer
+	file *os.File
+}
+
+func (s *spillBuffer) Write(p []byte) (int, error) {
+	if s.file == nil && s.buf.Len()+len(p) > maxBufferedObject {
+		f, err := ioutil.TempFile("", "pachyderm-verify-")
+		if err != nil {
+			return 0, errors.EnsureStack(err)
+		}
+		s.file = f
+		if _, err := s.buf.WriteTo(f); err != nil {
+			return 0, errors.EnsureStack(err)
+		}
+	}
+	if s.file != nil {
+		n, err := s.file.Write(p)
+		return n, errors.EnsureStack(err)
+	}
+	return s.buf.Write(p)
+}
+
+// writeTo writes the bytes held by s to 'w'.
+func (s *spillBuffer) writeTo(w io.Writer) error {
+	if s.file == nil {
+		_, err := s.buf.WriteTo(w)
+		return err
+	}
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return errors.EnsureStack(err)
+	}
+	_, err := io.Copy(w, s.file)
+	return err
+}
+
+// Close removes s's temporary file, if it has one.
+func (s *spillBuffer) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	if err := s.file.Close(); err != nil {
+		return errors.EnsureStack(err)
+	}
+	return errors.EnsureStack(os.Remove(s.file.Name()))
+}
@@ -0,0 +1,83 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	pachclient "github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/server/pkg/testpachd"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func hashOf(data string) string {
+	h := pfs.NewHash()
+	h.Write([]byte(data))
+	return pfs.EncodeHash(h.Sum(nil))
+}
+
+// withMockObjects runs 'cb' with a client of a mock pachd that stores the
+// file "/file" as 'objects', and serves each object with 'getObject'.
+func withMockObjects(t *testing.T, objects []string, getObject func(hash string, attempt int) string, cb func(*pachclient.APIClient)) {
+	mock, err := testpachd.NewMockPachd(context.Background())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, mock.Close()) }()
+	fileInfo := &pfs.FileInfo{File: pachclient.NewFile("repo", "master", "/file")}
+	for _, object := range objects {
+		fileInfo.Objects = append(fileInfo.Objects, &pfs.Object{Hash: hashOf(object)})
+	}
+	mock.PFS.InspectFile.Use(func(context.Context, *pfs.InspectFileRequest) (*pfs.FileInfo, error) {
+		return fileInfo, nil
+	})
+	attempts := make(map[string]int)
+	mock.Object.GetObject.Use(func(object *pfs.Object, server pfs.ObjectAPI_GetObjectServer) error {
+		attempts[object.Hash]++
+		return server.Send(&types.BytesValue{Value: []byte(getObject(object.Hash, attempts[object.Hash]))})
+	})
+	c, err := pachclient.NewFromAddress(mock.Addr.String())
+	require.NoError(t, err)
+	defer c.Close()
+	cb(c)
+}
+
+func TestGetFileVerified(t *testing.T) {
+	objects := []string{"foo", "bar"}
+	// The first copy of "bar" that's fetched is corrupt
+	getObject := func(hash string, attempt int) string {
+		if hash == hashOf("bar") && attempt == 1 {
+			return "baz"
+		}
+		for _, object := range objects {
+			if hashOf(object) == hash {
+				return object
+			}
+		}
+		return ""
+	}
+	withMockObjects(t, objects, getObject, func(c *pachclient.APIClient) {
+		p := NewPuller()
+		var buf bytes.Buffer
+		require.NoError(t, p.getFileVerified(c, "repo", "master", "/file", &buf))
+		require.Equal(t, "foobar", buf.String())
+		require.Equal(t, int64(1), p.CorrectedReads())
+		_, err := p.CleanUp()
+		require.NoError(t, err)
+		require.Equal(t, int64(0), p.CorrectedReads())
+	})
+}
+
+func TestGetFileVerifiedCorrupt(t *testing.T) {
+	withMockObjects(t, []string{"foo"}, func(string, int) string { return "bar" }, func(c *pachclient.APIClient) {
+		p := NewPuller()
+		var buf bytes.Buffer
+		err := p.getFileVerified(c, "repo", "master", "/file", &buf)
+		require.YesError(t, err)
+		require.Matches(t, "didn't match its hash after 3 attempts", err.Error())
+		// None of the corrupt data reached the reader
+		require.Equal(t, 0, buf.Len())
+		require.Equal(t, int64(0), p.CorrectedReads())
+	})
+}
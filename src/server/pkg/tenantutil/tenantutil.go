@@ -0,0 +1,71 @@
+// Package tenantutil implements tenants' quotas on the number of repos and
+// pipelines that they may own. PFS and PPS keep a count of each tenant's repos
+// and pipelines in etcd, which they update in the same transaction that
+// creates or deletes the repo or pipeline, so that concurrent creates can't
+// overshoot a tenant's quota.
+package tenantutil
+
+import (
+	"path"
+
+	etcd "github.com/coreos/etcd/clientv3"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/tenant"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+)
+
+const countsPrefix = "/tenantCounts"
+
+// Counts returns a collection of the number of 'resource's (e.g. "repos")
+// that each tenant owns, keyed by tenant.
+func Counts(etcdClient *etcd.Client, etcdPrefix string, resource string) col.Collection {
+	return col.NewCollection(
+		etcdClient,
+		path.Join(etcdPrefix, countsPrefix, resource),
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+}
+
+// Reserve adds one to the number of 'resource's that the tenant 't' owns in
+// 'counts', or returns tenant.ErrQuotaExceeded if 't' already owns 'limit' of
+// them (a 'limit' of 0 means no limit). The count is read and written in
+// 'counts''s STM, so if two transactions reserve the tenant's last slot at
+// once, one of them conflicts and is retried (and then fails).
+func Reserve(counts col.ReadWriteIntCollection, t string, resource string, limit int) error {
+	if t == "" {
+		return nil
+	}
+	n, err := counts.Get(t)
+	if err != nil {
+		if !col.IsErrNotFound(err) {
+			return err
+		}
+		return counts.Create(t, 1)
+	}
+	if limit > 0 && n >= limit {
+		return tenant.ErrQuotaExceeded{Tenant: t, Resource: resource, Limit: limit}
+	}
+	return counts.Increment(t)
+}
+
+// Release subtracts one from the number of resources that the tenant 't' owns
+// in 'counts', after one of them is deleted.
+func Release(counts col.ReadWriteIntCollection, t string) error {
+	if t == "" {
+		return nil
+	}
+	n, err := counts.Get(t)
+	if err != nil {
+		if col.IsErrNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if n <= 1 {
+		return counts.Delete(t)
+	}
+	return counts.Decrement(t)
+}
@@ -0,0 +1,81 @@
+package tenantutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pkg/tenant"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/testetcd"
+	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
+)
+
+func TestReserveAndRelease(t *testing.T) {
+	require.NoError(t, testetcd.WithEnv(func(e *testetcd.Env) error {
+		counts := Counts(e.EtcdClient, uuid.NewWithoutDashes(), "repos")
+		reserve := func(name string) error {
+			_, err := col.NewSTM(context.Background(), e.EtcdClient, func(stm col.STM) error {
+				return Reserve(counts.ReadWriteInt(stm), name, "repos", 2)
+			})
+			return err
+		}
+		release := func(name string) error {
+			_, err := col.NewSTM(context.Background(), e.EtcdClient, func(stm col.STM) error {
+				return Release(counts.ReadWriteInt(stm), name)
+			})
+			return err
+		}
+
+		require.NoError(t, reserve("acme"))
+		require.NoError(t, reserve("acme"))
+		err := reserve("acme")
+		require.YesError(t, err)
+		require.True(t, errorsIsQuota(err))
+
+		// Other tenants have their own quota, and untenanted repos don't count
+		require.NoError(t, reserve("other"))
+		for i := 0; i < 3; i++ {
+			require.NoError(t, reserve(""))
+		}
+
+		// Deleting one of the tenant's repos frees up a slot
+		require.NoError(t, release("acme"))
+		require.NoError(t, reserve("acme"))
+		require.YesError(t, reserve("acme"))
+
+		// Releasing a tenant with no count is a no-op
+		require.NoError(t, release("nobody"))
+		return nil
+	}))
+}
+
+func TestReserveConcurrent(t *testing.T) {
+	require.NoError(t, testetcd.WithEnv(func(e *testetcd.Env) error {
+		counts := Counts(e.EtcdClient, uuid.NewWithoutDashes(), "pipelines")
+		errCh := make(chan error)
+		for i := 0; i < 10; i++ {
+			go func() {
+				_, err := col.NewSTM(context.Background(), e.EtcdClient, func(stm col.STM) error {
+					return Reserve(counts.ReadWriteInt(stm), "acme", "pipelines", 3)
+				})
+				errCh <- err
+			}()
+		}
+		var succeeded int
+		for i := 0; i < 10; i++ {
+			if err := <-errCh; err == nil {
+				succeeded++
+			} else {
+				require.True(t, errorsIsQuota(err))
+			}
+		}
+		require.Equal(t, 3, succeeded)
+		return nil
+	}))
+}
+
+func errorsIsQuota(err error) bool {
+	return errors.As(err, &tenant.ErrQuotaExceeded{})
+}
@@ -47,28 +47,68 @@ func linkServers(mockServerPtr interface{}, realServer interface{}) {
 type extractFunc func(*admin.ExtractRequest, admin.API_ExtractServer) error
 type extractPipelineFunc func(context.Context, *admin.ExtractPipelineRequest) (*admin.Op, error)
 type restoreFunc func(admin.API_RestoreServer) error
+type extractWithProgressFunc func(*admin.ExtractRequest, admin.API_ExtractWithProgressServer) error
+type restoreWithProgressFunc func(admin.API_RestoreWithProgressServer) error
 type inspectClusterFunc func(context.Context, *types.Empty) (*admin.ClusterInfo, error)
+type setFaultsFunc func(context.Context, *admin.Faults) (*types.Empty, error)
+type getFaultsFunc func(context.Context, *types.Empty) (*admin.Faults, error)
+type inspectRateLimitsFunc func(context.Context, *admin.InspectRateLimitsRequest) (*admin.InspectRateLimitsResponse, error)
+type prepareDeleteAllAdminFunc func(context.Context, *types.Empty) (*admin.DeleteAllConfirmation, error)
+type deleteAllAdminFunc func(context.Context, *admin.DeleteAllRequest) (*types.Empty, error)
+type snapshotFunc func(context.Context, *admin.SnapshotRequest) (*admin.SnapshotInfo, error)
+type inspectSnapshotFunc func(context.Context, *admin.InspectSnapshotRequest) (*admin.InspectSnapshotResponse, error)
+type restoreSnapshotFunc func(context.Context, *admin.RestoreSnapshotRequest) (*admin.SnapshotInfo, error)
 
 type mockExtract struct{ handler extractFunc }
 type mockExtractPipeline struct{ handler extractPipelineFunc }
 type mockRestore struct{ handler restoreFunc }
+type mockExtractWithProgress struct{ handler extractWithProgressFunc }
+type mockRestoreWithProgress struct{ handler restoreWithProgressFunc }
 type mockInspectCluster struct{ handler inspectClusterFunc }
-
-func (mock *mockExtract) Use(cb extractFunc)                 { mock.handler = cb }
-func (mock *mockExtractPipeline) Use(cb extractPipelineFunc) { mock.handler = cb }
-func (mock *mockRestore) Use(cb restoreFunc)                 { mock.handler = cb }
-func (mock *mockInspectCluster) Use(cb inspectClusterFunc)   { mock.handler = cb }
+type mockSetFaults struct{ handler setFaultsFunc }
+type mockGetFaults struct{ handler getFaultsFunc }
+type mockInspectRateLimits struct{ handler inspectRateLimitsFunc }
+type mockPrepareDeleteAllAdmin struct{ handler prepareDeleteAllAdminFunc }
+type mockDeleteAllAdmin struct{ handler deleteAllAdminFunc }
+type mockSnapshot struct{ handler snapshotFunc }
+type mockInspectSnapshot struct{ handler inspectSnapshotFunc }
+type mockRestoreSnapshot struct{ handler restoreSnapshotFunc }
+
+func (mock *mockExtract) Use(cb extractFunc)                             { mock.handler = cb }
+func (mock *mockExtractPipeline) Use(cb extractPipelineFunc)             { mock.handler = cb }
+func (mock *mockRestore) Use(cb restoreFunc)                             { mock.handler = cb }
+func (mock *mockExtractWithProgress) Use(cb extractWithProgressFunc)     { mock.handler = cb }
+func (mock *mockRestoreWithProgress) Use(cb restoreWithProgressFunc)     { mock.handler = cb }
+func (mock *mockInspectCluster) Use(cb inspectClusterFunc)               { mock.handler = cb }
+func (mock *mockSetFaults) Use(cb setFaultsFunc)                         { mock.handler = cb }
+func (mock *mockGetFaults) Use(cb getFaultsFunc)                         { mock.handler = cb }
+func (mock *mockInspectRateLimits) Use(cb inspectRateLimitsFunc)         { mock.handler = cb }
+func (mock *mockPrepareDeleteAllAdmin) Use(cb prepareDeleteAllAdminFunc) { mock.handler = cb }
+func (mock *mockDeleteAllAdmin) Use(cb deleteAllAdminFunc)               { mock.handler = cb }
+func (mock *mockSnapshot) Use(cb snapshotFunc)                           { mock.handler = cb }
+func (mock *mockInspectSnapshot) Use(cb inspectSnapshotFunc)             { mock.handler = cb }
+func (mock *mockRestoreSnapshot) Use(cb restoreSnapshotFunc)             { mock.handler = cb }
 
 type adminServerAPI struct {
 	mock *mockAdminServer
 }
 
 type mockAdminServer struct {
-	api             adminServerAPI
-	Extract         mockExtract
-	ExtractPipeline mockExtractPipeline
-	Restore         mockRestore
-	InspectCluster  mockInspectCluster
+	api                 adminServerAPI
+	Extract             mockExtract
+	ExtractPipeline     mockExtractPipeline
+	Restore             mockRestore
+	ExtractWithProgress mockExtractWithProgress
+	RestoreWithProgress mockRestoreWithProgress
+	InspectCluster      mockInspectCluster
+	SetFaults           mockSetFaults
+	GetFaults           mockGetFaults
+	InspectRateLimits   mockInspectRateLimits
+	PrepareDeleteAll    mockPrepareDeleteAllAdmin
+	DeleteAll           mockDeleteAllAdmin
+	Snapshot            mockSnapshot
+	InspectSnapshot     mockInspectSnapshot
+	RestoreSnapshot     mockRestoreSnapshot
 }
 
 func (api *adminServerAPI) Extract(req *admin.ExtractRequest, serv admin.API_ExtractServer) error {
@@ -89,12 +129,73 @@ func (api *adminServerAPI) Restore(serv admin.API_RestoreServer) error {
 	}
 	return errors.Errorf("unhandled pachd mock admin.Restore")
 }
+func (api *adminServerAPI) ExtractWithProgress(req *admin.ExtractRequest, serv admin.API_ExtractWithProgressServer) error {
+	if api.mock.ExtractWithProgress.handler != nil {
+		return api.mock.ExtractWithProgress.handler(req, serv)
+	}
+	return errors.Errorf("unhandled pachd mock admin.ExtractWithProgress")
+}
+func (api *adminServerAPI) RestoreWithProgress(serv admin.API_RestoreWithProgressServer) error {
+	if api.mock.RestoreWithProgress.handler != nil {
+		return api.mock.RestoreWithProgress.handler(serv)
+	}
+	return errors.Errorf("unhandled pachd mock admin.RestoreWithProgress")
+}
 func (api *adminServerAPI) InspectCluster(ctx context.Context, req *types.Empty) (*admin.ClusterInfo, error) {
 	if api.mock.InspectCluster.handler != nil {
 		return api.mock.InspectCluster.handler(ctx, req)
 	}
 	return nil, errors.Errorf("unhandled pachd mock admin.InspectCluster")
 }
+func (api *adminServerAPI) SetFaults(ctx context.Context, req *admin.Faults) (*types.Empty, error) {
+	if api.mock.SetFaults.handler != nil {
+		return api.mock.SetFaults.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock admin.SetFaults")
+}
+func (api *adminServerAPI) GetFaults(ctx context.Context, req *types.Empty) (*admin.Faults, error) {
+	if api.mock.GetFaults.handler != nil {
+		return api.mock.GetFaults.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock admin.GetFaults")
+}
+
+func (api *adminServerAPI) InspectRateLimits(ctx context.Context, req *admin.InspectRateLimitsRequest) (*admin.InspectRateLimitsResponse, error) {
+	if api.mock.InspectRateLimits.handler != nil {
+		return api.mock.InspectRateLimits.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock admin.InspectRateLimits")
+}
+func (api *adminServerAPI) PrepareDeleteAll(ctx context.Context, req *types.Empty) (*admin.DeleteAllConfirmation, error) {
+	if api.mock.PrepareDeleteAll.handler != nil {
+		return api.mock.PrepareDeleteAll.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock admin.PrepareDeleteAll")
+}
+func (api *adminServerAPI) DeleteAll(ctx context.Context, req *admin.DeleteAllRequest) (*types.Empty, error) {
+	if api.mock.DeleteAll.handler != nil {
+		return api.mock.DeleteAll.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock admin.DeleteAll")
+}
+func (api *adminServerAPI) Snapshot(ctx context.Context, req *admin.SnapshotRequest) (*admin.SnapshotInfo, error) {
+	if api.mock.Snapshot.handler != nil {
+		return api.mock.Snapshot.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock admin.Snapshot")
+}
+func (api *adminServerAPI) InspectSnapshot(ctx context.Context, req *admin.InspectSnapshotRequest) (*admin.InspectSnapshotResponse, error) {
+	if api.mock.InspectSnapshot.handler != nil {
+		return api.mock.InspectSnapshot.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock admin.InspectSnapshot")
+}
+func (api *adminServerAPI) RestoreSnapshot(ctx context.Context, req *admin.RestoreSnapshotRequest) (*admin.SnapshotInfo, error) {
+	if api.mock.RestoreSnapshot.handler != nil {
+		return api.mock.RestoreSnapshot.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock admin.RestoreSnapshot")
+}
 
 /* Auth Server Mocks */
 
@@ -401,23 +502,38 @@ type createRepoFunc func(context.Context, *pfs.CreateRepoRequest) (*types.Empty,
 type inspectRepoFunc func(context.Context, *pfs.InspectRepoRequest) (*pfs.RepoInfo, error)
 type listRepoFunc func(context.Context, *pfs.ListRepoRequest) (*pfs.ListRepoResponse, error)
 type deleteRepoFunc func(context.Context, *pfs.DeleteRepoRequest) (*types.Empty, error)
+type setReadPolicyFunc func(context.Context, *pfs.SetReadPolicyRequest) (*types.Empty, error)
+type setOpenCommitPolicyFunc func(context.Context, *pfs.SetOpenCommitPolicyRequest) (*types.Empty, error)
+type updateRepoMetadataFunc func(context.Context, *pfs.UpdateRepoMetadataRequest) (*types.Empty, error)
+type discoverReposFunc func(context.Context, *pfs.DiscoverReposRequest) (*pfs.DiscoverReposResponse, error)
 type startCommitFunc func(context.Context, *pfs.StartCommitRequest) (*pfs.Commit, error)
 type finishCommitFunc func(context.Context, *pfs.FinishCommitRequest) (*types.Empty, error)
+type finishCommitStatusFunc func(context.Context, *pfs.FinishCommitStatusRequest) (*pfs.FinishCommitProgress, error)
 type inspectCommitFunc func(context.Context, *pfs.InspectCommitRequest) (*pfs.CommitInfo, error)
 type listCommitFunc func(context.Context, *pfs.ListCommitRequest) (*pfs.CommitInfos, error)
 type listCommitStreamFunc func(*pfs.ListCommitRequest, pfs.API_ListCommitStreamServer) error
+type listOpenCommitsFunc func(context.Context, *pfs.ListOpenCommitsRequest) (*pfs.CommitInfos, error)
 type deleteCommitFunc func(context.Context, *pfs.DeleteCommitRequest) (*types.Empty, error)
+type pruneCommitFunc func(context.Context, *pfs.PruneCommitRequest) (*types.Empty, error)
 type flushCommitFunc func(*pfs.FlushCommitRequest, pfs.API_FlushCommitServer) error
 type subscribeCommitFunc func(*pfs.SubscribeCommitRequest, pfs.API_SubscribeCommitServer) error
 type buildCommitFunc func(context.Context, *pfs.BuildCommitRequest) (*pfs.Commit, error)
 type createBranchFunc func(context.Context, *pfs.CreateBranchRequest) (*types.Empty, error)
+type moveBranchFunc func(context.Context, *pfs.MoveBranchRequest) (*types.Empty, error)
+type setBranchDeferredFunc func(context.Context, *pfs.SetBranchDeferredRequest) (*types.Empty, error)
 type inspectBranchFunc func(context.Context, *pfs.InspectBranchRequest) (*pfs.BranchInfo, error)
 type listBranchFunc func(context.Context, *pfs.ListBranchRequest) (*pfs.BranchInfos, error)
 type deleteBranchFunc func(context.Context, *pfs.DeleteBranchRequest) (*types.Empty, error)
+type tagCommitFunc func(context.Context, *pfs.TagCommitRequest) (*types.Empty, error)
+type inspectCommitTagFunc func(context.Context, *pfs.InspectCommitTagRequest) (*pfs.CommitTagInfo, error)
+type listCommitTagFunc func(context.Context, *pfs.ListCommitTagRequest) (*pfs.CommitTagInfos, error)
+type deleteCommitTagFunc func(context.Context, *pfs.DeleteCommitTagRequest) (*types.Empty, error)
+type getAttestationFunc func(context.Context, *pfs.GetAttestationRequest) (*pfs.Attestation, error)
 type putFileFunc func(pfs.API_PutFileServer) error
 type copyFileFunc func(context.Context, *pfs.CopyFileRequest) (*types.Empty, error)
 type getFileFunc func(*pfs.GetFileRequest, pfs.API_GetFileServer) error
 type inspectFileFunc func(context.Context, *pfs.InspectFileRequest) (*pfs.FileInfo, error)
+type getFileURLFunc func(context.Context, *pfs.GetFileURLRequest) (*pfs.GetFileURLResponse, error)
 type listFileFunc func(context.Context, *pfs.ListFileRequest) (*pfs.FileInfos, error)
 type listFileStreamFunc func(*pfs.ListFileRequest, pfs.API_ListFileStreamServer) error
 type walkFileFunc func(*pfs.WalkFileRequest, pfs.API_WalkFileServer) error
@@ -425,8 +541,16 @@ type globFileFunc func(context.Context, *pfs.GlobFileRequest) (*pfs.FileInfos, e
 type globFileStreamFunc func(*pfs.GlobFileRequest, pfs.API_GlobFileStreamServer) error
 type diffFileFunc func(context.Context, *pfs.DiffFileRequest) (*pfs.DiffFileResponse, error)
 type deleteFileFunc func(context.Context, *pfs.DeleteFileRequest) (*types.Empty, error)
+type deleteFilesFunc func(context.Context, *pfs.DeleteFilesRequest) (*types.Empty, error)
+type replaceDirectoryFunc func(context.Context, *pfs.ReplaceDirectoryRequest) (*types.Empty, error)
 type deleteAllPFSFunc func(context.Context, *types.Empty) (*types.Empty, error)
 type fsckFunc func(*pfs.FsckRequest, pfs.API_FsckServer) error
+type changeFeedFunc func(*pfs.ChangeFeedRequest, pfs.API_ChangeFeedServer) error
+type watchReposFunc func(*pfs.WatchReposRequest, pfs.API_WatchReposServer) error
+type archiveProvenanceFunc func(context.Context, *pfs.ArchiveProvenanceRequest) (*pfs.ArchiveProvenanceResponse, error)
+type listDeletedFunc func(context.Context, *pfs.ListDeletedRequest) (*pfs.ListDeletedResponse, error)
+type restoreDeletedFunc func(context.Context, *pfs.RestoreDeletedRequest) (*types.Empty, error)
+type purgeDeletedFunc func(context.Context, *pfs.PurgeDeletedRequest) (*types.Empty, error)
 type fileOperationFuncV2 func(pfs.API_FileOperationV2Server) error
 type getTarFuncV2 func(*pfs.GetTarRequestV2, pfs.API_GetTarV2Server) error
 type diffFileV2Func func(*pfs.DiffFileRequest, pfs.API_DiffFileV2Server) error
@@ -438,23 +562,38 @@ type mockCreateRepo struct{ handler createRepoFunc }
 type mockInspectRepo struct{ handler inspectRepoFunc }
 type mockListRepo struct{ handler listRepoFunc }
 type mockDeleteRepo struct{ handler deleteRepoFunc }
+type mockSetReadPolicy struct{ handler setReadPolicyFunc }
+type mockSetOpenCommitPolicy struct{ handler setOpenCommitPolicyFunc }
+type mockUpdateRepoMetadata struct{ handler updateRepoMetadataFunc }
+type mockDiscoverRepos struct{ handler discoverReposFunc }
 type mockStartCommit struct{ handler startCommitFunc }
 type mockFinishCommit struct{ handler finishCommitFunc }
+type mockFinishCommitStatus struct{ handler finishCommitStatusFunc }
 type mockInspectCommit struct{ handler inspectCommitFunc }
 type mockListCommit struct{ handler listCommitFunc }
 type mockListCommitStream struct{ handler listCommitStreamFunc }
+type mockListOpenCommits struct{ handler listOpenCommitsFunc }
 type mockDeleteCommit struct{ handler deleteCommitFunc }
+type mockPruneCommit struct{ handler pruneCommitFunc }
 type mockFlushCommit struct{ handler flushCommitFunc }
 type mockSubscribeCommit struct{ handler subscribeCommitFunc }
 type mockBuildCommit struct{ handler buildCommitFunc }
 type mockCreateBranch struct{ handler createBranchFunc }
+type mockMoveBranch struct{ handler moveBranchFunc }
+type mockSetBranchDeferred struct{ handler setBranchDeferredFunc }
 type mockInspectBranch struct{ handler inspectBranchFunc }
 type mockListBranch struct{ handler listBranchFunc }
 type mockDeleteBranch struct{ handler deleteBranchFunc }
+type mockTagCommit struct{ handler tagCommitFunc }
+type mockInspectCommitTag struct{ handler inspectCommitTagFunc }
+type mockListCommitTag struct{ handler listCommitTagFunc }
+type mockDeleteCommitTag struct{ handler deleteCommitTagFunc }
+type mockGetAttestation struct{ handler getAttestationFunc }
 type mockPutFile struct{ handler putFileFunc }
 type mockCopyFile struct{ handler copyFileFunc }
 type mockGetFile struct{ handler getFileFunc }
 type mockInspectFile struct{ handler inspectFileFunc }
+type mockGetFileURL struct{ handler getFileURLFunc }
 type mockListFile struct{ handler listFileFunc }
 type mockListFileStream struct{ handler listFileStreamFunc }
 type mockWalkFile struct{ handler walkFileFunc }
@@ -462,8 +601,16 @@ type mockGlobFile struct{ handler globFileFunc }
 type mockGlobFileStream struct{ handler globFileStreamFunc }
 type mockDiffFile struct{ handler diffFileFunc }
 type mockDeleteFile struct{ handler deleteFileFunc }
+type mockDeleteFiles struct{ handler deleteFilesFunc }
+type mockReplaceDirectory struct{ handler replaceDirectoryFunc }
 type mockDeleteAllPFS struct{ handler deleteAllPFSFunc }
 type mockFsck struct{ handler fsckFunc }
+type mockChangeFeed struct{ handler changeFeedFunc }
+type mockWatchRepos struct{ handler watchReposFunc }
+type mockArchiveProvenance struct{ handler archiveProvenanceFunc }
+type mockListDeleted struct{ handler listDeletedFunc }
+type mockRestoreDeleted struct{ handler restoreDeletedFunc }
+type mockPurgeDeleted struct{ handler purgeDeletedFunc }
 type mockFileOperationV2 struct{ handler fileOperationFuncV2 }
 type mockGetTarV2 struct{ handler getTarFuncV2 }
 type mockDiffFileV2 struct{ handler diffFileV2Func }
@@ -471,85 +618,131 @@ type mockClearCommitV2 struct{ handler clearCommitV2Func }
 type mockCreateTmpFileSet struct{ handler createTmpFileSetFunc }
 type mockRenewTmpFileSet struct{ handler renewTmpFileSetFunc }
 
-func (mock *mockCreateRepo) Use(cb createRepoFunc)             { mock.handler = cb }
-func (mock *mockInspectRepo) Use(cb inspectRepoFunc)           { mock.handler = cb }
-func (mock *mockListRepo) Use(cb listRepoFunc)                 { mock.handler = cb }
-func (mock *mockDeleteRepo) Use(cb deleteRepoFunc)             { mock.handler = cb }
-func (mock *mockStartCommit) Use(cb startCommitFunc)           { mock.handler = cb }
-func (mock *mockFinishCommit) Use(cb finishCommitFunc)         { mock.handler = cb }
-func (mock *mockInspectCommit) Use(cb inspectCommitFunc)       { mock.handler = cb }
-func (mock *mockListCommit) Use(cb listCommitFunc)             { mock.handler = cb }
-func (mock *mockListCommitStream) Use(cb listCommitStreamFunc) { mock.handler = cb }
-func (mock *mockDeleteCommit) Use(cb deleteCommitFunc)         { mock.handler = cb }
-func (mock *mockFlushCommit) Use(cb flushCommitFunc)           { mock.handler = cb }
-func (mock *mockSubscribeCommit) Use(cb subscribeCommitFunc)   { mock.handler = cb }
-func (mock *mockBuildCommit) Use(cb buildCommitFunc)           { mock.handler = cb }
-func (mock *mockCreateBranch) Use(cb createBranchFunc)         { mock.handler = cb }
-func (mock *mockInspectBranch) Use(cb inspectBranchFunc)       { mock.handler = cb }
-func (mock *mockListBranch) Use(cb listBranchFunc)             { mock.handler = cb }
-func (mock *mockDeleteBranch) Use(cb deleteBranchFunc)         { mock.handler = cb }
-func (mock *mockPutFile) Use(cb putFileFunc)                   { mock.handler = cb }
-func (mock *mockCopyFile) Use(cb copyFileFunc)                 { mock.handler = cb }
-func (mock *mockGetFile) Use(cb getFileFunc)                   { mock.handler = cb }
-func (mock *mockInspectFile) Use(cb inspectFileFunc)           { mock.handler = cb }
-func (mock *mockListFile) Use(cb listFileFunc)                 { mock.handler = cb }
-func (mock *mockListFileStream) Use(cb listFileStreamFunc)     { mock.handler = cb }
-func (mock *mockWalkFile) Use(cb walkFileFunc)                 { mock.handler = cb }
-func (mock *mockGlobFile) Use(cb globFileFunc)                 { mock.handler = cb }
-func (mock *mockGlobFileStream) Use(cb globFileStreamFunc)     { mock.handler = cb }
-func (mock *mockDiffFile) Use(cb diffFileFunc)                 { mock.handler = cb }
-func (mock *mockDeleteFile) Use(cb deleteFileFunc)             { mock.handler = cb }
-func (mock *mockDeleteAllPFS) Use(cb deleteAllPFSFunc)         { mock.handler = cb }
-func (mock *mockFsck) Use(cb fsckFunc)                         { mock.handler = cb }
-func (mock *mockFileOperationV2) Use(cb fileOperationFuncV2)   { mock.handler = cb }
-func (mock *mockGetTarV2) Use(cb getTarFuncV2)                 { mock.handler = cb }
-func (mock *mockDiffFileV2) Use(cb diffFileV2Func)             { mock.handler = cb }
-func (mock *mockClearCommitV2) Use(cb clearCommitV2Func)       { mock.handler = cb }
-func (mock *mockCreateTmpFileSet) Use(cb createTmpFileSetFunc) { mock.handler = cb }
-func (mock *mockRenewTmpFileSet) Use(cb renewTmpFileSetFunc)   { mock.handler = cb }
+func (mock *mockCreateRepo) Use(cb createRepoFunc)                   { mock.handler = cb }
+func (mock *mockInspectRepo) Use(cb inspectRepoFunc)                 { mock.handler = cb }
+func (mock *mockListRepo) Use(cb listRepoFunc)                       { mock.handler = cb }
+func (mock *mockDeleteRepo) Use(cb deleteRepoFunc)                   { mock.handler = cb }
+func (mock *mockSetReadPolicy) Use(cb setReadPolicyFunc)             { mock.handler = cb }
+func (mock *mockSetOpenCommitPolicy) Use(cb setOpenCommitPolicyFunc) { mock.handler = cb }
+func (mock *mockUpdateRepoMetadata) Use(cb updateRepoMetadataFunc)   { mock.handler = cb }
+func (mock *mockDiscoverRepos) Use(cb discoverReposFunc)             { mock.handler = cb }
+func (mock *mockStartCommit) Use(cb startCommitFunc)                 { mock.handler = cb }
+func (mock *mockFinishCommit) Use(cb finishCommitFunc)               { mock.handler = cb }
+func (mock *mockFinishCommitStatus) Use(cb finishCommitStatusFunc)   { mock.handler = cb }
+func (mock *mockInspectCommit) Use(cb inspectCommitFunc)             { mock.handler = cb }
+func (mock *mockListCommit) Use(cb listCommitFunc)                   { mock.handler = cb }
+func (mock *mockListCommitStream) Use(cb listCommitStreamFunc)       { mock.handler = cb }
+func (mock *mockListOpenCommits) Use(cb listOpenCommitsFunc)         { mock.handler = cb }
+func (mock *mockDeleteCommit) Use(cb deleteCommitFunc)               { mock.handler = cb }
+func (mock *mockPruneCommit) Use(cb pruneCommitFunc)                 { mock.handler = cb }
+func (mock *mockFlushCommit) Use(cb flushCommitFunc)                 { mock.handler = cb }
+func (mock *mockSubscribeCommit) Use(cb subscribeCommitFunc)         { mock.handler = cb }
+func (mock *mockBuildCommit) Use(cb buildCommitFunc)                 { mock.handler = cb }
+func (mock *mockCreateBranch) Use(cb createBranchFunc)               { mock.handler = cb }
+func (mock *mockMoveBranch) Use(cb moveBranchFunc)                   { mock.handler = cb }
+func (mock *mockSetBranchDeferred) Use(cb setBranchDeferredFunc)     { mock.handler = cb }
+func (mock *mockInspectBranch) Use(cb inspectBranchFunc)             { mock.handler = cb }
+func (mock *mockListBranch) Use(cb listBranchFunc)                   { mock.handler = cb }
+func (mock *mockDeleteBranch) Use(cb deleteBranchFunc)               { mock.handler = cb }
+func (mock *mockTagCommit) Use(cb tagCommitFunc)                     { mock.handler = cb }
+func (mock *mockInspectCommitTag) Use(cb inspectCommitTagFunc)       { mock.handler = cb }
+func (mock *mockListCommitTag) Use(cb listCommitTagFunc)             { mock.handler = cb }
+func (mock *mockDeleteCommitTag) Use(cb deleteCommitTagFunc)         { mock.handler = cb }
+func (mock *mockGetAttestation) Use(cb getAttestationFunc)           { mock.handler = cb }
+func (mock *mockPutFile) Use(cb putFileFunc)                         { mock.handler = cb }
+func (mock *mockCopyFile) Use(cb copyFileFunc)                       { mock.handler = cb }
+func (mock *mockGetFile) Use(cb getFileFunc)                         { mock.handler = cb }
+func (mock *mockInspectFile) Use(cb inspectFileFunc)                 { mock.handler = cb }
+func (mock *mockGetFileURL) Use(cb getFileURLFunc)                   { mock.handler = cb }
+func (mock *mockListFile) Use(cb listFileFunc)                       { mock.handler = cb }
+func (mock *mockListFileStream) Use(cb listFileStreamFunc)           { mock.handler = cb }
+func (mock *mockWalkFile) Use(cb walkFileFunc)                       { mock.handler = cb }
+func (mock *mockGlobFile) Use(cb globFileFunc)                       { mock.handler = cb }
+func (mock *mockGlobFileStream) Use(cb globFileStreamFunc)           { mock.handler = cb }
+func (mock *mockDiffFile) Use(cb diffFileFunc)                       { mock.handler = cb }
+func (mock *mockDeleteFile) Use(cb deleteFileFunc)                   { mock.handler = cb }
+func (mock *mockDeleteFiles) Use(cb deleteFilesFunc)                 { mock.handler = cb }
+func (mock *mockReplaceDirectory) Use(cb replaceDirectoryFunc)       { mock.handler = cb }
+func (mock *mockDeleteAllPFS) Use(cb deleteAllPFSFunc)               { mock.handler = cb }
+func (mock *mockFsck) Use(cb fsckFunc)                               { mock.handler = cb }
+func (mock *mockChangeFeed) Use(cb changeFeedFunc)                   { mock.handler = cb }
+func (mock *mockWatchRepos) Use(cb watchReposFunc)                   { mock.handler = cb }
+func (mock *mockArchiveProvenance) Use(cb archiveProvenanceFunc)     { mock.handler = cb }
+func (mock *mockListDeleted) Use(cb listDeletedFunc)                 { mock.handler = cb }
+func (mock *mockRestoreDeleted) Use(cb restoreDeletedFunc)           { mock.handler = cb }
+func (mock *mockPurgeDeleted) Use(cb purgeDeletedFunc)               { mock.handler = cb }
+func (mock *mockFileOperationV2) Use(cb fileOperationFuncV2)         { mock.handler = cb }
+func (mock *mockGetTarV2) Use(cb getTarFuncV2)                       { mock.handler = cb }
+func (mock *mockDiffFileV2) Use(cb diffFileV2Func)                   { mock.handler = cb }
+func (mock *mockClearCommitV2) Use(cb clearCommitV2Func)             { mock.handler = cb }
+func (mock *mockCreateTmpFileSet) Use(cb createTmpFileSetFunc)       { mock.handler = cb }
+func (mock *mockRenewTmpFileSet) Use(cb renewTmpFileSetFunc)         { mock.handler = cb }
 
 type pfsServerAPI struct {
 	mock *mockPFSServer
 }
 
 type mockPFSServer struct {
-	api              pfsServerAPI
-	CreateRepo       mockCreateRepo
-	InspectRepo      mockInspectRepo
-	ListRepo         mockListRepo
-	DeleteRepo       mockDeleteRepo
-	StartCommit      mockStartCommit
-	FinishCommit     mockFinishCommit
-	InspectCommit    mockInspectCommit
-	ListCommit       mockListCommit
-	ListCommitStream mockListCommitStream
-	DeleteCommit     mockDeleteCommit
-	FlushCommit      mockFlushCommit
-	SubscribeCommit  mockSubscribeCommit
-	BuildCommit      mockBuildCommit
-	CreateBranch     mockCreateBranch
-	InspectBranch    mockInspectBranch
-	ListBranch       mockListBranch
-	DeleteBranch     mockDeleteBranch
-	PutFile          mockPutFile
-	CopyFile         mockCopyFile
-	GetFile          mockGetFile
-	InspectFile      mockInspectFile
-	ListFile         mockListFile
-	ListFileStream   mockListFileStream
-	WalkFile         mockWalkFile
-	GlobFile         mockGlobFile
-	GlobFileStream   mockGlobFileStream
-	DiffFile         mockDiffFile
-	DeleteFile       mockDeleteFile
-	DeleteAll        mockDeleteAllPFS
-	Fsck             mockFsck
-	FileOperationV2  mockFileOperationV2
-	GetTarV2         mockGetTarV2
-	DiffFileV2       mockDiffFileV2
-	ClearCommitV2    mockClearCommitV2
-	CreateTmpFileSet mockCreateTmpFileSet
-	RenewTmpFileSet  mockRenewTmpFileSet
+	api                 pfsServerAPI
+	CreateRepo          mockCreateRepo
+	InspectRepo         mockInspectRepo
+	ListRepo            mockListRepo
+	DeleteRepo          mockDeleteRepo
+	SetReadPolicy       mockSetReadPolicy
+	SetOpenCommitPolicy mockSetOpenCommitPolicy
+	UpdateRepoMetadata  mockUpdateRepoMetadata
+	DiscoverRepos       mockDiscoverRepos
+	StartCommit         mockStartCommit
+	FinishCommit        mockFinishCommit
+	FinishCommitStatus  mockFinishCommitStatus
+	InspectCommit       mockInspectCommit
+	ListCommit          mockListCommit
+	ListCommitStream    mockListCommitStream
+	ListOpenCommits     mockListOpenCommits
+	DeleteCommit        mockDeleteCommit
+	PruneCommit         mockPruneCommit
+	FlushCommit         mockFlushCommit
+	SubscribeCommit     mockSubscribeCommit
+	BuildCommit         mockBuildCommit
+	CreateBranch        mockCreateBranch
+	MoveBranch          mockMoveBranch
+	SetBranchDeferred   mockSetBranchDeferred
+	InspectBranch       mockInspectBranch
+	ListBranch          mockListBranch
+	DeleteBranch        mockDeleteBranch
+	TagCommit           mockTagCommit
+	InspectCommitTag    mockInspectCommitTag
+	ListCommitTag       mockListCommitTag
+	DeleteCommitTag     mockDeleteCommitTag
+	GetAttestation      mockGetAttestation
+	PutFile             mockPutFile
+	CopyFile            mockCopyFile
+	GetFile             mockGetFile
+	InspectFile         mockInspectFile
+	GetFileURL          mockGetFileURL
+	ListFile            mockListFile
+	ListFileStream      mockListFileStream
+	WalkFile            mockWalkFile
+	GlobFile            mockGlobFile
+	GlobFileStream      mockGlobFileStream
+	DiffFile            mockDiffFile
+	DeleteFile          mockDeleteFile
+	DeleteFiles         mockDeleteFiles
+	ReplaceDirectory    mockReplaceDirectory
+	DeleteAll           mockDeleteAllPFS
+	Fsck                mockFsck
+	ChangeFeed          mockChangeFeed
+	WatchRepos          mockWatchRepos
+	ArchiveProvenance   mockArchiveProvenance
+	ListDeleted         mockListDeleted
+	RestoreDeleted      mockRestoreDeleted
+	PurgeDeleted        mockPurgeDeleted
+	FileOperationV2     mockFileOperationV2
+	GetTarV2            mockGetTarV2
+	DiffFileV2          mockDiffFileV2
+	ClearCommitV2       mockClearCommitV2
+	CreateTmpFileSet    mockCreateTmpFileSet
+	RenewTmpFileSet     mockRenewTmpFileSet
 }
 
 func (api *pfsServerAPI) CreateRepo(ctx context.Context, req *pfs.CreateRepoRequest) (*types.Empty, error) {
@@ -576,6 +769,31 @@ func (api *pfsServerAPI) DeleteRepo(ctx context.Context, req *pfs.DeleteRepoRequ
 	}
 	return nil, errors.Errorf("unhandled pachd mock pfs.DeleteRepo")
 }
+
+func (api *pfsServerAPI) SetReadPolicy(ctx context.Context, req *pfs.SetReadPolicyRequest) (*types.Empty, error) {
+	if api.mock.SetReadPolicy.handler != nil {
+		return api.mock.SetReadPolicy.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.SetReadPolicy")
+}
+func (api *pfsServerAPI) SetOpenCommitPolicy(ctx context.Context, req *pfs.SetOpenCommitPolicyRequest) (*types.Empty, error) {
+	if api.mock.SetOpenCommitPolicy.handler != nil {
+		return api.mock.SetOpenCommitPolicy.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.SetOpenCommitPolicy")
+}
+func (api *pfsServerAPI) UpdateRepoMetadata(ctx context.Context, req *pfs.UpdateRepoMetadataRequest) (*types.Empty, error) {
+	if api.mock.UpdateRepoMetadata.handler != nil {
+		return api.mock.UpdateRepoMetadata.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.UpdateRepoMetadata")
+}
+func (api *pfsServerAPI) DiscoverRepos(ctx context.Context, req *pfs.DiscoverReposRequest) (*pfs.DiscoverReposResponse, error) {
+	if api.mock.DiscoverRepos.handler != nil {
+		return api.mock.DiscoverRepos.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.DiscoverRepos")
+}
 func (api *pfsServerAPI) StartCommit(ctx context.Context, req *pfs.StartCommitRequest) (*pfs.Commit, error) {
 	if api.mock.StartCommit.handler != nil {
 		return api.mock.StartCommit.handler(ctx, req)
@@ -588,6 +806,12 @@ func (api *pfsServerAPI) FinishCommit(ctx context.Context, req *pfs.FinishCommit
 	}
 	return nil, errors.Errorf("unhandled pachd mock pfs.FinishCommit")
 }
+func (api *pfsServerAPI) FinishCommitStatus(ctx context.Context, req *pfs.FinishCommitStatusRequest) (*pfs.FinishCommitProgress, error) {
+	if api.mock.FinishCommitStatus.handler != nil {
+		return api.mock.FinishCommitStatus.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.FinishCommitStatus")
+}
 func (api *pfsServerAPI) InspectCommit(ctx context.Context, req *pfs.InspectCommitRequest) (*pfs.CommitInfo, error) {
 	if api.mock.InspectCommit.handler != nil {
 		return api.mock.InspectCommit.handler(ctx, req)
@@ -606,12 +830,24 @@ func (api *pfsServerAPI) ListCommitStream(req *pfs.ListCommitRequest, serv pfs.A
 	}
 	return errors.Errorf("unhandled pachd mock pfs.ListCommitStream")
 }
+func (api *pfsServerAPI) ListOpenCommits(ctx context.Context, req *pfs.ListOpenCommitsRequest) (*pfs.CommitInfos, error) {
+	if api.mock.ListOpenCommits.handler != nil {
+		return api.mock.ListOpenCommits.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.ListOpenCommits")
+}
 func (api *pfsServerAPI) DeleteCommit(ctx context.Context, req *pfs.DeleteCommitRequest) (*types.Empty, error) {
 	if api.mock.DeleteCommit.handler != nil {
 		return api.mock.DeleteCommit.handler(ctx, req)
 	}
 	return nil, errors.Errorf("unhandled pachd mock pfs.DeleteCommit")
 }
+func (api *pfsServerAPI) PruneCommit(ctx context.Context, req *pfs.PruneCommitRequest) (*types.Empty, error) {
+	if api.mock.PruneCommit.handler != nil {
+		return api.mock.PruneCommit.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.PruneCommit")
+}
 func (api *pfsServerAPI) FlushCommit(req *pfs.FlushCommitRequest, serv pfs.API_FlushCommitServer) error {
 	if api.mock.FlushCommit.handler != nil {
 		return api.mock.FlushCommit.handler(req, serv)
@@ -636,6 +872,19 @@ func (api *pfsServerAPI) CreateBranch(ctx context.Context, req *pfs.CreateBranch
 	}
 	return nil, errors.Errorf("unhandled pachd mock pfs.CreateBranch")
 }
+
+func (api *pfsServerAPI) MoveBranch(ctx context.Context, req *pfs.MoveBranchRequest) (*types.Empty, error) {
+	if api.mock.MoveBranch.handler != nil {
+		return api.mock.MoveBranch.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.MoveBranch")
+}
+func (api *pfsServerAPI) SetBranchDeferred(ctx context.Context, req *pfs.SetBranchDeferredRequest) (*types.Empty, error) {
+	if api.mock.SetBranchDeferred.handler != nil {
+		return api.mock.SetBranchDeferred.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.SetBranchDeferred")
+}
 func (api *pfsServerAPI) InspectBranch(ctx context.Context, req *pfs.InspectBranchRequest) (*pfs.BranchInfo, error) {
 	if api.mock.InspectBranch.handler != nil {
 		return api.mock.InspectBranch.handler(ctx, req)
@@ -654,6 +903,41 @@ func (api *pfsServerAPI) DeleteBranch(ctx context.Context, req *pfs.DeleteBranch
 	}
 	return nil, errors.Errorf("unhandled pachd mock pfs.DeleteBranch")
 }
+
+func (api *pfsServerAPI) TagCommit(ctx context.Context, req *pfs.TagCommitRequest) (*types.Empty, error) {
+	if api.mock.TagCommit.handler != nil {
+		return api.mock.TagCommit.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.TagCommit")
+}
+
+func (api *pfsServerAPI) InspectCommitTag(ctx context.Context, req *pfs.InspectCommitTagRequest) (*pfs.CommitTagInfo, error) {
+	if api.mock.InspectCommitTag.handler != nil {
+		return api.mock.InspectCommitTag.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.InspectCommitTag")
+}
+
+func (api *pfsServerAPI) ListCommitTag(ctx context.Context, req *pfs.ListCommitTagRequest) (*pfs.CommitTagInfos, error) {
+	if api.mock.ListCommitTag.handler != nil {
+		return api.mock.ListCommitTag.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.ListCommitTag")
+}
+
+func (api *pfsServerAPI) DeleteCommitTag(ctx context.Context, req *pfs.DeleteCommitTagRequest) (*types.Empty, error) {
+	if api.mock.DeleteCommitTag.handler != nil {
+		return api.mock.DeleteCommitTag.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.DeleteCommitTag")
+}
+
+func (api *pfsServerAPI) GetAttestation(ctx context.Context, req *pfs.GetAttestationRequest) (*pfs.Attestation, error) {
+	if api.mock.GetAttestation.handler != nil {
+		return api.mock.GetAttestation.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.GetAttestation")
+}
 func (api *pfsServerAPI) PutFile(serv pfs.API_PutFileServer) error {
 	if api.mock.PutFile.handler != nil {
 		return api.mock.PutFile.handler(serv)
@@ -678,6 +962,12 @@ func (api *pfsServerAPI) InspectFile(ctx context.Context, req *pfs.InspectFileRe
 	}
 	return nil, errors.Errorf("unhandled pachd mock pfs.InspectFile")
 }
+func (api *pfsServerAPI) GetFileURL(ctx context.Context, req *pfs.GetFileURLRequest) (*pfs.GetFileURLResponse, error) {
+	if api.mock.GetFileURL.handler != nil {
+		return api.mock.GetFileURL.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.GetFileURL")
+}
 func (api *pfsServerAPI) ListFile(ctx context.Context, req *pfs.ListFileRequest) (*pfs.FileInfos, error) {
 	if api.mock.ListFile.handler != nil {
 		return api.mock.ListFile.handler(ctx, req)
@@ -720,6 +1010,18 @@ func (api *pfsServerAPI) DeleteFile(ctx context.Context, req *pfs.DeleteFileRequ
 	}
 	return nil, errors.Errorf("unhandled pachd mock pfs.DeleteFile")
 }
+func (api *pfsServerAPI) DeleteFiles(ctx context.Context, req *pfs.DeleteFilesRequest) (*types.Empty, error) {
+	if api.mock.DeleteFiles.handler != nil {
+		return api.mock.DeleteFiles.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.DeleteFiles")
+}
+func (api *pfsServerAPI) ReplaceDirectory(ctx context.Context, req *pfs.ReplaceDirectoryRequest) (*types.Empty, error) {
+	if api.mock.ReplaceDirectory.handler != nil {
+		return api.mock.ReplaceDirectory.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.ReplaceDirectory")
+}
 func (api *pfsServerAPI) DeleteAll(ctx context.Context, req *types.Empty) (*types.Empty, error) {
 	if api.mock.DeleteAll.handler != nil {
 		return api.mock.DeleteAll.handler(ctx, req)
@@ -732,6 +1034,43 @@ func (api *pfsServerAPI) Fsck(req *pfs.FsckRequest, serv pfs.API_FsckServer) err
 	}
 	return errors.Errorf("unhandled pachd mock pfs.Fsck")
 }
+func (api *pfsServerAPI) ChangeFeed(req *pfs.ChangeFeedRequest, serv pfs.API_ChangeFeedServer) error {
+	if api.mock.ChangeFeed.handler != nil {
+		return api.mock.ChangeFeed.handler(req, serv)
+	}
+	return errors.Errorf("unhandled pachd mock pfs.ChangeFeed")
+}
+
+func (api *pfsServerAPI) WatchRepos(req *pfs.WatchReposRequest, serv pfs.API_WatchReposServer) error {
+	if api.mock.WatchRepos.handler != nil {
+		return api.mock.WatchRepos.handler(req, serv)
+	}
+	return errors.Errorf("unhandled pachd mock pfs.WatchRepos")
+}
+func (api *pfsServerAPI) ArchiveProvenance(ctx context.Context, req *pfs.ArchiveProvenanceRequest) (*pfs.ArchiveProvenanceResponse, error) {
+	if api.mock.ArchiveProvenance.handler != nil {
+		return api.mock.ArchiveProvenance.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.ArchiveProvenance")
+}
+func (api *pfsServerAPI) ListDeleted(ctx context.Context, req *pfs.ListDeletedRequest) (*pfs.ListDeletedResponse, error) {
+	if api.mock.ListDeleted.handler != nil {
+		return api.mock.ListDeleted.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.ListDeleted")
+}
+func (api *pfsServerAPI) RestoreDeleted(ctx context.Context, req *pfs.RestoreDeletedRequest) (*types.Empty, error) {
+	if api.mock.RestoreDeleted.handler != nil {
+		return api.mock.RestoreDeleted.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.RestoreDeleted")
+}
+func (api *pfsServerAPI) PurgeDeleted(ctx context.Context, req *pfs.PurgeDeletedRequest) (*types.Empty, error) {
+	if api.mock.PurgeDeleted.handler != nil {
+		return api.mock.PurgeDeleted.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pfs.PurgeDeleted")
+}
 func (api *pfsServerAPI) FileOperationV2(serv pfs.API_FileOperationV2Server) error {
 	if api.mock.FileOperationV2.handler != nil {
 		return api.mock.FileOperationV2.handler(serv)
@@ -778,19 +1117,36 @@ type listJobStreamFunc func(*pps.ListJobRequest, pps.API_ListJobStreamServer) er
 type flushJobFunc func(*pps.FlushJobRequest, pps.API_FlushJobServer) error
 type deleteJobFunc func(context.Context, *pps.DeleteJobRequest) (*types.Empty, error)
 type stopJobFunc func(context.Context, *pps.StopJobRequest) (*types.Empty, error)
+type scaleJobFunc func(context.Context, *pps.ScaleJobRequest) (*types.Empty, error)
 type updateJobStateFunc func(context.Context, *pps.UpdateJobStateRequest) (*types.Empty, error)
 type inspectDatumFunc func(context.Context, *pps.InspectDatumRequest) (*pps.DatumInfo, error)
 type listDatumFunc func(context.Context, *pps.ListDatumRequest) (*pps.ListDatumResponse, error)
 type listDatumStreamFunc func(*pps.ListDatumRequest, pps.API_ListDatumStreamServer) error
 type restartDatumFunc func(context.Context, *pps.RestartDatumRequest) (*types.Empty, error)
+type resolveDatumFunc func(context.Context, *pps.ResolveDatumRequest) (*pps.ResolveDatumResponse, error)
+type explainDatumFunc func(context.Context, *pps.ExplainDatumRequest) (*pps.ExplainDatumResponse, error)
 type createPipelineFunc func(context.Context, *pps.CreatePipelineRequest) (*types.Empty, error)
 type inspectPipelineFunc func(context.Context, *pps.InspectPipelineRequest) (*pps.PipelineInfo, error)
 type listPipelineFunc func(context.Context, *pps.ListPipelineRequest) (*pps.PipelineInfos, error)
+type watchPipelinesFunc func(*pps.WatchPipelinesRequest, pps.API_WatchPipelinesServer) error
 type deletePipelineFunc func(context.Context, *pps.DeletePipelineRequest) (*types.Empty, error)
 type startPipelineFunc func(context.Context, *pps.StartPipelineRequest) (*types.Empty, error)
 type stopPipelineFunc func(context.Context, *pps.StopPipelineRequest) (*types.Empty, error)
 type runPipelineFunc func(context.Context, *pps.RunPipelineRequest) (*types.Empty, error)
 type runCronFunc func(context.Context, *pps.RunCronRequest) (*types.Empty, error)
+type applyDAGFunc func(context.Context, *pps.ApplyDAGRequest) (*pps.ApplyDAGResponse, error)
+type createPipelinesFunc func(context.Context, *pps.CreatePipelinesRequest) (*pps.CreatePipelinesResponse, error)
+type listIdlePipelinesFunc func(context.Context, *pps.ListIdlePipelinesRequest) (*pps.ListIdlePipelinesResponse, error)
+type pruneStatsFunc func(context.Context, *pps.PruneStatsRequest) (*pps.PruneStatsResponse, error)
+type checkPipelineUpdateFunc func(context.Context, *pps.CheckPipelineUpdateRequest) (*pps.CheckPipelineUpdateResponse, error)
+type diffPipelineFunc func(context.Context, *pps.DiffPipelineRequest) (*pps.DiffPipelineResponse, error)
+type getMountCredentialsFunc func(context.Context, *pps.GetMountCredentialsRequest) (*pps.MountCredentials, error)
+type getSchedulerFunc func(context.Context, *pps.GetSchedulerRequest) (*pps.GetSchedulerResponse, error)
+type approveCommitFunc func(context.Context, *pps.ApproveCommitRequest) (*types.Empty, error)
+type updatePipelineConfigFunc func(context.Context, *pps.UpdatePipelineConfigRequest) (*types.Empty, error)
+type listGitHooksFunc func(context.Context, *pps.ListGitHooksRequest) (*pps.GitHookInfos, error)
+type inspectGitHookFunc func(context.Context, *pps.InspectGitHookRequest) (*pps.GitHookInfo, error)
+type replayGitHookDeliveryFunc func(context.Context, *pps.ReplayGitHookDeliveryRequest) (*pps.GitHookDelivery, error)
 type createSecretFunc func(context.Context, *pps.CreateSecretRequest) (*types.Empty, error)
 type deleteSecretFunc func(context.Context, *pps.DeleteSecretRequest) (*types.Empty, error)
 type inspectSecretFunc func(context.Context, *pps.InspectSecretRequest) (*pps.SecretInfo, error)
@@ -807,19 +1163,36 @@ type mockListJobStream struct{ handler listJobStreamFunc }
 type mockFlushJob struct{ handler flushJobFunc }
 type mockDeleteJob struct{ handler deleteJobFunc }
 type mockStopJob struct{ handler stopJobFunc }
+type mockScaleJob struct{ handler scaleJobFunc }
 type mockUpdateJobState struct{ handler updateJobStateFunc }
 type mockInspectDatum struct{ handler inspectDatumFunc }
 type mockListDatum struct{ handler listDatumFunc }
 type mockListDatumStream struct{ handler listDatumStreamFunc }
 type mockRestartDatum struct{ handler restartDatumFunc }
+type mockResolveDatum struct{ handler resolveDatumFunc }
+type mockExplainDatum struct{ handler explainDatumFunc }
 type mockCreatePipeline struct{ handler createPipelineFunc }
 type mockInspectPipeline struct{ handler inspectPipelineFunc }
 type mockListPipeline struct{ handler listPipelineFunc }
+type mockWatchPipelines struct{ handler watchPipelinesFunc }
 type mockDeletePipeline struct{ handler deletePipelineFunc }
 type mockStartPipeline struct{ handler startPipelineFunc }
 type mockStopPipeline struct{ handler stopPipelineFunc }
 type mockRunPipeline struct{ handler runPipelineFunc }
 type mockRunCron struct{ handler runCronFunc }
+type mockApplyDAG struct{ handler applyDAGFunc }
+type mockCreatePipelines struct{ handler createPipelinesFunc }
+type mockListIdlePipelines struct{ handler listIdlePipelinesFunc }
+type mockPruneStats struct{ handler pruneStatsFunc }
+type mockCheckPipelineUpdate struct{ handler checkPipelineUpdateFunc }
+type mockDiffPipeline struct{ handler diffPipelineFunc }
+type mockGetMountCredentials struct{ handler getMountCredentialsFunc }
+type mockGetScheduler struct{ handler getSchedulerFunc }
+type mockApproveCommit struct{ handler approveCommitFunc }
+type mockUpdatePipelineConfig struct{ handler updatePipelineConfigFunc }
+type mockListGitHooks struct{ handler listGitHooksFunc }
+type mockInspectGitHook struct{ handler inspectGitHookFunc }
+type mockReplayGitHookDelivery struct{ handler replayGitHookDeliveryFunc }
 type mockCreateSecret struct{ handler createSecretFunc }
 type mockDeleteSecret struct{ handler deleteSecretFunc }
 type mockInspectSecret struct{ handler inspectSecretFunc }
@@ -829,69 +1202,103 @@ type mockGetLogs struct{ handler getLogsFunc }
 type mockGarbageCollect struct{ handler garbageCollectFunc }
 type mockActivateAuthPPS struct{ handler activateAuthPPSFunc }
 
-func (mock *mockCreateJob) Use(cb createJobFunc)             { mock.handler = cb }
-func (mock *mockInspectJob) Use(cb inspectJobFunc)           { mock.handler = cb }
-func (mock *mockListJob) Use(cb listJobFunc)                 { mock.handler = cb }
-func (mock *mockListJobStream) Use(cb listJobStreamFunc)     { mock.handler = cb }
-func (mock *mockFlushJob) Use(cb flushJobFunc)               { mock.handler = cb }
-func (mock *mockDeleteJob) Use(cb deleteJobFunc)             { mock.handler = cb }
-func (mock *mockStopJob) Use(cb stopJobFunc)                 { mock.handler = cb }
-func (mock *mockUpdateJobState) Use(cb updateJobStateFunc)   { mock.handler = cb }
-func (mock *mockInspectDatum) Use(cb inspectDatumFunc)       { mock.handler = cb }
-func (mock *mockListDatum) Use(cb listDatumFunc)             { mock.handler = cb }
-func (mock *mockListDatumStream) Use(cb listDatumStreamFunc) { mock.handler = cb }
-func (mock *mockRestartDatum) Use(cb restartDatumFunc)       { mock.handler = cb }
-func (mock *mockCreatePipeline) Use(cb createPipelineFunc)   { mock.handler = cb }
-func (mock *mockInspectPipeline) Use(cb inspectPipelineFunc) { mock.handler = cb }
-func (mock *mockListPipeline) Use(cb listPipelineFunc)       { mock.handler = cb }
-func (mock *mockDeletePipeline) Use(cb deletePipelineFunc)   { mock.handler = cb }
-func (mock *mockStartPipeline) Use(cb startPipelineFunc)     { mock.handler = cb }
-func (mock *mockStopPipeline) Use(cb stopPipelineFunc)       { mock.handler = cb }
-func (mock *mockRunPipeline) Use(cb runPipelineFunc)         { mock.handler = cb }
-func (mock *mockRunCron) Use(cb runCronFunc)                 { mock.handler = cb }
-func (mock *mockCreateSecret) Use(cb createSecretFunc)       { mock.handler = cb }
-func (mock *mockDeleteSecret) Use(cb deleteSecretFunc)       { mock.handler = cb }
-func (mock *mockInspectSecret) Use(cb inspectSecretFunc)     { mock.handler = cb }
-func (mock *mockListSecret) Use(cb listSecretFunc)           { mock.handler = cb }
-func (mock *mockDeleteAllPPS) Use(cb deleteAllPPSFunc)       { mock.handler = cb }
-func (mock *mockGetLogs) Use(cb getLogsFunc)                 { mock.handler = cb }
-func (mock *mockGarbageCollect) Use(cb garbageCollectFunc)   { mock.handler = cb }
-func (mock *mockActivateAuthPPS) Use(cb activateAuthPPSFunc) { mock.handler = cb }
+func (mock *mockCreateJob) Use(cb createJobFunc)                         { mock.handler = cb }
+func (mock *mockInspectJob) Use(cb inspectJobFunc)                       { mock.handler = cb }
+func (mock *mockListJob) Use(cb listJobFunc)                             { mock.handler = cb }
+func (mock *mockListJobStream) Use(cb listJobStreamFunc)                 { mock.handler = cb }
+func (mock *mockFlushJob) Use(cb flushJobFunc)                           { mock.handler = cb }
+func (mock *mockDeleteJob) Use(cb deleteJobFunc)                         { mock.handler = cb }
+func (mock *mockStopJob) Use(cb stopJobFunc)                             { mock.handler = cb }
+func (mock *mockScaleJob) Use(cb scaleJobFunc)                           { mock.handler = cb }
+func (mock *mockUpdateJobState) Use(cb updateJobStateFunc)               { mock.handler = cb }
+func (mock *mockInspectDatum) Use(cb inspectDatumFunc)                   { mock.handler = cb }
+func (mock *mockListDatum) Use(cb listDatumFunc)                         { mock.handler = cb }
+func (mock *mockListDatumStream) Use(cb listDatumStreamFunc)             { mock.handler = cb }
+func (mock *mockRestartDatum) Use(cb restartDatumFunc)                   { mock.handler = cb }
+func (mock *mockResolveDatum) Use(cb resolveDatumFunc)                   { mock.handler = cb }
+func (mock *mockExplainDatum) Use(cb explainDatumFunc)                   { mock.handler = cb }
+func (mock *mockCreatePipeline) Use(cb createPipelineFunc)               { mock.handler = cb }
+func (mock *mockInspectPipeline) Use(cb inspectPipelineFunc)             { mock.handler = cb }
+func (mock *mockListPipeline) Use(cb listPipelineFunc)                   { mock.handler = cb }
+func (mock *mockWatchPipelines) Use(cb watchPipelinesFunc)               { mock.handler = cb }
+func (mock *mockDeletePipeline) Use(cb deletePipelineFunc)               { mock.handler = cb }
+func (mock *mockStartPipeline) Use(cb startPipelineFunc)                 { mock.handler = cb }
+func (mock *mockStopPipeline) Use(cb stopPipelineFunc)                   { mock.handler = cb }
+func (mock *mockRunPipeline) Use(cb runPipelineFunc)                     { mock.handler = cb }
+func (mock *mockRunCron) Use(cb runCronFunc)                             { mock.handler = cb }
+func (mock *mockApplyDAG) Use(cb applyDAGFunc)                           { mock.handler = cb }
+func (mock *mockCreatePipelines) Use(cb createPipelinesFunc)             { mock.handler = cb }
+func (mock *mockListIdlePipelines) Use(cb listIdlePipelinesFunc)         { mock.handler = cb }
+func (mock *mockPruneStats) Use(cb pruneStatsFunc)                       { mock.handler = cb }
+func (mock *mockCheckPipelineUpdate) Use(cb checkPipelineUpdateFunc)     { mock.handler = cb }
+func (mock *mockDiffPipeline) Use(cb diffPipelineFunc)                   { mock.handler = cb }
+func (mock *mockGetMountCredentials) Use(cb getMountCredentialsFunc)     { mock.handler = cb }
+func (mock *mockGetScheduler) Use(cb getSchedulerFunc)                   { mock.handler = cb }
+func (mock *mockApproveCommit) Use(cb approveCommitFunc)                 { mock.handler = cb }
+func (mock *mockUpdatePipelineConfig) Use(cb updatePipelineConfigFunc)   { mock.handler = cb }
+func (mock *mockListGitHooks) Use(cb listGitHooksFunc)                   { mock.handler = cb }
+func (mock *mockInspectGitHook) Use(cb inspectGitHookFunc)               { mock.handler = cb }
+func (mock *mockReplayGitHookDelivery) Use(cb replayGitHookDeliveryFunc) { mock.handler = cb }
+func (mock *mockCreateSecret) Use(cb createSecretFunc)                   { mock.handler = cb }
+func (mock *mockDeleteSecret) Use(cb deleteSecretFunc)                   { mock.handler = cb }
+func (mock *mockInspectSecret) Use(cb inspectSecretFunc)                 { mock.handler = cb }
+func (mock *mockListSecret) Use(cb listSecretFunc)                       { mock.handler = cb }
+func (mock *mockDeleteAllPPS) Use(cb deleteAllPPSFunc)                   { mock.handler = cb }
+func (mock *mockGetLogs) Use(cb getLogsFunc)                             { mock.handler = cb }
+func (mock *mockGarbageCollect) Use(cb garbageCollectFunc)               { mock.handler = cb }
+func (mock *mockActivateAuthPPS) Use(cb activateAuthPPSFunc)             { mock.handler = cb }
 
 type ppsServerAPI struct {
 	mock *mockPPSServer
 }
 
 type mockPPSServer struct {
-	api             ppsServerAPI
-	CreateJob       mockCreateJob
-	InspectJob      mockInspectJob
-	ListJob         mockListJob
-	ListJobStream   mockListJobStream
-	FlushJob        mockFlushJob
-	DeleteJob       mockDeleteJob
-	StopJob         mockStopJob
-	UpdateJobState  mockUpdateJobState
-	InspectDatum    mockInspectDatum
-	ListDatum       mockListDatum
-	ListDatumStream mockListDatumStream
-	RestartDatum    mockRestartDatum
-	CreatePipeline  mockCreatePipeline
-	InspectPipeline mockInspectPipeline
-	ListPipeline    mockListPipeline
-	DeletePipeline  mockDeletePipeline
-	StartPipeline   mockStartPipeline
-	StopPipeline    mockStopPipeline
-	RunPipeline     mockRunPipeline
-	RunCron         mockRunCron
-	CreateSecret    mockCreateSecret
-	DeleteSecret    mockDeleteSecret
-	InspectSecret   mockInspectSecret
-	ListSecret      mockListSecret
-	DeleteAll       mockDeleteAllPPS
-	GetLogs         mockGetLogs
-	GarbageCollect  mockGarbageCollect
-	ActivateAuth    mockActivateAuthPPS
+	api                   ppsServerAPI
+	CreateJob             mockCreateJob
+	InspectJob            mockInspectJob
+	ListJob               mockListJob
+	ListJobStream         mockListJobStream
+	FlushJob              mockFlushJob
+	DeleteJob             mockDeleteJob
+	StopJob               mockStopJob
+	ScaleJob              mockScaleJob
+	UpdateJobState        mockUpdateJobState
+	InspectDatum          mockInspectDatum
+	ListDatum             mockListDatum
+	ListDatumStream       mockListDatumStream
+	RestartDatum          mockRestartDatum
+	ResolveDatum          mockResolveDatum
+	ExplainDatum          mockExplainDatum
+	CreatePipeline        mockCreatePipeline
+	InspectPipeline       mockInspectPipeline
+	ListPipeline          mockListPipeline
+	WatchPipelines        mockWatchPipelines
+	DeletePipeline        mockDeletePipeline
+	StartPipeline         mockStartPipeline
+	StopPipeline          mockStopPipeline
+	RunPipeline           mockRunPipeline
+	RunCron               mockRunCron
+	ApplyDAG              mockApplyDAG
+	CreatePipelines       mockCreatePipelines
+	ListIdlePipelines     mockListIdlePipelines
+	PruneStats            mockPruneStats
+	CheckPipelineUpdate   mockCheckPipelineUpdate
+	DiffPipeline          mockDiffPipeline
+	GetMountCredentials   mockGetMountCredentials
+	GetScheduler          mockGetScheduler
+	ApproveCommit         mockApproveCommit
+	UpdatePipelineConfig  mockUpdatePipelineConfig
+	ListGitHooks          mockListGitHooks
+	InspectGitHook        mockInspectGitHook
+	ReplayGitHookDelivery mockReplayGitHookDelivery
+	CreateSecret          mockCreateSecret
+	DeleteSecret          mockDeleteSecret
+	InspectSecret         mockInspectSecret
+	ListSecret            mockListSecret
+	DeleteAll             mockDeleteAllPPS
+	GetLogs               mockGetLogs
+	GarbageCollect        mockGarbageCollect
+	ActivateAuth          mockActivateAuthPPS
 }
 
 func (api *ppsServerAPI) CreateJob(ctx context.Context, req *pps.CreateJobRequest) (*pps.Job, error) {
@@ -942,6 +1349,13 @@ func (api *ppsServerAPI) StopJob(ctx context.Context, req *pps.StopJobRequest) (
 	}
 	return nil, errors.Errorf("unhandled pachd mock pps.StopJob")
 }
+
+func (api *ppsServerAPI) ScaleJob(ctx context.Context, req *pps.ScaleJobRequest) (*types.Empty, error) {
+	if api.mock.ScaleJob.handler != nil {
+		return api.mock.ScaleJob.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pps.ScaleJob")
+}
 func (api *ppsServerAPI) InspectDatum(ctx context.Context, req *pps.InspectDatumRequest) (*pps.DatumInfo, error) {
 	if api.mock.InspectDatum.handler != nil {
 		return api.mock.InspectDatum.handler(ctx, req)
@@ -966,6 +1380,18 @@ func (api *ppsServerAPI) RestartDatum(ctx context.Context, req *pps.RestartDatum
 	}
 	return nil, errors.Errorf("unhandled pachd mock pps.RestartDatum")
 }
+func (api *ppsServerAPI) ResolveDatum(ctx context.Context, req *pps.ResolveDatumRequest) (*pps.ResolveDatumResponse, error) {
+	if api.mock.ResolveDatum.handler != nil {
+		return api.mock.ResolveDatum.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pps.ResolveDatum")
+}
+func (api *ppsServerAPI) ExplainDatum(ctx context.Context, req *pps.ExplainDatumRequest) (*pps.ExplainDatumResponse, error) {
+	if api.mock.ExplainDatum.handler != nil {
+		return api.mock.ExplainDatum.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pps.ExplainDatum")
+}
 func (api *ppsServerAPI) CreatePipeline(ctx context.Context, req *pps.CreatePipelineRequest) (*types.Empty, error) {
 	if api.mock.CreatePipeline.handler != nil {
 		return api.mock.CreatePipeline.handler(ctx, req)
@@ -984,6 +1410,13 @@ func (api *ppsServerAPI) ListPipeline(ctx context.Context, req *pps.ListPipeline
 	}
 	return nil, errors.Errorf("unhandled pachd mock pps.ListPipeline")
 }
+
+func (api *ppsServerAPI) WatchPipelines(req *pps.WatchPipelinesRequest, serv pps.API_WatchPipelinesServer) error {
+	if api.mock.WatchPipelines.handler != nil {
+		return api.mock.WatchPipelines.handler(req, serv)
+	}
+	return errors.Errorf("unhandled pachd mock pps.WatchPipelines")
+}
 func (api *ppsServerAPI) DeletePipeline(ctx context.Context, req *pps.DeletePipelineRequest) (*types.Empty, error) {
 	if api.mock.DeletePipeline.handler != nil {
 		return api.mock.DeletePipeline.handler(ctx, req)
@@ -1014,6 +1447,89 @@ func (api *ppsServerAPI) RunCron(ctx context.Context, req *pps.RunCronRequest) (
 	}
 	return nil, errors.Errorf("unhandled pachd mock pps.RunCron")
 }
+func (api *ppsServerAPI) ApplyDAG(ctx context.Context, req *pps.ApplyDAGRequest) (*pps.ApplyDAGResponse, error) {
+	if api.mock.ApplyDAG.handler != nil {
+		return api.mock.ApplyDAG.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pps.ApplyDAG")
+}
+
+func (api *ppsServerAPI) CreatePipelines(ctx context.Context, req *pps.CreatePipelinesRequest) (*pps.CreatePipelinesResponse, error) {
+	if api.mock.CreatePipelines.handler != nil {
+		return api.mock.CreatePipelines.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pps.CreatePipelines")
+}
+func (api *ppsServerAPI) ListIdlePipelines(ctx context.Context, req *pps.ListIdlePipelinesRequest) (*pps.ListIdlePipelinesResponse, error) {
+	if api.mock.ListIdlePipelines.handler != nil {
+		return api.mock.ListIdlePipelines.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pps.ListIdlePipelines")
+}
+func (api *ppsServerAPI) PruneStats(ctx context.Context, req *pps.PruneStatsRequest) (*pps.PruneStatsResponse, error) {
+	if api.mock.PruneStats.handler != nil {
+		return api.mock.PruneStats.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pps.PruneStats")
+}
+func (api *ppsServerAPI) CheckPipelineUpdate(ctx context.Context, req *pps.CheckPipelineUpdateRequest) (*pps.CheckPipelineUpdateResponse, error) {
+	if api.mock.CheckPipelineUpdate.handler != nil {
+		return api.mock.CheckPipelineUpdate.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pps.CheckPipelineUpdate")
+}
+
+func (api *ppsServerAPI) DiffPipeline(ctx context.Context, req *pps.DiffPipelineRequest) (*pps.DiffPipelineResponse, error) {
+	if api.mock.DiffPipeline.handler != nil {
+		return api.mock.DiffPipeline.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pps.DiffPipeline")
+}
+func (api *ppsServerAPI) GetMountCredentials(ctx context.Context, req *pps.GetMountCredentialsRequest) (*pps.MountCredentials, error) {
+	if api.mock.GetMountCredentials.handler != nil {
+		return api.mock.GetMountCredentials.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pps.GetMountCredentials")
+}
+func (api *ppsServerAPI) GetScheduler(ctx context.Context, req *pps.GetSchedulerRequest) (*pps.GetSchedulerResponse, error) {
+	if api.mock.GetScheduler.handler != nil {
+		return api.mock.GetScheduler.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pps.GetScheduler")
+}
+func (api *ppsServerAPI) ApproveCommit(ctx context.Context, req *pps.ApproveCommitRequest) (*types.Empty, error) {
+	if api.mock.ApproveCommit.handler != nil {
+		return api.mock.ApproveCommit.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pps.ApproveCommit")
+}
+func (api *ppsServerAPI) UpdatePipelineConfig(ctx context.Context, req *pps.UpdatePipelineConfigRequest) (*types.Empty, error) {
+	if api.mock.UpdatePipelineConfig.handler != nil {
+		return api.mock.UpdatePipelineConfig.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pps.UpdatePipelineConfig")
+}
+
+func (api *ppsServerAPI) ListGitHooks(ctx context.Context, req *pps.ListGitHooksRequest) (*pps.GitHookInfos, error) {
+	if api.mock.ListGitHooks.handler != nil {
+		return api.mock.ListGitHooks.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pps.ListGitHooks")
+}
+
+func (api *ppsServerAPI) InspectGitHook(ctx context.Context, req *pps.InspectGitHookRequest) (*pps.GitHookInfo, error) {
+	if api.mock.InspectGitHook.handler != nil {
+		return api.mock.InspectGitHook.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pps.InspectGitHook")
+}
+
+func (api *ppsServerAPI) ReplayGitHookDelivery(ctx context.Context, req *pps.ReplayGitHookDeliveryRequest) (*pps.GitHookDelivery, error) {
+	if api.mock.ReplayGitHookDelivery.handler != nil {
+		return api.mock.ReplayGitHookDelivery.handler(ctx, req)
+	}
+	return nil, errors.Errorf("unhandled pachd mock pps.ReplayGitHookDelivery")
+}
 func (api *ppsServerAPI) CreateSecret(ctx context.Context, req *pps.CreateSecretRequest) (*types.Empty, error) {
 	if api.mock.CreateSecret.handler != nil {
 		return api.mock.CreateSecret.handler(ctx, req)
@@ -84,6 +84,8 @@ func WithRealEnv(cb func(*RealEnv) error, customConfig ...*serviceenv.PachdFullC
 			pfsserver.LocalBackendEnvVar,
 			net.JoinHostPort(config.EtcdHost, config.EtcdPort),
 			true, // duplicate
+			"",   // residencyBuckets
+			nil,  // replicas
 		)
 		if err != nil {
 			return err
@@ -0,0 +1,274 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+)
+
+// schema is a compiled JSON Schema. Only the keywords that describe the shape
+// of data are supported: type, enum, const, properties, required,
+// additionalProperties, items, minItems, maxItems, minimum, maximum,
+// minLength, maxLength and pattern. Other keywords are ignored, as they are
+// by JSON Schema validators that don't know them.
+type schema struct {
+	types                []string
+	enum                 []interface{}
+	constant             *interface{}
+	properties           map[string]*schema
+	required             []string
+	additionalProperties *schema // nil if any are allowed
+	noAdditional         bool
+	items                *schema
+	minItems, maxItems   *float64
+	minimum, maximum     *float64
+	minLength, maxLength *float64
+	pattern              *regexp.Regexp
+}
+
+var schemaTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true,
+	"integer": true, "boolean": true, "null": true,
+}
+
+func compileSchema(raw string) (*schema, error) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		return nil, errors.Wrapf(err, "could not parse JSON schema")
+	}
+	return parseSchema(v, "")
+}
+
+func parseSchema(v interface{}, at string) (*schema, error) {
+	if b, ok := v.(bool); ok {
+		// 'true' allows anything and 'false' allows nothing
+		if b {
+			return &schema{}, nil
+		}
+		return &schema{enum: []interface{}{}}, nil
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("schema%s must be an object", at)
+	}
+	s := &schema{}
+	switch t := m["type"].(type) {
+	case nil:
+	case string:
+		s.types = []string{t}
+	case []interface{}:
+		for _, x := range t {
+			name, ok := x.(string)
+			if !ok {
+				return nil, errors.Errorf("schema%s has a non-string type", at)
+			}
+			s.types = append(s.types, name)
+		}
+	default:
+		return nil, errors.Errorf("schema%s has an invalid type", at)
+	}
+	for _, t := range s.types {
+		if !schemaTypes[t] {
+			return nil, errors.Errorf("schema%s has unknown type %q", at, t)
+		}
+	}
+	if enum, ok := m["enum"]; ok {
+		values, ok := enum.([]interface{})
+		if !ok {
+			return nil, errors.Errorf("schema%s has an enum that isn't an array", at)
+		}
+		s.enum = values
+	}
+	if c, ok := m["const"]; ok {
+		s.constant = &c
+	}
+	if properties, ok := m["properties"]; ok {
+		pm, ok := properties.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("schema%s has properties that aren't an object", at)
+		}
+		s.properties = make(map[string]*schema)
+		for name, p := range pm {
+			ps, err := parseSchema(p, at+"."+name)
+			if err != nil {
+				return nil, err
+			}
+			s.properties[name] = ps
+		}
+	}
+	if required, ok := m["required"]; ok {
+		names, ok := required.([]interface{})
+		if !ok {
+			return nil, errors.Errorf("schema%s has a required list that isn't an array", at)
+		}
+		for _, n := range names {
+			name, ok := n.(string)
+			if !ok {
+				return nil, errors.Errorf("schema%s has a required property that isn't a string", at)
+			}
+			s.required = append(s.required, name)
+		}
+	}
+	switch ap := m["additionalProperties"].(type) {
+	case nil:
+	case bool:
+		s.noAdditional = !ap
+	default:
+		aps, err := parseSchema(ap, at+".additionalProperties")
+		if err != nil {
+			return nil, err
+		}
+		s.additionalProperties = aps
+	}
+	if items, ok := m["items"]; ok {
+		is, err := parseSchema(items, at+"[]")
+		if err != nil {
+			return nil, err
+		}
+		s.items = is
+	}
+	for keyword, dst := range map[string]**float64{
+		"minItems": &s.minItems, "maxItems": &s.maxItems,
+		"minimum": &s.minimum, "maximum": &s.maximum,
+		"minLength": &s.minLength, "maxLength": &s.maxLength,
+	} {
+		if x, ok := m[keyword]; ok {
+			f, ok := x.(float64)
+			if !ok {
+				return nil, errors.Errorf("schema%s has a %s that isn't a number", at, keyword)
+			}
+			*dst = &f
+		}
+	}
+	if p, ok := m["pattern"]; ok {
+		ps, ok := p.(string)
+		if !ok {
+			return nil, errors.Errorf("schema%s has a pattern that isn't a string", at)
+		}
+		re, err := regexp.Compile(ps)
+		if err != nil {
+			return nil, errors.Wrapf(err, "schema%s has an invalid pattern", at)
+		}
+		s.pattern = re
+	}
+	return s, nil
+}
+
+func typeOf(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if x == math.Trunc(x) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
+
+// validate returns an error describing the first way in which 'v' doesn't
+// conform to 's'. 'at' is the location of 'v' in the document, for errors.
+func (s *schema) validate(v interface{}, at string) error {
+	where := at
+	if where == "" {
+		where = "document"
+	}
+	if len(s.types) > 0 {
+		t := typeOf(v)
+		var ok bool
+		for _, want := range s.types {
+			if want == t || (want == "number" && t == "integer") {
+				ok = true
+			}
+		}
+		if !ok {
+			return errors.Errorf("%s is a %s, not %v", where, t, s.types)
+		}
+	}
+	if s.enum != nil {
+		var ok bool
+		for _, e := range s.enum {
+			if reflect.DeepEqual(e, v) {
+				ok = true
+			}
+		}
+		if !ok {
+			return errors.Errorf("%s is not one of %v", where, s.enum)
+		}
+	}
+	if s.constant != nil && !reflect.DeepEqual(*s.constant, v) {
+		return errors.Errorf("%s is not %v", where, *s.constant)
+	}
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for _, name := range s.required {
+			if _, ok := x[name]; !ok {
+				return errors.Errorf("%s is missing required property %q", where, name)
+			}
+		}
+		names := make([]string, 0, len(x))
+		for name := range x {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if ps, ok := s.properties[name]; ok {
+				if err := ps.validate(x[name], at+"."+name); err != nil {
+					return err
+				}
+			} else if s.noAdditional {
+				return errors.Errorf("%s has unexpected property %q", where, name)
+			} else if s.additionalProperties != nil {
+				if err := s.additionalProperties.validate(x[name], at+"."+name); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		if s.minItems != nil && float64(len(x)) < *s.minItems {
+			return errors.Errorf("%s has fewer than %v items", where, *s.minItems)
+		}
+		if s.maxItems != nil && float64(len(x)) > *s.maxItems {
+			return errors.Errorf("%s has more than %v items", where, *s.maxItems)
+		}
+		if s.items != nil {
+			for i, item := range x {
+				if err := s.items.validate(item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case float64:
+		if s.minimum != nil && x < *s.minimum {
+			return errors.Errorf("%s is less than %v", where, *s.minimum)
+		}
+		if s.maximum != nil && x > *s.maximum {
+			return errors.Errorf("%s is greater than %v", where, *s.maximum)
+		}
+	case string:
+		n := float64(utf8.RuneCountInString(x))
+		if s.minLength != nil && n < *s.minLength {
+			return errors.Errorf("%s is shorter than %v characters", where, *s.minLength)
+		}
+		if s.maxLength != nil && n > *s.maxLength {
+			return errors.Errorf("%s is longer than %v characters", where, *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(x) {
+			return errors.Errorf("%s does not match %q", where, s.pattern.String())
+		}
+	}
+	return nil
+}
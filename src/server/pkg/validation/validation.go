@@ -0,0 +1,351 @@
+// Package validation implements the assertions of validation pipelines (see
+// pps.Validation), which check a pipeline's input without running user code.
+package validation
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	globlib "github.com/pachyderm/ohmyglob"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// ReportFile is the path, in a validation pipeline's output commits, of the
+// report of its assertions' results
+const ReportFile = "/report.json"
+
+// maxFailures is the number of failures that the report lists per assertion
+const maxFailures = 10
+
+// Report is the result of a validation pipeline's assertions
+type Report struct {
+	Passed     bool               `json:"passed"`
+	Assertions []*AssertionResult `json:"assertions"`
+}
+
+// AssertionResult is the result of one assertion
+type AssertionResult struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message"`
+	// Failures lists (some of) the files or records that failed the assertion
+	Failures []string `json:"failures,omitempty"`
+}
+
+// Failed returns the number of assertions in 'r' that failed
+func (r *Report) Failed() int {
+	var failed int
+	for _, a := range r.Assertions {
+		if !a.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+func (a *AssertionResult) fail(format string, args ...interface{}) {
+	a.Passed = false
+	if len(a.Failures) < maxFailures {
+		a.Failures = append(a.Failures, fmt.Sprintf(format, args...))
+	}
+}
+
+// ValidateSpec returns an error if 'validation' isn't a valid validation spec
+func ValidateSpec(validation *pps.Validation) error {
+	if len(validation.Assertions) == 0 {
+		return errors.New("a validation must have at least one assertion")
+	}
+	names := make(map[string]bool)
+	for _, assertion := range validation.Assertions {
+		if assertion.Name == "" {
+			return errors.New("every assertion must have a name")
+		}
+		if names[assertion.Name] {
+			return errors.Errorf("there's more than one assertion named %q", assertion.Name)
+		}
+		names[assertion.Name] = true
+		if _, err := compileGlob(assertion.Glob); err != nil {
+			return errors.Wrapf(err, "invalid glob in assertion %q", assertion.Name)
+		}
+		var kinds int
+		if assertion.FileCount != nil {
+			kinds++
+			if assertion.FileCount.Min < 0 || assertion.FileCount.Max < 0 ||
+				(assertion.FileCount.Max > 0 && assertion.FileCount.Max < assertion.FileCount.Min) {
+				return errors.Errorf("invalid file count bounds in assertion %q", assertion.Name)
+			}
+		}
+		if assertion.JsonSchema != "" {
+			kinds++
+			if _, err := compileSchema(assertion.JsonSchema); err != nil {
+				return errors.Wrapf(err, "invalid JSON schema in assertion %q", assertion.Name)
+			}
+		}
+		if assertion.NullRate != nil {
+			kinds++
+			if assertion.NullRate.Column == "" {
+				return errors.Errorf("null rate assertion %q must name a column", assertion.Name)
+			}
+			if assertion.NullRate.MaxRate < 0 || assertion.NullRate.MaxRate > 1 {
+				return errors.Errorf("the max rate of null rate assertion %q must be between 0 and 1", assertion.Name)
+			}
+		}
+		if kinds != 1 {
+			return errors.Errorf("assertion %q must set exactly one of file_count, json_schema and null_rate", assertion.Name)
+		}
+	}
+	return nil
+}
+
+func compileGlob(glob string) (*globlib.Glob, error) {
+	if glob == "" {
+		glob = "/**"
+	}
+	return globlib.Compile(path.Clean("/"+glob), '/')
+}
+
+// Validate checks the assertions of 'validation' against the files under
+// 'root', and returns a report of the results. Errors reading the files are
+// returned, rather than reported as failures, so that the datum is retried.
+func Validate(validation *pps.Validation, root string) (*Report, error) {
+	files, err := listFiles(root)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{Passed: true}
+	for _, assertion := range validation.Assertions {
+		g, err := compileGlob(assertion.Glob)
+		if err != nil {
+			return nil, errors.EnsureStack(err)
+		}
+		var matched []string
+		for _, f := range files {
+			if g.Match(f) {
+				matched = append(matched, f)
+			}
+		}
+		result := &AssertionResult{Name: assertion.Name, Passed: true}
+		switch {
+		case assertion.FileCount != nil:
+			checkFileCount(result, assertion.FileCount, matched)
+		case assertion.JsonSchema != "":
+			err = checkJSONSchema(result, assertion.JsonSchema, root, matched)
+		case assertion.NullRate != nil:
+			err = checkNullRate(result, assertion.NullRate, root, matched)
+		}
+		if err != nil {
+			return nil, err
+		}
+		report.Passed = report.Passed && result.Passed
+		report.Assertions = append(report.Assertions, result)
+	}
+	return report, nil
+}
+
+// listFiles returns the paths, relative to 'root' (with a leading slash), of
+// the regular files under 'root'. Inputs are symlinked into the worker's
+// input directory, so links are followed.
+func listFiles(root string) ([]string, error) {
+	var result []string
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		f, err := os.Open(filepath.Join(root, dir))
+		if err != nil {
+			return errors.EnsureStack(err)
+		}
+		names, err := f.Readdirnames(-1)
+		f.Close()
+		if err != nil {
+			return errors.EnsureStack(err)
+		}
+		for _, name := range names {
+			p := path.Join(dir, name)
+			info, err := os.Stat(filepath.Join(root, p))
+			if err != nil {
+				return errors.EnsureStack(err)
+			}
+			if info.IsDir() {
+				if err := walk(p); err != nil {
+					return err
+				}
+			} else if info.Mode().IsRegular() {
+				result = append(result, p)
+			}
+		}
+		return nil
+	}
+	if err := walk("/"); err != nil {
+		return nil, err
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func checkFileCount(result *AssertionResult, count *pps.FileCountAssertion, files []string) {
+	n := int64(len(files))
+	result.Message = fmt.Sprintf("%d matching files", n)
+	if n < count.Min {
+		result.fail("expected at least %d files", count.Min)
+	}
+	if count.Max > 0 && n > count.Max {
+		result.fail("expected at most %d files", count.Max)
+	}
+}
+
+// isJSONLines returns true if each line of the file at 'p' is a JSON document
+func isJSONLines(p string) bool {
+	ext := strings.ToLower(path.Ext(p))
+	return ext == ".jsonl" || ext == ".ndjson"
+}
+
+// forEachLine calls 'f' with each non-empty line of the file at 'p', and its
+// line number
+func forEachLine(p string, f func(line []byte, n int)) error {
+	file, err := os.Open(p)
+	if err != nil {
+		return errors.EnsureStack(err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if line := scanner.Bytes(); len(strings.TrimSpace(string(line))) > 0 {
+			f(line, n)
+		}
+	}
+	return errors.EnsureStack(scanner.Err())
+}
+
+func checkJSONSchema(result *AssertionResult, rawSchema, root string, files []string) error {
+	s, err := compileSchema(rawSchema)
+	if err != nil {
+		return err
+	}
+	var documents, invalid int
+	check := func(data []byte, where string) {
+		documents++
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			invalid++
+			result.fail("%s: invalid JSON: %v", where, err)
+			return
+		}
+		if err := s.validate(v, ""); err != nil {
+			invalid++
+			result.fail("%s: %v", where, err)
+		}
+	}
+	for _, f := range files {
+		p := filepath.Join(root, f)
+		if isJSONLines(f) {
+			if err := forEachLine(p, func(line []byte, n int) {
+				check(line, fmt.Sprintf("%s:%d", f, n))
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			return errors.EnsureStack(err)
+		}
+		check(data, f)
+	}
+	result.Message = fmt.Sprintf("%d of %d documents don't conform to the schema", invalid, documents)
+	return nil
+}
+
+// isNull returns true if a CSV field counts as null
+func isNull(field string) bool {
+	switch strings.ToLower(strings.TrimSpace(field)) {
+	case "", "null", "na", "nan":
+		return true
+	}
+	return false
+}
+
+func checkNullRate(result *AssertionResult, nullRate *pps.NullRateAssertion, root string, files []string) error {
+	var records, nulls int64
+	for _, f := range files {
+		p := filepath.Join(root, f)
+		switch {
+		case isJSONLines(f):
+			if err := forEachLine(p, func(line []byte, n int) {
+				records++
+				var record map[string]interface{}
+				if err := json.Unmarshal(line, &record); err != nil {
+					result.fail("%s:%d: not a JSON object", f, n)
+					return
+				}
+				if v, ok := record[nullRate.Column]; !ok || v == nil || v == "" {
+					nulls++
+				}
+			}); err != nil {
+				return err
+			}
+		case strings.ToLower(path.Ext(f)) == ".csv":
+			if err := countCSVNulls(result, nullRate.Column, p, f, &records, &nulls); err != nil {
+				return err
+			}
+		}
+	}
+	var rate float64
+	if records > 0 {
+		rate = float64(nulls) / float64(records)
+	}
+	result.Message = fmt.Sprintf("%q is null in %d of %d records (%.4f, max %.4f)", nullRate.Column, nulls, records, rate, nullRate.MaxRate)
+	if rate > nullRate.MaxRate {
+		result.fail("the null rate of %q is %.4f, which exceeds %.4f", nullRate.Column, rate, nullRate.MaxRate)
+	}
+	return nil
+}
+
+func countCSVNulls(result *AssertionResult, column, p, name string, records, nulls *int64) error {
+	file, err := os.Open(p)
+	if err != nil {
+		return errors.EnsureStack(err)
+	}
+	defer file.Close()
+	r := csv.NewReader(file)
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	} else if err != nil {
+		result.fail("%s: %v", name, err)
+		return nil
+	}
+	index := -1
+	for i, h := range header {
+		if h == column {
+			index = i
+		}
+	}
+	if index < 0 {
+		result.fail("%s: has no column %q", name, column)
+		return nil
+	}
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			result.fail("%s: %v", name, err)
+			return nil
+		}
+		*records++
+		if index >= len(row) || isNull(row[index]) {
+			*nulls++
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package validation
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	root, err := ioutil.TempDir("", "validation")
+	require.NoError(t, err)
+	for name, content := range files {
+		p := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, ioutil.WriteFile(p, []byte(content), 0644))
+	}
+	return root
+}
+
+func TestValidateSpec(t *testing.T) {
+	require.YesError(t, ValidateSpec(&pps.Validation{}))
+	require.NoError(t, ValidateSpec(&pps.Validation{Assertions: []*pps.Assertion{
+		{Name: "count", FileCount: &pps.FileCountAssertion{Min: 1}},
+	}}))
+	// Exactly one kind of assertion
+	require.YesError(t, ValidateSpec(&pps.Validation{Assertions: []*pps.Assertion{
+		{Name: "both", FileCount: &pps.FileCountAssertion{Min: 1}, JsonSchema: `{}`},
+	}}))
+	require.YesError(t, ValidateSpec(&pps.Validation{Assertions: []*pps.Assertion{
+		{Name: "schema", JsonSchema: `{"type": "strange"}`},
+	}}))
+	require.YesError(t, ValidateSpec(&pps.Validation{Assertions: []*pps.Assertion{
+		{Name: "nulls", NullRate: &pps.NullRateAssertion{Column: "a", MaxRate: 2}},
+	}}))
+	require.YesError(t, ValidateSpec(&pps.Validation{Assertions: []*pps.Assertion{
+		{Name: "dup", FileCount: &pps.FileCountAssertion{Min: 1}},
+		{Name: "dup", FileCount: &pps.FileCountAssertion{Min: 2}},
+	}}))
+}
+
+func TestValidate(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"users/a.json":  `{"name": "a", "age": 30}`,
+		"users/b.json":  `{"name": "b", "age": -1}`,
+		"events.jsonl":  "{\"id\": 1}\n{\"id\": null}\n\n{\"id\": 3}\n",
+		"table.csv":     "id,email\n1,a@x.com\n2,\n3,NULL\n4,d@x.com\n",
+		"ignored/x.txt": "x",
+	})
+	defer os.RemoveAll(root)
+
+	report, err := Validate(&pps.Validation{Assertions: []*pps.Assertion{
+		{Name: "users", Glob: "/users/*", FileCount: &pps.FileCountAssertion{Min: 2, Max: 2}},
+		{Name: "schema", Glob: "/users/*", JsonSchema: `{
+			"type": "object",
+			"required": ["name", "age"],
+			"properties": {"name": {"type": "string"}, "age": {"type": "integer", "minimum": 0}}
+		}`},
+		{Name: "events", Glob: "/*.jsonl", NullRate: &pps.NullRateAssertion{Column: "id", MaxRate: 0.5}},
+		{Name: "emails", Glob: "/*.csv", NullRate: &pps.NullRateAssertion{Column: "email", MaxRate: 0.25}},
+	}}, root)
+	require.NoError(t, err)
+	require.False(t, report.Passed)
+	require.Equal(t, 2, report.Failed())
+	require.True(t, report.Assertions[0].Passed)
+	require.False(t, report.Assertions[1].Passed)
+	require.Equal(t, []string{"/users/b.json: .age is less than 0"}, report.Assertions[1].Failures)
+	require.True(t, report.Assertions[2].Passed)
+	require.False(t, report.Assertions[3].Passed)
+}
+
+func TestSchema(t *testing.T) {
+	s, err := compileSchema(`{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"tags": {"type": "array", "items": {"enum": ["a", "b"]}, "maxItems": 2},
+			"code": {"type": "string", "pattern": "^[A-Z]{3}$"}
+		}
+	}`)
+	require.NoError(t, err)
+	require.NoError(t, s.validate(map[string]interface{}{"tags": []interface{}{"a"}, "code": "ABC"}, ""))
+	require.YesError(t, s.validate(map[string]interface{}{"tags": []interface{}{"c"}}, ""))
+	require.YesError(t, s.validate(map[string]interface{}{"tags": []interface{}{"a", "b", "a"}}, ""))
+	require.YesError(t, s.validate(map[string]interface{}{"code": "abc"}, ""))
+	require.YesError(t, s.validate(map[string]interface{}{"other": 1.0}, ""))
+	require.YesError(t, s.validate([]interface{}{}, ""))
+}
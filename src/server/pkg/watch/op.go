@@ -6,6 +6,9 @@ import etcd "github.com/coreos/etcd/clientv3"
 type OpOption struct {
 	Get   etcd.OpOption
 	Watch etcd.OpOption
+	// If set, the watcher sends an EventSynced event after the items that
+	// existed when the watch started
+	synced bool
 }
 
 // WithFilterPut discards PUT events from the watcher.
@@ -22,3 +25,10 @@ func WithSort(sortBy etcd.SortTarget, sortOrder etcd.SortOrder) OpOption {
 func WithFilterDelete() OpOption {
 	return OpOption{Watch: etcd.WithFilterDelete()}
 }
+
+// WithSynced makes the watcher send an EventSynced event after the items that
+// existed when the watch started, so that callers can tell them apart from
+// later changes.
+func WithSynced() OpOption {
+	return OpOption{synced: true}
+}
@@ -23,6 +23,9 @@ const (
 	EventDelete
 	// EventError happens when an error occurred
 	EventError
+	// EventSynced happens after the items that existed when the watch
+	// started, if the watch was started with WithSynced
+	EventSynced
 )
 
 // Event is an event that occurred to an item in etcd.
@@ -74,7 +77,9 @@ func NewWatcher(ctx context.Context, client *etcd.Client, trimPrefix, prefix str
 	// Sort by mod revision--how the items would have been returned if we watched
 	// them from the beginning.
 	getOptions := []etcd.OpOption{etcd.WithPrefix(), etcd.WithSort(etcd.SortByModRevision, etcd.SortAscend)}
+	var synced bool
 	for _, opt := range opts {
+		synced = synced || opt.synced
 		if opt.Get != nil {
 			getOptions = append(getOptions, etcd.OpOption(opt.Get))
 		}
@@ -123,6 +128,13 @@ func NewWatcher(ctx context.Context, client *etcd.Client, trimPrefix, prefix str
 				Template: template,
 			}
 		}
+		if synced {
+			select {
+			case eventCh <- &Event{Type: EventSynced, Template: template}:
+			case <-done:
+				return nil
+			}
+		}
 		for {
 			var resp etcd.WatchResponse
 			var ok bool
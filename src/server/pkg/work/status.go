@@ -0,0 +1,69 @@
+package work
+
+import (
+	"context"
+	"strings"
+
+	etcd "github.com/coreos/etcd/clientv3"
+
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+)
+
+// TaskStatus summarizes the state of a task's subtasks.
+type TaskStatus struct {
+	TaskID string
+	// Subtasks that are waiting for a worker to claim them
+	Pending int64
+	// Subtasks that a worker is processing
+	Claimed   int64
+	Succeeded int64
+	Failed    int64
+	// The number of times the task's subtasks were given up by a worker that
+	// was shutting down
+	Requeues int64
+}
+
+// ListTasks returns the status of each task in a task namespace, keyed by the
+// task's ID. It only reads etcd, so it can be called by any process, not only
+// by the task queue's.
+func ListTasks(ctx context.Context, etcdClient *etcd.Client, etcdPrefix string, taskNamespace string) (map[string]*TaskStatus, error) {
+	te := newTaskEtcd(etcdClient, etcdPrefix, taskNamespace)
+	result := make(map[string]*TaskStatus)
+	if err := te.taskCol.ReadOnly(ctx).List(&Task{}, col.DefaultOptions, func(taskID string) error {
+		result[taskID] = &TaskStatus{TaskID: taskID}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	claimed := make(map[string]bool)
+	if err := te.claimCol.ReadOnly(ctx).List(&Claim{}, col.DefaultOptions, func(subtaskKey string) error {
+		claimed[subtaskKey] = true
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	subtaskInfo := &TaskInfo{}
+	if err := te.subtaskCol.ReadOnly(ctx).List(subtaskInfo, col.DefaultOptions, func(subtaskKey string) error {
+		status, ok := result[strings.SplitN(subtaskKey, "/", 2)[0]]
+		if !ok {
+			return nil // the task finished after the tasks were listed
+		}
+		status.Requeues += subtaskInfo.Requeues
+		switch subtaskInfo.State {
+		case State_RUNNING:
+			if claimed[subtaskKey] {
+				status.Claimed++
+			} else {
+				status.Pending++
+			}
+		case State_SUCCESS:
+			status.Succeeded++
+		case State_FAILURE:
+			status.Failed++
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
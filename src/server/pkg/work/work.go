@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	etcd "github.com/coreos/etcd/clientv3"
 	"github.com/gogo/protobuf/proto"
@@ -20,8 +22,15 @@ const (
 	taskPrefix    = "/task"
 	subtaskPrefix = "/subtask"
 	claimPrefix   = "/claim"
+
+	drainPollInterval = 100 * time.Millisecond
 )
 
+// ErrRequeue may be returned (possibly wrapped) by a ProcessFunc to give up a
+// subtask without finishing it. The subtask stays in the RUNNING state, and is
+// claimed by the next available worker.
+var ErrRequeue = errors.New("subtask requeued")
+
 // TaskQueue manages a set of parallel tasks, and provides an interface for running tasks.
 // Priority of tasks (and therefore subtasks) is based on task creation time, so tasks created
 // earlier will be prioritized over tasks that were created later.
@@ -75,8 +84,15 @@ func newCollection(etcdClient *etcd.Client, etcdPrefix string, template proto.Me
 // The task code should be contained within the passed in callback.
 // The callback will receive a Master, which should be used for running subtasks in the task queue.
 // The task state will be cleaned up upon return of the callback.
-func (tq *TaskQueue) RunTask(ctx context.Context, f func(*Master)) (retErr error) {
-	task := &Task{ID: uuid.NewWithoutDashes()}
+func (tq *TaskQueue) RunTask(ctx context.Context, f func(*Master)) error {
+	return tq.RunTaskWithID(ctx, uuid.NewWithoutDashes(), f)
+}
+
+// RunTaskWithID is similar to RunTask, but the task has the given ID, which
+// must be unique in the task queue (e.g. the ID of the job that the task
+// processes), so that its state can be found with ListTasks.
+func (tq *TaskQueue) RunTaskWithID(ctx context.Context, taskID string, f func(*Master)) (retErr error) {
+	task := &Task{ID: taskID}
 	if _, err := col.NewSTM(ctx, tq.etcdClient, func(stm col.STM) error {
 		return tq.taskCol.ReadWrite(stm).Put(task.ID, task)
 	}); err != nil {
@@ -259,6 +275,14 @@ func (tq *TaskQueue) deleteAllTasks() error {
 // in the task.
 type Worker struct {
 	*taskEtcd
+	// group is the group of subtasks that the worker claims (see
+	// Task.Group)
+	group string
+
+	// mu protects draining and active
+	mu       sync.Mutex
+	draining bool
+	active   int
 }
 
 // NewWorker creates a new worker.
@@ -266,6 +290,57 @@ func NewWorker(etcdClient *etcd.Client, etcdPrefix string, taskNamespace string)
 	return &Worker{taskEtcd: newTaskEtcd(etcdClient, etcdPrefix, taskNamespace)}
 }
 
+// NewGroupWorker creates a new worker that only processes the subtasks in
+// 'group' (see Task.Group). Workers created with NewWorker process the
+// subtasks that aren't in a group.
+func NewGroupWorker(etcdClient *etcd.Client, etcdPrefix string, taskNamespace string, group string) *Worker {
+	w := NewWorker(etcdClient, etcdPrefix, taskNamespace)
+	w.group = group
+	return w
+}
+
+// Drain stops the worker from claiming any more subtasks, and waits until the
+// subtasks that it has already claimed are done (or 'ctx' is done). Subtasks
+// that can't be finished promptly should return ErrRequeue, so that another
+// worker picks them up.
+func (w *Worker) Drain(ctx context.Context) error {
+	w.mu.Lock()
+	w.draining = true
+	w.mu.Unlock()
+	for {
+		w.mu.Lock()
+		active := w.active
+		w.mu.Unlock()
+		if active == 0 {
+			return nil
+		}
+		select {
+		case <-time.After(drainPollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// startSubtask registers a subtask that the worker is about to claim. It
+// returns false if the worker is draining, in which case the subtask should be
+// left for another worker.
+func (w *Worker) startSubtask() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.draining {
+		return false
+	}
+	w.active++
+	return true
+}
+
+func (w *Worker) finishSubtask() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.active--
+}
+
 // ProcessFunc is a callback that is used for processing a subtask in a task.
 type ProcessFunc func(context.Context, *Task) error
 
@@ -333,6 +408,10 @@ func (w *Worker) taskFunc(task *Task, taskEntry *taskEntry, processFunc ProcessF
 
 func (w *Worker) subtaskFunc(subtaskKey string, processFunc ProcessFunc) subtaskFunc {
 	return func(ctx context.Context) {
+		if !w.startSubtask() {
+			return
+		}
+		defer w.finishSubtask()
 		if err := func() error {
 			// (bryce) this should be refactored to have the check and claim in the same stm.
 			// there is a rare race condition that does not affect correctness, but it is less
@@ -343,7 +422,7 @@ func (w *Worker) subtaskFunc(subtaskKey string, processFunc ProcessFunc) subtask
 			}); err != nil {
 				return err
 			}
-			if subtaskInfo.State != State_RUNNING {
+			if subtaskInfo.State != State_RUNNING || subtaskInfo.Task.Group != w.group {
 				return nil
 			}
 			return w.claimCol.Claim(ctx, subtaskKey, &Claim{}, func(claimCtx context.Context) (retErr error) {
@@ -354,13 +433,28 @@ func (w *Worker) subtaskFunc(subtaskKey string, processFunc ProcessFunc) subtask
 						retErr = nil
 						return
 					}
+					requeue := errors.Is(retErr, ErrRequeue)
+					if requeue {
+						retErr = nil
+					}
 					subtaskInfo := &TaskInfo{}
 					if _, err := col.NewSTM(claimCtx, w.etcdClient, func(stm col.STM) error {
+						if requeue {
+							// Release the claim, so that another worker can
+							// claim the subtask right away
+							if err := w.claimCol.ReadWrite(stm).Delete(subtaskKey); err != nil && !col.IsErrNotFound(err) {
+								return err
+							}
+						}
 						return w.subtaskCol.ReadWrite(stm).Update(subtaskKey, subtaskInfo, func() error {
 							// (bryce) remove when check and claim are in the same stm.
 							if subtaskInfo.State != State_RUNNING {
 								return nil
 							}
+							if requeue {
+								subtaskInfo.Requeues++
+								return nil
+							}
 							subtaskInfo.Task = subtask
 							subtaskInfo.State = State_SUCCESS
 							if retErr != nil {
@@ -53,11 +53,14 @@ func (State) EnumDescriptor() ([]byte, []int) {
 }
 
 type Task struct {
-	ID                   string     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Data                 *types.Any `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+	ID   string     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Data *types.Any `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// If set, the subtask is only claimed by workers in this group (see
+	// NewGroupWorker).
+	Group                string   `protobuf:"bytes,3,opt,name=group,proto3" json:"group,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Task) Reset()         { *m = Task{} }
@@ -107,10 +110,20 @@ func (m *Task) GetData() *types.Any {
 	return nil
 }
 
+func (m *Task) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
 type TaskInfo struct {
-	Task                 *Task    `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
-	State                State    `protobuf:"varint,2,opt,name=state,proto3,enum=work.State" json:"state,omitempty"`
-	Reason               string   `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Task   *Task  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	State  State  `protobuf:"varint,2,opt,name=state,proto3,enum=work.State" json:"state,omitempty"`
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// The number of times the subtask was given up by a worker that was shutting
+	// down, and handed to another worker
+	Requeues             int64    `protobuf:"varint,4,opt,name=requeues,proto3" json:"requeues,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -170,6 +183,13 @@ func (m *TaskInfo) GetReason() string {
 	return ""
 }
 
+func (m *TaskInfo) GetRequeues() int64 {
+	if m != nil {
+		return m.Requeues
+	}
+	return 0
+}
+
 type Claim struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -267,28 +287,30 @@ func init() {
 func init() { proto.RegisterFile("server/pkg/work/work.proto", fileDescriptor_58a68e4647f78187) }
 
 var fileDescriptor_58a68e4647f78187 = []byte{
-	// 332 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x91, 0xcb, 0x6a, 0xe3, 0x30,
-	0x14, 0x86, 0xc7, 0x1e, 0xe7, 0x76, 0x0c, 0x43, 0x10, 0x21, 0x64, 0xc2, 0xe0, 0x49, 0xbd, 0x32,
-	0x5d, 0xd8, 0xe0, 0xbe, 0x40, 0x73, 0x6b, 0x6b, 0x28, 0x59, 0xc8, 0xc9, 0xa6, 0x3b, 0xc5, 0x56,
-	0x1c, 0xe3, 0xc4, 0x32, 0x92, 0xd2, 0x92, 0x37, 0xec, 0xb2, 0x4f, 0x50, 0x8a, 0x9f, 0xa4, 0x48,
-	0x6e, 0x69, 0xe9, 0x46, 0xfc, 0x97, 0xc3, 0xc7, 0x91, 0x04, 0x63, 0x41, 0xf9, 0x23, 0xe5, 0x41,
-	0x55, 0x64, 0xc1, 0x13, 0xe3, 0x85, 0x3e, 0xfc, 0x8a, 0x33, 0xc9, 0x90, 0xa5, 0xf4, 0x78, 0x90,
-	0xb1, 0x8c, 0xe9, 0x20, 0x50, 0xaa, 0xe9, 0xc6, 0x7f, 0x33, 0xc6, 0xb2, 0x03, 0x0d, 0xb4, 0xdb,
-	0x9e, 0x76, 0x01, 0x29, 0xcf, 0x4d, 0xe5, 0xde, 0x81, 0xb5, 0x26, 0xa2, 0x40, 0x43, 0x30, 0xf3,
-	0x74, 0x64, 0x4c, 0x0c, 0xaf, 0x37, 0x6b, 0xd7, 0xaf, 0xff, 0xcd, 0x68, 0x81, 0xcd, 0x3c, 0x45,
-	0x1e, 0x58, 0x29, 0x91, 0x64, 0x64, 0x4e, 0x0c, 0xcf, 0x0e, 0x07, 0x7e, 0x43, 0xf2, 0x3f, 0x49,
-	0xfe, 0xb4, 0x3c, 0x63, 0x3d, 0xe1, 0x52, 0xe8, 0x2a, 0x52, 0x54, 0xee, 0x18, 0x72, 0xc0, 0x92,
-	0x44, 0x14, 0x9a, 0x67, 0x87, 0xe0, 0xeb, 0x3d, 0x55, 0x8b, 0x75, 0x8e, 0x2e, 0xa0, 0x25, 0x24,
-	0x91, 0x54, 0x63, 0xff, 0x84, 0x76, 0x33, 0x10, 0xab, 0x08, 0x37, 0x0d, 0x1a, 0x42, 0x9b, 0x53,
-	0x22, 0x58, 0x39, 0xfa, 0xad, 0x96, 0xc2, 0x1f, 0xce, 0xed, 0x40, 0x6b, 0x7e, 0x20, 0xf9, 0xd1,
-	0xf5, 0xa0, 0xbb, 0xa6, 0x42, 0x2e, 0x88, 0x24, 0xe8, 0x1f, 0xf4, 0x2a, 0xce, 0x12, 0x2a, 0x04,
-	0x6d, 0x2e, 0xd1, 0xc5, 0x5f, 0xc1, 0xa5, 0x0f, 0x2d, 0x8d, 0x46, 0x36, 0x74, 0xf0, 0x66, 0xb5,
-	0x8a, 0x56, 0xb7, 0xfd, 0x5f, 0xca, 0xc4, 0x9b, 0xf9, 0x7c, 0x19, 0xc7, 0x7d, 0x43, 0x99, 0x9b,
-	0x69, 0x74, 0xbf, 0xc1, 0xcb, 0xbe, 0x39, 0xbb, 0x7e, 0xae, 0x1d, 0xe3, 0xa5, 0x76, 0x8c, 0xb7,
-	0xda, 0x31, 0x1e, 0xc2, 0x2c, 0x97, 0xfb, 0xd3, 0xd6, 0x4f, 0xd8, 0x31, 0xa8, 0x48, 0xb2, 0x3f,
-	0xa7, 0x94, 0x7f, 0x57, 0x82, 0x27, 0xc1, 0x8f, 0x7f, 0xd9, 0xb6, 0xf5, 0xfb, 0x5c, 0xbd, 0x07,
-	0x00, 0x00, 0xff, 0xff, 0xec, 0xad, 0x45, 0x2a, 0xb1, 0x01, 0x00, 0x00,
+	// 360 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x91, 0xcf, 0xaa, 0x9b, 0x40,
+	0x14, 0xc6, 0x3b, 0x5e, 0xcd, 0xf5, 0x1e, 0xa1, 0x84, 0x21, 0x5c, 0xac, 0x14, 0x9b, 0xba, 0x92,
+	0x2e, 0x14, 0xec, 0x0b, 0x34, 0xff, 0x5a, 0x84, 0x92, 0xc5, 0x98, 0x6c, 0xba, 0x28, 0x4c, 0x74,
+	0x62, 0xc4, 0xc4, 0xb1, 0x33, 0xda, 0x92, 0x65, 0xdf, 0xae, 0xcb, 0x3e, 0x41, 0x29, 0x3e, 0x49,
+	0x99, 0x31, 0xfd, 0x43, 0x37, 0xc3, 0xf9, 0x7d, 0xdf, 0xe1, 0x7c, 0x67, 0x66, 0xc0, 0x93, 0x4c,
+	0x7c, 0x66, 0x22, 0x6e, 0xeb, 0x32, 0xfe, 0xc2, 0x45, 0xad, 0x8f, 0xa8, 0x15, 0xbc, 0xe3, 0xd8,
+	0x54, 0xb5, 0x37, 0x2b, 0x79, 0xc9, 0xb5, 0x10, 0xab, 0x6a, 0xf4, 0xbc, 0x67, 0x25, 0xe7, 0xe5,
+	0x99, 0xc5, 0x9a, 0x0e, 0xfd, 0x31, 0xa6, 0xcd, 0x75, 0xb4, 0x82, 0x8f, 0x60, 0xee, 0xa8, 0xac,
+	0xf1, 0x23, 0x18, 0x55, 0xe1, 0xa2, 0x39, 0x0a, 0x1f, 0x96, 0x93, 0xe1, 0xc7, 0x0b, 0x23, 0x5d,
+	0x13, 0xa3, 0x2a, 0x70, 0x08, 0x66, 0x41, 0x3b, 0xea, 0x1a, 0x73, 0x14, 0x3a, 0xc9, 0x2c, 0x1a,
+	0x27, 0x45, 0xbf, 0x27, 0x45, 0x8b, 0xe6, 0x4a, 0x74, 0x07, 0x9e, 0x81, 0x55, 0x0a, 0xde, 0xb7,
+	0xee, 0x9d, 0x1a, 0x42, 0x46, 0x08, 0xbe, 0x22, 0xb0, 0x55, 0x40, 0xda, 0x1c, 0x39, 0xf6, 0xc1,
+	0xec, 0xa8, 0xac, 0x75, 0x8c, 0x93, 0x40, 0xa4, 0xd7, 0x57, 0x2e, 0xd1, 0x3a, 0x7e, 0x09, 0x96,
+	0xec, 0x68, 0xc7, 0x74, 0xda, 0xd3, 0xc4, 0x19, 0x1b, 0x32, 0x25, 0x91, 0xd1, 0xc1, 0x8f, 0x30,
+	0x11, 0x8c, 0x4a, 0xde, 0xdc, 0x62, 0x6e, 0x84, 0x3d, 0xb0, 0x05, 0xfb, 0xd4, 0xb3, 0x9e, 0x49,
+	0xd7, 0x9c, 0xa3, 0xf0, 0x8e, 0xfc, 0xe1, 0xe0, 0x1e, 0xac, 0xd5, 0x99, 0x56, 0x97, 0x20, 0x04,
+	0x7b, 0xc7, 0x64, 0xb7, 0x56, 0xeb, 0x3e, 0x87, 0x87, 0x56, 0xf0, 0x9c, 0x49, 0xc9, 0xc6, 0x7b,
+	0xdb, 0xe4, 0xaf, 0xf0, 0x2a, 0x02, 0x4b, 0xc7, 0x62, 0x07, 0xee, 0xc9, 0x7e, 0xbb, 0x4d, 0xb7,
+	0xef, 0xa6, 0x4f, 0x14, 0x64, 0xfb, 0xd5, 0x6a, 0x93, 0x65, 0x53, 0xa4, 0xe0, 0xed, 0x22, 0x7d,
+	0xbf, 0x27, 0x9b, 0xa9, 0xb1, 0x7c, 0xf3, 0x6d, 0xf0, 0xd1, 0xf7, 0xc1, 0x47, 0x3f, 0x07, 0x1f,
+	0x7d, 0x48, 0xca, 0xaa, 0x3b, 0xf5, 0x87, 0x28, 0xe7, 0x97, 0xb8, 0xa5, 0xf9, 0xe9, 0x5a, 0x30,
+	0xf1, 0x6f, 0x25, 0x45, 0x1e, 0xff, 0xf7, 0x95, 0x87, 0x89, 0x7e, 0xd2, 0xd7, 0xbf, 0x06, 0x00,
+	0xf7, 0x71, 0xcf, 0x1c, 0xe4, 0x01, 0x00, 0x00,
 }
 
 func (m *Task) Marshal() (dAtA []byte, err error) {
@@ -315,6 +337,13 @@ func (m *Task) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Group) > 0 {
+		i -= len(m.Group)
+		copy(dAtA[i:], m.Group)
+		i = encodeVarintWork(dAtA, i, uint64(len(m.Group)))
+		i--
+		dAtA[i] = 0x1a
+	}
 	if m.Data != nil {
 		{
 			size, err := m.Data.MarshalToSizedBuffer(dAtA[:i])
@@ -361,6 +390,11 @@ func (m *TaskInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Requeues != 0 {
+		i = encodeVarintWork(dAtA, i, uint64(m.Requeues))
+		i--
+		dAtA[i] = 0x20
+	}
 	if len(m.Reason) > 0 {
 		i -= len(m.Reason)
 		copy(dAtA[i:], m.Reason)
@@ -477,6 +511,10 @@ func (m *Task) Size() (n int) {
 		l = m.Data.Size()
 		n += 1 + l + sovWork(uint64(l))
 	}
+	l = len(m.Group)
+	if l > 0 {
+		n += 1 + l + sovWork(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -500,6 +538,9 @@ func (m *TaskInfo) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovWork(uint64(l))
 	}
+	if m.Requeues != 0 {
+		n += 1 + sovWork(uint64(m.Requeues))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -636,6 +677,38 @@ func (m *Task) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Group", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWork
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthWork
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthWork
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Group = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipWork(dAtA[iNdEx:])
@@ -777,6 +850,25 @@ func (m *TaskInfo) Unmarshal(dAtA []byte) error {
 			}
 			m.Reason = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Requeues", wireType)
+			}
+			m.Requeues = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWork
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Requeues |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipWork(dAtA[iNdEx:])
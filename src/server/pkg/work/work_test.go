@@ -200,3 +200,127 @@ func TestRunZeroSubtasks(t *testing.T) {
 		})
 	}))
 }
+
+func TestRequeue(t *testing.T) {
+	require.NoError(t, testetcd.WithEnv(func(env *testetcd.Env) error {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		// The draining worker requeues the first subtask it claims, and then
+		// stops claiming subtasks, so the other worker processes all of them
+		drainingWorker := NewWorker(env.EtcdClient, "", "")
+		requeued := make(chan struct{})
+		go drainingWorker.Run(ctx, func(_ context.Context, _ *Task) error {
+			select {
+			case <-requeued:
+			default:
+				close(requeued)
+				go drainingWorker.Drain(ctx)
+			}
+			return ErrRequeue
+		})
+		tq, err := NewTaskQueue(ctx, env.EtcdClient, "", "")
+		require.NoError(t, err)
+		var eg errgroup.Group
+		var requeues int64
+		collected := make(map[string]bool)
+		eg.Go(func() error {
+			return tq.RunTaskBlock(ctx, func(m *Master) error {
+				var subtasks []*Task
+				for i := 0; i < 10; i++ {
+					data, err := serializeTestData(&TestData{})
+					if err != nil {
+						return err
+					}
+					subtasks = append(subtasks, &Task{ID: strconv.Itoa(i), Data: data})
+				}
+				return m.RunSubtasks(subtasks, func(_ context.Context, subtaskInfo *TaskInfo) error {
+					requeues += subtaskInfo.Requeues
+					return collectSubtask(subtaskInfo, collected)
+				})
+			})
+		})
+		<-requeued
+		go NewWorker(env.EtcdClient, "", "").Run(ctx, func(_ context.Context, subtask *Task) error {
+			return processSubtask(t, subtask)
+		})
+		require.NoError(t, eg.Wait())
+		require.Equal(t, 10, len(collected))
+		require.True(t, requeues >= 1)
+		return nil
+	}))
+}
+
+func TestListTasks(t *testing.T) {
+	require.NoError(t, testetcd.WithEnv(func(env *testetcd.Env) error {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		tq, err := NewTaskQueue(ctx, env.EtcdClient, "", "")
+		require.NoError(t, err)
+		// No workers are running, so the subtasks stay pending until the
+		// task is canceled
+		created := make(chan struct{})
+		require.NoError(t, tq.RunTaskWithID(ctx, "job", func(m *Master) {
+			subtaskChan := make(chan *Task)
+			go func() {
+				for i := 0; i < 3; i++ {
+					subtaskChan <- &Task{ID: strconv.Itoa(i)}
+				}
+				close(created)
+				<-m.Ctx().Done()
+				close(subtaskChan)
+			}()
+			m.RunSubtasksChan(subtaskChan, nil)
+		}))
+		<-created
+		require.NoErrorWithinTRetry(t, 10*time.Second, func() error {
+			statuses, err := ListTasks(ctx, env.EtcdClient, "", "")
+			if err != nil {
+				return err
+			}
+			if len(statuses) != 1 || statuses["job"] == nil || statuses["job"].Pending != 3 {
+				return errors.Errorf("unexpected task statuses: %v", statuses)
+			}
+			return nil
+		})
+		return nil
+	}))
+}
+
+func TestGroups(t *testing.T) {
+	require.NoError(t, testetcd.WithEnv(func(env *testetcd.Env) error {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		// Each worker only processes the subtasks in its group
+		groups := []string{"", "large"}
+		for _, group := range groups {
+			group := group
+			go NewGroupWorker(env.EtcdClient, "", "", group).Run(ctx, func(_ context.Context, subtask *Task) error {
+				if subtask.Group != group {
+					return errors.Errorf("worker in group %q processed a subtask in group %q", group, subtask.Group)
+				}
+				return processSubtask(t, subtask)
+			})
+		}
+		tq, err := NewTaskQueue(ctx, env.EtcdClient, "", "")
+		require.NoError(t, err)
+		collected := make(map[string]bool)
+		require.NoError(t, tq.RunTaskBlock(ctx, func(m *Master) error {
+			var subtasks []*Task
+			for i := 0; i < 10; i++ {
+				data, err := serializeTestData(&TestData{})
+				if err != nil {
+					return err
+				}
+				subtasks = append(subtasks, &Task{ID: strconv.Itoa(i), Data: data, Group: groups[i%len(groups)]})
+			}
+			return m.RunSubtasks(subtasks, func(_ context.Context, subtaskInfo *TaskInfo) error {
+				if subtaskInfo.State != State_SUCCESS {
+					return errors.Errorf("subtask %s failed: %s", subtaskInfo.Task.ID, subtaskInfo.Reason)
+				}
+				return collectSubtask(subtaskInfo, collected)
+			})
+		}))
+		require.Equal(t, 10, len(collected))
+		return nil
+	}))
+}
@@ -10,7 +10,9 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	pachdclient "github.com/pachyderm/pachyderm/src/client"
 	"github.com/pachyderm/pachyderm/src/client/pfs"
@@ -146,6 +148,10 @@ If the job fails, the output commit will not be populated with data.`,
 	var inputCommitStrs []string
 	var history string
 	var stateStrs []string
+	var failureCauseStrs []string
+	var createdAfter, createdBefore string
+	var jobPageSize int64
+	var jobPageToken string
 	listJob := &cobra.Command{
 		Short: "Return info about jobs.",
 		Long:  "Return info about jobs.",
@@ -163,7 +169,17 @@ $ {{alias}} -p foo --history all
 $ {{alias}} -i foo@XXX -i bar@YYY
 
 # Return all jobs in pipeline foo and whose input commits include bar@YYY
-$ {{alias}} -p foo -i bar@YYY`,
+$ {{alias}} -p foo -i bar@YYY
+
+# Return all jobs that failed because their workers ran out of memory
+$ {{alias}} --failure-cause oom_killed
+
+# Return the failed jobs of pipeline foo that were created in the last day
+$ {{alias}} -p foo --state failure --created-after 24h
+
+# Return the 100 most recent jobs, and then the next 100
+$ {{alias}} --page-size 100
+$ {{alias}} --page-size 100 --page-token <next page token>`,
 		Run: cmdutil.RunFixedArgs(0, func(args []string) error {
 			commits, err := cmdutil.ParseCommits(inputCommitStrs)
 			if err != nil {
@@ -180,12 +196,40 @@ $ {{alias}} -p foo -i bar@YYY`,
 					return err
 				}
 			}
-			var filter string
-			if len(stateStrs) > 0 {
-				filter, err = ParseJobStates(stateStrs)
+			request := &ppsclient.ListJobRequest{
+				InputCommit:  commits,
+				OutputCommit: outputCommit,
+				History:      history,
+				Full:         raw,
+				PageSize:     jobPageSize,
+				PageToken:    jobPageToken,
+			}
+			if pipelineName != "" {
+				request.Pipeline = pachdclient.NewPipeline(pipelineName)
+			}
+			for _, stateStr := range stateStrs {
+				state, err := ppsclient.JobStateFromName(stateStr)
 				if err != nil {
 					return errors.Wrap(err, "error parsing state")
 				}
+				request.State = append(request.State, state)
+			}
+			for _, causeStr := range failureCauseStrs {
+				cause, err := ppsclient.FailureCauseFromName(causeStr)
+				if err != nil {
+					return errors.Wrap(err, "error parsing failure cause")
+				}
+				request.FailureCause = append(request.FailureCause, cause)
+			}
+			now := time.Now()
+			if request.CreatedAfter, err = parseJobTime(createdAfter, now); err != nil {
+				return errors.Wrap(err, "error parsing --created-after")
+			}
+			if request.CreatedBefore, err = parseJobTime(createdBefore, now); err != nil {
+				return errors.Wrap(err, "error parsing --created-before")
+			}
+			if !raw && output != "" {
+				cmdutil.ErrorAndExit("cannot set --output (-o) without --raw")
 			}
 
 			client, err := pachdclient.NewOnUserMachine("user")
@@ -195,20 +239,39 @@ $ {{alias}} -p foo -i bar@YYY`,
 			defer client.Close()
 
 			return pager.Page(noPager, os.Stdout, func(w io.Writer) error {
+				var printJob func(*ppsclient.JobInfo) error
+				writer := tabwriter.NewWriter(w, pretty.JobHeader)
 				if raw {
 					e := encoder(output)
-					return client.ListJobFilterF(pipelineName, commits, outputCommit, history, true, filter, func(ji *ppsclient.JobInfo) error {
+					printJob = func(ji *ppsclient.JobInfo) error {
 						return e.EncodeProto(ji)
-					})
-				} else if output != "" {
-					cmdutil.ErrorAndExit("cannot set --output (-o) without --raw")
+					}
+				} else {
+					printJob = func(ji *ppsclient.JobInfo) error {
+						pretty.PrintJobInfo(writer, ji, fullTimestamps)
+						return nil
+					}
 				}
-				writer := tabwriter.NewWriter(w, pretty.JobHeader)
-				if err := client.ListJobFilterF(pipelineName, commits, outputCommit, history, false, filter, func(ji *ppsclient.JobInfo) error {
-					pretty.PrintJobInfo(writer, ji, fullTimestamps)
+				if jobPageSize == 0 {
+					if err := client.ListJobRequestF(request, printJob); err != nil {
+						return err
+					}
+				} else {
+					jobInfos, nextPageToken, err := client.ListJobPage(request)
+					if err != nil {
+						return err
+					}
+					for _, ji := range jobInfos {
+						if err := printJob(ji); err != nil {
+							return err
+						}
+					}
+					if nextPageToken != "" {
+						defer fmt.Fprintf(os.Stderr, "next page token: %s\n", nextPageToken)
+					}
+				}
+				if raw {
 					return nil
-				}); err != nil {
-					return err
 				}
 				return writer.Flush()
 			})
@@ -225,6 +288,11 @@ $ {{alias}} -p foo -i bar@YYY`,
 	listJob.Flags().AddFlagSet(noPagerFlags)
 	listJob.Flags().StringVar(&history, "history", "none", "Return jobs from historical versions of pipelines.")
 	listJob.Flags().StringArrayVar(&stateStrs, "state", []string{}, "Return only jobs with the specified state. Can be repeated to include multiple states")
+	listJob.Flags().StringArrayVar(&failureCauseStrs, "failure-cause", []string{}, "Return only jobs that failed with the specified cause (e.g. user_code, oom_killed, timeout). Can be repeated to include multiple causes")
+	listJob.Flags().StringVar(&createdAfter, "created-after", "", "Return only jobs created at or after this time: an RFC3339 timestamp, or a duration before now (e.g. 24h).")
+	listJob.Flags().StringVar(&createdBefore, "created-before", "", "Return only jobs created before this time: an RFC3339 timestamp, or a duration before now (e.g. 24h).")
+	listJob.Flags().Int64Var(&jobPageSize, "page-size", 0, "Return at most this many jobs, and print the token of the next page to stderr. If zero, all jobs are returned.")
+	listJob.Flags().StringVar(&jobPageToken, "page-token", "", "Return the page of jobs with this token, as printed by a previous call with --page-size.")
 	shell.RegisterCompletionFunc(listJob,
 		func(flag, text string, maxCompletions int64) ([]prompt.Suggest, shell.CacheFunc) {
 			if flag == "-p" || flag == "--pipeline" {
@@ -336,6 +404,37 @@ $ {{alias}} foo@XXX -p bar -p baz`,
 	shell.RegisterCompletionFunc(stopJob, shell.JobCompletion)
 	commands = append(commands, cmdutil.CreateAlias(stopJob, "stop job"))
 
+	scaleJob := &cobra.Command{
+		Use:   "{{alias}} <job> <workers>",
+		Short: "Change the number of workers that are running a job.",
+		Long: `Change the number of workers that are running a job, without restarting it.
+
+New workers start processing the job's remaining datums, and datums that were
+being processed by removed workers are retried. The job's pipeline returns to
+the number of workers in its parallelism spec once the job is finished. Changes
+are recorded in the job's scaling timeline, which 'inspect job' displays.`,
+		Example: `
+# run job 5f93d03b65fa421996185e53f7f8b1e4 with 10 workers
+$ {{alias}} 5f93d03b65fa421996185e53f7f8b1e4 10`,
+		Run: cmdutil.RunFixedArgs(2, func(args []string) error {
+			workers, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return errors.Wrapf(err, "invalid number of workers %q", args[1])
+			}
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			if err := client.ScaleJob(args[0], workers); err != nil {
+				cmdutil.ErrorAndExit("error from ScaleJob: %s", err.Error())
+			}
+			return nil
+		}),
+	}
+	shell.RegisterCompletionFunc(scaleJob, shell.JobCompletion)
+	commands = append(commands, cmdutil.CreateAlias(scaleJob, "scale job"))
+
 	datumDocs := &cobra.Command{
 		Short: "Docs for datums.",
 		Long: `Datums are the small independent units of processing for Pachyderm jobs.
@@ -443,6 +542,221 @@ each datum.`,
 	inspectDatum.Flags().AddFlagSet(outputFlags)
 	commands = append(commands, cmdutil.CreateAlias(inspectDatum, "inspect datum"))
 
+	resolveDatum := &cobra.Command{
+		Use:   "{{alias}} <job> <path>...",
+		Short: "Return the datums in a job that contain the given input files.",
+		Long:  "Return the datums in a job that contain the given input files. A datum contains a file if one of the datum's inputs is the file, or a directory containing it.",
+		Run: cmdutil.RunMinimumArgs(2, func(args []string) error {
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			resolved, err := client.ResolveDatum(args[0], args[1:]...)
+			if err != nil {
+				return err
+			}
+			if raw {
+				e := encoder(output)
+				for _, r := range resolved {
+					if err := e.EncodeProto(r); err != nil {
+						return err
+					}
+				}
+				return nil
+			} else if output != "" {
+				cmdutil.ErrorAndExit("cannot set --output (-o) without --raw")
+			}
+			writer := tabwriter.NewWriter(os.Stdout, pretty.ResolvedPathHeader)
+			for _, r := range resolved {
+				pretty.PrintResolvedPath(writer, r)
+			}
+			return writer.Flush()
+		}),
+	}
+	resolveDatum.Flags().AddFlagSet(outputFlags)
+	shell.RegisterCompletionFunc(resolveDatum, shell.JobCompletion)
+	commands = append(commands, cmdutil.CreateAlias(resolveDatum, "resolve datum"))
+
+	explainDatum := &cobra.Command{
+		Use:   "{{alias}} <job> <datum>",
+		Short: "Explain why a datum was or wasn't skipped.",
+		Long: `Explain why a datum was or wasn't skipped.
+
+A datum is skipped if a datum with the same hash was processed by an earlier
+job. This prints the values that the datum's hash is computed from, the datum
+with the same hash in the pipeline's previous job (if any), and the reasons
+why the datum was or wasn't skipped (e.g. the files that changed).`,
+		Run: cmdutil.RunFixedArgs(2, func(args []string) error {
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			explained, err := client.ExplainDatum(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			if raw {
+				return encoder(output).EncodeProto(explained)
+			} else if output != "" {
+				cmdutil.ErrorAndExit("cannot set --output (-o) without --raw")
+			}
+			pretty.PrintExplainedDatum(os.Stdout, explained)
+			return nil
+		}),
+	}
+	explainDatum.Flags().AddFlagSet(outputFlags)
+	shell.RegisterCompletionFunc(explainDatum, shell.JobCompletion)
+	commands = append(commands, cmdutil.CreateAlias(explainDatum, "explain datum"))
+
+	inspectScheduler := &cobra.Command{
+		Use:   "{{alias}} [<pipeline>]",
+		Short: "Show why jobs are or aren't running.",
+		Long: `Show why jobs are or aren't running.
+
+This prints the PPS master's view of each pipeline's unfinished jobs: why
+queued jobs aren't starting (e.g. an input commit hasn't finished, or the
+pipeline's workers aren't ready), the state of running jobs' chunks, and what
+each worker is processing. It also prints why the pipeline's worker pods
+aren't running, e.g. because they can't be scheduled or their image can't be
+pulled, whether gang-scheduled workers have been admitted by their queue, and
+which output commit is held by the pipeline's job gates (if any) and why. If no
+pipeline is given, every pipeline that has unfinished jobs, a held output
+commit, workers with problems, or workers waiting in a queue, is shown.`,
+		Run: cmdutil.RunBoundedArgs(0, 1, func(args []string) error {
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			var pipeline string
+			if len(args) > 0 {
+				pipeline = args[0]
+			}
+			schedules, err := client.GetScheduler(pipeline)
+			if err != nil {
+				return err
+			}
+			if raw {
+				e := encoder(output)
+				for _, schedule := range schedules {
+					if err := e.EncodeProto(schedule); err != nil {
+						return err
+					}
+				}
+				return nil
+			} else if output != "" {
+				cmdutil.ErrorAndExit("cannot set --output (-o) without --raw")
+			}
+			for i, schedule := range schedules {
+				if i > 0 {
+					fmt.Println()
+				}
+				pretty.PrintPipelineSchedule(os.Stdout, schedule, fullTimestamps)
+			}
+			return nil
+		}),
+	}
+	inspectScheduler.Flags().AddFlagSet(outputFlags)
+	inspectScheduler.Flags().AddFlagSet(fullTimestampsFlags)
+	shell.RegisterCompletionFunc(inspectScheduler, shell.PipelineCompletion)
+	commands = append(commands, cmdutil.CreateAlias(inspectScheduler, "inspect scheduler"))
+
+	listGitHooks := &cobra.Command{
+		Short: "Return info about git inputs and their webhook deliveries.",
+		Long: `Return info about the pipelines' git inputs, and the most recent webhook
+delivery for each. Recent deliveries that didn't match any git input (e.g.
+because they failed verification, or were for another repo or branch) are
+listed after them. If auth is active, only cluster admins may list git inputs.`,
+		Run: cmdutil.RunFixedArgs(0, func(args []string) error {
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			hookInfos, err := client.ListGitHooks()
+			if err != nil {
+				return err
+			}
+			if raw {
+				return encoder(output).EncodeProto(hookInfos)
+			} else if output != "" {
+				cmdutil.ErrorAndExit("cannot set --output (-o) without --raw")
+			}
+			writer := tabwriter.NewWriter(os.Stdout, pretty.GitHookHeader)
+			for _, hookInfo := range hookInfos.GitHookInfo {
+				pretty.PrintGitHookInfo(writer, hookInfo, fullTimestamps)
+			}
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			if len(hookInfos.UnmatchedDeliveries) == 0 {
+				return nil
+			}
+			fmt.Println("\nUnmatched deliveries:")
+			writer = tabwriter.NewWriter(os.Stdout, pretty.GitHookDeliveryHeader)
+			for _, delivery := range hookInfos.UnmatchedDeliveries {
+				pretty.PrintGitHookDelivery(writer, delivery, fullTimestamps)
+			}
+			return writer.Flush()
+		}),
+	}
+	listGitHooks.Flags().AddFlagSet(outputFlags)
+	listGitHooks.Flags().AddFlagSet(fullTimestampsFlags)
+	commands = append(commands, cmdutil.CreateAlias(listGitHooks, "list githook"))
+
+	inspectGitHook := &cobra.Command{
+		Use:   "{{alias}} <name>",
+		Short: "Return info about a git input and its recent webhook deliveries.",
+		Long: `Return info about a git input and its recent webhook deliveries, including
+why deliveries failed. Deliveries are kept for a week. If auth is active, only
+cluster admins may inspect git inputs.`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			hookInfo, err := client.InspectGitHook(args[0])
+			if err != nil {
+				return err
+			}
+			if raw {
+				return encoder(output).EncodeProto(hookInfo)
+			} else if output != "" {
+				cmdutil.ErrorAndExit("cannot set --output (-o) without --raw")
+			}
+			return pretty.PrintDetailedGitHookInfo(os.Stdout, hookInfo, fullTimestamps)
+		}),
+	}
+	inspectGitHook.Flags().AddFlagSet(outputFlags)
+	inspectGitHook.Flags().AddFlagSet(fullTimestampsFlags)
+	commands = append(commands, cmdutil.CreateAlias(inspectGitHook, "inspect githook"))
+
+	replayGitHookDelivery := &cobra.Command{
+		Use:   "{{alias}} <id>",
+		Short: "Replay a webhook delivery to git inputs.",
+		Long: `Replay a webhook delivery to git inputs, by committing its push to the git
+inputs that it's for again (e.g. after fixing why it failed). Only deliveries
+of pushes that could be parsed can be replayed.`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			delivery, err := client.ReplayGitHookDelivery(args[0])
+			if err != nil {
+				return err
+			}
+			writer := tabwriter.NewWriter(os.Stdout, pretty.GitHookDeliveryHeader)
+			pretty.PrintGitHookDelivery(writer, delivery, false)
+			return writer.Flush()
+		}),
+	}
+	commands = append(commands, cmdutil.CreateAlias(replayGitHookDelivery, "replay githook-delivery"))
+
 	var (
 		jobID       string
 		datumID     string
@@ -451,6 +765,9 @@ each datum.`,
 		worker      bool
 		follow      bool
 		tail        int64
+		since       string
+		until       string
+		fromStart   bool
 	)
 
 	// prettyLogsPrinter helps to print the logs recieved in different colours
@@ -490,7 +807,16 @@ $ {{alias}} --pipeline=filter
 $ {{alias}} --job=aedfa12aedf
 
 # Return logs emitted by the pipeline \"filter\" while processing /apple.txt and a file with the hash 123aef
-$ {{alias}} --pipeline=filter --inputs=/apple.txt,123aef`,
+$ {{alias}} --pipeline=filter --inputs=/apple.txt,123aef
+
+# Return logs emitted by the pipeline "filter" between 14:00 and 14:05 UTC on 2020-06-01
+$ {{alias}} --pipeline=filter --since=2020-06-01T14:00:00Z --until=2020-06-01T14:05:00Z
+
+# Return logs emitted by the pipeline "filter" in the last 10 minutes
+$ {{alias}} --pipeline=filter --since=10m
+
+# Follow the logs of the running job aedfa12aedf from its beginning
+$ {{alias}} --job=aedfa12aedf --from-start -f`,
 		Run: cmdutil.RunFixedArgs(0, func(args []string) error {
 			client, err := pachdclient.NewOnUserMachine("user")
 			if err != nil {
@@ -511,8 +837,18 @@ $ {{alias}} --pipeline=filter --inputs=/apple.txt,123aef`,
 				}
 			}
 
+			now := time.Now()
+			sinceTime, err := parseLogTime(since, now)
+			if err != nil {
+				return err
+			}
+			untilTime, err := parseLogTime(until, now)
+			if err != nil {
+				return err
+			}
+
 			// Issue RPC
-			iter := client.GetLogs(pipelineName, jobID, data, datumID, master, follow, tail)
+			iter := client.GetLogsInRange(pipelineName, jobID, data, datumID, master, follow, tail, sinceTime, untilTime, fromStart)
 			var buf bytes.Buffer
 			encoder := json.NewEncoder(&buf)
 			for iter.Next() {
@@ -549,6 +885,9 @@ $ {{alias}} --pipeline=filter --inputs=/apple.txt,123aef`,
 	getLogs.Flags().BoolVar(&raw, "raw", false, "Return log messages verbatim from server.")
 	getLogs.Flags().BoolVarP(&follow, "follow", "f", false, "Follow logs as more are created.")
 	getLogs.Flags().Int64VarP(&tail, "tail", "t", 0, "Lines of recent logs to display.")
+	getLogs.Flags().StringVar(&since, "since", "", "Return logs written at or after this time (accepts an RFC3339 timestamp, or a duration such as 5m, meaning that long ago).")
+	getLogs.Flags().StringVar(&until, "until", "", "Return logs written before this time (accepts an RFC3339 timestamp, or a duration such as 5m, meaning that long ago).")
+	getLogs.Flags().BoolVar(&fromStart, "from-start", false, "Return logs from the start of the job (job must be set).")
 	shell.RegisterCompletionFunc(getLogs,
 		func(flag, text string, maxCompletions int64) ([]prompt.Suggest, shell.CacheFunc) {
 			if flag == "--pipeline" || flag == "-p" {
@@ -584,7 +923,7 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 		Short: "Create a new pipeline.",
 		Long:  "Create a new pipeline from a pipeline specification. For details on the format, see http://docs.pachyderm.io/en/latest/reference/pipeline_spec.html.",
 		Run: cmdutil.RunFixedArgs(0, func(args []string) (retErr error) {
-			return pipelineHelper(false, build, pushImages, registry, username, pipelinePath, false)
+			return pipelineHelper(false, build, pushImages, registry, username, pipelinePath, false, false)
 		}),
 	}
 	createPipeline.Flags().StringVarP(&pipelinePath, "file", "f", "-", "The JSON file containing the pipeline, it can be a url or local file. - reads from stdin.")
@@ -595,11 +934,19 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 	commands = append(commands, cmdutil.CreateAlias(createPipeline, "create pipeline"))
 
 	var reprocess bool
+	var forceUpdate bool
 	updatePipeline := &cobra.Command{
 		Short: "Update an existing Pachyderm pipeline.",
-		Long:  "Update a Pachyderm pipeline with a new pipeline specification. For details on the format, see http://docs.pachyderm.io/en/latest/reference/pipeline_spec.html.",
+		Long: `Update a Pachyderm pipeline with a new pipeline specification. For details on the format, see http://docs.pachyderm.io/en/latest/reference/pipeline_spec.html.
+
+Before updating a pipeline, pachctl warns about changes that may invalidate
+the assumptions of its downstream pipelines: changes to how its input is split
+into datums, changes to its transform (which may change the paths it writes),
+and downstream pipelines whose globs may no longer match its output. If a
+downstream pipeline reads an output branch or named output that the update
+removes, the update is refused unless --force is passed.`,
 		Run: cmdutil.RunFixedArgs(0, func(args []string) (retErr error) {
-			return pipelineHelper(reprocess, build, pushImages, registry, username, pipelinePath, true)
+			return pipelineHelper(reprocess, build, pushImages, registry, username, pipelinePath, true, forceUpdate)
 		}),
 	}
 	updatePipeline.Flags().StringVarP(&pipelinePath, "file", "f", "-", "The JSON file containing the pipeline, it can be a url or local file. - reads from stdin.")
@@ -608,8 +955,177 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 	updatePipeline.Flags().StringVarP(&registry, "registry", "r", "index.docker.io", "The registry to push images to.")
 	updatePipeline.Flags().StringVarP(&username, "username", "u", "", "The username to push images as.")
 	updatePipeline.Flags().BoolVar(&reprocess, "reprocess", false, "If true, reprocess datums that were already processed by previous version of the pipeline.")
+	updatePipeline.Flags().BoolVar(&forceUpdate, "force", false, "If true, update the pipeline even if the update breaks a downstream pipeline.")
 	commands = append(commands, cmdutil.CreateAlias(updatePipeline, "update pipeline"))
 
+	var dagPath string
+	var dryRun bool
+	var prune bool
+	var reprocessPolicy string
+	applyDAG := &cobra.Command{
+		Short: "Make the cluster's pipelines and repos match a set of specs.",
+		Long: `Make the cluster's pipelines and repos match a set of specs.
+
+The specs are read from a file or, if --file is a directory, from every .json,
+.yaml and .yml file in it. Each spec is either a pipeline spec or, if it has a
+top-level "repo" field, a repo definition (e.g. {"repo": {"name": "images"},
+"description": "..."}).
+
+Pipelines and repos that don't exist are created, and those whose spec changed
+are updated. With --prune, pipelines that aren't in the specs are deleted. Use
+--dry-run to see the changes without making them.`,
+		Example: `
+# Show the changes needed to make the cluster match the specs in ./dag
+$ {{alias}} -f ./dag --dry-run
+
+# Apply them, deleting pipelines that aren't in ./dag and reprocessing the
+# input of pipelines whose transform changed
+$ {{alias}} -f ./dag --prune --reprocess on-transform-change`,
+		Run: cmdutil.RunFixedArgs(0, func(args []string) error {
+			request, err := dagRequest(dagPath)
+			if err != nil {
+				return err
+			}
+			request.Prune = prune
+			request.DryRun = dryRun
+			policy, ok := ppsclient.ReprocessPolicy_value["REPROCESS_"+strings.ToUpper(strings.Replace(reprocessPolicy, "-", "_", -1))]
+			if !ok {
+				return errors.Errorf("invalid reprocess policy %q (must be never, on-transform-change or always)", reprocessPolicy)
+			}
+			request.ReprocessPolicy = ppsclient.ReprocessPolicy(policy)
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			changes, err := client.ApplyDAG(request)
+			if err != nil {
+				return err
+			}
+			if len(changes) == 0 {
+				fmt.Println("No changes.")
+				return nil
+			}
+			writer := tabwriter.NewWriter(os.Stdout, pretty.DAGChangeHeader)
+			for _, change := range changes {
+				pretty.PrintDAGChange(writer, change)
+			}
+			return writer.Flush()
+		}),
+	}
+	applyDAG.Flags().StringVarP(&dagPath, "file", "f", "-", "A file or directory containing the pipeline specs and repo definitions. - reads from stdin.")
+	applyDAG.Flags().BoolVar(&dryRun, "dry-run", false, "If true, print the changes without making them.")
+	applyDAG.Flags().BoolVar(&prune, "prune", false, "If true, delete pipelines that aren't in the specs.")
+	applyDAG.Flags().StringVar(&reprocessPolicy, "reprocess", "never", "Which updated pipelines reprocess their input: never, on-transform-change or always. Pipeline specs that set 'reprocess' are always reprocessed.")
+	commands = append(commands, cmdutil.CreateAlias(applyDAG, "apply dag"))
+
+	var pipelinesPath string
+	var updatePipelines bool
+	var reprocessPipelines bool
+	createPipelines := &cobra.Command{
+		Short: "Create several pipelines at once.",
+		Long: `Create several pipelines at once.
+
+The pipeline specs are read from a file or, if --file is a directory, from every
+.json, .yaml and .yml file in it and its subdirectories. Each pipeline is
+created after the pipelines that it reads from, and if any pipeline can't be
+created, the pipelines that were already created are deleted (or, with
+--update, restored to their previous specs).`,
+		Example: `
+# Create every pipeline in ./pipelines and its subdirectories
+$ {{alias}} -f ./pipelines
+
+# Create or update every pipeline in ./pipelines
+$ {{alias}} -f ./pipelines --update`,
+		Run: cmdutil.RunFixedArgs(0, func(args []string) error {
+			request, err := dagRequest(pipelinesPath)
+			if err != nil {
+				return err
+			}
+			if len(request.Repos) > 0 {
+				return errors.Errorf("%s defines repos, which aren't pipelines; use 'pachctl apply dag' to create them", pipelinesPath)
+			}
+			if len(request.Pipelines) == 0 {
+				return errors.Errorf("no pipeline specs found in %s", pipelinesPath)
+			}
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			pipelines, err := client.CreatePipelines(request.Pipelines, updatePipelines, reprocessPipelines)
+			if err != nil {
+				return err
+			}
+			for _, pipeline := range pipelines {
+				fmt.Println(pipeline.Name)
+			}
+			return nil
+		}),
+	}
+	createPipelines.Flags().StringVarP(&pipelinesPath, "file", "f", "-", "A file or directory containing the pipeline specs. - reads from stdin.")
+	createPipelines.Flags().BoolVar(&updatePipelines, "update", false, "If true, update the pipelines that already exist.")
+	createPipelines.Flags().BoolVar(&reprocessPipelines, "reprocess", false, "If true (with --update), updated pipelines reprocess datums that were already processed.")
+	commands = append(commands, cmdutil.CreateAlias(createPipelines, "create pipelines"))
+
+	var mountJob string
+	var mountTTL time.Duration
+	createMountCredentials := &cobra.Command{
+		Use:   "{{alias}} [[<name>=]<repo>@<branch-or-commit>[:<path>]...]",
+		Short: "Create short-lived credentials for mounting commits in an in-cluster service.",
+		Long: `Create short-lived credentials for mounting commits in an in-cluster service.
+
+The credentials are printed as JSON. Branches are resolved to their head
+commit. The credentials' token can read every commit and path of the given
+commits' repos, not only the given commits and paths, as auth is granted to
+whole repos; the repos are listed in "readable_repos". Pass them to the
+pachyderm/mount-helper sidecar in $PACH_MOUNT_CREDENTIALS to mount each
+commit's path read-only at /pfs/<name> (by default, <name> is the repo), or
+use their token as the access and secret key of the S3 gateway, where each
+commit is the bucket "<commit ID>.<repo>".`,
+		Example: `
+# Mount the output of a job in a notebook, for an hour
+$ {{alias}} --job 5f93d03b65fa421996185e53f7f8b1e4
+
+# Mount a directory of the master branch of "images" at /pfs/training, for a day
+$ {{alias}} training=images@master:/train --ttl 24h`,
+		Run: cmdutil.Run(func(args []string) error {
+			var targets []*ppsclient.MountTarget
+			for _, arg := range args {
+				var name string
+				if parts := strings.SplitN(arg, "=", 2); len(parts) == 2 && !strings.Contains(parts[0], "@") {
+					name, arg = parts[0], parts[1]
+				}
+				file, err := cmdutil.ParseFile(arg)
+				if err != nil {
+					return err
+				}
+				targets = append(targets, &ppsclient.MountTarget{
+					Commit: file.Commit,
+					Path:   file.Path,
+					Name:   name,
+				})
+			}
+			if len(targets) == 0 && mountJob == "" {
+				return errors.New("nothing to mount (pass commits or --job)")
+			}
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			credentials, err := client.GetMountCredentials(targets, mountJob, mountTTL)
+			if err != nil {
+				return err
+			}
+			return encoder("json").EncodeProto(credentials)
+		}),
+	}
+	createMountCredentials.Flags().StringVar(&mountJob, "job", "", "Also mount the output commit of this job (at its pipeline's name).")
+	createMountCredentials.Flags().DurationVar(&mountTTL, "ttl", time.Hour, "How long the credentials are valid for (at most as long as your own session).")
+	commands = append(commands, cmdutil.CreateAlias(createMountCredentials, "create mount-credentials"))
+
+	var seedSalt string
 	runPipeline := &cobra.Command{
 		Use:   "{{alias}} <pipeline> [<repo>@[<branch>|<commit>|<branch>=<commit>]...]",
 		Short: "Run an existing Pachyderm pipeline on the specified commits-branch pairs.",
@@ -628,7 +1144,10 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 		$ {{alias}} filter repo1@testing
 
 		# Run the pipeline "filter" on the commit "af159e which originated on the "master" branch on repo "repo1"
-		$ {{alias}} filter repo1@af159`,
+		$ {{alias}} filter repo1@af159
+
+		# Rerun the job "a1b2c3", giving its datums different seeds in $PACH_DATUM_SEED
+		$ {{alias}} filter --job a1b2c3 --seed-salt 2`,
 
 		Run: cmdutil.RunMinimumArgs(1, func(args []string) (retErr error) {
 			client, err := pachdclient.NewOnUserMachine("user")
@@ -640,7 +1159,7 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 			if err != nil {
 				return err
 			}
-			err = client.RunPipeline(args[0], prov, jobID)
+			err = client.RunPipelineWithSeedSalt(args[0], prov, jobID, seedSalt)
 			if err != nil {
 				return err
 			}
@@ -648,6 +1167,7 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 		}),
 	}
 	runPipeline.Flags().StringVar(&jobID, "job", "", "rerun the given job")
+	runPipeline.Flags().StringVar(&seedSalt, "seed-salt", "", "derive the seeds of the new job's datums ($PACH_DATUM_SEED) from this salt, instead of the pipeline's salt (or, with --job, the rerun job's salt)")
 	commands = append(commands, cmdutil.CreateAlias(runPipeline, "run pipeline"))
 
 	runCron := &cobra.Command{
@@ -705,6 +1225,49 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 	inspectPipeline.Flags().AddFlagSet(fullTimestampsFlags)
 	commands = append(commands, cmdutil.CreateAlias(inspectPipeline, "inspect pipeline"))
 
+	diffPipeline := &cobra.Command{
+		Use:   "{{alias}} <pipeline> [<version-a> [<version-b>]]",
+		Short: "Compare two versions of a pipeline's spec.",
+		Long: `Compare two versions of a pipeline's spec, field by field.
+
+If <version-b> isn't given, <version-a> is compared with the current version of the pipeline. If neither is given, the current version is compared with the version before it.`,
+		Example: `
+# show what the last update of pipeline foo changed
+$ {{alias}} foo
+
+# show what changed in pipeline foo between versions 2 and 5
+$ {{alias}} foo 2 5`,
+		Run: cmdutil.RunBoundedArgs(1, 3, func(args []string) error {
+			var versions [2]uint64
+			for i, arg := range args[1:] {
+				v, err := strconv.ParseUint(arg, 10, 64)
+				if err != nil || v == 0 {
+					return errors.Errorf("invalid pipeline version %q", arg)
+				}
+				versions[i] = v
+			}
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			response, err := client.DiffPipeline(args[0], versions[0], versions[1])
+			if err != nil {
+				return err
+			}
+			if raw {
+				return encoder(output).EncodeProto(response)
+			} else if output != "" {
+				cmdutil.ErrorAndExit("cannot set --output (-o) without --raw")
+			}
+			fmt.Print(response.Summary)
+			return nil
+		}),
+	}
+	diffPipeline.Flags().AddFlagSet(outputFlags)
+	shell.RegisterCompletionFunc(diffPipeline, shell.PipelineCompletion)
+	commands = append(commands, cmdutil.CreateAlias(diffPipeline, "diff pipeline"))
+
 	extractPipeline := &cobra.Command{
 		Use:   "{{alias}} <pipeline>",
 		Short: "Return the manifest used to create a pipeline.",
@@ -797,10 +1360,21 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 	commands = append(commands, cmdutil.CreateAlias(editPipeline, "edit pipeline"))
 
 	var spec bool
+	var idle bool
+	var idleWithin time.Duration
 	listPipeline := &cobra.Command{
 		Use:   "{{alias}} [<pipeline>]",
 		Short: "Return info about all pipelines.",
-		Long:  "Return info about all pipelines.",
+		Long: `Return info about all pipelines.
+
+With --idle, only pipelines that the idle reaper has flagged, or will stop or
+flag within --within, are listed (soonest first), along with when they were
+last active. A pipeline is idle if it hasn't been created, updated, started,
+run a job or seen an input commit for longer than its idle timeout, which is
+set by the cluster's PPS_IDLE_TIMEOUT and the pipeline's 'idle_policy'.`,
+		Example: `
+# List the pipelines that are idle, or will be within the next week
+$ {{alias}} --idle --within 168h`,
 		Run: cmdutil.RunBoundedArgs(0, 1, func(args []string) error {
 			// validate flags
 			if raw && spec {
@@ -808,6 +1382,12 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 			} else if !raw && !spec && output != "" {
 				cmdutil.ErrorAndExit("cannot set --output (-o) without --raw or --spec")
 			}
+			if idle {
+				if spec || len(args) > 0 || history != "none" || len(stateStrs) > 0 {
+					return errors.Errorf("--idle can't be combined with a pipeline name, --spec, --history or --state")
+				}
+				return listIdlePipelines(idleWithin, raw, output, fullTimestamps)
+			}
 			history, err := cmdutil.ParseHistory(history)
 			if err != nil {
 				return errors.Wrapf(err, "error parsing history flag")
@@ -873,6 +1453,8 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 	listPipeline.Flags().AddFlagSet(fullTimestampsFlags)
 	listPipeline.Flags().StringVar(&history, "history", "none", "Return revision history for pipelines.")
 	listPipeline.Flags().StringArrayVar(&stateStrs, "state", []string{}, "Return only pipelines with the specified state. Can be repeated to include multiple states")
+	listPipeline.Flags().BoolVar(&idle, "idle", false, "Return only pipelines that are idle, with their idle policies.")
+	listPipeline.Flags().DurationVar(&idleWithin, "within", 0, "With --idle, also return pipelines that will become idle within this long.")
 	commands = append(commands, cmdutil.CreateAlias(listPipeline, "list pipeline"))
 
 	var (
@@ -951,6 +1533,102 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 	}
 	commands = append(commands, cmdutil.CreateAlias(stopPipeline, "stop pipeline"))
 
+	approveCommit := &cobra.Command{
+		Use:   "{{alias}} <repo>@<commit>",
+		Short: "Approve a pending output commit of a gated pipeline, or a job paused after its smoke test.",
+		Long: `Approve a pending output commit of a gated pipeline, or a job paused after its smoke test.
+
+Gated pipelines commit their output to a pending branch (named after the
+output branch, e.g. "master-pending"). Approving one of those commits moves the
+pipeline's output branch to it, which triggers any downstream pipelines.
+
+The jobs of pipelines whose smoke test pauses stop after their smoke test
+passes. Approving a paused job's (unfinished) output commit lets the job
+process the rest of its datums.
+
+Only owners of the pipeline's output repo may approve commits.`,
+		Example: `
+# approve the latest pending output commit of the gated pipeline "model"
+$ {{alias}} model@master-pending
+
+# let the paused job of the pipeline "train" process the rest of its datums
+$ {{alias}} train@master`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			commit, err := cmdutil.ParseCommit(args[0])
+			if err != nil {
+				return err
+			}
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			if err := client.ApproveCommit(commit.Repo.Name, commit.ID); err != nil {
+				cmdutil.ErrorAndExit("error from ApproveCommit: %s", err.Error())
+			}
+			return nil
+		}),
+	}
+	commands = append(commands, cmdutil.CreateAlias(approveCommit, "approve commit"))
+
+	var unset []string
+	var replaceConfig bool
+	updatePipelineConfig := &cobra.Command{
+		Use:   "{{alias}} <pipeline> [<key>=<value>...]",
+		Short: "Update the runtime config of a pipeline.",
+		Long: `Update the runtime config of a pipeline.
+
+A pipeline's runtime config is exposed to its user code as JSON, both in the
+PACH_RUNTIME_CONFIG environment variable (which is refreshed for each datum)
+and in the file named by PACH_RUNTIME_CONFIG_FILE (which is rewritten as soon as
+the config is updated). Updating it doesn't restart the pipeline's workers or
+reprocess any data.`,
+		Example: `
+# set the threshold of the pipeline "filter" to 0.9, keeping its other settings
+$ {{alias}} filter threshold=0.9
+
+# remove the setting "debug", and replace "mode"
+$ {{alias}} filter mode=fast --unset debug
+
+# replace all of the pipeline's settings
+$ {{alias}} filter --replace threshold=0.5`,
+		Run: cmdutil.RunMinimumArgs(1, func(args []string) error {
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			config := make(map[string]string)
+			if !replaceConfig {
+				pipelineInfo, err := client.InspectPipeline(args[0])
+				if err != nil {
+					return err
+				}
+				for key, value := range pipelineInfo.RuntimeConfig {
+					config[key] = value
+				}
+			}
+			for _, arg := range args[1:] {
+				parts := strings.SplitN(arg, "=", 2)
+				if len(parts) != 2 {
+					return errors.Errorf("malformed setting %q (expected <key>=<value>)", arg)
+				}
+				config[parts[0]] = parts[1]
+			}
+			for _, key := range unset {
+				delete(config, key)
+			}
+			if err := client.UpdatePipelineConfig(args[0], config); err != nil {
+				cmdutil.ErrorAndExit("error from UpdatePipelineConfig: %s", err.Error())
+			}
+			return nil
+		}),
+	}
+	updatePipelineConfig.Flags().StringSliceVar(&unset, "unset", nil, "A setting to remove from the runtime config (may be repeated).")
+	updatePipelineConfig.Flags().BoolVar(&replaceConfig, "replace", false, "Replace the pipeline's runtime config, rather than updating its existing settings.")
+	shell.RegisterCompletionFunc(updatePipelineConfig, shell.PipelineCompletion)
+	commands = append(commands, cmdutil.CreateAlias(updatePipelineConfig, "update pipeline-config"))
+
 	var file string
 	createSecret := &cobra.Command{
 		Short: "Create a secret on the cluster.",
@@ -1063,6 +1741,57 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 	commands = append(commands, cmdutil.CreateAlias(listSecret, "list secret"))
 
 	var memory string
+	var pruneDryRun bool
+	pruneStats := &cobra.Command{
+		Use:   "{{alias}} [<pipeline>]",
+		Short: "Drop the detailed stats of old jobs.",
+		Long: `Drop the detailed stats of the jobs that exceed their pipeline's stats retention policy, and list them.
+
+A job's stats are pruned once it finished longer ago than the policy's max_age, or once keep_last newer jobs of its pipeline have stats. Each pipeline's 'stats_retention' overrides the cluster's policy, which is set by PPS_STATS_MAX_AGE and PPS_STATS_KEEP_LAST. The PPS master prunes stats every hour; this command prunes them right away. The pruned data is removed by the next garbage collection.
+
+The stats of pruned jobs can't be listed or inspected with 'list datum' and 'inspect datum', and 'inspect job' shows when they were pruned.`,
+		Example: `
+# List the stats that would be pruned, without pruning them
+$ {{alias}} --dry-run
+
+# Prune the stats of pipeline foo's old jobs
+$ {{alias}} foo`,
+		Run: cmdutil.RunBoundedArgs(0, 1, func(args []string) error {
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return errors.Wrapf(err, "error connecting to pachd")
+			}
+			defer client.Close()
+			var pipeline string
+			if len(args) > 0 {
+				pipeline = args[0]
+			}
+			pruned, err := client.PruneStats(pipeline, pruneDryRun)
+			if err != nil {
+				return err
+			}
+			if raw {
+				e := encoder(output)
+				for _, p := range pruned {
+					if err := e.EncodeProto(p); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			writer := tabwriter.NewWriter(os.Stdout, pretty.PrunedStatsHeader)
+			for _, p := range pruned {
+				pretty.PrintPrunedStats(writer, p, fullTimestamps)
+			}
+			return writer.Flush()
+		}),
+	}
+	pruneStats.Flags().BoolVar(&pruneDryRun, "dry-run", false, "Only list the stats that would be pruned.")
+	pruneStats.Flags().AddFlagSet(outputFlags)
+	pruneStats.Flags().AddFlagSet(fullTimestampsFlags)
+	shell.RegisterCompletionFunc(pruneStats, shell.PipelineCompletion)
+	commands = append(commands, cmdutil.CreateAlias(pruneStats, "prune stats"))
+
 	garbageCollect := &cobra.Command{
 		Short: "Garbage collect unused data.",
 		Long: `Garbage collect unused data.
@@ -1107,7 +1836,103 @@ you can increase the amount of memory used for the bloom filters with the
 	return commands
 }
 
-func pipelineHelper(reprocess bool, build bool, pushImages bool, registry, username, pipelinePath string, update bool) error {
+// listIdlePipelines prints the pipelines returned by ListIdlePipelines, for
+// 'list pipeline --idle'
+func listIdlePipelines(within time.Duration, raw bool, output string, fullTimestamps bool) error {
+	client, err := pachdclient.NewOnUserMachine("user")
+	if err != nil {
+		return errors.Wrapf(err, "error connecting to pachd")
+	}
+	defer client.Close()
+	idlePipelines, err := client.ListIdlePipelines(within)
+	if err != nil {
+		return err
+	}
+	if raw {
+		e := encoder(output)
+		for _, idle := range idlePipelines {
+			if err := e.EncodeProto(idle); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	writer := tabwriter.NewWriter(os.Stdout, pretty.IdlePipelineHeader)
+	for _, idle := range idlePipelines {
+		pretty.PrintIdlePipeline(writer, idle, fullTimestamps)
+	}
+	return writer.Flush()
+}
+
+// dagRequest reads the pipeline specs and repo definitions at 'path' (a file,
+// or a directory of .json, .yaml and .yml files) into an ApplyDAGRequest.
+func dagRequest(path string) (*ppsclient.ApplyDAGRequest, error) {
+	paths := []string{path}
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		paths = nil
+		if err := filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			switch filepath.Ext(p) {
+			case ".json", ".yaml", ".yml":
+				if !fi.IsDir() {
+					paths = append(paths, p)
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	result := &ppsclient.ApplyDAGRequest{}
+	for _, p := range paths {
+		reader, err := ppsutil.NewPipelineManifestReader(p)
+		if err != nil {
+			return nil, err
+		}
+		for {
+			pipelineRequest, repoRequest, err := reader.NextDAGEntry()
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, errors.Wrapf(err, "could not read %s", p)
+			}
+			if repoRequest != nil {
+				result.Repos = append(result.Repos, repoRequest)
+				continue
+			}
+			if pipelineRequest.Pipeline == nil || pipelineRequest.Pipeline.Name == "" {
+				return nil, errors.Errorf("could not read %s: no pipeline `name` specified", p)
+			}
+			result.Pipelines = append(result.Pipelines, pipelineRequest)
+		}
+	}
+	return result, nil
+}
+
+// checkPipelineUpdate warns about the ways in which 'request' may break the
+// pipelines downstream of the pipeline it updates, and returns an error if
+// it's sure to break one, unless 'force' is set.
+func checkPipelineUpdate(pc *pachdclient.APIClient, request *ppsclient.CreatePipelineRequest, force bool) error {
+	incompatibilities, err := pc.CheckPipelineUpdate(request)
+	if err != nil {
+		return err
+	}
+	var blocking int
+	for _, incompatibility := range incompatibilities {
+		fmt.Fprintf(os.Stderr, "WARNING: updating pipeline %q: %s\n", request.Pipeline.Name, incompatibility.Message)
+		if incompatibility.Blocking {
+			blocking++
+		}
+	}
+	if blocking > 0 && !force {
+		return errors.Errorf("updating pipeline %q would break %d downstream reference(s); use --force to update it anyway", request.Pipeline.Name, blocking)
+	}
+	return nil
+}
+
+func pipelineHelper(reprocess bool, build bool, pushImages bool, registry, username, pipelinePath string, update bool, force bool) error {
 	if build && pushImages {
 		logrus.Warning("`--push-images` is redundant, as it's already enabled with `--build`")
 	}
@@ -1213,6 +2038,12 @@ func pipelineHelper(reprocess bool, build bool, pushImages bool, registry, usern
 			}
 		}
 
+		if update {
+			if err := checkPipelineUpdate(pc, request, force); err != nil {
+				return err
+			}
+		}
+
 		if _, err := pc.PpsAPIClient.CreatePipeline(
 			pc.Ctx(),
 			request,
@@ -1537,3 +2368,33 @@ func ParsePipelineStates(stateStrs []string) (string, error) {
 	}
 	return validateJQConditionString(strings.Join(conditions, " or "))
 }
+
+// parseLogTime parses the value of 'pachctl logs --since' or '--until', which
+// is either an RFC3339 timestamp or a duration before 'now'. An empty value
+// parses to the zero time.
+func parseLogTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, errors.Errorf("could not parse %q as a timestamp (e.g. 2006-01-02T15:04:05Z) or a duration (e.g. 5m)", value)
+	}
+	return now.Add(-d), nil
+}
+
+// parseJobTime parses the value of 'pachctl list job --created-after' or
+// '--created-before' like parseLogTime. An empty value parses to nil.
+func parseJobTime(value string, now time.Time) (*types.Timestamp, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := parseLogTime(value, now)
+	if err != nil {
+		return nil, err
+	}
+	return types.TimestampProto(t)
+}
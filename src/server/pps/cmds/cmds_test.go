@@ -30,6 +30,9 @@ import (
 	"os"
 	"os/exec"
 	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
 
 	"github.com/pachyderm/pachyderm/src/client/pkg/require"
 	tu "github.com/pachyderm/pachyderm/src/server/pkg/testutil"
@@ -1026,3 +1029,35 @@ func TestNoWarningTagSpecified(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "", stderr)
 }
+
+func TestParseLogTime(t *testing.T) {
+	now := time.Date(2020, 6, 1, 14, 10, 0, 0, time.UTC)
+	ts, err := parseLogTime("", now)
+	require.NoError(t, err)
+	require.True(t, ts.IsZero())
+
+	ts, err = parseLogTime("2020-06-01T14:00:00Z", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2020, 6, 1, 14, 0, 0, 0, time.UTC), ts)
+
+	ts, err = parseLogTime("5m", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2020, 6, 1, 14, 5, 0, 0, time.UTC), ts)
+
+	_, err = parseLogTime("yesterday", now)
+	require.YesError(t, err)
+}
+
+func TestParseJobTime(t *testing.T) {
+	now := time.Date(2020, 6, 1, 14, 10, 0, 0, time.UTC)
+	ts, err := parseJobTime("", now)
+	require.NoError(t, err)
+	require.Nil(t, ts)
+
+	ts, err = parseJobTime("24h", now)
+	require.NoError(t, err)
+	require.Equal(t, &types.Timestamp{Seconds: now.Add(-24 * time.Hour).Unix()}, ts)
+
+	_, err = parseJobTime("yesterday", now)
+	require.YesError(t, err)
+}
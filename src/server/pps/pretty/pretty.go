@@ -2,12 +2,14 @@ package pretty
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 	"text/template"
+	"time"
 
 	units "github.com/docker/go-units"
 	"github.com/fatih/color"
@@ -17,6 +19,7 @@ import (
 	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
 	ppsclient "github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
 	"github.com/pachyderm/pachyderm/src/server/pkg/pretty"
 )
 
@@ -27,8 +30,20 @@ const (
 	JobHeader = "ID\tPIPELINE\tSTARTED\tDURATION\tRESTART\tPROGRESS\tDL\tUL\tSTATE\t\n"
 	// DatumHeader is the header for datums
 	DatumHeader = "ID\tSTATUS\tTIME\t\n"
+	// ResolvedPathHeader is the header for resolved datum paths
+	ResolvedPathHeader = "PATH\tDATUM\t\n"
 	// SecretHeader is the header for secrets
 	SecretHeader = "NAME\tTYPE\tCREATED\t\n"
+	// DAGChangeHeader is the header for the changes made by ApplyDAG
+	DAGChangeHeader = "ACTION\tKIND\tNAME\tREPROCESS\t\n"
+	// IdlePipelineHeader is the header for idle pipelines
+	IdlePipelineHeader = "NAME\tLAST ACTIVITY\tTIMEOUT\tACTION\tSTATUS\t\n"
+	// PrunedStatsHeader is the header for the stats pruned by PruneStats
+	PrunedStatsHeader = "JOB\tPIPELINE\tSTATS COMMIT\tFINISHED\tSIZE\tREASON\t\n"
+	// GitHookHeader is the header for git inputs
+	GitHookHeader = "NAME\tURL\tBRANCH\tPIPELINES\tLAST DELIVERY\tSTATE\t\n"
+	// GitHookDeliveryHeader is the header for githook deliveries
+	GitHookDeliveryHeader = "ID\tPROVIDER\tRECEIVED\tBRANCH\tSHA\tSTATE\tERROR\t\n"
 	// jobReasonLen is the amount of the job reason that we print
 	jobReasonLen = 25
 )
@@ -59,7 +74,12 @@ func PrintJobInfo(w io.Writer, jobInfo *ppsclient.JobInfo, fullTimestamps bool)
 	fmt.Fprintf(w, "%s\t", pretty.Size(jobInfo.Stats.DownloadBytes))
 	fmt.Fprintf(w, "%s\t", pretty.Size(jobInfo.Stats.UploadBytes))
 	if jobInfo.State == ppsclient.JobState_JOB_FAILURE {
-		fmt.Fprintf(w, "%s: %s\t", JobState(jobInfo.State), safeTrim(jobInfo.Reason, jobReasonLen))
+		reason := jobInfo.Reason
+		if jobInfo.ReasonDetails != nil {
+			// The rest of the rendered reason is shown by inspect job
+			reason = jobInfo.ReasonDetails.Message
+		}
+		fmt.Fprintf(w, "%s: %s\t", JobState(jobInfo.State), safeTrim(reason, jobReasonLen))
 	} else {
 		fmt.Fprintf(w, "%s\t", JobState(jobInfo.State))
 	}
@@ -92,7 +112,7 @@ func PrintPipelineInfo(w io.Writer, pipelineInfo *ppsclient.PipelineInfo, fullTi
 
 // PrintWorkerStatusHeader pretty prints a worker status header.
 func PrintWorkerStatusHeader(w io.Writer) {
-	fmt.Fprint(w, "WORKER\tJOB\tDATUM\tSTARTED\tQUEUE\t\n")
+	fmt.Fprint(w, "WORKER\tJOB\tDATUM\tSTARTED\tQUEUE\tDATUMS/S (DOWNLOAD/PROCESS/UPLOAD)\tCPU\tMEMORY\tRESTARTS\tLAST TERMINATION\t\n")
 }
 
 // PrintWorkerStatus pretty prints a worker status.
@@ -109,9 +129,49 @@ func PrintWorkerStatus(w io.Writer, workerStatus *ppsclient.WorkerStatus, fullTi
 		fmt.Fprintf(w, "%s\t", pretty.Ago(workerStatus.Started))
 	}
 	fmt.Fprintf(w, "%d\t", workerStatus.QueueSize)
+	if workerStatus.Process == nil {
+		fmt.Fprint(w, "-\t")
+	} else {
+		fmt.Fprintf(w, "%s / %s / %s\t", phaseThroughput(workerStatus.Download),
+			phaseThroughput(workerStatus.Process), phaseThroughput(workerStatus.Upload))
+	}
+	printWorkerResources(w, workerStatus.Resources)
 	fmt.Fprintln(w)
 }
 
+// phaseThroughput returns the number of datums per second that completed the
+// phase whose stats are 'stats', while any datums were in it.
+func phaseThroughput(stats *ppsclient.PhaseStats) string {
+	if stats == nil {
+		return "-"
+	}
+	busy, err := types.DurationFromProto(stats.BusyTime)
+	if err != nil || busy <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.3g", float64(stats.Completed)/busy.Seconds())
+}
+
+func printWorkerResources(w io.Writer, resources *ppsclient.WorkerResources) {
+	if resources == nil {
+		fmt.Fprint(w, "-\t-\t-\t-\t")
+		return
+	}
+	if resources.UsageSource == "" {
+		fmt.Fprint(w, "-\t-\t")
+	} else {
+		fmt.Fprintf(w, "%.3g\t%s\t", resources.Cpu, units.BytesSize(float64(resources.MemoryBytes)))
+	}
+	fmt.Fprintf(w, "%d\t", resources.Restarts)
+	if t := resources.LastTermination; t != nil {
+		fmt.Fprintf(w, "%s (exit %d) %s\t", t.Reason, t.ExitCode, pretty.Ago(t.Finished))
+	} else if resources.WaitingReason != "" {
+		fmt.Fprintf(w, "%s\t", resources.WaitingReason)
+	} else {
+		fmt.Fprint(w, "-\t")
+	}
+}
+
 // PrintableJobInfo is a wrapper around JobInfo containing any formatting options
 // used within the template to conditionally print information.
 type PrintableJobInfo struct {
@@ -136,23 +196,36 @@ Started: {{.Started}}{{else}}
 Started: {{prettyAgo .Started}} {{end}}{{if .Finished}}
 Duration: {{prettyTimeDifference .Started .Finished}} {{end}}
 State: {{jobState .State}}
-Reason: {{.Reason}}
+Reason: {{.Reason}}{{if .FailureCause}}
+Failure Cause: {{.FailureCause}}{{end}}
 Processed: {{.DataProcessed}}
 Failed: {{.DataFailed}}
 Skipped: {{.DataSkipped}}
 Recovered: {{.DataRecovered}}
-Total: {{.DataTotal}}
+Total: {{.DataTotal}}{{if .SmokeTest}}
+Smoke Test: {{smokeTestStatus .SmokeTest}}{{end}}
+Eviction Retries: {{.EvictionRetries}}
 Data Downloaded: {{prettySize .Stats.DownloadBytes}}
-Data Uploaded: {{prettySize .Stats.UploadBytes}}
+Data Uploaded: {{prettySize .Stats.UploadBytes}}{{if .Stats.DedupedFiles}}
+Data Deduplicated: {{prettySize .Stats.DedupedBytes}} in {{.Stats.DedupedFiles}} files{{end}}
 Download Time: {{prettyDuration .Stats.DownloadTime}}
 Process Time: {{prettyDuration .Stats.ProcessTime}}
-Upload Time: {{prettyDuration .Stats.UploadTime}}
+Upload Time: {{prettyDuration .Stats.UploadTime}}{{if .Stats.DroppedLogBytes}}
+Logs Dropped: {{prettySize .Stats.DroppedLogBytes}}{{end}}{{if .Stats.AssertionsFailed}}
+Assertions Failed: {{.Stats.AssertionsFailed}}{{end}}{{if .Stats.CorrectedReads}}
+Corrected Reads: {{.Stats.CorrectedReads}}{{end}}{{if .Stats.Retries}}
+Retries: {{.Stats.Retries}}{{end}}{{if .Stats.OutputFiles}}
+Output: {{.Stats.OutputFiles}} files, {{prettySize .Stats.OutputBytes}}{{end}}{{if .LargestDatums}}
+Largest Datums:{{range .LargestDatums}}
+  {{.Datum.ID}}: {{prettySize .OutputBytes}} in {{.OutputFiles}} files{{end}}{{end}}
 Datum Timeout: {{.DatumTimeout}}
 Job Timeout: {{.JobTimeout}}
 Worker Status:
 {{workerStatus .}}Restarts: {{.Restart}}
 ParallelismSpec: {{.ParallelismSpec}}
-{{ if .ResourceRequests }}ResourceRequests:
+{{ if .ScalingEvents }}Scaling:
+{{range .ScalingEvents}}  {{prettyAgo .Time}}: {{.FromWorkers}} -> {{.ToWorkers}} workers
+{{end}}{{end}}{{ if .ResourceRequests }}ResourceRequests:
   CPU: {{ .ResourceRequests.Cpu }}
   Memory: {{ .ResourceRequests.Memory }} {{end}}
 {{ if .ResourceLimits }}ResourceLimits:
@@ -171,7 +244,7 @@ ParallelismSpec: {{.ParallelismSpec}}
 Transform:
 {{prettyTransform .Transform}} {{if .OutputCommit}}
 Output Commit: {{.OutputCommit.ID}} {{end}} {{ if .StatsCommit }}
-Stats Commit: {{.StatsCommit.ID}} {{end}} {{ if .Egress }}
+Stats Commit: {{.StatsCommit.ID}}{{ if .StatsPruned }} (pruned {{prettyAgo .StatsPruned}}){{end}} {{end}} {{ if .Egress }}
 Egress: {{.Egress.URL}} {{end}}
 `)
 	if err != nil {
@@ -202,7 +275,9 @@ func NewPrintablePipelineInfo(pi *ppsclient.PipelineInfo) *PrintablePipelineInfo
 func PrintDetailedPipelineInfo(w io.Writer, pipelineInfo *PrintablePipelineInfo) error {
 	template, err := template.New("PipelineInfo").Funcs(funcMap).Parse(
 		`Name: {{.Pipeline.Name}}{{if .Description}}
-Description: {{.Description}}{{end}}{{if .FullTimestamps }}
+Description: {{.Description}}{{end}}{{if .Tenant}}
+Tenant: {{.Tenant}}{{end}}{{if .Residency}}
+Residency: {{.Residency}}{{end}}{{if .FullTimestamps }}
 Created: {{.CreatedAt}}{{ else }}
 Created: {{prettyAgo .CreatedAt}} {{end}}
 State: {{pipelineState .State}}
@@ -219,19 +294,75 @@ Parallelism Spec: {{.ParallelismSpec}}
   {{ if .ResourceLimits.Gpu }}GPU:
     Type: {{ .ResourceLimits.Gpu.Type }} 
     Number: {{ .ResourceLimits.Gpu.Number }} {{end}} {{end}}
-Datum Timeout: {{.DatumTimeout}}
-Job Timeout: {{.JobTimeout}}
+{{ if .OomRetry }}OOM Retry:
+  Max Memory: {{ .OomRetry.MaxMemory }}{{ if .OomRetry.MemoryMultiplier }}
+  Memory Multiplier: {{ .OomRetry.MemoryMultiplier }}{{end}}{{ range $key, $value := .OomRetry.NodeSelector }}
+  Node Selector: {{ $key }}={{ $value }}{{end}}
+{{end}}{{ if .RetrySpec }}Retry Spec:
+  Max Retries: {{ .RetrySpec.MaxRetries }}{{ if .RetrySpec.BackoffBase }}
+  Backoff Base: {{prettyDuration .RetrySpec.BackoffBase}}{{end}}{{ if .RetrySpec.MaxBackoff }}
+  Max Backoff: {{prettyDuration .RetrySpec.MaxBackoff}}{{end}}{{ if .RetrySpec.Jitter }}
+  Jitter: {{ .RetrySpec.Jitter }}{{end}}
+{{end}}{{ if .EscalatedMemory }}Escalated Memory: {{ .EscalatedMemory }}
+{{end}}{{ if .PeakMemoryBytes }}Peak Memory: {{prettySize .PeakMemoryBytes}}
+{{end}}{{ if .SuggestedMemoryRequest }}Suggested Memory Request: {{ .SuggestedMemoryRequest }}
+{{end}}{{ if .FileCache }}File Cache: enabled{{ if .FileCache.MaxFileBytes }}
+  Max File Bytes: {{ .FileCache.MaxFileBytes }}{{end}}{{ if .FileCache.SizeBytes }}
+  Size Bytes: {{ .FileCache.SizeBytes }}{{end}}{{ if .FileCache.Ttl }}
+  TTL: {{prettyDuration .FileCache.Ttl}}{{end}}{{ if .FileCache.RedisAddress }}
+  Redis: {{ .FileCache.RedisAddress }}{{end}}{{ if .FileCache.MemcachedAddresses }}
+  Memcached: {{ join .FileCache.MemcachedAddresses ", " }}{{end}}
+{{end}}{{ if .StatsRetention }}Stats Retention:{{ if .StatsRetention.MaxAge }}
+  Max Age: {{prettyDuration .StatsRetention.MaxAge}}{{end}}{{ if .StatsRetention.KeepLast }}
+  Keep Last: {{ .StatsRetention.KeepLast }}{{end}}
+{{end}}{{ if .OutputRetention }}Output Retention:{{ if .OutputRetention.MaxAge }}
+  Max Age: {{prettyDuration .OutputRetention.MaxAge}}{{end}}{{ if .OutputRetention.KeepLast }}
+  Keep Last: {{ .OutputRetention.KeepLast }}{{end}}
+{{end}}{{ if .DatumSizing }}Datum Sizing:
+  Command: {{ join .DatumSizing.Cmd " " }}{{ range .DatumSizing.WorkerClasses }}
+  Worker Class {{ .Name }}: {{ workerClass . }}{{end}}
+{{end}}{{ if .PhaseConcurrency }}Phase Concurrency:
+  Download: {{ if .PhaseConcurrency.Download }}{{ .PhaseConcurrency.Download }}{{ else }}1{{ end }}
+  Upload: {{ if .PhaseConcurrency.Upload }}{{ .PhaseConcurrency.Upload }}{{ else }}1{{ end }}
+{{end}}{{ if .Sandbox }}Sandbox: {{ .Sandbox.Runtime }}{{ if .Sandbox.RuntimeClass }} (RuntimeClass {{ .Sandbox.RuntimeClass }}){{end}}
+{{end}}Datum Timeout: {{.DatumTimeout}}
+Job Timeout: {{.JobTimeout}}{{ if .LogQuota }}
+Log Quota:
+  Datum Bytes: {{ .LogQuota.DatumBytes }}
+  Job Bytes: {{ .LogQuota.JobBytes }} {{end}}
 Input:
 {{pipelineInput .PipelineInfo}}
 {{ if .GithookURL }}Githook URL: {{.GithookURL}} {{end}}
 Output Branch: {{.OutputBranch}}
-Transform:
+{{ if .Gated }}Pending Branch: {{pendingBranch .OutputBranch}} (commits must be approved)
+{{end}}{{ if .Outputs }}Outputs:{{ range .Outputs }}
+  {{ .Name }}: {{ .Repo }}@{{ .Branch }}{{ end }}
+{{end}}{{ if .OutputRoutes }}Routed Branch: {{routedBranch .OutputBranch}}
+Output Routes:{{ range .OutputRoutes }}
+  {{ .Glob }}: {{ .Branch }}{{ end }}
+{{end}}{{ if .SmokeTest }}Smoke Test: {{ if .SmokeTest.Fraction }}{{ .SmokeTest.Fraction }} of datums{{ else }}{{ .SmokeTest.Datums }} datums{{ end }}{{ if .SmokeTest.Pause }}, then pause until approved{{ end }}
+{{end}}{{ if .JobGates }}Job Gates:{{ range .JobGates.Gates }}
+  {{ .Name }}: {{ if .HTTP }}GET {{ .HTTP.URL }}{{ else if .MarkerFile }}{{ .MarkerFile.Repo }}@{{ if .MarkerFile.Branch }}{{ .MarkerFile.Branch }}{{ else }}master{{ end }}:{{ .MarkerFile.Path }}{{ end }}{{ end }}
+{{end}}{{ if .GateStatus }}Held Commit: {{ .GateStatus.OutputCommit.ID }}{{ range .GateStatus.WaitingOn }}
+  {{ . }}{{ end }}
+{{end}}{{ if .Metadata }}{{ if .Metadata.Labels }}Labels:{{ range $key, $value := .Metadata.Labels }}
+  {{ $key }}: {{ $value }}{{ end }}
+{{end}}{{ if .Metadata.Annotations }}Annotations:{{ range $key, $value := .Metadata.Annotations }}
+  {{ $key }}: {{ $value }}{{ end }}
+{{end}}{{end}}{{ if .RuntimeConfig }}Runtime Config:{{ range $key, $value := .RuntimeConfig }}
+  {{ $key }}: {{ $value }}{{ end }}
+{{end}}Transform:
 {{prettyTransform .Transform}}
-{{ if .Egress }}Egress: {{.Egress.URL}} {{end}}
+{{ if .ImageDigest }}Image Digest: {{.ImageDigest}}
+{{end}}{{ if .AvailableImageDigest }}Newer Image Available: {{.AvailableImageDigest}}
+{{end}}{{ if .IdleSince }}Idle Since: {{prettyAgo .IdleSince}}
+{{end}}{{ if .ImagePrewarm }}Image Prewarmed: {{.ImagePrewarm.NodesReady}}/{{.ImagePrewarm.NodesTotal}} nodes
+{{end}}{{ if .Egress }}Egress: {{.Egress.URL}} {{end}}
 {{if .RecentError}} Recent Error: {{.RecentError}} {{end}}
 Job Counts:
 {{jobCounts .JobCounts}}
-`)
+{{ if .SkippingStats }}Datum Skipping:
+{{skippingStats .SkippingStats}}{{end}}`)
 	if err != nil {
 		return err
 	}
@@ -242,6 +373,18 @@ Job Counts:
 	return nil
 }
 
+// PrintResolvedPath pretty-prints the datums that contain an input path, one
+// per line.
+func PrintResolvedPath(w io.Writer, resolved *ppsclient.ResolvedPath) {
+	if len(resolved.Datums) == 0 {
+		fmt.Fprintf(w, "%s\t-\t\n", resolved.Path)
+		return
+	}
+	for _, datum := range resolved.Datums {
+		fmt.Fprintf(w, "%s\t%s\t\n", resolved.Path, datum.ID)
+	}
+}
+
 // PrintDatumInfo pretty-prints file info.
 // If recurse is false and directory size is 0, display "-" instead
 // If fast is true and file size is 0, display "-" instead
@@ -259,8 +402,30 @@ func PrintDetailedDatumInfo(w io.Writer, datumInfo *ppsclient.DatumInfo) {
 	fmt.Fprintf(w, "ID\t%s\n", datumInfo.Datum.ID)
 	fmt.Fprintf(w, "Job ID\t%s\n", datumInfo.Datum.Job.ID)
 	fmt.Fprintf(w, "State\t%s\n", datumInfo.State)
+	if datumInfo.FailureCause != ppsclient.FailureCause_FAILURE_NONE {
+		fmt.Fprintf(w, "Failure Cause\t%s\n", datumInfo.FailureCause)
+	}
+	fmt.Fprintf(w, "Seed\t%d\n", datumInfo.Seed)
+	if datumInfo.EscalatedMemory != "" {
+		fmt.Fprintf(w, "Escalated Memory\t%s\n", datumInfo.EscalatedMemory)
+	}
 	fmt.Fprintf(w, "Data Downloaded\t%s\n", pretty.Size(datumInfo.Stats.DownloadBytes))
+	if datumInfo.Stats.CachedBytes > 0 {
+		fmt.Fprintf(w, "Data Read From Cache\t%s\n", pretty.Size(datumInfo.Stats.CachedBytes))
+	}
+	if datumInfo.Stats.CorrectedReads > 0 {
+		fmt.Fprintf(w, "Corrected Reads\t%d\n", datumInfo.Stats.CorrectedReads)
+	}
+	if datumInfo.Stats.Retries > 0 {
+		fmt.Fprintf(w, "Retries\t%d\n", datumInfo.Stats.Retries)
+	}
 	fmt.Fprintf(w, "Data Uploaded\t%s\n", pretty.Size(datumInfo.Stats.UploadBytes))
+	if datumInfo.Stats.DedupedFiles > 0 {
+		fmt.Fprintf(w, "Data Deduplicated\t%s in %d files\n", pretty.Size(datumInfo.Stats.DedupedBytes), datumInfo.Stats.DedupedFiles)
+	}
+	if datumInfo.Stats.OutputFiles > 0 {
+		fmt.Fprintf(w, "Output\t%d files, %s\n", datumInfo.Stats.OutputFiles, pretty.Size(datumInfo.Stats.OutputBytes))
+	}
 
 	totalTime := client.GetDatumTotalTime(datumInfo.Stats).String()
 	fmt.Fprintf(w, "Total Time\t%s\n", totalTime)
@@ -306,11 +471,225 @@ func PrintDetailedDatumInfo(w io.Writer, datumInfo *ppsclient.DatumInfo) {
 	tw.Flush()
 }
 
+// PrintExplainedDatum pretty-prints why a datum was or wasn't skipped.
+func PrintExplainedDatum(w io.Writer, explained *ppsclient.ExplainDatumResponse) {
+	fmt.Fprintf(w, "ID\t%s\n", explained.Datum.ID)
+	fmt.Fprintf(w, "Job ID\t%s\n", explained.Datum.Job.ID)
+	fmt.Fprintf(w, "Hash\t%s\n", explained.Hash)
+	fmt.Fprintf(w, "Pipeline\t%s\n", explained.Pipeline)
+	fmt.Fprintf(w, "Salt\t%s\n", explained.Salt)
+	fmt.Fprintf(w, "Transform Hash\t%s\n", explained.TransformHash)
+	fmt.Fprintf(w, "Output Exists\t%t\n", explained.OutputExists)
+	if explained.PriorJob != nil {
+		fmt.Fprintf(w, "Previous Job\t%s\n", explained.PriorJob.ID)
+	}
+	if explained.PriorDatum != nil {
+		fmt.Fprintf(w, "Matching Datum\t%s\n", explained.PriorDatum.ID)
+	}
+	fmt.Fprintf(w, "Inputs:\n")
+	tw := ansiterm.NewTabWriter(w, 10, 1, 3, ' ', 0)
+	fmt.Fprintf(tw, "  NAME\tREPO\tPATH\tHASH\n")
+	for _, input := range explained.Inputs {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", input.Name, input.File.Commit.Repo.Name, input.File.Path, hex.EncodeToString(input.Hash))
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "Reasons:\n")
+	for _, reason := range explained.Reasons {
+		fmt.Fprintf(w, "  %s\n", reason)
+	}
+}
+
 // PrintSecretInfo pretty-prints secret info.
 func PrintSecretInfo(w io.Writer, secretInfo *ppsclient.SecretInfo) {
 	fmt.Fprintf(w, "%s\t%s\t%s\t\n", secretInfo.Secret.Name, secretInfo.Type, pretty.Ago(secretInfo.CreationTimestamp))
 }
 
+// PrintGitHookInfo pretty-prints a git input and its most recent delivery.
+func PrintGitHookInfo(w io.Writer, hookInfo *ppsclient.GitHookInfo, fullTimestamps bool) {
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t", hookInfo.Name, hookInfo.URL, hookInfo.Branch, strings.Join(hookInfo.Pipelines, ", "))
+	if len(hookInfo.Deliveries) == 0 {
+		fmt.Fprintf(w, "none\t-\t\n")
+		return
+	}
+	delivery := hookInfo.Deliveries[0]
+	fmt.Fprintf(w, "%s\t%s\t\n", deliveryTime(delivery, fullTimestamps), gitHookDeliveryState(delivery.State))
+}
+
+// PrintGitHookDelivery pretty-prints a githook delivery.
+func PrintGitHookDelivery(w io.Writer, delivery *ppsclient.GitHookDelivery, fullTimestamps bool) {
+	provider := delivery.Provider
+	if provider == "" {
+		provider = "-"
+	}
+	sha := delivery.SHA
+	if len(sha) > 12 {
+		sha = sha[:12]
+	}
+	state := gitHookDeliveryState(delivery.State)
+	if delivery.ReplayOf != "" {
+		state += fmt.Sprintf(" (replay of %s)", delivery.ReplayOf)
+	}
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", delivery.ID, provider, deliveryTime(delivery, fullTimestamps),
+		delivery.Branch, sha, state, safeTrim(delivery.Error, 80))
+}
+
+// PrintDetailedGitHookInfo pretty-prints a git input and its recent
+// deliveries.
+func PrintDetailedGitHookInfo(w io.Writer, hookInfo *ppsclient.GitHookInfo, fullTimestamps bool) error {
+	fmt.Fprintf(w, "Name: %s\n", hookInfo.Name)
+	fmt.Fprintf(w, "URL: %s\n", hookInfo.URL)
+	fmt.Fprintf(w, "Branch: %s\n", hookInfo.Branch)
+	fmt.Fprintf(w, "Pipelines: %s\n", strings.Join(hookInfo.Pipelines, ", "))
+	fmt.Fprintf(w, "Githook URL: %s\n", hookInfo.GithookURL)
+	if len(hookInfo.Deliveries) == 0 {
+		fmt.Fprintf(w, "Deliveries: none\n")
+		return nil
+	}
+	fmt.Fprintf(w, "Deliveries:\n")
+	tw := ansiterm.NewTabWriter(w, 20, 1, 3, ' ', 0)
+	fmt.Fprint(tw, GitHookDeliveryHeader)
+	for _, delivery := range hookInfo.Deliveries {
+		PrintGitHookDelivery(tw, delivery, fullTimestamps)
+	}
+	return tw.Flush()
+}
+
+func deliveryTime(delivery *ppsclient.GitHookDelivery, fullTimestamps bool) string {
+	if fullTimestamps {
+		return delivery.Received.String()
+	}
+	return pretty.Ago(delivery.Received)
+}
+
+func gitHookDeliveryState(state ppsclient.GitHookDeliveryState) string {
+	switch state {
+	case ppsclient.GitHookDeliveryState_GIT_HOOK_DELIVERY_SUCCEEDED:
+		return color.New(color.FgGreen).SprintFunc()("succeeded")
+	case ppsclient.GitHookDeliveryState_GIT_HOOK_DELIVERY_FAILED:
+		return color.New(color.FgRed).SprintFunc()("failed")
+	}
+	return "ignored"
+}
+
+// PrintDAGChange pretty-prints a change made by ApplyDAG.
+func PrintDAGChange(w io.Writer, change *ppsclient.DAGChange) {
+	action := strings.ToLower(strings.TrimPrefix(change.Action.String(), "DAG_"))
+	if change.Repo != nil {
+		fmt.Fprintf(w, "%s\trepo\t%s\t-\t\n", action, change.Repo.Name)
+		return
+	}
+	reprocess := "-"
+	if change.Action == ppsclient.DAGAction_DAG_UPDATE {
+		reprocess = fmt.Sprintf("%t", change.Reprocess)
+	}
+	fmt.Fprintf(w, "%s\tpipeline\t%s\t%s\t\n", action, change.Pipeline.Name, reprocess)
+}
+
+// PrintIdlePipeline pretty-prints a pipeline returned by ListIdlePipelines.
+func PrintIdlePipeline(w io.Writer, idle *ppsclient.IdlePipeline, fullTimestamps bool) {
+	fmt.Fprintf(w, "%s\t", idle.Pipeline.Name)
+	if fullTimestamps {
+		fmt.Fprintf(w, "%s\t", idle.LastActivity.String())
+	} else {
+		fmt.Fprintf(w, "%s\t", pretty.Ago(idle.LastActivity))
+	}
+	fmt.Fprintf(w, "%s\t", pretty.Duration(idle.Timeout))
+	action := strings.ToLower(strings.TrimPrefix(idle.Action.String(), "IDLE_"))
+	fmt.Fprintf(w, "%s\t", action)
+	reapAt, _ := types.TimestampFromProto(idle.ReapAt)
+	switch {
+	case idle.IdleSince != nil:
+		fmt.Fprintf(w, "flagged %s\t\n", pretty.Ago(idle.IdleSince))
+	case reapAt.After(time.Now()):
+		fmt.Fprintf(w, "due in %s\t\n", units.HumanDuration(time.Until(reapAt)))
+	default:
+		fmt.Fprintf(w, "due\t\n")
+	}
+}
+
+// PrintPrunedStats pretty-prints the stats of a job returned by PruneStats.
+func PrintPrunedStats(w io.Writer, pruned *ppsclient.PrunedStats, fullTimestamps bool) {
+	fmt.Fprintf(w, "%s\t%s\t%s\t", pruned.Job.ID, pruned.Pipeline.Name, pruned.StatsCommit.ID)
+	if fullTimestamps {
+		fmt.Fprintf(w, "%s\t", pruned.Finished.String())
+	} else {
+		fmt.Fprintf(w, "%s\t", pretty.Ago(pruned.Finished))
+	}
+	fmt.Fprintf(w, "%s\t%s\t\n", pretty.Size(pruned.SizeBytes), pruned.Reason)
+}
+
+// PrintPipelineSchedule pretty-prints a pipeline's unfinished jobs and
+// workers, as returned by GetScheduler.
+func PrintPipelineSchedule(w io.Writer, schedule *ppsclient.PipelineSchedule, fullTimestamps bool) {
+	fmt.Fprintf(w, "Pipeline\t%s\n", schedule.Pipeline.Name)
+	fmt.Fprintf(w, "State\t%s\n", pipelineState(schedule.State))
+	if schedule.Reason != "" {
+		fmt.Fprintf(w, "Reason\t%s\n", schedule.Reason)
+	}
+	fmt.Fprintf(w, "Workers\t%d/%d ready\n", schedule.ReadyWorkers, schedule.DesiredWorkers)
+	if len(schedule.WorkerProblems) > 0 {
+		fmt.Fprintf(w, "Worker Problems:\n")
+		for _, problem := range schedule.WorkerProblems {
+			fmt.Fprintf(w, "  %s\n", problem)
+		}
+	}
+	if admission := schedule.QueueAdmission; admission != nil {
+		fmt.Fprintf(w, "Queue Admission:\n")
+		fmt.Fprintf(w, "  Scheduler\t%s\n", admission.Scheduler)
+		if admission.Queue != "" {
+			fmt.Fprintf(w, "  Queue\t%s\n", admission.Queue)
+		}
+		fmt.Fprintf(w, "  Admitted\t%t\n", admission.Admitted)
+		if admission.Status != "" {
+			fmt.Fprintf(w, "  Status\t%s\n", admission.Status)
+		}
+		if admission.Message != "" {
+			fmt.Fprintf(w, "  Message\t%s\n", admission.Message)
+		}
+	}
+	if status := schedule.GateStatus; status != nil {
+		fmt.Fprintf(w, "Held Commit %s:\n", status.OutputCommit.ID)
+		if fullTimestamps {
+			fmt.Fprintf(w, "  Held Since\t%s\n", status.Since.String())
+			fmt.Fprintf(w, "  Checked\t%s\n", status.Checked.String())
+		} else {
+			fmt.Fprintf(w, "  Held Since\t%s\n", pretty.Ago(status.Since))
+			fmt.Fprintf(w, "  Checked\t%s\n", pretty.Ago(status.Checked))
+		}
+		fmt.Fprintf(w, "  Waiting On:\n")
+		for _, reason := range status.WaitingOn {
+			fmt.Fprintf(w, "    %s\n", reason)
+		}
+	}
+	for _, job := range schedule.Jobs {
+		fmt.Fprintf(w, "Job %s:\n", job.Job.ID)
+		fmt.Fprintf(w, "  State\t%s\n", JobState(job.State))
+		if fullTimestamps {
+			fmt.Fprintf(w, "  Started\t%s\n", job.Started.String())
+		} else {
+			fmt.Fprintf(w, "  Started\t%s\n", pretty.Ago(job.Started))
+		}
+		if c := job.Chunks; c != nil {
+			fmt.Fprintf(w, "  Chunks\t%d pending, %d claimed, %d succeeded, %d failed, %d requeued\n",
+				c.Pending, c.Claimed, c.Succeeded, c.Failed, c.Requeued)
+		}
+		if len(job.WaitingOn) > 0 {
+			fmt.Fprintf(w, "  Waiting On:\n")
+			for _, reason := range job.WaitingOn {
+				fmt.Fprintf(w, "    %s\n", reason)
+			}
+		}
+		if len(job.Workers) > 0 {
+			tw := ansiterm.NewTabWriter(w, 20, 1, 3, ' ', 0)
+			PrintWorkerStatusHeader(tw)
+			for _, workerStatus := range job.Workers {
+				PrintWorkerStatus(tw, workerStatus, fullTimestamps)
+			}
+			tw.Flush()
+		}
+	}
+}
+
 // PrintFileHeader prints the header for a pfs file.
 func PrintFileHeader(w io.Writer) {
 	fmt.Fprintf(w, "  REPO\tCOMMIT\tPATH\t\n")
@@ -424,6 +803,28 @@ func jobCounts(counts map[int32]int32) string {
 	return buffer.String()
 }
 
+func datumCounts(counts *ppsclient.DatumCounts) string {
+	if counts == nil {
+		counts = &ppsclient.DatumCounts{}
+	}
+	var skippedPct float64
+	if counts.Total > 0 {
+		skippedPct = 100 * float64(counts.Skipped) / float64(counts.Total)
+	}
+	return fmt.Sprintf("%d jobs, %d processed, %d skipped (%.1f%%), %d reused, %s not downloaded",
+		counts.Jobs, counts.Processed, counts.Skipped, skippedPct, counts.Reused, pretty.Size(counts.SkippedBytes))
+}
+
+func skippingStats(stats *ppsclient.SkippingStats) string {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "  Since Creation: %s\n", datumCounts(stats.SinceCreation))
+	fmt.Fprintf(&buffer, "  Since Update: %s\n", datumCounts(stats.SinceUpdate))
+	if ppsutil.SkippingDisabled(stats) {
+		fmt.Fprintf(&buffer, "  WARNING: no datums have been skipped since the pipeline was last updated\n")
+	}
+	return buffer.String()
+}
+
 func prettyTransform(transform *ppsclient.Transform) (string, error) {
 	result, err := json.MarshalIndent(transform, "", "  ")
 	if err != nil {
@@ -465,6 +866,24 @@ func ShorthandInput(input *ppsclient.Input) string {
 		return "(" + strings.Join(subInput, " ∪ ") + ")"
 	case input.Cron != nil:
 		return fmt.Sprintf("%s:%s", input.Cron.Name, input.Cron.Spec)
+	case input.HTTPMirror != nil:
+		return fmt.Sprintf("%s:%s", input.HTTPMirror.Name, input.HTTPMirror.Spec)
+	case input.Parameter != nil:
+		var names []string
+		for _, p := range input.Parameter.Parameters {
+			names = append(names, p.Name)
+		}
+		return fmt.Sprintf("%s:(%s)", input.Parameter.Name, strings.Join(names, " ⨯ "))
+	case input.RemoteRepo != nil:
+		return fmt.Sprintf("%s:%s@%s", input.RemoteRepo.PachdAddress, input.RemoteRepo.SourceRepo, input.RemoteRepo.SourceBranch)
+	case input.Volume != nil:
+		return fmt.Sprintf("%s:%s", input.Volume.Name, input.Volume.MountPath)
+	case input.DBImport != nil:
+		var tables []string
+		for _, t := range input.DBImport.Tables {
+			tables = append(tables, t.Name)
+		}
+		return fmt.Sprintf("%s:%s(%s)", input.DBImport.Name, input.DBImport.Driver, strings.Join(tables, ", "))
 	}
 	return ""
 }
@@ -481,5 +900,59 @@ var funcMap = template.FuncMap{
 	"prettyDuration":       pretty.Duration,
 	"prettySize":           pretty.Size,
 	"jobCounts":            jobCounts,
+	"skippingStats":        skippingStats,
 	"prettyTransform":      prettyTransform,
+	"pendingBranch":        ppsutil.PendingBranch,
+	"routedBranch":         ppsutil.RoutedBranch,
+	"smokeTestStatus":      smokeTestStatus,
+	"join":                 strings.Join,
+	"workerClass":          workerClass,
+}
+
+// workerClass describes the workers of a pipeline's worker class
+func workerClass(class *ppsclient.WorkerClass) string {
+	workers := class.Parallelism
+	if workers == 0 {
+		workers = 1
+	}
+	parts := []string{fmt.Sprintf("%d workers", workers)}
+	if class.ResourceRequests != nil {
+		parts = append(parts, "requests "+resourceSpec(class.ResourceRequests))
+	}
+	if class.ResourceLimits != nil {
+		parts = append(parts, "limits "+resourceSpec(class.ResourceLimits))
+	}
+	for key, value := range class.NodeSelector {
+		parts = append(parts, fmt.Sprintf("node selector %s=%s", key, value))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// resourceSpec describes the resources in 'spec'
+func resourceSpec(spec *ppsclient.ResourceSpec) string {
+	var parts []string
+	if spec.Cpu != 0 {
+		parts = append(parts, fmt.Sprintf("cpu %v", spec.Cpu))
+	}
+	if spec.Memory != "" {
+		parts = append(parts, "memory "+spec.Memory)
+	}
+	if spec.Gpu != nil {
+		parts = append(parts, fmt.Sprintf("gpu %d %s", spec.Gpu.Number, spec.Gpu.Type))
+	}
+	if spec.Disk != "" {
+		parts = append(parts, "disk "+spec.Disk)
+	}
+	return strings.Join(parts, " ")
+}
+
+// smokeTestStatus describes the progress of a job's smoke test
+func smokeTestStatus(status *ppsclient.SmokeTestStatus) string {
+	switch {
+	case status.Approved != nil:
+		return fmt.Sprintf("%d datums passed, approved", status.Datums)
+	case status.Passed != nil:
+		return fmt.Sprintf("%d datums passed", status.Datums)
+	}
+	return fmt.Sprintf("%d datums", status.Datums)
 }
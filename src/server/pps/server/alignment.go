@@ -0,0 +1,31 @@
+package server
+
+import (
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+)
+
+// validateAlignment returns an error if the alignment of 'pipelineInfo' (if
+// any) can't be evaluated. It must be called after the pipeline's defaults
+// (e.g. the names of its inputs) have been set.
+func validateAlignment(pipelineInfo *pps.PipelineInfo) error {
+	alignment := pipelineInfo.Alignment
+	if alignment == nil {
+		return nil
+	}
+	if alignment.MetadataKey == "" {
+		return errors.New("invalid pipeline spec: alignment must specify a metadata_key")
+	}
+	if pipelineInfo.S3Out || pipelineInfo.Spout != nil || pipelineInfo.Service != nil {
+		return errors.New("invalid pipeline spec: alignment isn't supported for spouts, services, or pipelines that output via Pachyderm's S3 gateway")
+	}
+	inputs, missing := ppsutil.AlignedInputs(alignment, pipelineInfo.Input)
+	if len(missing) > 0 {
+		return errors.Errorf("invalid pipeline spec: alignment refers to inputs %v, which aren't PFS inputs of the pipeline", missing)
+	}
+	if len(inputs) < 2 {
+		return errors.New("invalid pipeline spec: alignment requires at least two PFS inputs")
+	}
+	return nil
+}
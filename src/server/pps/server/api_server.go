@@ -24,6 +24,7 @@ import (
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
 	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/tenant"
 	"github.com/pachyderm/pachyderm/src/client/pkg/tracing"
 	"github.com/pachyderm/pachyderm/src/client/pkg/tracing/extended"
 	"github.com/pachyderm/pachyderm/src/client/pps"
@@ -31,6 +32,8 @@ import (
 	"github.com/pachyderm/pachyderm/src/server/pkg/ancestry"
 	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
 	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/deleteall"
+	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
 	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
 	"github.com/pachyderm/pachyderm/src/server/pkg/log"
 	"github.com/pachyderm/pachyderm/src/server/pkg/lokiutil"
@@ -40,26 +43,31 @@ import (
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
 	"github.com/pachyderm/pachyderm/src/server/pkg/serde"
 	"github.com/pachyderm/pachyderm/src/server/pkg/serviceenv"
+	"github.com/pachyderm/pachyderm/src/server/pkg/tenantutil"
 	txnenv "github.com/pachyderm/pachyderm/src/server/pkg/transactionenv"
 	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
 	"github.com/pachyderm/pachyderm/src/server/pkg/watch"
 	ppsServer "github.com/pachyderm/pachyderm/src/server/pps"
-	"github.com/pachyderm/pachyderm/src/server/pps/server/githook"
 	workercommon "github.com/pachyderm/pachyderm/src/server/worker/common"
 	"github.com/pachyderm/pachyderm/src/server/worker/datum"
 	workerserver "github.com/pachyderm/pachyderm/src/server/worker/server"
 
 	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/protobuf/types"
 	opentracing "github.com/opentracing/opentracing-go"
+	glob "github.com/pachyderm/ohmyglob"
 	"github.com/robfig/cron"
 	logrus "github.com/sirupsen/logrus"
 	"github.com/willf/bloom"
 	"golang.org/x/net/context"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	kube "k8s.io/client-go/kubernetes"
 )
 
@@ -70,6 +78,12 @@ const (
 	// DefaultDatumTries is the default number of times a datum will be tried
 	// before we give up and consider the job failed.
 	DefaultDatumTries = 3
+	// DefaultMirrorSpec is the cron spec used by http_mirror inputs that don't
+	// specify one.
+	DefaultMirrorSpec = "@hourly"
+	// DefaultDBImportSpec is the cron spec used by db_import inputs that don't
+	// specify one.
+	DefaultDBImportSpec = "@hourly"
 )
 
 var (
@@ -123,6 +137,8 @@ type apiServer struct {
 	// collections
 	pipelines col.Collection
 	jobs      col.Collection
+	// the number of pipelines that each tenant owns
+	tenantPipelines col.Collection
 }
 
 func merge(from, to map[string]bool) {
@@ -181,6 +197,31 @@ func validateNames(names map[string]bool, input *pps.Input) error {
 			return errors.Errorf(`name "%s" was used more than once`, input.Git.Name)
 		}
 		names[input.Git.Name] = true
+	case input.HTTPMirror != nil:
+		if names[input.HTTPMirror.Name] {
+			return errors.Errorf(`name "%s" was used more than once`, input.HTTPMirror.Name)
+		}
+		names[input.HTTPMirror.Name] = true
+	case input.Parameter != nil:
+		if names[input.Parameter.Name] {
+			return errors.Errorf(`name "%s" was used more than once`, input.Parameter.Name)
+		}
+		names[input.Parameter.Name] = true
+	case input.RemoteRepo != nil:
+		if names[input.RemoteRepo.Name] {
+			return errors.Errorf(`name "%s" was used more than once`, input.RemoteRepo.Name)
+		}
+		names[input.RemoteRepo.Name] = true
+	case input.Volume != nil:
+		if names[input.Volume.Name] {
+			return errors.Errorf(`name "%s" was used more than once`, input.Volume.Name)
+		}
+		names[input.Volume.Name] = true
+	case input.DBImport != nil:
+		if names[input.DBImport.Name] {
+			return errors.Errorf(`name "%s" was used more than once`, input.DBImport.Name)
+		}
+		names[input.DBImport.Name] = true
 	}
 	return nil
 }
@@ -189,6 +230,9 @@ func (a *apiServer) validateInput(pachClient *client.APIClient, pipelineName str
 	if err := validateNames(make(map[string]bool), input); err != nil {
 		return err
 	}
+	if err := validateVolumePlacement(input); err != nil {
+		return err
+	}
 	var result error
 	pps.VisitInput(input, func(input *pps.Input) {
 		if err := func() error {
@@ -224,6 +268,37 @@ func (a *apiServer) validateInput(pachClient *client.APIClient, pipelineName str
 						"'empty_files', as 's3' requires input data to be accessed via " +
 						"Pachyderm's S3 gateway rather than the file system")
 				}
+				if input.Pfs.Partition != nil {
+					if err := datum.ValidatePartition(input.Pfs.Partition); err != nil {
+						return err
+					}
+					if glob, _ := datum.PartitionGlob(input.Pfs.Partition); input.Pfs.Glob != glob {
+						return errors.Errorf("input with a partition must not set 'glob', "+
+							"as it's determined by the partition's layout (%q)", glob)
+					}
+				}
+				if input.Pfs.Split != nil {
+					if input.Pfs.S3 {
+						return errors.Errorf("input cannot specify both 's3' and 'split', " +
+							"as splits are exposed as directories of the input")
+					}
+					if err := datum.ValidateSplit(input.Pfs.Split); err != nil {
+						return errors.Wrapf(err, "invalid split of input %q", input.Pfs.Name)
+					}
+				}
+				if input.Pfs.GroupFiles != nil {
+					if input.Pfs.S3 {
+						return errors.Errorf("input cannot specify both 's3' and " +
+							"'group_files', as 's3' inputs aren't split into datums")
+					}
+					if input.Pfs.Split != nil {
+						return errors.Errorf("input cannot specify both 'split' and " +
+							"'group_files', as splits are assigned one file at a time")
+					}
+					if err := datum.ValidateGroupFiles(input.Pfs.GroupFiles); err != nil {
+						return errors.Wrapf(err, "invalid group_files of input %q", input.Pfs.Name)
+					}
+				}
 				// Note that input.Pfs.Commit is empty if a) this is a job b) one of
 				// the job pipeline's input branches has no commits yet
 				if job && input.Pfs.Commit != "" {
@@ -236,6 +311,13 @@ func (a *apiServer) validateInput(pachClient *client.APIClient, pipelineName str
 					if _, err := pachClient.InspectRepo(input.Pfs.Repo); err != nil {
 						return err
 					}
+					// ...and that a pinned input commit is a commit tag, which
+					// always refers to the same commit
+					if input.Pfs.Commit != "" {
+						if _, err := pachClient.InspectCommitTag(input.Pfs.Repo, input.Pfs.Commit); err != nil {
+							return errors.Wrapf(err, "a pipeline's input can only be pinned to a commit tag")
+						}
+					}
 				}
 			}
 			if input.Cross != nil {
@@ -255,6 +337,14 @@ func (a *apiServer) validateInput(pachClient *client.APIClient, pipelineName str
 					// them until we know how they should work
 					return errors.Errorf("S3 inputs in join expressions are not supported")
 				}
+				if err := validateNoGroupFiles("join", input.Join); err != nil {
+					return err
+				}
+				if !job {
+					if err := validateJoinOn(input.Join); err != nil {
+						return err
+					}
+				}
 			}
 			if input.Group != nil {
 				if set {
@@ -266,6 +356,9 @@ func (a *apiServer) validateInput(pachClient *client.APIClient, pipelineName str
 					// we know how they should work
 					return errors.Errorf("S3 inputs in group expressions are not supported")
 				}
+				if err := validateNoGroupFiles("group", input.Group); err != nil {
+					return err
+				}
 			}
 			if input.Union != nil {
 				if set {
@@ -286,8 +379,8 @@ func (a *apiServer) validateInput(pachClient *client.APIClient, pipelineName str
 				if len(input.Cron.Name) == 0 {
 					return errors.Errorf("input must specify a name")
 				}
-				if _, err := cron.ParseStandard(input.Cron.Spec); err != nil {
-					return errors.Wrapf(err, "error parsing cron-spec")
+				if _, err := newCronSchedule(input.Cron); err != nil {
+					return err
 				}
 			}
 			if input.Git != nil {
@@ -300,6 +393,84 @@ func (a *apiServer) validateInput(pachClient *client.APIClient, pipelineName str
 					return err
 				}
 			}
+			if input.HTTPMirror != nil {
+				if set {
+					return errors.Errorf("multiple input types set")
+				}
+				set = true
+				if len(input.HTTPMirror.Name) == 0 {
+					return errors.Errorf("input must specify a name")
+				}
+				if len(input.HTTPMirror.URLs) == 0 && input.HTTPMirror.Manifest == "" {
+					return errors.Errorf("http_mirror input must specify urls or a manifest")
+				}
+				if input.HTTPMirror.BytesPerSecond < 0 {
+					return errors.Errorf("http_mirror input cannot have a negative bytes_per_second")
+				}
+				if _, err := cron.ParseStandard(input.HTTPMirror.Spec); err != nil {
+					return errors.Wrapf(err, "error parsing http_mirror spec")
+				}
+				if err := validateMirrorURLs(input.HTTPMirror); err != nil {
+					return err
+				}
+			}
+			if input.Parameter != nil {
+				if set {
+					return errors.Errorf("multiple input types set")
+				}
+				set = true
+				if len(input.Parameter.Name) == 0 {
+					return errors.Errorf("input must specify a name")
+				}
+				if err := validateParameterInput(input.Parameter); err != nil {
+					return err
+				}
+			}
+			if input.RemoteRepo != nil {
+				if set {
+					return errors.Errorf("multiple input types set")
+				}
+				set = true
+				if len(input.RemoteRepo.Name) == 0 {
+					return errors.Errorf("input must specify a name")
+				}
+				if len(input.RemoteRepo.SourceRepo) == 0 {
+					return errors.Errorf("remote_repo input must specify a source_repo")
+				}
+				if len(input.RemoteRepo.Glob) == 0 {
+					return errors.Errorf("input must specify a glob")
+				}
+				if _, err := grpcutil.ParsePachdAddress(input.RemoteRepo.PachdAddress); err != nil {
+					return errors.Wrapf(err, "invalid remote_repo pachd_address")
+				}
+			}
+			if input.Volume != nil {
+				if set {
+					return errors.Errorf("multiple input types set")
+				}
+				set = true
+				if err := validateVolumeInput(input.Volume); err != nil {
+					return err
+				}
+			}
+			if input.DBImport != nil {
+				if set {
+					return errors.Errorf("multiple input types set")
+				}
+				set = true
+				if len(input.DBImport.Name) == 0 {
+					return errors.Errorf("input must specify a name")
+				}
+				if len(input.DBImport.Glob) == 0 {
+					return errors.Errorf("input must specify a glob")
+				}
+				if _, err := cron.ParseStandard(input.DBImport.Spec); err != nil {
+					return errors.Wrapf(err, "error parsing db_import spec")
+				}
+				if err := validateDBImportInput(input.DBImport); err != nil {
+					return err
+				}
+			}
 			if !set {
 				return errors.Errorf("no input set")
 			}
@@ -318,6 +489,24 @@ func validateTransform(transform *pps.Transform) error {
 	if transform.Image == "" {
 		return errors.Errorf("pipeline transform must contain an image")
 	}
+	if transform.Builtin != nil {
+		if len(transform.Cmd) > 0 || len(transform.ErrCmd) > 0 || transform.Build != nil {
+			return errors.Errorf("a builtin transform cannot be combined with cmd, err_cmd or build")
+		}
+		if transform.Builtin.Repo == "" || transform.Builtin.Path == "" {
+			return errors.Errorf("a builtin transform must specify the repo and path of its plugin")
+		}
+	}
+	for _, secret := range transform.EnvFromSecrets {
+		if errs := validation.IsDNS1123Subdomain(secret.Name); len(errs) > 0 {
+			return errors.Errorf("invalid secret name %q in env_from_secrets: %s", secret.Name, strings.Join(errs, "; "))
+		}
+		if secret.Prefix != "" {
+			if errs := validation.IsEnvVarName(secret.Prefix); len(errs) > 0 {
+				return errors.Errorf("invalid prefix %q of secret %q in env_from_secrets: %s", secret.Prefix, secret.Name, strings.Join(errs, "; "))
+			}
+		}
+	}
 	return nil
 }
 
@@ -422,6 +611,8 @@ const (
 	pipelineOpUpdate
 	// pipelineOpUpdate is required for DeletePipeline
 	pipelineOpDelete
+	// pipelineOpApprove is required for ApproveCommit
+	pipelineOpApprove
 )
 
 // authorizePipelineOp checks if the user indicated by 'ctx' is authorized
@@ -502,6 +693,8 @@ func (a *apiServer) authorizePipelineOp(pachClient *client.APIClient, operation
 			return nil
 		}
 		required = auth.Scope_OWNER
+	case pipelineOpApprove:
+		required = auth.Scope_OWNER
 	default:
 		return errors.Errorf("internal error, unrecognized operation %v", operation)
 	}
@@ -554,9 +747,27 @@ func (a *apiServer) UpdateJobStateInTransaction(txnCtx *txnenv.TransactionContex
 	jobPtr.DataFailed = request.DataFailed
 	jobPtr.DataRecovered = request.DataRecovered
 	jobPtr.DataTotal = request.DataTotal
+	jobPtr.EvictionRetries = request.EvictionRetries
+	jobPtr.FailureCause = request.FailureCause
 	jobPtr.Stats = request.Stats
+	jobPtr.LargestDatums = request.LargestDatums
+	if request.SmokeTest != nil {
+		// Only ApproveCommit approves a job's smoke test
+		smokeTest := *request.SmokeTest
+		smokeTest.Approved = jobPtr.SmokeTest.GetApproved()
+		jobPtr.SmokeTest = &smokeTest
+	}
 
-	return ppsutil.UpdateJobState(a.pipelines.ReadWrite(txnCtx.Stm), jobs, jobPtr, request.State, request.Reason)
+	reason := request.Reason
+	if details := request.ReasonDetails; details != nil && ppsutil.IsTerminal(request.State) {
+		if details.Event == "" && ppsutil.IsKubeJobFailure(details.Category) {
+			details.Event = a.workerWarningEvent(jobPtr.Pipeline.Name)
+		}
+		reason = ppsutil.RenderJobReason(details)
+	}
+	jobPtr.ReasonDetails = request.ReasonDetails
+
+	return ppsutil.UpdateJobState(a.pipelines.ReadWrite(txnCtx.Stm), jobs, jobPtr, request.State, reason)
 }
 
 // CreateJob implements the protobuf pps.CreateJob RPC
@@ -575,19 +786,21 @@ func (a *apiServer) CreateJob(ctx context.Context, request *pps.CreateJobRequest
 	}
 	_, err = col.NewSTM(ctx, a.env.GetEtcdClient(), func(stm col.STM) error {
 		jobPtr := &pps.EtcdJobInfo{
-			Job:           job,
-			OutputCommit:  request.OutputCommit,
-			Pipeline:      request.Pipeline,
-			Stats:         request.Stats,
-			Restart:       request.Restart,
-			DataProcessed: request.DataProcessed,
-			DataSkipped:   request.DataSkipped,
-			DataTotal:     request.DataTotal,
-			DataFailed:    request.DataFailed,
-			DataRecovered: request.DataRecovered,
-			StatsCommit:   request.StatsCommit,
-			Started:       request.Started,
-			Finished:      request.Finished,
+			Job:                job,
+			OutputCommit:       request.OutputCommit,
+			Pipeline:           request.Pipeline,
+			Stats:              request.Stats,
+			Restart:            request.Restart,
+			DataProcessed:      request.DataProcessed,
+			DataSkipped:        request.DataSkipped,
+			DataTotal:          request.DataTotal,
+			DataFailed:         request.DataFailed,
+			DataRecovered:      request.DataRecovered,
+			StatsCommit:        request.StatsCommit,
+			Started:            request.Started,
+			Finished:           request.Finished,
+			SeedSalt:           request.SeedSalt,
+			VolumeFingerprints: request.VolumeFingerprints,
 		}
 		return ppsutil.UpdateJobState(a.pipelines.ReadWrite(stm), a.jobs.ReadWrite(stm), jobPtr, request.State, request.Reason)
 	})
@@ -618,7 +831,7 @@ func (a *apiServer) InspectJob(ctx context.Context, request *pps.InspectJobReque
 		if err != nil {
 			return nil, err
 		}
-		if err := a.listJob(pachClient, nil, ci.Commit, nil, -1, false, "", func(ji *pps.JobInfo) error {
+		if _, err := a.listJob(pachClient, &pps.ListJobRequest{OutputCommit: ci.Commit, History: -1}, func(ji *pps.JobInfo) error {
 			if request.Job != nil {
 				return errors.Errorf("internal error, more than 1 Job has output commit: %v (this is likely a bug)", request.OutputCommit)
 			}
@@ -641,7 +854,9 @@ func (a *apiServer) InspectJob(ctx context.Context, request *pps.InspectJobReque
 		for {
 			ev, ok := <-watcher.Watch()
 			if !ok {
-				return nil, errors.Errorf("the stream for job updates closed unexpectedly")
+				// This happens when pachd is shutting down, so the caller may
+				// retry (see client.WithBlockingRetryBudget)
+				return nil, status.Error(codes.Unavailable, "the stream for job updates closed unexpectedly")
 			}
 			switch ev.Type {
 			case watch.EventError:
@@ -655,6 +870,9 @@ func (a *apiServer) InspectJob(ctx context.Context, request *pps.InspectJobReque
 					return nil, err
 				}
 				if ppsutil.IsTerminal(jobPtr.State) {
+					if err := a.checkTenant(pachClient, jobPtr.Pipeline.Name); err != nil {
+						return nil, err
+					}
 					return a.jobInfoFromPtr(pachClient, jobPtr, true)
 				}
 			}
@@ -664,6 +882,9 @@ func (a *apiServer) InspectJob(ctx context.Context, request *pps.InspectJobReque
 	if err := jobs.Get(request.Job.ID, jobPtr); err != nil {
 		return nil, err
 	}
+	if err := a.checkTenant(pachClient, jobPtr.Pipeline.Name); err != nil {
+		return nil, err
+	}
 	jobInfo, err := a.jobInfoFromPtr(pachClient, jobPtr, true)
 	if err != nil {
 		return nil, err
@@ -676,6 +897,7 @@ func (a *apiServer) InspectJob(ctx context.Context, request *pps.InspectJobReque
 		}
 		workerPoolID := ppsutil.PipelineRcName(jobInfo.Pipeline.Name, jobInfo.PipelineVersion)
 		workerStatus, err := workerserver.Status(ctx, workerPoolID, a.env.GetEtcdClient(), a.etcdPrefix, a.workerGrpcPort)
+		otherJobs := make(map[string]bool)
 		if err != nil {
 			logrus.Errorf("failed to get worker status with err: %s", err.Error())
 		} else {
@@ -686,25 +908,32 @@ func (a *apiServer) InspectJob(ctx context.Context, request *pps.InspectJobReque
 				if status.JobID == jobInfo.Job.ID {
 					jobInfo.WorkerStatus = append(jobInfo.WorkerStatus, status)
 					jobInfo.DataProcessed += status.DataProcessed
+				} else {
+					otherJobs[status.WorkerID] = true
 				}
 			}
 		}
+		jobInfo.WorkerStatus = a.addWorkerResources(workerPoolID, jobInfo.WorkerStatus, otherJobs)
 	}
 	return jobInfo, nil
 }
 
 // listJob is the internal implementation of ListJob shared between ListJob and
 // ListJobStream. When ListJob is removed, this should be inlined into
-// ListJobStream.
-func (a *apiServer) listJob(pachClient *client.APIClient, pipeline *pps.Pipeline,
-	outputCommit *pfs.Commit, inputCommits []*pfs.Commit, history int64, full bool,
-	jqFilter string, f func(*pps.JobInfo) error) error {
+// ListJobStream. If 'request' sets a page size, listJob returns the token of
+// the next page, if there is one.
+func (a *apiServer) listJob(pachClient *client.APIClient, request *pps.ListJobRequest, f func(*pps.JobInfo) error) (string, error) {
+	pipeline, outputCommit, inputCommits := request.Pipeline, request.OutputCommit, request.InputCommit
+	filter, err := newJobFilter(request)
+	if err != nil {
+		return "", err
+	}
 	authIsActive := true
 	me, err := pachClient.WhoAmI(pachClient.Ctx(), &auth.WhoAmIRequest{})
 	if auth.IsErrNotActivated(err) {
 		authIsActive = false
 	} else if err != nil {
-		return err
+		return "", err
 	}
 	if authIsActive && pipeline != nil {
 		// If 'pipeline is set, check that caller has access to the pipeline's
@@ -719,10 +948,10 @@ func (a *apiServer) listJob(pachClient *client.APIClient, pipeline *pps.Pipeline
 			Scope: auth.Scope_READER,
 		})
 		if err != nil {
-			return err
+			return "", err
 		}
 		if !resp.Authorized {
-			return &auth.ErrNotAuthorized{
+			return "", &auth.ErrNotAuthorized{
 				Subject:  me.Username,
 				Repo:     pipeline.Name,
 				Required: auth.Scope_READER,
@@ -732,44 +961,56 @@ func (a *apiServer) listJob(pachClient *client.APIClient, pipeline *pps.Pipeline
 	if outputCommit != nil {
 		outputCommit, err = a.resolveCommit(pachClient, outputCommit)
 		if err != nil {
-			return err
+			return "", err
 		}
 	}
 	for i, inputCommit := range inputCommits {
 		inputCommits[i], err = a.resolveCommit(pachClient, inputCommit)
 		if err != nil {
-			return err
+			return "", err
 		}
 	}
 	var jqCode *gojq.Code
 	var enc serde.Encoder
 	var jsonBuffer bytes.Buffer
-	if jqFilter != "" {
-		jqQuery, err := gojq.Parse(jqFilter)
+	if request.JqFilter != "" {
+		jqQuery, err := gojq.Parse(request.JqFilter)
 		if err != nil {
-			return err
+			return "", err
 		}
 		jqCode, err = gojq.Compile(jqQuery)
 		if err != nil {
-			return err
+			return "", err
 		}
 		// ensure field names and enum values match with --raw output
 		enc = serde.NewJSONEncoder(&jsonBuffer, serde.WithOrigName(true))
 	}
 	// specCommits holds the specCommits of pipelines that we're interested in
 	specCommits := make(map[string]bool)
-	if err := a.listPipelinePtr(pachClient, pipeline, history,
+	if err := a.listPipelinePtr(pachClient, pipeline, request.History,
 		func(name string, ptr *pps.EtcdPipelineInfo) error {
 			specCommits[ptr.SpecCommit.ID] = true
 			return nil
 		}); err != nil {
-		return err
+		return "", err
 	}
 	jobs := a.jobs.ReadOnly(pachClient.Ctx())
 	jobPtr := &pps.EtcdJobInfo{}
+	var sent int64
+	var nextPageToken string
+	var more bool
 	_f := func(string) error {
+		if filter.skipping {
+			// Skip the jobs up to (and including) the last job of the
+			// previous page
+			filter.skipping = jobPtr.Job.ID != request.PageToken
+			return nil
+		}
+		if !filter.match(jobPtr) {
+			return nil
+		}
 		jobInfo, err := a.jobInfoFromPtr(pachClient, jobPtr,
-			len(inputCommits) > 0 || full)
+			len(inputCommits) > 0 || request.Full)
 		if err != nil {
 			if isNotFoundErr(err) {
 				// This can happen if a user deletes an upstream commit and thereby
@@ -814,35 +1055,64 @@ func (a *apiServer) listJob(pachClient *client.APIClient, pipeline *pps.Pipeline
 				return nil
 			}
 		}
-		return f(jobInfo)
+		if request.PageSize > 0 && sent == request.PageSize {
+			// There's another page, which starts after the last job sent
+			more = true
+			return errutil.ErrBreak
+		}
+		if err := f(jobInfo); err != nil {
+			return err
+		}
+		sent++
+		nextPageToken = jobInfo.Job.ID
+		return nil
 	}
 	if pipeline != nil {
-		return jobs.GetByIndex(ppsdb.JobsPipelineIndex, pipeline, jobPtr, col.DefaultOptions, _f)
+		err = jobs.GetByIndex(ppsdb.JobsPipelineIndex, pipeline, jobPtr, col.DefaultOptions, _f)
 	} else if outputCommit != nil {
-		return jobs.GetByIndex(ppsdb.JobsOutputIndex, outputCommit, jobPtr, col.DefaultOptions, _f)
+		err = jobs.GetByIndex(ppsdb.JobsOutputIndex, outputCommit, jobPtr, col.DefaultOptions, _f)
 	} else {
-		return jobs.List(jobPtr, col.DefaultOptions, _f)
+		err = jobs.List(jobPtr, col.DefaultOptions, _f)
+	}
+	if err != nil {
+		return "", err
+	}
+	if filter.skipping {
+		return "", errors.Errorf("invalid page token %q: job not found (it may have been deleted)", request.PageToken)
+	}
+	if !more {
+		return "", nil
 	}
+	return nextPageToken, nil
 }
 
 func (a *apiServer) jobInfoFromPtr(pachClient *client.APIClient, jobPtr *pps.EtcdJobInfo, full bool) (*pps.JobInfo, error) {
 	result := &pps.JobInfo{
-		Job:           jobPtr.Job,
-		Pipeline:      jobPtr.Pipeline,
-		OutputRepo:    &pfs.Repo{Name: jobPtr.Pipeline.Name},
-		OutputCommit:  jobPtr.OutputCommit,
-		Restart:       jobPtr.Restart,
-		DataProcessed: jobPtr.DataProcessed,
-		DataSkipped:   jobPtr.DataSkipped,
-		DataTotal:     jobPtr.DataTotal,
-		DataFailed:    jobPtr.DataFailed,
-		DataRecovered: jobPtr.DataRecovered,
-		Stats:         jobPtr.Stats,
-		StatsCommit:   jobPtr.StatsCommit,
-		State:         jobPtr.State,
-		Reason:        jobPtr.Reason,
-		Started:       jobPtr.Started,
-		Finished:      jobPtr.Finished,
+		Job:                jobPtr.Job,
+		Pipeline:           jobPtr.Pipeline,
+		OutputRepo:         &pfs.Repo{Name: jobPtr.Pipeline.Name},
+		OutputCommit:       jobPtr.OutputCommit,
+		Restart:            jobPtr.Restart,
+		DataProcessed:      jobPtr.DataProcessed,
+		DataSkipped:        jobPtr.DataSkipped,
+		DataTotal:          jobPtr.DataTotal,
+		DataFailed:         jobPtr.DataFailed,
+		DataRecovered:      jobPtr.DataRecovered,
+		EvictionRetries:    jobPtr.EvictionRetries,
+		FailureCause:       jobPtr.FailureCause,
+		ScalingEvents:      jobPtr.ScalingEvents,
+		SeedSalt:           jobPtr.SeedSalt,
+		VolumeFingerprints: jobPtr.VolumeFingerprints,
+		Stats:              jobPtr.Stats,
+		StatsCommit:        jobPtr.StatsCommit,
+		StatsPruned:        jobPtr.StatsPruned,
+		LargestDatums:      jobPtr.LargestDatums,
+		SmokeTest:          jobPtr.SmokeTest,
+		State:              jobPtr.State,
+		Reason:             jobPtr.Reason,
+		ReasonDetails:      jobPtr.ReasonDetails,
+		Started:            jobPtr.Started,
+		Finished:           jobPtr.Finished,
 	}
 	commitInfo, err := pachClient.InspectCommit(jobPtr.OutputCommit.Repo.Name, jobPtr.OutputCommit.ID)
 	if err != nil {
@@ -889,12 +1159,14 @@ func (a *apiServer) jobInfoFromPtr(pachClient *client.APIClient, jobPtr *pps.Etc
 		result.ResourceLimits = pipelineInfo.ResourceLimits
 		result.SidecarResourceLimits = pipelineInfo.SidecarResourceLimits
 		result.Input = ppsutil.JobInput(pipelineInfo, commitInfo)
+		ppsutil.SetVolumeFingerprints(result.Input, jobPtr.VolumeFingerprints)
 		result.EnableStats = pipelineInfo.EnableStats
 		result.Salt = pipelineInfo.Salt
 		result.ChunkSpec = pipelineInfo.ChunkSpec
 		result.DatumTimeout = pipelineInfo.DatumTimeout
 		result.JobTimeout = pipelineInfo.JobTimeout
 		result.DatumTries = pipelineInfo.DatumTries
+		result.RetrySpec = pipelineInfo.RetrySpec
 		result.SchedulingSpec = pipelineInfo.SchedulingSpec
 		result.PodSpec = pipelineInfo.PodSpec
 		result.PodPatch = pipelineInfo.PodPatch
@@ -915,13 +1187,14 @@ func (a *apiServer) ListJob(ctx context.Context, request *pps.ListJobRequest) (r
 	}(time.Now())
 	pachClient := a.env.GetPachClient(ctx)
 	var jobInfos []*pps.JobInfo
-	if err := a.listJob(pachClient, request.Pipeline, request.OutputCommit, request.InputCommit, request.History, request.Full, request.JqFilter, func(ji *pps.JobInfo) error {
+	nextPageToken, err := a.listJob(pachClient, request, func(ji *pps.JobInfo) error {
 		jobInfos = append(jobInfos, ji)
 		return nil
-	}); err != nil {
+	})
+	if err != nil {
 		return nil, err
 	}
-	return &pps.JobInfos{JobInfo: jobInfos}, nil
+	return &pps.JobInfos{JobInfo: jobInfos, NextPageToken: nextPageToken}, nil
 }
 
 // ListJobStream implements the protobuf pps.ListJobStream RPC
@@ -932,13 +1205,14 @@ func (a *apiServer) ListJobStream(request *pps.ListJobRequest, resp pps.API_List
 		a.Log(request, fmt.Sprintf("stream containing %d JobInfos", sent), retErr, time.Since(start))
 	}(time.Now())
 	pachClient := a.env.GetPachClient(resp.Context())
-	return a.listJob(pachClient, request.Pipeline, request.OutputCommit, request.InputCommit, request.History, request.Full, request.JqFilter, func(ji *pps.JobInfo) error {
+	_, err := a.listJob(pachClient, request, func(ji *pps.JobInfo) error {
 		if err := resp.Send(ji); err != nil {
 			return err
 		}
 		sent++
 		return nil
 	})
+	return err
 }
 
 // FlushJob implements the protobuf pps.FlushJob RPC
@@ -957,11 +1231,15 @@ func (a *apiServer) FlushJob(request *pps.FlushJobRequest, resp pps.API_FlushJob
 	for _, pipeline := range request.ToPipelines {
 		toRepos = append(toRepos, client.NewRepo(pipeline.Name))
 	}
+	skip := make(map[string]bool)
+	for _, job := range request.SkipJobs {
+		skip[job.ID] = true
+	}
 	return pachClient.FlushCommitF(request.Commits, toRepos, func(ci *pfs.CommitInfo) error {
 		var jis []*pps.JobInfo
 		// FlushJob passes -1 for history because we don't know which version
 		// of the pipeline created the output commit.
-		if err := a.listJob(pachClient, nil, ci.Commit, nil, -1, false, "", func(ji *pps.JobInfo) error {
+		if _, err := a.listJob(pachClient, &pps.ListJobRequest{OutputCommit: ci.Commit, History: -1}, func(ji *pps.JobInfo) error {
 			jis = append(jis, ji)
 			return nil
 		}); err != nil {
@@ -978,6 +1256,9 @@ func (a *apiServer) FlushJob(request *pps.FlushJobRequest, resp pps.API_FlushJob
 		if len(jis) > 1 {
 			return errors.Errorf("found too many jobs (%d) for output commit: %s/%s", len(jis), ci.Commit.Repo.Name, ci.Commit.ID)
 		}
+		if skip[jis[0].Job.ID] {
+			return nil
+		}
 		// Even though the commit has been finished the job isn't necessarily
 		// finished yet, so we block on its state as well.
 		ji, err := a.InspectJob(ctx, &pps.InspectJobRequest{Job: jis[0].Job, BlockState: true})
@@ -1030,6 +1311,9 @@ func (a *apiServer) stopJob(ctx context.Context, pachClient *client.APIClient, j
 	if err := a.jobs.ReadOnly(ctx).Get(job.ID, jobPtr); err != nil {
 		return err
 	}
+	if err := a.checkTenant(pachClient, jobPtr.Pipeline.Name); err != nil {
+		return err
+	}
 	// Finish the job's output commit without a tree -- worker/master will mark
 	// the job 'killed'
 	if _, err := pachClient.PfsAPIClient.FinishCommit(ctx,
@@ -1044,6 +1328,20 @@ func (a *apiServer) stopJob(ctx context.Context, pachClient *client.APIClient, j
 	return nil
 }
 
+// ScaleJob implements the protobuf pps.ScaleJob RPC
+func (a *apiServer) ScaleJob(ctx context.Context, request *pps.ScaleJobRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.env.GetPachClient(ctx)
+	if _, err := checkLoggedIn(pachClient); err != nil {
+		return nil, err
+	}
+	if err := a.scaleJob(pachClient, request.Job, request.Workers); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
 // RestartDatum implements the protobuf pps.RestartDatum RPC
 func (a *apiServer) RestartDatum(ctx context.Context, request *pps.RestartDatumRequest) (response *types.Empty, retErr error) {
 	func() { a.Log(request, nil, nil, 0) }()
@@ -1096,6 +1394,9 @@ func (a *apiServer) listDatum(pachClient *client.APIClient, job *pps.Job, page,
 	); err != nil {
 		return nil, err
 	}
+	if jobInfo.StatsPruned != nil {
+		return nil, errStatsPruned(jobInfo)
+	}
 
 	// helper functions for pagination
 	getTotalPages := func(totalSize int) int64 {
@@ -1144,13 +1445,14 @@ func (a *apiServer) listDatum(pachClient *client.APIClient, job *pps.Job, page,
 		var datumInfos []*pps.DatumInfo
 		for i := start; i < end; i++ {
 			datum := dit.DatumN(i) // flattened slice of *worker.Input to job
-			id := workercommon.HashDatum(jobInfo.Pipeline.Name, jobInfo.Salt, datum)
+			id := workercommon.DatumID(jobInfo.Salt, datum)
 			datumInfo := &pps.DatumInfo{
 				Datum: &pps.Datum{
 					ID:  id,
 					Job: jobInfo.Job,
 				},
 				State: pps.DatumState_STARTING,
+				Seed:  ppsutil.DatumSeed(ppsutil.JobSeedSalt(jobInfo), id),
 			}
 			for _, input := range datum {
 				datumInfo.Data = append(datumInfo.Data, input.FileInfo)
@@ -1214,7 +1516,7 @@ func (a *apiServer) listDatum(pachClient *client.APIClient, job *pps.Job, page,
 				// not a datum
 				return nil
 			}
-			datum, err := a.getDatum(pachClient, jobInfo.StatsCommit.Repo.Name, jobInfo.StatsCommit, job.ID, datumHash, dit)
+			datum, err := a.getDatum(pachClient, jobInfo.StatsCommit.Repo.Name, jobInfo.StatsCommit, job.ID, datumHash, ppsutil.JobSeedSalt(jobInfo), dit)
 			if err != nil {
 				return err
 			}
@@ -1289,13 +1591,14 @@ func (a *apiServer) ListDatumStream(req *pps.ListDatumRequest, resp pps.API_List
 	return nil
 }
 
-func (a *apiServer) getDatum(pachClient *client.APIClient, repo string, commit *pfs.Commit, jobID string, datumID string, dit datum.Iterator) (datumInfo *pps.DatumInfo, retErr error) {
+func (a *apiServer) getDatum(pachClient *client.APIClient, repo string, commit *pfs.Commit, jobID string, datumID string, seedSalt string, dit datum.Iterator) (datumInfo *pps.DatumInfo, retErr error) {
 	datumInfo = &pps.DatumInfo{
 		Datum: &pps.Datum{
 			ID:  datumID,
 			Job: client.NewJob(jobID),
 		},
 		State: pps.DatumState_SUCCESS,
+		Seed:  ppsutil.DatumSeed(seedSalt, datumID),
 	}
 	ctx := pachClient.Ctx()
 	pfsClient := pachClient.PfsAPIClient
@@ -1326,6 +1629,22 @@ func (a *apiServer) getDatum(pachClient *client.APIClient, repo string, commit *
 
 	// Populate stats
 	var buffer bytes.Buffer
+	if datumInfo.State == pps.DatumState_FAILED {
+		// Datums that failed before failure causes were recorded have no
+		// failure_cause file
+		if err := pachClient.GetFile(commit.Repo.Name, commit.ID, fmt.Sprintf("/%v/failure_cause", datumID), 0, 0, &buffer); err == nil {
+			datumInfo.FailureCause = pps.FailureCause(pps.FailureCause_value[buffer.String()])
+		} else if !isNotFoundErr(err) {
+			return nil, err
+		}
+		buffer.Reset()
+	}
+	if err := pachClient.GetFile(commit.Repo.Name, commit.ID, fmt.Sprintf("/%v/escalated_memory", datumID), 0, 0, &buffer); err == nil {
+		datumInfo.EscalatedMemory = buffer.String()
+	} else if !isNotFoundErr(err) {
+		return nil, err
+	}
+	buffer.Reset()
 	if err := pachClient.GetFile(commit.Repo.Name, commit.ID, fmt.Sprintf("/%v/stats", datumID), 0, 0, &buffer); err != nil {
 		return nil, err
 	}
@@ -1379,6 +1698,9 @@ func (a *apiServer) InspectDatum(ctx context.Context, request *pps.InspectDatumR
 	if !jobInfo.EnableStats {
 		return nil, errors.Errorf("stats not enabled on %v", jobInfo.Pipeline.Name)
 	}
+	if jobInfo.StatsPruned != nil {
+		return nil, errStatsPruned(jobInfo)
+	}
 	if jobInfo.StatsCommit == nil {
 		return nil, errors.Errorf("job not finished, no stats output yet")
 	}
@@ -1388,7 +1710,7 @@ func (a *apiServer) InspectDatum(ctx context.Context, request *pps.InspectDatumR
 	}
 
 	// Populate datumInfo given a path
-	datumInfo, err := a.getDatum(pachClient, jobInfo.StatsCommit.Repo.Name, jobInfo.StatsCommit, request.Datum.Job.ID, request.Datum.ID, dit)
+	datumInfo, err := a.getDatum(pachClient, jobInfo.StatsCommit.Repo.Name, jobInfo.StatsCommit, request.Datum.Job.ID, request.Datum.ID, ppsutil.JobSeedSalt(jobInfo), dit)
 	if err != nil {
 		return nil, err
 	}
@@ -1396,6 +1718,179 @@ func (a *apiServer) InspectDatum(ctx context.Context, request *pps.InspectDatumR
 	return datumInfo, nil
 }
 
+// ResolveDatum implements the protobuf pps.ResolveDatum RPC
+func (a *apiServer) ResolveDatum(ctx context.Context, request *pps.ResolveDatumRequest) (response *pps.ResolveDatumResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.env.GetPachClient(ctx)
+	ctx, err := checkLoggedIn(pachClient)
+	if err != nil {
+		return nil, err
+	}
+	if request.Job == nil {
+		return nil, errors.New("must specify a job")
+	}
+	jobInfo, err := a.InspectJob(ctx, &pps.InspectJobRequest{
+		Job: &pps.Job{
+			ID: request.Job.ID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// ResolveDatum reveals the same information as ListDatum, so it's
+	// authorized the same way
+	if err := a.authorizePipelineOp(pachClient,
+		pipelineOpListDatum,
+		jobInfo.Input,
+		jobInfo.Pipeline.Name,
+	); err != nil {
+		return nil, err
+	}
+
+	response = &pps.ResolveDatumResponse{}
+	for _, p := range request.Paths {
+		response.Resolved = append(response.Resolved, &pps.ResolvedPath{Path: p})
+	}
+	// Datum IDs only depend on the datums' inputs, so they can be computed
+	// from the job's input without reading the job's stats (which is what makes
+	// ListDatum slow for large jobs)
+	dit, err := datum.NewIterator(pachClient, jobInfo.Input)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < dit.Len(); i++ {
+		inputs := dit.DatumN(i)
+		var id string
+		for _, resolved := range response.Resolved {
+			if !datumContainsPath(inputs, resolved.Path) {
+				continue
+			}
+			if id == "" {
+				id = workercommon.DatumID(jobInfo.Salt, inputs)
+			}
+			resolved.Datums = append(resolved.Datums, &pps.Datum{
+				ID:  id,
+				Job: jobInfo.Job,
+			})
+		}
+	}
+	return response, nil
+}
+
+// datumContainsPath returns true if one of 'inputs' is the file at 'p', or a
+// directory containing it.
+func datumContainsPath(inputs []*workercommon.Input, p string) bool {
+	p = path.Clean("/" + p)
+	for _, input := range inputs {
+		if input.VolumePath != "" {
+			continue // volumes aren't in PFS
+		}
+		inputPath := path.Clean("/" + input.FileInfo.File.Path)
+		if p == inputPath || inputPath == "/" || strings.HasPrefix(p, inputPath+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ExplainDatum implements the protobuf pps.ExplainDatum RPC
+func (a *apiServer) ExplainDatum(ctx context.Context, request *pps.ExplainDatumRequest) (response *pps.ExplainDatumResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.env.GetPachClient(ctx)
+	ctx, err := checkLoggedIn(pachClient)
+	if err != nil {
+		return nil, err
+	}
+	if request.Datum == nil || request.Datum.Job == nil {
+		return nil, errors.New("must specify a datum and its job")
+	}
+	jobInfo, err := a.InspectJob(ctx, &pps.InspectJobRequest{
+		Job: &pps.Job{
+			ID: request.Datum.Job.ID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// ExplainDatum reveals the datum's inputs, so it's authorized like
+	// ListDatum
+	if err := a.authorizePipelineOp(pachClient,
+		pipelineOpListDatum,
+		jobInfo.Input,
+		jobInfo.Pipeline.Name,
+	); err != nil {
+		return nil, err
+	}
+	datums := func(jobInfo *pps.JobInfo) ([][]*workercommon.Input, error) {
+		dit, err := datum.NewIterator(pachClient, jobInfo.Input)
+		if err != nil {
+			return nil, err
+		}
+		var result [][]*workercommon.Input
+		for i := 0; i < dit.Len(); i++ {
+			result = append(result, dit.DatumN(i))
+		}
+		return result, nil
+	}
+	jobDatums, err := datums(jobInfo)
+	if err != nil {
+		return nil, err
+	}
+	var inputs []*workercommon.Input
+	for _, datumInputs := range jobDatums {
+		if workercommon.DatumID(jobInfo.Salt, datumInputs) == request.Datum.ID {
+			inputs = datumInputs
+			break
+		}
+	}
+	if inputs == nil {
+		return nil, errors.Errorf("datum %s not found in job %s", request.Datum.ID, jobInfo.Job.ID)
+	}
+
+	// Find the pipeline's previous job (by any version of the pipeline)
+	var priorJob *pps.JobInfo
+	var priorStarted time.Time
+	started, err := types.TimestampFromProto(jobInfo.Started)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := a.listJob(pachClient, &pps.ListJobRequest{Pipeline: jobInfo.Pipeline, History: -1}, func(ji *pps.JobInfo) error {
+		jiStarted, err := types.TimestampFromProto(ji.Started)
+		if err != nil || ji.Job.ID == jobInfo.Job.ID || !jiStarted.Before(started) {
+			return nil
+		}
+		if priorJob == nil || jiStarted.After(priorStarted) {
+			priorJob, priorStarted = ji, jiStarted
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	var priorDatums [][]*workercommon.Input
+	if priorJob != nil {
+		if priorJob, err = a.InspectJob(ctx, &pps.InspectJobRequest{Job: priorJob.Job}); err != nil {
+			return nil, err
+		}
+		if priorDatums, err = datums(priorJob); err != nil {
+			return nil, errors.Wrapf(err, "could not list the datums of the previous job %s", priorJob.Job.ID)
+		}
+	}
+	response, err = explainDatum(jobInfo, inputs, priorJob, priorDatums)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pachClient.InspectTag(ctx, client.NewTag(response.Hash)); err == nil {
+		response.OutputExists = true
+		if response.PriorDatum == nil {
+			response.Reasons = append(response.Reasons, "output for the datum's hash has been stored, either by this job "+
+				"or by a job older than the previous one (in which case the datum was skipped)")
+		}
+	}
+	return response, nil
+}
+
 // GetLogs implements the protobuf pps.GetLogs RPC
 func (a *apiServer) GetLogs(request *pps.GetLogsRequest, apiGetLogsServer pps.API_GetLogsServer) (retErr error) {
 	if a.env.LokiLogging || request.UseLokiBackend {
@@ -1405,6 +1900,10 @@ func (a *apiServer) GetLogs(request *pps.GetLogsRequest, apiGetLogsServer pps.AP
 	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
 	pachClient := a.env.GetPachClient(apiGetLogsServer.Context())
 	ctx := pachClient.Ctx() // pachClient will propagate auth info
+	since, until, err := logTimeBounds(request)
+	if err != nil {
+		return err
+	}
 
 	// Authorize request and get list of pods containing logs we're interested in
 	// (based on pipeline and job filters)
@@ -1447,8 +1946,13 @@ func (a *apiServer) GetLogs(request *pps.GetLogsRequest, apiGetLogsServer pps.AP
 		if err := a.authorizePipelineOp(pachClient, pipelineOpGetLogs, pipelineInfo.Input, pipelineInfo.Pipeline.Name); err != nil {
 			return err
 		}
-
-		// If the job had stats enabled, we use the logs from the stats
+		if request.FromStart {
+			if since, err = a.jobLogsStart(ctx, request.Job, pipelineInfo.Pipeline.Name); err != nil {
+				return err
+			}
+		}
+
+		// If the job had stats enabled, we use the logs from the stats
 		// commit since that's likely to yield better results.
 		if statsCommit != nil {
 			ci, err := pachClient.InspectCommit(statsCommit.Repo.Name, statsCommit.ID)
@@ -1456,7 +1960,7 @@ func (a *apiServer) GetLogs(request *pps.GetLogsRequest, apiGetLogsServer pps.AP
 				return err
 			}
 			if ci.Finished != nil {
-				return a.getLogsFromStats(pachClient, request, apiGetLogsServer, statsCommit)
+				return a.getLogsFromStats(pachClient, request, apiGetLogsServer, statsCommit, since, until)
 			}
 		}
 
@@ -1497,12 +2001,20 @@ func (a *apiServer) GetLogs(request *pps.GetLogsRequest, apiGetLogsServer pps.AP
 				if *tailLines <= 0 {
 					tailLines = nil
 				}
-				// Get full set of logs from pod i
+				var sinceTime *metav1.Time
+				if !since.IsZero() {
+					sinceTime = &metav1.Time{Time: since}
+				}
+				// Get full set of logs from pod i. pachd's log lines don't
+				// have timestamps of their own, so they're requested with
+				// kubernetes' timestamps, to apply the request's time bounds.
 				stream, err := a.env.GetKubeClient().CoreV1().Pods(a.namespace).GetLogs(
 					pod.ObjectMeta.Name, &v1.PodLogOptions{
-						Container: containerName,
-						Follow:    request.Follow,
-						TailLines: tailLines,
+						Container:  containerName,
+						Follow:     request.Follow,
+						TailLines:  tailLines,
+						SinceTime:  sinceTime,
+						Timestamps: containerName == "pachd",
 					}).Timeout(10 * time.Second).Stream()
 				if err != nil {
 					return err
@@ -1519,6 +2031,12 @@ func (a *apiServer) GetLogs(request *pps.GetLogsRequest, apiGetLogsServer pps.AP
 					msg := new(pps.LogMessage)
 					if containerName == "pachd" {
 						msg.Message = scanner.Text()
+						if i := strings.IndexByte(msg.Message, ' '); i > 0 {
+							if t, err := time.Parse(time.RFC3339Nano, msg.Message[:i]); err == nil {
+								msg.Ts, _ = types.TimestampProto(t)
+								msg.Message = msg.Message[i+1:]
+							}
+						}
 					} else {
 						logBytes := scanner.Bytes()
 						if err := jsonpb.Unmarshal(bytes.NewReader(logBytes), msg); err != nil {
@@ -1542,6 +2060,9 @@ func (a *apiServer) GetLogs(request *pps.GetLogsRequest, apiGetLogsServer pps.AP
 							continue
 						}
 					}
+					if !inLogTimeRange(msg.Ts, since, until) {
+						continue
+					}
 					msg.Message = strings.TrimSuffix(msg.Message, "\n")
 
 					// Log message passes all filters -- return it
@@ -1570,7 +2091,7 @@ func (a *apiServer) GetLogs(request *pps.GetLogsRequest, apiGetLogsServer pps.AP
 	return egErr
 }
 
-func (a *apiServer) getLogsFromStats(pachClient *client.APIClient, request *pps.GetLogsRequest, apiGetLogsServer pps.API_GetLogsServer, statsCommit *pfs.Commit) error {
+func (a *apiServer) getLogsFromStats(pachClient *client.APIClient, request *pps.GetLogsRequest, apiGetLogsServer pps.API_GetLogsServer, statsCommit *pfs.Commit, since, until time.Time) error {
 	pfsClient := pachClient.PfsAPIClient
 	fs, err := pfsClient.GlobFileStream(pachClient.Ctx(), &pfs.GlobFileRequest{
 		Commit:  statsCommit,
@@ -1621,6 +2142,9 @@ func (a *apiServer) getLogsFromStats(pachClient *client.APIClient, request *pps.
 				if !workercommon.MatchDatum(request.DataFilters, msg.Data) {
 					continue
 				}
+				if !inLogTimeRange(msg.Ts, since, until) {
+					continue
+				}
 
 				mu.Lock()
 				if err := apiGetLogsServer.Send(msg); err != nil {
@@ -1647,12 +2171,19 @@ func (a *apiServer) getLogsLoki(request *pps.GetLogsRequest, apiGetLogsServer pp
 	if err != nil {
 		return err
 	}
+	since, until, err := logTimeBounds(request)
+	if err != nil {
+		return err
+	}
+	if until.IsZero() {
+		until = time.Now()
+	}
 	if request.Pipeline == nil && request.Job == nil {
 		if len(request.DataFilters) > 0 || request.Datum != nil {
 			return errors.Errorf("must specify the Job or Pipeline that the datum is from to get logs for it")
 		}
 		// no authorization is done to get logs from master
-		return lokiutil.QueryRange(loki, `{app="pachd"}`, time.Time{}, time.Now(), func(t time.Time, line string) error {
+		return lokiutil.QueryRange(loki, `{app="pachd"}`, since, until, func(t time.Time, line string) error {
 			return apiGetLogsServer.Send(&pps.LogMessage{
 				Message: strings.TrimSuffix(line, "\n"),
 			})
@@ -1685,6 +2216,11 @@ func (a *apiServer) getLogsLoki(request *pps.GetLogsRequest, apiGetLogsServer pp
 	if err := a.authorizePipelineOp(pachClient, pipelineOpGetLogs, pipelineInfo.Input, pipelineInfo.Pipeline.Name); err != nil {
 		return err
 	}
+	if request.FromStart {
+		if since, err = a.jobLogsStart(ctx, request.Job, pipelineInfo.Pipeline.Name); err != nil {
+			return err
+		}
+	}
 	query := fmt.Sprintf(`{pipelineName=%q, container="user"}`, pipelineInfo.Pipeline.Name)
 	if request.Master {
 		query += contains("master")
@@ -1698,7 +2234,7 @@ func (a *apiServer) getLogsLoki(request *pps.GetLogsRequest, apiGetLogsServer pp
 	for _, filter := range request.DataFilters {
 		query += contains(filter)
 	}
-	return lokiutil.QueryRange(loki, query, time.Time{}, time.Now(), func(t time.Time, line string) error {
+	return lokiutil.QueryRange(loki, query, since, until, func(t time.Time, line string) error {
 		msg := &pps.LogMessage{}
 		// These filters are almost always unnecessary because we apply
 		// them in the Loki request, but many of them are just done with
@@ -1733,6 +2269,70 @@ func contains(s string) string {
 	return fmt.Sprintf(" |= %q", s)
 }
 
+// logTimeBounds validates the time bounds of a GetLogs request, and returns
+// them (unset bounds are returned as the zero time). If request.FromStart is
+// set, 'since' must be read with jobLogsStart once the caller is authorized.
+func logTimeBounds(request *pps.GetLogsRequest) (since time.Time, until time.Time, retErr error) {
+	if request.Since != nil {
+		if since, retErr = types.TimestampFromProto(request.Since); retErr != nil {
+			return time.Time{}, time.Time{}, errors.Wrapf(retErr, "invalid 'since'")
+		}
+	}
+	if request.Until != nil {
+		if until, retErr = types.TimestampFromProto(request.Until); retErr != nil {
+			return time.Time{}, time.Time{}, errors.Wrapf(retErr, "invalid 'until'")
+		}
+		if request.Follow {
+			return time.Time{}, time.Time{}, errors.New("cannot follow logs with an 'until' bound")
+		}
+		if !since.IsZero() && !until.After(since) {
+			return time.Time{}, time.Time{}, errors.New("'until' must be after 'since'")
+		}
+	}
+	if request.FromStart {
+		if request.Job == nil {
+			return time.Time{}, time.Time{}, errors.New("must specify a job to get logs from its start")
+		}
+		if request.Since != nil || request.Tail > 0 {
+			return time.Time{}, time.Time{}, errors.New("cannot combine 'from_start' with 'since' or 'tail'")
+		}
+	}
+	return since, until, nil
+}
+
+// jobLogsStart returns the time from which the logs of 'job', a job of
+// 'pipeline', are read for a 'from_start' GetLogs request: when the job
+// started, or the zero time if it hasn't. It reads the job, so it must only be
+// called once the caller is authorized to get the pipeline's logs.
+func (a *apiServer) jobLogsStart(ctx context.Context, job *pps.Job, pipeline string) (time.Time, error) {
+	var jobPtr pps.EtcdJobInfo
+	if err := a.jobs.ReadOnly(ctx).Get(job.ID, &jobPtr); err != nil {
+		return time.Time{}, errors.Wrapf(err, "could not get job information for \"%s\"", job.ID)
+	}
+	if jobPtr.Pipeline.Name != pipeline {
+		return time.Time{}, errors.Errorf("job %s is not a job of pipeline %s", job.ID, pipeline)
+	}
+	if jobPtr.Started == nil {
+		return time.Time{}, nil
+	}
+	return types.TimestampFromProto(jobPtr.Started)
+}
+
+// inLogTimeRange returns true if the log message timestamp 'ts' is at or after
+// 'since' and before 'until' (either of which may be the zero time, meaning
+// that end of the range is open). Messages without a timestamp are always in
+// range, as there's nothing to compare.
+func inLogTimeRange(ts *types.Timestamp, since, until time.Time) bool {
+	if ts == nil || (since.IsZero() && until.IsZero()) {
+		return true
+	}
+	t, err := types.TimestampFromProto(ts)
+	if err != nil {
+		return true
+	}
+	return !t.Before(since) && (until.IsZero() || t.Before(until))
+}
+
 func (a *apiServer) validatePipelineRequest(request *pps.CreatePipelineRequest) error {
 	if request.Pipeline == nil {
 		return errors.New("invalid pipeline spec: request.Pipeline cannot be nil")
@@ -1758,9 +2358,24 @@ func (a *apiServer) validatePipelineRequest(request *pps.CreatePipelineRequest)
 	if request.S3Out && request.EnableStats {
 		return errors.New("stats are not supported for pipelines that output via Pachyderm's S3 gateway")
 	}
-	if request.Transform == nil {
+	// Validation pipelines don't run user code, so they may omit their transform
+	if request.Transform == nil && request.Validation == nil {
 		return errors.Errorf("pipeline must specify a transform")
 	}
+	if request.Transform.GetCheckpoints() && (request.S3Out || request.Service != nil || request.Spout != nil) {
+		return errors.New("checkpoints are not supported in spouts, services, or pipelines that output via Pachyderm's S3 gateway")
+	}
+	if request.Transform.GetBuiltin() != nil && (request.S3Out || request.Service != nil || request.Spout != nil) {
+		return errors.New("builtin transforms are not supported in spouts, services, or pipelines that output via Pachyderm's S3 gateway")
+	}
+	if err := validateRuntimeConfig(request.RuntimeConfig); err != nil {
+		return err
+	}
+	if request.OutputCommitDescription != "" {
+		if err := ppsutil.ValidateOutputCommitDescription(request.OutputCommitDescription); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1800,6 +2415,9 @@ func (a *apiServer) validatePipeline(pachClient *client.APIClient, pipelineInfo
 		if pipelineInfo.Service != nil && pipelineInfo.ParallelismSpec.Constant != 1 {
 			return errors.New("services can only be run with a constant parallelism of 1")
 		}
+		if err := validateAutoscale(pipelineInfo); err != nil {
+			return err
+		}
 	}
 	if pipelineInfo.HashtreeSpec != nil {
 		if pipelineInfo.HashtreeSpec.Constant == 0 {
@@ -1809,6 +2427,21 @@ func (a *apiServer) validatePipeline(pachClient *client.APIClient, pipelineInfo
 	if pipelineInfo.OutputBranch == "" {
 		return errors.New("pipeline needs to specify an output branch")
 	}
+	if err := validateOutputs(pipelineInfo); err != nil {
+		return err
+	}
+	if err := validateNetworkPolicy(pipelineInfo.NetworkPolicy); err != nil {
+		return err
+	}
+	if err := validateSandbox(pipelineInfo.Sandbox); err != nil {
+		return err
+	}
+	if err := a.checkSandboxRuntime(pipelineInfo.Sandbox); err != nil {
+		return err
+	}
+	if err := validateMetadata(pipelineInfo.Metadata, a.iamRole); err != nil {
+		return err
+	}
 	if _, err := resource.ParseQuantity(pipelineInfo.CacheSize); err != nil {
 		return errors.Wrapf(err, "could not parse cacheSize '%s'", pipelineInfo.CacheSize)
 	}
@@ -1824,6 +2457,119 @@ func (a *apiServer) validatePipeline(pachClient *client.APIClient, pipelineInfo
 			return err
 		}
 	}
+	if pipelineInfo.LogQuota != nil {
+		if pipelineInfo.LogQuota.DatumBytes < 0 || pipelineInfo.LogQuota.JobBytes < 0 {
+			return errors.New("invalid pipeline spec: LogQuota limits cannot be negative")
+		}
+	}
+	if pipelineInfo.ImagePinning != nil && pipelineInfo.ImagePinning.CheckInterval != nil {
+		interval, err := types.DurationFromProto(pipelineInfo.ImagePinning.CheckInterval)
+		if err != nil {
+			return err
+		}
+		if interval < time.Minute {
+			return errors.New("invalid pipeline spec: ImagePinning.CheckInterval must be at least one minute")
+		}
+	}
+	if pipelineInfo.IdlePolicy != nil && pipelineInfo.IdlePolicy.Timeout != nil {
+		timeout, err := types.DurationFromProto(pipelineInfo.IdlePolicy.Timeout)
+		if err != nil {
+			return err
+		}
+		if timeout < time.Hour {
+			return errors.New("invalid pipeline spec: IdlePolicy.Timeout must be at least one hour")
+		}
+	}
+	if pipelineInfo.HashtreeMemoryLimit != "" {
+		limit, err := resource.ParseQuantity(pipelineInfo.HashtreeMemoryLimit)
+		if err != nil {
+			return errors.Wrapf(err, "could not parse hashtreeMemoryLimit '%s'", pipelineInfo.HashtreeMemoryLimit)
+		}
+		if limit.Sign() < 0 {
+			return errors.New("invalid pipeline spec: HashtreeMemoryLimit cannot be negative")
+		}
+	}
+	for _, outputMerge := range pipelineInfo.OutputMerges {
+		if pipelineInfo.Spout != nil || pipelineInfo.S3Out {
+			return errors.New("invalid pipeline spec: OutputMerges can't be set for spouts or pipelines with S3Out")
+		}
+		if outputMerge.Glob == "" {
+			return errors.New("invalid pipeline spec: every output merge must specify a glob")
+		}
+		if _, err := glob.Compile(path.Clean("/"+outputMerge.Glob), '/'); err != nil {
+			return errors.Wrapf(err, "invalid pipeline spec: could not parse output merge glob %q", outputMerge.Glob)
+		}
+		if _, ok := pps.OutputMerge_Strategy_name[int32(outputMerge.Strategy)]; !ok {
+			return errors.Errorf("invalid pipeline spec: unknown output merge strategy %v", outputMerge.Strategy)
+		}
+	}
+	if pipelineInfo.Gated && (pipelineInfo.Spout != nil || len(pipelineInfo.Outputs) > 0) {
+		return errors.New("invalid pipeline spec: spouts and pipelines with named outputs can't be gated")
+	}
+	if err := validateAlignment(pipelineInfo); err != nil {
+		return err
+	}
+	if err := validateJobGates(pipelineInfo); err != nil {
+		return err
+	}
+	if err := validateOutputRoutes(pipelineInfo); err != nil {
+		return err
+	}
+	if err := validateSmokeTest(pipelineInfo); err != nil {
+		return err
+	}
+	if err := validateRetrySpec(pipelineInfo.RetrySpec); err != nil {
+		return err
+	}
+	if gang := pipelineInfo.SchedulingSpec.GetGangScheduling(); gang != nil {
+		if err := validateGangScheduling(gang); err != nil {
+			return err
+		}
+	}
+	if err := validateEgress(pipelineInfo.Egress); err != nil {
+		return err
+	}
+	if err := a.checkEgressSecret(pipelineInfo.Egress); err != nil {
+		return err
+	}
+	if err := validateOOMRetry(pipelineInfo); err != nil {
+		return err
+	}
+	if fileCache := pipelineInfo.FileCache; fileCache != nil {
+		if fileCache.MaxFileBytes < 0 || fileCache.SizeBytes < 0 {
+			return errors.New("invalid pipeline spec: file_cache sizes must not be negative")
+		}
+		if fileCache.Ttl != nil {
+			if ttl, err := types.DurationFromProto(fileCache.Ttl); err != nil {
+				return errors.Wrapf(err, "invalid pipeline spec: could not parse file_cache.ttl")
+			} else if ttl < 0 {
+				return errors.New("invalid pipeline spec: file_cache.ttl must not be negative")
+			}
+		}
+		if fileCache.RedisAddress != "" && len(fileCache.MemcachedAddresses) > 0 {
+			return errors.New("invalid pipeline spec: only one of file_cache.redis_address and file_cache.memcached_addresses may be set")
+		}
+		for _, address := range fileCache.MemcachedAddresses {
+			if address == "" {
+				return errors.New("invalid pipeline spec: file_cache.memcached_addresses must not be empty")
+			}
+		}
+	}
+	if err := validateValidation(pipelineInfo); err != nil {
+		return err
+	}
+	if err := validateStatsRetention(pipelineInfo); err != nil {
+		return err
+	}
+	if err := validateOutputRetention(pipelineInfo.OutputRetention); err != nil {
+		return err
+	}
+	if err := validateDatumSizing(pipelineInfo); err != nil {
+		return err
+	}
+	if err := validatePhaseConcurrency(pipelineInfo); err != nil {
+		return err
+	}
 	if pipelineInfo.PodSpec != "" && !json.Valid([]byte(pipelineInfo.PodSpec)) {
 		return errors.Errorf("malformed PodSpec")
 	}
@@ -1870,6 +2616,18 @@ func branchProvenance(input *pps.Input) []*pfs.Branch {
 		if input.Git != nil {
 			result = append(result, client.NewBranch(input.Git.Name, input.Git.Branch))
 		}
+		if input.HTTPMirror != nil {
+			result = append(result, client.NewBranch(input.HTTPMirror.Repo, "master"))
+		}
+		if input.Parameter != nil {
+			result = append(result, client.NewBranch(input.Parameter.Repo, "master"))
+		}
+		if input.RemoteRepo != nil {
+			result = append(result, client.NewBranch(input.RemoteRepo.Repo, "master"))
+		}
+		if input.DBImport != nil {
+			result = append(result, client.NewBranch(input.DBImport.Repo, "master"))
+		}
 	})
 	return result
 }
@@ -1881,10 +2639,11 @@ func branchProvenance(input *pps.Input) []*pfs.Branch {
 // PPS master
 func (a *apiServer) hardStopPipeline(pachClient *client.APIClient, pipelineInfo *pps.PipelineInfo) error {
 	// Remove the output branch's provenance so that no new jobs can be created
+	jobOutputBranch := ppsutil.JobOutputBranch(pipelineInfo)
 	if err := pachClient.CreateBranch(
 		pipelineInfo.Pipeline.Name,
-		pipelineInfo.OutputBranch,
-		pipelineInfo.OutputBranch,
+		jobOutputBranch,
+		jobOutputBranch,
 		nil,
 	); err != nil && !isNotFoundErr(err) {
 		return errors.Wrapf(err, "could not recreate original output branch")
@@ -1899,15 +2658,18 @@ func (a *apiServer) hardStopPipeline(pachClient *client.APIClient, pipelineInfo
 			return errors.Wrapf(err, "could not recreate original stats branch")
 		}
 	}
+	if err := stopOutputBranches(pachClient, outputBranches(pipelineInfo)); err != nil {
+		return err
+	}
 
 	// Now that new commits won't be created on the master branch, enumerate
 	// existing commits and close any open ones.
 	iter, err := pachClient.ListCommitStream(pachClient.Ctx(), &pfs.ListCommitRequest{
 		Repo: client.NewRepo(pipelineInfo.Pipeline.Name),
-		To:   client.NewCommit(pipelineInfo.Pipeline.Name, pipelineInfo.OutputBranch),
+		To:   client.NewCommit(pipelineInfo.Pipeline.Name, jobOutputBranch),
 	})
 	if err != nil {
-		return errors.Wrapf(err, "couldn't get open commits on '%s'", pipelineInfo.OutputBranch)
+		return errors.Wrapf(err, "couldn't get open commits on '%s'", jobOutputBranch)
 	}
 	// Finish all open commits, most recent first (so that we finish the
 	// current job's output commit--the oldest--last, and unblock the master
@@ -1968,8 +2730,9 @@ func (a *apiServer) sudo(pachClient *client.APIClient, f func(*client.APIClient)
 	})
 
 	// Copy pach client, but keep ctx (to propagate cancellation). Replace token
-	// with superUserToken
-	superUserClient := pachClient.WithCtx(pachClient.Ctx())
+	// with superUserToken, and don't impersonate the subject that the caller
+	// may be impersonating, as superuser calls are made as PPS
+	superUserClient := pachClient.WithCtx(pachClient.Ctx()).WithoutImpersonation()
 	superUserClient.SetAuthToken(superUserToken)
 	return f(superUserClient)
 }
@@ -2017,11 +2780,29 @@ func (a *apiServer) fixPipelineInputRepoACLs(pachClient *client.APIClient, pipel
 				repo = input.Cron.Repo
 			case input.Git != nil:
 				repo = input.Git.Name
+			case input.HTTPMirror != nil:
+				repo = input.HTTPMirror.Repo
+			case input.Parameter != nil:
+				repo = input.Parameter.Repo
+			case input.RemoteRepo != nil:
+				repo = input.RemoteRepo.Repo
+			case input.DBImport != nil:
+				repo = input.DBImport.Repo
 			default:
 				return // no scope to set: input is not a repo
 			}
 			remove[repo] = struct{}{}
 		})
+		// The pipeline's workers read its builtin transform's plugin
+		if builtin := prevPipelineInfo.Transform.GetBuiltin(); builtin != nil {
+			remove[builtin.Repo] = struct{}{}
+		}
+		// and the marker files of its job gates
+		for _, gate := range prevPipelineInfo.JobGates.GetGates() {
+			if gate.MarkerFile != nil {
+				remove[gate.MarkerFile.Repo] = struct{}{}
+			}
+		}
 	}
 
 	// Figure out which repos 'pipeline' is using
@@ -2046,6 +2827,14 @@ func (a *apiServer) fixPipelineInputRepoACLs(pachClient *client.APIClient, pipel
 				repo = input.Cron.Repo
 			case input.Git != nil:
 				repo = input.Git.Name
+			case input.HTTPMirror != nil:
+				repo = input.HTTPMirror.Repo
+			case input.Parameter != nil:
+				repo = input.Parameter.Repo
+			case input.RemoteRepo != nil:
+				repo = input.RemoteRepo.Repo
+			case input.DBImport != nil:
+				repo = input.DBImport.Repo
 			default:
 				return // no scope to set: input is not a repo
 			}
@@ -2055,6 +2844,23 @@ func (a *apiServer) fixPipelineInputRepoACLs(pachClient *client.APIClient, pipel
 				add[repo] = struct{}{}
 			}
 		})
+		if builtin := pipelineInfo.Transform.GetBuiltin(); builtin != nil {
+			if _, ok := remove[builtin.Repo]; ok {
+				delete(remove, builtin.Repo)
+			} else {
+				add[builtin.Repo] = struct{}{}
+			}
+		}
+		for _, gate := range pipelineInfo.JobGates.GetGates() {
+			if gate.MarkerFile == nil {
+				continue
+			}
+			if _, ok := remove[gate.MarkerFile.Repo]; ok {
+				delete(remove, gate.MarkerFile.Repo)
+			} else {
+				add[gate.MarkerFile.Repo] = struct{}{}
+			}
+		}
 	}
 	if pipelineName == "" {
 		return errors.Errorf("fixPipelineInputRepoACLs called with both current and " +
@@ -2094,6 +2900,23 @@ func (a *apiServer) fixPipelineInputRepoACLs(pachClient *client.APIClient, pipel
 			})
 		})
 	}
+	// Add pipeline to its named outputs' repos' ACLs as a WRITER (these may
+	// change when the pipeline is updated)
+	if pipelineInfo != nil {
+		for _, output := range pipelineInfo.Outputs {
+			repo := output.Repo
+			eg.Go(func() error {
+				return a.sudo(pachClient, func(superUserClient *client.APIClient) error {
+					_, err := superUserClient.SetScope(superUserClient.Ctx(), &auth.SetScopeRequest{
+						Repo:     repo,
+						Username: auth.PipelinePrefix + pipelineName,
+						Scope:    auth.Scope_WRITER,
+					})
+					return grpcutil.ScrubGRPC(err)
+				})
+			})
+		}
+	}
 	// Add pipeline to its output repo's ACL as a WRITER if it's new
 	if prevPipelineInfo == nil {
 		eg.Go(func() error {
@@ -2118,7 +2941,13 @@ func (a *apiServer) fixPipelineInputRepoACLs(pachClient *client.APIClient, pipel
 // that can be stored in EtcdPipelineInfo.Parallelism
 func getExpectedNumWorkers(kc *kube.Clientset, pipelineInfo *pps.PipelineInfo) (int, error) {
 	switch pspec := pipelineInfo.ParallelismSpec; {
-	case pspec == nil, pspec.Constant == 0 && pspec.Coefficient == 0:
+	case pspec == nil:
+		return 1, nil
+	case pspec.Autoscale != nil && pspec.Constant == 0 && pspec.Coefficient == 0:
+		// The autoscaler scales the pipeline up from its minimum while it has
+		// outstanding datums (see runAutoscaler)
+		return int(autoscaleMinWorkers(pspec.Autoscale)), nil
+	case pspec.Constant == 0 && pspec.Coefficient == 0:
 		return 1, nil
 	case pspec.Constant > 0 && pspec.Coefficient == 0:
 		return int(pspec.Constant), nil
@@ -2140,6 +2969,66 @@ func getExpectedNumWorkers(kc *kube.Clientset, pipelineInfo *pps.PipelineInfo) (
 	}
 }
 
+// pipelineInfoFromRequest returns the PipelineInfo of the first version of the
+// pipeline described by 'request' (without defaults).
+func pipelineInfoFromRequest(request *pps.CreatePipelineRequest) *pps.PipelineInfo {
+	return &pps.PipelineInfo{
+		Pipeline:                request.Pipeline,
+		Version:                 1,
+		Transform:               request.Transform,
+		TFJob:                   request.TFJob,
+		ParallelismSpec:         request.ParallelismSpec,
+		HashtreeSpec:            request.HashtreeSpec,
+		Input:                   request.Input,
+		OutputBranch:            request.OutputBranch,
+		Egress:                  request.Egress,
+		CreatedAt:               now(),
+		ResourceRequests:        request.ResourceRequests,
+		ResourceLimits:          request.ResourceLimits,
+		SidecarResourceLimits:   request.SidecarResourceLimits,
+		Description:             request.Description,
+		CacheSize:               request.CacheSize,
+		EnableStats:             request.EnableStats,
+		Salt:                    request.Salt,
+		MaxQueueSize:            request.MaxQueueSize,
+		Service:                 request.Service,
+		Spout:                   request.Spout,
+		ChunkSpec:               request.ChunkSpec,
+		DatumTimeout:            request.DatumTimeout,
+		JobTimeout:              request.JobTimeout,
+		Standby:                 request.Standby,
+		DatumTries:              request.DatumTries,
+		SchedulingSpec:          request.SchedulingSpec,
+		PodSpec:                 request.PodSpec,
+		PodPatch:                request.PodPatch,
+		S3Out:                   request.S3Out,
+		Metadata:                request.Metadata,
+		LogQuota:                request.LogQuota,
+		ImagePinning:            request.ImagePinning,
+		Outputs:                 request.Outputs,
+		NetworkPolicy:           request.NetworkPolicy,
+		IdlePolicy:              request.IdlePolicy,
+		HashtreeMemoryLimit:     request.HashtreeMemoryLimit,
+		OutputMerges:            request.OutputMerges,
+		Gated:                   request.Gated,
+		OutputCommitDescription: request.OutputCommitDescription,
+		Alignment:               request.Alignment,
+		Attest:                  request.Attest,
+		Validation:              request.Validation,
+		OomRetry:                request.OomRetry,
+		FileCache:               request.FileCache,
+		StatsRetention:          request.StatsRetention,
+		Sandbox:                 request.Sandbox,
+		JobGates:                request.JobGates,
+		OutputRoutes:            request.OutputRoutes,
+		SmokeTest:               request.SmokeTest,
+		RetrySpec:               request.RetrySpec,
+		OutputRetention:         request.OutputRetention,
+		DatumSizing:             request.DatumSizing,
+		PhaseConcurrency:        request.PhaseConcurrency,
+	}
+}
+
 // CreatePipeline implements the protobuf pps.CreatePipeline RPC
 //
 // Implementation note:
@@ -2184,41 +3073,27 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 	pachClient := a.env.GetPachClient(ctx)
 	ctx = pachClient.Ctx() // GetPachClient propagates auth info to inner ctx
 	pfsClient := pachClient.PfsAPIClient
+	// Callers confined to a tenant may only create and update the tenant's
+	// pipelines
+	callerTenant, err := tenant.FromAuth(ctx, pachClient.AuthAPIClient)
+	if err != nil {
+		return nil, err
+	}
+	if err := tenant.CheckName(callerTenant, request.Pipeline.Name); err != nil {
+		return nil, err
+	}
 	// Reprocess overrides the salt in the request
 	if request.Salt == "" || request.Reprocess {
 		request.Salt = uuid.NewWithoutDashes()
 	}
-	pipelineInfo := &pps.PipelineInfo{
-		Pipeline:              request.Pipeline,
-		Version:               1,
-		Transform:             request.Transform,
-		TFJob:                 request.TFJob,
-		ParallelismSpec:       request.ParallelismSpec,
-		HashtreeSpec:          request.HashtreeSpec,
-		Input:                 request.Input,
-		OutputBranch:          request.OutputBranch,
-		Egress:                request.Egress,
-		CreatedAt:             now(),
-		ResourceRequests:      request.ResourceRequests,
-		ResourceLimits:        request.ResourceLimits,
-		SidecarResourceLimits: request.SidecarResourceLimits,
-		Description:           request.Description,
-		CacheSize:             request.CacheSize,
-		EnableStats:           request.EnableStats,
-		Salt:                  request.Salt,
-		MaxQueueSize:          request.MaxQueueSize,
-		Service:               request.Service,
-		Spout:                 request.Spout,
-		ChunkSpec:             request.ChunkSpec,
-		DatumTimeout:          request.DatumTimeout,
-		JobTimeout:            request.JobTimeout,
-		Standby:               request.Standby,
-		DatumTries:            request.DatumTries,
-		SchedulingSpec:        request.SchedulingSpec,
-		PodSpec:               request.PodSpec,
-		PodPatch:              request.PodPatch,
-		S3Out:                 request.S3Out,
-		Metadata:              request.Metadata,
+	pipelineInfo := pipelineInfoFromRequest(request)
+	if pipelineInfo.Transform == nil && pipelineInfo.Validation != nil {
+		pipelineInfo.Transform = &pps.Transform{}
+	}
+	if pipelineInfo.Transform.Builtin != nil && pipelineInfo.Transform.Image == "" {
+		// Plugins are built against a particular version of the worker, so
+		// the pipeline keeps the builtin worker image it was created with
+		pipelineInfo.Transform.Image = builtinWorkerImage(a.workerImage)
 	}
 	if err := setPipelineDefaults(pipelineInfo); err != nil {
 		return nil, err
@@ -2227,6 +3102,21 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 	if err := a.validatePipeline(pachClient, pipelineInfo); err != nil {
 		return nil, err
 	}
+	if pipelineInfo.ImagePinning != nil {
+		digest, err := resolvePipelineImage(ctx, a.env.GetKubeClient(), a.namespace, a.imagePullSecret, pipelineInfo.Transform)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not pin image %q", pipelineInfo.Transform.Image)
+		}
+		pipelineInfo.ImageDigest = digest
+	}
+	if pipelineInfo.Transform.Builtin != nil {
+		if err := resolveBuiltinTransform(pachClient, pipelineInfo.Transform.Builtin); err != nil {
+			return nil, err
+		}
+	}
+	if err := a.setPipelineResidency(pachClient, pipelineInfo); err != nil {
+		return nil, err
+	}
 
 	var visitErr error
 	pps.VisitInput(pipelineInfo.Input, func(input *pps.Input) {
@@ -2248,10 +3138,59 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 				visitErr = err
 			}
 		}
+		if input.HTTPMirror != nil {
+			if _, err := pachClient.PfsAPIClient.CreateRepo(pachClient.Ctx(),
+				&pfs.CreateRepoRequest{
+					Repo:        client.NewRepo(input.HTTPMirror.Repo),
+					Description: fmt.Sprintf("HTTP mirror repo for pipeline %s.", request.Pipeline.Name),
+				}); err != nil && !isAlreadyExistsErr(err) {
+				visitErr = err
+			}
+		}
+		if input.Parameter != nil {
+			if _, err := pachClient.PfsAPIClient.CreateRepo(pachClient.Ctx(),
+				&pfs.CreateRepoRequest{
+					Repo:        client.NewRepo(input.Parameter.Repo),
+					Description: fmt.Sprintf("Parameter input repo for pipeline %s.", request.Pipeline.Name),
+				}); err != nil && !isAlreadyExistsErr(err) {
+				visitErr = err
+			}
+		}
+		if input.RemoteRepo != nil {
+			if _, err := pachClient.PfsAPIClient.CreateRepo(pachClient.Ctx(),
+				&pfs.CreateRepoRequest{
+					Repo: client.NewRepo(input.RemoteRepo.Repo),
+					Description: fmt.Sprintf("Mirror of %s@%s on %s for pipeline %s.",
+						input.RemoteRepo.SourceRepo, input.RemoteRepo.SourceBranch,
+						input.RemoteRepo.PachdAddress, request.Pipeline.Name),
+				}); err != nil && !isAlreadyExistsErr(err) {
+				visitErr = err
+			}
+		}
+		if input.DBImport != nil {
+			if _, err := pachClient.PfsAPIClient.CreateRepo(pachClient.Ctx(),
+				&pfs.CreateRepoRequest{
+					Repo: client.NewRepo(input.DBImport.Repo),
+					Description: fmt.Sprintf("Tables imported from a %s database for pipeline %s.",
+						input.DBImport.Driver, request.Pipeline.Name),
+				}); err != nil && !isAlreadyExistsErr(err) {
+				visitErr = err
+			}
+		}
 	})
 	if visitErr != nil {
 		return nil, visitErr
 	}
+	for _, output := range pipelineInfo.Outputs {
+		if _, err := pachClient.PfsAPIClient.CreateRepo(pachClient.Ctx(),
+			&pfs.CreateRepoRequest{
+				Repo:        client.NewRepo(output.Repo),
+				Description: fmt.Sprintf("Output %q of pipeline %s.", output.Name, request.Pipeline.Name),
+				Residency:   pipelineInfo.Residency,
+			}); err != nil && !isAlreadyExistsErr(err) {
+			return nil, err
+		}
+	}
 
 	// Authorize pipeline creation
 	operation := pipelineOpCreate
@@ -2274,7 +3213,7 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 		// provenance for the pipeline's output branch (includes the spec branch)
 		provenance = append(branchProvenance(pipelineInfo.Input),
 			client.NewBranch(ppsconsts.SpecRepo, pipelineName))
-		outputBranch     = client.NewBranch(pipelineName, pipelineInfo.OutputBranch)
+		outputBranch     = client.NewBranch(pipelineName, ppsutil.JobOutputBranch(pipelineInfo))
 		statsBranch      = client.NewBranch(pipelineName, "stats")
 		markerBranch     = client.NewBranch(pipelineName, ppsconsts.SpoutMarkerBranch)
 		outputBranchHead *pfs.Commit
@@ -2332,6 +3271,15 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 				if oldPipelineInfo.EnableStats && !pipelineInfo.EnableStats {
 					return newErrPipelineUpdate(pipelineInfo.Pipeline.Name, "cannot disable stats")
 				}
+				// The branch that jobs commit to depends on whether the pipeline is
+				// gated, so gating can't change
+				if oldPipelineInfo.Gated != pipelineInfo.Gated {
+					return newErrPipelineUpdate(pipelineInfo.Pipeline.Name, "cannot change whether the pipeline is gated")
+				}
+				// Likewise for routing output
+				if (len(oldPipelineInfo.OutputRoutes) > 0) != (len(pipelineInfo.OutputRoutes) > 0) {
+					return newErrPipelineUpdate(pipelineInfo.Pipeline.Name, "cannot change whether the pipeline routes its output")
+				}
 
 				// Modify pipelineInfo (increment Version, and *preserve Stopped* so
 				// that updating a pipeline doesn't restart it)
@@ -2355,8 +3303,20 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 				pipelinePtr.State = pps.PipelineState_PIPELINE_STARTING
 				// Clear any failure reasons
 				pipelinePtr.Reason = ""
+				pipelinePtr.AvailableImageDigest = ""
+				pipelinePtr.IdleSince = nil
 				// Update pipeline parallelism
 				pipelinePtr.Parallelism = uint64(parallelism)
+				pipelinePtr.ScaledJob = ""
+				pipelinePtr.EscalatedMemory = ""
+				pipelinePtr.EscalatedJob = ""
+				pipelinePtr.PeakMemoryBytes = 0
+				pipelinePtr.GateStatus = nil
+				ppsutil.ResetSkippingStats(&pipelinePtr)
+				// Keep the pipeline's runtime config, unless a new one is given
+				if request.RuntimeConfig != nil {
+					pipelinePtr.RuntimeConfig = request.RuntimeConfig
+				}
 				return nil
 			})
 		}); err != nil {
@@ -2371,7 +3331,7 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 			if err != nil && !isNotFoundErr(err) {
 				return nil, err
 			} else if err == nil {
-				outputBranchHead = client.NewCommit(pipelineName, pipelineInfo.OutputBranch)
+				outputBranchHead = client.NewCommit(pipelineName, outputBranch.Name)
 			}
 
 			_, err = pfsClient.InspectBranch(ctx, &pfs.InspectBranchRequest{Branch: statsBranch})
@@ -2389,6 +3349,21 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 			}
 		}
 
+		// Outputs that were removed from the pipeline keep their repos, but
+		// stop getting new commits
+		removedOutputs := make(map[string]*pfs.Branch)
+		for _, branch := range outputBranches(oldPipelineInfo) {
+			removedOutputs[branch.Repo.Name+"@"+branch.Name] = branch
+		}
+		for _, branch := range outputBranches(pipelineInfo) {
+			delete(removedOutputs, branch.Repo.Name+"@"+branch.Name)
+		}
+		for _, branch := range removedOutputs {
+			if err := stopOutputBranches(pachClient, []*pfs.Branch{branch}); err != nil {
+				return nil, err
+			}
+		}
+
 		if pipelinePtr.AuthToken != "" {
 			if err := a.fixPipelineInputRepoACLs(pachClient, pipelineInfo, oldPipelineInfo); err != nil {
 				return nil, err
@@ -2400,6 +3375,7 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 			&pfs.CreateRepoRequest{
 				Repo:        client.NewRepo(pipelineName),
 				Description: fmt.Sprintf("Output repo for pipeline %s.", request.Pipeline.Name),
+				Residency:   pipelineInfo.Residency,
 			}); err != nil && !isAlreadyExistsErr(err) {
 			return nil, err
 		}
@@ -2416,7 +3392,7 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 			// It does, so we use that as the spec commit, rather than making a new one
 			commit = commitInfo.Commit
 			// We also use the existing head for the branches, rather than making a new one.
-			outputBranchHead = client.NewCommit(pipelineName, pipelineInfo.OutputBranch)
+			outputBranchHead = client.NewCommit(pipelineName, outputBranch.Name)
 			statsBranchHead = client.NewCommit(pipelineName, "stats")
 		} else {
 			var err error
@@ -2429,9 +3405,11 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 		// pipelinePtr will be written to etcd, pointing at 'commit'. May include an
 		// auth token
 		pipelinePtr := &pps.EtcdPipelineInfo{
-			SpecCommit:  commit,
-			State:       pps.PipelineState_PIPELINE_STARTING,
-			Parallelism: uint64(parallelism),
+			SpecCommit:    commit,
+			State:         pps.PipelineState_PIPELINE_STARTING,
+			Parallelism:   uint64(parallelism),
+			Tenant:        callerTenant,
+			RuntimeConfig: request.RuntimeConfig,
 		}
 
 		// Generate pipeline's auth token & add pipeline to the ACLs of input/output
@@ -2439,6 +3417,7 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 		if err := a.sudo(pachClient, func(superUserClient *client.APIClient) error {
 			tokenResp, err := superUserClient.GetAuthToken(superUserClient.Ctx(), &auth.GetAuthTokenRequest{
 				Subject: auth.PipelinePrefix + request.Pipeline.Name,
+				Tenant:  callerTenant,
 			})
 			if err != nil {
 				if auth.IsErrNotActivated(err) {
@@ -2454,17 +3433,25 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 
 		// Put a pointer to the new PipelineInfo commit into etcd
 		if _, err := col.NewSTM(ctx, a.env.GetEtcdClient(), func(stm col.STM) error {
-			err := a.pipelines.ReadWrite(stm).Create(pipelineName, pipelinePtr)
-			if isAlreadyExistsErr(err) {
+			if err := a.pipelines.ReadWrite(stm).Create(pipelineName, pipelinePtr); err != nil {
+				if isAlreadyExistsErr(err) {
+					return newErrPipelineExists(pipelineName)
+				}
+				return err
+			}
+			// The tenant's pipeline count is updated in the same transaction
+			// as the pipeline is created, so concurrent CreatePipeline calls
+			// can't exceed the tenant's quota
+			return tenantutil.Reserve(a.tenantPipelines.ReadWriteInt(stm), callerTenant, "pipelines", a.env.TenantMaxPipelines)
+		}); err != nil {
+			var errQuota tenant.ErrQuotaExceeded
+			if isAlreadyExistsErr(err) || errors.As(err, &errQuota) {
 				if err := a.sudo(pachClient, func(superUserClient *client.APIClient) error {
 					return superUserClient.DeleteCommit(ppsconsts.SpecRepo, commit.ID)
 				}); err != nil {
-					return errors.Wrapf(grpcutil.ScrubGRPC(err), "couldn't clean up orphaned spec commit")
+					return nil, errors.Wrapf(grpcutil.ScrubGRPC(err), "couldn't clean up orphaned spec commit")
 				}
-				return newErrPipelineExists(pipelineName)
 			}
-			return err
-		}); err != nil {
 			return nil, err
 		}
 		if pipelinePtr.AuthToken != "" {
@@ -2488,6 +3475,32 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 	}); err != nil {
 		return nil, errors.Wrapf(err, "could not create/update output branch")
 	}
+	if err := createGatedOutputBranch(pachClient, pipelineInfo); err != nil {
+		return nil, err
+	}
+	if err := createRouteBranches(pachClient, pipelineInfo); err != nil {
+		return nil, err
+	}
+	// Named outputs have the same provenance as the output branch, so that each
+	// job gets an output commit in each of them
+	for _, branch := range outputBranches(pipelineInfo) {
+		var head *pfs.Commit
+		if !request.Reprocess {
+			_, err := pfsClient.InspectBranch(ctx, &pfs.InspectBranchRequest{Branch: branch})
+			if err != nil && !isNotFoundErr(err) {
+				return nil, err
+			} else if err == nil {
+				head = client.NewCommit(branch.Repo.Name, branch.Name)
+			}
+		}
+		if _, err := pfsClient.CreateBranch(ctx, &pfs.CreateBranchRequest{
+			Branch:     branch,
+			Provenance: provenance,
+			Head:       head,
+		}); err != nil {
+			return nil, errors.Wrapf(err, "could not create/update output branch %s@%s", branch.Repo.Name, branch.Name)
+		}
+	}
 	if pipelineInfo.EnableStats {
 		if _, err := pfsClient.CreateBranch(ctx, &pfs.CreateBranchRequest{
 			Branch:     client.NewBranch(pipelineName, "stats"),
@@ -2515,6 +3528,9 @@ func setPipelineDefaults(pipelineInfo *pps.PipelineInfo) error {
 	if pipelineInfo.Transform.Image == "" {
 		pipelineInfo.Transform.Image = DefaultUserImage
 	}
+	if builtin := pipelineInfo.Transform.Builtin; builtin != nil && builtin.Commit == "" {
+		builtin.Commit = "master"
+	}
 	pps.VisitInput(pipelineInfo.Input, func(input *pps.Input) {
 		if input.Pfs != nil {
 			if input.Pfs.Branch == "" {
@@ -2523,6 +3539,18 @@ func setPipelineDefaults(pipelineInfo *pps.PipelineInfo) error {
 			if input.Pfs.Name == "" {
 				input.Pfs.Name = input.Pfs.Repo
 			}
+			if input.Pfs.Partition != nil && input.Pfs.Glob == "" {
+				// an invalid layout is reported by validateInput
+				input.Pfs.Glob, _ = datum.PartitionGlob(input.Pfs.Partition)
+			}
+		}
+		// Joined PFS inputs that opt in, and don't set join_on, are joined on
+		// all of their capture groups. Other inputs without join_on are still
+		// matched with every datum of the other inputs, as they always were.
+		for _, joined := range input.Join {
+			if joined.Pfs != nil && joined.Pfs.JoinOn == "" && joined.Pfs.JoinOnCaptureGroups {
+				joined.Pfs.JoinOn = datum.DefaultJoinOn(joined.Pfs.Glob)
+			}
 		}
 		if input.Cron != nil {
 			if input.Cron.Start == nil {
@@ -2544,17 +3572,58 @@ func setPipelineDefaults(pipelineInfo *pps.PipelineInfo) error {
 				input.Git.Name = tokens[0]
 			}
 		}
+		if input.HTTPMirror != nil {
+			if input.HTTPMirror.Spec == "" {
+				input.HTTPMirror.Spec = DefaultMirrorSpec
+			}
+			if input.HTTPMirror.Repo == "" {
+				input.HTTPMirror.Repo = fmt.Sprintf("%s_%s", pipelineInfo.Pipeline.Name, input.HTTPMirror.Name)
+			}
+		}
+		if input.Parameter != nil {
+			if input.Parameter.Repo == "" {
+				input.Parameter.Repo = fmt.Sprintf("%s_%s", pipelineInfo.Pipeline.Name, input.Parameter.Name)
+			}
+		}
+		if input.RemoteRepo != nil {
+			if input.RemoteRepo.SourceBranch == "" {
+				input.RemoteRepo.SourceBranch = "master"
+			}
+			if input.RemoteRepo.Repo == "" {
+				input.RemoteRepo.Repo = fmt.Sprintf("%s_%s", pipelineInfo.Pipeline.Name, input.RemoteRepo.Name)
+			}
+		}
+		if input.DBImport != nil {
+			if input.DBImport.Spec == "" {
+				input.DBImport.Spec = DefaultDBImportSpec
+			}
+			if input.DBImport.Repo == "" {
+				input.DBImport.Repo = fmt.Sprintf("%s_%s", pipelineInfo.Pipeline.Name, input.DBImport.Name)
+			}
+		}
 	})
 	if pipelineInfo.OutputBranch == "" {
 		// Output branches default to master
 		pipelineInfo.OutputBranch = "master"
 	}
+	for _, output := range pipelineInfo.Outputs {
+		if output.Repo == "" {
+			output.Repo = fmt.Sprintf("%s_%s", pipelineInfo.Pipeline.Name, output.Name)
+		}
+		if output.Branch == "" {
+			output.Branch = "master"
+		}
+	}
 	if pipelineInfo.CacheSize == "" {
 		pipelineInfo.CacheSize = "64M"
 	}
 	if pipelineInfo.MaxQueueSize < 1 {
 		pipelineInfo.MaxQueueSize = 1
 	}
+	if pipelineInfo.RetrySpec != nil {
+		// retry_spec.max_retries replaces datum_tries
+		pipelineInfo.DatumTries = pipelineInfo.RetrySpec.MaxRetries + 1
+	}
 	if pipelineInfo.DatumTries == 0 {
 		pipelineInfo.DatumTries = DefaultDatumTries
 	}
@@ -2574,7 +3643,19 @@ func (a *apiServer) InspectPipeline(ctx context.Context, request *pps.InspectPip
 	func() { a.Log(request, nil, nil, 0) }()
 	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
 	pachClient := a.env.GetPachClient(ctx)
-	return a.inspectPipeline(pachClient, request.Pipeline.Name)
+	pipelineInfo, err := a.inspectPipeline(pachClient, request.Pipeline.Name)
+	if err != nil {
+		return nil, err
+	}
+	if a.env.ImagePrewarming && pipelineInfo.Transform != nil {
+		nodes, err := a.env.GetKubeClient().CoreV1().Nodes().List(metav1.ListOptions{})
+		if err != nil {
+			logrus.Errorf("failed to list nodes to get image prewarm status: %v", err)
+		} else {
+			pipelineInfo.ImagePrewarm = imagePrewarmStatus(pipelineImage(pipelineInfo), nodes.Items)
+		}
+	}
+	return pipelineInfo, nil
 }
 
 // inspectPipeline contains the functional implementation of InspectPipeline.
@@ -2589,6 +3670,9 @@ func (a *apiServer) inspectPipeline(pachClient *client.APIClient, name string) (
 	if err != nil {
 		return nil, err
 	}
+	if err := a.checkTenant(pachClient, name); err != nil {
+		return nil, err
+	}
 	pipelinePtr := pps.EtcdPipelineInfo{}
 	if err := a.pipelines.ReadOnly(pachClient.Ctx()).Get(name, &pipelinePtr); err != nil {
 		if col.IsErrNotFound(err) {
@@ -2622,27 +3706,13 @@ func (a *apiServer) inspectPipeline(pachClient *client.APIClient, name string) (
 		}
 	})
 	if hasGitInput {
-		pipelineInfo.GithookURL = "pending"
-		svc, err := getGithookService(kubeClient, a.namespace)
+		pipelineInfo.GithookURL, err = a.githookURL(kubeClient)
 		if err != nil {
-			return pipelineInfo, nil
+			return nil, err
 		}
-		numIPs := len(svc.Status.LoadBalancer.Ingress)
-		if numIPs == 0 {
-			// When running locally, no external IP is set
+		if pipelineInfo.GithookURL == githookURLPending {
 			return pipelineInfo, nil
 		}
-		if numIPs != 1 {
-			return nil, errors.Errorf("unexpected number of external IPs set for githook service")
-		}
-		ingress := svc.Status.LoadBalancer.Ingress[0]
-		if ingress.IP != "" {
-			// GKE load balancing
-			pipelineInfo.GithookURL = githook.URLFromDomain(ingress.IP)
-		} else if ingress.Hostname != "" {
-			// AWS load balancing
-			pipelineInfo.GithookURL = githook.URLFromDomain(ingress.Hostname)
-		}
 	}
 
 	workerPoolID := ppsutil.PipelineRcName(pipelineInfo.Pipeline.Name, pipelineInfo.Version)
@@ -2731,6 +3801,76 @@ func (a *apiServer) listPipeline(pachClient *client.APIClient, request *pps.List
 		})
 }
 
+// WatchPipelines implements the protobuf pps.WatchPipelines RPC
+func (a *apiServer) WatchPipelines(request *pps.WatchPipelinesRequest, resp pps.API_WatchPipelinesServer) (retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	sent := 0
+	defer func(start time.Time) {
+		a.Log(request, fmt.Sprintf("stream containing %d PipelineEvents", sent), retErr, time.Since(start))
+	}(time.Now())
+	pachClient := a.env.GetPachClient(resp.Context())
+	ctx, err := checkLoggedIn(pachClient)
+	if err != nil {
+		return err
+	}
+	t, err := tenant.FromAuth(ctx, pachClient.AuthAPIClient)
+	if err != nil {
+		return err
+	}
+	// The pipelines that have been sent, so that deletes of pipelines that
+	// weren't (which only carry the pipeline's name) aren't sent either
+	seen := make(map[string]bool)
+	send := func(event *pps.PipelineEvent) error {
+		if err := resp.Send(event); err != nil {
+			return err
+		}
+		sent++
+		return nil
+	}
+	return a.pipelines.ReadOnly(ctx).WatchF(func(e *watch.Event) error {
+		switch e.Type {
+		case watch.EventSynced:
+			return send(&pps.PipelineEvent{Type: pps.PipelineEvent_SYNCED})
+		case watch.EventDelete:
+			name := string(e.Key)
+			if !seen[name] {
+				return nil
+			}
+			delete(seen, name)
+			return send(&pps.PipelineEvent{
+				Type:         pps.PipelineEvent_DELETE,
+				PipelineInfo: &pps.PipelineInfo{Pipeline: client.NewPipeline(name)},
+			})
+		case watch.EventPut:
+			var name string
+			ptr := &pps.EtcdPipelineInfo{}
+			if err := e.Unmarshal(&name, ptr); err != nil {
+				return err
+			}
+			if t != "" && ptr.Tenant != t {
+				return nil
+			}
+			pipelineInfo, err := ppsutil.GetPipelineInfo(pachClient, name, ptr)
+			if err != nil {
+				return err
+			}
+			seen[name] = true
+			return send(&pps.PipelineEvent{Type: pps.PipelineEvent_PUT, PipelineInfo: pipelineInfo})
+		}
+		return nil
+	}, watch.WithSynced())
+}
+
+// checkTenant returns an error if the caller of 'pachClient' is confined to a
+// tenant that doesn't own 'pipeline'.
+func (a *apiServer) checkTenant(pachClient *client.APIClient, pipeline string) error {
+	t, err := tenant.FromAuth(pachClient.Ctx(), pachClient.AuthAPIClient)
+	if err != nil {
+		return err
+	}
+	return tenant.CheckName(t, pipeline)
+}
+
 // listPipelinePtr enumerates all PPS pipelines in etcd, filters them based on
 // 'request', and then calls 'f' on each value
 func (a *apiServer) listPipelinePtr(pachClient *client.APIClient,
@@ -2754,12 +3894,31 @@ func (a *apiServer) listPipelinePtr(pachClient *client.APIClient,
 		return nil // shouldn't happen
 	}
 	if pipeline == nil {
-		if err := a.pipelines.ReadOnly(pachClient.Ctx()).List(p, col.DefaultOptions, func(name string) error {
+		t, err := tenant.FromAuth(pachClient.Ctx(), pachClient.AuthAPIClient)
+		if err != nil {
+			return err
+		}
+		// A tenant's pipelines are all under its prefix in etcd, so callers
+		// confined to a tenant only read (and return) those
+		pipelines := a.pipelines.ReadOnly(pachClient.Ctx())
+		list := pipelines.List
+		if t != "" {
+			list = func(val proto.Message, opts *col.Options, f func(string) error) error {
+				return pipelines.ListPrefix(tenant.Prefix(t), val, opts, f)
+			}
+		}
+		if err := list(p, col.DefaultOptions, func(name string) error {
+			if t != "" && p.Tenant != t {
+				return nil
+			}
 			return forEachPipeline(name)
 		}); err != nil {
 			return err
 		}
 	} else {
+		if err := a.checkTenant(pachClient, pipeline.Name); err != nil {
+			return err
+		}
 		if err := a.pipelines.ReadOnly(pachClient.Ctx()).Get(pipeline.Name, p); err != nil {
 			if col.IsErrNotFound(err) {
 				return errors.Errorf("pipeline \"%s\" not found", pipeline.Name)
@@ -2787,7 +3946,14 @@ func (a *apiServer) DeletePipeline(ctx context.Context, request *pps.DeletePipel
 	if request.All {
 		request.Pipeline = &pps.Pipeline{}
 		pipelinePtr := &pps.EtcdPipelineInfo{}
+		t, err := tenant.FromAuth(ctx, pachClient.AuthAPIClient)
+		if err != nil {
+			return nil, err
+		}
 		if err := a.pipelines.ReadOnly(ctx).List(pipelinePtr, col.DefaultOptions, func(pipelineName string) error {
+			if t != "" && pipelinePtr.Tenant != t {
+				return nil // only delete the tenant's pipelines
+			}
 			request.Pipeline.Name = pipelineName
 			_, err := a.deletePipeline(pachClient, request)
 			return err
@@ -2822,6 +3988,13 @@ func (a *apiServer) cleanUpSpecBranch(pachClient *client.APIClient, pipeline str
 func (a *apiServer) deletePipeline(pachClient *client.APIClient, request *pps.DeletePipelineRequest) (response *types.Empty, retErr error) {
 	ctx := pachClient.Ctx() // pachClient will propagate auth info
 
+	// Callers confined to a tenant may only delete the tenant's pipelines. This
+	// is checked up front, as the rest of the auth checks are skipped for
+	// pipelines whose output repo is missing
+	if err := a.checkTenant(pachClient, request.Pipeline.Name); err != nil {
+		return nil, err
+	}
+
 	// Check if there's an EtcdPipelineInfo for this pipeline. If not, we can't
 	// authorize, and must return something here
 	pipelinePtr := pps.EtcdPipelineInfo{}
@@ -2863,12 +4036,15 @@ func (a *apiServer) deletePipeline(pachClient *client.APIClient, request *pps.De
 			// at the same commit, but also pass empty provenance slice)
 			if err := pachClient.CreateBranch(
 				request.Pipeline.Name,
-				pipelineInfo.OutputBranch,
-				pipelineInfo.OutputBranch,
+				ppsutil.JobOutputBranch(pipelineInfo),
+				ppsutil.JobOutputBranch(pipelineInfo),
 				nil,
 			); err != nil {
 				return nil, err
 			}
+			if err := stopOutputBranches(pachClient, outputBranches(pipelineInfo)); err != nil {
+				return nil, err
+			}
 		} else {
 			// delete the pipeline's output repo
 			if err := pachClient.DeleteRepo(request.Pipeline.Name, request.Force); err != nil {
@@ -2934,13 +4110,21 @@ func (a *apiServer) deletePipeline(pachClient *client.APIClient, request *pps.De
 	// Delete EtcdPipelineInfo
 	eg.Go(func() error {
 		if _, err := col.NewSTM(ctx, a.env.GetEtcdClient(), func(stm col.STM) error {
-			return a.pipelines.ReadWrite(stm).Delete(request.Pipeline.Name)
+			pipelinePtr := &pps.EtcdPipelineInfo{}
+			if err := a.pipelines.ReadWrite(stm).Get(request.Pipeline.Name, pipelinePtr); err != nil {
+				return err
+			}
+			if err := a.pipelines.ReadWrite(stm).Delete(request.Pipeline.Name); err != nil {
+				return err
+			}
+			return tenantutil.Release(a.tenantPipelines.ReadWriteInt(stm), pipelinePtr.Tenant)
 		}); err != nil {
 			return errors.Wrapf(err, "collection.Delete")
 		}
 		return nil
 	})
-	// Delete cron input repos
+	// Delete the repos of inputs that pachd populates (cron, http mirror,
+	// parameter, remote repo and db import inputs)
 	if !request.KeepRepo {
 		pps.VisitInput(pipelineInfo.Input, func(input *pps.Input) {
 			if input.Cron != nil {
@@ -2948,7 +4132,36 @@ func (a *apiServer) deletePipeline(pachClient *client.APIClient, request *pps.De
 					return pachClient.DeleteRepo(input.Cron.Repo, request.Force)
 				})
 			}
+			if input.HTTPMirror != nil {
+				eg.Go(func() error {
+					return pachClient.DeleteRepo(input.HTTPMirror.Repo, request.Force)
+				})
+			}
+			if input.Parameter != nil {
+				eg.Go(func() error {
+					return pachClient.DeleteRepo(input.Parameter.Repo, request.Force)
+				})
+			}
+			if input.RemoteRepo != nil {
+				eg.Go(func() error {
+					return pachClient.DeleteRepo(input.RemoteRepo.Repo, request.Force)
+				})
+			}
+			if input.DBImport != nil {
+				eg.Go(func() error {
+					return pachClient.DeleteRepo(input.DBImport.Repo, request.Force)
+				})
+			}
 		})
+		for _, output := range pipelineInfo.Outputs {
+			output := output
+			eg.Go(func() error {
+				if err := pachClient.DeleteRepo(output.Repo, request.Force); err != nil && !isNotFoundErr(err) {
+					return err
+				}
+				return nil
+			})
+		}
 	}
 	if err := eg.Wait(); err != nil {
 		return nil, err
@@ -2988,12 +4201,15 @@ func (a *apiServer) StartPipeline(ctx context.Context, request *pps.StartPipelin
 		client.NewBranch(ppsconsts.SpecRepo, pipelineInfo.Pipeline.Name))
 	if err := pachClient.CreateBranch(
 		request.Pipeline.Name,
-		pipelineInfo.OutputBranch,
-		pipelineInfo.OutputBranch,
+		ppsutil.JobOutputBranch(pipelineInfo),
+		ppsutil.JobOutputBranch(pipelineInfo),
 		provenance,
 	); err != nil {
 		return nil, err
 	}
+	if err := setOutputProvenance(pachClient, pipelineInfo, provenance); err != nil {
+		return nil, err
+	}
 	return &types.Empty{}, nil
 }
 
@@ -3018,12 +4234,15 @@ func (a *apiServer) StopPipeline(ctx context.Context, request *pps.StopPipelineR
 	// at the same commit, but also pass empty provenance slice)
 	if err := pachClient.CreateBranch(
 		request.Pipeline.Name,
-		pipelineInfo.OutputBranch,
-		pipelineInfo.OutputBranch,
+		ppsutil.JobOutputBranch(pipelineInfo),
+		ppsutil.JobOutputBranch(pipelineInfo),
 		nil,
 	); err != nil {
 		return nil, err
 	}
+	if err := setOutputProvenance(pachClient, pipelineInfo, nil); err != nil {
+		return nil, err
+	}
 
 	// Update PipelineInfo with new state
 	pipelineInfo.Stopped = true
@@ -3052,7 +4271,7 @@ func (a *apiServer) RunPipeline(ctx context.Context, request *pps.RunPipelineReq
 	}
 	// make sure the user isn't trying to run pipeline on an empty branch
 	branch, err := pfsClient.InspectBranch(ctx, &pfs.InspectBranchRequest{
-		Branch: client.NewBranch(request.Pipeline.Name, pipelineInfo.OutputBranch),
+		Branch: client.NewBranch(request.Pipeline.Name, ppsutil.JobOutputBranch(pipelineInfo)),
 	})
 	if err != nil {
 		return nil, err
@@ -3091,6 +4310,7 @@ func (a *apiServer) RunPipeline(ctx context.Context, request *pps.RunPipelineReq
 	provenance := request.Provenance
 	provenanceMap := make(map[string]*pfs.CommitProvenance)
 
+	seedSalt := request.SeedSalt
 	if request.JobID != "" {
 		jobInfo, err := ppsClient.InspectJob(ctx, &pps.InspectJobRequest{
 			Job: client.NewJob(request.JobID),
@@ -3098,6 +4318,10 @@ func (a *apiServer) RunPipeline(ctx context.Context, request *pps.RunPipelineReq
 		if err != nil {
 			return nil, err
 		}
+		if seedSalt == "" {
+			// give the new job's datums the same seeds as the original job's
+			seedSalt = ppsutil.JobSeedSalt(jobInfo)
+		}
 		jobOutputCommit, err := pfsClient.InspectCommit(ctx, &pfs.InspectCommitRequest{
 			Commit: jobInfo.OutputCommit,
 		})
@@ -3172,6 +4396,12 @@ func (a *apiServer) RunPipeline(ctx context.Context, request *pps.RunPipelineReq
 	if _, ok := provenanceMap[key(specProvenance.Branch.Repo.Name, specProvenance.Branch.Name)]; !ok {
 		provenance = append(provenance, specProvenance)
 	}
+	// the worker that creates the new job reads its seed salt from the output
+	// commit's metadata
+	var metadata map[string]string
+	if seedSalt != "" {
+		metadata = map[string]string{client.SeedSaltMetadataKey: seedSalt}
+	}
 	if _, err := pachClient.ExecuteInTransaction(func(txnClient *client.APIClient) error {
 		newCommit, err := txnClient.PfsAPIClient.StartCommit(txnClient.Ctx(), &pfs.StartCommitRequest{
 			Parent: &pfs.Commit{
@@ -3180,6 +4410,7 @@ func (a *apiServer) RunPipeline(ctx context.Context, request *pps.RunPipelineReq
 				},
 			},
 			Provenance: provenance,
+			Metadata:   metadata,
 		})
 		if err != nil {
 			return err
@@ -3261,7 +4492,7 @@ func (a *apiServer) RunCron(ctx context.Context, request *pps.RunCronRequest) (r
 		}
 
 		// Put in an empty file named by the timestamp
-		_, err = pfc.PutFile(cron.Repo, "master", time.Now().Format(time.RFC3339), strings.NewReader(""))
+		_, err = pfc.PutFile(cron.Repo, "master", time.Now().UTC().Format(time.RFC3339), strings.NewReader(""))
 		if err != nil {
 			return nil, errors.Wrapf(err, "put error")
 		}
@@ -3279,6 +4510,145 @@ func (a *apiServer) RunCron(ctx context.Context, request *pps.RunCronRequest) (r
 	return &types.Empty{}, nil
 }
 
+// ApplyDAG implements the protobuf pps.ApplyDAG RPC
+func (a *apiServer) ApplyDAG(ctx context.Context, request *pps.ApplyDAGRequest) (response *pps.ApplyDAGResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	metricsFn := metrics.ReportUserAction(ctx, a.reporter, "ApplyDAG")
+	defer func(start time.Time) { metricsFn(start, retErr) }(time.Now())
+
+	for _, pipelineRequest := range request.Pipelines {
+		if err := a.validatePipelineRequest(pipelineRequest); err != nil {
+			return nil, err
+		}
+	}
+	pachClient := a.env.GetPachClient(ctx)
+	existingPipelines := make(map[string]*pps.PipelineInfo)
+	if err := a.listPipeline(pachClient, &pps.ListPipelineRequest{}, func(pipelineInfo *pps.PipelineInfo) error {
+		existingPipelines[pipelineInfo.Pipeline.Name] = pipelineInfo
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	repoInfos, err := pachClient.ListRepo()
+	if err != nil {
+		return nil, err
+	}
+	existingRepos := make(map[string]*pfs.RepoInfo)
+	for _, repoInfo := range repoInfos {
+		existingRepos[repoInfo.Repo.Name] = repoInfo
+	}
+	changes, err := planDAG(request, existingPipelines, existingRepos)
+	if err != nil {
+		return nil, err
+	}
+	if !request.DryRun {
+		if err := a.applyDAG(pachClient, request, changes); err != nil {
+			return nil, err
+		}
+	}
+	return &pps.ApplyDAGResponse{Changes: changes}, nil
+}
+
+// ListIdlePipelines implements the protobuf pps.ListIdlePipelines RPC
+func (a *apiServer) ListIdlePipelines(ctx context.Context, request *pps.ListIdlePipelinesRequest) (response *pps.ListIdlePipelinesResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	var within time.Duration
+	if request.Within != nil {
+		var err error
+		if within, err = types.DurationFromProto(request.Within); err != nil {
+			return nil, err
+		}
+	}
+	idlePipelines, err := a.idlePipelines(a.env.GetPachClient(ctx))
+	if err != nil {
+		return nil, err
+	}
+	response = &pps.ListIdlePipelinesResponse{}
+	deadline := time.Now().Add(within)
+	for _, idle := range idlePipelines {
+		if idle.IdleSince != nil || isReapable(idle, deadline) {
+			response.Pipelines = append(response.Pipelines, idle)
+		}
+	}
+	sort.Slice(response.Pipelines, func(i, j int) bool {
+		return response.Pipelines[i].ReapAt.Compare(response.Pipelines[j].ReapAt) < 0
+	})
+	return response, nil
+}
+
+// GetScheduler implements the protobuf pps.GetScheduler RPC
+func (a *apiServer) GetScheduler(ctx context.Context, request *pps.GetSchedulerRequest) (response *pps.GetSchedulerResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.env.GetPachClient(ctx)
+	response = &pps.GetSchedulerResponse{}
+	if err := a.listPipeline(pachClient, &pps.ListPipelineRequest{Pipeline: request.Pipeline}, func(pipelineInfo *pps.PipelineInfo) error {
+		schedule, err := a.pipelineSchedule(pachClient, pipelineInfo)
+		if err != nil {
+			return errors.Wrapf(err, "could not describe pipeline %q", pipelineInfo.Pipeline.Name)
+		}
+		if request.Pipeline != nil || len(schedule.Jobs) > 0 || len(schedule.WorkerProblems) > 0 || schedule.GateStatus != nil ||
+			(schedule.QueueAdmission != nil && !schedule.QueueAdmission.Admitted) {
+			response.Pipelines = append(response.Pipelines, schedule)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(response.Pipelines, func(i, j int) bool {
+		return response.Pipelines[i].Pipeline.Name < response.Pipelines[j].Pipeline.Name
+	})
+	return response, nil
+}
+
+// ApproveCommit implements the protobuf pps.ApproveCommit RPC
+func (a *apiServer) ApproveCommit(ctx context.Context, request *pps.ApproveCommitRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	metricsFn := metrics.ReportUserAction(ctx, a.reporter, "ApproveCommit")
+	defer func(start time.Time) { metricsFn(start, retErr) }(time.Now())
+	pachClient := a.env.GetPachClient(ctx)
+	if err := a.approveCommit(pachClient, request.Commit); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
+// UpdatePipelineConfig implements the protobuf pps.UpdatePipelineConfig RPC
+func (a *apiServer) UpdatePipelineConfig(ctx context.Context, request *pps.UpdatePipelineConfigRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	metricsFn := metrics.ReportUserAction(ctx, a.reporter, "UpdatePipelineConfig")
+	defer func(start time.Time) { metricsFn(start, retErr) }(time.Now())
+	pachClient := a.env.GetPachClient(ctx)
+	if err := a.updatePipelineConfig(pachClient, request.Pipeline, request.RuntimeConfig); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
+// PruneStats implements the protobuf pps.PruneStats RPC
+func (a *apiServer) PruneStats(ctx context.Context, request *pps.PruneStatsRequest) (response *pps.PruneStatsResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	metricsFn := metrics.ReportUserAction(ctx, a.reporter, "PruneStats")
+	defer func(start time.Time) { metricsFn(start, retErr) }(time.Now())
+
+	// Stats commits are pruned as the caller, who must be able to write to
+	// the pipelines' output repos
+	pachClient := a.env.GetPachClient(ctx)
+	if _, err := checkLoggedIn(pachClient); err != nil {
+		return nil, err
+	}
+	pruned, err := a.pruneStats(pachClient, request.Pipeline, request.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	return &pps.PruneStatsResponse{Pruned: pruned}, nil
+}
+
 // CreateSecret implements the protobuf pps.CreateSecret RPC
 func (a *apiServer) CreateSecret(ctx context.Context, request *pps.CreateSecretRequest) (response *types.Empty, retErr error) {
 	func() { a.Log(request, nil, nil, 0) }()
@@ -3392,8 +4762,13 @@ func (a *apiServer) DeleteAll(ctx context.Context, request *types.Empty) (respon
 	pachClient := a.env.GetPachClient(ctx)
 	ctx = pachClient.Ctx() // pachClient will propagate auth info
 
-	// check if the caller is authorized -- they must be an admin
+	// check if the caller is authorized -- they must be an admin, unless
+	// they're confined to a tenant, in which case only the tenant's pipelines
+	// are deleted (and cluster-wide state, like secrets and the spec repo, is
+	// left alone)
+	var username, t string
 	if me, err := pachClient.WhoAmI(ctx, &auth.WhoAmIRequest{}); err == nil {
+		username, t = me.Username, me.Tenant
 		var isAdmin bool
 		for _, s := range me.ClusterRoles.Roles {
 			if s == auth.ClusterRole_SUPER {
@@ -3402,7 +4777,7 @@ func (a *apiServer) DeleteAll(ctx context.Context, request *types.Empty) (respon
 			}
 		}
 
-		if !isAdmin {
+		if !isAdmin && t == "" {
 			return nil, &auth.ErrNotAuthorized{
 				Subject: me.Username,
 				AdminOp: "DeleteAll",
@@ -3411,6 +4786,30 @@ func (a *apiServer) DeleteAll(ctx context.Context, request *types.Empty) (respon
 	} else if !auth.IsErrNotActivated(err) {
 		return nil, errors.Wrapf(err, "error during authorization check")
 	}
+	if err := deleteall.Check(ctx, a.env.GetEtcdClient(), a.env.EtcdPrefix, a.env.DeleteAllPolicy, "pps.DeleteAll", username); err != nil {
+		return nil, err
+	}
+	defer func() {
+		deleteall.Audit("pps.DeleteAll", username, retErr)
+	}()
+
+	if t != "" {
+		// Check that the caller may delete every one of the tenant's
+		// pipelines before deleting any of them
+		if err := a.listPipelinePtr(pachClient, nil, 0, func(name string, _ *pps.EtcdPipelineInfo) error {
+			pipelineInfo, err := a.inspectPipeline(pachClient, name)
+			if err != nil {
+				return err
+			}
+			return a.authorizePipelineOp(pachClient, pipelineOpDelete, pipelineInfo.Input, name)
+		}); err != nil {
+			return nil, err
+		}
+		if _, err := a.DeletePipeline(ctx, &pps.DeletePipelineRequest{All: true, Force: true}); err != nil {
+			return nil, err
+		}
+		return &types.Empty{}, nil
+	}
 
 	if _, err := a.DeletePipeline(ctx, &pps.DeletePipelineRequest{All: true, Force: true}); err != nil {
 		return nil, err
@@ -3449,8 +4848,9 @@ type ActiveStat struct {
 }
 
 // CollectActiveObjectsAndTags collects all objects/tags that are not deleted
-// or eligible for garbage collection
-func CollectActiveObjectsAndTags(ctx context.Context, pachClient *client.APIClient, repoInfos []*pfs.RepoInfo, pipelineInfos []*pps.PipelineInfo, memoryAllowance int, storageRoot string) (*ActiveStat, error) {
+// or eligible for garbage collection. The commits in 'deletedInfos' (which are
+// in the trash) are treated like the commits of 'repoInfos'.
+func CollectActiveObjectsAndTags(ctx context.Context, pachClient *client.APIClient, repoInfos []*pfs.RepoInfo, deletedInfos []*pfs.DeletedInfo, pipelineInfos []*pps.PipelineInfo, memoryAllowance int, storageRoot string) (*ActiveStat, error) {
 	if memoryAllowance == 0 {
 		memoryAllowance = defaultGCMemory
 	}
@@ -3519,6 +4919,18 @@ func CollectActiveObjectsAndTags(ctx context.Context, pachClient *client.APIClie
 			})
 		}
 	}
+	for _, deletedInfo := range deletedInfos {
+		for _, ci := range deletedInfo.CommitInfos {
+			ci := ci
+			limiter.Acquire()
+			eg.Go(func() error {
+				defer limiter.Release()
+				addActiveObjects(ci.Trees...)
+				addActiveObjects(ci.Datums)
+				return addActiveTree(ci.Tree)
+			})
+		}
+	}
 	if err := eg.Wait(); err != nil {
 		return nil, err
 	}
@@ -3596,7 +5008,16 @@ func (a *apiServer) GarbageCollect(ctx context.Context, request *pps.GarbageColl
 	if err != nil {
 		return nil, err
 	}
-	activeStat, err := CollectActiveObjectsAndTags(ctx, pachClient, append(repoInfos.RepoInfo, specRepoInfo), pipelineInfos.PipelineInfo, int(request.MemoryBytes), a.storageRoot)
+	// Deleted repos and commits whose retention has expired are purged, and
+	// the objects of the rest are kept so that they can still be restored
+	if err := pachClient.PurgeDeleted(""); err != nil {
+		return nil, err
+	}
+	deletedInfos, err := pachClient.ListDeleted()
+	if err != nil {
+		return nil, err
+	}
+	activeStat, err := CollectActiveObjectsAndTags(ctx, pachClient, append(repoInfos.RepoInfo, specRepoInfo), deletedInfos, pipelineInfos.PipelineInfo, int(request.MemoryBytes), a.storageRoot)
 	if err != nil {
 		return nil, err
 	}
@@ -3708,6 +5129,7 @@ func (a *apiServer) ActivateAuth(ctx context.Context, req *pps.ActivateAuthReque
 			return a.sudo(pachClient, func(superUserClient *client.APIClient) error {
 				tokenResp, err := superUserClient.GetAuthToken(superUserClient.Ctx(), &auth.GetAuthTokenRequest{
 					Subject: auth.PipelinePrefix + pipelineName,
+					Tenant:  pipeline.Tenant,
 				})
 				if err != nil {
 					return errors.Wrapf(grpcutil.ScrubGRPC(err), "could not generate pipeline auth token")
@@ -3779,6 +5201,8 @@ func (a *apiServer) updatePipelineSpecCommit(pachClient *client.APIClient, pipel
 			return err
 		}
 		pipelinePtr.SpecCommit = commit
+		// A new spec commit resets the pipeline's idle time
+		pipelinePtr.IdleSince = nil
 		return pipelines.Put(pipelineName, pipelinePtr)
 	})
 	if isNotFoundErr(err) {
@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsdb"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// autoscaleInterval is how often the PPS master rescales autoscaled
+	// pipelines
+	autoscaleInterval = 30 * time.Second
+	// defaultScaleDownDelay is how long an autoscaled pipeline must need fewer
+	// workers before it's scaled down, unless its AutoscaleSpec says otherwise
+	defaultScaleDownDelay = 5 * time.Minute
+)
+
+// validateAutoscale returns an error if the AutoscaleSpec of 'pipelineInfo'
+// is invalid.
+func validateAutoscale(pipelineInfo *pps.PipelineInfo) error {
+	spec := pipelineInfo.ParallelismSpec.GetAutoscale()
+	if spec == nil {
+		return nil
+	}
+	if pipelineInfo.ParallelismSpec.Constant != 0 || pipelineInfo.ParallelismSpec.Coefficient != 0 {
+		return errors.New("contradictory parallelism strategies: ParallelismSpec.Autoscale " +
+			"can't be set with ParallelismSpec.Constant or ParallelismSpec.Coefficient")
+	}
+	if spec.MaxWorkers == 0 {
+		return errors.New("invalid pipeline spec: autoscale must set max_workers")
+	}
+	if spec.MaxWorkers < autoscaleMinWorkers(spec) {
+		return errors.Errorf("invalid pipeline spec: autoscale's max_workers (%d) is less than its min_workers (%d)",
+			spec.MaxWorkers, spec.MinWorkers)
+	}
+	if spec.ScaleDownDelay != nil {
+		delay, err := types.DurationFromProto(spec.ScaleDownDelay)
+		if err != nil {
+			return errors.Wrapf(err, "invalid pipeline spec: invalid autoscale scale_down_delay")
+		}
+		if delay < 0 {
+			return errors.New("invalid pipeline spec: autoscale's scale_down_delay can't be negative")
+		}
+	}
+	if pipelineInfo.Service != nil || pipelineInfo.Spout != nil {
+		return errors.New("invalid pipeline spec: services and spouts don't process datums, so they can't be autoscaled")
+	}
+	if pipelineInfo.SchedulingSpec.GetGangScheduling() != nil {
+		// The size of a gang-scheduled pipeline's pod group is fixed
+		return errors.New("invalid pipeline spec: gang-scheduled pipelines can't be autoscaled")
+	}
+	return nil
+}
+
+// autoscaleMinWorkers returns the number of workers that an autoscaled
+// pipeline runs while it has no outstanding datums.
+func autoscaleMinWorkers(spec *pps.AutoscaleSpec) uint64 {
+	if spec.MinWorkers == 0 {
+		return 1
+	}
+	return spec.MinWorkers
+}
+
+// outstandingDatums returns the number of datums of 'jobPtr' that haven't
+// been processed yet. Jobs that are still starting haven't counted their
+// datums, so they have none.
+func outstandingDatums(jobPtr *pps.EtcdJobInfo) int64 {
+	outstanding := jobPtr.DataTotal - jobPtr.DataProcessed - jobPtr.DataSkipped - jobPtr.DataFailed - jobPtr.DataRecovered
+	if outstanding < 0 {
+		return 0
+	}
+	return outstanding
+}
+
+// autoscaleTarget returns the number of workers that a pipeline with 'spec'
+// needs for 'outstanding' datums.
+func autoscaleTarget(spec *pps.AutoscaleSpec, outstanding int64) uint64 {
+	perWorker := spec.DatumsPerWorker
+	if perWorker == 0 {
+		perWorker = 1
+	}
+	target := (uint64(outstanding) + perWorker - 1) / perWorker
+	if min := autoscaleMinWorkers(spec); target < min {
+		target = min
+	}
+	if target > spec.MaxWorkers {
+		target = spec.MaxWorkers
+	}
+	return target
+}
+
+// nextParallelism returns the number of workers that a pipeline with 'spec',
+// which is running 'current' workers, should run for 'outstanding' datums at
+// 'now'. It's scaled up immediately, but only scaled down once it has needed
+// fewer workers for the spec's scale-down delay, starting at 'fewerSince'
+// (zero if it needed at least 'current' workers until now). It also returns
+// the new value of 'fewerSince'.
+func nextParallelism(spec *pps.AutoscaleSpec, current uint64, outstanding int64, fewerSince, now time.Time) (uint64, time.Time, error) {
+	target := autoscaleTarget(spec, outstanding)
+	if target >= current {
+		return target, time.Time{}, nil
+	}
+	delay := defaultScaleDownDelay
+	if spec.ScaleDownDelay != nil {
+		var err error
+		if delay, err = types.DurationFromProto(spec.ScaleDownDelay); err != nil {
+			return 0, time.Time{}, err
+		}
+	}
+	if fewerSince.IsZero() {
+		fewerSince = now
+	}
+	if now.Sub(fewerSince) < delay {
+		return current, fewerSince, nil
+	}
+	return target, time.Time{}, nil
+}
+
+// pipelineOutstandingDatums returns the number of datums that the running
+// jobs of 'pipeline' haven't processed yet.
+func (a *apiServer) pipelineOutstandingDatums(ctx context.Context, pipeline *pps.Pipeline) (int64, error) {
+	var outstanding int64
+	jobPtr := &pps.EtcdJobInfo{}
+	if err := a.jobs.ReadOnly(ctx).GetByIndex(ppsdb.JobsPipelineIndex, pipeline, jobPtr, col.DefaultOptions, func(string) error {
+		if !ppsutil.IsTerminal(jobPtr.State) {
+			outstanding += outstandingDatums(jobPtr)
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return outstanding, nil
+}
+
+// autoscalePipelines sets the parallelism of each running, autoscaled
+// pipeline to the number of workers that its outstanding datums need (see
+// pps.AutoscaleSpec), which the pipeline controller then scales its RC to.
+// 'fewerSince' tracks when each pipeline started needing fewer workers, across
+// calls. Pipelines that have been scaled with ScaleJob are left alone until
+// the job finishes.
+func (a *apiServer) autoscalePipelines(pachClient *client.APIClient, fewerSince map[string]time.Time) error {
+	return a.sudo(pachClient, func(superUserClient *client.APIClient) error {
+		ctx := superUserClient.Ctx()
+		autoscaled := make(map[string]bool)
+		if err := a.listPipeline(superUserClient, &pps.ListPipelineRequest{}, func(pipelineInfo *pps.PipelineInfo) error {
+			spec := pipelineInfo.ParallelismSpec.GetAutoscale()
+			if spec == nil || pipelineInfo.State != pps.PipelineState_PIPELINE_RUNNING {
+				return nil
+			}
+			pipeline := pipelineInfo.Pipeline.Name
+			autoscaled[pipeline] = true
+			outstanding, err := a.pipelineOutstandingDatums(ctx, pipelineInfo.Pipeline)
+			if err != nil {
+				return errors.Wrapf(err, "could not count the outstanding datums of pipeline %q", pipeline)
+			}
+			var from, to uint64
+			var since time.Time
+			if _, err := col.NewSTM(ctx, a.env.GetEtcdClient(), func(stm col.STM) error {
+				pipelines := a.pipelines.ReadWrite(stm)
+				pipelinePtr := &pps.EtcdPipelineInfo{}
+				if err := pipelines.Get(pipeline, pipelinePtr); err != nil {
+					return err
+				}
+				from, to, since = pipelinePtr.Parallelism, pipelinePtr.Parallelism, time.Time{}
+				if pipelinePtr.State != pps.PipelineState_PIPELINE_RUNNING || pipelinePtr.ScaledJob != "" {
+					return nil
+				}
+				var err error
+				if to, since, err = nextParallelism(spec, from, outstanding, fewerSince[pipeline], time.Now()); err != nil {
+					return err
+				}
+				if to == from {
+					return nil
+				}
+				pipelinePtr.Parallelism = to
+				return pipelines.Put(pipeline, pipelinePtr)
+			}); err != nil {
+				return errors.Wrapf(err, "could not autoscale pipeline %q", pipeline)
+			}
+			if to != from {
+				log.Infof("PPS master: autoscaling the workers of %q from %d to %d (%d outstanding datums)",
+					pipeline, from, to, outstanding)
+			}
+			if since.IsZero() {
+				delete(fewerSince, pipeline)
+			} else {
+				fewerSince[pipeline] = since
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		for pipeline := range fewerSince {
+			if !autoscaled[pipeline] {
+				delete(fewerSince, pipeline)
+			}
+		}
+		return nil
+	})
+}
+
+// runAutoscaler periodically autoscales pipelines until 'ctx' is cancelled.
+// It runs in the PPS master.
+func (a *apiServer) runAutoscaler(ctx context.Context, pachClient *client.APIClient) {
+	ticker := time.NewTicker(autoscaleInterval)
+	defer ticker.Stop()
+	fewerSince := make(map[string]time.Time)
+	for {
+		if err := a.autoscalePipelines(pachClient, fewerSince); err != nil {
+			log.Errorf("PPS master: could not autoscale pipelines: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
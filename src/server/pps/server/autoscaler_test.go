@@ -0,0 +1,108 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateAutoscale(t *testing.T) {
+	pipelineInfo := func(pspec *pps.ParallelismSpec) *pps.PipelineInfo {
+		return &pps.PipelineInfo{Pipeline: client.NewPipeline("p"), ParallelismSpec: pspec}
+	}
+	require.NoError(t, validateAutoscale(pipelineInfo(nil)))
+	require.NoError(t, validateAutoscale(pipelineInfo(&pps.ParallelismSpec{Constant: 2})))
+	require.NoError(t, validateAutoscale(pipelineInfo(&pps.ParallelismSpec{
+		Autoscale: &pps.AutoscaleSpec{MaxWorkers: 10},
+	})))
+	require.NoError(t, validateAutoscale(pipelineInfo(&pps.ParallelismSpec{
+		Autoscale: &pps.AutoscaleSpec{MinWorkers: 2, MaxWorkers: 2},
+	})))
+	require.YesError(t, validateAutoscale(pipelineInfo(&pps.ParallelismSpec{
+		Constant:  2,
+		Autoscale: &pps.AutoscaleSpec{MaxWorkers: 10},
+	})))
+	require.YesError(t, validateAutoscale(pipelineInfo(&pps.ParallelismSpec{
+		Autoscale: &pps.AutoscaleSpec{MinWorkers: 2},
+	})))
+	require.YesError(t, validateAutoscale(pipelineInfo(&pps.ParallelismSpec{
+		Autoscale: &pps.AutoscaleSpec{MinWorkers: 3, MaxWorkers: 2},
+	})))
+	require.YesError(t, validateAutoscale(pipelineInfo(&pps.ParallelismSpec{
+		Autoscale: &pps.AutoscaleSpec{MaxWorkers: 2, ScaleDownDelay: types.DurationProto(-time.Minute)},
+	})))
+
+	spout := pipelineInfo(&pps.ParallelismSpec{Autoscale: &pps.AutoscaleSpec{MaxWorkers: 2}})
+	spout.Spout = &pps.Spout{}
+	require.YesError(t, validateAutoscale(spout))
+	gang := pipelineInfo(&pps.ParallelismSpec{Autoscale: &pps.AutoscaleSpec{MaxWorkers: 2}})
+	gang.SchedulingSpec = &pps.SchedulingSpec{GangScheduling: &pps.GangScheduling{}}
+	require.YesError(t, validateAutoscale(gang))
+}
+
+func TestOutstandingDatums(t *testing.T) {
+	require.Equal(t, int64(0), outstandingDatums(&pps.EtcdJobInfo{}))
+	require.Equal(t, int64(3), outstandingDatums(&pps.EtcdJobInfo{
+		DataTotal:     10,
+		DataProcessed: 4,
+		DataSkipped:   1,
+		DataFailed:    1,
+		DataRecovered: 1,
+	}))
+	// Retried datums may be counted more than once
+	require.Equal(t, int64(0), outstandingDatums(&pps.EtcdJobInfo{DataTotal: 2, DataProcessed: 3}))
+}
+
+func TestAutoscaleTarget(t *testing.T) {
+	spec := &pps.AutoscaleSpec{MinWorkers: 2, MaxWorkers: 10, DatumsPerWorker: 5}
+	require.Equal(t, uint64(2), autoscaleTarget(spec, 0))
+	require.Equal(t, uint64(2), autoscaleTarget(spec, 10))
+	require.Equal(t, uint64(3), autoscaleTarget(spec, 11))
+	require.Equal(t, uint64(10), autoscaleTarget(spec, 50))
+	require.Equal(t, uint64(10), autoscaleTarget(spec, 1000))
+
+	// By default, there's a worker per datum, and at least one worker
+	spec = &pps.AutoscaleSpec{MaxWorkers: 4}
+	require.Equal(t, uint64(1), autoscaleTarget(spec, 0))
+	require.Equal(t, uint64(3), autoscaleTarget(spec, 3))
+	require.Equal(t, uint64(4), autoscaleTarget(spec, 5))
+}
+
+func TestNextParallelism(t *testing.T) {
+	spec := &pps.AutoscaleSpec{
+		MaxWorkers:     10,
+		ScaleDownDelay: types.DurationProto(time.Minute),
+	}
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// Pipelines are scaled up immediately
+	workers, since, err := nextParallelism(spec, 1, 5, time.Time{}, now)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), workers)
+	require.True(t, since.IsZero())
+
+	// ...but only scaled down after the delay
+	workers, since, err = nextParallelism(spec, 5, 2, time.Time{}, now)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), workers)
+	require.Equal(t, now, since)
+	workers, since, err = nextParallelism(spec, 5, 2, since, now.Add(30*time.Second))
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), workers)
+	require.Equal(t, now, since)
+	workers, since, err = nextParallelism(spec, 5, 2, since, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), workers)
+	require.True(t, since.IsZero())
+
+	// Needing as many workers again restarts the delay
+	workers, since, err = nextParallelism(spec, 5, 5, now, now.Add(30*time.Second))
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), workers)
+	require.True(t, since.IsZero())
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// builtinWorkerImage returns the image that runs pipelines with a builtin
+// transform (and no image of their own), which is built alongside
+// 'workerImage', e.g. pachyderm/worker:1.11.0 -> pachyderm/worker-builtin:1.11.0
+func builtinWorkerImage(workerImage string) string {
+	nameStart := strings.LastIndex(workerImage, "/") + 1
+	nameEnd := len(workerImage)
+	if i := strings.IndexAny(workerImage[nameStart:], ":@"); i >= 0 {
+		nameEnd = nameStart + i
+	}
+	return workerImage[:nameEnd] + "-builtin" + workerImage[nameEnd:]
+}
+
+// resolveBuiltinTransform checks that the plugin of 'builtin' exists, and pins
+// it to a commit, so that all of the pipeline's workers load the same plugin
+// until the pipeline is updated.
+func resolveBuiltinTransform(pachClient *client.APIClient, builtin *pps.BuiltinTransform) error {
+	commitInfo, err := pachClient.InspectCommit(builtin.Repo, builtin.Commit)
+	if err != nil {
+		return errors.Wrapf(grpcutil.ScrubGRPC(err), "could not resolve the commit of builtin transform %s@%s", builtin.Repo, builtin.Commit)
+	}
+	if _, err := pachClient.InspectFile(builtin.Repo, commitInfo.Commit.ID, builtin.Path); err != nil {
+		return errors.Wrapf(grpcutil.ScrubGRPC(err), "could not find builtin transform %s@%s:%s", builtin.Repo, builtin.Commit, builtin.Path)
+	}
+	builtin.Commit = commitInfo.Commit.ID
+	return nil
+}
@@ -0,0 +1,21 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestBuiltinWorkerImage(t *testing.T) {
+	require.Equal(t, "pachyderm/worker-builtin:1.11.0", builtinWorkerImage("pachyderm/worker:1.11.0"))
+	require.Equal(t, "localhost:5000/worker-builtin", builtinWorkerImage("localhost:5000/worker"))
+	require.Equal(t, "worker-builtin@sha256:abc", builtinWorkerImage("worker@sha256:abc"))
+}
+
+func TestValidateBuiltinTransform(t *testing.T) {
+	builtin := &pps.BuiltinTransform{Repo: "plugins", Commit: "master", Path: "/transform.so"}
+	require.NoError(t, validateTransform(&pps.Transform{Image: "pachyderm/worker-builtin", Builtin: builtin}))
+	require.YesError(t, validateTransform(&pps.Transform{Image: "pachyderm/worker-builtin", Builtin: builtin, Cmd: []string{"sh"}}))
+	require.YesError(t, validateTransform(&pps.Transform{Image: "pachyderm/worker-builtin", Builtin: &pps.BuiltinTransform{Repo: "plugins"}}))
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/metrics"
+
+	"golang.org/x/net/context"
+)
+
+// createdPipeline is a pipeline that CreatePipelines created or updated, which
+// it undoes if a later pipeline can't be created.
+type createdPipeline struct {
+	name string
+	// previous is the pipeline's spec before it was updated, or nil if the
+	// pipeline was created
+	previous *pps.CreatePipelineRequest
+}
+
+// CreatePipelines implements the protobuf pps.CreatePipelines RPC
+func (a *apiServer) CreatePipelines(ctx context.Context, request *pps.CreatePipelinesRequest) (response *pps.CreatePipelinesResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	metricsFn := metrics.ReportUserAction(ctx, a.reporter, "CreatePipelines")
+	defer func(start time.Time) { metricsFn(start, retErr) }(time.Now())
+
+	for _, pipelineRequest := range request.Pipelines {
+		if err := a.validatePipelineRequest(pipelineRequest); err != nil {
+			return nil, err
+		}
+	}
+	order, err := pipelineCreationOrder(request.Pipelines)
+	if err != nil {
+		return nil, err
+	}
+	pipelineRequests := make(map[string]*pps.CreatePipelineRequest)
+	for _, pipelineRequest := range request.Pipelines {
+		pipelineRequests[pipelineRequest.Pipeline.Name] = pipelineRequest
+	}
+
+	pachClient := a.env.GetPachClient(ctx)
+	response = &pps.CreatePipelinesResponse{}
+	var created []createdPipeline
+	for _, name := range order {
+		pipelineRequest := proto.Clone(pipelineRequests[name]).(*pps.CreatePipelineRequest)
+		if request.Update {
+			pipelineRequest.Update = true
+			pipelineRequest.Reprocess = pipelineRequest.Reprocess || request.Reprocess
+		}
+		var previous *pps.CreatePipelineRequest
+		if pipelineRequest.Update {
+			pipelineInfo, err := a.inspectPipeline(pachClient, name)
+			if err != nil && !isNotFoundErr(err) {
+				return nil, a.rollbackPipelines(pachClient, created, errors.Wrapf(err, "could not inspect pipeline %q", name))
+			}
+			if pipelineInfo != nil {
+				previous = existingPipelineRequest(pipelineInfo)
+			}
+		}
+		if _, err := pachClient.PpsAPIClient.CreatePipeline(pachClient.Ctx(), pipelineRequest); err != nil {
+			return nil, a.rollbackPipelines(pachClient, created, errors.Wrapf(err, "could not create pipeline %q", name))
+		}
+		created = append(created, createdPipeline{name: name, previous: previous})
+		response.Pipelines = append(response.Pipelines, client.NewPipeline(name))
+	}
+	return response, nil
+}
+
+// rollbackPipelines undoes the creation (or update) of 'created', newest
+// first, after CreatePipelines failed with 'cause'. It returns the error that
+// CreatePipelines should return.
+func (a *apiServer) rollbackPipelines(pachClient *client.APIClient, created []createdPipeline, cause error) error {
+	for i := len(created) - 1; i >= 0; i-- {
+		var err error
+		if created[i].previous == nil {
+			_, err = pachClient.PpsAPIClient.DeletePipeline(pachClient.Ctx(), &pps.DeletePipelineRequest{
+				Pipeline: client.NewPipeline(created[i].name),
+				Force:    true,
+			})
+		} else {
+			restore := proto.Clone(created[i].previous).(*pps.CreatePipelineRequest)
+			restore.Update = true
+			_, err = pachClient.PpsAPIClient.CreatePipeline(pachClient.Ctx(), restore)
+		}
+		if err != nil {
+			return errors.Wrapf(cause, "rolling back pipeline %q failed (%v), so it and the %d pipelines created before it were left in place",
+				created[i].name, err, i)
+		}
+	}
+	if len(created) == 0 {
+		return cause
+	}
+	return errors.Wrapf(cause, "the %d pipelines created before the error were rolled back", len(created))
+}
+
+// pipelineCreationOrder returns the names of the pipelines in 'requests' in
+// the order in which they must be created, i.e. each after the pipelines in
+// 'requests' that it reads from.
+func pipelineCreationOrder(requests []*pps.CreatePipelineRequest) ([]string, error) {
+	writers := make(map[string]string) // repo -> pipeline
+	for _, request := range requests {
+		name := request.Pipeline.Name
+		for _, repo := range pipelineRepos(request) {
+			if writer, ok := writers[repo]; ok {
+				if writer == name {
+					return nil, errors.Errorf("pipeline %q is defined more than once", name)
+				}
+				return nil, errors.Errorf("pipelines %q and %q both write to repo %q", writer, name, repo)
+			}
+			writers[repo] = name
+		}
+	}
+	deps := make(map[string][]string)
+	for _, request := range requests {
+		name := request.Pipeline.Name
+		deps[name] = nil
+		pps.VisitInput(request.Input, func(input *pps.Input) {
+			if input.Pfs == nil {
+				return
+			}
+			if writer, ok := writers[input.Pfs.Repo]; ok && writer != name {
+				deps[name] = append(deps[name], writer)
+			}
+		})
+	}
+	return sortPipelines(deps)
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/robfig/cron"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// secondsCronParser parses cron specs that start with a seconds field
+var secondsCronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// parseCronSpec parses a standard (five field) cron spec, a cron spec with a
+// leading seconds field (six fields), or a descriptor such as "@daily" or
+// "@every 30s"
+func parseCronSpec(spec string) (cron.Schedule, error) {
+	if len(strings.Fields(spec)) == 6 {
+		return secondsCronParser.Parse(spec)
+	}
+	return cron.ParseStandard(spec)
+}
+
+// cronSchedule computes the ticks of a cron input
+type cronSchedule struct {
+	schedule  cron.Schedule
+	location  *time.Location
+	jitter    time.Duration
+	dstPolicy pps.CronDSTPolicy
+}
+
+func newCronSchedule(in *pps.CronInput) (*cronSchedule, error) {
+	schedule, err := parseCronSpec(in.Spec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing cron-spec")
+	}
+	location := time.Local
+	if in.Timezone != "" {
+		location, err = time.LoadLocation(in.Timezone)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid timezone %q", in.Timezone)
+		}
+	}
+	var jitter time.Duration
+	if in.Jitter != nil {
+		jitter, err = types.DurationFromProto(in.Jitter)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid jitter")
+		}
+		if jitter < 0 {
+			return nil, errors.Errorf("jitter must not be negative")
+		}
+	}
+	if _, ok := pps.CronDSTPolicy_name[int32(in.DSTPolicy)]; !ok {
+		return nil, errors.Errorf("invalid DST policy %d", in.DSTPolicy)
+	}
+	return &cronSchedule{
+		schedule:  schedule,
+		location:  location,
+		jitter:    jitter,
+		dstPolicy: in.DSTPolicy,
+	}, nil
+}
+
+// next returns the first tick after 't'
+func (s *cronSchedule) next(t time.Time) time.Time {
+	spec, ok := s.schedule.(*cron.SpecSchedule)
+	if !ok {
+		// The other schedules (i.e. "@every") are fixed intervals, which DST
+		// doesn't affect
+		return s.schedule.Next(t)
+	}
+	t = t.In(s.location)
+	for {
+		next := spec.Next(t)
+		if next.IsZero() {
+			return next
+		}
+		if s.dstPolicy == pps.CronDSTPolicy_CRON_DST_DOUBLE_RUN {
+			// Ticks whose time is repeated are already run twice, but ticks
+			// whose time is skipped have to be found separately
+			if skipped, ok := skippedTick(spec, t, next, s.location); ok {
+				return skipped
+			}
+			return next
+		}
+		if everyHour(spec) || !repeatedTime(next) {
+			return next
+		}
+		t = next
+	}
+}
+
+// delay returns how long to wait after a tick before running it
+func (s *cronSchedule) delay() time.Duration {
+	if s.jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(s.jitter)))
+}
+
+// everyHour returns true if 'spec' runs every hour, in which case its ticks
+// are run at every instant that matches it, even when clocks are turned back
+func everyHour(spec *cron.SpecSchedule) bool {
+	const allHours = 1<<24 - 1
+	return spec.Hour&allHours == allHours
+}
+
+// repeatedTime returns true if the wall clock time of 't' also occurred
+// earlier, because clocks were turned back.
+func repeatedTime(t time.Time) bool {
+	_, offset := t.Zone()
+	// Clocks are turned back by (much) less than 12 hours, and not more often
+	// than every 12 hours
+	_, prevOffset := t.Add(-12 * time.Hour).Zone()
+	if prevOffset <= offset {
+		return false
+	}
+	// 'earlier' has the same wall clock time as 't' if it's before the clocks
+	// were turned back
+	earlier := t.Add(-time.Duration(prevOffset-offset) * time.Second)
+	_, earlierOffset := earlier.Zone()
+	return earlierOffset == prevOffset
+}
+
+// skippedTick returns the first tick of 'spec' after 't' and before 'next'
+// whose time was skipped in 'location' because clocks were turned forward,
+// if there is one. The returned tick is the instant the skipped time would
+// have been if clocks hadn't been turned forward.
+func skippedTick(spec *cron.SpecSchedule, t, next time.Time, location *time.Location) (time.Time, bool) {
+	// Find the ticks of 'spec' as if the clocks hadn't been turned forward
+	name, offset := t.Zone()
+	tick := spec.Next(t.In(time.FixedZone(name, offset)))
+	if tick.IsZero() || !tick.Before(next) {
+		return time.Time{}, false
+	}
+	wall := time.Date(tick.Year(), tick.Month(), tick.Day(), tick.Hour(), tick.Minute(), tick.Second(), 0, location)
+	if wall.Hour() == tick.Hour() && wall.Minute() == tick.Minute() && wall.Day() == tick.Day() {
+		return time.Time{}, false // the tick's time wasn't skipped
+	}
+	return tick.In(location), true
+}
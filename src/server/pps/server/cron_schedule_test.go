@@ -0,0 +1,86 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestNewCronSchedule(t *testing.T) {
+	_, err := newCronSchedule(&pps.CronInput{Spec: "*/10 * * * *"})
+	require.NoError(t, err)
+	_, err = newCronSchedule(&pps.CronInput{Spec: "@every 30s"})
+	require.NoError(t, err)
+	// A leading seconds field
+	s, err := newCronSchedule(&pps.CronInput{Spec: "30 0 * * * *", Timezone: "UTC"})
+	require.NoError(t, err)
+	start := time.Date(2020, 6, 1, 12, 10, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2020, 6, 1, 13, 0, 30, 0, time.UTC), s.next(start).UTC())
+
+	_, err = newCronSchedule(&pps.CronInput{Spec: "* * *"})
+	require.YesError(t, err)
+	_, err = newCronSchedule(&pps.CronInput{Spec: "@daily", Timezone: "Mars/Olympus_Mons"})
+	require.YesError(t, err)
+	_, err = newCronSchedule(&pps.CronInput{Spec: "@daily", Jitter: types.DurationProto(-time.Minute)})
+	require.YesError(t, err)
+
+	s, err = newCronSchedule(&pps.CronInput{Spec: "@daily", Jitter: types.DurationProto(time.Minute)})
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		d := s.delay()
+		require.True(t, d >= 0 && d < time.Minute)
+	}
+}
+
+func TestCronScheduleTimezone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	s, err := newCronSchedule(&pps.CronInput{Spec: "@daily", Timezone: "America/New_York"})
+	require.NoError(t, err)
+	// Midnight in New York stays midnight across DST transitions
+	next := s.next(time.Date(2020, 3, 7, 12, 0, 0, 0, newYork))
+	require.Equal(t, time.Date(2020, 3, 8, 5, 0, 0, 0, time.UTC), next.UTC())
+	next = s.next(next)
+	require.Equal(t, time.Date(2020, 3, 9, 4, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestCronScheduleDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	ticks := func(spec string, policy pps.CronDSTPolicy, from time.Time, n int) []time.Time {
+		s, err := newCronSchedule(&pps.CronInput{Spec: spec, Timezone: "America/New_York", DSTPolicy: policy})
+		require.NoError(t, err)
+		var result []time.Time
+		for i := 0; i < n; i++ {
+			from = s.next(from)
+			result = append(result, from.UTC())
+		}
+		return result
+	}
+	utc := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2020, month, day, hour, min, 0, 0, time.UTC)
+	}
+
+	// On March 8th 2020, clocks in New York went from 2 AM EST to 3 AM EDT,
+	// so 2:30 AM didn't happen
+	springForward := time.Date(2020, 3, 8, 0, 0, 0, 0, newYork)
+	require.Equal(t, []time.Time{utc(3, 9, 6, 30)},
+		ticks("30 2 * * *", pps.CronDSTPolicy_CRON_DST_SKIP, springForward, 1))
+	require.Equal(t, []time.Time{utc(3, 8, 7, 30), utc(3, 9, 6, 30)},
+		ticks("30 2 * * *", pps.CronDSTPolicy_CRON_DST_DOUBLE_RUN, springForward, 2))
+
+	// On November 1st 2020, clocks in New York went from 2 AM EDT back to 1 AM
+	// EST, so 1:30 AM happened twice
+	fallBack := time.Date(2020, 11, 1, 0, 0, 0, 0, newYork)
+	require.Equal(t, []time.Time{utc(11, 1, 5, 30), utc(11, 2, 6, 30)},
+		ticks("30 1 * * *", pps.CronDSTPolicy_CRON_DST_SKIP, fallBack, 2))
+	require.Equal(t, []time.Time{utc(11, 1, 5, 30), utc(11, 1, 6, 30), utc(11, 2, 6, 30)},
+		ticks("30 1 * * *", pps.CronDSTPolicy_CRON_DST_DOUBLE_RUN, fallBack, 3))
+	// Hourly specs run every hour regardless
+	require.Equal(t, []time.Time{utc(11, 1, 4, 30), utc(11, 1, 5, 30), utc(11, 1, 6, 30), utc(11, 1, 7, 30)},
+		ticks("30 * * * *", pps.CronDSTPolicy_CRON_DST_SKIP, fallBack, 4))
+}
@@ -0,0 +1,330 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ancestry"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+)
+
+// normalizePipelineRequest returns a copy of 'request' with the defaults that
+// CreatePipeline would apply, and without the fields that don't belong to the
+// pipeline's spec (e.g. 'update'), so that it can be compared with the
+// requests of existing pipelines (see existingPipelineRequest).
+func normalizePipelineRequest(request *pps.CreatePipelineRequest) (*pps.CreatePipelineRequest, error) {
+	pipelineInfo := pipelineInfoFromRequest(proto.Clone(request).(*pps.CreatePipelineRequest))
+	if err := setPipelineDefaults(pipelineInfo); err != nil {
+		return nil, err
+	}
+	pps.SortInput(pipelineInfo.Input)
+	return existingPipelineRequest(pipelineInfo), nil
+}
+
+// existingPipelineRequest returns the CreatePipelineRequest that describes
+// an existing pipeline's spec.
+func existingPipelineRequest(pipelineInfo *pps.PipelineInfo) *pps.CreatePipelineRequest {
+	result := ppsutil.PipelineReqFromInfo(proto.Clone(pipelineInfo).(*pps.PipelineInfo))
+	result.Salt = ""
+	return result
+}
+
+// samePipelineSpec returns true if the existing pipeline spec 'existing' is
+// the same as the desired spec 'desired' (both normalized). Cron inputs whose
+// start time isn't set in 'desired' match any start time, as CreatePipeline
+// sets it to the current time.
+func samePipelineSpec(existing, desired *pps.CreatePipelineRequest) bool {
+	unsetStart := make(map[string]bool)
+	pps.VisitInput(desired.Input, func(input *pps.Input) {
+		if input.Cron != nil && input.Cron.Start == nil {
+			unsetStart[input.Cron.Name] = true
+		}
+	})
+	existing = proto.Clone(existing).(*pps.CreatePipelineRequest)
+	pps.VisitInput(existing.Input, func(input *pps.Input) {
+		if input.Cron != nil && unsetStart[input.Cron.Name] {
+			input.Cron.Start = nil
+		}
+	})
+	return proto.Equal(existing, desired)
+}
+
+// pipelineRepos returns the repos that a pipeline writes to.
+func pipelineRepos(request *pps.CreatePipelineRequest) []string {
+	result := []string{request.Pipeline.Name}
+	for _, output := range request.Outputs {
+		result = append(result, output.Repo)
+	}
+	return result
+}
+
+// sortPipelines returns the pipelines in 'deps' (a map from each pipeline to
+// the pipelines that it reads from) in topological order, i.e. every pipeline
+// comes after the pipelines that it reads from. Ties are broken by name, so
+// that the order is deterministic.
+func sortPipelines(deps map[string][]string) ([]string, error) {
+	var result []string
+	done := make(map[string]bool)
+	for len(result) < len(deps) {
+		var ready []string
+		for pipeline, pipelineDeps := range deps {
+			if done[pipeline] {
+				continue
+			}
+			isReady := true
+			for _, dep := range pipelineDeps {
+				if !done[dep] {
+					isReady = false
+					break
+				}
+			}
+			if isReady {
+				ready = append(ready, pipeline)
+			}
+		}
+		if len(ready) == 0 {
+			var cycle []string
+			for pipeline := range deps {
+				if !done[pipeline] {
+					cycle = append(cycle, pipeline)
+				}
+			}
+			sort.Strings(cycle)
+			return nil, errors.Errorf("pipelines %v form a cycle", cycle)
+		}
+		sort.Strings(ready)
+		for _, pipeline := range ready {
+			done[pipeline] = true
+		}
+		result = append(result, ready...)
+	}
+	return result, nil
+}
+
+// planDAG returns the changes that make the cluster's repos and pipelines
+// match 'request', given its existing pipelines and repos. Repos are created or
+// updated first, then pipelines are created or updated (each after the
+// pipelines it reads from) and finally, if request.Prune is set, pipelines
+// that aren't in 'request' are deleted (each before the pipelines it reads
+// from).
+func planDAG(request *pps.ApplyDAGRequest, existingPipelines map[string]*pps.PipelineInfo, existingRepos map[string]*pfs.RepoInfo) ([]*pps.DAGChange, error) {
+	// Normalize the desired pipelines, and find the repos that they write to
+	desired := make(map[string]*pps.CreatePipelineRequest)
+	writers := make(map[string]string) // repo -> desired pipeline
+	for _, pipelineRequest := range request.Pipelines {
+		name := pipelineRequest.Pipeline.Name
+		if desired[name] != nil {
+			return nil, errors.Errorf("pipeline %q is defined more than once", name)
+		}
+		normalized, err := normalizePipelineRequest(pipelineRequest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid spec for pipeline %q", name)
+		}
+		desired[name] = normalized
+		for _, repo := range pipelineRepos(normalized) {
+			if writer, ok := writers[repo]; ok {
+				return nil, errors.Errorf("pipelines %q and %q both write to repo %q", writer, name, repo)
+			}
+			writers[repo] = name
+		}
+	}
+
+	// Plan repo changes
+	var changes []*pps.DAGChange
+	declared := make(map[string]bool)
+	for _, repoRequest := range request.Repos {
+		if repoRequest.Repo == nil {
+			return nil, errors.New("repo cannot be nil")
+		}
+		name := repoRequest.Repo.Name
+		if err := ancestry.ValidateName(name); err != nil {
+			return nil, errors.Wrapf(err, "invalid repo name")
+		}
+		if declared[name] {
+			return nil, errors.Errorf("repo %q is defined more than once", name)
+		}
+		declared[name] = true
+		if writer, ok := writers[name]; ok {
+			return nil, errors.Errorf("repo %q is an output of pipeline %q, so it cannot be defined as a repo", name, writer)
+		}
+		repoInfo, ok := existingRepos[name]
+		switch {
+		case !ok:
+			changes = append(changes, &pps.DAGChange{Action: pps.DAGAction_DAG_CREATE, Repo: client.NewRepo(name)})
+		case repoInfo.Description != repoRequest.Description:
+			changes = append(changes, &pps.DAGChange{Action: pps.DAGAction_DAG_UPDATE, Repo: client.NewRepo(name)})
+		}
+	}
+
+	// Find the pipelines that will be deleted, and the repos that go with them
+	var pruned []string
+	prunedRepos := make(map[string]bool)
+	if request.Prune {
+		for name, pipelineInfo := range existingPipelines {
+			if desired[name] != nil {
+				continue
+			}
+			pruned = append(pruned, name)
+			for _, repo := range pipelineRepos(existingPipelineRequest(pipelineInfo)) {
+				prunedRepos[repo] = true
+			}
+		}
+	}
+
+	// Check that every input exists, and order the desired pipelines
+	deps := make(map[string][]string)
+	for name, pipelineRequest := range desired {
+		deps[name] = nil
+		var missing []string
+		pps.VisitInput(pipelineRequest.Input, func(input *pps.Input) {
+			if input.Pfs == nil {
+				return // other inputs' repos are created by CreatePipeline
+			}
+			repo := input.Pfs.Repo
+			if writer, ok := writers[repo]; ok {
+				if writer != name {
+					deps[name] = append(deps[name], writer)
+				}
+				return
+			}
+			if declared[repo] {
+				return
+			}
+			if _, ok := existingRepos[repo]; !ok || prunedRepos[repo] {
+				missing = append(missing, repo)
+			}
+		})
+		if len(missing) > 0 {
+			return nil, errors.Errorf("pipeline %q reads from %v, which would not exist", name, missing)
+		}
+	}
+	order, err := sortPipelines(deps)
+	if err != nil {
+		return nil, err
+	}
+
+	// Plan pipeline creations and updates
+	for _, name := range order {
+		existing, ok := existingPipelines[name]
+		if !ok {
+			changes = append(changes, &pps.DAGChange{Action: pps.DAGAction_DAG_CREATE, Pipeline: client.NewPipeline(name)})
+			continue
+		}
+		existingRequest := existingPipelineRequest(existing)
+		if samePipelineSpec(existingRequest, desired[name]) {
+			continue
+		}
+		var reprocess bool
+		switch request.ReprocessPolicy {
+		case pps.ReprocessPolicy_REPROCESS_ALWAYS:
+			reprocess = true
+		case pps.ReprocessPolicy_REPROCESS_ON_TRANSFORM_CHANGE:
+			reprocess = !proto.Equal(existingRequest.Transform, desired[name].Transform)
+		}
+		for _, pipelineRequest := range request.Pipelines {
+			if pipelineRequest.Pipeline.Name == name && pipelineRequest.Reprocess {
+				reprocess = true
+			}
+		}
+		changes = append(changes, &pps.DAGChange{
+			Action:    pps.DAGAction_DAG_UPDATE,
+			Pipeline:  client.NewPipeline(name),
+			Reprocess: reprocess,
+		})
+	}
+
+	// Plan pipeline deletions, downstream pipelines first
+	prunedDeps := make(map[string][]string)
+	for _, name := range pruned {
+		prunedDeps[name] = nil
+	}
+	for _, name := range pruned {
+		for _, branch := range branchProvenance(existingPipelines[name].Input) {
+			if _, ok := prunedDeps[branch.Repo.Name]; ok && branch.Repo.Name != name {
+				prunedDeps[name] = append(prunedDeps[name], branch.Repo.Name)
+			}
+		}
+	}
+	deleteOrder, err := sortPipelines(prunedDeps)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(deleteOrder) - 1; i >= 0; i-- {
+		changes = append(changes, &pps.DAGChange{Action: pps.DAGAction_DAG_DELETE, Pipeline: client.NewPipeline(deleteOrder[i])})
+	}
+	return changes, nil
+}
+
+// applyDAG applies the changes planned by planDAG. Repo changes are applied
+// in a single transaction. CreatePipeline and DeletePipeline can't be run in a
+// transaction, so if a pipeline change fails, the error says which changes
+// were applied.
+func (a *apiServer) applyDAG(pachClient *client.APIClient, request *pps.ApplyDAGRequest, changes []*pps.DAGChange) error {
+	repoRequests := make(map[string]*pfs.CreateRepoRequest)
+	for _, repoRequest := range request.Repos {
+		repoRequests[repoRequest.Repo.Name] = repoRequest
+	}
+	pipelineRequests := make(map[string]*pps.CreatePipelineRequest)
+	for _, pipelineRequest := range request.Pipelines {
+		pipelineRequests[pipelineRequest.Pipeline.Name] = pipelineRequest
+	}
+	var applied int
+	if _, err := pachClient.ExecuteInTransaction(func(txnClient *client.APIClient) error {
+		for _, change := range changes {
+			if change.Repo == nil {
+				continue
+			}
+			if _, err := txnClient.PfsAPIClient.CreateRepo(txnClient.Ctx(), &pfs.CreateRepoRequest{
+				Repo:        change.Repo,
+				Description: repoRequests[change.Repo.Name].Description,
+				Update:      change.Action == pps.DAGAction_DAG_UPDATE,
+			}); err != nil {
+				return errors.Wrapf(err, "could not %s repo %q", dagActionVerb(change.Action), change.Repo.Name)
+			}
+			applied++
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, change := range changes {
+		if change.Pipeline == nil {
+			continue
+		}
+		var err error
+		if change.Action == pps.DAGAction_DAG_DELETE {
+			_, err = pachClient.PpsAPIClient.DeletePipeline(pachClient.Ctx(), &pps.DeletePipelineRequest{
+				Pipeline: change.Pipeline,
+			})
+		} else {
+			pipelineRequest := proto.Clone(pipelineRequests[change.Pipeline.Name]).(*pps.CreatePipelineRequest)
+			pipelineRequest.Update = change.Action == pps.DAGAction_DAG_UPDATE
+			pipelineRequest.Reprocess = change.Reprocess
+			_, err = pachClient.PpsAPIClient.CreatePipeline(pachClient.Ctx(), pipelineRequest)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "could not %s pipeline %q (%d of %d changes were applied)",
+				dagActionVerb(change.Action), change.Pipeline.Name, applied, len(changes))
+		}
+		applied++
+	}
+	return nil
+}
+
+func dagActionVerb(action pps.DAGAction) string {
+	switch action {
+	case pps.DAGAction_DAG_CREATE:
+		return "create"
+	case pps.DAGAction_DAG_UPDATE:
+		return "update"
+	case pps.DAGAction_DAG_DELETE:
+		return "delete"
+	default:
+		panic(fmt.Sprintf("unrecognized DAG action: %s", action))
+	}
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func dagPipeline(name string, cmd string, inputs ...string) *pps.CreatePipelineRequest {
+	var input *pps.Input
+	for _, repo := range inputs {
+		if input == nil {
+			input = client.NewPFSInput(repo, "/*")
+		} else {
+			input = client.NewCrossInput(input, client.NewPFSInput(repo, "/*"))
+		}
+	}
+	return &pps.CreatePipelineRequest{
+		Pipeline:  client.NewPipeline(name),
+		Transform: &pps.Transform{Cmd: []string{cmd}},
+		Input:     input,
+	}
+}
+
+// dagCluster returns the existing pipelines and repos of a cluster on which
+// 'pipelines' and 'repos' were created.
+func dagCluster(t *testing.T, pipelines []*pps.CreatePipelineRequest, repos ...string) (map[string]*pps.PipelineInfo, map[string]*pfs.RepoInfo) {
+	pipelineInfos := make(map[string]*pps.PipelineInfo)
+	repoInfos := make(map[string]*pfs.RepoInfo)
+	for _, repo := range repos {
+		repoInfos[repo] = &pfs.RepoInfo{Repo: client.NewRepo(repo)}
+	}
+	for _, request := range pipelines {
+		pipelineInfo := pipelineInfoFromRequest(request)
+		require.NoError(t, setPipelineDefaults(pipelineInfo))
+		pps.SortInput(pipelineInfo.Input)
+		pipelineInfo.Salt = "salt"
+		pipelineInfos[request.Pipeline.Name] = pipelineInfo
+		repoInfos[request.Pipeline.Name] = &pfs.RepoInfo{Repo: client.NewRepo(request.Pipeline.Name)}
+	}
+	return pipelineInfos, repoInfos
+}
+
+func dagChangeNames(changes []*pps.DAGChange) []string {
+	var result []string
+	for _, change := range changes {
+		name := "pipeline " + change.Pipeline.GetName()
+		if change.Repo != nil {
+			name = "repo " + change.Repo.Name
+		}
+		result = append(result, dagActionVerb(change.Action)+" "+name)
+	}
+	return result
+}
+
+func TestPlanDAG(t *testing.T) {
+	request := &pps.ApplyDAGRequest{
+		Repos: []*pfs.CreateRepoRequest{{Repo: client.NewRepo("images")}},
+		Pipelines: []*pps.CreatePipelineRequest{
+			dagPipeline("montage", "montage", "images", "edges"),
+			dagPipeline("edges", "edges", "images"),
+		},
+	}
+
+	// Empty cluster: everything is created, upstream first
+	pipelines, repos := dagCluster(t, nil)
+	changes, err := planDAG(request, pipelines, repos)
+	require.NoError(t, err)
+	require.Equal(t, []string{"create repo images", "create pipeline edges", "create pipeline montage"}, dagChangeNames(changes))
+
+	// The DAG already exists: nothing changes
+	pipelines, repos = dagCluster(t, []*pps.CreatePipelineRequest{
+		dagPipeline("edges", "edges", "images"),
+		dagPipeline("montage", "montage", "images", "edges"),
+	}, "images")
+	changes, err = planDAG(request, pipelines, repos)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(changes))
+
+	// Changed transform, with and without a reprocess policy
+	changed := &pps.ApplyDAGRequest{
+		Pipelines: []*pps.CreatePipelineRequest{
+			dagPipeline("edges", "edges2", "images"),
+			dagPipeline("montage", "montage", "images", "edges"),
+		},
+	}
+	changes, err = planDAG(changed, pipelines, repos)
+	require.NoError(t, err)
+	require.Equal(t, []string{"update pipeline edges"}, dagChangeNames(changes))
+	require.False(t, changes[0].Reprocess)
+	changed.ReprocessPolicy = pps.ReprocessPolicy_REPROCESS_ON_TRANSFORM_CHANGE
+	changes, err = planDAG(changed, pipelines, repos)
+	require.NoError(t, err)
+	require.True(t, changes[0].Reprocess)
+
+	// Prune deletes pipelines that aren't in the request...
+	pruned := &pps.ApplyDAGRequest{
+		Pipelines: []*pps.CreatePipelineRequest{dagPipeline("edges", "edges", "images")},
+		Prune:     true,
+	}
+	changes, err = planDAG(pruned, pipelines, repos)
+	require.NoError(t, err)
+	require.Equal(t, []string{"delete pipeline montage"}, dagChangeNames(changes))
+	// ...but not if a remaining pipeline reads from them
+	pruned.Pipelines = []*pps.CreatePipelineRequest{dagPipeline("montage", "montage", "images", "edges")}
+	_, err = planDAG(pruned, pipelines, repos)
+	require.YesError(t, err)
+}
+
+func TestPlanDAGErrors(t *testing.T) {
+	pipelines, repos := dagCluster(t, nil, "images")
+	// cycle
+	_, err := planDAG(&pps.ApplyDAGRequest{
+		Pipelines: []*pps.CreatePipelineRequest{
+			dagPipeline("a", "a", "images", "b"),
+			dagPipeline("b", "b", "a"),
+		},
+	}, pipelines, repos)
+	require.YesError(t, err)
+	// missing input
+	_, err = planDAG(&pps.ApplyDAGRequest{
+		Pipelines: []*pps.CreatePipelineRequest{dagPipeline("a", "a", "videos")},
+	}, pipelines, repos)
+	require.YesError(t, err)
+	// duplicate pipeline
+	_, err = planDAG(&pps.ApplyDAGRequest{
+		Pipelines: []*pps.CreatePipelineRequest{
+			dagPipeline("a", "a", "images"),
+			dagPipeline("a", "b", "images"),
+		},
+	}, pipelines, repos)
+	require.YesError(t, err)
+	// repo that is also a pipeline's output
+	_, err = planDAG(&pps.ApplyDAGRequest{
+		Repos:     []*pfs.CreateRepoRequest{{Repo: client.NewRepo("a")}},
+		Pipelines: []*pps.CreatePipelineRequest{dagPipeline("a", "a", "images")},
+	}, pipelines, repos)
+	require.YesError(t, err)
+}
+
+func TestPipelineCreationOrder(t *testing.T) {
+	order, err := pipelineCreationOrder([]*pps.CreatePipelineRequest{
+		dagPipeline("montage", "montage", "edges", "images"),
+		dagPipeline("edges", "edges", "images"),
+		dagPipeline("thumbnails", "thumbnails", "images"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"edges", "thumbnails", "montage"}, order)
+	// cycle
+	_, err = pipelineCreationOrder([]*pps.CreatePipelineRequest{
+		dagPipeline("a", "a", "b"),
+		dagPipeline("b", "b", "a"),
+	})
+	require.YesError(t, err)
+	// duplicate pipeline
+	_, err = pipelineCreationOrder([]*pps.CreatePipelineRequest{
+		dagPipeline("a", "a", "images"),
+		dagPipeline("a", "b", "images"),
+	})
+	require.YesError(t, err)
+}
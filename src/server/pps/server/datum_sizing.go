@@ -0,0 +1,194 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// workerClassLabel is the label that identifies the workers (and RC) of a
+// pipeline's worker class (see pps.DatumSizing). The pipeline's own workers
+// don't have it.
+const workerClassLabel = "workerClass"
+
+// validateDatumSizing returns an error if the DatumSizing spec of
+// 'pipelineInfo' is invalid.
+func validateDatumSizing(pipelineInfo *pps.PipelineInfo) error {
+	sizing := pipelineInfo.DatumSizing
+	if sizing == nil {
+		return nil
+	}
+	if len(sizing.Cmd) == 0 {
+		return errors.New("invalid pipeline spec: datum_sizing must set cmd")
+	}
+	if len(sizing.WorkerClasses) == 0 {
+		return errors.New("invalid pipeline spec: datum_sizing must have at least one worker class")
+	}
+	if pipelineInfo.Service != nil || pipelineInfo.Spout != nil {
+		return errors.New("invalid pipeline spec: services and spouts don't process datums, so they can't use datum_sizing")
+	}
+	if pipelineInfo.SchedulingSpec.GetGangScheduling() != nil {
+		return errors.New("invalid pipeline spec: datum_sizing can't be used with gang scheduling")
+	}
+	names := make(map[string]bool)
+	for _, class := range sizing.WorkerClasses {
+		if errs := validation.IsDNS1123Label(class.Name); len(errs) > 0 {
+			return errors.Errorf("invalid pipeline spec: invalid worker class name %q: %s", class.Name, strings.Join(errs, "; "))
+		}
+		if names[class.Name] {
+			return errors.Errorf("invalid pipeline spec: there are multiple worker classes named %q", class.Name)
+		}
+		names[class.Name] = true
+	}
+	return nil
+}
+
+// workerClassRcName returns the name of the RC of the worker class 'class' of
+// the pipeline whose RC is 'rcName'.
+func workerClassRcName(rcName, class string) string {
+	return rcName + "-" + class
+}
+
+// workerClassParallelism returns the number of workers in 'class'.
+func workerClassParallelism(class *pps.WorkerClass) int32 {
+	if class.Parallelism == 0 {
+		return 1
+	}
+	return int32(class.Parallelism)
+}
+
+// workerClassOptions returns the options of the workers of 'class', which are
+// the options of the pipeline's own workers ('options') with the class's
+// resources and node selector.
+func workerClassOptions(options *workerOptions, class *pps.WorkerClass) (*workerOptions, error) {
+	classOptions := *options
+	classOptions.rcName = workerClassRcName(options.rcName, class.Name)
+	// The class's workers have a selector of their own, so that they aren't
+	// selected by the RC of the pipeline's own workers
+	classOptions.selector = labels(classOptions.rcName)
+	classOptions.selector[pipelineNameLabel] = options.selector[pipelineNameLabel]
+	classOptions.selector[workerClassLabel] = class.Name
+	classOptions.labels = mergeMetadata(classOptions.selector, options.labels)
+	// Copy the slices that workerPodSpec appends to, so that the pod specs of
+	// the pipeline's workers and the class's workers don't share them
+	classOptions.volumes = append([]v1.Volume(nil), options.volumes...)
+	classOptions.volumeMounts = append([]v1.VolumeMount(nil), options.volumeMounts...)
+	classOptions.workerEnv = append(append([]v1.EnvVar(nil), options.workerEnv...), v1.EnvVar{
+		Name:  client.PPSWorkerClassEnv,
+		Value: class.Name,
+	})
+	if class.ResourceRequests != nil {
+		requests, err := ppsutil.GetRequestsResourceList(class.ResourceRequests)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not determine resource request of worker class %q", class.Name)
+		}
+		classOptions.resourceRequests = requests
+	}
+	if class.ResourceLimits != nil {
+		limits, err := ppsutil.GetLimitsResourceList(class.ResourceLimits)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not determine resource limit of worker class %q", class.Name)
+		}
+		classOptions.resourceLimits = limits
+	}
+	if len(class.NodeSelector) > 0 {
+		schedulingSpec := &pps.SchedulingSpec{}
+		if options.schedulingSpec != nil {
+			*schedulingSpec = *options.schedulingSpec
+		}
+		schedulingSpec.NodeSelector = mergeMetadata(class.NodeSelector, schedulingSpec.NodeSelector)
+		classOptions.schedulingSpec = schedulingSpec
+	}
+	return &classOptions, nil
+}
+
+// createWorkerClassRCs creates the RCs of a pipeline's worker classes, whose
+// workers have 'classOptions' (see workerClassOptions). Like the pipeline's
+// own RC, they start with no workers.
+func (a *apiServer) createWorkerClassRCs(classOptions []*workerOptions) error {
+	for _, options := range classOptions {
+		podSpec, err := a.workerPodSpec(options)
+		if err != nil {
+			return err
+		}
+		rc := &v1.ReplicationController{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ReplicationController",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        options.rcName,
+				Labels:      options.labels,
+				Annotations: options.annotations,
+			},
+			Spec: v1.ReplicationControllerSpec{
+				Selector: options.selector,
+				Replicas: &options.parallelism,
+				Template: &v1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        options.rcName,
+						Labels:      options.labels,
+						Annotations: options.annotations,
+					},
+					Spec: podSpec,
+				},
+			},
+		}
+		if _, err := a.env.GetKubeClient().CoreV1().ReplicationControllers(a.namespace).Create(rc); err != nil {
+			if !isAlreadyExistsErr(err) {
+				return errors.Wrapf(err, "could not create the RC of worker class %q", options.selector[workerClassLabel])
+			}
+		}
+	}
+	return nil
+}
+
+// scaleWorkerClasses scales the RCs of the worker classes of op's pipeline
+// (see pps.DatumSizing) up to each class's parallelism, or down to 0.
+//
+// Like other functions in pipeline_controller.go, it takes responsibility for
+// restarting op's pipeline if it can't scale them.
+func (op *pipelineOp) scaleWorkerClasses(up bool) error {
+	rcs := op.apiServer.env.GetKubeClient().CoreV1().ReplicationControllers(op.apiServer.namespace)
+	rcName := ppsutil.PipelineRcName(op.name, op.pipelineInfo.Version)
+	for _, class := range op.pipelineInfo.GetDatumSizing().GetWorkerClasses() {
+		var replicas int32
+		if up {
+			replicas = workerClassParallelism(class)
+		}
+		var errCount int
+		if err := backoff.RetryNotify(func() error {
+			rc, err := rcs.Get(workerClassRcName(rcName, class.Name), metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			if rc.Spec.Replicas != nil && *rc.Spec.Replicas == replicas {
+				return nil
+			}
+			rc.Spec.Replicas = &replicas
+			_, err = rcs.Update(rc)
+			return err
+		}, backoff.NewInfiniteBackOff(), func(err error, d time.Duration) error {
+			if errCount++; errCount >= maxErrCount {
+				// Restarting the pipeline creates the RC if it's missing
+				return op.restartPipeline(fmt.Sprintf("could not scale worker class %q: %v", class.Name, err))
+			}
+			log.Errorf("PPS master: error scaling worker class %q of %q: %v; retrying in %v", class.Name, op.name, err, d)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
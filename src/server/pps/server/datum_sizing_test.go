@@ -0,0 +1,81 @@
+package server
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateDatumSizing(t *testing.T) {
+	sizing := func() *pps.DatumSizing {
+		return &pps.DatumSizing{
+			Cmd:           []string{"python3", "size.py"},
+			WorkerClasses: []*pps.WorkerClass{{Name: "medium"}, {Name: "large"}},
+		}
+	}
+	require.NoError(t, validateDatumSizing(&pps.PipelineInfo{}))
+	require.NoError(t, validateDatumSizing(&pps.PipelineInfo{DatumSizing: sizing()}))
+
+	s := sizing()
+	s.Cmd = nil
+	require.YesError(t, validateDatumSizing(&pps.PipelineInfo{DatumSizing: s}))
+	s = sizing()
+	s.WorkerClasses = nil
+	require.YesError(t, validateDatumSizing(&pps.PipelineInfo{DatumSizing: s}))
+	s = sizing()
+	s.WorkerClasses[1].Name = "medium"
+	require.YesError(t, validateDatumSizing(&pps.PipelineInfo{DatumSizing: s}))
+	s = sizing()
+	s.WorkerClasses[1].Name = "Large_Workers"
+	require.YesError(t, validateDatumSizing(&pps.PipelineInfo{DatumSizing: s}))
+	require.YesError(t, validateDatumSizing(&pps.PipelineInfo{DatumSizing: sizing(), Service: &pps.Service{}}))
+	require.YesError(t, validateDatumSizing(&pps.PipelineInfo{
+		DatumSizing:    sizing(),
+		SchedulingSpec: &pps.SchedulingSpec{GangScheduling: &pps.GangScheduling{Scheduler: "volcano"}},
+	}))
+}
+
+func TestWorkerClassOptions(t *testing.T) {
+	selector := labels("pipeline-edges-v1")
+	selector[pipelineNameLabel] = "edges"
+	limits := v1.ResourceList{v1.ResourceMemory: resource.MustParse("1G")}
+	options := &workerOptions{
+		rcName:         "pipeline-edges-v1",
+		selector:       selector,
+		labels:         mergeMetadata(selector, map[string]string{"team": "vision"}),
+		workerEnv:      make([]v1.EnvVar, 1, 10),
+		volumes:        make([]v1.Volume, 1, 10),
+		resourceLimits: &limits,
+		schedulingSpec: &pps.SchedulingSpec{PriorityClassName: "high"},
+	}
+
+	classOptions, err := workerClassOptions(options, &pps.WorkerClass{
+		Name:           "large",
+		ResourceLimits: &pps.ResourceSpec{Memory: "32G"},
+		NodeSelector:   map[string]string{"pool": "highmem"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "pipeline-edges-v1-large", classOptions.rcName)
+	require.Equal(t, "pipeline-edges-v1-large", classOptions.selector["app"])
+	require.Equal(t, "edges", classOptions.selector[pipelineNameLabel])
+	require.Equal(t, "large", classOptions.selector[workerClassLabel])
+	require.Equal(t, "large", classOptions.labels[workerClassLabel])
+	require.Equal(t, "vision", classOptions.labels["team"])
+	require.Equal(t, v1.EnvVar{Name: client.PPSWorkerClassEnv, Value: "large"}, classOptions.workerEnv[1])
+	require.Equal(t, resource.MustParse("32G"), (*classOptions.resourceLimits)[v1.ResourceMemory])
+	require.Equal(t, map[string]string{"pool": "highmem"}, classOptions.schedulingSpec.NodeSelector)
+	require.Equal(t, "high", classOptions.schedulingSpec.PriorityClassName)
+
+	// The pipeline's own workers' options aren't modified
+	require.Equal(t, "pipeline-edges-v1", options.selector["app"])
+	require.Equal(t, 1, len(options.workerEnv))
+	require.Equal(t, resource.MustParse("1G"), (*options.resourceLimits)[v1.ResourceMemory])
+	require.Equal(t, 0, len(options.schedulingSpec.NodeSelector))
+	classOptions.volumes = append(classOptions.volumes, v1.Volume{Name: "pach-disk"})
+	require.Equal(t, "", options.volumes[:2][1].Name)
+}
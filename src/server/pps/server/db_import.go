@@ -0,0 +1,364 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	// The database drivers that db_import inputs can use
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube "k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultDBImportPartitionRows = 100000
+	// dbImportSchemaFile is the file, in each table's directory, that
+	// describes the table's columns
+	dbImportSchemaFile = "schema.json"
+	// dbImportOffsetFormat is the format that time offsets are stored in,
+	// which both Postgres and MySQL can compare with their time types
+	dbImportOffsetFormat = "2006-01-02 15:04:05.999999999"
+)
+
+// dbImportIdentifier matches the table and column names that db_import inputs
+// accept. They're quoted when they're used in queries, but they're also
+// restricted to plain identifiers, as they can't be passed as parameters.
+var dbImportIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// validateDBImportInput checks that a db_import input's database and tables
+// are well-formed.
+func validateDBImportInput(input *pps.DBImportInput) error {
+	switch input.Driver {
+	case "postgres", "mysql":
+	default:
+		return errors.Errorf("db_import input %q's driver must be \"postgres\" or \"mysql\", not %q", input.Name, input.Driver)
+	}
+	if input.DSNSecret == "" {
+		return errors.Errorf("db_import input %q must specify a dsn_secret", input.Name)
+	}
+	if input.PartitionRows < 0 {
+		return errors.Errorf("db_import input %q cannot have a negative partition_rows", input.Name)
+	}
+	if len(input.Tables) == 0 {
+		return errors.Errorf("db_import input %q must specify at least one table", input.Name)
+	}
+	tables := make(map[string]bool)
+	for _, table := range input.Tables {
+		parts := strings.Split(table.Name, ".")
+		if len(parts) > 2 {
+			return errors.Errorf("invalid db_import table %q: only the schema may qualify a table's name", table.Name)
+		}
+		for _, part := range parts {
+			if !dbImportIdentifier.MatchString(part) {
+				return errors.Errorf("invalid db_import table %q: names may only contain letters, digits, '_' and '$'", table.Name)
+			}
+		}
+		if tables[table.Name] {
+			return errors.Errorf("table %q was used more than once in db_import input %q", table.Name, input.Name)
+		}
+		tables[table.Name] = true
+		columns := make(map[string]bool)
+		for _, column := range table.Columns {
+			if !dbImportIdentifier.MatchString(column) {
+				return errors.Errorf("invalid column %q of db_import table %q: names may only contain letters, digits, '_' and '$'", column, table.Name)
+			}
+			columns[column] = true
+		}
+		if table.OffsetColumn == "" {
+			if input.Mode == pps.DBImportMode_DB_IMPORT_CDC {
+				return errors.Errorf("db_import table %q must specify an offset_column to be imported with DB_IMPORT_CDC", table.Name)
+			}
+			continue
+		}
+		if !dbImportIdentifier.MatchString(table.OffsetColumn) {
+			return errors.Errorf("invalid offset_column %q of db_import table %q: names may only contain letters, digits, '_' and '$'", table.OffsetColumn, table.Name)
+		}
+		if len(table.Columns) > 0 && !columns[table.OffsetColumn] {
+			return errors.Errorf("db_import table %q's offset_column %q must be one of its columns", table.Name, table.OffsetColumn)
+		}
+	}
+	return nil
+}
+
+// dbImportState is stored (as JSON) in the description of each commit made by
+// a db_import input, so that imports resume from the right offsets after pachd
+// restarts.
+type dbImportState struct {
+	// Cycle is the number of import cycles that have been committed
+	Cycle int64 `json:"cycle"`
+	// Offsets maps each table imported with DB_IMPORT_CDC to the greatest
+	// value of its offset_column that has been imported
+	Offsets map[string]string `json:"offsets,omitempty"`
+}
+
+// readDBImportState extracts the dbImportState from the description of the
+// most recent commit to a db_import input's repo. An empty state is returned
+// if 'commitInfo' is nil or its description can't be parsed, in which case
+// every table is imported from the start.
+func readDBImportState(commitInfo *pfs.CommitInfo) *dbImportState {
+	state := &dbImportState{Offsets: make(map[string]string)}
+	if commitInfo == nil || commitInfo.Description == "" {
+		return state
+	}
+	if err := json.Unmarshal([]byte(commitInfo.Description), state); err != nil {
+		return &dbImportState{Offsets: make(map[string]string)}
+	}
+	if state.Offsets == nil {
+		state.Offsets = make(map[string]string)
+	}
+	return state
+}
+
+func (s *dbImportState) String() string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		// can't happen, dbImportState only contains strings and ints
+		panic(err)
+	}
+	return string(data)
+}
+
+// next returns the state after another cycle, which starts with this cycle's
+// offsets.
+func (s *dbImportState) next() *dbImportState {
+	next := &dbImportState{Cycle: s.Cycle + 1, Offsets: make(map[string]string)}
+	for table, offset := range s.Offsets {
+		next.Offsets[table] = offset
+	}
+	return next
+}
+
+// openDBImport connects to a db_import input's database, using the data
+// source name in the input's dsn secret.
+func openDBImport(kubeClient kube.Interface, namespace string, input *pps.DBImportInput) (*sql.DB, error) {
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(input.DSNSecret, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get db_import dsn secret %q", input.DSNSecret)
+	}
+	dsn, ok := secret.Data["dsn"]
+	if !ok {
+		return nil, errors.Errorf("db_import dsn secret %q has no \"dsn\" key", input.DSNSecret)
+	}
+	db, err := sql.Open(input.Driver, strings.TrimSpace(string(dsn)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not open %s database for db_import input %q", input.Driver, input.Name)
+	}
+	return db, nil
+}
+
+// quoteDBIdentifier quotes a (possibly schema-qualified) table or column name
+// for 'driver'.
+func quoteDBIdentifier(driver, name string) string {
+	quote := `"`
+	if driver == "mysql" {
+		quote = "`"
+	}
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = quote + part + quote
+	}
+	return strings.Join(parts, ".")
+}
+
+// dbImportQuery returns the query that reads 'table'. If 'afterOffset' is
+// set, the query only reads the rows whose offset_column is greater than its
+// only parameter.
+func dbImportQuery(driver string, table *pps.DBImportTable, afterOffset bool) string {
+	columns := "*"
+	if len(table.Columns) > 0 {
+		quoted := make([]string, len(table.Columns))
+		for i, column := range table.Columns {
+			quoted[i] = quoteDBIdentifier(driver, column)
+		}
+		columns = strings.Join(quoted, ", ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", columns, quoteDBIdentifier(driver, table.Name))
+	if table.OffsetColumn == "" {
+		return query
+	}
+	offsetColumn := quoteDBIdentifier(driver, table.OffsetColumn)
+	if afterOffset {
+		placeholder := "$1"
+		if driver == "mysql" {
+			placeholder = "?"
+		}
+		query += fmt.Sprintf(" WHERE %s > %s", offsetColumn, placeholder)
+	}
+	return query + " ORDER BY " + offsetColumn
+}
+
+// dbImportValue converts a value scanned from a database into the value that's
+// written to the table's files.
+func dbImportValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	}
+	return v
+}
+
+// dbImportOffset converts a value of an offset_column into the string that's
+// stored in a dbImportState, and passed to the next cycle's query.
+func dbImportOffset(v interface{}) string {
+	switch v := v.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(dbImportOffsetFormat)
+	}
+	return fmt.Sprint(v)
+}
+
+// dbImportColumn is the description of a column in a table's schema file
+type dbImportColumn struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable *bool  `json:"nullable,omitempty"`
+}
+
+// importDBTables imports every table of a db_import input into the open
+// commit 'commit', reading the tables in a single read-only transaction so
+// that the commit holds a consistent view of the database. 'state' is the
+// state of the cycle that's being imported, and its offsets are updated with
+// the rows that are read. It returns the number of rows that were imported.
+func importDBTables(ctx context.Context, db *sql.DB, pachClient *client.APIClient, input *pps.DBImportInput, commit string, state *dbImportState) (_ int64, retErr error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return 0, errors.Wrapf(err, "could not start db_import transaction")
+	}
+	defer func() {
+		// The transaction only reads, so there's nothing to commit
+		if err := tx.Rollback(); err != nil && retErr == nil {
+			retErr = errors.EnsureStack(err)
+		}
+	}()
+	var total int64
+	for _, table := range input.Tables {
+		n, err := importDBTable(ctx, tx, pachClient, input, commit, table, state)
+		if err != nil {
+			return 0, errors.Wrapf(err, "could not import table %q", table.Name)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+func importDBTable(ctx context.Context, tx *sql.Tx, pachClient *client.APIClient, input *pps.DBImportInput, commit string, table *pps.DBImportTable, state *dbImportState) (_ int64, retErr error) {
+	cdc := input.Mode == pps.DBImportMode_DB_IMPORT_CDC
+	offset, afterOffset := state.Offsets[table.Name]
+	afterOffset = afterOffset && cdc
+	var args []interface{}
+	if afterOffset {
+		args = append(args, offset)
+	}
+	rows, err := tx.QueryContext(ctx, dbImportQuery(input.Driver, table, afterOffset), args...)
+	if err != nil {
+		return 0, errors.EnsureStack(err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil && retErr == nil {
+			retErr = errors.EnsureStack(err)
+		}
+	}()
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, errors.EnsureStack(err)
+	}
+
+	dir := "/" + table.Name
+	if !cdc {
+		// Snapshots replace the files written by earlier cycles
+		if err := pachClient.DeleteFile(input.Repo, commit, dir); err != nil {
+			return 0, err
+		}
+	}
+	schema := make([]dbImportColumn, len(columnTypes))
+	offsetIndex := -1
+	for i, ct := range columnTypes {
+		schema[i] = dbImportColumn{Name: ct.Name(), Type: ct.DatabaseTypeName()}
+		if nullable, ok := ct.Nullable(); ok {
+			schema[i].Nullable = &nullable
+		}
+		if ct.Name() == table.OffsetColumn {
+			offsetIndex = i
+		}
+	}
+	schemaJSON, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return 0, errors.EnsureStack(err)
+	}
+	if _, err := pachClient.PutFileOverwrite(input.Repo, commit, path.Join(dir, dbImportSchemaFile), bytes.NewReader(schemaJSON), 0); err != nil {
+		return 0, err
+	}
+
+	partitionRows := input.PartitionRows
+	if partitionRows == 0 {
+		partitionRows = defaultDBImportPartitionRows
+	}
+	var buf bytes.Buffer
+	var partition, n, total int64
+	flush := func() error {
+		if n == 0 {
+			return nil
+		}
+		name := fmt.Sprintf("part-%05d.jsonl", partition)
+		if cdc {
+			// Each cycle's changes are written to new files
+			name = fmt.Sprintf("%08d-%s", state.Cycle, name)
+		}
+		if _, err := pachClient.PutFileOverwrite(input.Repo, commit, path.Join(dir, name), &buf, 0); err != nil {
+			return err
+		}
+		buf.Reset()
+		partition, n = partition+1, 0
+		return nil
+	}
+	values := make([]interface{}, len(columnTypes))
+	pointers := make([]interface{}, len(columnTypes))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return 0, errors.EnsureStack(err)
+		}
+		record := make(map[string]interface{}, len(values))
+		for i, v := range values {
+			record[schema[i].Name] = dbImportValue(v)
+		}
+		line, err := json.Marshal(record)
+		if err != nil {
+			return 0, errors.EnsureStack(err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		if offsetIndex >= 0 && values[offsetIndex] != nil {
+			state.Offsets[table.Name] = dbImportOffset(values[offsetIndex])
+		}
+		total++
+		if n++; n == partitionRows {
+			if err := flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, errors.EnsureStack(err)
+	}
+	return total, flush()
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateDBImportInput(t *testing.T) {
+	input := func(mode pps.DBImportMode, tables ...*pps.DBImportTable) *pps.DBImportInput {
+		return &pps.DBImportInput{
+			Name:      "db",
+			Driver:    "postgres",
+			DSNSecret: "db-dsn",
+			Mode:      mode,
+			Tables:    tables,
+		}
+	}
+	require.NoError(t, validateDBImportInput(input(pps.DBImportMode_DB_IMPORT_SNAPSHOT,
+		&pps.DBImportTable{Name: "public.users"},
+		&pps.DBImportTable{Name: "orders", Columns: []string{"id", "total"}, OffsetColumn: "id"})))
+	require.NoError(t, validateDBImportInput(input(pps.DBImportMode_DB_IMPORT_CDC,
+		&pps.DBImportTable{Name: "events", OffsetColumn: "updated_at"})))
+
+	// CDC needs an offset column
+	require.YesError(t, validateDBImportInput(input(pps.DBImportMode_DB_IMPORT_CDC,
+		&pps.DBImportTable{Name: "events"})))
+	// which must be imported
+	require.YesError(t, validateDBImportInput(input(pps.DBImportMode_DB_IMPORT_CDC,
+		&pps.DBImportTable{Name: "events", Columns: []string{"name"}, OffsetColumn: "id"})))
+	// Names that can't be quoted safely are rejected
+	require.YesError(t, validateDBImportInput(input(pps.DBImportMode_DB_IMPORT_SNAPSHOT,
+		&pps.DBImportTable{Name: `users"; DROP TABLE users; --`})))
+	require.YesError(t, validateDBImportInput(input(pps.DBImportMode_DB_IMPORT_SNAPSHOT,
+		&pps.DBImportTable{Name: "db.public.users"})))
+	require.YesError(t, validateDBImportInput(input(pps.DBImportMode_DB_IMPORT_SNAPSHOT,
+		&pps.DBImportTable{Name: "users", Columns: []string{"name`"}})))
+	require.YesError(t, validateDBImportInput(input(pps.DBImportMode_DB_IMPORT_SNAPSHOT,
+		&pps.DBImportTable{Name: "users"}, &pps.DBImportTable{Name: "users"})))
+	require.YesError(t, validateDBImportInput(input(pps.DBImportMode_DB_IMPORT_SNAPSHOT)))
+
+	noDriver := input(pps.DBImportMode_DB_IMPORT_SNAPSHOT, &pps.DBImportTable{Name: "users"})
+	noDriver.Driver = "sqlite"
+	require.YesError(t, validateDBImportInput(noDriver))
+}
+
+func TestDBImportQuery(t *testing.T) {
+	table := &pps.DBImportTable{Name: "public.users"}
+	require.Equal(t, `SELECT * FROM "public"."users"`, dbImportQuery("postgres", table, false))
+
+	table = &pps.DBImportTable{Name: "users", Columns: []string{"id", "name"}, OffsetColumn: "id"}
+	require.Equal(t, `SELECT "id", "name" FROM "users" ORDER BY "id"`, dbImportQuery("postgres", table, false))
+	require.Equal(t, `SELECT "id", "name" FROM "users" WHERE "id" > $1 ORDER BY "id"`, dbImportQuery("postgres", table, true))
+	require.Equal(t, "SELECT `id`, `name` FROM `users` WHERE `id` > ? ORDER BY `id`", dbImportQuery("mysql", table, true))
+}
+
+func TestDBImportState(t *testing.T) {
+	state := readDBImportState(nil)
+	require.Equal(t, int64(0), state.Cycle)
+	require.Equal(t, 0, len(state.Offsets))
+	require.Equal(t, 0, len(readDBImportState(&pfs.CommitInfo{Description: "not json"}).Offsets))
+
+	next := state.next()
+	next.Offsets["users"] = "42"
+	require.Equal(t, 0, len(state.Offsets))
+
+	state = readDBImportState(&pfs.CommitInfo{Description: next.String()})
+	require.Equal(t, int64(1), state.Cycle)
+	require.Equal(t, "42", state.Offsets["users"])
+}
+
+func TestDBImportValues(t *testing.T) {
+	ts := time.Date(2020, 5, 1, 12, 30, 0, 500, time.FixedZone("", 3600))
+	require.Equal(t, "2020-05-01T11:30:00.0000005Z", dbImportValue(ts))
+	require.Equal(t, "2020-05-01 11:30:00.0000005", dbImportOffset(ts))
+	require.Equal(t, "abc", dbImportValue([]byte("abc")))
+	require.Equal(t, "abc", dbImportOffset([]byte("abc")))
+	require.Equal(t, int64(7), dbImportValue(int64(7)))
+	require.Equal(t, "7", dbImportOffset(int64(7)))
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"net/url"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// egressSecretVolume is the name of the volume in which a pipeline's egress
+// credentials secret is mounted
+const egressSecretVolume = "pachyderm-egress-secret"
+
+// validateEgress checks that a pipeline's egress (if any) is well-formed, and
+// in particular that its URL doesn't contain credentials, which would be
+// visible to anyone who can inspect the pipeline or read its logs.
+func validateEgress(egress *pps.Egress) error {
+	if egress == nil {
+		return nil
+	}
+	u, err := url.Parse(egress.URL)
+	if err != nil {
+		return errors.Wrapf(err, "invalid pipeline spec: could not parse egress URL")
+	}
+	if u.User != nil {
+		return errors.New("invalid pipeline spec: egress URL must not contain credentials; use egress.credentials instead")
+	}
+	creds := egress.Credentials
+	if creds == nil {
+		return nil
+	}
+	if (creds.Secret == "") == (creds.VaultPath == "") {
+		return errors.New("invalid pipeline spec: egress credentials must set exactly one of secret and vault_path")
+	}
+	objURL, err := obj.ParseURL(egress.URL)
+	if err != nil {
+		return errors.Wrapf(err, "invalid pipeline spec")
+	}
+	if objURL.Store == "local" {
+		return errors.New("invalid pipeline spec: egress to a local URL can't use credentials")
+	}
+	return nil
+}
+
+// checkEgressSecret checks that the kubernetes secret named in a pipeline's
+// egress credentials exists, so that the pipeline fails when it's created
+// rather than when its workers can't be started. Vault paths can't be
+// checked, as they're read with the workers' Vault token.
+func (a *apiServer) checkEgressSecret(egress *pps.Egress) error {
+	name := egress.GetCredentials().GetSecret()
+	if name == "" {
+		return nil
+	}
+	if _, err := a.env.GetKubeClient().CoreV1().Secrets(a.namespace).Get(name, metav1.GetOptions{}); err != nil {
+		if isNotFoundErr(err) {
+			return errors.Errorf("egress credentials secret %q not found", name)
+		}
+		return errors.Wrapf(err, "could not get egress credentials secret %q", name)
+	}
+	return nil
+}
+
+// egressSecretVolumes returns the volume and mount (if any) through which a
+// pipeline's workers read its egress credentials secret.
+func egressSecretVolumes(egress *pps.Egress) ([]v1.Volume, []v1.VolumeMount) {
+	name := egress.GetCredentials().GetSecret()
+	if name == "" {
+		return nil, nil
+	}
+	volume := v1.Volume{
+		Name: egressSecretVolume,
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{
+				SecretName: name,
+			},
+		},
+	}
+	mount := v1.VolumeMount{
+		Name:      egressSecretVolume,
+		MountPath: client.PPSEgressSecretPath,
+		ReadOnly:  true,
+	}
+	return []v1.Volume{volume}, []v1.VolumeMount{mount}
+}
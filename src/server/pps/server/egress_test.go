@@ -0,0 +1,56 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateEgress(t *testing.T) {
+	require.NoError(t, validateEgress(nil))
+	require.NoError(t, validateEgress(&pps.Egress{URL: "s3://bucket/dir"}))
+	require.NoError(t, validateEgress(&pps.Egress{
+		URL:         "s3://bucket/dir",
+		Credentials: &pps.EgressCredentials{Secret: "egress-creds"},
+	}))
+	require.NoError(t, validateEgress(&pps.Egress{
+		URL:         "gs://bucket/dir",
+		Credentials: &pps.EgressCredentials{VaultPath: "secret/egress"},
+	}))
+
+	// URLs can't contain credentials
+	require.YesError(t, validateEgress(&pps.Egress{URL: "s3://id:secret@bucket/dir"}))
+	// Exactly one of secret and vault_path must be set
+	require.YesError(t, validateEgress(&pps.Egress{
+		URL:         "s3://bucket/dir",
+		Credentials: &pps.EgressCredentials{},
+	}))
+	require.YesError(t, validateEgress(&pps.Egress{
+		URL:         "s3://bucket/dir",
+		Credentials: &pps.EgressCredentials{Secret: "egress-creds", VaultPath: "secret/egress"},
+	}))
+	// Local URLs don't take credentials
+	require.YesError(t, validateEgress(&pps.Egress{
+		URL:         "local://dir",
+		Credentials: &pps.EgressCredentials{Secret: "egress-creds"},
+	}))
+}
+
+func TestEgressSecretVolumes(t *testing.T) {
+	volumes, mounts := egressSecretVolumes(&pps.Egress{URL: "s3://bucket/dir"})
+	require.Equal(t, 0, len(volumes))
+	require.Equal(t, 0, len(mounts))
+
+	volumes, mounts = egressSecretVolumes(&pps.Egress{
+		URL:         "s3://bucket/dir",
+		Credentials: &pps.EgressCredentials{Secret: "egress-creds"},
+	})
+	require.Equal(t, 1, len(volumes))
+	require.Equal(t, "egress-creds", volumes[0].Secret.SecretName)
+	require.Equal(t, 1, len(mounts))
+	require.Equal(t, volumes[0].Name, mounts[0].Name)
+	require.Equal(t, client.PPSEgressSecretPath, mounts[0].MountPath)
+	require.True(t, mounts[0].ReadOnly)
+}
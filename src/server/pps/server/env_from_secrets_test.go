@@ -0,0 +1,19 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateEnvFromSecrets(t *testing.T) {
+	transform := func(secrets ...*pps.EnvFromSecret) *pps.Transform {
+		return &pps.Transform{Image: "ubuntu", EnvFromSecrets: secrets}
+	}
+	require.NoError(t, validateTransform(transform(&pps.EnvFromSecret{Name: "aws-creds"})))
+	require.NoError(t, validateTransform(transform(&pps.EnvFromSecret{Name: "db.creds", Prefix: "DB_"})))
+	require.YesError(t, validateTransform(transform(&pps.EnvFromSecret{})))
+	require.YesError(t, validateTransform(transform(&pps.EnvFromSecret{Name: "Creds"})))
+	require.YesError(t, validateTransform(transform(&pps.EnvFromSecret{Name: "creds", Prefix: "1="})))
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	workercommon "github.com/pachyderm/pachyderm/src/server/worker/common"
+)
+
+// transformHash returns a hash of 'transform', which is used to tell whether
+// two jobs ran the same transform.
+func transformHash(transform *pps.Transform) (string, error) {
+	// encoding/json sorts map keys, so the hash doesn't depend on the order in
+	// which the transform's env vars are iterated
+	data, err := json.Marshal(transform)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// inputFileName identifies a datum's input file, regardless of its content
+func inputFileName(input *workercommon.Input) string {
+	return fmt.Sprintf("%s:%s", input.Name, input.FileInfo.File.Path)
+}
+
+// explainDatum explains why the datum with 'inputs' in the job 'jobInfo' was
+// or wasn't skipped, by comparing it with the datums of the pipeline's previous
+// job 'priorJobInfo' (nil if there isn't one), whose inputs are 'priorDatums'.
+func explainDatum(jobInfo *pps.JobInfo, inputs []*workercommon.Input, priorJobInfo *pps.JobInfo, priorDatums [][]*workercommon.Input) (*pps.ExplainDatumResponse, error) {
+	pipeline := jobInfo.Pipeline.Name
+	response := &pps.ExplainDatumResponse{
+		Datum: &pps.Datum{
+			ID:  workercommon.DatumID(jobInfo.Salt, inputs),
+			Job: jobInfo.Job,
+		},
+		Hash:     workercommon.HashDatum(pipeline, jobInfo.Salt, inputs),
+		Pipeline: pipeline,
+		Salt:     jobInfo.Salt,
+	}
+	for _, input := range inputs {
+		response.Inputs = append(response.Inputs, &pps.DatumHashInput{
+			Name: input.Name,
+			File: input.FileInfo.File,
+			Hash: input.FileInfo.Hash,
+		})
+	}
+	var err error
+	if response.TransformHash, err = transformHash(jobInfo.Transform); err != nil {
+		return nil, err
+	}
+	if priorJobInfo == nil {
+		response.Reasons = append(response.Reasons, fmt.Sprintf("pipeline %q has no previous job, so the datum isn't skipped", pipeline))
+		return response, nil
+	}
+	response.PriorJob = priorJobInfo.Job
+	for _, priorInputs := range priorDatums {
+		if workercommon.HashDatum(pipeline, priorJobInfo.Salt, priorInputs) == response.Hash {
+			response.PriorDatum = &pps.Datum{
+				ID:  workercommon.DatumID(priorJobInfo.Salt, priorInputs),
+				Job: priorJobInfo.Job,
+			}
+			response.Reasons = append(response.Reasons, fmt.Sprintf("the datum has the same inputs as datum %s in the previous job %s, so it's skipped",
+				response.PriorDatum.ID, priorJobInfo.Job.ID))
+			return response, nil
+		}
+	}
+
+	// Explain what changed since the previous job
+	if priorJobInfo.Salt != jobInfo.Salt {
+		response.Reasons = append(response.Reasons, "the pipeline's salt changed (it was updated with reprocess), so no datums of previous jobs are skipped")
+	}
+	priorTransformHash, err := transformHash(priorJobInfo.Transform)
+	if err != nil {
+		return nil, err
+	}
+	if priorTransformHash != response.TransformHash {
+		reason := "the transform changed"
+		if priorJobInfo.Transform.GetImage() != jobInfo.Transform.GetImage() {
+			reason = fmt.Sprintf("the transform image changed from %q to %q", priorJobInfo.Transform.GetImage(), jobInfo.Transform.GetImage())
+		}
+		if priorJobInfo.Salt == jobInfo.Salt {
+			reason += ", but the pipeline wasn't updated with reprocess, so this doesn't cause datums to be reprocessed"
+		}
+		response.Reasons = append(response.Reasons, reason)
+	}
+	priorHashes := make(map[string][]byte)
+	for _, priorInputs := range priorDatums {
+		for _, input := range priorInputs {
+			priorHashes[inputFileName(input)] = input.FileInfo.Hash
+		}
+	}
+	filesChanged := false
+	for _, input := range inputs {
+		name := inputFileName(input)
+		priorHash, ok := priorHashes[name]
+		if !ok {
+			response.Reasons = append(response.Reasons, fmt.Sprintf("file %s is new", name))
+		} else if !bytes.Equal(priorHash, input.FileInfo.Hash) {
+			response.Reasons = append(response.Reasons, fmt.Sprintf("file %s changed", name))
+		} else {
+			continue
+		}
+		filesChanged = true
+	}
+	if !filesChanged && priorJobInfo.Salt == jobInfo.Salt {
+		// e.g. a cross of an existing file with a file that was only in
+		// another datum
+		response.Reasons = append(response.Reasons, "none of the datum's files changed, but no datum in the previous job had the same combination of files")
+	}
+	return response, nil
+}
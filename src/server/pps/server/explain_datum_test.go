@@ -0,0 +1,76 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	workercommon "github.com/pachyderm/pachyderm/src/server/worker/common"
+)
+
+func explainInput(path string, hash string) *workercommon.Input {
+	return &workercommon.Input{
+		Name: "images",
+		FileInfo: &pfs.FileInfo{
+			File: client.NewFile("images", "master", path),
+			Hash: []byte(hash),
+		},
+	}
+}
+
+func explainJob(id string, salt string, image string) *pps.JobInfo {
+	return &pps.JobInfo{
+		Job:       client.NewJob(id),
+		Pipeline:  client.NewPipeline("edges"),
+		Salt:      salt,
+		Transform: &pps.Transform{Image: image, Cmd: []string{"edges"}},
+	}
+}
+
+func TestExplainDatum(t *testing.T) {
+	jobInfo := explainJob("job2", "salt", "edges:v1")
+	inputs := []*workercommon.Input{explainInput("/a.png", "a")}
+
+	// No previous job
+	response, err := explainDatum(jobInfo, inputs, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, workercommon.DatumID("salt", inputs), response.Datum.ID)
+	require.Equal(t, 1, len(response.Inputs))
+	require.Nil(t, response.PriorJob)
+	require.Equal(t, 1, len(response.Reasons))
+
+	// The previous job processed the same datum, so it's skipped
+	priorJobInfo := explainJob("job1", "salt", "edges:v1")
+	priorDatums := [][]*workercommon.Input{
+		{explainInput("/a.png", "a")},
+		{explainInput("/b.png", "b")},
+	}
+	response, err = explainDatum(jobInfo, inputs, priorJobInfo, priorDatums)
+	require.NoError(t, err)
+	require.Equal(t, "job1", response.PriorJob.ID)
+	require.Equal(t, workercommon.DatumID("salt", priorDatums[0]), response.PriorDatum.ID)
+
+	// A file changed
+	changed := []*workercommon.Input{explainInput("/a.png", "a2")}
+	response, err = explainDatum(jobInfo, changed, priorJobInfo, priorDatums)
+	require.NoError(t, err)
+	require.Nil(t, response.PriorDatum)
+	require.Equal(t, []string{"file images:/a.png changed"}, response.Reasons)
+
+	// A new file
+	added := []*workercommon.Input{explainInput("/c.png", "c")}
+	response, err = explainDatum(jobInfo, added, priorJobInfo, priorDatums)
+	require.NoError(t, err)
+	require.Equal(t, []string{"file images:/c.png is new"}, response.Reasons)
+
+	// The pipeline was updated with reprocess and a new image
+	priorJobInfo = explainJob("job1", "oldsalt", "edges:v0")
+	priorDatums = [][]*workercommon.Input{{explainInput("/a.png", "a")}}
+	response, err = explainDatum(jobInfo, inputs, priorJobInfo, priorDatums)
+	require.NoError(t, err)
+	require.Nil(t, response.PriorDatum)
+	require.Equal(t, 2, len(response.Reasons))
+	require.NotEqual(t, "", response.TransformHash)
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// The gang schedulers that a pipeline's workers can be scheduled by
+	gangSchedulerVolcano = "volcano"
+	gangSchedulerKueue   = "kueue"
+
+	// volcanoGroupAnnotation assigns a pod to a Volcano PodGroup, which is
+	// scheduled once all of its members fit
+	volcanoGroupAnnotation = "scheduling.k8s.io/group-name"
+	// kueueQueueLabel, kueuePodGroupLabel and kueueGroupCountAnnotation
+	// submit a group of pods to a Kueue LocalQueue as a single workload
+	kueueQueueLabel           = "kueue.x-k8s.io/queue-name"
+	kueuePodGroupLabel        = "kueue.x-k8s.io/pod-group-name"
+	kueueGroupCountAnnotation = "kueue.x-k8s.io/pod-group-total-count"
+
+	volcanoPodGroupsAPI = "/apis/scheduling.volcano.sh/v1beta1/namespaces"
+	kueueWorkloadsAPI   = "/apis/kueue.x-k8s.io/v1beta1/namespaces"
+)
+
+func validateGangScheduling(gang *pps.GangScheduling) error {
+	switch gang.Scheduler {
+	case gangSchedulerVolcano:
+	case gangSchedulerKueue:
+		if gang.Queue == "" {
+			return errors.New("gang scheduling with kueue requires a queue (a LocalQueue in pachd's namespace)")
+		}
+	default:
+		return errors.Errorf("unknown gang scheduler %q (must be %q or %q)",
+			gang.Scheduler, gangSchedulerVolcano, gangSchedulerKueue)
+	}
+	return nil
+}
+
+// gangPodMetadata returns the labels and annotations of the pods of the
+// worker RC 'rcName', which has 'workers' workers that are gang-scheduled
+// with 'gang', given the pods' other labels and annotations.
+func gangPodMetadata(gang *pps.GangScheduling, rcName string, workers int32, labels, annotations map[string]string) (map[string]string, map[string]string) {
+	switch gang.Scheduler {
+	case gangSchedulerVolcano:
+		annotations = mergeMetadata(map[string]string{
+			volcanoGroupAnnotation: rcName,
+		}, annotations)
+	case gangSchedulerKueue:
+		labels = mergeMetadata(map[string]string{
+			kueueQueueLabel:    gang.Queue,
+			kueuePodGroupLabel: rcName,
+		}, labels)
+		annotations = mergeMetadata(map[string]string{
+			kueueGroupCountAnnotation: strconv.Itoa(int(workers)),
+		}, annotations)
+	}
+	return labels, annotations
+}
+
+// isGangScheduled returns true if 'pod' is scheduled by a gang scheduler, so
+// that it can't be scheduled until the rest of its group can be.
+func isGangScheduled(pod *v1.Pod) bool {
+	return pod.Annotations[volcanoGroupAnnotation] != "" || pod.Labels[kueuePodGroupLabel] != ""
+}
+
+// volcanoPodGroup is the subset of a Volcano PodGroup that Pachyderm creates
+// and reads
+type volcanoPodGroup struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels,omitempty"`
+	} `json:"metadata"`
+	Spec struct {
+		MinMember int32  `json:"minMember"`
+		Queue     string `json:"queue,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Phase      string `json:"phase,omitempty"`
+		Conditions []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Reason  string `json:"reason,omitempty"`
+			Message string `json:"message,omitempty"`
+		} `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+// createVolcanoPodGroup creates the Volcano PodGroup of the worker RC
+// 'rcName', which has 'workers' workers, unless it already exists. Volcano
+// only schedules the RC's pods once all 'workers' of them fit.
+func (a *apiServer) createVolcanoPodGroup(gang *pps.GangScheduling, rcName string, workers int32, labels map[string]string) error {
+	group := &volcanoPodGroup{APIVersion: "scheduling.volcano.sh/v1beta1", Kind: "PodGroup"}
+	group.Metadata.Name = rcName
+	group.Metadata.Labels = labels
+	group.Spec.MinMember = workers
+	group.Spec.Queue = gang.Queue
+	body, err := json.Marshal(group)
+	if err != nil {
+		return err
+	}
+	if _, err := a.env.GetKubeClient().CoreV1().RESTClient().Post().
+		AbsPath(volcanoPodGroupsAPI, a.namespace, "podgroups").
+		SetHeader("Content-Type", "application/json").
+		Body(body).
+		DoRaw(); err != nil && !isAlreadyExistsErr(err) {
+		return errors.Wrapf(err, "could not create Volcano PodGroup %q (is Volcano installed?)", rcName)
+	}
+	return nil
+}
+
+// deleteVolcanoPodGroup deletes the Volcano PodGroup 'name'.
+func (a *apiServer) deleteVolcanoPodGroup(name string) error {
+	if _, err := a.env.GetKubeClient().CoreV1().RESTClient().Delete().
+		AbsPath(volcanoPodGroupsAPI, a.namespace, "podgroups", name).
+		DoRaw(); err != nil && !isNotFoundErr(err) {
+		return errors.Wrapf(err, "could not delete Volcano PodGroup %q", name)
+	}
+	return nil
+}
+
+// parseVolcanoAdmission describes a Volcano PodGroup's admission by its queue.
+// A PodGroup is admitted once it's "Inqueue", after which its pods are bound.
+func parseVolcanoAdmission(data []byte, admission *pps.QueueAdmission) error {
+	group := &volcanoPodGroup{}
+	if err := json.Unmarshal(data, group); err != nil {
+		return errors.Wrapf(err, "could not parse Volcano PodGroup")
+	}
+	admission.Status = group.Status.Phase
+	switch group.Status.Phase {
+	case "Inqueue", "Running", "Completed":
+		admission.Admitted = true
+	}
+	for _, condition := range group.Status.Conditions {
+		if condition.Type == "Unschedulable" && condition.Status == "True" {
+			admission.Message = condition.Message
+			if admission.Message == "" {
+				admission.Message = condition.Reason
+			}
+		}
+	}
+	return nil
+}
+
+// kueueWorkload is the subset of a Kueue Workload that Pachyderm reads
+type kueueWorkload struct {
+	Status struct {
+		Conditions []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Reason  string `json:"reason,omitempty"`
+			Message string `json:"message,omitempty"`
+		} `json:"conditions,omitempty"`
+	} `json:"status"`
+}
+
+// parseKueueAdmission describes a Kueue Workload's admission by its
+// LocalQueue. The workload first reserves quota, and is then admitted.
+func parseKueueAdmission(data []byte, admission *pps.QueueAdmission) error {
+	workload := &kueueWorkload{}
+	if err := json.Unmarshal(data, workload); err != nil {
+		return errors.Wrapf(err, "could not parse Kueue Workload")
+	}
+	admission.Status = "Pending"
+	for _, condition := range workload.Status.Conditions {
+		switch {
+		case condition.Type == "Admitted" && condition.Status == "True":
+			admission.Admitted = true
+			admission.Status = "Admitted"
+			admission.Message = ""
+			return nil
+		case condition.Type == "QuotaReserved" && condition.Status == "True":
+			admission.Status = "QuotaReserved"
+		case condition.Type == "QuotaReserved" || condition.Type == "Admitted":
+			admission.Message = condition.Message
+		}
+	}
+	return nil
+}
+
+// queueAdmission returns the status of the workers of the RC 'rcName' in
+// the queue of their gang scheduler. If the scheduler hasn't seen them yet,
+// e.g. because the RC has no workers, it returns nil.
+func (a *apiServer) queueAdmission(gang *pps.GangScheduling, rcName string) *pps.QueueAdmission {
+	admission := &pps.QueueAdmission{Scheduler: gang.Scheduler, Queue: gang.Queue}
+	var path string
+	var parse func([]byte, *pps.QueueAdmission) error
+	switch gang.Scheduler {
+	case gangSchedulerVolcano:
+		path, parse = fmt.Sprintf("%s/%s/podgroups/%s", volcanoPodGroupsAPI, a.namespace, rcName), parseVolcanoAdmission
+	case gangSchedulerKueue:
+		// Kueue names the workload of a pod group after the group
+		path, parse = fmt.Sprintf("%s/%s/workloads/%s", kueueWorkloadsAPI, a.namespace, rcName), parseKueueAdmission
+	default:
+		return nil
+	}
+	data, err := a.env.GetKubeClient().CoreV1().RESTClient().Get().AbsPath(path).DoRaw()
+	if err != nil {
+		if isNotFoundErr(err) {
+			return nil
+		}
+		log.Warnf("could not get the %s queue status of %q: %v", gang.Scheduler, rcName, err)
+		admission.Message = fmt.Sprintf("could not get the workers' queue status: %v", err)
+		return admission
+	}
+	if err := parse(data, admission); err != nil {
+		admission.Message = err.Error()
+	}
+	return admission
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateGangScheduling(t *testing.T) {
+	require.NoError(t, validateGangScheduling(&pps.GangScheduling{Scheduler: "volcano"}))
+	require.NoError(t, validateGangScheduling(&pps.GangScheduling{Scheduler: "kueue", Queue: "gpus"}))
+	require.YesError(t, validateGangScheduling(&pps.GangScheduling{Scheduler: "kueue"}))
+	require.YesError(t, validateGangScheduling(&pps.GangScheduling{Scheduler: "yunikorn"}))
+}
+
+func TestGangPodMetadata(t *testing.T) {
+	labels := map[string]string{"app": "pipeline-edges-v1"}
+	annotations := map[string]string{"pipelineName": "edges"}
+
+	l, a := gangPodMetadata(&pps.GangScheduling{Scheduler: "volcano"}, "pipeline-edges-v1", 4, labels, annotations)
+	require.Equal(t, labels, l)
+	require.Equal(t, "pipeline-edges-v1", a[volcanoGroupAnnotation])
+	require.Equal(t, "edges", a["pipelineName"])
+	require.True(t, isGangScheduled(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: l, Annotations: a}}))
+
+	l, a = gangPodMetadata(&pps.GangScheduling{Scheduler: "kueue", Queue: "gpus"}, "pipeline-edges-v1", 4, labels, annotations)
+	require.Equal(t, "gpus", l[kueueQueueLabel])
+	require.Equal(t, "pipeline-edges-v1", l[kueuePodGroupLabel])
+	require.Equal(t, "4", a[kueueGroupCountAnnotation])
+	require.True(t, isGangScheduled(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: l, Annotations: a}}))
+	// The inputs aren't modified
+	require.Equal(t, 1, len(labels))
+
+	require.False(t, isGangScheduled(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: labels, Annotations: annotations}}))
+}
+
+func TestParseVolcanoAdmission(t *testing.T) {
+	admission := &pps.QueueAdmission{}
+	require.NoError(t, parseVolcanoAdmission([]byte(`{
+		"status": {
+			"phase": "Pending",
+			"conditions": [{"type": "Unschedulable", "status": "True", "message": "3/4 tasks in gang unschedulable"}]
+		}
+	}`), admission))
+	require.False(t, admission.Admitted)
+	require.Equal(t, "Pending", admission.Status)
+	require.Equal(t, "3/4 tasks in gang unschedulable", admission.Message)
+
+	admission = &pps.QueueAdmission{}
+	require.NoError(t, parseVolcanoAdmission([]byte(`{"status": {"phase": "Running"}}`), admission))
+	require.True(t, admission.Admitted)
+}
+
+func TestParseKueueAdmission(t *testing.T) {
+	admission := &pps.QueueAdmission{}
+	require.NoError(t, parseKueueAdmission([]byte(`{
+		"status": {
+			"conditions": [{"type": "QuotaReserved", "status": "False", "message": "couldn't assign flavors to pod set main: insufficient quota for nvidia.com/gpu"}]
+		}
+	}`), admission))
+	require.False(t, admission.Admitted)
+	require.Equal(t, "Pending", admission.Status)
+	require.Matches(t, "insufficient quota", admission.Message)
+
+	admission = &pps.QueueAdmission{}
+	require.NoError(t, parseKueueAdmission([]byte(`{
+		"status": {
+			"conditions": [
+				{"type": "QuotaReserved", "status": "True"},
+				{"type": "Admitted", "status": "True", "message": "The workload is admitted"}
+			]
+		}
+	}`), admission))
+	require.True(t, admission.Admitted)
+	require.Equal(t, "Admitted", admission.Status)
+	require.Equal(t, "", admission.Message)
+}
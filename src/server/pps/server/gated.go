@@ -0,0 +1,79 @@
+package server
+
+import (
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+)
+
+// createGatedOutputBranch creates the output branch of a gated pipeline, if it
+// doesn't exist yet. The branch has no provenance (jobs commit to the pending
+// branch instead), so it only moves when a commit is approved.
+func createGatedOutputBranch(pachClient *client.APIClient, pipelineInfo *pps.PipelineInfo) error {
+	if !pipelineInfo.Gated {
+		return nil
+	}
+	_, err := pachClient.InspectBranch(pipelineInfo.Pipeline.Name, pipelineInfo.OutputBranch)
+	if err == nil || !isNotFoundErr(err) {
+		return err
+	}
+	if err := pachClient.CreateBranch(pipelineInfo.Pipeline.Name, pipelineInfo.OutputBranch, "", nil); err != nil {
+		return errors.Wrapf(err, "could not create gated output branch")
+	}
+	return nil
+}
+
+// approveCommit moves the output branch of a gated pipeline to 'commit', one
+// of the pipeline's finished output commits, which triggers any downstream
+// pipelines. If 'commit' is unfinished and its job is paused after its smoke
+// test, the job is approved to continue instead. Only owners of the
+// pipeline's output repo may approve commits.
+func (a *apiServer) approveCommit(pachClient *client.APIClient, commit *pfs.Commit) error {
+	if commit == nil || commit.Repo == nil || commit.ID == "" {
+		return errors.New("must specify the commit to approve")
+	}
+	pipelineName := commit.Repo.Name
+	pipelineInfo, err := a.inspectPipeline(pachClient, pipelineName)
+	if err != nil {
+		return errors.Wrapf(err, "could not inspect pipeline %q", pipelineName)
+	}
+	if !pipelineInfo.Gated && !pipelineInfo.SmokeTest.GetPause() {
+		return errors.Errorf("pipeline %q isn't gated and its smoke test doesn't pause, so its commits don't need to be approved", pipelineName)
+	}
+	if err := a.authorizePipelineOp(pachClient, pipelineOpApprove, nil, pipelineName); err != nil {
+		return err
+	}
+	commitInfo, err := pachClient.InspectCommit(pipelineName, commit.ID)
+	if err != nil {
+		return err
+	}
+	if commitInfo.Finished == nil && pipelineInfo.SmokeTest.GetPause() {
+		return a.approveSmokeTest(pachClient, pipelineInfo, commitInfo)
+	}
+	if !pipelineInfo.Gated {
+		return errors.Errorf("commit %s@%s can only be approved while its job is paused after its smoke test", pipelineName, commitInfo.Commit.ID)
+	}
+	pendingBranch := ppsutil.JobOutputBranch(pipelineInfo)
+	if commitInfo.Branch == nil || commitInfo.Branch.Name != pendingBranch {
+		return errors.Errorf("commit %s@%s isn't on the pending branch %q of pipeline %q",
+			pipelineName, commitInfo.Commit.ID, pendingBranch, pipelineName)
+	}
+	if commitInfo.Finished == nil {
+		return errors.Errorf("commit %s@%s can't be approved until its job has finished", pipelineName, commitInfo.Commit.ID)
+	}
+	jobInfo, err := pachClient.InspectJobOutputCommit(pipelineName, commitInfo.Commit.ID, true)
+	if err != nil {
+		return err
+	}
+	if jobInfo.State != pps.JobState_JOB_SUCCESS {
+		return errors.Errorf("commit %s@%s can't be approved because its job %s is in state %v",
+			pipelineName, commitInfo.Commit.ID, jobInfo.Job.ID, jobInfo.State)
+	}
+	// Moving the branch propagates the commit to downstream pipelines
+	if err := pachClient.CreateBranch(pipelineName, pipelineInfo.OutputBranch, commitInfo.Commit.ID, nil); err != nil {
+		return errors.Wrapf(err, "could not move output branch %q", pipelineInfo.OutputBranch)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package githook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
+
+	etcd "github.com/coreos/etcd/clientv3"
+	logrus "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"gopkg.in/go-playground/webhooks.v5/bitbucket"
+	"gopkg.in/go-playground/webhooks.v5/github"
+	"gopkg.in/go-playground/webhooks.v5/gitlab"
+)
+
+const (
+	// The providers that the githook server accepts deliveries from
+	providerGitHub    = "github"
+	providerGitLab    = "gitlab"
+	providerBitbucket = "bitbucket"
+
+	// maxDeliveriesPerURL is the number of deliveries that are kept for each
+	// git URL (and for deliveries whose URL is unknown)
+	maxDeliveriesPerURL = 20
+	// deliveryTTL is how long deliveries are kept, in seconds
+	deliveryTTL = 7 * 24 * 60 * 60
+
+	// gitLabPublic is the visibility level of public GitLab projects
+	gitLabPublic = 20
+)
+
+// parseDelivery verifies and parses the webhook request 'r', and returns the
+// delivery that it describes. If the request is a push, the delivery's
+// payload is the push in GitHub's format, which is what's committed to git
+// input repos.
+func (s *gitHookServer) parseDelivery(r *http.Request) *pps.GitHookDelivery {
+	delivery := &pps.GitHookDelivery{
+		ID:       uuid.NewWithoutDashes(),
+		Received: types.TimestampNow(),
+	}
+	var pl *github.PushPayload
+	var err error
+	switch {
+	case r.Header.Get("X-GitHub-Event") != "":
+		delivery.Provider = providerGitHub
+		pl, err = s.parseGitHub(r)
+	case r.Header.Get("X-Gitlab-Event") != "":
+		delivery.Provider = providerGitLab
+		pl, err = s.parseGitLab(r)
+	case r.Header.Get("X-Event-Key") != "":
+		delivery.Provider = providerBitbucket
+		pl, err = s.parseBitbucket(r)
+	default:
+		err = errors.New("unrecognized webhook request: expected a GitHub, GitLab or Bitbucket event")
+	}
+	switch {
+	case err != nil:
+		delivery.State = pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_FAILED
+		delivery.Error = err.Error()
+	case pl == nil:
+		delivery.State = pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_IGNORED
+	default:
+		setPayload(delivery, pl)
+	}
+	return delivery
+}
+
+// setPayload sets the payload of 'delivery' to 'pl', along with the fields
+// that describe it.
+func setPayload(delivery *pps.GitHookDelivery, pl *github.PushPayload) {
+	delivery.URL = pl.Repository.CloneURL
+	delivery.Branch = strings.TrimPrefix(pl.Ref, "refs/heads/")
+	delivery.SHA = pl.After
+	raw, err := json.Marshal(pl)
+	if err != nil {
+		delivery.State = pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_FAILED
+		delivery.Error = fmt.Sprintf("could not marshal payload: %v", err)
+		return
+	}
+	delivery.Payload = raw
+}
+
+// parseGitHub parses a GitHub delivery. It returns nil if the delivery isn't
+// a push.
+func (s *gitHookServer) parseGitHub(r *http.Request) (*github.PushPayload, error) {
+	payload, err := s.github.Parse(r, github.PushEvent)
+	if err != nil {
+		// `ErrEventNotFound` implies github sent an event we didn't ask for
+		if errors.Is(err, github.ErrEventNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	pl, ok := payload.(github.PushPayload)
+	if !ok {
+		return nil, errors.Errorf("unexpected github payload type %T, this is likely a bug", payload)
+	}
+	return &pl, nil
+}
+
+// parseGitLab parses a GitLab delivery. It returns nil if the delivery isn't
+// a push.
+func (s *gitHookServer) parseGitLab(r *http.Request) (*github.PushPayload, error) {
+	payload, err := s.gitlab.Parse(r, gitlab.PushEvents)
+	if err != nil {
+		if errors.Is(err, gitlab.ErrEventNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	pl, ok := payload.(gitlab.PushEventPayload)
+	if !ok {
+		return nil, errors.Errorf("unexpected gitlab payload type %T, this is likely a bug", payload)
+	}
+	result := &github.PushPayload{
+		Ref:    pl.Ref,
+		Before: pl.Before,
+		After:  pl.After,
+	}
+	result.Repository.Name = pl.Project.Name
+	result.Repository.FullName = pl.Project.PathWithNamespace
+	result.Repository.CloneURL = pl.Project.GitHTTPURL
+	result.Repository.Private = pl.Project.VisibilityLevel != gitLabPublic
+	return result, nil
+}
+
+// bitbucketCloneLinks are the clone links of a Bitbucket repository, which
+// bitbucket.Repository doesn't have
+type bitbucketCloneLinks struct {
+	Repository struct {
+		Links struct {
+			Clone []struct {
+				Name string `json:"name"`
+				Href string `json:"href"`
+			} `json:"clone"`
+		} `json:"links"`
+	} `json:"repository"`
+}
+
+// bitbucketCloneURL returns the HTTPS clone URL of the repository of the
+// Bitbucket delivery 'body', without any user name in it (as in git inputs'
+// URLs). If the delivery has no clone links (Bitbucket Cloud's don't), the
+// URL is derived from the repository's web page, 'htmlURL'.
+func bitbucketCloneURL(body []byte, htmlURL string) string {
+	var links bitbucketCloneLinks
+	if err := json.Unmarshal(body, &links); err == nil {
+		for _, link := range links.Repository.Links.Clone {
+			if link.Name != "https" && link.Name != "http" {
+				continue
+			}
+			u, err := url.Parse(link.Href)
+			if err != nil {
+				continue
+			}
+			u.User = nil
+			return u.String()
+		}
+	}
+	return strings.TrimSuffix(htmlURL, "/") + ".git"
+}
+
+// parseBitbucket parses a Bitbucket delivery. It returns nil if the delivery
+// isn't a push to a branch.
+func (s *gitHookServer) parseBitbucket(r *http.Request) (*github.PushPayload, error) {
+	// The body is parsed twice, as the clone links aren't in
+	// bitbucket.RepoPushPayload
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	payload, err := s.bitbucket.Parse(r, bitbucket.RepoPushEvent)
+	if err != nil {
+		if errors.Is(err, bitbucket.ErrEventNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	pl, ok := payload.(bitbucket.RepoPushPayload)
+	if !ok {
+		return nil, errors.Errorf("unexpected bitbucket payload type %T, this is likely a bug", payload)
+	}
+	for _, change := range pl.Push.Changes {
+		if change.New.Type != "branch" {
+			continue // a tag, or a deleted branch
+		}
+		result := &github.PushPayload{
+			Ref:   "refs/heads/" + change.New.Name,
+			After: change.New.Target.Hash,
+		}
+		result.Repository.Name = pl.Repository.Name
+		result.Repository.FullName = pl.Repository.FullName
+		result.Repository.CloneURL = bitbucketCloneURL(body, pl.Repository.Links.HTML.Href)
+		result.Repository.Private = pl.Repository.IsPrivate
+		return result, nil
+	}
+	return nil, nil
+}
+
+// recordDelivery saves 'delivery', and deletes the oldest deliveries for
+// its URL beyond the most recent maxDeliveriesPerURL. Errors are only logged,
+// as they shouldn't fail the delivery.
+func (s *gitHookServer) recordDelivery(delivery *pps.GitHookDelivery) {
+	if err := recordDelivery(s.etcdClient, s.deliveries, delivery); err != nil {
+		logrus.Errorf("error recording githook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+func recordDelivery(etcdClient *etcd.Client, deliveries col.Collection, delivery *pps.GitHookDelivery) error {
+	ctx := context.Background()
+	if _, err := col.NewSTM(ctx, etcdClient, func(stm col.STM) error {
+		return deliveries.ReadWrite(stm).PutTTL(delivery.ID, delivery, deliveryTTL)
+	}); err != nil {
+		return err
+	}
+	var ids []string
+	received := make(map[string]*types.Timestamp)
+	d := &pps.GitHookDelivery{}
+	if err := deliveries.ReadOnly(ctx).List(d, col.DefaultOptions, func(id string) error {
+		if d.URL == delivery.URL {
+			ids = append(ids, id)
+			received[id] = proto.Clone(d.Received).(*types.Timestamp)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(ids) <= maxDeliveriesPerURL {
+		return nil
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return received[ids[i]].Compare(received[ids[j]]) > 0
+	})
+	_, err := col.NewSTM(ctx, etcdClient, func(stm col.STM) error {
+		for _, id := range ids[maxDeliveriesPerURL:] {
+			if err := deliveries.ReadWrite(stm).Delete(id); err != nil && !col.IsErrNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// ListDeliveries returns the githook server's recent deliveries, newest
+// first.
+func ListDeliveries(ctx context.Context, deliveries col.Collection) ([]*pps.GitHookDelivery, error) {
+	var result []*pps.GitHookDelivery
+	d := &pps.GitHookDelivery{}
+	if err := deliveries.ReadOnly(ctx).List(d, col.DefaultOptions, func(string) error {
+		result = append(result, proto.Clone(d).(*pps.GitHookDelivery))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Received.Compare(result[j].Received) > 0
+	})
+	return result, nil
+}
+
+// Replay commits the push of the recorded delivery with ID 'id' to its git
+// inputs again, as 'pachClient', and returns the new delivery that records
+// the result.
+func Replay(pachClient *client.APIClient, etcdClient *etcd.Client, etcdPrefix string, id string) (*pps.GitHookDelivery, error) {
+	s, err := newGitHookServer(pachClient, etcdClient, etcdPrefix, Secrets{})
+	if err != nil {
+		return nil, err
+	}
+	original := &pps.GitHookDelivery{}
+	if err := s.deliveries.ReadOnly(pachClient.Ctx()).Get(id, original); err != nil {
+		if col.IsErrNotFound(err) {
+			return nil, errors.Errorf("githook delivery %s not found (deliveries are only kept for a week)", id)
+		}
+		return nil, err
+	}
+	if len(original.Payload) == 0 {
+		return nil, errors.Errorf("githook delivery %s can't be replayed, as it wasn't a push that could be parsed", id)
+	}
+	delivery := &pps.GitHookDelivery{
+		ID:       uuid.NewWithoutDashes(),
+		Provider: original.Provider,
+		Received: types.TimestampNow(),
+		URL:      original.URL,
+		Branch:   original.Branch,
+		SHA:      original.SHA,
+		Payload:  original.Payload,
+		ReplayOf: original.ID,
+	}
+	s.handleDelivery(delivery)
+	if err := recordDelivery(etcdClient, s.deliveries, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
@@ -0,0 +1,285 @@
+package githook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	etcd "github.com/coreos/etcd/clientv3"
+	"github.com/gogo/protobuf/types"
+	"gopkg.in/go-playground/webhooks.v5/github"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsdb"
+	"github.com/pachyderm/pachyderm/src/server/pkg/testetcd"
+	"github.com/pachyderm/pachyderm/src/server/pkg/testpachd"
+	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
+)
+
+func newDeliveryRequest(t *testing.T, header map[string]string, body string) *http.Request {
+	r, err := http.NewRequest(http.MethodPost, hookPath(), strings.NewReader(body))
+	require.NoError(t, err)
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	return r
+}
+
+const gitLabPush = `{
+	"object_kind": "push",
+	"ref": "refs/heads/master",
+	"before": "0000",
+	"after": "abcd",
+	"project": {
+		"name": "repo",
+		"path_with_namespace": "owner/repo",
+		"git_http_url": "https://gitlab.com/owner/repo.git",
+		"visibility_level": %d
+	}
+}`
+
+const bitbucketPush = `{
+	"repository": {
+		"name": "repo",
+		"full_name": "owner/repo",
+		"is_private": false,
+		"links": {
+			"html": {"href": "https://bitbucket.org/owner/repo"}%s
+		}
+	},
+	"push": {"changes": [%s]}
+}`
+
+const bitbucketBranch = `{"new": {"type": "branch", "name": "master", "target": {"hash": "abcd"}}}`
+
+const bitbucketTag = `{"new": {"type": "tag", "name": "v1", "target": {"hash": "abcd"}}}`
+
+func TestParseDelivery(t *testing.T) {
+	s, err := newGitHookServer(nil, nil, "", Secrets{GitLab: "gitlab-secret", BitbucketHookUUID: "bitbucket-uuid"})
+	require.NoError(t, err)
+	gitLab := func(event, token string) map[string]string {
+		return map[string]string{"X-Gitlab-Event": event, "X-Gitlab-Token": token}
+	}
+	bitbucket := func(event, hookUUID string) map[string]string {
+		return map[string]string{"X-Event-Key": event, "X-Hook-UUID": hookUUID}
+	}
+	for _, test := range []struct {
+		name        string
+		header      map[string]string
+		body        string
+		provider    string
+		state       pps.GitHookDeliveryState
+		url, branch string
+		private     bool
+	}{{
+		name:     "GitLab public push",
+		header:   gitLab("Push Hook", "gitlab-secret"),
+		body:     fmt.Sprintf(gitLabPush, gitLabPublic),
+		provider: providerGitLab,
+		url:      "https://gitlab.com/owner/repo.git",
+		branch:   "master",
+	}, {
+		name:     "GitLab private push",
+		header:   gitLab("Push Hook", "gitlab-secret"),
+		body:     fmt.Sprintf(gitLabPush, 0),
+		provider: providerGitLab,
+		url:      "https://gitlab.com/owner/repo.git",
+		branch:   "master",
+		private:  true,
+	}, {
+		name:     "GitLab tag push",
+		header:   gitLab("Tag Push Hook", "gitlab-secret"),
+		body:     fmt.Sprintf(gitLabPush, gitLabPublic),
+		provider: providerGitLab,
+		state:    pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_IGNORED,
+	}, {
+		name:     "GitLab wrong token",
+		header:   gitLab("Push Hook", "wrong"),
+		body:     fmt.Sprintf(gitLabPush, gitLabPublic),
+		provider: providerGitLab,
+		state:    pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_FAILED,
+	}, {
+		name:   "Bitbucket push with clone links",
+		header: bitbucket("repo:push", "bitbucket-uuid"),
+		body: fmt.Sprintf(bitbucketPush, `, "clone": [
+			{"name": "ssh", "href": "ssh://git@bitbucket.example.com/owner/repo.git"},
+			{"name": "https", "href": "https://someone@bitbucket.example.com/scm/owner/repo.git"}
+		]`, bitbucketBranch),
+		provider: providerBitbucket,
+		url:      "https://bitbucket.example.com/scm/owner/repo.git",
+		branch:   "master",
+	}, {
+		name:     "Bitbucket push without clone links",
+		header:   bitbucket("repo:push", "bitbucket-uuid"),
+		body:     fmt.Sprintf(bitbucketPush, "", bitbucketBranch),
+		provider: providerBitbucket,
+		url:      "https://bitbucket.org/owner/repo.git",
+		branch:   "master",
+	}, {
+		name:     "Bitbucket tag push",
+		header:   bitbucket("repo:push", "bitbucket-uuid"),
+		body:     fmt.Sprintf(bitbucketPush, "", bitbucketTag),
+		provider: providerBitbucket,
+		state:    pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_IGNORED,
+	}, {
+		name:     "Bitbucket wrong hook UUID",
+		header:   bitbucket("repo:push", "wrong"),
+		body:     fmt.Sprintf(bitbucketPush, "", bitbucketBranch),
+		provider: providerBitbucket,
+		state:    pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_FAILED,
+	}, {
+		name:  "unknown provider",
+		body:  "{}",
+		state: pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_FAILED,
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			delivery := s.parseDelivery(newDeliveryRequest(t, test.header, test.body))
+			require.Equal(t, test.provider, delivery.Provider)
+			require.Equal(t, test.state, delivery.State, delivery.Error)
+			if test.state != pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_SUCCEEDED {
+				require.Equal(t, 0, len(delivery.Payload))
+				return
+			}
+			require.Equal(t, test.url, delivery.URL)
+			require.Equal(t, test.branch, delivery.Branch)
+			require.Equal(t, "abcd", delivery.SHA)
+			// The payload is in GitHub's format, whichever provider sent it
+			var pl github.PushPayload
+			require.NoError(t, json.Unmarshal(delivery.Payload, &pl))
+			require.Equal(t, test.url, pl.Repository.CloneURL)
+			require.Equal(t, "owner/repo", pl.Repository.FullName)
+			require.Equal(t, test.private, pl.Repository.Private)
+		})
+	}
+}
+
+func TestRecordDelivery(t *testing.T) {
+	require.NoError(t, testetcd.WithEnv(func(e *testetcd.Env) error {
+		prefix := uuid.NewWithoutDashes()
+		deliveries := ppsdb.GitHookDeliveries(e.EtcdClient, prefix)
+		record := func(url string, received int64) *pps.GitHookDelivery {
+			delivery := &pps.GitHookDelivery{
+				ID:       uuid.NewWithoutDashes(),
+				URL:      url,
+				Received: &types.Timestamp{Seconds: received},
+			}
+			require.NoError(t, recordDelivery(e.EtcdClient, deliveries, delivery))
+			return delivery
+		}
+		first := record("https://example.com/a.git", 0)
+		for i := int64(1); i < maxDeliveriesPerURL+5; i++ {
+			record("https://example.com/a.git", i)
+		}
+		other := record("https://example.com/b.git", 0)
+
+		// Only the newest deliveries of each URL are kept
+		result, err := ListDeliveries(context.Background(), deliveries)
+		require.NoError(t, err)
+		require.Equal(t, maxDeliveriesPerURL+1, len(result))
+		var a []*pps.GitHookDelivery
+		for _, delivery := range result {
+			if delivery.URL == "https://example.com/a.git" {
+				a = append(a, delivery)
+			}
+			require.NotEqual(t, first.ID, delivery.ID)
+		}
+		require.Equal(t, maxDeliveriesPerURL, len(a))
+		require.Equal(t, int64(maxDeliveriesPerURL+4), a[0].Received.Seconds)
+		require.Equal(t, int64(5), a[len(a)-1].Received.Seconds)
+
+		// Deliveries expire after a week
+		resp, err := e.EtcdClient.Get(context.Background(), deliveryKey(prefix, other.ID))
+		require.NoError(t, err)
+		require.Equal(t, 1, len(resp.Kvs))
+		ttl, err := e.EtcdClient.TimeToLive(context.Background(), etcd.LeaseID(resp.Kvs[0].Lease))
+		require.NoError(t, err)
+		require.Equal(t, int64(deliveryTTL), ttl.GrantedTTL)
+		return nil
+	}))
+}
+
+// deliveryKey returns the etcd key of the delivery 'id'
+func deliveryKey(etcdPrefix, id string) string {
+	return strings.TrimSuffix(etcdPrefix, "/") + "/githook_deliveries/" + id
+}
+
+func TestReplay(t *testing.T) {
+	require.NoError(t, testpachd.WithMockEnv(func(env *testpachd.MockEnv) error {
+		prefix := uuid.NewWithoutDashes()
+		deliveries := ppsdb.GitHookDeliveries(env.EtcdClient, prefix)
+		env.MockPachd.PPS.ListPipeline.Use(func(context.Context, *pps.ListPipelineRequest) (*pps.PipelineInfos, error) {
+			return &pps.PipelineInfos{PipelineInfo: []*pps.PipelineInfo{{
+				Pipeline: client.NewPipeline("pipeline"),
+				Input: &pps.Input{Git: &pps.GitInput{
+					Name:   "repo",
+					URL:    "https://gitlab.com/owner/repo.git",
+					Branch: "master",
+				}},
+			}}}, nil
+		})
+		env.MockPachd.PFS.StartCommit.Use(func(_ context.Context, req *pfs.StartCommitRequest) (*pfs.Commit, error) {
+			return client.NewCommit(req.Parent.Repo.Name, "commit"), nil
+		})
+		var finished bool
+		env.MockPachd.PFS.FinishCommit.Use(func(context.Context, *pfs.FinishCommitRequest) (*types.Empty, error) {
+			finished = true
+			return &types.Empty{}, nil
+		})
+		committed := &bytes.Buffer{}
+		env.MockPachd.PFS.PutFile.Use(func(serv pfs.API_PutFileServer) error {
+			for {
+				req, err := serv.Recv()
+				if err == io.EOF {
+					return serv.SendAndClose(&types.Empty{})
+				} else if err != nil {
+					return err
+				}
+				if !req.Delete {
+					committed.Write(req.Value)
+				}
+			}
+		})
+
+		// Deliveries can only be replayed if they're recorded and have a payload
+		_, err := Replay(env.PachClient, env.EtcdClient, prefix, "nonexistent")
+		require.YesError(t, err)
+		ignored := &pps.GitHookDelivery{
+			ID:       uuid.NewWithoutDashes(),
+			Received: types.TimestampNow(),
+			State:    pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_IGNORED,
+		}
+		require.NoError(t, recordDelivery(env.EtcdClient, deliveries, ignored))
+		_, err = Replay(env.PachClient, env.EtcdClient, prefix, ignored.ID)
+		require.YesError(t, err)
+
+		s, err := newGitHookServer(nil, nil, "", Secrets{})
+		require.NoError(t, err)
+		original := s.parseDelivery(newDeliveryRequest(t, map[string]string{"X-Gitlab-Event": "Push Hook"}, fmt.Sprintf(gitLabPush, gitLabPublic)))
+		require.Equal(t, pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_SUCCEEDED, original.State)
+		require.NoError(t, recordDelivery(env.EtcdClient, deliveries, original))
+
+		replay, err := Replay(env.PachClient, env.EtcdClient, prefix, original.ID)
+		require.NoError(t, err)
+		require.Equal(t, pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_SUCCEEDED, replay.State, replay.Error)
+		require.Equal(t, original.ID, replay.ReplayOf)
+		require.NotEqual(t, original.ID, replay.ID)
+		require.Equal(t, []string{"repo"}, replay.Repos)
+		require.True(t, finished)
+		require.Equal(t, string(original.Payload), committed.String())
+
+		// The replay is recorded as a delivery of its own
+		result, err := ListDeliveries(context.Background(), deliveries)
+		require.NoError(t, err)
+		require.Equal(t, 3, len(result))
+		require.Equal(t, replay.ID, result[0].ID)
+		return nil
+	}))
+}
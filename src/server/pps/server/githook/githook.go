@@ -1,7 +1,8 @@
 // Package githook adds support for git-based sources in pipeline specs. It
 // does so by exposing an HTTP server that listens for webhook requests. This
-// works with github's webhook API, and anything else API-compatible with
-// their push events.
+// works with the push events of GitHub, GitLab and Bitbucket webhooks (and
+// anything else API-compatible with them). Every delivery is recorded, so
+// that failed deliveries can be inspected and replayed.
 package githook
 
 // TODO(ys): remove githook server in pachyderm 2.0
@@ -24,19 +25,73 @@ import (
 	etcd "github.com/coreos/etcd/clientv3"
 	logrus "github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
+	"gopkg.in/go-playground/webhooks.v5/bitbucket"
 	"gopkg.in/go-playground/webhooks.v5/github"
+	"gopkg.in/go-playground/webhooks.v5/gitlab"
 )
 
 // GitHookPort specifies the port the server will listen on
 const GitHookPort = 655
 const apiVersion = "v1"
 
+// Secrets are the secrets that the githook server verifies the deliveries
+// of each provider with. A provider's deliveries aren't verified if its secret
+// is empty.
+type Secrets struct {
+	// GitHub is the secret of GitHub webhooks, which GitHub signs deliveries
+	// with
+	GitHub string
+	// GitLab is the secret token of GitLab webhooks
+	GitLab string
+	// BitbucketHookUUID is the UUID of the Bitbucket webhook
+	BitbucketHookUUID string
+}
+
 // gitHookServer serves GetFile requests over HTTP
 type gitHookServer struct {
-	hook       *github.Webhook
+	github     *github.Webhook
+	gitlab     *gitlab.Webhook
+	bitbucket  *bitbucket.Webhook
 	client     *client.APIClient
 	etcdClient *etcd.Client
 	pipelines  col.Collection
+	deliveries col.Collection
+}
+
+func newGitHookServer(c *client.APIClient, etcdClient *etcd.Client, etcdPrefix string, secrets Secrets) (*gitHookServer, error) {
+	var githubOptions []github.Option
+	if secrets.GitHub != "" {
+		githubOptions = append(githubOptions, github.Options.Secret(secrets.GitHub))
+	}
+	githubHook, err := github.New(githubOptions...)
+	if err != nil {
+		return nil, err
+	}
+	var gitlabOptions []gitlab.Option
+	if secrets.GitLab != "" {
+		gitlabOptions = append(gitlabOptions, gitlab.Options.Secret(secrets.GitLab))
+	}
+	gitlabHook, err := gitlab.New(gitlabOptions...)
+	if err != nil {
+		return nil, err
+	}
+	var bitbucketOptions []bitbucket.Option
+	if secrets.BitbucketHookUUID != "" {
+		bitbucketOptions = append(bitbucketOptions, bitbucket.Options.UUID(secrets.BitbucketHookUUID))
+	}
+	bitbucketHook, err := bitbucket.New(bitbucketOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return &gitHookServer{
+		github:     githubHook,
+		gitlab:     gitlabHook,
+		bitbucket:  bitbucketHook,
+		client:     c,
+		etcdClient: etcdClient,
+		pipelines:  ppsdb.Pipelines(etcdClient, etcdPrefix),
+		deliveries: ppsdb.GitHookDeliveries(etcdClient, etcdPrefix),
+	}, nil
 }
 
 func hookPath() string {
@@ -60,7 +115,7 @@ func URLFromDomain(domain string) string {
 }
 
 // RunGitHookServer starts the webhook server
-func RunGitHookServer(address string, etcdAddress string, etcdPrefix string) error {
+func RunGitHookServer(address string, etcdAddress string, etcdPrefix string, secrets Secrets) error {
 	c, err := client.NewFromAddress(address)
 	if err != nil {
 		return err
@@ -74,16 +129,10 @@ func RunGitHookServer(address string, etcdAddress string, etcdPrefix string) err
 	if err != nil {
 		return err
 	}
-	hook, err := github.New()
+	s, err := newGitHookServer(c, etcdClient, etcdPrefix, secrets)
 	if err != nil {
 		return err
 	}
-	s := &gitHookServer{
-		hook,
-		c,
-		etcdClient,
-		ppsdb.Pipelines(etcdClient, etcdPrefix),
-	}
 	return http.ListenAndServe(fmt.Sprintf(":%d", GitHookPort), s)
 }
 
@@ -98,22 +147,39 @@ func matchingBranch(inputBranch string, payloadBranch string) bool {
 }
 
 func (s *gitHookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	payload, err := s.hook.Parse(r, github.PushEvent)
-	if err != nil {
-		// `ErrEventNotFound` implies github sent an event we didn't ask for
-		if !errors.Is(err, github.ErrEventNotFound) {
-			logrus.Errorf("error parsing github hook: %v", err)
-		}
+	delivery := s.parseDelivery(r)
+	if delivery.State == pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_FAILED {
+		logrus.Errorf("error parsing %s webhook: %v", delivery.Provider, delivery.Error)
+		s.recordDelivery(delivery)
+		http.Error(w, delivery.Error, http.StatusBadRequest)
 		return
 	}
-
-	pl, ok := payload.(github.PushPayload)
-	if !ok {
-		logrus.Errorf("github webhook failed to cast payload, this is likely a bug")
+	if delivery.State == pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_IGNORED {
+		s.recordDelivery(delivery)
+		return
 	}
+	s.handleDelivery(delivery)
+	s.recordDelivery(delivery)
+	if delivery.State == pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_FAILED {
+		http.Error(w, delivery.Error, http.StatusInternalServerError)
+	}
+}
 
-	if err = s.handlePush(pl); err != nil {
-		logrus.Errorf("github webhook failed to handle push for repo (%v) on branch (%v) with error %v", pl.Repository.Name, path.Base(pl.Ref), err)
+// handleDelivery commits the push of 'delivery' to the git inputs that it's
+// for, and records the result in 'delivery'.
+func (s *gitHookServer) handleDelivery(delivery *pps.GitHookDelivery) {
+	var pl github.PushPayload
+	if err := json.Unmarshal(delivery.Payload, &pl); err != nil {
+		delivery.State = pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_FAILED
+		delivery.Error = fmt.Sprintf("could not unmarshal payload: %v", err)
+		return
+	}
+	repos, err := s.handlePush(pl)
+	delivery.Repos = repos
+	if err != nil {
+		logrus.Errorf("%s webhook failed to handle push for repo (%v) on branch (%v) with error %v", delivery.Provider, pl.Repository.Name, path.Base(pl.Ref), err)
+		delivery.State = pps.GitHookDeliveryState_GIT_HOOK_DELIVERY_FAILED
+		delivery.Error = err.Error()
 	}
 }
 
@@ -138,27 +204,32 @@ func (s *gitHookServer) findMatchingPipelineInputs(payload github.PushPayload) (
 	return pipelines, inputs, nil
 }
 
-func (s *gitHookServer) handlePush(pl github.PushPayload) (retErr error) {
-	logrus.Infof("received github push payload for repo (%v) on branch (%v)", pl.Repository.Name, path.Base(pl.Ref))
+// handlePush commits 'pl' to the git inputs that it's for, and returns the
+// input repos that it was committed to.
+func (s *gitHookServer) handlePush(pl github.PushPayload) (repos []string, retErr error) {
+	logrus.Infof("received push payload for repo (%v) on branch (%v)", pl.Repository.Name, path.Base(pl.Ref))
 
 	raw, err := json.Marshal(pl)
 	if err != nil {
-		return errors.Wrapf(err, "error marshalling payload (%v)", pl)
+		return nil, errors.Wrapf(err, "error marshalling payload (%v)", pl)
 	}
 	pipelines, gitInputs, err := s.findMatchingPipelineInputs(pl)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if pl.Repository.Private {
 		for _, pipelineInfo := range pipelines {
-			if err := ppsutil.FailPipeline(context.Background(), s.etcdClient, s.pipelines, pipelineInfo.Pipeline.Name, fmt.Sprintf("unable to clone private github repo (%v)", pl.Repository.CloneURL)); err != nil {
+			if err := ppsutil.FailPipeline(context.Background(), s.etcdClient, s.pipelines, pipelineInfo.Pipeline.Name, fmt.Sprintf("unable to clone private git repo (%v)", pl.Repository.CloneURL)); err != nil {
 				// err will be handled but first we want to
 				// try and fail all relevant pipelines
 				logrus.Errorf("error marking pipeline %v as failed %v", pipelineInfo.Pipeline.Name, err)
 				retErr = err
 			}
 		}
-		return retErr
+		if retErr != nil {
+			return nil, retErr
+		}
+		return nil, errors.Errorf("unable to clone private git repo (%v), so its pipelines were failed", pl.Repository.CloneURL)
 	}
 	triggeredRepos := make(map[string]bool)
 	for _, input := range gitInputs {
@@ -168,13 +239,14 @@ func (s *gitHookServer) handlePush(pl github.PushPayload) (retErr error) {
 			continue
 		}
 		if err := s.commitPayload(input.Name, input.Branch, raw); err != nil {
-			logrus.Errorf("github webhook failed to commit payload to repo (%v) push with error: %v\n", input.Name, err)
+			logrus.Errorf("git webhook failed to commit payload to repo (%v) push with error: %v\n", input.Name, err)
 			retErr = err
 			continue
 		}
 		triggeredRepos[input.Name] = true
+		repos = append(repos, input.Name)
 	}
-	return nil
+	return repos, retErr
 }
 
 func (s *gitHookServer) commitPayload(repoName string, branchName string, rawPayload []byte) (retErr error) {
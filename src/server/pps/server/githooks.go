@@ -0,0 +1,185 @@
+package server
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/metrics"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsdb"
+	"github.com/pachyderm/pachyderm/src/server/pps/server/githook"
+
+	"golang.org/x/net/context"
+	kube "k8s.io/client-go/kubernetes"
+)
+
+// githookURLPending is the githook URL of pipelines whose githook service
+// doesn't have an external IP or hostname (yet)
+const githookURLPending = "pending"
+
+// githookURL returns the URL of the githook service's load balancer, which
+// webhooks must be sent to, or githookURLPending if it isn't known.
+func (a *apiServer) githookURL(kubeClient *kube.Clientset) (string, error) {
+	svc, err := getGithookService(kubeClient, a.namespace)
+	if err != nil {
+		return githookURLPending, nil
+	}
+	numIPs := len(svc.Status.LoadBalancer.Ingress)
+	if numIPs == 0 {
+		// When running locally, no external IP is set
+		return githookURLPending, nil
+	}
+	if numIPs != 1 {
+		return "", errors.Errorf("unexpected number of external IPs set for githook service")
+	}
+	ingress := svc.Status.LoadBalancer.Ingress[0]
+	if ingress.IP != "" {
+		// GKE load balancing
+		return githook.URLFromDomain(ingress.IP), nil
+	} else if ingress.Hostname != "" {
+		// AWS load balancing
+		return githook.URLFromDomain(ingress.Hostname), nil
+	}
+	return githookURLPending, nil
+}
+
+// ListGitHooks implements the protobuf pps.ListGitHooks RPC
+func (a *apiServer) ListGitHooks(ctx context.Context, request *pps.ListGitHooksRequest) (response *pps.GitHookInfos, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	metricsFn := metrics.ReportUserAction(ctx, a.reporter, "ListGitHooks")
+	defer func(start time.Time) { metricsFn(start, retErr) }(time.Now())
+
+	hooks, unmatched, err := a.listGitHooks(a.env.GetPachClient(ctx), "ListGitHooks")
+	if err != nil {
+		return nil, err
+	}
+	response = &pps.GitHookInfos{UnmatchedDeliveries: unmatched}
+	for _, hook := range hooks {
+		if len(hook.Deliveries) > 1 {
+			hook.Deliveries = hook.Deliveries[:1]
+		}
+		response.GitHookInfo = append(response.GitHookInfo, hook)
+	}
+	return response, nil
+}
+
+// InspectGitHook implements the protobuf pps.InspectGitHook RPC
+func (a *apiServer) InspectGitHook(ctx context.Context, request *pps.InspectGitHookRequest) (response *pps.GitHookInfo, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	hooks, _, err := a.listGitHooks(a.env.GetPachClient(ctx), "InspectGitHook")
+	if err != nil {
+		return nil, err
+	}
+	for _, hook := range hooks {
+		if hook.Name == request.Name {
+			return hook, nil
+		}
+	}
+	return nil, errors.Errorf("git input %q not found", request.Name)
+}
+
+// ReplayGitHookDelivery implements the protobuf pps.ReplayGitHookDelivery RPC
+func (a *apiServer) ReplayGitHookDelivery(ctx context.Context, request *pps.ReplayGitHookDeliveryRequest) (response *pps.GitHookDelivery, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	metricsFn := metrics.ReportUserAction(ctx, a.reporter, "ReplayGitHookDelivery")
+	defer func(start time.Time) { metricsFn(start, retErr) }(time.Now())
+
+	pachClient := a.env.GetPachClient(ctx)
+	if _, err := checkLoggedIn(pachClient); err != nil {
+		return nil, err
+	}
+	// The push is committed as the caller, so the caller must be able to
+	// write to the git inputs' repos
+	return githook.Replay(pachClient, a.env.GetEtcdClient(), a.etcdPrefix, request.ID)
+}
+
+// checkGitHookAdmin returns an error if auth is active and the caller of
+// 'pachClient' isn't a cluster admin. Deliveries include the payloads of every
+// webhook sent to the cluster, whichever repo they're for, so only admins may
+// list them.
+func checkGitHookAdmin(pachClient *client.APIClient, op string) error {
+	me, err := pachClient.WhoAmI(pachClient.Ctx(), &auth.WhoAmIRequest{})
+	if err != nil {
+		if auth.IsErrNotActivated(err) {
+			return nil
+		}
+		return grpcutil.ScrubGRPC(err)
+	}
+	if !me.IsAdmin {
+		return &auth.ErrNotAuthorized{Subject: me.Username, AdminOp: op}
+	}
+	return nil
+}
+
+// listGitHooks returns the git inputs of the pipelines, sorted by name, each
+// with its recent deliveries, and the recent deliveries that don't match any
+// git input. Only cluster admins may call it.
+func (a *apiServer) listGitHooks(pachClient *client.APIClient, op string) ([]*pps.GitHookInfo, []*pps.GitHookDelivery, error) {
+	if _, err := checkLoggedIn(pachClient); err != nil {
+		return nil, nil, err
+	}
+	if err := checkGitHookAdmin(pachClient, op); err != nil {
+		return nil, nil, err
+	}
+	hooks := make(map[string]*pps.GitHookInfo)
+	if err := a.listPipeline(pachClient, &pps.ListPipelineRequest{}, func(pipelineInfo *pps.PipelineInfo) error {
+		pps.VisitInput(pipelineInfo.Input, func(input *pps.Input) {
+			if input.Git == nil {
+				return
+			}
+			hook, ok := hooks[input.Git.Name]
+			if !ok {
+				hook = &pps.GitHookInfo{
+					Name:   input.Git.Name,
+					URL:    input.Git.URL,
+					Branch: input.Git.Branch,
+				}
+				hooks[input.Git.Name] = hook
+			}
+			hook.Pipelines = append(hook.Pipelines, pipelineInfo.Pipeline.Name)
+		})
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+	githookURL := githookURLPending
+	if len(hooks) > 0 {
+		var err error
+		if githookURL, err = a.githookURL(a.env.GetKubeClient()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	deliveries, err := githook.ListDeliveries(pachClient.Ctx(), ppsdb.GitHookDeliveries(a.env.GetEtcdClient(), a.etcdPrefix))
+	if err != nil {
+		return nil, nil, err
+	}
+	var result []*pps.GitHookInfo
+	for _, hook := range hooks {
+		hook.GithookURL = githookURL
+		result = append(result, hook)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	var unmatched []*pps.GitHookDelivery
+	for _, delivery := range deliveries {
+		var matched bool
+		for _, hook := range result {
+			if hook.URL == delivery.URL && hook.Branch == delivery.Branch {
+				hook.Deliveries = append(hook.Deliveries, delivery)
+				matched = true
+			}
+		}
+		if !matched {
+			unmatched = append(unmatched, delivery)
+		}
+	}
+	return result, unmatched, nil
+}
@@ -0,0 +1,23 @@
+package server
+
+import (
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// validateNoGroupFiles checks that none of the inputs under the join or
+// group input 'inputs' (named by 'kind') group their files. Joins and groups
+// match up the datums of their inputs one file at a time, by each file's
+// join_on or group_by, which a datum of several grouped files doesn't have.
+func validateNoGroupFiles(kind string, inputs []*pps.Input) error {
+	var result error
+	for _, input := range inputs {
+		pps.VisitInput(input, func(input *pps.Input) {
+			if result == nil && input.Pfs != nil && input.Pfs.GroupFiles != nil {
+				result = errors.Errorf("input %q cannot set 'group_files', as it's "+
+					"part of a %s input", input.Pfs.Name, kind)
+			}
+		})
+	}
+	return result
+}
@@ -0,0 +1,325 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// mirrorEntry holds the cache validators returned by the server the last time
+// a mirrored URL was fetched.
+type mirrorEntry struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// mirrorState is stored (as JSON) in the description of each commit made by an
+// http_mirror input, so that the validators survive pachd restarts.
+type mirrorState struct {
+	URLs map[string]*mirrorEntry `json:"urls"`
+}
+
+// readMirrorState extracts the mirrorState from the description of the most
+// recent commit to an http_mirror input's repo. An empty state is returned if
+// 'commitInfo' is nil or its description can't be parsed, in which case every
+// URL is fetched again.
+func readMirrorState(commitInfo *pfs.CommitInfo) *mirrorState {
+	state := &mirrorState{URLs: make(map[string]*mirrorEntry)}
+	if commitInfo == nil || commitInfo.Description == "" {
+		return state
+	}
+	if err := json.Unmarshal([]byte(commitInfo.Description), state); err != nil || state.URLs == nil {
+		return &mirrorState{URLs: make(map[string]*mirrorEntry)}
+	}
+	return state
+}
+
+func (s *mirrorState) String() string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		// can't happen, mirrorState only contains strings
+		panic(err)
+	}
+	return string(data)
+}
+
+// mirrorFileName returns the name of the file that the content at 'rawURL' is
+// written to in an http_mirror input's repo.
+func mirrorFileName(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not parse mirror url %q", rawURL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", errors.Errorf("mirror url %q must use http or https", rawURL)
+	}
+	if u.Host == "" {
+		return "", errors.Errorf("mirror url %q is missing a host", rawURL)
+	}
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		name = u.Host
+	}
+	return name, nil
+}
+
+// validateMirrorURLs checks that the static URLs in an http_mirror input are
+// valid, and that no two of them would be written to the same file.
+func validateMirrorURLs(input *pps.HTTPMirrorInput) error {
+	names := make(map[string]string)
+	for _, u := range input.URLs {
+		name, err := mirrorFileName(u)
+		if err != nil {
+			return err
+		}
+		if other, ok := names[name]; ok {
+			return errors.Errorf("mirror urls %q and %q would both be written to %q", other, u, name)
+		}
+		names[name] = u
+	}
+	if input.Manifest != "" {
+		if _, err := mirrorFileName(input.Manifest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const (
+	// mirrorDialTimeout bounds how long an http_mirror input waits to connect
+	// to a server, and mirrorHeaderTimeout how long it waits for the server's
+	// response headers once connected.
+	mirrorDialTimeout   = 30 * time.Second
+	mirrorHeaderTimeout = time.Minute
+	// mirrorFetchTimeout bounds the whole of a single fetch, including reading
+	// the body, so that a server that stops sending can't block an input
+	// forever.
+	mirrorFetchTimeout = time.Hour
+)
+
+// parseMirrorAllowedNetworks parses HTTP_MIRROR_ALLOWED_NETWORKS, a
+// comma-separated list of CIDRs that http_mirror inputs may fetch from even
+// though they're private (see newMirrorClient).
+func parseMirrorAllowedNetworks(value string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, cidr := range strings.Split(value, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse http mirror allowed network %q", cidr)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// checkMirrorIP returns an error if an http_mirror input may not connect to
+// 'ip': loopback, link-local (which includes cloud metadata endpoints),
+// private and unspecified addresses are rejected unless they're in one of the
+// 'allowed' networks, so that pipeline creators can't use pachd to read
+// services inside the cluster.
+func checkMirrorIP(ip net.IP, allowed []*net.IPNet) error {
+	for _, network := range allowed {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() || isPrivateIP(ip) {
+		return errors.Errorf("http mirror may not connect to non-public address %s", ip)
+	}
+	return nil
+}
+
+var privateNetworks = func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range []string{
+		"10.0.0.0/8",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"100.64.0.0/10", // carrier-grade NAT
+		"fc00::/7",      // unique local addresses
+	} {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+func isPrivateIP(ip net.IP) bool {
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// newMirrorClient returns the HTTP client that http_mirror inputs fetch URLs
+// with. The address of every connection it makes (including those made to
+// follow redirects) is checked with checkMirrorIP after DNS resolution, and it
+// ignores proxy environment variables so that the check applies to the server
+// actually being fetched from.
+func newMirrorClient(allowed []*net.IPNet) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: mirrorDialTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return errors.EnsureStack(err)
+			}
+			ip := net.ParseIP(host)
+			if ip == nil {
+				return errors.Errorf("http mirror could not parse address %q", address)
+			}
+			return checkMirrorIP(ip, allowed)
+		},
+	}
+	return &http.Client{
+		Timeout: mirrorFetchTimeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   mirrorDialTimeout,
+			ResponseHeaderTimeout: mirrorHeaderTimeout,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+		},
+	}
+}
+
+// mirrorURLs returns the full list of URLs mirrored by 'input', reading its
+// manifest (if it has one) with 'client'.
+func mirrorURLs(ctx context.Context, client *http.Client, input *pps.HTTPMirrorInput) ([]string, error) {
+	urls := append([]string{}, input.URLs...)
+	if input.Manifest == "" {
+		return urls, nil
+	}
+	req, err := http.NewRequest("GET", input.Manifest, nil)
+	if err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not fetch mirror manifest")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("could not fetch mirror manifest %q: %s", input.Manifest, resp.Status)
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "could not read mirror manifest")
+	}
+	return urls, nil
+}
+
+// fetchMirrorURL fetches 'rawURL' with 'client', sending the validators in
+// 'prev' (if any) so that the server can skip sending content that hasn't
+// changed. If the content hasn't changed, the returned body is nil.
+func fetchMirrorURL(ctx context.Context, client *http.Client, rawURL string, prev *mirrorEntry) (io.ReadCloser, *mirrorEntry, error) {
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return nil, nil, errors.EnsureStack(err)
+	}
+	if prev != nil {
+		if prev.ETag != "" {
+			req.Header.Set("If-None-Match", prev.ETag)
+		}
+		if prev.LastModified != "" {
+			req.Header.Set("If-Modified-Since", prev.LastModified)
+		}
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "could not fetch %q", rawURL)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, &mirrorEntry{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		}, nil
+	case http.StatusNotModified:
+		resp.Body.Close()
+		return nil, prev, nil
+	default:
+		resp.Body.Close()
+		return nil, nil, errors.Errorf("could not fetch %q: %s", rawURL, resp.Status)
+	}
+}
+
+// mirrorThrottle limits the rate at which an http_mirror input downloads
+// content. A single mirrorThrottle is shared by every download in a tick, so
+// the limit applies to the input as a whole rather than to each URL.
+type mirrorThrottle struct {
+	ctx            context.Context
+	bytesPerSecond int64
+	start          time.Time
+	read           int64
+}
+
+func newMirrorThrottle(ctx context.Context, bytesPerSecond int64) *mirrorThrottle {
+	return &mirrorThrottle{
+		ctx:            ctx,
+		bytesPerSecond: bytesPerSecond,
+		start:          time.Now(),
+	}
+}
+
+// Reader wraps 'r' so that reads from it count against the throttle's limit.
+// If the throttle has no limit, 'r' is returned unchanged.
+func (t *mirrorThrottle) Reader(r io.Reader) io.Reader {
+	if t.bytesPerSecond <= 0 {
+		return r
+	}
+	return &throttledReader{r: r, t: t}
+}
+
+type throttledReader struct {
+	r io.Reader
+	t *mirrorThrottle
+}
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > r.t.bytesPerSecond {
+		p = p[:r.t.bytesPerSecond]
+	}
+	n, err := r.r.Read(p)
+	r.t.read += int64(n)
+	// Sleep until the average rate since the start of the tick is back under
+	// the limit
+	expected := time.Duration(float64(r.t.read) / float64(r.t.bytesPerSecond) * float64(time.Second))
+	if wait := expected - time.Since(r.t.start); wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-r.t.ctx.Done():
+			return n, r.t.ctx.Err()
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestMirrorFileName(t *testing.T) {
+	name, err := mirrorFileName("https://example.com/data/reference.csv")
+	require.NoError(t, err)
+	require.Equal(t, "reference.csv", name)
+
+	name, err = mirrorFileName("https://example.com/")
+	require.NoError(t, err)
+	require.Equal(t, "example.com", name)
+
+	_, err = mirrorFileName("ftp://example.com/reference.csv")
+	require.YesError(t, err)
+	_, err = mirrorFileName("/reference.csv")
+	require.YesError(t, err)
+}
+
+func TestValidateMirrorURLs(t *testing.T) {
+	require.NoError(t, validateMirrorURLs(&pps.HTTPMirrorInput{
+		URLs: []string{"https://example.com/a.csv", "https://example.com/b.csv"},
+	}))
+	require.YesError(t, validateMirrorURLs(&pps.HTTPMirrorInput{
+		URLs: []string{"https://example.com/a.csv", "https://mirror.example.com/a.csv"},
+	}))
+	require.YesError(t, validateMirrorURLs(&pps.HTTPMirrorInput{
+		Manifest: "file:///manifest",
+	}))
+}
+
+// testMirrorClient returns a mirror client that may connect to httptest
+// servers, which listen on loopback addresses.
+func testMirrorClient(t *testing.T) *http.Client {
+	allowed, err := parseMirrorAllowedNetworks("127.0.0.0/8, ::1/128")
+	require.NoError(t, err)
+	return newMirrorClient(allowed)
+}
+
+func TestCheckMirrorIP(t *testing.T) {
+	for _, addr := range []string{
+		"127.0.0.1", "::1", "169.254.169.254", "fe80::1", "10.1.2.3",
+		"172.16.0.1", "192.168.1.1", "100.64.0.1", "fd00::1", "0.0.0.0",
+	} {
+		require.YesError(t, checkMirrorIP(net.ParseIP(addr), nil), addr)
+	}
+	require.NoError(t, checkMirrorIP(net.ParseIP("93.184.216.34"), nil))
+	require.NoError(t, checkMirrorIP(net.ParseIP("2606:2800:220:1::1"), nil))
+
+	allowed, err := parseMirrorAllowedNetworks("10.1.0.0/16")
+	require.NoError(t, err)
+	require.NoError(t, checkMirrorIP(net.ParseIP("10.1.2.3"), allowed))
+	require.YesError(t, checkMirrorIP(net.ParseIP("10.2.0.1"), allowed))
+
+	_, err = parseMirrorAllowedNetworks("10.1.0.0")
+	require.YesError(t, err)
+}
+
+func TestFetchMirrorURLRejectsPrivateAddresses(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	_, _, err := fetchMirrorURL(context.Background(), newMirrorClient(nil), server.URL+"/file", nil)
+	require.YesError(t, err)
+	_, err = mirrorURLs(context.Background(), newMirrorClient(nil), &pps.HTTPMirrorInput{
+		Manifest: server.URL,
+	})
+	require.YesError(t, err)
+	require.Equal(t, 0, requests)
+}
+
+func TestFetchMirrorURLHonorsETag(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("data"))
+	}))
+	defer server.Close()
+
+	body, entry, err := fetchMirrorURL(context.Background(), testMirrorClient(t), server.URL+"/file", nil)
+	require.NoError(t, err)
+	require.NotNil(t, body)
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	require.Equal(t, "data", string(data))
+	require.Equal(t, `"v1"`, entry.ETag)
+
+	body, entry2, err := fetchMirrorURL(context.Background(), testMirrorClient(t), server.URL+"/file", entry)
+	require.NoError(t, err)
+	require.Nil(t, body)
+	require.Equal(t, entry, entry2)
+	require.Equal(t, 2, requests)
+}
+
+func TestMirrorURLsReadsManifest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("https://example.com/b.csv\n\n# comment\nhttps://example.com/c.csv\n"))
+	}))
+	defer server.Close()
+
+	urls, err := mirrorURLs(context.Background(), testMirrorClient(t), &pps.HTTPMirrorInput{
+		URLs:     []string{"https://example.com/a.csv"},
+		Manifest: server.URL,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"https://example.com/a.csv",
+		"https://example.com/b.csv",
+		"https://example.com/c.csv",
+	}, urls)
+}
+
+func TestMirrorStateRoundTrip(t *testing.T) {
+	state := readMirrorState(nil)
+	state.URLs["https://example.com/a.csv"] = &mirrorEntry{ETag: `"v1"`}
+	state2 := readMirrorState(&pfs.CommitInfo{Description: state.String()})
+	require.Equal(t, state, state2)
+
+	// unparseable descriptions are treated as empty state
+	require.Equal(t, 0, len(readMirrorState(&pfs.CommitInfo{Description: "hi"}).URLs))
+}
+
+func TestMirrorThrottle(t *testing.T) {
+	throttle := newMirrorThrottle(context.Background(), 1000)
+	start := time.Now()
+	data, err := ioutil.ReadAll(throttle.Reader(strings.NewReader(strings.Repeat("a", 1500))))
+	require.NoError(t, err)
+	require.Equal(t, 1500, len(data))
+	require.True(t, time.Since(start) >= time.Second)
+}
@@ -0,0 +1,236 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// idleReapInterval is how often the PPS master checks for idle pipelines
+const idleReapInterval = time.Hour
+
+// parseIdleAction parses the cluster's default idle action (PPS_IDLE_ACTION)
+func parseIdleAction(action string) (pps.IdleAction, error) {
+	switch strings.ToLower(action) {
+	case "", "flag":
+		return pps.IdleAction_IDLE_FLAG, nil
+	case "stop":
+		return pps.IdleAction_IDLE_STOP, nil
+	default:
+		return 0, errors.Errorf("invalid idle action %q (must be \"flag\" or \"stop\")", action)
+	}
+}
+
+// defaultIdlePolicy returns the cluster's default idle timeout and action. A
+// timeout of 0 means that pipelines are only reaped if their own IdlePolicy
+// sets a timeout.
+func (a *apiServer) defaultIdlePolicy() (time.Duration, pps.IdleAction, error) {
+	timeout, err := time.ParseDuration(a.env.PPSIdleTimeout)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "invalid PPS_IDLE_TIMEOUT %q", a.env.PPSIdleTimeout)
+	}
+	action, err := parseIdleAction(a.env.PPSIdleAction)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "invalid PPS_IDLE_ACTION")
+	}
+	return timeout, action, nil
+}
+
+// idlePipeline applies the cluster's defaults to the idle policy of
+// 'pipelineInfo', and returns when the idle reaper will act on the pipeline
+// (given that it was last active at 'lastActivity'). It returns nil if the
+// pipeline is never reaped: services and spouts are meant to run
+// indefinitely, and stopped pipelines already have no workers.
+func idlePipeline(pipelineInfo *pps.PipelineInfo, lastActivity time.Time, defaultTimeout time.Duration, defaultAction pps.IdleAction) (*pps.IdlePipeline, error) {
+	if pipelineInfo.Service != nil || pipelineInfo.Spout != nil || pipelineInfo.Stopped {
+		return nil, nil
+	}
+	timeout, action := defaultTimeout, defaultAction
+	if policy := pipelineInfo.IdlePolicy; policy != nil {
+		if policy.Timeout != nil {
+			var err error
+			if timeout, err = types.DurationFromProto(policy.Timeout); err != nil {
+				return nil, err
+			}
+		}
+		if policy.Action != pps.IdleAction_IDLE_DEFAULT {
+			action = policy.Action
+		}
+	}
+	if timeout <= 0 || action == pps.IdleAction_IDLE_IGNORE {
+		return nil, nil
+	}
+	lastActivityProto, err := types.TimestampProto(lastActivity)
+	if err != nil {
+		return nil, err
+	}
+	reapAt, err := types.TimestampProto(lastActivity.Add(timeout))
+	if err != nil {
+		return nil, err
+	}
+	return &pps.IdlePipeline{
+		Pipeline:     pipelineInfo.Pipeline,
+		LastActivity: lastActivityProto,
+		Timeout:      types.DurationProto(timeout),
+		Action:       action,
+		ReapAt:       reapAt,
+		IdleSince:    pipelineInfo.IdleSince,
+	}, nil
+}
+
+// lastActivity returns the last time that 'pipelineInfo' was created,
+// updated, started, ran a job or saw an input commit. Every input commit
+// creates an output commit (and a job), so the start time of the head of the
+// pipeline's output branch covers both of the latter.
+func lastActivity(pachClient *client.APIClient, pipelineInfo *pps.PipelineInfo) (time.Time, error) {
+	var result time.Time
+	specCommitInfo, err := pachClient.InspectCommit(ppsconsts.SpecRepo, pipelineInfo.SpecCommit.ID)
+	if err != nil {
+		return result, err
+	}
+	if result, err = types.TimestampFromProto(specCommitInfo.Started); err != nil {
+		return result, err
+	}
+	branchInfo, err := pachClient.InspectBranch(pipelineInfo.Pipeline.Name, ppsutil.JobOutputBranch(pipelineInfo))
+	if err != nil {
+		return result, err
+	}
+	if branchInfo.Head == nil {
+		return result, nil
+	}
+	outputCommitInfo, err := pachClient.InspectCommit(pipelineInfo.Pipeline.Name, branchInfo.Head.ID)
+	if err != nil {
+		return result, err
+	}
+	started, err := types.TimestampFromProto(outputCommitInfo.Started)
+	if err != nil {
+		return result, err
+	}
+	if started.After(result) {
+		result = started
+	}
+	return result, nil
+}
+
+// idlePipelines returns the idle reaper's plan for each of the pipelines
+// that 'pachClient' can list (and that may be reaped), whether or not they're
+// currently idle.
+func (a *apiServer) idlePipelines(pachClient *client.APIClient) ([]*pps.IdlePipeline, error) {
+	defaultTimeout, defaultAction, err := a.defaultIdlePolicy()
+	if err != nil {
+		return nil, err
+	}
+	var result []*pps.IdlePipeline
+	if err := a.listPipeline(pachClient, &pps.ListPipelineRequest{}, func(pipelineInfo *pps.PipelineInfo) error {
+		if pipelineInfo.State == pps.PipelineState_PIPELINE_PAUSED || pipelineInfo.State == pps.PipelineState_PIPELINE_FAILURE {
+			return nil
+		}
+		last, err := lastActivity(pachClient, pipelineInfo)
+		if err != nil {
+			return errors.Wrapf(err, "could not get last activity of pipeline %q", pipelineInfo.Pipeline.Name)
+		}
+		idle, err := idlePipeline(pipelineInfo, last, defaultTimeout, defaultAction)
+		if err != nil {
+			return err
+		}
+		if idle != nil {
+			result = append(result, idle)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// isReapable returns true if the idle reaper acts on 'idle' at or before 'at'
+func isReapable(idle *pps.IdlePipeline, at time.Time) bool {
+	reapAt, err := types.TimestampFromProto(idle.ReapAt)
+	return err == nil && !reapAt.After(at)
+}
+
+// setIdleSince sets (or, if 'since' is nil, clears) the time at which the
+// idle reaper flagged 'pipeline' as idle
+func (a *apiServer) setIdleSince(ctx context.Context, pipeline string, since *types.Timestamp) error {
+	_, err := col.NewSTM(ctx, a.env.GetEtcdClient(), func(stm col.STM) error {
+		pipelinePtr := &pps.EtcdPipelineInfo{}
+		return a.pipelines.ReadWrite(stm).Update(pipeline, pipelinePtr, func() error {
+			pipelinePtr.IdleSince = since
+			return nil
+		})
+	})
+	return err
+}
+
+// reapIdlePipelines flags or stops each pipeline that has been idle for
+// longer than its idle timeout, and clears the flag of pipelines that have
+// been active since they were flagged.
+func (a *apiServer) reapIdlePipelines(pachClient *client.APIClient) error {
+	return a.sudo(pachClient, func(superUserClient *client.APIClient) error {
+		idlePipelines, err := a.idlePipelines(superUserClient)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, idle := range idlePipelines {
+			pipeline := idle.Pipeline.Name
+			if !isReapable(idle, now) {
+				if idle.IdleSince != nil {
+					if err := a.setIdleSince(superUserClient.Ctx(), pipeline, nil); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if idle.IdleSince != nil {
+				continue // already flagged
+			}
+			last, err := types.TimestampFromProto(idle.LastActivity)
+			if err != nil {
+				return err
+			}
+			if idle.Action == pps.IdleAction_IDLE_STOP {
+				log.Infof("PPS master: stopping pipeline %q, which has been idle since %s",
+					pipeline, last.Format(time.RFC3339))
+				if err := superUserClient.StopPipeline(pipeline); err != nil {
+					return errors.Wrapf(err, "could not stop idle pipeline %q", pipeline)
+				}
+			} else {
+				log.Infof("PPS master: flagging pipeline %q, which has been idle since %s",
+					pipeline, last.Format(time.RFC3339))
+			}
+			if err := a.setIdleSince(superUserClient.Ctx(), pipeline, types.TimestampNow()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// runIdleReaper periodically reaps idle pipelines until 'ctx' is cancelled.
+// It runs in the PPS master.
+func (a *apiServer) runIdleReaper(ctx context.Context, pachClient *client.APIClient) {
+	ticker := time.NewTicker(idleReapInterval)
+	defer ticker.Stop()
+	for {
+		if err := a.reapIdlePipelines(pachClient); err != nil {
+			log.Errorf("PPS master: could not reap idle pipelines: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
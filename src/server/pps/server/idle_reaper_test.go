@@ -0,0 +1,62 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestParseIdleAction(t *testing.T) {
+	action, err := parseIdleAction("flag")
+	require.NoError(t, err)
+	require.Equal(t, pps.IdleAction_IDLE_FLAG, action)
+	action, err = parseIdleAction("Stop")
+	require.NoError(t, err)
+	require.Equal(t, pps.IdleAction_IDLE_STOP, action)
+	_, err = parseIdleAction("delete")
+	require.YesError(t, err)
+}
+
+func TestIdlePipeline(t *testing.T) {
+	last := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	pipelineInfo := &pps.PipelineInfo{Pipeline: client.NewPipeline("p")}
+
+	// The cluster's default policy is disabled
+	idle, err := idlePipeline(pipelineInfo, last, 0, pps.IdleAction_IDLE_FLAG)
+	require.NoError(t, err)
+	require.Nil(t, idle)
+
+	idle, err = idlePipeline(pipelineInfo, last, 24*time.Hour, pps.IdleAction_IDLE_FLAG)
+	require.NoError(t, err)
+	require.Equal(t, pps.IdleAction_IDLE_FLAG, idle.Action)
+	require.False(t, isReapable(idle, last.Add(23*time.Hour)))
+	require.True(t, isReapable(idle, last.Add(24*time.Hour)))
+
+	// The pipeline's policy overrides the cluster's
+	pipelineInfo.IdlePolicy = &pps.IdlePolicy{
+		Timeout: types.DurationProto(48 * time.Hour),
+		Action:  pps.IdleAction_IDLE_STOP,
+	}
+	idle, err = idlePipeline(pipelineInfo, last, 0, pps.IdleAction_IDLE_FLAG)
+	require.NoError(t, err)
+	require.Equal(t, pps.IdleAction_IDLE_STOP, idle.Action)
+	require.False(t, isReapable(idle, last.Add(24*time.Hour)))
+	require.True(t, isReapable(idle, last.Add(48*time.Hour)))
+
+	pipelineInfo.IdlePolicy = &pps.IdlePolicy{Action: pps.IdleAction_IDLE_IGNORE}
+	idle, err = idlePipeline(pipelineInfo, last, 24*time.Hour, pps.IdleAction_IDLE_STOP)
+	require.NoError(t, err)
+	require.Nil(t, idle)
+
+	// Services are never idle
+	pipelineInfo.IdlePolicy = nil
+	pipelineInfo.Service = &pps.Service{}
+	idle, err = idlePipeline(pipelineInfo, last, 24*time.Hour, pps.IdleAction_IDLE_STOP)
+	require.NoError(t, err)
+	require.Nil(t, idle)
+}
@@ -0,0 +1,259 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube "k8s.io/client-go/kubernetes"
+)
+
+const (
+	dockerHubRegistry = "registry-1.docker.io"
+	// dockerHubAuthKey is the key that docker uses for Docker Hub credentials
+	// in .dockerconfigjson
+	dockerHubAuthKey = "https://index.docker.io/v1/"
+)
+
+// manifestMediaTypes are the manifest types that pachd accepts when resolving
+// an image's digest. Manifest lists come first, so that a multi-arch image
+// resolves to the digest of its manifest list (which is what 'docker pull'
+// records too).
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+// challengeParam matches a key="value" parameter of a WWW-Authenticate header
+// (values, e.g. scopes, may contain commas, so the header can't just be split)
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// imageRef is a parsed docker image reference of the form
+// [registry/]repository[:tag][@digest]
+type imageRef struct {
+	// name is the reference without its tag or digest, as the user wrote it
+	// (e.g. "ubuntu" rather than "registry-1.docker.io/library/ubuntu")
+	name       string
+	registry   string
+	repository string
+	tag        string
+	digest     string
+}
+
+func parseImageRef(image string) (*imageRef, error) {
+	if image == "" {
+		return nil, errors.New("image cannot be empty")
+	}
+	ref := &imageRef{name: image}
+	if i := strings.Index(ref.name, "@"); i >= 0 {
+		ref.name, ref.digest = ref.name[:i], ref.name[i+1:]
+	}
+	// A ':' after the last '/' separates the tag (a ':' before it is a
+	// registry port)
+	if i := strings.LastIndex(ref.name, ":"); i > strings.LastIndex(ref.name, "/") {
+		ref.name, ref.tag = ref.name[:i], ref.name[i+1:]
+	}
+	if ref.tag == "" {
+		ref.tag = "latest"
+	}
+	// The first component is a registry if it looks like a hostname
+	parts := strings.SplitN(ref.name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		ref.registry, ref.repository = parts[0], parts[1]
+	} else {
+		ref.registry, ref.repository = dockerHubRegistry, ref.name
+		if len(parts) == 1 {
+			ref.repository = "library/" + ref.name
+		}
+	}
+	if ref.repository == "" {
+		return nil, errors.Errorf("invalid image %q", image)
+	}
+	return ref, nil
+}
+
+// registryCredentials are the (optional) credentials used to resolve an
+// image's digest.
+type registryCredentials struct {
+	username, password string
+}
+
+// imagePullCredentials looks for credentials for 'registry' in the
+// .dockerconfigjson of each of the image pull secrets in 'secrets', and
+// returns the first ones that it finds (or nil, if none of the secrets have
+// credentials for 'registry').
+func imagePullCredentials(kubeClient kube.Interface, namespace string, registry string, secrets []string) (*registryCredentials, error) {
+	key := registry
+	if registry == dockerHubRegistry {
+		key = dockerHubAuthKey
+	}
+	for _, name := range secrets {
+		secret, err := kubeClient.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not get image pull secret %q", name)
+		}
+		var config struct {
+			Auths map[string]struct {
+				Username string `json:"username"`
+				Password string `json:"password"`
+				Auth     string `json:"auth"`
+			} `json:"auths"`
+		}
+		if err := json.Unmarshal(secret.Data[".dockerconfigjson"], &config); err != nil {
+			continue // not a docker config secret
+		}
+		auth, ok := config.Auths[key]
+		if !ok {
+			continue
+		}
+		if auth.Username == "" && auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not decode credentials in image pull secret %q", name)
+			}
+			parts := strings.SplitN(string(decoded), ":", 2)
+			if len(parts) != 2 {
+				return nil, errors.Errorf("malformed credentials in image pull secret %q", name)
+			}
+			auth.Username, auth.Password = parts[0], parts[1]
+		}
+		return &registryCredentials{username: auth.Username, password: auth.Password}, nil
+	}
+	return nil, nil
+}
+
+// resolveImageDigest asks the registry that hosts 'image' which digest the
+// image's tag currently points to, and returns a digest-qualified reference to
+// the image (e.g. "ubuntu@sha256:..."). Images that already include a digest
+// are returned unchanged.
+func resolveImageDigest(ctx context.Context, image string, creds *registryCredentials) (string, error) {
+	ref, err := parseImageRef(image)
+	if err != nil {
+		return "", err
+	}
+	if ref.digest != "" {
+		return image, nil
+	}
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", ref.registry, ref.repository, ref.tag)
+	resp, err := headManifest(ctx, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		// The registry requires a bearer token, even for anonymous pulls
+		token, err := registryToken(ctx, resp.Header.Get("WWW-Authenticate"), creds)
+		if err != nil {
+			return "", errors.Wrapf(err, "could not authenticate with %s", ref.registry)
+		}
+		if resp, err = headManifest(ctx, manifestURL, token); err != nil {
+			return "", err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("could not resolve digest of %q: %s", image, resp.Status)
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", errors.Errorf("registry %s did not return a digest for %q", ref.registry, image)
+	}
+	return ref.name + "@" + digest, nil
+}
+
+func headManifest(ctx context.Context, manifestURL string, token string) (*http.Response, error) {
+	req, err := http.NewRequest("HEAD", manifestURL, nil)
+	if err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not reach image registry")
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// registryToken gets a bearer token from the token server described by the
+// registry's WWW-Authenticate 'challenge', using 'creds' if they're set.
+func registryToken(ctx context.Context, challenge string, creds *registryCredentials) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", errors.Errorf("unsupported auth challenge %q", challenge)
+	}
+	params := make(map[string]string)
+	for _, match := range challengeParam.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	if params["realm"] == "" {
+		return "", errors.Errorf("auth challenge %q has no realm", challenge)
+	}
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid realm in auth challenge")
+	}
+	q := tokenURL.Query()
+	for _, k := range []string{"service", "scope"} {
+		if params[k] != "" {
+			q.Set(k, params[k])
+		}
+	}
+	tokenURL.RawQuery = q.Encode()
+	req, err := http.NewRequest("GET", tokenURL.String(), nil)
+	if err != nil {
+		return "", errors.EnsureStack(err)
+	}
+	if creds != nil {
+		req.SetBasicAuth(creds.username, creds.password)
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", errors.EnsureStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("token request failed: %s", resp.Status)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", errors.Wrapf(err, "could not decode token response")
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// resolvePipelineImage resolves the digest of the image run by 'transform',
+// authenticating with the image's registry using the transform's image pull
+// secrets (or the cluster's default one, 'imagePullSecret').
+func resolvePipelineImage(ctx context.Context, kubeClient kube.Interface, namespace string, imagePullSecret string, transform *pps.Transform) (string, error) {
+	ref, err := parseImageRef(transform.Image)
+	if err != nil {
+		return "", err
+	}
+	secrets := append([]string{}, transform.ImagePullSecrets...)
+	if imagePullSecret != "" {
+		secrets = append(secrets, imagePullSecret)
+	}
+	creds, err := imagePullCredentials(kubeClient, namespace, ref.registry, secrets)
+	if err != nil {
+		return "", err
+	}
+	return resolveImageDigest(ctx, transform.Image, creds)
+}
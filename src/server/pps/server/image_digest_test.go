@@ -0,0 +1,40 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestParseImageRef(t *testing.T) {
+	ref, err := parseImageRef("ubuntu")
+	require.NoError(t, err)
+	require.Equal(t, &imageRef{
+		name:       "ubuntu",
+		registry:   dockerHubRegistry,
+		repository: "library/ubuntu",
+		tag:        "latest",
+	}, ref)
+
+	ref, err = parseImageRef("pachyderm/opencv:1.0")
+	require.NoError(t, err)
+	require.Equal(t, &imageRef{
+		name:       "pachyderm/opencv",
+		registry:   dockerHubRegistry,
+		repository: "pachyderm/opencv",
+		tag:        "1.0",
+	}, ref)
+
+	ref, err = parseImageRef("localhost:5000/team/app:v2@sha256:abc")
+	require.NoError(t, err)
+	require.Equal(t, &imageRef{
+		name:       "localhost:5000/team/app",
+		registry:   "localhost:5000",
+		repository: "team/app",
+		tag:        "v2",
+		digest:     "sha256:abc",
+	}, ref)
+
+	_, err = parseImageRef("")
+	require.YesError(t, err)
+}
@@ -0,0 +1,288 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+
+	log "github.com/sirupsen/logrus"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// prewarmerName is the name of the DaemonSet that pulls pipeline images
+	// onto every node
+	prewarmerName = "pachyderm-image-prewarmer"
+	// prewarmInterval is how often the PPS master updates the DaemonSet with
+	// the images of active pipelines
+	prewarmInterval = time.Minute
+)
+
+// pipelineImage returns the image that the workers of 'pipelineInfo' run
+func pipelineImage(pipelineInfo *pps.PipelineInfo) string {
+	if pipelineInfo.ImageDigest != "" {
+		return pipelineInfo.ImageDigest
+	}
+	if pipelineInfo.Transform.GetImage() != "" {
+		return pipelineInfo.Transform.Image
+	}
+	return DefaultUserImage
+}
+
+// prewarmImages returns the (sorted, de-duplicated) images of
+// 'pipelineInfos', and the image pull secrets needed to pull them
+func prewarmImages(pipelineInfos []*pps.PipelineInfo) ([]string, []string) {
+	images := make(map[string]bool)
+	secrets := make(map[string]bool)
+	for _, pipelineInfo := range pipelineInfos {
+		images[pipelineImage(pipelineInfo)] = true
+		for _, secret := range pipelineInfo.Transform.GetImagePullSecrets() {
+			secrets[secret] = true
+		}
+	}
+	sortedKeys := func(m map[string]bool) []string {
+		var result []string
+		for k := range m {
+			result = append(result, k)
+		}
+		sort.Strings(result)
+		return result
+	}
+	return sortedKeys(images), sortedKeys(secrets)
+}
+
+// prewarmerDaemonSet returns the DaemonSet that pulls 'images' onto every
+// node. Its pods pull each image by running it as an init container, which
+// runs the init binary that the worker image's init container copies into
+// /pach-bin (the images may not contain any other binary), and then idle.
+func (a *apiServer) prewarmerDaemonSet(images []string, secrets []string) *apps.DaemonSet {
+	labels := map[string]string{
+		"app":       prewarmerName,
+		"suite":     suite,
+		"component": "image-prewarmer",
+	}
+	pullPolicy := a.workerImagePullPolicy
+	if pullPolicy == "" {
+		pullPolicy = "IfNotPresent"
+	}
+	volumeMounts := []v1.VolumeMount{{Name: "pach-bin", MountPath: "/pach-bin"}}
+	// As for workers, explicitly set CPU requests to zero because some
+	// cloud providers set their own defaults
+	requests := v1.ResourceRequirements{
+		Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("0"),
+			v1.ResourceMemory: resource.MustParse("16M"),
+		},
+	}
+	initContainers := []v1.Container{{
+		Name:            "init",
+		Image:           a.workerImage,
+		Command:         []string{"/app/init"},
+		ImagePullPolicy: v1.PullPolicy(pullPolicy),
+		VolumeMounts:    volumeMounts,
+		Resources:       requests,
+	}}
+	for i, image := range images {
+		initContainers = append(initContainers, v1.Container{
+			Name:            fmt.Sprintf("prewarm-%d", i),
+			Image:           image,
+			Command:         []string{"/pach-bin/init", "prewarm"},
+			ImagePullPolicy: v1.PullIfNotPresent,
+			VolumeMounts:    volumeMounts,
+			Resources:       requests,
+		})
+	}
+	var pullSecrets []v1.LocalObjectReference
+	for _, secret := range secrets {
+		pullSecrets = append(pullSecrets, v1.LocalObjectReference{Name: secret})
+	}
+	if a.imagePullSecret != "" {
+		pullSecrets = append(pullSecrets, v1.LocalObjectReference{Name: a.imagePullSecret})
+	}
+	return &apps.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DaemonSet",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   prewarmerName,
+			Labels: labels,
+		},
+		Spec: apps.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   prewarmerName,
+					Labels: labels,
+				},
+				Spec: v1.PodSpec{
+					InitContainers: initContainers,
+					Containers: []v1.Container{{
+						Name:            "pause",
+						Image:           a.workerImage,
+						Command:         []string{"/app/init", "pause"},
+						ImagePullPolicy: v1.PullPolicy(pullPolicy),
+						Resources:       requests,
+					}},
+					Volumes: []v1.Volume{{
+						Name:         "pach-bin",
+						VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+					}},
+					ImagePullSecrets: pullSecrets,
+					// Prewarm images on tainted nodes as well, as pipelines may
+					// tolerate the taints
+					Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}},
+				},
+			},
+		},
+	}
+}
+
+// activePipelineInfos returns the PipelineInfos of the pipelines that aren't
+// paused or failed, as only their images need to be prewarmed
+func (a *apiServer) activePipelineInfos(pachClient *client.APIClient) ([]*pps.PipelineInfo, error) {
+	var result []*pps.PipelineInfo
+	if err := a.sudo(pachClient, func(superUserClient *client.APIClient) error {
+		pipelinePtr := &pps.EtcdPipelineInfo{}
+		return a.pipelines.ReadOnly(pachClient.Ctx()).List(pipelinePtr, col.DefaultOptions, func(name string) error {
+			if pipelinePtr.State == pps.PipelineState_PIPELINE_PAUSED || pipelinePtr.State == pps.PipelineState_PIPELINE_FAILURE {
+				return nil
+			}
+			pipelineInfo, err := ppsutil.GetPipelineInfo(superUserClient, name, pipelinePtr)
+			if err != nil {
+				return err
+			}
+			if pipelineInfo.Transform != nil {
+				result = append(result, pipelineInfo)
+			}
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// syncImagePrewarmer creates or updates the image prewarmer DaemonSet so that
+// it pulls the images of all active pipelines, and deletes it if there are
+// none.
+func (a *apiServer) syncImagePrewarmer(pachClient *client.APIClient) error {
+	pipelineInfos, err := a.activePipelineInfos(pachClient)
+	if err != nil {
+		return err
+	}
+	daemonSets := a.env.GetKubeClient().AppsV1().DaemonSets(a.namespace)
+	images, secrets := prewarmImages(pipelineInfos)
+	if len(images) == 0 {
+		return a.deleteImagePrewarmer()
+	}
+	daemonSet := a.prewarmerDaemonSet(images, secrets)
+	existing, err := daemonSets.Get(prewarmerName, metav1.GetOptions{})
+	if err != nil {
+		if !isNotFoundErr(err) {
+			return errors.Wrapf(err, "could not get the image prewarmer")
+		}
+		_, err := daemonSets.Create(daemonSet)
+		return errors.Wrapf(err, "could not create the image prewarmer")
+	}
+	if prewarmedImages(existing) == strings.Join(images, ",") &&
+		reflect.DeepEqual(existing.Spec.Template.Spec.ImagePullSecrets, daemonSet.Spec.Template.Spec.ImagePullSecrets) {
+		return nil
+	}
+	existing.Spec = daemonSet.Spec
+	_, err = daemonSets.Update(existing)
+	return errors.Wrapf(err, "could not update the image prewarmer")
+}
+
+// prewarmedImages returns the images that 'daemonSet' pulls, joined with ','
+func prewarmedImages(daemonSet *apps.DaemonSet) string {
+	var images []string
+	for _, c := range daemonSet.Spec.Template.Spec.InitContainers {
+		if strings.HasPrefix(c.Name, "prewarm-") {
+			images = append(images, c.Image)
+		}
+	}
+	return strings.Join(images, ",")
+}
+
+func (a *apiServer) deleteImagePrewarmer() error {
+	err := a.env.GetKubeClient().AppsV1().DaemonSets(a.namespace).Delete(prewarmerName, &metav1.DeleteOptions{
+		OrphanDependents: &falseVal,
+	})
+	if err != nil && !isNotFoundErr(err) {
+		return errors.Wrapf(err, "could not delete the image prewarmer")
+	}
+	return nil
+}
+
+// runImagePrewarmer keeps the image prewarmer in sync with the cluster's
+// pipelines until 'ctx' is cancelled. It runs in the PPS master.
+func (a *apiServer) runImagePrewarmer(ctx context.Context, pachClient *client.APIClient) {
+	ticker := time.NewTicker(prewarmInterval)
+	defer ticker.Stop()
+	for {
+		if err := a.syncImagePrewarmer(pachClient); err != nil {
+			log.Errorf("PPS master: could not sync the image prewarmer: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// normalizeImage returns 'image' in the fully-qualified form that nodes
+// report their images in, e.g. "ubuntu" -> "docker.io/library/ubuntu:latest"
+func normalizeImage(image string) string {
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, ":"); i < 0 || i < strings.LastIndex(name, "/") {
+		if !strings.Contains(image, "@") {
+			image += ":latest"
+		}
+	}
+	if i := strings.Index(image, "/"); i < 0 {
+		image = "docker.io/library/" + image
+	} else if domain := image[:i]; !strings.ContainsAny(domain, ".:") && domain != "localhost" {
+		image = "docker.io/" + image
+	}
+	return image
+}
+
+// imagePrewarmStatus returns how many of 'nodes' have pulled 'image'.
+// Unschedulable nodes aren't counted, as workers can't run on them.
+func imagePrewarmStatus(image string, nodes []v1.Node) *pps.ImagePrewarmStatus {
+	result := &pps.ImagePrewarmStatus{Image: image}
+	normalized := normalizeImage(image)
+	for _, node := range nodes {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		result.NodesTotal++
+	nodeImages:
+		for _, nodeImage := range node.Status.Images {
+			for _, name := range nodeImage.Names {
+				if name == image || normalizeImage(name) == normalized {
+					result.NodesReady++
+					break nodeImages
+				}
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestNormalizeImage(t *testing.T) {
+	require.Equal(t, "docker.io/library/ubuntu:latest", normalizeImage("ubuntu"))
+	require.Equal(t, "docker.io/library/ubuntu:18.04", normalizeImage("ubuntu:18.04"))
+	require.Equal(t, "docker.io/library/ubuntu@sha256:abc", normalizeImage("ubuntu@sha256:abc"))
+	require.Equal(t, "docker.io/pachyderm/opencv:1.0", normalizeImage("pachyderm/opencv:1.0"))
+	require.Equal(t, "gcr.io/project/image:v2", normalizeImage("gcr.io/project/image:v2"))
+	require.Equal(t, "localhost:5000/image:latest", normalizeImage("localhost:5000/image"))
+}
+
+func TestPrewarmImages(t *testing.T) {
+	images, secrets := prewarmImages([]*pps.PipelineInfo{
+		{Transform: &pps.Transform{Image: "b:1", ImagePullSecrets: []string{"regcred"}}},
+		{Transform: &pps.Transform{Image: "a:1"}, ImageDigest: "a@sha256:abc"},
+		{Transform: &pps.Transform{Image: "b:1", ImagePullSecrets: []string{"regcred"}}},
+		{Transform: &pps.Transform{}},
+	})
+	require.Equal(t, []string{"a@sha256:abc", "b:1", DefaultUserImage}, images)
+	require.Equal(t, []string{"regcred"}, secrets)
+}
+
+func TestImagePrewarmStatus(t *testing.T) {
+	node := func(unschedulable bool, images ...string) v1.Node {
+		return v1.Node{
+			Spec:   v1.NodeSpec{Unschedulable: unschedulable},
+			Status: v1.NodeStatus{Images: []v1.ContainerImage{{Names: images}}},
+		}
+	}
+	status := imagePrewarmStatus("pachyderm/opencv:1.0", []v1.Node{
+		node(false, "docker.io/pachyderm/opencv@sha256:abc", "docker.io/pachyderm/opencv:1.0"),
+		node(false, "docker.io/pachyderm/opencv:0.9"),
+		node(true, "docker.io/pachyderm/opencv:1.0"),
+	})
+	require.Equal(t, int64(1), status.NodesReady)
+	require.Equal(t, int64(2), status.NodesTotal)
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ancestry"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+
+	"github.com/gogo/protobuf/types"
+)
+
+// minJobGateCheckInterval is the shortest interval at which a pipeline's
+// closed job gates may be checked
+const minJobGateCheckInterval = 10 * time.Second
+
+// validateJobGates returns an error if the job gates of 'pipelineInfo' (if
+// any) can't be checked.
+func validateJobGates(pipelineInfo *pps.PipelineInfo) error {
+	gates := pipelineInfo.JobGates
+	if gates == nil {
+		return nil
+	}
+	if pipelineInfo.Spout != nil || pipelineInfo.Service != nil {
+		return errors.New("invalid pipeline spec: job_gates aren't supported for spouts or services")
+	}
+	if len(gates.Gates) == 0 {
+		return errors.New("invalid pipeline spec: job_gates must have at least one gate")
+	}
+	if gates.CheckInterval != nil {
+		interval, err := types.DurationFromProto(gates.CheckInterval)
+		if err != nil {
+			return errors.Wrapf(err, "invalid pipeline spec: could not parse job_gates.check_interval")
+		}
+		if interval < minJobGateCheckInterval {
+			return errors.Errorf("invalid pipeline spec: job_gates.check_interval must be at least %v", minJobGateCheckInterval)
+		}
+	}
+	names := make(map[string]bool)
+	for _, gate := range gates.Gates {
+		if gate.Name == "" {
+			return errors.New("invalid pipeline spec: every job gate must have a name")
+		}
+		if names[gate.Name] {
+			return errors.Errorf("invalid pipeline spec: more than one job gate is named %q", gate.Name)
+		}
+		names[gate.Name] = true
+		if gate.HTTP != nil && gate.MarkerFile != nil {
+			return errors.Errorf("invalid pipeline spec: job gate %q must set only one of http or marker_file", gate.Name)
+		}
+		if _, err := ppsutil.JobGateTemplate(gate); err != nil {
+			return errors.Wrapf(err, "invalid pipeline spec")
+		}
+		switch {
+		case gate.HTTP != nil:
+			if gate.HTTP.URL == "" {
+				return errors.Errorf("invalid pipeline spec: job gate %q must specify a url", gate.Name)
+			}
+			if gate.HTTP.Timeout != nil {
+				if _, err := types.DurationFromProto(gate.HTTP.Timeout); err != nil {
+					return errors.Wrapf(err, "invalid pipeline spec: could not parse the timeout of job gate %q", gate.Name)
+				}
+			}
+		case gate.MarkerFile != nil:
+			if err := ancestry.ValidateName(gate.MarkerFile.Repo); err != nil {
+				return errors.Wrapf(err, "invalid pipeline spec: invalid repo for job gate %q", gate.Name)
+			}
+			if gate.MarkerFile.Path == "" {
+				return errors.Errorf("invalid pipeline spec: job gate %q must specify a path", gate.Name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestValidateJobGates(t *testing.T) {
+	pipelineInfo := func(gates ...*pps.JobGate) *pps.PipelineInfo {
+		return &pps.PipelineInfo{JobGates: &pps.JobGates{Gates: gates}}
+	}
+	marker := &pps.JobGate{Name: "partner", MarkerFile: &pps.MarkerFileGate{
+		Repo: "uploads",
+		Path: `/done/{{(index .Inputs "sales").Metadata.date}}`,
+	}}
+	http := &pps.JobGate{Name: "api", HTTP: &pps.HTTPGate{URL: "http://api/ready"}}
+	require.NoError(t, validateJobGates(pipelineInfo(marker, http)))
+
+	require.YesError(t, validateJobGates(pipelineInfo()))
+	require.YesError(t, validateJobGates(pipelineInfo(marker, marker)))
+	require.YesError(t, validateJobGates(pipelineInfo(&pps.JobGate{Name: "none"})))
+	require.YesError(t, validateJobGates(pipelineInfo(&pps.JobGate{Name: "both", HTTP: http.HTTP, MarkerFile: marker.MarkerFile})))
+	require.YesError(t, validateJobGates(pipelineInfo(&pps.JobGate{Name: "bad", HTTP: &pps.HTTPGate{URL: "http://api/{{"}})))
+	require.YesError(t, validateJobGates(pipelineInfo(&pps.JobGate{Name: "bad", MarkerFile: &pps.MarkerFileGate{Repo: "a/b", Path: "/done"}})))
+
+	tooOften := pipelineInfo(http)
+	tooOften.JobGates.CheckInterval = types.DurationProto(0)
+	require.YesError(t, validateJobGates(tooOften))
+
+	spout := pipelineInfo(http)
+	spout.Spout = &pps.Spout{}
+	require.YesError(t, validateJobGates(spout))
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// latestWarningEvent returns the most recent warning event in 'events' about
+// one of 'pods', or nil if there isn't one.
+func latestWarningEvent(events []v1.Event, pods map[string]bool) *v1.Event {
+	var latest *v1.Event
+	for i := range events {
+		event := &events[i]
+		if event.Type != v1.EventTypeWarning || event.InvolvedObject.Kind != "Pod" || !pods[event.InvolvedObject.Name] {
+			continue
+		}
+		if latest == nil || latest.LastTimestamp.Before(&event.LastTimestamp) {
+			latest = event
+		}
+	}
+	return latest
+}
+
+// workerWarningEvent returns an excerpt of the most recent warning event on
+// the worker pods of 'pipelineName', to explain a job failure that kubernetes
+// may have caused. It returns "" if there's no such event. Errors are logged,
+// as the event is only informational.
+func (a *apiServer) workerWarningEvent(pipelineName string) string {
+	kubeClient := a.env.GetKubeClient()
+	selector := fmt.Sprintf("%s=%s", pipelineNameLabel, pipelineName)
+	podList, err := kubeClient.CoreV1().Pods(a.namespace).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		log.Errorf("could not list the worker pods of %q: %v", pipelineName, err)
+		return ""
+	}
+	pods := make(map[string]bool)
+	for _, pod := range podList.Items {
+		pods[pod.Name] = true
+	}
+	if len(pods) == 0 {
+		return ""
+	}
+	eventList, err := kubeClient.CoreV1().Events(a.namespace).List(metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("type=%s,involvedObject.kind=Pod", v1.EventTypeWarning),
+	})
+	if err != nil {
+		log.Errorf("could not list the events of the worker pods of %q: %v", pipelineName, err)
+		return ""
+	}
+	event := latestWarningEvent(eventList.Items, pods)
+	if event == nil {
+		return ""
+	}
+	return ppsutil.JobReasonEvent(event.Reason, event.Message)
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLatestWarningEvent(t *testing.T) {
+	now := time.Now()
+	event := func(kind, name, eventType, reason string, ago time.Duration) v1.Event {
+		return v1.Event{
+			InvolvedObject: v1.ObjectReference{Kind: kind, Name: name},
+			Type:           eventType,
+			Reason:         reason,
+			LastTimestamp:  metav1.NewTime(now.Add(-ago)),
+		}
+	}
+	pods := map[string]bool{"pipeline-edges-v1-abcde": true}
+	events := []v1.Event{
+		event("Pod", "pipeline-edges-v1-abcde", v1.EventTypeWarning, "BackOff", time.Hour),
+		event("Pod", "pipeline-edges-v1-abcde", v1.EventTypeWarning, "Evicted", time.Minute),
+		event("Pod", "pipeline-edges-v1-abcde", v1.EventTypeNormal, "Pulled", time.Second),
+		event("Pod", "pipeline-other-v1-fghij", v1.EventTypeWarning, "Failed", time.Second),
+		event("Node", "pipeline-edges-v1-abcde", v1.EventTypeWarning, "OOMKilling", time.Second),
+	}
+	latest := latestWarningEvent(events, pods)
+	require.NotNil(t, latest)
+	require.Equal(t, "Evicted", latest.Reason)
+
+	require.Nil(t, latestWarningEvent(events[2:], pods))
+}
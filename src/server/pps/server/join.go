@@ -0,0 +1,48 @@
+package server
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/worker/datum"
+)
+
+// joinOnGroup matches the references to capture groups in a join_on
+var joinOnGroup = regexp.MustCompile(`\$(\d+)|\$\{(\d+)\}`)
+
+// validateJoinOn checks that the join_on of each PFS input of the join input
+// 'join' only refers to capture groups that its glob has, and that inputs
+// that are joined on their capture groups have some. A joined input without a
+// join_on is still accepted, and is joined with every datum of the other
+// inputs, like a cross.
+func validateJoinOn(join []*pps.Input) error {
+	for _, input := range join {
+		if input.Pfs == nil {
+			continue
+		}
+		if input.Pfs.JoinOn == "" {
+			if input.Pfs.JoinOnCaptureGroups {
+				return errors.Errorf("joined input %q sets 'join_on_capture_groups', "+
+					"but its glob %q has no capture groups", input.Pfs.Name, input.Pfs.Glob)
+			}
+			continue
+		}
+		groups, err := datum.CaptureGroups(input.Pfs.Glob)
+		if err != nil {
+			return err
+		}
+		for _, match := range joinOnGroup.FindAllStringSubmatch(input.Pfs.JoinOn, -1) {
+			group := match[1]
+			if group == "" {
+				group = match[2]
+			}
+			if n, _ := strconv.Atoi(group); n > groups {
+				return errors.Errorf("'join_on' of joined input %q refers to capture "+
+					"group $%s, but its glob %q has %d", input.Pfs.Name, group, input.Pfs.Glob, groups)
+			}
+		}
+	}
+	return nil
+}
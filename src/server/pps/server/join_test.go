@@ -0,0 +1,53 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateJoinOn(t *testing.T) {
+	pfs := func(glob, joinOn string) *pps.Input {
+		return &pps.Input{Pfs: &pps.PFSInput{Name: "in", Repo: "in", Glob: glob, JoinOn: joinOn}}
+	}
+	require.NoError(t, validateJoinOn([]*pps.Input{pfs("/(*)", "$1"), pfs("/*/(*)/(*)", "$2${1}")}))
+	// Non-PFS inputs aren't checked
+	require.NoError(t, validateJoinOn([]*pps.Input{pfs("/(*)", "$1"), {Cross: []*pps.Input{pfs("/*", "")}}}))
+	// no join_on, i.e. no capture groups, is still accepted
+	require.NoError(t, validateJoinOn([]*pps.Input{pfs("/(*)", "$1"), pfs("/*", "")}))
+	// missing capture group
+	require.YesError(t, validateJoinOn([]*pps.Input{pfs("/(*)", "$2")}))
+	require.YesError(t, validateJoinOn([]*pps.Input{pfs("/(*)", "${3}")}))
+	// joined on capture groups that the glob doesn't have
+	captures := pfs("/*", "")
+	captures.Pfs.JoinOnCaptureGroups = true
+	require.YesError(t, validateJoinOn([]*pps.Input{pfs("/(*)", "$1"), captures}))
+}
+
+func TestJoinOnDefault(t *testing.T) {
+	pipelineInfo := func(joinOnCaptureGroups bool) *pps.PipelineInfo {
+		return &pps.PipelineInfo{
+			Pipeline:  client.NewPipeline("pipeline"),
+			Transform: &pps.Transform{},
+			Input: client.NewJoinInput(
+				&pps.Input{Pfs: &pps.PFSInput{Name: "a", Repo: "a", Glob: "/(*)/(*)", JoinOnCaptureGroups: joinOnCaptureGroups}},
+				&pps.Input{Pfs: &pps.PFSInput{Name: "b", Repo: "b", Glob: "/(*)", JoinOn: "$1"}},
+			),
+		}
+	}
+
+	// Specs that don't opt in, such as those saved before
+	// join_on_capture_groups was added, keep their empty join_on, so that
+	// their datums don't change
+	existing := pipelineInfo(false)
+	require.NoError(t, setPipelineDefaults(existing))
+	require.Equal(t, "", existing.Input.Join[0].Pfs.JoinOn)
+	require.Equal(t, "$1", existing.Input.Join[1].Pfs.JoinOn)
+
+	optedIn := pipelineInfo(true)
+	require.NoError(t, setPipelineDefaults(optedIn))
+	require.Equal(t, "$1/$2", optedIn.Input.Join[0].Pfs.JoinOn)
+	require.Equal(t, "$1", optedIn.Input.Join[1].Pfs.JoinOn)
+}
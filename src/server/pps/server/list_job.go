@@ -0,0 +1,80 @@
+package server
+
+import (
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// jobFilter holds the filters of a ListJobRequest that can be checked against
+// a job's EtcdJobInfo, before its (more expensive) JobInfo is read.
+type jobFilter struct {
+	failureCauses map[pps.FailureCause]bool
+	states        map[pps.JobState]bool
+	createdAfter  *time.Time
+	createdBefore *time.Time
+	// skipping is true until the last job of the previous page is reached
+	skipping bool
+}
+
+func newJobFilter(request *pps.ListJobRequest) (*jobFilter, error) {
+	if request.PageSize < 0 {
+		return nil, errors.Errorf("page_size must be non-negative (got %d)", request.PageSize)
+	}
+	filter := &jobFilter{skipping: request.PageToken != ""}
+	if len(request.FailureCause) > 0 {
+		filter.failureCauses = make(map[pps.FailureCause]bool)
+		for _, cause := range request.FailureCause {
+			filter.failureCauses[cause] = true
+		}
+	}
+	if len(request.State) > 0 {
+		filter.states = make(map[pps.JobState]bool)
+		for _, state := range request.State {
+			filter.states[state] = true
+		}
+	}
+	if request.CreatedAfter != nil {
+		createdAfter, err := types.TimestampFromProto(request.CreatedAfter)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid created_after")
+		}
+		filter.createdAfter = &createdAfter
+	}
+	if request.CreatedBefore != nil {
+		createdBefore, err := types.TimestampFromProto(request.CreatedBefore)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid created_before")
+		}
+		filter.createdBefore = &createdBefore
+	}
+	return filter, nil
+}
+
+// match returns true if 'jobPtr' passes the filter. Jobs that haven't
+// started have no creation time, so they don't pass a time range.
+func (f *jobFilter) match(jobPtr *pps.EtcdJobInfo) bool {
+	if f.failureCauses != nil && !f.failureCauses[jobPtr.FailureCause] {
+		return false
+	}
+	if f.states != nil && !f.states[jobPtr.State] {
+		return false
+	}
+	if f.createdAfter == nil && f.createdBefore == nil {
+		return true
+	}
+	created, err := types.TimestampFromProto(jobPtr.Started)
+	if err != nil {
+		return false
+	}
+	if f.createdAfter != nil && created.Before(*f.createdAfter) {
+		return false
+	}
+	if f.createdBefore != nil && !created.Before(*f.createdBefore) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestJobFilter(t *testing.T) {
+	now := time.Now()
+	timestamp := func(t time.Time) *types.Timestamp {
+		result, _ := types.TimestampProto(t)
+		return result
+	}
+	job := &pps.EtcdJobInfo{
+		State:        pps.JobState_JOB_FAILURE,
+		FailureCause: pps.FailureCause_FAILURE_USER_CODE,
+		Started:      timestamp(now.Add(-time.Hour)),
+	}
+
+	filter, err := newJobFilter(&pps.ListJobRequest{})
+	require.NoError(t, err)
+	require.True(t, filter.match(job))
+	require.False(t, filter.skipping)
+
+	filter, err = newJobFilter(&pps.ListJobRequest{State: []pps.JobState{pps.JobState_JOB_SUCCESS, pps.JobState_JOB_FAILURE}})
+	require.NoError(t, err)
+	require.True(t, filter.match(job))
+	filter, err = newJobFilter(&pps.ListJobRequest{State: []pps.JobState{pps.JobState_JOB_RUNNING}})
+	require.NoError(t, err)
+	require.False(t, filter.match(job))
+
+	filter, err = newJobFilter(&pps.ListJobRequest{
+		CreatedAfter:  timestamp(now.Add(-2 * time.Hour)),
+		CreatedBefore: timestamp(now),
+	})
+	require.NoError(t, err)
+	require.True(t, filter.match(job))
+	require.False(t, filter.match(&pps.EtcdJobInfo{})) // not started
+	filter, err = newJobFilter(&pps.ListJobRequest{CreatedBefore: job.Started})
+	require.NoError(t, err)
+	require.False(t, filter.match(job))
+	filter, err = newJobFilter(&pps.ListJobRequest{CreatedAfter: job.Started})
+	require.NoError(t, err)
+	require.True(t, filter.match(job))
+
+	filter, err = newJobFilter(&pps.ListJobRequest{PageToken: "abc"})
+	require.NoError(t, err)
+	require.True(t, filter.skipping)
+	_, err = newJobFilter(&pps.ListJobRequest{PageSize: -1})
+	require.YesError(t, err)
+}
@@ -54,6 +54,17 @@ func (a *apiServer) master() {
 
 		log.Infof("PPS master: launching master process")
 
+		if a.env.ImagePrewarming {
+			go a.runImagePrewarmer(ctx, pachClient)
+		} else if err := a.deleteImagePrewarmer(); err != nil {
+			// image prewarming may have been disabled since pachd last ran
+			log.Warnf("PPS master: %v", err)
+		}
+		go a.runIdleReaper(ctx, pachClient)
+		go a.runAutoscaler(ctx, pachClient)
+		go a.runStatsPruner(ctx, pachClient)
+		go a.runOutputPruner(ctx, pachClient)
+
 		// TODO(msteffen) request only keys, since pipeline_controller.go reads
 		// fresh values for each event anyway
 		pipelineWatcher, err := a.pipelines.ReadOnly(ctx).Watch()
@@ -138,7 +149,9 @@ func (a *apiServer) master() {
 					}
 				}
 				for _, condition := range pod.Status.Conditions {
-					if condition.Type == v1.PodScheduled &&
+					// Gang-scheduled workers are unschedulable until their
+					// queue admits them, which isn't a crash
+					if condition.Type == v1.PodScheduled && !isGangScheduled(pod) &&
 						condition.Status != v1.ConditionTrue && failures[condition.Reason] {
 						if err := a.setPipelineCrashing(pachClient.Ctx(), pipelineName, condition.Message); err != nil {
 							return err
@@ -194,6 +207,34 @@ func (a *apiServer) deletePipelineResources(ctx context.Context, pipelineName st
 			}
 		}
 	}
+	pdbs, err := kubeClient.PolicyV1beta1().PodDisruptionBudgets(a.namespace).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		// pachd may not have access to PodDisruptionBudgets (see
+		// createWorkerSvcAndRc), in which case it won't have created any
+		log.Warnf("PPS master: could not list PodDisruptionBudgets: %v", err)
+	} else {
+		for _, pdb := range pdbs.Items {
+			if err := kubeClient.PolicyV1beta1().PodDisruptionBudgets(a.namespace).Delete(pdb.Name, opts); err != nil {
+				if !isNotFoundErr(err) {
+					return errors.Wrapf(err, "could not delete PodDisruptionBudget %q", pdb.Name)
+				}
+			}
+		}
+	}
+	policies, err := kubeClient.NetworkingV1().NetworkPolicies(a.namespace).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		// pachd may not have access to NetworkPolicies, in which case it can
+		// only have created pipelines without a network policy
+		log.Warnf("PPS master: could not list NetworkPolicies: %v", err)
+	} else {
+		for _, policy := range policies.Items {
+			if err := kubeClient.NetworkingV1().NetworkPolicies(a.namespace).Delete(policy.Name, opts); err != nil {
+				if !isNotFoundErr(err) {
+					return errors.Wrapf(err, "could not delete NetworkPolicy %q", policy.Name)
+				}
+			}
+		}
+	}
 	rcs, err := kubeClient.CoreV1().ReplicationControllers(a.namespace).List(metav1.ListOptions{LabelSelector: selector})
 	if err != nil {
 		return errors.Wrapf(err, "could not list RCs")
@@ -204,6 +245,11 @@ func (a *apiServer) deletePipelineResources(ctx context.Context, pipelineName st
 				return errors.Wrapf(err, "could not delete RC %q: %v", rc.Name)
 			}
 		}
+		if group := rc.Spec.Template.Annotations[volcanoGroupAnnotation]; group != "" {
+			if err := a.deleteVolcanoPodGroup(group); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
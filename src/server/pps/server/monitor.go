@@ -17,6 +17,7 @@ package server
 // shouldn't call each other.
 
 import (
+	"bytes"
 	"context"
 	"path"
 	"strings"
@@ -31,11 +32,13 @@ import (
 	"github.com/pachyderm/pachyderm/src/client"
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
 	"github.com/pachyderm/pachyderm/src/client/pkg/tracing"
 	"github.com/pachyderm/pachyderm/src/client/pkg/tracing/extended"
 	"github.com/pachyderm/pachyderm/src/client/pps"
 	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
 	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
 	workerserver "github.com/pachyderm/pachyderm/src/server/worker/server"
@@ -178,7 +181,42 @@ func (a *apiServer) monitorPipeline(pachClient *client.APIClient, pipelineInfo *
 				}, backoff.NewInfiniteBackOff(), notifyCtx(pachClient.Ctx(), "cron for "+in.Cron.Name))
 			})
 		}
+		if in.HTTPMirror != nil {
+			eg.Go(func() error {
+				return backoff.RetryNotify(func() error {
+					return a.makeHTTPMirrorCommits(pachClient, in)
+				}, backoff.NewInfiniteBackOff(), notifyCtx(pachClient.Ctx(), "http mirror for "+in.HTTPMirror.Name))
+			})
+		}
+		if in.Parameter != nil {
+			eg.Go(func() error {
+				return backoff.RetryNotify(func() error {
+					return a.makeParameterCommit(pachClient, in)
+				}, backoff.NewInfiniteBackOff(), notifyCtx(pachClient.Ctx(), "parameter input "+in.Parameter.Name))
+			})
+		}
+		if in.RemoteRepo != nil {
+			eg.Go(func() error {
+				return backoff.RetryNotify(func() error {
+					return a.makeRemoteRepoCommits(pachClient, in)
+				}, backoff.NewInfiniteBackOff(), notifyCtx(pachClient.Ctx(), "remote repo input "+in.RemoteRepo.Name))
+			})
+		}
+		if in.DBImport != nil {
+			eg.Go(func() error {
+				return backoff.RetryNotify(func() error {
+					return a.makeDBImportCommits(pachClient, in)
+				}, backoff.NewInfiniteBackOff(), notifyCtx(pachClient.Ctx(), "db import input "+in.DBImport.Name))
+			})
+		}
 	})
+	if pipelineInfo.ImagePinning != nil && pipelineInfo.ImagePinning.CheckInterval != nil && pipelineInfo.ImageDigest != "" {
+		eg.Go(func() error {
+			return backoff.RetryNotify(func() error {
+				return a.checkImageDigest(pachClient, pipelineInfo)
+			}, backoff.NewInfiniteBackOff(), notifyCtx(pachClient.Ctx(), "image digest check for "+pipeline))
+		})
+	}
 	if pipelineInfo.Standby {
 		// Capacity 1 gives us a bit of buffer so we don't needlessly go into
 		// standby when SubscribeCommit takes too long to return.
@@ -381,7 +419,7 @@ func (a *apiServer) getLatestCronTime(pachClient *client.APIClient, in *pps.Inpu
 // makeCronCommits makes commits to a single cron input's repo. It's
 // a helper function called by monitorPipeline.
 func (a *apiServer) makeCronCommits(pachClient *client.APIClient, in *pps.Input) error {
-	schedule, err := cron.ParseStandard(in.Cron.Spec)
+	schedule, err := newCronSchedule(in.Cron)
 	if err != nil {
 		return err // Shouldn't happen, as the input is validated in CreatePipeline
 	}
@@ -403,10 +441,10 @@ func (a *apiServer) makeCronCommits(pachClient *client.APIClient, in *pps.Input)
 
 	for {
 		// get the time of the next time from the latest time using the cron schedule
-		next := schedule.Next(latestTime)
-		// and wait until then to make the next commit
+		next := schedule.next(latestTime)
+		// and wait until then (plus any jitter) to make the next commit
 		select {
-		case <-time.After(time.Until(next)):
+		case <-time.After(time.Until(next) + schedule.delay()):
 		case <-pachClient.Ctx().Done():
 			return pachClient.Ctx().Err()
 		}
@@ -427,8 +465,9 @@ func (a *apiServer) makeCronCommits(pachClient *client.APIClient, in *pps.Input)
 			}
 		}
 
-		// Put in an empty file named by the timestamp
-		_, err = pachClient.PutFile(in.Cron.Repo, "master", next.Format(time.RFC3339), strings.NewReader(""))
+		// Put in an empty file named by the timestamp (in UTC, so that files
+		// sort in time order regardless of the cron input's timezone)
+		_, err = pachClient.PutFile(in.Cron.Repo, "master", next.UTC().Format(time.RFC3339), strings.NewReader(""))
 		if err != nil {
 			return errors.Wrapf(err, "put error")
 		}
@@ -442,3 +481,368 @@ func (a *apiServer) makeCronCommits(pachClient *client.APIClient, in *pps.Input)
 		latestTime = next
 	}
 }
+
+// makeHTTPMirrorCommits polls a single http_mirror input's URLs on its
+// schedule, and commits the content of any that have changed to the input's
+// repo. It's a helper function called by monitorPipeline.
+func (a *apiServer) makeHTTPMirrorCommits(pachClient *client.APIClient, in *pps.Input) error {
+	mirror := in.HTTPMirror
+	schedule, err := cron.ParseStandard(mirror.Spec)
+	if err != nil {
+		return err // Shouldn't happen, as the input is validated in CreatePipeline
+	}
+	allowed, err := parseMirrorAllowedNetworks(a.env.HTTPMirrorAllowedNetworks)
+	if err != nil {
+		return err
+	}
+	httpClient := newMirrorClient(allowed)
+	// make sure there isn't an unfinished commit on the branch
+	commitInfo, err := pachClient.InspectCommit(mirror.Repo, "master")
+	if err != nil && !pfsserver.IsNoHeadErr(err) {
+		return err
+	} else if commitInfo != nil && commitInfo.Finished == nil {
+		// and if there is, delete it
+		if err = pachClient.DeleteCommit(mirror.Repo, commitInfo.Commit.ID); err != nil {
+			return err
+		}
+		if commitInfo, err = pachClient.InspectCommit(mirror.Repo, "master"); err != nil && !pfsserver.IsNoHeadErr(err) {
+			return err
+		}
+	}
+
+	// Check the URLs right away the first time the pipeline runs, and
+	// otherwise pick the schedule up where the last commit left off
+	state := readMirrorState(commitInfo)
+	var latestTime time.Time
+	if commitInfo != nil {
+		if latestTime, err = types.TimestampFromProto(commitInfo.Finished); err != nil {
+			return err
+		}
+	}
+	for {
+		if !latestTime.IsZero() {
+			next := schedule.Next(latestTime)
+			select {
+			case <-time.After(time.Until(next)):
+			case <-pachClient.Ctx().Done():
+				return pachClient.Ctx().Err()
+			}
+		}
+		latestTime = time.Now()
+
+		urls, err := mirrorURLs(pachClient.Ctx(), httpClient, mirror)
+		if err != nil {
+			return err
+		}
+		throttle := newMirrorThrottle(pachClient.Ctx(), mirror.BytesPerSecond)
+		var commit *pfs.Commit
+		written := make(map[string]string)
+		for _, u := range urls {
+			name, err := mirrorFileName(u)
+			if err != nil {
+				log.Errorf("PPS master: skipping http mirror url: %v", err)
+				continue
+			}
+			if other, ok := written[name]; ok {
+				log.Errorf("PPS master: skipping http mirror url %q, as %q is "+
+					"already written to %q", u, other, name)
+				continue
+			}
+			written[name] = u
+			body, entry, err := fetchMirrorURL(pachClient.Ctx(), httpClient, u, state.URLs[u])
+			if err != nil {
+				return err
+			}
+			if body == nil {
+				continue // unchanged since the last tick
+			}
+			if err := func() error {
+				defer body.Close()
+				if commit == nil {
+					// Only create a commit once something has changed
+					if commit, err = pachClient.StartCommit(mirror.Repo, "master"); err != nil {
+						return err
+					}
+				}
+				if _, err := pachClient.PutFileOverwrite(mirror.Repo, commit.ID, name, throttle.Reader(body), 0); err != nil {
+					return errors.Wrapf(err, "put error")
+				}
+				return nil
+			}(); err != nil {
+				return err
+			}
+			state.URLs[u] = entry
+		}
+		if commit == nil {
+			continue
+		}
+		if _, err := pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(),
+			&pfs.FinishCommitRequest{
+				Commit:      commit,
+				Description: state.String(),
+			}); err != nil {
+			return err
+		}
+	}
+}
+
+// checkImageDigest periodically re-resolves the tag of a pipeline's pinned
+// image, and when it resolves to a new digest, either records the new digest
+// in the pipeline's EtcdPipelineInfo or updates the pipeline to run it,
+// depending on the pipeline's ImageUpdatePolicy.
+func (a *apiServer) checkImageDigest(pachClient *client.APIClient, pipelineInfo *pps.PipelineInfo) error {
+	pipeline := pipelineInfo.Pipeline.Name
+	interval, err := types.DurationFromProto(pipelineInfo.ImagePinning.CheckInterval)
+	if err != nil {
+		return err // Shouldn't happen, as the pipeline is validated in CreatePipeline
+	}
+	for {
+		select {
+		case <-time.After(interval):
+		case <-pachClient.Ctx().Done():
+			return pachClient.Ctx().Err()
+		}
+		digest, err := resolvePipelineImage(pachClient.Ctx(), a.env.GetKubeClient(),
+			a.namespace, a.imagePullSecret, pipelineInfo.Transform)
+		if err != nil {
+			return err
+		}
+		if digest == pipelineInfo.ImageDigest {
+			continue
+		}
+
+		if pipelineInfo.ImagePinning.Policy == pps.ImageUpdatePolicy_IMAGE_UPDATE_REPROCESS {
+			log.Infof("PPS master: image %q of pipeline %q now resolves to %q; updating pipeline",
+				pipelineInfo.Transform.Image, pipeline, digest)
+			request := ppsutil.PipelineReqFromInfo(pipelineInfo)
+			request.Update = true
+			request.Reprocess = true
+			// Updating the pipeline cancels this monitor, which mustn't interrupt
+			// the update, so it runs in a context that isn't derived from the
+			// monitor's
+			updateClient := pachClient.WithCtx(context.Background())
+			if _, err := updateClient.PpsAPIClient.CreatePipeline(updateClient.Ctx(), request); err != nil {
+				return errors.Wrapf(err, "could not update pipeline %q to new image digest", pipeline)
+			}
+			return nil
+		}
+
+		log.Infof("PPS master: image %q of pipeline %q now resolves to %q, but the "+
+			"pipeline is pinned to %q", pipelineInfo.Transform.Image, pipeline, digest,
+			pipelineInfo.ImageDigest)
+		if _, err := col.NewSTM(pachClient.Ctx(), a.env.GetEtcdClient(), func(stm col.STM) error {
+			pipelinePtr := &pps.EtcdPipelineInfo{}
+			return a.pipelines.ReadWrite(stm).Update(pipeline, pipelinePtr, func() error {
+				pipelinePtr.AvailableImageDigest = digest
+				return nil
+			})
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+// makeParameterCommit commits the datums of a single parameter input to the
+// input's repo, unless the repo's most recent commit already contains them
+// (i.e. unless the pipeline was just created or its parameters were changed).
+// It's a helper function called by monitorPipeline.
+func (a *apiServer) makeParameterCommit(pachClient *client.APIClient, in *pps.Input) error {
+	param := in.Parameter
+	files, err := parameterFiles(param)
+	if err != nil {
+		return err // Shouldn't happen, as the input is validated in CreatePipeline
+	}
+	grid := parameterGrid(param)
+	// make sure there isn't an unfinished commit on the branch
+	commitInfo, err := pachClient.InspectCommit(param.Repo, "master")
+	if err != nil && !pfsserver.IsNoHeadErr(err) {
+		return err
+	} else if commitInfo != nil && commitInfo.Finished == nil {
+		// and if there is, delete it
+		if err = pachClient.DeleteCommit(param.Repo, commitInfo.Commit.ID); err != nil {
+			return err
+		}
+		if commitInfo, err = pachClient.InspectCommit(param.Repo, "master"); err != nil && !pfsserver.IsNoHeadErr(err) {
+			return err
+		}
+	}
+	if commitInfo != nil && commitInfo.Description == grid {
+		return nil // the datums are already committed
+	}
+
+	commit, err := pachClient.StartCommit(param.Repo, "master")
+	if err != nil {
+		return err
+	}
+	// Remove the datums of the previous parameters that aren't in the new grid
+	names := make(map[string]bool)
+	for _, f := range files {
+		names[f.name] = true
+	}
+	if commitInfo != nil {
+		fileInfos, err := pachClient.ListFile(param.Repo, commit.ID, "/")
+		if err != nil {
+			return err
+		}
+		for _, fi := range fileInfos {
+			if !names[path.Base(fi.File.Path)] {
+				if err := pachClient.DeleteFile(param.Repo, commit.ID, fi.File.Path); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, f := range files {
+		if _, err := pachClient.PutFileOverwrite(param.Repo, commit.ID, f.name, bytes.NewReader(f.content), 0); err != nil {
+			return errors.Wrapf(err, "put error")
+		}
+	}
+	_, err = pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(),
+		&pfs.FinishCommitRequest{
+			Commit:      commit,
+			Description: grid,
+		})
+	return err
+}
+
+// makeRemoteRepoCommits subscribes to a single remote_repo input's source
+// branch, and each time a new commit is finished on it, mirrors the branch's
+// head into a new commit in the input's repo. Commits that are finished while
+// an earlier one is being copied are coalesced into a single local commit.
+// It's a helper function called by monitorPipeline.
+func (a *apiServer) makeRemoteRepoCommits(pachClient *client.APIClient, in *pps.Input) error {
+	input := in.RemoteRepo
+	remote, err := newRemoteRepoClient(a.env.GetKubeClient(), a.namespace, input)
+	if err != nil {
+		return err
+	}
+	defer remote.Close()
+	remote = remote.WithCtx(pachClient.Ctx())
+
+	// make sure there isn't an unfinished commit on the branch
+	commitInfo, err := pachClient.InspectCommit(input.Repo, "master")
+	if err != nil && !pfsserver.IsNoHeadErr(err) {
+		return err
+	} else if commitInfo != nil && commitInfo.Finished == nil {
+		// and if there is, delete it
+		if err = pachClient.DeleteCommit(input.Repo, commitInfo.Commit.ID); err != nil {
+			return err
+		}
+		if commitInfo, err = pachClient.InspectCommit(input.Repo, "master"); err != nil && !pfsserver.IsNoHeadErr(err) {
+			return err
+		}
+	}
+	state := readRemoteRepoState(commitInfo)
+
+	// SubscribeCommit sends every commit on the branch, starting with the
+	// oldest, so rather than mirroring each commit it receives, this mirrors
+	// the branch's head whenever it has moved
+	return remote.SubscribeCommitF(input.SourceRepo, input.SourceBranch, nil, "",
+		pfs.CommitState_FINISHED, func(*pfs.CommitInfo) error {
+			head, err := remote.PfsAPIClient.InspectCommit(remote.Ctx(),
+				&pfs.InspectCommitRequest{
+					Commit:     client.NewCommit(input.SourceRepo, input.SourceBranch),
+					BlockState: pfs.CommitState_FINISHED,
+				})
+			if err != nil {
+				return grpcutil.ScrubGRPC(err)
+			}
+			if head.Commit.ID == state.SourceCommit {
+				return nil
+			}
+			commit, err := pachClient.StartCommit(input.Repo, "master")
+			if err != nil {
+				return err
+			}
+			if err := syncRemoteCommit(pachClient, remote, input, commit.ID, head.Commit.ID, state.SourceCommit); err != nil {
+				return err
+			}
+			state.SourceCommit = head.Commit.ID
+			_, err = pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(),
+				&pfs.FinishCommitRequest{
+					Commit:      commit,
+					Description: state.String(),
+				})
+			return err
+		})
+}
+
+// makeDBImportCommits imports a single db_import input's tables on its
+// schedule, committing each import cycle to the input's repo as a single
+// commit. It's a helper function called by monitorPipeline.
+func (a *apiServer) makeDBImportCommits(pachClient *client.APIClient, in *pps.Input) error {
+	input := in.DBImport
+	schedule, err := cron.ParseStandard(input.Spec)
+	if err != nil {
+		return err // Shouldn't happen, as the input is validated in CreatePipeline
+	}
+	db, err := openDBImport(a.env.GetKubeClient(), a.namespace, input)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// make sure there isn't an unfinished commit on the branch
+	commitInfo, err := pachClient.InspectCommit(input.Repo, "master")
+	if err != nil && !pfsserver.IsNoHeadErr(err) {
+		return err
+	} else if commitInfo != nil && commitInfo.Finished == nil {
+		// and if there is, delete it
+		if err = pachClient.DeleteCommit(input.Repo, commitInfo.Commit.ID); err != nil {
+			return err
+		}
+		if commitInfo, err = pachClient.InspectCommit(input.Repo, "master"); err != nil && !pfsserver.IsNoHeadErr(err) {
+			return err
+		}
+	}
+
+	// Import the tables right away the first time the pipeline runs, and
+	// otherwise pick the schedule up where the last commit left off
+	state := readDBImportState(commitInfo)
+	var latestTime time.Time
+	if commitInfo != nil {
+		if latestTime, err = types.TimestampFromProto(commitInfo.Finished); err != nil {
+			return err
+		}
+	}
+	for {
+		if !latestTime.IsZero() {
+			next := schedule.Next(latestTime)
+			select {
+			case <-time.After(time.Until(next)):
+			case <-pachClient.Ctx().Done():
+				return pachClient.Ctx().Err()
+			}
+		}
+		latestTime = time.Now()
+
+		commit, err := pachClient.StartCommit(input.Repo, "master")
+		if err != nil {
+			return err
+		}
+		next := state.next()
+		rows, err := importDBTables(pachClient.Ctx(), db, pachClient, input, commit.ID, next)
+		if err != nil {
+			// Don't leave a partial import in the repo
+			if err := pachClient.DeleteCommit(input.Repo, commit.ID); err != nil {
+				log.Errorf("PPS master: could not delete partial db import commit %s: %v", commit.ID, err)
+			}
+			return err
+		}
+		if rows == 0 && state.Cycle > 0 && input.Mode == pps.DBImportMode_DB_IMPORT_CDC {
+			// Nothing has changed since the last cycle
+			if err := pachClient.DeleteCommit(input.Repo, commit.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(),
+			&pfs.FinishCommitRequest{
+				Commit:      commit,
+				Description: next.String(),
+			}); err != nil {
+			return err
+		}
+		state = next
+	}
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"golang.org/x/net/context"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// defaultMountCredentialsTTL is how long mount credentials are valid for, if
+// the request doesn't say
+const defaultMountCredentialsTTL = time.Hour
+
+// GetMountCredentials implements the protobuf pps.GetMountCredentials RPC
+func (a *apiServer) GetMountCredentials(ctx context.Context, request *pps.GetMountCredentialsRequest) (response *pps.MountCredentials, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.env.GetPachClient(ctx)
+	ttl := defaultMountCredentialsTTL
+	if request.TTL != nil {
+		var err error
+		if ttl, err = types.DurationFromProto(request.TTL); err != nil {
+			return nil, err
+		}
+		if ttl < time.Second {
+			return nil, errors.Errorf("invalid TTL %v (must be at least 1s)", ttl)
+		}
+	}
+	targets, err := resolveMountTargets(pachClient, request)
+	if err != nil {
+		return nil, err
+	}
+	response = &pps.MountCredentials{
+		Targets:      targets,
+		PachdAddress: fmt.Sprintf("pachd.%s:%d", a.namespace, a.port),
+		S3Endpoint:   fmt.Sprintf("pachd.%s:%d", a.namespace, a.env.S3GatewayPort),
+	}
+
+	me, err := pachClient.WhoAmI(pachClient.Ctx(), &auth.WhoAmIRequest{})
+	if auth.IsErrNotActivated(err) {
+		return response, nil // no credentials are needed
+	} else if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	// The credentials can't outlive the caller's own token
+	ttlSecs := int64(ttl.Seconds())
+	if me.TTL > 0 && me.TTL < ttlSecs {
+		ttlSecs = me.TTL
+	}
+	// Tokens are authorized for whole repos, so the token can read every
+	// commit and path of the targets' repos, which the response reports
+	repos := make(map[string]bool)
+	for _, target := range targets {
+		repos[target.Commit.Repo.Name] = true
+	}
+	readableRepos := sortedRepos(repos)
+	tokenResp, err := pachClient.GetAuthToken(pachClient.Ctx(), &auth.GetAuthTokenRequest{
+		TTL:           ttlSecs,
+		ReadOnlyRepos: readableRepos,
+	})
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	response.Token = tokenResp.Token
+	response.ReadableRepos = readableRepos
+	response.Expiration, err = types.TimestampProto(time.Now().Add(time.Duration(ttlSecs) * time.Second))
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// resolveMountTargets returns the targets of 'request' (including the output
+// commit of its job, if it has one), with their defaults set and their
+// branches resolved to commit IDs. Resolving a target's commit also checks
+// that the caller can read it.
+func resolveMountTargets(pachClient *client.APIClient, request *pps.GetMountCredentialsRequest) ([]*pps.MountTarget, error) {
+	targets := request.Targets
+	var jobTarget *pps.MountTarget
+	if request.Job != nil {
+		jobInfo, err := pachClient.InspectJob(request.Job.ID, false)
+		if err != nil {
+			return nil, err
+		}
+		if jobInfo.OutputCommit == nil {
+			return nil, errors.Errorf("job %s has no output commit", request.Job.ID)
+		}
+		jobTarget = &pps.MountTarget{
+			Commit: jobInfo.OutputCommit,
+			Name:   jobInfo.Pipeline.Name,
+		}
+		targets = append(targets[:len(targets):len(targets)], jobTarget)
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("no commits to mount")
+	}
+
+	var result []*pps.MountTarget
+	names := make(map[string]bool)
+	commits := make(map[string]string) // repo -> commit ID
+	for _, target := range targets {
+		if target.Commit == nil || target.Commit.Repo == nil || target.Commit.Repo.Name == "" {
+			return nil, errors.New("every mount target must have a commit")
+		}
+		repo := target.Commit.Repo.Name
+		commitInfo, err := pachClient.InspectCommit(repo, target.Commit.ID)
+		if err != nil {
+			return nil, err
+		}
+		id := commitInfo.Commit.ID
+		if target == jobTarget && commits[repo] == id {
+			continue // the job's output commit is already mounted
+		}
+		if other, ok := commits[repo]; ok && other != id {
+			// The FUSE mount can only mount one commit of each repo
+			return nil, errors.Errorf("can't mount %s@%s and %s@%s at once (only one commit of each repo can be mounted)", repo, other, repo, id)
+		}
+		commits[repo] = id
+		name := target.Name
+		if name == "" {
+			name = repo
+		}
+		if name == "." || name == ".." || strings.ContainsAny(name, "/\\") {
+			return nil, errors.Errorf("invalid mount name %q", name)
+		}
+		if names[name] {
+			return nil, errors.Errorf("more than one mount is named %q", name)
+		}
+		names[name] = true
+		result = append(result, &pps.MountTarget{
+			Commit: client.NewCommit(repo, id),
+			Path:   path.Clean("/" + target.Path),
+			Name:   name,
+		})
+	}
+	return result, nil
+}
+
+func sortedRepos(repos map[string]bool) []string {
+	var result []string
+	for repo := range repos {
+		result = append(result, repo)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// dnsPort is the port that workers may always use to resolve hostnames
+const dnsPort = 53
+
+// validateNetworkPolicy checks that a pipeline's network policy (if any) is
+// well-formed.
+func validateNetworkPolicy(policy *pps.NetworkPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	for _, host := range policy.AllowedHosts {
+		if host == "" || strings.ContainsAny(host, ":/ ") {
+			return errors.Errorf("invalid pipeline spec: network policy host %q must be a hostname, "+
+				"without a scheme, port or path", host)
+		}
+	}
+	for _, cidr := range policy.AllowedCidrs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.Wrapf(err, "invalid pipeline spec: invalid network policy CIDR %q", cidr)
+		}
+	}
+	for _, port := range policy.AllowedPorts {
+		if port < 1 || port > 65535 {
+			return errors.Errorf("invalid pipeline spec: invalid network policy port %d", port)
+		}
+	}
+	return nil
+}
+
+// networkPolicyBlocks returns the IP ranges that 'policy' allows, resolving
+// its hosts with 'lookupHost' (net.LookupHost, except in tests).
+func networkPolicyBlocks(policy *pps.NetworkPolicy, lookupHost func(string) ([]string, error)) ([]string, error) {
+	blocks := make(map[string]bool)
+	for _, cidr := range policy.AllowedCidrs {
+		blocks[cidr] = true
+	}
+	for _, host := range policy.AllowedHosts {
+		addrs, err := lookupHost(host)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not resolve network policy host %q", host)
+		}
+		for _, addr := range addrs {
+			ip := net.ParseIP(addr)
+			if ip == nil {
+				continue
+			}
+			if ip.To4() != nil {
+				blocks[ip.String()+"/32"] = true
+			} else {
+				blocks[ip.String()+"/128"] = true
+			}
+		}
+	}
+	var result []string
+	for block := range blocks {
+		result = append(result, block)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// workerNetworkPolicy returns the Kubernetes NetworkPolicy that restricts the
+// egress of the workers selected by 'labels' according to the pipeline's
+// network policy. Workers can always reach pods in pachd's namespace (pachd,
+// etcd and other workers) and DNS servers. Ingress isn't restricted.
+func workerNetworkPolicy(name string, labels map[string]string, policy *pps.NetworkPolicy, lookupHost func(string) ([]string, error)) (*networkingv1.NetworkPolicy, error) {
+	blocks, err := networkPolicyBlocks(policy, lookupHost)
+	if err != nil {
+		return nil, err
+	}
+	udp, tcp := v1.ProtocolUDP, v1.ProtocolTCP
+	dns := intstr.FromInt(dnsPort)
+	egress := []networkingv1.NetworkPolicyEgressRule{
+		{
+			To: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}},
+		},
+		{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &udp, Port: &dns},
+				{Protocol: &tcp, Port: &dns},
+			},
+		},
+	}
+	if len(blocks) > 0 {
+		rule := networkingv1.NetworkPolicyEgressRule{}
+		for _, block := range blocks {
+			rule.To = append(rule.To, networkingv1.NetworkPolicyPeer{
+				IPBlock: &networkingv1.IPBlock{CIDR: block},
+			})
+		}
+		for _, port := range policy.AllowedPorts {
+			port := intstr.FromInt(int(port))
+			rule.Ports = append(rule.Ports, networkingv1.NetworkPolicyPort{Protocol: &tcp, Port: &port})
+		}
+		egress = append(egress, rule)
+	}
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "NetworkPolicy",
+			APIVersion: "networking.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: labels},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress:      egress,
+		},
+	}, nil
+}
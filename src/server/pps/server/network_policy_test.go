@@ -0,0 +1,60 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateNetworkPolicy(t *testing.T) {
+	require.NoError(t, validateNetworkPolicy(nil))
+	require.NoError(t, validateNetworkPolicy(&pps.NetworkPolicy{
+		AllowedHosts: []string{"pypi.org"},
+		AllowedCidrs: []string{"10.0.0.0/8"},
+		AllowedPorts: []int32{443},
+	}))
+	require.YesError(t, validateNetworkPolicy(&pps.NetworkPolicy{AllowedHosts: []string{"https://pypi.org"}}))
+	require.YesError(t, validateNetworkPolicy(&pps.NetworkPolicy{AllowedCidrs: []string{"10.0.0.0"}}))
+	require.YesError(t, validateNetworkPolicy(&pps.NetworkPolicy{AllowedPorts: []int32{70000}}))
+}
+
+func TestWorkerNetworkPolicy(t *testing.T) {
+	lookupHost := func(host string) ([]string, error) {
+		switch host {
+		case "pypi.org":
+			return []string{"151.101.0.223", "2a04:4e42::223"}, nil
+		default:
+			return nil, errors.Errorf("no such host %q", host)
+		}
+	}
+	labels := map[string]string{pipelineNameLabel: "train"}
+	policy, err := workerNetworkPolicy("pipeline-train-v1", labels, &pps.NetworkPolicy{
+		AllowedHosts: []string{"pypi.org"},
+		AllowedCidrs: []string{"10.0.0.0/8"},
+		AllowedPorts: []int32{443},
+	}, lookupHost)
+	require.NoError(t, err)
+	require.Equal(t, labels, policy.Spec.PodSelector.MatchLabels)
+	// pachd's namespace, DNS, and the allowed hosts and CIDRs
+	require.Equal(t, 3, len(policy.Spec.Egress))
+	var blocks []string
+	for _, peer := range policy.Spec.Egress[2].To {
+		blocks = append(blocks, peer.IPBlock.CIDR)
+	}
+	require.Equal(t, []string{"10.0.0.0/8", "151.101.0.223/32", "2a04:4e42::223/128"}, blocks)
+	require.Equal(t, 1, len(policy.Spec.Egress[2].Ports))
+	require.Equal(t, 443, policy.Spec.Egress[2].Ports[0].Port.IntValue())
+
+	// Without allowed hosts or CIDRs, workers can only reach pachd's namespace
+	// and DNS
+	policy, err = workerNetworkPolicy("pipeline-train-v1", labels, &pps.NetworkPolicy{}, lookupHost)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(policy.Spec.Egress))
+
+	_, err = workerNetworkPolicy("pipeline-train-v1", labels, &pps.NetworkPolicy{
+		AllowedHosts: []string{"example.com"},
+	}, lookupHost)
+	require.YesError(t, err)
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// validateOOMRetry returns an error if the OOMRetry spec of 'pipelineInfo'
+// is invalid.
+func validateOOMRetry(pipelineInfo *pps.PipelineInfo) error {
+	spec := pipelineInfo.OomRetry
+	if spec == nil {
+		return nil
+	}
+	if spec.MaxMemory == "" {
+		return errors.New("invalid pipeline spec: oom_retry must set max_memory")
+	}
+	maxMemory, err := resource.ParseQuantity(spec.MaxMemory)
+	if err != nil {
+		return errors.Wrapf(err, "invalid pipeline spec: could not parse oom_retry.max_memory %q", spec.MaxMemory)
+	}
+	if spec.MemoryMultiplier != 0 && spec.MemoryMultiplier <= 1 {
+		return errors.Errorf("invalid pipeline spec: oom_retry.memory_multiplier must be greater than 1 (got %v)", spec.MemoryMultiplier)
+	}
+	if pipelineInfo.ResourceLimits != nil && pipelineInfo.ResourceLimits.Memory != "" {
+		limit, err := resource.ParseQuantity(pipelineInfo.ResourceLimits.Memory)
+		if err == nil && limit.Cmp(maxMemory) > 0 {
+			return errors.Errorf("invalid pipeline spec: oom_retry.max_memory (%s) is less than the pipeline's memory limit (%s)",
+				spec.MaxMemory, pipelineInfo.ResourceLimits.Memory)
+		}
+	}
+	return nil
+}
+
+// escalateWorkerMemory applies the escalated memory of 'ptr' (if any) to the
+// options of its pipeline's workers: their memory request and limit are set
+// to it, and they're scheduled on the nodes selected by the pipeline's
+// OOMRetry spec.
+func escalateWorkerMemory(ptr *pps.EtcdPipelineInfo, pipelineInfo *pps.PipelineInfo, options *workerOptions) error {
+	if ptr.EscalatedMemory == "" {
+		return nil
+	}
+	memory, err := resource.ParseQuantity(ptr.EscalatedMemory)
+	if err != nil {
+		return errors.Wrapf(err, "could not parse escalated memory %q", ptr.EscalatedMemory)
+	}
+	if options.resourceRequests == nil {
+		options.resourceRequests = &v1.ResourceList{}
+	}
+	(*options.resourceRequests)[v1.ResourceMemory] = memory
+	if options.resourceLimits == nil {
+		options.resourceLimits = &v1.ResourceList{}
+	}
+	(*options.resourceLimits)[v1.ResourceMemory] = memory
+	options.workerEnv = append(options.workerEnv, v1.EnvVar{
+		Name:  client.PPSEscalatedMemoryEnv,
+		Value: ptr.EscalatedMemory,
+	})
+	options.annotations[escalatedMemoryAnnotation] = ptr.EscalatedMemory
+	if nodeSelector := pipelineInfo.GetOomRetry().GetNodeSelector(); len(nodeSelector) > 0 {
+		schedulingSpec := &pps.SchedulingSpec{}
+		if options.schedulingSpec != nil {
+			*schedulingSpec = *options.schedulingSpec
+		}
+		schedulingSpec.NodeSelector = mergeMetadata(nodeSelector, schedulingSpec.NodeSelector)
+		options.schedulingSpec = schedulingSpec
+	}
+	return nil
+}
+
+// resetMemoryEscalation restores the memory of op's pipeline's workers once
+// the job that their memory was escalated for (see pps.OOMRetry) has
+// finished. This makes op's RC stale, so the workers are restarted with the
+// memory in the pipeline's spec.
+func (op *pipelineOp) resetMemoryEscalation() error {
+	if op.ptr.EscalatedJob == "" {
+		return nil
+	}
+	jobPtr := &pps.EtcdJobInfo{}
+	if err := op.apiServer.jobs.ReadOnly(op.opClient.Ctx()).Get(op.ptr.EscalatedJob, jobPtr); err != nil {
+		if !col.IsErrNotFound(err) {
+			return err
+		}
+	} else if !ppsutil.IsTerminal(jobPtr.State) {
+		return nil
+	}
+	log.Infof("PPS master: job %s has finished, so restoring the memory of %q's workers", op.ptr.EscalatedJob, op.name)
+	_, err := col.NewSTM(op.opClient.Ctx(), op.apiServer.env.GetEtcdClient(), func(stm col.STM) error {
+		pipelinePtr := &pps.EtcdPipelineInfo{}
+		return op.apiServer.pipelines.ReadWrite(stm).Update(op.name, pipelinePtr, func() error {
+			if pipelinePtr.EscalatedJob != op.ptr.EscalatedJob {
+				return nil // escalated again since op.ptr was read
+			}
+			pipelinePtr.EscalatedMemory = ""
+			pipelinePtr.EscalatedJob = ""
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	op.ptr.EscalatedMemory = ""
+	op.ptr.EscalatedJob = ""
+	return nil
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// outputPruneInterval is how often the PPS master prunes pipelines' output
+// commits
+const outputPruneInterval = time.Hour
+
+// validateOutputRetention returns an error if 'retention' is invalid.
+func validateOutputRetention(retention *pps.OutputRetention) error {
+	if retention == nil {
+		return nil
+	}
+	if retention.MaxAge == nil && retention.KeepLast == 0 {
+		return errors.New("invalid pipeline spec: output_retention must set max_age or keep_last")
+	}
+	if retention.MaxAge != nil {
+		maxAge, err := types.DurationFromProto(retention.MaxAge)
+		if err != nil {
+			return errors.Wrapf(err, "invalid pipeline spec: could not parse output_retention.max_age")
+		}
+		if maxAge <= 0 {
+			return errors.New("invalid pipeline spec: output_retention.max_age must be positive")
+		}
+	}
+	if retention.KeepLast < 0 {
+		return errors.New("invalid pipeline spec: output_retention.keep_last must not be negative")
+	}
+	return nil
+}
+
+// prunedOutput is an output commit that exceeds its pipeline's output
+// retention, and why.
+type prunedOutput struct {
+	commitInfo *pfs.CommitInfo
+	reason     string
+}
+
+// outputsToPrune returns the finished commits in 'commitInfos' (which are
+// listed newest first, as by ListCommit) that exceed 'maxAge' or 'keepLast'
+// at 'now'. Commits that were already pruned, tagged commits, and the newest
+// finished commit, which the branch's next job may build on, are left out. A
+// limit of 0 isn't enforced.
+func outputsToPrune(commitInfos []*pfs.CommitInfo, now time.Time, maxAge time.Duration, keepLast int64) []prunedOutput {
+	var result []prunedOutput
+	var newer int64
+	for _, commitInfo := range commitInfos {
+		if commitInfo.Finished == nil {
+			continue
+		}
+		newer++
+		if newer == 1 || commitInfo.Pruned != nil || len(commitInfo.Tags) > 0 {
+			continue
+		}
+		finished, err := types.TimestampFromProto(commitInfo.Finished)
+		if err != nil {
+			continue
+		}
+		var reason string
+		switch {
+		case keepLast > 0 && newer > keepLast:
+			reason = fmt.Sprintf("more than %d newer output commits", keepLast)
+		case maxAge > 0 && now.Sub(finished) > maxAge:
+			reason = fmt.Sprintf("older than %s", maxAge)
+		default:
+			continue
+		}
+		result = append(result, prunedOutput{commitInfo: commitInfo, reason: reason})
+	}
+	return result
+}
+
+// pruneOutputs prunes the output commits of every pipeline that exceed its
+// output retention. The commits of the output branch, and of the branches of
+// the pipeline's additional outputs, are pruned. Pruning doesn't free any
+// storage by itself: garbage collection requires every pipeline to be stopped,
+// so it's left to an administrator to run it.
+func (a *apiServer) pruneOutputs(pachClient *client.APIClient) error {
+	now := time.Now()
+	var prunedCommits int
+	var prunedBytes uint64
+	if err := a.listPipeline(pachClient, &pps.ListPipelineRequest{}, func(pipelineInfo *pps.PipelineInfo) error {
+		retention := pipelineInfo.OutputRetention
+		if retention == nil {
+			return nil
+		}
+		var maxAge time.Duration
+		if retention.MaxAge != nil {
+			var err error
+			if maxAge, err = types.DurationFromProto(retention.MaxAge); err != nil {
+				return err
+			}
+		}
+		branches := []*pfs.Branch{client.NewBranch(pipelineInfo.Pipeline.Name, pipelineInfo.OutputBranch)}
+		for _, output := range pipelineInfo.Outputs {
+			branches = append(branches, client.NewBranch(output.Repo, output.Branch))
+		}
+		for _, branch := range branches {
+			// A branch that can't be listed doesn't keep the other
+			// branches from being pruned
+			commits, bytes, err := a.pruneOutputBranch(pachClient, pipelineInfo.Pipeline.Name, branch, now, maxAge, retention.KeepLast)
+			if err != nil {
+				log.Errorf("PPS master: could not prune the output commits of pipeline %q in %s@%s: %v",
+					pipelineInfo.Pipeline.Name, branch.Repo.Name, branch.Name, err)
+			}
+			prunedCommits += commits
+			prunedBytes += bytes
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if prunedCommits > 0 {
+		log.Infof("PPS master: pruned %d output commits (%d bytes), whose storage is freed by the next `pachctl garbage-collect`",
+			prunedCommits, prunedBytes)
+	}
+	return nil
+}
+
+// pruneOutputBranch prunes the commits of 'branch', an output branch of
+// 'pipeline', that exceed 'maxAge' or 'keepLast' at 'now'. It returns the
+// number of commits that it pruned, and their total size.
+func (a *apiServer) pruneOutputBranch(pachClient *client.APIClient, pipeline string, branch *pfs.Branch, now time.Time, maxAge time.Duration, keepLast int64) (int, uint64, error) {
+	var commitInfos []*pfs.CommitInfo
+	if err := pachClient.ListCommitF(branch.Repo.Name, branch.Name, "", 0, false, func(commitInfo *pfs.CommitInfo) error {
+		commitInfos = append(commitInfos, commitInfo)
+		return nil
+	}); err != nil && !isNotFoundErr(err) {
+		return 0, 0, err
+	}
+	var prunedCommits int
+	var prunedBytes uint64
+	for _, pruned := range outputsToPrune(commitInfos, now, maxAge, keepLast) {
+		commit := pruned.commitInfo.Commit
+		if err := pachClient.PruneCommit(commit.Repo.Name, commit.ID); err != nil {
+			// e.g. the commit is the head of another branch
+			log.Errorf("PPS master: could not prune output commit %s@%s of pipeline %q: %v",
+				commit.Repo.Name, commit.ID, pipeline, err)
+			continue
+		}
+		log.Infof("PPS master: pruned output commit %s@%s of pipeline %q (%d bytes): %s",
+			commit.Repo.Name, commit.ID, pipeline, pruned.commitInfo.SizeBytes, pruned.reason)
+		prunedCommits++
+		prunedBytes += pruned.commitInfo.SizeBytes
+	}
+	return prunedCommits, prunedBytes, nil
+}
+
+// runOutputPruner periodically prunes the output commits of every pipeline
+// until 'ctx' is cancelled. It runs in the PPS master.
+func (a *apiServer) runOutputPruner(ctx context.Context, pachClient *client.APIClient) {
+	ticker := time.NewTicker(outputPruneInterval)
+	defer ticker.Stop()
+	for {
+		if err := a.sudo(pachClient, a.pruneOutputs); err != nil {
+			log.Errorf("PPS master: could not prune output commits: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateOutputRetention(t *testing.T) {
+	require.NoError(t, validateOutputRetention(nil))
+	require.NoError(t, validateOutputRetention(&pps.OutputRetention{KeepLast: 10}))
+	require.NoError(t, validateOutputRetention(&pps.OutputRetention{MaxAge: types.DurationProto(24 * time.Hour)}))
+	require.YesError(t, validateOutputRetention(&pps.OutputRetention{}))
+	require.YesError(t, validateOutputRetention(&pps.OutputRetention{MaxAge: types.DurationProto(0)}))
+	require.YesError(t, validateOutputRetention(&pps.OutputRetention{KeepLast: -1}))
+}
+
+func TestOutputsToPrune(t *testing.T) {
+	now := time.Date(2020, 6, 10, 0, 0, 0, 0, time.UTC)
+	// An open commit at the head of the branch (whose job is running),
+	// followed by five finished commits, newest first
+	commitInfos := []*pfs.CommitInfo{{Commit: client.NewCommit("p", "open")}}
+	for i := 0; i < 5; i++ {
+		finished, err := types.TimestampProto(now.Add(-time.Duration(i) * 24 * time.Hour))
+		require.NoError(t, err)
+		commitInfos = append(commitInfos, &pfs.CommitInfo{
+			Commit:   client.NewCommit("p", fmt.Sprintf("commit%d", i)),
+			Finished: finished,
+		})
+	}
+	commitIDs := func(pruned []prunedOutput) []string {
+		var result []string
+		for _, p := range pruned {
+			result = append(result, p.commitInfo.Commit.ID)
+		}
+		return result
+	}
+
+	require.Equal(t, []string{"commit3", "commit4"}, commitIDs(outputsToPrune(commitInfos, now, 0, 3)))
+	require.Equal(t, []string{"commit2", "commit3", "commit4"}, commitIDs(outputsToPrune(commitInfos, now, 36*time.Hour, 0)))
+	require.Equal(t, []string{"commit1", "commit2", "commit3", "commit4"}, commitIDs(outputsToPrune(commitInfos, now, 36*time.Hour, 1)))
+	// The newest finished commit is kept, even if it's too old
+	require.Equal(t, []string{"commit4"}, commitIDs(outputsToPrune(commitInfos[4:], now, time.Hour, 0)))
+
+	// Tagged commits, and commits that were already pruned, are left out
+	commitInfos[4].Pruned = types.TimestampNow()
+	commitInfos[5].Tags = []string{"v1"}
+	require.Equal(t, []string{"commit2"}, commitIDs(outputsToPrune(commitInfos, now, 36*time.Hour, 0)))
+}
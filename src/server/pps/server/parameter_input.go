@@ -0,0 +1,148 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// maxParameterCombinations is the largest number of datums that a single
+// parameter input may generate. Larger sweeps should be generated into a repo
+// by the user, rather than committed by pachd.
+const maxParameterCombinations = 10000
+
+// parameterFile is a single datum of a parameter input: one combination of its
+// parameters' values, serialized as a JSON object.
+type parameterFile struct {
+	name    string
+	content []byte
+}
+
+// validateParameterInput checks that a parameter input's parameters are
+// well-formed, and that its grid isn't too large.
+func validateParameterInput(input *pps.ParameterInput) error {
+	if len(input.Parameters) == 0 {
+		return errors.Errorf("parameter input %q must specify at least one parameter", input.Name)
+	}
+	names := make(map[string]bool)
+	combinations := 1
+	for _, p := range input.Parameters {
+		if p.Name == "" {
+			return errors.Errorf("parameter input %q has a parameter without a name", input.Name)
+		}
+		if strings.ContainsAny(p.Name, "/=,") {
+			return errors.Errorf("parameter name %q cannot contain '/', '=' or ','", p.Name)
+		}
+		if names[p.Name] {
+			return errors.Errorf("parameter %q was used more than once in parameter input %q", p.Name, input.Name)
+		}
+		names[p.Name] = true
+		if len(p.Values) == 0 {
+			return errors.Errorf("parameter %q must have at least one value", p.Name)
+		}
+		values := make(map[string]string)
+		for _, v := range p.Values {
+			if other, ok := values[fileNameValue(v)]; ok {
+				return errors.Errorf("values %q and %q of parameter %q would generate datums with the same name", other, v, p.Name)
+			}
+			values[fileNameValue(v)] = v
+		}
+		combinations *= len(p.Values)
+		if combinations > maxParameterCombinations {
+			return errors.Errorf("parameter input %q generates more than %d datums", input.Name, maxParameterCombinations)
+		}
+	}
+	return nil
+}
+
+// parameterValue returns the JSON representation of a parameter value. Values
+// that are already valid JSON are used as-is, and anything else is encoded as a
+// JSON string.
+func parameterValue(value string) (json.RawMessage, error) {
+	if json.Valid([]byte(value)) {
+		return json.RawMessage(value), nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	return json.RawMessage(data), nil
+}
+
+// fileNameValue returns the representation of a parameter value in the names of
+// parameterFiles ('/' can't appear in a file name).
+func fileNameValue(value string) string {
+	return strings.Replace(value, "/", "_", -1)
+}
+
+// parameterFiles returns one parameterFile for each combination of the values
+// of 'input's parameters. Files are named after the values in them (e.g.
+// "learning_rate=0.01,batch_size=32.json"), so that a datum's path, and
+// therefore its ID, doesn't change when other values are added to the grid.
+func parameterFiles(input *pps.ParameterInput) ([]*parameterFile, error) {
+	// values[i][j] is the JSON encoding of the j'th value of parameter i
+	values := make([][]json.RawMessage, len(input.Parameters))
+	for i, p := range input.Parameters {
+		for _, v := range p.Values {
+			value, err := parameterValue(v)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = append(values[i], value)
+		}
+	}
+
+	var result []*parameterFile
+	indices := make([]int, len(input.Parameters))
+	for {
+		var name []string
+		content := &bytes.Buffer{}
+		content.WriteString("{")
+		for i, p := range input.Parameters {
+			if i > 0 {
+				content.WriteString(",")
+			}
+			key, err := json.Marshal(p.Name)
+			if err != nil {
+				return nil, errors.EnsureStack(err)
+			}
+			content.Write(key)
+			content.WriteString(":")
+			content.Write(values[i][indices[i]])
+			name = append(name, p.Name+"="+fileNameValue(p.Values[indices[i]]))
+		}
+		content.WriteString("}\n")
+		result = append(result, &parameterFile{
+			name:    strings.Join(name, ",") + ".json",
+			content: content.Bytes(),
+		})
+
+		// Advance to the next combination, like an odometer
+		i := len(indices) - 1
+		for ; i >= 0; i-- {
+			indices[i]++
+			if indices[i] < len(values[i]) {
+				break
+			}
+			indices[i] = 0
+		}
+		if i < 0 {
+			return result, nil
+		}
+	}
+}
+
+// parameterGrid returns a canonical description of 'input's parameters. It's
+// stored as the description of the commits that pachd makes to the input's
+// repo, so that the commit is only remade when the parameters change.
+func parameterGrid(input *pps.ParameterInput) string {
+	data, err := json.Marshal(input.Parameters)
+	if err != nil {
+		// can't happen, parameters only contain strings
+		panic(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestParameterFiles(t *testing.T) {
+	files, err := parameterFiles(&pps.ParameterInput{
+		Name: "params",
+		Parameters: []*pps.Parameter{
+			{Name: "learning_rate", Values: []string{"0.01", "0.1"}},
+			{Name: "optimizer", Values: []string{"adam", `"sgd"`}},
+		},
+	})
+	require.NoError(t, err)
+	var names, contents []string
+	for _, f := range files {
+		names = append(names, f.name)
+		contents = append(contents, string(f.content))
+	}
+	require.Equal(t, []string{
+		`learning_rate=0.01,optimizer=adam.json`,
+		`learning_rate=0.01,optimizer="sgd".json`,
+		`learning_rate=0.1,optimizer=adam.json`,
+		`learning_rate=0.1,optimizer="sgd".json`,
+	}, names)
+	require.Equal(t, []string{
+		"{\"learning_rate\":0.01,\"optimizer\":\"adam\"}\n",
+		"{\"learning_rate\":0.01,\"optimizer\":\"sgd\"}\n",
+		"{\"learning_rate\":0.1,\"optimizer\":\"adam\"}\n",
+		"{\"learning_rate\":0.1,\"optimizer\":\"sgd\"}\n",
+	}, contents)
+}
+
+func TestValidateParameterInput(t *testing.T) {
+	require.NoError(t, validateParameterInput(&pps.ParameterInput{
+		Name:       "params",
+		Parameters: []*pps.Parameter{{Name: "batch_size", Values: []string{"32", "64"}}},
+	}))
+	// no parameters
+	require.YesError(t, validateParameterInput(&pps.ParameterInput{Name: "params"}))
+	// no values
+	require.YesError(t, validateParameterInput(&pps.ParameterInput{
+		Name:       "params",
+		Parameters: []*pps.Parameter{{Name: "batch_size"}},
+	}))
+	// duplicate parameter
+	require.YesError(t, validateParameterInput(&pps.ParameterInput{
+		Name: "params",
+		Parameters: []*pps.Parameter{
+			{Name: "batch_size", Values: []string{"32"}},
+			{Name: "batch_size", Values: []string{"64"}},
+		},
+	}))
+	// values that would generate the same file
+	require.YesError(t, validateParameterInput(&pps.ParameterInput{
+		Name:       "params",
+		Parameters: []*pps.Parameter{{Name: "dir", Values: []string{"a/b", "a_b"}}},
+	}))
+	// too many combinations
+	var values []string
+	for i := 0; i < 101; i++ {
+		values = append(values, string(rune('a'+i%26))+string(rune('a'+i/26)))
+	}
+	require.YesError(t, validateParameterInput(&pps.ParameterInput{
+		Name: "params",
+		Parameters: []*pps.Parameter{
+			{Name: "x", Values: values},
+			{Name: "y", Values: values},
+		},
+	}))
+}
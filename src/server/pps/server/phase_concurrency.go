@@ -0,0 +1,25 @@
+package server
+
+import (
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// validatePhaseConcurrency returns an error if the PhaseConcurrency spec of
+// 'pipelineInfo' is invalid.
+func validatePhaseConcurrency(pipelineInfo *pps.PipelineInfo) error {
+	phases := pipelineInfo.PhaseConcurrency
+	if phases == nil {
+		return nil
+	}
+	if phases.Download < 0 || phases.Upload < 0 {
+		return errors.New("invalid pipeline spec: phase_concurrency must not be negative")
+	}
+	if pipelineInfo.MaxQueueSize > 1 {
+		return errors.New("invalid pipeline spec: phase_concurrency determines how many datums a worker processes at once, so it can't be set with max_queue_size")
+	}
+	if pipelineInfo.Service != nil || pipelineInfo.Spout != nil {
+		return errors.New("invalid pipeline spec: services and spouts don't process datums, so they can't use phase_concurrency")
+	}
+	return nil
+}
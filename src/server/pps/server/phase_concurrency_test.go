@@ -0,0 +1,26 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidatePhaseConcurrency(t *testing.T) {
+	pipelineInfo := func(phases *pps.PhaseConcurrency) *pps.PipelineInfo {
+		return &pps.PipelineInfo{PhaseConcurrency: phases, MaxQueueSize: 1}
+	}
+	require.NoError(t, validatePhaseConcurrency(pipelineInfo(nil)))
+	require.NoError(t, validatePhaseConcurrency(pipelineInfo(&pps.PhaseConcurrency{})))
+	require.NoError(t, validatePhaseConcurrency(pipelineInfo(&pps.PhaseConcurrency{Download: 4, Upload: 2})))
+	require.YesError(t, validatePhaseConcurrency(pipelineInfo(&pps.PhaseConcurrency{Download: -1})))
+	require.YesError(t, validatePhaseConcurrency(pipelineInfo(&pps.PhaseConcurrency{Upload: -1})))
+
+	queued := pipelineInfo(&pps.PhaseConcurrency{Download: 2})
+	queued.MaxQueueSize = 4
+	require.YesError(t, validatePhaseConcurrency(queued))
+	service := pipelineInfo(&pps.PhaseConcurrency{Download: 2})
+	service.Service = &pps.Service{}
+	require.YesError(t, validatePhaseConcurrency(service))
+}
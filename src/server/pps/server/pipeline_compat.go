@@ -0,0 +1,225 @@
+package server
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// CheckPipelineUpdate implements the protobuf pps.CheckPipelineUpdate RPC
+func (a *apiServer) CheckPipelineUpdate(ctx context.Context, request *pps.CheckPipelineUpdateRequest) (response *pps.CheckPipelineUpdateResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.env.GetPachClient(ctx)
+	update := request.Update
+	if update == nil || update.Pipeline == nil || update.Pipeline.Name == "" {
+		return nil, errors.New("request.Update.Pipeline cannot be empty")
+	}
+	response = &pps.CheckPipelineUpdateResponse{}
+	oldInfo, err := a.inspectPipeline(pachClient, update.Pipeline.Name)
+	if err != nil {
+		if isNotFoundErr(err) {
+			return response, nil // creating a pipeline can't break anything
+		}
+		return nil, err
+	}
+	newInfo := pipelineInfoFromRequest(update)
+	if newInfo.Transform == nil {
+		newInfo.Transform = &pps.Transform{}
+	}
+	if err := setPipelineDefaults(newInfo); err != nil {
+		return nil, err
+	}
+	var downstream []*pps.PipelineInfo
+	if err := a.listPipeline(pachClient, &pps.ListPipelineRequest{}, func(pipelineInfo *pps.PipelineInfo) error {
+		if pipelineInfo.Pipeline.Name != oldInfo.Pipeline.Name {
+			downstream = append(downstream, pipelineInfo)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	response.Incompatibilities = checkUpdateCompatibility(oldInfo, newInfo, downstream)
+	return response, nil
+}
+
+// checkUpdateCompatibility returns the ways in which updating the pipeline
+// 'oldInfo' to 'newInfo' may invalidate the assumptions of the pipelines in
+// 'pipelineInfos' that read its output (other pipelines are ignored).
+// Both 'oldInfo' and 'newInfo' must have their defaults set.
+func checkUpdateCompatibility(oldInfo, newInfo *pps.PipelineInfo, pipelineInfos []*pps.PipelineInfo) []*pps.UpdateIncompatibility {
+	var result []*pps.UpdateIncompatibility
+	add := func(kind pps.UpdateIncompatibility_Kind, blocking bool, downstream string, format string, args ...interface{}) {
+		result = append(result, &pps.UpdateIncompatibility{
+			Kind:               kind,
+			Message:            fmt.Sprintf(format, args...),
+			Blocking:           blocking,
+			DownstreamPipeline: downstream,
+		})
+	}
+
+	// Datum shape
+	if oldShape, newShape := inputShape(oldInfo.Input), inputShape(newInfo.Input); oldShape != newShape {
+		add(pps.UpdateIncompatibility_DATUM_SHAPE, false, "",
+			"the input's structure changes from %s to %s", oldShape, newShape)
+	}
+	oldInputs, newInputs := pfsInputs(oldInfo.Input), pfsInputs(newInfo.Input)
+	for _, name := range sortedKeys(oldInputs) {
+		oldInput, newInput := oldInputs[name], newInputs[name]
+		if newInput == nil {
+			add(pps.UpdateIncompatibility_DATUM_SHAPE, false, "", "input %q is removed", name)
+			continue
+		}
+		for _, field := range []struct{ name, old, new string }{
+			{"repo", oldInput.Repo, newInput.Repo},
+			{"branch", oldInput.Branch, newInput.Branch},
+			{"glob", oldInput.Glob, newInput.Glob},
+			{"join_on", oldInput.JoinOn, newInput.JoinOn},
+			{"group_by", oldInput.GroupBy, newInput.GroupBy},
+		} {
+			if field.old != field.new {
+				add(pps.UpdateIncompatibility_DATUM_SHAPE, false, "",
+					"the %s of input %q changes from %q to %q", field.name, name, field.old, field.new)
+			}
+		}
+	}
+	for _, name := range sortedKeys(newInputs) {
+		if oldInputs[name] == nil {
+			add(pps.UpdateIncompatibility_DATUM_SHAPE, false, "", "input %q is added", name)
+		}
+	}
+
+	// Output paths
+	outputChanged := transformChanged(oldInfo.Transform, newInfo.Transform)
+	if outputChanged {
+		add(pps.UpdateIncompatibility_OUTPUT_PATHS, false, "",
+			"the transform changes, so the paths it writes may change too")
+	}
+
+	// Downstream references
+	newOutputs := make(map[string]*pps.PipelineOutput)
+	for _, output := range newInfo.Outputs {
+		newOutputs[output.Repo] = output
+	}
+	for _, pipelineInfo := range pipelineInfos {
+		downstream := pipelineInfo.Pipeline.Name
+		pps.VisitInput(pipelineInfo.Input, func(input *pps.Input) {
+			if input.Pfs == nil {
+				return
+			}
+			in := input.Pfs
+			if in.Repo == oldInfo.Pipeline.Name {
+				if in.Branch == oldInfo.OutputBranch && newInfo.OutputBranch != oldInfo.OutputBranch {
+					add(pps.UpdateIncompatibility_DOWNSTREAM_REFERENCE, true, downstream,
+						"pipeline %q reads output branch %q, but the output branch changes to %q",
+						downstream, in.Branch, newInfo.OutputBranch)
+				} else if outputChanged && in.Glob != "/" {
+					add(pps.UpdateIncompatibility_DOWNSTREAM_REFERENCE, false, downstream,
+						"pipeline %q reads the output with glob %q, which may no longer match what the new transform writes",
+						downstream, in.Glob)
+				}
+				return
+			}
+			for _, oldOutput := range oldInfo.Outputs {
+				if in.Repo != oldOutput.Repo || in.Branch != oldOutput.Branch {
+					continue
+				}
+				newOutput := newOutputs[oldOutput.Repo]
+				switch {
+				case newOutput == nil:
+					add(pps.UpdateIncompatibility_DOWNSTREAM_REFERENCE, true, downstream,
+						"pipeline %q reads output %q, which is removed", downstream, oldOutput.Name)
+				case newOutput.Branch != oldOutput.Branch:
+					add(pps.UpdateIncompatibility_DOWNSTREAM_REFERENCE, true, downstream,
+						"pipeline %q reads branch %q of output %q, but its branch changes to %q",
+						downstream, oldOutput.Branch, oldOutput.Name, newOutput.Branch)
+				case outputChanged && in.Glob != "/":
+					add(pps.UpdateIncompatibility_DOWNSTREAM_REFERENCE, false, downstream,
+						"pipeline %q reads output %q with glob %q, which may no longer match what the new transform writes",
+						downstream, oldOutput.Name, in.Glob)
+				}
+			}
+		})
+	}
+	return result
+}
+
+// inputShape describes how 'input' combines its inputs, e.g.
+// "cross(pfs(a), union(pfs(b), cron(tick)))"
+func inputShape(input *pps.Input) string {
+	shape := func(op string, inputs []*pps.Input) string {
+		var shapes []string
+		for _, input := range inputs {
+			shapes = append(shapes, inputShape(input))
+		}
+		return fmt.Sprintf("%s(%s)", op, strings.Join(shapes, ", "))
+	}
+	switch {
+	case input == nil:
+		return "none"
+	case input.Cross != nil:
+		return shape("cross", input.Cross)
+	case input.Join != nil:
+		return shape("join", input.Join)
+	case input.Group != nil:
+		return shape("group", input.Group)
+	case input.Union != nil:
+		return shape("union", input.Union)
+	case input.Pfs != nil:
+		return fmt.Sprintf("pfs(%s)", input.Pfs.Name)
+	case input.Cron != nil:
+		return fmt.Sprintf("cron(%s)", input.Cron.Name)
+	case input.Git != nil:
+		return fmt.Sprintf("git(%s)", input.Git.Name)
+	case input.HTTPMirror != nil:
+		return fmt.Sprintf("http_mirror(%s)", input.HTTPMirror.Name)
+	case input.Parameter != nil:
+		return fmt.Sprintf("parameter(%s)", input.Parameter.Name)
+	case input.RemoteRepo != nil:
+		return fmt.Sprintf("remote_repo(%s)", input.RemoteRepo.Name)
+	case input.Volume != nil:
+		return fmt.Sprintf("volume(%s)", input.Volume.Name)
+	case input.DBImport != nil:
+		return fmt.Sprintf("db_import(%s)", input.DBImport.Name)
+	}
+	return "unknown"
+}
+
+// pfsInputs returns the pfs inputs in 'input', by name
+func pfsInputs(input *pps.Input) map[string]*pps.PFSInput {
+	result := make(map[string]*pps.PFSInput)
+	pps.VisitInput(input, func(input *pps.Input) {
+		if input.Pfs != nil {
+			result[input.Pfs.Name] = input.Pfs
+		}
+	})
+	return result
+}
+
+func sortedKeys(m map[string]*pps.PFSInput) []string {
+	var result []string
+	for key := range m {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// transformChanged returns true if the parts of a transform that determine
+// what it writes differ between 'a' and 'b'
+func transformChanged(a, b *pps.Transform) bool {
+	if a == nil || b == nil {
+		return a != b
+	}
+	return a.Image != b.Image ||
+		!reflect.DeepEqual(a.Cmd, b.Cmd) ||
+		!reflect.DeepEqual(a.Stdin, b.Stdin) ||
+		!reflect.DeepEqual(a.Env, b.Env)
+}
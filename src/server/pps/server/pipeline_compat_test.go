@@ -0,0 +1,128 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func compatPipeline(name string, input *pps.Input, cmd ...string) *pps.PipelineInfo {
+	return &pps.PipelineInfo{
+		Pipeline:     client.NewPipeline(name),
+		Transform:    &pps.Transform{Image: DefaultUserImage, Cmd: cmd},
+		Input:        input,
+		OutputBranch: "master",
+	}
+}
+
+// compatInput returns a pfs input with its defaults set
+func compatInput(repo, glob string) *pps.Input {
+	return client.NewPFSInputOpts(repo, repo, "master", glob, "", "", false)
+}
+
+func compatKinds(incompatibilities []*pps.UpdateIncompatibility) (kinds []pps.UpdateIncompatibility_Kind, blocking bool) {
+	for _, incompatibility := range incompatibilities {
+		kinds = append(kinds, incompatibility.Kind)
+		blocking = blocking || incompatibility.Blocking
+	}
+	return kinds, blocking
+}
+
+func TestCheckUpdateCompatibility(t *testing.T) {
+	old := compatPipeline("p", client.NewCrossInput(
+		compatInput("a", "/*"),
+		compatInput("b", "/"),
+	), "cp", "-r", "/pfs/a", "/pfs/out")
+	downstream := []*pps.PipelineInfo{
+		compatPipeline("root-reader", compatInput("p", "/")),
+		compatPipeline("unrelated", compatInput("a", "/*")),
+	}
+
+	// No changes
+	same := compatPipeline("p", client.NewCrossInput(
+		compatInput("a", "/*"),
+		compatInput("b", "/"),
+	), "cp", "-r", "/pfs/a", "/pfs/out")
+	require.Equal(t, 0, len(checkUpdateCompatibility(old, same, downstream)))
+
+	// A different glob changes the datum shape
+	newGlob := compatPipeline("p", client.NewCrossInput(
+		compatInput("a", "/*/*"),
+		compatInput("b", "/"),
+	), "cp", "-r", "/pfs/a", "/pfs/out")
+	kinds, blocking := compatKinds(checkUpdateCompatibility(old, newGlob, downstream))
+	require.Equal(t, []pps.UpdateIncompatibility_Kind{pps.UpdateIncompatibility_DATUM_SHAPE}, kinds)
+	require.False(t, blocking)
+
+	// So does replacing an input
+	newInputs := compatPipeline("p", client.NewCrossInput(
+		compatInput("a", "/*"),
+		compatInput("c", "/"),
+	), "cp", "-r", "/pfs/a", "/pfs/out")
+	incompatibilities := checkUpdateCompatibility(old, newInputs, downstream)
+	kinds, _ = compatKinds(incompatibilities)
+	require.Equal(t, []pps.UpdateIncompatibility_Kind{
+		pps.UpdateIncompatibility_DATUM_SHAPE, // cross(a, b) -> cross(a, c)
+		pps.UpdateIncompatibility_DATUM_SHAPE, // b is removed
+		pps.UpdateIncompatibility_DATUM_SHAPE, // c is added
+	}, kinds)
+	require.Matches(t, `input "b" is removed`, incompatibilities[1].Message)
+
+	// A new transform may write different paths, which matters to downstream
+	// pipelines that glob the output
+	newCmd := compatPipeline("p", old.Input, "cp", "-r", "/pfs/b", "/pfs/out")
+	globReader := compatPipeline("glob-reader", compatInput("p", "/*"))
+	incompatibilities = checkUpdateCompatibility(old, newCmd, append(downstream, globReader))
+	kinds, blocking = compatKinds(incompatibilities)
+	require.Equal(t, []pps.UpdateIncompatibility_Kind{
+		pps.UpdateIncompatibility_OUTPUT_PATHS,
+		pps.UpdateIncompatibility_DOWNSTREAM_REFERENCE,
+	}, kinds)
+	require.False(t, blocking)
+	require.Equal(t, "glob-reader", incompatibilities[1].DownstreamPipeline)
+
+	// Moving the output branch breaks downstream pipelines
+	newBranch := compatPipeline("p", old.Input, "cp", "-r", "/pfs/a", "/pfs/out")
+	newBranch.OutputBranch = "staging"
+	incompatibilities = checkUpdateCompatibility(old, newBranch, downstream)
+	kinds, blocking = compatKinds(incompatibilities)
+	require.Equal(t, []pps.UpdateIncompatibility_Kind{pps.UpdateIncompatibility_DOWNSTREAM_REFERENCE}, kinds)
+	require.True(t, blocking)
+	require.Equal(t, "root-reader", incompatibilities[0].DownstreamPipeline)
+}
+
+func TestCheckUpdateCompatibilityOutputs(t *testing.T) {
+	withOutputs := func(names ...string) *pps.PipelineInfo {
+		pipelineInfo := compatPipeline("p", compatInput("a", "/*"), "true")
+		for _, name := range names {
+			pipelineInfo.Outputs = append(pipelineInfo.Outputs, &pps.PipelineOutput{
+				Name:   name,
+				Repo:   "p_" + name,
+				Branch: "master",
+			})
+		}
+		return pipelineInfo
+	}
+	downstream := []*pps.PipelineInfo{
+		compatPipeline("metrics-reader", compatInput("p_metrics", "/")),
+	}
+
+	// Removing an output that nothing reads is fine
+	require.Equal(t, 0, len(checkUpdateCompatibility(
+		withOutputs("metrics", "logs"), withOutputs("metrics"), downstream)))
+
+	// Removing an output that a downstream pipeline reads isn't
+	incompatibilities := checkUpdateCompatibility(withOutputs("metrics", "logs"), withOutputs("logs"), downstream)
+	kinds, blocking := compatKinds(incompatibilities)
+	require.Equal(t, []pps.UpdateIncompatibility_Kind{pps.UpdateIncompatibility_DOWNSTREAM_REFERENCE}, kinds)
+	require.True(t, blocking)
+	require.Equal(t, "metrics-reader", incompatibilities[0].DownstreamPipeline)
+
+	// Nor is moving its branch
+	moved := withOutputs("metrics")
+	moved.Outputs[0].Branch = "v2"
+	_, blocking = compatKinds(checkUpdateCompatibility(withOutputs("metrics"), moved, downstream))
+	require.True(t, blocking)
+}
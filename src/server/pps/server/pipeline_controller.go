@@ -161,6 +161,12 @@ func (op *pipelineOp) run() error {
 		}
 
 		op.startPipelineMonitor()
+		if err := op.resetJobScaling(); err != nil {
+			return err
+		}
+		if err := op.resetMemoryEscalation(); err != nil {
+			return err
+		}
 		// default: scale up if pipeline start hasn't propagated to etcd yet
 		// Note: mostly this should do nothing, as this runs several times per job
 		return op.scaleUpPipeline()
@@ -173,6 +179,9 @@ func (op *pipelineOp) run() error {
 		}
 		// default: scale down if standby hasn't propagated to kube RC yet
 		op.startPipelineMonitor()
+		if err := op.resetMemoryEscalation(); err != nil {
+			return err
+		}
 		return op.scaleDownPipeline()
 	case pps.PipelineState_PIPELINE_PAUSED:
 		if !op.rcIsFresh() {
@@ -259,7 +268,9 @@ func (op *pipelineOp) getRC(expectation rcExpectation) (retErr error) {
 
 	kubeClient := op.apiServer.env.GetKubeClient()
 	namespace := op.apiServer.namespace
-	selector := fmt.Sprintf("%s=%s", pipelineNameLabel, op.name)
+	// The RCs of the pipeline's worker classes (see pps.DatumSizing) are
+	// managed along with its own RC
+	selector := fmt.Sprintf("%s=%s,!%s", pipelineNameLabel, op.name, workerClassLabel)
 
 	// count error types separately, so that this only errors if the pipeline is
 	// stuck and not changing
@@ -344,6 +355,7 @@ func (op *pipelineOp) rcIsFresh() bool {
 	rcPachVersion := op.rc.ObjectMeta.Annotations[pachVersionAnnotation]
 	rcAuthTokenHash := op.rc.ObjectMeta.Annotations[hashedAuthTokenAnnotation]
 	rcSpecCommit := op.rc.ObjectMeta.Annotations[specCommitAnnotation]
+	rcEscalatedMemory := op.rc.ObjectMeta.Annotations[escalatedMemoryAnnotation]
 	switch {
 	case rcAuthTokenHash != hashAuthToken(op.ptr.AuthToken):
 		log.Errorf("PPS master: auth token in %q is stale %s != %s",
@@ -353,6 +365,10 @@ func (op *pipelineOp) rcIsFresh() bool {
 		log.Errorf("PPS master: spec commit in %q looks stale %s != %s",
 			op.name, rcSpecCommit, op.ptr.SpecCommit.ID)
 		return false
+	case rcEscalatedMemory != op.ptr.EscalatedMemory:
+		log.Errorf("PPS master: escalated memory of %q is stale %q != %q",
+			op.name, rcEscalatedMemory, op.ptr.EscalatedMemory)
+		return false
 	case rcPachVersion != version.PrettyVersion():
 		log.Errorf("PPS master: %q is using stale pachd v%s != current v%s",
 			op.name, rcPachVersion, version.PrettyVersion())
@@ -464,7 +480,7 @@ func (op *pipelineOp) finishPipelineOutputCommits() (retErr error) {
 	}
 
 	return op.apiServer.sudo(pachClient, func(superUserClient *client.APIClient) error {
-		commitInfos, err := superUserClient.ListCommit(op.name, op.pipelineInfo.OutputBranch, "", 0)
+		commitInfos, err := superUserClient.ListCommit(op.name, ppsutil.JobOutputBranch(op.pipelineInfo), "", 0)
 		if isNotFoundErr(err) {
 			return nil // already deleted
 		}
@@ -571,13 +587,16 @@ func (op *pipelineOp) scaleUpPipeline() (retErr error) {
 	}
 
 	// update pipeline RC
-	return op.updateRC(func(rc *v1.ReplicationController) {
+	if err := op.updateRC(func(rc *v1.ReplicationController) {
 		if rc.Spec.Replicas != nil && *op.rc.Spec.Replicas == int32(parallelism) {
 			return // prior attempt succeeded
 		}
 		rc.Spec.Replicas = new(int32)
 		*rc.Spec.Replicas = int32(parallelism)
-	})
+	}); err != nil {
+		return err
+	}
+	return op.scaleWorkerClasses(true)
 }
 
 // scaleDownPipeline edits the RC associated with op's pipeline & spins down the
@@ -597,12 +616,15 @@ func (op *pipelineOp) scaleDownPipeline() (retErr error) {
 		tracing.FinishAnySpan(span)
 	}()
 
-	return op.updateRC(func(rc *v1.ReplicationController) {
+	if err := op.updateRC(func(rc *v1.ReplicationController) {
 		if rc.Spec.Replicas != nil && *op.rc.Spec.Replicas == 0 {
 			return // prior attempt succeeded
 		}
 		rc.Spec.Replicas = &zero
-	})
+	}); err != nil {
+		return err
+	}
+	return op.scaleWorkerClasses(false)
 }
 
 // restartPipeline updates the RC/service associated with op's pipeline, and
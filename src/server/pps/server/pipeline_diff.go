@@ -0,0 +1,79 @@
+package server
+
+import (
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+
+	"golang.org/x/net/context"
+)
+
+// DiffPipeline implements the protobuf pps.DiffPipeline RPC
+func (a *apiServer) DiffPipeline(ctx context.Context, request *pps.DiffPipelineRequest) (response *pps.DiffPipelineResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.env.GetPachClient(ctx)
+	if _, err := checkLoggedIn(pachClient); err != nil {
+		return nil, err
+	}
+	if request.Pipeline == nil || request.Pipeline.Name == "" {
+		return nil, errors.New("request.Pipeline cannot be empty")
+	}
+	versionA, versionB := request.VersionA, request.VersionB
+	if versionA != 0 && versionB != 0 && versionA >= versionB {
+		return nil, errors.Errorf("version_a (%d) must be before version_b (%d)", versionA, versionB)
+	}
+	infoA, infoB, err := a.pipelineVersions(pachClient, request.Pipeline.Name, versionA, versionB)
+	if err != nil {
+		return nil, err
+	}
+	diffs, err := ppsutil.DiffPipelineSpecs(ppsutil.PipelineReqFromInfo(infoA), ppsutil.PipelineReqFromInfo(infoB))
+	if err != nil {
+		return nil, err
+	}
+	return &pps.DiffPipelineResponse{
+		VersionA: infoA.Version,
+		VersionB: infoB.Version,
+		Diffs:    diffs,
+		Summary:  ppsutil.PipelineDiffSummary(request.Pipeline.Name, infoA.Version, infoB.Version, diffs),
+	}, nil
+}
+
+// pipelineVersions returns versions 'a' and 'b' of 'pipeline', which are
+// read from its spec commits, newest first. If 'b' is 0, it's the current
+// version, and if 'a' is 0, it's the version before 'b'.
+func (a *apiServer) pipelineVersions(pachClient *client.APIClient, pipeline string, versionA, versionB uint64) (*pps.PipelineInfo, *pps.PipelineInfo, error) {
+	var infoA, infoB *pps.PipelineInfo
+	if err := a.listPipelinePtr(pachClient, client.NewPipeline(pipeline), -1, func(name string, ptr *pps.EtcdPipelineInfo) error {
+		pipelineInfo, err := ppsutil.GetPipelineInfo(pachClient, name, ptr)
+		if err != nil {
+			return err
+		}
+		if infoB == nil {
+			if versionB == 0 || pipelineInfo.Version == versionB {
+				infoB = pipelineInfo
+			}
+			return nil
+		}
+		if versionA == 0 || pipelineInfo.Version == versionA {
+			infoA = pipelineInfo
+			return errutil.ErrBreak
+		}
+		return nil
+	}); err != nil && !errors.Is(err, errutil.ErrBreak) {
+		return nil, nil, err
+	}
+	switch {
+	case infoB == nil:
+		return nil, nil, errors.Errorf("pipeline %q has no version %d", pipeline, versionB)
+	case infoA == nil && versionA == 0:
+		return nil, nil, errors.Errorf("version %d of pipeline %q is its first version", infoB.Version, pipeline)
+	case infoA == nil:
+		return nil, nil, errors.Errorf("pipeline %q has no version %d before version %d", pipeline, versionA, infoB.Version)
+	}
+	return infoA, infoB, nil
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"path"
+
+	glob "github.com/pachyderm/ohmyglob"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ancestry"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+)
+
+// validateOutputs checks that a pipeline's named outputs (see PipelineOutput)
+// are well-formed, and that their repos don't collide with the pipeline's
+// output repo, its inputs, or each other. It must be called after
+// setPipelineDefaults.
+func validateOutputs(pipelineInfo *pps.PipelineInfo) error {
+	if len(pipelineInfo.Outputs) == 0 {
+		return nil
+	}
+	if pipelineInfo.Spout != nil {
+		return errors.New("invalid pipeline spec: spouts cannot have named outputs")
+	}
+	if pipelineInfo.S3Out {
+		return errors.New("invalid pipeline spec: pipelines with s3_out cannot have named outputs")
+	}
+	inputRepos := make(map[string]bool)
+	for _, branch := range branchProvenance(pipelineInfo.Input) {
+		inputRepos[branch.Repo.Name] = true
+	}
+	names := make(map[string]bool)
+	repos := map[string]bool{pipelineInfo.Pipeline.Name: true}
+	for _, output := range pipelineInfo.Outputs {
+		if output.Name == "" {
+			return errors.New("invalid pipeline spec: named outputs must have a name")
+		}
+		if err := ancestry.ValidateName(output.Name); err != nil {
+			return errors.Wrapf(err, "invalid output name")
+		}
+		if names[output.Name] {
+			return errors.Errorf("invalid pipeline spec: output name %q was used more than once", output.Name)
+		}
+		names[output.Name] = true
+		if err := ancestry.ValidateName(output.Repo); err != nil {
+			return errors.Wrapf(err, "invalid repo for output %q", output.Name)
+		}
+		if repos[output.Repo] {
+			return errors.Errorf("invalid pipeline spec: output %q would write to repo %q, "+
+				"which is already an output of the pipeline", output.Name, output.Repo)
+		}
+		repos[output.Repo] = true
+		if inputRepos[output.Repo] {
+			return errors.Errorf("invalid pipeline spec: output %q would write to repo %q, "+
+				"which is an input of the pipeline", output.Name, output.Repo)
+		}
+	}
+	return nil
+}
+
+// outputBranches returns the branches that a pipeline's named outputs are
+// committed to.
+func outputBranches(pipelineInfo *pps.PipelineInfo) []*pfs.Branch {
+	var result []*pfs.Branch
+	for _, output := range pipelineInfo.Outputs {
+		result = append(result, client.NewBranch(output.Repo, output.Branch))
+	}
+	return result
+}
+
+// setOutputProvenance sets the provenance of each of a pipeline's named output
+// branches to 'provenance' (which is nil when the pipeline is stopped, so that
+// no new output commits are created).
+func setOutputProvenance(pachClient *client.APIClient, pipelineInfo *pps.PipelineInfo, provenance []*pfs.Branch) error {
+	for _, branch := range outputBranches(pipelineInfo) {
+		if err := pachClient.CreateBranch(branch.Repo.Name, branch.Name, branch.Name, provenance); err != nil {
+			return errors.Wrapf(err, "could not update provenance of output branch %s@%s", branch.Repo.Name, branch.Name)
+		}
+	}
+	return nil
+}
+
+// stopOutputBranches removes the provenance of 'branches' (a pipeline's named
+// output branches) and finishes any of their open commits, which would
+// otherwise never be finished.
+func stopOutputBranches(pachClient *client.APIClient, branches []*pfs.Branch) error {
+	for _, branch := range branches {
+		if err := pachClient.CreateBranch(branch.Repo.Name, branch.Name, branch.Name, nil); err != nil {
+			if isNotFoundErr(err) {
+				continue
+			}
+			return errors.Wrapf(err, "could not recreate output branch %s@%s", branch.Repo.Name, branch.Name)
+		}
+		if err := pachClient.ListCommitF(branch.Repo.Name, branch.Name, "", 0, false, func(ci *pfs.CommitInfo) error {
+			if ci.Finished != nil {
+				return nil
+			}
+			_, err := pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(), &pfs.FinishCommitRequest{
+				Commit: ci.Commit,
+				Empty:  true,
+			})
+			return err
+		}); err != nil {
+			return errors.Wrapf(err, "could not finish open commits on output branch %s@%s", branch.Repo.Name, branch.Name)
+		}
+	}
+	return nil
+}
+
+// validateOutputRoutes checks that a pipeline's output routes (see
+// OutputRoute) are well-formed, and that their branches don't collide with the
+// pipeline's other branches or each other. It must be called after
+// setPipelineDefaults.
+func validateOutputRoutes(pipelineInfo *pps.PipelineInfo) error {
+	if len(pipelineInfo.OutputRoutes) == 0 {
+		return nil
+	}
+	if pipelineInfo.Spout != nil || pipelineInfo.Service != nil {
+		return errors.New("invalid pipeline spec: spouts and services cannot route their output")
+	}
+	if pipelineInfo.S3Out {
+		return errors.New("invalid pipeline spec: pipelines with s3_out cannot route their output")
+	}
+	if pipelineInfo.Gated {
+		return errors.New("invalid pipeline spec: gated pipelines cannot route their output")
+	}
+	branches := map[string]bool{
+		pipelineInfo.OutputBranch:                       true,
+		ppsutil.RoutedBranch(pipelineInfo.OutputBranch): true,
+		"stats": true,
+	}
+	for _, route := range pipelineInfo.OutputRoutes {
+		if err := ancestry.ValidateName(route.Branch); err != nil {
+			return errors.Wrapf(err, "invalid output route branch")
+		}
+		if branches[route.Branch] {
+			return errors.Errorf("invalid pipeline spec: output route branch %q is "+
+				"already used by the pipeline", route.Branch)
+		}
+		branches[route.Branch] = true
+		if _, err := glob.Compile(path.Clean("/"+route.Glob), '/'); err != nil {
+			return errors.Wrapf(err, "invalid pipeline spec: could not parse output route glob %q", route.Glob)
+		}
+	}
+	return nil
+}
+
+// createRouteBranches creates the output branch and route branches of a
+// pipeline that routes its output, if they don't exist yet. The branches have
+// no provenance (jobs commit to the routed branch instead), so they only move
+// when a job's routed output is copied to them.
+func createRouteBranches(pachClient *client.APIClient, pipelineInfo *pps.PipelineInfo) error {
+	for _, branch := range ppsutil.OutputRouteBranches(pipelineInfo) {
+		_, err := pachClient.InspectBranch(pipelineInfo.Pipeline.Name, branch)
+		if err == nil {
+			continue
+		}
+		if !isNotFoundErr(err) {
+			return err
+		}
+		if err := pachClient.CreateBranch(pipelineInfo.Pipeline.Name, branch, "", nil); err != nil {
+			return errors.Wrapf(err, "could not create output route branch %q", branch)
+		}
+	}
+	return nil
+}
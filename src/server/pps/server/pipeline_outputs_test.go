@@ -0,0 +1,83 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateOutputs(t *testing.T) {
+	pipelineInfo := func(outputs ...*pps.PipelineOutput) *pps.PipelineInfo {
+		return &pps.PipelineInfo{
+			Pipeline: client.NewPipeline("train"),
+			Input:    client.NewPFSInput("in", "/*"),
+			Outputs:  outputs,
+		}
+	}
+	require.NoError(t, validateOutputs(pipelineInfo()))
+	require.NoError(t, validateOutputs(pipelineInfo(
+		&pps.PipelineOutput{Name: "model", Repo: "train_model", Branch: "master"},
+		&pps.PipelineOutput{Name: "metrics", Repo: "train_metrics", Branch: "master"},
+	)))
+	// no name
+	require.YesError(t, validateOutputs(pipelineInfo(
+		&pps.PipelineOutput{Repo: "train_model", Branch: "master"},
+	)))
+	// duplicate name
+	require.YesError(t, validateOutputs(pipelineInfo(
+		&pps.PipelineOutput{Name: "model", Repo: "train_model", Branch: "master"},
+		&pps.PipelineOutput{Name: "model", Repo: "models", Branch: "master"},
+	)))
+	// duplicate repo
+	require.YesError(t, validateOutputs(pipelineInfo(
+		&pps.PipelineOutput{Name: "model", Repo: "models", Branch: "master"},
+		&pps.PipelineOutput{Name: "metrics", Repo: "models", Branch: "master"},
+	)))
+	// the pipeline's own output repo
+	require.YesError(t, validateOutputs(pipelineInfo(
+		&pps.PipelineOutput{Name: "model", Repo: "train", Branch: "master"},
+	)))
+	// an input repo
+	require.YesError(t, validateOutputs(pipelineInfo(
+		&pps.PipelineOutput{Name: "model", Repo: "in", Branch: "master"},
+	)))
+	// spout
+	spout := pipelineInfo(&pps.PipelineOutput{Name: "model", Repo: "train_model", Branch: "master"})
+	spout.Input = nil
+	spout.Spout = &pps.Spout{}
+	require.YesError(t, validateOutputs(spout))
+}
+
+func TestValidateOutputRoutes(t *testing.T) {
+	pipelineInfo := func(routes ...*pps.OutputRoute) *pps.PipelineInfo {
+		return &pps.PipelineInfo{
+			Pipeline:     client.NewPipeline("train"),
+			Input:        client.NewPFSInput("in", "/*"),
+			OutputBranch: "master",
+			OutputRoutes: routes,
+		}
+	}
+	require.NoError(t, validateOutputRoutes(pipelineInfo()))
+	require.NoError(t, validateOutputRoutes(pipelineInfo(
+		&pps.OutputRoute{Branch: "staging", Glob: "/new/*"},
+		&pps.OutputRoute{Branch: "review", Glob: "/**.tmp"},
+	)))
+	// duplicate branch
+	require.YesError(t, validateOutputRoutes(pipelineInfo(
+		&pps.OutputRoute{Branch: "staging", Glob: "/new/*"},
+		&pps.OutputRoute{Branch: "staging", Glob: "/old/*"},
+	)))
+	// the pipeline's own branches
+	for _, branch := range []string{"master", "master-routed", "stats"} {
+		require.YesError(t, validateOutputRoutes(pipelineInfo(&pps.OutputRoute{Branch: branch, Glob: "/*"})))
+	}
+	// invalid branch or glob
+	require.YesError(t, validateOutputRoutes(pipelineInfo(&pps.OutputRoute{Glob: "/*"})))
+	require.YesError(t, validateOutputRoutes(pipelineInfo(&pps.OutputRoute{Branch: "staging", Glob: "/["})))
+	// gated
+	gated := pipelineInfo(&pps.OutputRoute{Branch: "staging", Glob: "/*"})
+	gated.Gated = true
+	require.YesError(t, validateOutputRoutes(gated))
+}
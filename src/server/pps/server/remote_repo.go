@@ -0,0 +1,146 @@
+package server
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube "k8s.io/client-go/kubernetes"
+)
+
+// remoteRepoState is stored (as JSON) in the description of each commit made
+// by a remote_repo input, so that mirroring resumes from the right remote
+// commit after pachd restarts.
+type remoteRepoState struct {
+	// SourceCommit is the ID of the remote commit that the local commit mirrors
+	SourceCommit string `json:"source_commit"`
+}
+
+// readRemoteRepoState extracts the remoteRepoState from the description of the
+// most recent commit to a remote_repo input's repo. An empty state is returned
+// if 'commitInfo' is nil or its description can't be parsed, in which case the
+// whole remote branch is copied again.
+func readRemoteRepoState(commitInfo *pfs.CommitInfo) *remoteRepoState {
+	state := &remoteRepoState{}
+	if commitInfo == nil || commitInfo.Description == "" {
+		return state
+	}
+	if err := json.Unmarshal([]byte(commitInfo.Description), state); err != nil {
+		return &remoteRepoState{}
+	}
+	return state
+}
+
+func (s *remoteRepoState) String() string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		// can't happen, remoteRepoState only contains strings
+		panic(err)
+	}
+	return string(data)
+}
+
+// newRemoteRepoClient connects to the cluster that hosts a remote_repo input's
+// source repo, authenticating with the token in the input's token secret (if
+// it has one).
+func newRemoteRepoClient(kubeClient kube.Interface, namespace string, input *pps.RemoteRepoInput) (*client.APIClient, error) {
+	addr, err := grpcutil.ParsePachdAddress(input.PachdAddress)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid remote_repo pachd_address")
+	}
+	var options []client.Option
+	if addr.Secured {
+		options = append(options, client.WithSystemCAs)
+	}
+	remote, err := client.NewFromAddress(addr.Hostname(), options...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not connect to %s", input.PachdAddress)
+	}
+	if input.TokenSecret == "" {
+		return remote, nil
+	}
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(input.TokenSecret, metav1.GetOptions{})
+	if err != nil {
+		remote.Close()
+		return nil, errors.Wrapf(err, "could not get remote_repo token secret %q", input.TokenSecret)
+	}
+	token, ok := secret.Data["token"]
+	if !ok {
+		remote.Close()
+		return nil, errors.Errorf("remote_repo token secret %q has no \"token\" key", input.TokenSecret)
+	}
+	remote.SetAuthToken(strings.TrimSpace(string(token)))
+	return remote, nil
+}
+
+// syncRemoteCommit makes the open commit 'localCommit' in 'input's repo
+// match the remote commit 'sourceCommit'. If 'prevSourceCommit' (the remote
+// commit mirrored by the local commit's parent) is set, only the files that
+// changed between the two remote commits are copied.
+func syncRemoteCommit(local, remote *client.APIClient, input *pps.RemoteRepoInput, localCommit, sourceCommit, prevSourceCommit string) error {
+	if prevSourceCommit != "" {
+		newFiles, oldFiles, err := remote.DiffFile(input.SourceRepo, sourceCommit, "/",
+			input.SourceRepo, prevSourceCommit, "/", false)
+		if err == nil {
+			written := make(map[string]bool)
+			for _, fi := range newFiles {
+				if fi.FileType != pfs.FileType_FILE {
+					continue
+				}
+				if err := copyRemoteFile(local, remote, input, localCommit, sourceCommit, fi.File.Path); err != nil {
+					return err
+				}
+				written[fi.File.Path] = true
+			}
+			for _, fi := range oldFiles {
+				if fi.FileType != pfs.FileType_FILE || written[fi.File.Path] {
+					continue
+				}
+				if err := local.DeleteFile(input.Repo, localCommit, fi.File.Path); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		// The previous commit may have been deleted from the remote cluster, in
+		// which case the whole commit is copied
+		log.Warnf("PPS master: could not diff %s@%s against %s@%s, copying the "+
+			"whole commit: %v", input.SourceRepo, sourceCommit, input.SourceRepo,
+			prevSourceCommit, err)
+	}
+	fileInfos, err := local.ListFile(input.Repo, localCommit, "/")
+	if err != nil {
+		return err
+	}
+	for _, fi := range fileInfos {
+		if err := local.DeleteFile(input.Repo, localCommit, fi.File.Path); err != nil {
+			return err
+		}
+	}
+	return remote.Walk(input.SourceRepo, sourceCommit, "/", func(fi *pfs.FileInfo) error {
+		if fi.FileType != pfs.FileType_FILE {
+			return nil
+		}
+		return copyRemoteFile(local, remote, input, localCommit, sourceCommit, fi.File.Path)
+	})
+}
+
+// copyRemoteFile copies the file at 'path' in the remote commit 'sourceCommit'
+// to the same path in 'localCommit', replacing any file that's already there.
+func copyRemoteFile(local, remote *client.APIClient, input *pps.RemoteRepoInput, localCommit, sourceCommit, path string) error {
+	r, err := remote.GetFileReader(input.SourceRepo, sourceCommit, path, 0, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := local.PutFileOverwrite(input.Repo, localCommit, path, r, 0); err != nil {
+		return errors.Wrapf(err, "could not copy %s@%s:%s", input.SourceRepo, sourceCommit, path)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestReadRemoteRepoState(t *testing.T) {
+	require.Equal(t, "", readRemoteRepoState(nil).SourceCommit)
+	require.Equal(t, "", readRemoteRepoState(&pfs.CommitInfo{Description: "not json"}).SourceCommit)
+
+	state := &remoteRepoState{SourceCommit: "abc123"}
+	commitInfo := &pfs.CommitInfo{Description: state.String()}
+	require.Equal(t, "abc123", readRemoteRepoState(commitInfo).SourceCommit)
+}
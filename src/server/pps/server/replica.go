@@ -0,0 +1,28 @@
+package server
+
+import (
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// regionLabels are the node labels that pin a pipeline's workers to a region,
+// in order of preference
+var regionLabels = []string{
+	"topology.kubernetes.io/region",
+	"failure-domain.beta.kubernetes.io/region",
+}
+
+// workerRegion returns the region that a pipeline's workers run in, if its
+// scheduling spec pins them to one, so that their sidecars read from the
+// default bucket's replica in that region. It returns "" otherwise, in which
+// case they read from the default bucket.
+func workerRegion(schedulingSpec *pps.SchedulingSpec) string {
+	if schedulingSpec == nil {
+		return ""
+	}
+	for _, label := range regionLabels {
+		if region := schedulingSpec.NodeSelector[label]; region != "" {
+			return region
+		}
+	}
+	return ""
+}
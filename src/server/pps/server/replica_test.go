@@ -0,0 +1,19 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestWorkerRegion(t *testing.T) {
+	require.Equal(t, "", workerRegion(nil))
+	require.Equal(t, "", workerRegion(&pps.SchedulingSpec{NodeSelector: map[string]string{"disktype": "ssd"}}))
+	require.Equal(t, "us-west-2", workerRegion(&pps.SchedulingSpec{NodeSelector: map[string]string{
+		"topology.kubernetes.io/region": "us-west-2",
+	}}))
+	require.Equal(t, "eu-west-1", workerRegion(&pps.SchedulingSpec{NodeSelector: map[string]string{
+		"failure-domain.beta.kubernetes.io/region": "eu-west-1",
+	}}))
+}
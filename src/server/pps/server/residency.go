@@ -0,0 +1,94 @@
+package server
+
+import (
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/residency"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// repoResidency is a repo and its residency
+type repoResidency struct {
+	repo      string
+	residency string
+}
+
+// setPipelineResidency sets the residency of 'pipelineInfo' to the residency
+// of its input repos (or of its existing output repos), and returns an error
+// if the pipeline would move data out of a residency.
+func (a *apiServer) setPipelineResidency(pachClient *client.APIClient, pipelineInfo *pps.PipelineInfo) error {
+	var inputs, outputs []repoResidency
+	var visitErr error
+	pps.VisitInput(pipelineInfo.Input, func(input *pps.Input) {
+		if input.Pfs == nil || visitErr != nil {
+			return
+		}
+		repoInfo, err := pachClient.InspectRepo(input.Pfs.Repo)
+		if err != nil {
+			visitErr = err
+			return
+		}
+		inputs = append(inputs, repoResidency{repo: input.Pfs.Repo, residency: repoInfo.Residency})
+	})
+	if visitErr != nil {
+		return visitErr
+	}
+	outputRepos := []string{pipelineInfo.Pipeline.Name}
+	for _, output := range pipelineInfo.Outputs {
+		outputRepos = append(outputRepos, output.Repo)
+	}
+	for _, repo := range outputRepos {
+		repoInfo, err := pachClient.InspectRepo(repo)
+		if err != nil && !isNotFoundErr(err) {
+			return err
+		} else if err == nil {
+			outputs = append(outputs, repoResidency{repo: repo, residency: repoInfo.Residency})
+		}
+	}
+	var egressURL string
+	if pipelineInfo.Egress != nil {
+		egressURL = pipelineInfo.Egress.URL
+	}
+	egressAllowed, err := residency.ParseURLs(a.env.StorageResidencyEgress)
+	if err != nil {
+		return errors.Wrapf(err, "could not parse STORAGE_RESIDENCY_EGRESS")
+	}
+	r, err := pipelineResidency(inputs, outputs, egressURL, egressAllowed)
+	if err != nil {
+		return errors.Wrapf(err, "invalid pipeline %q", pipelineInfo.Pipeline.Name)
+	}
+	pipelineInfo.Residency = r
+	return nil
+}
+
+// pipelineResidency returns the residency of a pipeline whose input repos are
+// 'inputs' and whose existing output repos are 'outputs'. All of the
+// pipeline's restricted repos must be in the same residency, its existing
+// output repos must be in that residency (as their residency can't change),
+// and it may only egress to the URLs in 'egressAllowed' for that residency.
+func pipelineResidency(inputs, outputs []repoResidency, egressURL string, egressAllowed map[string][]string) (string, error) {
+	var r, rRepo string
+	for _, repo := range append(append([]repoResidency{}, inputs...), outputs...) {
+		if repo.residency == "" {
+			continue
+		}
+		if r == "" {
+			r, rRepo = repo.residency, repo.repo
+			continue
+		}
+		if err := residency.Check(repo.repo, repo.residency, rRepo, r); err != nil {
+			return "", err
+		}
+	}
+	for _, output := range outputs {
+		if err := residency.Check(rRepo, r, output.repo, output.residency); err != nil {
+			return "", err
+		}
+	}
+	if egressURL != "" {
+		if err := residency.CheckEgress(rRepo, r, egressURL, egressAllowed[r]); err != nil {
+			return "", err
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pkg/residency"
+)
+
+func TestPipelineResidency(t *testing.T) {
+	unrestricted := repoResidency{repo: "logs"}
+	eu := repoResidency{repo: "patients", residency: "eu"}
+	us := repoResidency{repo: "claims", residency: "us"}
+
+	r, err := pipelineResidency([]repoResidency{unrestricted}, nil, "s3://anywhere", nil)
+	require.NoError(t, err)
+	require.Equal(t, "", r)
+
+	r, err = pipelineResidency([]repoResidency{unrestricted, eu}, nil, "", nil)
+	require.NoError(t, err)
+	require.Equal(t, "eu", r)
+
+	// An existing output repo in a residency keeps it
+	r, err = pipelineResidency([]repoResidency{unrestricted}, []repoResidency{{repo: "out", residency: "eu"}}, "", nil)
+	require.NoError(t, err)
+	require.Equal(t, "eu", r)
+
+	_, err = pipelineResidency([]repoResidency{eu, us}, nil, "", nil)
+	require.True(t, residency.IsErrViolation(err))
+	_, err = pipelineResidency([]repoResidency{eu}, []repoResidency{{repo: "out"}}, "", nil)
+	require.True(t, residency.IsErrViolation(err))
+
+	egress := map[string][]string{"eu": {"s3://eu-exports"}}
+	_, err = pipelineResidency([]repoResidency{eu}, nil, "s3://eu-exports/out", egress)
+	require.NoError(t, err)
+	_, err = pipelineResidency([]repoResidency{eu}, nil, "s3://us-exports/out", egress)
+	require.True(t, residency.IsErrViolation(err))
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	"github.com/gogo/protobuf/types"
+)
+
+// validateRetrySpec returns an error if 'retrySpec' (if set) can't be used to
+// retry a pipeline's failed datums.
+func validateRetrySpec(retrySpec *pps.RetrySpec) error {
+	if retrySpec == nil {
+		return nil
+	}
+	if retrySpec.MaxRetries < 0 {
+		return errors.New("invalid pipeline spec: retry_spec.max_retries must not be negative")
+	}
+	if retrySpec.Jitter < 0 || retrySpec.Jitter > 1 {
+		return errors.Errorf("invalid pipeline spec: retry_spec.jitter must be between 0 and 1 (got %v)", retrySpec.Jitter)
+	}
+	for name, d := range map[string]*types.Duration{
+		"backoff_base": retrySpec.BackoffBase,
+		"max_backoff":  retrySpec.MaxBackoff,
+	} {
+		if d == nil {
+			continue
+		}
+		duration, err := types.DurationFromProto(d)
+		if err != nil {
+			return errors.Wrapf(err, "invalid pipeline spec: could not parse retry_spec.%s", name)
+		}
+		if duration < 0 {
+			return errors.Errorf("invalid pipeline spec: retry_spec.%s must not be negative", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	"github.com/gogo/protobuf/types"
+)
+
+func TestValidateRetrySpec(t *testing.T) {
+	require.NoError(t, validateRetrySpec(nil))
+	require.NoError(t, validateRetrySpec(&pps.RetrySpec{
+		MaxRetries:  5,
+		BackoffBase: types.DurationProto(time.Second),
+		MaxBackoff:  types.DurationProto(time.Minute),
+		Jitter:      0.2,
+	}))
+	require.YesError(t, validateRetrySpec(&pps.RetrySpec{MaxRetries: -1}))
+	require.YesError(t, validateRetrySpec(&pps.RetrySpec{MaxRetries: 5, Jitter: 1.5}))
+	require.YesError(t, validateRetrySpec(&pps.RetrySpec{MaxRetries: 5, BackoffBase: types.DurationProto(-time.Second)}))
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+)
+
+// validateRuntimeConfig returns an error if 'config' can't be used as a
+// pipeline's runtime config
+func validateRuntimeConfig(config map[string]string) error {
+	for key := range config {
+		if key == "" {
+			return errors.New("invalid runtime config: keys cannot be empty")
+		}
+	}
+	return nil
+}
+
+// updatePipelineConfig replaces the runtime config of 'pipeline' with
+// 'config'. The config is stored in the pipeline's etcd pointer, which the
+// pipeline's workers watch, rather than in its spec commit, so the update
+// doesn't restart the pipeline or cause any data to be reprocessed.
+func (a *apiServer) updatePipelineConfig(pachClient *client.APIClient, pipeline *pps.Pipeline, config map[string]string) error {
+	if pipeline == nil || pipeline.Name == "" {
+		return errors.New("must specify a pipeline")
+	}
+	if err := validateRuntimeConfig(config); err != nil {
+		return err
+	}
+	pipelineInfo, err := a.inspectPipeline(pachClient, pipeline.Name)
+	if err != nil {
+		return err
+	}
+	if err := a.authorizePipelineOp(pachClient, pipelineOpUpdate, pipelineInfo.Input, pipelineInfo.Pipeline.Name); err != nil {
+		return err
+	}
+	_, err = col.NewSTM(pachClient.Ctx(), a.env.GetEtcdClient(), func(stm col.STM) error {
+		pipelinePtr := &pps.EtcdPipelineInfo{}
+		return a.pipelines.ReadWrite(stm).Update(pipeline.Name, pipelinePtr, func() error {
+			pipelinePtr.RuntimeConfig = config
+			return nil
+		})
+	})
+	return err
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// sandboxHandlers maps each sandboxed runtime that pipelines may use to the
+// prefix of the CRI handler names that implement it. A RuntimeClass whose
+// handler doesn't match would silently run the pipeline's code without the
+// isolation that the user asked for.
+var sandboxHandlers = map[string]string{
+	"gvisor": "runsc",
+	"kata":   "kata",
+}
+
+// sandboxRuntimeClass returns the name of the RuntimeClass that runs a
+// pipeline's workers under 'sandbox', or "" if the pipeline isn't sandboxed.
+func sandboxRuntimeClass(sandbox *pps.Sandbox) string {
+	if sandbox == nil {
+		return ""
+	}
+	if sandbox.RuntimeClass != "" {
+		return sandbox.RuntimeClass
+	}
+	return sandbox.Runtime
+}
+
+// validateSandbox checks that a pipeline's sandbox (if any) is well-formed.
+func validateSandbox(sandbox *pps.Sandbox) error {
+	if sandbox == nil {
+		return nil
+	}
+	if _, ok := sandboxHandlers[sandbox.Runtime]; !ok {
+		return errors.Errorf("invalid pipeline spec: sandbox runtime must be \"gvisor\" or \"kata\", not %q", sandbox.Runtime)
+	}
+	return nil
+}
+
+// checkSandboxRuntime checks that the cluster has a RuntimeClass that runs
+// 'sandbox', so that a sandboxed pipeline fails when it's created rather
+// than when its workers can't be scheduled.
+func (a *apiServer) checkSandboxRuntime(sandbox *pps.Sandbox) error {
+	if sandbox == nil {
+		return nil
+	}
+	name := sandboxRuntimeClass(sandbox)
+	runtimeClass, err := a.env.GetKubeClient().NodeV1beta1().RuntimeClasses().Get(name, metav1.GetOptions{})
+	if err != nil {
+		if isNotFoundErr(err) {
+			return errors.Errorf("the cluster doesn't support the %s sandbox: RuntimeClass %q not found", sandbox.Runtime, name)
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			// pachd was deployed with namespaced roles, so it can't read
+			// RuntimeClasses (which aren't namespaced). The workers will fail
+			// to start if the RuntimeClass doesn't exist.
+			log.Warnf("could not check that RuntimeClass %q exists: %v", name, err)
+			return nil
+		}
+		return errors.Wrapf(err, "could not get RuntimeClass %q", name)
+	}
+	if !strings.HasPrefix(runtimeClass.Handler, sandboxHandlers[sandbox.Runtime]) {
+		return errors.Errorf("RuntimeClass %q uses the %q handler, which doesn't provide the %s sandbox",
+			name, runtimeClass.Handler, sandbox.Runtime)
+	}
+	return nil
+}
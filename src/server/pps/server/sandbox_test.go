@@ -0,0 +1,22 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateSandbox(t *testing.T) {
+	require.NoError(t, validateSandbox(nil))
+	require.NoError(t, validateSandbox(&pps.Sandbox{Runtime: "gvisor"}))
+	require.NoError(t, validateSandbox(&pps.Sandbox{Runtime: "kata", RuntimeClass: "kata-fc"}))
+	require.YesError(t, validateSandbox(&pps.Sandbox{}))
+	require.YesError(t, validateSandbox(&pps.Sandbox{Runtime: "runc"}))
+}
+
+func TestSandboxRuntimeClass(t *testing.T) {
+	require.Equal(t, "", sandboxRuntimeClass(nil))
+	require.Equal(t, "gvisor", sandboxRuntimeClass(&pps.Sandbox{Runtime: "gvisor"}))
+	require.Equal(t, "kata-fc", sandboxRuntimeClass(&pps.Sandbox{Runtime: "kata", RuntimeClass: "kata-fc"}))
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// scaleJob sets the number of workers of the pipeline that's running 'job' to
+// 'workers', and records the change in the job's scaling timeline. The
+// workers that are added claim the job's remaining datum tasks from the task
+// queue; the tasks of workers that are removed are retried by the others once
+// their claims expire. The PPS master resets the pipeline's parallelism when
+// the job finishes (see resetJobScaling).
+func (a *apiServer) scaleJob(pachClient *client.APIClient, job *pps.Job, workers uint64) error {
+	if job == nil || job.ID == "" {
+		return errors.New("must specify a job")
+	}
+	if workers == 0 {
+		return errors.New("a job must have at least one worker (use StopJob to stop it)")
+	}
+	ctx := pachClient.Ctx()
+	jobPtr := &pps.EtcdJobInfo{}
+	if err := a.jobs.ReadOnly(ctx).Get(job.ID, jobPtr); err != nil {
+		return err
+	}
+	pipelineInfo, err := a.inspectPipeline(pachClient, jobPtr.Pipeline.Name)
+	if err != nil {
+		return err
+	}
+	if err := a.authorizePipelineOp(pachClient, pipelineOpUpdate, pipelineInfo.Input, pipelineInfo.Pipeline.Name); err != nil {
+		return err
+	}
+	if pipelineInfo.SchedulingSpec.GetGangScheduling() != nil {
+		// The size of a gang-scheduled pipeline's pod group is fixed
+		return errors.Errorf("pipeline %q is gang-scheduled, so its jobs can't be scaled", pipelineInfo.Pipeline.Name)
+	}
+	_, err = col.NewSTM(ctx, a.env.GetEtcdClient(), func(stm col.STM) error {
+		return scaleJobPtrs(a.jobs.ReadWrite(stm), a.pipelines.ReadWrite(stm), job.ID, workers)
+	})
+	return err
+}
+
+// scaleJobPtrs sets the parallelism of the pipeline that's running the job
+// 'jobID' to 'workers' in 'pipelines', and appends the change to the job's
+// scaling events in 'jobs'.
+func scaleJobPtrs(jobs, pipelines col.ReadWriteCollection, jobID string, workers uint64) error {
+	jobPtr := &pps.EtcdJobInfo{}
+	if err := jobs.Get(jobID, jobPtr); err != nil {
+		return err
+	}
+	if ppsutil.IsTerminal(jobPtr.State) {
+		return errors.Errorf("job %s is already finished", jobID)
+	}
+	pipelinePtr := &pps.EtcdPipelineInfo{}
+	if err := pipelines.Get(jobPtr.Pipeline.Name, pipelinePtr); err != nil {
+		return err
+	}
+	if pipelinePtr.State != pps.PipelineState_PIPELINE_RUNNING {
+		return errors.Errorf("pipeline %q must be running to scale its jobs (it's %s)", jobPtr.Pipeline.Name, pipelinePtr.State)
+	}
+	if pipelinePtr.ScaledJob != "" && pipelinePtr.ScaledJob != jobID {
+		return errors.Errorf("pipeline %q was already scaled for job %s, which hasn't finished yet", jobPtr.Pipeline.Name, pipelinePtr.ScaledJob)
+	}
+	from := pipelinePtr.Parallelism
+	if from == workers {
+		return nil
+	}
+	pipelinePtr.Parallelism = workers
+	pipelinePtr.ScaledJob = jobID
+	if err := pipelines.Put(jobPtr.Pipeline.Name, pipelinePtr); err != nil {
+		return err
+	}
+	jobPtr.ScalingEvents = append(jobPtr.ScalingEvents, &pps.ScalingEvent{
+		Time:        types.TimestampNow(),
+		FromWorkers: from,
+		ToWorkers:   workers,
+	})
+	return jobs.Put(jobID, jobPtr)
+}
+
+// resetJobScaling resets the parallelism of op's pipeline to match its
+// parallelism spec if the job that it was scaled for with ScaleJob has
+// finished. Job state changes update the pipeline's job counts, so the PPS
+// master is notified when the job finishes.
+func (op *pipelineOp) resetJobScaling() error {
+	if op.ptr.ScaledJob == "" {
+		return nil
+	}
+	if finished, err := scaledJobFinished(op.apiServer.jobs.ReadOnly(op.opClient.Ctx()), op.ptr.ScaledJob); err != nil || !finished {
+		return err
+	}
+	parallelism, err := getExpectedNumWorkers(op.apiServer.env.GetKubeClient(), op.pipelineInfo)
+	if err != nil {
+		return err
+	}
+	log.Infof("PPS master: job %s has finished, so resetting the workers of %q to %d", op.ptr.ScaledJob, op.name, parallelism)
+	_, err = col.NewSTM(op.opClient.Ctx(), op.apiServer.env.GetEtcdClient(), func(stm col.STM) error {
+		return resetJobScalingPtr(op.apiServer.pipelines.ReadWrite(stm), op.name, op.ptr.ScaledJob, uint64(parallelism))
+	})
+	if err != nil {
+		return err
+	}
+	op.ptr.Parallelism = uint64(parallelism)
+	op.ptr.ScaledJob = ""
+	return nil
+}
+
+// scaledJobFinished returns true if the job 'jobID', which a pipeline was
+// scaled for, has finished (or no longer exists).
+func scaledJobFinished(jobs col.ReadonlyCollection, jobID string) (bool, error) {
+	jobPtr := &pps.EtcdJobInfo{}
+	if err := jobs.Get(jobID, jobPtr); err != nil {
+		if col.IsErrNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return ppsutil.IsTerminal(jobPtr.State), nil
+}
+
+// resetJobScalingPtr sets the parallelism of 'pipeline' in 'pipelines' back to
+// 'parallelism', unless the pipeline has been scaled for a job other than
+// 'scaledJob' since.
+func resetJobScalingPtr(pipelines col.ReadWriteCollection, pipeline, scaledJob string, parallelism uint64) error {
+	pipelinePtr := &pps.EtcdPipelineInfo{}
+	return pipelines.Update(pipeline, pipelinePtr, func() error {
+		if pipelinePtr.ScaledJob != scaledJob {
+			return nil // scaled again since the caller read the pipeline
+		}
+		pipelinePtr.Parallelism = parallelism
+		pipelinePtr.ScaledJob = ""
+		return nil
+	})
+}
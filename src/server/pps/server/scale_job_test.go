@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsdb"
+	"github.com/pachyderm/pachyderm/src/server/pkg/testetcd"
+	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
+)
+
+func TestScaleJob(t *testing.T) {
+	require.NoError(t, testetcd.WithEnv(func(e *testetcd.Env) error {
+		prefix := uuid.NewWithoutDashes()
+		jobs, pipelines := ppsdb.Jobs(e.EtcdClient, prefix), ppsdb.Pipelines(e.EtcdClient, prefix)
+		ctx := context.Background()
+		put := func(f func(jobs, pipelines col.ReadWriteCollection) error) error {
+			_, err := col.NewSTM(ctx, e.EtcdClient, func(stm col.STM) error {
+				return f(jobs.ReadWrite(stm), pipelines.ReadWrite(stm))
+			})
+			return err
+		}
+		scale := func(jobID string, workers uint64) error {
+			return put(func(jobs, pipelines col.ReadWriteCollection) error {
+				return scaleJobPtrs(jobs, pipelines, jobID, workers)
+			})
+		}
+		setJobState := func(jobID string, state pps.JobState) error {
+			return put(func(jobs, pipelines col.ReadWriteCollection) error {
+				jobPtr := &pps.EtcdJobInfo{}
+				return jobs.Update(jobID, jobPtr, func() error {
+					jobPtr.State = state
+					return nil
+				})
+			})
+		}
+		getPipeline := func() *pps.EtcdPipelineInfo {
+			ptr := &pps.EtcdPipelineInfo{}
+			require.NoError(t, pipelines.ReadOnly(ctx).Get("pipeline", ptr))
+			return ptr
+		}
+		require.NoError(t, put(func(jobs, pipelines col.ReadWriteCollection) error {
+			if err := pipelines.Put("pipeline", &pps.EtcdPipelineInfo{
+				State:       pps.PipelineState_PIPELINE_RUNNING,
+				Parallelism: 2,
+			}); err != nil {
+				return err
+			}
+			for _, id := range []string{"job1", "job2"} {
+				if err := jobs.Put(id, &pps.EtcdJobInfo{
+					Job:          client.NewJob(id),
+					Pipeline:     client.NewPipeline("pipeline"),
+					OutputCommit: client.NewCommit("pipeline", id),
+					State:        pps.JobState_JOB_RUNNING,
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+
+		// Unknown jobs can't be scaled
+		require.YesError(t, scale("nonexistent", 5))
+
+		require.NoError(t, scale("job1", 5))
+		ptr := getPipeline()
+		require.Equal(t, uint64(5), ptr.Parallelism)
+		require.Equal(t, "job1", ptr.ScaledJob)
+		jobPtr := &pps.EtcdJobInfo{}
+		require.NoError(t, jobs.ReadOnly(ctx).Get("job1", jobPtr))
+		require.Equal(t, 1, len(jobPtr.ScalingEvents))
+		require.Equal(t, uint64(2), jobPtr.ScalingEvents[0].FromWorkers)
+		require.Equal(t, uint64(5), jobPtr.ScalingEvents[0].ToWorkers)
+
+		// The same job can be scaled again, but another job can't be scaled
+		// until it finishes
+		require.NoError(t, scale("job1", 3))
+		require.Equal(t, uint64(3), getPipeline().Parallelism)
+		require.YesError(t, scale("job2", 4))
+
+		// The pipeline keeps its workers until the job finishes
+		finished, err := scaledJobFinished(jobs.ReadOnly(ctx), "job1")
+		require.NoError(t, err)
+		require.False(t, finished)
+		require.NoError(t, setJobState("job1", pps.JobState_JOB_SUCCESS))
+		finished, err = scaledJobFinished(jobs.ReadOnly(ctx), "job1")
+		require.NoError(t, err)
+		require.True(t, finished)
+
+		// Finished jobs can't be scaled
+		require.YesError(t, scale("job1", 4))
+
+		require.NoError(t, put(func(jobs, pipelines col.ReadWriteCollection) error {
+			return resetJobScalingPtr(pipelines, "pipeline", "job1", 2)
+		}))
+		ptr = getPipeline()
+		require.Equal(t, uint64(2), ptr.Parallelism)
+		require.Equal(t, "", ptr.ScaledJob)
+
+		// Once it's reset, the other job can be scaled. Resetting the scaling
+		// of the first job again doesn't affect it.
+		require.NoError(t, scale("job2", 4))
+		require.NoError(t, put(func(jobs, pipelines col.ReadWriteCollection) error {
+			return resetJobScalingPtr(pipelines, "pipeline", "job1", 2)
+		}))
+		ptr = getPipeline()
+		require.Equal(t, uint64(4), ptr.Parallelism)
+		require.Equal(t, "job2", ptr.ScaledJob)
+
+		// Deleted jobs count as finished
+		finished, err = scaledJobFinished(jobs.ReadOnly(ctx), "nonexistent")
+		require.NoError(t, err)
+		require.True(t, finished)
+		return nil
+	}))
+}
@@ -0,0 +1,254 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsdb"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/work"
+	workercommon "github.com/pachyderm/pachyderm/src/server/worker/common"
+	workerserver "github.com/pachyderm/pachyderm/src/server/worker/server"
+)
+
+// benignWaitingReasons are the reasons for which a container may be waiting
+// while its pod starts normally
+var benignWaitingReasons = map[string]bool{
+	"ContainerCreating": true,
+	"PodInitializing":   true,
+}
+
+// pipelineSchedule describes the unfinished jobs and the workers of the
+// pipeline 'pipelineInfo', and the output commit held by its job gates.
+func (a *apiServer) pipelineSchedule(pachClient *client.APIClient, pipelineInfo *pps.PipelineInfo) (*pps.PipelineSchedule, error) {
+	ctx := pachClient.Ctx()
+	pipelineName := pipelineInfo.Pipeline.Name
+	schedule := &pps.PipelineSchedule{
+		Pipeline:   pipelineInfo.Pipeline,
+		State:      pipelineInfo.State,
+		Reason:     pipelineInfo.Reason,
+		GateStatus: pipelineInfo.GateStatus,
+	}
+
+	rcName := ppsutil.PipelineRcName(pipelineName, pipelineInfo.Version)
+	rc, err := a.env.GetKubeClient().CoreV1().ReplicationControllers(a.namespace).Get(rcName, metav1.GetOptions{})
+	if err != nil && !isNotFoundErr(err) {
+		return nil, errors.Wrapf(err, "could not get the pipeline's replication controller")
+	}
+	if err == nil {
+		if rc.Spec.Replicas != nil {
+			schedule.DesiredWorkers = *rc.Spec.Replicas
+		}
+		schedule.ReadyWorkers = rc.Status.ReadyReplicas
+		pods, err := a.rcPods(rcName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not list the pipeline's pods")
+		}
+		schedule.WorkerProblems = workerProblems(rc, pods)
+		if gang := pipelineInfo.SchedulingSpec.GetGangScheduling(); gang != nil && schedule.DesiredWorkers > 0 {
+			schedule.QueueAdmission = a.queueAdmission(gang, rcName)
+		}
+	}
+
+	var jobPtrs []*pps.EtcdJobInfo
+	jobPtr := &pps.EtcdJobInfo{}
+	if err := a.jobs.ReadOnly(ctx).GetByIndex(ppsdb.JobsPipelineIndex, pipelineInfo.Pipeline, jobPtr, col.DefaultOptions, func(string) error {
+		if !ppsutil.IsTerminal(jobPtr.State) {
+			jobPtrs = append(jobPtrs, proto.Clone(jobPtr).(*pps.EtcdJobInfo))
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if len(jobPtrs) == 0 {
+		return schedule, nil
+	}
+	sort.SliceStable(jobPtrs, func(i, j int) bool {
+		return startTime(jobPtrs[i]).Before(startTime(jobPtrs[j]))
+	})
+
+	// The chunks of each job that the workers have started processing, and
+	// what each worker is processing
+	tasks, err := work.ListTasks(ctx, a.env.GetEtcdClient(), a.etcdPrefix, workercommon.WorkNamespace(pipelineName, pipelineInfo.Version))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not list the pipeline's chunks")
+	}
+	var workerStatus []*pps.WorkerStatus
+	if schedule.ReadyWorkers > 0 {
+		if workerStatus, err = workerserver.Status(ctx, rcName, a.env.GetEtcdClient(), a.etcdPrefix, a.workerGrpcPort); err != nil {
+			log.Errorf("failed to get worker status with err: %s", err.Error())
+		}
+	}
+
+	for i, jobPtr := range jobPtrs {
+		jobSchedule := &pps.JobSchedule{
+			Job:     jobPtr.Job,
+			State:   jobPtr.State,
+			Started: jobPtr.Started,
+		}
+		if task, ok := tasks[jobPtr.Job.ID]; ok {
+			jobSchedule.Chunks = &pps.ChunkCounts{
+				Pending:   task.Pending,
+				Claimed:   task.Claimed,
+				Succeeded: task.Succeeded,
+				Failed:    task.Failed,
+				Requeued:  task.Requeues,
+			}
+		}
+		for _, status := range workerStatus {
+			if status.JobID == jobPtr.Job.ID {
+				jobSchedule.Workers = append(jobSchedule.Workers, status)
+			}
+		}
+		var unfinished []string
+		if jobPtr.State == pps.JobState_JOB_STARTING {
+			jobInfo, err := a.jobInfoFromPtr(pachClient, jobPtr, true)
+			if err != nil {
+				return nil, err
+			}
+			if unfinished, err = unfinishedInputs(pachClient, jobInfo.Input); err != nil {
+				return nil, err
+			}
+		}
+		jobSchedule.WaitingOn = jobWaitingOn(pipelineInfo, schedule, jobPtr, jobPtrs[:i], unfinished)
+		schedule.Jobs = append(schedule.Jobs, jobSchedule)
+	}
+	return schedule, nil
+}
+
+func startTime(jobPtr *pps.EtcdJobInfo) time.Time {
+	started, err := types.TimestampFromProto(jobPtr.Started)
+	if err != nil {
+		return time.Time{}
+	}
+	return started
+}
+
+// unfinishedInputs returns the input commits of 'input' that haven't finished,
+// which a starting job waits for.
+func unfinishedInputs(pachClient *client.APIClient, input *pps.Input) ([]string, error) {
+	var commits []*pfs.Commit
+	pps.VisitInput(input, func(input *pps.Input) {
+		switch {
+		case input.Pfs != nil && input.Pfs.Commit != "":
+			commits = append(commits, client.NewCommit(input.Pfs.Repo, input.Pfs.Commit))
+		case input.Cron != nil && input.Cron.Commit != "":
+			commits = append(commits, client.NewCommit(input.Cron.Repo, input.Cron.Commit))
+		case input.Git != nil && input.Git.Commit != "":
+			commits = append(commits, client.NewCommit(input.Git.Name, input.Git.Commit))
+		case input.HTTPMirror != nil && input.HTTPMirror.Commit != "":
+			commits = append(commits, client.NewCommit(input.HTTPMirror.Repo, input.HTTPMirror.Commit))
+		case input.Parameter != nil && input.Parameter.Commit != "":
+			commits = append(commits, client.NewCommit(input.Parameter.Repo, input.Parameter.Commit))
+		case input.RemoteRepo != nil && input.RemoteRepo.Commit != "":
+			commits = append(commits, client.NewCommit(input.RemoteRepo.Repo, input.RemoteRepo.Commit))
+		case input.DBImport != nil && input.DBImport.Commit != "":
+			commits = append(commits, client.NewCommit(input.DBImport.Repo, input.DBImport.Commit))
+		}
+	})
+	var result []string
+	for _, commit := range commits {
+		commitInfo, err := pachClient.InspectCommit(commit.Repo.Name, commit.ID)
+		if err != nil {
+			return nil, err
+		}
+		if commitInfo.Finished == nil {
+			result = append(result, fmt.Sprintf("%s@%s", commit.Repo.Name, commit.ID))
+		}
+	}
+	return result, nil
+}
+
+// jobWaitingOn explains why the job 'jobPtr' of the pipeline 'pipelineInfo',
+// whose workers are described by 'schedule', isn't starting or making
+// progress. 'earlier' are the pipeline's unfinished jobs that started before
+// it, and 'unfinished' are its input commits that haven't finished.
+func jobWaitingOn(pipelineInfo *pps.PipelineInfo, schedule *pps.PipelineSchedule, jobPtr *pps.EtcdJobInfo, earlier []*pps.EtcdJobInfo, unfinished []string) []string {
+	var reasons []string
+	switch pipelineInfo.State {
+	case pps.PipelineState_PIPELINE_PAUSED:
+		reasons = append(reasons, "the pipeline is stopped")
+	case pps.PipelineState_PIPELINE_STANDBY:
+		reasons = append(reasons, "the pipeline is in standby, and its workers are being started")
+	case pps.PipelineState_PIPELINE_STARTING, pps.PipelineState_PIPELINE_RESTARTING:
+		reasons = append(reasons, "the pipeline's workers are being started")
+	case pps.PipelineState_PIPELINE_CRASHING:
+		reasons = append(reasons, fmt.Sprintf("the pipeline's workers are crashing: %s", pipelineInfo.Reason))
+	case pps.PipelineState_PIPELINE_FAILURE:
+		reasons = append(reasons, fmt.Sprintf("the pipeline failed: %s", pipelineInfo.Reason))
+	}
+	if admission := schedule.QueueAdmission; admission != nil && !admission.Admitted {
+		reason := fmt.Sprintf("the pipeline's workers are waiting to be admitted by %s", admission.Scheduler)
+		if admission.Queue != "" {
+			reason += fmt.Sprintf(" queue %q", admission.Queue)
+		}
+		if admission.Message != "" {
+			reason += ": " + admission.Message
+		}
+		reasons = append(reasons, reason)
+	}
+	if schedule.ReadyWorkers == 0 && pipelineInfo.State != pps.PipelineState_PIPELINE_PAUSED {
+		reason := "none of the pipeline's workers are ready"
+		if len(schedule.WorkerProblems) > 0 {
+			reason += " (see the pipeline's worker problems)"
+		}
+		reasons = append(reasons, reason)
+	}
+	for _, commit := range unfinished {
+		reasons = append(reasons, fmt.Sprintf("input commit %s hasn't finished", commit))
+	}
+	if jobPtr.State == pps.JobState_JOB_STARTING && len(earlier) > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d earlier job(s) of the pipeline haven't finished, the oldest being %s",
+			len(earlier), earlier[0].Job.ID))
+	}
+	return reasons
+}
+
+// workerProblems explains why the pods of the replication controller 'rc'
+// (which are 'pods') aren't running.
+func workerProblems(rc *v1.ReplicationController, pods []v1.Pod) []string {
+	var problems []string
+	// Pods that can't be created, e.g. because they would exceed the
+	// namespace's resource quota, are reported by the RC
+	for _, condition := range rc.Status.Conditions {
+		if condition.Type == v1.ReplicationControllerReplicaFailure && condition.Status == v1.ConditionTrue {
+			problems = append(problems, fmt.Sprintf("workers can't be created: %s", condition.Message))
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse {
+				problems = append(problems, fmt.Sprintf("pod %s can't be scheduled: %s", pod.Name, condition.Message))
+			}
+		}
+		statuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			waiting := status.State.Waiting
+			if waiting == nil || benignWaitingReasons[waiting.Reason] {
+				continue
+			}
+			problem := fmt.Sprintf("container %s of pod %s is waiting (%s)", status.Name, pod.Name, waiting.Reason)
+			if waiting.Message != "" {
+				problem += ": " + waiting.Message
+			}
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestJobWaitingOn(t *testing.T) {
+	pipelineInfo := &pps.PipelineInfo{State: pps.PipelineState_PIPELINE_RUNNING}
+	schedule := &pps.PipelineSchedule{ReadyWorkers: 1}
+	running := &pps.EtcdJobInfo{Job: client.NewJob("a"), State: pps.JobState_JOB_RUNNING}
+	starting := &pps.EtcdJobInfo{Job: client.NewJob("b"), State: pps.JobState_JOB_STARTING}
+
+	require.Equal(t, 0, len(jobWaitingOn(pipelineInfo, schedule, running, nil, nil)))
+	reasons := jobWaitingOn(pipelineInfo, schedule, starting, []*pps.EtcdJobInfo{running}, []string{"in@master"})
+	require.Equal(t, 2, len(reasons))
+	require.Matches(t, "in@master", reasons[0])
+	require.Matches(t, "oldest being a", reasons[1])
+
+	// A stopped pipeline has no ready workers, but that's expected
+	pipelineInfo.State = pps.PipelineState_PIPELINE_PAUSED
+	schedule.ReadyWorkers = 0
+	reasons = jobWaitingOn(pipelineInfo, schedule, running, nil, nil)
+	require.Equal(t, 1, len(reasons))
+	require.Matches(t, "stopped", reasons[0])
+
+	// Gang-scheduled workers may be waiting for their queue
+	pipelineInfo.State = pps.PipelineState_PIPELINE_RUNNING
+	schedule.QueueAdmission = &pps.QueueAdmission{Scheduler: "kueue", Queue: "gpus", Message: "insufficient quota"}
+	reasons = jobWaitingOn(pipelineInfo, schedule, running, nil, nil)
+	require.Equal(t, 2, len(reasons))
+	require.Matches(t, `admitted by kueue queue "gpus": insufficient quota`, reasons[0])
+}
+
+func TestWorkerProblems(t *testing.T) {
+	rc := &v1.ReplicationController{Status: v1.ReplicationControllerStatus{
+		Conditions: []v1.ReplicationControllerCondition{{
+			Type:    v1.ReplicationControllerReplicaFailure,
+			Status:  v1.ConditionTrue,
+			Message: "exceeded quota",
+		}},
+	}}
+	pods := []v1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-b"},
+		Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{
+			Name:  "user",
+			State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+		}, {
+			Name:  "storage",
+			State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+		}}},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-a"},
+		Status: v1.PodStatus{Conditions: []v1.PodCondition{{
+			Type:    v1.PodScheduled,
+			Status:  v1.ConditionFalse,
+			Message: "insufficient cpu",
+		}}},
+	}}
+	problems := workerProblems(rc, pods)
+	require.Equal(t, 3, len(problems))
+	require.Matches(t, "exceeded quota", problems[0])
+	require.Matches(t, "pod pod-a can't be scheduled: insufficient cpu", problems[1])
+	require.Matches(t, "container user of pod pod-b is waiting \\(ImagePullBackOff\\)", problems[2])
+}
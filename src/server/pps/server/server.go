@@ -6,6 +6,7 @@ import (
 	"github.com/pachyderm/pachyderm/src/server/pkg/metrics"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsdb"
 	"github.com/pachyderm/pachyderm/src/server/pkg/serviceenv"
+	"github.com/pachyderm/pachyderm/src/server/pkg/tenantutil"
 	txnenv "github.com/pachyderm/pachyderm/src/server/pkg/transactionenv"
 )
 
@@ -57,6 +58,7 @@ func NewAPIServer(
 		workerUsesRoot:         workerUsesRoot,
 		pipelines:              ppsdb.Pipelines(env.GetEtcdClient(), etcdPrefix),
 		jobs:                   ppsdb.Jobs(env.GetEtcdClient(), etcdPrefix),
+		tenantPipelines:        tenantutil.Counts(env.GetEtcdClient(), etcdPrefix, "pipelines"),
 		monitorCancels:         make(map[string]func()),
 		crashingMonitorCancels: make(map[string]func()),
 		workerGrpcPort:         workerGrpcPort,
@@ -66,6 +68,7 @@ func NewAPIServer(
 		gcPercent:              gcPercent,
 	}
 	apiServer.validateKube()
+	registerSkippingStats(apiServer.pipelines)
 	go apiServer.master()
 	return apiServer, nil
 }
@@ -85,19 +88,20 @@ func NewSidecarAPIServer(
 	peerPort uint16,
 ) (APIServer, error) {
 	apiServer := &apiServer{
-		Logger:         log.NewLogger("pps.API"),
-		env:            env,
-		txnEnv:         txnEnv,
-		etcdPrefix:     etcdPrefix,
-		iamRole:        iamRole,
-		reporter:       reporter,
-		namespace:      namespace,
-		workerUsesRoot: true,
-		pipelines:      ppsdb.Pipelines(env.GetEtcdClient(), etcdPrefix),
-		jobs:           ppsdb.Jobs(env.GetEtcdClient(), etcdPrefix),
-		workerGrpcPort: workerGrpcPort,
-		httpPort:       httpPort,
-		peerPort:       peerPort,
+		Logger:          log.NewLogger("pps.API"),
+		env:             env,
+		txnEnv:          txnEnv,
+		etcdPrefix:      etcdPrefix,
+		iamRole:         iamRole,
+		reporter:        reporter,
+		namespace:       namespace,
+		workerUsesRoot:  true,
+		pipelines:       ppsdb.Pipelines(env.GetEtcdClient(), etcdPrefix),
+		jobs:            ppsdb.Jobs(env.GetEtcdClient(), etcdPrefix),
+		tenantPipelines: tenantutil.Counts(env.GetEtcdClient(), etcdPrefix, "pipelines"),
+		workerGrpcPort:  workerGrpcPort,
+		httpPort:        httpPort,
+		peerPort:        peerPort,
 	}
 	go apiServer.ServeSidecarS3G()
 	return apiServer, nil
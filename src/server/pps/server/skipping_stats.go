@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	pipelineJobsDesc = prometheus.NewDesc(
+		"pachyderm_pachd_pipeline_jobs_finished_total",
+		"Number of a pipeline's jobs that finished",
+		[]string{"pipeline"}, nil,
+	)
+	pipelineDatumsDesc = prometheus.NewDesc(
+		"pachyderm_pachd_pipeline_datums_total",
+		"Number of datums in a pipeline's finished jobs, by outcome (processed, skipped, failed or recovered)",
+		[]string{"pipeline", "outcome"}, nil,
+	)
+	pipelineDatumsReusedDesc = prometheus.NewDesc(
+		"pachyderm_pachd_pipeline_datums_reused_total",
+		"Number of a pipeline's skipped datums whose output was found in the datum cache",
+		[]string{"pipeline"}, nil,
+	)
+	pipelineSkippedBytesDesc = prometheus.NewDesc(
+		"pachyderm_pachd_pipeline_skipped_bytes_total",
+		"Total size of the inputs of a pipeline's reused datums",
+		[]string{"pipeline"}, nil,
+	)
+	pipelineSkippingDisabledDesc = prometheus.NewDesc(
+		"pachyderm_pachd_pipeline_skipping_disabled",
+		"1 if a pipeline skipped datums before it was last updated, but hasn't since",
+		[]string{"pipeline"}, nil,
+	)
+)
+
+// skippingStatsCollector exports the skipping stats of every pipeline (see
+// pps.SkippingStats) to prometheus.
+type skippingStatsCollector struct {
+	pipelines col.Collection
+}
+
+// registerSkippingStats registers a skippingStatsCollector for the pipelines
+// in 'pipelines'.
+func registerSkippingStats(pipelines col.Collection) {
+	if err := prometheus.Register(&skippingStatsCollector{pipelines: pipelines}); err != nil {
+		// metrics may be redundantly registered; ignore these errors
+		if !errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+			logrus.Infof("error registering prometheus metric: %v", err)
+		}
+	}
+}
+
+func (c *skippingStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- pipelineJobsDesc
+	ch <- pipelineDatumsDesc
+	ch <- pipelineDatumsReusedDesc
+	ch <- pipelineSkippedBytesDesc
+	ch <- pipelineSkippingDisabledDesc
+}
+
+func (c *skippingStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	pipelinePtr := &pps.EtcdPipelineInfo{}
+	if err := c.pipelines.ReadOnly(ctx).List(pipelinePtr, col.DefaultOptions, func(name string) error {
+		stats := pipelinePtr.SkippingStats
+		if stats == nil || stats.SinceCreation == nil {
+			return nil
+		}
+		counts := stats.SinceCreation
+		ch <- prometheus.MustNewConstMetric(pipelineJobsDesc, prometheus.CounterValue, float64(counts.Jobs), name)
+		for outcome, n := range map[string]int64{
+			"processed": counts.Processed,
+			"skipped":   counts.Skipped,
+			"failed":    counts.Failed,
+			"recovered": counts.Recovered,
+		} {
+			ch <- prometheus.MustNewConstMetric(pipelineDatumsDesc, prometheus.CounterValue, float64(n), name, outcome)
+		}
+		ch <- prometheus.MustNewConstMetric(pipelineDatumsReusedDesc, prometheus.CounterValue, float64(counts.Reused), name)
+		ch <- prometheus.MustNewConstMetric(pipelineSkippedBytesDesc, prometheus.CounterValue, float64(counts.SkippedBytes), name)
+		var disabled float64
+		if ppsutil.SkippingDisabled(stats) {
+			disabled = 1
+		}
+		ch <- prometheus.MustNewConstMetric(pipelineSkippingDisabledDesc, prometheus.GaugeValue, disabled, name)
+		return nil
+	}); err != nil {
+		logrus.Infof("error collecting pipeline skipping stats: %v", err)
+	}
+}
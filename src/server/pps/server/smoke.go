@@ -0,0 +1,56 @@
+package server
+
+import (
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+
+	"github.com/gogo/protobuf/types"
+)
+
+// validateSmokeTest returns an error if the smoke test of 'pipelineInfo' (if
+// any) doesn't determine the size of its jobs' samples.
+func validateSmokeTest(pipelineInfo *pps.PipelineInfo) error {
+	smokeTest := pipelineInfo.SmokeTest
+	if smokeTest == nil {
+		return nil
+	}
+	if pipelineInfo.Spout != nil || pipelineInfo.Service != nil {
+		return errors.New("invalid pipeline spec: smoke_test isn't supported for spouts or services")
+	}
+	if (smokeTest.Datums != 0) == (smokeTest.Fraction != 0) {
+		return errors.New("invalid pipeline spec: smoke_test must set exactly one of datums and fraction")
+	}
+	if smokeTest.Datums < 0 {
+		return errors.New("invalid pipeline spec: smoke_test.datums must be positive")
+	}
+	if smokeTest.Fraction < 0 || smokeTest.Fraction >= 1 {
+		return errors.New("invalid pipeline spec: smoke_test.fraction must be between 0 and 1")
+	}
+	return nil
+}
+
+// approveSmokeTest lets the job of 'commitInfo', an unfinished output commit
+// of 'pipelineInfo', process the rest of its datums after it paused at the
+// end of its smoke test. Its workers notice the approval the next time they
+// check the job.
+func (a *apiServer) approveSmokeTest(pachClient *client.APIClient, pipelineInfo *pps.PipelineInfo, commitInfo *pfs.CommitInfo) error {
+	jobInfo, err := pachClient.InspectJobOutputCommit(pipelineInfo.Pipeline.Name, commitInfo.Commit.ID, false)
+	if err != nil {
+		return err
+	}
+	_, err = col.NewSTM(pachClient.Ctx(), a.env.GetEtcdClient(), func(stm col.STM) error {
+		jobPtr := &pps.EtcdJobInfo{}
+		return a.jobs.ReadWrite(stm).Update(jobInfo.Job.ID, jobPtr, func() error {
+			if !ppsutil.SmokeTestPaused(pipelineInfo.SmokeTest, jobPtr.SmokeTest) {
+				return errors.Errorf("job %s isn't paused after its smoke test", jobInfo.Job.ID)
+			}
+			jobPtr.SmokeTest.Approved = types.TimestampNow()
+			return nil
+		})
+	})
+	return err
+}
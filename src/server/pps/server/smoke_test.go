@@ -0,0 +1,33 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateSmokeTest(t *testing.T) {
+	pipelineInfo := func(smokeTest *pps.SmokeTest) *pps.PipelineInfo {
+		return &pps.PipelineInfo{
+			Pipeline:  client.NewPipeline("train"),
+			Input:     client.NewPFSInput("in", "/*"),
+			SmokeTest: smokeTest,
+		}
+	}
+	require.NoError(t, validateSmokeTest(pipelineInfo(nil)))
+	require.NoError(t, validateSmokeTest(pipelineInfo(&pps.SmokeTest{Datums: 10})))
+	require.NoError(t, validateSmokeTest(pipelineInfo(&pps.SmokeTest{Fraction: 0.01, Pause: true})))
+	// neither or both of datums and fraction
+	require.YesError(t, validateSmokeTest(pipelineInfo(&pps.SmokeTest{Pause: true})))
+	require.YesError(t, validateSmokeTest(pipelineInfo(&pps.SmokeTest{Datums: 10, Fraction: 0.01})))
+	// out of range
+	require.YesError(t, validateSmokeTest(pipelineInfo(&pps.SmokeTest{Datums: -1})))
+	require.YesError(t, validateSmokeTest(pipelineInfo(&pps.SmokeTest{Fraction: 1})))
+	// spout
+	spout := pipelineInfo(&pps.SmokeTest{Datums: 10})
+	spout.Input = nil
+	spout.Spout = &pps.Spout{}
+	require.YesError(t, validateSmokeTest(spout))
+}
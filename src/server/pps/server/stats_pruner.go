@@ -0,0 +1,233 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsdb"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// statsPruneInterval is how often the PPS master prunes pipelines' stats
+const statsPruneInterval = time.Hour
+
+// validateStatsRetention returns an error if the StatsRetention of
+// 'pipelineInfo' is invalid.
+func validateStatsRetention(pipelineInfo *pps.PipelineInfo) error {
+	retention := pipelineInfo.StatsRetention
+	if retention == nil || retention.MaxAge == nil {
+		return nil
+	}
+	maxAge, err := types.DurationFromProto(retention.MaxAge)
+	if err != nil {
+		return errors.Wrapf(err, "invalid pipeline spec: could not parse stats_retention.max_age")
+	}
+	if maxAge < 0 {
+		return errors.New("invalid pipeline spec: stats_retention.max_age must not be negative")
+	}
+	return nil
+}
+
+// errStatsPruned is returned when the detailed stats of 'jobInfo' are read
+// after they were pruned.
+func errStatsPruned(jobInfo *pps.JobInfo) error {
+	pruned, _ := types.TimestampFromProto(jobInfo.StatsPruned)
+	return errors.Errorf("the stats of job %s were pruned at %s under the stats retention policy of pipeline %q",
+		jobInfo.Job.ID, pruned.Format(time.RFC3339), jobInfo.Pipeline.Name)
+}
+
+// defaultStatsRetention returns the cluster's default stats retention. A
+// limit of 0 isn't enforced.
+func (a *apiServer) defaultStatsRetention() (time.Duration, int64, error) {
+	maxAge, err := time.ParseDuration(a.env.PPSStatsMaxAge)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "invalid PPS_STATS_MAX_AGE %q", a.env.PPSStatsMaxAge)
+	}
+	return maxAge, a.env.PPSStatsKeepLast, nil
+}
+
+// statsRetention applies the cluster's defaults to the stats retention of
+// 'pipelineInfo'. A limit of 0 isn't enforced.
+func statsRetention(pipelineInfo *pps.PipelineInfo, defaultMaxAge time.Duration, defaultKeepLast int64) (time.Duration, int64, error) {
+	maxAge, keepLast := defaultMaxAge, defaultKeepLast
+	if retention := pipelineInfo.StatsRetention; retention != nil {
+		if retention.MaxAge != nil {
+			var err error
+			if maxAge, err = types.DurationFromProto(retention.MaxAge); err != nil {
+				return 0, 0, err
+			}
+		}
+		if retention.KeepLast != 0 {
+			keepLast = retention.KeepLast
+		}
+	}
+	if keepLast < 0 {
+		keepLast = 0
+	}
+	return maxAge, keepLast, nil
+}
+
+// statsToPrune returns the finished jobs in 'jobs' whose stats exceed
+// 'maxAge' or 'keepLast' at 'now'. Stats that were already pruned, and the
+// stats commit at the head of the stats branch ('statsHead'), are left out.
+// The 'size_bytes' of the results isn't set.
+func statsToPrune(jobs []*pps.EtcdJobInfo, now time.Time, maxAge time.Duration, keepLast int64, statsHead string) []*pps.PrunedStats {
+	var finished []*pps.EtcdJobInfo
+	for _, jobPtr := range jobs {
+		if jobPtr.StatsCommit != nil && jobPtr.Finished != nil && ppsutil.IsTerminal(jobPtr.State) {
+			finished = append(finished, jobPtr)
+		}
+	}
+	sort.SliceStable(finished, func(i, j int) bool {
+		return finished[i].Finished.Compare(finished[j].Finished) > 0
+	})
+	var result []*pps.PrunedStats
+	for i, jobPtr := range finished {
+		if jobPtr.StatsPruned != nil || jobPtr.StatsCommit.ID == statsHead {
+			continue
+		}
+		var reason string
+		finishedAt, err := types.TimestampFromProto(jobPtr.Finished)
+		if err != nil {
+			continue
+		}
+		switch {
+		case keepLast > 0 && int64(i) >= keepLast:
+			reason = fmt.Sprintf("more than %d newer jobs", keepLast)
+		case maxAge > 0 && now.Sub(finishedAt) > maxAge:
+			reason = fmt.Sprintf("older than %s", maxAge)
+		default:
+			continue
+		}
+		result = append(result, &pps.PrunedStats{
+			Job:         jobPtr.Job,
+			Pipeline:    jobPtr.Pipeline,
+			StatsCommit: jobPtr.StatsCommit,
+			Finished:    jobPtr.Finished,
+			Reason:      reason,
+		})
+	}
+	return result
+}
+
+// pruneStats prunes the stats of the jobs of 'pipeline' (or, if it's empty,
+// of every pipeline) that exceed their pipeline's stats retention, and
+// returns them, oldest first. If 'dryRun' is set, nothing is pruned.
+func (a *apiServer) pruneStats(pachClient *client.APIClient, pipeline *pps.Pipeline, dryRun bool) ([]*pps.PrunedStats, error) {
+	defaultMaxAge, defaultKeepLast, err := a.defaultStatsRetention()
+	if err != nil {
+		return nil, err
+	}
+	ctx := pachClient.Ctx()
+	now := time.Now()
+	var result []*pps.PrunedStats
+	if err := a.listPipeline(pachClient, &pps.ListPipelineRequest{Pipeline: pipeline}, func(pipelineInfo *pps.PipelineInfo) error {
+		if !pipelineInfo.EnableStats {
+			return nil
+		}
+		maxAge, keepLast, err := statsRetention(pipelineInfo, defaultMaxAge, defaultKeepLast)
+		if err != nil {
+			return err
+		}
+		if maxAge <= 0 && keepLast <= 0 {
+			return nil
+		}
+		pipelineName := pipelineInfo.Pipeline.Name
+		branchInfo, err := pachClient.InspectBranch(pipelineName, "stats")
+		if err != nil {
+			if isNotFoundErr(err) {
+				return nil
+			}
+			return err
+		}
+		var statsHead string
+		if branchInfo.Head != nil {
+			statsHead = branchInfo.Head.ID
+		}
+		var jobs []*pps.EtcdJobInfo
+		jobPtr := &pps.EtcdJobInfo{}
+		if err := a.jobs.ReadOnly(ctx).GetByIndex(ppsdb.JobsPipelineIndex, pipelineInfo.Pipeline, jobPtr, col.DefaultOptions, func(string) error {
+			jobs = append(jobs, proto.Clone(jobPtr).(*pps.EtcdJobInfo))
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, pruned := range statsToPrune(jobs, now, maxAge, keepLast, statsHead) {
+			commitInfo, err := pachClient.InspectCommit(pipelineName, pruned.StatsCommit.ID)
+			if err != nil && !isNotFoundErr(err) {
+				return err
+			}
+			if commitInfo != nil {
+				pruned.SizeBytes = commitInfo.SizeBytes
+			}
+			if !dryRun {
+				if commitInfo != nil {
+					if err := pachClient.PruneCommit(pipelineName, pruned.StatsCommit.ID); err != nil {
+						return errors.Wrapf(err, "could not prune the stats of job %s", pruned.Job.ID)
+					}
+				}
+				if err := a.setStatsPruned(ctx, pruned.Job.ID); err != nil {
+					return err
+				}
+			}
+			result = append(result, pruned)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Finished.Compare(result[j].Finished) < 0
+	})
+	return result, nil
+}
+
+// setStatsPruned records that the stats of the job with ID 'jobID' were
+// pruned
+func (a *apiServer) setStatsPruned(ctx context.Context, jobID string) error {
+	_, err := col.NewSTM(ctx, a.env.GetEtcdClient(), func(stm col.STM) error {
+		jobPtr := &pps.EtcdJobInfo{}
+		return a.jobs.ReadWrite(stm).Update(jobID, jobPtr, func() error {
+			if jobPtr.StatsPruned == nil {
+				jobPtr.StatsPruned = types.TimestampNow()
+			}
+			return nil
+		})
+	})
+	return err
+}
+
+// runStatsPruner periodically prunes the stats of every pipeline until 'ctx'
+// is cancelled. It runs in the PPS master.
+func (a *apiServer) runStatsPruner(ctx context.Context, pachClient *client.APIClient) {
+	ticker := time.NewTicker(statsPruneInterval)
+	defer ticker.Stop()
+	for {
+		if err := a.sudo(pachClient, func(superUserClient *client.APIClient) error {
+			pruned, err := a.pruneStats(superUserClient, nil, false)
+			for _, p := range pruned {
+				log.Infof("PPS master: pruned the stats of job %s (pipeline %q, %d bytes): %s",
+					p.Job.ID, p.Pipeline.Name, p.SizeBytes, p.Reason)
+			}
+			return err
+		}); err != nil {
+			log.Errorf("PPS master: could not prune stats: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestStatsRetention(t *testing.T) {
+	pipelineInfo := &pps.PipelineInfo{Pipeline: client.NewPipeline("p")}
+	maxAge, keepLast, err := statsRetention(pipelineInfo, 24*time.Hour, 10)
+	require.NoError(t, err)
+	require.Equal(t, 24*time.Hour, maxAge)
+	require.Equal(t, int64(10), keepLast)
+
+	// The pipeline's retention overrides the cluster's, and can disable it
+	pipelineInfo.StatsRetention = &pps.StatsRetention{MaxAge: types.DurationProto(0), KeepLast: -1}
+	maxAge, keepLast, err = statsRetention(pipelineInfo, 24*time.Hour, 10)
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(0), maxAge)
+	require.Equal(t, int64(0), keepLast)
+
+	pipelineInfo.StatsRetention = &pps.StatsRetention{MaxAge: types.DurationProto(-time.Hour)}
+	require.YesError(t, validateStatsRetention(pipelineInfo))
+}
+
+func TestStatsToPrune(t *testing.T) {
+	now := time.Date(2020, 6, 10, 0, 0, 0, 0, time.UTC)
+	var jobs []*pps.EtcdJobInfo
+	for i := 0; i < 5; i++ {
+		finished, err := types.TimestampProto(now.Add(-time.Duration(i) * 24 * time.Hour))
+		require.NoError(t, err)
+		jobs = append(jobs, &pps.EtcdJobInfo{
+			Job:         client.NewJob(fmt.Sprintf("job%d", i)),
+			Pipeline:    client.NewPipeline("p"),
+			StatsCommit: client.NewCommit("p", fmt.Sprintf("stats%d", i)),
+			State:       pps.JobState_JOB_SUCCESS,
+			Finished:    finished,
+		})
+	}
+	// Running jobs and jobs without stats are never pruned
+	jobs = append(jobs,
+		&pps.EtcdJobInfo{Job: client.NewJob("running"), StatsCommit: client.NewCommit("p", "running"), State: pps.JobState_JOB_RUNNING},
+		&pps.EtcdJobInfo{Job: client.NewJob("nostats"), State: pps.JobState_JOB_SUCCESS, Finished: jobs[4].Finished},
+	)
+	jobIDs := func(pruned []*pps.PrunedStats) []string {
+		var result []string
+		for _, p := range pruned {
+			result = append(result, p.Job.ID)
+		}
+		return result
+	}
+
+	require.Equal(t, []string{"job3", "job4"}, jobIDs(statsToPrune(jobs, now, 0, 3, "")))
+	require.Equal(t, []string{"job2", "job3", "job4"}, jobIDs(statsToPrune(jobs, now, 36*time.Hour, 0, "")))
+	require.Equal(t, []string{"job1", "job2", "job3", "job4"}, jobIDs(statsToPrune(jobs, now, 36*time.Hour, 1, "")))
+
+	// The head of the stats branch, and stats that were already pruned, are
+	// left out
+	jobs[3].StatsPruned = types.TimestampNow()
+	require.Equal(t, []string{"job4"}, jobIDs(statsToPrune(jobs, now, 0, 3, "")))
+	require.Equal(t, 0, len(statsToPrune(jobs, now, 0, 3, "stats4")))
+}
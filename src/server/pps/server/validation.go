@@ -0,0 +1,34 @@
+package server
+
+import (
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/validation"
+)
+
+// validateValidation returns an error if 'pipelineInfo' is a validation
+// pipeline that can't be run. Validation pipelines check a single PFS input
+// as a whole, so that each job's report covers the entire input commit, and
+// they don't run user code.
+func validateValidation(pipelineInfo *pps.PipelineInfo) error {
+	if pipelineInfo.Validation == nil {
+		return nil
+	}
+	if err := validation.ValidateSpec(pipelineInfo.Validation); err != nil {
+		return errors.Wrapf(err, "invalid pipeline spec")
+	}
+	if pipelineInfo.S3Out || pipelineInfo.Spout != nil || pipelineInfo.Service != nil {
+		return errors.New("invalid pipeline spec: validation isn't supported for spouts, services, or pipelines that output via Pachyderm's S3 gateway")
+	}
+	if t := pipelineInfo.Transform; len(t.Cmd) > 0 || len(t.ErrCmd) > 0 || t.Build != nil || t.Builtin != nil {
+		return errors.New("invalid pipeline spec: a validation pipeline cannot have a cmd, err_cmd, build or builtin transform")
+	}
+	input := pipelineInfo.Input
+	if input == nil || input.Pfs == nil {
+		return errors.New("invalid pipeline spec: a validation pipeline must have a single PFS input")
+	}
+	if input.Pfs.Glob != "/" {
+		return errors.New("invalid pipeline spec: the input of a validation pipeline must have the glob \"/\"")
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// validateVolumeInput checks that a volume input is well-formed.
+func validateVolumeInput(input *pps.VolumeInput) error {
+	switch {
+	case input.Name == "":
+		return errors.Errorf("input must specify a name")
+	case !path.IsAbs(input.MountPath):
+		return errors.Errorf("volume input %q must specify an absolute mount_path", input.Name)
+	case path.Clean(input.MountPath) == "/" || isUnder(input.MountPath, client.PPSInputPrefix) || isUnder(input.MountPath, "/pach-bin"):
+		return errors.Errorf("volume input %q cannot be mounted at %q, which is used by pachyderm", input.Name, input.MountPath)
+	case input.ClaimName == "" && input.NfsServer == "":
+		return errors.Errorf("volume input %q must specify a claim_name or an nfs_server", input.Name)
+	case input.ClaimName != "" && input.NfsServer != "":
+		return errors.Errorf("volume input %q cannot specify both a claim_name and an nfs_server", input.Name)
+	case input.NfsServer != "" && !path.IsAbs(input.Path):
+		return errors.Errorf("volume input %q must specify the absolute path of its nfs export", input.Name)
+	case len(input.FingerprintCmd) == 0:
+		// Without a fingerprint, datums would never be reprocessed when the
+		// volume's contents change
+		return errors.Errorf("volume input %q must specify a fingerprint_cmd", input.Name)
+	case input.Fingerprint != "":
+		return errors.Errorf("volume input %q cannot set 'fingerprint', which is computed by pachyderm", input.Name)
+	}
+	if input.FingerprintTimeout != nil {
+		timeout, err := types.DurationFromProto(input.FingerprintTimeout)
+		if err != nil {
+			return errors.Wrapf(err, "invalid fingerprint_timeout for volume input %q", input.Name)
+		}
+		if timeout <= 0 {
+			return errors.Errorf("volume input %q must have a positive fingerprint_timeout", input.Name)
+		}
+	}
+	return nil
+}
+
+func isUnder(p, dir string) bool {
+	p = path.Clean(p)
+	return p == dir || strings.HasPrefix(p, dir+"/")
+}
+
+// validateVolumePlacement checks that volume inputs only appear directly
+// inside of cross inputs. A volume input has a single datum, which only makes
+// sense as one dimension of a cross, and the volumes mounted by the workers
+// mustn't collide.
+func validateVolumePlacement(input *pps.Input) error {
+	mountPaths := make(map[string]string)
+	var visit func(input *pps.Input, inCross bool) error
+	visit = func(input *pps.Input, inCross bool) error {
+		if input.Volume != nil {
+			if !inCross {
+				return errors.Errorf("volume input %q must be crossed with another input", input.Volume.Name)
+			}
+			mountPath := path.Clean(input.Volume.MountPath)
+			for otherPath, other := range mountPaths {
+				if isUnder(mountPath, otherPath) || isUnder(otherPath, mountPath) {
+					return errors.Errorf("volume inputs %q and %q have overlapping mount paths", other, input.Volume.Name)
+				}
+			}
+			mountPaths[mountPath] = input.Volume.Name
+			return nil
+		}
+		for _, subInputs := range [][]*pps.Input{input.Join, input.Group, input.Union} {
+			for _, subInput := range subInputs {
+				if err := visit(subInput, false); err != nil {
+					return err
+				}
+			}
+		}
+		for _, subInput := range input.Cross {
+			if err := visit(subInput, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if input == nil {
+		return nil
+	}
+	return visit(input, false)
+}
+
+// volumeInputVolumes returns the volumes and read-only mounts that expose a
+// pipeline's volume inputs to its user container.
+func volumeInputVolumes(input *pps.Input) ([]v1.Volume, []v1.VolumeMount) {
+	var volumes []v1.Volume
+	var mounts []v1.VolumeMount
+	pps.VisitInput(input, func(input *pps.Input) {
+		if input.Volume == nil {
+			return
+		}
+		// Input names needn't be valid kubernetes names, so volumes are
+		// named after their position
+		name := fmt.Sprintf("volume-input-%d", len(volumes))
+		volume := v1.Volume{Name: name}
+		mount := v1.VolumeMount{
+			Name:      name,
+			MountPath: input.Volume.MountPath,
+			ReadOnly:  true,
+		}
+		if input.Volume.NfsServer != "" {
+			volume.NFS = &v1.NFSVolumeSource{
+				Server:   input.Volume.NfsServer,
+				Path:     input.Volume.Path,
+				ReadOnly: true,
+			}
+		} else {
+			volume.PersistentVolumeClaim = &v1.PersistentVolumeClaimVolumeSource{
+				ClaimName: input.Volume.ClaimName,
+				ReadOnly:  true,
+			}
+			mount.SubPath = strings.TrimPrefix(input.Volume.Path, "/")
+		}
+		volumes = append(volumes, volume)
+		mounts = append(mounts, mount)
+	})
+	return volumes, mounts
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateVolumeInput(t *testing.T) {
+	valid := func() *pps.VolumeInput {
+		return &pps.VolumeInput{
+			Name:           "ref",
+			MountPath:      "/ref",
+			ClaimName:      "genomes",
+			FingerprintCmd: []string{"ls", "-l"},
+		}
+	}
+	require.NoError(t, validateVolumeInput(valid()))
+	nfs := valid()
+	nfs.ClaimName, nfs.NfsServer, nfs.Path = "", "nfs.example.com", "/exports/genomes"
+	require.NoError(t, validateVolumeInput(nfs))
+
+	for _, breakIt := range []func(*pps.VolumeInput){
+		func(v *pps.VolumeInput) { v.Name = "" },
+		func(v *pps.VolumeInput) { v.MountPath = "ref" },
+		func(v *pps.VolumeInput) { v.MountPath = "/pfs/ref" },
+		func(v *pps.VolumeInput) { v.MountPath = "/" },
+		func(v *pps.VolumeInput) { v.ClaimName = "" },
+		func(v *pps.VolumeInput) { v.NfsServer = "nfs.example.com" },
+		func(v *pps.VolumeInput) { v.FingerprintCmd = nil },
+		func(v *pps.VolumeInput) { v.Fingerprint = "abc" },
+	} {
+		v := valid()
+		breakIt(v)
+		require.YesError(t, validateVolumeInput(v))
+	}
+}
+
+func TestValidateVolumePlacement(t *testing.T) {
+	pfsInput := &pps.Input{Pfs: &pps.PFSInput{Name: "in", Repo: "in", Glob: "/*"}}
+	volume := func(name, mountPath string) *pps.Input {
+		return &pps.Input{Volume: &pps.VolumeInput{Name: name, MountPath: mountPath}}
+	}
+	require.NoError(t, validateVolumePlacement(&pps.Input{Cross: []*pps.Input{pfsInput, volume("ref", "/ref")}}))
+	require.NoError(t, validateVolumePlacement(&pps.Input{Union: []*pps.Input{
+		{Cross: []*pps.Input{pfsInput, volume("a", "/ref/a")}},
+		{Cross: []*pps.Input{pfsInput, volume("b", "/ref/b")}},
+	}}))
+	require.YesError(t, validateVolumePlacement(volume("ref", "/ref")))
+	require.YesError(t, validateVolumePlacement(&pps.Input{Union: []*pps.Input{pfsInput, volume("ref", "/ref")}}))
+	require.YesError(t, validateVolumePlacement(&pps.Input{Cross: []*pps.Input{
+		pfsInput, volume("a", "/ref"), volume("b", "/ref/b"),
+	}}))
+}
+
+func TestVolumeInputVolumes(t *testing.T) {
+	volumes, mounts := volumeInputVolumes(&pps.Input{Cross: []*pps.Input{
+		{Pfs: &pps.PFSInput{Name: "in"}},
+		{Volume: &pps.VolumeInput{Name: "claim", MountPath: "/claim", ClaimName: "genomes", Path: "/hg38"}},
+		{Volume: &pps.VolumeInput{Name: "nfs", MountPath: "/nfs", NfsServer: "nfs.example.com", Path: "/exports"}},
+	}})
+	require.Equal(t, 2, len(volumes))
+	require.Equal(t, 2, len(mounts))
+	require.Equal(t, "genomes", volumes[0].PersistentVolumeClaim.ClaimName)
+	require.True(t, volumes[0].PersistentVolumeClaim.ReadOnly)
+	require.Equal(t, "hg38", mounts[0].SubPath)
+	require.Equal(t, "nfs.example.com", volumes[1].NFS.Server)
+	require.Equal(t, "/exports", volumes[1].NFS.Path)
+	require.Equal(t, "", mounts[1].SubPath)
+	for i := range mounts {
+		require.Equal(t, volumes[i].Name, mounts[i].Name)
+		require.True(t, mounts[i].ReadOnly)
+	}
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ignoredLabels are the labels that Pachyderm sets on a pipeline's workers and
+// uses to select them. Pipelines created before metadata was validated may set
+// them, so they're ignored (Pachyderm's values take precedence) rather than
+// rejected.
+var ignoredLabels = map[string]bool{
+	"app":       true,
+	"suite":     true,
+	"component": true,
+}
+
+// systemLabels are the other labels that Pachyderm sets on a pipeline's
+// workers, so a pipeline's metadata can't set them
+var systemLabels = map[string]bool{
+	pipelineNameLabel: true,
+	workerClassLabel:  true,
+}
+
+// systemAnnotations are the annotations that Pachyderm sets on a pipeline's
+// workers, so a pipeline's metadata can't set them
+var systemAnnotations = map[string]bool{
+	pipelineNameLabel:         true,
+	pachVersionAnnotation:     true,
+	specCommitAnnotation:      true,
+	hashedAuthTokenAnnotation: true,
+}
+
+// iamRoleAnnotation is the annotation that Pachyderm sets on a pipeline's
+// workers if pachd is deployed with an IAM role. Otherwise pipelines may set it
+// themselves.
+const iamRoleAnnotation = "iam.amazonaws.com/role"
+
+// validateMetadata checks that a pipeline's metadata (if any) are valid
+// kubernetes labels and annotations, and don't override Pachyderm's. 'iamRole'
+// is the IAM role that pachd applies to workers, if any.
+func validateMetadata(metadata *pps.Metadata, iamRole string) error {
+	if metadata == nil {
+		return nil
+	}
+	for k, v := range metadata.Labels {
+		if ignoredLabels[k] {
+			logrus.Warnf("ignoring label %q in pipeline metadata, as it is set by Pachyderm", k)
+			continue
+		}
+		if systemLabels[k] {
+			return errors.Errorf("invalid pipeline spec: label %q is set by Pachyderm", k)
+		}
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return errors.Errorf("invalid pipeline spec: invalid label %q: %s", k, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return errors.Errorf("invalid pipeline spec: invalid value for label %q: %s", k, strings.Join(errs, "; "))
+		}
+	}
+	for k := range metadata.Annotations {
+		if systemAnnotations[k] || (k == iamRoleAnnotation && iamRole != "") {
+			return errors.Errorf("invalid pipeline spec: annotation %q is set by Pachyderm", k)
+		}
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return errors.Errorf("invalid pipeline spec: invalid annotation %q: %s", k, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// mergeMetadata returns the labels (or annotations) 'system' merged with a
+// pipeline's 'user' labels (or annotations). Keys in 'system' take precedence,
+// as Pachyderm relies on them.
+func mergeMetadata(system map[string]string, user map[string]string) map[string]string {
+	result := make(map[string]string)
+	for k, v := range user {
+		result[k] = v
+	}
+	for k, v := range system {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	require.NoError(t, validateMetadata(nil, ""))
+	require.NoError(t, validateMetadata(&pps.Metadata{
+		Labels:      map[string]string{"team": "vision", "example.com/tier": "batch"},
+		Annotations: map[string]string{"example.com/owner": "any value, even with spaces"},
+	}, ""))
+
+	// Pachyderm's own labels and annotations are reserved
+	require.YesError(t, validateMetadata(&pps.Metadata{Labels: map[string]string{pipelineNameLabel: "foo"}}, ""))
+	require.YesError(t, validateMetadata(&pps.Metadata{Annotations: map[string]string{specCommitAnnotation: "foo"}}, ""))
+
+	// ...except for the labels that existing pipelines may set, which are
+	// ignored
+	require.NoError(t, validateMetadata(&pps.Metadata{Labels: map[string]string{"app": "foo", "component": "bar"}}, ""))
+
+	// The IAM role annotation is only reserved if pachd sets it
+	iamRole := &pps.Metadata{Annotations: map[string]string{iamRoleAnnotation: "my-role"}}
+	require.NoError(t, validateMetadata(iamRole, ""))
+	require.YesError(t, validateMetadata(iamRole, "pachd-role"))
+
+	// Keys and label values must be valid kubernetes metadata
+	require.YesError(t, validateMetadata(&pps.Metadata{Labels: map[string]string{"not a key": "foo"}}, ""))
+	require.YesError(t, validateMetadata(&pps.Metadata{Labels: map[string]string{"team": "not a value"}}, ""))
+	require.YesError(t, validateMetadata(&pps.Metadata{Annotations: map[string]string{"-owner": "foo"}}, ""))
+}
+
+func TestMergeMetadata(t *testing.T) {
+	merged := mergeMetadata(
+		map[string]string{"app": "pipeline-edges-v1"},
+		map[string]string{"app": "mine", "team": "vision"},
+	)
+	require.Equal(t, map[string]string{"app": "pipeline-edges-v1", "team": "vision"}, merged)
+	require.Equal(t, map[string]string{}, mergeMetadata(nil, nil))
+}
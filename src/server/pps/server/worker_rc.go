@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"net"
 	"os"
 	"strconv"
 
@@ -21,6 +22,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -32,6 +34,12 @@ const (
 	pachVersionAnnotation     = "version"
 	specCommitAnnotation      = "specCommit"
 	hashedAuthTokenAnnotation = "authTokenHash"
+	escalatedMemoryAnnotation = "escalatedMemory"
+
+	// workerTerminationGracePeriod is how long (in seconds) kubernetes waits,
+	// after asking a worker to shut down, for the worker to finish its
+	// in-flight datums before killing it
+	workerTerminationGracePeriod = int64(60)
 )
 
 // Parameters used when creating the kubernetes replication controller in charge
@@ -42,7 +50,9 @@ type workerOptions struct {
 	s3GatewayPort int32  // s3 gateway port (if any s3 pipeline inputs)
 
 	userImage             string              // The user's pipeline/job image
-	labels                map[string]string   // k8s labels attached to the RC and workers
+	workerCommand         string              // The worker binary that the user container runs
+	selector              map[string]string   // k8s labels that Pachyderm uses to select the workers
+	labels                map[string]string   // k8s labels attached to the RC and workers ('selector' and the pipeline's labels)
 	annotations           map[string]string   // k8s annotations attached to the RC and workers
 	parallelism           int32               // Number of replicas the RC maintains
 	cacheSize             string              // Size of cache that sidecar uses
@@ -50,9 +60,11 @@ type workerOptions struct {
 	resourceLimits        *v1.ResourceList    // Resources requested by pipeline/job pods, applied to the user and init containers
 	sidecarResourceLimits *v1.ResourceList    // Resources requested by pipeline/job pods, applied to the sidecar container
 	workerEnv             []v1.EnvVar         // Environment vars set in the user container
+	workerEnvFrom         []v1.EnvFromSource  // Secrets whose keys are set as environment vars in the user container
 	volumes               []v1.Volume         // Volumes that we expose to the user container
 	volumeMounts          []v1.VolumeMount    // Paths where we mount each volume in 'volumes'
 	schedulingSpec        *pps.SchedulingSpec // the SchedulingSpec for the pipeline
+	runtimeClass          string              // The RuntimeClass that sandboxes the workers (if any)
 	podSpec               string
 	podPatch              string
 
@@ -114,6 +126,19 @@ func (a *apiServer) workerPodSpec(options *workerOptions) (v1.PodSpec, error) {
 		return v1.PodSpec{}, err
 	}
 	sidecarEnv = append(sidecarEnv, storageEnvVars...)
+	if a.env.StorageResidencyBuckets != "" {
+		// The sidecar's Object API stores the output of pipelines with a
+		// residency in the residency's bucket
+		sidecarEnv = append(sidecarEnv, v1.EnvVar{Name: "STORAGE_RESIDENCY_BUCKETS", Value: a.env.StorageResidencyBuckets})
+	}
+	if a.env.StorageReplicaBuckets != "" {
+		// The sidecar's Object API reads from the replica of the default bucket
+		// in the region that the workers are pinned to, if any
+		sidecarEnv = append(sidecarEnv, v1.EnvVar{Name: "STORAGE_REPLICA_BUCKETS", Value: a.env.StorageReplicaBuckets})
+		if region := workerRegion(options.schedulingSpec); region != "" {
+			sidecarEnv = append(sidecarEnv, v1.EnvVar{Name: "STORAGE_REGION", Value: region})
+		}
+	}
 
 	// Set up worker env vars
 	workerEnv := append(options.workerEnv, []v1.EnvVar{
@@ -191,6 +216,10 @@ func (a *apiServer) workerPodSpec(options *workerOptions) (v1.PodSpec, error) {
 		sidecarEnv = append(sidecarEnv, v1.EnvVar{Name: "LOKI_LOGGING", Value: "true"})
 		workerEnv = append(workerEnv, v1.EnvVar{Name: "LOKI_LOGGING", Value: "true"})
 	}
+	if a.env.FaultInjection {
+		sidecarEnv = append(sidecarEnv, v1.EnvVar{Name: "FAULT_INJECTION", Value: "true"})
+		workerEnv = append(workerEnv, v1.EnvVar{Name: "FAULT_INJECTION", Value: "true"})
+	}
 
 	// This only happens in local deployment.  We want the workers to be
 	// able to read from/write to the hostpath volume as well.
@@ -281,6 +310,7 @@ func (a *apiServer) workerPodSpec(options *workerOptions) (v1.PodSpec, error) {
 	if resp.State != enterprise.State_ACTIVE {
 		workerImage = assets.AddRegistry("", workerImage)
 	}
+	terminationGracePeriod := workerTerminationGracePeriod
 	podSpec := v1.PodSpec{
 		InitContainers: []v1.Container{
 			{
@@ -301,9 +331,10 @@ func (a *apiServer) workerPodSpec(options *workerOptions) (v1.PodSpec, error) {
 			{
 				Name:            client.PPSWorkerUserContainerName,
 				Image:           options.userImage,
-				Command:         []string{"/pach-bin/worker"},
+				Command:         []string{options.workerCommand},
 				ImagePullPolicy: v1.PullPolicy(pullPolicy),
 				Env:             workerEnv,
+				EnvFrom:         options.workerEnvFrom,
 				Resources: v1.ResourceRequirements{
 					Requests: v1.ResourceList{
 						v1.ResourceCPU:    cpuZeroQuantity,
@@ -332,12 +363,15 @@ func (a *apiServer) workerPodSpec(options *workerOptions) (v1.PodSpec, error) {
 		RestartPolicy:                 "Always",
 		Volumes:                       options.volumes,
 		ImagePullSecrets:              options.imagePullSecrets,
-		TerminationGracePeriodSeconds: &zeroVal,
+		TerminationGracePeriodSeconds: &terminationGracePeriod,
 		SecurityContext:               securityContext,
 	}
 	if options.schedulingSpec != nil {
 		podSpec.NodeSelector = options.schedulingSpec.NodeSelector
 		podSpec.PriorityClassName = options.schedulingSpec.PriorityClassName
+		if options.schedulingSpec.GangScheduling.GetScheduler() == gangSchedulerVolcano {
+			podSpec.SchedulerName = gangSchedulerVolcano
+		}
 	}
 
 	if options.resourceRequests != nil {
@@ -401,6 +435,11 @@ func (a *apiServer) workerPodSpec(options *workerOptions) (v1.PodSpec, error) {
 			return v1.PodSpec{}, err
 		}
 	}
+	// Set the sandbox's RuntimeClass after applying the user's pod spec and
+	// patch, so that they can't run a sandboxed pipeline without the sandbox
+	if options.runtimeClass != "" {
+		podSpec.RuntimeClassName = &options.runtimeClass
+	}
 	return podSpec, nil
 }
 
@@ -461,6 +500,15 @@ func (a *apiServer) getWorkerOptions(ptr *pps.EtcdPipelineInfo, pipelineInfo *pp
 	if userImage == "" {
 		userImage = DefaultUserImage
 	}
+	if pipelineInfo.ImageDigest != "" {
+		userImage = pipelineInfo.ImageDigest
+	}
+	// The worker copied into /pach-bin is built without cgo, so it can't load
+	// plugins. The builtin worker image contains one that can.
+	workerCommand := "/pach-bin/worker"
+	if transform.Builtin != nil {
+		workerCommand = "/app/worker"
+	}
 
 	workerEnv := []v1.EnvVar{{
 		Name:  client.PPSPipelineNameEnv,
@@ -476,6 +524,19 @@ func (a *apiServer) getWorkerOptions(ptr *pps.EtcdPipelineInfo, pipelineInfo *pp
 		)
 	}
 
+	// Variables in workerEnv take precedence over those from secrets
+	var workerEnvFrom []v1.EnvFromSource
+	for _, secret := range transform.EnvFromSecrets {
+		workerEnvFrom = append(workerEnvFrom, v1.EnvFromSource{
+			Prefix: secret.Prefix,
+			SecretRef: &v1.SecretEnvSource{
+				LocalObjectReference: v1.LocalObjectReference{
+					Name: secret.Name,
+				},
+			},
+		})
+	}
+
 	var volumes []v1.Volume
 	var volumeMounts []v1.VolumeMount
 	for _, secret := range transform.Secrets {
@@ -508,6 +569,13 @@ func (a *apiServer) getWorkerOptions(ptr *pps.EtcdPipelineInfo, pipelineInfo *pp
 		}
 	}
 
+	inputVolumes, inputVolumeMounts := volumeInputVolumes(pipelineInfo.Input)
+	volumes = append(volumes, inputVolumes...)
+	volumeMounts = append(volumeMounts, inputVolumeMounts...)
+	egressVolumes, egressVolumeMounts := egressSecretVolumes(pipelineInfo.Egress)
+	volumes = append(volumes, egressVolumes...)
+	volumeMounts = append(volumeMounts, egressVolumeMounts...)
+
 	volumes = append(volumes, v1.Volume{
 		Name: "pach-bin",
 		VolumeSource: v1.VolumeSource{
@@ -544,24 +612,16 @@ func (a *apiServer) getWorkerOptions(ptr *pps.EtcdPipelineInfo, pipelineInfo *pp
 		hashedAuthTokenAnnotation: hashAuthToken(ptr.AuthToken),
 	}
 	if a.iamRole != "" {
-		annotations["iam.amazonaws.com/role"] = a.iamRole
+		annotations[iamRoleAnnotation] = a.iamRole
 	}
 
-	// add the user's custom metadata (annotations and labels).
+	// add the user's custom metadata (annotations and labels). Workers are
+	// only selected by Pachyderm's labels, so that changing the pipeline's
+	// labels doesn't orphan its workers
 	metadata := pipelineInfo.GetMetadata()
-	if metadata != nil {
-		for k, v := range metadata.Annotations {
-			if annotations[k] == "" {
-				annotations[k] = v
-			}
-		}
-
-		for k, v := range metadata.Labels {
-			if labels[k] == "" {
-				labels[k] = v
-			}
-		}
-	}
+	selector := labels
+	labels = mergeMetadata(selector, metadata.GetLabels())
+	annotations = mergeMetadata(annotations, metadata.GetAnnotations())
 
 	// A service can be present either directly on the pipeline spec
 	// or on the spout field of the spec.
@@ -579,10 +639,11 @@ func (a *apiServer) getWorkerOptions(ptr *pps.EtcdPipelineInfo, pipelineInfo *pp
 	}
 
 	// Generate options for new RC
-	return &workerOptions{
+	options := &workerOptions{
 		rcName:                rcName,
 		s3GatewayPort:         s3GatewayPort,
 		specCommit:            ptr.SpecCommit.ID,
+		selector:              selector,
 		labels:                labels,
 		annotations:           annotations,
 		parallelism:           int32(0), // pipelines start w/ 0 workers & are scaled up
@@ -590,16 +651,23 @@ func (a *apiServer) getWorkerOptions(ptr *pps.EtcdPipelineInfo, pipelineInfo *pp
 		resourceLimits:        resourceLimits,
 		sidecarResourceLimits: sidecarResourceLimits,
 		userImage:             userImage,
+		workerCommand:         workerCommand,
 		workerEnv:             workerEnv,
+		workerEnvFrom:         workerEnvFrom,
 		volumes:               volumes,
 		volumeMounts:          volumeMounts,
 		imagePullSecrets:      imagePullSecrets,
 		cacheSize:             pipelineInfo.CacheSize,
 		service:               service,
 		schedulingSpec:        pipelineInfo.SchedulingSpec,
+		runtimeClass:          sandboxRuntimeClass(pipelineInfo.Sandbox),
 		podSpec:               pipelineInfo.PodSpec,
 		podPatch:              pipelineInfo.PodPatch,
-	}, nil
+	}
+	if err := escalateWorkerMemory(ptr, pipelineInfo, options); err != nil {
+		return nil, err
+	}
+	return options, nil
 }
 
 // noValidOptions error may be returned by createWorkerSvcAndRc to indicate that
@@ -623,10 +691,48 @@ func (a *apiServer) createWorkerSvcAndRc(ctx context.Context, ptr *pps.EtcdPipel
 	if err != nil {
 		return noValidOptionsErr{err}
 	}
+	// Derive the options of the pipeline's worker classes (if any) before
+	// workerPodSpec adds the storage volumes to 'options'
+	var classOptions []*workerOptions
+	for _, class := range pipelineInfo.GetDatumSizing().GetWorkerClasses() {
+		o, err := workerClassOptions(options, class)
+		if err != nil {
+			return noValidOptionsErr{err}
+		}
+		classOptions = append(classOptions, o)
+	}
 	podSpec, err := a.workerPodSpec(options)
 	if err != nil {
 		return err
 	}
+	// Create the network policy before the workers, so that they never run
+	// without it
+	if pipelineInfo.NetworkPolicy != nil {
+		policy, err := workerNetworkPolicy(options.rcName, options.selector, pipelineInfo.NetworkPolicy, net.LookupHost)
+		if err != nil {
+			return err
+		}
+		policies := a.env.GetKubeClient().NetworkingV1().NetworkPolicies(a.namespace)
+		if _, err := policies.Create(policy); err != nil {
+			if !isAlreadyExistsErr(err) {
+				return errors.Wrapf(err, "could not create network policy")
+			}
+			if _, err := policies.Update(policy); err != nil {
+				return errors.Wrapf(err, "could not update network policy")
+			}
+		}
+	}
+	podLabels, podAnnotations := options.labels, options.annotations
+	if gang := pipelineInfo.SchedulingSpec.GetGangScheduling(); gang != nil {
+		// The workers are scheduled together once they're all scaled up
+		workers := int32(ptr.Parallelism)
+		podLabels, podAnnotations = gangPodMetadata(gang, options.rcName, workers, podLabels, podAnnotations)
+		if gang.Scheduler == gangSchedulerVolcano {
+			if err := a.createVolcanoPodGroup(gang, options.rcName, workers, options.labels); err != nil {
+				return err
+			}
+		}
+	}
 	rc := &v1.ReplicationController{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "ReplicationController",
@@ -638,13 +744,13 @@ func (a *apiServer) createWorkerSvcAndRc(ctx context.Context, ptr *pps.EtcdPipel
 			Annotations: options.annotations,
 		},
 		Spec: v1.ReplicationControllerSpec{
-			Selector: options.labels,
+			Selector: options.selector,
 			Replicas: &options.parallelism,
 			Template: &v1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:        options.rcName,
-					Labels:      options.labels,
-					Annotations: options.annotations,
+					Labels:      podLabels,
+					Annotations: podAnnotations,
 				},
 				Spec: podSpec,
 			},
@@ -655,10 +761,40 @@ func (a *apiServer) createWorkerSvcAndRc(ctx context.Context, ptr *pps.EtcdPipel
 			return err
 		}
 	}
-	serviceAnnotations := map[string]string{
+	if err := a.createWorkerClassRCs(classOptions); err != nil {
+		return err
+	}
+	// Only allow one worker at a time to be evicted voluntarily (e.g. by a node
+	// drain), so that draining several nodes doesn't interrupt all of a
+	// pipeline's workers at once
+	maxUnavailable := intstr.FromInt(1)
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PodDisruptionBudget",
+			APIVersion: "policy/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        options.rcName,
+			Labels:      options.labels,
+			Annotations: pipelineInfo.GetMetadata().GetAnnotations(),
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			Selector:       &metav1.LabelSelector{MatchLabels: options.selector},
+			MaxUnavailable: &maxUnavailable,
+		},
+	}
+	if _, err := a.env.GetKubeClient().PolicyV1beta1().PodDisruptionBudgets(a.namespace).Create(pdb); err != nil {
+		if !isAlreadyExistsErr(err) {
+			// Clusters deployed by older versions of pachctl may not grant
+			// pachd access to PodDisruptionBudgets, which shouldn't stop the
+			// pipeline from running
+			log.Warnf("PPS master: could not create PodDisruptionBudget for %q: %v", pipelineInfo.Pipeline.Name, err)
+		}
+	}
+	serviceAnnotations := mergeMetadata(map[string]string{
 		"prometheus.io/scrape": "true",
 		"prometheus.io/port":   strconv.Itoa(workerstats.PrometheusPort),
-	}
+	}, pipelineInfo.GetMetadata().GetAnnotations())
 
 	service := &v1.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -671,7 +807,7 @@ func (a *apiServer) createWorkerSvcAndRc(ctx context.Context, ptr *pps.EtcdPipel
 			Annotations: serviceAnnotations,
 		},
 		Spec: v1.ServiceSpec{
-			Selector: options.labels,
+			Selector: options.selector,
 			Ports: []v1.ServicePort{
 				{
 					Port: int32(a.workerGrpcPort),
@@ -713,7 +849,7 @@ func (a *apiServer) createWorkerSvcAndRc(ctx context.Context, ptr *pps.EtcdPipel
 				Annotations: options.annotations,
 			},
 			Spec: v1.ServiceSpec{
-				Selector: options.labels,
+				Selector: options.selector,
 				Type:     serviceType,
 				Ports:    servicePort,
 			},
@@ -0,0 +1,238 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// The sources of worker pods' resource usage
+	usageSourceMetricsServer = "metrics-server"
+	usageSourceKubelet       = "kubelet"
+)
+
+// podUsage is the resource usage of a pod at 'sampled'
+type podUsage struct {
+	cpu     float64 // cores
+	memory  uint64  // working set bytes
+	sampled time.Time
+}
+
+// podMetricsList is the subset of metrics-server's PodMetricsList that
+// worker resources are read from
+type podMetricsList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Timestamp  time.Time `json:"timestamp"`
+		Containers []struct {
+			Name  string          `json:"name"`
+			Usage v1.ResourceList `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// parsePodMetrics returns the usage of each pod in a metrics-server
+// PodMetricsList, by pod name.
+func parsePodMetrics(data []byte) (map[string]*podUsage, error) {
+	list := &podMetricsList{}
+	if err := json.Unmarshal(data, list); err != nil {
+		return nil, errors.Wrapf(err, "could not parse pod metrics")
+	}
+	result := make(map[string]*podUsage)
+	for _, item := range list.Items {
+		usage := &podUsage{sampled: item.Timestamp}
+		for _, container := range item.Containers {
+			if cpu, ok := container.Usage[v1.ResourceCPU]; ok {
+				usage.cpu += float64(cpu.MilliValue()) / 1000
+			}
+			if memory, ok := container.Usage[v1.ResourceMemory]; ok {
+				usage.memory += uint64(memory.Value())
+			}
+		}
+		result[item.Metadata.Name] = usage
+	}
+	return result, nil
+}
+
+// kubeletSummary is the subset of the kubelet's stats summary that worker
+// resources are read from
+type kubeletSummary struct {
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		CPU *struct {
+			Time           time.Time `json:"time"`
+			UsageNanoCores *uint64   `json:"usageNanoCores"`
+		} `json:"cpu"`
+		Memory *struct {
+			Time            time.Time `json:"time"`
+			WorkingSetBytes *uint64   `json:"workingSetBytes"`
+		} `json:"memory"`
+	} `json:"pods"`
+}
+
+// parseKubeletSummary returns the usage of each pod in 'namespace' in a
+// kubelet's stats summary, by pod name.
+func parseKubeletSummary(data []byte, namespace string) (map[string]*podUsage, error) {
+	summary := &kubeletSummary{}
+	if err := json.Unmarshal(data, summary); err != nil {
+		return nil, errors.Wrapf(err, "could not parse kubelet stats summary")
+	}
+	result := make(map[string]*podUsage)
+	for _, pod := range summary.Pods {
+		if pod.PodRef.Namespace != namespace {
+			continue
+		}
+		usage := &podUsage{}
+		if pod.CPU != nil && pod.CPU.UsageNanoCores != nil {
+			usage.cpu = float64(*pod.CPU.UsageNanoCores) / 1e9
+			usage.sampled = pod.CPU.Time
+		}
+		if pod.Memory != nil && pod.Memory.WorkingSetBytes != nil {
+			usage.memory = *pod.Memory.WorkingSetBytes
+			if usage.sampled.IsZero() {
+				usage.sampled = pod.Memory.Time
+			}
+		}
+		result[pod.PodRef.Name] = usage
+	}
+	return result, nil
+}
+
+// workerPodUsage returns the resource usage of 'pods' (the pods of the
+// worker RC 'rcName'), by pod name, and where it came from. Usage is read
+// from metrics-server if it's installed, and otherwise from the kubelets of
+// the pods' nodes.
+func (a *apiServer) workerPodUsage(rcName string, pods []v1.Pod) (map[string]*podUsage, string, error) {
+	restClient := a.env.GetKubeClient().CoreV1().RESTClient()
+	data, err := restClient.Get().
+		AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces", a.namespace, "pods").
+		Param("labelSelector", fmt.Sprintf("app=%s", rcName)).
+		DoRaw()
+	if err == nil {
+		usage, err := parsePodMetrics(data)
+		return usage, usageSourceMetricsServer, err
+	}
+	log.Debugf("could not get worker pod metrics from metrics-server (%v); falling back to the kubelet", err)
+	result := make(map[string]*podUsage)
+	nodes := make(map[string]bool)
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || nodes[pod.Spec.NodeName] {
+			continue
+		}
+		nodes[pod.Spec.NodeName] = true
+		data, err := restClient.Get().
+			Resource("nodes").Name(pod.Spec.NodeName).
+			SubResource("proxy").Suffix("stats/summary").
+			DoRaw()
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "could not get the stats summary of node %q", pod.Spec.NodeName)
+		}
+		usage, err := parseKubeletSummary(data, a.namespace)
+		if err != nil {
+			return nil, "", err
+		}
+		for name, u := range usage {
+			result[name] = u
+		}
+	}
+	return result, usageSourceKubelet, nil
+}
+
+// workerResources describes 'pod', whose resource usage is 'usage' (which
+// may be nil) as reported by 'source'.
+func workerResources(pod *v1.Pod, usage *podUsage, source string) *pps.WorkerResources {
+	result := &pps.WorkerResources{Phase: string(pod.Status.Phase)}
+	if usage != nil {
+		result.Cpu = float32(usage.cpu)
+		result.MemoryBytes = usage.memory
+		result.UsageSource = source
+		if !usage.sampled.IsZero() {
+			result.Sampled, _ = types.TimestampProto(usage.sampled)
+		}
+	}
+	var cpuLimit, memoryLimit resource.Quantity
+	for _, container := range pod.Spec.Containers {
+		if cpu, ok := container.Resources.Limits[v1.ResourceCPU]; ok {
+			cpuLimit.Add(cpu)
+		}
+		if memory, ok := container.Resources.Limits[v1.ResourceMemory]; ok {
+			memoryLimit.Add(memory)
+		}
+	}
+	result.CpuLimit = float32(cpuLimit.MilliValue()) / 1000
+	result.MemoryLimitBytes = uint64(memoryLimit.Value())
+	for _, status := range pod.Status.ContainerStatuses {
+		result.Restarts += status.RestartCount
+		if waiting := status.State.Waiting; waiting != nil && result.WaitingReason == "" {
+			result.WaitingReason = waiting.Reason
+		}
+		terminated := status.LastTerminationState.Terminated
+		if terminated == nil {
+			continue
+		}
+		last := result.LastTermination
+		if last == nil || last.Finished.Compare(toTimestamp(terminated.FinishedAt.Time)) < 0 {
+			result.LastTermination = &pps.ContainerTermination{
+				Container: status.Name,
+				Reason:    terminated.Reason,
+				ExitCode:  terminated.ExitCode,
+				Finished:  toTimestamp(terminated.FinishedAt.Time),
+			}
+		}
+	}
+	return result
+}
+
+func toTimestamp(t time.Time) *types.Timestamp {
+	result, _ := types.TimestampProto(t)
+	return result
+}
+
+// addWorkerResources fills in the resources of the workers in 'statuses',
+// which belong to the worker RC 'rcName'. Workers whose pods didn't report a
+// status (e.g. because they're crash looping) are added with only their
+// resources, except for those in 'otherJobs', which are working on other
+// jobs. Errors are logged, as the resources are only informational.
+func (a *apiServer) addWorkerResources(rcName string, statuses []*pps.WorkerStatus, otherJobs map[string]bool) []*pps.WorkerStatus {
+	pods, err := a.rcPods(rcName)
+	if err != nil {
+		log.Errorf("could not list the pods of %q: %v", rcName, err)
+		return statuses
+	}
+	usage, source, err := a.workerPodUsage(rcName, pods)
+	if err != nil {
+		log.Warnf("could not get the resource usage of the pods of %q: %v", rcName, err)
+	}
+	byPod := make(map[string]*pps.WorkerStatus)
+	for _, status := range statuses {
+		byPod[status.WorkerID] = status
+	}
+	for i := range pods {
+		pod := &pods[i]
+		if otherJobs[pod.Name] {
+			continue
+		}
+		status, ok := byPod[pod.Name]
+		if !ok {
+			status = &pps.WorkerStatus{WorkerID: pod.Name}
+			statuses = append(statuses, status)
+		}
+		status.Resources = workerResources(pod, usage[pod.Name], source)
+	}
+	return statuses
+}
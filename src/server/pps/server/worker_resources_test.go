@@ -0,0 +1,120 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParsePodMetrics(t *testing.T) {
+	usage, err := parsePodMetrics([]byte(`{
+  "kind": "PodMetricsList",
+  "items": [{
+    "metadata": {"name": "pipeline-p-v1-abcde"},
+    "timestamp": "2020-06-10T00:00:00Z",
+    "containers": [
+      {"name": "user", "usage": {"cpu": "250m", "memory": "64Mi"}},
+      {"name": "storage", "usage": {"cpu": "50m", "memory": "16Mi"}}
+    ]
+  }]
+}`))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(usage))
+	u := usage["pipeline-p-v1-abcde"]
+	require.NotNil(t, u)
+	require.Equal(t, 0.3, u.cpu)
+	require.Equal(t, uint64(80*1024*1024), u.memory)
+	require.Equal(t, time.Date(2020, 6, 10, 0, 0, 0, 0, time.UTC), u.sampled)
+
+	_, err = parsePodMetrics([]byte("not json"))
+	require.YesError(t, err)
+}
+
+func TestParseKubeletSummary(t *testing.T) {
+	usage, err := parseKubeletSummary([]byte(`{
+  "node": {"nodeName": "node-1"},
+  "pods": [
+    {
+      "podRef": {"name": "pipeline-p-v1-abcde", "namespace": "default"},
+      "cpu": {"time": "2020-06-10T00:00:00Z", "usageNanoCores": 500000000},
+      "memory": {"time": "2020-06-10T00:00:00Z", "workingSetBytes": 1048576}
+    },
+    {
+      "podRef": {"name": "pipeline-p-v1-fghij", "namespace": "default"},
+      "memory": {"time": "2020-06-10T00:00:01Z", "workingSetBytes": 2048}
+    },
+    {
+      "podRef": {"name": "other", "namespace": "kube-system"},
+      "cpu": {"time": "2020-06-10T00:00:00Z", "usageNanoCores": 1}
+    }
+  ]
+}`), "default")
+	require.NoError(t, err)
+	require.Equal(t, 2, len(usage))
+	require.Equal(t, 0.5, usage["pipeline-p-v1-abcde"].cpu)
+	require.Equal(t, uint64(1048576), usage["pipeline-p-v1-abcde"].memory)
+	require.Equal(t, 0.0, usage["pipeline-p-v1-fghij"].cpu)
+	require.Equal(t, time.Date(2020, 6, 10, 0, 0, 1, 0, time.UTC), usage["pipeline-p-v1-fghij"].sampled)
+}
+
+func TestWorkerResources(t *testing.T) {
+	finished := time.Date(2020, 6, 10, 0, 0, 0, 0, time.UTC)
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pipeline-p-v1-abcde"},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "user", Resources: v1.ResourceRequirements{Limits: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("1"),
+					v1.ResourceMemory: resource.MustParse("1Gi"),
+				}}},
+				{Name: "storage", Resources: v1.ResourceRequirements{Limits: v1.ResourceList{
+					v1.ResourceCPU: resource.MustParse("500m"),
+				}}},
+			},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodRunning,
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					Name:         "user",
+					RestartCount: 3,
+					State:        v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+						Reason: "OOMKilled", ExitCode: 137, FinishedAt: metav1.NewTime(finished),
+					}},
+				},
+				{
+					Name:         "storage",
+					RestartCount: 1,
+					LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+						Reason: "Error", ExitCode: 1, FinishedAt: metav1.NewTime(finished.Add(-time.Hour)),
+					}},
+				},
+			},
+		},
+	}
+
+	// Without usage, only the pod's status and limits are reported
+	resources := workerResources(pod, nil, "")
+	require.Equal(t, "Running", resources.Phase)
+	require.Equal(t, "", resources.UsageSource)
+	require.Nil(t, resources.Sampled)
+	require.Equal(t, float32(1.5), resources.CpuLimit)
+	require.Equal(t, uint64(1024*1024*1024), resources.MemoryLimitBytes)
+	require.Equal(t, int32(4), resources.Restarts)
+	require.Equal(t, "CrashLoopBackOff", resources.WaitingReason)
+	require.Equal(t, "user", resources.LastTermination.Container)
+	require.Equal(t, "OOMKilled", resources.LastTermination.Reason)
+	require.Equal(t, int32(137), resources.LastTermination.ExitCode)
+
+	resources = workerResources(pod, &podUsage{cpu: 0.25, memory: 1024, sampled: finished}, usageSourceKubelet)
+	require.Equal(t, usageSourceKubelet, resources.UsageSource)
+	require.Equal(t, float32(0.25), resources.Cpu)
+	require.Equal(t, uint64(1024), resources.MemoryBytes)
+	require.NotNil(t, resources.Sampled)
+}
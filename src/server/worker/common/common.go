@@ -5,9 +5,14 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
+	"fmt"
+	"strings"
+	"syscall"
 
 	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
 	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/exec"
 )
 
 // IsDone returns true if the given context has been canceled, or false otherwise
@@ -20,19 +25,46 @@ func IsDone(ctx context.Context) bool {
 	}
 }
 
-// DatumID computes the id for a datum, this value is used in ListDatum and
-// InspectDatum.
-func DatumID(inputs []*Input) string {
+// WorkNamespace returns the namespace of the task queue that the workers of
+// version 'version' of the pipeline 'pipeline' share.
+func WorkNamespace(pipeline string, version uint64) string {
+	return fmt.Sprintf("/pipeline-%s/v%d", pipeline, version)
+}
+
+// DatumID computes the id for a datum, this value is used in ListDatum,
+// InspectDatum, ResolveDatum and in logs. The ID is derived only from the
+// datum's inputs (their names, paths and content hashes, in the order in which
+// they appear in the pipeline's input) and the pipeline's salt, so a datum with
+// identical inputs has the same ID in every job run by the same version of a
+// pipeline, whether or not the job has finished.
+func DatumID(pipelineSalt string, inputs []*Input) string {
 	hash := sha256.New()
 	for _, input := range inputs {
+		hash.Write([]byte(input.Name))
 		hash.Write([]byte(input.FileInfo.File.Path))
 		hash.Write(input.FileInfo.Hash)
+		// A datum that moves to another split is mounted at a different path
+		if input.Split != "" {
+			hash.Write([]byte(input.Split))
+		}
 	}
-	// InputFileID is a single string id for the data from this input, it's used in logs and in
-	// the statsTree
+	hash.Write([]byte(pipelineSalt))
 	return hex.EncodeToString(hash.Sum(nil))
 }
 
+// DatumKey returns the key that orders a datum's writes to output files that
+// are merged with a sorted or last-writer-wins strategy: the names and paths
+// of its inputs, in the order in which they appear in the pipeline's input
+// (e.g. "images:/a.png,labels:/a.json"). Unlike DatumID, it doesn't depend on
+// the inputs' content, so datums are ordered by the files they read.
+func DatumKey(inputs []*Input) string {
+	var parts []string
+	for _, input := range inputs {
+		parts = append(parts, input.Name+":"+input.FileInfo.File.Path)
+	}
+	return strings.Join(parts, ",")
+}
+
 // HashDatum computes and returns the hash of datum + pipeline, with a
 // pipeline-specific prefix.
 func HashDatum(pipelineName string, pipelineSalt string, inputs []*Input) string {
@@ -41,6 +73,10 @@ func HashDatum(pipelineName string, pipelineSalt string, inputs []*Input) string
 		hash.Write([]byte(input.Name))
 		hash.Write([]byte(input.FileInfo.File.Path))
 		hash.Write(input.FileInfo.Hash)
+		// A datum that moves to another split is mounted at a different path
+		if input.Split != "" {
+			hash.Write([]byte(input.Split))
+		}
 	}
 
 	hash.Write([]byte(pipelineName))
@@ -70,3 +106,22 @@ dataFilters:
 	}
 	return matchesData
 }
+
+// FailureCause classifies an error returned while processing a datum (e.g. by
+// the driver's RunUserCode).
+func FailureCause(err error) pps.FailureCause {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return pps.FailureCause_FAILURE_TIMEOUT
+	}
+	exiterr := &exec.ExitError{}
+	if errors.As(err, &exiterr) {
+		// The kernel's OOM killer kills processes with SIGKILL. User code that
+		// the worker kills (on timeout or cancellation) is reported as a context
+		// error instead.
+		if status, ok := exiterr.Sys().(syscall.WaitStatus); ok && status.Signaled() && status.Signal() == syscall.SIGKILL {
+			return pps.FailureCause_FAILURE_OOM_KILLED
+		}
+		return pps.FailureCause_FAILURE_USER_CODE
+	}
+	return pps.FailureCause_FAILURE_OTHER
+}
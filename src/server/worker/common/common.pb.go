@@ -31,13 +31,20 @@ type Input struct {
 	JoinOn               string        `protobuf:"bytes,8,opt,name=join_on,json=joinOn,proto3" json:"join_on,omitempty"`
 	GroupBy              string        `protobuf:"bytes,10,opt,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
 	Lazy                 bool          `protobuf:"varint,3,opt,name=lazy,proto3" json:"lazy,omitempty"`
+	SkipLazyVerification bool          `protobuf:"varint,12,opt,name=skip_lazy_verification,json=skipLazyVerification,proto3" json:"skip_lazy_verification,omitempty"`
 	Branch               string        `protobuf:"bytes,4,opt,name=branch,proto3" json:"branch,omitempty"`
 	GitURL               string        `protobuf:"bytes,6,opt,name=git_url,json=gitUrl,proto3" json:"git_url,omitempty"`
 	EmptyFiles           bool          `protobuf:"varint,7,opt,name=empty_files,json=emptyFiles,proto3" json:"empty_files,omitempty"`
 	S3                   bool          `protobuf:"varint,9,opt,name=s3,proto3" json:"s3,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+	// If set, the input is a volume that's mounted at this path, and workers
+	// won't create an input directory for it
+	VolumePath string `protobuf:"bytes,11,opt,name=volume_path,json=volumePath,proto3" json:"volume_path,omitempty"`
+	// If set, the input's files are under this directory of the input
+	// directory (see pps.SplitInput)
+	Split                string   `protobuf:"bytes,13,opt,name=split,proto3" json:"split,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Input) Reset()         { *m = Input{} }
@@ -115,6 +122,13 @@ func (m *Input) GetLazy() bool {
 	return false
 }
 
+func (m *Input) GetSkipLazyVerification() bool {
+	if m != nil {
+		return m.SkipLazyVerification
+	}
+	return false
+}
+
 func (m *Input) GetBranch() string {
 	if m != nil {
 		return m.Branch
@@ -143,6 +157,20 @@ func (m *Input) GetS3() bool {
 	return false
 }
 
+func (m *Input) GetVolumePath() string {
+	if m != nil {
+		return m.VolumePath
+	}
+	return ""
+}
+
+func (m *Input) GetSplit() string {
+	if m != nil {
+		return m.Split
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Input)(nil), "common.Input")
 }
@@ -150,29 +178,33 @@ func init() {
 func init() { proto.RegisterFile("server/worker/common/common.proto", fileDescriptor_91fb6c79ddd9db74) }
 
 var fileDescriptor_91fb6c79ddd9db74 = []byte{
-	// 352 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xb1, 0x4e, 0xeb, 0x30,
-	0x14, 0x86, 0x95, 0xdc, 0x36, 0x49, 0x4f, 0x6f, 0xef, 0x60, 0x55, 0x17, 0xd3, 0xa1, 0x2d, 0xb0,
-	0x54, 0x0c, 0x0d, 0xa2, 0x03, 0x7b, 0x11, 0xa0, 0x4a, 0x48, 0x48, 0x91, 0xba, 0xb0, 0x44, 0x49,
-	0x70, 0x52, 0x43, 0x62, 0x47, 0xb6, 0x03, 0x0a, 0x2f, 0xc5, 0x6b, 0x30, 0xf2, 0x04, 0x08, 0xe5,
-	0x49, 0x90, 0xed, 0x0e, 0x0c, 0x0c, 0x96, 0xbf, 0xff, 0x3f, 0xc7, 0xbf, 0x75, 0x6c, 0x38, 0x92,
-	0x44, 0x3c, 0x13, 0x11, 0xbe, 0x70, 0xf1, 0x44, 0x44, 0x98, 0xf1, 0xaa, 0xe2, 0x6c, 0xbf, 0x2d,
-	0x6b, 0xc1, 0x15, 0x47, 0x9e, 0x55, 0x93, 0x71, 0x56, 0x52, 0xc2, 0x54, 0x58, 0xe7, 0x52, 0x2f,
-	0x5b, 0x9d, 0x8c, 0x0b, 0x5e, 0x70, 0x83, 0xa1, 0x26, 0xeb, 0x1e, 0xbf, 0xb9, 0xd0, 0xdf, 0xb0,
-	0xba, 0x51, 0xe8, 0x14, 0x06, 0x39, 0x2d, 0x49, 0x4c, 0x59, 0xce, 0xb1, 0x33, 0x77, 0x16, 0xc3,
-	0xf3, 0xd1, 0x52, 0x1f, 0xbf, 0xa6, 0x25, 0xd9, 0xb0, 0x9c, 0x47, 0x41, 0xbe, 0x27, 0x74, 0x06,
-	0xa3, 0x3a, 0x11, 0x84, 0xa9, 0x58, 0x5f, 0x49, 0x15, 0xee, 0x9b, 0xfe, 0xa1, 0xe9, 0xbf, 0x34,
-	0x56, 0xf4, 0xd7, 0x76, 0x58, 0x85, 0x10, 0xf4, 0x58, 0x52, 0x11, 0xec, 0xce, 0x9d, 0xc5, 0x20,
-	0x32, 0x8c, 0x0e, 0xc0, 0x7f, 0xe4, 0x94, 0xc5, 0x9c, 0xe1, 0xc0, 0xd8, 0x9e, 0x96, 0x77, 0x0c,
-	0x1d, 0x42, 0x50, 0x08, 0xde, 0xd4, 0x71, 0xda, 0x62, 0x30, 0x15, 0xdf, 0xe8, 0x75, 0xab, 0x73,
-	0xca, 0xe4, 0xb5, 0xc5, 0x7f, 0xe6, 0xce, 0x22, 0x88, 0x0c, 0xa3, 0xff, 0xe0, 0xa5, 0x22, 0x61,
-	0xd9, 0x0e, 0xf7, 0x6c, 0x8c, 0x55, 0xe8, 0x04, 0xfc, 0x82, 0xaa, 0xb8, 0x11, 0x25, 0xf6, 0x74,
-	0x61, 0x0d, 0xdd, 0xe7, 0xcc, 0xbb, 0xa1, 0x6a, 0x1b, 0xdd, 0x46, 0x5e, 0x41, 0xd5, 0x56, 0x94,
-	0x68, 0x06, 0x43, 0x52, 0xd5, 0xaa, 0x8d, 0xf5, 0x70, 0x12, 0xfb, 0x26, 0x17, 0x8c, 0xa5, 0x07,
-	0x97, 0xe8, 0x1f, 0xb8, 0x72, 0x85, 0x07, 0xc6, 0x77, 0xe5, 0x6a, 0x7d, 0xf5, 0xde, 0x4d, 0x9d,
-	0x8f, 0x6e, 0xea, 0x7c, 0x75, 0x53, 0xe7, 0xfe, 0xa2, 0xa0, 0x6a, 0xd7, 0xa4, 0xcb, 0x8c, 0x57,
-	0x61, 0x9d, 0x64, 0xbb, 0xf6, 0x81, 0x88, 0x9f, 0x24, 0x45, 0x16, 0xfe, 0xf6, 0x73, 0xa9, 0x67,
-	0xde, 0x7f, 0xf5, 0x1d, 0x00, 0x00, 0xff, 0xff, 0xe7, 0x9e, 0xf6, 0x08, 0xd8, 0x01, 0x00, 0x00,
+	// 408 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x52, 0xcf, 0x6e, 0xd3, 0x30,
+	0x18, 0x57, 0xba, 0x35, 0x6d, 0xbf, 0xae, 0x1c, 0xac, 0x6a, 0x98, 0x1d, 0xba, 0x02, 0x97, 0x8a,
+	0x43, 0x83, 0x28, 0x12, 0xf7, 0x22, 0x40, 0x93, 0x26, 0x81, 0x22, 0x8d, 0x03, 0x17, 0xcb, 0x0d,
+	0x4e, 0x62, 0x96, 0xd8, 0x96, 0xed, 0x14, 0x65, 0xcf, 0xc2, 0x03, 0x71, 0xe4, 0x09, 0x10, 0xca,
+	0x93, 0x20, 0x7f, 0xde, 0x61, 0x87, 0x1d, 0xa2, 0xfc, 0xfe, 0x7d, 0xfe, 0x49, 0x9f, 0x0d, 0xcf,
+	0x9d, 0xb0, 0x47, 0x61, 0xb3, 0x9f, 0xda, 0xde, 0x0a, 0x9b, 0x15, 0xba, 0x6d, 0xb5, 0xba, 0xff,
+	0x6d, 0x8d, 0xd5, 0x5e, 0x93, 0x34, 0xb2, 0x8b, 0x65, 0xd1, 0x48, 0xa1, 0x7c, 0x66, 0x4a, 0x17,
+	0xbe, 0xe8, 0x5e, 0x2c, 0x2b, 0x5d, 0x69, 0x84, 0x59, 0x40, 0x51, 0x7d, 0xf1, 0xeb, 0x04, 0xc6,
+	0x57, 0xca, 0x74, 0x9e, 0xbc, 0x82, 0x59, 0x29, 0x1b, 0xc1, 0xa4, 0x2a, 0x35, 0x4d, 0xd6, 0xc9,
+	0x66, 0xfe, 0x66, 0xb1, 0x0d, 0xe3, 0x1f, 0x65, 0x23, 0xae, 0x54, 0xa9, 0xf3, 0x69, 0x79, 0x8f,
+	0xc8, 0x6b, 0x58, 0x18, 0x6e, 0x85, 0xf2, 0x2c, 0x54, 0x4a, 0x4f, 0xc7, 0x98, 0x9f, 0x63, 0xfe,
+	0x3d, 0x4a, 0xf9, 0x59, 0x4c, 0x44, 0x46, 0x08, 0x9c, 0x2a, 0xde, 0x0a, 0x3a, 0x5a, 0x27, 0x9b,
+	0x59, 0x8e, 0x98, 0x3c, 0x85, 0xc9, 0x0f, 0x2d, 0x15, 0xd3, 0x8a, 0x4e, 0x51, 0x4e, 0x03, 0xfd,
+	0xac, 0xc8, 0x33, 0x98, 0x56, 0x56, 0x77, 0x86, 0x1d, 0x7a, 0x0a, 0xe8, 0x4c, 0x90, 0xef, 0xfb,
+	0x70, 0x4e, 0xc3, 0xef, 0x7a, 0x7a, 0xb2, 0x4e, 0x36, 0xd3, 0x1c, 0x31, 0x79, 0x0b, 0xe7, 0xee,
+	0x56, 0x1a, 0x16, 0x08, 0x3b, 0x0a, 0x2b, 0x4b, 0x59, 0x70, 0x2f, 0xb5, 0xa2, 0x67, 0x98, 0x5a,
+	0x06, 0xf7, 0x9a, 0xdf, 0xf5, 0x5f, 0x1f, 0x78, 0xe4, 0x1c, 0xd2, 0x83, 0xe5, 0xaa, 0xa8, 0xe9,
+	0x69, 0x2c, 0x8f, 0x8c, 0xbc, 0x84, 0x49, 0x25, 0x3d, 0xeb, 0x6c, 0x43, 0xd3, 0x60, 0xec, 0x61,
+	0xf8, 0x7b, 0x99, 0x7e, 0x92, 0xfe, 0x26, 0xbf, 0xce, 0xd3, 0x4a, 0xfa, 0x1b, 0xdb, 0x90, 0x4b,
+	0x98, 0x8b, 0xd6, 0xf8, 0x9e, 0x85, 0x95, 0x38, 0x3a, 0xc1, 0x1e, 0x40, 0x29, 0xac, 0xcb, 0x91,
+	0x27, 0x30, 0x72, 0x3b, 0x3a, 0x43, 0x7d, 0xe4, 0x76, 0x61, 0xe0, 0xa8, 0x9b, 0xae, 0x15, 0xcc,
+	0x70, 0x5f, 0xd3, 0x39, 0x56, 0x42, 0x94, 0xbe, 0x70, 0x5f, 0x93, 0x25, 0x8c, 0x9d, 0x69, 0xa4,
+	0xa7, 0x0b, 0xb4, 0x22, 0xd9, 0x7f, 0xf8, 0x3d, 0xac, 0x92, 0x3f, 0xc3, 0x2a, 0xf9, 0x37, 0xac,
+	0x92, 0x6f, 0xef, 0x2a, 0xe9, 0xeb, 0xee, 0xb0, 0x2d, 0x74, 0x9b, 0x19, 0x5e, 0xd4, 0xfd, 0x77,
+	0x61, 0x1f, 0x22, 0x67, 0x8b, 0xec, 0xb1, 0x67, 0x72, 0x48, 0xf1, 0xb2, 0x77, 0xff, 0x07, 0x00,
+	0x4b, 0x67, 0x7e, 0x4c, 0x45, 0x02, 0x00, 0x00,
 }
 
 func (m *Input) Marshal() (dAtA []byte, err error) {
@@ -199,6 +231,30 @@ func (m *Input) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Split) > 0 {
+		i -= len(m.Split)
+		copy(dAtA[i:], m.Split)
+		i = encodeVarintCommon(dAtA, i, uint64(len(m.Split)))
+		i--
+		dAtA[i] = 0x6a
+	}
+	if m.SkipLazyVerification {
+		i--
+		if m.SkipLazyVerification {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x60
+	}
+	if len(m.VolumePath) > 0 {
+		i -= len(m.VolumePath)
+		copy(dAtA[i:], m.VolumePath)
+		i = encodeVarintCommon(dAtA, i, uint64(len(m.VolumePath)))
+		i--
+		dAtA[i] = 0x5a
+	}
 	if len(m.GroupBy) > 0 {
 		i -= len(m.GroupBy)
 		copy(dAtA[i:], m.GroupBy)
@@ -345,6 +401,17 @@ func (m *Input) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovCommon(uint64(l))
 	}
+	l = len(m.VolumePath)
+	if l > 0 {
+		n += 1 + l + sovCommon(uint64(l))
+	}
+	if m.SkipLazyVerification {
+		n += 2
+	}
+	l = len(m.Split)
+	if l > 0 {
+		n += 1 + l + sovCommon(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -678,6 +745,90 @@ func (m *Input) Unmarshal(dAtA []byte) error {
 			}
 			m.GroupBy = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field VolumePath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCommon
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCommon
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.VolumePath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SkipLazyVerification", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.SkipLazyVerification = bool(v != 0)
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Split", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCommon
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCommon
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Split = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipCommon(dAtA[iNdEx:])
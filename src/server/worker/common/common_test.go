@@ -0,0 +1,54 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/exec"
+)
+
+func testInput(name, path, hash string) *Input {
+	return &Input{
+		Name: name,
+		FileInfo: &pfs.FileInfo{
+			File: &pfs.File{Path: path},
+			Hash: []byte(hash),
+		},
+	}
+}
+
+func TestDatumIDIsStable(t *testing.T) {
+	inputs := []*Input{testInput("a", "/foo", "h1"), testInput("b", "/bar", "h2")}
+	// Identical inputs (e.g. from a different job) get the same ID
+	same := []*Input{testInput("a", "/foo", "h1"), testInput("b", "/bar", "h2")}
+	require.Equal(t, DatumID("salt", inputs), DatumID("salt", same))
+
+	// Changing the content of an input, the input's name or the pipeline's
+	// salt changes the ID
+	require.NotEqual(t, DatumID("salt", inputs), DatumID("salt2", inputs))
+	changed := []*Input{testInput("a", "/foo", "h3"), testInput("b", "/bar", "h2")}
+	require.NotEqual(t, DatumID("salt", inputs), DatumID("salt", changed))
+	renamed := []*Input{testInput("c", "/foo", "h1"), testInput("b", "/bar", "h2")}
+	require.NotEqual(t, DatumID("salt", inputs), DatumID("salt", renamed))
+}
+
+func TestDatumKey(t *testing.T) {
+	inputs := []*Input{testInput("a", "/foo", "h1"), testInput("b", "/bar", "h2")}
+	require.Equal(t, "a:/foo,b:/bar", DatumKey(inputs))
+	// The key doesn't depend on the inputs' content
+	changed := []*Input{testInput("a", "/foo", "h3"), testInput("b", "/bar", "h2")}
+	require.Equal(t, DatumKey(inputs), DatumKey(changed))
+}
+
+func TestFailureCause(t *testing.T) {
+	err := exec.Command("sh", "-c", "exit 1").Run()
+	require.Equal(t, pps.FailureCause_FAILURE_USER_CODE, FailureCause(errors.Wrapf(err, "error running user code")))
+	err = exec.Command("sh", "-c", "kill -9 $$").Run()
+	require.Equal(t, pps.FailureCause_FAILURE_OOM_KILLED, FailureCause(err))
+	require.Equal(t, pps.FailureCause_FAILURE_TIMEOUT, FailureCause(errors.Wrapf(context.DeadlineExceeded, "datum timed out")))
+	require.Equal(t, pps.FailureCause_FAILURE_OTHER, FailureCause(errors.New("could not download input")))
+}
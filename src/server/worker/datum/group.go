@@ -0,0 +1,176 @@
+package datum
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/worker/common"
+)
+
+// ValidateGroupFiles checks that 'group' sets exactly one of regex and
+// metadata_key, and that its regex compiles.
+func ValidateGroupFiles(group *pps.GroupFiles) error {
+	switch {
+	case group.Regex == "" && group.MetadataKey == "":
+		return errors.New("group_files must set either regex or metadata_key")
+	case group.Regex != "" && group.MetadataKey != "":
+		return errors.New("group_files can't set both regex and metadata_key")
+	case group.MetadataKey != "" && group.Manifest == "":
+		return errors.New("group_files must set manifest to group by metadata_key")
+	case group.Regex != "" && group.Manifest != "":
+		return errors.New("group_files only reads a manifest to group by metadata_key")
+	}
+	if group.Regex != "" {
+		if _, err := regexp.Compile(group.Regex); err != nil {
+			return errors.Wrapf(err, "invalid group_files regex")
+		}
+	}
+	return nil
+}
+
+// fileGrouper assigns the files of a PFS input to the groups of its
+// pps.GroupFiles
+type fileGrouper struct {
+	group    *pps.GroupFiles
+	regex    *regexp.Regexp
+	metadata map[string]string // file path -> metadata_key's value
+}
+
+// newFileGrouper returns the fileGrouper of 'input', reading its manifest (if
+// it has one) from its commit.
+func newFileGrouper(pachClient *client.APIClient, input *pps.PFSInput) (*fileGrouper, error) {
+	g := &fileGrouper{group: input.GroupFiles}
+	if input.GroupFiles.Regex != "" {
+		var err error
+		if g.regex, err = regexp.Compile(input.GroupFiles.Regex); err != nil {
+			return nil, errors.EnsureStack(err)
+		}
+		return g, nil
+	}
+	var buf bytes.Buffer
+	if err := pachClient.GetFile(input.Repo, input.Commit, input.GroupFiles.Manifest, 0, 0, &buf); err != nil {
+		return nil, errors.Wrapf(err, "could not read the group_files manifest of input %q", input.Name)
+	}
+	var err error
+	if g.metadata, err = parseGroupManifest(input.GroupFiles.MetadataKey, &buf); err != nil {
+		return nil, errors.Wrapf(err, "invalid group_files manifest %s of input %q", input.GroupFiles.Manifest, input.Name)
+	}
+	return g, nil
+}
+
+// parseGroupManifest returns each file's value for 'key' in the manifest
+// read from 'r'. Files with no value for 'key' are left out.
+func parseGroupManifest(key string, r io.Reader) (map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("manifest is empty")
+	} else if err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	column := -1
+	for i, name := range header {
+		if i > 0 && strings.TrimSpace(name) == key {
+			column = i
+		}
+	}
+	if column < 0 {
+		return nil, errors.Errorf("manifest has no column %q", key)
+	}
+	result := make(map[string]string)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, errors.EnsureStack(err)
+		}
+		if value := strings.TrimSpace(record[column]); value != "" {
+			result[cleanPath(record[0])] = value
+		}
+	}
+}
+
+// key returns the group of the file at 'path', or "" if it has none. 'ok' is
+// false if the file is the manifest, and so isn't part of any datum.
+func (g *fileGrouper) key(path string) (key string, ok bool) {
+	if g.regex != nil {
+		match := g.regex.FindStringSubmatch(path)
+		switch {
+		case match == nil:
+			return "", true
+		case len(match) == 1:
+			return match[0], true
+		}
+		return strings.Join(match[1:], "\x00"), true
+	}
+	if cleanPath(path) == cleanPath(g.group.Manifest) {
+		return "", false
+	}
+	return g.metadata[cleanPath(path)], true
+}
+
+// groupFiles returns the datums of a PFS input whose files are 'inputs',
+// grouped by 'g'. Datums are ordered by their group (files with no group are
+// ordered by path), and the files of each datum by path, so that a group's
+// datum always has the same hash.
+func groupFiles(g *fileGrouper, inputs []*common.Input) [][]*common.Input {
+	groups := make(map[string][]*common.Input)
+	var keys []string
+	for _, in := range inputs {
+		key, ok := g.key(in.FileInfo.File.Path)
+		if !ok {
+			continue
+		}
+		// Prefix keys so that a file with no group can't collide with a
+		// group that happens to be named after its path.
+		if key == "" {
+			key = "\x01" + in.FileInfo.File.Path
+		} else {
+			key = "\x00" + key
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], in)
+	}
+	sort.Strings(keys)
+	result := make([][]*common.Input, 0, len(keys))
+	for _, key := range keys {
+		datum := groups[key]
+		sort.Slice(datum, func(i, j int) bool {
+			return datum[i].FileInfo.File.Path < datum[j].FileInfo.File.Path
+		})
+		result = append(result, datum)
+	}
+	return result
+}
+
+// newGroupedPFSIterator returns an iterator over the datums of 'input', whose
+// files are grouped by its GroupFiles.
+func newGroupedPFSIterator(pachClient *client.APIClient, input *pps.PFSInput, listings *ListingCache) (Iterator, error) {
+	result := &groupIterator{}
+	defer result.Reset()
+	if input.Commit == "" {
+		return result, nil
+	}
+	files, err := newPFSIterator(pachClient, input, listings)
+	if err != nil {
+		return nil, err
+	}
+	g, err := newFileGrouper(pachClient, input)
+	if err != nil {
+		return nil, err
+	}
+	result.datums = groupFiles(g, files.(*pfsIterator).inputs)
+	return result, nil
+}
@@ -0,0 +1,82 @@
+package datum
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/worker/common"
+)
+
+func fileInputs(paths ...string) []*common.Input {
+	var result []*common.Input
+	for _, p := range paths {
+		result = append(result, &common.Input{
+			Name:     "in",
+			FileInfo: &pfs.FileInfo{File: client.NewFile("in", "master", p)},
+		})
+	}
+	return result
+}
+
+func datumPaths(datums [][]*common.Input) [][]string {
+	var result [][]string
+	for _, datum := range datums {
+		var paths []string
+		for _, in := range datum {
+			paths = append(paths, in.FileInfo.File.Path)
+		}
+		result = append(result, paths)
+	}
+	return result
+}
+
+func TestValidateGroupFiles(t *testing.T) {
+	require.NoError(t, ValidateGroupFiles(&pps.GroupFiles{Regex: `session-(\d+)`}))
+	require.NoError(t, ValidateGroupFiles(&pps.GroupFiles{MetadataKey: "session", Manifest: "/meta.csv"}))
+	require.YesError(t, ValidateGroupFiles(&pps.GroupFiles{}))
+	require.YesError(t, ValidateGroupFiles(&pps.GroupFiles{Regex: "(", MetadataKey: "session"}))
+	require.YesError(t, ValidateGroupFiles(&pps.GroupFiles{Regex: "("}))
+	require.YesError(t, ValidateGroupFiles(&pps.GroupFiles{MetadataKey: "session"}))
+	require.YesError(t, ValidateGroupFiles(&pps.GroupFiles{Regex: "a", Manifest: "/meta.csv"}))
+}
+
+func TestGroupFilesByRegex(t *testing.T) {
+	g := &fileGrouper{
+		group: &pps.GroupFiles{Regex: `session-(\d+)-shard`},
+		regex: regexp.MustCompile(`session-(\d+)-shard`),
+	}
+	datums := groupFiles(g, fileInputs(
+		"/session-2-shard-1",
+		"/session-1-shard-2",
+		"/readme",
+		"/session-1-shard-1",
+		"/session-2-shard-0",
+	))
+	require.Equal(t, [][]string{
+		{"/session-1-shard-1", "/session-1-shard-2"},
+		{"/session-2-shard-0", "/session-2-shard-1"},
+		{"/readme"},
+	}, datumPaths(datums))
+}
+
+func TestGroupFilesByMetadata(t *testing.T) {
+	metadata, err := parseGroupManifest("session", strings.NewReader(
+		"path, session, size\n/a,s1,1\nb, s2,2\n/c,s1,3\n/d,,4\n"))
+	require.NoError(t, err)
+	g := &fileGrouper{
+		group:    &pps.GroupFiles{MetadataKey: "session", Manifest: "/meta.csv"},
+		metadata: metadata,
+	}
+	datums := groupFiles(g, fileInputs("/a", "/b", "/c", "/d", "/e", "/meta.csv"))
+	require.Equal(t, [][]string{{"/a", "/c"}, {"/b"}, {"/d"}, {"/e"}}, datumPaths(datums))
+
+	_, err = parseGroupManifest("session", strings.NewReader("path,size\n/a,1\n"))
+	require.YesError(t, err)
+	_, err = parseGroupManifest("session", strings.NewReader(""))
+	require.YesError(t, err)
+}
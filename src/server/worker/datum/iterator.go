@@ -32,7 +32,7 @@ type pfsIterator struct {
 	location int
 }
 
-func newPFSIterator(pachClient *client.APIClient, input *pps.PFSInput) (Iterator, error) {
+func newPFSIterator(pachClient *client.APIClient, input *pps.PFSInput, listings *ListingCache) (Iterator, error) {
 	result := &pfsIterator{}
 	// make sure it gets initialized properly (location = -1)
 	result.Reset()
@@ -41,37 +41,17 @@ func newPFSIterator(pachClient *client.APIClient, input *pps.PFSInput) (Iterator
 		// before all commits have inputs
 		return result, nil
 	}
-	fs, err := pachClient.GlobFileStream(pachClient.Ctx(), &pfs.GlobFileRequest{
-		Commit:  client.NewCommit(input.Repo, input.Commit),
-		Pattern: input.Glob,
-	})
+	inputs, ok, err := listings.reuse(pachClient, input)
 	if err != nil {
 		return nil, err
 	}
-	for {
-		fileInfo, err := fs.Recv()
-		if errors.Is(err, io.EOF) {
-			break
-		} else if err != nil {
+	if !ok {
+		if inputs, err = listPFSInput(pachClient, input); err != nil {
 			return nil, err
 		}
-		g, err := glob.Compile(input.Glob, '/')
-		if err != nil {
-			return nil, err
-		}
-		joinOn := g.Replace(fileInfo.File.Path, input.JoinOn)
-		groupBy := g.Replace(fileInfo.File.Path, input.GroupBy)
-		result.inputs = append(result.inputs, &common.Input{
-			FileInfo:   fileInfo,
-			JoinOn:     joinOn,
-			GroupBy:    groupBy,
-			Name:       input.Name,
-			Lazy:       input.Lazy,
-			Branch:     input.Branch,
-			EmptyFiles: input.EmptyFiles,
-			S3:         input.S3,
-		})
 	}
+	listings.put(input, inputs)
+	result.inputs = inputs
 	// We sort the inputs so that the order is deterministic. Note that it's
 	// not possible for 2 inputs to have the same path so this is guaranteed to
 	// produce a deterministic order.
@@ -86,6 +66,71 @@ func newPFSIterator(pachClient *client.APIClient, input *pps.PFSInput) (Iterator
 	return result, nil
 }
 
+// listPFSInput lists the datums of 'input' in its commit.
+func listPFSInput(pachClient *client.APIClient, input *pps.PFSInput) ([]*common.Input, error) {
+	inPartition := func(string) bool { return true }
+	if input.Partition != nil {
+		var err error
+		if inPartition, err = partitionFilter(pachClient, input); err != nil {
+			return nil, err
+		}
+	}
+	var split *splitter
+	if input.Split != nil {
+		var err error
+		if split, err = newSplitter(pachClient, input); err != nil {
+			return nil, err
+		}
+	}
+	g, err := glob.Compile(input.Glob, '/')
+	if err != nil {
+		return nil, err
+	}
+	fs, err := pachClient.GlobFileStream(pachClient.Ctx(), &pfs.GlobFileRequest{
+		Commit:  client.NewCommit(input.Repo, input.Commit),
+		Pattern: input.Glob,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var inputs []*common.Input
+	for {
+		fileInfo, err := fs.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if !inPartition(fileInfo.File.Path) {
+			continue
+		}
+		in := newPFSInput(input, g, fileInfo)
+		if split != nil {
+			if in.Split = split.assign(fileInfo.File.Path); in.Split == "" {
+				continue
+			}
+		}
+		inputs = append(inputs, in)
+	}
+	return inputs, nil
+}
+
+// newPFSInput returns the datum of 'input' for the file 'fileInfo', which
+// matches the glob pattern 'g'.
+func newPFSInput(input *pps.PFSInput, g *glob.Glob, fileInfo *pfs.FileInfo) *common.Input {
+	return &common.Input{
+		FileInfo:             fileInfo,
+		JoinOn:               g.Replace(fileInfo.File.Path, input.JoinOn),
+		GroupBy:              g.Replace(fileInfo.File.Path, input.GroupBy),
+		Name:                 input.Name,
+		Lazy:                 input.Lazy,
+		SkipLazyVerification: input.SkipLazyVerification,
+		Branch:               input.Branch,
+		EmptyFiles:           input.EmptyFiles,
+		S3:                   input.S3,
+	}
+}
+
 func (d *pfsIterator) Reset() {
 	d.location = -1
 }
@@ -151,11 +196,11 @@ type unionIterator struct {
 	location  int
 }
 
-func newUnionIterator(pachClient *client.APIClient, union []*pps.Input) (Iterator, error) {
+func newUnionIterator(pachClient *client.APIClient, union []*pps.Input, listings *ListingCache) (Iterator, error) {
 	result := &unionIterator{}
 	defer result.Reset()
 	for _, input := range union {
-		datumIterator, err := NewIterator(pachClient, input)
+		datumIterator, err := newIterator(pachClient, input, listings)
 		if err != nil {
 			return nil, err
 		}
@@ -212,11 +257,11 @@ type crossIterator struct {
 	location      int
 }
 
-func newCrossIterator(pachClient *client.APIClient, cross []*pps.Input) (Iterator, error) {
+func newCrossIterator(pachClient *client.APIClient, cross []*pps.Input, listings *ListingCache) (Iterator, error) {
 	result := &crossIterator{}
 	defer result.Reset() // Call Next() on all inner iterators once
 	for _, iterator := range cross {
-		datumIterator, err := NewIterator(pachClient, iterator)
+		datumIterator, err := newIterator(pachClient, iterator, listings)
 		if err != nil {
 			return nil, err
 		}
@@ -322,7 +367,7 @@ type groupIterator struct {
 	location int
 }
 
-func newGroupIterator(pachClient *client.APIClient, group []*pps.Input) (Iterator, error) {
+func newGroupIterator(pachClient *client.APIClient, group []*pps.Input, listings *ListingCache) (Iterator, error) {
 	groupMap := make(map[string][]*common.Input)
 	keys := make([]string, 0, len(group))
 	result := &groupIterator{}
@@ -331,7 +376,7 @@ func newGroupIterator(pachClient *client.APIClient, group []*pps.Input) (Iterato
 	// okay, so we have a slice of pps Inputs
 	for _, input := range group {
 		// turn our inputs into iterators
-		datumIterator, err := NewIterator(pachClient, input)
+		datumIterator, err := newIterator(pachClient, input, listings)
 		if err != nil {
 			return nil, err
 		}
@@ -388,12 +433,12 @@ type joinIterator struct {
 	location int
 }
 
-func newJoinIterator(pachClient *client.APIClient, join []*pps.Input) (Iterator, error) {
+func newJoinIterator(pachClient *client.APIClient, join []*pps.Input, listings *ListingCache) (Iterator, error) {
 	result := &joinIterator{}
 	om := ordered_map.NewOrderedMap()
 
 	for i, input := range join {
-		datumIterator, err := NewIterator(pachClient, input)
+		datumIterator, err := newIterator(pachClient, input, listings)
 		if err != nil {
 			return nil, err
 		}
@@ -520,26 +565,104 @@ func newCronIterator(pachClient *client.APIClient, input *pps.CronInput) (Iterat
 		Branch: "master",
 		Commit: input.Commit,
 		Glob:   "/*",
-	})
+	}, nil)
+}
+
+func newHTTPMirrorIterator(pachClient *client.APIClient, input *pps.HTTPMirrorInput) (Iterator, error) {
+	return newPFSIterator(pachClient, &pps.PFSInput{
+		Name:   input.Name,
+		Repo:   input.Repo,
+		Branch: "master",
+		Commit: input.Commit,
+		Glob:   "/*",
+	}, nil)
+}
+
+func newParameterIterator(pachClient *client.APIClient, input *pps.ParameterInput) (Iterator, error) {
+	return newPFSIterator(pachClient, &pps.PFSInput{
+		Name:   input.Name,
+		Repo:   input.Repo,
+		Branch: "master",
+		Commit: input.Commit,
+		Glob:   "/*",
+	}, nil)
+}
+
+func newRemoteRepoIterator(pachClient *client.APIClient, input *pps.RemoteRepoInput) (Iterator, error) {
+	return newPFSIterator(pachClient, &pps.PFSInput{
+		Name:   input.Name,
+		Repo:   input.Repo,
+		Branch: "master",
+		Commit: input.Commit,
+		Glob:   input.Glob,
+	}, nil)
+}
+
+func newDBImportIterator(pachClient *client.APIClient, input *pps.DBImportInput) (Iterator, error) {
+	return newPFSIterator(pachClient, &pps.PFSInput{
+		Name:   input.Name,
+		Repo:   input.Repo,
+		Branch: "master",
+		Commit: input.Commit,
+		Glob:   input.Glob,
+	}, nil)
+}
+
+// newVolumeIterator returns an iterator with a single datum, which stands for
+// the whole volume. Its hash is the volume's fingerprint, so that the datums
+// that it's crossed with are reprocessed when the volume's contents change.
+func newVolumeIterator(pachClient *client.APIClient, input *pps.VolumeInput) (Iterator, error) {
+	return newListIterator(pachClient, []*common.Input{{
+		FileInfo: &pfs.FileInfo{
+			File:     &pfs.File{Path: "/"},
+			FileType: pfs.FileType_DIR,
+			Hash:     []byte(input.Fingerprint),
+		},
+		Name:       input.Name,
+		VolumePath: input.MountPath,
+	}})
 }
 
 // NewIterator creates an Iterator for an input.
 func NewIterator(pachClient *client.APIClient, input *pps.Input) (Iterator, error) {
+	return newIterator(pachClient, input, nil)
+}
+
+// NewIteratorWithListings creates an Iterator for an input, reusing the
+// listings of its PFS inputs in 'listings' where their commits allow it, and
+// saving their new listings there.
+func NewIteratorWithListings(pachClient *client.APIClient, input *pps.Input, listings *ListingCache) (Iterator, error) {
+	return newIterator(pachClient, input, listings)
+}
+
+func newIterator(pachClient *client.APIClient, input *pps.Input, listings *ListingCache) (Iterator, error) {
 	switch {
+	case input.Pfs != nil && input.Pfs.GroupFiles != nil:
+		return newGroupedPFSIterator(pachClient, input.Pfs, listings)
 	case input.Pfs != nil:
-		return newPFSIterator(pachClient, input.Pfs)
+		return newPFSIterator(pachClient, input.Pfs, listings)
 	case input.Union != nil:
-		return newUnionIterator(pachClient, input.Union)
+		return newUnionIterator(pachClient, input.Union, listings)
 	case input.Cross != nil:
-		return newCrossIterator(pachClient, input.Cross)
+		return newCrossIterator(pachClient, input.Cross, listings)
 	case input.Join != nil:
-		return newJoinIterator(pachClient, input.Join)
+		return newJoinIterator(pachClient, input.Join, listings)
 	case input.Group != nil:
-		return newGroupIterator(pachClient, input.Group)
+		return newGroupIterator(pachClient, input.Group, listings)
 	case input.Cron != nil:
 		return newCronIterator(pachClient, input.Cron)
 	case input.Git != nil:
 		return newGitIterator(pachClient, input.Git)
+	case input.HTTPMirror != nil:
+		return newHTTPMirrorIterator(pachClient, input.HTTPMirror)
+	case input.Parameter != nil:
+		return newParameterIterator(pachClient, input.Parameter)
+	case input.RemoteRepo != nil:
+		return newRemoteRepoIterator(pachClient, input.RemoteRepo)
+	case input.Volume != nil:
+		return newVolumeIterator(pachClient, input.Volume)
+	case input.DBImport != nil:
+		return newDBImportIterator(pachClient, input.DBImport)
 	}
 	return nil, errors.Errorf("unrecognized input type: %v", input)
 }
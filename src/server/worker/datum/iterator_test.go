@@ -148,6 +148,34 @@ func TestIterators(t *testing.T) {
 			"/foo44/foo44")
 	})
 
+	// Joined inputs without join_on are matched with every datum of the other
+	// inputs, even if their globs have capture groups, unless they're joined
+	// on their capture groups
+	t.Run("JoinOnCaptureGroups", func(t *testing.T) {
+		joined := func(joinOnCaptureGroups bool) *pps.Input {
+			in1 := client.NewPFSInput(dataRepo, "/foo(?)1")
+			in1.Pfs.Commit = commit.ID
+			in2 := client.NewPFSInput(dataRepo, "/foo(?)2")
+			in2.Pfs.Commit = commit.ID
+			if joinOnCaptureGroups {
+				in1.Pfs.JoinOn = DefaultJoinOn(in1.Pfs.Glob)
+				in2.Pfs.JoinOn = DefaultJoinOn(in2.Pfs.Glob)
+			}
+			return client.NewJoinInput(in1, in2)
+		}
+		join1, err := NewIterator(c, joined(false))
+		require.NoError(t, err)
+		validateDI(t, join1,
+			"/foo11/foo12", "/foo21/foo12", "/foo31/foo12", "/foo41/foo12",
+			"/foo11/foo22", "/foo21/foo22", "/foo31/foo22", "/foo41/foo22",
+			"/foo11/foo32", "/foo21/foo32", "/foo31/foo32", "/foo41/foo32",
+			"/foo11/foo42", "/foo21/foo42", "/foo31/foo42", "/foo41/foo42",
+		)
+		join2, err := NewIterator(c, joined(true))
+		require.NoError(t, err)
+		validateDI(t, join2, "/foo11/foo12", "/foo21/foo22", "/foo31/foo32", "/foo41/foo42")
+	})
+
 	// in11 is an S3 input
 	in11 := client.NewS3PFSInput("", dataRepo, "")
 	in11.Pfs.Commit = commit.ID
@@ -394,7 +422,7 @@ func benchmarkIterators(j int, b *testing.B) {
 			in8.Pfs.Commit = commit.ID
 			in9 := client.NewPFSInputOpts("", dataRepo, "", "/foo(?)(?)*", "$2$1", "", false)
 			in9.Pfs.Commit = commit.ID
-			join1, err := newJoinIterator(c, []*pps.Input{in8, in9})
+			join1, err := newJoinIterator(c, []*pps.Input{in8, in9}, nil)
 			require.NoError(b, err)
 			validateDI(b, join1)
 		})
@@ -402,7 +430,7 @@ func benchmarkIterators(j int, b *testing.B) {
 		b.Run("group", func(b *testing.B) {
 			in10 := client.NewPFSInputOpts("", dataRepo, "", "/foo(?)(?)*", "", "$2", false)
 			in10.Pfs.Commit = commit.ID
-			group1, err := newGroupIterator(c, []*pps.Input{in10})
+			group1, err := newGroupIterator(c, []*pps.Input{in10}, nil)
 			require.NoError(b, err)
 			validateDI(b, group1)
 		})
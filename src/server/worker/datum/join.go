@@ -0,0 +1,50 @@
+package datum
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pachyderm/ohmyglob/syntax"
+	"github.com/pachyderm/ohmyglob/syntax/ast"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+)
+
+// CaptureGroups returns the number of capture groups in the glob pattern
+// 'glob' (nested groups included), which join_on and group_by may refer to
+// as $1, $2, etc.
+func CaptureGroups(glob string) (int, error) {
+	node, err := syntax.Parse(glob)
+	if err != nil {
+		return 0, errors.Wrapf(err, "could not parse glob %q", glob)
+	}
+	var count func(*ast.Node) int
+	count = func(n *ast.Node) int {
+		result := 0
+		if n.Kind == ast.KindCapture {
+			result++
+		}
+		for _, child := range n.Children {
+			result += count(child)
+		}
+		return result
+	}
+	return count(node), nil
+}
+
+// DefaultJoinOn returns the join_on of a joined PFS input whose glob is
+// 'glob', which sets join_on_capture_groups but not join_on: all of the
+// glob's capture groups, separated by slashes, so that files are joined when
+// all of their captures match. It returns "" if the glob has no capture
+// groups.
+func DefaultJoinOn(glob string) string {
+	n, err := CaptureGroups(glob)
+	if err != nil {
+		return "" // an invalid glob is reported by validateInput
+	}
+	var groups []string
+	for i := 1; i <= n; i++ {
+		groups = append(groups, fmt.Sprintf("$%d", i))
+	}
+	return strings.Join(groups, "/")
+}
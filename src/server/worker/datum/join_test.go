@@ -0,0 +1,24 @@
+package datum
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestCaptureGroups(t *testing.T) {
+	for glob, expected := range map[string]int{
+		"/*":               0,
+		"/(*)":             1,
+		"/{a,b}/(*)":       1,
+		"/*/(bar-(*))/*":   2,
+		"/(*)/*/(??)*.txt": 2,
+	} {
+		n, err := CaptureGroups(glob)
+		require.NoError(t, err)
+		require.Equal(t, expected, n, glob)
+	}
+	require.Equal(t, "", DefaultJoinOn("/*"))
+	require.Equal(t, "$1", DefaultJoinOn("/foo/(*)/bar"))
+	require.Equal(t, "$1/$2", DefaultJoinOn("/(*)/*/(??)*.txt"))
+}
@@ -0,0 +1,168 @@
+package datum
+
+import (
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+	glob "github.com/pachyderm/ohmyglob"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
+	"github.com/pachyderm/pachyderm/src/server/worker/common"
+)
+
+// ListingCache holds the most recent listing of each PFS input, so that when
+// the input's next commit summarizes the paths that changed since the listed
+// commit (see pfs.ChangedPaths), only the datums under those paths are listed
+// again. It is safe for concurrent use.
+type ListingCache struct {
+	mu       sync.Mutex
+	listings map[string]*listing
+}
+
+type listing struct {
+	commit string
+	inputs []*common.Input
+}
+
+// NewListingCache creates a new, empty ListingCache.
+func NewListingCache() *ListingCache {
+	return &ListingCache{listings: make(map[string]*listing)}
+}
+
+// listingKey identifies the listings of 'input' across commits
+func listingKey(input *pps.PFSInput) string {
+	key := proto.Clone(input).(*pps.PFSInput)
+	key.Commit = ""
+	return key.String()
+}
+
+func (c *ListingCache) put(input *pps.PFSInput, inputs []*common.Input) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listings[listingKey(input)] = &listing{commit: input.Commit, inputs: inputs}
+}
+
+func (c *ListingCache) get(input *pps.PFSInput) *listing {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.listings[listingKey(input)]
+}
+
+// reuse returns the datums of 'input', built from the cached listing of an
+// earlier commit in its repo and from the datums under the paths that
+// changed since. It returns false if the datums have to be listed, e.g.
+// because the commit doesn't summarize its changes relative to the listed
+// commit, or the input's glob pattern can't be matched against them.
+func (c *ListingCache) reuse(pachClient *client.APIClient, input *pps.PFSInput) ([]*common.Input, bool, error) {
+	prev := c.get(input)
+	if prev == nil || input.Partition != nil || input.Split.GetManifest() != "" {
+		// Partitions' windows move with each commit, and split manifests may
+		// change in any commit, so such inputs' listings can't be reused
+		return nil, false, nil
+	}
+	if prev.commit == input.Commit {
+		return copyInputs(input, prev.inputs, nil), true, nil
+	}
+	depth, ok := globDepth(input.Glob)
+	if !ok {
+		return nil, false, nil
+	}
+	commitInfo, err := pachClient.InspectCommit(input.Repo, input.Commit)
+	if err != nil {
+		return nil, false, err
+	}
+	changed := commitInfo.ChangedPaths
+	if changed == nil || changed.Base == nil || changed.Base.ID != prev.commit {
+		return nil, false, nil
+	}
+	g, err := glob.Compile(cleanPath(input.Glob), '/')
+	if err != nil {
+		return nil, false, err
+	}
+	// A datum may have changed if a changed path is in it. A change above
+	// the glob's depth may be in any number of datums, so it can't be pruned.
+	relist := make(map[string]bool)
+	for _, p := range changed.Paths {
+		parts := splitPath(p)
+		if len(parts) < depth {
+			return nil, false, nil
+		}
+		if datumPath := "/" + strings.Join(parts[:depth], "/"); g.Match(datumPath) {
+			relist[datumPath] = true
+		}
+	}
+	inputGlob, err := glob.Compile(input.Glob, '/')
+	if err != nil {
+		return nil, false, err
+	}
+	var split *splitter
+	if input.Split != nil {
+		if split, err = newSplitter(pachClient, input); err != nil {
+			return nil, false, err
+		}
+	}
+	result := copyInputs(input, prev.inputs, relist)
+	for p := range relist {
+		fileInfo, err := pachClient.InspectFile(input.Repo, input.Commit, p)
+		if err != nil {
+			if pfsserver.IsFileNotFoundErr(err) {
+				continue // the datum was deleted
+			}
+			return nil, false, err
+		}
+		in := newPFSInput(input, inputGlob, fileInfo)
+		if split != nil {
+			if in.Split = split.assign(fileInfo.File.Path); in.Split == "" {
+				continue
+			}
+		}
+		result = append(result, in)
+	}
+	return result, true, nil
+}
+
+// copyInputs copies the datums in 'inputs' (except those in 'skip') to the
+// commit of 'input', which has the same contents at their paths.
+func copyInputs(input *pps.PFSInput, inputs []*common.Input, skip map[string]bool) []*common.Input {
+	var result []*common.Input
+	for _, in := range inputs {
+		if skip[cleanPath(in.FileInfo.File.Path)] {
+			continue
+		}
+		in = proto.Clone(in).(*common.Input)
+		in.FileInfo.File.Commit = client.NewCommit(input.Repo, input.Commit)
+		result = append(result, in)
+	}
+	return result
+}
+
+// globDepth returns the number of path components that every path matching
+// 'pattern' has, or false if they don't all have the same number.
+func globDepth(pattern string) (int, bool) {
+	if strings.Contains(pattern, "**") || strings.ContainsAny(pattern, "{}") {
+		return 0, false
+	}
+	return len(splitPath(pattern)), true
+}
+
+func cleanPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(cleanPath(p), "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
@@ -0,0 +1,60 @@
+package datum
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/worker/common"
+)
+
+func TestGlobDepth(t *testing.T) {
+	for pattern, depth := range map[string]int{
+		"/":             0,
+		"/*":            1,
+		"*":             1,
+		"/region=*/":    1,
+		"/logs/*/*.csv": 3,
+	} {
+		d, ok := globDepth(pattern)
+		require.True(t, ok, pattern)
+		require.Equal(t, depth, d, pattern)
+	}
+	for _, pattern := range []string{"/**", "/{a,b/c}"} {
+		_, ok := globDepth(pattern)
+		require.False(t, ok, pattern)
+	}
+}
+
+func TestListingCache(t *testing.T) {
+	input := &pps.PFSInput{Name: "in", Repo: "in", Glob: "/region=*", Commit: "c1"}
+	fileInput := func(path string) *common.Input {
+		return &common.Input{FileInfo: &pfs.FileInfo{File: client.NewFile("in", "c1", path)}}
+	}
+	listings := NewListingCache()
+	listings.put(input, []*common.Input{fileInput("/region=EU"), fileInput("/region=US")})
+
+	// The listing of the same commit is reused without any requests
+	inputs, ok, err := listings.reuse(nil, input)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 2, len(inputs))
+
+	// Datums under changed paths are left out of the copy, which is moved to
+	// the new commit
+	inputs = copyInputs(&pps.PFSInput{Repo: "in", Commit: "c2"}, inputs, map[string]bool{"/region=EU": true})
+	require.Equal(t, 1, len(inputs))
+	require.Equal(t, "/region=US", inputs[0].FileInfo.File.Path)
+	require.Equal(t, "c2", inputs[0].FileInfo.File.Commit.ID)
+
+	// Other inputs, and partitioned inputs, don't reuse the listing
+	_, ok, err = listings.reuse(nil, &pps.PFSInput{Name: "in", Repo: "in", Glob: "/*", Commit: "c1"})
+	require.NoError(t, err)
+	require.False(t, ok)
+	var none *ListingCache
+	_, ok, err = none.reuse(nil, input)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
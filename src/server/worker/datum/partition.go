@@ -0,0 +1,189 @@
+package datum
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// partitionFields are the placeholders that a partition layout may contain,
+// in the order in which they're checked
+var partitionFields = []string{"YYYY", "MM", "DD", "HH"}
+
+// partitionLayout is a parsed pps.Partition layout
+type partitionLayout struct {
+	glob   string
+	re     *regexp.Regexp
+	fields []string // the placeholders in 're's capture groups, in order
+}
+
+func parsePartitionLayout(layout string) (*partitionLayout, error) {
+	if !strings.HasPrefix(layout, "/") {
+		layout = "/" + layout
+	}
+	if strings.ContainsAny(layout, `?[]{}\`) {
+		return nil, errors.Errorf("partition layout %q can't contain any of ?[]{}\\", layout)
+	}
+	result := &partitionLayout{}
+	var glob, re strings.Builder
+	re.WriteString("^")
+	seen := make(map[string]bool)
+	for rest := layout; rest != ""; {
+		field := ""
+		for _, f := range partitionFields {
+			if strings.HasPrefix(rest, f) {
+				field = f
+				break
+			}
+		}
+		switch {
+		case field != "":
+			if seen[field] {
+				return nil, errors.Errorf("partition layout %q contains %s more than once", layout, field)
+			}
+			seen[field] = true
+			result.fields = append(result.fields, field)
+			writeGlobStar(&glob)
+			re.WriteString("([0-9]{" + strconv.Itoa(len(field)) + "})")
+			rest = rest[len(field):]
+		case rest[0] == '*':
+			writeGlobStar(&glob)
+			re.WriteString("[^/]*")
+			rest = rest[1:]
+		default:
+			glob.WriteByte(rest[0])
+			re.WriteString(regexp.QuoteMeta(rest[:1]))
+			rest = rest[1:]
+		}
+	}
+	re.WriteString("$")
+	// Each placeholder needs the ones before it, so that a partition's path
+	// determines its time
+	for i, f := range partitionFields {
+		if i == 0 && !seen[f] {
+			return nil, errors.Errorf("partition layout %q must contain %s", layout, f)
+		}
+		if i > 0 && seen[f] && !seen[partitionFields[i-1]] {
+			return nil, errors.Errorf("partition layout %q contains %s but not %s", layout, f, partitionFields[i-1])
+		}
+	}
+	result.glob = glob.String()
+	result.re = regexp.MustCompile(re.String())
+	return result, nil
+}
+
+// writeGlobStar appends '*' to 'glob', unless it already ends in '*', as
+// adjacent placeholders (e.g. in "YYYYMMDD") would otherwise become "**",
+// which matches '/'
+func writeGlobStar(glob *strings.Builder) {
+	if !strings.HasSuffix(glob.String(), "*") {
+		glob.WriteString("*")
+	}
+}
+
+// time returns the start of the partition at 'path', or false if 'path'
+// isn't a partition (e.g. it matches the layout's glob but isn't a date)
+func (l *partitionLayout) time(path string) (time.Time, bool) {
+	match := l.re.FindStringSubmatch(path)
+	if match == nil {
+		return time.Time{}, false
+	}
+	values := map[string]int{"MM": 1, "DD": 1}
+	for i, field := range l.fields {
+		values[field], _ = strconv.Atoi(match[i+1])
+	}
+	t := time.Date(values["YYYY"], time.Month(values["MM"]), values["DD"], values["HH"], 0, 0, 0, time.UTC)
+	// time.Date normalizes out of range values (e.g. month 13), which aren't
+	// partitions
+	if t.Month() != time.Month(values["MM"]) || t.Day() != values["DD"] || t.Hour() != values["HH"] {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// PartitionGlob returns the glob pattern that matches the partitions of
+// 'partition'.
+func PartitionGlob(partition *pps.Partition) (string, error) {
+	layout, err := parsePartitionLayout(partition.Layout)
+	if err != nil {
+		return "", err
+	}
+	return layout.glob, nil
+}
+
+// ValidatePartition checks that 'partition' has a valid layout and window.
+func ValidatePartition(partition *pps.Partition) error {
+	if _, err := parsePartitionLayout(partition.Layout); err != nil {
+		return err
+	}
+	_, _, err := partitionWindow(partition, time.Now())
+	return err
+}
+
+// partitionWindow returns the start and end of the window of 'partition',
+// which ends at 'ref' (or the next multiple of the partition's align after
+// 'ref').
+func partitionWindow(partition *pps.Partition, ref time.Time) (time.Time, time.Time, error) {
+	if partition.Window == nil {
+		return time.Time{}, time.Time{}, errors.Errorf("partition must specify a window")
+	}
+	window, err := types.DurationFromProto(partition.Window)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Wrapf(err, "invalid partition window")
+	}
+	if window <= 0 {
+		return time.Time{}, time.Time{}, errors.Errorf("partition window must be positive")
+	}
+	end := ref.UTC()
+	if partition.Align != nil {
+		align, err := types.DurationFromProto(partition.Align)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.Wrapf(err, "invalid partition align")
+		}
+		if align < 0 {
+			return time.Time{}, time.Time{}, errors.Errorf("partition align can't be negative")
+		}
+		if align > 0 {
+			end = end.Truncate(align).Add(align)
+		}
+	}
+	return end.Add(-window), end, nil
+}
+
+// partitionFilter returns a function that reports whether a path in the
+// input's commit is a partition within the input's window. The window ends
+// when the commit was finished, so that every iterator over the same commit
+// selects the same partitions.
+func partitionFilter(pachClient *client.APIClient, input *pps.PFSInput) (func(string) bool, error) {
+	layout, err := parsePartitionLayout(input.Partition.Layout)
+	if err != nil {
+		return nil, err
+	}
+	commitInfo, err := pachClient.InspectCommit(input.Repo, input.Commit)
+	if err != nil {
+		return nil, err
+	}
+	refProto := commitInfo.Finished
+	if refProto == nil {
+		refProto = commitInfo.Started
+	}
+	ref, err := types.TimestampFromProto(refProto)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := partitionWindow(input.Partition, ref)
+	if err != nil {
+		return nil, err
+	}
+	return func(path string) bool {
+		t, ok := layout.time(path)
+		return ok && !t.Before(start) && t.Before(end)
+	}, nil
+}
@@ -0,0 +1,68 @@
+package datum
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestPartitionLayout(t *testing.T) {
+	layout, err := parsePartitionLayout("/YYYY/MM/DD")
+	require.NoError(t, err)
+	require.Equal(t, "/*/*/*", layout.glob)
+	partitionTime, ok := layout.time("/2020/06/30")
+	require.True(t, ok)
+	require.Equal(t, time.Date(2020, 6, 30, 0, 0, 0, 0, time.UTC), partitionTime)
+	_, ok = layout.time("/2020/13/01")
+	require.False(t, ok)
+	_, ok = layout.time("/2020/02/30")
+	require.False(t, ok)
+	_, ok = layout.time("/2020/notes/01")
+	require.False(t, ok)
+
+	layout, err = parsePartitionLayout("*/logs/YYYYMMDD-HH")
+	require.NoError(t, err)
+	require.Equal(t, "/*/logs/*-*", layout.glob)
+	partitionTime, ok = layout.time("/web/logs/20200630-17")
+	require.True(t, ok)
+	require.Equal(t, time.Date(2020, 6, 30, 17, 0, 0, 0, time.UTC), partitionTime)
+
+	_, err = parsePartitionLayout("/MM/DD")
+	require.YesError(t, err)
+	_, err = parsePartitionLayout("/YYYY/DD")
+	require.YesError(t, err)
+	_, err = parsePartitionLayout("/YYYY/YYYY")
+	require.YesError(t, err)
+	_, err = parsePartitionLayout("/YYYY/[0-9]")
+	require.YesError(t, err)
+}
+
+func TestPartitionWindow(t *testing.T) {
+	ref := time.Date(2020, 6, 30, 15, 0, 0, 0, time.UTC)
+	partition := &pps.Partition{
+		Layout: "/YYYY/MM/DD",
+		Window: types.DurationProto(7 * 24 * time.Hour),
+	}
+	start, end, err := partitionWindow(partition, ref)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2020, 6, 23, 15, 0, 0, 0, time.UTC), start)
+	require.Equal(t, ref, end)
+
+	// Aligned daily, the window covers today and the six days before it
+	partition.Align = types.DurationProto(24 * time.Hour)
+	start, end, err = partitionWindow(partition, ref)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2020, 6, 24, 0, 0, 0, 0, time.UTC), start)
+	require.Equal(t, time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC), end)
+
+	require.NoError(t, ValidatePartition(partition))
+	require.YesError(t, ValidatePartition(&pps.Partition{Layout: "/YYYY/MM/DD"}))
+	require.YesError(t, ValidatePartition(&pps.Partition{
+		Layout: "/YYYY/MM/DD",
+		Window: types.DurationProto(-time.Hour),
+	}))
+}
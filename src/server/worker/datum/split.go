@@ -0,0 +1,148 @@
+package datum
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/csv"
+	"io"
+	"math"
+	"strings"
+
+	glob "github.com/pachyderm/ohmyglob"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ancestry"
+)
+
+// splitRatioTolerance is how far from 1 the ratios of a split input's splits
+// may add up to, to allow for their decimal representations
+const splitRatioTolerance = 1e-6
+
+// ValidateSplit checks that 'split' has uniquely named splits whose ratios
+// add up to 1 (or are all 0, if it has a manifest).
+func ValidateSplit(split *pps.SplitInput) error {
+	if len(split.Splits) == 0 {
+		return errors.New("split must have at least one split")
+	}
+	names := make(map[string]bool)
+	var total float64
+	for _, s := range split.Splits {
+		if err := ancestry.ValidateName(s.Name); err != nil {
+			return errors.Wrapf(err, "invalid split name")
+		}
+		if names[s.Name] {
+			return errors.Errorf("split %q is listed more than once", s.Name)
+		}
+		names[s.Name] = true
+		if s.Ratio < 0 || math.IsNaN(s.Ratio) {
+			return errors.Errorf("the ratio of split %q can't be negative", s.Name)
+		}
+		total += s.Ratio
+	}
+	if total == 0 && split.Manifest != "" {
+		return nil
+	}
+	if math.Abs(total-1) > splitRatioTolerance {
+		return errors.Errorf("split ratios must add up to 1 (they add up to %v)", total)
+	}
+	return nil
+}
+
+// splitter assigns the datums of a PFS input to the splits of its
+// pps.SplitInput
+type splitter struct {
+	split    *pps.SplitInput
+	glob     *glob.Glob
+	manifest map[string]string // datum key -> split
+}
+
+// newSplitter returns the splitter of 'input', reading its manifest (if it
+// has one) from its commit.
+func newSplitter(pachClient *client.APIClient, input *pps.PFSInput) (*splitter, error) {
+	g, err := glob.Compile(input.Glob, '/')
+	if err != nil {
+		return nil, err
+	}
+	s := &splitter{split: input.Split, glob: g}
+	if input.Split.Manifest == "" {
+		return s, nil
+	}
+	var buf bytes.Buffer
+	if err := pachClient.GetFile(input.Repo, input.Commit, input.Split.Manifest, 0, 0, &buf); err != nil {
+		return nil, errors.Wrapf(err, "could not read the split manifest of input %q", input.Name)
+	}
+	if s.manifest, err = parseSplitManifest(input.Split, &buf); err != nil {
+		return nil, errors.Wrapf(err, "invalid split manifest %s of input %q", input.Split.Manifest, input.Name)
+	}
+	return s, nil
+}
+
+// parseSplitManifest parses the rows of a split manifest, which must refer to
+// the splits of 'split'.
+func parseSplitManifest(split *pps.SplitInput, r io.Reader) (map[string]string, error) {
+	names := make(map[string]bool)
+	for _, s := range split.Splits {
+		names[s.Name] = true
+	}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	result := make(map[string]string)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, errors.EnsureStack(err)
+		}
+		key, name := record[0], strings.TrimSpace(record[1])
+		if !names[name] {
+			return nil, errors.Errorf("key %q is assigned to unknown split %q", key, name)
+		}
+		if prev, ok := result[key]; ok && prev != name {
+			return nil, errors.Errorf("key %q is assigned to both %q and %q", key, prev, name)
+		}
+		result[key] = name
+	}
+}
+
+// assign returns the split of the datum at 'path', or "" if it's dropped.
+func (s *splitter) assign(path string) string {
+	if s.split.Manifest != "" && cleanPath(path) == cleanPath(s.split.Manifest) {
+		return ""
+	}
+	key := path
+	if s.split.Key != "" {
+		key = s.glob.Replace(path, s.split.Key)
+	}
+	if name, ok := s.manifest[key]; ok {
+		return name
+	}
+	return splitByHash(s.split, key)
+}
+
+// splitByHash returns the split that the datum with key 'key' is assigned to
+// by hash, or "" if no split has a ratio.
+func splitByHash(split *pps.SplitInput, key string) string {
+	hash := sha256.Sum256([]byte(split.Seed + "\x00" + key))
+	// The first 64 bits of the hash, as a fraction in [0, 1)
+	x := float64(binary.BigEndian.Uint64(hash[:8])) / math.Exp2(64)
+	var cumulative float64
+	last := ""
+	for _, s := range split.Splits {
+		if s.Ratio == 0 {
+			continue
+		}
+		cumulative += s.Ratio
+		if x < cumulative {
+			return s.Name
+		}
+		last = s.Name
+	}
+	// The ratios may add up to slightly less than 1
+	return last
+}
@@ -0,0 +1,94 @@
+package datum
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	glob "github.com/pachyderm/ohmyglob"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func trainValTest() *pps.SplitInput {
+	return &pps.SplitInput{Splits: []*pps.DatumSplit{
+		{Name: "train", Ratio: 0.8},
+		{Name: "val", Ratio: 0.1},
+		{Name: "test", Ratio: 0.1},
+	}}
+}
+
+func TestValidateSplit(t *testing.T) {
+	require.NoError(t, ValidateSplit(trainValTest()))
+
+	split := trainValTest()
+	split.Splits[0].Ratio = 0.7
+	require.YesError(t, ValidateSplit(split))
+	split.Splits = append(split.Splits, &pps.DatumSplit{Name: "train", Ratio: 0.1})
+	require.YesError(t, ValidateSplit(split))
+	require.YesError(t, ValidateSplit(&pps.SplitInput{}))
+	require.YesError(t, ValidateSplit(&pps.SplitInput{Splits: []*pps.DatumSplit{{Name: "a/b", Ratio: 1}}}))
+	require.YesError(t, ValidateSplit(&pps.SplitInput{Splits: []*pps.DatumSplit{{Name: "a", Ratio: 1.5}, {Name: "b", Ratio: -0.5}}}))
+
+	// Splits that are only assigned by a manifest have no ratios
+	manifestOnly := &pps.SplitInput{Manifest: "/splits.csv", Splits: []*pps.DatumSplit{{Name: "train"}, {Name: "test"}}}
+	require.NoError(t, ValidateSplit(manifestOnly))
+	manifestOnly.Manifest = ""
+	require.YesError(t, ValidateSplit(manifestOnly))
+}
+
+func TestSplitByHash(t *testing.T) {
+	split := trainValTest()
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		key := fmt.Sprintf("/file-%d", i)
+		name := splitByHash(split, key)
+		require.Equal(t, name, splitByHash(split, key))
+		counts[name]++
+	}
+	require.Equal(t, 3, len(counts))
+	require.True(t, counts["train"] > 7700 && counts["train"] < 8300, "train: %d", counts["train"])
+	require.True(t, counts["val"] > 800 && counts["val"] < 1200, "val: %d", counts["val"])
+
+	// Changing the seed reshuffles the splits
+	reseeded := trainValTest()
+	reseeded.Seed = "2"
+	moved := 0
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("/file-%d", i)
+		if splitByHash(split, key) != splitByHash(reseeded, key) {
+			moved++
+		}
+	}
+	require.True(t, moved > 0)
+
+	require.Equal(t, "", splitByHash(&pps.SplitInput{Splits: []*pps.DatumSplit{{Name: "train"}}}, "/file"))
+}
+
+func TestSplitterAssign(t *testing.T) {
+	split := trainValTest()
+	split.Key = "$1"
+	split.Manifest = "/splits.csv"
+	manifest, err := parseSplitManifest(split, strings.NewReader("patient-1,test\npatient-2, val\n"))
+	require.NoError(t, err)
+	g, err := glob.Compile("/(*)/*", '/')
+	require.NoError(t, err)
+	s := &splitter{split: split, glob: g, manifest: manifest}
+
+	require.Equal(t, "test", s.assign("/patient-1/scan-1.png"))
+	require.Equal(t, "test", s.assign("/patient-1/scan-2.png"))
+	require.Equal(t, "val", s.assign("/patient-2/scan-1.png"))
+	// Unlisted keys are assigned by hash, so all of a patient's scans are in
+	// the same split
+	require.Equal(t, splitByHash(split, "patient-3"), s.assign("/patient-3/scan-1.png"))
+	require.Equal(t, s.assign("/patient-3/scan-1.png"), s.assign("/patient-3/scan-2.png"))
+	require.Equal(t, "", s.assign("/splits.csv"))
+
+	_, err = parseSplitManifest(split, strings.NewReader("patient-1,holdout\n"))
+	require.YesError(t, err)
+	_, err = parseSplitManifest(split, strings.NewReader("patient-1,test\npatient-1,train\n"))
+	require.YesError(t, err)
+	_, err = parseSplitManifest(split, strings.NewReader("patient-1\n"))
+	require.YesError(t, err)
+}
@@ -0,0 +1,215 @@
+package driver
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"plugin"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/builtin"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/worker/common"
+	"github.com/pachyderm/pachyderm/src/server/worker/logs"
+)
+
+// loadBuiltinTransform downloads the plugin of 'transform' into 'dir', and
+// loads the function that it exports.
+func loadBuiltinTransform(pachClient *client.APIClient, transform *pps.BuiltinTransform, dir string) (builtin.Func, error) {
+	name := transform.Repo + "@" + transform.Commit + ":" + transform.Path
+	pluginPath := filepath.Join(dir, "builtin.so")
+	f, err := os.Create(pluginPath)
+	if err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	if err := pachClient.GetFile(transform.Repo, transform.Commit, transform.Path, 0, 0, f); err != nil {
+		f.Close()
+		return nil, errors.Wrapf(grpcutil.ScrubGRPC(err), "could not download builtin transform %s", name)
+	}
+	if err := f.Close(); err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	p, err := plugin.Open(pluginPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not load builtin transform %s (it must be "+
+			"built with the pachyderm/worker-builtin image of the same version as "+
+			"the worker)", name)
+	}
+	sym, err := p.Lookup(builtin.SymbolName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "builtin transform %s does not export %s", name, builtin.SymbolName)
+	}
+	switch fn := sym.(type) {
+	case builtin.Func:
+		return fn, nil
+	case *builtin.Func:
+		return *fn, nil
+	}
+	return nil, errors.Errorf("builtin transform %s exports %s with type %T, "+
+		"instead of builtin.Func", name, builtin.SymbolName, sym)
+}
+
+// RunBuiltinTransform runs the pipeline's builtin transform on the datum whose
+// inputs are 'inputs', which must already be linked into the active input
+// directory. The transform runs in the worker's process, so it can't be
+// killed if the datum times out; it's only told to stop through its context.
+func (d *driver) RunBuiltinTransform(
+	logger logs.TaggedLogger,
+	inputs []*common.Input,
+	procStats *pps.ProcessStats,
+	rawDatumTimeout *types.Duration,
+) (retErr error) {
+	ctx := d.pachClient.Ctx()
+	d.reportUserCodeStats(logger)
+	defer func(start time.Time) { d.reportDeferredUserCodeStats(retErr, start, procStats, logger) }(time.Now())
+	logger.Logf("beginning to run builtin transform")
+	defer func(start time.Time) {
+		if retErr != nil {
+			logger.Logf("errored running builtin transform after %v: %v", time.Since(start), retErr)
+		} else {
+			logger.Logf("finished running builtin transform after %v", time.Since(start))
+		}
+	}(time.Now())
+	if rawDatumTimeout != nil {
+		datumTimeout, err := types.DurationFromProto(rawDatumTimeout)
+		if err != nil {
+			return errors.EnsureStack(err)
+		}
+		datumTimeoutCtx, cancel := context.WithTimeout(ctx, datumTimeout)
+		defer cancel()
+		ctx = datumTimeoutCtx
+	}
+
+	if d.builtin == nil {
+		return errors.New("invalid pipeline transform, no builtin transform loaded")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			retErr = errors.Errorf("builtin transform panicked: %v", r)
+		}
+	}()
+	if err := d.builtin(ctx, newBuiltinDatum(d.InputDir(), inputs, logger.WithUserCode())); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return errors.EnsureStack(err)
+	}
+	return nil
+}
+
+// builtinDatum implements builtin.Datum for the datum whose inputs are linked
+// into 'inputDir'. Paths are cleaned so that they stay inside their input (or
+// the output), but this only keeps well-behaved transforms to their datum:
+// the transform runs in the worker's process and can open any file itself.
+type builtinDatum struct {
+	inputDir string
+	inputs   []string
+	logger   logs.TaggedLogger
+}
+
+func newBuiltinDatum(inputDir string, inputs []*common.Input, logger logs.TaggedLogger) *builtinDatum {
+	result := &builtinDatum{inputDir: inputDir, logger: logger}
+	for _, input := range inputs {
+		if !input.S3 && input.VolumePath == "" {
+			result.inputs = append(result.inputs, input.Name)
+		}
+	}
+	return result
+}
+
+func (bd *builtinDatum) Inputs() []string {
+	return append([]string{}, bd.inputs...)
+}
+
+// inputPath returns the local path of 'path' in the input 'name'
+func (bd *builtinDatum) inputPath(name, path string) (string, error) {
+	for _, input := range bd.inputs {
+		if input == name {
+			return filepath.Join(bd.inputDir, name, filepath.Clean("/"+path)), nil
+		}
+	}
+	return "", errors.Errorf("datum has no input named %q", name)
+}
+
+func (bd *builtinDatum) Open(name, path string) (io.ReadCloser, error) {
+	p, err := bd.inputPath(name, path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	return f, nil
+}
+
+func (bd *builtinDatum) Walk(name, path string, f func(string, os.FileInfo) error) error {
+	p, err := bd.inputPath(name, path)
+	if err != nil {
+		return err
+	}
+	root := filepath.Join(bd.inputDir, name)
+	// Inputs are linked into the input directory, and filepath.Walk doesn't
+	// follow symlinks, so the walk resolves them itself
+	return walkFollowingLinks(p, func(p string, info os.FileInfo) error {
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return errors.EnsureStack(err)
+		}
+		return f(filepath.Clean("/"+rel), info)
+	})
+}
+
+// walkFollowingLinks walks the files under 'path' in lexical order, like
+// filepath.Walk, but follows symlinks.
+func walkFollowingLinks(path string, f func(string, os.FileInfo) error) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.EnsureStack(err)
+	}
+	if err := f(path, info); err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return nil
+	}
+	dir, err := os.Open(path)
+	if err != nil {
+		return errors.EnsureStack(err)
+	}
+	names, err := dir.Readdirnames(-1)
+	dir.Close()
+	if err != nil {
+		return errors.EnsureStack(err)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := walkFollowingLinks(filepath.Join(path, name), f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bd *builtinDatum) Create(path string) (io.WriteCloser, error) {
+	p := filepath.Join(bd.inputDir, "out", filepath.Clean("/"+path))
+	if err := os.MkdirAll(filepath.Dir(p), 0777); err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	f, err := os.Create(p)
+	if err != nil {
+		return nil, errors.EnsureStack(err)
+	}
+	return f, nil
+}
+
+func (bd *builtinDatum) Logf(format string, args ...interface{}) {
+	bd.logger.Logf(format, args...)
+}
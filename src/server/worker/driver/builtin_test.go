@@ -0,0 +1,70 @@
+package driver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/server/worker/common"
+	"github.com/pachyderm/pachyderm/src/server/worker/logs"
+)
+
+func TestBuiltinDatum(t *testing.T) {
+	inputDir, err := ioutil.TempDir("", "builtin-datum")
+	require.NoError(t, err)
+	defer os.RemoveAll(inputDir)
+	// Inputs and the output are symlinks into the datum's scratch directory,
+	// as they are when the datum is active
+	scratchDir, err := ioutil.TempDir("", "builtin-scratch")
+	require.NoError(t, err)
+	defer os.RemoveAll(scratchDir)
+	require.NoError(t, os.MkdirAll(filepath.Join(scratchDir, "in", "dir"), 0777))
+	require.NoError(t, os.MkdirAll(filepath.Join(scratchDir, "out"), 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(scratchDir, "in", "dir", "b"), []byte("bar"), 0666))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(scratchDir, "in", "a"), []byte("foo"), 0666))
+	require.NoError(t, os.Symlink(filepath.Join(scratchDir, "in"), filepath.Join(inputDir, "in")))
+	require.NoError(t, os.Symlink(filepath.Join(scratchDir, "out"), filepath.Join(inputDir, "out")))
+
+	datum := newBuiltinDatum(inputDir, []*common.Input{{Name: "in"}, {Name: "s3", S3: true}}, logs.NewMockLogger())
+	require.Equal(t, []string{"in"}, datum.Inputs())
+
+	r, err := datum.Open("in", "/a")
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "foo", string(data))
+
+	// Paths are relative to their input, so "../../a" is clamped to "/a",
+	// and "../../in/a" (the same file, if it weren't clamped) doesn't exist
+	r, err = datum.Open("in", "../../a")
+	require.NoError(t, err)
+	data, err = ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "foo", string(data))
+	_, err = datum.Open("in", "../../in/a")
+	require.YesError(t, err)
+	require.True(t, errors.Is(err, os.ErrNotExist))
+	_, err = datum.Open("s3", "/a")
+	require.YesError(t, err)
+
+	var paths []string
+	require.NoError(t, datum.Walk("in", "/", func(path string, info os.FileInfo) error {
+		paths = append(paths, path)
+		return nil
+	}))
+	require.Equal(t, []string{"/", "/a", "/dir", "/dir/b"}, paths)
+
+	w, err := datum.Create("../x/y")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("baz"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	data, err = ioutil.ReadFile(filepath.Join(scratchDir, "out", "x", "y"))
+	require.NoError(t, err)
+	require.Equal(t, "baz", string(data))
+}
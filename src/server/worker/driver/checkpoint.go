@@ -0,0 +1,200 @@
+package driver
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/server/pkg/tar"
+	"github.com/pachyderm/pachyderm/src/server/pkg/tarutil"
+	"github.com/pachyderm/pachyderm/src/server/worker/logs"
+)
+
+const (
+	// CheckpointMarker is the name of the file that user code creates in
+	// /pfs/out to checkpoint the datum's output. The worker deletes the marker
+	// once the checkpoint has been uploaded, so user code that wants to be sure
+	// that a checkpoint is durable can wait for the marker to disappear.
+	CheckpointMarker = ".checkpoint"
+
+	// CheckpointDir is the name of the directory in /pfs (and in the datum's
+	// scratch space) at which the most recent checkpoint of a datum is exposed
+	// when the datum is retried. Input names can't begin with '.', so this
+	// can't collide with an input.
+	CheckpointDir = ".checkpoint"
+
+	checkpointTagSuffix = "_checkpoint"
+	checkpointInterval  = time.Second
+)
+
+// WithCheckpoints calls 'cb' with checkpointing enabled for the datum whose
+// scratch space is 'dir'. The datum's most recent checkpoint (if any) is
+// restored read-only into the scratch space before 'cb' is called, and while
+// 'cb' runs, the datum's output is uploaded as a new checkpoint whenever user
+// code creates CheckpointMarker. If 'cb' succeeds, the datum's checkpoint is
+// deleted, as it's no longer needed.
+func (d *driver) WithCheckpoints(tag string, dir string, logger logs.TaggedLogger, cb func() error) (retErr error) {
+	if !d.PipelineInfo().Transform.Checkpoints {
+		return cb()
+	}
+	checkpointTag := tag + checkpointTagSuffix
+	restoreDir := filepath.Join(dir, CheckpointDir)
+	if err := d.restoreCheckpoint(checkpointTag, restoreDir, logger); err != nil {
+		return err
+	}
+	// WithData removes the scratch space once the datum is done, which
+	// requires the restored checkpoint to be writable again
+	defer func() {
+		if err := setCheckpointMode(restoreDir, true); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+
+	done := make(chan struct{})
+	watcherDone := make(chan struct{})
+	go func() {
+		defer close(watcherDone)
+		d.watchCheckpoints(checkpointTag, filepath.Join(dir, "out"), logger, done)
+	}()
+	err := cb()
+	close(done)
+	<-watcherDone
+	if err != nil {
+		return err
+	}
+	if _, err := d.pachClient.DeleteTags(d.pachClient.Ctx(), &pfs.DeleteTagsRequest{
+		Tags: []*pfs.Tag{client.NewTag(checkpointTag)},
+	}); err != nil {
+		logger.Logf("could not delete checkpoint of completed datum: %v", err)
+	}
+	return nil
+}
+
+// restoreCheckpoint downloads the checkpoint stored under 'checkpointTag' (if
+// there is one) into 'restoreDir', and makes it read-only.
+func (d *driver) restoreCheckpoint(checkpointTag string, restoreDir string, logger logs.TaggedLogger) error {
+	if _, err := d.pachClient.InspectTag(d.pachClient.Ctx(), client.NewTag(checkpointTag)); err != nil {
+		return nil // no checkpoint, the datum starts from scratch
+	}
+	buf := &bytes.Buffer{}
+	if err := d.pachClient.GetTag(checkpointTag, buf); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(restoreDir, 0777); err != nil {
+		return errors.EnsureStack(err)
+	}
+	if err := tarutil.Import(restoreDir, buf); err != nil {
+		return err
+	}
+	logger.Logf("restored checkpoint of datum to /pfs/%s", CheckpointDir)
+	return setCheckpointMode(restoreDir, false)
+}
+
+// watchCheckpoints polls 'outputDir' for CheckpointMarker until 'done' is
+// closed, and uploads a checkpoint each time the marker appears. A failed
+// upload doesn't fail the datum: the marker is left in place, so the upload is
+// retried on the next poll.
+func (d *driver) watchCheckpoints(checkpointTag string, outputDir string, logger logs.TaggedLogger, done <-chan struct{}) {
+	ticker := time.NewTicker(checkpointInterval)
+	defer ticker.Stop()
+	marker := filepath.Join(outputDir, CheckpointMarker)
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+		if _, err := os.Stat(marker); err != nil {
+			continue
+		}
+		start := time.Now()
+		if err := d.uploadCheckpoint(checkpointTag, outputDir); err != nil {
+			logger.Logf("error uploading checkpoint of datum output: %v", err)
+			continue
+		}
+		if err := os.Remove(marker); err != nil {
+			logger.Logf("could not remove checkpoint marker: %v", err)
+			continue
+		}
+		logger.Logf("uploaded checkpoint of datum output in %v", time.Since(start))
+	}
+}
+
+// uploadCheckpoint uploads the contents of 'outputDir' to object storage,
+// replacing the checkpoint stored under 'checkpointTag'.
+func (d *driver) uploadCheckpoint(checkpointTag string, outputDir string) error {
+	buf := &bytes.Buffer{}
+	if err := exportCheckpoint(outputDir, buf); err != nil {
+		return err
+	}
+	// Checkpoints are datum output, so they're stored like the rest of the
+	// pipeline's output
+	pachClient := d.pachClient.WithCtx(d.objectContext(d.pachClient.Ctx()))
+	_, _, err := pachClient.PutObject(buf, checkpointTag)
+	return err
+}
+
+// exportCheckpoint writes the regular files in 'outputDir' (other than
+// CheckpointMarker) to 'w' as a tar stream. Symlinks are followed, so that
+// output linked from the input directories is included in the checkpoint.
+func exportCheckpoint(outputDir string, w io.Writer) error {
+	return tarutil.WithWriter(w, func(tw *tar.Writer) error {
+		return filepath.Walk(outputDir, func(file string, _ os.FileInfo, err error) (retErr error) {
+			if err != nil {
+				return err
+			}
+			fi, err := os.Stat(file)
+			if err != nil {
+				return errors.EnsureStack(err)
+			}
+			if !fi.Mode().IsRegular() {
+				return nil
+			}
+			name, err := filepath.Rel(outputDir, file)
+			if err != nil {
+				return errors.EnsureStack(err)
+			}
+			if name == CheckpointMarker {
+				return nil
+			}
+			f, err := os.Open(file)
+			if err != nil {
+				return errors.EnsureStack(err)
+			}
+			defer func() {
+				if err := f.Close(); err != nil && retErr == nil {
+					retErr = errors.EnsureStack(err)
+				}
+			}()
+			return tarutil.WriteFile(tw, tarutil.NewStreamFile(filepath.Join("/", name), fi.Size(), f))
+		})
+	})
+}
+
+// setCheckpointMode makes the files and directories under 'restoreDir'
+// read-only (or writable again, if 'writable' is true). It's a no-op if
+// 'restoreDir' doesn't exist.
+func setCheckpointMode(restoreDir string, writable bool) error {
+	if _, err := os.Stat(restoreDir); os.IsNotExist(err) {
+		return nil
+	}
+	var fileMode, dirMode os.FileMode = 0444, 0555
+	if writable {
+		fileMode, dirMode = 0644, 0755
+	}
+	return filepath.Walk(restoreDir, func(file string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		mode := fileMode
+		if fi.IsDir() {
+			mode = dirMode
+		}
+		return errors.EnsureStack(os.Chmod(file, mode))
+	})
+}
@@ -0,0 +1,49 @@
+package driver
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/server/pkg/tarutil"
+)
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	outputDir, err := ioutil.TempDir("", "checkpoint-out")
+	require.NoError(t, err)
+	defer os.RemoveAll(outputDir)
+	require.NoError(t, os.MkdirAll(filepath.Join(outputDir, "dir"), 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(outputDir, "dir", "file"), []byte("partial"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(outputDir, CheckpointMarker), nil, 0644))
+	require.NoError(t, os.Symlink(filepath.Join(outputDir, "dir", "file"), filepath.Join(outputDir, "link")))
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, exportCheckpoint(outputDir, buf))
+
+	restoreDir, err := ioutil.TempDir("", "checkpoint-restore")
+	require.NoError(t, err)
+	defer os.RemoveAll(restoreDir)
+	require.NoError(t, tarutil.Import(restoreDir, buf))
+	require.NoError(t, setCheckpointMode(restoreDir, false))
+
+	data, err := ioutil.ReadFile(filepath.Join(restoreDir, "dir", "file"))
+	require.NoError(t, err)
+	require.Equal(t, "partial", string(data))
+	// symlinks are followed
+	data, err = ioutil.ReadFile(filepath.Join(restoreDir, "link"))
+	require.NoError(t, err)
+	require.Equal(t, "partial", string(data))
+	// the marker isn't part of the checkpoint
+	_, err = os.Stat(filepath.Join(restoreDir, CheckpointMarker))
+	require.True(t, os.IsNotExist(err))
+
+	fi, err := os.Stat(filepath.Join(restoreDir, "dir", "file"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0444), fi.Mode().Perm())
+
+	require.NoError(t, setCheckpointMode(restoreDir, true))
+	require.NoError(t, os.RemoveAll(restoreDir))
+}
@@ -26,16 +26,21 @@ import (
 	"gopkg.in/go-playground/webhooks.v5/github"
 	"gopkg.in/src-d/go-git.v4"
 	gitPlumbing "gopkg.in/src-d/go-git.v4/plumbing"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/builtin"
 	"github.com/pachyderm/pachyderm/src/client/enterprise"
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
 	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/residency"
+	"github.com/pachyderm/pachyderm/src/client/pkg/tenant"
 	"github.com/pachyderm/pachyderm/src/client/pps"
 	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
 	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
 	"github.com/pachyderm/pachyderm/src/server/pkg/exec"
+	"github.com/pachyderm/pachyderm/src/server/pkg/filecache"
 	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
 	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
@@ -60,7 +65,7 @@ var (
 )
 
 func workNamespace(pipelineInfo *pps.PipelineInfo) string {
-	return fmt.Sprintf("/pipeline-%s/v%d", pipelineInfo.Pipeline.Name, pipelineInfo.Version)
+	return common.WorkNamespace(pipelineInfo.Pipeline.Name, pipelineInfo.Version)
 }
 
 // Driver provides an interface for common functions needed by worker code, and
@@ -72,7 +77,9 @@ type Driver interface {
 	Jobs() col.Collection
 	Pipelines() col.Collection
 
-	NewTaskWorker() *work.Worker
+	// NewTaskWorker returns a worker that processes the subtasks of the
+	// worker class 'workerClass' ("" for the pipeline's own workers)
+	NewTaskWorker(workerClass string) *work.Worker
 	NewTaskQueue() (*work.TaskQueue, error)
 
 	// Returns the PipelineInfo for the pipeline that this worker belongs to
@@ -113,22 +120,53 @@ type Driver interface {
 	// launching the configured user process.
 	UserCodeEnv(string, *pfs.Commit, []*common.Input) []string
 
+	// WatchRuntimeConfig keeps the runtime config that UserCodeEnv exposes to
+	// user code up to date with the pipeline's, until the driver's context is
+	// cancelled.
+	WatchRuntimeConfig() error
+
 	// RunUserCode links a specific scratch space for the active input/output
 	// data, then runs the pipeline's configured code. It uses a mutex to enforce
 	// that this is not done concurrently, and may block.
 	RunUserCode(logs.TaggedLogger, []string, *pps.ProcessStats, *types.Duration) error
 
+	// RunBuiltinTransform runs the pipeline's builtin transform in-process on
+	// the datum with the given inputs, which must already be active.
+	RunBuiltinTransform(logs.TaggedLogger, []*common.Input, *pps.ProcessStats, *types.Duration) error
+
+	// RunValidation checks the assertions of a validation pipeline against the
+	// datum with the given inputs, which must already be active, and writes
+	// its report to the datum's output.
+	RunValidation(logs.TaggedLogger, []*common.Input, *pps.ProcessStats) error
+
 	// RunUserErrorHandlingCode runs the pipeline's configured error handling code
 	RunUserErrorHandlingCode(logs.TaggedLogger, []string, *pps.ProcessStats, *types.Duration) error
 
+	// WithCheckpoints restores the most recent checkpoint of the datum with the
+	// given tag into the given scratch directory, and uploads new checkpoints
+	// while the callback runs. It only does anything if the pipeline's
+	// transform enables checkpoints.
+	WithCheckpoints(string, string, logs.TaggedLogger, func() error) error
+
 	// TODO: provide a more generic interface for modifying jobs, and
 	// some quality-of-life functions for common operations.
 	DeleteJob(col.STM, *pps.EtcdJobInfo) error
 	UpdateJobState(string, pps.JobState, string) error
 
 	// UploadOutput uploads the stats hashtree and pfs output directory to object
-	// storage and returns a buffer of the serialized hashtree
-	UploadOutput(string, string, logs.TaggedLogger, []*common.Input, *pps.ProcessStats, *hashtree.Ordered) ([]byte, error)
+	// storage, and puts the serialized hashtree in the given datum cache
+	UploadOutput(string, string, logs.TaggedLogger, []*common.Input, *pps.ProcessStats, *hashtree.Ordered, *hashtree.MergeCache) error
+
+	// NewOrderedTree returns a hashtree, rooted at the given path, for building
+	// a datum's hashtree. The tree spills its files to disk and counts its
+	// directories against the pipeline's hashtree memory limit, and must be
+	// closed.
+	NewOrderedTree(string) (*hashtree.Ordered, error)
+
+	// WithSpillFile calls the given callback with an empty temporary file, in
+	// which hashtrees that are too large to buffer in memory can be buffered.
+	// The file is removed when the callback returns.
+	WithSpillFile(func(*os.File) error) error
 
 	// TODO: figure out how to not expose this
 	ReportUploadStats(time.Time, *pps.ProcessStats, logs.TaggedLogger)
@@ -147,7 +185,7 @@ type Driver interface {
 	// bound to the callback, and any resources will be cleaned up upon return.
 	WithDatumCache(func(*hashtree.MergeCache, *hashtree.MergeCache) error) error
 
-	Egress(commit *pfs.Commit, egressURL string) error
+	Egress(commit *pfs.Commit, egress *pps.Egress) error
 }
 
 type driver struct {
@@ -187,6 +225,31 @@ type driver struct {
 	// These caches are used for storing and merging hashtrees from jobs until the
 	// job is complete
 	chunkCaches, chunkStatsCaches cache.WorkerCache
+
+	// The directory in which hashtrees are buffered on disk, and the memory
+	// budget shared by the hashtrees that this worker builds
+	spillDir       string
+	hashtreeBudget *hashtree.MemoryBudget
+
+	// The pipeline's output merges, compiled
+	outputMerges []*outputMerge
+
+	// The pipeline's output routes, compiled
+	outputRoutes []*outputRoute
+
+	// The pipeline's builtin transform, if it has one
+	builtin builtin.Func
+
+	// The pipeline's current runtime config
+	runtimeConfig *runtimeConfig
+
+	// The cache that small input files are read through, if the pipeline has
+	// a file cache
+	fileCache *filecache.Cache
+
+	// Where the content of the output files that this worker uploaded is
+	// stored, so that identical output files aren't uploaded again
+	outputIndex *outputIndex
 }
 
 // NewDriver constructs a Driver object using the given clients and pipeline
@@ -206,6 +269,7 @@ func NewDriver(
 	pfsPath := filepath.Join(rootPath, client.PPSInputPrefix)
 	chunkCachePath := filepath.Join(hashtreePath, "chunk")
 	chunkStatsCachePath := filepath.Join(hashtreePath, "chunkStats")
+	spillPath := filepath.Join(hashtreePath, "spill")
 
 	// Delete the hashtree path (if it exists) in case it is left over from a previous run
 	if err := os.RemoveAll(chunkCachePath); err != nil {
@@ -214,6 +278,9 @@ func NewDriver(
 	if err := os.RemoveAll(chunkStatsCachePath); err != nil {
 		return nil, errors.EnsureStack(err)
 	}
+	if err := os.RemoveAll(spillPath); err != nil {
+		return nil, errors.EnsureStack(err)
+	}
 
 	if err := os.MkdirAll(pfsPath, 0777); err != nil {
 		return nil, errors.EnsureStack(err)
@@ -224,6 +291,9 @@ func NewDriver(
 	if err := os.MkdirAll(chunkStatsCachePath, 0777); err != nil {
 		return nil, errors.EnsureStack(err)
 	}
+	if err := os.MkdirAll(spillPath, 0777); err != nil {
+		return nil, errors.EnsureStack(err)
+	}
 
 	numShards, err := ppsutil.GetExpectedNumHashtrees(pipelineInfo.HashtreeSpec)
 	if err != nil {
@@ -246,7 +316,64 @@ func NewDriver(
 		chunkCaches:      cache.NewWorkerCache(chunkCachePath),
 		chunkStatsCaches: cache.NewWorkerCache(chunkStatsCachePath),
 		namespace:        namespace,
+		spillDir:         spillPath,
+	}
+
+	if result.outputMerges, err = compileOutputMerges(pipelineInfo.OutputMerges); err != nil {
+		return nil, err
+	}
+	if result.outputRoutes, err = compileOutputRoutes(pipelineInfo.OutputRoutes); err != nil {
+		return nil, err
+	}
+	if result.runtimeConfig, err = newRuntimeConfig(filepath.Join(hashtreePath, runtimeConfigFile), pipelineInfo.RuntimeConfig); err != nil {
+		return nil, errors.Wrapf(err, "could not write runtime config")
+	}
+	if spec := pipelineInfo.FileCache; spec != nil {
+		var ttl time.Duration
+		if spec.Ttl != nil {
+			if ttl, err = types.DurationFromProto(spec.Ttl); err != nil {
+				return nil, errors.Wrapf(err, "could not parse file cache ttl")
+			}
+		}
+		var store filecache.Store
+		if spec.RedisAddress != "" {
+			store = filecache.NewRedisStore(spec.RedisAddress)
+		} else if len(spec.MemcachedAddresses) > 0 {
+			store = filecache.NewMemcachedStore(spec.MemcachedAddresses...)
+		}
+		// Pipelines only share cached files with the pipelines of their tenant
+		result.fileCache = filecache.NewStoreCache(spec.MaxFileBytes, spec.SizeBytes, ttl, store, pipelineInfo.Tenant)
+	}
+	if result.outputIndex, err = newOutputIndex(maxDedupedOutputs); err != nil {
+		return nil, err
+	}
+	if pipelineInfo.Transform.Builtin != nil {
+		if result.builtin, err = loadBuiltinTransform(pachClient, pipelineInfo.Transform.Builtin, hashtreePath); err != nil {
+			return nil, err
+		}
+	}
+
+	var hashtreeMemoryLimit int64
+	if pipelineInfo.HashtreeMemoryLimit != "" {
+		limit, err := resource.ParseQuantity(pipelineInfo.HashtreeMemoryLimit)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse hashtree memory limit")
+		}
+		hashtreeMemoryLimit = limit.Value()
 	}
+	result.hashtreeBudget = hashtree.NewMemoryBudget(hashtreeMemoryLimit, func() {
+		if result.exportStats {
+			if counter, err := stats.HashtreeMemoryLimitExceededCount.GetMetricWithLabelValues(pipelineInfo.ID); err == nil {
+				counter.Add(1)
+			}
+		}
+	}, func(used int64) {
+		if result.exportStats {
+			if gauge, err := stats.HashtreeMemoryBytes.GetMetricWithLabelValues(pipelineInfo.ID); err == nil {
+				gauge.Set(float64(used))
+			}
+		}
+	})
 
 	if pipelineInfo.Transform.User != "" {
 		user, err := lookupDockerUser(pipelineInfo.Transform.User)
@@ -371,8 +498,8 @@ func (d *driver) Pipelines() col.Collection {
 	return d.pipelines
 }
 
-func (d *driver) NewTaskWorker() *work.Worker {
-	return work.NewWorker(d.etcdClient, d.etcdPrefix, workNamespace(d.pipelineInfo))
+func (d *driver) NewTaskWorker(workerClass string) *work.Worker {
+	return work.NewGroupWorker(d.etcdClient, d.etcdPrefix, workNamespace(d.pipelineInfo), workerClass)
 }
 
 func (d *driver) NewTaskQueue() (*work.TaskQueue, error) {
@@ -451,6 +578,26 @@ func (d *driver) WithDatumCache(cb func(*hashtree.MergeCache, *hashtree.MergeCac
 	return withDatumCache(d.hashtreeDir, cb)
 }
 
+func (d *driver) NewOrderedTree(root string) (*hashtree.Ordered, error) {
+	return hashtree.NewSpillingOrdered(root, d.spillDir, d.hashtreeBudget)
+}
+
+func (d *driver) WithSpillFile(cb func(*os.File) error) (retErr error) {
+	f, err := ioutil.TempFile(d.spillDir, "hashtree-")
+	if err != nil {
+		return errors.EnsureStack(err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil && retErr == nil {
+			retErr = errors.EnsureStack(err)
+		}
+		if err := os.Remove(f.Name()); err != nil && retErr == nil {
+			retErr = errors.EnsureStack(err)
+		}
+	}()
+	return cb(f)
+}
+
 func (d *driver) NewSTM(cb func(col.STM) error) (*etcd.TxnResponse, error) {
 	return col.NewSTM(d.pachClient.Ctx(), d.etcdClient, cb)
 }
@@ -461,7 +608,7 @@ func (d *driver) WithData(
 	logger logs.TaggedLogger,
 	cb func(string, *pps.ProcessStats) error,
 ) (retStats *pps.ProcessStats, retErr error) {
-	puller := filesync.NewPuller()
+	puller := filesync.NewCachingPuller(d.fileCache)
 	stats := &pps.ProcessStats{}
 
 	// Download input data into a temporary directory
@@ -510,6 +657,8 @@ func (d *driver) WithData(
 	// concerned, they might've just seen an empty or partially completed
 	// file.
 	// TODO: do we really need two puller.CleanUps?
+	cachedSize := puller.CachedSize()
+	correctedReads := puller.CorrectedReads()
 	downSize, err := puller.CleanUp()
 	if err != nil {
 		logger.Logf("puller encountered an error while cleaning up: %v", err)
@@ -517,7 +666,12 @@ func (d *driver) WithData(
 	}
 
 	atomic.AddUint64(&stats.DownloadBytes, uint64(downSize))
-	d.reportDownloadSizeStats(float64(downSize), logger)
+	atomic.AddUint64(&stats.CachedBytes, uint64(cachedSize))
+	if correctedReads > 0 {
+		logger.Logf("fetched %d lazily-read objects again because they didn't match their hashes", correctedReads)
+		atomic.AddUint64(&stats.CorrectedReads, uint64(correctedReads))
+	}
+	d.reportDownloadSizeStats(float64(downSize), float64(cachedSize), logger)
 	return stats, nil
 }
 
@@ -597,6 +751,13 @@ func (d *driver) downloadData(
 		if err := os.MkdirAll(outPath, 0777); err != nil {
 			return "", errors.Wrapf(err, "couldn't create %q", outPath)
 		}
+		// Create the directories of the pipeline's named outputs
+		// (typically /pfs/out/<name>)
+		for _, output := range d.PipelineInfo().Outputs {
+			if err := os.MkdirAll(filepath.Join(outPath, output.Name), 0777); err != nil {
+				return "", errors.Wrapf(err, "couldn't create directory for output %q", output.Name)
+			}
+		}
 	}
 	for _, input := range inputs {
 		if input.GitURL != "" {
@@ -605,17 +766,18 @@ func (d *driver) downloadData(
 			}
 			continue
 		}
-		if input.S3 {
+		if input.S3 || input.VolumePath != "" {
 			continue // don't download any data
 		}
 		file := input.FileInfo.File
-		fullInputPath := filepath.Join(scratchPath, input.Name, file.Path)
+		fullInputPath := filepath.Join(scratchPath, input.Name, input.Split, file.Path)
 		var statsRoot string
 		if statsTree != nil {
-			statsRoot = filepath.Join(input.Name, file.Path)
+			statsRoot = filepath.Join(input.Name, input.Split, file.Path)
 			parent, _ := filepath.Split(statsRoot)
 			statsTree.MkdirAll(parent)
 		}
+		puller.VerifyPipes(!input.SkipLazyVerification)
 		if err := puller.Pull(
 			d.pachClient,
 			fullInputPath,
@@ -630,6 +792,11 @@ func (d *driver) downloadData(
 		); err != nil {
 			return "", errors.EnsureStack(err)
 		}
+		if statsTree != nil {
+			if err := statsTree.Err(); err != nil {
+				return "", err
+			}
+		}
 	}
 	return scratchPath, nil
 }
@@ -707,6 +874,11 @@ func (d *driver) RunUserCode(
 	ctx := d.pachClient.Ctx()
 	d.reportUserCodeStats(logger)
 	defer func(start time.Time) { d.reportDeferredUserCodeStats(retErr, start, procStats, logger) }(time.Now())
+	if procStats != nil {
+		defer func(dropped int64) {
+			procStats.DroppedLogBytes += uint64(logger.DroppedLogBytes() - dropped)
+		}(logger.DroppedLogBytes())
+	}
 	logger.Logf("beginning to run user code")
 	defer func(start time.Time) {
 		if retErr != nil {
@@ -751,6 +923,11 @@ func (d *driver) RunUserCode(
 	if err != nil {
 		return errors.EnsureStack(err)
 	}
+	if procStats != nil {
+		if peak := peakMemoryBytes(state); peak > procStats.PeakMemoryBytes {
+			procStats.PeakMemoryBytes = peak
+		}
+	}
 	if common.IsDone(ctx) {
 		if err = ctx.Err(); err != nil {
 			return errors.EnsureStack(err)
@@ -969,11 +1146,15 @@ func (d *driver) ReportUploadStats(
 		d.updateCounter(stats.DatumUploadBytesCount, logger, "", func(counter prometheus.Counter) {
 			counter.Add(float64(procStats.UploadBytes))
 		})
+		d.updateCounter(stats.DatumUploadDedupedBytesCount, logger, "", func(counter prometheus.Counter) {
+			counter.Add(float64(procStats.DedupedBytes))
+		})
 	}
 }
 
 func (d *driver) reportDownloadSizeStats(
 	downSize float64,
+	cachedSize float64,
 	logger logs.TaggedLogger,
 ) {
 	if d.exportStats {
@@ -983,6 +1164,9 @@ func (d *driver) reportDownloadSizeStats(
 		d.updateCounter(stats.DatumDownloadBytesCount, logger, "", func(counter prometheus.Counter) {
 			counter.Add(downSize)
 		})
+		d.updateCounter(stats.DatumDownloadCachedBytesCount, logger, "", func(counter prometheus.Counter) {
+			counter.Add(cachedSize)
+		})
 	}
 }
 
@@ -1021,6 +1205,12 @@ func (d *driver) unlinkData(inputs []*common.Input) error {
 	return nil
 }
 
+// objectContext returns a copy of 'ctx' that puts objects in the bucket of
+// the pipeline's residency, under the prefix of the pipeline's tenant.
+func (d *driver) objectContext(ctx context.Context) context.Context {
+	return tenant.WithObjectContext(residency.WithContext(ctx, d.pipelineInfo.Residency), d.pipelineInfo.Tenant)
+}
+
 func (d *driver) UploadOutput(
 	dir string,
 	tag string,
@@ -1028,7 +1218,8 @@ func (d *driver) UploadOutput(
 	inputs []*common.Input,
 	stats *pps.ProcessStats,
 	statsTree *hashtree.Ordered,
-) (retBuffer []byte, retErr error) {
+	datumCache *hashtree.MergeCache,
+) (retErr error) {
 	defer d.ReportUploadStats(time.Now(), stats, logger)
 	logger.Logf("starting to upload output")
 	defer func(start time.Time) {
@@ -1039,22 +1230,55 @@ func (d *driver) UploadOutput(
 		}
 	}(time.Now())
 
-	// Set up client for writing file data
-	putObjsClient, err := d.pachClient.ObjectAPIClient.PutObjects(d.pachClient.Ctx())
+	// Set up client for writing file data, which is stored in the bucket of
+	// the pipeline's residency, under the prefix of the pipeline's tenant
+	putObjsClient, err := d.pachClient.ObjectAPIClient.PutObjects(d.objectContext(d.pachClient.Ctx()))
 	if err != nil {
-		return nil, errors.EnsureStack(err)
+		return errors.EnsureStack(err)
 	}
-	block := &pfs.Block{Hash: uuid.NewWithoutDashes()}
+	block := tenant.NewBlock(d.pipelineInfo.Residency, d.pipelineInfo.Tenant)
 	if err := putObjsClient.Send(&pfs.PutObjectRequest{
 		Block: block,
 	}); err != nil {
-		return nil, errors.EnsureStack(err)
+		return errors.EnsureStack(err)
 	}
 	outputPath := filepath.Join(dir, "out")
+	datumKey := common.DatumKey(inputs)
 	buf := grpcutil.GetBuffer()
 	defer grpcutil.PutBuffer(buf)
 	var offset uint64
-	var tree *hashtree.Ordered
+	// The content uploaded for this datum, which is added to the worker's
+	// output index once the upload succeeds
+	uploaded := make(map[string]*pfs.BlockRef)
+	// The datum's hashtree spills its files to disk, so that datums with
+	// millions of output files don't exhaust the worker's memory
+	tree, err := d.NewOrderedTree("/")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tree.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	// checkTrees returns the error (if any) that adding a node to the datum's
+	// hashtrees caused, e.g. because the hashtree memory limit was exceeded
+	checkTrees := func() error {
+		if err := tree.Err(); err != nil {
+			return err
+		}
+		if statsTree != nil {
+			return statsTree.Err()
+		}
+		return nil
+	}
+	// If the pipeline routes its output, the datum's output is written under
+	// the directory of the branch that it's routed to. Stats and merge
+	// strategies still use the paths that the user code wrote.
+	routeDir := d.outputRoute(inputs)
+	if routeDir != "" {
+		tree.PutDir(routeDir)
+	}
 
 	// Upload all files in output directory
 	if err := filepath.Walk(outputPath, func(filePath string, info os.FileInfo, err error) error {
@@ -1065,7 +1289,6 @@ func (d *driver) UploadOutput(
 			return errors.Errorf("file path is not valid utf-8: %s", filePath)
 		}
 		if filePath == outputPath {
-			tree = hashtree.NewOrdered("/")
 			return nil
 		}
 		relPath, err := filepath.Rel(outputPath, filePath)
@@ -1077,11 +1300,11 @@ func (d *driver) UploadOutput(
 		// TODO(msteffen) write a test pipeline that outputs an empty directory and
 		// make sure it's preserved
 		if info.IsDir() {
-			tree.PutDir(relPath)
+			tree.PutDir(filepath.Join(routeDir, relPath))
 			if statsTree != nil {
 				statsTree.PutDir(relPath)
 			}
-			return nil
+			return checkTrees()
 		}
 		// Under some circumstances, the user might have copied
 		// some pipes from the input directory to the output directory.
@@ -1125,16 +1348,16 @@ func (d *driver) UploadOutput(
 							}
 							subRelPath := filepath.Join(relPath, rel)
 							// The path of the input file
-							pfsPath, err := filepath.Rel(filepath.Join(dir, input.Name), filePath)
+							pfsPath, err := filepath.Rel(filepath.Join(dir, input.Name, input.Split), filePath)
 							if err != nil {
 								return errors.EnsureStack(err)
 							}
 							if info.IsDir() {
-								tree.PutDir(subRelPath)
+								tree.PutDir(filepath.Join(routeDir, subRelPath))
 								if statsTree != nil {
 									statsTree.PutDir(subRelPath)
 								}
-								return nil
+								return checkTrees()
 							}
 							fc := input.FileInfo.File.Commit
 							fileInfo, err := d.pachClient.InspectFile(fc.Repo.Name, fc.ID, pfsPath)
@@ -1151,11 +1374,14 @@ func (d *driver) UploadOutput(
 							}
 							blockRefs = append(blockRefs, fileInfo.BlockRefs...)
 							n := &hashtree.FileNodeProto{BlockRefs: blockRefs}
-							tree.PutFile(subRelPath, fileInfo.Hash, int64(fileInfo.SizeBytes), n)
+							d.setMergeStrategy(n, subRelPath, datumKey, int64(fileInfo.SizeBytes), fileInfo.Hash)
+							tree.PutFile(filepath.Join(routeDir, subRelPath), fileInfo.Hash, int64(fileInfo.SizeBytes), n)
 							if statsTree != nil {
 								statsTree.PutFile(subRelPath, fileInfo.Hash, int64(fileInfo.SizeBytes), n)
 							}
-							return nil
+							stats.OutputFiles++
+							stats.OutputBytes += fileInfo.SizeBytes
+							return checkTrees()
 						})
 					}
 				}
@@ -1177,69 +1403,105 @@ func (d *driver) UploadOutput(
 				retErr = err
 			}
 		}()
-		var size int64
+		// Hash the file before uploading it, so that if its content was
+		// already uploaded, that copy can be referenced instead
 		h := pfs.NewHash()
-		r := io.TeeReader(f, h)
-		// Write local file to object storage block
-		for {
-			n, err := r.Read(buf)
-			if n == 0 && err != nil {
-				if errors.Is(err, io.EOF) {
-					break
-				}
+		size, err := io.CopyBuffer(h, f, buf)
+		if err != nil {
+			return errors.EnsureStack(err)
+		}
+		hash := h.Sum(nil)
+		key := outputKey(hash, size)
+		blockRef := uploaded[key]
+		if blockRef == nil && d.outputIndex != nil {
+			blockRef = d.outputIndex.get(key)
+		}
+		if blockRef != nil && size > 0 {
+			stats.DedupedFiles++
+			stats.DedupedBytes += uint64(size)
+		} else {
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
 				return errors.EnsureStack(err)
 			}
-			if err := putObjsClient.Send(&pfs.PutObjectRequest{
-				Value: buf[:n],
-			}); err != nil {
-				return errors.EnsureStack(err)
+			// Write local file to object storage block
+			var written int64
+			for {
+				n, err := f.Read(buf)
+				if n == 0 && err != nil {
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					return errors.EnsureStack(err)
+				}
+				if err := putObjsClient.Send(&pfs.PutObjectRequest{
+					Value: buf[:n],
+				}); err != nil {
+					return errors.EnsureStack(err)
+				}
+				written += int64(n)
 			}
-			size += int64(n)
-		}
-		n := &hashtree.FileNodeProto{
-			BlockRefs: []*pfs.BlockRef{
-				&pfs.BlockRef{
-					Block: block,
-					Range: &pfs.ByteRange{
-						Lower: offset,
-						Upper: offset + uint64(size),
-					},
+			if written != size {
+				return errors.Errorf("output file %s changed while it was being uploaded", relPath)
+			}
+			blockRef = &pfs.BlockRef{
+				Block: block,
+				Range: &pfs.ByteRange{
+					Lower: offset,
+					Upper: offset + uint64(size),
 				},
-			},
+			}
+			uploaded[key] = blockRef
+			offset += uint64(size)
+			stats.UploadBytes += uint64(size)
 		}
-		hash := h.Sum(nil)
-		tree.PutFile(relPath, hash, size, n)
+		n := &hashtree.FileNodeProto{BlockRefs: []*pfs.BlockRef{blockRef}}
+		d.setMergeStrategy(n, relPath, datumKey, size, hash)
+		tree.PutFile(filepath.Join(routeDir, relPath), hash, size, n)
 		if statsTree != nil {
 			statsTree.PutFile(relPath, hash, size, n)
 		}
-		offset += uint64(size)
-		stats.UploadBytes += uint64(size)
-		return nil
+		stats.OutputFiles++
+		stats.OutputBytes += uint64(size)
+		return checkTrees()
 	}); err != nil {
-		return nil, errors.Wrap(err, "error walking output")
+		return errors.Wrap(err, "error walking output")
 	}
 	if _, err := putObjsClient.CloseAndRecv(); err != nil && !errors.Is(err, io.EOF) {
-		return nil, errors.EnsureStack(err)
-	}
-	// Serialize datum hashtree
-	b := &bytes.Buffer{}
-	if err := tree.Serialize(b); err != nil {
-		return nil, err
+		return errors.EnsureStack(err)
 	}
-	// Write datum hashtree to object storage
-	w, err := d.pachClient.PutObjectAsync([]*pfs.Tag{client.NewTag(tag)})
-	if err != nil {
-		return nil, errors.EnsureStack(err)
+	if d.outputIndex != nil {
+		d.outputIndex.add(uploaded)
 	}
-	defer func() {
-		if err := w.Close(); err != nil && retErr != nil {
-			retErr = errors.EnsureStack(err)
+	// Serialize datum hashtree to disk rather than to memory
+	return d.WithSpillFile(func(f *os.File) error {
+		bufW := bufio.NewWriter(f)
+		if err := tree.Serialize(bufW); err != nil {
+			return err
 		}
-	}()
-	if _, err := w.Write(b.Bytes()); err != nil {
-		return nil, errors.EnsureStack(err)
-	}
-	return b.Bytes(), nil
+		if err := bufW.Flush(); err != nil {
+			return errors.EnsureStack(err)
+		}
+		// Write datum hashtree to object storage
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return errors.EnsureStack(err)
+		}
+		w, err := d.pachClient.PutObjectAsync([]*pfs.Tag{client.NewTag(tag)})
+		if err != nil {
+			return errors.EnsureStack(err)
+		}
+		if _, err := io.CopyBuffer(w, f, buf); err != nil {
+			w.Close()
+			return errors.EnsureStack(err)
+		}
+		if err := w.Close(); err != nil {
+			return errors.EnsureStack(err)
+		}
+		// Cache datum hashtree locally
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return errors.EnsureStack(err)
+		}
+		return datumCache.Put(uuid.NewWithoutDashes(), f)
+	})
 }
 
 func (d *driver) UserCodeEnv(
@@ -1250,7 +1512,12 @@ func (d *driver) UserCodeEnv(
 	result := os.Environ()
 
 	for _, input := range inputs {
-		result = append(result, fmt.Sprintf("%s=%s", input.Name, filepath.Join(d.InputDir(), input.Name, input.FileInfo.File.Path)))
+		if input.VolumePath != "" {
+			// volumes are mounted where the pipeline spec says, and have no commit
+			result = append(result, fmt.Sprintf("%s=%s", input.Name, input.VolumePath))
+			continue
+		}
+		result = append(result, fmt.Sprintf("%s=%s", input.Name, filepath.Join(d.InputDir(), input.Name, input.Split, input.FileInfo.File.Path)))
 		result = append(result, fmt.Sprintf("%s_COMMIT=%s", input.Name, input.FileInfo.File.Commit.ID))
 	}
 
@@ -1280,22 +1547,42 @@ func (d *driver) UserCodeEnv(
 		result = append(result, fmt.Sprintf("%s=%s", client.OutputCommitIDEnv, outputCommit.ID))
 	}
 
+	if d.runtimeConfig != nil {
+		result = append(result, fmt.Sprintf("%s=%s", client.RuntimeConfigEnv, d.runtimeConfig.get()))
+		result = append(result, fmt.Sprintf("%s=%s", client.RuntimeConfigFileEnv, d.runtimeConfig.path))
+	}
+
 	return result
 }
 
-func (d *driver) Egress(commit *pfs.Commit, egressURL string) error {
+func (d *driver) Egress(commit *pfs.Commit, egress *pps.Egress) error {
 	// copy the pach client (preserving auth info) so we can set a different
 	// number of concurrent streams
 	pachClient := d.PachClient().WithCtx(d.PachClient().Ctx())
 	pachClient.SetMaxConcurrentStreams(100)
 
-	url, err := obj.ParseURL(egressURL)
+	url, err := obj.ParseURL(egress.URL)
 	if err != nil {
 		return err
 	}
-	objClient, err := obj.NewClientFromURLAndSecret(url, false)
-	if err != nil {
-		return err
+	var objClient obj.Client
+	if egress.Credentials != nil {
+		creds, err := egressCredentials(egress.Credentials)
+		if err != nil {
+			return err
+		}
+		objClient, err = obj.NewClientFromURLAndCredentials(url, creds, false)
+		if err != nil {
+			return err
+		}
+	} else {
+		objClient, err = obj.NewClientFromURLAndSecret(url, false)
+		if err != nil {
+			return err
+		}
+	}
+	if egress.Manifest {
+		return filesync.PushObjWithManifest(pachClient, commit, objClient, url.Object)
 	}
 	return filesync.PushObj(pachClient, commit, objClient, url.Object)
 }
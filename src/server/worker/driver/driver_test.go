@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -24,6 +25,7 @@ import (
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
 	"github.com/pachyderm/pachyderm/src/client/pkg/require"
 	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
 	"github.com/pachyderm/pachyderm/src/server/pkg/testpachd"
 	tu "github.com/pachyderm/pachyderm/src/server/pkg/testutil"
 	"github.com/pachyderm/pachyderm/src/server/worker/common"
@@ -802,3 +804,86 @@ func TestRunUserCodeWithData(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+func TestUploadOutputDedupe(t *testing.T) {
+	err := withTestEnv(func(env *testEnv) {
+		var uploaded []byte
+		env.MockPachd.Object.PutObjects.Use(func(server pfs.ObjectAPI_PutObjectsServer) error {
+			for {
+				request, err := server.Recv()
+				if errors.Is(err, io.EOF) {
+					return server.SendAndClose(&types.Empty{})
+				}
+				if err != nil {
+					return err
+				}
+				uploaded = append(uploaded, request.Value...)
+			}
+		})
+		env.MockPachd.Object.PutObject.Use(func(server pfs.ObjectAPI_PutObjectServer) error {
+			for {
+				if _, err := server.Recv(); errors.Is(err, io.EOF) {
+					return server.SendAndClose(&pfs.Object{Hash: "datum-tree"})
+				} else if err != nil {
+					return err
+				}
+			}
+		})
+		datumCache, err := hashtree.NewMergeCache(filepath.Join(env.Directory, "datums"))
+		require.NoError(t, err)
+		upload := func(files map[string]string) *pps.ProcessStats {
+			dir, err := ioutil.TempDir(env.Directory, "datum")
+			require.NoError(t, err)
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, "out"), 0777))
+			for name, content := range files {
+				require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "out", name), []byte(content), 0666))
+			}
+			stats := &pps.ProcessStats{}
+			require.NoError(t, env.driver.UploadOutput(dir, tu.UniqueString("datum"), logs.NewMockLogger(), nil, stats, nil, datumCache))
+			return stats
+		}
+
+		// Files with the same content as a file of the same datum aren't
+		// uploaded again
+		stats := upload(map[string]string{"a": "same", "b": "same", "c": "other", "d": ""})
+		require.Equal(t, "sameother", string(uploaded))
+		require.Equal(t, uint64(9), stats.UploadBytes)
+		require.Equal(t, uint64(1), stats.DedupedFiles)
+		require.Equal(t, uint64(4), stats.DedupedBytes)
+		require.Equal(t, uint64(4), stats.OutputFiles)
+		require.Equal(t, uint64(13), stats.OutputBytes)
+
+		// Nor are files with the same content as a file of an earlier datum
+		uploaded = nil
+		stats = upload(map[string]string{"a": "same", "b": "new"})
+		require.Equal(t, "new", string(uploaded))
+		require.Equal(t, uint64(3), stats.UploadBytes)
+		require.Equal(t, uint64(1), stats.DedupedFiles)
+		require.Equal(t, uint64(4), stats.DedupedBytes)
+	})
+	require.NoError(t, err)
+}
+
+// Test that the user code output dropped by the pipeline's log quota is
+// counted in the datum's ProcessStats
+func TestRunUserCodeLogQuota(t *testing.T) {
+	t.Parallel()
+	err := withTestEnv(func(env *testEnv) {
+		env.driver.pipelineInfo.Transform.Cmd = []string{"bash", "-c", "for i in 1 2 3 4; do echo aaaa; done"}
+		env.driver.pipelineInfo.LogQuota = &pps.LogQuota{DatumBytes: 10}
+		env.driver.pipelineInfo.Transform.WorkingDir = ""
+		require.NoError(t, os.MkdirAll(env.driver.rootDir, 0777))
+		logger := logs.NewStatlessLogger(env.driver.pipelineInfo).WithJob("job").WithData(nil)
+
+		procStats := &pps.ProcessStats{}
+		require.NoError(t, env.driver.RunUserCode(logger, []string{}, procStats, nil))
+		require.Equal(t, uint64(10), procStats.DroppedLogBytes)
+
+		// Only the bytes dropped by this run are counted, not those already
+		// dropped for the datum
+		procStats = &pps.ProcessStats{}
+		require.NoError(t, env.driver.RunUserCode(logger, []string{}, procStats, nil))
+		require.Equal(t, uint64(20), procStats.DroppedLogBytes)
+	})
+	require.NoError(t, err)
+}
@@ -18,6 +18,15 @@ func createSpoutFifo(path string) error {
 	return syscall.Mkfifo(path, 0666)
 }
 
+// peakMemoryBytes returns the peak resident set size of the exited process
+// described by 'state' (which linux reports in KiB).
+func peakMemoryBytes(state *os.ProcessState) uint64 {
+	if rusage, ok := state.SysUsage().(*syscall.Rusage); ok && rusage.Maxrss > 0 {
+		return uint64(rusage.Maxrss) * 1024
+	}
+	return 0
+}
+
 func makeCmdCredentials(uid uint32, gid uint32) *syscall.SysProcAttr {
 	return &syscall.SysProcAttr{
 		Credential: &syscall.Credential{
@@ -108,8 +117,8 @@ func (d *driver) linkData(inputs []*common.Input, dir string) error {
 	}
 
 	for _, input := range inputs {
-		if input.S3 {
-			continue // S3 data is not downloaded
+		if input.S3 || input.VolumePath != "" {
+			continue // S3 and volume data is not downloaded
 		}
 		if input.Name == "" {
 			return errors.New("input does not have a name")
@@ -136,5 +145,12 @@ func (d *driver) linkData(inputs []*common.Input, dir string) error {
 		}
 	}
 
+	// Expose the datum's restored checkpoint, if it has one
+	if _, err := os.Stat(filepath.Join(dir, CheckpointDir)); err == nil {
+		if err := os.Symlink(filepath.Join(dir, CheckpointDir), filepath.Join(d.InputDir(), CheckpointDir)); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -19,6 +19,10 @@ func makeCmdCredentials(uid uint32, gid uint32) *syscall.SysProcAttr {
 	return nil
 }
 
+func peakMemoryBytes(state *os.ProcessState) uint64 {
+	return 0
+}
+
 // Note: this function only exists for tests, the real system uses a fifo for
 // this (which does not exist in the normal filesystem on Windows)
 func createSpoutFifo(path string) error {
@@ -56,7 +60,7 @@ func (d *driver) moveData(inputs []*common.Input, dir string) error {
 	}
 
 	for _, input := range inputs {
-		if input.S3 {
+		if input.S3 || input.VolumePath != "" {
 			continue
 		}
 		src := filepath.Join(dir, input.Name)
@@ -74,6 +78,12 @@ func (d *driver) moveData(inputs []*common.Input, dir string) error {
 		}
 	}
 
+	if _, err := os.Stat(filepath.Join(dir, CheckpointDir)); err == nil {
+		if err := os.Rename(filepath.Join(dir, CheckpointDir), filepath.Join(d.InputDir(), CheckpointDir)); err != nil {
+			return err
+		}
+	}
+
 	return os.Rename(filepath.Join(dir, "out"), filepath.Join(d.InputDir(), "out"))
 }
 
@@ -0,0 +1,53 @@
+package driver
+
+import (
+	"fmt"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// egressCredentials reads the credentials that 'creds' refers to. They're
+// read anew for each egress, so that rotated credentials are picked up.
+func egressCredentials(creds *pps.EgressCredentials) (map[string]string, error) {
+	if creds.Secret != "" {
+		return obj.ReadCredentialsDir(client.PPSEgressSecretPath)
+	}
+	// The vault client reads VAULT_ADDR and VAULT_TOKEN from the environment
+	vaultClient, err := vault.NewClient(vault.DefaultConfig())
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not create vault client")
+	}
+	secret, err := vaultClient.Logical().Read(creds.VaultPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read egress credentials from vault")
+	}
+	if secret == nil {
+		return nil, errors.Errorf("no egress credentials found at vault path %q", creds.VaultPath)
+	}
+	return vaultCredentials(secret.Data)
+}
+
+// vaultCredentials converts the data in a vault secret to credentials. Secrets
+// in version 2 of vault's KV engine nest their data under "data".
+func vaultCredentials(data map[string]interface{}) (map[string]string, error) {
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+	creds := make(map[string]string)
+	for key, value := range data {
+		switch value := value.(type) {
+		case string:
+			creds[key] = value
+		case fmt.Stringer:
+			creds[key] = value.String()
+		default:
+			return nil, errors.Errorf("egress credential %q in vault is a %T, not a string", key, value)
+		}
+	}
+	return creds, nil
+}
@@ -0,0 +1,28 @@
+package driver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestVaultCredentials(t *testing.T) {
+	creds, err := vaultCredentials(map[string]interface{}{
+		"amazon-id":     "id",
+		"amazon-secret": "secret",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"amazon-id": "id", "amazon-secret": "secret"}, creds)
+
+	// Secrets in vault's KV v2 engine nest their data
+	creds, err = vaultCredentials(map[string]interface{}{
+		"data":     map[string]interface{}{"google-cred": "{}"},
+		"metadata": map[string]interface{}{"version": json.Number("2")},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"google-cred": "{}"}, creds)
+
+	_, err = vaultCredentials(map[string]interface{}{"amazon-id": []interface{}{"id"}})
+	require.YesError(t, err)
+}
@@ -0,0 +1,54 @@
+package driver
+
+import (
+	"fmt"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+)
+
+// maxDedupedOutputs is the number of distinct output files whose uploaded
+// content a worker remembers.
+const maxDedupedOutputs = 100000
+
+// outputIndex remembers where the content of the output files that a worker
+// uploaded is stored, so that output files with the same content (e.g. the
+// empty results of many datums) reference that copy rather than being
+// uploaded again.
+//
+// The blocks it refers to stay valid for as long as the worker runs, as
+// garbage collection requires that every pipeline's workers are stopped.
+type outputIndex struct {
+	cache *lru.Cache
+}
+
+func newOutputIndex(size int) (*outputIndex, error) {
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	return &outputIndex{cache: cache}, nil
+}
+
+// outputKey identifies the content of a file by its hash and size.
+func outputKey(hash []byte, size int64) string {
+	return fmt.Sprintf("%x:%d", hash, size)
+}
+
+// get returns where content with 'key' is stored, or nil if it isn't known.
+func (i *outputIndex) get(key string) *pfs.BlockRef {
+	value, ok := i.cache.Get(key)
+	if !ok {
+		return nil
+	}
+	return value.(*pfs.BlockRef)
+}
+
+// add records where the content of the files in 'refs', which must have been
+// uploaded successfully, is stored.
+func (i *outputIndex) add(refs map[string]*pfs.BlockRef) {
+	for key, ref := range refs {
+		i.cache.Add(key, ref)
+	}
+}
@@ -0,0 +1,63 @@
+package driver
+
+import (
+	"path"
+
+	glob "github.com/pachyderm/ohmyglob"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
+)
+
+// outputMerge is a pps.OutputMerge with its glob compiled
+type outputMerge struct {
+	glob     *glob.Glob
+	strategy hashtree.MergeStrategy
+}
+
+var mergeStrategies = map[pps.OutputMerge_Strategy]hashtree.MergeStrategy{
+	pps.OutputMerge_CONCAT:                 hashtree.MergeStrategy_CONCAT,
+	pps.OutputMerge_CONCAT_SORTED_BY_DATUM: hashtree.MergeStrategy_CONCAT_SORTED,
+	pps.OutputMerge_LAST_WRITER_WINS:       hashtree.MergeStrategy_LAST_WRITER_WINS,
+	pps.OutputMerge_ERROR_ON_CONFLICT:      hashtree.MergeStrategy_ERROR_ON_CONFLICT,
+}
+
+func compileOutputMerges(outputMerges []*pps.OutputMerge) ([]*outputMerge, error) {
+	var result []*outputMerge
+	for _, m := range outputMerges {
+		g, err := glob.Compile(path.Clean("/"+m.Glob), '/')
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not compile output merge glob %q", m.Glob)
+		}
+		strategy, ok := mergeStrategies[m.Strategy]
+		if !ok {
+			return nil, errors.Errorf("unknown output merge strategy %v", m.Strategy)
+		}
+		result = append(result, &outputMerge{glob: g, strategy: strategy})
+	}
+	return result, nil
+}
+
+// setMergeStrategy sets the merge strategy of the output file at 'relPath',
+// whose node is 'n', if the pipeline declares one. The file's content, of
+// 'size' bytes with hash 'hash', is recorded as written by the datum with the
+// key 'datumKey'.
+func (d *driver) setMergeStrategy(n *hashtree.FileNodeProto, relPath string, datumKey string, size int64, hash []byte) {
+	p := path.Clean("/" + relPath)
+	for _, m := range d.outputMerges {
+		if !m.glob.Match(p) {
+			continue
+		}
+		if m.strategy != hashtree.MergeStrategy_CONCAT {
+			n.MergeStrategy = m.strategy
+			n.DatumSegments = []*hashtree.DatumSegment{{
+				Datum:     datumKey,
+				NumRefs:   int64(len(n.Objects) + len(n.BlockRefs)),
+				SizeBytes: size,
+				Hash:      hash,
+			}}
+		}
+		return
+	}
+}
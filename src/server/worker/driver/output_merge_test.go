@@ -0,0 +1,39 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
+)
+
+func TestSetMergeStrategy(t *testing.T) {
+	outputMerges, err := compileOutputMerges([]*pps.OutputMerge{
+		{Glob: "/summary.csv", Strategy: pps.OutputMerge_CONCAT_SORTED_BY_DATUM},
+		{Glob: "/logs/*", Strategy: pps.OutputMerge_CONCAT},
+		{Glob: "/**", Strategy: pps.OutputMerge_ERROR_ON_CONFLICT},
+	})
+	require.NoError(t, err)
+	d := &driver{outputMerges: outputMerges}
+	node := func(relPath string) *hashtree.FileNodeProto {
+		n := &hashtree.FileNodeProto{BlockRefs: []*pfs.BlockRef{{}}}
+		d.setMergeStrategy(n, relPath, "in:/a", 3, []byte("h"))
+		return n
+	}
+
+	n := node("summary.csv")
+	require.Equal(t, hashtree.MergeStrategy_CONCAT_SORTED, n.MergeStrategy)
+	require.Equal(t, []*hashtree.DatumSegment{{Datum: "in:/a", NumRefs: 1, SizeBytes: 3, Hash: []byte("h")}}, n.DatumSegments)
+
+	// The first matching output merge is used, and CONCAT files aren't
+	// annotated
+	n = node("logs/worker-1")
+	require.Equal(t, hashtree.MergeStrategy_CONCAT, n.MergeStrategy)
+	require.Equal(t, 0, len(n.DatumSegments))
+	require.Equal(t, hashtree.MergeStrategy_ERROR_ON_CONFLICT, node("results/a.json").MergeStrategy)
+
+	_, err = compileOutputMerges([]*pps.OutputMerge{{Glob: "/[", Strategy: pps.OutputMerge_LAST_WRITER_WINS}})
+	require.YesError(t, err)
+}
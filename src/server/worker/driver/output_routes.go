@@ -0,0 +1,46 @@
+package driver
+
+import (
+	"path"
+
+	glob "github.com/pachyderm/ohmyglob"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/worker/common"
+)
+
+// outputRoute is a pps.OutputRoute with its glob compiled
+type outputRoute struct {
+	glob   *glob.Glob
+	branch string
+}
+
+func compileOutputRoutes(outputRoutes []*pps.OutputRoute) ([]*outputRoute, error) {
+	var result []*outputRoute
+	for _, r := range outputRoutes {
+		g, err := glob.Compile(path.Clean("/"+r.Glob), '/')
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not compile output route glob %q", r.Glob)
+		}
+		result = append(result, &outputRoute{glob: g, branch: r.Branch})
+	}
+	return result, nil
+}
+
+// outputRoute returns the directory of the routed branch that the output of
+// the datum made of 'inputs' is written to, which is named after the branch
+// that the datum is routed to, or "" if the pipeline doesn't route its output.
+func (d *driver) outputRoute(inputs []*common.Input) string {
+	if len(d.outputRoutes) == 0 {
+		return ""
+	}
+	for _, r := range d.outputRoutes {
+		for _, input := range inputs {
+			if r.glob.Match(path.Clean("/" + input.FileInfo.File.Path)) {
+				return "/" + r.branch
+			}
+		}
+	}
+	return "/" + d.pipelineInfo.OutputBranch
+}
@@ -0,0 +1,45 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/worker/common"
+)
+
+func TestOutputRoute(t *testing.T) {
+	outputRoutes, err := compileOutputRoutes([]*pps.OutputRoute{
+		{Branch: "staging", Glob: "/new/*"},
+		{Branch: "review", Glob: "/**.tmp"},
+	})
+	require.NoError(t, err)
+	d := &driver{
+		pipelineInfo: &pps.PipelineInfo{OutputBranch: "master"},
+		outputRoutes: outputRoutes,
+	}
+	inputs := func(paths ...string) []*common.Input {
+		var result []*common.Input
+		for _, p := range paths {
+			result = append(result, &common.Input{
+				FileInfo: &pfs.FileInfo{File: client.NewFile("in", "master", p)},
+			})
+		}
+		return result
+	}
+	require.Equal(t, "/staging", d.outputRoute(inputs("new/a")))
+	require.Equal(t, "/review", d.outputRoute(inputs("/old/a.tmp")))
+	// The first matching route is used, whichever input matches it
+	require.Equal(t, "/staging", d.outputRoute(inputs("/old/a.tmp", "/new/b")))
+	// Datums that match no route are routed to the output branch
+	require.Equal(t, "/master", d.outputRoute(inputs("/old/a")))
+
+	// Pipelines without routes don't route their output
+	d.outputRoutes = nil
+	require.Equal(t, "", d.outputRoute(inputs("/new/a")))
+
+	_, err = compileOutputRoutes([]*pps.OutputRoute{{Branch: "staging", Glob: "/["}})
+	require.YesError(t, err)
+}
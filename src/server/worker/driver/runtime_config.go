@@ -0,0 +1,80 @@
+package driver
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/watch"
+)
+
+// runtimeConfigFile is the name of the file, in the worker's hashtree
+// directory, that contains the pipeline's current runtime config
+const runtimeConfigFile = "runtime_config.json"
+
+// runtimeConfig is the pipeline's current runtime config, which may be
+// updated while the worker is running. It's shared by all of the copies of a
+// driver.
+type runtimeConfig struct {
+	mu   sync.Mutex
+	data []byte
+	// The file that the config is written to, for user code to read
+	path string
+}
+
+// newRuntimeConfig returns a runtimeConfig whose initial value is 'config',
+// and writes it to 'path'
+func newRuntimeConfig(path string, config map[string]string) (*runtimeConfig, error) {
+	r := &runtimeConfig{path: path}
+	if err := r.set(config); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// get returns the current config, as JSON
+func (r *runtimeConfig) get() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return string(r.data)
+}
+
+// set replaces the current config with 'config'. The config file is replaced
+// atomically, so user code that reads it never sees a partial config.
+func (r *runtimeConfig) set(config map[string]string) error {
+	if config == nil {
+		config = make(map[string]string)
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return errors.EnsureStack(err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tmpPath := r.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0644); err != nil {
+		return errors.EnsureStack(err)
+	}
+	if err := os.Rename(tmpPath, r.path); err != nil {
+		return errors.EnsureStack(err)
+	}
+	r.data = data
+	return nil
+}
+
+func (d *driver) WatchRuntimeConfig() error {
+	return d.pipelines.ReadOnly(d.pachClient.Ctx()).WatchOneF(d.pipelineInfo.Pipeline.Name, func(e *watch.Event) error {
+		if e.Type != watch.EventPut {
+			return nil
+		}
+		var key string
+		pipelinePtr := &pps.EtcdPipelineInfo{}
+		if err := e.Unmarshal(&key, pipelinePtr); err != nil {
+			return err
+		}
+		return d.runtimeConfig.set(pipelinePtr.RuntimeConfig)
+	})
+}
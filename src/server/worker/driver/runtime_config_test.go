@@ -0,0 +1,37 @@
+package driver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestRuntimeConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "runtime_config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, runtimeConfigFile)
+
+	// A pipeline without a runtime config gets an empty one
+	r, err := newRuntimeConfig(path, nil)
+	require.NoError(t, err)
+	require.Equal(t, "{}", r.get())
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(data))
+
+	require.NoError(t, r.set(map[string]string{"threshold": "0.9"}))
+	require.Equal(t, `{"threshold":"0.9"}`, r.get())
+	data, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, `{"threshold":"0.9"}`, string(data))
+
+	d := &driver{runtimeConfig: r}
+	env := d.UserCodeEnv("", nil, nil)
+	require.OneOfEquals(t, client.RuntimeConfigEnv+`={"threshold":"0.9"}`, env)
+	require.OneOfEquals(t, client.RuntimeConfigFileEnv+"="+path, env)
+}
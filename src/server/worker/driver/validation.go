@@ -0,0 +1,62 @@
+package driver
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/validation"
+	"github.com/pachyderm/pachyderm/src/server/worker/common"
+	"github.com/pachyderm/pachyderm/src/server/worker/logs"
+)
+
+// RunValidation checks the pipeline's assertions against its (single) input,
+// and writes the report to the datum's output. Failed assertions don't fail
+// the datum; they're counted in 'procStats', so that the job's output commit
+// can be marked as having failed validation. Errors reading the input do fail
+// the datum, so that it's retried.
+func (d *driver) RunValidation(
+	logger logs.TaggedLogger,
+	inputs []*common.Input,
+	procStats *pps.ProcessStats,
+) (retErr error) {
+	logger.Logf("beginning to run validation")
+	defer func(start time.Time) {
+		if retErr != nil {
+			logger.Logf("errored running validation after %v: %v", time.Since(start), retErr)
+		} else {
+			logger.Logf("finished running validation after %v", time.Since(start))
+		}
+	}(time.Now())
+
+	var root string
+	for _, input := range inputs {
+		if !input.S3 && input.VolumePath == "" {
+			root = filepath.Join(d.InputDir(), input.Name)
+		}
+	}
+	if root == "" {
+		return errors.New("validation pipeline has no input to validate")
+	}
+	report, err := validation.Validate(d.PipelineInfo().Validation, root)
+	if err != nil {
+		return err
+	}
+	for _, assertion := range report.Assertions {
+		if !assertion.Passed {
+			logger.Logf("assertion %q failed: %s", assertion.Name, assertion.Message)
+		}
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.EnsureStack(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(d.InputDir(), "out", validation.ReportFile), data, 0666); err != nil {
+		return errors.EnsureStack(err)
+	}
+	procStats.AssertionsFailed += uint64(report.Failed())
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
@@ -18,6 +19,8 @@ import (
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
 	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/residency"
+	"github.com/pachyderm/pachyderm/src/client/pkg/tenant"
 	"github.com/pachyderm/pachyderm/src/client/pps"
 	"github.com/pachyderm/pachyderm/src/server/worker/common"
 )
@@ -51,6 +54,11 @@ type TaggedLogger interface {
 
 	JobID() string
 
+	// DroppedLogBytes returns the number of bytes of user code output that
+	// have been dropped by the pipeline's log quota for the logger's current
+	// datum (or job, if the logger isn't tagged with a datum).
+	DroppedLogBytes() int64
+
 	// Close will flush any writes to object storage and return information about
 	// where log statements were stored in object storage.
 	Close() (*pfs.Object, int64, error)
@@ -58,8 +66,18 @@ type TaggedLogger interface {
 
 type taggedLogger struct {
 	template  pps.LogMessage
+	stdout    io.Writer
 	stderrLog *log.Logger
 	marshaler *jsonpb.Marshaler
+	// Used to compute the IDs of the datums that the logger is tagged with
+	salt string
+
+	// Used for enforcing the pipeline's log quota (if it has one) on user code
+	// output. 'quota' is the quota of the current datum (or job, if the logger
+	// isn't tagged with a datum).
+	logQuota *pps.LogQuota
+	jobQuota *logQuota
+	quota    *logQuota
 
 	// Used for mirroring log statements to object storage
 	putObjClient pfs.ObjectAPI_PutObjectClient
@@ -70,9 +88,12 @@ type taggedLogger struct {
 }
 
 func newLogger(pipelineInfo *pps.PipelineInfo) *taggedLogger {
-	name := ""
+	name, salt := "", ""
+	var quota *pps.LogQuota
 	if pipelineInfo != nil {
 		name = pipelineInfo.Pipeline.Name
+		salt = pipelineInfo.Salt
+		quota = pipelineInfo.LogQuota
 	}
 
 	return &taggedLogger{
@@ -80,8 +101,11 @@ func newLogger(pipelineInfo *pps.PipelineInfo) *taggedLogger {
 			PipelineName: name,
 			WorkerID:     os.Getenv(client.PPSPodNameEnv),
 		},
+		stdout:    os.Stdout,
 		stderrLog: log.New(os.Stderr, "", log.LstdFlags|log.Llongfile),
 		marshaler: &jsonpb.Marshaler{},
+		salt:      salt,
+		logQuota:  quota,
 		msgCh:     make(chan string, logBuffer),
 	}
 }
@@ -103,7 +127,10 @@ func NewLogger(pipelineInfo *pps.PipelineInfo, pachClient *client.APIClient) (Ta
 	result := newLogger(pipelineInfo)
 
 	if pachClient != nil && pipelineInfo.EnableStats {
-		putObjClient, err := pachClient.ObjectAPIClient.PutObject(pachClient.Ctx())
+		// User code may log its data, so logs are stored in the bucket of the
+		// pipeline's residency, under the prefix of the pipeline's tenant
+		ctx := tenant.WithObjectContext(residency.WithContext(pachClient.Ctx(), pipelineInfo.Residency), pipelineInfo.Tenant)
+		putObjClient, err := pachClient.ObjectAPIClient.PutObject(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -148,6 +175,13 @@ func NewMasterLogger(pipelineInfo *pps.PipelineInfo) TaggedLogger {
 func (logger *taggedLogger) WithJob(jobID string) TaggedLogger {
 	result := logger.clone()
 	result.template.JobID = jobID
+	if result.logQuota != nil {
+		result.jobQuota = &logQuota{
+			limit: result.logQuota.JobBytes,
+			kind:  "job",
+		}
+		result.quota = result.jobQuota
+	}
 	return result
 }
 
@@ -166,7 +200,14 @@ func (logger *taggedLogger) WithData(data []*common.Input) TaggedLogger {
 	}
 
 	// This is the same ID used in the stats tree for the datum
-	result.template.DatumID = common.DatumID(data)
+	result.template.DatumID = common.DatumID(logger.salt, data)
+	if result.logQuota != nil {
+		result.quota = &logQuota{
+			limit:  result.logQuota.DatumBytes,
+			kind:   "datum",
+			parent: result.jobQuota,
+		}
+	}
 	return result
 }
 
@@ -186,11 +227,27 @@ func (logger *taggedLogger) JobID() string {
 	return logger.template.JobID
 }
 
+// DroppedLogBytes returns the number of bytes of user code output that have
+// been dropped by the log quota of the logger's current datum or job.
+func (logger *taggedLogger) DroppedLogBytes() int64 {
+	if logger.quota == nil {
+		return 0
+	}
+	logger.quota.mu.Lock()
+	defer logger.quota.mu.Unlock()
+	return logger.quota.dropped
+}
+
 func (logger *taggedLogger) clone() *taggedLogger {
 	return &taggedLogger{
-		template:     logger.template,  // Copy struct
+		template:     logger.template, // Copy struct
+		stdout:       logger.stdout,
 		stderrLog:    logger.stderrLog, // logger should be goroutine-safe
 		marshaler:    &jsonpb.Marshaler{},
+		salt:         logger.salt,
+		logQuota:     logger.logQuota,
+		jobQuota:     logger.jobQuota, // quotas are goroutine-safe
+		quota:        logger.quota,
 		putObjClient: logger.putObjClient,
 		msgCh:        logger.msgCh,
 	}
@@ -213,7 +270,7 @@ func (logger *taggedLogger) Logf(formatString string, args ...interface{}) {
 		logger.Errf("could not marshal %v for logging: %s\n", &logger.template, err)
 		return
 	}
-	fmt.Println(msg)
+	fmt.Fprintln(logger.stdout, msg)
 	if logger.putObjClient != nil {
 		logger.msgCh <- msg + "\n"
 	}
@@ -256,6 +313,14 @@ func (logger *taggedLogger) Write(p []byte) (_ int, retErr error) {
 			// the only error bufio.Reader can return when using a buffer.
 			return 0, errors.Wrap(err, "ReadString")
 		}
+		if logger.quota != nil {
+			if ok, marker := logger.quota.admit(int64(len(message))); !ok {
+				if marker != "" {
+					logger.Logf("%s", marker)
+				}
+				continue
+			}
+		}
 		// We don't want to make this call as:
 		// logger.Logf(message)
 		// because if the message has format characters like %s in it those
@@ -264,6 +329,61 @@ func (logger *taggedLogger) Write(p []byte) (_ int, retErr error) {
 	}
 }
 
+// logQuota counts the bytes of user code output logged for a datum or job
+// against the corresponding limit in the pipeline's LogQuota. A logQuota is
+// shared by every logger cloned from the logger that created it (e.g. the
+// stdout and stderr loggers used for a datum), so it's goroutine-safe.
+type logQuota struct {
+	mu        sync.Mutex
+	limit     int64 // 0 means no limit
+	kind      string
+	used      int64
+	dropped   int64
+	truncated bool
+
+	// parent is the job quota, for datum quotas. Output must fit in both the
+	// datum's quota and the job's quota to be logged.
+	parent *logQuota
+}
+
+// admit counts 'n' bytes of output against the quota (and its parent), and
+// returns true if they fit. If they don't, they're counted as dropped instead,
+// and the first time that happens 'marker' is set to a message that should be
+// logged in place of the dropped output.
+func (q *logQuota) admit(n int64) (ok bool, marker string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	limit, kind := q.limit, q.kind
+	fits := limit == 0 || q.used+n <= limit
+	if fits && q.parent != nil {
+		if fits = q.parent.reserve(n); !fits {
+			limit, kind = q.parent.limit, q.parent.kind
+		}
+	}
+	if fits {
+		q.used += n
+		return true, ""
+	}
+	q.dropped += n
+	if !q.truncated {
+		q.truncated = true
+		marker = fmt.Sprintf("log quota of %d bytes per %s exceeded; dropping "+
+			"further output from this %s (see the job's dropped log bytes)", limit, kind, q.kind)
+	}
+	return false, marker
+}
+
+// reserve counts 'n' bytes of output against a parent quota, if they fit.
+func (q *logQuota) reserve(n int64) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.limit != 0 && q.used+n > q.limit {
+		return false
+	}
+	q.used += n
+	return true
+}
+
 // Close flushes and closes the object storage client used to mirror log
 // statements to object storage.  Returns a pointer to the generated pfs.Object
 // as well as the total size of all written messages.
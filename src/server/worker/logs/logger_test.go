@@ -0,0 +1,128 @@
+package logs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/jsonpb"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/worker/common"
+)
+
+// newQuotaLogger returns a job logger for a pipeline with the given log quota,
+// whose log messages are written to 'out'.
+func newQuotaLogger(out *bytes.Buffer, quota *pps.LogQuota) TaggedLogger {
+	logger := newLogger(&pps.PipelineInfo{
+		Pipeline: client.NewPipeline("pipeline"),
+		LogQuota: quota,
+	})
+	logger.stdout = out
+	return logger.WithJob("job")
+}
+
+// userMessages returns the messages logged to 'out', and how many of them were
+// truncation markers.
+func userMessages(t *testing.T, out *bytes.Buffer) (messages []string, markers int) {
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		msg := &pps.LogMessage{}
+		require.NoError(t, jsonpb.UnmarshalString(line, msg))
+		if strings.HasPrefix(msg.Message, "log quota of") {
+			markers++
+			continue
+		}
+		messages = append(messages, msg.Message)
+	}
+	return messages, markers
+}
+
+func datum(path string) []*common.Input {
+	return []*common.Input{{
+		FileInfo: &pfs.FileInfo{File: client.NewFile("repo", "commit", path)},
+		Name:     "repo",
+	}}
+}
+
+func TestLogQuotaDatumLimit(t *testing.T) {
+	out := &bytes.Buffer{}
+	logger := newQuotaLogger(out, &pps.LogQuota{DatumBytes: 10, JobBytes: 100})
+
+	// "aaaa\n" is 5 bytes, so each datum logs two lines and drops the rest
+	datumLogger := logger.WithData(datum("a"))
+	_, err := datumLogger.Write([]byte("aaaa\naaaa\naaaa\naaaa\n"))
+	require.NoError(t, err)
+	require.Equal(t, int64(10), datumLogger.DroppedLogBytes())
+
+	// The next datum has its own quota
+	datumLogger = logger.WithData(datum("b"))
+	_, err = datumLogger.Write([]byte("bbbb\nbbbb\nbbbb\n"))
+	require.NoError(t, err)
+	require.Equal(t, int64(5), datumLogger.DroppedLogBytes())
+
+	messages, markers := userMessages(t, out)
+	require.Equal(t, []string{"aaaa", "aaaa", "bbbb", "bbbb"}, messages)
+	require.Equal(t, 2, markers)
+}
+
+func TestLogQuotaJobLimit(t *testing.T) {
+	out := &bytes.Buffer{}
+	logger := newQuotaLogger(out, &pps.LogQuota{DatumBytes: 100, JobBytes: 15})
+
+	datumLogger := logger.WithData(datum("a"))
+	_, err := datumLogger.Write([]byte("aaaa\naaaa\n"))
+	require.NoError(t, err)
+	require.Equal(t, int64(0), datumLogger.DroppedLogBytes())
+
+	// Only one more line fits in the job's quota, even though the new datum's
+	// quota is nowhere near its limit
+	datumLogger = logger.WithData(datum("b"))
+	_, err = datumLogger.Write([]byte("bbbb\nbbbb\nbbbb\n"))
+	require.NoError(t, err)
+	require.Equal(t, int64(10), datumLogger.DroppedLogBytes())
+
+	messages, markers := userMessages(t, out)
+	require.Equal(t, []string{"aaaa", "aaaa", "bbbb"}, messages)
+	require.Equal(t, 1, markers)
+	require.True(t, strings.Contains(out.String(), "per job"))
+}
+
+func TestLogQuotaMarkerLoggedOnce(t *testing.T) {
+	out := &bytes.Buffer{}
+	logger := newQuotaLogger(out, &pps.LogQuota{DatumBytes: 5})
+
+	// stdout and stderr are written through separate clones of the datum's
+	// logger, which share its quota
+	datumLogger := logger.WithData(datum("a"))
+	stdout, stderr := datumLogger.WithUserCode(), datumLogger.WithUserCode()
+	for i := 0; i < 10; i++ {
+		_, err := stdout.Write([]byte("out!\n"))
+		require.NoError(t, err)
+		_, err = stderr.Write([]byte("err!\n"))
+		require.NoError(t, err)
+	}
+	require.Equal(t, int64(95), datumLogger.DroppedLogBytes())
+
+	messages, markers := userMessages(t, out)
+	require.Equal(t, []string{"out!"}, messages)
+	require.Equal(t, 1, markers)
+}
+
+func TestLogQuotaUnlimited(t *testing.T) {
+	out := &bytes.Buffer{}
+	logger := newQuotaLogger(out, nil)
+	datumLogger := logger.WithData(datum("a"))
+	_, err := datumLogger.Write([]byte(strings.Repeat("aaaa\n", 100)))
+	require.NoError(t, err)
+	require.Equal(t, int64(0), datumLogger.DroppedLogBytes())
+
+	messages, markers := userMessages(t, out)
+	require.Equal(t, 100, len(messages))
+	require.Equal(t, 0, markers)
+}
@@ -114,6 +114,11 @@ func (ml *MockLogger) JobID() string {
 	return ml.Job
 }
 
+// DroppedLogBytes always returns 0, as MockLogger doesn't enforce log quotas.
+func (ml *MockLogger) DroppedLogBytes() int64 {
+	return 0
+}
+
 // Close is meant to be called to flush logs to object storage and return the
 // generated object, but this behavior is not implemented in MockLogger.
 func (ml *MockLogger) Close() (*pfs.Object, int64, error) {
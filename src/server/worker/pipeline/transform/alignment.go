@@ -0,0 +1,85 @@
+package transform
+
+import (
+	"fmt"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+)
+
+// checkAlignment returns "" if the input commits of 'commitInfo', an output
+// commit, satisfy the pipeline's alignment (if any). Otherwise, it returns why
+// they don't.
+func checkAlignment(pachClient *client.APIClient, pipelineInfo *pps.PipelineInfo, commitInfo *pfs.CommitInfo) (string, error) {
+	alignment := pipelineInfo.Alignment
+	if alignment == nil {
+		return "", nil
+	}
+	inputs, _ := ppsutil.AlignedInputs(alignment, ppsutil.JobInput(pipelineInfo, commitInfo))
+	var first *pps.PFSInput
+	var value string
+	for _, input := range inputs {
+		if input.Commit == "" {
+			return fmt.Sprintf("input %q has no commits", input.Name), nil
+		}
+		inputCommitInfo, err := pachClient.InspectCommit(input.Repo, input.Commit)
+		if err != nil {
+			return "", err
+		}
+		v, ok := inputCommitInfo.Metadata[alignment.MetadataKey]
+		if !ok {
+			return fmt.Sprintf("commit %s@%s of input %q has no %q metadata", input.Repo, input.Commit, input.Name, alignment.MetadataKey), nil
+		}
+		if first == nil {
+			first, value = input, v
+		} else if v != value {
+			return fmt.Sprintf("inputs %q and %q have different %q metadata (%q and %q)", first.Name, input.Name, alignment.MetadataKey, value, v), nil
+		}
+	}
+	return "", nil
+}
+
+// skipUnalignedCommit finishes 'commitInfo', an output commit whose inputs
+// aren't aligned, without running a job for it. It keeps the output of the
+// last output commit that has any, so that the output repo doesn't change.
+func skipUnalignedCommit(pachClient *client.APIClient, commitInfo *pfs.CommitInfo, statsCommit *pfs.Commit, reason string) error {
+	request := &pfs.FinishCommitRequest{
+		Commit:      commitInfo.Commit,
+		Description: fmt.Sprintf("no job was run because the inputs aren't aligned: %s", reason),
+		Empty:       true,
+	}
+	// The previous output commit's job may still be running, so wait for it to
+	// finish rather than finishing it (as getParentCommitInfo would)
+	for parent := commitInfo.ParentCommit; parent != nil; {
+		parentCommitInfo, err := pachClient.PfsAPIClient.InspectCommit(pachClient.Ctx(), &pfs.InspectCommitRequest{
+			Commit:     parent,
+			BlockState: pfs.CommitState_FINISHED,
+		})
+		if err != nil {
+			return err
+		}
+		if parentCommitInfo.Trees != nil {
+			request.Empty = false
+			request.Trees = parentCommitInfo.Trees
+			request.Datums = parentCommitInfo.Datums
+			request.SizeBytes = parentCommitInfo.SizeBytes
+			break
+		}
+		parent = parentCommitInfo.ParentCommit
+	}
+	if statsCommit != nil {
+		if _, err := pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(), &pfs.FinishCommitRequest{
+			Commit: statsCommit,
+			Empty:  true,
+		}); err != nil && !pfsserver.IsCommitFinishedErr(err) {
+			return err
+		}
+	}
+	if _, err := pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(), request); err != nil && !pfsserver.IsCommitFinishedErr(err) {
+		return err
+	}
+	return nil
+}
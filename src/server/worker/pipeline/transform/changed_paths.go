@@ -0,0 +1,89 @@
+package transform
+
+import (
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
+)
+
+const (
+	// changedPathsDepth is the depth at which output commits' changed paths
+	// are summarized: a change to a deeper path is recorded as a change to its
+	// ancestor at this depth.
+	changedPathsDepth = 3
+	// maxChangedPaths is the most paths that an output commit's summary holds.
+	// Bigger summaries are coarsened to shallower paths until they fit.
+	maxChangedPaths = 100
+)
+
+// truncatePath returns the ancestor of 'p' at 'depth', or 'p' if it isn't
+// deeper than that.
+func truncatePath(p string, depth int) string {
+	p = strings.Trim(p, "/")
+	if p == "" || depth <= 0 {
+		return "/"
+	}
+	parts := strings.Split(p, "/")
+	if len(parts) > depth {
+		parts = parts[:depth]
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// shardChangedPaths returns the paths, at most changedPathsDepth deep, under
+// which the files of one shard of an output commit changed between the
+// serialized hashtrees 'previous' and 'tree'.
+func shardChangedPaths(previous, tree io.Reader) ([]string, error) {
+	paths := make(map[string]bool)
+	if err := hashtree.Changed(previous, tree, nil, func(p string) error {
+		paths[truncatePath(p, changedPathsDepth)] = true
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	result := make([]string, 0, len(paths))
+	for p := range paths {
+		result = append(result, p)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// summarizeChangedPaths combines the changed paths of an output commit's
+// shards into its summary, relative to the output commit 'base'. Paths under
+// other changed paths are dropped, and the summary is coarsened until it has
+// at most maxChangedPaths paths. It returns nil if the whole commit may have
+// changed.
+func summarizeChangedPaths(base *pfs.Commit, paths []string) *pfs.ChangedPaths {
+	for depth := changedPathsDepth; depth > 0; depth-- {
+		changed := make(map[string]bool)
+		for _, p := range paths {
+			changed[truncatePath(p, depth)] = true
+		}
+		if changed["/"] {
+			return nil
+		}
+		result := &pfs.ChangedPaths{Base: base}
+		for p := range changed {
+			covered := false
+			for dir := path.Dir(p); dir != "/"; dir = path.Dir(dir) {
+				if changed[dir] {
+					covered = true
+					break
+				}
+			}
+			if !covered {
+				result.Paths = append(result.Paths, p)
+			}
+		}
+		if len(result.Paths) <= maxChangedPaths {
+			sort.Strings(result.Paths)
+			return result
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package transform
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestTruncatePath(t *testing.T) {
+	require.Equal(t, "/", truncatePath("/", 3))
+	require.Equal(t, "/a/b", truncatePath("a/b", 3))
+	require.Equal(t, "/a/b/c", truncatePath("/a/b/c/d/e", 3))
+	require.Equal(t, "/", truncatePath("/a/b", 0))
+}
+
+func TestSummarizeChangedPaths(t *testing.T) {
+	base := client.NewCommit("out", "c1")
+
+	// Paths under other changed paths are dropped
+	summary := summarizeChangedPaths(base, []string{"/a/b/c", "/a/b", "/d"})
+	require.Equal(t, "c1", summary.Base.ID)
+	require.Equal(t, []string{"/a/b", "/d"}, summary.Paths)
+
+	// A commit without changes has an empty summary
+	summary = summarizeChangedPaths(base, nil)
+	require.NotNil(t, summary)
+	require.Equal(t, 0, len(summary.Paths))
+
+	// Summaries with too many paths are coarsened
+	var paths []string
+	for i := 0; i < maxChangedPaths+1; i++ {
+		paths = append(paths, fmt.Sprintf("/a/%d/file", i))
+	}
+	summary = summarizeChangedPaths(base, paths)
+	require.Equal(t, []string{"/a"}, summary.Paths)
+
+	// A change to the root is a change to the whole commit
+	require.Nil(t, summarizeChangedPaths(base, []string{"/"}))
+	for i := 0; i < maxChangedPaths+1; i++ {
+		paths = append(paths, fmt.Sprintf("/%d", i))
+	}
+	require.Nil(t, summarizeChangedPaths(base, paths))
+}
@@ -2,6 +2,7 @@ package transform
 
 import (
 	"context"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -69,8 +70,8 @@ func (td *testDriver) Jobs() col.Collection {
 func (td *testDriver) Pipelines() col.Collection {
 	return td.inner.Pipelines()
 }
-func (td *testDriver) NewTaskWorker() *work.Worker {
-	return td.inner.NewTaskWorker()
+func (td *testDriver) NewTaskWorker(workerClass string) *work.Worker {
+	return td.inner.NewTaskWorker(workerClass)
 }
 func (td *testDriver) NewTaskQueue() (*work.TaskQueue, error) {
 	return td.inner.NewTaskQueue()
@@ -105,20 +106,38 @@ func (td *testDriver) WithActiveData(inputs []*common.Input, dir string, cb func
 func (td *testDriver) UserCodeEnv(job string, commit *pfs.Commit, inputs []*common.Input) []string {
 	return td.inner.UserCodeEnv(job, commit, inputs)
 }
+func (td *testDriver) WatchRuntimeConfig() error {
+	return td.inner.WatchRuntimeConfig()
+}
 func (td *testDriver) RunUserCode(logger logs.TaggedLogger, env []string, stats *pps.ProcessStats, d *types.Duration) error {
 	return td.inner.RunUserCode(logger, env, stats, d)
 }
+func (td *testDriver) RunBuiltinTransform(logger logs.TaggedLogger, inputs []*common.Input, stats *pps.ProcessStats, d *types.Duration) error {
+	return td.inner.RunBuiltinTransform(logger, inputs, stats, d)
+}
+func (td *testDriver) RunValidation(logger logs.TaggedLogger, inputs []*common.Input, stats *pps.ProcessStats) error {
+	return td.inner.RunValidation(logger, inputs, stats)
+}
 func (td *testDriver) RunUserErrorHandlingCode(logger logs.TaggedLogger, env []string, stats *pps.ProcessStats, d *types.Duration) error {
 	return td.inner.RunUserErrorHandlingCode(logger, env, stats, d)
 }
+func (td *testDriver) WithCheckpoints(tag string, dir string, logger logs.TaggedLogger, cb func() error) error {
+	return td.inner.WithCheckpoints(tag, dir, logger, cb)
+}
 func (td *testDriver) DeleteJob(stm col.STM, ji *pps.EtcdJobInfo) error {
 	return td.inner.DeleteJob(stm, ji)
 }
 func (td *testDriver) UpdateJobState(job string, state pps.JobState, reason string) error {
 	return td.inner.UpdateJobState(job, state, reason)
 }
-func (td *testDriver) UploadOutput(dir string, tag string, logger logs.TaggedLogger, input []*common.Input, stats *pps.ProcessStats, tree *hashtree.Ordered) ([]byte, error) {
-	return td.inner.UploadOutput(dir, tag, logger, input, stats, tree)
+func (td *testDriver) UploadOutput(dir string, tag string, logger logs.TaggedLogger, input []*common.Input, stats *pps.ProcessStats, tree *hashtree.Ordered, datumCache *hashtree.MergeCache) error {
+	return td.inner.UploadOutput(dir, tag, logger, input, stats, tree, datumCache)
+}
+func (td *testDriver) NewOrderedTree(root string) (*hashtree.Ordered, error) {
+	return td.inner.NewOrderedTree(root)
+}
+func (td *testDriver) WithSpillFile(cb func(*os.File) error) error {
+	return td.inner.WithSpillFile(cb)
 }
 func (td *testDriver) ReportUploadStats(t time.Time, stats *pps.ProcessStats, logger logs.TaggedLogger) {
 	td.inner.ReportUploadStats(t, stats, logger)
@@ -136,7 +155,7 @@ func (td *testDriver) WithDatumCache(cb func(*hashtree.MergeCache, *hashtree.Mer
 	return td.inner.WithDatumCache(cb)
 }
 
-func (td *testDriver) Egress(commit *pfs.Commit, egressURL string) error {
+func (td *testDriver) Egress(commit *pfs.Commit, egress *pps.Egress) error {
 	return nil
 }
 
@@ -0,0 +1,193 @@
+package transform
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+	"github.com/pachyderm/pachyderm/src/server/worker/driver"
+	"github.com/pachyderm/pachyderm/src/server/worker/logs"
+
+	"github.com/gogo/protobuf/types"
+)
+
+const (
+	defaultJobGateCheckInterval = time.Minute
+	defaultHTTPGateTimeout      = 30 * time.Second
+)
+
+// waitForJobGates blocks until the pipeline's job gates (if any) are open for
+// 'commitInfo', an output commit, recording why they're closed in the
+// pipeline's EtcdPipelineInfo while it waits. It returns false if the commit
+// was finished (e.g. by StopPipeline) while it waited, in which case its stats
+// commit is finished too, and no job should be created for it.
+func waitForJobGates(driver driver.Driver, logger logs.TaggedLogger, commitInfo *pfs.CommitInfo, statsCommit *pfs.Commit) (bool, error) {
+	pipelineInfo := driver.PipelineInfo()
+	gates := pipelineInfo.JobGates
+	if gates == nil {
+		return true, nil
+	}
+	interval := defaultJobGateCheckInterval
+	if gates.CheckInterval != nil {
+		var err error
+		if interval, err = types.DurationFromProto(gates.CheckInterval); err != nil {
+			return false, errors.EnsureStack(err)
+		}
+	}
+	pachClient := driver.PachClient()
+	var status *pps.JobGateStatus
+	for {
+		waitingOn, err := checkJobGates(pachClient, pipelineInfo, commitInfo)
+		if err != nil {
+			return false, err
+		}
+		if len(waitingOn) == 0 {
+			if status != nil {
+				logger.Logf("the job gates of output commit %s are open", commitInfo.Commit.ID)
+			}
+			return true, setJobGateStatus(driver, nil)
+		}
+		now := types.TimestampNow()
+		if status == nil {
+			logger.Logf("holding output commit %s until its job gates are open: %v", commitInfo.Commit.ID, waitingOn)
+			status = &pps.JobGateStatus{OutputCommit: commitInfo.Commit, Since: now}
+		}
+		status.WaitingOn = waitingOn
+		status.Checked = now
+		if err := setJobGateStatus(driver, status); err != nil {
+			return false, err
+		}
+		select {
+		case <-time.After(interval):
+		case <-pachClient.Ctx().Done():
+			return false, errors.EnsureStack(pachClient.Ctx().Err())
+		}
+		ci, err := pachClient.InspectCommit(commitInfo.Commit.Repo.Name, commitInfo.Commit.ID)
+		if err != nil {
+			return false, err
+		}
+		if ci.Finished != nil {
+			logger.Logf("output commit %s was finished while it was held by its job gates", commitInfo.Commit.ID)
+			if statsCommit != nil {
+				if _, err := pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(), &pfs.FinishCommitRequest{
+					Commit: statsCommit,
+					Empty:  true,
+				}); err != nil && !pfsserver.IsCommitFinishedErr(err) {
+					return false, err
+				}
+			}
+			return false, setJobGateStatus(driver, nil)
+		}
+	}
+}
+
+// setJobGateStatus records 'status' (or clears it, if it's nil) in the
+// pipeline's EtcdPipelineInfo, so that it's reported by InspectPipeline and
+// GetScheduler.
+func setJobGateStatus(driver driver.Driver, status *pps.JobGateStatus) error {
+	pipelineName := driver.PipelineInfo().Pipeline.Name
+	if _, err := driver.NewSTM(func(stm col.STM) error {
+		pipelinePtr := &pps.EtcdPipelineInfo{}
+		return driver.Pipelines().ReadWrite(stm).Update(pipelineName, pipelinePtr, func() error {
+			pipelinePtr.GateStatus = status
+			return nil
+		})
+	}); err != nil {
+		return errors.EnsureStack(err)
+	}
+	return nil
+}
+
+// checkJobGates returns why each of the pipeline's job gates that's closed
+// for 'commitInfo', an output commit, is closed. Gates that can't be checked
+// (e.g. because their URL can't be reached) are closed.
+func checkJobGates(pachClient *client.APIClient, pipelineInfo *pps.PipelineInfo, commitInfo *pfs.CommitInfo) ([]string, error) {
+	data := &ppsutil.JobGateData{Inputs: make(map[string]*ppsutil.JobGateInput)}
+	if ppsutil.JobGatesUseInputs(pipelineInfo.JobGates) {
+		var err error
+		pps.VisitInput(ppsutil.JobInput(pipelineInfo, commitInfo), func(input *pps.Input) {
+			if input.Pfs == nil || input.Pfs.Commit == "" || err != nil {
+				return
+			}
+			var inputCommitInfo *pfs.CommitInfo
+			inputCommitInfo, err = pachClient.InspectCommit(input.Pfs.Repo, input.Pfs.Commit)
+			if err != nil {
+				return
+			}
+			data.Inputs[input.Pfs.Name] = &ppsutil.JobGateInput{
+				Repo:     input.Pfs.Repo,
+				Branch:   input.Pfs.Branch,
+				Commit:   input.Pfs.Commit,
+				Metadata: inputCommitInfo.Metadata,
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	var waitingOn []string
+	for _, gate := range pipelineInfo.JobGates.Gates {
+		if reason := checkJobGate(pachClient, gate, data); reason != "" {
+			waitingOn = append(waitingOn, fmt.Sprintf("%s: %s", gate.Name, reason))
+		}
+	}
+	return waitingOn, nil
+}
+
+// checkJobGate returns "" if 'gate' is open, and why it's closed otherwise.
+func checkJobGate(pachClient *client.APIClient, gate *pps.JobGate, data *ppsutil.JobGateData) string {
+	target, err := ppsutil.ExpandJobGate(gate, data)
+	if err != nil {
+		return err.Error()
+	}
+	switch {
+	case gate.HTTP != nil:
+		timeout := defaultHTTPGateTimeout
+		if gate.HTTP.Timeout != nil {
+			if timeout, err = types.DurationFromProto(gate.HTTP.Timeout); err != nil {
+				return err.Error()
+			}
+		}
+		return checkHTTPGate(target, timeout)
+	case gate.MarkerFile != nil:
+		branch := gate.MarkerFile.Branch
+		if branch == "" {
+			branch = "master"
+		}
+		if _, err := pachClient.InspectFile(gate.MarkerFile.Repo, branch, target); err != nil {
+			if pfsserver.IsFileNotFoundErr(err) || pfsserver.IsNoHeadErr(err) || pfsserver.IsBranchNotFoundErr(err) {
+				return fmt.Sprintf("%s@%s:%s doesn't exist", gate.MarkerFile.Repo, branch, target)
+			}
+			return err.Error()
+		}
+		return ""
+	}
+	return "the gate has no condition"
+}
+
+// checkHTTPGate returns "" if a GET request to 'target' returns a 2xx
+// status, and why the gate is closed otherwise. The URL isn't included in the
+// reason, as it may contain credentials.
+func checkHTTPGate(target string, timeout time.Duration) string {
+	resp, err := (&http.Client{Timeout: timeout}).Get(target)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Sprintf("the request failed: %v", urlErr.Err)
+		}
+		return "the request failed"
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Sprintf("the request returned %s", resp.Status)
+	}
+	return ""
+}
@@ -0,0 +1,30 @@
+package transform
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestCheckHTTPGate(t *testing.T) {
+	ready := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	require.Equal(t, "the request returned 503 Service Unavailable", checkHTTPGate(server.URL, time.Second))
+	ready = true
+	require.Equal(t, "", checkHTTPGate(server.URL, time.Second))
+	// Neither the URL nor the error, which includes it, is reported
+	server.Close()
+	reason := checkHTTPGate(server.URL+"?token=secret", time.Second)
+	require.Matches(t, "^the request failed", reason)
+	require.False(t, strings.Contains(reason, "secret"))
+}
@@ -0,0 +1,41 @@
+package transform
+
+import (
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+)
+
+// escalateMemory asks the PPS master to restart the pipeline's workers with
+// more memory (see pps.OOMRetry), after one of the datums of 'pj' was
+// OOM-killed. The job resumes once the workers restart, skipping the datums
+// it already processed. It returns false if the pipeline doesn't retry
+// OOM-killed datums, or its workers' memory can't be escalated any further.
+func (reg *registry) escalateMemory(pj *pendingJob) (bool, error) {
+	pipelineInfo := reg.driver.PipelineInfo()
+	if pipelineInfo.OomRetry == nil {
+		return false, nil
+	}
+	var memory string
+	if _, err := reg.driver.NewSTM(func(stm col.STM) error {
+		pipelinePtr := &pps.EtcdPipelineInfo{}
+		return reg.driver.Pipelines().ReadWrite(stm).Update(pipelineInfo.Pipeline.Name, pipelinePtr, func() error {
+			var err error
+			memory, err = ppsutil.EscalatedMemory(pipelineInfo, pipelinePtr.EscalatedMemory)
+			if err != nil || memory == "" {
+				return err
+			}
+			pipelinePtr.EscalatedMemory = memory
+			pipelinePtr.EscalatedJob = pj.ji.Job.ID
+			return nil
+		})
+	}); err != nil {
+		return false, errors.EnsureStack(err)
+	}
+	if memory == "" {
+		return false, nil
+	}
+	pj.logger.Logf("a datum was OOM-killed, so the workers are being restarted with %s of memory", memory)
+	return true, nil
+}
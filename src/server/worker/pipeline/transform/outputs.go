@@ -0,0 +1,181 @@
+package transform
+
+import (
+	"path"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
+	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+)
+
+// sameProvenance returns true if 'a' and 'b' are provenant on exactly the same
+// commits.
+func sameProvenance(a, b []*pfs.CommitProvenance) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	commits := make(map[string]bool)
+	for _, prov := range a {
+		commits[prov.Commit.Repo.Name+"@"+prov.Commit.ID] = true
+	}
+	for _, prov := range b {
+		if !commits[prov.Commit.Repo.Name+"@"+prov.Commit.ID] {
+			return false
+		}
+	}
+	return true
+}
+
+// namedOutputCommit returns the commit in 'output's branch that corresponds to
+// the job output commit 'outputCommitInfo'. Named output branches have the same
+// provenance as the pipeline's output branch, so PFS creates the two commits
+// together, with the same provenance. nil is returned if there's no such
+// commit (e.g. if the output was added to the pipeline after the job started).
+func namedOutputCommit(pachClient *client.APIClient, outputCommitInfo *pfs.CommitInfo, output *pps.PipelineOutput) (*pfs.CommitInfo, error) {
+	var result *pfs.CommitInfo
+	if err := pachClient.ListCommitF(output.Repo, output.Branch, "", 0, false, func(ci *pfs.CommitInfo) error {
+		if sameProvenance(ci.Provenance, outputCommitInfo.Provenance) {
+			result = ci
+			return errutil.ErrBreak
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// finishNamedOutputs finishes the commits of a job in each of its pipeline's
+// named outputs. If the job succeeded, each commit gets the contents of
+// /<name> in the job's (finished) output commit, and otherwise it's finished
+// empty. Commits that are already finished are skipped, so it's safe to call
+// finishNamedOutputs more than once for the same job.
+func finishNamedOutputs(pipelineInfo *pps.PipelineInfo, pachClient *client.APIClient, jobInfo *pps.JobInfo) error {
+	if len(pipelineInfo.Outputs) == 0 {
+		return nil
+	}
+	outputCommitInfo, err := pachClient.InspectCommit(jobInfo.OutputCommit.Repo.Name, jobInfo.OutputCommit.ID)
+	if err != nil {
+		if pfsserver.IsCommitNotFoundErr(err) || pfsserver.IsCommitDeletedErr(err) {
+			return nil
+		}
+		return err
+	}
+	succeeded := jobInfo.State == pps.JobState_JOB_SUCCESS || jobInfo.State == pps.JobState_JOB_EGRESSING
+	for _, output := range pipelineInfo.Outputs {
+		commitInfo, err := namedOutputCommit(pachClient, outputCommitInfo, output)
+		if err != nil {
+			return errors.Wrapf(err, "could not find commit for output %q", output.Name)
+		}
+		if commitInfo == nil || commitInfo.Finished != nil {
+			continue
+		}
+		if succeeded {
+			if err := copyOutputDir(pachClient, jobInfo.OutputCommit, "/"+output.Name, commitInfo.Commit); err != nil {
+				return errors.Wrapf(err, "could not commit output %q", output.Name)
+			}
+		}
+		if _, err := pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(), &pfs.FinishCommitRequest{
+			Commit: commitInfo.Commit,
+			Empty:  !succeeded,
+		}); err != nil && !pfsserver.IsCommitFinishedErr(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyOutputDir replaces the contents of the open commit 'commit' (which
+// starts with the contents of its parent) with the contents of 'dir' in the
+// job's output commit.
+func copyOutputDir(pachClient *client.APIClient, outputCommit *pfs.Commit, dir string, commit *pfs.Commit) error {
+	fileInfos, err := pachClient.ListFile(commit.Repo.Name, commit.ID, "/")
+	if err != nil {
+		return err
+	}
+	for _, fi := range fileInfos {
+		if err := pachClient.DeleteFile(commit.Repo.Name, commit.ID, fi.File.Path); err != nil {
+			return err
+		}
+	}
+	fileInfos, err = pachClient.ListFile(outputCommit.Repo.Name, outputCommit.ID, dir)
+	if err != nil {
+		if pfsserver.IsFileNotFoundErr(err) {
+			return nil // user code didn't write anything to this directory
+		}
+		return err
+	}
+	for _, fi := range fileInfos {
+		if err := pachClient.CopyFile(outputCommit.Repo.Name, outputCommit.ID, fi.File.Path,
+			commit.Repo.Name, commit.ID, "/"+path.Base(fi.File.Path), false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finishOutputRoutes copies the output of a job of a pipeline that routes its
+// output from the job's (finished) output commit to the branches it's routed
+// to: each branch gets a commit with the contents of /<branch> in the output
+// commit. Nothing is copied if the job didn't succeed. Branches whose head
+// already contains the job's output are skipped, and a commit left open by a
+// previous attempt is reused, so it's safe to call finishOutputRoutes more
+// than once for the same job.
+func finishOutputRoutes(pipelineInfo *pps.PipelineInfo, pachClient *client.APIClient, jobInfo *pps.JobInfo) error {
+	if len(pipelineInfo.OutputRoutes) == 0 {
+		return nil
+	}
+	if jobInfo.State != pps.JobState_JOB_SUCCESS && jobInfo.State != pps.JobState_JOB_EGRESSING {
+		return nil
+	}
+	outputCommit := jobInfo.OutputCommit
+	if _, err := pachClient.InspectCommit(outputCommit.Repo.Name, outputCommit.ID); err != nil {
+		if pfsserver.IsCommitNotFoundErr(err) || pfsserver.IsCommitDeletedErr(err) {
+			return nil
+		}
+		return err
+	}
+	metadata := map[string]string{client.RoutedCommitMetadataKey: outputCommit.ID}
+	for _, branch := range ppsutil.OutputRouteBranches(pipelineInfo) {
+		var commit *pfs.Commit
+		branchInfo, err := pachClient.InspectBranch(outputCommit.Repo.Name, branch)
+		if err != nil {
+			return errors.Wrapf(err, "could not inspect output route branch %q", branch)
+		}
+		if branchInfo.Head != nil {
+			headInfo, err := pachClient.InspectCommit(branchInfo.Head.Repo.Name, branchInfo.Head.ID)
+			if err != nil {
+				return err
+			}
+			if headInfo.Metadata[client.RoutedCommitMetadataKey] == outputCommit.ID {
+				if headInfo.Finished != nil {
+					continue
+				}
+				commit = headInfo.Commit
+			}
+		}
+		if commit == nil {
+			if commit, err = pachClient.PfsAPIClient.StartCommit(pachClient.Ctx(), &pfs.StartCommitRequest{
+				Parent:   client.NewCommit(outputCommit.Repo.Name, ""),
+				Branch:   branch,
+				Metadata: metadata,
+			}); err != nil {
+				return errors.Wrapf(err, "could not start commit on output route branch %q", branch)
+			}
+		}
+		if err := copyOutputDir(pachClient, outputCommit, "/"+branch, commit); err != nil {
+			return errors.Wrapf(err, "could not commit output routed to branch %q", branch)
+		}
+		if _, err := pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(), &pfs.FinishCommitRequest{
+			Commit:   commit,
+			Metadata: metadata,
+		}); err != nil && !pfsserver.IsCommitFinishedErr(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package transform
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/limit"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// datumPhases limits and tracks the datums in each phase of processing in a
+// worker's datum task (see pps.PhaseConcurrency). A datum holds its download
+// slot until it has a process slot, so that the worker only downloads as many
+// datums ahead as the pipeline allows, and gives up its process slot before it
+// waits for an upload slot, so that the next datum can be processed while its
+// output is uploaded.
+type datumPhases struct {
+	download, process, upload *phase
+}
+
+// newDatumPhases returns the phases of a datum task of a pipeline with the
+// phase concurrency 'spec'. If 'spec' is nil, downloads and uploads are only
+// limited by the pipeline's max_queue_size.
+func newDatumPhases(spec *pps.PhaseConcurrency) *datumPhases {
+	// The user code only runs on one datum at a time, as the datum's data is
+	// linked into /pfs
+	phases := &datumPhases{process: newPhase(1)}
+	if spec == nil {
+		phases.download, phases.upload = newPhase(0), newPhase(0)
+	} else {
+		phases.download = newPhase(phaseConcurrency(spec.Download))
+		phases.upload = newPhase(phaseConcurrency(spec.Upload))
+	}
+	return phases
+}
+
+// phaseConcurrency returns the concurrency of a phase whose concurrency is
+// set to 'n' in a pipeline's PhaseConcurrency.
+func phaseConcurrency(n int64) int64 {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// datumQueueSize returns the number of datums that a worker of the pipeline
+// 'pipelineInfo' can have in flight at once.
+func datumQueueSize(pipelineInfo *pps.PipelineInfo) int {
+	if spec := pipelineInfo.PhaseConcurrency; spec != nil {
+		return int(phaseConcurrency(spec.Download) + 1 + phaseConcurrency(spec.Upload))
+	}
+	return int(pipelineInfo.MaxQueueSize)
+}
+
+// phase limits how many datums can be in one phase of processing at once, and
+// tracks the datums in it for its PhaseStats.
+type phase struct {
+	limiter limit.ConcurrencyLimiter
+	now     func() time.Time
+
+	mu        sync.Mutex
+	active    int64
+	waiting   int64
+	completed int64
+	bytes     uint64
+	// The time during which at least one datum was in the phase, before
+	// 'busySince'
+	busy      time.Duration
+	busySince time.Time
+}
+
+// newPhase returns a phase that up to 'concurrency' datums can be in at once,
+// or any number of datums if it's 0.
+func newPhase(concurrency int64) *phase {
+	return &phase{limiter: limit.New(int(concurrency)), now: time.Now}
+}
+
+// enter waits until a datum can enter the phase, and returns its slot in it.
+// The slot must be released.
+func (p *phase) enter() *phaseSlot {
+	p.withLock(func() { p.waiting++ })
+	p.limiter.Acquire()
+	p.withLock(func() {
+		p.waiting--
+		if p.active == 0 {
+			p.busySince = p.now()
+		}
+		p.active++
+	})
+	return &phaseSlot{phase: p}
+}
+
+// addBytes adds to the number of bytes downloaded or uploaded by the datums
+// that completed the phase.
+func (p *phase) addBytes(bytes uint64) {
+	p.withLock(func() { p.bytes += bytes })
+}
+
+func (p *phase) withLock(cb func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cb()
+}
+
+// stats returns the phase's PhaseStats.
+func (p *phase) stats() *pps.PhaseStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	busy := p.busy
+	if p.active > 0 {
+		busy += p.now().Sub(p.busySince)
+	}
+	return &pps.PhaseStats{
+		Active:    p.active,
+		Waiting:   p.waiting,
+		Completed: p.completed,
+		Bytes:     p.bytes,
+		BusyTime:  types.DurationProto(busy),
+	}
+}
+
+// phaseSlot is a datum's slot in a phase. It's only used by the goroutine
+// that processes the datum.
+type phaseSlot struct {
+	phase              *phase
+	finished, released bool
+}
+
+// finish marks the datum as no longer in the phase, and as having completed
+// it if 'completed' is set. The datum keeps its slot until it's released.
+func (s *phaseSlot) finish(completed bool) {
+	if s.finished {
+		return
+	}
+	s.finished = true
+	p := s.phase
+	p.withLock(func() {
+		p.active--
+		if completed {
+			p.completed++
+		}
+		if p.active == 0 {
+			p.busy += p.now().Sub(p.busySince)
+		}
+	})
+}
+
+// release gives up the datum's slot in the phase, so that another datum can
+// enter it. A datum that hasn't finished the phase didn't complete it.
+func (s *phaseSlot) release() {
+	s.finish(false)
+	if s.released {
+		return
+	}
+	s.released = true
+	s.phase.limiter.Release()
+}
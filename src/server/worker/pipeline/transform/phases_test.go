@@ -0,0 +1,58 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestDatumQueueSize(t *testing.T) {
+	require.Equal(t, 4, datumQueueSize(&pps.PipelineInfo{MaxQueueSize: 4}))
+	require.Equal(t, 3, datumQueueSize(&pps.PipelineInfo{MaxQueueSize: 1, PhaseConcurrency: &pps.PhaseConcurrency{}}))
+	require.Equal(t, 7, datumQueueSize(&pps.PipelineInfo{MaxQueueSize: 1, PhaseConcurrency: &pps.PhaseConcurrency{Download: 4, Upload: 2}}))
+}
+
+func TestPhaseStats(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	p := newPhase(2)
+	p.now = func() time.Time { return now }
+
+	a := p.enter()
+	now = now.Add(time.Second)
+	b := p.enter()
+	require.Equal(t, &pps.PhaseStats{Active: 2, BusyTime: types.DurationProto(time.Second)}, p.stats())
+
+	// A third datum waits for a slot, which a datum keeps after it finishes
+	// the phase
+	entered := make(chan *phaseSlot)
+	go func() { entered <- p.enter() }()
+	require.NoErrorWithinT(t, 10*time.Second, func() error {
+		for p.stats().Waiting != 1 {
+			time.Sleep(time.Millisecond)
+		}
+		return nil
+	})
+	a.finish(true)
+	select {
+	case <-entered:
+		t.Fatal("datum entered a full phase")
+	case <-time.After(50 * time.Millisecond):
+	}
+	a.release()
+	c := <-entered
+	require.Equal(t, &pps.PhaseStats{Active: 2, Completed: 1, BusyTime: types.DurationProto(time.Second)}, p.stats())
+
+	// Datums that are released without finishing didn't complete the phase,
+	// and the phase isn't busy while no datums are in it
+	now = now.Add(time.Second)
+	b.release()
+	c.finish(true)
+	c.release()
+	p.addBytes(100)
+	now = now.Add(time.Hour)
+	require.Equal(t, &pps.PhaseStats{Completed: 2, Bytes: 100, BusyTime: types.DurationProto(2 * time.Second)}, p.stats())
+}
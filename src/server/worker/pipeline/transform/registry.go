@@ -18,6 +18,7 @@ import (
 	"github.com/pachyderm/pachyderm/src/client/limit"
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
 	"github.com/pachyderm/pachyderm/src/client/pkg/pbutil"
 	"github.com/pachyderm/pachyderm/src/client/pps"
 	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
@@ -53,6 +54,7 @@ func jobArtifactRecoveredObject(jobID string) string {
 
 type pendingJob struct {
 	driver          driver.Driver
+	listings        *datum.ListingCache
 	commitInfo      *pfs.CommitInfo
 	statsCommitInfo *pfs.CommitInfo
 	cancel          context.CancelFunc
@@ -65,6 +67,10 @@ type pendingJob struct {
 	// from object storage.
 	chunkHashtrees []*HashtreeInfo
 	statsHashtrees []*HashtreeInfo
+
+	// changedPathsBase is the previous output commit, which the merge
+	// subtasks compare the output commit to, or nil if they don't
+	changedPathsBase *pfs.Commit
 }
 
 type registry struct {
@@ -74,6 +80,9 @@ type registry struct {
 	concurrency int64
 	limiter     limit.ConcurrencyLimiter
 	jobChain    chain.JobChain
+	// listings holds the listings of the pipeline's PFS inputs in the last
+	// jobs, which the next jobs reuse for the paths that didn't change
+	listings *datum.ListingCache
 }
 
 type hasher struct {
@@ -108,30 +117,109 @@ func newRegistry(
 		taskQueue:   taskQueue,
 		limiter:     limit.New(int(concurrency)),
 		jobChain:    nil,
+		listings:    datum.NewListingCache(),
 	}, nil
 }
 
+// outputCommitDescription returns the description and metadata of the output
+// commit of 'jobInfo'. A template that can't be executed doesn't fail the
+// job; its error is used as the description instead.
+func outputCommitDescription(pipelineInfo *pps.PipelineInfo, jobInfo *pps.JobInfo) (string, map[string]string) {
+	info := ppsutil.NewOutputCommitInfo(jobInfo, time.Now())
+	description, err := ppsutil.OutputCommitDescription(pipelineInfo.OutputCommitDescription, info)
+	if err != nil {
+		description = err.Error()
+	}
+	return description, info.Metadata()
+}
+
+// outputCommitBuild returns the description of 'jobInfo' that PFS attests to
+// when it finishes the job's output commit, or nil if the pipeline doesn't
+// have 'attest' set. Only the output commits of successful jobs are attested.
+func outputCommitBuild(pipelineInfo *pps.PipelineInfo, jobInfo *pps.JobInfo) *pfs.BuildInfo {
+	if !pipelineInfo.Attest || jobInfo.State != pps.JobState_JOB_SUCCESS {
+		return nil
+	}
+	build := &pfs.BuildInfo{
+		Pipeline: pipelineInfo.Pipeline.Name,
+		JobID:    jobInfo.Job.ID,
+		Started:  jobInfo.Started,
+	}
+	pps.VisitInput(jobInfo.Input, func(input *pps.Input) {
+		if input.Pfs != nil && input.Pfs.Commit != "" {
+			build.Inputs = append(build.Inputs, client.NewCommit(input.Pfs.Repo, input.Pfs.Commit))
+		}
+	})
+	return build
+}
+
+// outputCommitValidation returns the result that the output commit of
+// 'jobInfo' records for a validation pipeline (see pfs.ValidationMetadataKey),
+// or "" if the pipeline isn't a validation pipeline or the job didn't succeed.
+// If the job skipped its datum, its input hasn't changed since the parent
+// output commit, which therefore has the result.
+func outputCommitValidation(pipelineInfo *pps.PipelineInfo, pachClient *client.APIClient, jobInfo *pps.JobInfo) (string, error) {
+	if pipelineInfo.Validation == nil || jobInfo.State != pps.JobState_JOB_SUCCESS {
+		return "", nil
+	}
+	if jobInfo.Stats != nil && jobInfo.Stats.AssertionsFailed > 0 {
+		return pfs.ValidationFailed, nil
+	}
+	if jobInfo.DataProcessed == 0 && jobInfo.DataSkipped > 0 {
+		commitInfo, err := pachClient.InspectCommit(jobInfo.OutputCommit.Repo.Name, jobInfo.OutputCommit.ID)
+		if err != nil {
+			return "", err
+		}
+		if commitInfo.ParentCommit != nil {
+			parentInfo, err := pachClient.InspectCommit(commitInfo.ParentCommit.Repo.Name, commitInfo.ParentCommit.ID)
+			if err != nil {
+				return "", err
+			}
+			if result := parentInfo.Metadata[pfs.ValidationMetadataKey]; result != "" {
+				return result, nil
+			}
+		}
+	}
+	return pfs.ValidationPassed, nil
+}
+
 // Helper function for succeedJob and failJob, do not use directly
 func finishJob(
 	pipelineInfo *pps.PipelineInfo,
 	pachClient *client.APIClient,
 	jobInfo *pps.JobInfo,
 	state pps.JobState,
-	reason string,
+	reason *pps.JobReason,
 	datums *pfs.Object,
 	trees []*pfs.Object,
 	size uint64,
+	changedPaths *pfs.ChangedPaths,
 	statsTrees []*pfs.Object,
 	statsSize uint64,
 ) error {
 	// Optimistically update the local state and reason - if any errors occur the
 	// local state will be reloaded way up the stack
 	jobInfo.State = state
-	jobInfo.Reason = reason
+	jobInfo.Reason = ppsutil.RenderJobReason(reason)
+	jobInfo.ReasonDetails = reason
+	description, metadata := outputCommitDescription(pipelineInfo, jobInfo)
+	build := outputCommitBuild(pipelineInfo, jobInfo)
+	validation, err := outputCommitValidation(pipelineInfo, pachClient, jobInfo)
+	if err != nil {
+		return err
+	}
+	if validation != "" {
+		metadata[pfs.ValidationMetadataKey] = validation
+	}
 
 	if _, err := pachClient.RunBatchInTransaction(func(builder *client.TransactionBuilder) error {
 		if pipelineInfo.S3Out {
-			if err := builder.FinishCommit(jobInfo.OutputCommit.Repo.Name, jobInfo.OutputCommit.ID); err != nil {
+			if _, err := builder.PfsAPIClient.FinishCommit(pachClient.Ctx(), &pfs.FinishCommitRequest{
+				Commit:      jobInfo.OutputCommit,
+				Description: description,
+				Metadata:    metadata,
+				Build:       build,
+			}); err != nil {
 				return err
 			}
 		} else {
@@ -147,11 +235,15 @@ func finishJob(
 			}
 
 			if _, err := builder.PfsAPIClient.FinishCommit(pachClient.Ctx(), &pfs.FinishCommitRequest{
-				Commit:    jobInfo.OutputCommit,
-				Empty:     trees == nil,
-				Datums:    datums,
-				Trees:     trees,
-				SizeBytes: size,
+				Commit:       jobInfo.OutputCommit,
+				Description:  description,
+				Empty:        trees == nil,
+				Datums:       datums,
+				Trees:        trees,
+				SizeBytes:    size,
+				ChangedPaths: changedPaths,
+				Metadata:     metadata,
+				Build:        build,
 			}); err != nil {
 				return err
 			}
@@ -163,13 +255,16 @@ func finishJob(
 			// For certain types of errors, we want to reattempt these operations
 			// outside of a transaction (in case the job or commits were affected by
 			// some non-transactional code elsewhere, we can attempt to recover)
-			return recoverFinishedJob(pipelineInfo, pachClient, jobInfo, state, reason, datums, trees, size, statsTrees, statsSize)
+			return recoverFinishedJob(pipelineInfo, pachClient, jobInfo, state, jobInfo.Reason, datums, trees, size, changedPaths, statsTrees, statsSize, description, metadata, build)
 		}
 		// For other types of errors, we want to fail the job supervision and let it
 		// reattempt later
 		return err
 	}
-	return nil
+	if err := finishNamedOutputs(pipelineInfo, pachClient, jobInfo); err != nil {
+		return err
+	}
+	return finishOutputRoutes(pipelineInfo, pachClient, jobInfo)
 }
 
 // recoverFinishedJob performs job and output commit updates outside of a
@@ -184,11 +279,20 @@ func recoverFinishedJob(
 	datums *pfs.Object,
 	trees []*pfs.Object,
 	size uint64,
+	changedPaths *pfs.ChangedPaths,
 	statsTrees []*pfs.Object,
 	statsSize uint64,
+	description string,
+	metadata map[string]string,
+	build *pfs.BuildInfo,
 ) error {
 	if pipelineInfo.S3Out {
-		if err := pachClient.FinishCommit(jobInfo.OutputCommit.Repo.Name, jobInfo.OutputCommit.ID); err != nil {
+		if _, err := pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(), &pfs.FinishCommitRequest{
+			Commit:      jobInfo.OutputCommit,
+			Description: description,
+			Metadata:    metadata,
+			Build:       build,
+		}); err != nil {
 			return err
 		}
 	} else {
@@ -206,11 +310,15 @@ func recoverFinishedJob(
 		}
 
 		if _, err := pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(), &pfs.FinishCommitRequest{
-			Commit:    jobInfo.OutputCommit,
-			Empty:     trees == nil,
-			Datums:    datums,
-			Trees:     trees,
-			SizeBytes: size,
+			Commit:       jobInfo.OutputCommit,
+			Description:  description,
+			Empty:        trees == nil,
+			Datums:       datums,
+			Trees:        trees,
+			SizeBytes:    size,
+			ChangedPaths: changedPaths,
+			Metadata:     metadata,
+			Build:        build,
 		}); err != nil {
 			if !pfsserver.IsCommitFinishedErr(err) && !pfsserver.IsCommitNotFoundErr(err) && !pfsserver.IsCommitDeletedErr(err) {
 				return err
@@ -218,6 +326,13 @@ func recoverFinishedJob(
 		}
 	}
 
+	if err := finishNamedOutputs(pipelineInfo, pachClient, jobInfo); err != nil {
+		return err
+	}
+	if err := finishOutputRoutes(pipelineInfo, pachClient, jobInfo); err != nil {
+		return err
+	}
+
 	if err := writeJobInfo(pachClient, jobInfo); err != nil {
 		if !ppsserver.IsJobFinishedErr(err) {
 			return err
@@ -232,6 +347,7 @@ func (reg *registry) succeedJob(
 	pj *pendingJob,
 	trees []*pfs.Object,
 	size uint64,
+	changedPaths *pfs.ChangedPaths,
 	statsTrees []*pfs.Object,
 	statsSize uint64,
 ) error {
@@ -250,7 +366,7 @@ func (reg *registry) succeedJob(
 	}
 
 	// Use the registry's driver so that the job's supervision goroutine cannot cancel us
-	if err := finishJob(reg.driver.PipelineInfo(), reg.driver.PachClient(), pj.ji, newState, "", datums, trees, size, statsTrees, statsSize); err != nil {
+	if err := finishJob(reg.driver.PipelineInfo(), reg.driver.PachClient(), pj.ji, newState, nil, datums, trees, size, changedPaths, statsTrees, statsSize); err != nil {
 		return err
 	}
 
@@ -263,14 +379,15 @@ func (reg *registry) succeedJob(
 
 func (reg *registry) failJob(
 	pj *pendingJob,
-	reason string,
+	reason *pps.JobReason,
 	statsTrees []*pfs.Object,
 	statsSize uint64,
 ) error {
-	pj.logger.Logf("failing job with reason: %s", reason)
+	pj.logger.Logf("failing job with reason: %s", ppsutil.RenderJobReason(reason))
+	pj.ji.FailureCause = reason.Category
 
 	// Use the registry's driver so that the job's supervision goroutine cannot cancel us
-	if err := finishJob(reg.driver.PipelineInfo(), reg.driver.PachClient(), pj.ji, pps.JobState_JOB_FAILURE, reason, nil, nil, 0, statsTrees, statsSize); err != nil {
+	if err := finishJob(reg.driver.PipelineInfo(), reg.driver.PachClient(), pj.ji, pps.JobState_JOB_FAILURE, reason, nil, nil, 0, nil, statsTrees, statsSize); err != nil {
 		return err
 	}
 
@@ -286,12 +403,13 @@ func (reg *registry) failJob(
 
 func (reg *registry) killJob(
 	pj *pendingJob,
-	reason string,
+	reason *pps.JobReason,
 ) error {
-	pj.logger.Logf("killing job with reason: %s", reason)
+	pj.logger.Logf("killing job with reason: %s", ppsutil.RenderJobReason(reason))
+	pj.ji.FailureCause = reason.Category
 
 	// Use the registry's driver so that the job's supervision goroutine cannot cancel us
-	if err := finishJob(reg.driver.PipelineInfo(), reg.driver.PachClient(), pj.ji, pps.JobState_JOB_KILLED, reason, nil, nil, 0, nil, 0); err != nil {
+	if err := finishJob(reg.driver.PipelineInfo(), reg.driver.PachClient(), pj.ji, pps.JobState_JOB_KILLED, reason, nil, nil, 0, nil, nil, 0); err != nil {
 		return err
 	}
 
@@ -314,16 +432,21 @@ func (reg *registry) cleanJobArtifacts(job *pps.Job) error {
 
 func writeJobInfo(pachClient *client.APIClient, jobInfo *pps.JobInfo) error {
 	_, err := pachClient.PpsAPIClient.UpdateJobState(pachClient.Ctx(), &pps.UpdateJobStateRequest{
-		Job:           jobInfo.Job,
-		State:         jobInfo.State,
-		Reason:        jobInfo.Reason,
-		Restart:       jobInfo.Restart,
-		DataProcessed: jobInfo.DataProcessed,
-		DataSkipped:   jobInfo.DataSkipped,
-		DataTotal:     jobInfo.DataTotal,
-		DataFailed:    jobInfo.DataFailed,
-		DataRecovered: jobInfo.DataRecovered,
-		Stats:         jobInfo.Stats,
+		Job:             jobInfo.Job,
+		State:           jobInfo.State,
+		Reason:          jobInfo.Reason,
+		ReasonDetails:   jobInfo.ReasonDetails,
+		Restart:         jobInfo.Restart,
+		DataProcessed:   jobInfo.DataProcessed,
+		DataSkipped:     jobInfo.DataSkipped,
+		DataTotal:       jobInfo.DataTotal,
+		DataFailed:      jobInfo.DataFailed,
+		DataRecovered:   jobInfo.DataRecovered,
+		EvictionRetries: jobInfo.EvictionRetries,
+		FailureCause:    jobInfo.FailureCause,
+		Stats:           jobInfo.Stats,
+		LargestDatums:   jobInfo.LargestDatums,
+		SmokeTest:       jobInfo.SmokeTest,
 	})
 	return err
 }
@@ -385,6 +508,10 @@ func (reg *registry) sendDatumTasks(ctx context.Context, pj *pendingJob, numDatu
 	maxDatumsPerTask := int64(chunkSpec.Number)
 	maxBytesPerTask := int64(chunkSpec.SizeBytes)
 	driver := pj.driver.WithContext(ctx)
+	seedSalt := pj.ji.SeedSalt
+	if seedSalt == "" {
+		seedSalt = driver.PipelineInfo().Salt
+	}
 	var numTasks int64
 	if numDatums < reg.concurrency {
 		numTasks = numDatums
@@ -395,12 +522,17 @@ func (reg *registry) sendDatumTasks(ctx context.Context, pj *pendingJob, numDatu
 	}
 	datumsPerTask := int64(math.Ceil(float64(numDatums) / float64(numTasks)))
 
-	datumsSize := int64(0)
-	datums := []*DatumInputs{}
+	// The datums of the task being built for each worker class (see
+	// pps.DatumSizing), and their size
+	type datumChunk struct {
+		datums []*DatumInputs
+		size   int64
+	}
+	chunks := make(map[string]*datumChunk)
 
-	// finishTask will finish the currently-writing object and append it to the
-	// subtasks, then reset all the relevant variables
-	finishTask := func() (retErr error) {
+	// finishTask will finish the currently-writing object of 'class' and
+	// append it to the subtasks, then reset all the relevant variables
+	finishTask := func(class string) (retErr error) {
 		subtaskID := uuid.NewWithoutDashes()
 		datumsObject := jobArtifactChunkDatumList(pj.ji.Job.ID, subtaskID)
 		writer, err := driver.PachClient().DirectObjWriter(datumsObject)
@@ -414,23 +546,22 @@ func (reg *registry) sendDatumTasks(ctx context.Context, pj *pendingJob, numDatu
 		}()
 
 		protoWriter := pbutil.NewWriter(writer)
-		if _, err := protoWriter.Write(&DatumInputsList{Datums: datums}); err != nil {
+		if _, err := protoWriter.Write(&DatumInputsList{Datums: chunks[class].datums}); err != nil {
 			return err
 		}
 
-		taskData, err := serializeDatumData(&DatumData{DatumsObject: datumsObject, OutputCommit: pj.ji.OutputCommit, JobID: pj.ji.Job.ID})
+		taskData, err := serializeDatumData(&DatumData{DatumsObject: datumsObject, OutputCommit: pj.ji.OutputCommit, JobID: pj.ji.Job.ID, SeedSalt: seedSalt})
 		if err != nil {
 			return err
 		}
 
 		select {
-		case subtasks <- &work.Task{ID: subtaskID, Data: taskData}:
+		case subtasks <- &work.Task{ID: subtaskID, Data: taskData, Group: class}:
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 
-		datumsSize = 0
-		datums = []*DatumInputs{}
+		delete(chunks, class)
 		return nil
 	}
 
@@ -441,30 +572,40 @@ func (reg *registry) sendDatumTasks(ctx context.Context, pj *pendingJob, numDatu
 			return errors.New("job datum iterator returned nil inputs")
 		}
 
-		datums = append(datums, &DatumInputs{Inputs: inputs, Index: index})
+		var class string
+		if driver.PipelineInfo().DatumSizing != nil {
+			class = pj.datumWorkerClass(ctx, inputs)
+		}
+		chunk, ok := chunks[class]
+		if !ok {
+			chunk = &datumChunk{}
+			chunks[class] = chunk
+		}
+		chunk.datums = append(chunk.datums, &DatumInputs{Inputs: inputs, Index: index})
 
 		// If we have enough input bytes, finish the task
 		if maxBytesPerTask != 0 {
 			for _, input := range inputs {
-				datumsSize += int64(input.FileInfo.SizeBytes)
+				chunk.size += int64(input.FileInfo.SizeBytes)
 			}
-			if datumsSize >= maxBytesPerTask {
-				if err := finishTask(); err != nil {
+			if chunk.size >= maxBytesPerTask {
+				if err := finishTask(class); err != nil {
 					return err
 				}
+				continue
 			}
 		}
 
 		// If we hit the upper threshold for task size, finish the task
-		if int64(len(datums)) >= datumsPerTask {
-			if err := finishTask(); err != nil {
+		if int64(len(chunk.datums)) >= datumsPerTask {
+			if err := finishTask(class); err != nil {
 				return err
 			}
 		}
 	}
 
-	if len(datums) > 0 {
-		if err := finishTask(); err != nil {
+	for class := range chunks {
+		if err := finishTask(class); err != nil {
 			return err
 		}
 	}
@@ -554,6 +695,34 @@ func (reg *registry) getParentCommitInfo(commitInfo *pfs.CommitInfo) (*pfs.Commi
 			}); err != nil && !pfsserver.IsCommitFinishedErr(err) {
 				return nil, err
 			}
+		} else if parentCommitInfo.Pruned != nil {
+			// The contents of the parent (e.g. a stats commit whose stats were
+			// pruned) were dropped, so there's nothing to build on
+			return nil, nil
+		} else if parentCommitInfo.Trees != nil {
+			return parentCommitInfo, nil
+		}
+		commitInfo = parentCommitInfo
+	}
+	return nil, nil
+}
+
+// getPreviousOutputCommitInfo returns the most recent finished ancestor of
+// the output commit 'commitInfo' that has output. Unlike getParentCommitInfo,
+// it doesn't finish unfinished ancestors (which may belong to jobs that are
+// still running); it returns nil if it finds one.
+func (reg *registry) getPreviousOutputCommitInfo(commitInfo *pfs.CommitInfo) (*pfs.CommitInfo, error) {
+	pachClient := reg.driver.PachClient()
+	for commitInfo.ParentCommit != nil {
+		parentCommitInfo, err := pachClient.PfsAPIClient.InspectCommit(pachClient.Ctx(),
+			&pfs.InspectCommitRequest{
+				Commit: commitInfo.ParentCommit,
+			})
+		if err != nil {
+			return nil, err
+		}
+		if parentCommitInfo.Finished == nil || parentCommitInfo.Pruned != nil {
+			return nil, nil
 		} else if parentCommitInfo.Trees != nil {
 			return parentCommitInfo, nil
 		}
@@ -579,10 +748,23 @@ func (reg *registry) ensureJob(
 	if len(jobInfos) > 1 {
 		return nil, errors.Errorf("multiple jobs found for commit: %s/%s", commitInfo.Commit.Repo.Name, commitInfo.Commit.ID)
 	} else if len(jobInfos) < 1 {
-		job, err := pachClient.CreateJob(reg.driver.PipelineInfo().Pipeline.Name, commitInfo.Commit, statsCommit)
+		// The job's volumes are fingerprinted once, when it's created, so that
+		// all of its datums are hashed with the same fingerprints
+		fingerprints, err := volumeFingerprints(pachClient.Ctx(), reg.driver.PipelineInfo().Input)
 		if err != nil {
 			return nil, err
 		}
+		// RunPipeline records an overridden seed salt in the output commit
+		job, err := pachClient.PpsAPIClient.CreateJob(pachClient.Ctx(), &pps.CreateJobRequest{
+			Pipeline:           reg.driver.PipelineInfo().Pipeline,
+			OutputCommit:       commitInfo.Commit,
+			StatsCommit:        statsCommit,
+			SeedSalt:           commitInfo.Metadata[client.SeedSaltMetadataKey],
+			VolumeFingerprints: fingerprints,
+		})
+		if err != nil {
+			return nil, grpcutil.ScrubGRPC(err)
+		}
 		reg.logger.Logf("created new job %q for output commit %q", job.ID, commitInfo.Commit.ID)
 		// get jobInfo to look up spec commit, pipeline version, etc (if this
 		// worker is stale and about to be killed, the new job may have a newer
@@ -631,6 +813,7 @@ func (reg *registry) startJob(commitInfo *pfs.CommitInfo, statsCommit *pfs.Commi
 	// too many already
 	pj := &pendingJob{
 		driver:          driver,
+		listings:        reg.listings,
 		commitInfo:      commitInfo,
 		statsCommitInfo: statsCommitInfo,
 		logger:          reg.logger.WithJob(jobInfo.Job.ID),
@@ -641,7 +824,7 @@ func (reg *registry) startJob(commitInfo *pfs.CommitInfo, statsCommit *pfs.Commi
 	switch {
 	case ppsutil.IsTerminal(jobInfo.State):
 		// Make sure the output commits are closed
-		if err := recoverFinishedJob(pj.driver.PipelineInfo(), pj.driver.PachClient(), pj.ji, jobInfo.State, "", nil, nil, 0, nil, 0); err != nil {
+		if err := recoverFinishedJob(pj.driver.PipelineInfo(), pj.driver.PachClient(), pj.ji, jobInfo.State, "", nil, nil, 0, nil, nil, 0, "", nil, nil); err != nil {
 			return err
 		}
 		// ignore finished jobs (e.g. old pipeline & already killed)
@@ -713,7 +896,7 @@ func (reg *registry) startJob(commitInfo *pfs.CommitInfo, statsCommit *pfs.Commi
 		if pj.ji.JobTimeout != nil {
 			pj.logger.Logf("cancelling job at: %+v", afterTime)
 			timer := time.AfterFunc(afterTime, func() {
-				reg.killJob(pj, "job timed out")
+				reg.killJob(pj, ppsutil.NewJobReason(pps.FailureCause_FAILURE_TIMEOUT, "job timed out"))
 
 				// We cancel egress after the timeout, but we don't cancel egress if the
 				// job's output commit is closed - that is both how jobs complete and
@@ -744,7 +927,7 @@ func (reg *registry) startJob(commitInfo *pfs.CommitInfo, statsCommit *pfs.Commi
 		defer mutex.Unlock()
 
 		// This runs the callback asynchronously, but we want to block the errgroup until it completes
-		if err := reg.taskQueue.RunTask(pj.driver.PachClient().Ctx(), func(master *work.Master) {
+		if err := reg.taskQueue.RunTaskWithID(pj.driver.PachClient().Ctx(), pj.ji.Job.ID, func(master *work.Master) {
 			defer mutex.Unlock()
 			pj.taskMaster = master
 
@@ -842,7 +1025,7 @@ func (reg *registry) superviseJob(pj *pendingJob) error {
 			defer pj.cancel() // whether we return error or nil, job is done
 
 			// Stop the job and clean up any job state in the registry
-			if err := reg.killJob(pj, "output commit missing"); err != nil {
+			if err := reg.killJob(pj, ppsutil.NewJobReason(pps.FailureCause_FAILURE_NONE, "output commit missing")); err != nil {
 				return err
 			}
 
@@ -866,7 +1049,7 @@ func (reg *registry) superviseJob(pj *pendingJob) error {
 	// TODO: why don't we cancel in all cases?
 	if commitInfo.Trees == nil && commitInfo.Tree == nil {
 		defer pj.cancel() // whether job state update succeeds or not, job is done
-		return reg.killJob(pj, "output commit closed")
+		return reg.killJob(pj, ppsutil.NewJobReason(pps.FailureCause_FAILURE_NONE, "output commit closed"))
 	}
 	return nil
 }
@@ -901,7 +1084,7 @@ func (reg *registry) processJobStarting(pj *pendingJob) error {
 
 	if len(failed) > 0 {
 		reason := fmt.Sprintf("inputs failed: %s", strings.Join(failed, ", "))
-		return reg.failJob(pj, reason, nil, 0)
+		return reg.failJob(pj, ppsutil.NewJobReason(pps.FailureCause_FAILURE_INPUT, reason), nil, 0)
 	}
 
 	if pj.driver.PipelineInfo().S3Out && pj.commitInfo.ParentCommit != nil {
@@ -927,100 +1110,141 @@ func (reg *registry) processJobStarting(pj *pendingJob) error {
 func (pj *pendingJob) Iterator() (datum.Iterator, error) {
 	var dit datum.Iterator
 	err := pj.logger.LogStep("constructing datum iterator", func() (err error) {
-		dit, err = datum.NewIterator(pj.driver.PachClient(), pj.ji.Input)
+		dit, err = datum.NewIteratorWithListings(pj.driver.PachClient(), pj.ji.Input, pj.listings)
 		return
 	})
 	return dit, err
 }
 
 func (reg *registry) processJobRunning(pj *pendingJob) error {
-	pj.logger.Logf("processJobRunning creating task channel")
-	subtasks := make(chan *work.Task, 10)
-
-	eg, ctx := errgroup.WithContext(reg.driver.PachClient().Ctx())
-
-	// Spawn a goroutine to emit tasks on the datum task channel
-	eg.Go(func() error {
-		defer close(subtasks)
-		return pj.logger.LogStep("collecting datums for tasks", func() error {
-			for {
-				numDatums, err := pj.jdit.NextBatch(ctx)
-				if err != nil {
-					return err
-				}
-				if numDatums == 0 {
-					return nil
-				}
-
-				if err := reg.sendDatumTasks(ctx, pj, numDatums, subtasks); err != nil {
-					return err
-				}
-			}
-		})
-	})
-
 	mutex := &sync.Mutex{}
 	stats := &DatumStats{ProcessStats: &pps.ProcessStats{}}
 	chunkHashtrees := []*HashtreeInfo{}
 	statsHashtrees := []*HashtreeInfo{}
 	recoveredObjects := []string{}
 
-	// Run subtasks until we are done
-	eg.Go(func() error {
-		return pj.logger.LogStep("running datum tasks", func() error {
-			return pj.taskMaster.RunSubtasksChan(
-				subtasks,
-				func(ctx context.Context, taskInfo *work.TaskInfo) error {
-					if taskInfo.State == work.State_FAILURE {
-						return errors.Errorf("datum task failed: %s", taskInfo.Reason)
-					}
+	// runTasks runs the datum tasks that 'produce' emits on the datum task
+	// channel until they're all done
+	runTasks := func(produce func(context.Context, chan<- *work.Task) error) error {
+		pj.logger.Logf("processJobRunning creating task channel")
+		subtasks := make(chan *work.Task, 10)
 
-					data, err := deserializeDatumData(taskInfo.Task.Data)
-					if err != nil {
-						return err
-					}
+		eg, ctx := errgroup.WithContext(reg.driver.PachClient().Ctx())
 
-					mutex.Lock()
-					defer mutex.Unlock()
+		// Spawn a goroutine to emit tasks on the datum task channel
+		eg.Go(func() error {
+			defer close(subtasks)
+			return produce(ctx, subtasks)
+		})
 
-					mergeStats(stats, data.Stats)
+		// Run subtasks until we are done
+		eg.Go(func() error {
+			return pj.logger.LogStep("running datum tasks", func() error {
+				return pj.taskMaster.RunSubtasksChan(
+					subtasks,
+					func(ctx context.Context, taskInfo *work.TaskInfo) error {
+						if taskInfo.State == work.State_FAILURE {
+							return errors.Errorf("datum task failed: %s", taskInfo.Reason)
+						}
+
+						data, err := deserializeDatumData(taskInfo.Task.Data)
+						if err != nil {
+							return err
+						}
+
+						mutex.Lock()
+						defer mutex.Unlock()
+
+						mergeStats(stats, data.Stats)
+						pj.ji.EvictionRetries += taskInfo.Requeues
+
+						if data.ChunkHashtree != nil {
+							chunkHashtrees = append(chunkHashtrees, data.ChunkHashtree)
+						}
+						if data.StatsHashtree != nil {
+							statsHashtrees = append(statsHashtrees, data.StatsHashtree)
+						}
+						if data.RecoveredDatumsObject != "" {
+							recoveredObjects = append(recoveredObjects, data.RecoveredDatumsObject)
+						}
+						// propagate the stats to etcd
+						pj.saveJobStats(stats)
+						return pj.writeJobInfo()
+					},
+				)
+			})
+		})
+
+		// If these was no failed datum, we can reattempt later
+		return errors.Wrap(eg.Wait(), "process datum error")
+	}
 
-					if data.ChunkHashtree != nil {
-						chunkHashtrees = append(chunkHashtrees, data.ChunkHashtree)
+	// Process the job's smoke test sample (if any) first, so that a broken
+	// transform fails the job before the rest of its datums are processed
+	var smokeTestRest int64
+	if err := runTasks(func(ctx context.Context, subtasks chan<- *work.Task) error {
+		return pj.logger.LogStep("collecting smoke test datums for tasks", func() (err error) {
+			smokeTestRest, err = reg.sendSmokeTestTasks(ctx, pj, subtasks)
+			return err
+		})
+	}); err != nil {
+		return err
+	}
+	if stats.FailedDatumID == "" {
+		if err := reg.finishSmokeTest(pj, smokeTestRest); err != nil {
+			return err
+		}
+		if err := runTasks(func(ctx context.Context, subtasks chan<- *work.Task) error {
+			return pj.logger.LogStep("collecting datums for tasks", func() error {
+				for {
+					numDatums, err := pj.jdit.NextBatch(ctx)
+					if err != nil {
+						return err
 					}
-					if data.StatsHashtree != nil {
-						statsHashtrees = append(statsHashtrees, data.StatsHashtree)
+					if numDatums == 0 {
+						return nil
 					}
-					if data.RecoveredDatumsObject != "" {
-						recoveredObjects = append(recoveredObjects, data.RecoveredDatumsObject)
+
+					if err := reg.sendDatumTasks(ctx, pj, numDatums, subtasks); err != nil {
+						return err
 					}
-					// propagate the stats to etcd
-					pj.saveJobStats(stats)
-					return pj.writeJobInfo()
-				},
-			)
-		})
-	})
+				}
+			})
+		}); err != nil {
+			return err
+		}
+	}
 
-	err := eg.Wait()
-	if err != nil {
-		// If these was no failed datum, we can reattempt later
-		return errors.Wrap(err, "process datum error")
+	if stats.FailedDatumCause == pps.FailureCause_FAILURE_OOM_KILLED {
+		if escalated, err := reg.escalateMemory(pj); err != nil {
+			return err
+		} else if escalated {
+			// The workers (including this one) are about to be restarted, and
+			// the job will be resumed by the new ones
+			return errors.Errorf("datum %s was OOM-killed; retrying it on workers with more memory", stats.FailedDatumID)
+		}
 	}
 
 	if stats.FailedDatumID != "" {
 		// A datum failed, but we still may need to merge stats - discard chunk hashtrees
 		chunkHashtrees = []*HashtreeInfo{}
+		// Recorded now, as the job isn't failed until it's done merging
+		pj.ji.FailureCause = stats.FailedDatumCause
+		message := "datum failed"
+		if pj.ji.SmokeTest != nil && pj.ji.SmokeTest.Passed == nil {
+			message = "datum failed in smoke test"
+		}
+		pj.ji.ReasonDetails = ppsutil.NewJobReason(stats.FailedDatumCause, message, stats.FailedDatumSample...)
 	}
 
 	// S3Out pipelines don't use hashtrees, so skip over the MERGING state - this
 	// will go to EGRESSING, if applicable.
 	if pj.driver.PipelineInfo().S3Out {
 		if stats.FailedDatumID != "" {
-			return reg.failJob(pj, "datum failed", nil, 0)
+			return reg.failJob(pj, pj.ji.ReasonDetails, nil, 0)
 		}
 		pj.logger.Logf("processJobRunning succeeding s3out job, total stats: %v", stats)
-		return reg.succeedJob(pj, nil, 0, nil, 0)
+		return reg.succeedJob(pj, nil, 0, nil, nil, 0)
 	}
 
 	// Write the hashtrees list and recovered datums list to object storage
@@ -1045,6 +1269,7 @@ func (pj *pendingJob) saveJobStats(stats *DatumStats) {
 	pj.ji.DataRecovered = stats.DatumsRecovered
 	pj.ji.DataTotal = int64(pj.jdit.MaxLen())
 	pj.ji.Stats = stats.ProcessStats
+	pj.ji.LargestDatums = stats.LargestDatums
 }
 
 func (pj *pendingJob) finalizeJobStats() {
@@ -1225,6 +1450,21 @@ func (reg *registry) makeMergeSubtasks(pj *pendingJob, commitInfo *pfs.CommitInf
 		}
 	}
 
+	// Output commits are compared to the previous output commit, to
+	// summarize the paths that changed for downstream pipelines
+	var previousHashtrees []*pfs.Object
+	if !stats {
+		pj.changedPathsBase = nil
+		previousCommitInfo, err := reg.getPreviousOutputCommitInfo(commitInfo)
+		if err != nil {
+			return nil, err
+		}
+		if previousCommitInfo != nil && len(previousCommitInfo.Trees) == int(reg.driver.NumShards()) {
+			previousHashtrees = previousCommitInfo.Trees
+			pj.changedPathsBase = previousCommitInfo.Commit
+		}
+	}
+
 	mergeSubtasks := []*work.Task{}
 	for i := int64(0); i < reg.driver.NumShards(); i++ {
 		mergeData := &MergeData{Hashtrees: hashtrees, Shard: i, JobID: pj.ji.Job.ID, Stats: stats}
@@ -1232,6 +1472,9 @@ func (reg *registry) makeMergeSubtasks(pj *pendingJob, commitInfo *pfs.CommitInf
 		if parentHashtrees != nil {
 			mergeData.Parent = parentHashtrees[i]
 		}
+		if previousHashtrees != nil {
+			mergeData.Previous = previousHashtrees[i]
+		}
 
 		data, err := serializeMergeData(mergeData)
 		if err != nil {
@@ -1273,6 +1516,7 @@ func (reg *registry) processJobMerging(pj *pendingJob) error {
 
 	trees := make([]*pfs.Object, reg.driver.NumShards())
 	size := uint64(0)
+	var changedPaths []string
 	statsTrees := make([]*pfs.Object, reg.driver.NumShards())
 	statsSize := uint64(0)
 
@@ -1304,6 +1548,7 @@ func (reg *registry) processJobMerging(pj *pendingJob) error {
 			} else {
 				trees[data.Shard] = data.Tree
 				size += data.TreeSize
+				changedPaths = append(changedPaths, data.ChangedPaths...)
 			}
 			return nil
 		},
@@ -1315,15 +1560,29 @@ func (reg *registry) processJobMerging(pj *pendingJob) error {
 	pj.logger.Logf("merge results: %v trees (%d bytes), %v stats trees (%d bytes)", trees, size, statsTrees, statsSize)
 
 	if pj.ji.DataFailed == 0 {
-		if err := reg.succeedJob(pj, trees, size, statsTrees, statsSize); err != nil {
+		var summary *pfs.ChangedPaths
+		if pj.changedPathsBase != nil {
+			summary = summarizeChangedPaths(pj.changedPathsBase, changedPaths)
+		}
+		if err := reg.succeedJob(pj, trees, size, summary, statsTrees, statsSize); err != nil {
 			return err
 		}
-	} else if err := reg.failJob(pj, "datum failed", statsTrees, statsSize); err != nil {
+	} else if err := reg.failJob(pj, datumFailedReason(pj.ji), statsTrees, statsSize); err != nil {
 		return err
 	}
 	return nil
 }
 
+// datumFailedReason returns the reason for failing 'jobInfo', whose datums
+// failed. The reason is normally recorded (with a sample of the failed datums)
+// while the job is running, but jobs from older workers may lack it.
+func datumFailedReason(jobInfo *pps.JobInfo) *pps.JobReason {
+	if jobInfo.ReasonDetails != nil {
+		return jobInfo.ReasonDetails
+	}
+	return ppsutil.NewJobReason(jobInfo.FailureCause, "datum failed")
+}
+
 func (reg *registry) storeJobDatums(pj *pendingJob) (*pfs.Object, error) {
 	// Update recovered datums through the job chain, which will update its internal DatumSet
 	recoveredDatums, err := pj.loadRecoveredDatums()
@@ -1351,7 +1610,7 @@ func (reg *registry) storeJobDatums(pj *pendingJob) (*pfs.Object, error) {
 
 func (reg *registry) processJobEgress(pj *pendingJob) error {
 	if err := reg.egress(pj); err != nil {
-		return reg.failJob(pj, fmt.Sprintf("egress error: %v", err), nil, 0)
+		return reg.failJob(pj, ppsutil.NewJobReason(pps.FailureCause_FAILURE_EGRESS, fmt.Sprintf("egress error: %v", err)), nil, 0)
 	}
 
 	pj.ji.State = pps.JobState_JOB_SUCCESS
@@ -1388,6 +1647,18 @@ func failedInputs(pachClient *client.APIClient, jobInfo *pps.JobInfo) ([]string,
 		if input.Git != nil && input.Git.Commit != "" {
 			blockCommit(input.Git.Name, client.NewCommit(input.Git.Name, input.Git.Commit))
 		}
+		if input.HTTPMirror != nil && input.HTTPMirror.Commit != "" {
+			blockCommit(input.HTTPMirror.Name, client.NewCommit(input.HTTPMirror.Repo, input.HTTPMirror.Commit))
+		}
+		if input.Parameter != nil && input.Parameter.Commit != "" {
+			blockCommit(input.Parameter.Name, client.NewCommit(input.Parameter.Repo, input.Parameter.Commit))
+		}
+		if input.RemoteRepo != nil && input.RemoteRepo.Commit != "" {
+			blockCommit(input.RemoteRepo.Name, client.NewCommit(input.RemoteRepo.Repo, input.RemoteRepo.Commit))
+		}
+		if input.DBImport != nil && input.DBImport.Commit != "" {
+			blockCommit(input.DBImport.Name, client.NewCommit(input.DBImport.Repo, input.DBImport.Commit))
+		}
 	})
 	return failed, vistErr
 }
@@ -1397,7 +1668,7 @@ func (reg *registry) egress(pj *pendingJob) error {
 	return backoff.RetryNotify(func() (retErr error) {
 		if pj.ji.Egress != nil {
 			return pj.logger.LogStep("egress upload", func() error {
-				return pj.driver.Egress(pj.ji.OutputCommit, pj.ji.Egress.URL)
+				return pj.driver.Egress(pj.ji.OutputCommit, pj.ji.Egress)
 			})
 		}
 		return nil
@@ -0,0 +1,45 @@
+package transform
+
+import (
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+)
+
+// defaultMaxDatumBackoff is the longest wait between a datum's retries, if
+// the pipeline's RetrySpec doesn't set one
+const defaultMaxDatumBackoff = 10 * time.Minute
+
+// datumBackOff returns the backoff with which a pipeline's failed datums are
+// retried. Without a RetrySpec, they're retried immediately.
+func datumBackOff(retrySpec *pps.RetrySpec) (backoff.BackOff, error) {
+	if retrySpec == nil {
+		return &backoff.ZeroBackOff{}, nil
+	}
+	b := &backoff.ExponentialBackOff{
+		RandomizationFactor: retrySpec.Jitter,
+		Multiplier:          2,
+		MaxInterval:         defaultMaxDatumBackoff,
+		Clock:               backoff.SystemClock,
+	}
+	if retrySpec.BackoffBase != nil {
+		base, err := types.DurationFromProto(retrySpec.BackoffBase)
+		if err != nil {
+			return nil, errors.EnsureStack(err)
+		}
+		b.InitialInterval = base
+	}
+	if retrySpec.MaxBackoff != nil {
+		max, err := types.DurationFromProto(retrySpec.MaxBackoff)
+		if err != nil {
+			return nil, errors.EnsureStack(err)
+		}
+		b.MaxInterval = max
+	}
+	b.Reset()
+	return b, nil
+}
@@ -0,0 +1,41 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestDatumBackOff(t *testing.T) {
+	// Without a RetrySpec, datums are retried immediately
+	b, err := datumBackOff(nil)
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(0), b.NextBackOff())
+
+	// The wait doubles with each retry, up to max_backoff
+	b, err = datumBackOff(&pps.RetrySpec{
+		MaxRetries:  5,
+		BackoffBase: types.DurationProto(time.Second),
+		MaxBackoff:  types.DurationProto(5 * time.Second),
+	})
+	require.NoError(t, err)
+	var waits []time.Duration
+	for i := 0; i < 5; i++ {
+		waits = append(waits, b.NextBackOff())
+	}
+	require.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, waits)
+
+	// Jitter randomizes each wait by up to that fraction
+	b, err = datumBackOff(&pps.RetrySpec{
+		MaxRetries:  5,
+		BackoffBase: types.DurationProto(10 * time.Second),
+		Jitter:      0.5,
+	})
+	require.NoError(t, err)
+	wait := b.NextBackOff()
+	require.True(t, wait >= 5*time.Second && wait <= 15*time.Second, wait)
+}
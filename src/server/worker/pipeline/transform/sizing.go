@@ -0,0 +1,152 @@
+package transform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/worker/common"
+)
+
+// sizingTimeout is how long a pipeline's sizing command may take to estimate
+// the needs of a datum.
+const sizingTimeout = time.Minute
+
+// sizingFile describes one of a datum's files to a pipeline's sizing command
+// (see pps.DatumSizing).
+type sizingFile struct {
+	Input     string `json:"input"`
+	Repo      string `json:"repo,omitempty"`
+	Commit    string `json:"commit,omitempty"`
+	Path      string `json:"path"`
+	SizeBytes uint64 `json:"size_bytes"`
+}
+
+// datumEstimate is what a pipeline's sizing command estimates that a datum
+// needs.
+type datumEstimate struct {
+	Memory string  `json:"memory"`
+	CPU    float64 `json:"cpu"`
+}
+
+// estimateDatum runs the sizing command of 'pipelineInfo' for the datum with
+// 'inputs', and returns its estimate of the datum's needs.
+func estimateDatum(ctx context.Context, pipelineInfo *pps.PipelineInfo, inputs []*common.Input) (*datumEstimate, error) {
+	sizing := pipelineInfo.DatumSizing
+	var stdin bytes.Buffer
+	encoder := json.NewEncoder(&stdin)
+	for _, input := range inputs {
+		file := sizingFile{Input: input.Name}
+		if fileInfo := input.FileInfo; fileInfo != nil {
+			file.Path = fileInfo.File.Path
+			file.SizeBytes = fileInfo.SizeBytes
+			if commit := fileInfo.File.Commit; commit != nil {
+				file.Repo = commit.Repo.Name
+				file.Commit = commit.ID
+			}
+		}
+		if err := encoder.Encode(&file); err != nil {
+			return nil, errors.EnsureStack(err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, sizingTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, sizing.Cmd[0], sizing.Cmd[1:]...)
+	cmd.Dir = pipelineInfo.Transform.WorkingDir
+	cmd.Stdin = &stdin
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "sizing command failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	estimate := &datumEstimate{}
+	if err := json.Unmarshal(stdout.Bytes(), estimate); err != nil {
+		return nil, errors.Wrapf(err, "could not parse the sizing command's output %q", strings.TrimSpace(stdout.String()))
+	}
+	return estimate, nil
+}
+
+// fits returns true if workers with 'requests' and 'limits' have the
+// resources in 'estimate'. A worker's resource is its limit, or its request if
+// it has no limit, and workers without either don't fit an estimate of it.
+func fits(requests, limits *pps.ResourceSpec, estimate *datumEstimate) (bool, error) {
+	if estimate.Memory != "" {
+		needed, err := resource.ParseQuantity(estimate.Memory)
+		if err != nil {
+			return false, errors.Wrapf(err, "could not parse estimated memory %q", estimate.Memory)
+		}
+		memory := limits.GetMemory()
+		if memory == "" {
+			memory = requests.GetMemory()
+		}
+		if memory == "" {
+			return false, nil
+		}
+		available, err := resource.ParseQuantity(memory)
+		if err != nil {
+			return false, errors.Wrapf(err, "could not parse memory %q", memory)
+		}
+		if available.Cmp(needed) < 0 {
+			return false, nil
+		}
+	}
+	if estimate.CPU > 0 {
+		cpu := limits.GetCpu()
+		if cpu == 0 {
+			cpu = requests.GetCpu()
+		}
+		if float64(cpu) < estimate.CPU {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// assignWorkerClass returns the worker class of 'pipelineInfo' whose workers
+// process a datum with 'estimate' ("" for the pipeline's own workers).
+func assignWorkerClass(pipelineInfo *pps.PipelineInfo, estimate *datumEstimate) (string, error) {
+	if ok, err := fits(pipelineInfo.ResourceRequests, pipelineInfo.ResourceLimits, estimate); err != nil || ok {
+		return "", err
+	}
+	classes := pipelineInfo.DatumSizing.WorkerClasses
+	for _, class := range classes {
+		requests, limits := class.ResourceRequests, class.ResourceLimits
+		if requests == nil {
+			requests = pipelineInfo.ResourceRequests
+		}
+		if limits == nil {
+			limits = pipelineInfo.ResourceLimits
+		}
+		if ok, err := fits(requests, limits, estimate); err != nil {
+			return "", err
+		} else if ok {
+			return class.Name, nil
+		}
+	}
+	// None of the classes fit the datum, so it's given the largest
+	return classes[len(classes)-1].Name, nil
+}
+
+// datumWorkerClass returns the worker class whose workers process the datum
+// with 'inputs' (see pps.DatumSizing). Datums that can't be sized are
+// processed by the pipeline's own workers.
+func (pj *pendingJob) datumWorkerClass(ctx context.Context, inputs []*common.Input) string {
+	pipelineInfo := pj.driver.PipelineInfo()
+	estimate, err := estimateDatum(ctx, pipelineInfo, inputs)
+	if err == nil {
+		var class string
+		if class, err = assignWorkerClass(pipelineInfo, estimate); err == nil {
+			return class
+		}
+	}
+	pj.logger.Logf("could not size datum (it will be processed by the pipeline's own workers): %v", err)
+	return ""
+}
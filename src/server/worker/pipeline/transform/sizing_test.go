@@ -0,0 +1,73 @@
+package transform
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/worker/common"
+)
+
+func TestEstimateDatum(t *testing.T) {
+	// The sizing command estimates 1G of memory per line of input
+	pipelineInfo := &pps.PipelineInfo{
+		Transform: &pps.Transform{},
+		DatumSizing: &pps.DatumSizing{
+			Cmd: []string{"sh", "-c", `echo "{\"memory\": \"$(wc -l | tr -d ' ')G\", \"cpu\": 0.5}"`},
+		},
+	}
+	inputs := []*common.Input{
+		{Name: "images", FileInfo: &pfs.FileInfo{File: client.NewFile("images", "abc", "/a.png"), SizeBytes: 10}},
+		{Name: "labels", FileInfo: &pfs.FileInfo{File: client.NewFile("labels", "def", "/a.json"), SizeBytes: 20}},
+	}
+	estimate, err := estimateDatum(context.Background(), pipelineInfo, inputs)
+	require.NoError(t, err)
+	require.Equal(t, &datumEstimate{Memory: "2G", CPU: 0.5}, estimate)
+
+	pipelineInfo.DatumSizing.Cmd = []string{"sh", "-c", "echo not json"}
+	_, err = estimateDatum(context.Background(), pipelineInfo, inputs)
+	require.YesError(t, err)
+	pipelineInfo.DatumSizing.Cmd = []string{"sh", "-c", "echo failed >&2; exit 1"}
+	_, err = estimateDatum(context.Background(), pipelineInfo, inputs)
+	require.YesError(t, err)
+	require.Matches(t, "failed", err.Error())
+}
+
+func TestAssignWorkerClass(t *testing.T) {
+	pipelineInfo := &pps.PipelineInfo{
+		ResourceRequests: &pps.ResourceSpec{Memory: "1G", Cpu: 1},
+		ResourceLimits:   &pps.ResourceSpec{Memory: "2G"},
+		DatumSizing: &pps.DatumSizing{
+			WorkerClasses: []*pps.WorkerClass{
+				{Name: "medium", ResourceLimits: &pps.ResourceSpec{Memory: "8G"}},
+				{Name: "large", ResourceRequests: &pps.ResourceSpec{Memory: "32G", Cpu: 8}, ResourceLimits: &pps.ResourceSpec{Memory: "32G"}},
+			},
+		},
+	}
+	for estimate, class := range map[datumEstimate]string{
+		{}:                        "",
+		{Memory: "2G"}:            "",
+		{Memory: "2G", CPU: 1}:    "",
+		{Memory: "2G", CPU: 2}:    "large",
+		{Memory: "3G"}:            "medium",
+		{Memory: "8G"}:            "medium",
+		{Memory: "9G"}:            "large",
+		{Memory: "64G", CPU: 100}: "large",
+	} {
+		estimate := estimate
+		assigned, err := assignWorkerClass(pipelineInfo, &estimate)
+		require.NoError(t, err)
+		require.Equal(t, class, assigned, "estimate: %v", estimate)
+	}
+	_, err := assignWorkerClass(pipelineInfo, &datumEstimate{Memory: "lots"})
+	require.YesError(t, err)
+
+	// Workers without a memory request or limit don't fit a memory estimate
+	pipelineInfo.ResourceRequests, pipelineInfo.ResourceLimits = nil, nil
+	assigned, err := assignWorkerClass(pipelineInfo, &datumEstimate{Memory: "1M"})
+	require.NoError(t, err)
+	require.Equal(t, "medium", assigned)
+}
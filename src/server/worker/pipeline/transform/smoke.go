@@ -0,0 +1,84 @@
+package transform
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/work"
+)
+
+// smokeTestCheckInterval is how often a job that's paused after its smoke
+// test checks whether it has been approved
+const smokeTestCheckInterval = 10 * time.Second
+
+// sendSmokeTestTasks emits the datum tasks for the smoke test sample of a job
+// whose pipeline has a smoke test, and returns the number of the job's
+// available datums that aren't in the sample. Nothing is emitted if the job's
+// smoke test already passed (i.e. the job is being resumed).
+func (reg *registry) sendSmokeTestTasks(ctx context.Context, pj *pendingJob, subtasks chan<- *work.Task) (int64, error) {
+	smokeTest := pj.driver.PipelineInfo().SmokeTest
+	if smokeTest == nil || pj.ji.SmokeTest.GetPassed() != nil {
+		return 0, nil
+	}
+	numDatums, err := pj.jdit.NextBatch(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n := ppsutil.SmokeTestDatums(smokeTest, numDatums)
+	pj.ji.SmokeTest = &pps.SmokeTestStatus{Datums: n}
+	if n == 0 {
+		return 0, nil
+	}
+	pj.logger.Logf("processing %d of %d datums as the job's smoke test", n, numDatums)
+	if err := reg.sendDatumTasks(ctx, pj, n, subtasks); err != nil {
+		return 0, err
+	}
+	return numDatums - n, nil
+}
+
+// finishSmokeTest records that the smoke test of a job passed and, if its
+// pipeline's smoke test pauses and 'rest' of the job's datums are left, blocks
+// until the job is approved with ApproveCommit.
+func (reg *registry) finishSmokeTest(pj *pendingJob, rest int64) error {
+	status := pj.ji.SmokeTest
+	if status == nil {
+		return nil
+	}
+	if status.Passed == nil {
+		status.Passed = types.TimestampNow()
+		if err := pj.writeJobInfo(); err != nil {
+			return err
+		}
+		pj.logger.Logf("the job's smoke test of %d datums passed", status.Datums)
+	}
+	if !ppsutil.SmokeTestPaused(pj.driver.PipelineInfo().SmokeTest, status) {
+		return nil
+	}
+	if rest == 0 && status.Datums > 0 {
+		// The sample covered every datum, so there's nothing to approve
+		return nil
+	}
+	pachClient := pj.driver.PachClient()
+	pj.logger.Logf("pausing after the smoke test until output commit %s is approved", pj.ji.OutputCommit.ID)
+	for {
+		select {
+		case <-time.After(smokeTestCheckInterval):
+		case <-pachClient.Ctx().Done():
+			return errors.EnsureStack(pachClient.Ctx().Err())
+		}
+		jobInfo, err := pachClient.InspectJob(pj.ji.Job.ID, false)
+		if err != nil {
+			return err
+		}
+		if approved := jobInfo.SmokeTest.GetApproved(); approved != nil {
+			pj.logger.Logf("output commit %s was approved, processing the rest of the job's datums", pj.ji.OutputCommit.ID)
+			status.Approved = approved
+			return nil
+		}
+	}
+}
@@ -21,9 +21,11 @@ type Status struct {
 	queueSize     *int64
 	dataProcessed *int64
 	dataRecovered *int64
+	phases        *datumPhases
 	datum         []*pps.InputFile
 	cancel        func()
 	started       time.Time
+	draining      bool
 }
 
 func convertInputs(inputs []*common.Input) []*pps.InputFile {
@@ -55,12 +57,13 @@ func (s *Status) withJob(jobID string, cb func() error) error {
 	return cb()
 }
 
-func (s *Status) withStats(stats *pps.ProcessStats, queueSize *int64, dataProcessed, dataRecovered *int64, cb func() error) error {
+func (s *Status) withStats(stats *pps.ProcessStats, queueSize *int64, dataProcessed, dataRecovered *int64, phases *datumPhases, cb func() error) error {
 	s.withLock(func() {
 		s.stats = stats
 		s.queueSize = queueSize
 		s.dataProcessed = dataProcessed
 		s.dataRecovered = dataRecovered
+		s.phases = phases
 	})
 
 	defer s.withLock(func() {
@@ -68,6 +71,7 @@ func (s *Status) withStats(stats *pps.ProcessStats, queueSize *int64, dataProces
 		s.queueSize = nil
 		s.dataProcessed = nil
 		s.dataRecovered = nil
+		s.phases = nil
 	})
 
 	return cb()
@@ -89,6 +93,21 @@ func (s *Status) withDatum(inputs []*common.Input, cancel func(), cb func() erro
 	return cb()
 }
 
+// Drain stops the worker from starting any more datums in its current datum
+// task. Datums that are already running are finished, and the task's remaining
+// datums are handed to another worker.
+func (s *Status) Drain() {
+	s.withLock(func() {
+		s.draining = true
+	})
+}
+
+func (s *Status) isDraining() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.draining
+}
+
 // GetStatus returns the current WorkerStatus for the transform worker
 func (s *Status) GetStatus() (*pps.WorkerStatus, error) {
 	s.mutex.Lock()
@@ -112,6 +131,11 @@ func (s *Status) GetStatus() (*pps.WorkerStatus, error) {
 	if s.dataRecovered != nil {
 		result.DataRecovered = atomic.LoadInt64(s.dataRecovered)
 	}
+	if s.phases != nil {
+		result.Download = s.phases.download.stats()
+		result.Process = s.phases.process.stats()
+		result.Upload = s.phases.upload.stats()
+	}
 	return result, nil
 }
 
@@ -73,12 +73,12 @@ func forEachCommit(
 					if !ppsutil.IsTerminal(ji.State) {
 						if ci.Trees == nil && ci.Tree == nil {
 							ji.State = pps.JobState_JOB_KILLED
-							ji.Reason = "output commit is finished without data, but job state has not been updated"
+							ji.ReasonDetails = ppsutil.NewJobReason(pps.FailureCause_FAILURE_NONE, "output commit is finished without data, but job state has not been updated")
 						} else {
 							ji.State = pps.JobState_JOB_SUCCESS
 						}
 
-						if err := finishJob(pi, pachClient, ji, ji.State, ji.Reason, nil, nil, 0, nil, 0); err != nil {
+						if err := finishJob(pi, pachClient, ji, ji.State, ji.ReasonDetails, nil, nil, 0, nil, nil, 0); err != nil {
 							return errors.Wrap(err, "could not update job with finished output commit")
 						}
 					}
@@ -102,6 +102,17 @@ func Run(driver driver.Driver, logger logs.TaggedLogger) error {
 	// after them, bubbling up errors, canceling
 
 	return forEachCommit(driver, func(commitInfo *pfs.CommitInfo, statsCommit *pfs.Commit) error {
+		unaligned, err := checkAlignment(driver.PachClient(), driver.PipelineInfo(), commitInfo)
+		if err != nil {
+			return err
+		}
+		if unaligned != "" {
+			logger.Logf("not running a job for output commit %s: %s", commitInfo.Commit.ID, unaligned)
+			return skipUnalignedCommit(driver.PachClient(), commitInfo, statsCommit, unaligned)
+		}
+		if open, err := waitForJobGates(driver, logger, commitInfo, statsCommit); err != nil || !open {
+			return err
+		}
 		return reg.startJob(commitInfo, statsCommit)
 	})
 }
@@ -351,15 +351,20 @@ func (m *HashtreeInfo) GetObject() string {
 }
 
 type DatumStats struct {
-	ProcessStats         *pps.ProcessStats `protobuf:"bytes,1,opt,name=process_stats,json=processStats,proto3" json:"process_stats,omitempty"`
-	DatumsProcessed      int64             `protobuf:"varint,2,opt,name=datums_processed,json=datumsProcessed,proto3" json:"datums_processed,omitempty"`
-	DatumsSkipped        int64             `protobuf:"varint,3,opt,name=datums_skipped,json=datumsSkipped,proto3" json:"datums_skipped,omitempty"`
-	DatumsFailed         int64             `protobuf:"varint,5,opt,name=datums_failed,json=datumsFailed,proto3" json:"datums_failed,omitempty"`
-	DatumsRecovered      int64             `protobuf:"varint,6,opt,name=datums_recovered,json=datumsRecovered,proto3" json:"datums_recovered,omitempty"`
-	FailedDatumID        string            `protobuf:"bytes,8,opt,name=failed_datum_id,json=failedDatumId,proto3" json:"failed_datum_id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	ProcessStats     *pps.ProcessStats `protobuf:"bytes,1,opt,name=process_stats,json=processStats,proto3" json:"process_stats,omitempty"`
+	DatumsProcessed  int64             `protobuf:"varint,2,opt,name=datums_processed,json=datumsProcessed,proto3" json:"datums_processed,omitempty"`
+	DatumsSkipped    int64             `protobuf:"varint,3,opt,name=datums_skipped,json=datumsSkipped,proto3" json:"datums_skipped,omitempty"`
+	DatumsFailed     int64             `protobuf:"varint,5,opt,name=datums_failed,json=datumsFailed,proto3" json:"datums_failed,omitempty"`
+	DatumsRecovered  int64             `protobuf:"varint,6,opt,name=datums_recovered,json=datumsRecovered,proto3" json:"datums_recovered,omitempty"`
+	FailedDatumID    string            `protobuf:"bytes,8,opt,name=failed_datum_id,json=failedDatumId,proto3" json:"failed_datum_id,omitempty"`
+	FailedDatumCause pps.FailureCause  `protobuf:"varint,9,opt,name=failed_datum_cause,json=failedDatumCause,proto3,enum=pps.FailureCause" json:"failed_datum_cause,omitempty"`
+	// The processed datums with the largest outputs, largest first
+	LargestDatums []*pps.DatumOutputSize `protobuf:"bytes,10,rep,name=largest_datums,json=largestDatums,proto3" json:"largest_datums,omitempty"`
+	// The IDs of (up to 5 of) the failed datums
+	FailedDatumSample    []string `protobuf:"bytes,11,rep,name=failed_datum_sample,json=failedDatumSample,proto3" json:"failed_datum_sample,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *DatumStats) Reset()         { *m = DatumStats{} }
@@ -437,11 +442,34 @@ func (m *DatumStats) GetFailedDatumID() string {
 	return ""
 }
 
+func (m *DatumStats) GetFailedDatumCause() pps.FailureCause {
+	if m != nil {
+		return m.FailedDatumCause
+	}
+	return pps.FailureCause_FAILURE_NONE
+}
+
+func (m *DatumStats) GetLargestDatums() []*pps.DatumOutputSize {
+	if m != nil {
+		return m.LargestDatums
+	}
+	return nil
+}
+
+func (m *DatumStats) GetFailedDatumSample() []string {
+	if m != nil {
+		return m.FailedDatumSample
+	}
+	return nil
+}
+
 type DatumData struct {
 	// Inputs
 	JobID        string      `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
 	DatumsObject string      `protobuf:"bytes,8,opt,name=datums_object,json=datumsObject,proto3" json:"datums_object,omitempty"`
 	OutputCommit *pfs.Commit `protobuf:"bytes,3,opt,name=output_commit,json=outputCommit,proto3" json:"output_commit,omitempty"`
+	// The salt from which the seeds of the job's datums are derived
+	SeedSalt string `protobuf:"bytes,9,opt,name=seed_salt,json=seedSalt,proto3" json:"seed_salt,omitempty"`
 	// Outputs
 	Stats                 *DatumStats   `protobuf:"bytes,4,opt,name=stats,proto3" json:"stats,omitempty"`
 	ChunkHashtree         *HashtreeInfo `protobuf:"bytes,5,opt,name=chunk_hashtree,json=chunkHashtree,proto3" json:"chunk_hashtree,omitempty"`
@@ -506,6 +534,13 @@ func (m *DatumData) GetOutputCommit() *pfs.Commit {
 	return nil
 }
 
+func (m *DatumData) GetSeedSalt() string {
+	if m != nil {
+		return m.SeedSalt
+	}
+	return ""
+}
+
 func (m *DatumData) GetStats() *DatumStats {
 	if m != nil {
 		return m.Stats
@@ -541,9 +576,13 @@ type MergeData struct {
 	Parent    *pfs.Object     `protobuf:"bytes,3,opt,name=parent,proto3" json:"parent,omitempty"`
 	Shard     int64           `protobuf:"varint,4,opt,name=shard,proto3" json:"shard,omitempty"`
 	Stats     bool            `protobuf:"varint,5,opt,name=stats,proto3" json:"stats,omitempty"`
+	// The shard's tree in the previous output commit, if the changed paths
+	// should be found by comparing it to the new tree
+	Previous *pfs.Object `protobuf:"bytes,8,opt,name=previous,proto3" json:"previous,omitempty"`
 	// Outputs
 	Tree                 *pfs.Object `protobuf:"bytes,6,opt,name=tree,proto3" json:"tree,omitempty"`
 	TreeSize             uint64      `protobuf:"varint,7,opt,name=tree_size,json=treeSize,proto3" json:"tree_size,omitempty"`
+	ChangedPaths         []string    `protobuf:"bytes,9,rep,name=changed_paths,json=changedPaths,proto3" json:"changed_paths,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
 	XXX_unrecognized     []byte      `json:"-"`
 	XXX_sizecache        int32       `json:"-"`
@@ -617,6 +656,13 @@ func (m *MergeData) GetStats() bool {
 	return false
 }
 
+func (m *MergeData) GetPrevious() *pfs.Object {
+	if m != nil {
+		return m.Previous
+	}
+	return nil
+}
+
 func (m *MergeData) GetTree() *pfs.Object {
 	if m != nil {
 		return m.Tree
@@ -631,6 +677,13 @@ func (m *MergeData) GetTreeSize() uint64 {
 	return 0
 }
 
+func (m *MergeData) GetChangedPaths() []string {
+	if m != nil {
+		return m.ChangedPaths
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*DatumInputs)(nil), "pachyderm.worker.pipeline.transform.DatumInputs")
 	proto.RegisterType((*DatumInputsList)(nil), "pachyderm.worker.pipeline.transform.DatumInputsList")
@@ -648,55 +701,63 @@ func init() {
 }
 
 var fileDescriptor_21583a759eb7fa97 = []byte{
-	// 760 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0xd1, 0x4e, 0xdb, 0x4a,
-	0x10, 0x55, 0x12, 0x62, 0xf0, 0x26, 0x26, 0x97, 0x15, 0xdc, 0x1b, 0x71, 0x25, 0xc2, 0x35, 0x42,
-	0x02, 0xe9, 0xca, 0x86, 0x5c, 0x09, 0xe9, 0xbe, 0x42, 0x5a, 0x11, 0xd4, 0x0a, 0xea, 0xbc, 0x54,
-	0xed, 0x83, 0xe5, 0xd8, 0x9b, 0xd8, 0x84, 0x78, 0xad, 0x5d, 0x87, 0xb6, 0xfc, 0x43, 0x3f, 0xa2,
-	0x5f, 0xd1, 0x5f, 0xe8, 0x63, 0xbf, 0x00, 0x55, 0xf9, 0x92, 0x6a, 0x67, 0xd6, 0xc1, 0xe9, 0x4b,
-	0x23, 0x1e, 0x2c, 0xef, 0x9c, 0x3d, 0x73, 0x66, 0x76, 0x66, 0xc7, 0x26, 0x27, 0x92, 0x89, 0x7b,
-	0x26, 0xdc, 0x0f, 0x5c, 0x4c, 0x98, 0x70, 0xb3, 0x24, 0x63, 0x77, 0x49, 0xca, 0xdc, 0x5c, 0x04,
-	0xa9, 0x1c, 0x71, 0x31, 0x7d, 0x5a, 0x39, 0x99, 0xe0, 0x39, 0xa7, 0x07, 0x59, 0x10, 0xc6, 0x9f,
-	0x22, 0x26, 0xa6, 0x0e, 0x3a, 0x39, 0x85, 0x93, 0xb3, 0xa0, 0xee, 0x6e, 0x8f, 0xf9, 0x98, 0x03,
-	0xdf, 0x55, 0x2b, 0x74, 0xdd, 0xdd, 0x0e, 0xef, 0x12, 0x96, 0xe6, 0x6e, 0x36, 0x92, 0xea, 0xf9,
-	0x15, 0xcd, 0xa4, 0x7a, 0x34, 0xfa, 0xcf, 0x72, 0x62, 0x21, 0x9f, 0x4e, 0x79, 0xaa, 0x5f, 0x48,
-	0xb1, 0xaf, 0x48, 0xa3, 0x17, 0xe4, 0xb3, 0x69, 0x3f, 0xcd, 0x66, 0xb9, 0xa4, 0x87, 0xc4, 0x48,
-	0x60, 0xd5, 0xae, 0xec, 0xd7, 0x8e, 0x1a, 0x5d, 0xcb, 0xd1, 0x6c, 0xd8, 0xf7, 0xf4, 0x26, 0xdd,
-	0x26, 0xf5, 0x24, 0x8d, 0xd8, 0xc7, 0x76, 0x75, 0xbf, 0x72, 0x54, 0xf3, 0xd0, 0xb0, 0xdf, 0x93,
-	0x56, 0x49, 0xeb, 0x55, 0x22, 0x73, 0x7a, 0x49, 0x8c, 0x48, 0x41, 0x85, 0xde, 0x89, 0xb3, 0xc2,
-	0xc9, 0x9d, 0x92, 0x8a, 0xa7, 0xfd, 0x95, 0xf8, 0x65, 0x20, 0xe3, 0x5c, 0x30, 0x76, 0x3d, 0xbc,
-	0x65, 0x61, 0x2e, 0xe9, 0x01, 0xb1, 0xc2, 0x78, 0x96, 0x4e, 0x7c, 0x8e, 0x00, 0xc4, 0x30, 0xbd,
-	0x26, 0x80, 0x25, 0x92, 0xcc, 0x83, 0x5c, 0x2e, 0x48, 0x55, 0x24, 0x01, 0xa8, 0x49, 0xf6, 0x31,
-	0x69, 0x79, 0x2c, 0xe4, 0xf7, 0x4c, 0xb0, 0x08, 0x82, 0x4b, 0xfa, 0x27, 0x31, 0xe2, 0x40, 0xc6,
-	0xac, 0x50, 0xd5, 0x96, 0x7d, 0x4a, 0x76, 0x96, 0xa9, 0x45, 0xa0, 0x36, 0x59, 0x5f, 0xce, 0xa3,
-	0x30, 0xed, 0x94, 0x34, 0x8b, 0xd4, 0xfb, 0xe9, 0x88, 0x2b, 0x66, 0x10, 0x45, 0x82, 0x49, 0xc5,
-	0xac, 0x28, 0xa6, 0x36, 0xe9, 0xbf, 0x84, 0xc8, 0xd9, 0x30, 0x0f, 0xe4, 0xc4, 0x4f, 0x22, 0x28,
-	0xae, 0x79, 0x6e, 0xcd, 0x1f, 0x3b, 0xe6, 0x00, 0xd1, 0x7e, 0xcf, 0x33, 0x35, 0xa1, 0x1f, 0xa9,
-	0x14, 0x31, 0x44, 0xbb, 0x06, 0x32, 0xda, 0xb2, 0xbf, 0x54, 0x09, 0x81, 0xd4, 0x06, 0xea, 0x8c,
-	0xf4, 0x8c, 0x58, 0x99, 0xe0, 0x21, 0x93, 0xd2, 0x87, 0x43, 0x43, 0xd0, 0x46, 0x77, 0xcb, 0x51,
-	0x17, 0xe5, 0x06, 0x77, 0x80, 0xe9, 0x35, 0xb3, 0x92, 0x45, 0x8f, 0xc9, 0x1f, 0x58, 0x7b, 0x5f,
-	0xc3, 0x2c, 0xd2, 0xfd, 0x6e, 0x21, 0x7e, 0x53, 0xc0, 0xf4, 0x90, 0x6c, 0x6a, 0xaa, 0x9c, 0x24,
-	0x59, 0xc6, 0x22, 0xc8, 0xa8, 0xe6, 0x59, 0x88, 0x0e, 0x10, 0x54, 0xbd, 0xd0, 0xb4, 0x51, 0x90,
-	0xdc, 0xb1, 0xa8, 0x5d, 0x07, 0x56, 0x13, 0xc1, 0x97, 0x80, 0x95, 0xc2, 0x8a, 0xa2, 0xce, 0x6d,
-	0xa3, 0x1c, 0x76, 0x51, 0x7e, 0xfa, 0x3f, 0x69, 0xa1, 0x90, 0x0f, 0x3b, 0xaa, 0x66, 0x1b, 0x50,
-	0xb3, 0xad, 0xf9, 0x63, 0xc7, 0x42, 0x3d, 0xbc, 0x4b, 0x3d, 0xcf, 0x1a, 0x95, 0xcc, 0xc8, 0xfe,
-	0x5a, 0x23, 0x26, 0xac, 0x7b, 0x41, 0x1e, 0xd0, 0x7d, 0x62, 0xdc, 0xf2, 0xa1, 0xf2, 0x87, 0x86,
-	0x9c, 0x9b, 0xf3, 0xc7, 0x4e, 0xfd, 0x8a, 0x0f, 0xfb, 0x3d, 0xaf, 0x7e, 0xcb, 0x87, 0xfd, 0x72,
-	0xea, 0xba, 0xe4, 0x10, 0xa8, 0x48, 0x1d, 0xef, 0x00, 0x3d, 0x21, 0x16, 0x9f, 0xe5, 0xd9, 0x2c,
-	0xf7, 0xd5, 0xd4, 0x24, 0xd8, 0x97, 0x46, 0xb7, 0xe1, 0xa8, 0x41, 0xbd, 0x00, 0xc8, 0x6b, 0x22,
-	0x03, 0x2d, 0xfa, 0x82, 0xd4, 0xb1, 0x27, 0x6b, 0xc0, 0x74, 0x57, 0x1f, 0x0f, 0xec, 0x18, 0x7a,
-	0xd3, 0xb7, 0x64, 0x13, 0x27, 0x21, 0xd6, 0xf7, 0x0c, 0x2a, 0xdb, 0xe8, 0x9e, 0xae, 0xa4, 0x57,
-	0xbe, 0x9c, 0x1e, 0x8e, 0x54, 0x01, 0x29, 0x65, 0x1c, 0x9f, 0x85, 0xb2, 0xf1, 0x6c, 0x65, 0x10,
-	0x5a, 0x28, 0x9f, 0x91, 0xbf, 0x16, 0x0d, 0xf6, 0x97, 0x6b, 0xbb, 0x0e, 0xb5, 0xdd, 0x11, 0xcb,
-	0x23, 0x89, 0x45, 0xb6, 0x3f, 0x57, 0x89, 0xf9, 0x9a, 0x89, 0x31, 0x5b, 0xb1, 0x73, 0xd7, 0xc4,
-	0x2c, 0x72, 0xc7, 0xe1, 0x7f, 0x56, 0xf2, 0x4f, 0x1a, 0xf4, 0x80, 0x18, 0x59, 0x20, 0x58, 0xba,
-	0xdc, 0x5e, 0xcc, 0xce, 0xd3, 0x5b, 0xea, 0x0b, 0x29, 0xe3, 0x40, 0x44, 0xd0, 0xd8, 0x9a, 0x87,
-	0x06, 0xa0, 0xd0, 0x6e, 0xd5, 0x9e, 0x8d, 0xa2, 0x7b, 0x1d, 0xb2, 0x56, 0xaa, 0xec, 0x92, 0x1c,
-	0x6c, 0xd0, 0xbf, 0x89, 0xa9, 0xde, 0xbe, 0x4c, 0x1e, 0x18, 0x14, 0x67, 0xcd, 0xdb, 0x50, 0xc0,
-	0x20, 0x79, 0x60, 0xe7, 0x6f, 0xbe, 0xcd, 0xf7, 0x2a, 0xdf, 0xe7, 0x7b, 0x95, 0x1f, 0xf3, 0xbd,
-	0xca, 0xbb, 0x8b, 0x71, 0x92, 0xc7, 0xb3, 0xa1, 0xfa, 0x6c, 0xbb, 0x8b, 0x43, 0x96, 0x56, 0x52,
-	0x84, 0xee, 0xef, 0x7e, 0x57, 0x43, 0x03, 0xfe, 0x0d, 0xff, 0xfd, 0x0c, 0x00, 0x00, 0xff, 0xff,
-	0x6f, 0x96, 0xe4, 0xe7, 0xd9, 0x06, 0x00, 0x00,
+	// 883 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x51, 0x6f, 0xe3, 0x44,
+	0x10, 0x56, 0x9a, 0x26, 0x17, 0x4f, 0xe2, 0xf6, 0xba, 0xf4, 0xc0, 0x3a, 0xa4, 0xb6, 0xa4, 0x3a,
+	0xd1, 0x93, 0x90, 0xd3, 0x2b, 0xd2, 0x49, 0x88, 0x07, 0xa4, 0x36, 0xa0, 0xcb, 0x09, 0x74, 0x65,
+	0xf3, 0x82, 0xe0, 0xc1, 0xda, 0xd8, 0x9b, 0xd8, 0x6d, 0xe2, 0xb5, 0x76, 0xd7, 0x05, 0xee, 0x17,
+	0xf2, 0x06, 0x0f, 0xbc, 0x21, 0x55, 0x28, 0xbf, 0x04, 0xed, 0xec, 0xda, 0x67, 0xc3, 0x03, 0xd5,
+	0x3d, 0x44, 0xf1, 0x7c, 0xf3, 0xcd, 0x37, 0xe3, 0x99, 0xd9, 0x35, 0x9c, 0x2b, 0x2e, 0xef, 0xb8,
+	0x9c, 0xfc, 0x2c, 0xe4, 0x2d, 0x97, 0x93, 0x22, 0x2b, 0xf8, 0x3a, 0xcb, 0xf9, 0x44, 0x4b, 0x96,
+	0xab, 0xa5, 0x90, 0x9b, 0x77, 0x4f, 0x61, 0x21, 0x85, 0x16, 0xe4, 0xb4, 0x60, 0x71, 0xfa, 0x6b,
+	0xc2, 0xe5, 0x26, 0xb4, 0x41, 0x61, 0x15, 0x14, 0xd6, 0xd4, 0xa7, 0x87, 0x2b, 0xb1, 0x12, 0xc8,
+	0x9f, 0x98, 0x27, 0x1b, 0xfa, 0xf4, 0x30, 0x5e, 0x67, 0x3c, 0xd7, 0x93, 0x62, 0xa9, 0xcc, 0xef,
+	0xdf, 0x68, 0xa1, 0xcc, 0xcf, 0xa1, 0x9f, 0xb4, 0x0b, 0x8b, 0xc5, 0x66, 0x23, 0x72, 0xf7, 0x67,
+	0x29, 0xe3, 0xd7, 0x30, 0x9c, 0x32, 0x5d, 0x6e, 0x66, 0x79, 0x51, 0x6a, 0x45, 0x9e, 0x41, 0x3f,
+	0xc3, 0xa7, 0xa0, 0x73, 0xd2, 0x3d, 0x1b, 0x5e, 0xf8, 0xa1, 0x63, 0xa3, 0x9f, 0x3a, 0x27, 0x39,
+	0x84, 0x5e, 0x96, 0x27, 0xfc, 0x97, 0x60, 0xe7, 0xa4, 0x73, 0xd6, 0xa5, 0xd6, 0x18, 0xff, 0x04,
+	0xfb, 0x0d, 0xad, 0x6f, 0x33, 0xa5, 0xc9, 0x2b, 0xe8, 0x27, 0x06, 0xaa, 0xf4, 0xce, 0xc3, 0x07,
+	0xbc, 0x79, 0xd8, 0x50, 0xa1, 0x2e, 0xde, 0x88, 0xbf, 0x62, 0x2a, 0xd5, 0x92, 0xf3, 0x37, 0x8b,
+	0x1b, 0x1e, 0x6b, 0x45, 0x4e, 0xc1, 0x8f, 0xd3, 0x32, 0xbf, 0x8d, 0x84, 0x05, 0x30, 0x87, 0x47,
+	0x47, 0x08, 0x36, 0x48, 0x4a, 0x33, 0xad, 0x6a, 0xd2, 0x8e, 0x25, 0x21, 0xe8, 0x48, 0xe3, 0xe7,
+	0xb0, 0x4f, 0x79, 0x2c, 0xee, 0xb8, 0xe4, 0x09, 0x26, 0x57, 0xe4, 0x43, 0xe8, 0xa7, 0x4c, 0xa5,
+	0xbc, 0x52, 0x75, 0xd6, 0xf8, 0x05, 0x3c, 0x69, 0x53, 0xab, 0x44, 0x01, 0x3c, 0x6a, 0xd7, 0x51,
+	0x99, 0xe3, 0x1c, 0x46, 0x55, 0xe9, 0xb3, 0x7c, 0x29, 0x0c, 0x93, 0x25, 0x89, 0xe4, 0xca, 0x30,
+	0x3b, 0x86, 0xe9, 0x4c, 0xf2, 0x19, 0x80, 0x2a, 0x17, 0x9a, 0xa9, 0xdb, 0x28, 0x4b, 0xb0, 0xb9,
+	0xde, 0xa5, 0xbf, 0xbd, 0x3f, 0xf6, 0xe6, 0x16, 0x9d, 0x4d, 0xa9, 0xe7, 0x08, 0xb3, 0xc4, 0x94,
+	0x68, 0x53, 0x04, 0x5d, 0x94, 0x71, 0xd6, 0xf8, 0xf7, 0x2e, 0x00, 0x96, 0x36, 0x37, 0xef, 0x48,
+	0x5e, 0x82, 0x5f, 0x48, 0x11, 0x73, 0xa5, 0x22, 0x7c, 0x69, 0x4c, 0x3a, 0xbc, 0x38, 0x08, 0xcd,
+	0xa2, 0x5c, 0x5b, 0x0f, 0x32, 0xe9, 0xa8, 0x68, 0x58, 0xe4, 0x39, 0x3c, 0xb6, 0xbd, 0x8f, 0x1c,
+	0xcc, 0x13, 0x37, 0xef, 0x7d, 0x8b, 0x5f, 0x57, 0x30, 0x79, 0x06, 0x7b, 0x8e, 0xaa, 0x6e, 0xb3,
+	0xa2, 0xe0, 0x09, 0x56, 0xd4, 0xa5, 0xbe, 0x45, 0xe7, 0x16, 0x34, 0xb3, 0x70, 0xb4, 0x25, 0xcb,
+	0xd6, 0x3c, 0x09, 0x7a, 0xc8, 0x1a, 0x59, 0xf0, 0x1b, 0xc4, 0x1a, 0x69, 0x65, 0xd5, 0xe7, 0xa0,
+	0xdf, 0x4c, 0x5b, 0xb7, 0x9f, 0x7c, 0x01, 0xfb, 0x56, 0x28, 0x42, 0x8f, 0xe9, 0xd9, 0x00, 0x7b,
+	0x76, 0xb0, 0xbd, 0x3f, 0xf6, 0xad, 0x9e, 0xdd, 0xa5, 0x29, 0xf5, 0x97, 0x0d, 0x33, 0x21, 0x5f,
+	0x01, 0x69, 0x85, 0xc6, 0xac, 0x54, 0x3c, 0xf0, 0x4e, 0x3a, 0x67, 0x7b, 0xae, 0x33, 0x26, 0xbc,
+	0x94, 0xfc, 0xca, 0x38, 0xe8, 0xe3, 0x46, 0x34, 0x22, 0xe4, 0x4b, 0xd8, 0x5b, 0x33, 0xb9, 0xe2,
+	0x4a, 0x47, 0x6e, 0xc3, 0x01, 0x37, 0xfc, 0x10, 0x83, 0xed, 0x66, 0x94, 0xba, 0x28, 0xf5, 0x3c,
+	0x7b, 0xcb, 0xa9, 0xef, 0xb8, 0x6e, 0xb9, 0x42, 0xf8, 0xa0, 0x95, 0x5d, 0xb1, 0x4d, 0xb1, 0xe6,
+	0xc1, 0x10, 0xf7, 0xe6, 0xa0, 0x91, 0x6b, 0x8e, 0x8e, 0xf1, 0x9f, 0x5d, 0xf0, 0xd0, 0x9e, 0x32,
+	0xcd, 0xc8, 0x09, 0xf4, 0x6f, 0xc4, 0xc2, 0xbc, 0x2d, 0xae, 0xcf, 0xa5, 0xb7, 0xbd, 0x3f, 0xee,
+	0xbd, 0x16, 0x8b, 0xd9, 0x94, 0xf6, 0x6e, 0xc4, 0x62, 0xd6, 0x6c, 0xb4, 0x5b, 0x10, 0x6c, 0x4b,
+	0xd5, 0x68, 0xbb, 0xb1, 0xe4, 0x1c, 0x7c, 0x81, 0x15, 0x46, 0xe6, 0x8c, 0x67, 0x76, 0x8b, 0x86,
+	0x17, 0xc3, 0xd0, 0x5c, 0x2b, 0x57, 0x08, 0xd1, 0x91, 0x65, 0x58, 0x8b, 0x7c, 0x0c, 0x9e, 0xe2,
+	0x3c, 0x89, 0x14, 0x5b, 0x6b, 0xec, 0x95, 0x47, 0x07, 0x06, 0x98, 0xb3, 0xb5, 0x26, 0x5f, 0x43,
+	0xcf, 0xae, 0xd7, 0x2e, 0xca, 0x4c, 0x1e, 0x7e, 0xd2, 0xed, 0xf2, 0xd9, 0x68, 0xf2, 0x03, 0xec,
+	0xd9, 0x43, 0x9d, 0xba, 0x23, 0x83, 0x4b, 0x32, 0xbc, 0x78, 0xf1, 0x20, 0xbd, 0xe6, 0x39, 0xa3,
+	0xf6, 0x76, 0xa8, 0x20, 0xa3, 0x6c, 0x6f, 0x82, 0x5a, 0xb9, 0xff, 0xde, 0xca, 0x28, 0x54, 0x2b,
+	0xbf, 0x84, 0x8f, 0xea, 0x5d, 0x8d, 0xda, 0x8d, 0x7f, 0x84, 0x5d, 0x7a, 0x22, 0xdb, 0xb7, 0x8b,
+	0x9d, 0xc0, 0xf8, 0xaf, 0x1d, 0xf0, 0xbe, 0xe3, 0x72, 0xc5, 0x1f, 0x38, 0xd6, 0x37, 0xe0, 0x55,
+	0xb5, 0xdb, 0x7b, 0xec, 0xbd, 0x8a, 0x7f, 0xa7, 0x41, 0x4e, 0xa1, 0x5f, 0x30, 0xc9, 0xf3, 0xf6,
+	0xec, 0x6d, 0x75, 0xd4, 0xb9, 0xcc, 0x65, 0xaf, 0x52, 0x26, 0x13, 0x1c, 0x6c, 0x97, 0x5a, 0x03,
+	0x51, 0x1c, 0xb7, 0x19, 0xcf, 0xa0, 0x9a, 0xde, 0xa7, 0x30, 0x28, 0x24, 0xbf, 0xcb, 0x44, 0xa9,
+	0x82, 0xc1, 0x7f, 0x25, 0x6b, 0x27, 0x39, 0x86, 0xdd, 0xc6, 0x08, 0x5a, 0x24, 0x74, 0x98, 0x5d,
+	0x33, 0xff, 0x91, 0xca, 0xde, 0x72, 0xec, 0xe2, 0x2e, 0x1d, 0x18, 0xc0, 0x1c, 0x27, 0x7b, 0xf3,
+	0xb3, 0x7c, 0xc5, 0x93, 0xa8, 0x60, 0x3a, 0x55, 0x81, 0x57, 0xdd, 0xfc, 0x08, 0x5e, 0x1b, 0xec,
+	0xf2, 0xfb, 0xdf, 0xb6, 0x47, 0x9d, 0x3f, 0xb6, 0x47, 0x9d, 0xbf, 0xb7, 0x47, 0x9d, 0x1f, 0xaf,
+	0x56, 0x99, 0x4e, 0xcb, 0x85, 0xf9, 0x9e, 0x4d, 0xea, 0x96, 0x35, 0x9e, 0x94, 0x8c, 0x27, 0xff,
+	0xf7, 0x1d, 0x5f, 0xf4, 0xf1, 0xa3, 0xf9, 0xf9, 0x3f, 0x03, 0x00, 0xb9, 0x6f, 0xbf, 0xca, 0xf2,
+	0x07, 0x00, 0x00,
 }
 
 func (m *DatumInputs) Marshal() (dAtA []byte, err error) {
@@ -975,6 +1036,34 @@ func (m *DatumStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.FailedDatumSample) > 0 {
+		for iNdEx := len(m.FailedDatumSample) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.FailedDatumSample[iNdEx])
+			copy(dAtA[i:], m.FailedDatumSample[iNdEx])
+			i = encodeVarintTransform(dAtA, i, uint64(len(m.FailedDatumSample[iNdEx])))
+			i--
+			dAtA[i] = 0x5a
+		}
+	}
+	if len(m.LargestDatums) > 0 {
+		for iNdEx := len(m.LargestDatums) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.LargestDatums[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTransform(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x52
+		}
+	}
+	if m.FailedDatumCause != 0 {
+		i = encodeVarintTransform(dAtA, i, uint64(m.FailedDatumCause))
+		i--
+		dAtA[i] = 0x48
+	}
 	if len(m.FailedDatumID) > 0 {
 		i -= len(m.FailedDatumID)
 		copy(dAtA[i:], m.FailedDatumID)
@@ -1041,6 +1130,13 @@ func (m *DatumData) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.SeedSalt) > 0 {
+		i -= len(m.SeedSalt)
+		copy(dAtA[i:], m.SeedSalt)
+		i = encodeVarintTransform(dAtA, i, uint64(len(m.SeedSalt)))
+		i--
+		dAtA[i] = 0x4a
+	}
 	if len(m.DatumsObject) > 0 {
 		i -= len(m.DatumsObject)
 		copy(dAtA[i:], m.DatumsObject)
@@ -1137,6 +1233,27 @@ func (m *MergeData) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.ChangedPaths) > 0 {
+		for iNdEx := len(m.ChangedPaths) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ChangedPaths[iNdEx])
+			copy(dAtA[i:], m.ChangedPaths[iNdEx])
+			i = encodeVarintTransform(dAtA, i, uint64(len(m.ChangedPaths[iNdEx])))
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	if m.Previous != nil {
+		{
+			size, err := m.Previous.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTransform(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x42
+	}
 	if m.TreeSize != 0 {
 		i = encodeVarintTransform(dAtA, i, uint64(m.TreeSize))
 		i--
@@ -1365,6 +1482,21 @@ func (m *DatumStats) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTransform(uint64(l))
 	}
+	if m.FailedDatumCause != 0 {
+		n += 1 + sovTransform(uint64(m.FailedDatumCause))
+	}
+	if len(m.LargestDatums) > 0 {
+		for _, e := range m.LargestDatums {
+			l = e.Size()
+			n += 1 + l + sovTransform(uint64(l))
+		}
+	}
+	if len(m.FailedDatumSample) > 0 {
+		for _, s := range m.FailedDatumSample {
+			l = len(s)
+			n += 1 + l + sovTransform(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1405,6 +1537,10 @@ func (m *DatumData) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTransform(uint64(l))
 	}
+	l = len(m.SeedSalt)
+	if l > 0 {
+		n += 1 + l + sovTransform(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1444,6 +1580,16 @@ func (m *MergeData) Size() (n int) {
 	if m.TreeSize != 0 {
 		n += 1 + sovTransform(uint64(m.TreeSize))
 	}
+	if m.Previous != nil {
+		l = m.Previous.Size()
+		n += 1 + l + sovTransform(uint64(l))
+	}
+	if len(m.ChangedPaths) > 0 {
+		for _, s := range m.ChangedPaths {
+			l = len(s)
+			n += 1 + l + sovTransform(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2264,6 +2410,91 @@ func (m *DatumStats) Unmarshal(dAtA []byte) error {
 			}
 			m.FailedDatumID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FailedDatumCause", wireType)
+			}
+			m.FailedDatumCause = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTransform
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FailedDatumCause |= pps.FailureCause(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LargestDatums", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTransform
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTransform
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTransform
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LargestDatums = append(m.LargestDatums, &pps.DatumOutputSize{})
+			if err := m.LargestDatums[len(m.LargestDatums)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FailedDatumSample", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTransform
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTransform
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTransform
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FailedDatumSample = append(m.FailedDatumSample, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTransform(dAtA[iNdEx:])
@@ -2558,6 +2789,38 @@ func (m *DatumData) Unmarshal(dAtA []byte) error {
 			}
 			m.DatumsObject = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SeedSalt", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTransform
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTransform
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTransform
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SeedSalt = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTransform(dAtA[iNdEx:])
@@ -2808,6 +3071,74 @@ func (m *MergeData) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Previous", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTransform
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTransform
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTransform
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Previous == nil {
+				m.Previous = &pfs.Object{}
+			}
+			if err := m.Previous.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChangedPaths", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTransform
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTransform
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTransform
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ChangedPaths = append(m.ChangedPaths, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTransform(dAtA[iNdEx:])
@@ -0,0 +1,79 @@
+package transform
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errors"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// defaultFingerprintTimeout bounds a volume input's fingerprint_cmd, if the
+// input doesn't set fingerprint_timeout
+const defaultFingerprintTimeout = 10 * time.Minute
+
+// volumeFingerprints runs the fingerprint commands of the volume inputs in
+// 'input', and returns their fingerprints, by input name. A fingerprint is a
+// hash of the volume's source and of its command's output, so that changing
+// either one reprocesses the datums that the volume is crossed with.
+func volumeFingerprints(ctx context.Context, input *pps.Input) (map[string]string, error) {
+	var result map[string]string
+	var volumes []*pps.VolumeInput
+	pps.VisitInput(input, func(input *pps.Input) {
+		if input.Volume != nil {
+			volumes = append(volumes, input.Volume)
+		}
+	})
+	for _, volume := range volumes {
+		fingerprint, err := volumeFingerprint(ctx, volume)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result[volume.Name] = fingerprint
+	}
+	return result, nil
+}
+
+func volumeFingerprint(ctx context.Context, volume *pps.VolumeInput) (string, error) {
+	if len(volume.FingerprintCmd) == 0 {
+		return "", errors.Errorf("volume input %q has no fingerprint_cmd", volume.Name)
+	}
+	timeout := defaultFingerprintTimeout
+	if volume.FingerprintTimeout != nil {
+		var err error
+		if timeout, err = types.DurationFromProto(volume.FingerprintTimeout); err != nil {
+			return "", errors.EnsureStack(err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	hash := sha256.New()
+	for _, source := range []string{volume.ClaimName, volume.NfsServer, volume.Path} {
+		hash.Write([]byte(source))
+		hash.Write([]byte{0})
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, volume.FingerprintCmd[0], volume.FingerprintCmd[1:]...)
+	cmd.Dir = volume.MountPath
+	cmd.Stdout = hash
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", errors.Errorf("fingerprint_cmd of volume input %q timed out after %v", volume.Name, timeout)
+		}
+		return "", errors.Wrapf(err, "fingerprint_cmd of volume input %q failed: %s",
+			volume.Name, strings.TrimSpace(stderr.String()))
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
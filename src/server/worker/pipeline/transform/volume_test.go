@@ -0,0 +1,73 @@
+package transform
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestVolumeFingerprints(t *testing.T) {
+	dir, err := ioutil.TempDir("", "volume")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	volume := &pps.VolumeInput{
+		Name:           "ref",
+		MountPath:      dir,
+		ClaimName:      "genomes",
+		FingerprintCmd: []string{"sh", "-c", "echo $VERSION; pwd"},
+	}
+	input := &pps.Input{Cross: []*pps.Input{
+		{Pfs: &pps.PFSInput{Name: "in"}},
+		{Volume: volume},
+	}}
+	fingerprints, err := volumeFingerprints(context.Background(), input)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(fingerprints))
+	first := fingerprints["ref"]
+	require.NotEqual(t, "", first)
+
+	// The same output gives the same fingerprint
+	fingerprints, err = volumeFingerprints(context.Background(), input)
+	require.NoError(t, err)
+	require.Equal(t, first, fingerprints["ref"])
+
+	// ...but different output, or a different source, doesn't
+	volume.FingerprintCmd = []string{"sh", "-c", "echo v2; pwd"}
+	fingerprints, err = volumeFingerprints(context.Background(), input)
+	require.NoError(t, err)
+	require.NotEqual(t, first, fingerprints["ref"])
+	second := fingerprints["ref"]
+	volume.ClaimName = "genomes-v2"
+	fingerprints, err = volumeFingerprints(context.Background(), input)
+	require.NoError(t, err)
+	require.NotEqual(t, second, fingerprints["ref"])
+
+	// Inputs without volumes have no fingerprints
+	fingerprints, err = volumeFingerprints(context.Background(), input.Cross[0])
+	require.NoError(t, err)
+	require.Equal(t, 0, len(fingerprints))
+}
+
+func TestVolumeFingerprintErrors(t *testing.T) {
+	volume := &pps.VolumeInput{
+		Name:           "ref",
+		MountPath:      os.TempDir(),
+		FingerprintCmd: []string{"sh", "-c", "echo oops >&2; exit 1"},
+	}
+	_, err := volumeFingerprint(context.Background(), volume)
+	require.YesError(t, err)
+	require.Matches(t, "oops", err.Error())
+
+	volume.FingerprintCmd = []string{"sleep", "10"}
+	volume.FingerprintTimeout = types.DurationProto(100 * time.Millisecond)
+	_, err = volumeFingerprint(context.Background(), volume)
+	require.YesError(t, err)
+	require.Matches(t, "timed out", err.Error())
+}
@@ -1,6 +1,7 @@
 package transform
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -38,6 +40,7 @@ import (
 
 var (
 	errDatumRecovered = errors.New("the datum errored, and the error was handled successfully")
+	errDraining       = errors.New("the worker is draining")
 	statsTagSuffix    = "_stats"
 )
 
@@ -95,6 +98,20 @@ func mergeStats(x, y *DatumStats) error {
 		}
 		xps.DownloadBytes += yps.DownloadBytes
 		xps.UploadBytes += yps.UploadBytes
+		xps.DroppedLogBytes += yps.DroppedLogBytes
+		xps.AssertionsFailed += yps.AssertionsFailed
+		xps.DatumsReused += yps.DatumsReused
+		xps.SkippedBytes += yps.SkippedBytes
+		xps.CachedBytes += yps.CachedBytes
+		xps.CorrectedReads += yps.CorrectedReads
+		xps.OutputFiles += yps.OutputFiles
+		xps.OutputBytes += yps.OutputBytes
+		xps.Retries += yps.Retries
+		xps.DedupedFiles += yps.DedupedFiles
+		xps.DedupedBytes += yps.DedupedBytes
+		if yps.PeakMemoryBytes > xps.PeakMemoryBytes {
+			xps.PeakMemoryBytes = yps.PeakMemoryBytes
+		}
 	}
 
 	x.DatumsProcessed += y.DatumsProcessed
@@ -103,10 +120,41 @@ func mergeStats(x, y *DatumStats) error {
 	x.DatumsRecovered += y.DatumsRecovered
 	if x.FailedDatumID == "" {
 		x.FailedDatumID = y.FailedDatumID
+		x.FailedDatumCause = y.FailedDatumCause
+	}
+	x.LargestDatums = mergeLargestDatums(x.LargestDatums, y.LargestDatums)
+	for _, datumID := range y.FailedDatumSample {
+		if len(x.FailedDatumSample) >= ppsutil.MaxJobReasonDatums {
+			break
+		}
+		x.FailedDatumSample = append(x.FailedDatumSample, datumID)
 	}
 	return nil
 }
 
+// maxLargestDatums is the number of datums with the largest outputs that are
+// recorded in a job's info
+const maxLargestDatums = 10
+
+// mergeLargestDatums returns the (up to maxLargestDatums) datums in 'x' and
+// 'y' with the largest outputs, largest first.
+func mergeLargestDatums(x, y []*pps.DatumOutputSize) []*pps.DatumOutputSize {
+	if len(y) == 0 {
+		return x
+	}
+	result := append(append([]*pps.DatumOutputSize{}, x...), y...)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].OutputBytes != result[j].OutputBytes {
+			return result[i].OutputBytes > result[j].OutputBytes
+		}
+		return result[i].OutputFiles > result[j].OutputFiles
+	})
+	if len(result) > maxLargestDatums {
+		result = result[:maxLargestDatums]
+	}
+	return result
+}
+
 // Worker handles a transform pipeline work subtask, then returns.
 func Worker(driver driver.Driver, logger logs.TaggedLogger, subtask *work.Task, status *Status) (retErr error) {
 	defer func() {
@@ -207,36 +255,69 @@ func uploadChunk(
 	chunkCache *hashtree.MergeCache,
 	object string,
 	subtaskID string,
-) (retErr error) {
+) error {
 	return logger.LogStep("uploading hashtree chunk", func() error {
-		// Merge the datums for this job into a chunk
-		buf := &bytes.Buffer{}
-		if err := subtaskCache.Merge(hashtree.NewWriter(buf), nil, nil); err != nil {
-			return err
-		}
+		// Merge the datums for this job into a chunk, on disk rather than in
+		// memory
+		return driver.WithSpillFile(func(f *os.File) (retErr error) {
+			bufW := bufio.NewWriter(f)
+			if err := subtaskCache.Merge(hashtree.NewWriter(bufW), nil, nil); err != nil {
+				return err
+			}
+			if err := bufW.Flush(); err != nil {
+				return errors.EnsureStack(err)
+			}
+			size, err := f.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return errors.EnsureStack(err)
+			}
 
-		chunkID := hashtreeChunkID(subtaskID)
-		logger.Logf("merged hashtree cache into buffer, len: %d, chunkID: %s, object: %s", buf.Len(), chunkID, object)
-		if err := chunkCache.Put(chunkID, bytes.NewBuffer(buf.Bytes())); err != nil {
-			return err
-		}
+			chunkID := hashtreeChunkID(subtaskID)
+			logger.Logf("merged hashtree cache into spill file, len: %d, chunkID: %s, object: %s", size, chunkID, object)
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return errors.EnsureStack(err)
+			}
+			if err := chunkCache.Put(chunkID, f); err != nil {
+				return err
+			}
 
-		// Upload the hashtree for this subtask to the given object
-		writer, err := driver.PachClient().DirectObjWriter(object)
-		if err != nil {
-			return errors.EnsureStack(err)
-		}
-		defer func() {
-			if err := writer.Close(); err != nil && retErr == nil {
-				retErr = errors.EnsureStack(err)
+			// Upload the hashtree for this subtask to the given object
+			writer, err := driver.PachClient().DirectObjWriter(object)
+			if err != nil {
+				return errors.EnsureStack(err)
 			}
-		}()
+			defer func() {
+				if err := writer.Close(); err != nil && retErr == nil {
+					retErr = errors.EnsureStack(err)
+				}
+			}()
 
-		_, err = writer.Write(buf.Bytes())
-		return err
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return errors.EnsureStack(err)
+			}
+			_, err = io.Copy(writer, f)
+			return errors.EnsureStack(err)
+		})
 	})
 }
 
+// withSpillFiles calls 'cb' with 'n' temporary files in the worker's spill
+// directory, which are removed once 'cb' returns
+func withSpillFiles(driver driver.Driver, n int, cb func([]*os.File) error) error {
+	var files []*os.File
+	var next func() error
+	next = func() error {
+		if len(files) == n {
+			return cb(files)
+		}
+		return driver.WithSpillFile(func(f *os.File) error {
+			files = append(files, f)
+			return next()
+		})
+	}
+	return next()
+}
+
 func checkS3Gateway(driver driver.Driver, logger logs.TaggedLogger) error {
 	return backoff.RetryNotify(func() error {
 		endpoint := fmt.Sprintf("http://%s:%s/",
@@ -257,7 +338,7 @@ func checkS3Gateway(driver driver.Driver, logger logs.TaggedLogger) error {
 	//   reason := fmt.Sprintf("could not connect to s3 gateway for %q: %v", logger.JobID(), err)
 	//   logger.Logf("failing job with reason: %s", reason)
 	//   // NOTE: this is the only place a worker will reach over and change the job state, this should not generally be done.
-	//   return finishJob(driver.PipelineInfo(), driver.PachClient(), jobInfo, pps.JobState_JOB_FAILURE, reason, nil, nil, 0, nil, 0)
+	//   return finishJob(driver.PipelineInfo(), driver.PachClient(), jobInfo, pps.JobState_JOB_FAILURE, reason, nil, nil, 0, nil, nil, 0)
 	// }
 	// return nil
 }
@@ -272,7 +353,8 @@ func handleDatumTask(driver driver.Driver, logger logs.TaggedLogger, data *Datum
 	// TODO: check for existing tagged output files - continue with processing if any are missing
 	return driver.WithDatumCache(func(datumCache *hashtree.MergeCache, statsCache *hashtree.MergeCache) error {
 		logger.Logf("transform worker datum task: %v", data)
-		limiter := limit.New(int(driver.PipelineInfo().MaxQueueSize))
+		limiter := limit.New(datumQueueSize(driver.PipelineInfo()))
+		phases := newDatumPhases(driver.PipelineInfo().PhaseConcurrency)
 
 		// statsMutex controls access to stats so that they can be safely merged
 		statsMutex := &sync.Mutex{}
@@ -284,7 +366,7 @@ func handleDatumTask(driver driver.Driver, logger logs.TaggedLogger, data *Datum
 		var queueSize, dataProcessed, dataRecovered int64
 		// TODO: the status.GetStatus call may read the process stats without having a lock, it this ~ok?
 		if err := logger.LogStep("processing datums", func() error {
-			return status.withStats(data.Stats.ProcessStats, &queueSize, &dataProcessed, &dataRecovered, func() error {
+			return status.withStats(data.Stats.ProcessStats, &queueSize, &dataProcessed, &dataRecovered, phases, func() error {
 				ctx, cancel := context.WithCancel(driver.PachClient().Ctx())
 				defer cancel()
 
@@ -292,6 +374,10 @@ func handleDatumTask(driver driver.Driver, logger logs.TaggedLogger, data *Datum
 				driver := driver.WithContext(ctx)
 				if err := forEachDatum(driver, data.DatumsObject, func(index int64, inputs []*common.Input) error {
 					limiter.Acquire()
+					if status.isDraining() {
+						limiter.Release()
+						return errDraining
+					}
 					atomic.AddInt64(&queueSize, 1)
 					eg.Go(func() error {
 						defer limiter.Release()
@@ -307,7 +393,7 @@ func handleDatumTask(driver driver.Driver, logger logs.TaggedLogger, data *Datum
 						logger = logger.WithJob(jobID).WithData(inputs)
 
 						// subStats is still valid even on an error, merge those in before proceeding
-						subStats, subRecovered, err := processDatum(driver, logger, index, inputs, data.OutputCommit, datumCache, statsCache, status)
+						subStats, subRecovered, err := processDatum(driver, logger, index, inputs, data.OutputCommit, data.SeedSalt, datumCache, statsCache, status, phases)
 
 						statsMutex.Lock()
 						defer statsMutex.Unlock()
@@ -324,6 +410,16 @@ func handleDatumTask(driver driver.Driver, logger logs.TaggedLogger, data *Datum
 					})
 					return nil
 				}); err != nil {
+					if errors.Is(err, errDraining) {
+						// Let the datums that are already running finish, so
+						// that their output is tagged and the worker that
+						// picks up this task skips them
+						if err := eg.Wait(); err != nil {
+							return err
+						}
+						logger.Logf("worker is draining, requeueing datum task")
+						return work.ErrRequeue
+					}
 					cancel()
 					eg.Wait()
 					return err
@@ -380,14 +476,19 @@ func processDatum(
 	datumIndex int64,
 	inputs []*common.Input,
 	outputCommit *pfs.Commit,
+	seedSalt string,
 	datumCache *hashtree.MergeCache,
 	datumStatsCache *hashtree.MergeCache,
 	status *Status,
+	phases *datumPhases,
 ) (_ *DatumStats, _ []string, retErr error) {
 	recoveredDatums := []string{}
 	stats := &DatumStats{}
 	tag := common.HashDatum(driver.PipelineInfo().Pipeline.Name, driver.PipelineInfo().Salt, inputs)
-	datumID := common.DatumID(inputs)
+	datumID := common.DatumID(driver.PipelineInfo().Salt, inputs)
+	if seedSalt == "" {
+		seedSalt = driver.PipelineInfo().Salt
+	}
 
 	if _, err := driver.PachClient().InspectTag(driver.PachClient().Ctx(), client.NewTag(tag)); err == nil {
 		buf := &bytes.Buffer{}
@@ -409,15 +510,43 @@ func processDatum(
 			}
 		}
 		stats.DatumsSkipped++
+		stats.ProcessStats = &pps.ProcessStats{DatumsReused: 1}
+		for _, input := range inputs {
+			stats.ProcessStats.SkippedBytes += input.FileInfo.SizeBytes
+		}
 		return stats, recoveredDatums, nil
 	}
 
 	statsRoot := path.Join("/", datumID)
 	var inputTree, outputTree *hashtree.Ordered
 	var statsTree *hashtree.Unordered
+	// closeStatsTrees releases the datum's input and output stats trees' spill
+	// files and memory
+	closeStatsTrees := func() {
+		for _, tree := range []*hashtree.Ordered{inputTree, outputTree} {
+			if tree != nil {
+				if err := tree.Close(); err != nil {
+					logger.Errf("could not close stats hashtree: %v", err)
+				}
+			}
+		}
+	}
+	// newStatsTrees (re)creates the datum's input and output stats trees
+	newStatsTrees := func() error {
+		closeStatsTrees()
+		inputTree, outputTree = nil, nil
+		var err error
+		if inputTree, err = driver.NewOrderedTree(path.Join(statsRoot, "pfs")); err != nil {
+			return err
+		}
+		outputTree, err = driver.NewOrderedTree(path.Join(statsRoot, "pfs", "out"))
+		return err
+	}
+	defer closeStatsTrees()
 	if driver.PipelineInfo().EnableStats {
-		inputTree = hashtree.NewOrdered(path.Join(statsRoot, "pfs"))
-		outputTree = hashtree.NewOrdered(path.Join(statsRoot, "pfs", "out"))
+		if err := newStatsTrees(); err != nil {
+			return stats, recoveredDatums, err
+		}
 		statsTree = hashtree.NewUnordered(statsRoot)
 		// Write job id to stats tree
 		statsTree.PutFile(fmt.Sprintf("job:%s", logger.JobID()), nil, 0)
@@ -435,6 +564,11 @@ func processDatum(
 			return stats, recoveredDatums, err
 		}
 		statsTree.PutFile("index", h, size, objectInfo.BlockRef)
+		if memory := os.Getenv(client.PPSEscalatedMemoryEnv); memory != "" {
+			if err := putStatsFile(driver, statsTree, "escalated_memory", memory); err != nil {
+				return stats, recoveredDatums, err
+			}
+		}
 		defer func() {
 			logger.Logf("writing stats for datum: %s, current err: %v", tag, retErr)
 			if err := writeStats(driver, logger, stats.ProcessStats, inputTree, outputTree, statsTree, tag, datumStatsCache); err != nil && retErr == nil {
@@ -443,54 +577,97 @@ func processDatum(
 		}()
 	}
 
+	retryBackOff, err := datumBackOff(driver.PipelineInfo().RetrySpec)
+	if err != nil {
+		return stats, recoveredDatums, err
+	}
 	var failures int64
+	// Record how many times the datum was retried, once its stats are final
+	defer func() {
+		if stats.ProcessStats != nil {
+			retries := failures
+			if failures >= driver.PipelineInfo().DatumTries {
+				retries-- // the last failure wasn't retried
+			}
+			stats.ProcessStats.Retries = retries
+		}
+	}()
 	if err := backoff.RetryUntilCancel(driver.PachClient().Ctx(), func() error {
 		var err error
 
 		// WithData will download the inputs for this datum
+		download := phases.download.enter()
+		defer download.release()
 		stats.ProcessStats, err = driver.WithData(inputs, inputTree, logger, func(dir string, processStats *pps.ProcessStats) error {
-
-			// WithActiveData acquires a mutex so that we don't run this section concurrently
-			if err := driver.WithActiveData(inputs, dir, func() error {
-				ctx, cancel := context.WithCancel(driver.PachClient().Ctx())
-				defer cancel()
-
-				driver := driver.WithContext(ctx)
-
-				return status.withDatum(inputs, cancel, func() error {
-					env := driver.UserCodeEnv(logger.JobID(), outputCommit, inputs)
-					if err := driver.RunUserCode(logger, env, processStats, driver.PipelineInfo().DatumTimeout); err != nil {
-						if driver.PipelineInfo().Transform.ErrCmd != nil && failures == driver.PipelineInfo().DatumTries-1 {
-							if err = driver.RunUserErrorHandlingCode(logger, env, processStats, driver.PipelineInfo().DatumTimeout); err != nil {
-								return errors.Wrap(err, "RunUserErrorHandlingCode")
+			download.finish(true)
+			// WithCheckpoints restores the datum's checkpoint from a previous
+			// attempt (if any) and deletes it once the datum's output is uploaded
+			return driver.WithCheckpoints(tag, dir, logger, func() error {
+				process := phases.process.enter()
+				defer process.release()
+				// The next datum is downloaded while this one is processed
+				download.release()
+				// WithActiveData acquires a mutex so that we don't run this section concurrently
+				if err := driver.WithActiveData(inputs, dir, func() error {
+					ctx, cancel := context.WithCancel(driver.PachClient().Ctx())
+					defer cancel()
+
+					driver := driver.WithContext(ctx)
+
+					return status.withDatum(inputs, cancel, func() error {
+						if driver.PipelineInfo().Validation != nil {
+							return driver.RunValidation(logger, inputs, processStats)
+						}
+						if driver.PipelineInfo().Transform.Builtin != nil {
+							return driver.RunBuiltinTransform(logger, inputs, processStats, driver.PipelineInfo().DatumTimeout)
+						}
+						env := driver.UserCodeEnv(logger.JobID(), outputCommit, inputs)
+						env = append(env, fmt.Sprintf("%s=%d", client.DatumSeedEnv, ppsutil.DatumSeed(seedSalt, datumID)))
+						if err := driver.RunUserCode(logger, env, processStats, driver.PipelineInfo().DatumTimeout); err != nil {
+							if driver.PipelineInfo().Transform.ErrCmd != nil && failures == driver.PipelineInfo().DatumTries-1 {
+								if err = driver.RunUserErrorHandlingCode(logger, env, processStats, driver.PipelineInfo().DatumTimeout); err != nil {
+									return errors.Wrap(err, "RunUserErrorHandlingCode")
+								}
+								return errDatumRecovered
 							}
-							return errDatumRecovered
+							return err
 						}
-						return err
-					}
-					return nil
-				})
-			}); err != nil {
-				return err
-			}
-
-			if driver.PipelineInfo().S3Out {
-				return nil // S3Out pipelines do not store data in worker hashtrees
-			}
+						return nil
+					})
+				}); err != nil {
+					return err
+				}
+				process.finish(true)
+				// The next datum is processed while this one's output is uploaded
+				process.release()
 
-			hashtreeBytes, err := driver.UploadOutput(dir, tag, logger, inputs, processStats, outputTree)
-			if err != nil {
-				return err
-			}
+				if driver.PipelineInfo().S3Out {
+					return nil // S3Out pipelines do not store data in worker hashtrees
+				}
 
-			// Cache datum hashtree locally
-			return datumCache.Put(uuid.NewWithoutDashes(), bytes.NewReader(hashtreeBytes))
+				upload := phases.upload.enter()
+				defer upload.release()
+				// UploadOutput also caches the datum hashtree locally
+				if err := driver.UploadOutput(dir, tag, logger, inputs, processStats, outputTree, datumCache); err != nil {
+					return err
+				}
+				upload.finish(true)
+				return nil
+			})
 		})
+		if err == nil {
+			phases.download.addBytes(stats.ProcessStats.DownloadBytes)
+			phases.upload.addBytes(stats.ProcessStats.UploadBytes)
+		}
 		return err
-	}, &backoff.ZeroBackOff{}, func(err error, d time.Duration) error {
+	}, retryBackOff, func(err error, d time.Duration) error {
 		failures++
+		if hashtree.IsMemoryLimitExceeded(err) {
+			logger.Errf("the datum's hashtrees exceeded the pipeline's hashtree_memory_limit (%q): %v", driver.PipelineInfo().HashtreeMemoryLimit, err)
+		}
 		if failures >= driver.PipelineInfo().DatumTries {
 			logger.Logf("failed to process datum with error: %+v", err)
+			stats.FailedDatumCause = common.FailureCause(err)
 			if statsTree != nil {
 				object, size, err := driver.PachClient().PutObject(strings.NewReader(err.Error()))
 				if err != nil {
@@ -506,13 +683,17 @@ func processDatum(
 					}
 					statsTree.PutFile("failure", h, size, objectInfo.BlockRef)
 				}
+				if err := putStatsFile(driver, statsTree, "failure_cause", stats.FailedDatumCause.String()); err != nil {
+					logger.Errf("could not put failure cause object: %s\n", err)
+				}
 			}
 			return err
 		}
 		// If stats is enabled, reset input and output tree on retry.
 		if statsTree != nil {
-			inputTree = hashtree.NewOrdered(path.Join(statsRoot, "pfs"))
-			outputTree = hashtree.NewOrdered(path.Join(statsRoot, "pfs", "out"))
+			if err := newStatsTrees(); err != nil {
+				return err
+			}
 		}
 		logger.Logf("failed processing datum: %v, retrying in %v", err, d)
 		return nil
@@ -522,13 +703,40 @@ func processDatum(
 		stats.DatumsRecovered++
 	} else if err != nil {
 		stats.FailedDatumID = datumID
+		stats.FailedDatumSample = []string{datumID}
 		stats.DatumsFailed++
 	} else {
 		stats.DatumsProcessed++
+		if ps := stats.ProcessStats; ps != nil && (ps.OutputFiles > 0 || ps.OutputBytes > 0) {
+			stats.LargestDatums = []*pps.DatumOutputSize{{
+				Datum:       &pps.Datum{ID: datumID, Job: client.NewJob(logger.JobID())},
+				OutputBytes: ps.OutputBytes,
+				OutputFiles: ps.OutputFiles,
+			}}
+		}
 	}
 	return stats, recoveredDatums, nil
 }
 
+// putStatsFile writes a file (e.g. why the datum failed) to the datum's
+// stats, so that InspectDatum and ListDatum can report it.
+func putStatsFile(driver driver.Driver, statsTree *hashtree.Unordered, name string, contents string) error {
+	object, size, err := driver.PachClient().PutObject(strings.NewReader(contents))
+	if err != nil {
+		return err
+	}
+	objectInfo, err := driver.PachClient().InspectObject(object.Hash)
+	if err != nil {
+		return err
+	}
+	h, err := pfs.DecodeHash(object.Hash)
+	if err != nil {
+		return err
+	}
+	statsTree.PutFile(name, h, size, objectInfo.BlockRef)
+	return nil
+}
+
 func writeStats(
 	driver driver.Driver,
 	logger logs.TaggedLogger,
@@ -538,7 +746,7 @@ func writeStats(
 	statsTree *hashtree.Unordered,
 	tag string,
 	datumStatsCache *hashtree.MergeCache,
-) (retErr error) {
+) error {
 	// Store stats and add stats file
 	marshaler := &jsonpb.Marshaler{}
 	statsString, err := marshaler.MarshalToString(stats)
@@ -576,37 +784,55 @@ func writeStats(
 		}
 		statsTree.PutFile("logs", h, size, objectInfo.BlockRef)
 	}
-	// Merge stats trees (input, output, stats) and write out
-	inputBuf := &bytes.Buffer{}
-	inputTree.Serialize(inputBuf)
-	outputBuf := &bytes.Buffer{}
-	outputTree.Serialize(outputBuf)
-	statsBuf := &bytes.Buffer{}
-	statsTree.Ordered().Serialize(statsBuf)
-	// Merge datum stats hashtree
-	buf := &bytes.Buffer{}
-	if err := hashtree.Merge(hashtree.NewWriter(buf), []*hashtree.Reader{
-		hashtree.NewReader(inputBuf, nil),
-		hashtree.NewReader(outputBuf, nil),
-		hashtree.NewReader(statsBuf, nil),
-	}); err != nil {
-		return err
-	}
-	// Write datum stats hashtree to object storage
-	objW, err := driver.PachClient().PutObjectAsync([]*pfs.Tag{client.NewTag(tag + statsTagSuffix)})
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err := objW.Close(); err != nil && retErr == nil {
-			retErr = err
+	// Merge stats trees (input, output, stats) and write out. The trees are
+	// serialized and merged on disk, so that datums with many files don't
+	// exhaust the worker's memory
+	return withSpillFiles(driver, 4, func(files []*os.File) error {
+		trees := []*hashtree.Ordered{inputTree, outputTree, statsTree.Ordered()}
+		var readers []*hashtree.Reader
+		for i, tree := range trees {
+			bufW := bufio.NewWriter(files[i])
+			if err := tree.Serialize(bufW); err != nil {
+				return err
+			}
+			if err := bufW.Flush(); err != nil {
+				return errors.EnsureStack(err)
+			}
+			if _, err := files[i].Seek(0, io.SeekStart); err != nil {
+				return errors.EnsureStack(err)
+			}
+			readers = append(readers, hashtree.NewReader(bufio.NewReader(files[i]), nil))
 		}
-	}()
-	if _, err := objW.Write(buf.Bytes()); err != nil {
-		return err
-	}
-	// Cache datum stats hashtree locally
-	return datumStatsCache.Put(tag, bytes.NewReader(buf.Bytes()))
+		// Merge datum stats hashtree
+		merged := files[len(trees)]
+		bufW := bufio.NewWriter(merged)
+		if err := hashtree.Merge(hashtree.NewWriter(bufW), readers); err != nil {
+			return err
+		}
+		if err := bufW.Flush(); err != nil {
+			return errors.EnsureStack(err)
+		}
+		// Write datum stats hashtree to object storage
+		if _, err := merged.Seek(0, io.SeekStart); err != nil {
+			return errors.EnsureStack(err)
+		}
+		objW, err := driver.PachClient().PutObjectAsync([]*pfs.Tag{client.NewTag(tag + statsTagSuffix)})
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(objW, merged); err != nil {
+			objW.Close()
+			return err
+		}
+		if err := objW.Close(); err != nil {
+			return err
+		}
+		// Cache datum stats hashtree locally
+		if _, err := merged.Seek(0, io.SeekStart); err != nil {
+			return errors.EnsureStack(err)
+		}
+		return datumStatsCache.Put(tag, merged)
+	})
 }
 
 func fetchChunkFromWorker(driver driver.Driver, logger logs.TaggedLogger, address string, subtaskID string, shard int64, stats bool) (io.ReadCloser, error) {
@@ -654,13 +880,18 @@ func handleMergeTask(driver driver.Driver, logger logs.TaggedLogger, data *Merge
 		return err
 	}
 
-	var parentReader io.ReadCloser
+	var parentReader, previousReader io.ReadCloser
 	defer func() {
 		if parentReader != nil {
 			if err := parentReader.Close(); retErr == nil {
 				retErr = err
 			}
 		}
+		if previousReader != nil {
+			if err := previousReader.Close(); retErr == nil {
+				retErr = err
+			}
+		}
 	}()
 
 	if err := logger.LogStep("downloading hashtree chunks", func() error {
@@ -691,13 +922,18 @@ func handleMergeTask(driver driver.Driver, logger logs.TaggedLogger, data *Merge
 						}
 					}()
 
-					// TODO: this only works if it is read into a buffer first?
-					buf := &bytes.Buffer{}
-					if _, err := io.Copy(buf, reader); err != nil {
-						return errors.EnsureStack(err)
-					}
-
-					return errors.EnsureStack(cache.Put(chunkID, bytes.NewBuffer(buf.Bytes())))
+					// The chunk is downloaded before it's cached, because the
+					// cache holds its lock while writing. Download it to disk
+					// rather than memory, as chunks can be large.
+					return driver.WithSpillFile(func(f *os.File) error {
+						if _, err := io.Copy(f, reader); err != nil {
+							return errors.EnsureStack(err)
+						}
+						if _, err := f.Seek(0, io.SeekStart); err != nil {
+							return errors.EnsureStack(err)
+						}
+						return errors.EnsureStack(cache.Put(chunkID, f))
+					})
 				})
 				downloadedChunks++
 			} else {
@@ -722,6 +958,13 @@ func handleMergeTask(driver driver.Driver, logger logs.TaggedLogger, data *Merge
 				return errors.EnsureStack(err)
 			})
 		}
+		if data.Previous != nil {
+			eg.Go(func() error {
+				var err error
+				previousReader, err = driver.PachClient().GetObjectReader(data.Previous.Hash)
+				return errors.EnsureStack(err)
+			})
+		}
 
 		return errors.EnsureStack(eg.Wait())
 	}); err != nil {
@@ -729,18 +972,42 @@ func handleMergeTask(driver driver.Driver, logger logs.TaggedLogger, data *Merge
 	}
 
 	return logger.LogStep("merging hashtree chunks", func() error {
-		tree, size, err := merge(driver, parentReader, cache, data.Shard)
-		if err != nil {
-			return err
+		if previousReader == nil {
+			tree, size, err := merge(driver, parentReader, cache, data.Shard, nil)
+			if err != nil {
+				return err
+			}
+
+			data.Tree = tree
+			data.TreeSize = size
+			return nil
 		}
+		// The merged tree is also written to disk, to find the paths that
+		// changed since the previous output commit
+		return driver.WithSpillFile(func(f *os.File) error {
+			tree, size, err := merge(driver, parentReader, cache, data.Shard, f)
+			if err != nil {
+				return err
+			}
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return errors.EnsureStack(err)
+			}
+			changedPaths, err := shardChangedPaths(bufio.NewReader(previousReader), bufio.NewReader(f))
+			if err != nil {
+				return err
+			}
 
-		data.Tree = tree
-		data.TreeSize = size
-		return nil
+			data.Tree = tree
+			data.TreeSize = size
+			data.ChangedPaths = changedPaths
+			return nil
+		})
 	})
 }
 
-func merge(driver driver.Driver, parent io.Reader, cache *hashtree.MergeCache, shard int64) (*pfs.Object, uint64, error) {
+// merge merges the chunks in 'cache' (and 'parent', if it's set) into the
+// tree for 'shard'. If 'tee' is set, the tree is also written to it.
+func merge(driver driver.Driver, parent io.Reader, cache *hashtree.MergeCache, shard int64, tee io.Writer) (*pfs.Object, uint64, error) {
 	var tree *pfs.Object
 	var size uint64
 	if err := func() (retErr error) {
@@ -749,7 +1016,11 @@ func merge(driver driver.Driver, parent io.Reader, cache *hashtree.MergeCache, s
 			return errors.EnsureStack(err)
 		}
 
-		w := hashtree.NewWriter(objW)
+		var out io.Writer = objW
+		if tee != nil {
+			out = io.MultiWriter(objW, tee)
+		}
+		w := hashtree.NewWriter(out)
 		filter := hashtree.NewFilter(driver.NumShards(), shard)
 		err = cache.Merge(w, parent, filter)
 		size = w.Size()
@@ -0,0 +1,65 @@
+package transform
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+)
+
+func outputSize(id string, bytes, files uint64) *pps.DatumOutputSize {
+	return &pps.DatumOutputSize{
+		Datum:       &pps.Datum{ID: id},
+		OutputBytes: bytes,
+		OutputFiles: files,
+	}
+}
+
+func datumIDs(sizes []*pps.DatumOutputSize) []string {
+	var ids []string
+	for _, s := range sizes {
+		ids = append(ids, s.Datum.ID)
+	}
+	return ids
+}
+
+func TestMergeLargestDatums(t *testing.T) {
+	// Datums are ordered by output bytes, then by output files
+	merged := mergeLargestDatums(
+		[]*pps.DatumOutputSize{outputSize("a", 10, 1), outputSize("b", 5, 1)},
+		[]*pps.DatumOutputSize{outputSize("c", 5, 3), outputSize("d", 20, 1)},
+	)
+	require.Equal(t, []string{"d", "a", "c", "b"}, datumIDs(merged))
+
+	// Only the largest maxLargestDatums datums are kept
+	var sizes []*pps.DatumOutputSize
+	for i := 0; i < 2*maxLargestDatums; i++ {
+		sizes = mergeLargestDatums(sizes, []*pps.DatumOutputSize{outputSize(fmt.Sprint(i), uint64(i), 1)})
+	}
+	require.Equal(t, maxLargestDatums, len(sizes))
+	require.Equal(t, fmt.Sprint(2*maxLargestDatums-1), sizes[0].Datum.ID)
+	require.Equal(t, fmt.Sprint(maxLargestDatums), sizes[maxLargestDatums-1].Datum.ID)
+
+	// Merging stats merges their largest datums
+	x := &DatumStats{ProcessStats: &pps.ProcessStats{}, LargestDatums: []*pps.DatumOutputSize{outputSize("a", 1, 1)}}
+	y := &DatumStats{ProcessStats: &pps.ProcessStats{OutputFiles: 2, OutputBytes: 7, Retries: 3, DedupedFiles: 1, DedupedBytes: 4}, LargestDatums: []*pps.DatumOutputSize{outputSize("b", 7, 2)}}
+	require.NoError(t, mergeStats(x, y))
+	require.Equal(t, []string{"b", "a"}, datumIDs(x.LargestDatums))
+	require.Equal(t, uint64(2), x.ProcessStats.OutputFiles)
+	require.Equal(t, uint64(7), x.ProcessStats.OutputBytes)
+	require.Equal(t, int64(3), x.ProcessStats.Retries)
+	require.Equal(t, uint64(1), x.ProcessStats.DedupedFiles)
+	require.Equal(t, uint64(4), x.ProcessStats.DedupedBytes)
+}
+
+func TestMergeFailedDatumSample(t *testing.T) {
+	x := &DatumStats{}
+	for i := 0; i < 2*ppsutil.MaxJobReasonDatums; i++ {
+		y := &DatumStats{FailedDatumID: fmt.Sprint(i), FailedDatumSample: []string{fmt.Sprint(i)}}
+		require.NoError(t, mergeStats(x, y))
+	}
+	require.Equal(t, "0", x.FailedDatumID)
+	require.Equal(t, []string{"0", "1", "2", "3", "4"}, x.FailedDatumSample)
+}
@@ -152,6 +152,21 @@ var (
 		},
 	)
 
+	// DatumDownloadCachedBytesCount is a counter tracking the total size of
+	// input data that a pipeline read from its workers' file caches
+	DatumDownloadCachedBytesCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pachyderm",
+			Subsystem: "worker",
+			Name:      "datum_download_cached_bytes_count",
+			Help:      "Cumulative number of downloaded bytes that were read from the file cache",
+		},
+		[]string{
+			"pipeline",
+			"job",
+		},
+	)
+
 	// DatumUploadSize is a histogram tracking the size of output data uploaded by a pipeline
 	DatumUploadSize = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -180,6 +195,50 @@ var (
 			"job",
 		},
 	)
+
+	// DatumUploadDedupedBytesCount is a counter tracking the total size of
+	// output data that a pipeline didn't upload, as identical data had
+	// already been uploaded
+	DatumUploadDedupedBytesCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pachyderm",
+			Subsystem: "worker",
+			Name:      "datum_upload_deduped_bytes_count",
+			Help:      "Cumulative number of output bytes that referenced identical, already uploaded data",
+		},
+		[]string{
+			"pipeline",
+			"job",
+		},
+	)
+
+	// HashtreeMemoryBytes is a gauge tracking the memory used by the hashtrees
+	// that a worker is building
+	HashtreeMemoryBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pachyderm",
+			Subsystem: "worker",
+			Name:      "hashtree_memory_bytes",
+			Help:      "Approximate memory used by the hashtrees being built",
+		},
+		[]string{
+			"pipeline",
+		},
+	)
+
+	// HashtreeMemoryLimitExceededCount is a counter tracking the number of
+	// times a worker's hashtrees exceeded the pipeline's hashtree memory limit
+	HashtreeMemoryLimitExceededCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pachyderm",
+			Subsystem: "worker",
+			Name:      "hashtree_memory_limit_exceeded_count",
+			Help:      "Number of times building hashtrees exceeded the hashtree memory limit",
+		},
+		[]string{
+			"pipeline",
+		},
+	)
 )
 
 // InitPrometheus sets up the default datum stats collectors for use by worker
@@ -195,8 +254,12 @@ func InitPrometheus() {
 		DatumUploadSecondsCount,
 		DatumDownloadSize,
 		DatumDownloadBytesCount,
+		DatumDownloadCachedBytesCount,
 		DatumUploadSize,
 		DatumUploadBytesCount,
+		DatumUploadDedupedBytesCount,
+		HashtreeMemoryBytes,
+		HashtreeMemoryLimitExceededCount,
 	}
 	for _, metric := range metrics {
 		if err := prometheus.Register(metric); err != nil {
@@ -35,9 +35,10 @@ const (
 
 // The Worker object represents
 type Worker struct {
-	APIServer *server.APIServer // Provides rpcs for other nodes in the cluster
-	driver    driver.Driver     // Provides common functions used by worker code
-	status    *transform.Status // An interface for inspecting and canceling the actively running task
+	APIServer  *server.APIServer // Provides rpcs for other nodes in the cluster
+	driver     driver.Driver     // Provides common functions used by worker code
+	status     *transform.Status // An interface for inspecting and canceling the actively running task
+	taskWorker *work.Worker      // Claims and runs the subtasks created by the master
 }
 
 // NewWorker constructs a Worker object that provides all worker functionality:
@@ -45,6 +46,9 @@ type Worker struct {
 //  2. a worker goroutine that gets tasks from the master and processes them
 //  3. an api server that serves requests for status or cross-worker communication
 //  4. a driver that provides common functionality between the above components
+//
+// Workers in a worker class (see pps.DatumSizing) only process the datums that
+// are assigned to their class.
 func NewWorker(
 	pachClient *client.APIClient,
 	etcdClient *etcd.Client,
@@ -54,6 +58,7 @@ func NewWorker(
 	namespace string,
 	hashtreePath string,
 	rootPath string,
+	workerClass string,
 ) (*Worker, error) {
 	stats.InitPrometheus()
 
@@ -101,8 +106,9 @@ func NewWorker(
 	}
 
 	worker := &Worker{
-		driver: driver,
-		status: &transform.Status{},
+		driver:     driver,
+		status:     &transform.Status{},
+		taskWorker: driver.NewTaskWorker(workerClass),
 	}
 
 	worker.APIServer = server.NewAPIServer(driver, worker.status, workerName)
@@ -137,9 +143,14 @@ func (w *Worker) worker() {
 			})
 		})
 
+		// Keep the runtime config that's exposed to user code up to date
+		eg.Go(func() error {
+			return driver.WatchRuntimeConfig()
+		})
+
 		// Run any worker tasks that the master creates
 		eg.Go(func() error {
-			return driver.NewTaskWorker().Run(
+			return w.taskWorker.Run(
 				ctx,
 				func(ctx context.Context, subtask *work.Task) error {
 					driver := w.driver.WithContext(ctx)
@@ -159,6 +170,16 @@ func (w *Worker) worker() {
 	})
 }
 
+// Drain prepares the worker to be shut down (e.g. because its node is being
+// drained). The worker stops claiming subtasks, and its current datum task
+// stops after the datums that are already running, so that the rest of the
+// task's datums are processed by another worker. Drain returns once the worker
+// has no subtasks left, or when 'ctx' is done.
+func (w *Worker) Drain(ctx context.Context) error {
+	w.status.Drain()
+	return w.taskWorker.Drain(ctx)
+}
+
 func (w *Worker) master(etcdClient *etcd.Client, etcdPrefix string) {
 	pipelineInfo := w.driver.PipelineInfo()
 	logger := logs.NewMasterLogger(pipelineInfo)